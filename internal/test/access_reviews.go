@@ -0,0 +1,82 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// AccessReviewHandler serves the discovery of the authorization.k8s.io group, the SelfSubjectAccessReviews and the
+// SubjectAccessReviews, the reviews are allowed unless Denied returns true for their resource attributes
+type AccessReviewHandler struct {
+	DiscoveryClientHandler
+	Denied         func(attributes *authorizationv1.ResourceAttributes) bool
+	mu             sync.Mutex
+	reviews        []authorizationv1.ResourceAttributes
+	subjectReviews []authorizationv1.SubjectAccessReviewSpec
+}
+
+var _ http.Handler = (*AccessReviewHandler)(nil)
+
+func NewAccessReviewHandler(denied func(attributes *authorizationv1.ResourceAttributes) bool) *AccessReviewHandler {
+	arh := &AccessReviewHandler{Denied: denied}
+	arh.Groups = []string{
+		`{"name":"authorization.k8s.io","versions":[{"groupVersion":"authorization.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"authorization.k8s.io/v1","version":"v1"}}`,
+	}
+	return arh
+}
+
+func (h *AccessReviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.DiscoveryClientHandler.ServeHTTP(w, req)
+	if req.URL.EscapedPath() == "/apis/authorization.k8s.io/v1" {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"authorization.k8s.io/v1","resources":[{"name":"selfsubjectaccessreviews","singularName":"","namespaced":false,"kind":"SelfSubjectAccessReview","verbs":["create"]},{"name":"subjectaccessreviews","singularName":"","namespaced":false,"kind":"SubjectAccessReview","verbs":["create"]}]}`))
+		return
+	}
+	if req.URL.EscapedPath() == "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews" {
+		body, _ := io.ReadAll(req.Body)
+		obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review := obj.(*authorizationv1.SelfSubjectAccessReview)
+		h.mu.Lock()
+		h.reviews = append(h.reviews, *review.Spec.ResourceAttributes)
+		h.mu.Unlock()
+		review.Status.Allowed = h.Denied == nil || !h.Denied(review.Spec.ResourceAttributes)
+		WriteObject(w, review)
+	}
+	if req.URL.EscapedPath() == "/apis/authorization.k8s.io/v1/subjectaccessreviews" {
+		body, _ := io.ReadAll(req.Body)
+		obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review := obj.(*authorizationv1.SubjectAccessReview)
+		h.mu.Lock()
+		h.subjectReviews = append(h.subjectReviews, review.Spec)
+		h.mu.Unlock()
+		review.Status.Allowed = h.Denied == nil || !h.Denied(review.Spec.ResourceAttributes)
+		WriteObject(w, review)
+	}
+}
+
+// Reviews returns the resource attributes of the SelfSubjectAccessReviews served so far
+func (h *AccessReviewHandler) Reviews() []authorizationv1.ResourceAttributes {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]authorizationv1.ResourceAttributes{}, h.reviews...)
+}
+
+// SubjectReviews returns the specs of the SubjectAccessReviews served so far
+func (h *AccessReviewHandler) SubjectReviews() []authorizationv1.SubjectAccessReviewSpec {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]authorizationv1.SubjectAccessReviewSpec{}, h.subjectReviews...)
+}
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// HelperDaemonSetHandler simulates the lifecycle of the helper DaemonSets created by the server.
+// Created DaemonSets get a pod on each of the nodes they're pinned to (node affinity on metadata.name), whose helper
+// init container completes immediately with the configured ExitCode. The logs are provided by the Logs function.
+// Only the DaemonSets (and their pods) created through the handler are served.
+type HelperDaemonSetHandler struct {
+	// Logs returns the logs of the helper init container of the pod
+	Logs func(pod *v1.Pod) string
+	// ExitCode returns the exit code of the helper init container of the pod (defaults to 0)
+	ExitCode func(pod *v1.Pod) int32
+	mu       sync.Mutex
+	created  []*appsv1.DaemonSet
+	deleted  []string
+}
+
+var _ http.Handler = (*HelperDaemonSetHandler)(nil)
+
+func (h *HelperDaemonSetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/apis/apps/v1/namespaces/"):
+		h.serveDaemonSets(w, req)
+	case strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/"):
+		h.servePods(w, req)
+	}
+}
+
+func (h *HelperDaemonSetHandler) serveDaemonSets(w http.ResponseWriter, req *http.Request) {
+	// /apis/apps/v1/namespaces/{namespace}/daemonsets[/{name}]
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/apis/apps/v1/namespaces/"), "/")
+	if len(parts) < 2 || parts[1] != "daemonsets" {
+		return
+	}
+	if len(parts) == 2 && req.Method == http.MethodPost {
+		daemonSet := &appsv1.DaemonSet{}
+		body, _ := io.ReadAll(req.Body)
+		// Typed clients send protobuf encoded objects
+		if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, daemonSet); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		daemonSet.Namespace = parts[0]
+		h.mu.Lock()
+		h.created = append(h.created, daemonSet)
+		h.mu.Unlock()
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(daemonSet)
+		return
+	}
+	if len(parts) == 3 && req.Method == http.MethodDelete && h.daemonSet(parts[0], parts[2]) != nil {
+		h.mu.Lock()
+		h.deleted = append(h.deleted, parts[2])
+		h.mu.Unlock()
+		WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+	}
+}
+
+func (h *HelperDaemonSetHandler) servePods(w http.ResponseWriter, req *http.Request) {
+	// /api/v1/namespaces/{namespace}/pods[/{name}/log]
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/api/v1/namespaces/"), "/")
+	if len(parts) < 2 || parts[1] != "pods" || req.Method != http.MethodGet {
+		return
+	}
+	if len(parts) == 2 {
+		selector, err := labels.Parse(req.URL.Query().Get("labelSelector"))
+		if err != nil {
+			return
+		}
+		for _, daemonSet := range h.Created() {
+			if daemonSet.Namespace == parts[0] && !selector.Empty() && selector.Matches(labels.Set(daemonSet.Spec.Template.Labels)) {
+				WriteObject(w, &v1.PodList{
+					TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+					Items:    h.pods(daemonSet),
+				})
+				return
+			}
+		}
+		return
+	}
+	if len(parts) == 4 && parts[3] == "log" {
+		for _, daemonSet := range h.Created() {
+			for _, pod := range h.pods(daemonSet) {
+				if pod.Namespace == parts[0] && pod.Name == parts[2] {
+					if h.Logs != nil {
+						_, _ = io.WriteString(w, h.Logs(&pod))
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// pods returns the pods of the DaemonSet, one per node it's pinned to, with the helper init container completed
+func (h *HelperDaemonSetHandler) pods(daemonSet *appsv1.DaemonSet) []v1.Pod {
+	var nodeNames []string
+	if affinity := daemonSet.Spec.Template.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			for _, requirement := range term.MatchFields {
+				if requirement.Key == "metadata.name" {
+					nodeNames = append(nodeNames, requirement.Values...)
+				}
+			}
+		}
+	}
+	pods := make([]v1.Pod, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		pod := v1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      daemonSet.Name + "-" + nodeName,
+				Namespace: daemonSet.Namespace,
+				Labels:    daemonSet.Spec.Template.Labels,
+			},
+			Spec: *daemonSet.Spec.Template.Spec.DeepCopy(),
+		}
+		pod.Spec.NodeName = nodeName
+		for _, container := range pod.Spec.InitContainers {
+			terminated := &v1.ContainerStateTerminated{}
+			if h.ExitCode != nil {
+				terminated.ExitCode = h.ExitCode(&pod)
+			}
+			pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, v1.ContainerStatus{
+				Name:  container.Name,
+				State: v1.ContainerState{Terminated: terminated},
+			})
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func (h *HelperDaemonSetHandler) daemonSet(namespace, name string) *appsv1.DaemonSet {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, daemonSet := range h.created {
+		if daemonSet.Namespace == namespace && daemonSet.Name == name {
+			return daemonSet
+		}
+	}
+	return nil
+}
+
+// Created returns the helper DaemonSets created through the handler
+func (h *HelperDaemonSetHandler) Created() []*appsv1.DaemonSet {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*appsv1.DaemonSet{}, h.created...)
+}
+
+// Deleted returns the names of the helper DaemonSets deleted through the handler
+func (h *HelperDaemonSetHandler) Deleted() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string{}, h.deleted...)
+}
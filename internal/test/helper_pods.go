@@ -0,0 +1,104 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// HelperPodHandler simulates the lifecycle of the helper pods created by the server.
+// Created pods complete immediately with the configured Phase and their logs are provided by the Logs function.
+// Only the pods created through the handler are served, other pod requests are left to the remaining handlers.
+type HelperPodHandler struct {
+	// Logs returns the logs of the completed helper pod
+	Logs func(pod *v1.Pod) string
+	// Phase of the completed helper pods (defaults to Succeeded)
+	Phase   v1.PodPhase
+	mu      sync.Mutex
+	created []*v1.Pod
+	deleted []string
+}
+
+var _ http.Handler = (*HelperPodHandler)(nil)
+
+func (h *HelperPodHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// /api/v1/namespaces/{namespace}/pods[/{name}[/log]]
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/api/v1/namespaces/"), "/")
+	if !strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/") || len(parts) < 2 || parts[1] != "pods" {
+		return
+	}
+	if len(parts) == 2 && req.Method == http.MethodPost {
+		pod := &v1.Pod{}
+		body, _ := io.ReadAll(req.Body)
+		// Typed clients send protobuf encoded objects
+		if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, pod); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pod.Namespace = parts[0]
+		h.mu.Lock()
+		h.created = append(h.created, pod)
+		h.mu.Unlock()
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(pod)
+		return
+	}
+	if len(parts) < 3 {
+		return
+	}
+	pod := h.pod(parts[0], parts[2])
+	if pod == nil {
+		return
+	}
+	switch {
+	case len(parts) == 4 && parts[3] == "log":
+		if h.Logs != nil {
+			_, _ = io.WriteString(w, h.Logs(pod))
+		}
+	case len(parts) == 3 && req.Method == http.MethodDelete:
+		h.mu.Lock()
+		h.deleted = append(h.deleted, pod.Name)
+		h.mu.Unlock()
+		WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+	case len(parts) == 3 && req.Method == http.MethodGet:
+		completed := pod.DeepCopy()
+		completed.Status.Phase = h.Phase
+		if completed.Status.Phase == "" {
+			completed.Status.Phase = v1.PodSucceeded
+		}
+		WriteObject(w, completed)
+	}
+}
+
+func (h *HelperPodHandler) pod(namespace, name string) *v1.Pod {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, pod := range h.created {
+		if pod.Namespace == namespace && pod.Name == name {
+			return pod
+		}
+	}
+	return nil
+}
+
+// Created returns the helper pods created through the handler
+func (h *HelperPodHandler) Created() []*v1.Pod {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*v1.Pod{}, h.created...)
+}
+
+// Deleted returns the names of the helper pods deleted through the handler
+func (h *HelperPodHandler) Deleted() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string{}, h.deleted...)
+}
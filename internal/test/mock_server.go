@@ -222,7 +222,10 @@ func (h *DiscoveryClientHandler) ServeHTTP(w http.ResponseWriter, req *http.Requ
 	if req.URL.Path == "/apis/apps/v1" {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"apps/v1","resources":[
-			{"name":"deployments","singularName":"","namespaced":true,"kind":"Deployment","verbs":["get","list","watch","create","update","patch","delete"]}
+			{"name":"deployments","singularName":"","namespaced":true,"kind":"Deployment","verbs":["get","list","watch","create","update","patch","delete"]},
+			{"name":"replicasets","singularName":"","namespaced":true,"kind":"ReplicaSet","verbs":["get","list","watch","create","update","patch","delete"]},
+			{"name":"statefulsets","singularName":"","namespaced":true,"kind":"StatefulSet","verbs":["get","list","watch","create","update","patch","delete"]},
+			{"name":"daemonsets","singularName":"","namespaced":true,"kind":"DaemonSet","verbs":["get","list","watch","create","update","patch","delete"]}
 		]}`))
 		return
 	}
@@ -15,9 +15,17 @@ import (
 
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/config"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/core"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/diagnostics"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/gitops"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/helm"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/kiali"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/kubevirt"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/machines"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/metrics"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/networkdebug"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/openshift"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/rbac"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/storage"
 )
 
 type OpenShift struct{}
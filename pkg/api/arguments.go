@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// resolvedSchemas caches the resolved property schemas, tool input schemas don't change once the tool is registered
+var resolvedSchemas sync.Map
+
+// ParseArguments decodes the tool call arguments into the typed arguments struct T, fields are mapped with their
+// json tags. When the tool input schema is available (ToolHandlerParams.InputSchema) the arguments are validated
+// against it and the declared defaults are applied first, so that every tool reports the same errors:
+// "missing argument <name>" for required arguments that are not provided (or empty) and
+// "invalid argument <name>: <reason>" for arguments that don't match their schema.
+// The errors of the arguments are classified as ErrorCategoryInvalidArgument.
+func ParseArguments[T any](params ToolHandlerParams) (*T, error) {
+	arguments := maps.Clone(params.GetArguments())
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	if schema := params.InputSchema; schema != nil {
+		for _, name := range schema.Required {
+			if value, ok := arguments[name]; !ok || value == nil || value == "" {
+				return nil, InvalidArgument(fmt.Errorf("missing argument %s", name))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			property := schema.Properties[name]
+			value, ok := arguments[name]
+			if !ok || value == nil {
+				if property.Default != nil {
+					var defaultValue any
+					if err := json.Unmarshal(property.Default, &defaultValue); err != nil {
+						return nil, fmt.Errorf("invalid default for argument %s: %w", name, err)
+					}
+					arguments[name] = defaultValue
+				}
+				continue
+			}
+			resolved, err := resolveSchema(property)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema for argument %s: %w", name, err)
+			}
+			if err = resolved.Validate(value); err != nil {
+				// the property schema is validated on its own, the root is the argument itself
+				return nil, InvalidArgument(fmt.Errorf("invalid argument %s: %s", name, strings.TrimPrefix(err.Error(), "validating root: ")))
+			}
+		}
+	}
+	marshalled, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, InvalidArgument(fmt.Errorf("invalid arguments: %w", err))
+	}
+	ret := new(T)
+	if err = json.Unmarshal(marshalled, ret); err != nil {
+		return nil, InvalidArgument(fmt.Errorf("invalid arguments: %w", err))
+	}
+	return ret, nil
+}
+
+func resolveSchema(schema *jsonschema.Schema) (*jsonschema.Resolved, error) {
+	if resolved, ok := resolvedSchemas.Load(schema); ok {
+		return resolved.(*jsonschema.Resolved), nil
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, err
+	}
+	resolvedSchemas.Store(schema, resolved)
+	return resolved, nil
+}
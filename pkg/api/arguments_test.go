@@ -0,0 +1,98 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/utils/ptr"
+)
+
+type ArgumentsSuite struct {
+	suite.Suite
+}
+
+type argumentsRequest map[string]any
+
+func (r argumentsRequest) GetArguments() map[string]any {
+	return r
+}
+
+type testArguments struct {
+	Name      string            `json:"name"`
+	Format    string            `json:"format"`
+	TailLines int64             `json:"tailLines"`
+	Sysctls   []string          `json:"sysctls"`
+	Labels    map[string]string `json:"labels"`
+}
+
+var testArgumentsSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"name":      {Type: "string"},
+		"format":    {Type: "string", Enum: []any{"text", "json"}, Default: ToRawMessage("text")},
+		"tailLines": {Type: "integer", Minimum: ptr.To(float64(0)), Default: ToRawMessage(100)},
+		"sysctls":   {Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		"labels":    {Type: "object", AdditionalProperties: &jsonschema.Schema{Type: "string"}},
+	},
+	Required: []string{"name"},
+}
+
+func (s *ArgumentsSuite) parse(arguments map[string]any) (*testArguments, error) {
+	return ParseArguments[testArguments](ToolHandlerParams{ToolCallRequest: argumentsRequest(arguments), InputSchema: testArgumentsSchema})
+}
+
+func (s *ArgumentsSuite) TestParseArguments() {
+	s.Run("decodes the arguments into the typed struct", func() {
+		args, err := s.parse(map[string]any{
+			"name": "node-1", "format": "json", "tailLines": float64(10),
+			"sysctls": []any{"vm.max_map_count"}, "labels": map[string]any{"app": "web"},
+		})
+		s.Require().NoError(err)
+		s.Equal(&testArguments{Name: "node-1", Format: "json", TailLines: 10, Sysctls: []string{"vm.max_map_count"}, Labels: map[string]string{"app": "web"}}, args)
+	})
+	s.Run("applies the schema defaults", func() {
+		args, err := s.parse(map[string]any{"name": "node-1"})
+		s.Require().NoError(err)
+		s.Equal("text", args.Format)
+		s.Equal(int64(100), args.TailLines)
+	})
+	s.Run("missing required argument", func() {
+		_, err := s.parse(map[string]any{})
+		s.EqualError(err, "missing argument name")
+	})
+	s.Run("empty required argument", func() {
+		_, err := s.parse(map[string]any{"name": ""})
+		s.EqualError(err, "missing argument name")
+	})
+	s.Run("argument with invalid type", func() {
+		_, err := s.parse(map[string]any{"name": "node-1", "tailLines": "ten"})
+		s.EqualError(err, `invalid argument tailLines: type: ten has type "string", want "integer"`)
+	})
+	s.Run("argument out of range", func() {
+		_, err := s.parse(map[string]any{"name": "node-1", "tailLines": float64(-1)})
+		s.Require().Error(err)
+		s.Contains(err.Error(), "invalid argument tailLines: minimum: ")
+	})
+	s.Run("argument not in enum", func() {
+		_, err := s.parse(map[string]any{"name": "node-1", "format": "xml"})
+		s.EqualError(err, "invalid argument format: enum: xml does not equal any of: [text json]")
+	})
+	s.Run("without input schema only decodes the arguments", func() {
+		args, err := ParseArguments[testArguments](ToolHandlerParams{ToolCallRequest: argumentsRequest{"format": "xml"}})
+		s.Require().NoError(err)
+		s.Equal(&testArguments{Format: "xml"}, args)
+	})
+}
+
+func (s *ArgumentsSuite) TestParseArgumentsErrorCategory() {
+	for _, arguments := range []map[string]any{{}, {"name": "node-1", "format": "xml"}, {"name": "node-1", "labels": "app=web"}} {
+		_, err := s.parse(arguments)
+		s.Require().Error(err)
+		s.Equalf(ErrorCategoryInvalidArgument, ErrorCategoryOf(err), "arguments %v", arguments)
+	}
+}
+
+func TestArguments(t *testing.T) {
+	suite.Run(t, new(ArgumentsSuite))
+}
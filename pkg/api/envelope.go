@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// OutputFormatParameterName is the name of the input schema property to select the output format of the tools
+// supporting the structured output envelope
+const OutputFormatParameterName = "output_format"
+
+// Output formats of the tools supporting the structured output envelope
+const (
+	// OutputFormatText is the default human-readable output of the tool
+	OutputFormatText = "text"
+	// OutputFormatJson is the structured output envelope marshalled as JSON
+	OutputFormatJson = "json"
+)
+
+// OutputFormatProperty is the input schema property tools expose to select between the text and the structured output
+func OutputFormatProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Description: "Format of the result (Optional, default text). " +
+			"json returns a machine-parseable envelope with the kind of the items, the items, a summary, and whether the items were truncated",
+		Enum:    []any{OutputFormatText, OutputFormatJson},
+		Default: ToRawMessage(OutputFormatText),
+	}
+}
+
+// Envelope is the structured result of a tool, returned as JSON when the json output format is requested
+type Envelope struct {
+	// Kind identifies the type of the items (e.g. NodeMetrics)
+	Kind  string `json:"kind"`
+	Items any    `json:"items"`
+	// Summary is a short human-readable description of the result
+	Summary string `json:"summary,omitempty"`
+	// Truncated is true if the items are incomplete (e.g. limited number of log lines)
+	Truncated bool `json:"truncated"`
+	// Errors are the targets that failed in a partial result (see NewPartialToolCallResult)
+	Errors internalk8s.TargetErrors `json:"errors,omitempty"`
+}
+
+// IsStructuredOutput returns true if the tool call requests the structured output envelope
+func IsStructuredOutput(request ToolCallRequest) bool {
+	format, _ := request.GetArguments()[OutputFormatParameterName].(string)
+	return format == OutputFormatJson
+}
+
+// NewStructuredToolCallResult returns the envelope marshalled as JSON when the structured output is requested,
+// or the text content otherwise
+func NewStructuredToolCallResult(request ToolCallRequest, envelope *Envelope, text string) *ToolCallResult {
+	if !IsStructuredOutput(request) {
+		return NewToolCallResult(text, nil)
+	}
+	marshalled, err := json.Marshal(envelope)
+	if err != nil {
+		return NewToolCallResult("", fmt.Errorf("failed to marshal %s result: %w", envelope.Kind, err))
+	}
+	return NewToolCallResult(string(marshalled), nil)
+}
+
+// NewStructuredPartialToolCallResult is the structured counterpart of NewPartialToolCallResult,
+// the failed targets are reported in the errors field of the envelope
+func NewStructuredPartialToolCallResult(request ToolCallRequest, envelope *Envelope, text string, succeeded int, targetErrors internalk8s.TargetErrors) *ToolCallResult {
+	if !IsStructuredOutput(request) {
+		return NewPartialToolCallResult(text, succeeded, targetErrors)
+	}
+	if succeeded == 0 && len(targetErrors) > 0 {
+		return NewToolCallResult("", fmt.Errorf("failed for all targets: %w", targetErrors))
+	}
+	envelope.Errors = targetErrors
+	return NewStructuredToolCallResult(request, envelope, text)
+}
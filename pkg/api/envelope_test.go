@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type EnvelopeSuite struct {
+	suite.Suite
+}
+
+type envelopeRequest map[string]any
+
+func (r envelopeRequest) GetArguments() map[string]any {
+	return r
+}
+
+func (s *EnvelopeSuite) TestIsStructuredOutput() {
+	s.Run("defaults to false", func() {
+		s.False(IsStructuredOutput(envelopeRequest{}))
+	})
+	s.Run("text returns false", func() {
+		s.False(IsStructuredOutput(envelopeRequest{OutputFormatParameterName: OutputFormatText}))
+	})
+	s.Run("json returns true", func() {
+		s.True(IsStructuredOutput(envelopeRequest{OutputFormatParameterName: OutputFormatJson}))
+	})
+}
+
+func (s *EnvelopeSuite) TestNewStructuredToolCallResult() {
+	envelope := &Envelope{Kind: "NodeLog", Items: []string{"Line 1", "Line 2"}, Summary: "2 lines", Truncated: true}
+	s.Run("text output returns text content", func() {
+		result := NewStructuredToolCallResult(envelopeRequest{}, envelope, "Line 1\nLine 2\n")
+		s.NoError(result.Error)
+		s.Equal("Line 1\nLine 2\n", result.Content)
+	})
+	s.Run("json output returns marshalled envelope", func() {
+		result := NewStructuredToolCallResult(envelopeRequest{OutputFormatParameterName: OutputFormatJson}, envelope, "Line 1\nLine 2\n")
+		s.NoError(result.Error)
+		s.JSONEq(`{"kind":"NodeLog","items":["Line 1","Line 2"],"summary":"2 lines","truncated":true}`, result.Content)
+	})
+}
+
+func (s *EnvelopeSuite) TestNewStructuredPartialToolCallResult() {
+	json := envelopeRequest{OutputFormatParameterName: OutputFormatJson}
+	s.Run("text output returns partial text content", func() {
+		var targetErrors internalk8s.TargetErrors
+		targetErrors.Add("node/node-2", errors.New("kubelet unreachable"))
+		result := NewStructuredPartialToolCallResult(envelopeRequest{}, &Envelope{Kind: "NodeStatsSummary"}, "content\n", 1, targetErrors)
+		s.NoError(result.Error)
+		s.Contains(result.Content, "# Partial result: 1 of 2 targets failed\n")
+	})
+	s.Run("json output with some failed targets returns envelope with errors", func() {
+		var targetErrors internalk8s.TargetErrors
+		targetErrors.Add("node/node-2", errors.New("kubelet unreachable"))
+		result := NewStructuredPartialToolCallResult(json, &Envelope{Kind: "NodeStatsSummary", Items: []string{"node-1"}}, "content\n", 1, targetErrors)
+		s.NoError(result.Error)
+		s.JSONEq(`{"kind":"NodeStatsSummary","items":["node-1"],"truncated":false,"errors":[{"target":"node/node-2","error":"kubelet unreachable"}]}`, result.Content)
+	})
+	s.Run("json output with all failed targets returns error", func() {
+		var targetErrors internalk8s.TargetErrors
+		targetErrors.Add("node/node-1", errors.New("kubelet unreachable"))
+		result := NewStructuredPartialToolCallResult(json, &Envelope{Kind: "NodeStatsSummary"}, "", 0, targetErrors)
+		s.EqualError(result.Error, "failed for all targets: node/node-1: kubelet unreachable")
+		s.Empty(result.Content)
+	})
+}
+
+func TestEnvelope(t *testing.T) {
+	suite.Run(t, new(EnvelopeSuite))
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// ErrorCategory classifies the errors of the tool results, the category is returned to the MCP clients in the
+// error field of the structured content of the result so that they can branch on it instead of matching the message
+type ErrorCategory string
+
+const (
+	// ErrorCategoryNotFound the object (or resource type) doesn't exist
+	ErrorCategoryNotFound ErrorCategory = "not_found"
+	// ErrorCategoryForbidden the cluster (RBAC or authentication) rejected the request
+	ErrorCategoryForbidden ErrorCategory = "forbidden"
+	// ErrorCategoryDeniedByConfig the server configuration rejected the call (denied_resources, policies, break-glass)
+	ErrorCategoryDeniedByConfig ErrorCategory = "denied_by_config"
+	// ErrorCategoryTimeout the call didn't complete in time (tool timeouts or API server timeouts)
+	ErrorCategoryTimeout ErrorCategory = "timeout"
+	// ErrorCategoryInvalidArgument the arguments of the call are missing or invalid
+	ErrorCategoryInvalidArgument ErrorCategory = "invalid_argument"
+	// ErrorCategoryRateLimited the call exceeded the rate limits of the session or was throttled by the API server
+	ErrorCategoryRateLimited ErrorCategory = "rate_limited"
+	// ErrorCategoryUpstream the rest of the failures, reported by the API server or the external services
+	ErrorCategoryUpstream ErrorCategory = "upstream"
+)
+
+// CategorizedError is an error explicitly classified in an ErrorCategory, the message is the one of the wrapped error
+type CategorizedError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+func (e *CategorizedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CategorizedError) Unwrap() error {
+	return e.Err
+}
+
+// NewCategorizedError classifies the error in the category, nil if err is nil
+func NewCategorizedError(category ErrorCategory, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CategorizedError{Category: category, Err: err}
+}
+
+// InvalidArgument classifies the error as an ErrorCategoryInvalidArgument
+func InvalidArgument(err error) error {
+	return NewCategorizedError(ErrorCategoryInvalidArgument, err)
+}
+
+// ErrorCategoryOf returns the category of the error: the explicit category of a wrapped CategorizedError, or the
+// category of the wrapped Kubernetes API, access control, or context errors. ErrorCategoryUpstream otherwise.
+// The tool handlers must wrap the errors (%w) for them to be classified.
+func ErrorCategoryOf(err error) ErrorCategory {
+	var categorized *CategorizedError
+	switch {
+	case errors.As(err, &categorized):
+		return categorized.Category
+	case errors.Is(err, internalk8s.ErrResourceNotAllowed):
+		return ErrorCategoryDeniedByConfig
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return ErrorCategoryTimeout
+	case apierrors.IsNotFound(err):
+		return ErrorCategoryNotFound
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return ErrorCategoryForbidden
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return ErrorCategoryInvalidArgument
+	// evictions blocked by a PodDisruptionBudget are also rejected with 429 Too Many Requests
+	case apierrors.IsTooManyRequests(err) && !apierrors.HasStatusCause(err, policyv1.DisruptionBudgetCause):
+		return ErrorCategoryRateLimited
+	}
+	return ErrorCategoryUpstream
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type ErrorsSuite struct {
+	suite.Suite
+}
+
+func (s *ErrorsSuite) TestErrorCategoryOf() {
+	pods := schema.GroupResource{Resource: "pods"}
+	disruptionBudget := apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	disruptionBudget.ErrStatus.Details.Causes = []metav1.StatusCause{{Type: policyv1.DisruptionBudgetCause}}
+	for _, tc := range []struct {
+		name     string
+		err      error
+		category ErrorCategory
+	}{
+		{"categorized", NewCategorizedError(ErrorCategoryDeniedByConfig, errors.New("denied by policy")), ErrorCategoryDeniedByConfig},
+		{"invalid argument", InvalidArgument(errors.New("missing argument name")), ErrorCategoryInvalidArgument},
+		{"resource not allowed", fmt.Errorf("%w: /v1, Kind=Secret", internalk8s.ErrResourceNotAllowed), ErrorCategoryDeniedByConfig},
+		{"deadline exceeded", context.DeadlineExceeded, ErrorCategoryTimeout},
+		{"server timeout", apierrors.NewServerTimeout(pods, "list", 1), ErrorCategoryTimeout},
+		{"not found", apierrors.NewNotFound(pods, "web"), ErrorCategoryNotFound},
+		{"forbidden", apierrors.NewForbidden(pods, "web", errors.New("rbac")), ErrorCategoryForbidden},
+		{"unauthorized", apierrors.NewUnauthorized("token expired"), ErrorCategoryForbidden},
+		{"invalid", apierrors.NewBadRequest("invalid manifest"), ErrorCategoryInvalidArgument},
+		{"too many requests", apierrors.NewTooManyRequests("too many requests", 1), ErrorCategoryRateLimited},
+		{"eviction blocked by PodDisruptionBudget", disruptionBudget, ErrorCategoryUpstream},
+		{"internal error", apierrors.NewInternalError(errors.New("etcd")), ErrorCategoryUpstream},
+		{"other", errors.New("connection refused"), ErrorCategoryUpstream},
+	} {
+		s.Run(tc.name, func() {
+			s.Equal(tc.category, ErrorCategoryOf(tc.err))
+		})
+		s.Run(tc.name+" wrapped", func() {
+			s.Equal(tc.category, ErrorCategoryOf(fmt.Errorf("failed to get pod: %w", tc.err)))
+		})
+	}
+}
+
+func (s *ErrorsSuite) TestCategorizedError() {
+	s.Run("keeps the message of the error", func() {
+		err := InvalidArgument(errors.New("missing argument name"))
+		s.EqualError(err, "missing argument name")
+	})
+	s.Run("unwraps the error", func() {
+		err := NewCategorizedError(ErrorCategoryUpstream, context.Canceled)
+		s.ErrorIs(err, context.Canceled)
+	})
+	s.Run("nil error", func() {
+		s.NoError(InvalidArgument(nil))
+	})
+}
+
+func TestErrors(t *testing.T) {
+	suite.Run(t, new(ErrorsSuite))
+}
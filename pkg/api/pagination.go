@@ -0,0 +1,124 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ContinueResultToolName is the name of the tool that returns the next chunk of a truncated tool output
+	ContinueResultToolName = "continue_result"
+	// resultCursorTTL is the time the remaining chunks of a truncated tool output are kept for
+	resultCursorTTL = 10 * time.Minute
+)
+
+// ResultPager truncates the tool outputs that exceed the configured byte budget and keeps the remaining content
+// in memory so that it can be retrieved in chunks with a continuation cursor (see ContinueResultToolName).
+// Cursors can only be used once, by the MCP session they were issued for, and expire after 10 minutes.
+type ResultPager struct {
+	maxBytes int
+	now      func() time.Time
+	mu       sync.Mutex
+	pages    map[string]*resultPage
+}
+
+type resultPage struct {
+	// content is the whole tool output, offset is where the next chunk starts
+	content   string
+	offset    int
+	owner     string
+	expiresAt time.Time
+}
+
+// NewResultPager returns a ResultPager with the provided budget, outputs are never truncated if maxBytes <= 0
+func NewResultPager(maxBytes int) *ResultPager {
+	return &ResultPager{maxBytes: maxBytes, now: time.Now, pages: make(map[string]*resultPage)}
+}
+
+// Enabled returns true if the tool outputs are truncated to a byte budget
+func (p *ResultPager) Enabled() bool {
+	return p != nil && p.maxBytes > 0
+}
+
+// Paginate returns the content if it fits in the budget, or its first chunk followed by the cursor to retrieve
+// the next one otherwise. The owner (MCP session ID) is the only one allowed to use the cursor.
+func (p *ResultPager) Paginate(content, owner string) string {
+	if !p.Enabled() || len(content) <= p.maxBytes {
+		return content
+	}
+	return p.chunk(&resultPage{content: content, owner: owner})
+}
+
+// Next returns the next chunk of the truncated tool output, followed by a new cursor if there's more content
+func (p *ResultPager) Next(cursor, owner string) (string, error) {
+	p.mu.Lock()
+	now := p.now()
+	for key, page := range p.pages {
+		if now.After(page.expiresAt) {
+			delete(p.pages, key)
+		}
+	}
+	page, ok := p.pages[cursor]
+	if ok && page.owner == owner {
+		delete(p.pages, cursor)
+	}
+	p.mu.Unlock()
+	if !ok {
+		return "", errors.New("invalid or expired cursor, call the original tool again")
+	}
+	if page.owner != owner {
+		return "", errors.New("cursor was issued for a different MCP session")
+	}
+	return p.chunk(page), nil
+}
+
+// chunk returns the chunk of the page starting at its offset, the page is stored under a new cursor if there's
+// remaining content
+func (p *ResultPager) chunk(page *resultPage) string {
+	start := page.offset
+	end := start + p.maxBytes
+	if end < len(page.content) {
+		// Prefer cutting at a line boundary so that YAML and tables aren't split mid-line
+		if newline := strings.LastIndexByte(page.content[start:end], '\n'); newline >= 0 {
+			end = start + newline + 1
+		} else {
+			for end > start && !utf8.RuneStart(page.content[end]) {
+				end--
+			}
+			// A rune larger than the budget is returned whole so that the cursor always advances
+			if end == start {
+				_, size := utf8.DecodeRuneInString(page.content[start:])
+				end = start + size
+			}
+		}
+	}
+	if end >= len(page.content) {
+		return fmt.Sprintf("# Output continued: bytes %d-%d of %d (end of output)\n", start, len(page.content), len(page.content)) +
+			page.content[start:]
+	}
+	cursor := newResultCursor()
+	page.offset = end
+	page.expiresAt = p.now().Add(resultCursorTTL)
+	p.mu.Lock()
+	p.pages[cursor] = page
+	p.mu.Unlock()
+	header := ""
+	if start > 0 {
+		header = fmt.Sprintf("# Output continued: bytes %d-%d of %d\n", start, end, len(page.content))
+	}
+	return header + strings.TrimSuffix(page.content[start:end], "\n") + "\n" +
+		fmt.Sprintf("# Output truncated: returned bytes %d-%d of %d, call %s with cursor %q to get the next chunk\n",
+			start, end, len(page.content), ContinueResultToolName, cursor)
+}
+
+func newResultCursor() string {
+	cursor := make([]byte, 16)
+	_, _ = rand.Read(cursor)
+	return hex.EncodeToString(cursor)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PaginationSuite struct {
+	suite.Suite
+}
+
+var paginationCursorRegexp = regexp.MustCompile(`with cursor "([0-9a-f]+)"`)
+
+func paginationCursor(content string) string {
+	if match := paginationCursorRegexp.FindStringSubmatch(content); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func (s *PaginationSuite) TestPaginate() {
+	s.Run("disabled pager returns content", func() {
+		s.Equal(strings.Repeat("a", 100), NewResultPager(0).Paginate(strings.Repeat("a", 100), ""))
+	})
+	s.Run("content within budget is returned as is", func() {
+		s.Equal("line 1\nline 2\n", NewResultPager(14).Paginate("line 1\nline 2\n", ""))
+	})
+	s.Run("content over budget is truncated at the last line boundary", func() {
+		ret := NewResultPager(10).Paginate("line 1\nline 2\n", "")
+		s.True(strings.HasPrefix(ret, "line 1\n# Output truncated: returned bytes 0-7 of 14, call continue_result with cursor "), ret)
+		s.NotEmpty(paginationCursor(ret))
+	})
+	s.Run("content without line boundaries is truncated at a rune boundary", func() {
+		ret := NewResultPager(5).Paginate("ääää", "")
+		s.True(strings.HasPrefix(ret, "ää\n# Output truncated: returned bytes 0-4 of 8"), ret)
+	})
+	s.Run("rune larger than the budget at the chunk boundary is returned whole", func() {
+		pager := NewResultPager(3)
+		ret := pager.Paginate("a😀b😀", "")
+		s.True(strings.HasPrefix(ret, "a\n# Output truncated: returned bytes 0-1 of 10"), ret)
+		next, err := pager.Next(paginationCursor(ret), "")
+		s.Require().NoError(err)
+		s.True(strings.HasPrefix(next, "# Output continued: bytes 1-5 of 10\n😀\n# Output truncated"), next)
+		next, err = pager.Next(paginationCursor(next), "")
+		s.Require().NoError(err)
+		s.True(strings.HasPrefix(next, "# Output continued: bytes 5-6 of 10\nb\n# Output truncated"), next)
+		last, err := pager.Next(paginationCursor(next), "")
+		s.Require().NoError(err)
+		s.Equal("# Output continued: bytes 6-10 of 10 (end of output)\n😀", last)
+	})
+}
+
+func (s *PaginationSuite) TestNext() {
+	content := "line 1\nline 2\nline 3\n"
+	s.Run("returns the remaining chunks until the end of the output", func() {
+		pager := NewResultPager(7)
+		ret := pager.Paginate(content, "session-1")
+		next, err := pager.Next(paginationCursor(ret), "session-1")
+		s.Require().NoError(err)
+		s.True(strings.HasPrefix(next, "# Output continued: bytes 7-14 of 21\nline 2\n# Output truncated"), next)
+		last, err := pager.Next(paginationCursor(next), "session-1")
+		s.Require().NoError(err)
+		s.Equal("# Output continued: bytes 14-21 of 21 (end of output)\nline 3\n", last)
+	})
+	s.Run("cursor can only be used once", func() {
+		pager := NewResultPager(7)
+		cursor := paginationCursor(pager.Paginate(content, ""))
+		_, err := pager.Next(cursor, "")
+		s.Require().NoError(err)
+		_, err = pager.Next(cursor, "")
+		s.EqualError(err, "invalid or expired cursor, call the original tool again")
+	})
+	s.Run("cursor can only be used by the same session", func() {
+		pager := NewResultPager(7)
+		cursor := paginationCursor(pager.Paginate(content, "session-1"))
+		_, err := pager.Next(cursor, "session-2")
+		s.EqualError(err, "cursor was issued for a different MCP session")
+		_, err = pager.Next(cursor, "session-1")
+		s.NoError(err, "cursor should still be usable by its session")
+	})
+	s.Run("expired cursor", func() {
+		pager := NewResultPager(7)
+		cursor := paginationCursor(pager.Paginate(content, ""))
+		pager.now = func() time.Time { return time.Now().Add(resultCursorTTL + time.Second) }
+		_, err := pager.Next(cursor, "")
+		s.EqualError(err, "invalid or expired cursor, call the original tool again")
+	})
+}
+
+func TestPagination(t *testing.T) {
+	suite.Run(t, new(PaginationSuite))
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// MutationRequest describes the call of a mutating tool (not annotated with readOnlyHint=true) to the mutation
+// policies, evaluated before the tool is invoked
+type MutationRequest struct {
+	Tool      string
+	Arguments map[string]any
+	// Object is the manifest of the object the tool creates or updates (see ServerTool.ManifestArgument and
+	// ServerTool.Manifest),
+	// nil if the tool doesn't take a manifest
+	Object     map[string]any
+	APIVersion string
+	Kind       string
+	// Group and Resource are the API group and plural resource of the mutating permission of the tool (e.g. pods for
+	// pods_delete), for the tools whose target kind is not provided in their arguments
+	Group     string
+	Resource  string
+	Namespace string
+	Name      string
+}
+
+// MutationPolicy evaluates the mutating tool calls before their handler is invoked.
+// The calls are denied when a policy returns a reason or an error.
+type MutationPolicy interface {
+	// Evaluate returns the reasons why the request is denied, none if it's allowed
+	Evaluate(ctx context.Context, request MutationRequest) ([]string, error)
+}
+
+// NewMutationRequests returns the requests the mutation policies evaluate for the call of a mutating tool.
+// Each object of the manifest argument or of the rendered manifest (multi-document YAML or JSON) is evaluated on its
+// own, the target of the tools without a manifest is read from their apiVersion, kind, namespace, and name arguments.
+func NewMutationRequests(tool ServerTool, toolCallRequest ToolCallRequest) ([]MutationRequest, error) {
+	arguments := toolCallRequest.GetArguments()
+	request := MutationRequest{Tool: tool.Tool.Name, Arguments: arguments}
+	request.APIVersion, _ = arguments["apiVersion"].(string)
+	request.Kind, _ = arguments["kind"].(string)
+	request.Namespace, _ = arguments["namespace"].(string)
+	request.Name, _ = arguments["name"].(string)
+	for _, permission := range tool.Permissions {
+		if permission.Verb != "get" && permission.Verb != "list" && permission.Verb != "watch" {
+			request.Group, request.Resource = permission.Group, permission.Resource
+			break
+		}
+	}
+	manifest, _ := arguments[tool.ManifestArgument].(string)
+	source := tool.ManifestArgument + " argument"
+	if tool.Manifest != nil {
+		var err error
+		if manifest, err = tool.Manifest(arguments); err != nil {
+			return nil, fmt.Errorf("failed to render the manifest: %w", err)
+		}
+		source = "rendered manifest"
+	} else if tool.ManifestArgument == "" {
+		return []MutationRequest{request}, nil
+	}
+	if strings.TrimSpace(manifest) == "" {
+		return []MutationRequest{request}, nil
+	}
+	var requests []MutationRequest
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for {
+		var object map[string]any
+		if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", source, err)
+		}
+		if object == nil {
+			continue
+		}
+		objectRequest := request
+		objectRequest.Object = object
+		objectRequest.APIVersion, _ = object["apiVersion"].(string)
+		objectRequest.Kind, _ = object["kind"].(string)
+		if metadata, ok := object["metadata"].(map[string]any); ok {
+			objectRequest.Namespace, _ = metadata["namespace"].(string)
+			objectRequest.Name, _ = metadata["name"].(string)
+		}
+		requests = append(requests, objectRequest)
+	}
+	return requests, nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicySuite struct {
+	suite.Suite
+}
+
+func (s *PolicySuite) TestNewMutationRequests() {
+	s.Run("reads the target from the arguments", func() {
+		tool := ServerTool{Tool: Tool{Name: "resources_delete"}}
+		requests, err := NewMutationRequests(tool, argumentsRequest{"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "shop", "name": "web"})
+		s.Require().NoError(err)
+		s.Equal([]MutationRequest{{
+			Tool:       "resources_delete",
+			Arguments:  map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "shop", "name": "web"},
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Namespace:  "shop",
+			Name:       "web",
+		}}, requests)
+	})
+	s.Run("reads the resource from the mutating permission", func() {
+		tool := ServerTool{Tool: Tool{Name: "pods_delete"}, Permissions: []ResourcePermission{
+			{Verb: "get", Resource: "pods"},
+			{Verb: "delete", Resource: "pods"},
+		}}
+		requests, err := NewMutationRequests(tool, argumentsRequest{"name": "web-1"})
+		s.Require().NoError(err)
+		s.Require().Len(requests, 1)
+		s.Equal("pods", requests[0].Resource)
+		s.Empty(requests[0].Group)
+		s.Empty(requests[0].Kind)
+		s.Equal("web-1", requests[0].Name)
+	})
+	s.Run("returns one request per object of the manifest", func() {
+		tool := ServerTool{Tool: Tool{Name: "resources_create_or_update"}, ManifestArgument: "resource"}
+		requests, err := NewMutationRequests(tool, argumentsRequest{"resource": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: shop
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+`})
+		s.Require().NoError(err)
+		s.Require().Len(requests, 2)
+		s.Equal("ConfigMap", requests[0].Kind)
+		s.Equal("shop", requests[0].Namespace)
+		s.Equal("settings", requests[0].Name)
+		s.Equal(map[string]any{"name": "settings", "namespace": "shop"}, requests[0].Object["metadata"])
+		s.Equal("Namespace", requests[1].Kind)
+		s.Empty(requests[1].Namespace)
+	})
+	s.Run("reads JSON manifests", func() {
+		tool := ServerTool{Tool: Tool{Name: "resources_create_or_update"}, ManifestArgument: "resource"}
+		requests, err := NewMutationRequests(tool, argumentsRequest{"resource": `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web"}}`})
+		s.Require().NoError(err)
+		s.Require().Len(requests, 1)
+		s.Equal("Pod", requests[0].Kind)
+	})
+	s.Run("invalid manifest", func() {
+		tool := ServerTool{Tool: Tool{Name: "resources_create_or_update"}, ManifestArgument: "resource"}
+		_, err := NewMutationRequests(tool, argumentsRequest{"resource": "kind: [Pod"})
+		s.ErrorContains(err, "invalid resource argument")
+	})
+	s.Run("returns one request per object of the rendered manifest", func() {
+		tool := ServerTool{Tool: Tool{Name: "kustomize_apply"}, Manifest: func(arguments map[string]any) (string, error) {
+			return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + arguments["name"].(string) + "\n", nil
+		}}
+		requests, err := NewMutationRequests(tool, argumentsRequest{"name": "settings"})
+		s.Require().NoError(err)
+		s.Require().Len(requests, 1)
+		s.Equal("ConfigMap", requests[0].Kind)
+		s.Equal("settings", requests[0].Name)
+	})
+	s.Run("manifest failing to render", func() {
+		tool := ServerTool{Tool: Tool{Name: "kustomize_apply"}, Manifest: func(map[string]any) (string, error) {
+			return "", errors.New("missing kustomization")
+		}}
+		_, err := NewMutationRequests(tool, argumentsRequest{})
+		s.ErrorContains(err, "failed to render the manifest: missing kustomization")
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(PolicySuite))
+}
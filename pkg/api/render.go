@@ -0,0 +1,206 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// Formats of the built-in renderers
+const (
+	RenderTable    = "table"
+	RenderJson     = "json"
+	RenderYaml     = "yaml"
+	RenderMarkdown = "markdown"
+)
+
+// Renderer renders the results of the tools in an output format
+type Renderer interface {
+	// GetName returns the name of the output format, used to select the renderer in Render
+	GetName() string
+	// Render returns the object rendered in the output format
+	Render(obj any) (string, error)
+}
+
+// Table is tabular data, rendered as aligned columns by the table renderer, as a Markdown table by the markdown
+// renderer, and as a list of objects keyed by the column names by the json and yaml renderers
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// NewTable returns an empty table with the provided columns
+func NewTable(columns ...string) *Table {
+	return &Table{Columns: columns, Rows: [][]string{}}
+}
+
+// AddRow appends a row with the provided values, formatted with %v
+func (t *Table) AddRow(values ...any) {
+	row := make([]string, len(values))
+	for i, value := range values {
+		row[i] = fmt.Sprint(value)
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// records returns the rows as objects keyed by the column names
+func (t *Table) records() []map[string]string {
+	records := make([]map[string]string, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		record := make(map[string]string, len(t.Columns))
+		for i, column := range t.Columns {
+			if i < len(row) {
+				record[column] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{}
+)
+
+// RegisterRenderer makes the renderer available to Render, it replaces the renderer of the same output format
+func RegisterRenderer(renderer Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[renderer.GetName()] = renderer
+}
+
+// RendererFromString returns the renderer of the output format or nil if there's none
+func RendererFromString(format string) Renderer {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	return renderers[format]
+}
+
+// RendererNames returns the sorted output formats of the registered renderers
+func RendererNames() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Render renders the object in the output format with the registered renderer.
+// The values of the Secrets (Kubernetes objects) and the credentials matching the common token patterns are
+// redacted, the provided object is not modified. Like the rest of the tool outputs, the rendered text is truncated
+// by the server to the output_max_bytes budget (the remaining chunks are retrieved with continue_result).
+func Render(obj any, format string) (string, error) {
+	renderer := RendererFromString(format)
+	if renderer == nil {
+		return "", fmt.Errorf("invalid output format: %s, valid formats are: %s", format, strings.Join(RendererNames(), ", "))
+	}
+	if u, ok := obj.(runtime.Unstructured); ok {
+		if redacted, ok := u.DeepCopyObject().(runtime.Unstructured); ok {
+			output.RedactSecrets(redacted)
+			obj = redacted
+		}
+	}
+	rendered, err := renderer.Render(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", format, err)
+	}
+	return output.Redact(rendered), nil
+}
+
+type tableRenderer struct{}
+
+func (r *tableRenderer) GetName() string {
+	return RenderTable
+}
+
+func (r *tableRenderer) Render(obj any) (string, error) {
+	switch t := obj.(type) {
+	case *Table:
+		buf := new(bytes.Buffer)
+		w := printers.GetNewTabWriter(buf)
+		_, _ = fmt.Fprintln(w, strings.Join(t.Columns, "\t"))
+		for _, row := range t.Rows {
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		err := w.Flush()
+		return buf.String(), err
+	case runtime.Unstructured:
+		return output.Table.PrintObj(t)
+	}
+	return "", fmt.Errorf("unsupported type %T", obj)
+}
+
+type jsonRenderer struct{}
+
+func (r *jsonRenderer) GetName() string {
+	return RenderJson
+}
+
+func (r *jsonRenderer) Render(obj any) (string, error) {
+	if t, ok := obj.(*Table); ok {
+		obj = t.records()
+	}
+	marshalled, err := json.Marshal(obj)
+	return string(marshalled), err
+}
+
+type yamlRenderer struct{}
+
+func (r *yamlRenderer) GetName() string {
+	return RenderYaml
+}
+
+func (r *yamlRenderer) Render(obj any) (string, error) {
+	if t, ok := obj.(*Table); ok {
+		obj = t.records()
+	}
+	return output.MarshalYaml(obj)
+}
+
+type markdownRenderer struct{}
+
+func (r *markdownRenderer) GetName() string {
+	return RenderMarkdown
+}
+
+// Render returns a Markdown table for the tables and a YAML code block for the rest of the objects
+func (r *markdownRenderer) Render(obj any) (string, error) {
+	t, ok := obj.(*Table)
+	if !ok {
+		marshalled, err := output.MarshalYaml(obj)
+		return "```yaml\n" + marshalled + "```\n", err
+	}
+	ret := &strings.Builder{}
+	writeRow := func(values []string) {
+		ret.WriteString("|")
+		for _, value := range values {
+			ret.WriteString(" " + strings.NewReplacer("|", `\|`, "\n", " ").Replace(value) + " |")
+		}
+		ret.WriteString("\n")
+	}
+	writeRow(t.Columns)
+	ret.WriteString("|" + strings.Repeat(" --- |", len(t.Columns)) + "\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	return ret.String(), nil
+}
+
+func init() {
+	RegisterRenderer(&tableRenderer{})
+	RegisterRenderer(&jsonRenderer{})
+	RegisterRenderer(&yamlRenderer{})
+	RegisterRenderer(&markdownRenderer{})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type RenderSuite struct {
+	suite.Suite
+	table *Table
+}
+
+func (s *RenderSuite) SetupTest() {
+	s.table = NewTable("NAME", "STATUS")
+	s.table.AddRow("node-1", "Ready")
+	s.table.AddRow("node-2", "Not|Ready")
+}
+
+func (s *RenderSuite) TestRendererNames() {
+	s.Equal([]string{"json", "markdown", "table", "yaml"}, RendererNames())
+}
+
+func (s *RenderSuite) TestRenderTable() {
+	s.Run("table", func() {
+		rendered, err := Render(s.table, RenderTable)
+		s.Require().NoError(err)
+		s.Equal("NAME     STATUS\nnode-1   Ready\nnode-2   Not|Ready\n", rendered)
+	})
+	s.Run("markdown", func() {
+		rendered, err := Render(s.table, RenderMarkdown)
+		s.Require().NoError(err)
+		s.Equal("| NAME | STATUS |\n| --- | --- |\n| node-1 | Ready |\n| node-2 | Not\\|Ready |\n", rendered)
+	})
+	s.Run("json", func() {
+		rendered, err := Render(s.table, RenderJson)
+		s.Require().NoError(err)
+		s.JSONEq(`[{"NAME":"node-1","STATUS":"Ready"},{"NAME":"node-2","STATUS":"Not|Ready"}]`, rendered)
+	})
+	s.Run("yaml", func() {
+		rendered, err := Render(s.table, RenderYaml)
+		s.Require().NoError(err)
+		s.Equal("- NAME: node-1\n  STATUS: Ready\n- NAME: node-2\n  STATUS: Not|Ready\n", rendered)
+	})
+}
+
+func (s *RenderSuite) TestRenderObject() {
+	s.Run("yaml", func() {
+		rendered, err := Render(map[string]any{"name": "node-1"}, RenderYaml)
+		s.Require().NoError(err)
+		s.Equal("name: node-1\n", rendered)
+	})
+	s.Run("markdown renders a yaml code block", func() {
+		rendered, err := Render(map[string]any{"name": "node-1"}, RenderMarkdown)
+		s.Require().NoError(err)
+		s.Equal("```yaml\nname: node-1\n```\n", rendered)
+	})
+	s.Run("table doesn't support arbitrary objects", func() {
+		_, err := Render(map[string]any{"name": "node-1"}, RenderTable)
+		s.EqualError(err, "failed to render table: unsupported type map[string]interface {}")
+	})
+}
+
+func (s *RenderSuite) TestRenderRedacts() {
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1", "kind": "Secret",
+		"metadata": map[string]any{"name": "credentials"},
+		"data":     map[string]any{"password": "c2VjcmV0"},
+	}}
+	s.Run("secret values", func() {
+		rendered, err := Render(secret, RenderYaml)
+		s.Require().NoError(err)
+		s.Contains(rendered, "password: <redacted, 6 bytes>")
+		s.Equal("c2VjcmV0", secret.Object["data"].(map[string]any)["password"], "the provided object is not modified")
+	})
+	s.Run("credentials", func() {
+		table := NewTable("NAME", "VALUE")
+		table.AddRow("header", "Bearer 0123456789abcdef0123")
+		rendered, err := Render(table, RenderMarkdown)
+		s.Require().NoError(err)
+		s.Contains(rendered, "| header | Bearer <redacted> |")
+	})
+}
+
+func (s *RenderSuite) TestRenderInvalidFormat() {
+	_, err := Render(s.table, "csv")
+	s.EqualError(err, "invalid output format: csv, valid formats are: json, markdown, table, yaml")
+}
+
+type csvRenderer struct{}
+
+func (r *csvRenderer) GetName() string {
+	return "csv"
+}
+
+func (r *csvRenderer) Render(_ any) (string, error) {
+	return "NAME,STATUS\n", nil
+}
+
+func (s *RenderSuite) TestRegisterRenderer() {
+	RegisterRenderer(&csvRenderer{})
+	s.T().Cleanup(func() {
+		renderersMu.Lock()
+		defer renderersMu.Unlock()
+		delete(renderers, "csv")
+	})
+	rendered, err := Render(s.table, "csv")
+	s.Require().NoError(err)
+	s.Equal("NAME,STATUS\n", rendered)
+}
+
+func TestRender(t *testing.T) {
+	suite.Run(t, new(RenderSuite))
+}
@@ -3,19 +3,50 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
 	"github.com/google/jsonschema-go/jsonschema"
 )
 
+// Names of the tools added by the MCP server itself rather than by a toolset (see also ContinueResultToolName)
+const (
+	// ElevateToolName is the name of the break-glass tool that temporarily enables the destructive tools
+	ElevateToolName = "elevate"
+	// ConfirmActionToolName is the name of the tool that performs the destructive actions put on hold for confirmation
+	ConfirmActionToolName = "confirm_action"
+	// SelfCheckToolName is the name of the tool that reviews the connection to the cluster and the tool permissions
+	SelfCheckToolName = "self_check"
+	// SessionConfigureToolName is the name of the tool that configures the defaults of the MCP session
+	SessionConfigureToolName = "session_configure"
+)
+
+// ServerToolNames are the names of the tools added by the MCP server itself, the toolsets (e.g. the plugins) can't
+// provide tools with these names
+var ServerToolNames = []string{ContinueResultToolName, ElevateToolName, ConfirmActionToolName, SelfCheckToolName, SessionConfigureToolName}
+
 type ServerTool struct {
 	Tool               Tool
 	Handler            ToolHandlerFunc
 	ClusterAware       *bool
 	TargetListProvider *bool
+	DryRunSupported    *bool
+	// Permissions are the Kubernetes API permissions needed by the tool, reviewed by the self_check tool.
+	// Left empty for the tools whose permissions depend on their arguments (e.g. the resource kind)
+	Permissions []ResourcePermission
+	// ManifestArgument is the name of the argument holding the manifest (YAML or JSON) of the objects the tool creates
+	// or updates, the objects are evaluated by the mutation policies
+	ManifestArgument string
+	// Manifest renders the manifest of the objects the tool creates or updates from its arguments, for the tools that
+	// don't take the manifest as an argument (e.g. kustomize_apply), the objects are evaluated by the mutation policies
+	Manifest func(arguments map[string]any) (string, error)
 }
 
+// ResourcePermission is a permission on a Kubernetes API resource, e.g. list pods or create pods/exec
+type ResourcePermission = internalk8s.ResourcePermission
+
 // IsClusterAware indicates whether the tool can accept a "cluster" or "context" parameter
 // to operate on a specific Kubernetes cluster context.
 // Defaults to true if not explicitly set
@@ -35,6 +66,17 @@ func (s *ServerTool) IsTargetListProvider() bool {
 	return false
 }
 
+// IsDryRunSupported indicates whether the tool performs its changes honoring the Kubernetes server-side dry-run
+// requested through the handler context (see kubernetes.WithDryRun).
+// Mutating tools that don't support it are never invoked in dry-run mode, the call is described instead.
+// Defaults to false if not explicitly set
+func (s *ServerTool) IsDryRunSupported() bool {
+	if s.DryRunSupported != nil {
+		return *s.DryRunSupported
+	}
+	return false
+}
+
 type Toolset interface {
 	// GetName returns the name of the toolset.
 	// Used to identify the toolset in configuration, logs, and command-line arguments.
@@ -55,6 +97,9 @@ type ToolCallResult struct {
 	Content string
 	// Error (non-protocol) to send back to the LLM.
 	Error error
+	// Revealed is set when the content includes sensitive values explicitly requested by the user (e.g. Secret values),
+	// the content is not redacted.
+	Revealed bool
 }
 
 func NewToolCallResult(content string, err error) *ToolCallResult {
@@ -64,15 +109,65 @@ func NewToolCallResult(content string, err error) *ToolCallResult {
 	}
 }
 
+// NewPartialToolCallResult creates a result for tools that aggregate data across multiple targets
+// (nodes, namespaces, clusters).
+// The content retrieved from the successful targets is returned along with a structured list of the
+// targets that failed. The call is only considered failed if none of the targets succeeded.
+func NewPartialToolCallResult(content string, succeeded int, targetErrors internalk8s.TargetErrors) *ToolCallResult {
+	if len(targetErrors) == 0 {
+		return NewToolCallResult(content, nil)
+	}
+	if succeeded == 0 {
+		return NewToolCallResult("", fmt.Errorf("failed for all targets: %w", targetErrors))
+	}
+	marshalledYaml, err := output.MarshalYaml(map[string]any{"targetErrors": targetErrors})
+	if err != nil {
+		return NewToolCallResult(content, fmt.Errorf("failed to marshal target errors: %w", err))
+	}
+	return NewToolCallResult(
+		strings.TrimSuffix(content, "\n")+"\n"+
+			fmt.Sprintf("# Partial result: %d of %d targets failed\n", len(targetErrors), succeeded+len(targetErrors))+
+			marshalledYaml,
+		nil)
+}
+
 type ToolHandlerParams struct {
 	context.Context
 	*internalk8s.Kubernetes
 	ToolCallRequest
 	ListOutput output.Output
+	// InputSchema is the input schema of the called tool, used by ParseArguments to validate and default the arguments
+	InputSchema *jsonschema.Schema
+}
+
+// ProgressReporter receives the stages of long-running tool calls, they are sent to the client as MCP progress
+// notifications when the client requested them (progressToken of the tool call).
+// The operations of the Kubernetes layer report their stages through the handler context.
+type ProgressReporter = internalk8s.ProgressReporter
+
+// ReportProgress reports a stage of the tool call (e.g. "uploading bundle"), it's a no-op if the client didn't
+// request progress notifications
+func (p ToolHandlerParams) ReportProgress(format string, args ...any) {
+	internalk8s.ReportProgress(p.Context, format, args...)
+}
+
+// ToolHandler handles the calls of a ServerTool.
+// Tool errors that should be reported back to the LLM must be returned in the ToolCallResult,
+// the returned error is reserved for protocol-level failures.
+type ToolHandler interface {
+	Handle(params ToolHandlerParams) (*ToolCallResult, error)
 }
 
+// ToolHandlerFunc is an adapter to use ordinary functions as a ToolHandler
 type ToolHandlerFunc func(params ToolHandlerParams) (*ToolCallResult, error)
 
+var _ ToolHandler = ToolHandlerFunc(nil)
+
+// Handle calls f(params)
+func (f ToolHandlerFunc) Handle(params ToolHandlerParams) (*ToolCallResult, error) {
+	return f(params)
+}
+
 type Tool struct {
 	// The name of the tool.
 	// Intended for programmatic or logical use, but used as a display name in past
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"errors"
 	"testing"
 
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/stretchr/testify/suite"
 	"k8s.io/utils/ptr"
 )
@@ -42,6 +44,33 @@ func (s *ToolsetsSuite) TestServerTool() {
 	})
 }
 
+func (s *ToolsetsSuite) TestNewPartialToolCallResult() {
+	s.Run("without target errors returns content", func() {
+		result := NewPartialToolCallResult("content\n", 2, nil)
+		s.NoError(result.Error)
+		s.Equal("content\n", result.Content)
+	})
+	s.Run("with some failed targets returns content and target errors", func() {
+		var targetErrors internalk8s.TargetErrors
+		targetErrors.Add("node/node-2", errors.New("kubelet unreachable"))
+		result := NewPartialToolCallResult("content\n", 1, targetErrors)
+		s.NoError(result.Error)
+		s.Equal("content\n"+
+			"# Partial result: 1 of 2 targets failed\n"+
+			"targetErrors:\n"+
+			"- error: kubelet unreachable\n"+
+			"  target: node/node-2\n", result.Content)
+	})
+	s.Run("with all failed targets returns error", func() {
+		var targetErrors internalk8s.TargetErrors
+		targetErrors.Add("node/node-1", errors.New("kubelet unreachable"))
+		targetErrors.Add("node/node-2", errors.New("timeout"))
+		result := NewPartialToolCallResult("", 0, targetErrors)
+		s.EqualError(result.Error, "failed for all targets: node/node-1: kubelet unreachable; node/node-2: timeout")
+		s.Empty(result.Content)
+	})
+}
+
 func TestToolsets(t *testing.T) {
 	suite.Run(t, new(ToolsetsSuite))
 }
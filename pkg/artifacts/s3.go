@@ -0,0 +1,100 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+const s3DefaultRegion = "us-east-1"
+
+// upload puts the object in the bucket with a path-style request (<endpoint>/<bucket>/<prefix><key>) signed with
+// AWS Signature Version 4, supported by AWS S3 and by the S3-compatible object storages.
+// The errors are returned unwrapped, the callers describe the uploaded object.
+func upload(ctx context.Context, c *config.ObjectStorage, key, contentType string, content []byte) (string, error) {
+	secretAccessKey := c.SecretAccessKey
+	if c.SecretAccessKeyFile != "" {
+		secret, err := os.ReadFile(c.SecretAccessKeyFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read s3 secret access key file: %w", err)
+		}
+		secretAccessKey = strings.TrimSpace(string(secret))
+	}
+	objectURL, err := url.Parse(strings.TrimSuffix(c.Endpoint, "/") + "/" + c.Bucket + "/" + c.Prefix + key)
+	if err != nil {
+		return "", fmt.Errorf("failed to build s3 object URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	sign(c, req, content, secretAccessKey, time.Now().UTC())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return objectURL.String(), nil
+}
+
+// sign sets the x-amz-* and Authorization headers of the request (https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html)
+func sign(c *config.ObjectStorage, req *http.Request, payload []byte, secretAccessKey string, now time.Time) {
+	region := c.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"content-type:" + req.Header.Get("Content-Type"),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	key := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,275 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+const (
+	// URIScheme is the scheme of the MCP resource URIs of the artifacts: artifact://<name>
+	URIScheme = "artifact://"
+	// DefaultDirName is the directory of the temporary directory where the artifacts are written by default
+	DefaultDirName = "kubernetes-mcp-server-artifacts"
+	// gcsRegion is the region of the signed requests to the XML API of Google Cloud Storage
+	gcsRegion = "auto"
+)
+
+// ErrNotFound is returned when the requested artifact doesn't exist (or has expired)
+var ErrNotFound = errors.New("artifact not found")
+
+var (
+	namePattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	nameInvalidChar = regexp.MustCompile(`[^a-z0-9.]+`)
+)
+
+// Artifact is a file generated by a tool (diagnostic bundle, node file, log) persisted in the store
+type Artifact struct {
+	Name string `json:"name"`
+	// URI is the artifact:// URI of the MCP resource of the artifact
+	URI      string    `json:"uri"`
+	MIMEType string    `json:"mimeType"`
+	Size     int64     `json:"size"`
+	Created  time.Time `json:"created"`
+	// Path is the local path of the artifact
+	Path string `json:"path"`
+	// URL of the uploaded object, only returned when the artifact is stored
+	URL string `json:"url,omitempty"`
+}
+
+// Store persists the artifacts in a local directory, where the retention policies apply, and uploads them to the
+// S3-compatible object storage (S3 or GCS) if configured.
+// The store is stateless, the artifacts are the files of the local directory, expired artifacts are removed when
+// artifacts are stored or listed.
+type Store struct {
+	dir       string
+	retention time.Duration
+	maxCount  int
+	maxBytes  int64
+	// remote is the object storage where the artifacts are uploaded, remoteName its type (s3 or gcs)
+	remote     *config.ObjectStorage
+	remoteName string
+	now        func() time.Time
+}
+
+// NewStore returns the artifact store of the artifacts configuration of the StaticConfig
+func NewStore(cfg *config.StaticConfig) *Store {
+	s := &Store{
+		dir:       cfg.Artifacts.Dir,
+		retention: cfg.ArtifactsRetention(),
+		maxCount:  cfg.Artifacts.MaxCount,
+		maxBytes:  cfg.Artifacts.MaxBytes,
+		now:       time.Now,
+	}
+	if s.dir == "" {
+		s.dir = filepath.Join(os.TempDir(), DefaultDirName)
+	}
+	if cfg.Artifacts.S3 != nil {
+		s.remote, s.remoteName = cfg.Artifacts.S3, "s3"
+	} else if cfg.Artifacts.GCS != nil {
+		gcs := *cfg.Artifacts.GCS
+		if gcs.Endpoint == "" {
+			gcs.Endpoint = config.GCSEndpoint
+		}
+		if gcs.Region == "" {
+			gcs.Region = gcsRegion
+		}
+		s.remote, s.remoteName = &gcs, "gcs"
+	}
+	return s
+}
+
+// WithLocation returns a copy of the store that writes the artifacts to the provided directory and uploads them to
+// the provided S3 object storage, the empty ones are not overridden.
+// The retention policies don't apply to the overridden directory since it may be shared with other files.
+func (s *Store) WithLocation(dir string, s3 *config.ObjectStorage) *Store {
+	ret := *s
+	if dir != "" {
+		ret.dir, ret.retention, ret.maxCount, ret.maxBytes = dir, 0, 0, 0
+	}
+	if s3 != nil {
+		ret.remote, ret.remoteName = s3, "s3"
+	}
+	return &ret
+}
+
+// RemoteName returns the type of the object storage where the artifacts are uploaded (s3 or gcs), empty if none
+func (s *Store) RemoteName() string {
+	return s.remoteName
+}
+
+// NewName returns a unique artifact name built from the provided (non-empty) parts and the extension, e.g.
+// pod-log-default-web-<time>-<id>.log
+func NewName(now time.Time, extension string, parts ...string) string {
+	id := make([]byte, 4)
+	_, _ = rand.Read(id)
+	segments := make([]string, 0, len(parts)+2)
+	for _, part := range parts {
+		if segment := strings.Trim(nameInvalidChar.ReplaceAllString(strings.ToLower(part), "-"), "-."); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	segments = append(segments, now.UTC().Format("20060102-150405"), hex.EncodeToString(id))
+	return strings.Join(segments, "-") + nameInvalidChar.ReplaceAllString(strings.ToLower(extension), "")
+}
+
+// NameFromURI returns the name of the artifact referenced by the artifact:// URI
+func NameFromURI(uri string) (string, bool) {
+	name, ok := strings.CutPrefix(uri, URIScheme)
+	return name, ok && namePattern.MatchString(name)
+}
+
+// Put writes the content into the <name> artifact of the local directory, removes the expired artifacts, and
+// uploads the artifact to the object storage if configured.
+// If the upload fails, the locally stored artifact is returned along with the (unwrapped) upload error.
+func (s *Store) Put(ctx context.Context, name string, content []byte) (*Artifact, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid artifact name %q", name)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory %s: %w", s.dir, err)
+	}
+	localPath := filepath.Join(s.dir, name)
+	if err := os.WriteFile(localPath, content, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write artifact %s: %w", localPath, err)
+	}
+	artifact := s.artifact(name, int64(len(content)), s.now())
+	if err := s.prune(name); err != nil {
+		return nil, err
+	}
+	if s.remote == nil {
+		return artifact, nil
+	}
+	objectURL, err := upload(ctx, s.remote, name, artifact.MIMEType, content)
+	if err != nil {
+		return artifact, err
+	}
+	artifact.URL = objectURL
+	return artifact, nil
+}
+
+// Get returns the artifact with the provided name and its content
+func (s *Store) Get(name string) (*Artifact, []byte, error) {
+	if !namePattern.MatchString(name) {
+		return nil, nil, fmt.Errorf("invalid artifact name %q", name)
+	}
+	localPath := filepath.Join(s.dir, name)
+	info, err := os.Stat(localPath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read artifact %s: %w", name, err)
+	}
+	return s.artifact(name, info.Size(), info.ModTime()), content, nil
+}
+
+// List returns the artifacts of the local directory, the most recent first, after removing the expired ones
+func (s *Store) List() ([]Artifact, error) {
+	if err := s.prune(""); err != nil {
+		return nil, err
+	}
+	return s.list()
+}
+
+func (s *Store) list() ([]Artifact, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Artifact{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts directory %s: %w", s.dir, err)
+	}
+	artifacts := make([]Artifact, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !namePattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// removed in the meantime
+			continue
+		}
+		artifacts = append(artifacts, *s.artifact(entry.Name(), info.Size(), info.ModTime()))
+	}
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		if !artifacts[i].Created.Equal(artifacts[j].Created) {
+			return artifacts[i].Created.After(artifacts[j].Created)
+		}
+		return artifacts[i].Name < artifacts[j].Name
+	})
+	return artifacts, nil
+}
+
+// prune removes the artifacts older than the retention, and the oldest artifacts exceeding the maximum count or
+// total size, except the provided one (just stored)
+func (s *Store) prune(keep string) error {
+	if s.retention <= 0 && s.maxCount <= 0 && s.maxBytes <= 0 {
+		return nil
+	}
+	artifacts, err := s.list()
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	count, total := 0, int64(0)
+	for _, artifact := range artifacts {
+		expired := s.retention > 0 && now.Sub(artifact.Created) > s.retention
+		exceeded := (s.maxCount > 0 && count+1 > s.maxCount) || (s.maxBytes > 0 && total+artifact.Size > s.maxBytes)
+		if artifact.Name != keep && (expired || exceeded) {
+			if err = os.Remove(artifact.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to remove expired artifact %s: %w", artifact.Name, err)
+			}
+			continue
+		}
+		count++
+		total += artifact.Size
+	}
+	return nil
+}
+
+func (s *Store) artifact(name string, size int64, created time.Time) *Artifact {
+	return &Artifact{
+		Name:     name,
+		URI:      URIScheme + name,
+		MIMEType: MIMEType(name),
+		Size:     size,
+		Created:  created.UTC(),
+		Path:     filepath.Join(s.dir, name),
+	}
+}
+
+// MIMEType returns the MIME type of the artifact with the provided name, by extension
+func MIMEType(name string) string {
+	switch {
+	case strings.HasSuffix(name, ".log"), strings.HasSuffix(name, ".txt"), strings.HasSuffix(name, ".conf"):
+		return "text/plain"
+	case strings.HasSuffix(name, ".yaml"), strings.HasSuffix(name, ".yml"):
+		return "application/yaml"
+	case strings.HasSuffix(name, ".json"):
+		return "application/json"
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"), strings.HasSuffix(name, ".gz"):
+		return "application/gzip"
+	}
+	return "application/octet-stream"
+}
+
+// IsText returns true if the artifacts with the provided MIME type are text files
+func IsText(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || mimeType == "application/yaml" || mimeType == "application/json"
+}
@@ -3,9 +3,18 @@ package config
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -16,23 +25,130 @@ const (
 	ClusterProviderDisabled   = "disabled"
 )
 
+const (
+	ToolProfileReadOnly = "read-only"
+	ToolProfileOperator = "operator"
+	ToolProfileAdmin    = "admin"
+)
+
+// ToolProfiles are the valid values for the tool_profile configuration option
+var ToolProfiles = []string{ToolProfileReadOnly, ToolProfileOperator, ToolProfileAdmin}
+
+// nodeArchitecture matches the node architectures of the per-architecture helper images (kubernetes.io/arch, e.g. arm64)
+var nodeArchitecture = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// windowsAbsNodePath matches the absolute paths of the Windows nodes (e.g. C:\ProgramData), the other node paths are POSIX
+var windowsAbsNodePath = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+const (
+	LogFormatText = "text"
+	LogFormatJson = "json"
+)
+
+// LogFormats are the valid values for the log_format configuration option
+var LogFormats = []string{LogFormatText, LogFormatJson}
+
+const (
+	PolicyEngineCEL = "cel"
+)
+
+// PolicyEngines are the valid values for the engine of the policies
+var PolicyEngines = []string{PolicyEngineCEL}
+
 // StaticConfig is the configuration for the server.
 // It allows to configure server specific settings and tools to be enabled or disabled.
 type StaticConfig struct {
 	DeniedResources []GroupVersionKind `toml:"denied_resources"`
+	// TargetNamespaces restricts the server to the listed namespaces: the requests to the other namespaces, across all
+	// namespaces, to the nodes, and the changes to the cluster-scoped resources are denied, and the tools needing
+	// cluster-wide permissions are not exposed. The server is not restricted if empty.
+	TargetNamespaces []string `toml:"target_namespaces,omitempty"`
 
 	LogLevel   int    `toml:"log_level,omitzero"`
 	Port       string `toml:"port,omitempty"`
 	SSEBaseURL string `toml:"sse_base_url,omitempty"`
 	KubeConfig string `toml:"kubeconfig,omitempty"`
 	ListOutput string `toml:"list_output,omitempty"`
+	// LogFormat is the format of the server logs in HTTP mode: text (default) or json.
+	// Both formats are structured, the tool calls are logged with their requestId, sessionId, tool and cluster.
+	LogFormat string `toml:"log_format,omitempty"`
 	// When true, expose only tools annotated with readOnlyHint=true
 	ReadOnly bool `toml:"read_only,omitempty"`
 	// When true, disable tools annotated with destructiveHint=true
-	DisableDestructive bool     `toml:"disable_destructive,omitempty"`
-	Toolsets           []string `toml:"toolsets,omitempty"`
-	EnabledTools       []string `toml:"enabled_tools,omitempty"`
-	DisabledTools      []string `toml:"disabled_tools,omitempty"`
+	DisableDestructive bool `toml:"disable_destructive,omitempty"`
+	// ToolProfile controls which tools are exposed based on their annotations:
+	// "read-only" exposes only tools annotated with readOnlyHint=true,
+	// "operator" disables the tools annotated with destructiveHint=true,
+	// "admin" (default) exposes all tools.
+	// It's combined with ReadOnly and DisableDestructive, the most restrictive setting applies.
+	ToolProfile string `toml:"tool_profile,omitempty"`
+	// When true, the mutating tools run in "plan only" mode: changes are validated with Kubernetes server-side
+	// dry-run where supported, the rest of the mutating tools only describe the operation they would perform.
+	DryRun bool `toml:"dry_run,omitempty"`
+	// When true, the tools annotated with destructiveHint=true don't perform their action right away, they return
+	// a signed confirmation token describing the pending action that must be passed to the confirm_action tool.
+	// This lets MCP hosts insert a human approval step between planning and execution.
+	RequireConfirmation bool `toml:"require_confirmation,omitempty"`
+	// When true, the secrets tool returns the decoded Secret values when explicitly requested (reveal=true).
+	// Secret values and common credential patterns are redacted from every tool output otherwise.
+	AllowSecretReveal bool `toml:"allow_secret_reveal,omitempty"`
+	// BreakGlassMaxDuration, when set (e.g. "30m"), hides the tools annotated with destructiveHint=true until they are
+	// enabled for a bounded time window with the elevate tool. The server reverts to the restricted tool set once the
+	// window expires. Elevations can't be requested for longer than this duration.
+	BreakGlassMaxDuration string `toml:"break_glass_max_duration,omitempty"`
+	// BreakGlassToken is the secret that must be provided to the elevate tool.
+	// It's required unless require_oauth is enabled (the elevation is then granted to authenticated clients).
+	BreakGlassToken string `toml:"break_glass_token,omitempty"`
+	// OutputMaxBytes is the maximum size of a tool output (roughly 4 bytes per token), larger outputs are truncated
+	// and the remaining chunks are retrieved with the continue_result tool. Outputs are never truncated if 0.
+	OutputMaxBytes int      `toml:"output_max_bytes,omitzero"`
+	Toolsets       []string `toml:"toolsets,omitempty"`
+	// EnabledTools, when set, restricts the registered tools to the ones matching any of the entries.
+	// Entries are tool names or glob patterns (e.g. "pods_*").
+	EnabledTools []string `toml:"enabled_tools,omitempty"`
+	// DisabledTools prevents the tools matching any of the entries from being registered.
+	// Entries are tool names or glob patterns (e.g. "node_*").
+	DisabledTools []string `toml:"disabled_tools,omitempty"`
+	// PluginsDir is the directory of the toolset plugins, executables providing out-of-tree toolsets that are loaded
+	// at startup and enabled like the built-in ones with Toolsets.
+	PluginsDir string `toml:"plugins_dir,omitempty"`
+	// Timeouts are the maximum durations of the tool calls by tool name (e.g. node_files = "5m"), the calls that don't
+	// complete in time are cancelled. Helper pods wait up to the tool timeout instead of the default 2 minutes.
+	Timeouts map[string]string `toml:"timeouts,omitempty"`
+	// CacheTTLs are the durations the results of the read-only tools are cached for by tool name
+	// (e.g. nodes_top = "30s"), the repeated calls with the same arguments in a session return the cached result
+	// until it expires. The tool results are not cached if the tool has no entry.
+	CacheTTLs map[string]string `toml:"cache_ttls,omitempty"`
+	// ToolCallsPerMinute limits the rate of the tool calls of each MCP session, the calls above the limit fail with a
+	// "rate limited, retry after" error. The tool calls are not rate limited if 0.
+	ToolCallsPerMinute int `toml:"tool_calls_per_minute,omitzero"`
+	// ToolCallsBurst is the number of tool calls of a session allowed at once before the rate limit applies
+	// (defaults to ToolCallsPerMinute).
+	ToolCallsBurst int `toml:"tool_calls_burst,omitzero"`
+	// MaxConcurrentDestructiveToolCalls limits the tool calls annotated with destructiveHint=true that run at the same
+	// time in each MCP session, the calls above the limit fail with a "rate limited, retry after" error.
+	// They are not limited if 0.
+	MaxConcurrentDestructiveToolCalls int `toml:"max_concurrent_destructive_tool_calls,omitzero"`
+	// QPS is the maximum queries per second of the Kubernetes clients to the API server (client-go defaults to 5 if 0).
+	QPS float32 `toml:"qps,omitzero"`
+	// Burst is the number of queries the Kubernetes clients can send at once above QPS (client-go defaults to 10 if 0).
+	Burst int `toml:"burst,omitzero"`
+	// UserAgent is the User-Agent header of the requests to the API server, used by the API Priority and Fairness
+	// flow schemas and the audit logs to identify the server (defaults to the client-go user agent).
+	UserAgent string `toml:"user_agent,omitempty"`
+	// Policies are the checks every mutating tool call (the tools not annotated with readOnlyHint=true) must pass
+	// before the tool is invoked, planned with dry-run, or put on hold for confirmation.
+	// The calls violating a policy are denied and the policy message is returned to the agent.
+	Policies []Policy `toml:"policies,omitempty"`
+	// Cost are the unit prices the cost_report tool multiplies the resource requests (and usage) of the namespaces with
+	// to estimate their cost.
+	Cost Cost `toml:"cost,omitempty"`
+	// ImageScanner is the vulnerability scanner the images_inventory tool queries for the known CVEs of the images
+	// running in the cluster (not queried if empty).
+	ImageScanner ImageScanner `toml:"image_scanner,omitempty"`
+	// Artifacts is the store of the files generated by the tools (diagnostic bundles, node files, large logs), listed by
+	// the artifacts_list tool and exposed as artifact:// MCP resources.
+	Artifacts Artifacts `toml:"artifacts,omitempty"`
 
 	// Authorization-related fields
 	// RequireOAuth indicates whether the server requires OAuth for authentication.
@@ -49,6 +165,15 @@ type StaticConfig struct {
 	DisableDynamicClientRegistration bool `toml:"disable_dynamic_client_registration,omitempty"`
 	// OAuthScopes are the supported **client** scopes requested during the **client/frontend** OAuth flow.
 	OAuthScopes []string `toml:"oauth_scopes,omitempty"`
+	// Impersonation makes the server keep its own Kubernetes credentials and impersonate the MCP caller
+	// (Impersonate-User and Impersonate-Group headers) so that the RBAC of the caller is enforced.
+	// It requires require_oauth, the identity is the user of the TokenReview of the OAuth token (validate_token) or is
+	// read from the claims of the OAuth token verified by the OIDC provider (authorization_url).
+	Impersonation bool `toml:"impersonation,omitempty"`
+	// ImpersonationUserClaim is the OAuth token claim impersonated as user name (defaults to sub), unused with validate_token.
+	ImpersonationUserClaim string `toml:"impersonation_user_claim,omitempty"`
+	// ImpersonationGroupsClaim is the OAuth token claim impersonated as groups (defaults to groups), unused with validate_token.
+	ImpersonationGroupsClaim string `toml:"impersonation_groups_claim,omitempty"`
 	// StsClientId is the OAuth client ID used for backend token exchange
 	StsClientId string `toml:"sts_client_id,omitempty"`
 	// StsClientSecret is the OAuth client secret used for backend token exchange
@@ -64,6 +189,49 @@ type StaticConfig struct {
 	// If set to "in-cluster", the server will use the in cluster config
 	ClusterProviderStrategy string `toml:"cluster_provider_strategy,omitempty"`
 
+	// HelperImages overrides the container images used by the helper pods the server creates on behalf of
+	// some tools (e.g. node file access, debugging, network tests).
+	// Keys are helper image roles ("busybox", "debug", "network-test", "must-gather", "windows-host-process").
+	HelperImages map[string]HelperImage `toml:"helper_images,omitempty"`
+	// HelperImageRegistry is the registry (e.g. "registry.example.com:5000/mirror") used instead of the upstream
+	// registry for the default helper images, useful for disconnected (air-gapped) environments.
+	HelperImageRegistry string `toml:"helper_image_registry,omitempty"`
+	// HelperImagePullSecrets are the names of the image pull secrets added to every helper pod.
+	// The secrets must exist in the namespace where the helper pods are created.
+	HelperImagePullSecrets []string `toml:"helper_image_pull_secrets,omitempty"`
+	// HelperPodNamePrefix is the prefix of the names of the helper pods (defaults to kubernetes-mcp-server).
+	HelperPodNamePrefix string `toml:"helper_pod_name_prefix,omitempty"`
+	// HelperPodLabels are extra labels added to every helper pod (e.g. cost-center, owner, or the labels required by
+	// organizational admission policies). They can't override the labels set by the server (app.kubernetes.io/*).
+	HelperPodLabels map[string]string `toml:"helper_pod_labels,omitempty"`
+	// HelperPodAnnotations are extra annotations added to every helper pod (e.g. Pod Security Admission exemptions).
+	HelperPodAnnotations map[string]string `toml:"helper_pod_annotations,omitempty"`
+	// HelperCleanupTTL enables the deletion, on startup, of the helper pods and DaemonSets created by the server that
+	// are older than the provided duration (e.g. 30m), left behind when a server stopped before deleting them.
+	// The duration must be longer than the helper timeouts so that the helpers of other running servers are kept.
+	HelperCleanupTTL string `toml:"helper_cleanup_ttl,omitempty"`
+	// NodeFilesReadOnly restricts the node_files tool to the list and get operations and mounts the node root
+	// filesystem read-only in its helper pods, regardless of the read_only argument of the tool calls.
+	NodeFilesReadOnly bool `toml:"node_files_read_only,omitempty"`
+	// NodeFilesUnprivileged runs the node_files helper pods without privileges, as a non-root user with the
+	// restricted Pod Security Standard security context, and only mounts the NodeFilesAllowedHostPaths instead of the
+	// node root filesystem.
+	NodeFilesUnprivileged bool `toml:"node_files_unprivileged,omitempty"`
+	// NodeFilesAllowedHostPaths are the absolute node paths mounted in the unprivileged node_files helper pods, the
+	// only paths that can be accessed in the unprivileged mode (e.g. /var/log).
+	NodeFilesAllowedHostPaths []string `toml:"node_files_allowed_host_paths,omitempty"`
+	// NodeFilesAllowedPaths restricts the node_files tool to the provided absolute node paths and their contents (e.g.
+	// /var/log, or C:\var\log for Windows nodes), every node path is allowed if empty.
+	NodeFilesAllowedPaths []string `toml:"node_files_allowed_paths,omitempty"`
+	// NodeFilesDeniedPaths are the absolute node paths (and their contents) the node_files tool can't access, even if
+	// they are in NodeFilesAllowedPaths (e.g. /etc/kubernetes/pki).
+	NodeFilesDeniedPaths []string `toml:"node_files_denied_paths,omitempty"`
+
+	// EventStoreSize is the maximum number of events kept by the embedded event store.
+	// When greater than 0, the server continuously watches the cluster events and keeps them in memory beyond
+	// the API server retention period (1 hour by default) so that they can be queried with the events_history tool.
+	EventStoreSize int `toml:"event_store_size,omitzero"`
+
 	// ClusterProvider-specific configurations
 	// This map holds raw TOML primitives that will be parsed by registered provider parsers
 	ClusterProviderConfigs map[string]toml.Primitive `toml:"cluster_provider_configs,omitempty"`
@@ -81,12 +249,140 @@ type StaticConfig struct {
 	configDirPath string
 }
 
+// reloadMu guards the ReloadableSettings of the configurations against Reload, the configurations are copied by value
+// so the lock can't be one of their fields
+var reloadMu sync.RWMutex
+
 type GroupVersionKind struct {
 	Group   string `toml:"group"`
 	Version string `toml:"version"`
 	Kind    string `toml:"kind,omitempty"`
 }
 
+// Policy is a check of the mutating tool calls, evaluated with the manifest (object), and the tool, arguments, target
+// kind, namespace, and name of the call (request).
+type Policy struct {
+	// Name identifies the policy in the denial reasons
+	Name string `toml:"name"`
+	// Engine is the language of the expression, only "cel" is supported (default)
+	Engine string `toml:"engine,omitempty"`
+	// Expression must evaluate to true for the tool call to be allowed,
+	// e.g. request.namespace != "kube-system" or object == null || !has(object.spec) || !has(object.spec.hostNetwork) || !object.spec.hostNetwork
+	Expression string `toml:"expression"`
+	// Message is the reason returned to the agent when the call is denied (defaults to the expression)
+	Message string `toml:"message,omitempty"`
+	// Tools restricts the policy to the tools matching any of the entries (names or glob patterns, e.g. "resources_*"),
+	// the policy applies to every mutating tool if empty
+	Tools []string `toml:"tools,omitempty"`
+}
+
+// Cost are the unit prices of the compute resources, e.g. the on-demand price of the cluster nodes divided by their
+// capacity.
+type Cost struct {
+	// Currency of the prices, displayed in the cost reports (e.g. USD)
+	Currency string `toml:"currency,omitempty"`
+	// CPUCoreHour is the price of one CPU core for one hour
+	CPUCoreHour float64 `toml:"cpu_core_hour,omitzero"`
+	// MemoryGiBHour is the price of one GiB of memory for one hour
+	MemoryGiBHour float64 `toml:"memory_gib_hour,omitzero"`
+}
+
+// Image scanner types
+const (
+	// ImageScannerClair queries the vulnerability reports of the Clair v4 matcher API by image manifest digest
+	ImageScannerClair = "clair"
+	// ImageScannerTrivy queries a scan service returning the Trivy JSON report (trivy image --format json) of the image
+	ImageScannerTrivy = "trivy"
+)
+
+// ImageScannerTypes are the supported image scanner types
+var ImageScannerTypes = []string{ImageScannerClair, ImageScannerTrivy}
+
+// ImageScanner is the endpoint of a vulnerability scanner queried for the known CVEs of an image.
+type ImageScanner struct {
+	// Type of the scanner API, clair or trivy
+	Type string `toml:"type,omitempty"`
+	// Url of the scanner, e.g. https://clair.example.com for Clair or https://trivy-scan.example.com/scan for Trivy
+	Url string `toml:"url,omitempty"`
+	// BearerToken is sent in the Authorization header of the scanner requests
+	BearerToken          string `toml:"bearer_token,omitempty"`
+	Insecure             bool   `toml:"insecure,omitempty"`
+	CertificateAuthority string `toml:"certificate_authority,omitempty"`
+}
+
+// Artifacts is the configuration of the artifact store.
+// The artifacts are always written to the local directory, where the retention policies apply, and uploaded to the
+// S3 or GCS bucket if configured (the expiration of the uploaded objects is managed by the bucket lifecycle rules).
+type Artifacts struct {
+	// Dir is the local directory where the artifacts are written
+	// (defaults to the kubernetes-mcp-server-artifacts directory of the temporary directory)
+	Dir string `toml:"dir,omitempty"`
+	// Retention is how long the local artifacts are kept, e.g. 24h (kept until max_count or max_bytes is exceeded if empty)
+	Retention string `toml:"retention,omitempty"`
+	// MaxCount is the maximum number of local artifacts, the oldest ones are removed first (0 for no limit)
+	MaxCount int `toml:"max_count,omitzero"`
+	// MaxBytes is the maximum total size of the local artifacts, the oldest ones are removed first (0 for no limit)
+	MaxBytes int64 `toml:"max_bytes,omitzero"`
+	// S3 uploads the artifacts to an S3-compatible object storage (AWS S3, MinIO, Ceph RGW, ...)
+	S3 *ObjectStorage `toml:"s3,omitempty"`
+	// GCS uploads the artifacts to a Google Cloud Storage bucket through its XML API, authenticated with HMAC keys
+	// (the endpoint defaults to https://storage.googleapis.com)
+	GCS *ObjectStorage `toml:"gcs,omitempty"`
+}
+
+// GCSEndpoint is the endpoint of the XML API of Google Cloud Storage, compatible with the S3 API
+const GCSEndpoint = "https://storage.googleapis.com"
+
+// ObjectStorage is a bucket of an S3-compatible endpoint where files are uploaded
+type ObjectStorage struct {
+	// Endpoint is the URL of the object storage, e.g. https://s3.us-east-1.amazonaws.com
+	Endpoint string `toml:"endpoint"`
+	Region   string `toml:"region,omitempty"`
+	Bucket   string `toml:"bucket"`
+	// Prefix is prepended to the object keys, e.g. "clusters/prod/"
+	Prefix      string `toml:"prefix,omitempty"`
+	AccessKeyID string `toml:"access_key_id"`
+	// SecretAccessKey or SecretAccessKeyFile (read on every upload so that rotated keys are picked up)
+	SecretAccessKey     string `toml:"secret_access_key,omitempty"`
+	SecretAccessKeyFile string `toml:"secret_access_key_file,omitempty"`
+}
+
+// Validate returns an error if the endpoint is not a valid URL or the bucket or credentials are missing, the errors
+// are prefixed with the name of the configuration section, e.g. artifacts.s3
+func (o *ObjectStorage) Validate(section string) error {
+	u, err := url.Parse(o.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s.endpoint must be a valid http(s) URL", section)
+	}
+	if strings.TrimSpace(o.Bucket) == "" {
+		return fmt.Errorf("%s.bucket is required", section)
+	}
+	if strings.TrimSpace(o.AccessKeyID) == "" {
+		return fmt.Errorf("%s.access_key_id is required", section)
+	}
+	if (o.SecretAccessKey == "") == (o.SecretAccessKeyFile == "") {
+		return fmt.Errorf("exactly one of %[1]s.secret_access_key and %[1]s.secret_access_key_file is required", section)
+	}
+	if o.SecretAccessKeyFile != "" {
+		if _, err = os.Stat(o.SecretAccessKeyFile); err != nil {
+			return fmt.Errorf("%s.secret_access_key_file must be a valid file path: %w", section, err)
+		}
+	}
+	return nil
+}
+
+// HelperImage is the image configuration for a helper pod role.
+type HelperImage struct {
+	// Image is the image reference used for any node architecture without a specific entry in Arch.
+	// It's expected to be a multi-arch image (manifest list).
+	Image string `toml:"image,omitempty"`
+	// Arch maps a node architecture, as reported by the kubernetes.io/arch node label (e.g. "amd64", "arm64"),
+	// to a specific image reference.
+	Arch map[string]string `toml:"arch,omitempty"`
+	// PullSecrets are the names of additional image pull secrets required to pull the images for this role.
+	PullSecrets []string `toml:"pull_secrets,omitempty"`
+}
+
 type ReadConfigOpt func(cfg *StaticConfig)
 
 // WithDirPath returns a ReadConfigOpt that sets the config directory path.
@@ -145,6 +441,377 @@ func ReadToml(configData []byte, opts ...ReadConfigOpt) (*StaticConfig, error) {
 	return config, nil
 }
 
+// IsReadOnly returns true if only the tools annotated with readOnlyHint=true should be exposed
+// (read_only enabled or read-only tool profile)
+func (c *StaticConfig) IsReadOnly() bool {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.ReadOnly || c.ToolProfile == ToolProfileReadOnly
+}
+
+// IsDestructiveDisabled returns true if the tools annotated with destructiveHint=true should be disabled
+// (disable_destructive enabled or operator tool profile)
+func (c *StaticConfig) IsDestructiveDisabled() bool {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.DisableDestructive || c.ToolProfile == ToolProfileOperator
+}
+
+// IsToolEnabled returns true if the tool is allowed by the enabled_tools and disabled_tools lists
+func (c *StaticConfig) IsToolEnabled(name string) bool {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	if c.EnabledTools != nil && !matchesAny(c.EnabledTools, name) {
+		return false
+	}
+	return !matchesAny(c.DisabledTools, name)
+}
+
+// ToolTimeout returns the timeout configured for the calls of the tool, or 0 if they are not bounded
+func (c *StaticConfig) ToolTimeout(name string) time.Duration {
+	timeout, err := time.ParseDuration(c.Timeouts[name])
+	if err != nil || timeout <= 0 {
+		return 0
+	}
+	return timeout
+}
+
+// ValidateTimeouts returns an error if any of the tool timeouts is not a positive duration
+func (c *StaticConfig) ValidateTimeouts() error {
+	for _, name := range slices.Sorted(maps.Keys(c.Timeouts)) {
+		if c.ToolTimeout(name) == 0 {
+			return fmt.Errorf("invalid timeouts.%s %q, expected a positive duration (e.g. 5m)", name, c.Timeouts[name])
+		}
+	}
+	return nil
+}
+
+// CacheTTL returns the duration the results of the tool are cached for, 0 if they're not cached
+func (c *StaticConfig) CacheTTL(name string) time.Duration {
+	ttl, err := time.ParseDuration(c.CacheTTLs[name])
+	if err != nil || ttl <= 0 {
+		return 0
+	}
+	return ttl
+}
+
+// ValidateCacheTTLs returns an error if any of the cache TTLs is not a positive duration
+func (c *StaticConfig) ValidateCacheTTLs() error {
+	for _, name := range slices.Sorted(maps.Keys(c.CacheTTLs)) {
+		if c.CacheTTL(name) == 0 {
+			return fmt.Errorf("invalid cache_ttls.%s %q, expected a positive duration (e.g. 30s)", name, c.CacheTTLs[name])
+		}
+	}
+	return nil
+}
+
+// ValidateTargetNamespaces returns an error if any of the target namespaces is empty
+func (c *StaticConfig) ValidateTargetNamespaces() error {
+	for _, namespace := range c.TargetNamespaces {
+		if strings.TrimSpace(namespace) == "" {
+			return fmt.Errorf("invalid target_namespaces %q, expected non-empty namespace names", c.TargetNamespaces)
+		}
+	}
+	return nil
+}
+
+// ValidateRateLimits returns an error if any of the tool call or Kubernetes client rate limits is negative
+func (c *StaticConfig) ValidateRateLimits() error {
+	if c.QPS < 0 {
+		return fmt.Errorf("invalid qps %v, expected a positive number (or 0 for the client-go default)", c.QPS)
+	}
+	if c.Burst < 0 {
+		return fmt.Errorf("invalid burst %d, expected a positive number (or 0 for the client-go default)", c.Burst)
+	}
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"tool_calls_per_minute", c.ToolCallsPerMinute},
+		{"tool_calls_burst", c.ToolCallsBurst},
+		{"max_concurrent_destructive_tool_calls", c.MaxConcurrentDestructiveToolCalls},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return fmt.Errorf("invalid %s %d, expected a positive number (or 0 for no limit)", limit.name, limit.value)
+		}
+	}
+	return nil
+}
+
+// ValidateCost returns an error if any of the unit prices is negative
+func (c *StaticConfig) ValidateCost() error {
+	prices := []struct {
+		name  string
+		value float64
+	}{
+		{"cost.cpu_core_hour", c.Cost.CPUCoreHour},
+		{"cost.memory_gib_hour", c.Cost.MemoryGiBHour},
+	}
+	for _, price := range prices {
+		if price.value < 0 {
+			return fmt.Errorf("invalid %s %v, expected a positive price", price.name, price.value)
+		}
+	}
+	return nil
+}
+
+// ValidateImageScanner returns an error if the image scanner type is not supported or its url is not valid
+func (c *StaticConfig) ValidateImageScanner() error {
+	scanner := c.ImageScanner
+	if scanner.Type == "" && scanner.Url == "" {
+		return nil
+	}
+	if !slices.Contains(ImageScannerTypes, scanner.Type) {
+		return fmt.Errorf("invalid image_scanner.type %q, valid types are: %s", scanner.Type, strings.Join(ImageScannerTypes, ", "))
+	}
+	if u, err := url.Parse(scanner.Url); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid image_scanner.url %q, expected a valid URL", scanner.Url)
+	}
+	if scanner.CertificateAuthority != "" {
+		if _, err := os.Stat(scanner.CertificateAuthority); err != nil {
+			return fmt.Errorf("invalid image_scanner.certificate_authority: %w", err)
+		}
+	}
+	return nil
+}
+
+// ArtifactsRetention returns how long the local artifacts are kept, 0 if they're not expired
+func (c *StaticConfig) ArtifactsRetention() time.Duration {
+	retention, err := time.ParseDuration(c.Artifacts.Retention)
+	if err != nil || retention <= 0 {
+		return 0
+	}
+	return retention
+}
+
+// ValidateArtifacts returns an error if the artifacts retention or limits are not valid, or if the object storage
+// is not valid (only one of s3 and gcs can be configured)
+func (c *StaticConfig) ValidateArtifacts() error {
+	if c.Artifacts.Retention != "" && c.ArtifactsRetention() == 0 {
+		return fmt.Errorf("invalid artifacts.retention %q, expected a positive duration (e.g. 24h)", c.Artifacts.Retention)
+	}
+	if c.Artifacts.MaxCount < 0 {
+		return fmt.Errorf("invalid artifacts.max_count %d, expected a positive number (or 0 for no limit)", c.Artifacts.MaxCount)
+	}
+	if c.Artifacts.MaxBytes < 0 {
+		return fmt.Errorf("invalid artifacts.max_bytes %d, expected a positive number (or 0 for no limit)", c.Artifacts.MaxBytes)
+	}
+	if c.Artifacts.S3 != nil && c.Artifacts.GCS != nil {
+		return errors.New("invalid artifacts, only one of artifacts.s3 and artifacts.gcs can be configured")
+	}
+	if c.Artifacts.S3 != nil {
+		return c.Artifacts.S3.Validate("artifacts.s3")
+	}
+	if c.Artifacts.GCS != nil {
+		gcs := *c.Artifacts.GCS
+		if gcs.Endpoint == "" {
+			gcs.Endpoint = GCSEndpoint
+		}
+		return gcs.Validate("artifacts.gcs")
+	}
+	return nil
+}
+
+// ValidatePolicies returns an error if a policy has no name or expression, its name is not unique, its engine is not
+// supported, or its tools are not valid glob patterns.
+// The expressions are compiled when the server starts.
+func (c *StaticConfig) ValidatePolicies() error {
+	names := make(map[string]bool, len(c.Policies))
+	for i, policy := range c.Policies {
+		if policy.Name == "" {
+			return fmt.Errorf("invalid policies[%d], name is required", i)
+		}
+		if names[policy.Name] {
+			return fmt.Errorf("invalid policy %s, the name is already used by another policy", policy.Name)
+		}
+		names[policy.Name] = true
+		if policy.Expression == "" {
+			return fmt.Errorf("invalid policy %s, expression is required", policy.Name)
+		}
+		if policy.Engine != "" && !slices.Contains(PolicyEngines, policy.Engine) {
+			return fmt.Errorf("invalid policy %s, unsupported engine %q, valid engines are: %s", policy.Name, policy.Engine, strings.Join(PolicyEngines, ", "))
+		}
+		for _, pattern := range policy.Tools {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid policy %s, invalid tool pattern %q: %w", policy.Name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AppliesTo returns true if the policy applies to the tool (no tools or a matching tools entry)
+func (p *Policy) AppliesTo(tool string) bool {
+	return len(p.Tools) == 0 || matchesAny(p.Tools, tool)
+}
+
+// ValidateToolPatterns returns an error if any of the enabled_tools or disabled_tools entries is not a valid glob pattern
+func (c *StaticConfig) ValidateToolPatterns() error {
+	for _, pattern := range slices.Concat(c.EnabledTools, c.DisabledTools) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// ReloadableSettings are the settings applied by Reload, the other settings require a restart of the server
+var ReloadableSettings = []string{"toolsets", "enabled_tools", "disabled_tools", "read_only", "disable_destructive", "tool_profile", "denied_resources"}
+
+// Reload replaces the ReloadableSettings with the ones of the reloaded configuration and returns the names of the
+// settings that changed.
+// The settings are replaced while holding reloadMu, the readers running along with the server (e.g. the Kubernetes
+// requests) must read them with the accessors (GetDeniedResources, IsReadOnly, IsDestructiveDisabled, IsToolEnabled)
+// or from a Snapshot.
+func (c *StaticConfig) Reload(reloaded *StaticConfig) []string {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	var changed []string
+	reloadSlice(&changed, "toolsets", &c.Toolsets, reloaded.Toolsets)
+	reloadSlice(&changed, "enabled_tools", &c.EnabledTools, reloaded.EnabledTools)
+	reloadSlice(&changed, "disabled_tools", &c.DisabledTools, reloaded.DisabledTools)
+	reloadValue(&changed, "read_only", &c.ReadOnly, reloaded.ReadOnly)
+	reloadValue(&changed, "disable_destructive", &c.DisableDestructive, reloaded.DisableDestructive)
+	reloadValue(&changed, "tool_profile", &c.ToolProfile, reloaded.ToolProfile)
+	reloadSlice(&changed, "denied_resources", &c.DeniedResources, reloaded.DeniedResources)
+	return changed
+}
+
+// GetDeniedResources returns the denied_resources, safe to call while the configuration is reloaded
+func (c *StaticConfig) GetDeniedResources() []GroupVersionKind {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.DeniedResources
+}
+
+// Snapshot returns a copy of the configuration, safe to read while the configuration is reloaded
+func (c *StaticConfig) Snapshot() *StaticConfig {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	snapshot := *c
+	return &snapshot
+}
+
+func reloadValue[T comparable](changed *[]string, name string, current *T, reloaded T) {
+	if *current != reloaded {
+		*current = reloaded
+		*changed = append(*changed, name)
+	}
+}
+
+// reloadSlice replaces the slice if its elements changed, or if it changed from unset (nil) to empty or vice versa
+func reloadSlice[T comparable](changed *[]string, name string, current *[]T, reloaded []T) {
+	if slices.Equal(*current, reloaded) && (*current == nil) == (reloaded == nil) {
+		return
+	}
+	*current = slices.Clone(reloaded)
+	*changed = append(*changed, name)
+}
+
+// BreakGlassDuration returns the maximum duration of the break-glass elevations, or 0 if break-glass is not enabled
+func (c *StaticConfig) BreakGlassDuration() (time.Duration, error) {
+	if c.BreakGlassMaxDuration == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(c.BreakGlassMaxDuration)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid break_glass_max_duration %q, expected a positive duration (e.g. 30m)", c.BreakGlassMaxDuration)
+	}
+	return duration, nil
+}
+
+// HelperCleanupDuration returns the age of the orphaned helpers deleted on startup, or 0 if the cleanup is not enabled
+func (c *StaticConfig) HelperCleanupDuration() (time.Duration, error) {
+	if c.HelperCleanupTTL == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(c.HelperCleanupTTL)
+	if err != nil || duration < 5*time.Minute {
+		return 0, fmt.Errorf("invalid helper_cleanup_ttl %q, expected a duration of at least 5m (e.g. 30m)", c.HelperCleanupTTL)
+	}
+	return duration, nil
+}
+
+// ValidateImpersonation returns an error if the impersonated identity would not be read from verified OAuth tokens,
+// either without require_oauth or with tokens whose signature is never verified, neither by the OIDC provider
+// (authorization_url) nor by a TokenReview (validate_token)
+func (c *StaticConfig) ValidateImpersonation() error {
+	if c.Impersonation && !c.RequireOAuth {
+		return fmt.Errorf("require_oauth is required when impersonation is enabled")
+	}
+	if c.Impersonation && c.AuthorizationURL == "" && !c.ValidateToken {
+		return fmt.Errorf("authorization_url or validate_token is required when impersonation and require_oauth are enabled")
+	}
+	return nil
+}
+
+// ValidateBreakGlass returns an error if the break-glass duration is invalid or the elevations can't be authenticated
+func (c *StaticConfig) ValidateBreakGlass() error {
+	duration, err := c.BreakGlassDuration()
+	if err != nil || duration == 0 {
+		return err
+	}
+	if c.BreakGlassToken == "" && !c.RequireOAuth {
+		return fmt.Errorf("break_glass_token is required when break_glass_max_duration is set and require_oauth is disabled")
+	}
+	return nil
+}
+
+// ValidateNodeFiles returns an error if the node_files paths are not absolute or if the unprivileged mode is enabled
+// without allowed host paths
+func (c *StaticConfig) ValidateNodeFiles() error {
+	if c.NodeFilesUnprivileged && len(c.NodeFilesAllowedHostPaths) == 0 {
+		return fmt.Errorf("node_files_allowed_host_paths is required when node_files_unprivileged is enabled")
+	}
+	for _, hostPath := range c.NodeFilesAllowedHostPaths {
+		if !path.IsAbs(hostPath) || path.Clean(hostPath) == "/" {
+			return fmt.Errorf("invalid node_files_allowed_host_paths entry %q, expected an absolute path other than /", hostPath)
+		}
+	}
+	for _, nodePath := range c.NodeFilesAllowedPaths {
+		if !isAbsNodePath(nodePath) {
+			return fmt.Errorf("invalid node_files_allowed_paths entry %q, expected an absolute path", nodePath)
+		}
+	}
+	for _, nodePath := range c.NodeFilesDeniedPaths {
+		if !isAbsNodePath(nodePath) {
+			return fmt.Errorf("invalid node_files_denied_paths entry %q, expected an absolute path", nodePath)
+		}
+	}
+	return nil
+}
+
+// ValidateHelperImages returns an error if a helper_images architecture entry has no image or is not a
+// kubernetes.io/arch label value (e.g. "arm64")
+func (c *StaticConfig) ValidateHelperImages() error {
+	for _, role := range slices.Sorted(maps.Keys(c.HelperImages)) {
+		for _, arch := range slices.Sorted(maps.Keys(c.HelperImages[role].Arch)) {
+			if !nodeArchitecture.MatchString(arch) {
+				return fmt.Errorf("invalid helper_images.%s.arch entry %q, expected a kubernetes.io/arch node label value (e.g. amd64, arm64)", role, arch)
+			}
+			if c.HelperImages[role].Arch[arch] == "" {
+				return fmt.Errorf("invalid helper_images.%s.arch entry %q, expected an image", role, arch)
+			}
+		}
+	}
+	return nil
+}
+
+// isAbsNodePath returns true if the provided path is an absolute Linux or Windows (e.g. C:\var\log) node path
+func isAbsNodePath(nodePath string) bool {
+	return path.IsAbs(nodePath) || windowsAbsNodePath.MatchString(nodePath)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *StaticConfig) GetProviderConfig(strategy string) (Extended, bool) {
 	config, ok := c.parsedClusterProviderConfigs[strategy]
 
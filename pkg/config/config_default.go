@@ -8,8 +8,9 @@ import (
 
 func Default() *StaticConfig {
 	defaultConfig := StaticConfig{
-		ListOutput: "table",
-		Toolsets:   []string{"core", "config", "helm"},
+		ListOutput:     "table",
+		OutputMaxBytes: 100 * 1024,
+		Toolsets:       []string{"core", "config", "helm"},
 	}
 	overrides := defaultOverrides()
 	mergedConfig := mergeConfig(defaultConfig, overrides)
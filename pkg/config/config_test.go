@@ -5,8 +5,10 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -75,6 +77,11 @@ func (s *ConfigSuite) TestReadConfigValid() {
 		list_output = "yaml"
 		read_only = true
 		disable_destructive = true
+		tool_profile = "operator"
+		dry_run = true
+		require_confirmation = true
+		allow_secret_reveal = true
+		output_max_bytes = 2048
 
 		toolsets = ["core", "config", "helm", "metrics"]
 		
@@ -115,6 +122,21 @@ func (s *ConfigSuite) TestReadConfigValid() {
 	s.Run("disable_destructive parsed correctly", func() {
 		s.Truef(config.DisableDestructive, "Expected DisableDestructive to be true, got %v", config.DisableDestructive)
 	})
+	s.Run("tool_profile parsed correctly", func() {
+		s.Equalf(ToolProfileOperator, config.ToolProfile, "Expected ToolProfile to be operator, got %s", config.ToolProfile)
+	})
+	s.Run("dry_run parsed correctly", func() {
+		s.Truef(config.DryRun, "Expected DryRun to be true, got %v", config.DryRun)
+	})
+	s.Run("require_confirmation parsed correctly", func() {
+		s.Truef(config.RequireConfirmation, "Expected RequireConfirmation to be true, got %v", config.RequireConfirmation)
+	})
+	s.Run("allow_secret_reveal parsed correctly", func() {
+		s.Truef(config.AllowSecretReveal, "Expected AllowSecretReveal to be true, got %v", config.AllowSecretReveal)
+	})
+	s.Run("output_max_bytes parsed correctly", func() {
+		s.Equalf(2048, config.OutputMaxBytes, "Expected OutputMaxBytes to be 2048, got %d", config.OutputMaxBytes)
+	})
 	s.Run("toolsets", func() {
 		s.Require().Lenf(config.Toolsets, 4, "Expected 4 toolsets, got %d", len(config.Toolsets))
 		for _, toolset := range []string{"core", "config", "helm", "metrics"} {
@@ -169,6 +191,9 @@ func (s *ConfigSuite) TestReadConfigValidPreservesDefaultsForMissingFields() {
 	s.Run("list_output defaulted correctly", func() {
 		s.Equalf("table", config.ListOutput, "Expected ListOutput to be table, got %s", config.ListOutput)
 	})
+	s.Run("output_max_bytes defaulted correctly", func() {
+		s.Equalf(100*1024, config.OutputMaxBytes, "Expected OutputMaxBytes to be 102400, got %d", config.OutputMaxBytes)
+	})
 	s.Run("toolsets defaulted correctly", func() {
 		s.Require().Lenf(config.Toolsets, 3, "Expected 3 toolsets, got %d", len(config.Toolsets))
 		for _, toolset := range []string{"core", "config", "helm"} {
@@ -220,6 +245,491 @@ func (s *ConfigSuite) TestMergeConfig() {
 	})
 }
 
+func (s *ConfigSuite) TestToolProfile() {
+	s.Run("read-only profile implies read-only", func() {
+		config := &StaticConfig{ToolProfile: ToolProfileReadOnly}
+		s.True(config.IsReadOnly())
+		s.False(config.IsDestructiveDisabled())
+	})
+	s.Run("operator profile disables destructive tools", func() {
+		config := &StaticConfig{ToolProfile: ToolProfileOperator}
+		s.False(config.IsReadOnly())
+		s.True(config.IsDestructiveDisabled())
+	})
+	s.Run("admin profile keeps explicit flags", func() {
+		config := &StaticConfig{ToolProfile: ToolProfileAdmin, ReadOnly: true, DisableDestructive: true}
+		s.True(config.IsReadOnly())
+		s.True(config.IsDestructiveDisabled())
+		config = &StaticConfig{ToolProfile: ToolProfileAdmin}
+		s.False(config.IsReadOnly())
+		s.False(config.IsDestructiveDisabled())
+	})
+}
+
+func (s *ConfigSuite) TestIsToolEnabled() {
+	s.Run("all tools enabled by default", func() {
+		config := &StaticConfig{}
+		s.True(config.IsToolEnabled("pods_list"))
+	})
+	s.Run("enabled_tools", func() {
+		config := &StaticConfig{EnabledTools: []string{"namespaces_list", "pods_*"}}
+		s.Run("matches tool names", func() {
+			s.True(config.IsToolEnabled("namespaces_list"))
+		})
+		s.Run("matches glob patterns", func() {
+			s.True(config.IsToolEnabled("pods_list"))
+			s.True(config.IsToolEnabled("pods_exec"))
+		})
+		s.Run("excludes other tools", func() {
+			s.False(config.IsToolEnabled("resources_list"))
+			s.False(config.IsToolEnabled("namespaces_list_all"))
+		})
+	})
+	s.Run("disabled_tools", func() {
+		config := &StaticConfig{DisabledTools: []string{"pods_exec", "node_*"}}
+		s.Run("excludes tool names", func() {
+			s.False(config.IsToolEnabled("pods_exec"))
+		})
+		s.Run("excludes glob patterns", func() {
+			s.False(config.IsToolEnabled("node_files"))
+			s.False(config.IsToolEnabled("node_logs"))
+		})
+		s.Run("includes other tools", func() {
+			s.True(config.IsToolEnabled("pods_list"))
+		})
+	})
+	s.Run("disabled_tools take precedence over enabled_tools", func() {
+		config := &StaticConfig{EnabledTools: []string{"pods_*"}, DisabledTools: []string{"pods_exec"}}
+		s.True(config.IsToolEnabled("pods_list"))
+		s.False(config.IsToolEnabled("pods_exec"))
+	})
+}
+
+func (s *ConfigSuite) TestValidateToolPatterns() {
+	s.Run("valid patterns", func() {
+		config := &StaticConfig{EnabledTools: []string{"pods_*", "namespaces_list"}, DisabledTools: []string{"node_[a-z]*"}}
+		s.NoError(config.ValidateToolPatterns())
+	})
+	s.Run("invalid pattern", func() {
+		config := &StaticConfig{DisabledTools: []string{"node_[files"}}
+		s.EqualError(config.ValidateToolPatterns(), `invalid tool pattern "node_[files": syntax error in pattern`)
+	})
+}
+
+func (s *ConfigSuite) TestReload() {
+	s.Run("replaces the reloadable settings", func() {
+		config := &StaticConfig{Port: "8080", Toolsets: []string{"core"}, DisabledTools: []string{"pods_exec"}}
+		changed := config.Reload(&StaticConfig{
+			Port:            "9090",
+			Toolsets:        []string{"core", "helm"},
+			DisabledTools:   []string{"pods_exec"},
+			ToolProfile:     ToolProfileOperator,
+			DeniedResources: []GroupVersionKind{{Version: "v1", Kind: "Secret"}},
+		})
+		s.Equal([]string{"toolsets", "tool_profile", "denied_resources"}, changed)
+		s.Equal([]string{"core", "helm"}, config.Toolsets)
+		s.Equal(ToolProfileOperator, config.ToolProfile)
+		s.Equal([]GroupVersionKind{{Version: "v1", Kind: "Secret"}}, config.DeniedResources)
+		s.Equal("8080", config.Port, "requires a restart")
+	})
+	s.Run("empty enabled_tools differ from unset", func() {
+		config := &StaticConfig{}
+		s.Equal([]string{"enabled_tools"}, config.Reload(&StaticConfig{EnabledTools: []string{}}))
+		s.False(config.IsToolEnabled("pods_list"))
+	})
+	s.Run("no changes", func() {
+		config := &StaticConfig{Toolsets: []string{"core"}, ReadOnly: true}
+		s.Empty(config.Reload(&StaticConfig{Toolsets: []string{"core"}, ReadOnly: true}))
+	})
+	s.Run("can be read while reloading", func() {
+		config := &StaticConfig{}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				config.Reload(&StaticConfig{ReadOnly: i%2 == 0, DeniedResources: []GroupVersionKind{{Version: "v1", Kind: strconv.Itoa(i)}}})
+			}
+		}()
+		for i := 0; i < 100; i++ {
+			_ = config.GetDeniedResources()
+			_ = config.IsReadOnly()
+			_ = config.Snapshot().ToolProfile
+		}
+		<-done
+		s.Equal([]GroupVersionKind{{Version: "v1", Kind: "99"}}, config.GetDeniedResources())
+	})
+}
+
+func (s *ConfigSuite) TestValidateBreakGlass() {
+	s.Run("disabled by default", func() {
+		config := &StaticConfig{}
+		s.NoError(config.ValidateBreakGlass())
+		duration, err := config.BreakGlassDuration()
+		s.NoError(err)
+		s.Zero(duration)
+	})
+	s.Run("valid duration and token", func() {
+		config := &StaticConfig{BreakGlassMaxDuration: "30m", BreakGlassToken: "s3cr3t"}
+		s.NoError(config.ValidateBreakGlass())
+		duration, _ := config.BreakGlassDuration()
+		s.Equal(30*time.Minute, duration)
+	})
+	s.Run("valid duration with require_oauth", func() {
+		config := &StaticConfig{BreakGlassMaxDuration: "1h", RequireOAuth: true}
+		s.NoError(config.ValidateBreakGlass())
+	})
+	s.Run("invalid duration", func() {
+		config := &StaticConfig{BreakGlassMaxDuration: "-5m", BreakGlassToken: "s3cr3t"}
+		s.EqualError(config.ValidateBreakGlass(), `invalid break_glass_max_duration "-5m", expected a positive duration (e.g. 30m)`)
+	})
+	s.Run("missing token", func() {
+		config := &StaticConfig{BreakGlassMaxDuration: "30m"}
+		s.EqualError(config.ValidateBreakGlass(), "break_glass_token is required when break_glass_max_duration is set and require_oauth is disabled")
+	})
+}
+
+func (s *ConfigSuite) TestValidateImpersonation() {
+	s.Run("impersonation disabled", func() {
+		s.NoError((&StaticConfig{}).ValidateImpersonation())
+	})
+	s.Run("without require_oauth", func() {
+		s.EqualError((&StaticConfig{Impersonation: true}).ValidateImpersonation(),
+			"require_oauth is required when impersonation is enabled")
+		s.EqualError((&StaticConfig{Impersonation: true, ValidateToken: true}).ValidateImpersonation(),
+			"require_oauth is required when impersonation is enabled")
+	})
+	s.Run("oauth token verified by the OIDC provider", func() {
+		s.NoError((&StaticConfig{Impersonation: true, RequireOAuth: true, AuthorizationURL: "https://oidc.example.com"}).ValidateImpersonation())
+	})
+	s.Run("oauth token verified with a TokenReview", func() {
+		s.NoError((&StaticConfig{Impersonation: true, RequireOAuth: true, ValidateToken: true}).ValidateImpersonation())
+	})
+	s.Run("unverified oauth token", func() {
+		s.EqualError((&StaticConfig{Impersonation: true, RequireOAuth: true}).ValidateImpersonation(),
+			"authorization_url or validate_token is required when impersonation and require_oauth are enabled")
+	})
+}
+
+func (s *ConfigSuite) TestHelperCleanupDuration() {
+	s.Run("disabled by default", func() {
+		duration, err := (&StaticConfig{}).HelperCleanupDuration()
+		s.NoError(err)
+		s.Zero(duration)
+	})
+	s.Run("valid duration", func() {
+		duration, err := (&StaticConfig{HelperCleanupTTL: "30m"}).HelperCleanupDuration()
+		s.NoError(err)
+		s.Equal(30*time.Minute, duration)
+	})
+	s.Run("duration shorter than the helper timeouts", func() {
+		_, err := (&StaticConfig{HelperCleanupTTL: "1m"}).HelperCleanupDuration()
+		s.EqualError(err, `invalid helper_cleanup_ttl "1m", expected a duration of at least 5m (e.g. 30m)`)
+	})
+	s.Run("invalid duration", func() {
+		_, err := (&StaticConfig{HelperCleanupTTL: "soon"}).HelperCleanupDuration()
+		s.EqualError(err, `invalid helper_cleanup_ttl "soon", expected a duration of at least 5m (e.g. 30m)`)
+	})
+}
+
+func (s *ConfigSuite) TestToolTimeouts() {
+	config, err := ReadToml([]byte(`
+		[timeouts]
+		node_files = "5m"
+		pods_log = "30s"
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the timeouts by tool name", func() {
+		s.NoError(config.ValidateTimeouts())
+		s.Equal(5*time.Minute, config.ToolTimeout("node_files"))
+		s.Equal(30*time.Second, config.ToolTimeout("pods_log"))
+	})
+	s.Run("tools without timeout are not bounded", func() {
+		s.Zero(config.ToolTimeout("pods_list"))
+	})
+	s.Run("invalid duration", func() {
+		config := &StaticConfig{Timeouts: map[string]string{"node_files": "5m", "nodes_journal": "-1m"}}
+		s.EqualError(config.ValidateTimeouts(), `invalid timeouts.nodes_journal "-1m", expected a positive duration (e.g. 5m)`)
+	})
+}
+
+func (s *ConfigSuite) TestCacheTTLs() {
+	config, err := ReadToml([]byte(`
+		[cache_ttls]
+		nodes_top = "30s"
+		namespaces_list = "5m"
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the cache ttls by tool name", func() {
+		s.NoError(config.ValidateCacheTTLs())
+		s.Equal(30*time.Second, config.CacheTTL("nodes_top"))
+		s.Equal(5*time.Minute, config.CacheTTL("namespaces_list"))
+	})
+	s.Run("tools without ttl are not cached", func() {
+		s.Zero(config.CacheTTL("pods_list"))
+	})
+	s.Run("invalid duration", func() {
+		config := &StaticConfig{CacheTTLs: map[string]string{"nodes_top": "30s", "pods_top": "0s"}}
+		s.EqualError(config.ValidateCacheTTLs(), `invalid cache_ttls.pods_top "0s", expected a positive duration (e.g. 30s)`)
+	})
+}
+
+func (s *ConfigSuite) TestTargetNamespaces() {
+	config, err := ReadToml([]byte(`
+		target_namespaces = ["team-a", "team-b"]
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the target namespaces", func() {
+		s.NoError(config.ValidateTargetNamespaces())
+		s.Equal([]string{"team-a", "team-b"}, config.TargetNamespaces)
+	})
+	s.Run("empty namespace", func() {
+		config := &StaticConfig{TargetNamespaces: []string{"team-a", ""}}
+		s.EqualError(config.ValidateTargetNamespaces(), `invalid target_namespaces ["team-a" ""], expected non-empty namespace names`)
+	})
+}
+
+func (s *ConfigSuite) TestRateLimits() {
+	config, err := ReadToml([]byte(`
+		tool_calls_per_minute = 60
+		tool_calls_burst = 10
+		max_concurrent_destructive_tool_calls = 2
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the rate limits", func() {
+		s.NoError(config.ValidateRateLimits())
+		s.Equal(60, config.ToolCallsPerMinute)
+		s.Equal(10, config.ToolCallsBurst)
+		s.Equal(2, config.MaxConcurrentDestructiveToolCalls)
+	})
+	s.Run("negative limit", func() {
+		config := &StaticConfig{ToolCallsPerMinute: 60, MaxConcurrentDestructiveToolCalls: -1}
+		s.EqualError(config.ValidateRateLimits(), "invalid max_concurrent_destructive_tool_calls -1, expected a positive number (or 0 for no limit)")
+	})
+}
+
+func (s *ConfigSuite) TestClientRateLimits() {
+	config, err := ReadToml([]byte(`
+		qps = 50.5
+		burst = 100
+		user_agent = "kubernetes-mcp-server/fleet"
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the client settings", func() {
+		s.NoError(config.ValidateRateLimits())
+		s.Equal(float32(50.5), config.QPS)
+		s.Equal(100, config.Burst)
+		s.Equal("kubernetes-mcp-server/fleet", config.UserAgent)
+	})
+	s.Run("negative qps", func() {
+		config := &StaticConfig{QPS: -1}
+		s.EqualError(config.ValidateRateLimits(), "invalid qps -1, expected a positive number (or 0 for the client-go default)")
+	})
+	s.Run("negative burst", func() {
+		config := &StaticConfig{QPS: 50, Burst: -1}
+		s.EqualError(config.ValidateRateLimits(), "invalid burst -1, expected a positive number (or 0 for the client-go default)")
+	})
+}
+
+func (s *ConfigSuite) TestCost() {
+	config, err := ReadToml([]byte(`
+		[cost]
+		currency = "EUR"
+		cpu_core_hour = 0.0316
+		memory_gib_hour = 0.0042
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the unit prices", func() {
+		s.NoError(config.ValidateCost())
+		s.Equal(Cost{Currency: "EUR", CPUCoreHour: 0.0316, MemoryGiBHour: 0.0042}, config.Cost)
+	})
+	s.Run("negative price", func() {
+		config := &StaticConfig{Cost: Cost{CPUCoreHour: 0.03, MemoryGiBHour: -1}}
+		s.EqualError(config.ValidateCost(), "invalid cost.memory_gib_hour -1, expected a positive price")
+	})
+}
+
+func (s *ConfigSuite) TestImageScanner() {
+	config, err := ReadToml([]byte(`
+		[image_scanner]
+		type = "clair"
+		url = "https://clair.example.com"
+		bearer_token = "token"
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the scanner", func() {
+		s.NoError(config.ValidateImageScanner())
+		s.Equal(ImageScanner{Type: "clair", Url: "https://clair.example.com", BearerToken: "token"}, config.ImageScanner)
+	})
+	s.Run("not configured", func() {
+		s.NoError((&StaticConfig{}).ValidateImageScanner())
+	})
+	s.Run("invalid type", func() {
+		config := &StaticConfig{ImageScanner: ImageScanner{Type: "grype", Url: "https://grype.example.com"}}
+		s.EqualError(config.ValidateImageScanner(), `invalid image_scanner.type "grype", valid types are: clair, trivy`)
+	})
+	s.Run("invalid url", func() {
+		config := &StaticConfig{ImageScanner: ImageScanner{Type: "trivy", Url: "trivy-scan"}}
+		s.EqualError(config.ValidateImageScanner(), `invalid image_scanner.url "trivy-scan", expected a valid URL`)
+	})
+}
+
+func (s *ConfigSuite) TestArtifacts() {
+	config, err := ReadToml([]byte(`
+		[artifacts]
+		dir = "/var/lib/kubernetes-mcp-server"
+		retention = "24h"
+		max_count = 100
+		max_bytes = 1073741824
+		[artifacts.gcs]
+		bucket = "must-gather"
+		access_key_id = "GOOGEXAMPLE"
+		secret_access_key = "s3cr3t"
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the artifacts", func() {
+		s.NoError(config.ValidateArtifacts())
+		s.Equal("/var/lib/kubernetes-mcp-server", config.Artifacts.Dir)
+		s.Equal(24*time.Hour, config.ArtifactsRetention())
+		s.Equal(100, config.Artifacts.MaxCount)
+		s.Equal(int64(1073741824), config.Artifacts.MaxBytes)
+		s.Equal(&ObjectStorage{Bucket: "must-gather", AccessKeyID: "GOOGEXAMPLE", SecretAccessKey: "s3cr3t"}, config.Artifacts.GCS)
+	})
+	s.Run("not configured", func() {
+		s.NoError((&StaticConfig{}).ValidateArtifacts())
+		s.Zero((&StaticConfig{}).ArtifactsRetention())
+	})
+	s.Run("invalid retention", func() {
+		config := &StaticConfig{Artifacts: Artifacts{Retention: "-1h"}}
+		s.EqualError(config.ValidateArtifacts(), `invalid artifacts.retention "-1h", expected a positive duration (e.g. 24h)`)
+	})
+	s.Run("invalid max_count", func() {
+		config := &StaticConfig{Artifacts: Artifacts{MaxCount: -1}}
+		s.EqualError(config.ValidateArtifacts(), "invalid artifacts.max_count -1, expected a positive number (or 0 for no limit)")
+	})
+	s.Run("s3 and gcs", func() {
+		config := &StaticConfig{Artifacts: Artifacts{S3: &ObjectStorage{}, GCS: &ObjectStorage{}}}
+		s.EqualError(config.ValidateArtifacts(), "invalid artifacts, only one of artifacts.s3 and artifacts.gcs can be configured")
+	})
+	s.Run("invalid s3", func() {
+		config := &StaticConfig{Artifacts: Artifacts{S3: &ObjectStorage{Endpoint: "s3.example.com"}}}
+		s.EqualError(config.ValidateArtifacts(), "artifacts.s3.endpoint must be a valid http(s) URL")
+	})
+}
+
+func (s *ConfigSuite) TestPolicies() {
+	config, err := ReadToml([]byte(`
+		[[policies]]
+		name = "no-kube-system"
+		expression = 'request.namespace != "kube-system"'
+		message = "kube-system can't be modified"
+
+		[[policies]]
+		name = "no-host-network"
+		engine = "cel"
+		expression = 'object == null || !has(object.spec.hostNetwork) || !object.spec.hostNetwork'
+		tools = ["resources_*"]
+	`))
+	s.Require().NoError(err)
+	s.Run("reads the policies", func() {
+		s.NoError(config.ValidatePolicies())
+		s.Require().Len(config.Policies, 2)
+		s.Equal(Policy{Name: "no-kube-system", Expression: `request.namespace != "kube-system"`, Message: "kube-system can't be modified"}, config.Policies[0])
+		s.Equal([]string{"resources_*"}, config.Policies[1].Tools)
+	})
+	s.Run("applies to the matching tools", func() {
+		s.True(config.Policies[0].AppliesTo("pods_delete"))
+		s.True(config.Policies[1].AppliesTo("resources_create_or_update"))
+		s.False(config.Policies[1].AppliesTo("pods_delete"))
+	})
+	s.Run("missing name", func() {
+		config := &StaticConfig{Policies: []Policy{{Expression: "true"}}}
+		s.EqualError(config.ValidatePolicies(), "invalid policies[0], name is required")
+	})
+	s.Run("duplicated name", func() {
+		config := &StaticConfig{Policies: []Policy{{Name: "p", Expression: "true"}, {Name: "p", Expression: "false"}}}
+		s.EqualError(config.ValidatePolicies(), "invalid policy p, the name is already used by another policy")
+	})
+	s.Run("missing expression", func() {
+		config := &StaticConfig{Policies: []Policy{{Name: "p"}}}
+		s.EqualError(config.ValidatePolicies(), "invalid policy p, expression is required")
+	})
+	s.Run("unsupported engine", func() {
+		config := &StaticConfig{Policies: []Policy{{Name: "p", Engine: "rego", Expression: "allow"}}}
+		s.EqualError(config.ValidatePolicies(), `invalid policy p, unsupported engine "rego", valid engines are: cel`)
+	})
+	s.Run("invalid tool pattern", func() {
+		config := &StaticConfig{Policies: []Policy{{Name: "p", Expression: "true", Tools: []string{"pods_["}}}}
+		s.ErrorContains(config.ValidatePolicies(), `invalid policy p, invalid tool pattern "pods_["`)
+	})
+}
+
+func (s *ConfigSuite) TestValidateNodeFiles() {
+	s.Run("privileged by default", func() {
+		s.NoError((&StaticConfig{}).ValidateNodeFiles())
+	})
+	s.Run("valid allowed host paths", func() {
+		config := &StaticConfig{NodeFilesUnprivileged: true, NodeFilesAllowedHostPaths: []string{"/var/log", "/etc/kubernetes/manifests"}}
+		s.NoError(config.ValidateNodeFiles())
+	})
+	s.Run("missing allowed host paths", func() {
+		config := &StaticConfig{NodeFilesUnprivileged: true}
+		s.EqualError(config.ValidateNodeFiles(), "node_files_allowed_host_paths is required when node_files_unprivileged is enabled")
+	})
+	s.Run("relative allowed host path", func() {
+		config := &StaticConfig{NodeFilesUnprivileged: true, NodeFilesAllowedHostPaths: []string{"var/log"}}
+		s.EqualError(config.ValidateNodeFiles(), `invalid node_files_allowed_host_paths entry "var/log", expected an absolute path other than /`)
+	})
+	s.Run("root allowed host path", func() {
+		config := &StaticConfig{NodeFilesUnprivileged: true, NodeFilesAllowedHostPaths: []string{"/"}}
+		s.EqualError(config.ValidateNodeFiles(), `invalid node_files_allowed_host_paths entry "/", expected an absolute path other than /`)
+	})
+	s.Run("valid allowed and denied paths", func() {
+		config := &StaticConfig{NodeFilesAllowedPaths: []string{"/var/log", "/etc/kubernetes"}, NodeFilesDeniedPaths: []string{"/etc/kubernetes/pki"}}
+		s.NoError(config.ValidateNodeFiles())
+	})
+	s.Run("valid Windows allowed and denied paths", func() {
+		config := &StaticConfig{NodeFilesAllowedPaths: []string{`C:\var\log`, "c:/etc/kubernetes"}, NodeFilesDeniedPaths: []string{`C:\etc\kubernetes\pki`}}
+		s.NoError(config.ValidateNodeFiles())
+	})
+	s.Run("relative Windows denied path", func() {
+		config := &StaticConfig{NodeFilesDeniedPaths: []string{`C:etc\kubernetes\pki`}}
+		s.EqualError(config.ValidateNodeFiles(), `invalid node_files_denied_paths entry "C:etc\\kubernetes\\pki", expected an absolute path`)
+	})
+	s.Run("relative allowed path", func() {
+		config := &StaticConfig{NodeFilesAllowedPaths: []string{"var/log"}}
+		s.EqualError(config.ValidateNodeFiles(), `invalid node_files_allowed_paths entry "var/log", expected an absolute path`)
+	})
+	s.Run("relative denied path", func() {
+		config := &StaticConfig{NodeFilesDeniedPaths: []string{"etc/kubernetes/pki"}}
+		s.EqualError(config.ValidateNodeFiles(), `invalid node_files_denied_paths entry "etc/kubernetes/pki", expected an absolute path`)
+	})
+}
+
+func (s *ConfigSuite) TestValidateHelperImages() {
+	s.Run("no helper images", func() {
+		s.NoError((&StaticConfig{}).ValidateHelperImages())
+	})
+	s.Run("valid architecture images", func() {
+		config, err := ReadToml([]byte(`
+			[helper_images.busybox.arch]
+			amd64 = "registry.example.com/amd64/busybox:1.37"
+			arm64 = "registry.example.com/arm64v8/busybox:1.37"
+		`))
+		s.Require().NoError(err)
+		s.NoError(config.ValidateHelperImages())
+	})
+	s.Run("invalid architecture", func() {
+		config := &StaticConfig{HelperImages: map[string]HelperImage{
+			"busybox": {Arch: map[string]string{"linux/arm64": "registry.example.com/arm64v8/busybox:1.37"}},
+		}}
+		s.EqualError(config.ValidateHelperImages(), `invalid helper_images.busybox.arch entry "linux/arm64", expected a kubernetes.io/arch node label value (e.g. amd64, arm64)`)
+	})
+	s.Run("missing architecture image", func() {
+		config := &StaticConfig{HelperImages: map[string]HelperImage{"debug": {Arch: map[string]string{"arm64": ""}}}}
+		s.EqualError(config.ValidateHelperImages(), `invalid helper_images.debug.arch entry "arm64", expected an image`)
+	})
+}
+
 func TestConfig(t *testing.T) {
 	suite.Run(t, new(ConfigSuite))
 }
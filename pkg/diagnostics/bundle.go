@@ -0,0 +1,88 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// File is an entry of a diagnostic bundle
+type File struct {
+	// Name is the slash-separated path of the file in the bundle, e.g. nodes/worker-1/kubelet.log
+	Name    string
+	Content []byte
+}
+
+// Diagnostics stores the diagnostic bundles in the artifact store
+type Diagnostics struct {
+	store *artifacts.Store
+}
+
+// NewDiagnostics creates a bundle store from the artifacts configuration, the deprecated output_dir and s3 of the
+// diagnostics toolset configuration take precedence if set
+func NewDiagnostics(config *config.StaticConfig) *Diagnostics {
+	d := &Diagnostics{store: artifacts.NewStore(config)}
+	if cfg, ok := config.GetToolsetConfig(ToolsetName); ok {
+		if dc, ok := cfg.(*Config); ok && dc != nil {
+			d.store = d.store.WithLocation(dc.OutputDir, dc.S3)
+		}
+	}
+	return d
+}
+
+// Store writes the files into a <name>.tar.gz bundle artifact, uploaded to the object storage if configured.
+// The local path of the bundle is returned along with the URL of the uploaded object (empty if not uploaded).
+func (d *Diagnostics) Store(ctx context.Context, name string, files []File) (string, string, error) {
+	archive, err := Archive(name, files, time.Now())
+	if err != nil {
+		return "", "", err
+	}
+	artifact, err := d.store.Put(ctx, name+".tar.gz", archive)
+	if artifact == nil {
+		return "", "", err
+	}
+	if err != nil {
+		return artifact.Path, "", fmt.Errorf("failed to upload diagnostic bundle to %s: %w", d.store.RemoteName(), err)
+	}
+	return artifact.Path, artifact.URL, nil
+}
+
+// Archive returns the gzip compressed tar archive of the files, rooted in a directory with the provided name
+func Archive(name string, files []File, modTime time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		fileName := path.Clean("/" + file.Name)
+		if fileName == "/" || strings.HasSuffix(file.Name, "/") {
+			return nil, fmt.Errorf("invalid bundle file name %q", file.Name)
+		}
+		header := &tar.Header{
+			Name:    name + fileName,
+			Mode:    0600,
+			Size:    int64(len(file.Content)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write bundle file %s: %w", file.Name, err)
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return nil, fmt.Errorf("failed to write bundle file %s: %w", file.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,69 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// ToolsetName is the name of the toolset (and of its configuration section) that collects diagnostic bundles
+const ToolsetName = "diagnostics"
+
+// Config holds the diagnostics toolset configuration.
+// OutputDir and S3 are deprecated in favor of the artifact store configuration ([artifacts]), they take precedence
+// over it for the diagnostic bundles if set.
+type Config struct {
+	// OutputDir is the local directory where the bundles are written
+	OutputDir string `toml:"output_dir,omitempty"`
+	// S3 uploads the bundles to an S3-compatible object storage (Optional)
+	S3 *S3Config `toml:"s3,omitempty"`
+}
+
+// S3Config is the S3-compatible endpoint where the bundles are uploaded (AWS S3, MinIO, Ceph RGW, ...)
+type S3Config = config.ObjectStorage
+
+var _ config.Extended = (*Config)(nil)
+
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("diagnostics config is nil")
+	}
+	if c.OutputDir != "" {
+		if info, err := os.Stat(c.OutputDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("output_dir must be an existing directory: %s", c.OutputDir)
+		}
+	}
+	if c.S3 == nil {
+		return nil
+	}
+	return c.S3.Validate("s3")
+}
+
+func diagnosticsToolsetParser(ctx context.Context, primitive toml.Primitive, md toml.MetaData) (config.Extended, error) {
+	var cfg Config
+	if err := md.PrimitiveDecode(primitive, &cfg); err != nil {
+		return nil, err
+	}
+	// Relative file paths are resolved relative to the config directory
+	configDir := config.ConfigDirPathFromContext(ctx)
+	paths := []*string{&cfg.OutputDir}
+	if cfg.S3 != nil {
+		paths = append(paths, &cfg.S3.SecretAccessKeyFile)
+	}
+	for _, path := range paths {
+		if *path != "" && configDir != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(configDir, *path)
+		}
+	}
+	return &cfg, nil
+}
+
+func init() {
+	config.RegisterToolsetConfig(ToolsetName, diagnosticsToolsetParser)
+}
@@ -0,0 +1,178 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type DiagnosticsSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	requests []*http.Request
+	bodies   [][]byte
+	status   int
+}
+
+func (s *DiagnosticsSuite) SetupTest() {
+	s.requests, s.bodies = nil, nil
+	s.status = http.StatusOK
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		s.requests = append(s.requests, req)
+		s.bodies = append(s.bodies, body)
+		w.WriteHeader(s.status)
+		if s.status != http.StatusOK {
+			_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+		}
+	}))
+}
+
+func (s *DiagnosticsSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *DiagnosticsSuite) diagnostics(toml string) *Diagnostics {
+	return NewDiagnostics(test.Must(config.ReadToml([]byte(toml))))
+}
+
+func (s *DiagnosticsSuite) TestArchive() {
+	archive, err := Archive("bundle-1", []File{
+		{Name: "nodes.yaml", Content: []byte("items: []\n")},
+		{Name: "nodes/node-1/kubelet.log", Content: []byte("I0101 kubelet started\n")},
+	}, time.Unix(1735725600, 0))
+	s.Require().NoError(err)
+	s.Run("writes the files in a directory named after the bundle", func() {
+		s.Equal(map[string]string{
+			"bundle-1/nodes.yaml":               "items: []\n",
+			"bundle-1/nodes/node-1/kubelet.log": "I0101 kubelet started\n",
+		}, s.untar(archive))
+	})
+	s.Run("confines the files to the bundle directory", func() {
+		archive, err = Archive("bundle-1", []File{{Name: "../../etc/passwd"}}, time.Now())
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"bundle-1/etc/passwd": ""}, s.untar(archive))
+	})
+	s.Run("rejects directories", func() {
+		_, err = Archive("bundle-1", []File{{Name: "nodes/"}}, time.Now())
+		s.EqualError(err, `invalid bundle file name "nodes/"`)
+	})
+}
+
+func (s *DiagnosticsSuite) TestStoreLocal() {
+	outputDir := s.T().TempDir()
+	localPath, objectURL, err := s.diagnostics(`
+		[toolset_configs.diagnostics]
+		output_dir = "`+filepath.ToSlash(outputDir)+`"
+	`).Store(s.T().Context(), "bundle-1", []File{{Name: "nodes.yaml", Content: []byte("items: []\n")}})
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(outputDir, "bundle-1.tar.gz"), localPath)
+	s.Empty(objectURL)
+	archive, err := os.ReadFile(localPath)
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"bundle-1/nodes.yaml": "items: []\n"}, s.untar(archive))
+	s.Empty(s.requests)
+}
+
+func (s *DiagnosticsSuite) TestStoreS3() {
+	secretFile := filepath.Join(s.T().TempDir(), "secret")
+	s.Require().NoError(os.WriteFile(secretFile, []byte("s3cr3t\n"), 0600))
+	diagnostics := s.diagnostics(`
+		[toolset_configs.diagnostics]
+		output_dir = "` + filepath.ToSlash(s.T().TempDir()) + `"
+		[toolset_configs.diagnostics.s3]
+		endpoint = "` + s.server.URL + `"
+		region = "eu-west-1"
+		bucket = "must-gather"
+		prefix = "clusters/prod/"
+		access_key_id = "AKIDEXAMPLE"
+		secret_access_key_file = "` + filepath.ToSlash(secretFile) + `"
+	`)
+	s.Run("uploads the bundle with a path-style request", func() {
+		_, objectURL, err := diagnostics.Store(s.T().Context(), "bundle-1", []File{{Name: "nodes.yaml", Content: []byte("items: []\n")}})
+		s.Require().NoError(err)
+		s.Equal(s.server.URL+"/must-gather/clusters/prod/bundle-1.tar.gz", objectURL)
+		s.Require().Len(s.requests, 1)
+		s.Equal(http.MethodPut, s.requests[0].Method)
+		s.Equal("/must-gather/clusters/prod/bundle-1.tar.gz", s.requests[0].URL.Path)
+		s.Equal(map[string]string{"bundle-1/nodes.yaml": "items: []\n"}, s.untar(s.bodies[0]))
+	})
+	s.Run("signs the request with AWS Signature Version 4", func() {
+		s.Regexp(`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/\d{8}/eu-west-1/s3/aws4_request, `+
+			`SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`,
+			s.requests[0].Header.Get("Authorization"))
+		sum := sha256.Sum256(s.bodies[0])
+		s.Equal(hex.EncodeToString(sum[:]), s.requests[0].Header.Get("X-Amz-Content-Sha256"))
+	})
+	s.Run("returns the error of the object storage", func() {
+		s.status = http.StatusForbidden
+		localPath, _, err := diagnostics.Store(s.T().Context(), "bundle-2", nil)
+		s.EqualError(err, "failed to upload diagnostic bundle to s3: 403 Forbidden: <Error><Code>AccessDenied</Code></Error>")
+		s.FileExists(localPath, "the local bundle is kept")
+	})
+}
+
+func (s *DiagnosticsSuite) TestConfig() {
+	s.Run("requires an existing output_dir", func() {
+		_, err := config.ReadToml([]byte(`
+			[toolset_configs.diagnostics]
+			output_dir = "/does/not/exist"
+		`))
+		s.ErrorContains(err, "output_dir must be an existing directory: /does/not/exist")
+	})
+	s.Run("requires s3 bucket", func() {
+		_, err := config.ReadToml([]byte(`
+			[toolset_configs.diagnostics.s3]
+			endpoint = "https://s3.example.com"
+			access_key_id = "AKIDEXAMPLE"
+			secret_access_key = "s3cr3t"
+		`))
+		s.ErrorContains(err, "s3.bucket is required")
+	})
+	s.Run("rejects secret_access_key with secret_access_key_file", func() {
+		_, err := config.ReadToml([]byte(`
+			[toolset_configs.diagnostics.s3]
+			endpoint = "https://s3.example.com"
+			bucket = "must-gather"
+			access_key_id = "AKIDEXAMPLE"
+			secret_access_key = "s3cr3t"
+			secret_access_key_file = "/var/run/secrets/s3"
+		`))
+		s.ErrorContains(err, "exactly one of s3.secret_access_key and s3.secret_access_key_file is required")
+	})
+}
+
+func (s *DiagnosticsSuite) untar(archive []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	s.Require().NoError(err)
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		s.Require().NoError(err)
+		content, err := io.ReadAll(tr)
+		s.Require().NoError(err)
+		files[header.Name] = string(content)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	suite.Run(t, new(DiagnosticsSuite))
+}
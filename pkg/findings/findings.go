@@ -0,0 +1,255 @@
+// Package findings provides the common report format for the audit-style tools (scanners, linters, analyzers).
+//
+// Reports can be rendered as YAML (default, for the LLM), as the common findings JSON, or as
+// SARIF 2.1.0 so that the same results can be ingested by CI pipelines and security dashboards.
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+const (
+	FormatYaml  = "yaml"
+	FormatJson  = "json"
+	FormatSarif = "sarif"
+)
+
+// Formats are the supported report formats
+var Formats = []string{FormatYaml, FormatJson, FormatSarif}
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rule describes a check performed by an audit tool
+type Rule struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	HelpURI     string   `json:"helpUri,omitempty"`
+	Severity    Severity `json:"severity"`
+}
+
+// Resource identifies the Kubernetes object a Finding applies to
+type Resource struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Field is the path of the offending field within the object (e.g. spec.containers[0].securityContext)
+	Field string `json:"field,omitempty"`
+}
+
+// String returns the fully qualified name of the resource (namespace/kind/name)
+func (r *Resource) String() string {
+	parts := []string{r.Kind, r.Name}
+	if r.Namespace != "" {
+		parts = append([]string{r.Namespace}, parts...)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Finding is a single result reported by an audit tool
+type Finding struct {
+	RuleID   string   `json:"ruleId"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Resource Resource `json:"resource"`
+	// Remediation is a human-readable suggestion to fix the finding
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// Report is the result of an audit tool execution
+type Report struct {
+	// Tool is the name of the MCP tool that produced the report
+	Tool     string    `json:"tool"`
+	Rules    []Rule    `json:"rules,omitempty"`
+	Findings []Finding `json:"findings"`
+}
+
+// Add appends a finding for the provided rule, the rule severity is used
+func (r *Report) Add(rule Rule, resource Resource, message, remediation string) {
+	if !slices.ContainsFunc(r.Rules, func(existing Rule) bool { return existing.ID == rule.ID }) {
+		r.Rules = append(r.Rules, rule)
+	}
+	r.Findings = append(r.Findings, Finding{
+		RuleID:      rule.ID,
+		Severity:    rule.Severity,
+		Message:     message,
+		Resource:    resource,
+		Remediation: remediation,
+	})
+}
+
+// Summary returns the number of findings per severity
+func (r *Report) Summary() map[Severity]int {
+	summary := map[Severity]int{}
+	for _, f := range r.Findings {
+		summary[f.Severity]++
+	}
+	return summary
+}
+
+// Render returns the report in the requested format (yaml if empty)
+func (r *Report) Render(format string) (string, error) {
+	switch format {
+	case "", FormatYaml:
+		return output.MarshalYaml(r)
+	case FormatJson:
+		ret, err := json.MarshalIndent(r, "", "  ")
+		return string(ret), err
+	case FormatSarif:
+		ret, err := json.MarshalIndent(r.Sarif(), "", "  ")
+		return string(ret), err
+	default:
+		return "", fmt.Errorf("unsupported report format %q, supported formats: %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// FormatProperty is the input schema property audit tools expose to select the report format
+func FormatProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Description: "Format of the report (Optional, default yaml). " +
+			"json returns the common findings JSON and sarif returns a SARIF 2.1.0 log, both suitable for CI pipelines and security dashboards",
+		Enum: []any{FormatYaml, FormatJson, FormatSarif},
+	}
+}
+
+// SarifLog is the root object of a SARIF 2.1.0 log file (only the properties used by the reports are defined)
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type SarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SarifRule `json:"rules,omitempty"`
+}
+
+type SarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name,omitempty"`
+	ShortDescription     *SarifMessage      `json:"shortDescription,omitempty"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration SarifConfiguration `json:"defaultConfiguration"`
+}
+
+type SarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+type SarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations"`
+}
+
+type SarifLocation struct {
+	LogicalLocations []SarifLogicalLocation `json:"logicalLocations"`
+}
+
+type SarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// Sarif converts the report to a SARIF 2.1.0 log with a single run.
+// Kubernetes objects don't map to files, they're reported as logical locations (namespace/kind/name[/field]).
+func (r *Report) Sarif() *SarifLog {
+	run := SarifRun{
+		Tool: SarifTool{Driver: SarifDriver{
+			Name:           version.BinaryName + "/" + r.Tool,
+			Version:        version.Version,
+			InformationURI: "https://github.com/containers/kubernetes-mcp-server",
+		}},
+		Results: []SarifResult{},
+	}
+	ruleIndex := map[string]int{}
+	for _, rule := range r.Rules {
+		ruleIndex[rule.ID] = len(run.Tool.Driver.Rules)
+		sarifRule := SarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			HelpURI:              rule.HelpURI,
+			DefaultConfiguration: SarifConfiguration{Level: sarifLevel(rule.Severity)},
+		}
+		if rule.Description != "" {
+			sarifRule.ShortDescription = &SarifMessage{Text: rule.Description}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule)
+	}
+	for _, f := range r.Findings {
+		index, ok := ruleIndex[f.RuleID]
+		if !ok {
+			index = -1
+		}
+		message := f.Message
+		if f.Remediation != "" {
+			message += " Remediation: " + f.Remediation
+		}
+		fullyQualifiedName := f.Resource.String()
+		if f.Resource.Field != "" {
+			fullyQualifiedName += "/" + f.Resource.Field
+		}
+		run.Results = append(run.Results, SarifResult{
+			RuleID:    f.RuleID,
+			RuleIndex: index,
+			Level:     sarifLevel(f.Severity),
+			Message:   SarifMessage{Text: message},
+			Locations: []SarifLocation{{LogicalLocations: []SarifLogicalLocation{{
+				Name:               f.Resource.Name,
+				FullyQualifiedName: fullyQualifiedName,
+				Kind:               "resource",
+			}}}},
+		})
+	}
+	return &SarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []SarifRun{run},
+	}
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
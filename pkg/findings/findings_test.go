@@ -0,0 +1,101 @@
+package findings
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FindingsSuite struct {
+	suite.Suite
+	report *Report
+}
+
+var (
+	privilegedRule = Rule{ID: "KMS001", Name: "PrivilegedContainer", Description: "Containers should not run privileged", Severity: SeverityError}
+	latestTagRule  = Rule{ID: "KMS002", Name: "LatestImageTag", Severity: SeverityWarning}
+)
+
+func (s *FindingsSuite) SetupTest() {
+	s.report = &Report{Tool: "workloads_lint"}
+	s.report.Add(privilegedRule, Resource{APIVersion: "v1", Kind: "Pod", Namespace: "ns-1", Name: "pod-1", Field: "spec.containers[0].securityContext.privileged"},
+		"Container nginx is privileged", "Set privileged to false")
+	s.report.Add(latestTagRule, Resource{APIVersion: "v1", Kind: "Pod", Namespace: "ns-1", Name: "pod-1"},
+		"Container nginx uses the latest tag", "")
+	s.report.Add(privilegedRule, Resource{APIVersion: "v1", Kind: "Pod", Namespace: "ns-2", Name: "pod-2"},
+		"Container sidecar is privileged", "")
+}
+
+func (s *FindingsSuite) TestAdd() {
+	s.Run("registers each rule once", func() {
+		s.Equal([]Rule{privilegedRule, latestTagRule}, s.report.Rules)
+	})
+	s.Run("uses the rule severity", func() {
+		s.Equal(map[Severity]int{SeverityError: 2, SeverityWarning: 1}, s.report.Summary())
+	})
+}
+
+func (s *FindingsSuite) TestRender() {
+	s.Run("yaml by default", func() {
+		rendered, err := s.report.Render("")
+		s.Require().NoError(err)
+		s.Contains(rendered, "tool: workloads_lint\n")
+		s.Contains(rendered, "- message: Container nginx is privileged\n")
+	})
+	s.Run("json", func() {
+		rendered, err := s.report.Render(FormatJson)
+		s.Require().NoError(err)
+		var report Report
+		s.Require().NoError(json.Unmarshal([]byte(rendered), &report))
+		s.Equal(*s.report, report)
+	})
+	s.Run("unsupported format", func() {
+		_, err := s.report.Render("xml")
+		s.EqualError(err, `unsupported report format "xml", supported formats: yaml, json, sarif`)
+	})
+}
+
+func (s *FindingsSuite) TestSarif() {
+	rendered, err := s.report.Render(FormatSarif)
+	s.Require().NoError(err)
+	var log SarifLog
+	s.Require().NoError(json.Unmarshal([]byte(rendered), &log))
+	s.Run("is a SARIF 2.1.0 log with a single run", func() {
+		s.Equal("2.1.0", log.Version)
+		s.Equal("https://json.schemastore.org/sarif-2.1.0.json", log.Schema)
+		s.Require().Len(log.Runs, 1)
+		s.Equal("kubernetes-mcp-server/workloads_lint", log.Runs[0].Tool.Driver.Name)
+	})
+	s.Run("contains the rules", func() {
+		s.Equal([]SarifRule{
+			{ID: "KMS001", Name: "PrivilegedContainer", ShortDescription: &SarifMessage{Text: "Containers should not run privileged"}, DefaultConfiguration: SarifConfiguration{Level: "error"}},
+			{ID: "KMS002", Name: "LatestImageTag", DefaultConfiguration: SarifConfiguration{Level: "warning"}},
+		}, log.Runs[0].Tool.Driver.Rules)
+	})
+	s.Run("contains the results with logical locations", func() {
+		s.Require().Len(log.Runs[0].Results, 3)
+		s.Equal(SarifResult{
+			RuleID:    "KMS001",
+			RuleIndex: 0,
+			Level:     "error",
+			Message:   SarifMessage{Text: "Container nginx is privileged Remediation: Set privileged to false"},
+			Locations: []SarifLocation{{LogicalLocations: []SarifLogicalLocation{{
+				Name:               "pod-1",
+				FullyQualifiedName: "ns-1/Pod/pod-1/spec.containers[0].securityContext.privileged",
+				Kind:               "resource",
+			}}}},
+		}, log.Runs[0].Results[0])
+		s.Equal(1, log.Runs[0].Results[1].RuleIndex)
+		s.Equal("ns-2/Pod/pod-2", log.Runs[0].Results[2].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	})
+	s.Run("empty reports have empty results", func() {
+		rendered, err := (&Report{Tool: "empty"}).Render(FormatSarif)
+		s.Require().NoError(err)
+		s.Contains(rendered, `"results": []`)
+	})
+}
+
+func TestFindings(t *testing.T) {
+	suite.Run(t, new(FindingsSuite))
+}
@@ -0,0 +1,356 @@
+// Package gitops reads the state of the GitOps controllers of a cluster, the Applications of Argo CD
+// (argoproj.io/v1alpha1) and the Kustomizations of Flux (kustomize.toolkit.fluxcd.io/v1), and computes the drift
+// of the objects they manage.
+//
+// The CRDs are read with the dynamic client, the controllers don't need to be installed for the package to build:
+// the kinds whose CRD is missing are reported as not installed.
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	KindApplication   = "Application"
+	KindKustomization = "Kustomization"
+
+	SyncStatusSynced    = "Synced"
+	SyncStatusOutOfSync = "OutOfSync"
+	SyncStatusUnknown   = "Unknown"
+
+	// DriftMissing is the drift of the managed objects that don't exist in the cluster
+	DriftMissing = "Missing"
+	// DriftModified is the drift of the objects whose live fields differ from the desired ones
+	DriftModified = "Modified"
+	// DriftOutOfSync is the drift of the objects reported out of sync by the controller
+	DriftOutOfSync = "OutOfSync"
+	// DriftExtra is the drift of the objects managed by the controller but not in the desired manifest (pruned on sync)
+	DriftExtra = "Extra"
+
+	// MaxFieldDiffs is the maximum number of field diffs reported per object
+	MaxFieldDiffs = 50
+
+	// FluxReconcileAnnotation requests the reconciliation of a Flux object, like flux reconcile
+	FluxReconcileAnnotation = "reconcile.fluxcd.io/requestedAt"
+)
+
+var (
+	ApplicationGVR   = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+	KustomizationGVR = schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+	// Kinds are the supported GitOps kinds
+	Kinds = []string{KindApplication, KindKustomization}
+	// controllerManagers are the field managers of the controllers, the changes of the other managers made after
+	// the last apply of the controller are drift
+	controllerManagers = map[string][]string{
+		KindApplication:   {"argocd-controller", "argocd-application-controller"},
+		KindKustomization: {"kustomize-controller"},
+	}
+)
+
+// GVR returns the GroupVersionResource of the GitOps kind
+func GVR(kind string) (schema.GroupVersionResource, error) {
+	switch kind {
+	case KindApplication:
+		return ApplicationGVR, nil
+	case KindKustomization:
+		return KustomizationGVR, nil
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("unsupported GitOps kind %s, supported kinds are %v", kind, Kinds)
+}
+
+// App is an Argo CD Application or a Flux Kustomization
+type App struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Source of the desired state, e.g. https://github.com/org/repo path apps/web at main, GitRepository/flux-system/repo path ./apps
+	Source string `json:"source"`
+	// Revision is the last synced (Argo CD) or applied (Flux) revision
+	Revision   string `json:"revision,omitempty"`
+	SyncStatus string `json:"syncStatus"`
+	Health     string `json:"health,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Suspended  bool   `json:"suspended,omitempty"`
+	// LastSync is the time of the last sync operation (Argo CD) or of the last Ready transition (Flux)
+	LastSync string `json:"lastSync,omitempty"`
+	// TargetNamespace is the default namespace of the managed objects without namespace
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// Resources are the objects managed by the controller, with their sync status for Argo CD
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+// Resource is an object managed by a GitOps controller
+type Resource struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Status is the sync status reported by Argo CD (e.g. Synced, OutOfSync), empty for Flux
+	Status string `json:"status,omitempty"`
+	Health string `json:"health,omitempty"`
+}
+
+// GroupVersionKind returns the GroupVersionKind of the managed object
+func (r Resource) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: r.Group, Version: r.Version, Kind: r.Kind}
+}
+
+// String returns the reference of the managed object, e.g. Deployment.apps/shop/web
+func (r Resource) String() string {
+	kind := r.Kind
+	if r.Group != "" {
+		kind += "." + r.Group
+	}
+	return strings.Join(slices.DeleteFunc([]string{kind, r.Namespace, r.Name}, func(s string) bool { return s == "" }), "/")
+}
+
+// NewApp reads the Argo CD Application or Flux Kustomization
+func NewApp(obj *unstructured.Unstructured) *App {
+	if obj.GetKind() == KindKustomization {
+		return newKustomization(obj)
+	}
+	return newApplication(obj)
+}
+
+func newApplication(obj *unstructured.Unstructured) *App {
+	app := &App{Kind: KindApplication, Namespace: obj.GetNamespace(), Name: obj.GetName(), SyncStatus: SyncStatusUnknown}
+	sources, _, _ := unstructured.NestedSlice(obj.Object, "spec", "sources")
+	if source, found, _ := unstructured.NestedMap(obj.Object, "spec", "source"); found {
+		sources = append([]any{source}, sources...)
+	}
+	var descriptions []string
+	for _, s := range sources {
+		source, _ := s.(map[string]any)
+		description, _, _ := unstructured.NestedString(source, "repoURL")
+		for _, field := range []string{"path", "chart"} {
+			if value, _, _ := unstructured.NestedString(source, field); value != "" {
+				description += " " + field + " " + value
+			}
+		}
+		if revision, _, _ := unstructured.NestedString(source, "targetRevision"); revision != "" {
+			description += " at " + revision
+		}
+		descriptions = append(descriptions, description)
+	}
+	app.Source = strings.Join(descriptions, ", ")
+	app.TargetNamespace, _, _ = unstructured.NestedString(obj.Object, "spec", "destination", "namespace")
+	if status, _, _ := unstructured.NestedString(obj.Object, "status", "sync", "status"); status != "" {
+		app.SyncStatus = status
+	}
+	app.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "revision")
+	app.Health, _, _ = unstructured.NestedString(obj.Object, "status", "health", "status")
+	app.LastSync, _, _ = unstructured.NestedString(obj.Object, "status", "operationState", "finishedAt")
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "phase"); phase != "" && phase != "Succeeded" {
+		message, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "message")
+		app.Message = strings.TrimSuffix("sync "+phase+": "+message, ": ")
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, _ := c.(map[string]any)
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		app.Message = strings.TrimPrefix(app.Message+"; "+conditionType+": "+message, "; ")
+	}
+	resources, _, _ := unstructured.NestedSlice(obj.Object, "status", "resources")
+	for _, r := range resources {
+		resource, _ := r.(map[string]any)
+		managed := Resource{}
+		managed.Group, _, _ = unstructured.NestedString(resource, "group")
+		managed.Version, _, _ = unstructured.NestedString(resource, "version")
+		managed.Kind, _, _ = unstructured.NestedString(resource, "kind")
+		managed.Namespace, _, _ = unstructured.NestedString(resource, "namespace")
+		managed.Name, _, _ = unstructured.NestedString(resource, "name")
+		managed.Status, _, _ = unstructured.NestedString(resource, "status")
+		managed.Health, _, _ = unstructured.NestedString(resource, "health", "status")
+		app.Resources = append(app.Resources, managed)
+	}
+	return app
+}
+
+func newKustomization(obj *unstructured.Unstructured) *App {
+	app := &App{Kind: KindKustomization, Namespace: obj.GetNamespace(), Name: obj.GetName(), SyncStatus: SyncStatusUnknown}
+	sourceKind, _, _ := unstructured.NestedString(obj.Object, "spec", "sourceRef", "kind")
+	sourceName, _, _ := unstructured.NestedString(obj.Object, "spec", "sourceRef", "name")
+	sourceNamespace, _, _ := unstructured.NestedString(obj.Object, "spec", "sourceRef", "namespace")
+	if sourceNamespace == "" {
+		sourceNamespace = app.Namespace
+	}
+	app.Source = sourceKind + "/" + sourceNamespace + "/" + sourceName
+	if path, _, _ := unstructured.NestedString(obj.Object, "spec", "path"); path != "" {
+		app.Source += " path " + path
+	}
+	app.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	app.TargetNamespace, _, _ = unstructured.NestedString(obj.Object, "spec", "targetNamespace")
+	app.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+	attempted, _, _ := unstructured.NestedString(obj.Object, "status", "lastAttemptedRevision")
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, _ := c.(map[string]any)
+		if conditionType, _, _ := unstructured.NestedString(condition, "type"); conditionType != "Ready" {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		app.LastSync, _, _ = unstructured.NestedString(condition, "lastTransitionTime")
+		app.Message = strings.TrimPrefix(reason+": "+message, ": ")
+		switch {
+		case status == string(metav1.ConditionTrue) && attempted == app.Revision:
+			app.SyncStatus, app.Health = SyncStatusSynced, "Healthy"
+		case status == string(metav1.ConditionFalse):
+			app.SyncStatus, app.Health = SyncStatusOutOfSync, "Degraded"
+		default:
+			app.Health = "Progressing"
+		}
+	}
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "status", "inventory", "entries")
+	for _, e := range entries {
+		entry, _ := e.(map[string]any)
+		id, _, _ := unstructured.NestedString(entry, "id")
+		version, _, _ := unstructured.NestedString(entry, "v")
+		// The inventory ids are <namespace>_<name>_<group>_<kind>, the names and namespaces can't contain underscores
+		parts := strings.SplitN(id, "_", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		app.Resources = append(app.Resources, Resource{Namespace: parts[0], Name: parts[1], Group: parts[2], Kind: parts[3], Version: version})
+	}
+	return app
+}
+
+// Drift is the difference between the desired and the live state of a managed object
+type Drift struct {
+	Resource
+	Drift string `json:"drift"`
+	// Reason explains the drift, e.g. the field manager that changed the object after the controller
+	Reason string      `json:"reason,omitempty"`
+	Fields []FieldDiff `json:"fields,omitempty"`
+	// FieldsTruncated is set when more than MaxFieldDiffs fields differ
+	FieldsTruncated bool `json:"fieldsTruncated,omitempty"`
+}
+
+// FieldDiff is a desired field whose live value differs, Live is missing if the field isn't set in the live object
+type FieldDiff struct {
+	Path    string `json:"path"`
+	Desired any    `json:"desired"`
+	Live    any    `json:"live,omitempty"`
+}
+
+// DesiredDiff compares the fields set in the desired object with the live object, the fields only set in the live
+// object (defaults, status, fields of other managers) are not drift.
+// The values of the Secrets are redacted.
+func DesiredDiff(desired, live *unstructured.Unstructured) *Drift {
+	drift := &Drift{Drift: DriftModified}
+	desiredObject, liveObject := normalize(desired.Object), normalize(live.Object)
+	for _, field := range []string{"apiVersion", "kind", "status"} {
+		delete(desiredObject, field)
+	}
+	if metadata, ok := desiredObject["metadata"].(map[string]any); ok {
+		desiredObject["metadata"] = map[string]any{"labels": metadata["labels"], "annotations": metadata["annotations"]}
+	}
+	// stringData is write-only, it's merged into the data of the Secret
+	if desired.GetKind() == "Secret" {
+		delete(desiredObject, "stringData")
+	}
+	diffFields(drift, "", desiredObject, liveObject)
+	if desired.GetKind() == "Secret" {
+		for i := range drift.Fields {
+			drift.Fields[i].Desired, drift.Fields[i].Live = output.Redacted, output.Redacted
+		}
+	}
+	return drift
+}
+
+func diffFields(drift *Drift, path string, desired, live any) {
+	if desired == nil || reflect.DeepEqual(desired, live) {
+		return
+	}
+	desiredMap, desiredIsMap := desired.(map[string]any)
+	liveMap, liveIsMap := live.(map[string]any)
+	if desiredIsMap && liveIsMap {
+		keys := make([]string, 0, len(desiredMap))
+		for key := range desiredMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffFields(drift, fieldPath(path, key), desiredMap[key], liveMap[key])
+		}
+		return
+	}
+	desiredList, desiredIsList := desired.([]any)
+	liveList, liveIsList := live.([]any)
+	if desiredIsList && liveIsList && len(desiredList) == len(liveList) {
+		for i := range desiredList {
+			diffFields(drift, fmt.Sprintf("%s[%d]", path, i), desiredList[i], liveList[i])
+		}
+		return
+	}
+	if len(drift.Fields) >= MaxFieldDiffs {
+		drift.FieldsTruncated = true
+		return
+	}
+	drift.Fields = append(drift.Fields, FieldDiff{Path: path, Desired: desired, Live: live})
+}
+
+// fieldPath quotes the keys that aren't identifiers, e.g. metadata.labels["app.kubernetes.io/name"]
+func fieldPath(path, key string) string {
+	if strings.ContainsAny(key, "./[]\" ") || key == "" {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// normalize returns a copy of the object with the JSON representation of its values, so that the numbers decoded
+// from YAML manifests and from the API server compare equal
+func normalize(object map[string]any) map[string]any {
+	ret := map[string]any{}
+	if content, err := json.Marshal(object); err == nil {
+		_ = json.Unmarshal(content, &ret)
+	}
+	return ret
+}
+
+// OutOfBandChanges returns the drift of the live object changed by other field managers than the controller of the
+// GitOps kind after its last apply, nil if there's none (or if the controller doesn't manage the fields of the object)
+func OutOfBandChanges(kind string, live *unstructured.Unstructured) *Drift {
+	var applied time.Time
+	for _, entry := range live.GetManagedFields() {
+		if slices.Contains(controllerManagers[kind], entry.Manager) && entry.Time != nil && entry.Time.After(applied) {
+			applied = entry.Time.Time
+		}
+	}
+	if applied.IsZero() {
+		return nil
+	}
+	var changes []string
+	for _, entry := range live.GetManagedFields() {
+		if slices.Contains(controllerManagers[kind], entry.Manager) || entry.Subresource != "" || entry.Time == nil || !entry.Time.After(applied) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s (%s at %s)", entry.Manager, entry.Operation, entry.Time.UTC().Format(time.RFC3339)))
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return &Drift{
+		Drift:  DriftModified,
+		Reason: fmt.Sprintf("changed by %s after the last apply of the controller at %s", strings.Join(changes, ", "), applied.UTC().Format(time.RFC3339)),
+	}
+}
@@ -0,0 +1,164 @@
+package gitops
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func TestNewApp(t *testing.T) {
+	t.Run("reads an Argo CD Application", func(t *testing.T) {
+		app := NewApp(&unstructured.Unstructured{Object: map[string]any{
+			"kind":     "Application",
+			"metadata": map[string]any{"namespace": "argocd", "name": "shop"},
+			"spec": map[string]any{
+				"source":      map[string]any{"repoURL": "https://github.com/org/repo", "path": "apps/shop", "targetRevision": "main"},
+				"destination": map[string]any{"namespace": "shop"},
+			},
+			"status": map[string]any{
+				"sync":           map[string]any{"status": "OutOfSync", "revision": "abc123"},
+				"health":         map[string]any{"status": "Degraded"},
+				"operationState": map[string]any{"phase": "Failed", "message": "one or more objects failed to apply"},
+				"resources": []any{
+					map[string]any{"group": "apps", "version": "v1", "kind": "Deployment", "namespace": "shop", "name": "web", "status": "OutOfSync", "health": map[string]any{"status": "Degraded"}},
+				},
+			},
+		}})
+		expected := &App{
+			Kind: KindApplication, Namespace: "argocd", Name: "shop",
+			Source: "https://github.com/org/repo path apps/shop at main", Revision: "abc123",
+			SyncStatus: "OutOfSync", Health: "Degraded", Message: "sync Failed: one or more objects failed to apply", TargetNamespace: "shop",
+			Resources: []Resource{{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "shop", Name: "web", Status: "OutOfSync", Health: "Degraded"}},
+		}
+		if !reflect.DeepEqual(app, expected) {
+			t.Errorf("expected %+v, got %+v", expected, app)
+		}
+	})
+	t.Run("reads a Flux Kustomization", func(t *testing.T) {
+		app := NewApp(&unstructured.Unstructured{Object: map[string]any{
+			"kind":     "Kustomization",
+			"metadata": map[string]any{"namespace": "flux-system", "name": "shop"},
+			"spec": map[string]any{
+				"sourceRef":       map[string]any{"kind": "GitRepository", "name": "repo"},
+				"path":            "./apps/shop",
+				"targetNamespace": "shop",
+			},
+			"status": map[string]any{
+				"lastAppliedRevision":   "main@sha1:abc123",
+				"lastAttemptedRevision": "main@sha1:abc123",
+				"conditions": []any{
+					map[string]any{"type": "Ready", "status": "True", "reason": "ReconciliationSucceeded", "message": "Applied revision: main@sha1:abc123", "lastTransitionTime": "2025-01-01T00:00:00Z"},
+				},
+				"inventory": map[string]any{"entries": []any{
+					map[string]any{"id": "shop_web_apps_Deployment", "v": "v1"},
+					map[string]any{"id": "shop_settings__ConfigMap", "v": "v1"},
+				}},
+			},
+		}})
+		expected := &App{
+			Kind: KindKustomization, Namespace: "flux-system", Name: "shop",
+			Source: "GitRepository/flux-system/repo path ./apps/shop", Revision: "main@sha1:abc123",
+			SyncStatus: "Synced", Health: "Healthy", Message: "ReconciliationSucceeded: Applied revision: main@sha1:abc123",
+			LastSync: "2025-01-01T00:00:00Z", TargetNamespace: "shop",
+			Resources: []Resource{
+				{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "shop", Name: "web"},
+				{Version: "v1", Kind: "ConfigMap", Namespace: "shop", Name: "settings"},
+			},
+		}
+		if !reflect.DeepEqual(app, expected) {
+			t.Errorf("expected %+v, got %+v", expected, app)
+		}
+	})
+}
+
+func TestDesiredDiff(t *testing.T) {
+	t.Run("compares the desired fields only", func(t *testing.T) {
+		desired := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1", "kind": "Deployment",
+			"metadata": map[string]any{"name": "web", "labels": map[string]any{"app.kubernetes.io/name": "web"}},
+			"spec":     map[string]any{"replicas": int64(2), "paused": false},
+		}}
+		live := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1", "kind": "Deployment",
+			"metadata": map[string]any{"name": "web", "namespace": "shop", "resourceVersion": "5", "labels": map[string]any{"app.kubernetes.io/name": "shop"}},
+			"spec":     map[string]any{"replicas": int64(3), "revisionHistoryLimit": int64(10)},
+			"status":   map[string]any{"replicas": int64(3)},
+		}}
+		expected := []FieldDiff{
+			{Path: `metadata.labels["app.kubernetes.io/name"]`, Desired: "web", Live: "shop"},
+			{Path: "spec.paused", Desired: false},
+			{Path: "spec.replicas", Desired: float64(2), Live: float64(3)},
+		}
+		if drift := DesiredDiff(desired, live); !reflect.DeepEqual(drift.Fields, expected) {
+			t.Errorf("expected %+v, got %+v", expected, drift.Fields)
+		}
+	})
+	t.Run("redacts the values of the Secrets", func(t *testing.T) {
+		desired := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1", "kind": "Secret", "metadata": map[string]any{"name": "db"},
+			"data": map[string]any{"password": "czNjcjN0"}, "stringData": map[string]any{"user": "admin"},
+		}}
+		live := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1", "kind": "Secret", "metadata": map[string]any{"name": "db"},
+			"data": map[string]any{"password": "aHVudGVyMg==", "user": "YWRtaW4="},
+		}}
+		expected := []FieldDiff{{Path: "data.password", Desired: output.Redacted, Live: output.Redacted}}
+		if drift := DesiredDiff(desired, live); !reflect.DeepEqual(drift.Fields, expected) {
+			t.Errorf("expected %+v, got %+v", expected, drift.Fields)
+		}
+	})
+}
+
+func TestOutOfBandChanges(t *testing.T) {
+	applied := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	before := metav1.NewTime(applied.Add(-time.Hour))
+	after := metav1.NewTime(applied.Add(time.Hour))
+	live := func(entries ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{}}
+		obj.SetManagedFields(entries)
+		return obj
+	}
+	t.Run("reports the changes of other managers after the last apply", func(t *testing.T) {
+		drift := OutOfBandChanges(KindApplication, live(
+			metav1.ManagedFieldsEntry{Manager: "argocd-controller", Operation: metav1.ManagedFieldsOperationApply, Time: &applied},
+			metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &after},
+			metav1.ManagedFieldsEntry{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Time: &after, Subresource: "status"},
+		))
+		if drift == nil {
+			t.Fatal("expected drift, got nil")
+		}
+		expected := "changed by kubectl-edit (Update at 2025-01-01T11:00:00Z) after the last apply of the controller at 2025-01-01T10:00:00Z"
+		if drift.Drift != DriftModified || drift.Reason != expected {
+			t.Errorf("expected %s drift %q, got %s drift %q", DriftModified, expected, drift.Drift, drift.Reason)
+		}
+	})
+	t.Run("ignores the changes made before the last apply", func(t *testing.T) {
+		drift := OutOfBandChanges(KindKustomization, live(
+			metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &before},
+			metav1.ManagedFieldsEntry{Manager: "kustomize-controller", Operation: metav1.ManagedFieldsOperationApply, Time: &applied},
+		))
+		if drift != nil {
+			t.Errorf("expected no drift, got %+v", drift)
+		}
+	})
+	t.Run("ignores the objects not applied by the controller", func(t *testing.T) {
+		drift := OutOfBandChanges(KindKustomization, live(
+			metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &after},
+		))
+		if drift != nil {
+			t.Errorf("expected no drift, got %+v", drift)
+		}
+	})
+}
+
+func TestGVR(t *testing.T) {
+	if _, err := GVR("HelmRelease"); err == nil || !strings.HasPrefix(err.Error(), "unsupported GitOps kind HelmRelease") {
+		t.Errorf("expected unsupported kind error, got %v", err)
+	}
+}
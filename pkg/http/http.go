@@ -19,6 +19,7 @@ import (
 
 const (
 	healthEndpoint     = "/healthz"
+	readyEndpoint      = "/readyz"
 	mcpEndpoint        = "/mcp"
 	sseEndpoint        = "/sse"
 	sseMessageEndpoint = "/message"
@@ -44,13 +45,25 @@ func Serve(ctx context.Context, mcpServer *mcp.Server, staticConfig *config.Stat
 	mux.HandleFunc(healthEndpoint, func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
+	// the server is ready once the API server of the default target can be reached (e.g. Deployment readiness probe),
+	// the endpoint is not authenticated so the cause of the failure is only logged
+	mux.HandleFunc(readyEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		if err := mcpServer.Ready(ctx); err != nil {
+			klog.V(1).Infof("Readiness check failed: %v", err)
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
 	mux.Handle("/.well-known/", WellKnownHandler(staticConfig, httpClient))
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	serverErr := make(chan error, 1)
 	go func() {
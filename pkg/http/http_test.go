@@ -254,6 +254,56 @@ func TestHealthCheck(t *testing.T) {
 	})
 }
 
+func TestReadyCheck(t *testing.T) {
+	testCase(t, func(ctx *httpContext) {
+		t.Run("Exposes readiness check endpoint at /readyz", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("http://%s/readyz", ctx.HttpAddress))
+			if err != nil {
+				t.Fatalf("Failed to get readiness check endpoint: %v", err)
+			}
+			t.Cleanup(func() { _ = resp.Body.Close() })
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected HTTP 200 OK, got %d", resp.StatusCode)
+			}
+		})
+		t.Run("Readiness check with API server not ready returns HTTP 503 Service Unavailable", func(t *testing.T) {
+			ctx.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path == "/readyz" {
+					http.Error(w, "[-]etcd failed: reason withheld", http.StatusInternalServerError)
+				}
+			}))
+			resp, err := http.Get(fmt.Sprintf("http://%s/readyz", ctx.HttpAddress))
+			if err != nil {
+				t.Fatalf("Failed to get readiness check endpoint: %v", err)
+			}
+			t.Cleanup(func() { _ = resp.Body.Close() })
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("Expected HTTP 503 Service Unavailable, got %d", resp.StatusCode)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if strings.TrimSpace(string(body)) != "not ready" {
+				t.Errorf("Expected generic readiness error in body, got %s", string(body))
+			}
+			if strings.Contains(string(body), "etcd") {
+				t.Errorf("Expected the readiness error of the API server to be withheld, got %s", string(body))
+			}
+		})
+	})
+	// Readiness exposed even when require Authorization
+	testCaseWithContext(t, &httpContext{StaticConfig: &config.StaticConfig{RequireOAuth: true, ValidateToken: true, ClusterProviderStrategy: config.ClusterProviderKubeConfig}}, func(ctx *httpContext) {
+		resp, err := http.Get(fmt.Sprintf("http://%s/readyz", ctx.HttpAddress))
+		if err != nil {
+			t.Fatalf("Failed to get readiness check endpoint with OAuth: %v", err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		t.Run("Readiness check with OAuth returns HTTP 200 OK", func(t *testing.T) {
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected HTTP 200 OK, got %d", resp.StatusCode)
+			}
+		})
+	})
+}
+
 func TestWellKnownReverseProxy(t *testing.T) {
 	cases := []string{
 		".well-known/oauth-authorization-server",
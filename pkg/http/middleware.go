@@ -11,7 +11,7 @@ import (
 
 func RequestMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/healthz" {
+		if r.URL.Path == healthEndpoint || r.URL.Path == readyEndpoint {
 			next.ServeHTTP(w, r)
 			return
 		}
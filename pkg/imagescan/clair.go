@@ -0,0 +1,80 @@
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// clair queries the vulnerability reports of the Clair v4 matcher API.
+// Clair only reports the manifests it indexed (e.g. pushed to a Quay registry with Clair enabled), the images are
+// identified by the digest of their manifest.
+// https://quay.github.io/clair/reference/api.html
+type clair struct {
+	*client
+}
+
+var _ Scanner = (*clair)(nil)
+
+// clairVulnerabilityReport is the subset of the Clair v4 vulnerability report used by the scanner
+type clairVulnerabilityReport struct {
+	Packages map[string]struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"packages"`
+	Vulnerabilities map[string]struct {
+		Name               string `json:"name"`
+		Description        string `json:"description"`
+		NormalizedSeverity string `json:"normalized_severity"`
+		FixedInVersion     string `json:"fixed_in_version"`
+	} `json:"vulnerabilities"`
+	// PackageVulnerabilities are the IDs of the vulnerabilities of each package ID
+	PackageVulnerabilities map[string][]string `json:"package_vulnerabilities"`
+}
+
+func (c *clair) Name() string {
+	return config.ImageScannerClair
+}
+
+func (c *clair) Scan(ctx context.Context, image, digest string) ([]Vulnerability, error) {
+	if digest == "" {
+		return nil, fmt.Errorf("clair identifies the images by digest, the digest of %s is unknown (image not pulled yet)", image)
+	}
+	status, body, err := c.get(ctx, c.url+"/matcher/api/v1/vulnerability_report/"+digest)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case status == http.StatusNotFound:
+		return nil, fmt.Errorf("manifest %s of %s is not indexed by clair", digest, image)
+	case status < 200 || status >= 300:
+		return nil, statusError(c.Name(), status, body)
+	}
+	report := &clairVulnerabilityReport{}
+	if err = json.Unmarshal(body, report); err != nil {
+		return nil, fmt.Errorf("failed to parse clair vulnerability report: %w", err)
+	}
+	vulnerabilities := []Vulnerability{}
+	for packageID, vulnerabilityIDs := range report.PackageVulnerabilities {
+		pkg := report.Packages[packageID]
+		for _, vulnerabilityID := range vulnerabilityIDs {
+			vulnerability, ok := report.Vulnerabilities[vulnerabilityID]
+			if !ok {
+				continue
+			}
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:               vulnerability.Name,
+				Severity:         NormalizeSeverity(vulnerability.NormalizedSeverity),
+				Package:          pkg.Name,
+				InstalledVersion: pkg.Version,
+				FixedVersion:     vulnerability.FixedInVersion,
+				Title:            firstLine(vulnerability.Description),
+			})
+		}
+	}
+	SortVulnerabilities(vulnerabilities)
+	return vulnerabilities, nil
+}
@@ -0,0 +1,179 @@
+// Package imagescan queries vulnerability scanners (Clair, Trivy) for the known vulnerabilities (CVEs) of container images.
+package imagescan
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// Normalized severities of the vulnerabilities, from the most to the least severe
+const (
+	SeverityCritical   = "critical"
+	SeverityHigh       = "high"
+	SeverityMedium     = "medium"
+	SeverityLow        = "low"
+	SeverityNegligible = "negligible"
+	SeverityUnknown    = "unknown"
+)
+
+// Severities are the normalized severities, from the most to the least severe
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityNegligible, SeverityUnknown}
+
+// Vulnerability is a known vulnerability of a package of an image
+type Vulnerability struct {
+	// ID is the identifier of the vulnerability, e.g. CVE-2024-6387
+	ID string `json:"id"`
+	// Severity is one of Severities
+	Severity         string `json:"severity"`
+	Package          string `json:"package"`
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	// FixedVersion is the version of the package that fixes the vulnerability, empty if there is no fix
+	FixedVersion string `json:"fixedVersion,omitempty"`
+	Title        string `json:"title,omitempty"`
+}
+
+// Scanner returns the known vulnerabilities of an image
+type Scanner interface {
+	// Name is the type of the scanner (clair or trivy)
+	Name() string
+	// Scan returns the vulnerabilities of the image, identified by its reference and the digest of its manifest
+	// (empty if the image is not pulled yet), sorted by severity
+	Scan(ctx context.Context, image, digest string) ([]Vulnerability, error)
+}
+
+// NewScanner returns the Scanner of the image_scanner configuration, nil if no scanner is configured
+func NewScanner(cfg *config.StaticConfig) Scanner {
+	if cfg == nil || cfg.ImageScanner.Url == "" {
+		return nil
+	}
+	c := &client{
+		url:                  strings.TrimSuffix(cfg.ImageScanner.Url, "/"),
+		bearerToken:          cfg.ImageScanner.BearerToken,
+		insecure:             cfg.ImageScanner.Insecure,
+		certificateAuthority: cfg.ImageScanner.CertificateAuthority,
+	}
+	switch cfg.ImageScanner.Type {
+	case config.ImageScannerClair:
+		return &clair{client: c}
+	case config.ImageScannerTrivy:
+		return &trivy{client: c}
+	}
+	return nil
+}
+
+// NormalizeSeverity returns the normalized severity of a severity reported by a scanner, e.g. CRITICAL or Moderate
+func NormalizeSeverity(severity string) string {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	switch severity {
+	case "important":
+		return SeverityHigh
+	case "moderate":
+		return SeverityMedium
+	}
+	if slices.Contains(Severities, severity) {
+		return severity
+	}
+	return SeverityUnknown
+}
+
+// SortVulnerabilities sorts the vulnerabilities by severity (most severe first), then by ID and package
+func SortVulnerabilities(vulnerabilities []Vulnerability) {
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		vi, vj := vulnerabilities[i], vulnerabilities[j]
+		if ri, rj := slices.Index(Severities, vi.Severity), slices.Index(Severities, vj.Severity); ri != rj {
+			return ri < rj
+		}
+		if vi.ID != vj.ID {
+			return vi.ID < vj.ID
+		}
+		return vi.Package < vj.Package
+	})
+}
+
+type client struct {
+	url                  string
+	bearerToken          string
+	insecure             bool
+	certificateAuthority string
+}
+
+// get performs a GET request to the scanner and returns the status code and the body of the response
+func (c *client) get(ctx context.Context, apiURL string) (int, []byte, error) {
+	klog.V(3).Infof("image scanner API call: GET %s", apiURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := strings.TrimSpace(c.bearerToken); token != "" {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimPrefix(token, "Bearer "))
+	}
+	resp, err := c.createHTTPClient().Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+func (c *client) createHTTPClient() *http.Client {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.insecure,
+	}
+	if caValue := strings.TrimSpace(c.certificateAuthority); caValue != "" {
+		caPEM, err := os.ReadFile(caValue)
+		if err != nil {
+			klog.Errorf("failed to read CA certificate from file %s: %v; proceeding without custom CA", caValue, err)
+		} else {
+			certPool, err := x509.SystemCertPool()
+			if err != nil || certPool == nil {
+				certPool = x509.NewCertPool()
+			}
+			if certPool.AppendCertsFromPEM(caPEM) {
+				tlsConfig.RootCAs = certPool
+			} else {
+				klog.Errorf("failed to append certificate authority %s; proceeding without custom CA", caValue)
+			}
+		}
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+}
+
+// firstLine returns the first line of a description, truncated to 120 characters
+func firstLine(description string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	if len(line) > 120 {
+		line = line[:117] + "..."
+	}
+	return line
+}
+
+// statusError returns the error of an unexpected status code of the scanner response
+func statusError(scanner string, status int, body []byte) error {
+	message := strings.TrimSpace(string(body))
+	if len(message) > 200 {
+		message = message[:200] + "..."
+	}
+	return fmt.Errorf("%s API error: status %d: %s", scanner, status, message)
+}
@@ -0,0 +1,132 @@
+package imagescan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+const digest = "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+type ImageScanSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	requests []*http.Request
+	response string
+	status   int
+}
+
+func (s *ImageScanSuite) SetupTest() {
+	s.requests = nil
+	s.status = http.StatusOK
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.requests = append(s.requests, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(s.response))
+	}))
+}
+
+func (s *ImageScanSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ImageScanSuite) scanner(scannerType string) Scanner {
+	return NewScanner(&config.StaticConfig{ImageScanner: config.ImageScanner{Type: scannerType, Url: s.server.URL + "/", BearerToken: "token"}})
+}
+
+func (s *ImageScanSuite) TestNewScanner() {
+	s.Nil(NewScanner(&config.StaticConfig{}), "no scanner is configured")
+	s.Equal("clair", s.scanner(config.ImageScannerClair).Name())
+	s.Equal("trivy", s.scanner(config.ImageScannerTrivy).Name())
+}
+
+func (s *ImageScanSuite) TestClair() {
+	s.response = `{
+		"manifest_hash": "` + digest + `",
+		"packages": {"1": {"name": "openssl", "version": "3.0.2"}, "2": {"name": "zlib", "version": "1.2.11"}},
+		"vulnerabilities": {
+			"10": {"name": "CVE-2024-0001", "description": "openssl: buffer overflow\nmore details", "normalized_severity": "High", "fixed_in_version": "3.0.3"},
+			"11": {"name": "CVE-2024-0002", "normalized_severity": "Critical"},
+			"12": {"name": "CVE-2024-0003", "normalized_severity": "Low"}
+		},
+		"package_vulnerabilities": {"1": ["10", "12"], "2": ["11"]}
+	}`
+	vulnerabilities, err := s.scanner(config.ImageScannerClair).Scan(s.T().Context(), "quay.io/app/web:1.0", digest)
+	s.Require().NoError(err)
+	s.Run("queries the vulnerability report of the manifest", func() {
+		s.Require().Len(s.requests, 1)
+		s.Equal("/matcher/api/v1/vulnerability_report/"+digest, s.requests[0].URL.Path)
+		s.Equal("Bearer token", s.requests[0].Header.Get("Authorization"))
+	})
+	s.Run("sorts the vulnerabilities by severity", func() {
+		s.Equal([]Vulnerability{
+			{ID: "CVE-2024-0002", Severity: SeverityCritical, Package: "zlib", InstalledVersion: "1.2.11"},
+			{ID: "CVE-2024-0001", Severity: SeverityHigh, Package: "openssl", InstalledVersion: "3.0.2", FixedVersion: "3.0.3", Title: "openssl: buffer overflow"},
+			{ID: "CVE-2024-0003", Severity: SeverityLow, Package: "openssl", InstalledVersion: "3.0.2"},
+		}, vulnerabilities)
+	})
+	s.Run("manifest not indexed", func() {
+		s.status = http.StatusNotFound
+		_, err := s.scanner(config.ImageScannerClair).Scan(s.T().Context(), "quay.io/app/web:1.0", digest)
+		s.EqualError(err, "manifest "+digest+" of quay.io/app/web:1.0 is not indexed by clair")
+	})
+	s.Run("unknown digest", func() {
+		_, err := s.scanner(config.ImageScannerClair).Scan(s.T().Context(), "quay.io/app/web:1.0", "")
+		s.EqualError(err, "clair identifies the images by digest, the digest of quay.io/app/web:1.0 is unknown (image not pulled yet)")
+	})
+}
+
+func (s *ImageScanSuite) TestTrivy() {
+	s.response = `{
+		"ArtifactName": "docker.io/library/nginx@` + digest + `",
+		"Results": [
+			{"Target": "nginx (debian 12.5)", "Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2024-0010", "PkgName": "libc6", "InstalledVersion": "2.36-9", "Severity": "MEDIUM", "Title": "glibc: issue"},
+				{"VulnerabilityID": "CVE-2024-0011", "PkgName": "curl", "InstalledVersion": "7.88.1", "FixedVersion": "7.88.2", "Severity": "CRITICAL"}
+			]},
+			{"Target": "usr/local/bin/app", "Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2024-0010", "PkgName": "libc6", "InstalledVersion": "2.36-9", "Severity": "MEDIUM"}
+			]}
+		]
+	}`
+	vulnerabilities, err := s.scanner(config.ImageScannerTrivy).Scan(s.T().Context(), "docker.io/library/nginx:1.27", digest)
+	s.Require().NoError(err)
+	s.Run("scans the running image digest", func() {
+		s.Require().Len(s.requests, 1)
+		s.Equal("docker.io/library/nginx@"+digest, s.requests[0].URL.Query().Get("image"))
+	})
+	s.Run("deduplicates and sorts the vulnerabilities by severity", func() {
+		s.Equal([]Vulnerability{
+			{ID: "CVE-2024-0011", Severity: SeverityCritical, Package: "curl", InstalledVersion: "7.88.1", FixedVersion: "7.88.2"},
+			{ID: "CVE-2024-0010", Severity: SeverityMedium, Package: "libc6", InstalledVersion: "2.36-9", Title: "glibc: issue"},
+		}, vulnerabilities)
+	})
+	s.Run("scans the image reference without digest", func() {
+		s.requests = nil
+		_, err := s.scanner(config.ImageScannerTrivy).Scan(s.T().Context(), "registry:5000/app:1.0", "")
+		s.Require().NoError(err)
+		s.Equal("registry:5000/app:1.0", s.requests[0].URL.Query().Get("image"))
+	})
+	s.Run("scanner error", func() {
+		s.status = http.StatusInternalServerError
+		s.response = "scan failed"
+		_, err := s.scanner(config.ImageScannerTrivy).Scan(s.T().Context(), "docker.io/library/nginx:1.27", "")
+		s.EqualError(err, "trivy API error: status 500: scan failed")
+	})
+}
+
+func (s *ImageScanSuite) TestNormalizeSeverity() {
+	s.Equal(SeverityCritical, NormalizeSeverity("CRITICAL"))
+	s.Equal(SeverityHigh, NormalizeSeverity("Important"))
+	s.Equal(SeverityMedium, NormalizeSeverity("Moderate"))
+	s.Equal(SeverityUnknown, NormalizeSeverity(""))
+}
+
+func TestImageScan(t *testing.T) {
+	suite.Run(t, new(ImageScanSuite))
+}
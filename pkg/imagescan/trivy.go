@@ -0,0 +1,91 @@
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// trivy queries a scan service returning the Trivy JSON report (trivy image --format json) of the image provided in
+// the image query parameter, e.g. a wrapper of the Trivy CLI in client mode.
+// The Trivy server API is not queried directly, its clients analyze the image layers locally before the scan.
+type trivy struct {
+	*client
+}
+
+var _ Scanner = (*trivy)(nil)
+
+// trivyReport is the subset of the Trivy JSON report used by the scanner
+// https://trivy.dev/latest/docs/configuration/reporting/#json
+type trivyReport struct {
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+func (t *trivy) Name() string {
+	return config.ImageScannerTrivy
+}
+
+func (t *trivy) Scan(ctx context.Context, image, digest string) ([]Vulnerability, error) {
+	ref := image
+	if digest != "" {
+		// Scan the running image rather than the current image of a mutable tag
+		repository, _, _ := strings.Cut(image, "@")
+		if slash, colon := strings.LastIndex(repository, "/"), strings.LastIndex(repository, ":"); colon > slash {
+			repository = repository[:colon]
+		}
+		ref = repository + "@" + digest
+	}
+	apiURL, err := url.Parse(t.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trivy url: %w", err)
+	}
+	query := apiURL.Query()
+	query.Set("image", ref)
+	apiURL.RawQuery = query.Encode()
+	status, body, err := t.get(ctx, apiURL.String())
+	if err != nil {
+		return nil, err
+	}
+	if status < 200 || status >= 300 {
+		return nil, statusError(t.Name(), status, body)
+	}
+	report := &trivyReport{}
+	if err = json.Unmarshal(body, report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+	vulnerabilities := []Vulnerability{}
+	seen := map[string]bool{}
+	for _, result := range report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			key := vulnerability.VulnerabilityID + "/" + vulnerability.PkgName + "/" + vulnerability.InstalledVersion
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:               vulnerability.VulnerabilityID,
+				Severity:         NormalizeSeverity(vulnerability.Severity),
+				Package:          vulnerability.PkgName,
+				InstalledVersion: vulnerability.InstalledVersion,
+				FixedVersion:     vulnerability.FixedVersion,
+				Title:            vulnerability.Title,
+			})
+		}
+	}
+	SortVulnerabilities(vulnerabilities)
+	return vulnerabilities, nil
+}
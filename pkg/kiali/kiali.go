@@ -25,7 +25,7 @@ type Kiali struct {
 
 // NewKiali creates a new Kiali instance
 func NewKiali(config *config.StaticConfig, kubernetes *rest.Config) *Kiali {
-	kiali := &Kiali{bearerToken: kubernetes.BearerToken}
+	kiali := &Kiali{bearerToken: bearerToken(kubernetes)}
 	if cfg, ok := config.GetToolsetConfig("kiali"); ok {
 		if kc, ok := cfg.(*Config); ok && kc != nil {
 			kiali.kialiURL = kc.Url
@@ -36,6 +36,38 @@ func NewKiali(config *config.StaticConfig, kubernetes *rest.Config) *Kiali {
 	return kiali
 }
 
+// bearerToken returns the bearer token the Kubernetes client currently authenticates with.
+// The token is resolved through the client-go authentication wrappers so that rotated token files,
+// exec credential plugins and auth providers (e.g. OIDC) provide a fresh token instead of the one loaded on startup.
+func bearerToken(kubernetes *rest.Config) string {
+	capture := &authorizationCapture{}
+	rt, err := rest.HTTPWrappersForConfig(kubernetes, capture)
+	if err == nil {
+		var req *http.Request
+		if req, err = http.NewRequest(http.MethodGet, kubernetes.Host, nil); err == nil {
+			_, err = rt.RoundTrip(req)
+		}
+	}
+	if err != nil {
+		klog.V(1).Infof("failed to resolve the Kubernetes bearer token, using the configured one: %v", err)
+		return kubernetes.BearerToken
+	}
+	if capture.authorization == "" {
+		return kubernetes.BearerToken
+	}
+	return strings.TrimPrefix(capture.authorization, "Bearer ")
+}
+
+// authorizationCapture is a http.RoundTripper that records the Authorization header instead of sending the request
+type authorizationCapture struct {
+	authorization string
+}
+
+func (c *authorizationCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.authorization = req.Header.Get("Authorization")
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
 // validateAndGetURL validates the Kiali client configuration and returns the full URL
 // by safely concatenating the base URL with the provided endpoint, avoiding duplicate
 // or missing slashes regardless of trailing/leading slashes.
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/containers/kubernetes-mcp-server/internal/test"
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
 	"github.com/stretchr/testify/suite"
+	"k8s.io/client-go/rest"
 )
 
 type KialiSuite struct {
@@ -173,6 +176,34 @@ func (s *KialiSuite) TestValidateAndGetURL() {
 	})
 }
 
+func (s *KialiSuite) TestNewKiali_RefreshesBearerToken() {
+	s.Run("reads the rotated token file", func() {
+		tokenFile := filepath.Join(s.T().TempDir(), "token")
+		s.Require().NoError(os.WriteFile(tokenFile, []byte("token-1"), 0600))
+		s.MockServer.Config().BearerTokenFile = tokenFile
+		s.Equal("token-1", NewKiali(s.Config, s.MockServer.Config()).bearerToken)
+		s.Require().NoError(os.WriteFile(tokenFile, []byte("token-2"), 0600))
+		s.Equal("token-2", NewKiali(s.Config, s.MockServer.Config()).bearerToken)
+	})
+	s.Run("uses the token provided by the auth wrappers", func() {
+		cfg := rest.CopyConfig(s.MockServer.Config())
+		cfg.BearerTokenFile = ""
+		cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("Authorization", "Bearer exec-token")
+				return rt.RoundTrip(req)
+			})
+		})
+		s.Equal("exec-token", NewKiali(s.Config, cfg).bearerToken)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 // CurrentAuthorizationHeader behavior is now implicit via executeRequest using Manager.BearerToken
 
 func (s *KialiSuite) TestExecuteRequest() {
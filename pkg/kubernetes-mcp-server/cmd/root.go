@@ -7,13 +7,18 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -26,6 +31,7 @@ import (
 	internalhttp "github.com/containers/kubernetes-mcp-server/pkg/http"
 	"github.com/containers/kubernetes-mcp-server/pkg/mcp"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/plugin"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
 	"github.com/containers/kubernetes-mcp-server/pkg/version"
 )
@@ -56,6 +62,7 @@ kubernetes-mcp-server --port 8080 --disable-multi-cluster
 const (
 	flagVersion              = "version"
 	flagLogLevel             = "log-level"
+	flagLogFormat            = "log-format"
 	flagConfig               = "config"
 	flagPort                 = "port"
 	flagSSEBaseUrl           = "sse-base-url"
@@ -64,6 +71,9 @@ const (
 	flagListOutput           = "list-output"
 	flagReadOnly             = "read-only"
 	flagDisableDestructive   = "disable-destructive"
+	flagToolProfile          = "tool-profile"
+	flagDryRun               = "dry-run"
+	flagRequireConfirmation  = "require-confirmation"
 	flagRequireOAuth         = "require-oauth"
 	flagOAuthAudience        = "oauth-audience"
 	flagValidateToken        = "validate-token"
@@ -76,6 +86,7 @@ const (
 type MCPServerOptions struct {
 	Version              bool
 	LogLevel             int
+	LogFormat            string
 	Port                 string
 	SSEBaseUrl           string
 	Kubeconfig           string
@@ -83,6 +94,9 @@ type MCPServerOptions struct {
 	ListOutput           string
 	ReadOnly             bool
 	DisableDestructive   bool
+	ToolProfile          string
+	DryRun               bool
+	RequireConfirmation  bool
 	RequireOAuth         bool
 	OAuthAudience        string
 	ValidateToken        bool
@@ -95,6 +109,8 @@ type MCPServerOptions struct {
 	StaticConfig *config.StaticConfig
 
 	genericiooptions.IOStreams
+
+	cmd *cobra.Command
 }
 
 func NewMCPServerOptions(streams genericiooptions.IOStreams) *MCPServerOptions {
@@ -128,6 +144,7 @@ func NewMCPServer(streams genericiooptions.IOStreams) *cobra.Command {
 
 	cmd.Flags().BoolVar(&o.Version, flagVersion, o.Version, "Print version information and quit")
 	cmd.Flags().IntVar(&o.LogLevel, flagLogLevel, o.LogLevel, "Set the log level (from 0 to 9)")
+	cmd.Flags().StringVar(&o.LogFormat, flagLogFormat, o.LogFormat, "Set the log format (one of: "+strings.Join(config.LogFormats, ", ")+"). Defaults to "+config.LogFormatText+".")
 	cmd.Flags().StringVar(&o.ConfigPath, flagConfig, o.ConfigPath, "Path of the config file.")
 	cmd.Flags().StringVar(&o.Port, flagPort, o.Port, "Start a streamable HTTP and SSE HTTP server on the specified port (e.g. 8080)")
 	cmd.Flags().StringVar(&o.SSEBaseUrl, flagSSEBaseUrl, o.SSEBaseUrl, "SSE public base URL to use when sending the endpoint message (e.g. https://example.com)")
@@ -136,6 +153,9 @@ func NewMCPServer(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVar(&o.ListOutput, flagListOutput, o.ListOutput, "Output format for resource list operations (one of: "+strings.Join(output.Names, ", ")+"). Defaults to "+o.StaticConfig.ListOutput+".")
 	cmd.Flags().BoolVar(&o.ReadOnly, flagReadOnly, o.ReadOnly, "If true, only tools annotated with readOnlyHint=true are exposed")
 	cmd.Flags().BoolVar(&o.DisableDestructive, flagDisableDestructive, o.DisableDestructive, "If true, tools annotated with destructiveHint=true are disabled")
+	cmd.Flags().StringVar(&o.ToolProfile, flagToolProfile, o.ToolProfile, "Profile that controls which tools are exposed (one of: "+strings.Join(config.ToolProfiles, ", ")+"). read-only exposes only tools annotated with readOnlyHint=true, operator disables tools annotated with destructiveHint=true. Defaults to admin (all tools).")
+	cmd.Flags().BoolVar(&o.DryRun, flagDryRun, o.DryRun, "If true, mutating tools run in plan only mode: changes are validated with server-side dry-run where supported and never persisted")
+	cmd.Flags().BoolVar(&o.RequireConfirmation, flagRequireConfirmation, o.RequireConfirmation, "If true, tools annotated with destructiveHint=true return a confirmation token instead of performing their action, the action is performed by the confirm_action tool")
 	cmd.Flags().BoolVar(&o.RequireOAuth, flagRequireOAuth, o.RequireOAuth, "If true, requires OAuth authorization as defined in the Model Context Protocol (MCP) specification. This flag is ignored if transport type is stdio")
 	_ = cmd.Flags().MarkHidden(flagRequireOAuth)
 	cmd.Flags().StringVar(&o.OAuthAudience, flagOAuthAudience, o.OAuthAudience, "OAuth audience for token claims validation. Optional. If not set, the audience is not validated. Only valid if require-oauth is enabled.")
@@ -162,10 +182,17 @@ func (m *MCPServerOptions) Complete(cmd *cobra.Command) error {
 		m.StaticConfig = cnf
 	}
 
-	m.loadFlags(cmd)
+	m.loadFlags(cmd, m.StaticConfig)
+	m.cmd = cmd
 
 	m.initializeLogging()
 
+	if m.StaticConfig.PluginsDir != "" {
+		if err := plugin.Register(context.Background(), toolsets.DefaultRegistry, m.StaticConfig.PluginsDir, m.StaticConfig); err != nil {
+			return err
+		}
+	}
+
 	if m.StaticConfig.RequireOAuth && m.StaticConfig.Port == "" {
 		// RequireOAuth is not relevant flow for STDIO transport
 		m.StaticConfig.RequireOAuth = false
@@ -174,51 +201,63 @@ func (m *MCPServerOptions) Complete(cmd *cobra.Command) error {
 	return nil
 }
 
-func (m *MCPServerOptions) loadFlags(cmd *cobra.Command) {
+func (m *MCPServerOptions) loadFlags(cmd *cobra.Command, cfg *config.StaticConfig) {
 	if cmd.Flag(flagLogLevel).Changed {
-		m.StaticConfig.LogLevel = m.LogLevel
+		cfg.LogLevel = m.LogLevel
+	}
+	if cmd.Flag(flagLogFormat).Changed {
+		cfg.LogFormat = m.LogFormat
 	}
 	if cmd.Flag(flagPort).Changed {
-		m.StaticConfig.Port = m.Port
+		cfg.Port = m.Port
 	}
 	if cmd.Flag(flagSSEBaseUrl).Changed {
-		m.StaticConfig.SSEBaseURL = m.SSEBaseUrl
+		cfg.SSEBaseURL = m.SSEBaseUrl
 	}
 	if cmd.Flag(flagKubeconfig).Changed {
-		m.StaticConfig.KubeConfig = m.Kubeconfig
+		cfg.KubeConfig = m.Kubeconfig
 	}
 	if cmd.Flag(flagListOutput).Changed {
-		m.StaticConfig.ListOutput = m.ListOutput
+		cfg.ListOutput = m.ListOutput
 	}
 	if cmd.Flag(flagReadOnly).Changed {
-		m.StaticConfig.ReadOnly = m.ReadOnly
+		cfg.ReadOnly = m.ReadOnly
 	}
 	if cmd.Flag(flagDisableDestructive).Changed {
-		m.StaticConfig.DisableDestructive = m.DisableDestructive
+		cfg.DisableDestructive = m.DisableDestructive
+	}
+	if cmd.Flag(flagToolProfile).Changed {
+		cfg.ToolProfile = m.ToolProfile
+	}
+	if cmd.Flag(flagDryRun).Changed {
+		cfg.DryRun = m.DryRun
+	}
+	if cmd.Flag(flagRequireConfirmation).Changed {
+		cfg.RequireConfirmation = m.RequireConfirmation
 	}
 	if cmd.Flag(flagToolsets).Changed {
-		m.StaticConfig.Toolsets = m.Toolsets
+		cfg.Toolsets = m.Toolsets
 	}
 	if cmd.Flag(flagRequireOAuth).Changed {
-		m.StaticConfig.RequireOAuth = m.RequireOAuth
+		cfg.RequireOAuth = m.RequireOAuth
 	}
 	if cmd.Flag(flagOAuthAudience).Changed {
-		m.StaticConfig.OAuthAudience = m.OAuthAudience
+		cfg.OAuthAudience = m.OAuthAudience
 	}
 	if cmd.Flag(flagValidateToken).Changed {
-		m.StaticConfig.ValidateToken = m.ValidateToken
+		cfg.ValidateToken = m.ValidateToken
 	}
 	if cmd.Flag(flagAuthorizationURL).Changed {
-		m.StaticConfig.AuthorizationURL = m.AuthorizationURL
+		cfg.AuthorizationURL = m.AuthorizationURL
 	}
 	if cmd.Flag(flagServerUrl).Changed {
-		m.StaticConfig.ServerURL = m.ServerURL
+		cfg.ServerURL = m.ServerURL
 	}
 	if cmd.Flag(flagCertificateAuthority).Changed {
-		m.StaticConfig.CertificateAuthority = m.CertificateAuthority
+		cfg.CertificateAuthority = m.CertificateAuthority
 	}
 	if cmd.Flag(flagDisableMultiCluster).Changed && m.DisableMultiCluster {
-		m.StaticConfig.ClusterProviderStrategy = config.ClusterProviderDisabled
+		cfg.ClusterProviderStrategy = config.ClusterProviderDisabled
 	}
 }
 
@@ -237,16 +276,68 @@ func (m *MCPServerOptions) initializeLogging() {
 		_ = flagSet.Parse([]string{"--v", strconv.Itoa(m.StaticConfig.LogLevel)})
 	}
 	logger := textlogger.NewLogger(textlogger.NewConfig(loggerOptions...))
+	if m.StaticConfig.LogFormat == config.LogFormatJson {
+		// logr maps the verbosity V(n) to the slog level -n
+		logger = logr.FromSlogHandler(slog.NewJSONHandler(m.Out, &slog.HandlerOptions{Level: slog.Level(-max(m.StaticConfig.LogLevel, 0))}))
+	}
 	klog.SetLoggerWithOptions(logger)
 }
 
 func (m *MCPServerOptions) Validate() error {
+	if m.StaticConfig.LogFormat != "" && !slices.Contains(config.LogFormats, m.StaticConfig.LogFormat) {
+		return fmt.Errorf("invalid log format: %s, valid formats are: %s", m.StaticConfig.LogFormat, strings.Join(config.LogFormats, ", "))
+	}
 	if output.FromString(m.StaticConfig.ListOutput) == nil {
 		return fmt.Errorf("invalid output name: %s, valid names are: %s", m.StaticConfig.ListOutput, strings.Join(output.Names, ", "))
 	}
 	if err := toolsets.Validate(m.StaticConfig.Toolsets); err != nil {
 		return err
 	}
+	if m.StaticConfig.ToolProfile != "" && !slices.Contains(config.ToolProfiles, m.StaticConfig.ToolProfile) {
+		return fmt.Errorf("invalid tool profile: %s, valid profiles are: %s", m.StaticConfig.ToolProfile, strings.Join(config.ToolProfiles, ", "))
+	}
+	if err := m.StaticConfig.ValidateToolPatterns(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateBreakGlass(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateImpersonation(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateNodeFiles(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateHelperImages(); err != nil {
+		return err
+	}
+	if _, err := m.StaticConfig.HelperCleanupDuration(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateTimeouts(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateCacheTTLs(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateTargetNamespaces(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateRateLimits(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidatePolicies(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateCost(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateImageScanner(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ValidateArtifacts(); err != nil {
+		return err
+	}
 	if !m.StaticConfig.RequireOAuth && (m.StaticConfig.ValidateToken || m.StaticConfig.OAuthAudience != "" || m.StaticConfig.AuthorizationURL != "" || m.StaticConfig.ServerURL != "" || m.StaticConfig.CertificateAuthority != "") {
 		return fmt.Errorf("validate-token, oauth-audience, authorization-url, server-url and certificate-authority are only valid if require-oauth is enabled. Missing --port may implicitly set require-oauth to false")
 	}
@@ -278,6 +369,18 @@ func (m *MCPServerOptions) Run() error {
 	klog.V(1).Infof(" - ListOutput: %s", m.StaticConfig.ListOutput)
 	klog.V(1).Infof(" - Read-only mode: %t", m.StaticConfig.ReadOnly)
 	klog.V(1).Infof(" - Disable destructive tools: %t", m.StaticConfig.DisableDestructive)
+	if m.StaticConfig.ToolProfile != "" {
+		klog.V(1).Infof(" - Tool profile: %s", m.StaticConfig.ToolProfile)
+	}
+	if m.StaticConfig.DryRun {
+		klog.V(1).Info(" - Dry-run mode: mutating tools don't persist any change")
+	}
+	if m.StaticConfig.RequireConfirmation {
+		klog.V(1).Info(" - Require confirmation: destructive tools must be confirmed with confirm_action")
+	}
+	if m.StaticConfig.BreakGlassMaxDuration != "" {
+		klog.V(1).Infof(" - Break-glass: destructive tools must be enabled with elevate (up to %s)", m.StaticConfig.BreakGlassMaxDuration)
+	}
 
 	strategy := m.StaticConfig.ClusterProviderStrategy
 	if strategy == "" {
@@ -330,6 +433,8 @@ func (m *MCPServerOptions) Run() error {
 		return fmt.Errorf("failed to initialize MCP server: %w", err)
 	}
 	defer mcpServer.Close()
+	stopReload := m.watchReload(mcpServer)
+	defer stopReload()
 
 	if m.StaticConfig.Port != "" {
 		ctx := context.Background()
@@ -343,3 +448,40 @@ func (m *MCPServerOptions) Run() error {
 
 	return nil
 }
+
+// watchReload reloads the config file on SIGHUP and applies the config.ReloadableSettings to the running server.
+// The command line flags keep precedence over the reloaded config file.
+func (m *MCPServerOptions) watchReload(mcpServer *mcp.Server) (stop func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigChan:
+				if m.ConfigPath == "" {
+					klog.Warning("Received SIGHUP, but no config file was provided, nothing to reload")
+					continue
+				}
+				klog.V(0).Infof("Received SIGHUP, reloading config file %s", m.ConfigPath)
+				cnf, err := config.Read(m.ConfigPath)
+				if err != nil {
+					klog.Errorf("Failed to reload config file %s: %v", m.ConfigPath, err)
+					continue
+				}
+				if m.cmd != nil {
+					m.loadFlags(m.cmd, cnf)
+				}
+				if err = mcpServer.ReloadConfiguration(cnf); err != nil {
+					klog.Errorf("Failed to apply reloaded config file %s: %v", m.ConfigPath, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
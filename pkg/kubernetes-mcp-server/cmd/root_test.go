@@ -137,7 +137,7 @@ func TestToolsets(t *testing.T) {
 		rootCmd := NewMCPServer(ioStreams)
 		rootCmd.SetArgs([]string{"--help"})
 		o, err := captureOutput(rootCmd.Execute) // --help doesn't use logger/klog, cobra prints directly to stdout
-		if !strings.Contains(o, "Comma-separated list of MCP toolsets to use (available toolsets: config, core, helm, kiali, kubevirt).") {
+		if !strings.Contains(o, "Comma-separated list of MCP toolsets to use (available toolsets: config, core, diagnostics, gitops, helm, kiali, kubevirt, machines, metrics, network_debug, openshift, rbac, storage).") {
 			t.Fatalf("Expected all available toolsets, got %s %v", o, err)
 		}
 	})
@@ -233,6 +233,81 @@ func TestDisableDestructive(t *testing.T) {
 	})
 }
 
+func TestToolProfile(t *testing.T) {
+	t.Run("available", func(t *testing.T) {
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--help"})
+		o, err := captureOutput(rootCmd.Execute) // --help doesn't use logger/klog, cobra prints directly to stdout
+		if !strings.Contains(o, "Profile that controls which tools are exposed (one of: read-only, operator, admin)") {
+			t.Fatalf("Expected all available tool profiles, got %s %v", o, err)
+		}
+	})
+	t.Run("set with --tool-profile", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1", "--tool-profile", "operator"})
+		_ = rootCmd.Execute()
+		expected := `(?m)\" - Tool profile\: operator\"`
+		if m, err := regexp.MatchString(expected, out.String()); !m || err != nil {
+			t.Fatalf("Expected tool profile to be %s, got %s %v", expected, out.String(), err)
+		}
+	})
+	t.Run("invalid --tool-profile", func(t *testing.T) {
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--tool-profile", "superuser"})
+		err := rootCmd.Execute()
+		if err == nil || err.Error() != "invalid tool profile: superuser, valid profiles are: read-only, operator, admin" {
+			t.Fatalf("Expected invalid tool profile error, got %v", err)
+		}
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	t.Run("defaults to false", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1"})
+		_ = rootCmd.Execute()
+		if strings.Contains(out.String(), " - Dry-run mode") {
+			t.Fatalf("Expected dry-run mode to be disabled, got %s", out.String())
+		}
+	})
+	t.Run("set with --dry-run", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1", "--dry-run"})
+		_ = rootCmd.Execute()
+		expected := `(?m)\" - Dry-run mode\: mutating tools don't persist any change\"`
+		if m, err := regexp.MatchString(expected, out.String()); !m || err != nil {
+			t.Fatalf("Expected dry-run mode to be enabled, got %s %v", out.String(), err)
+		}
+	})
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	t.Run("defaults to false", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1"})
+		_ = rootCmd.Execute()
+		if strings.Contains(out.String(), " - Require confirmation") {
+			t.Fatalf("Expected confirmation not to be required, got %s", out.String())
+		}
+	})
+	t.Run("set with --require-confirmation", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1", "--require-confirmation"})
+		_ = rootCmd.Execute()
+		expected := `(?m)\" - Require confirmation\: destructive tools must be confirmed with confirm_action\"`
+		if m, err := regexp.MatchString(expected, out.String()); !m || err != nil {
+			t.Fatalf("Expected confirmation to be required, got %s %v", out.String(), err)
+		}
+	})
+}
+
 func TestAuthorizationURL(t *testing.T) {
 	t.Run("invalid authorization-url without protocol", func(t *testing.T) {
 		ioStreams, _ := testStream()
@@ -277,6 +352,32 @@ func TestStdioLogging(t *testing.T) {
 	})
 }
 
+func TestLogFormat(t *testing.T) {
+	t.Run("defaults to text", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--log-level=1", "--port=1337"})
+		err := rootCmd.Execute()
+		require.NoErrorf(t, err, "Expected no error executing command, got %v", err)
+		assert.Containsf(t, out.String(), `"Starting kubernetes-mcp-server"`, "Expected text output, got %s", out.String())
+	})
+	t.Run("set with --log-format", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--log-level=1", "--port=1337", "--log-format=json"})
+		err := rootCmd.Execute()
+		require.NoErrorf(t, err, "Expected no error executing command, got %v", err)
+		assert.Containsf(t, out.String(), `"msg":"Starting kubernetes-mcp-server`, "Expected json output, got %s", out.String())
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-format=xml"})
+		err := rootCmd.Execute()
+		assert.EqualErrorf(t, err, "invalid log format: xml, valid formats are: text, json", "Expected invalid log format error, got %v", err)
+	})
+}
+
 func TestDisableMultiCluster(t *testing.T) {
 	t.Run("defaults to false", func(t *testing.T) {
 		ioStreams, out := testStream()
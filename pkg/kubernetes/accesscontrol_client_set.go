@@ -44,14 +44,14 @@ func NewAccessControlClientset(staticConfig *config.StaticConfig, clientCmdConfi
 	}
 	acc.cfg.Wrap(func(original http.RoundTripper) http.RoundTripper {
 		return &AccessControlRoundTripper{
-			delegate:     original,
+			delegate:     &throttlingRoundTripper{delegate: original},
 			staticConfig: staticConfig,
 			restMapper:   acc.restMapper,
 		}
 	})
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(acc.cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %v", err)
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
 	}
 	acc.discoveryClient = memory.NewMemCacheClient(discoveryClient)
 	acc.restMapper = restmapper.NewDeferredDiscoveryRESTMapper(acc.discoveryClient)
@@ -86,6 +86,12 @@ func (a *AccessControlClientset) MetricsV1beta1Client() *metricsv1beta1.MetricsV
 	return a.metricsV1beta1
 }
 
+// IsNamespaceAllowed returns an error if the server is restricted to the target namespaces of the configuration
+// (target_namespaces) and the namespace isn't one of them, an empty namespace refers to all the namespaces
+func (a *AccessControlClientset) IsNamespaceAllowed(namespace string) error {
+	return isTargetNamespace(a.staticConfig, namespace)
+}
+
 // Nodes returns NodeInterface
 // Deprecated: use CoreV1().Nodes() directly
 func (a *AccessControlClientset) Nodes() (corev1.NodeInterface, error) {
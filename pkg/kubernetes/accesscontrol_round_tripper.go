@@ -1,8 +1,10 @@
 package kubernetes
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
@@ -10,6 +12,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// ErrResourceNotAllowed is returned for the requests of the resources denied by the configuration (denied_resources)
+var ErrResourceNotAllowed = errors.New("resource not allowed")
+
 type AccessControlRoundTripper struct {
 	delegate     http.RoundTripper
 	staticConfig *config.StaticConfig
@@ -28,12 +33,72 @@ func (rt *AccessControlRoundTripper) RoundTrip(req *http.Request) (*http.Respons
 		return nil, fmt.Errorf("failed to make request: AccessControlRoundTripper failed to get kind for gvr %v: %w", gvr, err)
 	}
 	if !rt.isAllowed(gvk) {
-		return nil, fmt.Errorf("resource not allowed: %s", gvk.String())
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotAllowed, gvk.String())
+	}
+	if err = rt.isNamespaceAllowed(req, gvk); err != nil {
+		return nil, err
 	}
 
 	return rt.delegate.RoundTrip(req)
 }
 
+// isNamespaceAllowed checks the request complies with the target namespaces of the configuration.
+// The requests to the other namespaces, across all namespaces, and to the nodes are denied, the cluster-scoped
+// resources are read-only (except the access and token reviews, which don't persist anything).
+func (rt *AccessControlRoundTripper) isNamespaceAllowed(req *http.Request, gvk schema.GroupVersionKind) error {
+	if rt.staticConfig == nil || len(rt.staticConfig.TargetNamespaces) == 0 {
+		return nil
+	}
+	targetNamespaces := rt.staticConfig.TargetNamespaces
+	restricted := targetNamespacesRestriction(rt.staticConfig)
+	namespace, name := parseURLNamespace(req.URL.Path)
+	readOnly := req.Method == http.MethodGet || req.Method == http.MethodHead
+	switch {
+	case gvk.Group == "" && gvk.Kind == "Namespace":
+		if name == "" {
+			return fmt.Errorf("%w: %s across all namespaces, %s", ErrResourceNotAllowed, gvk.String(), restricted)
+		}
+		if !readOnly || !slices.Contains(targetNamespaces, name) {
+			return fmt.Errorf("%w: %s %s, %s", ErrResourceNotAllowed, gvk.String(), name, restricted)
+		}
+		return nil
+	case namespace != "":
+		return isTargetNamespace(rt.staticConfig, namespace)
+	case gvk.Kind == "Node" || gvk.Kind == "NodeMetrics":
+		return fmt.Errorf("%w: %s, %s", ErrResourceNotAllowed, gvk.String(), restricted)
+	}
+	mapping, err := rt.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to make request: AccessControlRoundTripper failed to get scope for gvk %v: %w", gvk, err)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return fmt.Errorf("%w: %s across all namespaces, %s", ErrResourceNotAllowed, gvk.String(), restricted)
+	}
+	if !readOnly && gvk.Group != "authorization.k8s.io" && gvk.Group != "authentication.k8s.io" {
+		return fmt.Errorf("%w: changes to the cluster-scoped %s, %s", ErrResourceNotAllowed, gvk.String(), restricted)
+	}
+	return nil
+}
+
+// isTargetNamespace checks the namespace is one of the target namespaces of the configuration, an empty namespace
+// refers to all the namespaces and is only allowed if the server isn't restricted to target namespaces.
+func isTargetNamespace(staticConfig *config.StaticConfig, namespace string) error {
+	if staticConfig == nil || len(staticConfig.TargetNamespaces) == 0 {
+		return nil
+	}
+	if namespace == "" {
+		return fmt.Errorf("%w: all namespaces, %s", ErrResourceNotAllowed, targetNamespacesRestriction(staticConfig))
+	}
+	if !slices.Contains(staticConfig.TargetNamespaces, namespace) {
+		return fmt.Errorf("%w: namespace %s, %s", ErrResourceNotAllowed, namespace, targetNamespacesRestriction(staticConfig))
+	}
+	return nil
+}
+
+func targetNamespacesRestriction(staticConfig *config.StaticConfig) string {
+	return fmt.Sprintf("the server is restricted to the target_namespaces (%s)", strings.Join(staticConfig.TargetNamespaces, ", "))
+}
+
 // isAllowed checks the resource is in denied list or not.
 // If it is in denied list, this function returns false.
 func (rt *AccessControlRoundTripper) isAllowed(
@@ -43,7 +108,7 @@ func (rt *AccessControlRoundTripper) isAllowed(
 		return true
 	}
 
-	for _, val := range rt.staticConfig.DeniedResources {
+	for _, val := range rt.staticConfig.GetDeniedResources() {
 		// If kind is empty, that means Group/Version pair is denied entirely
 		if val.Kind == "" {
 			if gvk.Group == val.Group && gvk.Version == val.Version {
@@ -94,3 +159,23 @@ func parseURLToGVR(path string) (gvr schema.GroupVersionResource, ok bool) {
 	}
 	return gvr, true
 }
+
+// parseURLNamespace returns the namespace of the API resource request, and the name of the namespace for the requests
+// of the namespaces themselves (e.g. /api/v1/namespaces/foo)
+func parseURLNamespace(path string) (namespace, name string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var rest []string
+	switch {
+	case len(parts) > 2 && parts[0] == "api":
+		rest = parts[2:]
+	case len(parts) > 3 && parts[0] == "apis":
+		rest = parts[3:]
+	}
+	if len(rest) < 2 || rest[0] != "namespaces" {
+		return "", ""
+	}
+	if len(rest) == 2 {
+		return "", rest[1]
+	}
+	return rest[1], rest[1]
+}
@@ -34,7 +34,10 @@ type AccessControlRoundTripperTestSuite struct {
 
 func (s *AccessControlRoundTripperTestSuite) SetupTest() {
 	s.mockServer = test.NewMockServer()
-	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: []string{
+		`{"name":"namespaces","singularName":"","namespaced":false,"kind":"Namespace","verbs":["get","list","watch","create","delete"]}`,
+		`{"name":"persistentvolumes","singularName":"","namespaced":false,"kind":"PersistentVolume","verbs":["get","list","watch","create","delete"]}`,
+	}})
 
 	clientSet, err := kubernetes.NewForConfig(s.mockServer.Config())
 	s.Require().NoError(err, "Expected no error creating clientset")
@@ -242,6 +245,62 @@ func (s *AccessControlRoundTripperTestSuite) TestRoundTripForDeniedAPIResources(
 	})
 }
 
+func (s *AccessControlRoundTripperTestSuite) TestRoundTripForTargetNamespaces() {
+	delegateCalled := false
+	mockDelegate := &mockRoundTripper{
+		called: &delegateCalled,
+		onRequest: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+	}
+	rt := &AccessControlRoundTripper{
+		delegate:     mockDelegate,
+		staticConfig: &config.StaticConfig{TargetNamespaces: []string{"team-a", "team-b"}},
+		restMapper:   s.restMapper,
+	}
+	for _, tc := range []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/api/v1/namespaces/team-a/pods"},
+		{"DELETE", "/api/v1/namespaces/team-b/pods/my-pod"},
+		{"POST", "/apis/apps/v1/namespaces/team-a/deployments"},
+		{"GET", "/api/v1/namespaces/team-a"},
+		{"GET", "/api/v1/persistentvolumes"},
+	} {
+		s.Run(tc.method+" "+tc.path+" is allowed", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest(tc.method, tc.path, nil))
+			s.Require().NoError(err)
+			s.Equal(http.StatusOK, resp.StatusCode)
+			s.True(delegateCalled)
+		})
+	}
+	for _, tc := range []struct {
+		method string
+		path   string
+		err    string
+	}{
+		{"GET", "/api/v1/namespaces/kube-system/pods", "resource not allowed: namespace kube-system, the server is restricted to the target_namespaces (team-a, team-b)"},
+		{"GET", "/api/v1/pods", "resource not allowed: /v1, Kind=Pod across all namespaces, the server is restricted to the target_namespaces (team-a, team-b)"},
+		{"GET", "/apis/apps/v1/deployments", "resource not allowed: apps/v1, Kind=Deployment across all namespaces, the server is restricted to the target_namespaces (team-a, team-b)"},
+		{"GET", "/api/v1/namespaces", "resource not allowed: /v1, Kind=Namespace across all namespaces, the server is restricted to the target_namespaces (team-a, team-b)"},
+		{"GET", "/api/v1/namespaces/kube-system", "resource not allowed: /v1, Kind=Namespace kube-system, the server is restricted to the target_namespaces (team-a, team-b)"},
+		{"DELETE", "/api/v1/namespaces/team-a", "resource not allowed: /v1, Kind=Namespace team-a, the server is restricted to the target_namespaces (team-a, team-b)"},
+		{"GET", "/api/v1/nodes/node-1", "resource not allowed: /v1, Kind=Node, the server is restricted to the target_namespaces (team-a, team-b)"},
+		{"DELETE", "/api/v1/persistentvolumes/pv-1", "resource not allowed: changes to the cluster-scoped /v1, Kind=PersistentVolume, the server is restricted to the target_namespaces (team-a, team-b)"},
+	} {
+		s.Run(tc.method+" "+tc.path+" is denied", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest(tc.method, tc.path, nil))
+			s.EqualError(err, tc.err)
+			s.ErrorIs(err, ErrResourceNotAllowed)
+			s.Nil(resp)
+			s.False(delegateCalled)
+		})
+	}
+}
+
 func TestAccessControlRoundTripper(t *testing.T) {
 	suite.Run(t, new(AccessControlRoundTripperTestSuite))
 }
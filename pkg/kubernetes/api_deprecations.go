@@ -0,0 +1,259 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	// APIRemoved is the status of the objects last applied with an API version the cluster no longer serves
+	APIRemoved = "removed"
+	// APIRemovedInTarget is the status of the objects last applied with an API version the target version no longer serves
+	APIRemovedInTarget = "removed-in-target"
+	// APIDeprecated is the status of the objects last applied with a deprecated API version still served by the target version
+	APIDeprecated = "deprecated"
+)
+
+// lastAppliedConfigurationAnnotation is the annotation where kubectl apply (client-side) stores the applied manifest
+const lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// APIDeprecationsReport is the upgrade readiness report of the objects of the cluster that were created or updated
+// with deprecated or removed API versions
+type APIDeprecationsReport struct {
+	// Summary is a one line description, e.g. "2 objects use deprecated API versions in all namespaces (cluster v1.24.3, target 1.25), 1 use API versions removed in 1.25"
+	Summary       string `json:"summary"`
+	ServerVersion string `json:"serverVersion"`
+	TargetVersion string `json:"targetVersion"`
+	// UpgradeReady is false when some objects are managed with API versions removed in the cluster or target version,
+	// their manifests (or the tools applying them) must be migrated to the replacement API versions
+	UpgradeReady bool                    `json:"upgradeReady"`
+	Findings     []APIDeprecationFinding `json:"findings"`
+	Warnings     []string                `json:"warnings,omitempty"`
+}
+
+type APIDeprecationFinding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// APIVersion is the deprecated API version the object was applied with
+	APIVersion string `json:"apiVersion"`
+	// Sources are where the API version was found: last-applied (kubectl apply) or the managers of the managed fields (server-side apply and updates)
+	Sources      []string `json:"sources"`
+	DeprecatedIn string   `json:"deprecatedIn,omitempty"`
+	RemovedIn    string   `json:"removedIn,omitempty"`
+	// Replacement is the apiVersion to migrate to, empty if the API was removed with no replacement
+	Replacement string `json:"replacement,omitempty"`
+	// Status is removed, removed-in-target, or deprecated
+	Status string `json:"status"`
+}
+
+type APIDeprecationsOptions struct {
+	// Namespace of the objects to scan, all namespaces and the cluster-scoped objects if empty
+	Namespace string
+	// TargetVersion is the Kubernetes minor version of the upgrade, e.g. 1.32 (Optional, the next minor version of the cluster if empty)
+	TargetVersion string
+}
+
+// APIDeprecations scans the live objects of the kinds with deprecated API versions for the apiVersion they were last
+// applied with (kubectl.kubernetes.io/last-applied-configuration annotation) and managed with (managed fields), and
+// reports the ones using API versions deprecated or removed in the cluster version or the target version.
+// Failures listing a kind don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) APIDeprecations(ctx context.Context, options APIDeprecationsOptions) (*APIDeprecationsReport, error) {
+	serverInfo, err := k.AccessControlClientset().DiscoveryClient().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	serverVersion, err := version.ParseGeneric(serverInfo.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the cluster version %s: %w", serverInfo.GitVersion, err)
+	}
+	serverVersion = version.MajorMinor(serverVersion.Major(), serverVersion.Minor())
+	targetVersion := version.MajorMinor(serverVersion.Major(), serverVersion.Minor()+1)
+	if options.TargetVersion != "" {
+		if targetVersion, err = version.ParseGeneric(options.TargetVersion); err != nil {
+			return nil, fmt.Errorf("invalid target version %q", options.TargetVersion)
+		}
+		targetVersion = version.MajorMinor(targetVersion.Major(), targetVersion.Minor())
+		if targetVersion.LessThan(serverVersion) {
+			return nil, fmt.Errorf("target version %s is older than the cluster version %s", targetVersion, serverVersion)
+		}
+	}
+	report := &APIDeprecationsReport{ServerVersion: serverInfo.GitVersion, TargetVersion: targetVersion.String(), Findings: []APIDeprecationFinding{}}
+	// The objects are listed with the version preferred by the cluster, the replacement kind if the deprecated API
+	// version was moved to another group (e.g. extensions/v1beta1 Ingress to networking.k8s.io/v1 Ingress)
+	deprecationsByKind := map[schema.GroupKind][]APIDeprecation{}
+	for _, d := range APIDeprecations {
+		if !d.DeprecatedBy(targetVersion) && !d.RemovedBy(targetVersion) {
+			continue
+		}
+		gk := d.GroupVersionKind.GroupKind()
+		if !d.Replacement.Empty() {
+			gk = d.Replacement.GroupKind()
+		}
+		deprecationsByKind[gk] = append(deprecationsByKind[gk], d)
+	}
+	kinds := make([]schema.GroupKind, 0, len(deprecationsByKind))
+	for gk := range deprecationsByKind {
+		kinds = append(kinds, gk)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	for _, gk := range kinds {
+		mapping, err := k.AccessControlClientset().RESTMapper().RESTMapping(gk)
+		if meta.IsNoMatchError(err) {
+			// the kind isn't served by the cluster, there's no object to scan
+			continue
+		} else if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("unable to resolve %s: %v", gk, err))
+			continue
+		}
+		resource := k.AccessControlClientset().DynamicClient().Resource(mapping.Resource)
+		var list *unstructured.UnstructuredList
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			list, err = resource.Namespace(options.Namespace).List(ctx, metav1.ListOptions{})
+		} else if options.Namespace == "" {
+			list, err = resource.List(ctx, metav1.ListOptions{})
+		} else {
+			continue
+		}
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("unable to list %s: %v", mapping.Resource.GroupResource(), err))
+			continue
+		}
+		for i := range list.Items {
+			for _, finding := range DeprecatedAPIFindings(&list.Items[i], deprecationsByKind[gk]) {
+				finding.Status = APIDeprecated
+				if d := deprecationFor(finding.APIVersion, finding.Kind); d.RemovedBy(serverVersion) {
+					finding.Status = APIRemoved
+				} else if d.RemovedBy(targetVersion) {
+					finding.Status = APIRemovedInTarget
+				}
+				report.Findings = append(report.Findings, finding)
+			}
+		}
+	}
+	sortAPIDeprecationFindings(report.Findings)
+	report.UpgradeReady = true
+	for _, finding := range report.Findings {
+		if finding.Status != APIDeprecated {
+			report.UpgradeReady = false
+		}
+	}
+	report.Summary = report.summary(options.Namespace)
+	return report, nil
+}
+
+// DeprecatedAPIFindings returns the deprecated API versions of the provided deprecations (of the object kind) the
+// object was last applied or managed with, one finding per API version
+func DeprecatedAPIFindings(obj *unstructured.Unstructured, deprecations []APIDeprecation) []APIDeprecationFinding {
+	sources := map[string][]string{}
+	var apiVersions []string
+	addSource := func(apiVersion, source string) {
+		for _, d := range deprecations {
+			if d.GroupVersionKind.Kind != obj.GetKind() || d.APIVersion() != apiVersion {
+				continue
+			}
+			if _, ok := sources[apiVersion]; !ok {
+				apiVersions = append(apiVersions, apiVersion)
+			}
+			for _, s := range sources[apiVersion] {
+				if s == source {
+					return
+				}
+			}
+			sources[apiVersion] = append(sources[apiVersion], source)
+			return
+		}
+	}
+	if lastApplied, ok := obj.GetAnnotations()[lastAppliedConfigurationAnnotation]; ok {
+		var applied metav1.TypeMeta
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err == nil {
+			addSource(applied.APIVersion, "last-applied")
+		}
+	}
+	for _, managedField := range obj.GetManagedFields() {
+		addSource(managedField.APIVersion, fmt.Sprintf("managedFields (%s)", managedField.Manager))
+	}
+	findings := make([]APIDeprecationFinding, 0, len(apiVersions))
+	for _, apiVersion := range apiVersions {
+		d := deprecationFor(apiVersion, obj.GetKind())
+		finding := APIDeprecationFinding{
+			Kind:         obj.GetKind(),
+			Namespace:    obj.GetNamespace(),
+			Name:         obj.GetName(),
+			APIVersion:   apiVersion,
+			Sources:      sources[apiVersion],
+			DeprecatedIn: d.DeprecatedIn,
+			RemovedIn:    d.RemovedIn,
+		}
+		if !d.Replacement.Empty() {
+			finding.Replacement = d.Replacement.GroupVersion().String()
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// deprecationFor returns the deprecation of the provided API version of the kind, a zero value if not deprecated
+func deprecationFor(apiVersion, kind string) APIDeprecation {
+	for _, d := range APIDeprecations {
+		if d.GroupVersionKind.Kind == kind && d.APIVersion() == apiVersion {
+			return d
+		}
+	}
+	return APIDeprecation{}
+}
+
+// sortAPIDeprecationFindings sorts the findings by status, the removed API versions first, then by kind, namespace, and name
+func sortAPIDeprecationFindings(findings []APIDeprecationFinding) {
+	severity := map[string]int{APIRemoved: 0, APIRemovedInTarget: 1, APIDeprecated: 2}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if severity[findings[i].Status] != severity[findings[j].Status] {
+			return severity[findings[i].Status] < severity[findings[j].Status]
+		}
+		if findings[i].Kind != findings[j].Kind {
+			return findings[i].Kind < findings[j].Kind
+		}
+		if findings[i].Namespace != findings[j].Namespace {
+			return findings[i].Namespace < findings[j].Namespace
+		}
+		return findings[i].Name < findings[j].Name
+	})
+}
+
+func (r *APIDeprecationsReport) summary(namespace string) string {
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = "namespace " + namespace
+	}
+	// an object applied with several deprecated API versions is counted once, with its most severe status
+	objects := map[string]bool{}
+	counts := map[string]int{}
+	for _, finding := range r.Findings {
+		object := finding.Kind + "/" + finding.Namespace + "/" + finding.Name
+		if !objects[object] {
+			objects[object] = true
+			counts[finding.Status]++
+		}
+	}
+	summary := fmt.Sprintf("%d objects use deprecated API versions in %s (cluster %s, target %s)", len(objects), scope, r.ServerVersion, r.TargetVersion)
+	var details []string
+	if counts[APIRemoved] > 0 {
+		details = append(details, fmt.Sprintf("%d use API versions removed in the cluster version", counts[APIRemoved]))
+	}
+	if counts[APIRemovedInTarget] > 0 {
+		details = append(details, fmt.Sprintf("%d use API versions removed in %s", counts[APIRemovedInTarget], r.TargetVersion))
+	}
+	if len(details) > 0 {
+		summary += ", " + strings.Join(details, ", ")
+	}
+	return summary
+}
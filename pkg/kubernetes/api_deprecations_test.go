@@ -0,0 +1,115 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+type APIDeprecationsSuite struct {
+	suite.Suite
+}
+
+func apiDeprecationsTestObject(kind, namespace, name, lastApplied string, managedFields ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	if lastApplied != "" {
+		obj.SetAnnotations(map[string]string{lastAppliedConfigurationAnnotation: lastApplied})
+	}
+	obj.SetManagedFields(managedFields)
+	return obj
+}
+
+func (s *APIDeprecationsSuite) TestAPIDeprecations() {
+	s.Run("every deprecation has a valid deprecated and removed version", func() {
+		for _, d := range APIDeprecations {
+			_, err := version.ParseGeneric(d.DeprecatedIn)
+			s.NoErrorf(err, "invalid deprecatedIn of %s", d.GroupVersionKind)
+			_, err = version.ParseGeneric(d.RemovedIn)
+			s.NoErrorf(err, "invalid removedIn of %s", d.GroupVersionKind)
+			s.Truef(d.Replacement.Empty() || d.Replacement.Kind == d.GroupVersionKind.Kind, "invalid replacement of %s", d.GroupVersionKind)
+		}
+	})
+	s.Run("deprecated and removed by the releases since the ones that deprecated and removed them", func() {
+		cronJob := deprecationFor("batch/v1beta1", "CronJob")
+		s.Equal("batch/v1", cronJob.Replacement.GroupVersion().String())
+		s.False(cronJob.DeprecatedBy(version.MajorMinor(1, 20)))
+		s.True(cronJob.DeprecatedBy(version.MajorMinor(1, 21)))
+		s.False(cronJob.RemovedBy(version.MajorMinor(1, 24)))
+		s.True(cronJob.RemovedBy(version.MajorMinor(1, 25)))
+		s.True(cronJob.RemovedBy(version.MajorMinor(1, 30)))
+	})
+	s.Run("not deprecated API versions have no deprecation", func() {
+		s.Equal(APIDeprecation{}, deprecationFor("batch/v1", "CronJob"))
+		s.False(deprecationFor("batch/v1", "CronJob").RemovedBy(version.MajorMinor(1, 30)))
+	})
+}
+
+func (s *APIDeprecationsSuite) TestDeprecatedAPIFindings() {
+	ingressDeprecations := []APIDeprecation{deprecationFor("extensions/v1beta1", "Ingress"), deprecationFor("networking.k8s.io/v1beta1", "Ingress")}
+	s.Run("reports the last applied API version", func() {
+		findings := DeprecatedAPIFindings(apiDeprecationsTestObject("Ingress", "shop", "web",
+			`{"apiVersion":"extensions/v1beta1","kind":"Ingress","metadata":{"name":"web"}}`,
+			metav1.ManagedFieldsEntry{Manager: "kubectl-client-side-apply", APIVersion: "extensions/v1beta1"},
+		), ingressDeprecations)
+		s.Equal([]APIDeprecationFinding{{
+			Kind:         "Ingress",
+			Namespace:    "shop",
+			Name:         "web",
+			APIVersion:   "extensions/v1beta1",
+			Sources:      []string{"last-applied", "managedFields (kubectl-client-side-apply)"},
+			DeprecatedIn: "1.14",
+			RemovedIn:    "1.22",
+			Replacement:  "networking.k8s.io/v1",
+		}}, findings)
+	})
+	s.Run("reports each deprecated API version of the managed fields", func() {
+		findings := DeprecatedAPIFindings(apiDeprecationsTestObject("Ingress", "shop", "api", "",
+			metav1.ManagedFieldsEntry{Manager: "helm", APIVersion: "networking.k8s.io/v1beta1"},
+			metav1.ManagedFieldsEntry{Manager: "nginx-ingress-controller", APIVersion: "networking.k8s.io/v1"},
+			metav1.ManagedFieldsEntry{Manager: "argocd", APIVersion: "extensions/v1beta1"},
+		), ingressDeprecations)
+		s.Require().Len(findings, 2)
+		s.Equal("networking.k8s.io/v1beta1", findings[0].APIVersion)
+		s.Equal([]string{"managedFields (helm)"}, findings[0].Sources)
+		s.Equal("extensions/v1beta1", findings[1].APIVersion)
+		s.Equal([]string{"managedFields (argocd)"}, findings[1].Sources)
+	})
+	s.Run("ignores the objects using the current API versions", func() {
+		s.Empty(DeprecatedAPIFindings(apiDeprecationsTestObject("Ingress", "shop", "web",
+			`{"apiVersion":"networking.k8s.io/v1","kind":"Ingress"}`,
+			metav1.ManagedFieldsEntry{Manager: "kubectl", APIVersion: "networking.k8s.io/v1"},
+		), ingressDeprecations))
+	})
+	s.Run("ignores invalid last applied configurations", func() {
+		s.Empty(DeprecatedAPIFindings(apiDeprecationsTestObject("Ingress", "shop", "web", "not json"), ingressDeprecations))
+	})
+}
+
+func (s *APIDeprecationsSuite) TestSummary() {
+	report := &APIDeprecationsReport{ServerVersion: "v1.24.3", TargetVersion: "1.25", Findings: []APIDeprecationFinding{
+		{Kind: "CronJob", Namespace: "shop", Name: "backup", APIVersion: "batch/v1beta1", Status: APIDeprecated},
+		{Kind: "Ingress", Namespace: "shop", Name: "web", APIVersion: "extensions/v1beta1", Status: APIRemoved},
+		{Kind: "Ingress", Namespace: "shop", Name: "web", APIVersion: "networking.k8s.io/v1beta1", Status: APIRemoved},
+		{Kind: "CronJob", Namespace: "shop", Name: "cleanup", APIVersion: "batch/v1beta1", Status: APIRemovedInTarget},
+	}}
+	sortAPIDeprecationFindings(report.Findings)
+	s.Run("sorts the removed API versions first", func() {
+		s.Equal("web", report.Findings[0].Name)
+		s.Equal("cleanup", report.Findings[2].Name)
+		s.Equal("backup", report.Findings[3].Name)
+	})
+	s.Run("counts each object once", func() {
+		s.Equal("3 objects use deprecated API versions in namespace shop (cluster v1.24.3, target 1.25), "+
+			"1 use API versions removed in the cluster version, 1 use API versions removed in 1.25", report.summary("shop"))
+	})
+}
+
+func TestAPIDeprecations(t *testing.T) {
+	suite.Run(t, new(APIDeprecationsSuite))
+}
@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	explainv2 "k8s.io/kubectl/pkg/explain/v2"
+)
+
+// crdGVR is the GroupVersionResource of the CustomResourceDefinitions
+var crdGVR = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+// CustomResourceDefinition summarizes a CRD with its served versions and its conditions
+type CustomResourceDefinition struct {
+	Name  string `json:"name"`
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	// Scope is Namespaced or Cluster
+	Scope      string   `json:"scope"`
+	ShortNames []string `json:"shortNames,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	// Versions are the versions of the CRD, the storage version first
+	Versions []CustomResourceVersion `json:"versions"`
+	// Established is true when the CRD is served by the API server
+	Established bool `json:"established"`
+	// Conditions are the conditions of the CRD that are not in their healthy state (e.g. NamesAccepted False, NonStructuralSchema True)
+	Conditions []string `json:"conditions,omitempty"`
+	Created    string   `json:"created,omitempty"`
+}
+
+type CustomResourceVersion struct {
+	Name       string `json:"name"`
+	Served     bool   `json:"served"`
+	Storage    bool   `json:"storage,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+	// DeprecationWarning is returned by the API server to the clients of a deprecated version
+	DeprecationWarning string `json:"deprecationWarning,omitempty"`
+}
+
+// CRDsList returns the CustomResourceDefinitions of the cluster, only the ones of the provided group (or group suffix,
+// e.g. cert-manager.io) if not empty, sorted by name
+func (k *Kubernetes) CRDsList(ctx context.Context, group string) ([]CustomResourceDefinition, error) {
+	list, err := k.AccessControlClientset().DynamicClient().Resource(crdGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	crds := make([]apiextensionsv1.CustomResourceDefinition, 0, len(list.Items))
+	for _, item := range list.Items {
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &crd); err != nil {
+			return nil, fmt.Errorf("failed to convert CustomResourceDefinition %s: %w", item.GetName(), err)
+		}
+		if group == "" || crd.Spec.Group == group || strings.HasSuffix(crd.Spec.Group, "."+group) {
+			crds = append(crds, crd)
+		}
+	}
+	return CustomResourceDefinitions(crds), nil
+}
+
+// CustomResourceDefinitions summarizes the CRDs, sorted by name
+func CustomResourceDefinitions(crds []apiextensionsv1.CustomResourceDefinition) []CustomResourceDefinition {
+	result := make([]CustomResourceDefinition, 0, len(crds))
+	for _, crd := range crds {
+		summary := CustomResourceDefinition{
+			Name:       crd.Name,
+			Group:      crd.Spec.Group,
+			Kind:       crd.Spec.Names.Kind,
+			Scope:      string(crd.Spec.Scope),
+			ShortNames: crd.Spec.Names.ShortNames,
+			Categories: crd.Spec.Names.Categories,
+			Versions:   make([]CustomResourceVersion, 0, len(crd.Spec.Versions)),
+		}
+		if !crd.CreationTimestamp.IsZero() {
+			summary.Created = crd.CreationTimestamp.UTC().Format(time.RFC3339)
+		}
+		for _, version := range crd.Spec.Versions {
+			v := CustomResourceVersion{Name: version.Name, Served: version.Served, Storage: version.Storage, Deprecated: version.Deprecated}
+			if version.DeprecationWarning != nil {
+				v.DeprecationWarning = *version.DeprecationWarning
+			}
+			summary.Versions = append(summary.Versions, v)
+		}
+		sort.SliceStable(summary.Versions, func(i, j int) bool { return summary.Versions[i].Storage && !summary.Versions[j].Storage })
+		for _, condition := range crd.Status.Conditions {
+			healthy := condition.Status == apiextensionsv1.ConditionTrue
+			// These conditions report an issue when they're true
+			if condition.Type == apiextensionsv1.NonStructuralSchema || condition.Type == apiextensionsv1.Terminating {
+				healthy = condition.Status != apiextensionsv1.ConditionTrue
+			}
+			if condition.Type == apiextensionsv1.Established {
+				summary.Established = condition.Status == apiextensionsv1.ConditionTrue
+			}
+			if !healthy {
+				description := fmt.Sprintf("%s %s", condition.Type, condition.Status)
+				if condition.Message != "" {
+					description += ": " + condition.Message
+				}
+				summary.Conditions = append(summary.Conditions, description)
+			}
+		}
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Explain returns the documentation of the fields of the resource with the provided GroupVersionKind, built-in or
+// custom, from the OpenAPI v3 schema published by the API server, as kubectl explain does.
+// field is the dotted path of the field to explain (e.g. spec.template.spec.containers), the whole resource if empty.
+// With recursive, the nested fields are listed with their types instead of the documentation of the direct fields.
+func (k *Kubernetes) Explain(gvk *schema.GroupVersionKind, field string, recursive bool) (string, error) {
+	if !(&AccessControlRoundTripper{staticConfig: k.AccessControlClientset().staticConfig}).isAllowed(*gvk) {
+		return "", fmt.Errorf("%w: %s", ErrResourceNotAllowed, gvk.String())
+	}
+	gvr, err := k.resourceFor(gvk)
+	if err != nil {
+		return "", err
+	}
+	var fieldsPath []string
+	if field = strings.Trim(field, "."); field != "" {
+		fieldsPath = strings.Split(field, ".")
+		for _, f := range fieldsPath {
+			if f == "" {
+				return "", fmt.Errorf("invalid field path %q", field)
+			}
+		}
+	}
+	var out bytes.Buffer
+	if err = explainv2.PrintModelDescription(fieldsPath, &out, k.AccessControlClientset().DiscoveryClient().OpenAPIV3(), *gvr, recursive, "plaintext"); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type ApisSuite struct {
+	suite.Suite
+}
+
+func (s *ApisSuite) TestCustomResourceDefinitions() {
+	crds := CustomResourceDefinitions([]apiextensionsv1.CustomResourceDefinition{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Widget", ShortNames: []string{"wg"}},
+				Scope: apiextensionsv1.NamespaceScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true, Deprecated: true, DeprecationWarning: ptr.To("example.com/v1alpha1 Widget is deprecated")},
+					{Name: "v1", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+				{Type: apiextensionsv1.NonStructuralSchema, Status: apiextensionsv1.ConditionTrue, Message: "spec.validation.openAPIV3Schema.type: Required value"},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gadgets.example.com"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    "example.com",
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: "Gadget"},
+				Scope:    apiextensionsv1.ClusterScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionFalse, Message: `"gadgets" is already in use`},
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionFalse, Message: "not all names are accepted"},
+			}},
+		},
+	})
+	s.Require().Len(crds, 2)
+	s.Run("sorts CRDs by name", func() {
+		s.Equal("gadgets.example.com", crds[0].Name)
+		s.Equal("widgets.example.com", crds[1].Name)
+	})
+	s.Run("lists the storage version first", func() {
+		s.Equal([]CustomResourceVersion{
+			{Name: "v1", Served: true, Storage: true},
+			{Name: "v1alpha1", Served: true, Deprecated: true, DeprecationWarning: "example.com/v1alpha1 Widget is deprecated"},
+		}, crds[1].Versions)
+	})
+	s.Run("reports the established CRDs", func() {
+		s.False(crds[0].Established)
+		s.True(crds[1].Established)
+	})
+	s.Run("reports the unhealthy conditions", func() {
+		s.Equal([]string{
+			`NamesAccepted False: "gadgets" is already in use`,
+			"Established False: not all names are accepted",
+		}, crds[0].Conditions)
+		s.Equal([]string{"NonStructuralSchema True: spec.validation.openAPIV3Schema.type: Required value"}, crds[1].Conditions)
+	})
+	s.Run("reports the names and scope", func() {
+		s.Equal("Widget", crds[1].Kind)
+		s.Equal([]string{"wg"}, crds[1].ShortNames)
+		s.Equal("Namespaced", crds[1].Scope)
+		s.Equal("Cluster", crds[0].Scope)
+	})
+}
+
+func TestApis(t *testing.T) {
+	suite.Run(t, new(ApisSuite))
+}
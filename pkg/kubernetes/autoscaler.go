@@ -0,0 +1,454 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ClusterAutoscalerStatusConfigMap is the ConfigMap where the cluster-autoscaler writes its status
+	ClusterAutoscalerStatusConfigMap = "cluster-autoscaler-status"
+	// AutoscalerMaxEvents is the maximum number of recent autoscaler events reported
+	AutoscalerMaxEvents = 20
+	// AutoscalerMaxNodeClaims is the maximum number of Karpenter NodeClaims reported
+	AutoscalerMaxNodeClaims = 100
+
+	autoscalerSourceClusterAutoscaler = "cluster-autoscaler"
+	autoscalerSourceKarpenter         = "karpenter"
+)
+
+var (
+	// ClusterAutoscalerNamespaces are the namespaces searched for the status ConfigMap of the cluster-autoscaler,
+	// kube-system upstream and openshift-machine-api in OpenShift
+	ClusterAutoscalerNamespaces = []string{"kube-system", "openshift-machine-api"}
+	karpenterNodePoolGVR        = schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1", Resource: "nodepools"}
+	karpenterNodeClaimGVR       = schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1", Resource: "nodeclaims"}
+	// autoscalerHints explain the messages of the autoscaler events of the Pods that didn't trigger a scale-up
+	autoscalerHints = []struct{ message, hint string }{
+		{"max node group size reached", "the node groups reached their maximum size, raise the maximum size of the node groups"},
+		{"max cluster", "the cluster reached the maximum number of nodes or resources of the cluster-autoscaler"},
+		{"in backoff after failed scale-up", "the node group is in backoff after a failed scale-up, check the cloud provider errors and quotas"},
+		{"node affinity", "no node group provides nodes matching the node selector or node affinity of the Pod"},
+		{"untolerated taint", "the new nodes would have taints that the Pod doesn't tolerate"},
+		{"Insufficient", "the Pod requests more resources than a new node would provide"},
+		{"incompatible with nodepool", "no Karpenter NodePool matches the requirements of the Pod"},
+		{"exceed limits", "the resource limits of the Karpenter NodePools are reached"},
+		{"no instance type", "no instance type of the Karpenter NodePools satisfies the requirements of the Pod"},
+	}
+)
+
+// AutoscalerStatus is the state of the node autoscalers of the cluster, the cluster-autoscaler and Karpenter, with
+// their recent decisions
+type AutoscalerStatus struct {
+	Summary           string                   `json:"summary"`
+	ClusterAutoscaler *ClusterAutoscalerStatus `json:"clusterAutoscaler,omitempty"`
+	Karpenter         *KarpenterStatus         `json:"karpenter,omitempty"`
+	// Events are the most recent events of the autoscalers, newest first
+	Events []WorkloadEvent `json:"events,omitempty"`
+	// Pod explains why the pending Pod did or didn't trigger a scale-up
+	Pod      *AutoscalerPod `json:"pod,omitempty"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+// ClusterAutoscalerStatus is the status reported by the cluster-autoscaler in its status ConfigMap
+type ClusterAutoscalerStatus struct {
+	Namespace           string                       `json:"namespace"`
+	Time                string                       `json:"time,omitempty"`
+	Status              string                       `json:"status,omitempty"`
+	Health              string                       `json:"health,omitempty"`
+	ScaleUp             string                       `json:"scaleUp,omitempty"`
+	ScaleDown           string                       `json:"scaleDown,omitempty"`
+	ScaleDownCandidates int                          `json:"scaleDownCandidates,omitempty"`
+	NodeGroups          []ClusterAutoscalerNodeGroup `json:"nodeGroups,omitempty"`
+	// Raw is the status of the cluster-autoscaler versions reporting it as text instead of YAML
+	Raw string `json:"raw,omitempty"`
+}
+
+// ClusterAutoscalerNodeGroup is the status of a node group of the cluster-autoscaler
+type ClusterAutoscalerNodeGroup struct {
+	Name       string `json:"name"`
+	Health     string `json:"health"`
+	Ready      int    `json:"ready"`
+	Registered int    `json:"registered"`
+	Target     int    `json:"target"`
+	MinSize    int    `json:"minSize"`
+	MaxSize    int    `json:"maxSize"`
+	ScaleUp    string `json:"scaleUp,omitempty"`
+	ScaleDown  string `json:"scaleDown,omitempty"`
+	// Backoff is the error of the last failed scale-up of the node group in backoff
+	Backoff string `json:"backoff,omitempty"`
+}
+
+// KarpenterStatus is the state of the Karpenter NodePools and NodeClaims
+type KarpenterStatus struct {
+	NodePools  []KarpenterNodePool  `json:"nodePools"`
+	NodeClaims []KarpenterNodeClaim `json:"nodeClaims"`
+	Truncated  bool                 `json:"truncated,omitempty"`
+}
+
+type KarpenterNodePool struct {
+	Name  string `json:"name"`
+	Ready string `json:"ready"`
+	// Resources are the resources of the nodes of the NodePool, e.g. cpu, memory, nodes
+	Resources map[string]string `json:"resources,omitempty"`
+	Limits    map[string]string `json:"limits,omitempty"`
+	// Consolidation is the consolidation policy and delay, e.g. WhenEmptyOrUnderutilized after 1m
+	Consolidation string `json:"consolidation,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+type KarpenterNodeClaim struct {
+	Name         string `json:"name"`
+	NodePool     string `json:"nodePool,omitempty"`
+	Node         string `json:"node,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	CapacityType string `json:"capacityType,omitempty"`
+	Zone         string `json:"zone,omitempty"`
+	// Status is the last lifecycle step reached (Launched, Registered, Initialized), Pending, Drifted, or Terminating
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// AutoscalerPod explains the scale-up decisions of the autoscalers for a pending Pod
+type AutoscalerPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	Node      string `json:"node,omitempty"`
+	// Unschedulable is the message of the scheduler for the Pods it couldn't schedule
+	Unschedulable string          `json:"unschedulable,omitempty"`
+	Verdict       string          `json:"verdict"`
+	Hints         []string        `json:"hints,omitempty"`
+	Events        []WorkloadEvent `json:"events,omitempty"`
+}
+
+// AutoscalerStatus reads the status ConfigMap of the cluster-autoscaler and the Karpenter NodePools and NodeClaims,
+// with the recent events of the autoscalers.
+// With a Pod, it explains why the Pod did or didn't trigger a scale-up from its scheduling condition and events.
+func (k *Kubernetes) AutoscalerStatus(ctx context.Context, podNamespace, podName string) (*AutoscalerStatus, error) {
+	ret := &AutoscalerStatus{}
+	var err error
+	if ret.ClusterAutoscaler, err = k.clusterAutoscalerStatus(ctx); err != nil {
+		ret.Warnings = append(ret.Warnings, fmt.Sprintf("unable to read the status of the cluster-autoscaler: %v", err))
+	}
+	if k.supportsGroupVersion(karpenterNodePoolGVR.GroupVersion().String()) {
+		if ret.Karpenter, err = k.karpenterStatus(ctx); err != nil {
+			ret.Warnings = append(ret.Warnings, fmt.Sprintf("unable to read the Karpenter NodePools and NodeClaims: %v", err))
+		}
+	}
+	if ret.ClusterAutoscaler == nil && ret.Karpenter == nil && len(ret.Warnings) == 0 {
+		return nil, fmt.Errorf("no node autoscaler is found: no %s ConfigMap in the %s namespaces and the Karpenter CRDs (%s) are not served",
+			ClusterAutoscalerStatusConfigMap, strings.Join(ClusterAutoscalerNamespaces, " and "), karpenterNodePoolGVR.GroupVersion())
+	}
+	if ret.Events, err = k.autoscalerEvents(ctx); err != nil {
+		ret.Warnings = append(ret.Warnings, fmt.Sprintf("unable to list the events of the autoscalers: %v", err))
+	}
+	if podName != "" {
+		if ret.Pod, err = k.autoscalerPod(ctx, k.NamespaceOrDefault(podNamespace), podName); err != nil {
+			return nil, err
+		}
+	}
+	ret.Summary = ret.summary()
+	return ret, nil
+}
+
+func (s *AutoscalerStatus) summary() string {
+	var parts []string
+	if ca := s.ClusterAutoscaler; ca != nil && ca.Raw != "" {
+		parts = append(parts, "cluster-autoscaler in namespace "+ca.Namespace)
+	} else if ca != nil {
+		parts = append(parts, fmt.Sprintf("cluster-autoscaler %s (%s) in namespace %s, scale-up %s, scale-down %s, %d node groups",
+			ca.Status, ca.Health, ca.Namespace, ca.ScaleUp, ca.ScaleDown, len(ca.NodeGroups)))
+	}
+	if s.Karpenter != nil {
+		pending := 0
+		for _, nodeClaim := range s.Karpenter.NodeClaims {
+			if nodeClaim.Status != "Initialized" {
+				pending++
+			}
+		}
+		parts = append(parts, fmt.Sprintf("Karpenter %d NodePools, %d NodeClaims (%d not initialized)", len(s.Karpenter.NodePools), len(s.Karpenter.NodeClaims), pending))
+	}
+	parts = append(parts, fmt.Sprintf("%d recent autoscaler events", len(s.Events)))
+	ret := strings.Join(parts, "; ")
+	if s.Pod != nil {
+		ret += fmt.Sprintf("; Pod %s/%s: %s", s.Pod.Namespace, s.Pod.Name, s.Pod.Verdict)
+	}
+	return ret
+}
+
+// clusterAutoscalerStatus reads the status ConfigMap of the cluster-autoscaler, nil if there's none
+func (k *Kubernetes) clusterAutoscalerStatus(ctx context.Context) (*ClusterAutoscalerStatus, error) {
+	for _, namespace := range ClusterAutoscalerNamespaces {
+		cm, err := k.AccessControlClientset().CoreV1().ConfigMaps(namespace).Get(ctx, ClusterAutoscalerStatusConfigMap, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return parseClusterAutoscalerStatus(namespace, cm.Data["status"]), nil
+	}
+	return nil, nil
+}
+
+// clusterAutoscalerStatusYaml is the YAML status of the cluster-autoscaler (1.30 and later)
+type clusterAutoscalerStatusYaml struct {
+	Time             string `json:"time"`
+	AutoscalerStatus string `json:"autoscalerStatus"`
+	ClusterWide      struct {
+		Health struct {
+			Status string `json:"status"`
+		} `json:"health"`
+		ScaleUp struct {
+			Status string `json:"status"`
+		} `json:"scaleUp"`
+		ScaleDown struct {
+			Status     string `json:"status"`
+			Candidates int    `json:"candidates"`
+		} `json:"scaleDown"`
+	} `json:"clusterWide"`
+	NodeGroups []struct {
+		Name   string `json:"name"`
+		Health struct {
+			Status     string `json:"status"`
+			NodeCounts struct {
+				Registered struct {
+					Total int `json:"total"`
+					Ready int `json:"ready"`
+				} `json:"registered"`
+			} `json:"nodeCounts"`
+			CloudProviderTarget int `json:"cloudProviderTarget"`
+			MinSize             int `json:"minSize"`
+			MaxSize             int `json:"maxSize"`
+		} `json:"health"`
+		ScaleUp struct {
+			Status      string `json:"status"`
+			BackoffInfo struct {
+				ErrorCode    string `json:"errorCode"`
+				ErrorMessage string `json:"errorMessage"`
+			} `json:"backoffInfo"`
+		} `json:"scaleUp"`
+		ScaleDown struct {
+			Status string `json:"status"`
+		} `json:"scaleDown"`
+	} `json:"nodeGroups"`
+}
+
+// parseClusterAutoscalerStatus parses the YAML status of the cluster-autoscaler, the text status of the older
+// versions is returned as is
+func parseClusterAutoscalerStatus(namespace, status string) *ClusterAutoscalerStatus {
+	ret := &ClusterAutoscalerStatus{Namespace: namespace}
+	parsed := &clusterAutoscalerStatusYaml{}
+	if err := yaml.Unmarshal([]byte(status), parsed); err != nil || parsed.AutoscalerStatus == "" {
+		ret.Raw = strings.TrimSpace(status)
+		return ret
+	}
+	ret.Time, ret.Status = parsed.Time, parsed.AutoscalerStatus
+	ret.Health = parsed.ClusterWide.Health.Status
+	ret.ScaleUp = parsed.ClusterWide.ScaleUp.Status
+	ret.ScaleDown = parsed.ClusterWide.ScaleDown.Status
+	ret.ScaleDownCandidates = parsed.ClusterWide.ScaleDown.Candidates
+	for _, group := range parsed.NodeGroups {
+		nodeGroup := ClusterAutoscalerNodeGroup{
+			Name:       group.Name,
+			Health:     group.Health.Status,
+			Ready:      group.Health.NodeCounts.Registered.Ready,
+			Registered: group.Health.NodeCounts.Registered.Total,
+			Target:     group.Health.CloudProviderTarget,
+			MinSize:    group.Health.MinSize,
+			MaxSize:    group.Health.MaxSize,
+			ScaleUp:    group.ScaleUp.Status,
+			ScaleDown:  group.ScaleDown.Status,
+		}
+		if backoff := group.ScaleUp.BackoffInfo; backoff.ErrorCode != "" {
+			nodeGroup.Backoff = strings.TrimSuffix(backoff.ErrorCode+": "+backoff.ErrorMessage, ": ")
+		}
+		ret.NodeGroups = append(ret.NodeGroups, nodeGroup)
+	}
+	return ret
+}
+
+// karpenterStatus reads the Karpenter NodePools and NodeClaims
+func (k *Kubernetes) karpenterStatus(ctx context.Context) (*KarpenterStatus, error) {
+	nodePools, err := k.AccessControlClientset().DynamicClient().Resource(karpenterNodePoolGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodeClaims, err := k.AccessControlClientset().DynamicClient().Resource(karpenterNodeClaimGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := &KarpenterStatus{NodePools: []KarpenterNodePool{}, NodeClaims: []KarpenterNodeClaim{}}
+	for _, obj := range nodePools.Items {
+		nodePool := KarpenterNodePool{Name: obj.GetName(), Ready: string(v1.ConditionUnknown)}
+		nodePool.Resources, _, _ = unstructured.NestedStringMap(obj.Object, "status", "resources")
+		nodePool.Limits, _, _ = unstructured.NestedStringMap(obj.Object, "spec", "limits")
+		nodePool.Consolidation, _, _ = unstructured.NestedString(obj.Object, "spec", "disruption", "consolidationPolicy")
+		if after, _, _ := unstructured.NestedString(obj.Object, "spec", "disruption", "consolidateAfter"); after != "" && nodePool.Consolidation != "" {
+			nodePool.Consolidation += " after " + after
+		}
+		if ready := karpenterCondition(&obj, "Ready"); ready != nil {
+			nodePool.Ready = ready["status"]
+			if nodePool.Ready != string(v1.ConditionTrue) {
+				nodePool.Message = ready["message"]
+			}
+		}
+		ret.NodePools = append(ret.NodePools, nodePool)
+	}
+	sort.Slice(nodeClaims.Items, func(i, j int) bool { return nodeClaims.Items[i].GetName() < nodeClaims.Items[j].GetName() })
+	for i := range nodeClaims.Items {
+		if len(ret.NodeClaims) >= AutoscalerMaxNodeClaims {
+			ret.Truncated = true
+			break
+		}
+		ret.NodeClaims = append(ret.NodeClaims, karpenterNodeClaim(&nodeClaims.Items[i]))
+	}
+	return ret, nil
+}
+
+func karpenterNodeClaim(obj *unstructured.Unstructured) KarpenterNodeClaim {
+	labels := obj.GetLabels()
+	nodeClaim := KarpenterNodeClaim{
+		Name:         obj.GetName(),
+		NodePool:     labels["karpenter.sh/nodepool"],
+		InstanceType: labels[v1.LabelInstanceTypeStable],
+		CapacityType: labels["karpenter.sh/capacity-type"],
+		Zone:         labels[v1.LabelTopologyZone],
+		Status:       "Pending",
+	}
+	nodeClaim.Node, _, _ = unstructured.NestedString(obj.Object, "status", "nodeName")
+	// The lifecycle of a NodeClaim: the instance is launched, its node registers and is initialized
+	for _, step := range []string{"Launched", "Registered", "Initialized"} {
+		condition := karpenterCondition(obj, step)
+		if condition == nil || condition["status"] != string(v1.ConditionTrue) {
+			if condition != nil {
+				nodeClaim.Message = strings.TrimPrefix(condition["reason"]+": "+condition["message"], ": ")
+			}
+			break
+		}
+		nodeClaim.Status = step
+	}
+	if drifted := karpenterCondition(obj, "Drifted"); drifted != nil && drifted["status"] == string(v1.ConditionTrue) {
+		nodeClaim.Status, nodeClaim.Message = "Drifted", drifted["message"]
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		nodeClaim.Status = "Terminating"
+	}
+	return nodeClaim
+}
+
+// karpenterCondition returns the condition of the type as a string map, nil if missing
+func karpenterCondition(obj *unstructured.Unstructured, conditionType string) map[string]string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, _ := c.(map[string]any)
+		if t, _, _ := unstructured.NestedString(condition, "type"); t != conditionType {
+			continue
+		}
+		ret := map[string]string{}
+		for _, field := range []string{"status", "reason", "message"} {
+			ret[field], _, _ = unstructured.NestedString(condition, field)
+		}
+		return ret
+	}
+	return nil
+}
+
+// autoscalerEvents returns the most recent events of the cluster-autoscaler and Karpenter in all namespaces
+func (k *Kubernetes) autoscalerEvents(ctx context.Context) ([]WorkloadEvent, error) {
+	var events []v1.Event
+	objects := map[string]bool{}
+	for _, source := range []string{autoscalerSourceClusterAutoscaler, autoscalerSourceKarpenter} {
+		list, err := k.AccessControlClientset().CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: fields.Set{"source": source}.String()})
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range list.Items {
+			if autoscalerEventSource(&event) == source {
+				events = append(events, event)
+				objects[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name] = true
+			}
+		}
+	}
+	return workloadEvents(events, objects, AutoscalerMaxEvents), nil
+}
+
+// autoscalerEventSource returns the component that emitted the event
+func autoscalerEventSource(event *v1.Event) string {
+	if event.ReportingController != "" {
+		return event.ReportingController
+	}
+	return event.Source.Component
+}
+
+// autoscalerPod explains why the Pod did or didn't trigger a scale-up
+func (k *Kubernetes) autoscalerPod(ctx context.Context, namespace, name string) (*AutoscalerPod, error) {
+	pod, err := k.AccessControlClientset().CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := &AutoscalerPod{Namespace: namespace, Name: name, Phase: string(pod.Status.Phase), Node: pod.Spec.NodeName}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse {
+			ret.Unschedulable = strings.TrimSpace(condition.Message)
+		}
+	}
+	if pod.Spec.NodeName != "" {
+		ret.Verdict = "the Pod is scheduled on node " + pod.Spec.NodeName + ", it doesn't need a scale-up"
+		return ret, nil
+	}
+	list, err := k.AccessControlClientset().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": name}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var events []v1.Event
+	for _, event := range list.Items {
+		if source := autoscalerEventSource(&event); source == autoscalerSourceClusterAutoscaler || source == autoscalerSourceKarpenter {
+			events = append(events, event)
+		}
+	}
+	ret.Events = workloadEvents(events, map[string]bool{"Pod/" + name: true}, AutoscalerMaxEvents)
+	if len(ret.Events) == 0 {
+		ret.Verdict = "no autoscaler decision was recorded for the Pod"
+		ret.Hints = append(ret.Hints,
+			"the autoscaler may not be running or may not watch the Pod (e.g. the Pod uses another scheduler)",
+			fmt.Sprintf("the Pod may be too recent, it's pending since %s", pod.CreationTimestamp.UTC().Format(time.RFC3339)))
+		if ret.Unschedulable == "" {
+			ret.Hints = append(ret.Hints, "the scheduler didn't report the Pod as unschedulable, it may still be scheduling it")
+		}
+		return ret, nil
+	}
+	latest := ret.Events[0]
+	switch latest.Reason {
+	case "TriggeredScaleUp":
+		ret.Verdict = "the Pod triggered a scale-up, the new node may still be provisioning: " + latest.Message
+	case "Nominated":
+		ret.Verdict = "Karpenter nominated a node for the Pod, the node may still be provisioning: " + latest.Message
+	case "FailedScheduling":
+		ret.Verdict = "Karpenter couldn't provision a node for the Pod: " + latest.Message
+	case "NotTriggerScaleUp":
+		ret.Verdict = "the cluster-autoscaler didn't scale up: " + latest.Message
+	default:
+		ret.Verdict = fmt.Sprintf("%s (%s): %s", latest.Reason, latest.Object, latest.Message)
+	}
+	for _, hint := range autoscalerHints {
+		if strings.Contains(latest.Message, hint.message) {
+			ret.Hints = append(ret.Hints, hint.hint)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,278 @@
+package kubernetes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+const autoscalerStatusYaml = `time: 2026-10-18 10:00:00.000000000 +0000 UTC
+autoscalerStatus: Running
+clusterWide:
+  health:
+    status: Healthy
+  scaleUp:
+    status: NoActivity
+  scaleDown:
+    status: CandidatesPresent
+    candidates: 1
+nodeGroups:
+- name: workers-a
+  health:
+    status: Healthy
+    nodeCounts:
+      registered:
+        total: 3
+        ready: 3
+    cloudProviderTarget: 3
+    minSize: 1
+    maxSize: 3
+  scaleUp:
+    status: NoActivity
+  scaleDown:
+    status: CandidatesPresent
+- name: workers-gpu
+  health:
+    status: Unhealthy
+    cloudProviderTarget: 1
+    maxSize: 2
+  scaleUp:
+    status: Backoff
+    backoffInfo:
+      errorCode: QuotaExceeded
+      errorMessage: the GPU instance quota is exhausted
+  scaleDown:
+    status: NoCandidates
+`
+
+var autoscalerV1Resources = []string{
+	`{"name":"configmaps","singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["get","list","watch"]}`,
+	`{"name":"events","singularName":"event","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+}
+
+type AutoscalerSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+}
+
+func autoscalerEvent(namespace, kind, name, source, reason, message string, at time.Time) v1.Event {
+	return v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: namespace, Name: name + "." + reason},
+		InvolvedObject: v1.ObjectReference{Kind: kind, Namespace: namespace, Name: name},
+		Source:         v1.EventSource{Component: source},
+		Type:           v1.EventTypeNormal,
+		Reason:         reason,
+		Message:        message,
+		FirstTimestamp: metav1.NewTime(at),
+	}
+}
+
+func (s *AutoscalerSuite) SetupTest() {
+	now := time.Now()
+	nodePool := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "karpenter.sh/v1", "kind": "NodePool",
+		"metadata": map[string]any{"name": "default"},
+		"spec": map[string]any{
+			"limits":     map[string]any{"cpu": "100"},
+			"disruption": map[string]any{"consolidationPolicy": "WhenEmptyOrUnderutilized", "consolidateAfter": "1m"},
+		},
+		"status": map[string]any{
+			"resources":  map[string]any{"cpu": "8", "nodes": "2"},
+			"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}
+	nodeClaim := func(name string, conditions ...string) unstructured.Unstructured {
+		var c []any
+		for _, condition := range conditions {
+			c = append(c, map[string]any{"type": condition, "status": "True"})
+		}
+		return unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "karpenter.sh/v1", "kind": "NodeClaim",
+			"metadata": map[string]any{"name": name, "labels": map[string]any{
+				"karpenter.sh/nodepool":            "default",
+				"karpenter.sh/capacity-type":       "spot",
+				"node.kubernetes.io/instance-type": "m6i.large",
+			}},
+			"status": map[string]any{"conditions": c},
+		}}
+	}
+	nodeClaims := []unstructured.Unstructured{
+		nodeClaim("default-abcde", "Launched", "Registered", "Initialized"),
+		nodeClaim("default-fghij", "Launched"),
+	}
+	nodeClaims[0].Object["status"].(map[string]any)["nodeName"] = "ip-10-0-0-1"
+	events := map[string][]v1.Event{
+		"cluster-autoscaler": {
+			autoscalerEvent("kube-system", "ConfigMap", "cluster-autoscaler-status", "cluster-autoscaler", "ScaledUpGroup", "Scale-up: setting group workers-a size to 3", now.Add(-10*time.Minute)),
+			autoscalerEvent("default", "Pod", "web-1", "cluster-autoscaler", "NotTriggerScaleUp", "pod didn't trigger scale-up: 1 max node group size reached", now.Add(-time.Minute)),
+		},
+		"karpenter": {
+			autoscalerEvent("", "NodeClaim", "default-fghij", "karpenter", "Launched", "Status condition transitioned, Type: Launched", now.Add(-5*time.Minute)),
+		},
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: autoscalerV1Resources, Groups: []string{
+		`{"name":"karpenter.sh","versions":[{"groupVersion":"karpenter.sh/v1","version":"v1"}],"preferredVersion":{"groupVersion":"karpenter.sh/v1","version":"v1"}}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/karpenter.sh/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"karpenter.sh/v1","resources":[
+				{"name":"nodepools","singularName":"nodepool","namespaced":false,"kind":"NodePool","verbs":["get","list"]},
+				{"name":"nodeclaims","singularName":"nodeclaim","namespaced":false,"kind":"NodeClaim","verbs":["get","list"]}
+			]}`))
+		case "/apis/karpenter.sh/v1/nodepools":
+			list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "karpenter.sh/v1", "kind": "NodePoolList"}}
+			list.Items = []unstructured.Unstructured{nodePool}
+			test.WriteObject(w, list)
+		case "/apis/karpenter.sh/v1/nodeclaims":
+			list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "karpenter.sh/v1", "kind": "NodeClaimList"}}
+			list.Items = nodeClaims
+			test.WriteObject(w, list)
+		case "/api/v1/namespaces/kube-system/configmaps/cluster-autoscaler-status":
+			test.WriteObject(w, &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "cluster-autoscaler-status"},
+				Data:       map[string]string{"status": autoscalerStatusYaml},
+			})
+		case "/api/v1/namespaces/openshift-machine-api/configmaps/cluster-autoscaler-status":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+		case "/api/v1/events":
+			source := req.URL.Query().Get("fieldSelector")[len("source="):]
+			test.WriteObject(w, &v1.EventList{Items: events[source]})
+		case "/api/v1/namespaces/default/events":
+			test.WriteObject(w, &v1.EventList{Items: []v1.Event{
+				events["cluster-autoscaler"][1],
+				autoscalerEvent("default", "Pod", "web-1", "default-scheduler", "FailedScheduling", "0/3 nodes are available", now),
+			}})
+		case "/api/v1/namespaces/default/pods/web-1":
+			test.WriteObject(w, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-1"},
+				Status: v1.PodStatus{Phase: v1.PodPending, Conditions: []v1.PodCondition{
+					{Type: v1.PodScheduled, Status: v1.ConditionFalse, Message: "0/3 nodes are available: 3 Insufficient cpu."},
+				}},
+			})
+		case "/api/v1/namespaces/default/pods/web-2":
+			test.WriteObject(w, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-2"},
+				Spec:       v1.PodSpec{NodeName: "ip-10-0-0-1"},
+				Status:     v1.PodStatus{Phase: v1.PodRunning},
+			})
+		}
+	}))
+}
+
+func (s *AutoscalerSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *AutoscalerSuite) derived() *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *AutoscalerSuite) TestAutoscalerStatus() {
+	k := s.derived()
+	result, err := k.AutoscalerStatus(s.T().Context(), "", "")
+	s.Require().NoError(err)
+	s.Run("returns the summary of the autoscalers", func() {
+		s.Equal("cluster-autoscaler Running (Healthy) in namespace kube-system, scale-up NoActivity, scale-down CandidatesPresent, 2 node groups; "+
+			"Karpenter 1 NodePools, 2 NodeClaims (1 not initialized); 3 recent autoscaler events", result.Summary)
+		s.Empty(result.Warnings)
+	})
+	s.Run("returns the node groups of the cluster-autoscaler with their backoff", func() {
+		s.Require().NotNil(result.ClusterAutoscaler)
+		s.Equal([]ClusterAutoscalerNodeGroup{
+			{Name: "workers-a", Health: "Healthy", Ready: 3, Registered: 3, Target: 3, MinSize: 1, MaxSize: 3, ScaleUp: "NoActivity", ScaleDown: "CandidatesPresent"},
+			{Name: "workers-gpu", Health: "Unhealthy", Target: 1, MaxSize: 2, ScaleUp: "Backoff", ScaleDown: "NoCandidates", Backoff: "QuotaExceeded: the GPU instance quota is exhausted"},
+		}, result.ClusterAutoscaler.NodeGroups)
+		s.Equal(1, result.ClusterAutoscaler.ScaleDownCandidates)
+	})
+	s.Run("returns the Karpenter NodePools", func() {
+		s.Require().NotNil(result.Karpenter)
+		s.Equal([]KarpenterNodePool{{
+			Name: "default", Ready: "True",
+			Resources:     map[string]string{"cpu": "8", "nodes": "2"},
+			Limits:        map[string]string{"cpu": "100"},
+			Consolidation: "WhenEmptyOrUnderutilized after 1m",
+		}}, result.Karpenter.NodePools)
+	})
+	s.Run("returns the lifecycle status of the Karpenter NodeClaims", func() {
+		s.Require().Len(result.Karpenter.NodeClaims, 2)
+		s.Equal(KarpenterNodeClaim{Name: "default-abcde", NodePool: "default", Node: "ip-10-0-0-1", InstanceType: "m6i.large", CapacityType: "spot", Status: "Initialized"},
+			result.Karpenter.NodeClaims[0])
+		s.Equal("Launched", result.Karpenter.NodeClaims[1].Status)
+	})
+	s.Run("returns the events of the autoscalers, newest first", func() {
+		s.Require().Len(result.Events, 3)
+		s.Equal("NotTriggerScaleUp", result.Events[0].Reason)
+		s.Equal("NodeClaim/default-fghij", result.Events[1].Object)
+		s.Equal("ScaledUpGroup", result.Events[2].Reason)
+	})
+}
+
+func (s *AutoscalerSuite) TestAutoscalerStatusPod() {
+	k := s.derived()
+	s.Run("explains why the pending Pod didn't trigger a scale-up", func() {
+		result, err := k.AutoscalerStatus(s.T().Context(), "default", "web-1")
+		s.Require().NoError(err)
+		s.Require().NotNil(result.Pod)
+		s.Equal("the cluster-autoscaler didn't scale up: pod didn't trigger scale-up: 1 max node group size reached", result.Pod.Verdict)
+		s.Equal([]string{"the node groups reached their maximum size, raise the maximum size of the node groups"}, result.Pod.Hints)
+		s.Equal("0/3 nodes are available: 3 Insufficient cpu.", result.Pod.Unschedulable)
+		s.Len(result.Pod.Events, 1, "the events of the scheduler are ignored")
+		s.Contains(result.Summary, "; Pod default/web-1: the cluster-autoscaler didn't scale up")
+	})
+	s.Run("reports the scheduled Pods", func() {
+		result, err := k.AutoscalerStatus(s.T().Context(), "default", "web-2")
+		s.Require().NoError(err)
+		s.Equal("the Pod is scheduled on node ip-10-0-0-1, it doesn't need a scale-up", result.Pod.Verdict)
+	})
+}
+
+func (s *AutoscalerSuite) TestAutoscalerStatusNoAutoscaler() {
+	s.mockServer.ResetHandlers()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: autoscalerV1Resources})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api", "/apis", "/api/v1", "/apis/apps/v1":
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+		}
+	}))
+	_, err := s.derived().AutoscalerStatus(s.T().Context(), "", "")
+	s.EqualError(err, "no node autoscaler is found: no cluster-autoscaler-status ConfigMap in the kube-system and openshift-machine-api namespaces and the Karpenter CRDs (karpenter.sh/v1) are not served")
+}
+
+func TestParseClusterAutoscalerStatus(t *testing.T) {
+	status := parseClusterAutoscalerStatus("kube-system", "Cluster-autoscaler status at 2024-01-01 10:00:00\nCluster-wide:\n  Health: Healthy\n")
+	if status.Raw != "Cluster-autoscaler status at 2024-01-01 10:00:00\nCluster-wide:\n  Health: Healthy" {
+		t.Errorf("expected the text status to be returned as is, got %q", status.Raw)
+	}
+	if status.Status != "" || len(status.NodeGroups) != 0 {
+		t.Errorf("expected no parsed status for the text status, got %+v", status)
+	}
+}
+
+func TestAutoscaler(t *testing.T) {
+	suite.Run(t, new(AutoscalerSuite))
+}
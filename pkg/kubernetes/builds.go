@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	buildGVK        = schema.GroupVersionKind{Group: "build.openshift.io", Version: "v1", Kind: "Build"}
+	buildConfigGVK  = schema.GroupVersionKind{Group: "build.openshift.io", Version: "v1", Kind: "BuildConfig"}
+	buildRequestGVK = schema.GroupVersionKind{Group: "build.openshift.io", Version: "v1", Kind: "BuildRequest"}
+)
+
+// BuildsStart triggers a new build for the provided BuildConfig (equivalent to `oc start-build`)
+func (k *Kubernetes) BuildsStart(ctx context.Context, namespace, buildConfig string) (*unstructured.Unstructured, error) {
+	gvr, err := k.resourceFor(&buildConfigGVK)
+	if err != nil {
+		return nil, err
+	}
+	buildRequest := &unstructured.Unstructured{Object: map[string]any{}}
+	buildRequest.SetGroupVersionKind(buildRequestGVK)
+	buildRequest.SetName(buildConfig)
+	return k.AccessControlClientset().DynamicClient().Resource(*gvr).Namespace(k.NamespaceOrDefault(namespace)).
+		Create(ctx, buildRequest, metav1.CreateOptions{}, "instantiate")
+}
+
+// BuildsLatest returns the name of the latest build started for the provided BuildConfig
+func (k *Kubernetes) BuildsLatest(ctx context.Context, namespace, buildConfig string) (string, error) {
+	bc, err := k.ResourcesGet(ctx, &buildConfigGVK, namespace, buildConfig)
+	if err != nil {
+		return "", err
+	}
+	lastVersion, _, _ := unstructured.NestedInt64(bc.Object, "status", "lastVersion")
+	if lastVersion == 0 {
+		return "", fmt.Errorf("no builds have been started for build config %s", buildConfig)
+	}
+	return fmt.Sprintf("%s-%d", buildConfig, lastVersion), nil
+}
+
+// BuildsLog returns the last tail lines of the logs of the provided build
+func (k *Kubernetes) BuildsLog(ctx context.Context, namespace, name string, tail int64) (string, error) {
+	gvr, err := k.resourceFor(&buildGVK)
+	if err != nil {
+		return "", err
+	}
+	req := k.AccessControlClientset().CoreV1().RESTClient().
+		Get().
+		AbsPath("apis", gvr.Group, gvr.Version, "namespaces", k.NamespaceOrDefault(namespace), gvr.Resource, name, "log")
+	// Default to DefaultTailLines lines when not specified
+	if tail <= 0 {
+		tail = DefaultTailLines
+	}
+	req.Param("tailLines", fmt.Sprintf("%d", tail))
+	rawData, err := req.Do(ctx).Raw()
+	if err != nil {
+		return "", err
+	}
+	return string(rawData), nil
+}
@@ -0,0 +1,142 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CertificateSigningRequestPending is the status of the CSRs that were neither approved nor denied
+	CertificateSigningRequestPending = "Pending"
+	// CertificateSigningRequestIssued is appended to the status of the approved CSRs with a signed certificate
+	CertificateSigningRequestIssued = "Issued"
+)
+
+// CertificateSigningRequest is the view of a Kubernetes CertificateSigningRequest returned by the certificates tools
+type CertificateSigningRequest struct {
+	Name              string `json:"name"`
+	CreationTimestamp string `json:"creationTimestamp,omitempty"`
+	// Status is Pending, Approved, Denied or Failed, followed by ",Issued" when the certificate was signed (same as kubectl)
+	Status     string   `json:"status"`
+	SignerName string   `json:"signerName"`
+	Requester  string   `json:"requester,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	Usages     []string `json:"usages,omitempty"`
+	// Subject is the subject of the certificate request, e.g. CN=system:node:worker-1,O=system:nodes for kubelets
+	Subject     string   `json:"subject,omitempty"`
+	DNSNames    []string `json:"dnsNames,omitempty"`
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+	// ExpirationSeconds is the requested duration of validity of the certificate
+	ExpirationSeconds *int32 `json:"expirationSeconds,omitempty"`
+	// Conditions are the messages of the Approved, Denied and Failed conditions
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// CertificatesList returns the CertificateSigningRequests of the cluster, only the pending ones unless all is true
+func (k *Kubernetes) CertificatesList(ctx context.Context, all bool) ([]CertificateSigningRequest, error) {
+	csrs, err := k.AccessControlClientset().CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]CertificateSigningRequest, 0, len(csrs.Items))
+	for _, csr := range csrs.Items {
+		view := newCertificateSigningRequest(&csr)
+		if all || view.Status == CertificateSigningRequestPending {
+			ret = append(ret, *view)
+		}
+	}
+	return ret, nil
+}
+
+// CertificatesApprove approves (or denies if approve is false) the pending CertificateSigningRequest with the
+// provided name, the same way kubectl certificate approve/deny does.
+// CSRs that were already approved or denied are rejected, approving a denied CSR (or vice versa) is not allowed
+// by the API server.
+func (k *Kubernetes) CertificatesApprove(ctx context.Context, name string, approve bool, message string) (*CertificateSigningRequest, error) {
+	client := k.AccessControlClientset().CertificatesV1().CertificateSigningRequests()
+	csr, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if status := certificateSigningRequestStatus(csr); status != CertificateSigningRequestPending {
+		return nil, fmt.Errorf("certificate signing request is not pending (status: %s)", status)
+	}
+	condition := certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateApproved,
+		Status:         v1.ConditionTrue,
+		Reason:         "KubernetesMCPServerApprove",
+		Message:        "This CSR was approved by the Kubernetes MCP server",
+		LastUpdateTime: metav1.Now(),
+	}
+	if !approve {
+		condition.Type, condition.Reason, condition.Message = certificatesv1.CertificateDenied,
+			"KubernetesMCPServerDeny", "This CSR was denied by the Kubernetes MCP server"
+	}
+	if message != "" {
+		condition.Message = message
+	}
+	csr.Status.Conditions = append(csr.Status.Conditions, condition)
+	updated, err := client.UpdateApproval(ctx, name, csr, metav1.UpdateOptions{DryRun: dryRun(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	return newCertificateSigningRequest(updated), nil
+}
+
+func newCertificateSigningRequest(csr *certificatesv1.CertificateSigningRequest) *CertificateSigningRequest {
+	ret := &CertificateSigningRequest{
+		Name:              csr.Name,
+		Status:            certificateSigningRequestStatus(csr),
+		SignerName:        csr.Spec.SignerName,
+		Requester:         csr.Spec.Username,
+		Groups:            csr.Spec.Groups,
+		ExpirationSeconds: csr.Spec.ExpirationSeconds,
+	}
+	if !csr.CreationTimestamp.IsZero() {
+		ret.CreationTimestamp = csr.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	for _, usage := range csr.Spec.Usages {
+		ret.Usages = append(ret.Usages, string(usage))
+	}
+	for _, condition := range csr.Status.Conditions {
+		ret.Conditions = append(ret.Conditions, fmt.Sprintf("%s: %s %s", condition.Type, condition.Reason, condition.Message))
+	}
+	if block, _ := pem.Decode(csr.Spec.Request); block != nil {
+		if request, err := x509.ParseCertificateRequest(block.Bytes); err == nil {
+			ret.Subject = request.Subject.String()
+			ret.DNSNames = request.DNSNames
+			for _, ip := range request.IPAddresses {
+				ret.IPAddresses = append(ret.IPAddresses, ip.String())
+			}
+		}
+	}
+	return ret
+}
+
+// certificateSigningRequestStatus returns the status of the CSR as displayed by kubectl
+func certificateSigningRequestStatus(csr *certificatesv1.CertificateSigningRequest) string {
+	var statuses []string
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			if condition.Status == v1.ConditionTrue || condition.Status == "" {
+				statuses = append(statuses, string(condition.Type))
+			}
+		}
+	}
+	if len(statuses) == 0 {
+		statuses = append(statuses, CertificateSigningRequestPending)
+	}
+	if len(csr.Status.Certificate) > 0 {
+		statuses = append(statuses, CertificateSigningRequestIssued)
+	}
+	return strings.Join(statuses, ",")
+}
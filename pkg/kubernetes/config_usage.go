@@ -0,0 +1,410 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// Kinds of the objects supported by ConfigUsage
+const (
+	ConfigUsageKindConfigMap = "ConfigMap"
+	ConfigUsageKindSecret    = "Secret"
+)
+
+// Types of the references of a Pod to a ConfigMap or Secret
+const (
+	// ConfigReferenceVolume is a volume (or projected volume source) mounted by a container, the kubelet updates its files
+	ConfigReferenceVolume = "volume"
+	// ConfigReferenceSubPath is a volume mounted with subPath by a container, its files are never updated
+	ConfigReferenceSubPath = "subPath"
+	// ConfigReferenceEnvFrom imports all the keys as environment variables of a container
+	ConfigReferenceEnvFrom = "envFrom"
+	// ConfigReferenceEnv is an environment variable of a container with the value of a key
+	ConfigReferenceEnv = "env"
+	// ConfigReferenceImagePullSecret is an image pull secret of the Pod (Secret only)
+	ConfigReferenceImagePullSecret = "imagePullSecret"
+)
+
+// ConfigUsage maps the Pods using a ConfigMap or Secret and how they use it (volumes, environment variables), and
+// estimates whether the running Pods are stale relative to the current version of the object.
+// The version of the object loaded by a Pod isn't recorded by Kubernetes: the environment variables and the subPath
+// mounts of a container are stale when the object was updated (last managedFields time) after the container started,
+// the other volume mounts are updated by the kubelet.
+type ConfigUsage struct {
+	// Summary is a one line description, e.g. "ConfigMap app-config, used by 3 pods of 2 workloads, 1 pods are stale (web-5d9c-x2k4p)"
+	Summary   string `json:"summary"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Found is false when the object doesn't exist, the Pods referencing it are still reported
+	Found           bool   `json:"found"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// LastUpdate is the time of the last update of the object (its creation time if it was never updated)
+	LastUpdate string `json:"lastUpdate,omitempty"`
+	Immutable  bool   `json:"immutable,omitempty"`
+	// Keys are the keys of the object (the values are never reported)
+	Keys []string `json:"keys,omitempty"`
+	// Impact explains the effect of a change of the object on its consumers
+	Impact []string `json:"impact"`
+	// Workloads are the workloads (or the Pods without controller) using the object, Kind/name
+	Workloads []string         `json:"workloads,omitempty"`
+	Consumers []ConfigConsumer `json:"consumers"`
+	Warnings  []string         `json:"warnings,omitempty"`
+}
+
+// ConfigConsumer is a Pod using the ConfigMap or Secret
+type ConfigConsumer struct {
+	Pod      string `json:"pod"`
+	Workload string `json:"workload"`
+	Status   string `json:"status"`
+	// References are the ways the Pod uses the object
+	References []ConfigReference `json:"references"`
+	// Stale is true when a container reads the object at startup (env, envFrom, subPath) and started before its last update
+	Stale bool `json:"stale,omitempty"`
+	// StaleContainers are the stale containers with their start time
+	StaleContainers []string `json:"staleContainers,omitempty"`
+	// MissingKeys are the keys referenced by the Pod that don't exist in the object
+	MissingKeys []string `json:"missingKeys,omitempty"`
+}
+
+// ConfigReference is a reference of a Pod to the ConfigMap or Secret
+type ConfigReference struct {
+	// Type is one of ConfigReferenceVolume, ConfigReferenceSubPath, ConfigReferenceEnvFrom, ConfigReferenceEnv or ConfigReferenceImagePullSecret
+	Type      string `json:"type"`
+	Container string `json:"container,omitempty"`
+	// Target is the mount path of the volumes, the variable of env, or the prefix of envFrom
+	Target string `json:"target,omitempty"`
+	// Keys are the keys referenced (env key or volume items), all the keys if empty
+	Keys     []string `json:"keys,omitempty"`
+	Optional bool     `json:"optional,omitempty"`
+}
+
+// ConfigUsageInput are the data gathered from the cluster analyzed by AnalyzeConfigUsage
+type ConfigUsageInput struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// ConfigMap or Secret is the analyzed object, both nil if not found
+	ConfigMap *v1.ConfigMap
+	Secret    *v1.Secret
+	// Pods are the Pods of the namespace of the object
+	Pods []v1.Pod
+	// Workload returns the workload of a Pod (Kind/name)
+	Workload func(pod *v1.Pod) string
+}
+
+// ConfigUsage returns the ConfigUsage of the ConfigMap or Secret with the provided kind and name.
+// The ReplicaSets are resolved to the Deployments owning them, failures listing them are reported as warnings.
+func (k *Kubernetes) ConfigUsage(ctx context.Context, kind, namespace, name string) (*ConfigUsage, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	core := k.AccessControlClientset().CoreV1()
+	input := ConfigUsageInput{Kind: kind, Namespace: namespace, Name: name}
+	var err error
+	switch kind {
+	case ConfigUsageKindConfigMap:
+		input.ConfigMap, err = core.ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			input.ConfigMap, err = nil, nil
+		}
+	case ConfigUsageKindSecret:
+		input.Secret, err = core.Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			input.Secret, err = nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %s, supported kinds are %s and %s", kind, ConfigUsageKindConfigMap, ConfigUsageKindSecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pods, err := core.Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	input.Pods = pods.Items
+	var warnings []string
+	if input.Workload, err = k.podWorkloads(ctx, namespace); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	usage := AnalyzeConfigUsage(input)
+	usage.Warnings = append(warnings, usage.Warnings...)
+	return usage, nil
+}
+
+// AnalyzeConfigUsage maps the Pods that are not completed using the object and explains the impact of a change
+func AnalyzeConfigUsage(input ConfigUsageInput) *ConfigUsage {
+	usage := &ConfigUsage{
+		Kind:      input.Kind,
+		Namespace: input.Namespace,
+		Name:      input.Name,
+		Impact:    []string{},
+		Consumers: []ConfigConsumer{},
+	}
+	var object metav1.Object
+	switch {
+	case input.ConfigMap != nil:
+		object = input.ConfigMap
+		usage.Immutable = ptr.Deref(input.ConfigMap.Immutable, false)
+		for key := range input.ConfigMap.Data {
+			usage.Keys = append(usage.Keys, key)
+		}
+		for key := range input.ConfigMap.BinaryData {
+			usage.Keys = append(usage.Keys, key)
+		}
+	case input.Secret != nil:
+		object = input.Secret
+		usage.Immutable = ptr.Deref(input.Secret.Immutable, false)
+		for key := range input.Secret.Data {
+			usage.Keys = append(usage.Keys, key)
+		}
+	}
+	sort.Strings(usage.Keys)
+	var lastUpdate time.Time
+	if object != nil {
+		usage.Found = true
+		usage.ResourceVersion = object.GetResourceVersion()
+		lastUpdate = objectLastUpdate(object)
+		if !lastUpdate.IsZero() {
+			usage.LastUpdate = lastUpdate.UTC().Format(time.RFC3339)
+		}
+	}
+	workload := input.Workload
+	if workload == nil {
+		workload = func(pod *v1.Pod) string { return "Pod/" + pod.Name }
+	}
+	for i := range input.Pods {
+		pod := &input.Pods[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		references := configReferences(pod, input.Kind, input.Name)
+		if len(references) == 0 {
+			continue
+		}
+		consumer := ConfigConsumer{Pod: pod.Name, Workload: workload(pod), Status: PodStatusReason(pod), References: references}
+		if usage.Found {
+			consumer.MissingKeys = missingKeys(references, usage.Keys)
+			consumer.StaleContainers = staleContainers(pod, references, lastUpdate)
+			consumer.Stale = len(consumer.StaleContainers) > 0
+		}
+		usage.Consumers = append(usage.Consumers, consumer)
+		if !slices.Contains(usage.Workloads, consumer.Workload) {
+			usage.Workloads = append(usage.Workloads, consumer.Workload)
+		}
+	}
+	sort.Slice(usage.Consumers, func(i, j int) bool { return usage.Consumers[i].Pod < usage.Consumers[j].Pod })
+	sort.Strings(usage.Workloads)
+	usage.Impact = usage.impact()
+	usage.Summary = usage.summary()
+	return usage
+}
+
+// configReferences returns the references of the Pod to the ConfigMap or Secret
+func configReferences(pod *v1.Pod, kind, name string) []ConfigReference {
+	var references []ConfigReference
+	// volumes referencing the object by name, with the referenced keys and whether the reference is optional
+	volumes := map[string]ConfigReference{}
+	for _, volume := range pod.Spec.Volumes {
+		var sources []ConfigReference
+		switch {
+		case kind == ConfigUsageKindConfigMap && volume.ConfigMap != nil && volume.ConfigMap.Name == name:
+			sources = append(sources, ConfigReference{Keys: keyToPathKeys(volume.ConfigMap.Items), Optional: ptr.Deref(volume.ConfigMap.Optional, false)})
+		case kind == ConfigUsageKindSecret && volume.Secret != nil && volume.Secret.SecretName == name:
+			sources = append(sources, ConfigReference{Keys: keyToPathKeys(volume.Secret.Items), Optional: ptr.Deref(volume.Secret.Optional, false)})
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if kind == ConfigUsageKindConfigMap && source.ConfigMap != nil && source.ConfigMap.Name == name {
+					sources = append(sources, ConfigReference{Keys: keyToPathKeys(source.ConfigMap.Items), Optional: ptr.Deref(source.ConfigMap.Optional, false)})
+				}
+				if kind == ConfigUsageKindSecret && source.Secret != nil && source.Secret.Name == name {
+					sources = append(sources, ConfigReference{Keys: keyToPathKeys(source.Secret.Items), Optional: ptr.Deref(source.Secret.Optional, false)})
+				}
+			}
+		}
+		if len(sources) > 0 {
+			volumes[volume.Name] = sources[0]
+		}
+	}
+	containers := slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers)
+	for _, container := range pod.Spec.EphemeralContainers {
+		containers = append(containers, v1.Container(container.EphemeralContainerCommon))
+	}
+	for _, container := range containers {
+		for _, mount := range container.VolumeMounts {
+			volume, ok := volumes[mount.Name]
+			if !ok {
+				continue
+			}
+			reference := ConfigReference{Type: ConfigReferenceVolume, Container: container.Name, Target: mount.MountPath, Keys: volume.Keys, Optional: volume.Optional}
+			if mount.SubPath != "" || mount.SubPathExpr != "" {
+				reference.Type = ConfigReferenceSubPath
+			}
+			references = append(references, reference)
+		}
+		for _, envFrom := range container.EnvFrom {
+			if (kind == ConfigUsageKindConfigMap && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name) ||
+				(kind == ConfigUsageKindSecret && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name) {
+				optional := envFrom.ConfigMapRef != nil && ptr.Deref(envFrom.ConfigMapRef.Optional, false) || envFrom.SecretRef != nil && ptr.Deref(envFrom.SecretRef.Optional, false)
+				references = append(references, ConfigReference{Type: ConfigReferenceEnvFrom, Container: container.Name, Target: envFrom.Prefix, Optional: optional})
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; kind == ConfigUsageKindConfigMap && ref != nil && ref.Name == name {
+				references = append(references, ConfigReference{Type: ConfigReferenceEnv, Container: container.Name, Target: env.Name, Keys: []string{ref.Key}, Optional: ptr.Deref(ref.Optional, false)})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; kind == ConfigUsageKindSecret && ref != nil && ref.Name == name {
+				references = append(references, ConfigReference{Type: ConfigReferenceEnv, Container: container.Name, Target: env.Name, Keys: []string{ref.Key}, Optional: ptr.Deref(ref.Optional, false)})
+			}
+		}
+	}
+	if kind == ConfigUsageKindSecret && slices.ContainsFunc(pod.Spec.ImagePullSecrets, func(ref v1.LocalObjectReference) bool { return ref.Name == name }) {
+		references = append(references, ConfigReference{Type: ConfigReferenceImagePullSecret})
+	}
+	return references
+}
+
+// staleContainers returns the containers that read the object at startup (env, envFrom, subPath) and started before
+// its last update, with their start time
+func staleContainers(pod *v1.Pod, references []ConfigReference, lastUpdate time.Time) []string {
+	if lastUpdate.IsZero() {
+		return nil
+	}
+	var stale []string
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses) {
+		if status.State.Running == nil || !status.State.Running.StartedAt.Time.Before(lastUpdate) {
+			continue
+		}
+		if slices.ContainsFunc(references, func(reference ConfigReference) bool {
+			return reference.Container == status.Name && reference.Type != ConfigReferenceVolume
+		}) {
+			stale = append(stale, fmt.Sprintf("%s (started %s)", status.Name, status.State.Running.StartedAt.UTC().Format(time.RFC3339)))
+		}
+	}
+	return stale
+}
+
+// missingKeys returns the keys referenced by the non-optional references that don't exist in the object
+func missingKeys(references []ConfigReference, keys []string) []string {
+	var missing []string
+	for _, reference := range references {
+		for _, key := range reference.Keys {
+			if !reference.Optional && !slices.Contains(keys, key) && !slices.Contains(missing, key) {
+				missing = append(missing, key)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// objectLastUpdate returns the time of the last update of the object recorded in its managedFields, or its creation time
+func objectLastUpdate(object metav1.Object) time.Time {
+	lastUpdate := object.GetCreationTimestamp().Time
+	for _, entry := range object.GetManagedFields() {
+		if entry.Time != nil && entry.Time.After(lastUpdate) {
+			lastUpdate = entry.Time.Time
+		}
+	}
+	return lastUpdate
+}
+
+func keyToPathKeys(items []v1.KeyToPath) []string {
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
+
+func (u *ConfigUsage) impact() []string {
+	var impact []string
+	if !u.Found {
+		required := 0
+		for _, consumer := range u.Consumers {
+			if slices.ContainsFunc(consumer.References, func(reference ConfigReference) bool {
+				return !reference.Optional && reference.Type != ConfigReferenceImagePullSecret
+			}) {
+				required++
+			}
+		}
+		impact = append(impact, fmt.Sprintf("the %s doesn't exist, %d pods reference it without optional, their containers fail to start (CreateContainerConfigError) until it's created", u.Kind, required))
+		return impact
+	}
+	if len(u.Consumers) == 0 {
+		return append(impact, fmt.Sprintf("no running pod uses the %s, changing it has no effect on the running pods "+
+			"(workloads scaled to zero and the Jobs and CronJobs not running are not checked)", u.Kind))
+	}
+	if u.Immutable {
+		impact = append(impact, fmt.Sprintf("the %s is immutable, changing it requires deleting and recreating it, and restarting its consumers", u.Kind))
+	}
+	count := func(referenceTypes ...string) int {
+		pods := 0
+		for _, consumer := range u.Consumers {
+			if slices.ContainsFunc(consumer.References, func(reference ConfigReference) bool { return slices.Contains(referenceTypes, reference.Type) }) {
+				pods++
+			}
+		}
+		return pods
+	}
+	if pods := count(ConfigReferenceEnv, ConfigReferenceEnvFrom); pods > 0 {
+		impact = append(impact, fmt.Sprintf("%d pods read it as environment variables (env or envFrom), they only see the changes after a restart (e.g. rollout restart of their workloads)", pods))
+	}
+	if pods := count(ConfigReferenceSubPath); pods > 0 {
+		impact = append(impact, fmt.Sprintf("%d pods mount it with subPath, the mounted files are never updated, they only see the changes after a restart", pods))
+	}
+	if pods := count(ConfigReferenceVolume); pods > 0 {
+		impact = append(impact, fmt.Sprintf("%d pods mount it as a volume, the kubelet updates the files within its sync period (about a minute), the applications must reload them", pods))
+	}
+	if pods := count(ConfigReferenceImagePullSecret); pods > 0 {
+		impact = append(impact, fmt.Sprintf("%d pods use it as image pull secret, the changes apply to the next image pulls", pods))
+	}
+	stale, missing := 0, 0
+	for _, consumer := range u.Consumers {
+		if consumer.Stale {
+			stale++
+		}
+		if len(consumer.MissingKeys) > 0 {
+			missing++
+		}
+	}
+	if stale > 0 {
+		impact = append(impact, fmt.Sprintf("%d pods started before the last update of the %s (%s) and still use the previous values", stale, u.Kind, u.LastUpdate))
+	}
+	if missing > 0 {
+		impact = append(impact, fmt.Sprintf("%d pods reference keys that don't exist in the %s, their containers fail to start when they're restarted", missing, u.Kind))
+	}
+	return impact
+}
+
+func (u *ConfigUsage) summary() string {
+	summary := fmt.Sprintf("%s %s", u.Kind, u.Name)
+	if !u.Found {
+		summary += " not found"
+	}
+	if len(u.Consumers) == 0 {
+		return summary + ", not used by any running pod"
+	}
+	summary += fmt.Sprintf(", used by %d pods of %d workloads", len(u.Consumers), len(u.Workloads))
+	var stale []string
+	for _, consumer := range u.Consumers {
+		if consumer.Stale {
+			stale = append(stale, consumer.Pod)
+		}
+	}
+	if len(stale) > 0 {
+		summary += fmt.Sprintf(", %d pods are stale (%s)", len(stale), strings.Join(stale, ", "))
+	}
+	return summary
+}
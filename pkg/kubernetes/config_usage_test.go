@@ -0,0 +1,153 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type ConfigUsageSuite struct {
+	suite.Suite
+	created time.Time
+	updated time.Time
+}
+
+func (s *ConfigUsageSuite) SetupTest() {
+	s.created = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.updated = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *ConfigUsageSuite) configMap() *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default", Name: "app-config", ResourceVersion: "4242",
+			CreationTimestamp: metav1.NewTime(s.created),
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl-create", Operation: metav1.ManagedFieldsOperationUpdate, Time: ptr.To(metav1.NewTime(s.created))},
+				{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: ptr.To(metav1.NewTime(s.updated))},
+			},
+		},
+		Data: map[string]string{"log-level": "debug", "app.yaml": "port: 8080"},
+	}
+}
+
+func (s *ConfigUsageSuite) pod(name string, started time.Time, container v1.Container, volumes ...v1.Volume) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       v1.PodSpec{Containers: []v1.Container{container}, Volumes: volumes},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name: container.Name, Ready: true,
+				State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(started)}},
+			}},
+		},
+	}
+}
+
+func (s *ConfigUsageSuite) configVolume(items ...v1.KeyToPath) v1.Volume {
+	return v1.Volume{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+		LocalObjectReference: v1.LocalObjectReference{Name: "app-config"}, Items: items,
+	}}}
+}
+
+func (s *ConfigUsageSuite) TestConfigMapUsage() {
+	before, after := s.updated.Add(-time.Hour), s.updated.Add(time.Hour)
+	usage := AnalyzeConfigUsage(ConfigUsageInput{
+		Kind: ConfigUsageKindConfigMap, Namespace: "default", Name: "app-config",
+		ConfigMap: s.configMap(),
+		Pods: []v1.Pod{
+			s.pod("env-old", before, v1.Container{Name: "app", Env: []v1.EnvVar{{Name: "LOG_LEVEL", ValueFrom: &v1.EnvVarSource{
+				ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "app-config"}, Key: "log-level"},
+			}}}}),
+			s.pod("envfrom-new", after, v1.Container{Name: "app", EnvFrom: []v1.EnvFromSource{{
+				Prefix: "APP_", ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app-config"}},
+			}}}),
+			s.pod("volume-old", before, v1.Container{Name: "app", VolumeMounts: []v1.VolumeMount{{Name: "config", MountPath: "/etc/app"}}}, s.configVolume()),
+			s.pod("subpath-old", before, v1.Container{Name: "app", VolumeMounts: []v1.VolumeMount{{Name: "config", MountPath: "/etc/app/app.yaml", SubPath: "app.yaml"}}},
+				s.configVolume(v1.KeyToPath{Key: "app.yaml", Path: "app.yaml"}, v1.KeyToPath{Key: "missing.yaml", Path: "missing.yaml"})),
+			s.pod("unrelated", before, v1.Container{Name: "app", EnvFrom: []v1.EnvFromSource{{
+				ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "other"}},
+			}}}),
+		},
+		Workload: func(pod *v1.Pod) string { return "Deployment/" + pod.Name },
+	})
+	s.Run("reports the object version without values", func() {
+		s.True(usage.Found)
+		s.Equal("4242", usage.ResourceVersion)
+		s.Equal("2026-01-10T12:00:00Z", usage.LastUpdate)
+		s.Equal([]string{"app.yaml", "log-level"}, usage.Keys)
+	})
+	s.Run("maps the references of the consumers", func() {
+		s.Require().Len(usage.Consumers, 4)
+		s.Equal([]ConfigReference{{Type: ConfigReferenceEnv, Container: "app", Target: "LOG_LEVEL", Keys: []string{"log-level"}}}, usage.Consumers[0].References)
+		s.Equal([]ConfigReference{{Type: ConfigReferenceEnvFrom, Container: "app", Target: "APP_"}}, usage.Consumers[1].References)
+		s.Equal(ConfigReferenceSubPath, usage.Consumers[2].References[0].Type)
+		s.Equal(ConfigReferenceVolume, usage.Consumers[3].References[0].Type)
+		s.Equal([]string{"Deployment/env-old", "Deployment/envfrom-new", "Deployment/subpath-old", "Deployment/volume-old"}, usage.Workloads)
+	})
+	s.Run("only the containers reading it at startup before the update are stale", func() {
+		s.Equal([]string{"app (started 2026-01-10T11:00:00Z)"}, usage.Consumers[0].StaleContainers)
+		s.False(usage.Consumers[1].Stale, "started after the update")
+		s.True(usage.Consumers[2].Stale, "subPath mounts are never updated")
+		s.False(usage.Consumers[3].Stale, "volumes are updated by the kubelet")
+		s.Equal("ConfigMap app-config, used by 4 pods of 4 workloads, 2 pods are stale (env-old, subpath-old)", usage.Summary)
+	})
+	s.Run("reports the missing keys", func() {
+		s.Equal([]string{"missing.yaml"}, usage.Consumers[2].MissingKeys)
+	})
+	s.Run("explains the impact of a change", func() {
+		s.Equal([]string{
+			"2 pods read it as environment variables (env or envFrom), they only see the changes after a restart (e.g. rollout restart of their workloads)",
+			"1 pods mount it with subPath, the mounted files are never updated, they only see the changes after a restart",
+			"1 pods mount it as a volume, the kubelet updates the files within its sync period (about a minute), the applications must reload them",
+			"2 pods started before the last update of the ConfigMap (2026-01-10T12:00:00Z) and still use the previous values",
+			"1 pods reference keys that don't exist in the ConfigMap, their containers fail to start when they're restarted",
+		}, usage.Impact)
+	})
+}
+
+func (s *ConfigUsageSuite) TestSecretUsage() {
+	pod := s.pod("web", s.created, v1.Container{Name: "app", Env: []v1.EnvVar{{Name: "PASSWORD", ValueFrom: &v1.EnvVarSource{
+		SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "creds"}, Key: "password"},
+	}}}}, v1.Volume{Name: "projected", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+		{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "creds"}, Optional: ptr.To(true)}},
+	}}}})
+	pod.Spec.Containers[0].VolumeMounts = []v1.VolumeMount{{Name: "projected", MountPath: "/var/run/creds"}}
+	pod.Spec.ImagePullSecrets = []v1.LocalObjectReference{{Name: "creds"}}
+	s.Run("maps env, projected volumes, and image pull secrets", func() {
+		usage := AnalyzeConfigUsage(ConfigUsageInput{
+			Kind: ConfigUsageKindSecret, Namespace: "default", Name: "creds",
+			Secret: &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", CreationTimestamp: metav1.NewTime(s.created)},
+				Immutable: ptr.To(true), Data: map[string][]byte{"password": []byte("s3cr3t")}},
+			Pods: []v1.Pod{pod},
+		})
+		s.Require().Len(usage.Consumers, 1)
+		s.Equal("Pod/web", usage.Consumers[0].Workload)
+		s.Equal([]string{ConfigReferenceVolume, ConfigReferenceEnv, ConfigReferenceImagePullSecret}, []string{
+			usage.Consumers[0].References[0].Type, usage.Consumers[0].References[1].Type, usage.Consumers[0].References[2].Type,
+		})
+		s.True(usage.Consumers[0].References[0].Optional)
+		s.False(usage.Consumers[0].Stale, "never updated")
+		s.Equal("the Secret is immutable, changing it requires deleting and recreating it, and restarting its consumers", usage.Impact[0])
+	})
+	s.Run("missing secret", func() {
+		usage := AnalyzeConfigUsage(ConfigUsageInput{Kind: ConfigUsageKindSecret, Namespace: "default", Name: "creds", Pods: []v1.Pod{pod}})
+		s.False(usage.Found)
+		s.Equal("Secret creds not found, used by 1 pods of 1 workloads", usage.Summary)
+		s.Equal([]string{"the Secret doesn't exist, 1 pods reference it without optional, their containers fail to start (CreateContainerConfigError) until it's created"}, usage.Impact)
+	})
+	s.Run("unused secret", func() {
+		usage := AnalyzeConfigUsage(ConfigUsageInput{Kind: ConfigUsageKindSecret, Namespace: "default", Name: "unused", Secret: &v1.Secret{}, Pods: []v1.Pod{pod}})
+		s.Equal("Secret unused, not used by any running pod", usage.Summary)
+		s.Empty(usage.Consumers)
+	})
+}
+
+func TestConfigUsage(t *testing.T) {
+	suite.Run(t, new(ConfigUsageSuite))
+}
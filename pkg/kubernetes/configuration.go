@@ -1,9 +1,15 @@
 package kubernetes
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/clientcmd/api/latest"
 )
@@ -22,6 +28,23 @@ var InClusterConfig = func() (*rest.Config, error) {
 	return inClusterConfig, err
 }
 
+// inClusterNamespaceFile is the namespace of the service account mounted in the server pod
+// Exposed for testing
+var inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// inClusterNamespace returns the namespace of the server pod for in-cluster deployments, the POD_NAMESPACE environment
+// variable (e.g. set with the downward API) or the namespace of the mounted service account.
+// It's the default namespace of the tools and the namespace where the helper pods are created.
+func inClusterNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	if namespace, err := os.ReadFile(inClusterNamespaceFile); err == nil {
+		return strings.TrimSpace(string(namespace))
+	}
+	return ""
+}
+
 func IsInCluster(cfg *config.StaticConfig) bool {
 	// Even if running in-cluster, if a kubeconfig is provided, we consider it as out-of-cluster
 	if cfg != nil && cfg.KubeConfig != "" {
@@ -67,6 +90,32 @@ func (k *Kubernetes) ConfigurationContextsList() (map[string]string, error) {
 	return contexts, nil
 }
 
+// ConfigurationContextsSetDefault sets the current context of the configured kubeconfig file
+// (equivalent to `kubectl config use-context`).
+// The kubeconfig watcher picks up the change and the new context is used as the default target for the tools.
+func (k *Kubernetes) ConfigurationContextsSetDefault(context string) error {
+	staticConfig := k.AccessControlClientset().staticConfig
+	if IsInCluster(staticConfig) {
+		return errors.New("the default context can't be changed for in-cluster deployments")
+	}
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	if staticConfig.KubeConfig != "" {
+		pathOptions.LoadingRules.ExplicitPath = staticConfig.KubeConfig
+	}
+	cfg, err := pathOptions.GetStartingConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if _, ok := cfg.Contexts[context]; !ok {
+		return fmt.Errorf("context %s not found in kubeconfig", context)
+	}
+	cfg.CurrentContext = context
+	if err = clientcmd.ModifyConfig(pathOptions, *cfg, true); err != nil {
+		return fmt.Errorf("failed to update kubeconfig: %w", err)
+	}
+	return nil
+}
+
 // ConfigurationView returns the current kubeconfig content as a kubeconfig YAML
 // If minify is true, keeps only the current-context and the relevant pieces of the configuration for that context.
 // If minify is false, all contexts, clusters, auth-infos, and users are returned in the configuration.
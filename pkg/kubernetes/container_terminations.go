@@ -0,0 +1,204 @@
+package kubernetes
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Container termination states reported by ContainerTerminations
+const (
+	ContainerTerminationCurrent = "current"
+	ContainerTerminationLast    = "last"
+)
+
+// ContainerTermination is a container termination enriched with the explanation of its exit code (or signal)
+// and the likely causes
+type ContainerTermination struct {
+	// Pod is only set when the terminations of several Pods are reported together
+	Pod          string   `json:"pod,omitempty"`
+	Container    string   `json:"container"`
+	State        string   `json:"state"`
+	Reason       string   `json:"reason,omitempty"`
+	ExitCode     int32    `json:"exitCode"`
+	Signal       string   `json:"signal,omitempty"`
+	FinishedAt   string   `json:"finishedAt,omitempty"`
+	RestartCount int32    `json:"restartCount"`
+	Explanation  string   `json:"explanation"`
+	LikelyCauses []string `json:"likelyCauses,omitempty"`
+}
+
+// exitCodeKnowledge is a canned explanation of a container exit code or termination reason
+type exitCodeKnowledge struct {
+	explanation  string
+	likelyCauses []string
+}
+
+// terminationReasons are the termination reasons set by the kubelet or the container runtime that explain
+// the termination better than the exit code
+var terminationReasons = map[string]exitCodeKnowledge{
+	"OOMKilled": {
+		explanation: "The container was killed by the kernel OOM killer because it exceeded its memory limit (or the node ran out of memory)",
+		likelyCauses: []string{
+			"memory limit too low for the workload peak usage",
+			"memory leak in the application",
+			"runtime heap not sized to the container limit (e.g. JVM -Xmx, Node.js --max-old-space-size, GOMEMLIMIT)",
+			"node memory pressure when the container has no limit",
+		},
+	},
+	"ContainerCannotRun": {
+		explanation:  "The container runtime could not start the container process",
+		likelyCauses: []string{"invalid command or entrypoint", "missing binary or shared library in the image", "invalid volume mount or working directory"},
+	},
+	"StartError": {
+		explanation:  "The container failed during start, before the process could run",
+		likelyCauses: []string{"invalid command or entrypoint", "missing binary in the image", "permission denied on the entrypoint", "invalid mount or security context"},
+	},
+	"ContainerStatusUnknown": {
+		explanation:  "The container state could not be determined, usually because the node was lost or the Pod was evicted while it was running",
+		likelyCauses: []string{"node became NotReady or was deleted", "Pod eviction due to node pressure"},
+	},
+	"DeadlineExceeded": {
+		explanation:  "The container was stopped because the Pod exceeded its activeDeadlineSeconds",
+		likelyCauses: []string{"activeDeadlineSeconds too low for the Job or Pod", "hanging process"},
+	},
+}
+
+// exitCodes are the canned explanations of the well-known container exit codes
+var exitCodes = map[int32]exitCodeKnowledge{
+	0: {explanation: "The container process exited successfully"},
+	1: {
+		explanation:  "The application exited with a generic error",
+		likelyCauses: []string{"unhandled exception or application error, check the container logs", "missing or invalid configuration (environment variables, ConfigMaps, Secrets)", "unreachable dependency at startup"},
+	},
+	2: {
+		explanation:  "Misuse of a shell builtin or invalid command line arguments",
+		likelyCauses: []string{"invalid arguments in the container command or args", "syntax error in a shell script"},
+	},
+	126: {
+		explanation:  "The command was found but could not be executed",
+		likelyCauses: []string{"entrypoint is not executable (missing execute permission)", "volume mounted with noexec", "binary built for a different architecture"},
+	},
+	127: {
+		explanation:  "The command was not found",
+		likelyCauses: []string{"typo in the container command or entrypoint", "binary missing from the image or not in PATH", "shell (e.g. /bin/sh, bash) not available in a distroless image"},
+	},
+	128: {
+		explanation:  "The process exited with an invalid exit argument",
+		likelyCauses: []string{"application called exit with a non integer or out of range value"},
+	},
+	255: {
+		explanation:  "The process exited with status -1 (out of range), usually an application specific fatal error",
+		likelyCauses: []string{"application fatal error, check the container logs"},
+	},
+}
+
+// signals are the canned explanations of the exit codes of the processes terminated by a signal (128 + signal number)
+var signals = map[int32]struct {
+	name string
+	exitCodeKnowledge
+}{
+	1: {"SIGHUP", exitCodeKnowledge{
+		explanation:  "The process was terminated by SIGHUP (hangup)",
+		likelyCauses: []string{"controlling terminal closed", "process doesn't handle the reload signal"},
+	}},
+	2: {"SIGINT", exitCodeKnowledge{
+		explanation:  "The process was interrupted by SIGINT",
+		likelyCauses: []string{"interrupted from an interactive session (Ctrl+C)"},
+	}},
+	6: {"SIGABRT", exitCodeKnowledge{
+		explanation:  "The process aborted itself with SIGABRT",
+		likelyCauses: []string{"failed assertion or fatal runtime error, check the container logs", "heap corruption detected by the C library"},
+	}},
+	9: {"SIGKILL", exitCodeKnowledge{
+		explanation: "The process was forcibly killed with SIGKILL",
+		likelyCauses: []string{
+			"out of memory (check for OOMKilled on the container or the node)",
+			"liveness probe failures followed by a kill after the termination grace period",
+			"the process didn't stop within terminationGracePeriodSeconds after SIGTERM",
+			"Pod eviction due to node pressure",
+		},
+	}},
+	11: {"SIGSEGV", exitCodeKnowledge{
+		explanation:  "The process crashed with a segmentation fault (invalid memory access)",
+		likelyCauses: []string{"bug in native code or a native library", "binary incompatible with the base image libraries or CPU architecture", "stack overflow"},
+	}},
+	13: {"SIGPIPE", exitCodeKnowledge{
+		explanation:  "The process wrote to a pipe or socket with no reader",
+		likelyCauses: []string{"peer closed the connection", "piped command exited early"},
+	}},
+	15: {"SIGTERM", exitCodeKnowledge{
+		explanation: "The process was asked to stop gracefully with SIGTERM",
+		likelyCauses: []string{
+			"Pod deleted, rolled out, scaled down, or evicted",
+			"liveness probe failure caused a container restart",
+			"node drain or shutdown",
+		},
+	}},
+}
+
+// ExplainExitCode returns the canned explanation and likely causes of a container termination reason and exit code,
+// and the name of the signal that terminated the process (if any)
+func ExplainExitCode(reason string, exitCode int32) (explanation string, likelyCauses []string, signal string) {
+	if exitCode > 128 && exitCode < 128+65 {
+		if s, ok := signals[exitCode-128]; ok {
+			signal = s.name
+			explanation, likelyCauses = s.explanation, s.likelyCauses
+		} else {
+			signal = fmt.Sprintf("signal %d", exitCode-128)
+			explanation = fmt.Sprintf("The process was terminated by signal %d", exitCode-128)
+		}
+	} else if knowledge, ok := exitCodes[exitCode]; ok {
+		explanation, likelyCauses = knowledge.explanation, knowledge.likelyCauses
+	} else {
+		explanation = fmt.Sprintf("The application exited with code %d, its meaning is application specific, check the container logs", exitCode)
+	}
+	// The reason is more accurate than the exit code (e.g. OOMKilled processes exit with 137 like any SIGKILL)
+	if knowledge, ok := terminationReasons[reason]; ok {
+		explanation, likelyCauses = knowledge.explanation, knowledge.likelyCauses
+	}
+	return explanation, likelyCauses, signal
+}
+
+// ContainerTerminations returns the abnormal terminations (non-zero exit code or well-known termination reason) of the
+// containers of the Pod, both the current state and the last termination of the restarted containers
+func ContainerTerminations(pod *v1.Pod) []ContainerTermination {
+	terminations := make([]ContainerTermination, 0)
+	statuses := append(append(append([]v1.ContainerStatus{},
+		pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...), pod.Status.EphemeralContainerStatuses...)
+	for _, status := range statuses {
+		for _, s := range []struct {
+			state      string
+			terminated *v1.ContainerStateTerminated
+		}{
+			{ContainerTerminationCurrent, status.State.Terminated},
+			{ContainerTerminationLast, status.LastTerminationState.Terminated},
+		} {
+			if s.terminated == nil {
+				continue
+			}
+			if _, known := terminationReasons[s.terminated.Reason]; s.terminated.ExitCode == 0 && s.terminated.Signal == 0 && !known {
+				continue
+			}
+			termination := ContainerTermination{
+				Container:    status.Name,
+				State:        s.state,
+				Reason:       s.terminated.Reason,
+				ExitCode:     s.terminated.ExitCode,
+				RestartCount: status.RestartCount,
+			}
+			if !s.terminated.FinishedAt.IsZero() {
+				termination.FinishedAt = s.terminated.FinishedAt.UTC().Format(time.RFC3339)
+			}
+			exitCode := s.terminated.ExitCode
+			// Some runtimes report the signal separately from the exit code
+			if s.terminated.Signal > 0 && exitCode <= 128 {
+				exitCode = 128 + s.terminated.Signal
+			}
+			termination.Explanation, termination.LikelyCauses, termination.Signal = ExplainExitCode(s.terminated.Reason, exitCode)
+			terminations = append(terminations, termination)
+		}
+	}
+	return terminations
+}
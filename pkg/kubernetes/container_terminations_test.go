@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+)
+
+type ContainerTerminationsSuite struct {
+	suite.Suite
+}
+
+func (s *ContainerTerminationsSuite) TestExplainExitCode() {
+	s.Run("OOMKilled reason takes precedence over exit code", func() {
+		explanation, causes, signal := ExplainExitCode("OOMKilled", 137)
+		s.Contains(explanation, "OOM killer")
+		s.Contains(causes, "memory leak in the application")
+		s.Equal("SIGKILL", signal)
+	})
+	s.Run("137 without reason is SIGKILL", func() {
+		explanation, causes, signal := ExplainExitCode("Error", 137)
+		s.Equal("The process was forcibly killed with SIGKILL", explanation)
+		s.Contains(causes, "liveness probe failures followed by a kill after the termination grace period")
+		s.Equal("SIGKILL", signal)
+	})
+	s.Run("143 is SIGTERM", func() {
+		explanation, _, signal := ExplainExitCode("Error", 143)
+		s.Equal("The process was asked to stop gracefully with SIGTERM", explanation)
+		s.Equal("SIGTERM", signal)
+	})
+	s.Run("126 is command not executable", func() {
+		explanation, causes, signal := ExplainExitCode("Error", 126)
+		s.Equal("The command was found but could not be executed", explanation)
+		s.Contains(causes, "volume mounted with noexec")
+		s.Empty(signal)
+	})
+	s.Run("unknown signal", func() {
+		explanation, _, signal := ExplainExitCode("Error", 128+31)
+		s.Equal("The process was terminated by signal 31", explanation)
+		s.Equal("signal 31", signal)
+	})
+	s.Run("application specific exit code", func() {
+		explanation, causes, _ := ExplainExitCode("Error", 42)
+		s.Equal("The application exited with code 42, its meaning is application specific, check the container logs", explanation)
+		s.Empty(causes)
+	})
+}
+
+func (s *ContainerTerminationsSuite) TestContainerTerminations() {
+	pod := &v1.Pod{Status: v1.PodStatus{
+		InitContainerStatuses: []v1.ContainerStatus{
+			{Name: "init", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed", ExitCode: 0}}},
+		},
+		ContainerStatuses: []v1.ContainerStatus{
+			{
+				Name:                 "app",
+				RestartCount:         4,
+				State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			},
+			{
+				Name:  "sidecar",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", Signal: 15}},
+			},
+		},
+	}}
+	terminations := ContainerTerminations(pod)
+	s.Run("ignores successful terminations", func() {
+		s.Require().Len(terminations, 2)
+	})
+	s.Run("reports last termination of restarted containers", func() {
+		s.Equal("app", terminations[0].Container)
+		s.Equal(ContainerTerminationLast, terminations[0].State)
+		s.Equal(int32(4), terminations[0].RestartCount)
+		s.Equal("OOMKilled", terminations[0].Reason)
+		s.Contains(terminations[0].Explanation, "OOM killer")
+	})
+	s.Run("explains signal reported separately from the exit code", func() {
+		s.Equal("sidecar", terminations[1].Container)
+		s.Equal(ContainerTerminationCurrent, terminations[1].State)
+		s.Equal("SIGTERM", terminations[1].Signal)
+	})
+}
+
+func TestContainerTerminations(t *testing.T) {
+	suite.Run(t, new(ContainerTerminationsSuite))
+}
@@ -0,0 +1,223 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/metrics/pkg/apis/metrics"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// CostHoursPerMonth is the average number of hours in a month used for the monthly estimates (365 * 24 / 12)
+const CostHoursPerMonth = 730
+
+// CostReport estimates the cost of the namespaces from the resources requested by their Pods (and optionally the
+// resources they use) and the configured unit prices
+type CostReport struct {
+	// Summary is a one line description, e.g. "Estimated cost of 3 namespaces: 152.35 USD per month (requests)"
+	Summary  string     `json:"summary"`
+	Currency string     `json:"currency,omitempty"`
+	Prices   CostPrices `json:"prices"`
+	// Namespaces are sorted by the monthly cost of their requests, the most expensive first
+	Namespaces []NamespaceCost `json:"namespaces"`
+	Total      NamespaceCost   `json:"total"`
+	Warnings   []string        `json:"warnings,omitempty"`
+}
+
+// CostPrices are the configured unit prices the costs are estimated with
+type CostPrices struct {
+	CPUCoreHour   float64 `json:"cpuCoreHour"`
+	MemoryGiBHour float64 `json:"memoryGiBHour"`
+}
+
+// NamespaceCost is the estimated cost of the Pods of a namespace scheduled to a node and not completed
+type NamespaceCost struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pods      int    `json:"pods"`
+	// PodsWithoutRequests are the Pods that don't request CPU nor memory, their cost is not attributed
+	PodsWithoutRequests int     `json:"podsWithoutRequests,omitempty"`
+	CPURequestCores     float64 `json:"cpuRequestCores"`
+	MemoryRequestGiB    float64 `json:"memoryRequestGiB"`
+	HourlyCost          float64 `json:"hourlyCost"`
+	MonthlyCost         float64 `json:"monthlyCost"`
+	// The usage fields are reported when the usage is requested and the metrics API is available
+	CPUUsageCores    *float64 `json:"cpuUsageCores,omitempty"`
+	MemoryUsageGiB   *float64 `json:"memoryUsageGiB,omitempty"`
+	UsageMonthlyCost *float64 `json:"usageMonthlyCost,omitempty"`
+	// IdleMonthlyCost is the cost of the requested resources that are not used (requests above usage)
+	IdleMonthlyCost *float64 `json:"idleMonthlyCost,omitempty"`
+}
+
+type CostReportOptions struct {
+	// Namespace to estimate the cost of, all namespaces if empty
+	Namespace string
+	// Usage adds the current usage of the Pods (metrics API) to the report
+	Usage bool
+}
+
+// CostReport estimates the cost of the namespaces with the unit prices of the configuration (cost.cpu_core_hour and
+// cost.memory_gib_hour).
+// The usage is a point in time measure of the metrics API, failures retrieving it are reported as warnings.
+func (k *Kubernetes) CostReport(ctx context.Context, options CostReportOptions) (*CostReport, error) {
+	prices := k.AccessControlClientset().staticConfig.Cost
+	if prices.CPUCoreHour == 0 && prices.MemoryGiBHour == 0 {
+		return nil, errors.New("no unit price configured, set cost.cpu_core_hour and cost.memory_gib_hour in the configuration")
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods(options.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var podMetrics *metrics.PodMetricsList
+	var warnings []string
+	if options.Usage {
+		podMetrics, err = k.PodsTop(ctx, PodsTopOptions{Namespace: options.Namespace, AllNamespaces: options.Namespace == ""})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to get the usage of the pods: %v", err))
+		}
+	}
+	report := EstimateCost(prices, pods.Items, podMetrics)
+	report.Warnings = warnings
+	report.Summary = report.summary(options.Namespace)
+	return report, nil
+}
+
+// EstimateCost multiplies the requests of the Pods scheduled to a node and not completed (and the usage of the Pods if
+// the metrics are provided) by the unit prices, by namespace
+func EstimateCost(prices config.Cost, pods []v1.Pod, podMetrics *metrics.PodMetricsList) *CostReport {
+	report := &CostReport{
+		Currency:   prices.Currency,
+		Prices:     CostPrices{CPUCoreHour: prices.CPUCoreHour, MemoryGiBHour: prices.MemoryGiBHour},
+		Namespaces: []NamespaceCost{},
+	}
+	type usage struct{ cpu, memory resource.Quantity }
+	requests := map[string]*usage{}
+	counts := map[string]*NamespaceCost{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if _, ok := requests[pod.Namespace]; !ok {
+			requests[pod.Namespace] = &usage{}
+			counts[pod.Namespace] = &NamespaceCost{Namespace: pod.Namespace}
+		}
+		podRequests := podRequests(pod)
+		cpu, memory := podRequests[v1.ResourceCPU], podRequests[v1.ResourceMemory]
+		requests[pod.Namespace].cpu.Add(cpu)
+		requests[pod.Namespace].memory.Add(memory)
+		counts[pod.Namespace].Pods++
+		if cpu.IsZero() && memory.IsZero() {
+			counts[pod.Namespace].PodsWithoutRequests++
+		}
+	}
+	var usages map[string]*usage
+	if podMetrics != nil {
+		usages = map[string]*usage{}
+		for _, podMetric := range podMetrics.Items {
+			if _, ok := usages[podMetric.Namespace]; !ok {
+				usages[podMetric.Namespace] = &usage{}
+			}
+			for _, container := range podMetric.Containers {
+				usages[podMetric.Namespace].cpu.Add(container.Usage[v1.ResourceCPU])
+				usages[podMetric.Namespace].memory.Add(container.Usage[v1.ResourceMemory])
+			}
+		}
+	}
+	total := NamespaceCost{}
+	totalIdle := 0.0
+	var totalUsage *usage
+	if usages != nil {
+		totalUsage = &usage{}
+	}
+	for namespace, requested := range requests {
+		cost := *counts[namespace]
+		cost.setRequests(prices, cores(requested.cpu), gib(requested.memory))
+		if usages != nil {
+			used := usages[namespace]
+			if used == nil {
+				used = &usage{}
+			}
+			cost.setUsage(prices, cores(requested.cpu), gib(requested.memory), cores(used.cpu), gib(used.memory))
+			totalUsage.cpu.Add(used.cpu)
+			totalUsage.memory.Add(used.memory)
+			totalIdle += *cost.IdleMonthlyCost
+		}
+		report.Namespaces = append(report.Namespaces, cost)
+		total.Pods += cost.Pods
+		total.PodsWithoutRequests += cost.PodsWithoutRequests
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		if report.Namespaces[i].MonthlyCost != report.Namespaces[j].MonthlyCost {
+			return report.Namespaces[i].MonthlyCost > report.Namespaces[j].MonthlyCost
+		}
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+	var totalCPU, totalMemory resource.Quantity
+	for _, requested := range requests {
+		totalCPU.Add(requested.cpu)
+		totalMemory.Add(requested.memory)
+	}
+	total.setRequests(prices, cores(totalCPU), gib(totalMemory))
+	if totalUsage != nil {
+		total.setUsage(prices, cores(totalCPU), gib(totalMemory), cores(totalUsage.cpu), gib(totalUsage.memory))
+		// The idle cost of a namespace is not offset by the resources other namespaces use above their requests
+		total.IdleMonthlyCost = ptr.To(round(totalIdle, 2))
+	}
+	report.Total = total
+	return report
+}
+
+func (c *NamespaceCost) setRequests(prices config.Cost, cpuCores, memoryGiB float64) {
+	c.CPURequestCores = round(cpuCores, 3)
+	c.MemoryRequestGiB = round(memoryGiB, 3)
+	hourly := cpuCores*prices.CPUCoreHour + memoryGiB*prices.MemoryGiBHour
+	c.HourlyCost = round(hourly, 4)
+	c.MonthlyCost = round(hourly*CostHoursPerMonth, 2)
+}
+
+// setUsage sets the usage and the idle cost, the cost of the requests minus the usage, by resource so that the
+// resources used above their requests don't offset the idle ones
+func (c *NamespaceCost) setUsage(prices config.Cost, requestCPUCores, requestMemoryGiB, cpuCores, memoryGiB float64) {
+	c.CPUUsageCores = ptr.To(round(cpuCores, 3))
+	c.MemoryUsageGiB = ptr.To(round(memoryGiB, 3))
+	c.UsageMonthlyCost = ptr.To(round((cpuCores*prices.CPUCoreHour+memoryGiB*prices.MemoryGiBHour)*CostHoursPerMonth, 2))
+	idle := math.Max(requestCPUCores-cpuCores, 0)*prices.CPUCoreHour + math.Max(requestMemoryGiB-memoryGiB, 0)*prices.MemoryGiBHour
+	c.IdleMonthlyCost = ptr.To(round(idle*CostHoursPerMonth, 2))
+}
+
+func (r *CostReport) summary(namespace string) string {
+	scope := fmt.Sprintf("%d namespaces", len(r.Namespaces))
+	if namespace != "" {
+		scope = "namespace " + namespace
+	}
+	currency := ""
+	if r.Currency != "" {
+		currency = " " + r.Currency
+	}
+	summary := fmt.Sprintf("Estimated cost of %s: %.2f%s per month (requests)", scope, r.Total.MonthlyCost, currency)
+	if r.Total.UsageMonthlyCost != nil {
+		summary += fmt.Sprintf(", %.2f%s per month (usage), %.2f%s idle", *r.Total.UsageMonthlyCost, currency, *r.Total.IdleMonthlyCost, currency)
+	}
+	return summary
+}
+
+func cores(q resource.Quantity) float64 {
+	return float64(q.MilliValue()) / 1000
+}
+
+func gib(q resource.Quantity) float64 {
+	return q.AsApproximateFloat64() / (1 << 30)
+}
+
+func round(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,123 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/metrics/pkg/apis/metrics"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type CostSuite struct {
+	suite.Suite
+	prices config.Cost
+}
+
+func (s *CostSuite) SetupTest() {
+	s.prices = config.Cost{Currency: "USD", CPUCoreHour: 0.04, MemoryGiBHour: 0.005}
+}
+
+func costPod(namespace, name, cpu, memory string, phase v1.PodPhase) v1.Pod {
+	requests := v1.ResourceList{}
+	if cpu != "" {
+		requests[v1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		requests[v1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []v1.Container{{Name: "main", Resources: v1.ResourceRequirements{Requests: requests}}},
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func podMetrics(namespace, name, cpu, memory string) metrics.PodMetrics {
+	return metrics.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Containers: []metrics.ContainerMetrics{{Name: "main", Usage: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}},
+	}
+}
+
+func (s *CostSuite) TestEstimateCostFromRequests() {
+	pending := costPod("shop", "pending", "4", "8Gi", v1.PodPending)
+	pending.Spec.NodeName = ""
+	report := EstimateCost(s.prices, []v1.Pod{
+		costPod("shop", "web-1", "500m", "1Gi", v1.PodRunning),
+		costPod("shop", "web-2", "500m", "1Gi", v1.PodRunning),
+		costPod("shop", "sidecar-only", "", "", v1.PodRunning),
+		costPod("shop", "job", "8", "16Gi", v1.PodSucceeded),
+		pending,
+		costPod("batch", "worker", "2", "4Gi", v1.PodRunning),
+	}, nil)
+	s.Run("sorts the namespaces by monthly cost", func() {
+		s.Require().Len(report.Namespaces, 2)
+		s.Equal("batch", report.Namespaces[0].Namespace)
+		s.Equal("shop", report.Namespaces[1].Namespace)
+	})
+	s.Run("ignores the unscheduled and completed pods", func() {
+		s.Equal(3, report.Namespaces[1].Pods)
+		s.Equal(1, report.Namespaces[1].PodsWithoutRequests)
+		s.Equal(1.0, report.Namespaces[1].CPURequestCores)
+		s.Equal(2.0, report.Namespaces[1].MemoryRequestGiB)
+	})
+	s.Run("multiplies the requests by the unit prices", func() {
+		s.Equal(0.05, report.Namespaces[1].HourlyCost)
+		s.Equal(36.5, report.Namespaces[1].MonthlyCost)
+		s.Equal(0.1, report.Namespaces[0].HourlyCost)
+		s.Equal(73.0, report.Namespaces[0].MonthlyCost)
+	})
+	s.Run("totals the namespaces", func() {
+		s.Equal(4, report.Total.Pods)
+		s.Equal(3.0, report.Total.CPURequestCores)
+		s.Equal(109.5, report.Total.MonthlyCost)
+		s.Nil(report.Total.UsageMonthlyCost)
+	})
+	s.Run("summary", func() {
+		s.Equal("Estimated cost of 2 namespaces: 109.50 USD per month (requests)", report.summary(""))
+		s.Equal("Estimated cost of namespace shop: 109.50 USD per month (requests)", report.summary("shop"))
+	})
+}
+
+func (s *CostSuite) TestEstimateCostFromUsage() {
+	report := EstimateCost(s.prices, []v1.Pod{
+		costPod("shop", "web-1", "1", "2Gi", v1.PodRunning),
+		costPod("batch", "worker", "1", "1Gi", v1.PodRunning),
+	}, &metrics.PodMetricsList{Items: []metrics.PodMetrics{
+		podMetrics("shop", "web-1", "250m", "3Gi"),
+	}})
+	s.Require().Len(report.Namespaces, 2)
+	shop, batch := report.Namespaces[0], report.Namespaces[1]
+	s.Run("multiplies the usage by the unit prices", func() {
+		s.Equal(0.25, *shop.CPUUsageCores)
+		s.Equal(3.0, *shop.MemoryUsageGiB)
+		s.Equal(18.25, *shop.UsageMonthlyCost)
+	})
+	s.Run("memory used above its request doesn't offset the idle CPU", func() {
+		s.Equal(21.9, *shop.IdleMonthlyCost)
+	})
+	s.Run("namespaces without metrics are idle", func() {
+		s.Equal(0.0, *batch.UsageMonthlyCost)
+		s.Equal(batch.MonthlyCost, *batch.IdleMonthlyCost)
+	})
+	s.Run("total idle cost is the sum of the namespaces idle costs", func() {
+		s.Equal(54.75, *report.Total.IdleMonthlyCost)
+	})
+	s.Run("summary", func() {
+		s.Equal("Estimated cost of 2 namespaces: 69.35 USD per month (requests), 18.25 USD per month (usage), 54.75 USD idle", report.summary(""))
+	})
+}
+
+func TestCost(t *testing.T) {
+	suite.Run(t, new(CostSuite))
+}
@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// APIDeprecation describes a deprecated API version of a kind, the Kubernetes release that deprecated it, the one
+// that stopped serving it, and its replacement
+type APIDeprecation struct {
+	GroupVersionKind schema.GroupVersionKind
+	// DeprecatedIn is the Kubernetes minor release that deprecated the API version, e.g. 1.21
+	DeprecatedIn string
+	// RemovedIn is the Kubernetes minor release that stopped serving the API version, e.g. 1.25
+	RemovedIn string
+	// Replacement is the GroupVersionKind to migrate to, empty if the API was removed with no replacement
+	Replacement schema.GroupVersionKind
+}
+
+// APIVersion returns the apiVersion of the deprecated API, e.g. batch/v1beta1
+func (d APIDeprecation) APIVersion() string {
+	return d.GroupVersionKind.GroupVersion().String()
+}
+
+// DeprecatedBy returns whether the API version is deprecated in the provided Kubernetes version
+func (d APIDeprecation) DeprecatedBy(v *version.Version) bool {
+	return d.DeprecatedIn != "" && !v.LessThan(version.MustParseGeneric(d.DeprecatedIn))
+}
+
+// RemovedBy returns whether the API version is no longer served in the provided Kubernetes version
+func (d APIDeprecation) RemovedBy(v *version.Version) bool {
+	return d.RemovedIn != "" && !v.LessThan(version.MustParseGeneric(d.RemovedIn))
+}
+
+// APIDeprecations is the database of the deprecated and removed Kubernetes API versions of the kinds that are
+// persisted, from https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var APIDeprecations = []APIDeprecation{
+	// 1.16
+	deprecation("extensions", "v1beta1", "Deployment", "1.8", "1.16", "apps", "v1"),
+	deprecation("extensions", "v1beta1", "DaemonSet", "1.8", "1.16", "apps", "v1"),
+	deprecation("extensions", "v1beta1", "ReplicaSet", "1.8", "1.16", "apps", "v1"),
+	deprecation("extensions", "v1beta1", "NetworkPolicy", "1.9", "1.16", "networking.k8s.io", "v1"),
+	deprecation("extensions", "v1beta1", "PodSecurityPolicy", "1.10", "1.16", "policy", "v1beta1"),
+	deprecation("apps", "v1beta1", "Deployment", "1.9", "1.16", "apps", "v1"),
+	deprecation("apps", "v1beta1", "StatefulSet", "1.9", "1.16", "apps", "v1"),
+	deprecation("apps", "v1beta2", "Deployment", "1.9", "1.16", "apps", "v1"),
+	deprecation("apps", "v1beta2", "StatefulSet", "1.9", "1.16", "apps", "v1"),
+	deprecation("apps", "v1beta2", "DaemonSet", "1.9", "1.16", "apps", "v1"),
+	deprecation("apps", "v1beta2", "ReplicaSet", "1.9", "1.16", "apps", "v1"),
+	// 1.22
+	deprecation("extensions", "v1beta1", "Ingress", "1.14", "1.22", "networking.k8s.io", "v1"),
+	deprecation("networking.k8s.io", "v1beta1", "Ingress", "1.19", "1.22", "networking.k8s.io", "v1"),
+	deprecation("networking.k8s.io", "v1beta1", "IngressClass", "1.19", "1.22", "networking.k8s.io", "v1"),
+	deprecation("apiextensions.k8s.io", "v1beta1", "CustomResourceDefinition", "1.16", "1.22", "apiextensions.k8s.io", "v1"),
+	deprecation("admissionregistration.k8s.io", "v1beta1", "MutatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io", "v1"),
+	deprecation("admissionregistration.k8s.io", "v1beta1", "ValidatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io", "v1"),
+	deprecation("apiregistration.k8s.io", "v1beta1", "APIService", "1.19", "1.22", "apiregistration.k8s.io", "v1"),
+	deprecation("certificates.k8s.io", "v1beta1", "CertificateSigningRequest", "1.19", "1.22", "certificates.k8s.io", "v1"),
+	deprecation("coordination.k8s.io", "v1beta1", "Lease", "1.19", "1.22", "coordination.k8s.io", "v1"),
+	deprecation("rbac.authorization.k8s.io", "v1beta1", "ClusterRole", "1.17", "1.22", "rbac.authorization.k8s.io", "v1"),
+	deprecation("rbac.authorization.k8s.io", "v1beta1", "ClusterRoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io", "v1"),
+	deprecation("rbac.authorization.k8s.io", "v1beta1", "Role", "1.17", "1.22", "rbac.authorization.k8s.io", "v1"),
+	deprecation("rbac.authorization.k8s.io", "v1beta1", "RoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io", "v1"),
+	deprecation("scheduling.k8s.io", "v1beta1", "PriorityClass", "1.14", "1.22", "scheduling.k8s.io", "v1"),
+	deprecation("storage.k8s.io", "v1beta1", "CSIDriver", "1.19", "1.22", "storage.k8s.io", "v1"),
+	deprecation("storage.k8s.io", "v1beta1", "CSINode", "1.17", "1.22", "storage.k8s.io", "v1"),
+	deprecation("storage.k8s.io", "v1beta1", "StorageClass", "1.19", "1.22", "storage.k8s.io", "v1"),
+	deprecation("storage.k8s.io", "v1beta1", "VolumeAttachment", "1.19", "1.22", "storage.k8s.io", "v1"),
+	// 1.25
+	deprecation("batch", "v1beta1", "CronJob", "1.21", "1.25", "batch", "v1"),
+	deprecation("discovery.k8s.io", "v1beta1", "EndpointSlice", "1.21", "1.25", "discovery.k8s.io", "v1"),
+	deprecation("events.k8s.io", "v1beta1", "Event", "1.21", "1.25", "events.k8s.io", "v1"),
+	deprecation("autoscaling", "v2beta1", "HorizontalPodAutoscaler", "1.22", "1.25", "autoscaling", "v2"),
+	deprecation("policy", "v1beta1", "PodDisruptionBudget", "1.21", "1.25", "policy", "v1"),
+	deprecation("policy", "v1beta1", "PodSecurityPolicy", "1.21", "1.25", "", ""),
+	deprecation("node.k8s.io", "v1beta1", "RuntimeClass", "1.20", "1.25", "node.k8s.io", "v1"),
+	// 1.26
+	deprecation("flowcontrol.apiserver.k8s.io", "v1beta1", "FlowSchema", "1.23", "1.26", "flowcontrol.apiserver.k8s.io", "v1"),
+	deprecation("flowcontrol.apiserver.k8s.io", "v1beta1", "PriorityLevelConfiguration", "1.23", "1.26", "flowcontrol.apiserver.k8s.io", "v1"),
+	deprecation("autoscaling", "v2beta2", "HorizontalPodAutoscaler", "1.23", "1.26", "autoscaling", "v2"),
+	// 1.27
+	deprecation("storage.k8s.io", "v1beta1", "CSIStorageCapacity", "1.24", "1.27", "storage.k8s.io", "v1"),
+	// 1.29
+	deprecation("flowcontrol.apiserver.k8s.io", "v1beta2", "FlowSchema", "1.26", "1.29", "flowcontrol.apiserver.k8s.io", "v1"),
+	deprecation("flowcontrol.apiserver.k8s.io", "v1beta2", "PriorityLevelConfiguration", "1.26", "1.29", "flowcontrol.apiserver.k8s.io", "v1"),
+	// 1.32
+	deprecation("flowcontrol.apiserver.k8s.io", "v1beta3", "FlowSchema", "1.29", "1.32", "flowcontrol.apiserver.k8s.io", "v1"),
+	deprecation("flowcontrol.apiserver.k8s.io", "v1beta3", "PriorityLevelConfiguration", "1.29", "1.32", "flowcontrol.apiserver.k8s.io", "v1"),
+}
+
+func deprecation(group, apiVersion, kind, deprecatedIn, removedIn, replacementGroup, replacementVersion string) APIDeprecation {
+	d := APIDeprecation{
+		GroupVersionKind: schema.GroupVersionKind{Group: group, Version: apiVersion, Kind: kind},
+		DeprecatedIn:     deprecatedIn,
+		RemovedIn:        removedIn,
+	}
+	if replacementVersion != "" {
+		d.Replacement = schema.GroupVersionKind{Group: replacementGroup, Version: replacementVersion, Kind: kind}
+	}
+	return d
+}
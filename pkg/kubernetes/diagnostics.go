@@ -0,0 +1,239 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/diagnostics"
+)
+
+// DiagnosticsDefaultLogLines is the number of kubelet log lines collected per node when no limit is provided
+const DiagnosticsDefaultLogLines = 1000
+
+// diagnosticsNodeFilesScript prints every node file (positional parameters) preceded by a "==> ok <path>" header, base64
+// encoded so that binary files are not altered by the pod logs, or a "==> missing <path>" header, or a "==> denied <path>"
+// header if its real path is not allowed (see nodeFilesResolveScript)
+const diagnosticsNodeFilesScript = `for f in "$@"; do
+p=$(resolve "$f" "$f" 2>/dev/null) || { echo "==> denied $f"; continue; }
+if [ -f "$p" ]; then echo "==> ok $f"; base64 -- "$p"; else echo "==> missing $f"; fi
+done`
+
+// DiagnosticsCollectOptions selects the content of a diagnostic bundle
+type DiagnosticsCollectOptions struct {
+	// Namespaces whose Pods and events are collected (Optional, defaults to all namespaces)
+	Namespaces []string
+	// Nodes whose kubelet logs, stats, and files are collected (Optional, defaults to all nodes)
+	Nodes []string
+	// NodeFiles are the absolute paths of the files collected from every node (Optional)
+	NodeFiles []string
+	// LogLines is the number of kubelet log lines collected per node (DiagnosticsDefaultLogLines if <= 0)
+	LogLines int64
+}
+
+// DiagnosticsCollect gathers the Nodes, the Pods and events of the selected namespaces, and the kubelet logs, stats
+// summary, and files of the selected nodes into the files of a diagnostic bundle.
+// Namespaces and nodes are collected concurrently (see FanOut), those that fail are returned as TargetErrors and
+// listed in the errors.yaml file of the bundle.
+func (k *Kubernetes) DiagnosticsCollect(ctx context.Context, options DiagnosticsCollectOptions) ([]diagnostics.File, TargetErrors, error) {
+	for _, nodeFile := range options.NodeFiles {
+		if !path.IsAbs(nodeFile) || strings.ContainsAny(nodeFile, "\n\r") {
+			return nil, nil, fmt.Errorf("invalid node file %q, node paths must be absolute", nodeFile)
+		}
+	}
+	if len(options.NodeFiles) > 0 {
+		// Fail early instead of for every node if the node files are not allowed
+		if _, err := k.nodeFilesHelperPodOptions("", true, options.NodeFiles...); err != nil {
+			return nil, nil, err
+		}
+	}
+	if options.LogLines <= 0 {
+		options.LogLines = DiagnosticsDefaultLogLines
+	}
+	nodeList, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var targetErrors TargetErrors
+	nodes := nodeList.Items
+	if len(options.Nodes) > 0 {
+		nodes = slices.DeleteFunc(nodes, func(node v1.Node) bool { return !slices.Contains(options.Nodes, node.Name) })
+		for _, name := range options.Nodes {
+			if !slices.ContainsFunc(nodes, func(node v1.Node) bool { return node.Name == name }) {
+				targetErrors.Add("node/"+name, fmt.Errorf("node %s not found", name))
+			}
+		}
+	}
+	nodesYaml, err := yaml.Marshal(nodes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal nodes: %w", err)
+	}
+	files := []diagnostics.File{{Name: "nodes.yaml", Content: nodesYaml}}
+
+	namespaces := options.Namespaces
+	if len(namespaces) == 0 {
+		// Empty namespace lists the Pods and events of all the namespaces
+		namespaces = []string{""}
+		ReportProgress(ctx, "Collecting the pods and events of all namespaces")
+	} else {
+		ReportProgress(ctx, "Collecting the pods and events of %d namespaces", len(namespaces))
+	}
+	namespaceResults, namespaceErrors := FanOut(ctx, DefaultFanOutParallelism, namespaces, namespaceTarget,
+		func(ctx context.Context, namespace string) ([]diagnostics.File, error) {
+			return k.diagnosticsNamespace(ctx, namespace)
+		})
+	for _, result := range namespaceResults {
+		files = append(files, result.Value...)
+	}
+	targetErrors = append(targetErrors, namespaceErrors...)
+
+	ReportProgress(ctx, "Collecting the logs, stats, and files of %d nodes", len(nodes))
+	nodeResults, nodeErrors := FanOutNodes(ctx, nodes, func(ctx context.Context, node v1.Node) ([]diagnostics.File, error) {
+		return k.diagnosticsNode(ctx, node.Name, options)
+	})
+	for _, result := range nodeResults {
+		files = append(files, result.Value...)
+	}
+	targetErrors = append(targetErrors, nodeErrors...)
+
+	if len(targetErrors) > 0 {
+		errorsYaml, err := yaml.Marshal(targetErrors)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal errors: %w", err)
+		}
+		files = append(files, diagnostics.File{Name: "errors.yaml", Content: errorsYaml})
+	}
+	return files, targetErrors, nil
+}
+
+func namespaceTarget(namespace string) string {
+	if namespace == "" {
+		return "namespace/*"
+	}
+	return "namespace/" + namespace
+}
+
+func (k *Kubernetes) diagnosticsNamespace(ctx context.Context, namespace string) ([]diagnostics.File, error) {
+	dir := ""
+	if namespace != "" {
+		dir = "namespaces/" + namespace + "/"
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	events, err := k.AccessControlClientset().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	podsYaml, err := yaml.Marshal(pods.Items)
+	if err != nil {
+		return nil, err
+	}
+	eventsYaml, err := yaml.Marshal(events.Items)
+	if err != nil {
+		return nil, err
+	}
+	return []diagnostics.File{
+		{Name: dir + "pods.yaml", Content: podsYaml},
+		{Name: dir + "events.yaml", Content: eventsYaml},
+	}, nil
+}
+
+func (k *Kubernetes) diagnosticsNode(ctx context.Context, nodeName string, options DiagnosticsCollectOptions) ([]diagnostics.File, error) {
+	dir := "nodes/" + nodeName + "/"
+	kubeletLog, err := k.NodesLog(ctx, nodeName, "kubelet", options.LogLines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubelet log: %w", err)
+	}
+	statsSummary, err := k.nodeStatsSummary(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	files := []diagnostics.File{
+		{Name: dir + "kubelet.log", Content: []byte(kubeletLog)},
+		{Name: dir + "stats-summary.json", Content: []byte(statsSummary)},
+	}
+	if len(options.NodeFiles) == 0 {
+		return files, nil
+	}
+	nodeFiles, err := k.diagnosticsNodeFiles(ctx, nodeName, options.NodeFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node files: %w", err)
+	}
+	for _, nodeFile := range nodeFiles {
+		nodeFile.Name = dir + "files" + nodeFile.Name
+		files = append(files, nodeFile)
+	}
+	return files, nil
+}
+
+// diagnosticsNodeFiles reads the node files from a single node_files helper pod with the node filesystem mounted
+// read-only (see nodeFilesHelperPodOptions), the missing files are skipped
+func (k *Kubernetes) diagnosticsNodeFiles(ctx context.Context, nodeName string, nodeFiles []string) ([]diagnostics.File, error) {
+	return k.readNodeFiles(ctx, nodeName, diagnosticsNodeFilesScript, nodeFiles)
+}
+
+// readNodeFiles runs the script with the node files as positional parameters in a node_files helper pod and decodes
+// its output, a "==> ok <path>" header followed by the base64 encoded content of each file found (see
+// diagnosticsNodeFilesScript), the script can call the resolve function of nodeFilesResolveScript
+func (k *Kubernetes) readNodeFiles(ctx context.Context, nodeName, script string, nodeFiles []string) ([]diagnostics.File, error) {
+	command := []string{"sh", "-c", k.nodeFilesResolveScript() + script, "sh"}
+	for _, nodeFile := range nodeFiles {
+		hostPath, err := nodeFilesHostPath(nodeFile)
+		if err != nil {
+			return nil, err
+		}
+		command = append(command, hostPath)
+	}
+	helperPodOptions, err := k.nodeFilesHelperPodOptions(nodeName, true, nodeFiles...)
+	if err != nil {
+		return nil, err
+	}
+	helperPodOptions.Command = command
+	out, err := k.RunHelperPod(ctx, helperPodOptions)
+	if err != nil {
+		return nil, err
+	}
+	var files []diagnostics.File
+	var encoded *strings.Builder
+	flush := func() error {
+		if encoded == nil {
+			return nil
+		}
+		content, err := base64.StdEncoding.DecodeString(encoded.String())
+		if err != nil {
+			return fmt.Errorf("failed to decode file %s: %w", files[len(files)-1].Name, err)
+		}
+		files[len(files)-1].Content = content
+		encoded = nil
+		return nil
+	}
+	for _, line := range strings.Split(out, "\n") {
+		header, found := strings.CutPrefix(line, "==> ")
+		if !found {
+			if encoded != nil {
+				encoded.WriteString(strings.TrimSpace(line))
+			}
+			continue
+		}
+		if err = flush(); err != nil {
+			return nil, err
+		}
+		if hostPath, found := strings.CutPrefix(header, "ok "); found {
+			files = append(files, diagnostics.File{Name: strings.TrimPrefix(hostPath, HelperPodHostRoot)})
+			encoded = &strings.Builder{}
+		}
+	}
+	if err = flush(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
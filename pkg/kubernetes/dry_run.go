@@ -0,0 +1,29 @@
+package kubernetes
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context that makes the mutating operations performed with it use Kubernetes server-side
+// dry-run: the requests are fully validated and admitted by the API server, but no change is persisted.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun returns true if the context was created with WithDryRun
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// dryRun returns the value of the DryRun field of the Create/Update/Patch/Apply/Delete options for the context
+func dryRun(ctx context.Context) []string {
+	if IsDryRun(ctx) {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
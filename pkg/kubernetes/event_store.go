@@ -0,0 +1,177 @@
+package kubernetes
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+var eventGVK = v1.SchemeGroupVersion.WithKind("Event")
+
+// EventRecord is the compact representation of a Kubernetes event kept in the EventStore
+type EventRecord struct {
+	UID            types.UID         `json:"-"`
+	Namespace      string            `json:"namespace"`
+	Timestamp      time.Time         `json:"timestamp"`
+	Type           string            `json:"type"`
+	Reason         string            `json:"reason"`
+	InvolvedObject map[string]string `json:"involvedObject"`
+	Message        string            `json:"message"`
+	Count          int32             `json:"count,omitempty"`
+}
+
+type EventStoreQuery struct {
+	// Namespace to filter the events (Optional, all namespaces if empty)
+	Namespace string
+	// Since filters out the events that happened before the provided time (Optional)
+	Since time.Time
+	// Until filters out the events that happened after the provided time (Optional)
+	Until time.Time
+	// Type of the events (Normal, Warning) to include (Optional)
+	Type string
+	// InvolvedObjectKind filters events by the kind of the involved object (Optional, case-insensitive)
+	InvolvedObjectKind string
+	// InvolvedObjectName filters events by the name of the involved object (Optional)
+	InvolvedObjectName string
+}
+
+// EventStore continuously ingests the cluster events into a bounded in-memory ring buffer.
+// Events are kept after they've been garbage collected by the API server (1 hour retention by default)
+// until they're evicted by newer events once the store is full.
+type EventStore struct {
+	mu      sync.RWMutex
+	records []EventRecord
+	next    int
+	full    bool
+	// index of the position of each event UID in records, to update recurring events in place
+	index  map[types.UID]int
+	stopCh chan struct{}
+	once   sync.Once
+}
+
+func NewEventStore(size int) *EventStore {
+	return &EventStore{
+		records: make([]EventRecord, size),
+		index:   make(map[types.UID]int, size),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start watches the events in all namespaces using the provided client and stores them until Stop is called
+func (s *EventStore) Start(client kubernetes.Interface) {
+	factory := informers.NewSharedInformerFactory(client, 0)
+	informer := factory.Core().V1().Events().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if event, ok := obj.(*v1.Event); ok {
+				s.Add(event)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if event, ok := obj.(*v1.Event); ok {
+				s.Add(event)
+			}
+		},
+	})
+	if err != nil {
+		klog.Errorf("failed to start event store: %v", err)
+		return
+	}
+	factory.Start(s.stopCh)
+}
+
+// Stop stops ingesting events, the events already stored can still be queried
+func (s *EventStore) Stop() {
+	s.once.Do(func() { close(s.stopCh) })
+}
+
+// Add stores the provided event, updating the previous record if the event was already stored
+func (s *EventStore) Add(event *v1.Event) {
+	if len(s.records) == 0 {
+		return
+	}
+	record := EventRecord{
+		UID:       event.UID,
+		Namespace: event.Namespace,
+		Timestamp: eventTimestamp(event),
+		Type:      event.Type,
+		Reason:    event.Reason,
+		InvolvedObject: map[string]string{
+			"apiVersion": event.InvolvedObject.APIVersion,
+			"kind":       event.InvolvedObject.Kind,
+			"name":       event.InvolvedObject.Name,
+		},
+		Message: strings.TrimSpace(event.Message),
+		Count:   event.Count,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.index[event.UID]; ok {
+		s.records[i] = record
+		return
+	}
+	if s.full {
+		delete(s.index, s.records[s.next].UID)
+	}
+	s.records[s.next] = record
+	s.index[event.UID] = s.next
+	s.next = (s.next + 1) % len(s.records)
+	s.full = s.full || s.next == 0
+}
+
+// Query returns the stored events matching the query sorted by timestamp
+func (s *EventStore) Query(query EventStoreQuery) []EventRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stored := s.records[:s.next]
+	if s.full {
+		stored = s.records
+	}
+	var ret []EventRecord
+	for _, record := range stored {
+		if query.Namespace != "" && record.Namespace != query.Namespace {
+			continue
+		}
+		if !query.Since.IsZero() && record.Timestamp.Before(query.Since) {
+			continue
+		}
+		if !query.Until.IsZero() && record.Timestamp.After(query.Until) {
+			continue
+		}
+		if query.Type != "" && !strings.EqualFold(record.Type, query.Type) {
+			continue
+		}
+		if query.InvolvedObjectKind != "" && !strings.EqualFold(record.InvolvedObject["kind"], query.InvolvedObjectKind) {
+			continue
+		}
+		if query.InvolvedObjectName != "" && record.InvolvedObject["name"] != query.InvolvedObjectName {
+			continue
+		}
+		ret = append(ret, record)
+	}
+	slices.SortStableFunc(ret, func(a, b EventRecord) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return ret
+}
+
+// eventTimestamp returns the time of the last occurrence of the event
+func eventTimestamp(event *v1.Event) time.Time {
+	timestamp := event.EventTime.Time
+	if timestamp.IsZero() && event.Series != nil {
+		timestamp = event.Series.LastObservedTime.Time
+	} else if timestamp.IsZero() && event.Count > 1 {
+		timestamp = event.LastTimestamp.Time
+	} else if timestamp.IsZero() {
+		timestamp = event.FirstTimestamp.Time
+	}
+	return timestamp
+}
@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type EventStoreSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (s *EventStoreSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *EventStoreSuite) event(uid, namespace, eventType, kind, name string, ago time.Duration) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{UID: types.UID(uid), Name: uid, Namespace: namespace},
+		Type:           eventType,
+		Reason:         "Reason-" + uid,
+		Message:        " Message " + uid + "\n",
+		InvolvedObject: v1.ObjectReference{APIVersion: "v1", Kind: kind, Name: name},
+		FirstTimestamp: metav1.NewTime(s.now.Add(-ago)),
+		Count:          1,
+	}
+}
+
+func (s *EventStoreSuite) uids(records []EventRecord) []string {
+	var ret []string
+	for _, r := range records {
+		ret = append(ret, string(r.UID))
+	}
+	return ret
+}
+
+func (s *EventStoreSuite) TestAdd() {
+	s.Run("stores event records", func() {
+		store := NewEventStore(10)
+		store.Add(s.event("e1", "ns-1", "Warning", "Pod", "pod-1", time.Minute))
+		records := store.Query(EventStoreQuery{})
+		s.Require().Len(records, 1)
+		s.Equal(EventRecord{
+			UID:            "e1",
+			Namespace:      "ns-1",
+			Timestamp:      s.now.Add(-time.Minute),
+			Type:           "Warning",
+			Reason:         "Reason-e1",
+			InvolvedObject: map[string]string{"apiVersion": "v1", "kind": "Pod", "name": "pod-1"},
+			Message:        "Message e1",
+			Count:          1,
+		}, records[0])
+	})
+	s.Run("updates recurring events in place", func() {
+		store := NewEventStore(10)
+		store.Add(s.event("e1", "ns-1", "Warning", "Pod", "pod-1", 10*time.Minute))
+		recurring := s.event("e1", "ns-1", "Warning", "Pod", "pod-1", 10*time.Minute)
+		recurring.Count = 5
+		recurring.LastTimestamp = metav1.NewTime(s.now)
+		store.Add(recurring)
+		records := store.Query(EventStoreQuery{})
+		s.Require().Len(records, 1)
+		s.Equal(int32(5), records[0].Count)
+		s.Equal(s.now, records[0].Timestamp)
+	})
+	s.Run("evicts oldest events when full", func() {
+		store := NewEventStore(3)
+		for i, uid := range []string{"e1", "e2", "e3", "e4", "e5"} {
+			store.Add(s.event(uid, "ns-1", "Normal", "Pod", "pod-1", time.Duration(10-i)*time.Minute))
+		}
+		s.Equal([]string{"e3", "e4", "e5"}, s.uids(store.Query(EventStoreQuery{})))
+	})
+	s.Run("updates events after eviction of others", func() {
+		store := NewEventStore(2)
+		store.Add(s.event("e1", "ns-1", "Normal", "Pod", "pod-1", 3*time.Minute))
+		store.Add(s.event("e2", "ns-1", "Normal", "Pod", "pod-1", 2*time.Minute))
+		store.Add(s.event("e3", "ns-1", "Normal", "Pod", "pod-1", time.Minute))
+		store.Add(s.event("e2", "ns-1", "Warning", "Pod", "pod-1", 2*time.Minute))
+		records := store.Query(EventStoreQuery{})
+		s.Equal([]string{"e2", "e3"}, s.uids(records))
+		s.Equal("Warning", records[0].Type)
+	})
+}
+
+func (s *EventStoreSuite) TestQuery() {
+	store := NewEventStore(10)
+	store.Add(s.event("e3", "ns-2", "Normal", "Deployment", "deployment-1", 30*time.Minute))
+	store.Add(s.event("e1", "ns-1", "Warning", "Pod", "pod-1", 3*time.Hour))
+	store.Add(s.event("e2", "ns-1", "Normal", "Pod", "pod-2", 2*time.Hour))
+	store.Add(s.event("e4", "ns-1", "Warning", "Pod", "pod-1", 5*time.Minute))
+	s.Run("returns all events sorted by timestamp", func() {
+		s.Equal([]string{"e1", "e2", "e3", "e4"}, s.uids(store.Query(EventStoreQuery{})))
+	})
+	s.Run("filters by namespace", func() {
+		s.Equal([]string{"e3"}, s.uids(store.Query(EventStoreQuery{Namespace: "ns-2"})))
+	})
+	s.Run("filters by time window", func() {
+		s.Equal([]string{"e2", "e3"}, s.uids(store.Query(EventStoreQuery{
+			Since: s.now.Add(-2 * time.Hour),
+			Until: s.now.Add(-10 * time.Minute),
+		})))
+	})
+	s.Run("filters by type", func() {
+		s.Equal([]string{"e1", "e4"}, s.uids(store.Query(EventStoreQuery{Type: "warning"})))
+	})
+	s.Run("filters by involved object", func() {
+		s.Equal([]string{"e1", "e4"}, s.uids(store.Query(EventStoreQuery{InvolvedObjectKind: "pod", InvolvedObjectName: "pod-1"})))
+	})
+}
+
+func TestEventStore(t *testing.T) {
+	suite.Run(t, new(EventStoreSuite))
+}
@@ -2,11 +2,14 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"strings"
 )
 
 func (k *Kubernetes) EventsList(ctx context.Context, namespace string) ([]map[string]any, error) {
@@ -26,17 +29,9 @@ func (k *Kubernetes) EventsList(ctx context.Context, namespace string) ([]map[st
 		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, event); err != nil {
 			return eventMap, err
 		}
-		timestamp := event.EventTime.Time
-		if timestamp.IsZero() && event.Series != nil {
-			timestamp = event.Series.LastObservedTime.Time
-		} else if timestamp.IsZero() && event.Count > 1 {
-			timestamp = event.LastTimestamp.Time
-		} else if timestamp.IsZero() {
-			timestamp = event.FirstTimestamp.Time
-		}
 		eventMap = append(eventMap, map[string]any{
 			"Namespace": event.Namespace,
-			"Timestamp": timestamp.String(),
+			"Timestamp": eventTimestamp(event).String(),
 			"Type":      event.Type,
 			"Reason":    event.Reason,
 			"InvolvedObject": map[string]string{
@@ -49,3 +44,18 @@ func (k *Kubernetes) EventsList(ctx context.Context, namespace string) ([]map[st
 	}
 	return eventMap, nil
 }
+
+// EventsHistory queries the events retained by the embedded event store (see config.StaticConfig.EventStoreSize)
+func (k *Kubernetes) EventsHistory(ctx context.Context, query EventStoreQuery) ([]EventRecord, error) {
+	if k.eventStore == nil {
+		return nil, errors.New("the event store is not enabled, set event_store_size in the server configuration to enable it")
+	}
+	// The event store ingests the events with the server's credentials, check that the caller can list them
+	if !k.canIUse(ctx, &schema.GroupVersionResource{Version: "v1", Resource: "events"}, query.Namespace, "list") {
+		if query.Namespace == "" {
+			return nil, errors.New("not allowed to list events in all namespaces")
+		}
+		return nil, fmt.Errorf("not allowed to list events in namespace %s", query.Namespace)
+	}
+	return k.eventStore.Query(query), nil
+}
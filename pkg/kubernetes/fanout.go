@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// DefaultFanOutParallelism is the maximum number of targets processed concurrently by FanOut when no parallelism
+// is provided, high enough to process hundreds of nodes in a reasonable time without flooding the API server
+// (or the kubelets through the API server proxy)
+const DefaultFanOutParallelism = 10
+
+// FanOutResult is the value returned by the FanOut function for one of the targets.
+type FanOutResult[T any] struct {
+	// Target identifies the target the value was retrieved from, e.g. "node/worker-1"
+	Target string
+	Value  T
+}
+
+// FanOut runs fn for every target concurrently, with up to parallelism targets processed at a time
+// (DefaultFanOutParallelism if parallelism <= 0).
+// Each target is isolated from the rest: targets where fn fails (or panics) don't fail the operation, their errors are
+// returned as TargetErrors along with the values of the remaining targets.
+// The results and the errors are returned in the order of the targets.
+// When the context is cancelled, the targets that were not started yet are reported as failed with the context error.
+// The processed targets are reported as progress (see ReportProgress).
+func FanOut[I, T any](ctx context.Context, parallelism int, targets []I, target func(I) string, fn func(context.Context, I) (T, error)) ([]FanOutResult[T], TargetErrors) {
+	if parallelism <= 0 {
+		parallelism = DefaultFanOutParallelism
+	}
+	values := make([]T, len(targets))
+	errs := make([]error, len(targets))
+	semaphore := make(chan struct{}, parallelism)
+	wg := sync.WaitGroup{}
+	done := atomic.Int32{}
+	for i, t := range targets {
+		if errs[i] = ctx.Err(); errs[i] != nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		case semaphore <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("panic: %v", r)
+				}
+				ReportProgress(ctx, "Processed %s (%d/%d)", target(t), done.Add(1), len(targets))
+				<-semaphore
+				wg.Done()
+			}()
+			values[i], errs[i] = fn(ctx, t)
+		}()
+	}
+	wg.Wait()
+	var results []FanOutResult[T]
+	var targetErrors TargetErrors
+	for i, t := range targets {
+		if errs[i] != nil {
+			targetErrors.Add(target(t), errs[i])
+			continue
+		}
+		results = append(results, FanOutResult[T]{Target: target(t), Value: values[i]})
+	}
+	return results, targetErrors
+}
+
+// FanOutNodes runs fn for every node concurrently, see FanOut.
+// Targets are identified as "node/<name>".
+func FanOutNodes[T any](ctx context.Context, nodes []v1.Node, fn func(context.Context, v1.Node) (T, error)) ([]FanOutResult[T], TargetErrors) {
+	return FanOut(ctx, DefaultFanOutParallelism, nodes, nodeTarget, fn)
+}
+
+func nodeTarget(node v1.Node) string {
+	return "node/" + node.Name
+}
@@ -0,0 +1,111 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type FanOutSuite struct {
+	suite.Suite
+}
+
+func (s *FanOutSuite) TestFanOut() {
+	targets := make([]int, 50)
+	for i := range targets {
+		targets[i] = i
+	}
+	var running, maxRunning atomic.Int32
+	results, targetErrors := FanOut(s.T().Context(), 4, targets, strconv.Itoa, func(_ context.Context, i int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			observed := maxRunning.Load()
+			if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		switch i {
+		case 7:
+			return 0, errors.New("unreachable")
+		case 13:
+			panic("boom")
+		}
+		return i * 2, nil
+	})
+	s.Run("processes up to parallelism targets at a time", func() {
+		s.LessOrEqual(maxRunning.Load(), int32(4))
+		s.Greater(maxRunning.Load(), int32(1))
+	})
+	s.Run("returns the results in the order of the targets", func() {
+		s.Require().Len(results, 48)
+		s.Equal(FanOutResult[int]{Target: "0", Value: 0}, results[0])
+		s.Equal(FanOutResult[int]{Target: "8", Value: 16}, results[7])
+		s.Equal(FanOutResult[int]{Target: "49", Value: 98}, results[47])
+	})
+	s.Run("isolates failed and panicking targets", func() {
+		s.Equal(TargetErrors{
+			{Target: "7", Error: "unreachable"},
+			{Target: "13", Error: "panic: boom"},
+		}, targetErrors)
+	})
+}
+
+func (s *FanOutSuite) TestFanOutCancelled() {
+	ctx, cancel := context.WithCancel(s.T().Context())
+	cancel()
+	results, targetErrors := FanOut(ctx, 1, []string{"a", "b"}, func(t string) string { return t }, func(context.Context, string) (string, error) {
+		s.Fail("fn should not be called for a cancelled context")
+		return "", nil
+	})
+	s.Empty(results)
+	s.Equal(TargetErrors{{Target: "a", Error: "context canceled"}, {Target: "b", Error: "context canceled"}}, targetErrors)
+}
+
+func (s *FanOutSuite) TestFanOutNodes() {
+	nodes := []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}, {ObjectMeta: metav1.ObjectMeta{Name: "worker-2"}}}
+	results, targetErrors := FanOutNodes(s.T().Context(), nodes, func(_ context.Context, node v1.Node) (string, error) {
+		if node.Name == "worker-2" {
+			return "", errors.New("kubelet unreachable")
+		}
+		return "ok", nil
+	})
+	s.Equal([]FanOutResult[string]{{Target: "node/worker-1", Value: "ok"}}, results)
+	s.Equal(TargetErrors{{Target: "node/worker-2", Error: "kubelet unreachable"}}, targetErrors)
+}
+
+type progressRecorder struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *progressRecorder) Report(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, message)
+}
+
+func (s *FanOutSuite) TestFanOutProgress() {
+	recorder := &progressRecorder{}
+	ctx := WithProgressReporter(s.T().Context(), recorder)
+	_, _ = FanOut(ctx, 1, []string{"a", "b"}, func(t string) string { return "node/" + t }, func(_ context.Context, t string) (string, error) {
+		if t == "b" {
+			panic("boom")
+		}
+		return t, nil
+	})
+	s.Equal([]string{"Processed node/a (1/2)", "Processed node/b (2/2)"}, recorder.messages)
+}
+
+func TestFanOut(t *testing.T) {
+	suite.Run(t, new(FanOutSuite))
+}
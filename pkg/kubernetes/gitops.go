@@ -0,0 +1,372 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/gitops"
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+// GitOpsDriftMaxObjects is the maximum number of managed objects compared with their live state
+const GitOpsDriftMaxObjects = 200
+
+// GitOpsApps lists the Argo CD Applications and Flux Kustomizations
+type GitOpsApps struct {
+	Summary string       `json:"summary"`
+	Apps    []gitops.App `json:"apps"`
+	// NotInstalled are the GitOps kinds whose CRD isn't served by the cluster
+	NotInstalled []string     `json:"notInstalled,omitempty"`
+	TargetErrors TargetErrors `json:"targetErrors,omitempty"`
+}
+
+// GitOpsDriftOptions selects the GitOps object whose drift is computed
+type GitOpsDriftOptions struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Manifest is the desired rendered source (e.g. the output of kustomize build or helm template), the drift is
+	// computed from the state of the controller and the field managers of the live objects if empty
+	Manifest string
+}
+
+// GitOpsDrift is the drift of the objects managed by an Argo CD Application or a Flux Kustomization
+type GitOpsDrift struct {
+	Summary string         `json:"summary"`
+	App     *gitops.App    `json:"app"`
+	Drift   []gitops.Drift `json:"drift"`
+	// Checked is the number of objects compared with their live state
+	Checked      int          `json:"checked"`
+	Truncated    bool         `json:"truncated,omitempty"`
+	TargetErrors TargetErrors `json:"targetErrors,omitempty"`
+}
+
+// GitOpsList lists the Argo CD Applications and Flux Kustomizations of the provided namespace (all namespaces if
+// empty), only the ones of the provided kind if not empty.
+// The kinds whose CRD is not installed are reported, it's an error if none is.
+func (k *Kubernetes) GitOpsList(ctx context.Context, namespace, kind string) (*GitOpsApps, error) {
+	kinds := gitops.Kinds
+	if kind != "" {
+		kinds = []string{kind}
+	}
+	ret := &GitOpsApps{Apps: []gitops.App{}}
+	for _, kind := range kinds {
+		gvr, err := gitops.GVR(kind)
+		if err != nil {
+			return nil, err
+		}
+		if !k.supportsGroupVersion(gvr.GroupVersion().String()) {
+			ret.NotInstalled = append(ret.NotInstalled, kind)
+			continue
+		}
+		list, err := k.AccessControlClientset().DynamicClient().Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			ret.TargetErrors.Add("resource/"+gvr.GroupResource().String(), err)
+			continue
+		}
+		for i := range list.Items {
+			list.Items[i].SetKind(kind)
+			ret.Apps = append(ret.Apps, *gitops.NewApp(&list.Items[i]))
+		}
+	}
+	if len(ret.NotInstalled) == len(kinds) {
+		return nil, fmt.Errorf("no GitOps controller is installed, the CRDs of the %s kinds are not served (Argo CD %s, Flux %s)",
+			strings.Join(kinds, " and "), gitops.ApplicationGVR.GroupVersion(), gitops.KustomizationGVR.GroupVersion())
+	}
+	if len(ret.TargetErrors) > 0 && len(ret.Apps) == 0 && len(ret.TargetErrors)+len(ret.NotInstalled) == len(kinds) {
+		return nil, ret.TargetErrors
+	}
+	ret.Summary = ret.summary(namespace, kinds)
+	return ret, nil
+}
+
+func (a *GitOpsApps) summary(namespace string, kinds []string) string {
+	var parts []string
+	for _, kind := range kinds {
+		if slices.Contains(a.NotInstalled, kind) {
+			continue
+		}
+		total, outOfSync := 0, 0
+		for _, app := range a.Apps {
+			if app.Kind == kind {
+				total++
+				if app.SyncStatus != gitops.SyncStatusSynced {
+					outOfSync++
+				}
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%d %ss (%d not synced)", total, kind, outOfSync))
+	}
+	ret := strings.Join(parts, ", ")
+	if namespace == "" {
+		ret += " in all namespaces"
+	} else {
+		ret += " in namespace " + namespace
+	}
+	for _, kind := range a.NotInstalled {
+		ret += fmt.Sprintf(", %ss are not installed", kind)
+	}
+	return ret
+}
+
+// GitOpsDrift computes the drift of the objects managed by the Argo CD Application or Flux Kustomization.
+// With a desired manifest, the fields of the desired objects are compared with the live ones, and the managed objects
+// missing from the manifest are reported as extra.
+// Otherwise, the managed objects are checked for their existence, for the changes made by other field managers after
+// the last apply of the controller, and for the sync status reported by Argo CD.
+func (k *Kubernetes) GitOpsDrift(ctx context.Context, options GitOpsDriftOptions) (*GitOpsDrift, error) {
+	gvr, err := gitops.GVR(options.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if options.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	namespace := k.NamespaceOrDefault(options.Namespace)
+	obj, err := k.AccessControlClientset().DynamicClient().Resource(gvr).Namespace(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	obj.SetKind(options.Kind)
+	ret := &GitOpsDrift{App: gitops.NewApp(obj), Drift: []gitops.Drift{}}
+	if options.Manifest != "" {
+		err = k.gitOpsManifestDrift(ctx, ret, options.Manifest)
+	} else {
+		k.gitOpsLiveDrift(ctx, ret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret.Summary = ret.summary(options.Manifest != "")
+	return ret, nil
+}
+
+// gitOpsLiveDrift checks the managed objects reported by the controller against their live state
+func (k *Kubernetes) gitOpsLiveDrift(ctx context.Context, ret *GitOpsDrift) {
+	resources := ret.App.Resources
+	if len(resources) > GitOpsDriftMaxObjects {
+		resources, ret.Truncated = resources[:GitOpsDriftMaxObjects], true
+	}
+	results, targetErrors := FanOut(ctx, 0, resources, gitops.Resource.String, func(ctx context.Context, resource gitops.Resource) (*gitops.Drift, error) {
+		live, err := k.gitOpsLive(ctx, resource.GroupVersionKind(), resource.Namespace, resource.Name)
+		if apierrors.IsNotFound(err) {
+			return &gitops.Drift{Resource: resource, Drift: gitops.DriftMissing, Reason: "the object doesn't exist"}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if drift := gitops.OutOfBandChanges(ret.App.Kind, live); drift != nil {
+			drift.Resource = resource
+			return drift, nil
+		}
+		if resource.Status != "" && resource.Status != gitops.SyncStatusSynced {
+			return &gitops.Drift{Resource: resource, Drift: gitops.DriftOutOfSync, Reason: "reported " + resource.Status + " by Argo CD"}, nil
+		}
+		return nil, nil
+	})
+	ret.TargetErrors = targetErrors
+	ret.Checked = len(results)
+	for _, result := range results {
+		if result.Value != nil {
+			ret.Drift = append(ret.Drift, *result.Value)
+		}
+	}
+}
+
+// gitOpsManifestDrift compares the objects of the desired manifest with their live state
+func (k *Kubernetes) gitOpsManifestDrift(ctx context.Context, ret *GitOpsDrift, manifest string) error {
+	desired, err := parseManifest(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(desired) > GitOpsDriftMaxObjects {
+		desired, ret.Truncated = desired[:GitOpsDriftMaxObjects], true
+	}
+	targets := make([]gitOpsDesired, 0, len(desired))
+	for _, obj := range desired {
+		gvk := obj.GroupVersionKind()
+		mapping, err := k.AccessControlClientset().RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the resource of %s %s: %w", obj.GetAPIVersion(), obj.GetKind(), err)
+		}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && obj.GetNamespace() == "" {
+			obj.SetNamespace(ret.App.TargetNamespace)
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(ret.App.Namespace)
+			}
+		}
+		targets = append(targets, gitOpsDesired{obj: obj, resource: gitops.Resource{
+			Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(),
+		}})
+	}
+	results, targetErrors := FanOut(ctx, 0, targets, gitOpsDesired.String, func(ctx context.Context, target gitOpsDesired) (*gitops.Drift, error) {
+		resource := target.resource
+		live, err := k.gitOpsLive(ctx, resource.GroupVersionKind(), resource.Namespace, resource.Name)
+		if apierrors.IsNotFound(err) {
+			return &gitops.Drift{Resource: resource, Drift: gitops.DriftMissing, Reason: "the desired object doesn't exist"}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		drift := gitops.DesiredDiff(target.obj, live)
+		if len(drift.Fields) == 0 {
+			return nil, nil
+		}
+		drift.Resource = resource
+		drift.Reason = fmt.Sprintf("%d desired fields differ", len(drift.Fields))
+		return drift, nil
+	})
+	ret.TargetErrors = targetErrors
+	ret.Checked = len(results)
+	for _, result := range results {
+		if result.Value != nil {
+			ret.Drift = append(ret.Drift, *result.Value)
+		}
+	}
+	desiredResources := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		desiredResources[target.String()] = true
+	}
+	for _, resource := range ret.App.Resources {
+		if !desiredResources[resource.String()] {
+			ret.Drift = append(ret.Drift, gitops.Drift{Resource: resource, Drift: gitops.DriftExtra,
+				Reason: "managed by the controller but not in the desired manifest, pruned on sync if pruning is enabled"})
+		}
+	}
+	return nil
+}
+
+// gitOpsDesired is an object of the desired manifest and the reference of the managed object
+type gitOpsDesired struct {
+	obj      *unstructured.Unstructured
+	resource gitops.Resource
+}
+
+func (d gitOpsDesired) String() string {
+	return d.resource.String()
+}
+
+func (k *Kubernetes) gitOpsLive(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	mapping, err := k.AccessControlClientset().RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	return k.AccessControlClientset().DynamicClient().Resource(mapping.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (d *GitOpsDrift) summary(manifest bool) string {
+	counts := map[string]int{}
+	for _, drift := range d.Drift {
+		counts[drift.Drift]++
+	}
+	ret := fmt.Sprintf("%s %s/%s (%s", d.App.Kind, d.App.Namespace, d.App.Name, d.App.SyncStatus)
+	if d.App.Health != "" {
+		ret += ", " + d.App.Health
+	}
+	if d.App.Revision != "" {
+		ret += ", revision " + d.App.Revision
+	}
+	ret += fmt.Sprintf("): %d of %d checked objects drifted: %d missing, %d modified", len(d.Drift)-counts[gitops.DriftExtra], d.Checked,
+		counts[gitops.DriftMissing], counts[gitops.DriftModified])
+	if manifest {
+		ret += fmt.Sprintf(", %d extra, compared with the provided manifest", counts[gitops.DriftExtra])
+	} else {
+		ret += fmt.Sprintf(", %d out of sync, compared with the state of the controller", counts[gitops.DriftOutOfSync])
+	}
+	if d.Truncated {
+		ret += fmt.Sprintf(", only the first %d objects were checked", GitOpsDriftMaxObjects)
+	}
+	return ret
+}
+
+// GitOpsSync requests the sync of the Argo CD Application, like argocd app sync, or the reconciliation of the Flux
+// Kustomization, like flux reconcile. The controller performs it asynchronously.
+func (k *Kubernetes) GitOpsSync(ctx context.Context, kind, namespace, name string, prune bool) (string, error) {
+	gvr, err := gitops.GVR(kind)
+	if err != nil {
+		return "", err
+	}
+	namespace = k.NamespaceOrDefault(namespace)
+	client := k.AccessControlClientset().DynamicClient().Resource(gvr).Namespace(namespace)
+	obj, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	var patch map[string]any
+	switch kind {
+	case gitops.KindApplication:
+		if phase, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "phase"); phase == "Running" {
+			return "", fmt.Errorf("a sync operation of Application %s/%s is already running", namespace, name)
+		}
+		patch = map[string]any{"operation": map[string]any{
+			"initiatedBy": map[string]any{"username": version.BinaryName},
+			"sync":        map[string]any{"prune": prune},
+		}}
+	case gitops.KindKustomization:
+		if suspended, _, _ := unstructured.NestedBool(obj.Object, "spec", "suspend"); suspended {
+			return "", fmt.Errorf("Kustomization %s/%s is suspended, resume it to reconcile it", namespace, name)
+		}
+		if prune {
+			return "", errors.New("prune is only supported for Argo CD Applications, the pruning of Flux Kustomizations is set by their spec.prune field")
+		}
+		patch = map[string]any{"metadata": map[string]any{"annotations": map[string]any{
+			gitops.FluxReconcileAnnotation: time.Now().UTC().Format(time.RFC3339Nano),
+		}}}
+	}
+	content, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	if _, err = client.Patch(ctx, name, types.MergePatchType, content, metav1.PatchOptions{DryRun: dryRun(ctx)}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Sync of %s %s/%s requested, the controller applies the revision asynchronously, follow the progress with gitops_drift", kind, namespace, name), nil
+}
+
+// parseManifest decodes the objects of a multi-document YAML or JSON manifest, the empty documents are skipped
+func parseManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for _, document := range regexp.MustCompile(`(?m)^---\s*$`).Split(manifest, -1) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		err := yaml.NewYAMLToJSONDecoder(strings.NewReader(document)).Decode(obj)
+		if errors.Is(err, io.EOF) || (err == nil && len(obj.Object) == 0) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, errors.New("every object must have a kind and a name")
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
@@ -0,0 +1,219 @@
+package kubernetes
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/gitops"
+)
+
+type GitOpsSuite struct {
+	suite.Suite
+	mockServer  *test.MockServer
+	application *unstructured.Unstructured
+	mu          sync.Mutex
+	patches     []string
+	dryRun      []string
+}
+
+func (s *GitOpsSuite) SetupTest() {
+	s.patches, s.dryRun = nil, nil
+	s.application = &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1", "kind": "Application",
+		"metadata": map[string]any{"namespace": "argocd", "name": "shop"},
+		"spec": map[string]any{
+			"source":      map[string]any{"repoURL": "https://github.com/org/repo", "path": "apps/shop", "targetRevision": "main"},
+			"destination": map[string]any{"namespace": "shop"},
+		},
+		"status": map[string]any{
+			"sync":   map[string]any{"status": "OutOfSync", "revision": "abc123"},
+			"health": map[string]any{"status": "Healthy"},
+			"resources": []any{
+				map[string]any{"group": "apps", "version": "v1", "kind": "Deployment", "namespace": "shop", "name": "web", "status": "Synced"},
+				map[string]any{"group": "apps", "version": "v1", "kind": "Deployment", "namespace": "shop", "name": "api", "status": "OutOfSync"},
+				map[string]any{"version": "v1", "kind": "ConfigMap", "namespace": "shop", "name": "settings", "status": "OutOfSync"},
+			},
+		},
+	}}
+	applied := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	edited := metav1.NewTime(applied.Add(time.Hour))
+	web := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web", ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "argocd-controller", Operation: metav1.ManagedFieldsOperationApply, Time: &applied},
+			{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &edited},
+		}},
+		Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))},
+	}
+	settings := &v1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "settings"},
+		Data:       map[string]string{"mode": "blue"},
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"configmaps","singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["get","list","watch"]}`,
+		},
+		Groups: []string{
+			`{"name":"argoproj.io","versions":[{"groupVersion":"argoproj.io/v1alpha1","version":"v1alpha1"}],"preferredVersion":{"groupVersion":"argoproj.io/v1alpha1","version":"v1alpha1"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch req.URL.Path {
+		case "/apis/argoproj.io/v1alpha1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"argoproj.io/v1alpha1","resources":[
+				{"name":"applications","singularName":"application","namespaced":true,"kind":"Application","verbs":["get","list","patch"]}
+			]}`))
+		case "/apis/argoproj.io/v1alpha1/applications", "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications":
+			list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "argoproj.io/v1alpha1", "kind": "ApplicationList"}}
+			list.Items = []unstructured.Unstructured{*s.application}
+			test.WriteObject(w, list)
+		case "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications/shop":
+			if req.Method == http.MethodPatch {
+				body, _ := io.ReadAll(req.Body)
+				s.patches = append(s.patches, string(body))
+				s.dryRun = append(s.dryRun, req.URL.Query().Get("dryRun"))
+			}
+			test.WriteObject(w, s.application)
+		case "/apis/apps/v1/namespaces/shop/deployments/web":
+			test.WriteObject(w, web)
+		case "/api/v1/namespaces/shop/configmaps/settings":
+			test.WriteObject(w, settings)
+		case "/apis/apps/v1/namespaces/shop/deployments/api":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+		}
+	}))
+}
+
+func (s *GitOpsSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *GitOpsSuite) derived() *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *GitOpsSuite) TestGitOpsList() {
+	k := s.derived()
+	result, err := k.GitOpsList(s.T().Context(), "", "")
+	s.Require().NoError(err)
+	s.Run("returns the summary with the kinds not installed", func() {
+		s.Equal("1 Applications (1 not synced) in all namespaces, Kustomizations are not installed", result.Summary)
+	})
+	s.Run("returns the Applications", func() {
+		s.Require().Len(result.Apps, 1)
+		s.Equal("https://github.com/org/repo path apps/shop at main", result.Apps[0].Source)
+		s.Equal("OutOfSync", result.Apps[0].SyncStatus)
+	})
+	s.Run("returns error if no kind is installed", func() {
+		_, err := k.GitOpsList(s.T().Context(), "", gitops.KindKustomization)
+		s.ErrorContains(err, "no GitOps controller is installed")
+	})
+}
+
+func (s *GitOpsSuite) TestGitOpsDriftLive() {
+	k := s.derived()
+	result, err := k.GitOpsDrift(s.T().Context(), GitOpsDriftOptions{Kind: gitops.KindApplication, Namespace: "argocd", Name: "shop"})
+	s.Require().NoError(err)
+	s.Run("returns the summary", func() {
+		s.Equal("Application argocd/shop (OutOfSync, Healthy, revision abc123): 3 of 3 checked objects drifted: 1 missing, 1 modified, 1 out of sync, compared with the state of the controller", result.Summary)
+	})
+	s.Run("returns the drift of the managed objects", func() {
+		drift := map[string]gitops.Drift{}
+		for _, d := range result.Drift {
+			drift[d.String()] = d
+		}
+		s.Equal(gitops.DriftModified, drift["Deployment.apps/shop/web"].Drift)
+		s.Equal("changed by kubectl-edit (Update at 2025-01-01T11:00:00Z) after the last apply of the controller at 2025-01-01T10:00:00Z", drift["Deployment.apps/shop/web"].Reason)
+		s.Equal(gitops.DriftMissing, drift["Deployment.apps/shop/api"].Drift)
+		s.Equal(gitops.DriftOutOfSync, drift["ConfigMap/shop/settings"].Drift)
+	})
+}
+
+func (s *GitOpsSuite) TestGitOpsDriftManifest() {
+	k := s.derived()
+	result, err := k.GitOpsDrift(s.T().Context(), GitOpsDriftOptions{Kind: gitops.KindApplication, Namespace: "argocd", Name: "shop", Manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: blue
+---
+`})
+	s.Require().NoError(err)
+	s.Run("returns the summary", func() {
+		s.Equal("Application argocd/shop (OutOfSync, Healthy, revision abc123): 1 of 2 checked objects drifted: 0 missing, 1 modified, 1 extra, compared with the provided manifest", result.Summary)
+	})
+	s.Run("returns the field diffs in the destination namespace", func() {
+		s.Require().Len(result.Drift, 2)
+		s.Equal("Deployment.apps/shop/web", result.Drift[0].String())
+		s.Equal([]gitops.FieldDiff{{Path: "spec.replicas", Desired: float64(2), Live: float64(3)}}, result.Drift[0].Fields)
+	})
+	s.Run("returns the managed objects missing from the manifest as extra", func() {
+		s.Equal(gitops.DriftExtra, result.Drift[1].Drift)
+		s.Equal("Deployment.apps/shop/api", result.Drift[1].String())
+	})
+	s.Run("returns error for objects without name", func() {
+		_, err := k.GitOpsDrift(s.T().Context(), GitOpsDriftOptions{Kind: gitops.KindApplication, Namespace: "argocd", Name: "shop", Manifest: "kind: ConfigMap\n"})
+		s.EqualError(err, "failed to parse manifest: every object must have a kind and a name")
+	})
+}
+
+func (s *GitOpsSuite) TestGitOpsSync() {
+	k := s.derived()
+	s.Run("requests the sync of the Application", func() {
+		ret, err := k.GitOpsSync(s.T().Context(), gitops.KindApplication, "argocd", "shop", true)
+		s.Require().NoError(err)
+		s.Equal("Sync of Application argocd/shop requested, the controller applies the revision asynchronously, follow the progress with gitops_drift", ret)
+		s.Require().Len(s.patches, 1)
+		s.JSONEq(`{"operation":{"initiatedBy":{"username":"kubernetes-mcp-server"},"sync":{"prune":true}}}`, s.patches[0])
+	})
+	s.Run("propagates dry run", func() {
+		_, err := k.GitOpsSync(WithDryRun(s.T().Context()), gitops.KindApplication, "argocd", "shop", false)
+		s.Require().NoError(err)
+		s.Equal("All", s.dryRun[len(s.dryRun)-1])
+	})
+	s.Run("returns error if a sync is running", func() {
+		s.application.Object["status"].(map[string]any)["operationState"] = map[string]any{"phase": "Running"}
+		_, err := k.GitOpsSync(s.T().Context(), gitops.KindApplication, "argocd", "shop", false)
+		s.EqualError(err, "a sync operation of Application argocd/shop is already running")
+	})
+}
+
+func TestGitOps(t *testing.T) {
+	suite.Run(t, new(GitOpsSuite))
+}
@@ -0,0 +1,74 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+// helperLabelSelector selects the helper pods and DaemonSets created by the server (any role, any namespace)
+var helperLabelSelector = labels.SelectorFromSet(labels.Set{
+	AppKubernetesManagedBy: version.BinaryName,
+	AppKubernetesPartOf:    version.BinaryName + "-helper",
+}).String()
+
+// CleanupHelpers deletes the helper pods and DaemonSets created by the server that are older than the ttl.
+// Helpers are always deleted once their tool call completes, the ones left behind were created by a server that
+// stopped (e.g. crashed or was killed) before deleting them.
+// The ttl must be longer than the helper timeouts so that the helpers of the running servers are not deleted.
+// The names of the deleted helpers are returned ("pod/<namespace>/<name>" or "daemonset/<namespace>/<name>").
+func (k *Kubernetes) CleanupHelpers(ctx context.Context, ttl time.Duration) ([]string, error) {
+	deleteOptions := metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0)), PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)}
+	cutoff := time.Now().Add(-ttl)
+	var deleted []string
+	daemonSets, err := k.AccessControlClientset().AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{LabelSelector: helperLabelSelector})
+	if err != nil {
+		return deleted, fmt.Errorf("failed to list helper daemonsets: %w", err)
+	}
+	for _, daemonSet := range daemonSets.Items {
+		if daemonSet.CreationTimestamp.After(cutoff) {
+			continue
+		}
+		if err = k.AccessControlClientset().AppsV1().DaemonSets(daemonSet.Namespace).Delete(ctx, daemonSet.Name, deleteOptions); err != nil {
+			return deleted, fmt.Errorf("failed to delete helper daemonset %s/%s: %w", daemonSet.Namespace, daemonSet.Name, err)
+		}
+		deleted = append(deleted, "daemonset/"+daemonSet.Namespace+"/"+daemonSet.Name)
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: helperLabelSelector})
+	if err != nil {
+		return deleted, fmt.Errorf("failed to list helper pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		// The pods of the helper DaemonSets are deleted with their DaemonSet
+		if pod.CreationTimestamp.After(cutoff) || metav1.GetControllerOf(&pod) != nil {
+			continue
+		}
+		if err = k.AccessControlClientset().CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOptions); err != nil {
+			return deleted, fmt.Errorf("failed to delete helper pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		deleted = append(deleted, "pod/"+pod.Namespace+"/"+pod.Name)
+	}
+	return deleted, nil
+}
+
+// cleanupHelpersInBackground runs CleanupHelpers once without blocking the server startup, the result is logged
+func (k *Kubernetes) cleanupHelpersInBackground(ttl time.Duration) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		deleted, err := k.CleanupHelpers(ctx, ttl)
+		if len(deleted) > 0 {
+			klog.V(1).Infof("Deleted %d orphaned helpers older than %s: %v", len(deleted), ttl, deleted)
+		}
+		if err != nil {
+			klog.Warningf("Failed to clean up orphaned helpers: %v", err)
+		}
+	}()
+}
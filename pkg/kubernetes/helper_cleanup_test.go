@@ -0,0 +1,126 @@
+package kubernetes
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type HelperCleanupSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	selectors  []string
+	deleted    []string
+}
+
+func (s *HelperCleanupSuite) SetupTest() {
+	s.selectors, s.deleted = nil, nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	created := func(ago time.Duration) metav1.Time { return metav1.NewTime(time.Now().Add(-ago)) }
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if req.Method == http.MethodDelete {
+			s.deleted = append(s.deleted, req.URL.Path)
+			test.WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+			return
+		}
+		switch req.URL.Path {
+		case "/apis/apps/v1/daemonsets":
+			s.selectors = append(s.selectors, req.URL.Query().Get("labelSelector"))
+			test.WriteObject(w, &appsv1.DaemonSetList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSetList"},
+				Items: []appsv1.DaemonSet{
+					{ObjectMeta: metav1.ObjectMeta{Name: "helper-busybox-old", Namespace: "default", CreationTimestamp: created(time.Hour)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "helper-busybox-new", Namespace: "default", CreationTimestamp: created(time.Minute)}},
+				},
+			})
+		case "/api/v1/pods":
+			s.selectors = append(s.selectors, req.URL.Query().Get("labelSelector"))
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "helper-busybox-orphan", Namespace: "default", CreationTimestamp: created(time.Hour)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "helper-network-test-orphan", Namespace: "frontend", CreationTimestamp: created(2 * time.Hour)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "helper-busybox-running", Namespace: "default", CreationTimestamp: created(time.Minute)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "helper-busybox-old-x1", Namespace: "default", CreationTimestamp: created(time.Hour),
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "helper-busybox-old", Controller: ptr.To(true)}}}},
+				},
+			})
+		}
+	}))
+}
+
+func (s *HelperCleanupSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *HelperCleanupSuite) derived(toml string) *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(toml)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *HelperCleanupSuite) TestCleanupHelpers() {
+	deleted, err := s.derived(``).CleanupHelpers(s.T().Context(), 30*time.Minute)
+	s.Require().NoError(err)
+	s.Run("lists the helpers managed by the server in all namespaces", func() {
+		s.Equal([]string{
+			"app.kubernetes.io/managed-by=kubernetes-mcp-server,app.kubernetes.io/part-of=kubernetes-mcp-server-helper",
+			"app.kubernetes.io/managed-by=kubernetes-mcp-server,app.kubernetes.io/part-of=kubernetes-mcp-server-helper",
+		}, s.selectors)
+	})
+	s.Run("deletes the helpers older than the ttl", func() {
+		s.Equal([]string{
+			"daemonset/default/helper-busybox-old",
+			"pod/default/helper-busybox-orphan",
+			"pod/frontend/helper-network-test-orphan",
+		}, deleted)
+		s.Equal([]string{
+			"/apis/apps/v1/namespaces/default/daemonsets/helper-busybox-old",
+			"/api/v1/namespaces/default/pods/helper-busybox-orphan",
+			"/api/v1/namespaces/frontend/pods/helper-network-test-orphan",
+		}, s.deleted)
+	})
+}
+
+func (s *HelperCleanupSuite) TestCleanupHelpersOnStartup() {
+	s.Run("disabled by default", func() {
+		s.derived(``)
+		s.Never(func() bool {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return len(s.selectors) > 0
+		}, 500*time.Millisecond, 50*time.Millisecond)
+	})
+	s.Run("enabled with helper_cleanup_ttl", func() {
+		s.derived(`helper_cleanup_ttl = "30m"`)
+		s.Eventually(func() bool {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return len(s.deleted) == 3
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
+
+func TestHelperCleanup(t *testing.T) {
+	suite.Run(t, new(HelperCleanupSuite))
+}
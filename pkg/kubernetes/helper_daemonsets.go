@@ -0,0 +1,213 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+// helperDaemonSetIdleCommand keeps the helper DaemonSet pods running once the helper command completed,
+// DaemonSet pods are always restarted so the pod can't simply exit
+var helperDaemonSetIdleCommand = []string{"sleep", "2147483647"}
+
+// RunHelperDaemonSet runs the helper command once on each of the provided nodes and returns the output per node name.
+// Instead of creating a helper pod per node, a short-lived DaemonSet that tolerates every taint is created and
+// pinned to the provided nodes (one per helper image if the nodes have different architectures).
+// The command runs as an init container and its logs are collected as soon as it completes on each node.
+// Nodes that are not ready, or where the command fails or doesn't complete in time, are returned as TargetErrors.
+// The DaemonSets are always deleted before returning. The NodeName option is ignored.
+func (k *Kubernetes) RunHelperDaemonSet(ctx context.Context, options HelperPodOptions, nodes []v1.Node) (map[string]string, TargetErrors, error) {
+	var targetErrors TargetErrors
+	// Group the nodes by helper image, architecture-specific images need a DaemonSet each
+	nodeNamesByImage := map[string][]string{}
+	nodeArchitectures := map[string]string{}
+	for _, node := range nodes {
+		if isNodeNotReady(&node) {
+			targetErrors.Add("node/"+node.Name, fmt.Errorf("node %s is not ready", node.Name))
+			continue
+		}
+		nodeArchitectures[node.Name] = NodeArchitecture(&node)
+		image := k.resolveHelperImage(options.Role, nodeArchitectures[node.Name])
+		if image == "" {
+			return nil, nil, fmt.Errorf("no helper image configured for role %s", options.Role)
+		}
+		nodeNamesByImage[image] = append(nodeNamesByImage[image], node.Name)
+	}
+	namespace := k.NamespaceOrDefault("")
+	daemonSets := k.AccessControlClientset().AppsV1().DaemonSets(namespace)
+	pending := map[string]string{} // node name -> DaemonSet name
+	defer func() {
+		// Use a fresh context, the helper DaemonSets must be removed even if the tool call was cancelled
+		for _, name := range uniqueValues(pending) {
+			_ = daemonSets.Delete(context.Background(), name, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)})
+		}
+	}()
+	for image, nodeNames := range nodeNamesByImage {
+		daemonSet := k.helperDaemonSet(namespace, image, options, nodeNames)
+		ReportProgress(ctx, "Creating helper daemonset %s/%s for %d nodes", namespace, daemonSet.Name, len(nodeNames))
+		if _, err := daemonSets.Create(ctx, daemonSet, metav1.CreateOptions{}); err != nil {
+			return nil, nil, fmt.Errorf("failed to create helper daemonset: %w", err)
+		}
+		for _, nodeName := range nodeNames {
+			pending[nodeName] = daemonSet.Name
+		}
+	}
+	timeout := helperTimeout(ctx, options.Timeout)
+	ret := map[string]string{}
+	pods := k.AccessControlClientset().CoreV1().Pods(namespace)
+	completed := map[string]bool{}
+	reported := 0
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		for _, name := range uniqueValues(pending) {
+			podList, err := pods.List(ctx, metav1.ListOptions{
+				LabelSelector: labels.SelectorFromSet(labels.Set{AppKubernetesName: name}).String(),
+			})
+			if err != nil {
+				return false, err
+			}
+			for _, pod := range podList.Items {
+				if completed[pod.Spec.NodeName] || pending[pod.Spec.NodeName] != name {
+					continue
+				}
+				out, done, err := k.helperDaemonSetPodOutput(ctx, &pod, options.Role, nodeArchitectures[pod.Spec.NodeName])
+				if !done {
+					continue
+				}
+				completed[pod.Spec.NodeName] = true
+				if err != nil {
+					targetErrors.Add("node/"+pod.Spec.NodeName, err)
+				} else {
+					ret[pod.Spec.NodeName] = out
+				}
+			}
+		}
+		if len(completed) != reported {
+			reported = len(completed)
+			ReportProgress(ctx, "Helper pods completed on %d/%d nodes", reported, len(pending))
+		}
+		return len(completed) == len(pending), nil
+	})
+	if err != nil {
+		for _, nodeName := range slices.Sorted(maps.Keys(pending)) {
+			if !completed[nodeName] {
+				targetErrors.Add("node/"+nodeName, fmt.Errorf("helper pod did not complete on node %s: %w", nodeName, err))
+			}
+		}
+	}
+	return ret, targetErrors, nil
+}
+
+func (k *Kubernetes) helperDaemonSet(namespace, image string, options HelperPodOptions, nodeNames []string) *appsv1.DaemonSet {
+	name := k.helperPodName(options.Role)
+	podLabels := k.helperPodLabels(name, options.Role, nil)
+	annotations := k.helperPodAnnotations()
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: podLabels, Annotations: annotations},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{AppKubernetesName: name}},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels, Annotations: annotations},
+				Spec: v1.PodSpec{
+					HostNetwork:                   options.HostNetwork,
+					HostPID:                       options.HostPID,
+					TerminationGracePeriodSeconds: ptr.To(int64(0)),
+					ImagePullSecrets:              k.HelperImagePullSecrets(options.Role),
+					// Tolerate every taint so that the helper pods land on control plane and cordoned nodes too
+					Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+					Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+							NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchFields: []v1.NodeSelectorRequirement{{
+								Key:      "metadata.name",
+								Operator: v1.NodeSelectorOpIn,
+								Values:   nodeNames,
+							}}}},
+						},
+					}},
+					Volumes: options.volumes(),
+					InitContainers: []v1.Container{{
+						Name:            options.Role,
+						Image:           image,
+						Command:         options.Command,
+						VolumeMounts:    options.volumeMounts(),
+						SecurityContext: options.securityContext(),
+					}},
+					Containers: []v1.Container{{
+						Name:    "idle",
+						Image:   image,
+						Command: helperDaemonSetIdleCommand,
+					}},
+				},
+			},
+		},
+	}
+}
+
+// helperDaemonSetPodOutput returns the output of the helper command of the DaemonSet pod, done is false while the
+// command is still running, arch is the architecture of the node of the pod
+func (k *Kubernetes) helperDaemonSetPodOutput(ctx context.Context, pod *v1.Pod, role, arch string) (out string, done bool, err error) {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != role {
+			continue
+		}
+		terminated, previous := status.State.Terminated, false
+		// Failed init containers are restarted, the failure is kept in the last termination state
+		if terminated == nil && status.LastTerminationState.Terminated != nil {
+			terminated, previous = status.LastTerminationState.Terminated, true
+		}
+		if terminated == nil {
+			return "", false, nil
+		}
+		logs, err := k.AccessControlClientset().CoreV1().Pods(pod.Namespace).
+			GetLogs(pod.Name, &v1.PodLogOptions{Container: role, Previous: previous}).DoRaw(ctx)
+		if err != nil {
+			return "", true, fmt.Errorf("failed to get helper pod %s logs: %w", pod.Name, err)
+		}
+		if terminated.ExitCode != 0 {
+			if cause := helperImageFailure(role, helperContainerImage(pod, role), arch, terminated, string(logs)); cause != "" {
+				return "", true, fmt.Errorf("helper pod %s failed (%s): %s", pod.Name, cause, string(logs))
+			}
+			return "", true, fmt.Errorf("helper pod %s failed: %s", pod.Name, string(logs))
+		}
+		return string(logs), true, nil
+	}
+	return "", false, nil
+}
+
+// helperContainerImage returns the image of the helper init container of the DaemonSet pod
+func helperContainerImage(pod *v1.Pod, role string) string {
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == role {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+// isNodeNotReady returns true if the node reports it's not ready (unlike !isNodeReady, nodes without a Ready
+// condition are not reported), helper pods would never start on it
+func isNodeNotReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status != v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func uniqueValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+	return slices.Compact(values)
+}
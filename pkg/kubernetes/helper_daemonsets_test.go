@@ -0,0 +1,205 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type HelperDaemonSetsSuite struct {
+	suite.Suite
+	mockServer             *test.MockServer
+	helperDaemonSetHandler *test.HelperDaemonSetHandler
+}
+
+func (s *HelperDaemonSetsSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.helperDaemonSetHandler = &test.HelperDaemonSetHandler{
+		Logs: func(pod *v1.Pod) string {
+			return "output of " + pod.Spec.NodeName
+		},
+		ExitCode: func(pod *v1.Pod) int32 {
+			if pod.Spec.NodeName == "failing-node" {
+				return 1
+			}
+			return 0
+		},
+	}
+	s.mockServer.Handle(s.helperDaemonSetHandler)
+}
+
+func (s *HelperDaemonSetsSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *HelperDaemonSetsSuite) derived(toml string) *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(toml)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func helperDaemonSetNode(name, arch string, ready v1.ConditionStatus) v1.Node {
+	return v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{v1.LabelArchStable: arch}},
+		Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}}},
+	}
+}
+
+func (s *HelperDaemonSetsSuite) TestRunHelperDaemonSet() {
+	k := s.derived(`helper_image_pull_secrets = ["mirror-pull-secret"]`)
+	out, targetErrors, err := k.RunHelperDaemonSet(s.T().Context(), HelperPodOptions{
+		Role:        HelperImageBusybox,
+		Command:     []string{"cat", "/proc/sys/vm/max_map_count"},
+		HostNetwork: true,
+	}, []v1.Node{
+		helperDaemonSetNode("node-1", "amd64", v1.ConditionTrue),
+		helperDaemonSetNode("node-2", "amd64", v1.ConditionTrue),
+	})
+	s.Run("returns helper command output per node", func() {
+		s.Require().NoError(err)
+		s.Empty(targetErrors)
+		s.Equal(map[string]string{"node-1": "output of node-1", "node-2": "output of node-2"}, out)
+	})
+	s.Require().Len(s.helperDaemonSetHandler.Created(), 1)
+	daemonSet := s.helperDaemonSetHandler.Created()[0]
+	s.Run("creates helper daemonset in configured namespace", func() {
+		s.Equal("default", daemonSet.Namespace)
+		s.Regexp("^kubernetes-mcp-server-busybox-[a-z0-9]{5}$", daemonSet.Name)
+	})
+	s.Run("creates helper daemonset with managed-by labels", func() {
+		s.Equal("kubernetes-mcp-server", daemonSet.Spec.Template.Labels[AppKubernetesManagedBy])
+		s.Equal("kubernetes-mcp-server-helper", daemonSet.Spec.Template.Labels[AppKubernetesPartOf])
+		s.Equal(HelperImageBusybox, daemonSet.Spec.Template.Labels[AppKubernetesComponent])
+	})
+	spec := daemonSet.Spec.Template.Spec
+	s.Run("creates helper daemonset pinned to the provided nodes", func() {
+		terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		s.Require().Len(terms, 1)
+		s.Equal([]v1.NodeSelectorRequirement{{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"node-1", "node-2"}}}, terms[0].MatchFields)
+	})
+	s.Run("creates helper daemonset tolerating every taint", func() {
+		s.Equal([]v1.Toleration{{Operator: v1.TolerationOpExists}}, spec.Tolerations)
+	})
+	s.Run("creates helper daemonset with the command in an init container", func() {
+		s.True(spec.HostNetwork)
+		s.Equal([]v1.LocalObjectReference{{Name: "mirror-pull-secret"}}, spec.ImagePullSecrets)
+		s.Require().Len(spec.InitContainers, 1)
+		s.Equal("docker.io/library/busybox:1.37", spec.InitContainers[0].Image)
+		s.Equal([]string{"cat", "/proc/sys/vm/max_map_count"}, spec.InitContainers[0].Command)
+	})
+	s.Run("deletes helper daemonset", func() {
+		s.Equal([]string{daemonSet.Name}, s.helperDaemonSetHandler.Deleted())
+	})
+}
+
+func (s *HelperDaemonSetsSuite) TestRunHelperDaemonSetArchitectures() {
+	k := s.derived(`
+		[helper_images.busybox.arch]
+		arm64 = "registry.example.com/arm64v8/busybox:1.37"
+	`)
+	out, _, err := k.RunHelperDaemonSet(s.T().Context(), HelperPodOptions{Role: HelperImageBusybox, Command: []string{"true"}}, []v1.Node{
+		helperDaemonSetNode("amd64-node", "amd64", v1.ConditionTrue),
+		helperDaemonSetNode("arm64-node", "arm64", v1.ConditionTrue),
+	})
+	s.Require().NoError(err)
+	s.Run("returns helper command output of every node", func() {
+		s.Len(out, 2)
+	})
+	s.Run("creates a helper daemonset per image", func() {
+		images := map[string]string{}
+		for _, daemonSet := range s.helperDaemonSetHandler.Created() {
+			images[helperDaemonSetNodeNames(daemonSet)] = daemonSet.Spec.Template.Spec.InitContainers[0].Image
+		}
+		s.Equal(map[string]string{
+			"amd64-node": "docker.io/library/busybox:1.37",
+			"arm64-node": "registry.example.com/arm64v8/busybox:1.37",
+		}, images)
+	})
+	s.Run("deletes every helper daemonset", func() {
+		s.Len(s.helperDaemonSetHandler.Deleted(), 2)
+	})
+}
+
+func (s *HelperDaemonSetsSuite) TestRunHelperDaemonSetTargetErrors() {
+	k := s.derived(``)
+	out, targetErrors, err := k.RunHelperDaemonSet(s.T().Context(), HelperPodOptions{Role: HelperImageBusybox, Command: []string{"true"}}, []v1.Node{
+		helperDaemonSetNode("node-1", "amd64", v1.ConditionTrue),
+		helperDaemonSetNode("failing-node", "amd64", v1.ConditionTrue),
+		helperDaemonSetNode("not-ready-node", "amd64", v1.ConditionUnknown),
+	})
+	s.Require().NoError(err)
+	s.Run("returns output of successful nodes", func() {
+		s.Equal(map[string]string{"node-1": "output of node-1"}, out)
+	})
+	s.Require().Len(targetErrors, 2)
+	s.Run("returns not ready nodes as target errors", func() {
+		s.Equal("node/not-ready-node", targetErrors[0].Target)
+		s.Equal("node not-ready-node is not ready", targetErrors[0].Error)
+	})
+	s.Run("returns failed nodes as target errors", func() {
+		s.Equal("node/failing-node", targetErrors[1].Target)
+		s.Regexp("^helper pod kubernetes-mcp-server-busybox-[a-z0-9]{5}-failing-node failed: output of failing-node$", targetErrors[1].Error)
+	})
+	s.Run("doesn't pin helper daemonset to not ready nodes", func() {
+		s.Require().Len(s.helperDaemonSetHandler.Created(), 1)
+		s.Equal("node-1,failing-node", helperDaemonSetNodeNames(s.helperDaemonSetHandler.Created()[0]))
+	})
+}
+
+func (s *HelperDaemonSetsSuite) TestRunHelperDaemonSetConfiguredMetadata() {
+	k := s.derived(`
+		helper_pod_name_prefix = "mcp-"
+		helper_pod_labels = { cost-center = "platform" }
+		helper_pod_annotations = { "pod-security.kubernetes.io/exempt" = "true" }
+	`)
+	_, _, err := k.RunHelperDaemonSet(s.T().Context(), HelperPodOptions{Role: HelperImageBusybox, Command: []string{"true"}}, []v1.Node{
+		helperDaemonSetNode("node-1", "amd64", v1.ConditionTrue),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(s.helperDaemonSetHandler.Created(), 1)
+	daemonSet := s.helperDaemonSetHandler.Created()[0]
+	s.Run("creates helper daemonset with configured name prefix", func() {
+		s.Regexp("^mcp-busybox-[a-z0-9]{5}$", daemonSet.Name)
+	})
+	s.Run("creates helper daemonset pods with configured labels and annotations", func() {
+		s.Equal("platform", daemonSet.Spec.Template.Labels["cost-center"])
+		s.Equal("kubernetes-mcp-server", daemonSet.Spec.Template.Labels[AppKubernetesManagedBy])
+		s.Equal(map[string]string{"pod-security.kubernetes.io/exempt": "true"}, daemonSet.Spec.Template.Annotations)
+	})
+}
+
+func (s *HelperDaemonSetsSuite) TestRunHelperDaemonSetUnknownRole() {
+	k := s.derived(``)
+	_, _, err := k.RunHelperDaemonSet(s.T().Context(), HelperPodOptions{Role: "unknown"}, []v1.Node{
+		helperDaemonSetNode("node-1", "amd64", v1.ConditionTrue),
+	})
+	s.Run("returns error", func() {
+		s.EqualError(err, "no helper image configured for role unknown")
+	})
+	s.Run("doesn't create helper daemonset", func() {
+		s.Empty(s.helperDaemonSetHandler.Created())
+	})
+}
+
+func helperDaemonSetNodeNames(daemonSet *appsv1.DaemonSet) string {
+	terms := daemonSet.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	return strings.Join(terms[0].MatchFields[0].Values, ",")
+}
+
+func TestHelperDaemonSets(t *testing.T) {
+	suite.Run(t, new(HelperDaemonSetsSuite))
+}
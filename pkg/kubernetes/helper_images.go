@@ -0,0 +1,129 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Helper image roles used by the tools that create short-lived helper pods.
+// The image for each role can be overridden through the helper_images configuration.
+const (
+	HelperImageBusybox     = "busybox"
+	HelperImageDebug       = "debug"
+	HelperImageNetworkTest = "network-test"
+	HelperImageMustGather  = "must-gather"
+	// HelperImageWindowsHostProcess is the image of the Windows HostProcess helper pods, the PowerShell of the node is used
+	HelperImageWindowsHostProcess = "windows-host-process"
+)
+
+// defaultHelperImages are multi-arch images (manifest lists) so that the container runtime pulls
+// the variant matching the node where the helper pod lands.
+var defaultHelperImages = map[string]string{
+	HelperImageBusybox:            "docker.io/library/busybox:1.37",
+	HelperImageDebug:              "docker.io/nicolaka/netshoot:v0.14",
+	HelperImageNetworkTest:        "docker.io/nicolaka/netshoot:v0.14",
+	HelperImageMustGather:         "quay.io/openshift/origin-must-gather:latest",
+	HelperImageWindowsHostProcess: "mcr.microsoft.com/oss/kubernetes/windows-host-process-containers-base-image:v1.0.0",
+}
+
+// NodeArchitecture returns the CPU architecture of the provided node.
+// The well-known kubernetes.io/arch label is preferred, falling back to the deprecated beta label and
+// to the architecture reported by the kubelet in the node status.
+func NodeArchitecture(node *v1.Node) string {
+	if node == nil {
+		return ""
+	}
+	if arch := node.Labels[v1.LabelArchStable]; arch != "" {
+		return arch
+	}
+	if arch := node.Labels["beta.kubernetes.io/arch"]; arch != "" {
+		return arch
+	}
+	return node.Status.NodeInfo.Architecture
+}
+
+// HelperImage returns the image to use for a helper pod with the provided role.
+// If nodeName is provided, the node's architecture is used to select an architecture-specific image
+// (if configured), otherwise the default (multi-arch) image for the role is returned.
+func (k *Kubernetes) HelperImage(ctx context.Context, role, nodeName string) (string, error) {
+	image, _, err := k.helperImage(ctx, role, nodeName)
+	return image, err
+}
+
+// helperImage returns the image to use for a helper pod with the provided role and the architecture of the node it
+// was selected for (empty if no node is provided)
+func (k *Kubernetes) helperImage(ctx context.Context, role, nodeName string) (string, string, error) {
+	arch := ""
+	if nodeName != "" {
+		node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get node %s: %w", nodeName, err)
+		}
+		arch = NodeArchitecture(node)
+	}
+	image := k.resolveHelperImage(role, arch)
+	if image == "" {
+		return "", "", fmt.Errorf("no helper image configured for role %s", role)
+	}
+	return image, arch, nil
+}
+
+// HelperImagePullSecrets returns the image pull secrets to set in a helper pod with the provided role.
+// Includes the globally configured pull secrets and the ones specific to the role.
+func (k *Kubernetes) HelperImagePullSecrets(role string) []v1.LocalObjectReference {
+	staticConfig := k.AccessControlClientset().staticConfig
+	names := slices.Concat(staticConfig.HelperImagePullSecrets, staticConfig.HelperImages[role].PullSecrets)
+	slices.Sort(names)
+	var pullSecrets []v1.LocalObjectReference
+	for _, name := range slices.Compact(names) {
+		pullSecrets = append(pullSecrets, v1.LocalObjectReference{Name: name})
+	}
+	return pullSecrets
+}
+
+func (k *Kubernetes) resolveHelperImage(role, arch string) string {
+	staticConfig := k.AccessControlClientset().staticConfig
+	if cfg, ok := staticConfig.HelperImages[role]; ok {
+		if image := cfg.Arch[arch]; arch != "" && image != "" {
+			return image
+		}
+		if cfg.Image != "" {
+			return cfg.Image
+		}
+	}
+	image, ok := defaultHelperImages[role]
+	if !ok || staticConfig.HelperImageRegistry == "" {
+		return image
+	}
+	// Default images are always fully qualified, replace the upstream registry host with the mirror
+	_, repository, _ := strings.Cut(image, "/")
+	return strings.TrimSuffix(staticConfig.HelperImageRegistry, "/") + "/" + repository
+}
+
+// helperImageFailure returns the likely cause of a helper container failure due to its image, empty if none:
+// an image built for another architecture than the node's (exec format error), or an image overridden without the
+// busybox-compatible commands run by the helper pods (exit code 127, command not found)
+func helperImageFailure(role, image, arch string, terminated *v1.ContainerStateTerminated, logs string) string {
+	output := logs
+	exitCode := int32(0)
+	if terminated != nil {
+		output += "\n" + terminated.Message
+		exitCode = terminated.ExitCode
+	}
+	switch {
+	case strings.Contains(output, "exec format error"):
+		if arch == "" {
+			arch = "node"
+		}
+		return fmt.Sprintf("image %s can't run on the %s architecture, configure a multi-arch image or an image for the architecture in helper_images.%s.arch",
+			image, arch, role)
+	case exitCode == 127 || strings.Contains(output, "executable file not found"):
+		return fmt.Sprintf("a command is missing from image %s, the %s helper pods run busybox-compatible commands", image, role)
+	}
+	return ""
+}
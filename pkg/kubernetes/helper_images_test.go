@@ -0,0 +1,162 @@
+package kubernetes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type HelperImagesSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+}
+
+func (s *HelperImagesSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/amd64-node":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "amd64-node", Labels: map[string]string{v1.LabelArchStable: "amd64"}},
+			})
+		case "/api/v1/nodes/arm64-node":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "arm64-node", Labels: map[string]string{v1.LabelArchStable: "arm64"}},
+			})
+		case "/api/v1/nodes/unlabeled-node":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "unlabeled-node"},
+				Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{Architecture: "arm64"}},
+			})
+		}
+	}))
+}
+
+func (s *HelperImagesSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *HelperImagesSuite) derived(toml string) *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(toml)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *HelperImagesSuite) TestHelperImageDefaults() {
+	k := s.derived(``)
+	s.Run("returns multi-arch default image without node", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageBusybox, "")
+		s.Require().NoError(err)
+		s.Equal("docker.io/library/busybox:1.37", image)
+	})
+	s.Run("returns multi-arch default image for arm64 node", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageBusybox, "arm64-node")
+		s.Require().NoError(err)
+		s.Equal("docker.io/library/busybox:1.37", image)
+	})
+	s.Run("returns error for unknown role", func() {
+		_, err := k.HelperImage(s.T().Context(), "unknown", "")
+		s.EqualError(err, "no helper image configured for role unknown")
+	})
+	s.Run("returns error for missing node", func() {
+		_, err := k.HelperImage(s.T().Context(), HelperImageBusybox, "missing-node")
+		s.ErrorContains(err, "failed to get node missing-node")
+	})
+}
+
+func (s *HelperImagesSuite) TestHelperImageArchOverrides() {
+	k := s.derived(`
+		[helper_images.busybox]
+		image = "registry.example.com/busybox:1.37"
+		[helper_images.busybox.arch]
+		arm64 = "registry.example.com/arm64v8/busybox:1.37"
+	`)
+	s.Run("returns configured image for amd64 node", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageBusybox, "amd64-node")
+		s.Require().NoError(err)
+		s.Equal("registry.example.com/busybox:1.37", image)
+	})
+	s.Run("returns arch specific image for arm64 node", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageBusybox, "arm64-node")
+		s.Require().NoError(err)
+		s.Equal("registry.example.com/arm64v8/busybox:1.37", image)
+	})
+	s.Run("returns arch specific image for node without arch label using node info", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageBusybox, "unlabeled-node")
+		s.Require().NoError(err)
+		s.Equal("registry.example.com/arm64v8/busybox:1.37", image)
+	})
+	s.Run("returns default image for roles without overrides", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageDebug, "arm64-node")
+		s.Require().NoError(err)
+		s.Equal("docker.io/nicolaka/netshoot:v0.14", image)
+	})
+}
+
+func (s *HelperImagesSuite) TestHelperImageAirGapped() {
+	k := s.derived(`
+		helper_image_registry = "mirror.example.com:5000/upstream/"
+		helper_image_pull_secrets = ["mirror-pull-secret"]
+		[helper_images.debug]
+		image = "mirror.example.com:5000/tools/debug:1.0"
+		pull_secrets = ["debug-pull-secret", "mirror-pull-secret"]
+	`)
+	s.Run("default images are pulled from mirror registry", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageMustGather, "")
+		s.Require().NoError(err)
+		s.Equal("mirror.example.com:5000/upstream/openshift/origin-must-gather:latest", image)
+	})
+	s.Run("explicitly configured images are not rewritten", func() {
+		image, err := k.HelperImage(s.T().Context(), HelperImageDebug, "")
+		s.Require().NoError(err)
+		s.Equal("mirror.example.com:5000/tools/debug:1.0", image)
+	})
+	s.Run("pull secrets include global pull secrets", func() {
+		s.Equal([]v1.LocalObjectReference{{Name: "mirror-pull-secret"}}, k.HelperImagePullSecrets(HelperImageBusybox))
+	})
+	s.Run("pull secrets include role pull secrets without duplicates", func() {
+		s.Equal([]v1.LocalObjectReference{{Name: "debug-pull-secret"}, {Name: "mirror-pull-secret"}}, k.HelperImagePullSecrets(HelperImageDebug))
+	})
+}
+
+func (s *HelperImagesSuite) TestHelperImageFailure() {
+	s.Run("detects images built for another architecture", func() {
+		cause := helperImageFailure(HelperImageBusybox, "registry.example.com/busybox:1.37", "arm64",
+			&v1.ContainerStateTerminated{ExitCode: 1}, "exec /bin/sh: exec format error\n")
+		s.Equal("image registry.example.com/busybox:1.37 can't run on the arm64 architecture, "+
+			"configure a multi-arch image or an image for the architecture in helper_images.busybox.arch", cause)
+	})
+	s.Run("detects missing commands from the exit code", func() {
+		cause := helperImageFailure(HelperImageBusybox, "registry.example.com/ubi-minimal:9", "amd64",
+			&v1.ContainerStateTerminated{ExitCode: 127}, "sh: sha256sum: command not found\n")
+		s.Equal("a command is missing from image registry.example.com/ubi-minimal:9, the busybox helper pods run busybox-compatible commands", cause)
+	})
+	s.Run("detects missing commands from the termination message", func() {
+		cause := helperImageFailure(HelperImageDebug, "registry.example.com/distroless:1", "",
+			&v1.ContainerStateTerminated{ExitCode: 128, Message: `exec: "sh": executable file not found in $PATH`}, "")
+		s.Equal("a command is missing from image registry.example.com/distroless:1, the debug helper pods run busybox-compatible commands", cause)
+	})
+	s.Run("returns empty for other failures", func() {
+		s.Empty(helperImageFailure(HelperImageBusybox, "docker.io/library/busybox:1.37", "amd64",
+			&v1.ContainerStateTerminated{ExitCode: 1}, "cat: can't open '/host/missing': No such file or directory\n"))
+	})
+}
+
+func TestHelperImages(t *testing.T) {
+	suite.Run(t, new(HelperImagesSuite))
+}
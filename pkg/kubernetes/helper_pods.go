@@ -0,0 +1,296 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+const (
+	// defaultHelperPodTimeout is the maximum time to wait for a helper pod to complete
+	defaultHelperPodTimeout = 2 * time.Minute
+	// HelperPodHostRoot is the path where the node root filesystem is mounted in the helper pod container (HostRoot)
+	HelperPodHostRoot = "/host"
+	// helperPodNonRootUser is the user of the Restricted helper pods (nobody), the helper images run as root by default
+	helperPodNonRootUser = int64(65534)
+	// windowsHostProcessUser is the user of the Windows HostProcess helper pods, with access to every node file
+	windowsHostProcessUser = "NT AUTHORITY\\SYSTEM"
+)
+
+// HelperPodOptions describes a short-lived helper pod created on behalf of a tool
+type HelperPodOptions struct {
+	// Role of the helper pod, selects the image (HelperImageBusybox, HelperImageDebug, ...)
+	Role string
+	// NodeName pins the helper pod to the provided node (Optional)
+	NodeName string
+	// Namespace where the helper pod is created (Optional, defaults to the configured namespace)
+	Namespace string
+	// Labels added to the helper pod, e.g. to match the selectors of NetworkPolicies (Optional).
+	// The labels that identify the helper pods managed by the server take precedence.
+	Labels map[string]string
+	// Command to run in the helper pod container, its output is returned
+	Command []string
+	// HostNetwork runs the helper pod in the node network namespace
+	HostNetwork bool
+	// HostPID runs the helper pod in the node PID namespace
+	HostPID bool
+	// HostRoot mounts the node root filesystem in the helper pod container at HelperPodHostRoot
+	HostRoot bool
+	// ReadOnlyHostRoot mounts the node root filesystem read-only (only applicable with HostRoot and HostPaths)
+	ReadOnlyHostRoot bool
+	// HostPaths are the absolute node paths mounted in the helper pod container under HelperPodHostRoot (e.g.
+	// /var/log is mounted at /host/var/log), an alternative to HostRoot limited to the provided paths
+	HostPaths []string
+	// Privileged runs the helper pod container in privileged mode
+	Privileged bool
+	// Restricted runs the helper pod container with the security context required by the restricted Pod Security
+	// Standard (non-root user, no privilege escalation, no capabilities, RuntimeDefault seccomp profile)
+	Restricted bool
+	// WindowsHostProcess runs the helper pod as a Windows HostProcess container, in the node network (required), with
+	// access to the node filesystem, the equivalent of the privileged helper pods on Windows nodes (HostRoot, HostPaths, Privileged,
+	// and Restricted don't apply)
+	WindowsHostProcess bool
+	// Env are the environment variables of the helper pod container, e.g. the arguments of the PowerShell scripts,
+	// which can't take positional parameters (Optional)
+	Env []v1.EnvVar
+	// Tolerations of the helper pod, e.g. to run on tainted control plane nodes (Optional)
+	Tolerations []v1.Toleration
+	// ImagePullSecrets are the names of image pull secrets added to the configured ones (Optional)
+	ImagePullSecrets []string
+	// Resources are the compute resource requests and limits of the helper pod container (Optional)
+	Resources v1.ResourceRequirements
+	// Timeout is the maximum time to wait for the helper pod to complete (defaults to 2 minutes)
+	Timeout time.Duration
+}
+
+// RunHelperPod creates a helper pod in the configured namespace, waits for it to complete and returns its logs.
+// The helper pod is always deleted before returning.
+func (k *Kubernetes) RunHelperPod(ctx context.Context, options HelperPodOptions) (string, error) {
+	image, arch, err := k.helperImage(ctx, options.Role, options.NodeName)
+	if err != nil {
+		return "", err
+	}
+	name := k.helperPodName(options.Role)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   k.NamespaceOrDefault(options.Namespace),
+			Labels:      k.helperPodLabels(name, options.Role, options.Labels),
+			Annotations: k.helperPodAnnotations(),
+		},
+		Spec: v1.PodSpec{
+			NodeName:                      options.NodeName,
+			OS:                            options.os(),
+			HostNetwork:                   options.HostNetwork || options.WindowsHostProcess,
+			HostPID:                       options.HostPID,
+			RestartPolicy:                 v1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: ptr.To(int64(0)),
+			Tolerations:                   options.Tolerations,
+			ImagePullSecrets:              options.imagePullSecrets(k.HelperImagePullSecrets(options.Role)),
+			Volumes:                       options.volumes(),
+			Containers: []v1.Container{{
+				Name:            options.Role,
+				Image:           image,
+				Command:         options.Command,
+				Env:             options.Env,
+				VolumeMounts:    options.volumeMounts(),
+				SecurityContext: options.securityContext(),
+				Resources:       options.Resources,
+			}},
+		},
+	}
+	if err = k.preflight(ctx, pod.Namespace, pod.Name, ResourcePermission{Verb: "create", Resource: "pods"}); err != nil {
+		return "", fmt.Errorf("failed to create helper pod: %w", err)
+	}
+	pods := k.AccessControlClientset().CoreV1().Pods(pod.Namespace)
+	if options.NodeName != "" {
+		ReportProgress(ctx, "Creating helper pod %s/%s on node %s", pod.Namespace, name, options.NodeName)
+	} else {
+		ReportProgress(ctx, "Creating helper pod %s/%s", pod.Namespace, name)
+	}
+	if _, err = pods.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return "", fmt.Errorf("failed to create helper pod: %w", err)
+	}
+	defer func() {
+		// Use a fresh context, the helper pod must be removed even if the tool call was cancelled
+		_ = pods.Delete(context.Background(), name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0))})
+	}()
+	var phase v1.PodPhase
+	var status string
+	var terminated *v1.ContainerStateTerminated
+	err = wait.PollUntilContextTimeout(ctx, time.Second, helperTimeout(ctx, options.Timeout), true, func(ctx context.Context) (bool, error) {
+		current, err := pods.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase = current.Status.Phase
+		for _, containerStatus := range current.Status.ContainerStatuses {
+			if containerStatus.Name == options.Role {
+				terminated = containerStatus.State.Terminated
+			}
+		}
+		// Report the status changes so that slow image pulls or scheduling issues are visible before the timeout
+		if current := helperPodStatus(current); current != status {
+			status = current
+			ReportProgress(ctx, "Waiting for helper pod %s to complete: %s", name, status)
+		}
+		return phase == v1.PodSucceeded || phase == v1.PodFailed, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("helper pod %s did not complete (phase %s): %w", name, phase, err)
+	}
+	ReportProgress(ctx, "Reading helper pod %s output", name)
+	logs, err := pods.GetLogs(name, &v1.PodLogOptions{Container: options.Role}).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get helper pod %s logs: %w", name, err)
+	}
+	if phase == v1.PodFailed {
+		if cause := helperImageFailure(options.Role, image, arch, terminated, string(logs)); cause != "" {
+			return "", fmt.Errorf("helper pod %s failed (%s): %s", name, cause, string(logs))
+		}
+		return "", fmt.Errorf("helper pod %s failed: %s", name, string(logs))
+	}
+	return string(logs), nil
+}
+
+// imagePullSecrets returns the configured image pull secrets followed by the provided ones that are not configured
+// helperTimeout returns the maximum time to wait for a helper to complete, the provided timeout or, if unset, the
+// defaultHelperPodTimeout extended up to the deadline of the context (e.g. a longer timeout configured for the tool)
+func helperTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) > defaultHelperPodTimeout {
+		return time.Until(deadline)
+	}
+	return defaultHelperPodTimeout
+}
+
+// helperPodStatus describes the status of a helper pod, the waiting reason of its container (e.g. ContainerCreating,
+// ImagePullBackOff) is more helpful than the Pending phase
+func helperPodStatus(pod *v1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return string(pod.Status.Phase) + " (" + status.State.Waiting.Reason + ")"
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
+func (o HelperPodOptions) imagePullSecrets(configured []v1.LocalObjectReference) []v1.LocalObjectReference {
+	pullSecrets := configured
+	for _, name := range o.ImagePullSecrets {
+		if !slices.Contains(pullSecrets, v1.LocalObjectReference{Name: name}) {
+			pullSecrets = append(pullSecrets, v1.LocalObjectReference{Name: name})
+		}
+	}
+	return pullSecrets
+}
+
+func (o HelperPodOptions) os() *v1.PodOS {
+	if o.WindowsHostProcess {
+		return &v1.PodOS{Name: v1.Windows}
+	}
+	return nil
+}
+
+func (o HelperPodOptions) volumes() []v1.Volume {
+	if o.WindowsHostProcess {
+		return nil
+	}
+	var volumes []v1.Volume
+	if o.HostRoot {
+		volumes = append(volumes, v1.Volume{
+			Name:         "host-root",
+			VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/"}},
+		})
+	}
+	for i, hostPath := range o.HostPaths {
+		volumes = append(volumes, v1.Volume{
+			Name:         fmt.Sprintf("host-path-%d", i),
+			VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: hostPath}},
+		})
+	}
+	return volumes
+}
+
+func (o HelperPodOptions) volumeMounts() []v1.VolumeMount {
+	if o.WindowsHostProcess {
+		return nil
+	}
+	var volumeMounts []v1.VolumeMount
+	if o.HostRoot {
+		volumeMounts = append(volumeMounts, v1.VolumeMount{Name: "host-root", MountPath: HelperPodHostRoot, ReadOnly: o.ReadOnlyHostRoot})
+	}
+	for i, hostPath := range o.HostPaths {
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      fmt.Sprintf("host-path-%d", i),
+			MountPath: path.Join(HelperPodHostRoot, hostPath),
+			ReadOnly:  o.ReadOnlyHostRoot,
+		})
+	}
+	return volumeMounts
+}
+
+func (o HelperPodOptions) securityContext() *v1.SecurityContext {
+	switch {
+	case o.WindowsHostProcess:
+		return &v1.SecurityContext{WindowsOptions: &v1.WindowsSecurityContextOptions{
+			HostProcess:   ptr.To(true),
+			RunAsUserName: ptr.To(windowsHostProcessUser),
+		}}
+	case o.Privileged:
+		return &v1.SecurityContext{Privileged: ptr.To(true)}
+	case o.Restricted:
+		return &v1.SecurityContext{
+			RunAsNonRoot:             ptr.To(true),
+			RunAsUser:                ptr.To(helperPodNonRootUser),
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities:             &v1.Capabilities{Drop: []v1.Capability{"ALL"}},
+			SeccompProfile:           &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+		}
+	}
+	return nil
+}
+
+// helperPodName returns a unique name for a helper pod (or DaemonSet) with the provided role, prefixed with the
+// configured helper_pod_name_prefix
+func (k *Kubernetes) helperPodName(role string) string {
+	prefix := k.AccessControlClientset().staticConfig.HelperPodNamePrefix
+	if prefix == "" {
+		prefix = version.BinaryName
+	}
+	return strings.TrimSuffix(prefix, "-") + "-" + role + "-" + rand.String(5)
+}
+
+// helperPodLabels returns the configured helper_pod_labels and the provided extra labels with the labels that
+// identify the helper pods managed by the server, which take precedence
+func (k *Kubernetes) helperPodLabels(name, role string, extra map[string]string) map[string]string {
+	labels := maps.Clone(k.AccessControlClientset().staticConfig.HelperPodLabels)
+	if labels == nil {
+		labels = make(map[string]string, 4+len(extra))
+	}
+	maps.Copy(labels, extra)
+	labels[AppKubernetesName] = name
+	labels[AppKubernetesComponent] = role
+	labels[AppKubernetesManagedBy] = version.BinaryName
+	labels[AppKubernetesPartOf] = version.BinaryName + "-helper"
+	return labels
+}
+
+// helperPodAnnotations returns the configured helper_pod_annotations
+func (k *Kubernetes) helperPodAnnotations() map[string]string {
+	return maps.Clone(k.AccessControlClientset().staticConfig.HelperPodAnnotations)
+}
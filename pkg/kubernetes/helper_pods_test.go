@@ -0,0 +1,290 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type HelperPodsSuite struct {
+	suite.Suite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+}
+
+func (s *HelperPodsSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		return "output of " + pod.Spec.NodeName
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+}
+
+func (s *HelperPodsSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *HelperPodsSuite) derived(toml string) *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(toml)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *HelperPodsSuite) TestRunHelperPod() {
+	k := s.derived(`helper_image_pull_secrets = ["mirror-pull-secret"]`)
+	out, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{
+		Role:        HelperImageBusybox,
+		Command:     []string{"cat", "/proc/sys/vm/max_map_count"},
+		HostNetwork: true,
+	})
+	s.Run("returns helper pod logs", func() {
+		s.Require().NoError(err)
+		s.Equal("output of ", out)
+	})
+	s.Require().Len(s.helperPodHandler.Created(), 1)
+	pod := s.helperPodHandler.Created()[0]
+	s.Run("creates helper pod in configured namespace", func() {
+		s.Equal("default", pod.Namespace)
+		s.Regexp("^kubernetes-mcp-server-busybox-[a-z0-9]{5}$", pod.Name)
+	})
+	s.Run("creates helper pod with managed-by labels", func() {
+		s.Equal("kubernetes-mcp-server", pod.Labels[AppKubernetesManagedBy])
+		s.Equal("kubernetes-mcp-server-helper", pod.Labels[AppKubernetesPartOf])
+		s.Equal(HelperImageBusybox, pod.Labels[AppKubernetesComponent])
+	})
+	s.Run("creates helper pod with provided spec", func() {
+		s.Equal(v1.RestartPolicyNever, pod.Spec.RestartPolicy)
+		s.True(pod.Spec.HostNetwork)
+		s.False(pod.Spec.HostPID)
+		s.Equal([]v1.LocalObjectReference{{Name: "mirror-pull-secret"}}, pod.Spec.ImagePullSecrets)
+		s.Require().Len(pod.Spec.Containers, 1)
+		s.Equal("docker.io/library/busybox:1.37", pod.Spec.Containers[0].Image)
+		s.Equal([]string{"cat", "/proc/sys/vm/max_map_count"}, pod.Spec.Containers[0].Command)
+	})
+	s.Run("deletes helper pod", func() {
+		s.Equal([]string{pod.Name}, s.helperPodHandler.Deleted())
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodConfiguredMetadata() {
+	k := s.derived(`
+		helper_pod_name_prefix = "mcp"
+		[helper_pod_labels]
+		cost-center = "platform"
+		"app.kubernetes.io/managed-by" = "someone-else"
+		[helper_pod_annotations]
+		"pod-security.kubernetes.io/exempt" = "true"
+	`)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{Role: HelperImageBusybox, Command: []string{"true"}})
+	s.Require().NoError(err)
+	s.Require().Len(s.helperPodHandler.Created(), 1)
+	pod := s.helperPodHandler.Created()[0]
+	s.Run("creates helper pod with configured name prefix", func() {
+		s.Regexp("^mcp-busybox-[a-z0-9]{5}$", pod.Name)
+	})
+	s.Run("creates helper pod with configured labels", func() {
+		s.Equal("platform", pod.Labels["cost-center"])
+	})
+	s.Run("configured labels don't override managed-by labels", func() {
+		s.Equal("kubernetes-mcp-server", pod.Labels[AppKubernetesManagedBy])
+		s.Equal(pod.Name, pod.Labels[AppKubernetesName])
+	})
+	s.Run("creates helper pod with configured annotations", func() {
+		s.Equal(map[string]string{"pod-security.kubernetes.io/exempt": "true"}, pod.Annotations)
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodNamespaceAndLabels() {
+	k := s.derived(`
+		[helper_pod_labels]
+		cost-center = "platform"
+	`)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{
+		Role:      HelperImageNetworkTest,
+		Command:   []string{"true"},
+		Namespace: "ns-1",
+		Labels:    map[string]string{"app": "frontend", AppKubernetesManagedBy: "someone-else"},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(s.helperPodHandler.Created(), 1)
+	pod := s.helperPodHandler.Created()[0]
+	s.Run("creates helper pod in provided namespace", func() {
+		s.Equal("ns-1", pod.Namespace)
+	})
+	s.Run("creates helper pod with provided and configured labels", func() {
+		s.Equal("frontend", pod.Labels["app"])
+		s.Equal("platform", pod.Labels["cost-center"])
+	})
+	s.Run("provided labels don't override managed-by labels", func() {
+		s.Equal("kubernetes-mcp-server", pod.Labels[AppKubernetesManagedBy])
+	})
+	s.Run("deletes helper pod", func() {
+		s.Equal([]string{pod.Name}, s.helperPodHandler.Deleted())
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodRestrictedHostPaths() {
+	k := s.derived(``)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{
+		Role:             HelperImageBusybox,
+		Command:          []string{"true"},
+		HostPaths:        []string{"/var/log", "/etc/kubernetes/manifests"},
+		ReadOnlyHostRoot: true,
+		Restricted:       true,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(s.helperPodHandler.Created(), 1)
+	pod := s.helperPodHandler.Created()[0]
+	s.Run("mounts only the provided host paths under the host root", func() {
+		s.Require().Len(pod.Spec.Volumes, 2)
+		s.Equal("/var/log", pod.Spec.Volumes[0].HostPath.Path)
+		s.Equal("/etc/kubernetes/manifests", pod.Spec.Volumes[1].HostPath.Path)
+		s.Equal([]v1.VolumeMount{
+			{Name: "host-path-0", MountPath: "/host/var/log", ReadOnly: true},
+			{Name: "host-path-1", MountPath: "/host/etc/kubernetes/manifests", ReadOnly: true},
+		}, pod.Spec.Containers[0].VolumeMounts)
+	})
+	s.Run("sets the restricted security context", func() {
+		securityContext := pod.Spec.Containers[0].SecurityContext
+		s.Nil(securityContext.Privileged)
+		s.True(*securityContext.RunAsNonRoot)
+		s.Equal(int64(65534), *securityContext.RunAsUser)
+		s.False(*securityContext.AllowPrivilegeEscalation)
+		s.Equal([]v1.Capability{"ALL"}, securityContext.Capabilities.Drop)
+		s.Equal(v1.SeccompProfileTypeRuntimeDefault, securityContext.SeccompProfile.Type)
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodWindowsHostProcess() {
+	k := s.derived(``)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{
+		Role:               HelperImageWindowsHostProcess,
+		Command:            powerShellCommand(`Get-Item -LiteralPath $env:NODE_PATH`),
+		Env:                []v1.EnvVar{{Name: "NODE_PATH", Value: "C:/var/log"}},
+		WindowsHostProcess: true,
+		HostRoot:           true,
+		Privileged:         true,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(s.helperPodHandler.Created(), 1)
+	pod := s.helperPodHandler.Created()[0]
+	s.Run("runs on Windows in the host network", func() {
+		s.Equal(&v1.PodOS{Name: v1.Windows}, pod.Spec.OS)
+		s.True(pod.Spec.HostNetwork)
+	})
+	s.Run("uses the HostProcess image", func() {
+		s.Equal("mcr.microsoft.com/oss/kubernetes/windows-host-process-containers-base-image:v1.0.0", pod.Spec.Containers[0].Image)
+	})
+	s.Run("sets the HostProcess security context", func() {
+		securityContext := pod.Spec.Containers[0].SecurityContext
+		s.Nil(securityContext.Privileged)
+		s.True(*securityContext.WindowsOptions.HostProcess)
+		s.Equal(`NT AUTHORITY\SYSTEM`, *securityContext.WindowsOptions.RunAsUserName)
+	})
+	s.Run("doesn't mount the host root", func() {
+		s.Empty(pod.Spec.Volumes)
+		s.Empty(pod.Spec.Containers[0].VolumeMounts)
+	})
+	s.Run("passes the arguments as environment variables", func() {
+		s.Equal([]v1.EnvVar{{Name: "NODE_PATH", Value: "C:/var/log"}}, pod.Spec.Containers[0].Env)
+		s.Equal("powershell.exe", pod.Spec.Containers[0].Command[0])
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodFailed() {
+	s.helperPodHandler.Phase = v1.PodFailed
+	k := s.derived(``)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{Role: HelperImageBusybox, Command: []string{"false"}})
+	s.Run("returns error with helper pod logs", func() {
+		s.Require().Error(err)
+		s.Regexp("^helper pod kubernetes-mcp-server-busybox-[a-z0-9]{5} failed: output of $", err.Error())
+	})
+	s.Run("deletes helper pod", func() {
+		s.Len(s.helperPodHandler.Deleted(), 1)
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodFailedImage() {
+	s.helperPodHandler.Phase = v1.PodFailed
+	s.helperPodHandler.Logs = func(pod *v1.Pod) string {
+		return "exec /bin/sh: exec format error"
+	}
+	k := s.derived(`
+		[helper_images.busybox]
+		image = "registry.example.com/amd64/busybox:1.37"
+	`)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{Role: HelperImageBusybox, Command: []string{"true"}})
+	s.Run("returns error with the image failure cause", func() {
+		s.Require().Error(err)
+		s.Regexp(`^helper pod kubernetes-mcp-server-busybox-[a-z0-9]{5} failed \(image registry.example.com/amd64/busybox:1.37 can't run on the node architecture, `+
+			`configure a multi-arch image or an image for the architecture in helper_images.busybox.arch\): exec /bin/sh: exec format error$`, err.Error())
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodUnknownRole() {
+	k := s.derived(``)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{Role: "unknown"})
+	s.Run("returns error", func() {
+		s.EqualError(err, "no helper image configured for role unknown")
+	})
+	s.Run("doesn't create helper pod", func() {
+		s.Empty(s.helperPodHandler.Created())
+	})
+}
+
+func (s *HelperPodsSuite) TestRunHelperPodPermissionDenied() {
+	s.mockServer.ResetHandlers()
+	s.mockServer.Handle(test.NewAccessReviewHandler(func(attributes *authorizationv1.ResourceAttributes) bool {
+		return attributes.Verb == "create" && attributes.Resource == "pods"
+	}))
+	s.mockServer.Handle(s.helperPodHandler)
+	k := s.derived(``)
+	_, err := k.RunHelperPod(s.T().Context(), HelperPodOptions{Role: HelperImageBusybox, Command: []string{"true"}})
+	s.Run("returns a permission denied error", func() {
+		var deniedError *PermissionDeniedError
+		s.Require().ErrorAs(err, &deniedError)
+		s.EqualError(err, "failed to create helper pod: you lack permission to create pods in namespace default")
+	})
+	s.Run("doesn't create helper pod", func() {
+		s.Empty(s.helperPodHandler.Created())
+	})
+}
+
+func (s *HelperPodsSuite) TestHelperTimeout() {
+	s.Run("defaults to the helper pod timeout", func() {
+		s.Equal(defaultHelperPodTimeout, helperTimeout(s.T().Context(), 0))
+	})
+	s.Run("provided timeout", func() {
+		ctx, cancel := context.WithTimeout(s.T().Context(), time.Hour)
+		defer cancel()
+		s.Equal(30*time.Second, helperTimeout(ctx, 30*time.Second))
+	})
+	s.Run("extended up to a longer context deadline", func() {
+		ctx, cancel := context.WithTimeout(s.T().Context(), time.Hour)
+		defer cancel()
+		s.InDelta(time.Hour, helperTimeout(ctx, 0), float64(time.Second))
+	})
+	s.Run("keeps the default with a shorter context deadline", func() {
+		ctx, cancel := context.WithTimeout(s.T().Context(), time.Second)
+		defer cancel()
+		s.Equal(defaultHelperPodTimeout, helperTimeout(ctx, 0))
+	})
+}
+
+func TestHelperPods(t *testing.T) {
+	suite.Run(t, new(HelperPodsSuite))
+}
@@ -0,0 +1,262 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/imagescan"
+)
+
+// ImagesInventoryMaxVulnerabilitiesDefault is the default maximum number of vulnerabilities listed for each image,
+// the most severe first (all of them are counted)
+const ImagesInventoryMaxVulnerabilitiesDefault = 10
+
+// ImagesInventory lists the unique images of the containers of the Pods that are not completed, with the workloads
+// using them and, when a scanner is configured, their known vulnerabilities
+type ImagesInventory struct {
+	// Summary is a one line description, e.g. "12 unique images used by 30 containers in all namespaces"
+	Summary string `json:"summary"`
+	// Scanner is the type of the scanner queried for the vulnerabilities (clair or trivy), empty if not queried
+	Scanner string `json:"scanner,omitempty"`
+	// Images are sorted by the severity of their vulnerabilities (if queried) and by name
+	Images   []InventoryImage `json:"images"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// InventoryImage is an image of the inventory, identified by the reference of the container spec
+type InventoryImage struct {
+	Image string `json:"image"`
+	// Digests are the manifest digests of the running image reported by the container runtime, different digests
+	// mean that the nodes pulled different versions of a mutable tag
+	Digests []string `json:"digests,omitempty"`
+	// PullPolicies are the imagePullPolicy of the containers using the image
+	PullPolicies []string `json:"pullPolicies"`
+	Containers   int      `json:"containers"`
+	// Workloads are the workloads (or the Pods without controller) using the image, namespace/Kind/name
+	Workloads       []string              `json:"workloads"`
+	Vulnerabilities *ImageVulnerabilities `json:"vulnerabilities,omitempty"`
+}
+
+// ImageVulnerabilities are the known vulnerabilities of an image reported by the scanner
+type ImageVulnerabilities struct {
+	Total int `json:"total"`
+	// Severities are the number of vulnerabilities by severity
+	Severities map[string]int `json:"severities,omitempty"`
+	// Fixable is the number of vulnerabilities with a fixed version of the package
+	Fixable int `json:"fixable,omitempty"`
+	// Items are the most severe vulnerabilities (up to the maximum of the options)
+	Items []imagescan.Vulnerability `json:"items,omitempty"`
+	// Error is the failure of the scanner for the image
+	Error string `json:"error,omitempty"`
+}
+
+type ImagesInventoryOptions struct {
+	// Namespace of the Pods, all namespaces if empty
+	Namespace string
+	// Vulnerabilities queries the configured scanner for the known vulnerabilities of each image
+	Vulnerabilities bool
+	// MaxVulnerabilities is the maximum number of vulnerabilities listed for each image
+	// (ImagesInventoryMaxVulnerabilitiesDefault if zero)
+	MaxVulnerabilities int
+}
+
+// ImagesInventory returns the ImagesInventory of the Pods of the provided namespace (or all namespaces).
+// The ReplicaSets are resolved to the Deployments owning them, failures listing them are reported as warnings.
+// The vulnerabilities require the image_scanner configuration, the failures of the scanner are reported for each image.
+func (k *Kubernetes) ImagesInventory(ctx context.Context, options ImagesInventoryOptions) (*ImagesInventory, error) {
+	var scanner imagescan.Scanner
+	if options.Vulnerabilities {
+		if scanner = k.NewImageScanner(); scanner == nil {
+			return nil, errors.New("no image scanner configured, set image_scanner.type and image_scanner.url in the configuration")
+		}
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods(options.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	inventory := &ImagesInventory{}
+	workload, err := k.podWorkloads(ctx, options.Namespace)
+	if err != nil {
+		inventory.Warnings = append(inventory.Warnings, err.Error())
+	}
+	inventory.Images = InventoryImages(pods.Items, workload)
+	if scanner != nil {
+		inventory.Scanner = scanner.Name()
+		maxVulnerabilities := options.MaxVulnerabilities
+		if maxVulnerabilities <= 0 {
+			maxVulnerabilities = ImagesInventoryMaxVulnerabilitiesDefault
+		}
+		for i := range inventory.Images {
+			image := &inventory.Images[i]
+			ReportProgress(ctx, "Scanning image %s (%d/%d)", image.Image, i+1, len(inventory.Images))
+			image.Vulnerabilities = scanImage(ctx, scanner, image, maxVulnerabilities)
+		}
+		sortInventoryImages(inventory.Images)
+	}
+	inventory.Summary = inventory.summary(options.Namespace)
+	return inventory, nil
+}
+
+// podWorkloads returns a function returning the workload of a Pod (Kind/name, or Pod/name without controller) where
+// the ReplicaSets are resolved to the Deployments owning them.
+// If the ReplicaSets can't be listed, the function reports the ReplicaSets and the error is returned as a warning.
+func (k *Kubernetes) podWorkloads(ctx context.Context, namespace string) (func(pod *v1.Pod) string, error) {
+	replicaSetOwners := map[string]string{}
+	replicaSets, err := k.AccessControlClientset().AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		err = fmt.Errorf("unable to list the replicasets, the pods of the deployments are reported by replicaset: %w", err)
+	} else {
+		for i := range replicaSets.Items {
+			if owner := metav1.GetControllerOf(&replicaSets.Items[i]); owner != nil {
+				replicaSetOwners[replicaSets.Items[i].Namespace+"/"+replicaSets.Items[i].Name] = owner.Kind + "/" + owner.Name
+			}
+		}
+	}
+	return func(pod *v1.Pod) string {
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil {
+			return "Pod/" + pod.Name
+		}
+		if owner, ok := replicaSetOwners[pod.Namespace+"/"+ref.Name]; ok && ref.Kind == "ReplicaSet" {
+			return owner
+		}
+		return ref.Kind + "/" + ref.Name
+	}, err
+}
+
+// InventoryImages returns the unique images of the containers of the Pods that are not completed, sorted by name.
+// The owner function returns the workload of a Pod (Kind/name).
+func InventoryImages(pods []v1.Pod, owner func(pod *v1.Pod) string) []InventoryImage {
+	images := map[string]*InventoryImage{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		digests := map[string]string{}
+		for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+			for _, status := range statuses {
+				digests[status.Name] = imageDigest(status.ImageID)
+			}
+		}
+		workload := pod.Namespace + "/" + owner(pod)
+		add := func(name, image string, pullPolicy v1.PullPolicy) {
+			entry, ok := images[image]
+			if !ok {
+				entry = &InventoryImage{Image: image, PullPolicies: []string{}, Workloads: []string{}}
+				images[image] = entry
+			}
+			entry.Containers++
+			if digest := digests[name]; digest != "" && !slices.Contains(entry.Digests, digest) {
+				entry.Digests = append(entry.Digests, digest)
+			}
+			if pullPolicy != "" && !slices.Contains(entry.PullPolicies, string(pullPolicy)) {
+				entry.PullPolicies = append(entry.PullPolicies, string(pullPolicy))
+			}
+			if !slices.Contains(entry.Workloads, workload) {
+				entry.Workloads = append(entry.Workloads, workload)
+			}
+		}
+		for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+			add(container.Name, container.Image, container.ImagePullPolicy)
+		}
+		for _, container := range pod.Spec.EphemeralContainers {
+			add(container.Name, container.Image, container.ImagePullPolicy)
+		}
+	}
+	ret := make([]InventoryImage, 0, len(images))
+	for _, image := range images {
+		sort.Strings(image.Digests)
+		sort.Strings(image.PullPolicies)
+		sort.Strings(image.Workloads)
+		ret = append(ret, *image)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Image < ret[j].Image })
+	return ret
+}
+
+// imageDigest returns the manifest digest of the image ID reported by the container runtime,
+// e.g. docker.io/library/nginx@sha256:... or docker-pullable://nginx@sha256:..., empty for local image IDs
+func imageDigest(imageID string) string {
+	if _, digest, ok := strings.Cut(imageID, "@"); ok && strings.Contains(digest, ":") {
+		return digest
+	}
+	return ""
+}
+
+func scanImage(ctx context.Context, scanner imagescan.Scanner, image *InventoryImage, maxVulnerabilities int) *ImageVulnerabilities {
+	digest := ""
+	if len(image.Digests) > 0 {
+		digest = image.Digests[0]
+	}
+	vulnerabilities, err := scanner.Scan(ctx, image.Image, digest)
+	if err != nil {
+		return &ImageVulnerabilities{Error: err.Error()}
+	}
+	ret := &ImageVulnerabilities{Total: len(vulnerabilities), Severities: map[string]int{}}
+	for _, vulnerability := range vulnerabilities {
+		ret.Severities[vulnerability.Severity]++
+		if vulnerability.FixedVersion != "" {
+			ret.Fixable++
+		}
+	}
+	ret.Items = vulnerabilities[:min(len(vulnerabilities), maxVulnerabilities)]
+	return ret
+}
+
+// sortInventoryImages sorts the images by the number of vulnerabilities of each severity, the most vulnerable first
+func sortInventoryImages(images []InventoryImage) {
+	count := func(image InventoryImage, severity string) int {
+		if image.Vulnerabilities == nil {
+			return 0
+		}
+		return image.Vulnerabilities.Severities[severity]
+	}
+	sort.SliceStable(images, func(i, j int) bool {
+		for _, severity := range imagescan.Severities {
+			if ci, cj := count(images[i], severity), count(images[j], severity); ci != cj {
+				return ci > cj
+			}
+		}
+		return false
+	})
+}
+
+func (i *ImagesInventory) summary(namespace string) string {
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = "namespace " + namespace
+	}
+	containers := 0
+	for _, image := range i.Images {
+		containers += image.Containers
+	}
+	summary := fmt.Sprintf("%d unique images used by %d containers in %s", len(i.Images), containers, scope)
+	if i.Scanner == "" {
+		return summary
+	}
+	severities := map[string]int{}
+	total, failed := 0, 0
+	for _, image := range i.Images {
+		if image.Vulnerabilities == nil || image.Vulnerabilities.Error != "" {
+			failed++
+			continue
+		}
+		total += image.Vulnerabilities.Total
+		for severity, count := range image.Vulnerabilities.Severities {
+			severities[severity] += count
+		}
+	}
+	summary += fmt.Sprintf(", %d known vulnerabilities (%d critical, %d high) reported by %s", total, severities[imagescan.SeverityCritical], severities[imagescan.SeverityHigh], i.Scanner)
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d images not scanned", failed)
+	}
+	return summary
+}
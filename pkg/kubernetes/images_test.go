@@ -0,0 +1,129 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/imagescan"
+)
+
+type ImagesSuite struct {
+	suite.Suite
+}
+
+type fakeScanner struct {
+	vulnerabilities map[string][]imagescan.Vulnerability
+}
+
+func (f *fakeScanner) Name() string { return "fake" }
+
+func (f *fakeScanner) Scan(_ context.Context, image, _ string) ([]imagescan.Vulnerability, error) {
+	if vulnerabilities, ok := f.vulnerabilities[image]; ok {
+		return vulnerabilities, nil
+	}
+	return nil, errors.New("image not found")
+}
+
+func inventoryPod(name, owner string, phase v1.PodPhase, containers ...v1.Container) v1.Pod {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Spec:       v1.PodSpec{Containers: containers},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	if owner != "" {
+		pod.Labels = map[string]string{"owner": owner}
+	}
+	for _, container := range containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+			Name: container.Name, ImageID: "docker.io/library/" + container.Name + "@sha256:" + name,
+		})
+	}
+	return pod
+}
+
+func (s *ImagesSuite) TestInventoryImages() {
+	images := InventoryImages([]v1.Pod{
+		inventoryPod("web-1", "Deployment/web", v1.PodRunning,
+			v1.Container{Name: "nginx", Image: "nginx:1.27", ImagePullPolicy: v1.PullIfNotPresent},
+			v1.Container{Name: "envoy", Image: "envoyproxy/envoy:v1.31", ImagePullPolicy: v1.PullIfNotPresent}),
+		inventoryPod("web-2", "Deployment/web", v1.PodRunning,
+			v1.Container{Name: "nginx", Image: "nginx:1.27", ImagePullPolicy: v1.PullIfNotPresent}),
+		inventoryPod("debug", "", v1.PodPending,
+			v1.Container{Name: "nginx", Image: "nginx:1.27", ImagePullPolicy: v1.PullAlways}),
+		inventoryPod("migration", "Job/migrate", v1.PodSucceeded,
+			v1.Container{Name: "migrate", Image: "migrate:2"}),
+	}, func(pod *v1.Pod) string {
+		if owner := pod.Labels["owner"]; owner != "" {
+			return owner
+		}
+		return "Pod/" + pod.Name
+	})
+	s.Require().Len(images, 2, "the images of the completed pods are not reported")
+	s.Equal(InventoryImage{
+		Image:        "envoyproxy/envoy:v1.31",
+		Digests:      []string{"sha256:web-1"},
+		PullPolicies: []string{"IfNotPresent"},
+		Containers:   1,
+		Workloads:    []string{"shop/Deployment/web"},
+	}, images[0])
+	s.Equal(InventoryImage{
+		Image:        "nginx:1.27",
+		Digests:      []string{"sha256:debug", "sha256:web-1", "sha256:web-2"},
+		PullPolicies: []string{"Always", "IfNotPresent"},
+		Containers:   3,
+		Workloads:    []string{"shop/Deployment/web", "shop/Pod/debug"},
+	}, images[1])
+}
+
+func (s *ImagesSuite) TestImageDigest() {
+	s.Equal("sha256:abc", imageDigest("docker.io/library/nginx@sha256:abc"))
+	s.Equal("sha256:abc", imageDigest("docker-pullable://nginx@sha256:abc"))
+	s.Empty(imageDigest("sha256:0123456789"), "local image IDs are not manifest digests")
+	s.Empty(imageDigest(""))
+}
+
+func (s *ImagesSuite) TestScanImages() {
+	scanner := &fakeScanner{vulnerabilities: map[string][]imagescan.Vulnerability{
+		"nginx:1.27": {
+			{ID: "CVE-2", Severity: imagescan.SeverityCritical, Package: "curl", FixedVersion: "8.0"},
+			{ID: "CVE-1", Severity: imagescan.SeverityHigh, Package: "openssl"},
+			{ID: "CVE-3", Severity: imagescan.SeverityLow, Package: "zlib"},
+		},
+		"busybox:1.37": {},
+	}}
+	inventory := &ImagesInventory{Scanner: scanner.Name(), Images: []InventoryImage{
+		{Image: "busybox:1.37", Containers: 1},
+		{Image: "internal/app:1.0", Containers: 1},
+		{Image: "nginx:1.27", Containers: 2},
+	}}
+	for i := range inventory.Images {
+		inventory.Images[i].Vulnerabilities = scanImage(s.T().Context(), scanner, &inventory.Images[i], 2)
+	}
+	sortInventoryImages(inventory.Images)
+	s.Run("sorts the images by vulnerabilities", func() {
+		s.Equal("nginx:1.27", inventory.Images[0].Image)
+	})
+	s.Run("counts all the vulnerabilities and lists the most severe", func() {
+		vulnerabilities := inventory.Images[0].Vulnerabilities
+		s.Equal(3, vulnerabilities.Total)
+		s.Equal(1, vulnerabilities.Fixable)
+		s.Equal(map[string]int{"critical": 1, "high": 1, "low": 1}, vulnerabilities.Severities)
+		s.Len(vulnerabilities.Items, 2)
+	})
+	s.Run("reports the scanner failures by image", func() {
+		s.Equal("image not found", inventory.Images[2].Vulnerabilities.Error)
+	})
+	s.Run("summary", func() {
+		s.Equal("3 unique images used by 4 containers in namespace shop, 3 known vulnerabilities (1 critical, 1 high) reported by fake, 1 images not scanned",
+			inventory.summary("shop"))
+	})
+}
+
+func TestImages(t *testing.T) {
+	suite.Run(t, new(ImagesSuite))
+}
@@ -0,0 +1,107 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultImpersonationUserClaim   = "sub"
+	defaultImpersonationGroupsClaim = "groups"
+)
+
+// impersonated returns a Kubernetes that keeps the Manager credentials and impersonates the MCP caller.
+// The caller identity is only read from the verified OAuth token (require_oauth), never from the request headers.
+// Calls without identity are rejected, they never get the Manager clientset.
+func (m *Manager) impersonated(ctx context.Context) (*Kubernetes, error) {
+	impersonate, err := m.impersonationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(5).Infof("impersonating user %s (groups %v)", impersonate.UserName, impersonate.Groups)
+	impersonatedCfg := rest.CopyConfig(m.accessControlClientset.cfg)
+	impersonatedCfg.Impersonate = impersonate
+	impersonated, err := NewAccessControlClientset(m.staticConfig, m.accessControlClientset.clientCmdConfig, impersonatedCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonated clientset: %w", err)
+	}
+	return &Kubernetes{accessControlClientSet: impersonated, eventStore: m.eventStore}, nil
+}
+
+func (m *Manager) impersonationConfig(ctx context.Context) (rest.ImpersonationConfig, error) {
+	if !m.staticConfig.RequireOAuth {
+		return rest.ImpersonationConfig{}, errors.New("impersonation requires a verified oauth token, require_oauth must be enabled")
+	}
+	authorization, ok := ctx.Value(OAuthAuthorizationHeader).(string)
+	if !ok || !strings.HasPrefix(authorization, "Bearer ") {
+		return rest.ImpersonationConfig{}, errors.New("oauth token required")
+	}
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	// The identity is the one authenticated by the API server for the token
+	if m.staticConfig.ValidateToken {
+		user, _, err := m.VerifyToken(ctx, token, m.staticConfig.OAuthAudience)
+		if err != nil {
+			return rest.ImpersonationConfig{}, fmt.Errorf("failed to verify oauth token: %w", err)
+		}
+		if user.Username == "" {
+			return rest.ImpersonationConfig{}, errors.New("oauth token has no user to impersonate")
+		}
+		return rest.ImpersonationConfig{UserName: user.Username, Groups: user.Groups}, nil
+	}
+	// The claims are only trusted when the HTTP layer verified the signature of the token with the OIDC provider
+	if m.staticConfig.AuthorizationURL == "" {
+		return rest.ImpersonationConfig{}, errors.New("impersonation requires a verified oauth token, authorization_url or validate_token must be configured")
+	}
+	claims, err := tokenClaims(token)
+	if err != nil {
+		return rest.ImpersonationConfig{}, err
+	}
+	userClaim := m.staticConfig.ImpersonationUserClaim
+	if userClaim == "" {
+		userClaim = defaultImpersonationUserClaim
+	}
+	groupsClaim := m.staticConfig.ImpersonationGroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = defaultImpersonationGroupsClaim
+	}
+	user, _ := claims[userClaim].(string)
+	if user == "" {
+		return rest.ImpersonationConfig{}, fmt.Errorf("oauth token has no %s claim to impersonate", userClaim)
+	}
+	var groups []string
+	switch value := claims[groupsClaim].(type) {
+	case string:
+		groups = []string{value}
+	case []any:
+		for _, group := range value {
+			if g, ok := group.(string); ok && g != "" {
+				groups = append(groups, g)
+			}
+		}
+	}
+	return rest.ImpersonationConfig{UserName: user, Groups: groups}, nil
+}
+
+// tokenClaims returns the claims of a JWT, its signature must have been verified by the OIDC provider
+func tokenClaims(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("oauth token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode oauth token claims: %w", err)
+	}
+	claims := map[string]any{}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse oauth token claims: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,292 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+// Job states reported by the jobs tools
+const (
+	JobStatusActive    = "Active"
+	JobStatusSucceeded = "Succeeded"
+	JobStatusFailed    = "Failed"
+	JobStatusSuspended = "Suspended"
+)
+
+const (
+	// defaultJobTimeout is the maximum time to wait for a Job to complete
+	defaultJobTimeout = 5 * time.Minute
+	// jobLogsTailLines is the number of log lines of the last Pod of a Job returned with its status
+	jobLogsTailLines = int64(100)
+	// cronJobInstantiateAnnotation marks the Jobs created manually from a CronJob (kubectl create job --from=cronjob/...)
+	cronJobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+)
+
+type JobsRunOptions struct {
+	Namespace string
+	// Name of the Job (Optional, generated if empty)
+	Name    string
+	Image   string
+	Command []string
+	// BackoffLimit is the number of retries before the Job is considered failed
+	BackoffLimit int32
+	// TTLSecondsAfterFinished deletes the Job (and its Pods) the provided number of seconds after it finishes (Optional)
+	TTLSecondsAfterFinished *int32
+	// Wait blocks until the Job completes and returns the logs of its last Pod
+	Wait bool
+	// Timeout for the Wait condition
+	Timeout time.Duration
+}
+
+type JobsTriggerOptions struct {
+	Namespace string
+	CronJob   string
+	// Wait blocks until the Job completes and returns the logs of its last Pod
+	Wait bool
+	// Timeout for the Wait condition
+	Timeout time.Duration
+}
+
+// JobStatus is a compact view of a Job and, once finished, the logs of its last Pod
+type JobStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// CronJob that created the Job (if any)
+	CronJob        string `json:"cronJob,omitempty"`
+	Status         string `json:"status"`
+	Active         int32  `json:"active"`
+	Succeeded      int32  `json:"succeeded"`
+	Failed         int32  `json:"failed"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	// FailedAt, Reason and Message of the Failed condition
+	FailedAt string `json:"failedAt,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+	// Terminations are the abnormal container terminations of the Job Pods, with the explanation of their exit codes
+	Terminations []ContainerTermination `json:"terminations,omitempty"`
+	// Pod is the most recent Pod of the Job, whose logs are reported
+	Pod  string `json:"pod,omitempty"`
+	Logs string `json:"logs,omitempty"`
+}
+
+// JobsRun creates a Job running the command in the image and optionally waits for it to complete
+func (k *Kubernetes) JobsRun(ctx context.Context, options JobsRunOptions) (*JobStatus, error) {
+	namespace := k.NamespaceOrDefault(options.Namespace)
+	name := options.Name
+	if name == "" {
+		name = version.BinaryName + "-job-" + rand.String(5)
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				AppKubernetesName:      name,
+				AppKubernetesManagedBy: version.BinaryName,
+				AppKubernetesPartOf:    version.BinaryName + "-job",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To(options.BackoffLimit),
+			TTLSecondsAfterFinished: options.TTLSecondsAfterFinished,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers: []v1.Container{{
+						Name:    "job",
+						Image:   options.Image,
+						Command: options.Command,
+					}},
+				},
+			},
+		},
+	}
+	if err := k.preflight(ctx, namespace, job.Name, ResourcePermission{Verb: "create", Group: batchv1.GroupName, Resource: "jobs"}); err != nil {
+		return nil, err
+	}
+	created, err := k.AccessControlClientset().BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{DryRun: dryRun(ctx)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+	return k.jobResult(ctx, created, options.Wait, options.Timeout)
+}
+
+// JobsTrigger creates a Job from the template of the CronJob ("run now", like kubectl create job --from=cronjob/<name>)
+// and optionally waits for it to complete
+func (k *Kubernetes) JobsTrigger(ctx context.Context, options JobsTriggerOptions) (*JobStatus, error) {
+	namespace := k.NamespaceOrDefault(options.Namespace)
+	cronJob, err := k.AccessControlClientset().BatchV1().CronJobs(namespace).Get(ctx, options.CronJob, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{cronJobInstantiateAnnotation: "manual"}
+	for key, value := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[key] = value
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-manual-%s", cronJob.Name, rand.String(5)),
+			Namespace:   namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+	if err = k.preflight(ctx, namespace, job.Name, ResourcePermission{Verb: "create", Group: batchv1.GroupName, Resource: "jobs"}); err != nil {
+		return nil, err
+	}
+	created, err := k.AccessControlClientset().BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{DryRun: dryRun(ctx)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job from cronjob %s: %w", cronJob.Name, err)
+	}
+	return k.jobResult(ctx, created, options.Wait, options.Timeout)
+}
+
+// JobsFailures returns the failed Jobs of the namespace (all namespaces if empty) that finished within the provided
+// duration, most recent first
+func (k *Kubernetes) JobsFailures(ctx context.Context, namespace string, since time.Duration) ([]JobStatus, error) {
+	jobs, err := k.AccessControlClientset().BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-since)
+	failures := make([]JobStatus, 0)
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		condition := jobCondition(job, batchv1.JobFailed)
+		if condition == nil || (since > 0 && condition.LastTransitionTime.Time.Before(cutoff)) {
+			continue
+		}
+		status := jobStatus(job)
+		if pods, err := k.jobPods(ctx, job); err == nil {
+			for _, pod := range pods {
+				for _, termination := range ContainerTerminations(&pod) {
+					termination.Pod = pod.Name
+					status.Terminations = append(status.Terminations, termination)
+				}
+			}
+		}
+		failures = append(failures, *status)
+	}
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].FailedAt > failures[j].FailedAt
+	})
+	return failures, nil
+}
+
+// jobResult returns the status of the created Job, after waiting for its completion if requested
+func (k *Kubernetes) jobResult(ctx context.Context, job *batchv1.Job, waitCompletion bool, timeout time.Duration) (*JobStatus, error) {
+	// Nothing to wait for when the Job was only validated with server-side dry-run
+	if !waitCompletion || IsDryRun(ctx) {
+		return jobStatus(job), nil
+	}
+	if timeout <= 0 {
+		timeout = defaultJobTimeout
+	}
+	jobs := k.AccessControlClientset().BatchV1().Jobs(job.Namespace)
+	var current string
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		latest, err := jobs.Get(ctx, job.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		job = latest
+		status := jobStatus(job)
+		if status.Status != current {
+			current = status.Status
+			ReportProgress(ctx, "Waiting for job %s/%s to complete: %s", job.Namespace, job.Name, current)
+		}
+		return status.Status == JobStatusSucceeded || status.Status == JobStatusFailed, nil
+	})
+	status := jobStatus(job)
+	if pods, podsErr := k.jobPods(ctx, job); podsErr == nil && len(pods) > 0 {
+		last := pods[len(pods)-1]
+		status.Terminations = ContainerTerminations(&last)
+		status.Pod = last.Name
+		logs, logsErr := k.AccessControlClientset().CoreV1().Pods(job.Namespace).
+			GetLogs(last.Name, &v1.PodLogOptions{Container: last.Spec.Containers[0].Name, TailLines: ptr.To(jobLogsTailLines)}).DoRaw(ctx)
+		if logsErr == nil {
+			status.Logs = string(logs)
+		}
+	}
+	if err != nil {
+		return status, fmt.Errorf("job %s did not complete (status %s): %w", job.Name, status.Status, err)
+	}
+	return status, nil
+}
+
+// jobPods returns the Pods of the Job sorted by creation time
+func (k *Kubernetes) jobPods(ctx context.Context, job *batchv1.Job) ([]v1.Pod, error) {
+	if job.Spec.Selector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+	})
+	return pods.Items, nil
+}
+
+// jobStatus returns the compact view of the Job
+func jobStatus(job *batchv1.Job) *JobStatus {
+	status := &JobStatus{
+		Namespace: job.Namespace,
+		Name:      job.Name,
+		Status:    JobStatusActive,
+		Active:    job.Status.Active,
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+	}
+	if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "CronJob" {
+		status.CronJob = owner.Name
+	}
+	if job.Status.StartTime != nil {
+		status.StartTime = job.Status.StartTime.UTC().Format(time.RFC3339)
+	}
+	if job.Status.CompletionTime != nil {
+		status.CompletionTime = job.Status.CompletionTime.UTC().Format(time.RFC3339)
+	}
+	switch {
+	case jobCondition(job, batchv1.JobComplete) != nil:
+		status.Status = JobStatusSucceeded
+	case jobCondition(job, batchv1.JobFailed) != nil:
+		condition := jobCondition(job, batchv1.JobFailed)
+		status.Status, status.Reason, status.Message = JobStatusFailed, condition.Reason, condition.Message
+		status.FailedAt = condition.LastTransitionTime.UTC().Format(time.RFC3339)
+	case ptr.Deref(job.Spec.Suspend, false):
+		status.Status = JobStatusSuspended
+	}
+	return status
+}
+
+// jobCondition returns the condition of the Job with the provided type if its status is True
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == conditionType && job.Status.Conditions[i].Status == v1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
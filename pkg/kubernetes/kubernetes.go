@@ -9,8 +9,12 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+	"github.com/containers/kubernetes-mcp-server/pkg/diagnostics"
 	"github.com/containers/kubernetes-mcp-server/pkg/helm"
+	"github.com/containers/kubernetes-mcp-server/pkg/imagescan"
 	"github.com/containers/kubernetes-mcp-server/pkg/kiali"
+	"github.com/containers/kubernetes-mcp-server/pkg/prometheus"
 )
 
 type HeaderKey string
@@ -18,6 +22,8 @@ type HeaderKey string
 const (
 	CustomAuthorizationHeader = HeaderKey("kubernetes-authorization")
 	OAuthAuthorizationHeader  = HeaderKey("Authorization")
+	// ContextHeader selects the kubeconfig context used by the tool calls of an MCP connection that don't provide one
+	ContextHeader = HeaderKey("kubernetes-context")
 
 	CustomUserAgent = "kubernetes-mcp-server/bearer-token-auth"
 )
@@ -29,6 +35,8 @@ var ParameterCodec = runtime.NewParameterCodec(Scheme)
 
 type Kubernetes struct {
 	accessControlClientSet *AccessControlClientset
+	// eventStore is shared by all the Kubernetes instances derived from the same Manager (nil if disabled)
+	eventStore *EventStore
 }
 
 var _ helm.Kubernetes = (*Kubernetes)(nil)
@@ -50,11 +58,37 @@ func (k *Kubernetes) NewKiali() *kiali.Kiali {
 	return kiali.NewKiali(k.AccessControlClientset().staticConfig, k.AccessControlClientset().cfg)
 }
 
+// NewPrometheus returns a Prometheus client initialized with the metrics toolset configuration of the StaticConfig.
+func (k *Kubernetes) NewPrometheus() *prometheus.Prometheus {
+	return prometheus.NewPrometheus(k.AccessControlClientset().staticConfig)
+}
+
+// NewArtifactStore returns the artifact store initialized with the artifacts configuration of the StaticConfig.
+func (k *Kubernetes) NewArtifactStore() *artifacts.Store {
+	return artifacts.NewStore(k.AccessControlClientset().staticConfig)
+}
+
+// NewDiagnostics returns the diagnostic bundle store initialized with the diagnostics toolset configuration of the StaticConfig.
+func (k *Kubernetes) NewDiagnostics() *diagnostics.Diagnostics {
+	return diagnostics.NewDiagnostics(k.AccessControlClientset().staticConfig)
+}
+
+// NewImageScanner returns the vulnerability scanner of the image_scanner configuration of the StaticConfig, nil if not configured.
+func (k *Kubernetes) NewImageScanner() imagescan.Scanner {
+	return imagescan.NewScanner(k.AccessControlClientset().staticConfig)
+}
+
+// configuredNamespace returns the namespace of the kubeconfig context, or the first of the target namespaces if the
+// server is restricted to namespaces that don't include it
 func (k *Kubernetes) configuredNamespace() string {
-	if ns, _, nsErr := k.AccessControlClientset().ToRawKubeConfigLoader().Namespace(); nsErr == nil {
-		return ns
+	ns, _, nsErr := k.AccessControlClientset().ToRawKubeConfigLoader().Namespace()
+	if nsErr != nil {
+		ns = ""
+	}
+	if k.AccessControlClientset().IsNamespaceAllowed(ns) != nil {
+		return k.AccessControlClientset().staticConfig.TargetNamespaces[0]
 	}
-	return ""
+	return ns
 }
 
 func (k *Kubernetes) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
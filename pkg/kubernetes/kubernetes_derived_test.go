@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/base64"
 	"os"
 	"path/filepath"
 	"strings"
@@ -293,6 +294,109 @@ users:
 	})
 }
 
+func (s *DerivedTestSuite) TestImpersonation() {
+	kubeconfigPath := test.KubeconfigFile(s.T(), test.KubeConfigFake())
+	token := func(claims string) string {
+		return "Bearer " + base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+	}
+
+	s.Run("with impersonation and RequireOAuth=false", func() {
+		testStaticConfig := test.Must(config.ReadToml([]byte(`
+			kubeconfig = "` + strings.ReplaceAll(kubeconfigPath, `\`, `\\`) + `"
+			impersonation = true
+		`)))
+		testManager, err := NewKubeconfigManager(testStaticConfig, "")
+		s.Require().NoErrorf(err, "failed to create test manager: %v", err)
+
+		s.Run("refuses to impersonate without a verified oauth token", func() {
+			_, err := testManager.Derived(s.T().Context())
+			s.EqualError(err, "impersonation requires a verified oauth token, require_oauth must be enabled")
+		})
+		s.Run("refuses to impersonate the claims of the provided token", func() {
+			ctx := context.WithValue(s.T().Context(), OAuthAuthorizationHeader, token(`{"sub":"admin","groups":["system:masters"]}`))
+			_, err := testManager.Derived(ctx)
+			s.EqualError(err, "impersonation requires a verified oauth token, require_oauth must be enabled")
+		})
+	})
+
+	s.Run("with impersonation and RequireOAuth=true", func() {
+		testStaticConfig := test.Must(config.ReadToml([]byte(`
+			kubeconfig = "` + strings.ReplaceAll(kubeconfigPath, `\`, `\\`) + `"
+			require_oauth = true
+			authorization_url = "https://oidc.example.com"
+			impersonation = true
+			impersonation_user_claim = "preferred_username"
+		`)))
+		testManager, err := NewKubeconfigManager(testStaticConfig, "")
+		s.Require().NoErrorf(err, "failed to create test manager: %v", err)
+
+		s.Run("impersonates the identity of the oauth token", func() {
+			ctx := context.WithValue(s.T().Context(), OAuthAuthorizationHeader, token(`{"sub":"1234","preferred_username":"bob","groups":["ops"]}`))
+			derived, err := testManager.Derived(ctx)
+			s.Require().NoError(err)
+			derivedCfg := derived.AccessControlClientset().cfg
+			s.Equal("bob", derivedCfg.Impersonate.UserName)
+			s.Equal([]string{"ops"}, derivedCfg.Impersonate.Groups)
+			s.Empty(derivedCfg.BearerToken, "the oauth token must not be forwarded")
+			s.Run("keeps the server credentials", func() {
+				s.Equal(testManager.accessControlClientset.cfg.CertData, derivedCfg.CertData)
+			})
+			s.Run("keeps the kubeconfig", func() {
+				s.Equal(testManager.accessControlClientset.clientCmdConfig, derived.AccessControlClientset().clientCmdConfig)
+			})
+		})
+		s.Run("with oauth token without user claim returns error", func() {
+			ctx := context.WithValue(s.T().Context(), OAuthAuthorizationHeader, token(`{"sub":"1234"}`))
+			_, err := testManager.Derived(ctx)
+			s.EqualError(err, "oauth token has no preferred_username claim to impersonate")
+		})
+		s.Run("without oauth token returns error", func() {
+			_, err := testManager.Derived(s.T().Context())
+			s.EqualError(err, "oauth token required")
+		})
+	})
+
+	s.Run("with impersonation, RequireOAuth=true and ValidateToken=true", func() {
+		mockServer := test.NewMockServer()
+		defer mockServer.Close()
+		mockServer.Handle(test.NewTokenReviewHandler())
+		testStaticConfig := test.Must(config.ReadToml([]byte(`
+			require_oauth = true
+			validate_token = true
+			impersonation = true
+		`)))
+		testStaticConfig.KubeConfig = mockServer.KubeconfigFile(s.T())
+		testManager, err := NewKubeconfigManager(testStaticConfig, "")
+		s.Require().NoErrorf(err, "failed to create test manager: %v", err)
+
+		s.Run("impersonates the user of the TokenReview instead of the token claims", func() {
+			ctx := context.WithValue(s.T().Context(), OAuthAuthorizationHeader, token(`{"sub":"admin","groups":["system:masters"]}`))
+			derived, err := testManager.Derived(ctx)
+			s.Require().NoError(err)
+			derivedCfg := derived.AccessControlClientset().cfg
+			s.Equal("test-user", derivedCfg.Impersonate.UserName)
+			s.Equal([]string{"system:authenticated"}, derivedCfg.Impersonate.Groups)
+		})
+	})
+
+	s.Run("with impersonation, RequireOAuth=true and no token verification", func() {
+		testStaticConfig := test.Must(config.ReadToml([]byte(`
+			kubeconfig = "` + strings.ReplaceAll(kubeconfigPath, `\`, `\\`) + `"
+			require_oauth = true
+			impersonation = true
+		`)))
+		testManager, err := NewKubeconfigManager(testStaticConfig, "")
+		s.Require().NoErrorf(err, "failed to create test manager: %v", err)
+
+		s.Run("refuses to impersonate the claims of unverified tokens", func() {
+			ctx := context.WithValue(s.T().Context(), OAuthAuthorizationHeader, token(`{"sub":"admin","groups":["system:masters"]}`))
+			_, err := testManager.Derived(ctx)
+			s.EqualError(err, "impersonation requires a verified oauth token, authorization_url or validate_token must be configured")
+		})
+	})
+}
+
 func TestDerived(t *testing.T) {
 	suite.Run(t, new(DerivedTestSuite))
 }
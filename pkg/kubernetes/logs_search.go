@@ -0,0 +1,148 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LogsSearchDefaultMaxPods is the default maximum number of pods whose logs are searched
+	LogsSearchDefaultMaxPods = 20
+	// LogsSearchDefaultMaxMatches is the default maximum number of matching lines returned
+	LogsSearchDefaultMaxMatches = 100
+)
+
+// LogsSearchOptions selects the pods whose logs are searched and the lines that match
+type LogsSearchOptions struct {
+	// Pattern is the regular expression (RE2 syntax) matched against each log line
+	Pattern    string
+	IgnoreCase bool
+	// Namespace of the pods (Optional, all namespaces if empty)
+	Namespace     string
+	LabelSelector string
+	// TailLines is the number of lines searched from the end of the log of each container (DefaultTailLines if <= 0)
+	TailLines int64
+	// MaxPods is the maximum number of pods searched (LogsSearchDefaultMaxPods if <= 0)
+	MaxPods int
+	// MaxMatches is the maximum number of matching lines returned (LogsSearchDefaultMaxMatches if <= 0)
+	MaxMatches int
+}
+
+// LogsSearchResult are the log lines matching the pattern with the pod and container that logged them
+type LogsSearchResult struct {
+	Matches []LogMatch `json:"matches"`
+	// Pods is the number of pods whose logs were searched, TotalPods the number of started pods matching the selector
+	Pods      int `json:"pods"`
+	TotalPods int `json:"totalPods"`
+	// Containers is the number of containers whose logs were searched
+	Containers int `json:"containers"`
+	// DroppedMatches is the number of matching lines not returned because of MaxMatches
+	DroppedMatches int `json:"droppedMatches,omitempty"`
+	// Truncated is true if some pods were not searched (MaxPods) or some matches were dropped (MaxMatches)
+	Truncated bool `json:"truncated"`
+}
+
+// LogMatch is a log line matching the pattern
+type LogMatch struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Line      string `json:"line"`
+}
+
+// logsSearchTarget is a container whose logs are searched
+type logsSearchTarget struct {
+	namespace string
+	pod       string
+	container string
+}
+
+func (t logsSearchTarget) String() string {
+	return "pod/" + t.namespace + "/" + t.pod + "/" + t.container
+}
+
+// LogsSearch searches the pattern in the tail of the logs of the containers of the pods matching the namespace and
+// label selector, up to MaxPods pods (in namespace and name order) whose containers have started.
+// The logs are retrieved concurrently (see FanOut), containers whose logs can't be retrieved (e.g. waiting to start)
+// don't fail the operation, their errors are returned as TargetErrors.
+func (k *Kubernetes) LogsSearch(ctx context.Context, options LogsSearchOptions) (*LogsSearchResult, TargetErrors, error) {
+	pattern := options.Pattern
+	if options.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pattern %q: %w", options.Pattern, err)
+	}
+	if options.TailLines <= 0 {
+		options.TailLines = DefaultTailLines
+	}
+	if options.MaxPods <= 0 {
+		options.MaxPods = LogsSearchDefaultMaxPods
+	}
+	if options.MaxMatches <= 0 {
+		options.MaxMatches = LogsSearchDefaultMaxMatches
+	}
+	podList, err := k.AccessControlClientset().CoreV1().Pods(options.Namespace).List(ctx, metav1.ListOptions{LabelSelector: options.LabelSelector})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods := podList.Items
+	sort.SliceStable(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	result := &LogsSearchResult{Matches: []LogMatch{}}
+	var targets []logsSearchTarget
+	for i := range pods {
+		pod := &pods[i]
+		// Pending pods have no logs yet
+		if pod.Status.Phase == v1.PodPending {
+			continue
+		}
+		result.TotalPods++
+		if result.Pods == options.MaxPods {
+			result.Truncated = true
+			continue
+		}
+		result.Pods++
+		for _, container := range pod.Spec.Containers {
+			targets = append(targets, logsSearchTarget{namespace: pod.Namespace, pod: pod.Name, container: container.Name})
+		}
+	}
+	ReportProgress(ctx, "Searching the logs of %d containers of %d pods", len(targets), result.Pods)
+	results, targetErrors := FanOut(ctx, DefaultFanOutParallelism, targets, logsSearchTarget.String,
+		func(ctx context.Context, target logsSearchTarget) ([]LogMatch, error) {
+			logs, err := k.PodsLog(ctx, target.namespace, target.pod, target.container, false, options.TailLines)
+			if err != nil {
+				return nil, err
+			}
+			var matches []LogMatch
+			for _, line := range strings.Split(logs, "\n") {
+				if line != "" && expression.MatchString(line) {
+					matches = append(matches, LogMatch{Namespace: target.namespace, Pod: target.pod, Container: target.container, Line: line})
+				}
+			}
+			return matches, nil
+		})
+	result.Containers = len(results)
+	for _, r := range results {
+		for _, match := range r.Value {
+			if len(result.Matches) == options.MaxMatches {
+				result.DroppedMatches++
+				result.Truncated = true
+				continue
+			}
+			result.Matches = append(result.Matches, match)
+		}
+	}
+	return result, targetErrors, nil
+}
@@ -0,0 +1,127 @@
+package kubernetes
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type LogsSearchSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+	k          *Kubernetes
+}
+
+func (s *LogsSearchSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	pod := func(namespace, name string, phase v1.PodPhase, containers ...string) v1.Pod {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}, Status: v1.PodStatus{Phase: phase}}
+		for _, container := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: container})
+		}
+		return pod
+	}
+	logs := map[string]string{
+		"/api/v1/namespaces/ns-1/pods/web/log?container=app":     "GET /healthz 200\nERROR connection refused\nGET / 200\n",
+		"/api/v1/namespaces/ns-1/pods/web/log?container=sidecar": "proxy started\nerror: upstream connection refused\n",
+		"/api/v1/namespaces/ns-2/pods/api/log?container=app":     "listening on :8080\n",
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/api/v1/pods" || req.URL.Path == "/api/v1/namespaces/ns-1/pods":
+			pods := []v1.Pod{
+				pod("ns-2", "api", v1.PodRunning, "app"),
+				pod("ns-1", "web", v1.PodRunning, "app", "sidecar"),
+				pod("ns-1", "pending", v1.PodPending, "app"),
+				pod("ns-1", "worker", v1.PodFailed, "app"),
+			}
+			if req.URL.Path != "/api/v1/pods" {
+				pods = pods[1:]
+			}
+			test.WriteObject(w, &v1.PodList{Items: pods})
+		case strings.HasSuffix(req.URL.Path, "/log"):
+			if content, ok := logs[req.URL.Path+"?container="+req.URL.Query().Get("container")]; ok {
+				s.Equal("50", req.URL.Query().Get("tailLines"))
+				_, _ = w.Write([]byte(content))
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":"container \"app\" in pod \"worker\" is terminated","reason":"BadRequest","code":400}`))
+		}
+	}))
+	cfg := test.Must(config.ReadToml([]byte("")))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err)
+	s.k, err = m.Derived(s.T().Context())
+	s.Require().NoError(err)
+}
+
+func (s *LogsSearchSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *LogsSearchSuite) TestLogsSearch() {
+	s.Run("returns the matching lines with their pod and container", func() {
+		result, targetErrors, err := s.k.LogsSearch(s.T().Context(), LogsSearchOptions{Pattern: "connection refused", TailLines: 50})
+		s.Require().NoError(err)
+		s.Equal([]LogMatch{
+			{Namespace: "ns-1", Pod: "web", Container: "app", Line: "ERROR connection refused"},
+			{Namespace: "ns-1", Pod: "web", Container: "sidecar", Line: "error: upstream connection refused"},
+		}, result.Matches)
+		s.Run("skips the pending pods", func() {
+			s.Equal(3, result.Pods)
+			s.Equal(3, result.TotalPods)
+			s.False(result.Truncated)
+		})
+		s.Run("reports the containers without logs as target errors", func() {
+			s.Equal(3, result.Containers)
+			s.Require().Len(targetErrors, 1)
+			s.Equal("pod/ns-1/worker/app", targetErrors[0].Target)
+		})
+	})
+	s.Run("matches case-insensitively", func() {
+		result, _, err := s.k.LogsSearch(s.T().Context(), LogsSearchOptions{Pattern: "^error", IgnoreCase: true, TailLines: 50})
+		s.Require().NoError(err)
+		s.Len(result.Matches, 2)
+	})
+	s.Run("searches the provided namespace", func() {
+		result, _, err := s.k.LogsSearch(s.T().Context(), LogsSearchOptions{Pattern: "listening", Namespace: "ns-1", TailLines: 50})
+		s.Require().NoError(err)
+		s.Empty(result.Matches)
+		s.Equal(2, result.Pods)
+	})
+	s.Run("searches up to max pods", func() {
+		result, _, err := s.k.LogsSearch(s.T().Context(), LogsSearchOptions{Pattern: "listening", TailLines: 50, MaxPods: 1})
+		s.Require().NoError(err)
+		s.Empty(result.Matches, "ns-2/api is not searched")
+		s.Equal(1, result.Pods)
+		s.Equal(3, result.TotalPods)
+		s.True(result.Truncated)
+	})
+	s.Run("returns up to max matches", func() {
+		result, _, err := s.k.LogsSearch(s.T().Context(), LogsSearchOptions{Pattern: "connection refused", TailLines: 50, MaxMatches: 1})
+		s.Require().NoError(err)
+		s.Len(result.Matches, 1)
+		s.Equal(1, result.DroppedMatches)
+		s.True(result.Truncated)
+	})
+	s.Run("rejects invalid patterns", func() {
+		_, _, err := s.k.LogsSearch(s.T().Context(), LogsSearchOptions{Pattern: "error("})
+		s.ErrorContains(err, `invalid pattern "error("`)
+	})
+}
+
+func TestLogsSearch(t *testing.T) {
+	suite.Run(t, new(LogsSearchSuite))
+}
@@ -0,0 +1,293 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/machines"
+)
+
+// MachinesList lists the Machines of the Cluster API and of the OpenShift Machine API
+type MachinesList struct {
+	Summary  string             `json:"summary"`
+	Machines []machines.Machine `json:"machines"`
+	// NotInstalled are the group versions of the machine APIs whose CRDs aren't served by the cluster
+	NotInstalled []string     `json:"notInstalled,omitempty"`
+	TargetErrors TargetErrors `json:"targetErrors,omitempty"`
+}
+
+// MachineSetsList lists the MachineSets of the Cluster API and of the OpenShift Machine API
+type MachineSetsList struct {
+	Summary      string                `json:"summary"`
+	MachineSets  []machines.MachineSet `json:"machineSets"`
+	NotInstalled []string              `json:"notInstalled,omitempty"`
+	TargetErrors TargetErrors          `json:"targetErrors,omitempty"`
+}
+
+// MachinesListOptions selects the Machines
+type MachinesListOptions struct {
+	// APIGroup is the group of the machine API, both APIs if empty
+	APIGroup string
+	// Namespace of the Machines, all namespaces if empty
+	Namespace string
+	// MachineSet selects the Machines of the MachineSet
+	MachineSet string
+}
+
+// NodeMachine is the Machine backing a Node, and the MachineSet of the Machine
+type NodeMachine struct {
+	Summary    string               `json:"summary"`
+	Node       string               `json:"node"`
+	ProviderID string               `json:"providerID,omitempty"`
+	Ready      string               `json:"ready"`
+	Machine    *machines.Machine    `json:"machine,omitempty"`
+	MachineSet *machines.MachineSet `json:"machineSet,omitempty"`
+}
+
+type MachineSetsScaleOptions struct {
+	// APIGroup is the group of the machine API, the installed one if empty
+	APIGroup  string
+	Namespace string
+	Name      string
+	Replicas  int64
+	// Force scales the MachineSet outside of the bounds of the cluster autoscaler
+	Force bool
+}
+
+type MachineSetsScaleResult struct {
+	APIGroup         string   `json:"apiGroup"`
+	Namespace        string   `json:"namespace"`
+	Name             string   `json:"name"`
+	PreviousReplicas int64    `json:"previousReplicas"`
+	Replicas         int64    `json:"replicas"`
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// machineAPIs returns the machine APIs served by the cluster, the API of the group only if not empty.
+// It's an error if none is served.
+func (k *Kubernetes) machineAPIs(group string) (installed []machines.API, notInstalled []string, err error) {
+	apis := machines.APIs
+	if group != "" {
+		api, err := machines.APIFor(group)
+		if err != nil {
+			return nil, nil, err
+		}
+		apis = []machines.API{api}
+	}
+	for _, api := range apis {
+		if k.supportsGroupVersion(api.GroupVersion()) {
+			installed = append(installed, api)
+		} else {
+			notInstalled = append(notInstalled, api.GroupVersion())
+		}
+	}
+	if len(installed) == 0 {
+		return nil, nil, fmt.Errorf("no machine API is installed, the Machine CRDs of %s are not served", strings.Join(notInstalled, " and "))
+	}
+	return installed, notInstalled, nil
+}
+
+// machineNamespace returns the namespace of the Machines and MachineSets, the OpenShift Machine API namespace or the
+// current namespace if not provided
+func (k *Kubernetes) machineNamespace(api machines.API, namespace string) string {
+	if namespace == "" && api.Group == machines.GroupOpenShift {
+		return machines.OpenShiftNamespace
+	}
+	return k.NamespaceOrDefault(namespace)
+}
+
+// MachinesList lists the Machines of the installed machine APIs
+func (k *Kubernetes) MachinesList(ctx context.Context, options MachinesListOptions) (*MachinesList, error) {
+	apis, notInstalled, err := k.machineAPIs(options.APIGroup)
+	if err != nil {
+		return nil, err
+	}
+	ret := &MachinesList{Machines: []machines.Machine{}, NotInstalled: notInstalled}
+	for _, api := range apis {
+		listOptions := metav1.ListOptions{}
+		if options.MachineSet != "" {
+			listOptions.LabelSelector = labels.Set{api.MachineSetLabel: options.MachineSet}.String()
+		}
+		list, err := k.AccessControlClientset().DynamicClient().Resource(api.MachineGVR()).Namespace(options.Namespace).List(ctx, listOptions)
+		if err != nil {
+			ret.TargetErrors.Add("resource/"+api.MachineGVR().GroupResource().String(), err)
+			continue
+		}
+		for i := range list.Items {
+			ret.Machines = append(ret.Machines, *machines.NewMachine(api, &list.Items[i]))
+		}
+	}
+	if len(ret.TargetErrors) == len(apis) {
+		return nil, ret.TargetErrors
+	}
+	notRunning := 0
+	for _, machine := range ret.Machines {
+		if machine.Phase != "Running" {
+			notRunning++
+		}
+	}
+	ret.Summary = fmt.Sprintf("%d Machines (%d not running)", len(ret.Machines), notRunning)
+	if options.MachineSet != "" {
+		ret.Summary += " of MachineSet " + options.MachineSet
+	}
+	ret.Summary += machinesScope(options.Namespace, notInstalled)
+	return ret, nil
+}
+
+// MachineSetsList lists the MachineSets of the installed machine APIs
+func (k *Kubernetes) MachineSetsList(ctx context.Context, apiGroup, namespace string) (*MachineSetsList, error) {
+	apis, notInstalled, err := k.machineAPIs(apiGroup)
+	if err != nil {
+		return nil, err
+	}
+	ret := &MachineSetsList{MachineSets: []machines.MachineSet{}, NotInstalled: notInstalled}
+	for _, api := range apis {
+		list, err := k.AccessControlClientset().DynamicClient().Resource(api.MachineSetGVR()).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			ret.TargetErrors.Add("resource/"+api.MachineSetGVR().GroupResource().String(), err)
+			continue
+		}
+		for i := range list.Items {
+			ret.MachineSets = append(ret.MachineSets, *machines.NewMachineSet(api, &list.Items[i]))
+		}
+	}
+	if len(ret.TargetErrors) == len(apis) {
+		return nil, ret.TargetErrors
+	}
+	var replicas, ready int64
+	for _, machineSet := range ret.MachineSets {
+		replicas += machineSet.Replicas
+		ready += machineSet.ReadyReplicas
+	}
+	ret.Summary = fmt.Sprintf("%d MachineSets (%d of %d replicas ready)", len(ret.MachineSets), ready, replicas) + machinesScope(namespace, notInstalled)
+	return ret, nil
+}
+
+func machinesScope(namespace string, notInstalled []string) string {
+	ret := " in namespace " + namespace
+	if namespace == "" {
+		ret = " in all namespaces"
+	}
+	for _, groupVersion := range notInstalled {
+		ret += ", " + groupVersion + " is not installed"
+	}
+	return ret
+}
+
+// MachinesForNode returns the Machine backing the Node and its MachineSet.
+// The Machine is found from the annotations of the Node, or else from the node reference or the provider ID of the
+// Machines. The Machine is nil for the Nodes not backed by a Machine (e.g. provisioned by hand).
+func (k *Kubernetes) MachinesForNode(ctx context.Context, nodeName string) (*NodeMachine, error) {
+	node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := &NodeMachine{Node: node.Name, ProviderID: node.Spec.ProviderID, Ready: "Unknown"}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			ret.Ready = string(condition.Status)
+		}
+	}
+	apis, _, err := k.machineAPIs("")
+	if err != nil {
+		return nil, err
+	}
+	var api machines.API
+	if annotated, namespace, name, ok := machines.MachineRef(node); ok {
+		obj, err := k.AccessControlClientset().DynamicClient().Resource(annotated.MachineGVR()).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Machine %s/%s of the annotation of node %s: %w", namespace, name, node.Name, err)
+		}
+		api, ret.Machine = annotated, machines.NewMachine(annotated, obj)
+	}
+	for i := 0; ret.Machine == nil && i < len(apis); i++ {
+		list, err := k.AccessControlClientset().DynamicClient().Resource(apis[i].MachineGVR()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", apis[i].MachineGVR().GroupResource(), err)
+		}
+		for j := range list.Items {
+			machine := machines.NewMachine(apis[i], &list.Items[j])
+			if machine.Node == node.Name || (machine.ProviderID != "" && machine.ProviderID == node.Spec.ProviderID) {
+				api, ret.Machine = apis[i], machine
+				break
+			}
+		}
+	}
+	if ret.Machine == nil {
+		ret.Summary = fmt.Sprintf("Node %s (Ready=%s) isn't backed by a Machine of the installed machine APIs", node.Name, ret.Ready)
+		return ret, nil
+	}
+	ret.Summary = fmt.Sprintf("Node %s (Ready=%s) is backed by Machine %s/%s (%s)", node.Name, ret.Ready, ret.Machine.Namespace, ret.Machine.Name, ret.Machine.Phase)
+	if ret.Machine.MachineSet == "" {
+		return ret, nil
+	}
+	obj, err := k.AccessControlClientset().DynamicClient().Resource(api.MachineSetGVR()).Namespace(ret.Machine.Namespace).Get(ctx, ret.Machine.MachineSet, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MachineSet %s/%s: %w", ret.Machine.Namespace, ret.Machine.MachineSet, err)
+	}
+	ret.MachineSet = machines.NewMachineSet(api, obj)
+	ret.Summary += fmt.Sprintf(" of MachineSet %s (%d of %d replicas ready)", ret.MachineSet.Name, ret.MachineSet.ReadyReplicas, ret.MachineSet.Replicas)
+	return ret, nil
+}
+
+// MachineSetsScale scales the MachineSet through its scale subresource.
+// The MachineSets owned by a Cluster API MachineDeployment are not scaled, the MachineDeployment reverts their replicas.
+// The MachineSets managed by the cluster autoscaler are not scaled outside of its bounds unless Force is set.
+func (k *Kubernetes) MachineSetsScale(ctx context.Context, options MachineSetsScaleOptions) (*MachineSetsScaleResult, error) {
+	apis, _, err := k.machineAPIs(options.APIGroup)
+	if err != nil {
+		return nil, err
+	}
+	if len(apis) > 1 {
+		return nil, fmt.Errorf("both %s and %s are installed, the API group of the MachineSet is required", apis[0].GroupVersion(), apis[1].GroupVersion())
+	}
+	api := apis[0]
+	namespace := k.machineNamespace(api, options.Namespace)
+	client := k.AccessControlClientset().DynamicClient().Resource(api.MachineSetGVR()).Namespace(namespace)
+	obj, err := client.Get(ctx, options.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	machineSet := machines.NewMachineSet(api, obj)
+	result := &MachineSetsScaleResult{APIGroup: api.Group, Namespace: namespace, Name: options.Name, PreviousReplicas: machineSet.Replicas, Replicas: options.Replicas}
+	if machineSet.MachineDeployment != "" {
+		return nil, fmt.Errorf("refusing to scale: MachineSet %s is owned by MachineDeployment %s which reverts its replicas, scale the MachineDeployment instead",
+			options.Name, machineSet.MachineDeployment)
+	}
+	if machineSet.MinSize != nil || machineSet.MaxSize != nil {
+		bounds := fmt.Sprintf("MachineSet %s is a node group of the cluster autoscaler (min=%s, max=%s)", options.Name, machinesSize(machineSet.MinSize), machinesSize(machineSet.MaxSize))
+		outOfBounds := (machineSet.MinSize != nil && options.Replicas < *machineSet.MinSize) || (machineSet.MaxSize != nil && options.Replicas > *machineSet.MaxSize)
+		if outOfBounds && !options.Force {
+			return nil, fmt.Errorf("refusing to scale: %s, update the bounds of the autoscaler instead or set force=true to override", bounds)
+		}
+		result.Warnings = append(result.Warnings, bounds+", the autoscaler may override the requested replicas")
+	}
+	if options.Replicas < machineSet.Replicas {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d Machines are deleted, their Nodes are drained and their Pods evicted", machineSet.Replicas-options.Replicas))
+	}
+	if options.Replicas == machineSet.Replicas {
+		return result, nil
+	}
+	patch, err := json.Marshal(map[string]any{"spec": map[string]any{"replicas": options.Replicas}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = client.Patch(ctx, options.Name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun(ctx)}, "scale"); err != nil {
+		return nil, fmt.Errorf("failed to update scale: %w", err)
+	}
+	return result, nil
+}
+
+func machinesSize(size *int64) string {
+	if size == nil {
+		return "none"
+	}
+	return fmt.Sprint(*size)
+}
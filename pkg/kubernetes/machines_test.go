@@ -0,0 +1,201 @@
+package kubernetes
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/machines"
+)
+
+type MachinesSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	patches    []string
+	dryRun     []string
+}
+
+func machinesMachine(name, node, providerID, phase string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "machine.openshift.io/v1beta1", "kind": "Machine",
+		"metadata": map[string]any{"namespace": "openshift-machine-api", "name": name, "labels": map[string]any{
+			"machine.openshift.io/cluster-api-machineset": "worker-a",
+			"machine.openshift.io/instance-type":          "m6i.xlarge",
+		}},
+		"spec":   map[string]any{"providerID": providerID},
+		"status": map[string]any{"phase": phase, "nodeRef": map[string]any{"name": node}},
+	}}
+}
+
+func (s *MachinesSuite) SetupTest() {
+	s.patches, s.dryRun = nil, nil
+	machineList := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "machine.openshift.io/v1beta1", "kind": "MachineList"}}
+	machineList.Items = []unstructured.Unstructured{
+		machinesMachine("worker-a-1", "node-1", "aws:///us-east-1a/i-1", "Running"),
+		machinesMachine("worker-a-2", "", "aws:///us-east-1a/i-2", "Provisioned"),
+	}
+	machineSet := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "machine.openshift.io/v1beta1", "kind": "MachineSet",
+		"metadata": map[string]any{"namespace": "openshift-machine-api", "name": "worker-a", "annotations": map[string]any{
+			"machine.openshift.io/cluster-api-autoscaler-node-group-min-size": "1",
+			"machine.openshift.io/cluster-api-autoscaler-node-group-max-size": "4",
+		}},
+		"spec":   map[string]any{"replicas": int64(2)},
+		"status": map[string]any{"replicas": int64(2), "readyReplicas": int64(1), "availableReplicas": int64(1)},
+	}}
+	nodes := map[string]*v1.Node{
+		"node-1": {ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: map[string]string{"machine.openshift.io/machine": "openshift-machine-api/worker-a-1"}},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}}},
+		"node-2": {ObjectMeta: metav1.ObjectMeta{Name: "node-2"}, Spec: v1.NodeSpec{ProviderID: "aws:///us-east-1a/i-2"}},
+		"node-3": {ObjectMeta: metav1.ObjectMeta{Name: "node-3"}, Spec: v1.NodeSpec{ProviderID: "metal://node-3"}},
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{Groups: []string{
+		`{"name":"machine.openshift.io","versions":[{"groupVersion":"machine.openshift.io/v1beta1","version":"v1beta1"}],"preferredVersion":{"groupVersion":"machine.openshift.io/v1beta1","version":"v1beta1"}}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		const namespacePath = "/apis/machine.openshift.io/v1beta1/namespaces/openshift-machine-api"
+		switch req.URL.Path {
+		case "/apis/machine.openshift.io/v1beta1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"machine.openshift.io/v1beta1","resources":[
+				{"name":"machines","singularName":"machine","namespaced":true,"kind":"Machine","verbs":["get","list"]},
+				{"name":"machinesets","singularName":"machineset","namespaced":true,"kind":"MachineSet","verbs":["get","list","patch"]},
+				{"name":"machinesets/scale","singularName":"","namespaced":true,"kind":"Scale","verbs":["get","patch","update"]}
+			]}`))
+		case "/apis/machine.openshift.io/v1beta1/machines":
+			test.WriteObject(w, machineList)
+		case namespacePath + "/machines/worker-a-1":
+			test.WriteObject(w, &machineList.Items[0])
+		case "/apis/machine.openshift.io/v1beta1/machinesets":
+			list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "machine.openshift.io/v1beta1", "kind": "MachineSetList"}}
+			list.Items = []unstructured.Unstructured{*machineSet}
+			test.WriteObject(w, list)
+		case namespacePath + "/machinesets/worker-a":
+			test.WriteObject(w, machineSet)
+		case namespacePath + "/machinesets/worker-a/scale":
+			body, _ := io.ReadAll(req.Body)
+			s.patches = append(s.patches, string(body))
+			s.dryRun = append(s.dryRun, req.URL.Query().Get("dryRun"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"Scale","apiVersion":"autoscaling/v1","metadata":{"name":"worker-a","namespace":"openshift-machine-api"},"spec":{"replicas":3}}`))
+		case "/api/v1/nodes/node-1", "/api/v1/nodes/node-2", "/api/v1/nodes/node-3":
+			node := nodes[req.URL.Path[len("/api/v1/nodes/"):]]
+			test.WriteObject(w, node)
+		}
+	}))
+}
+
+func (s *MachinesSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *MachinesSuite) derived() *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *MachinesSuite) TestMachinesList() {
+	k := s.derived()
+	result, err := k.MachinesList(s.T().Context(), MachinesListOptions{})
+	s.Require().NoError(err)
+	s.Run("returns the summary with the APIs not installed", func() {
+		s.Equal("2 Machines (1 not running) in all namespaces, cluster.x-k8s.io/v1beta1 is not installed", result.Summary)
+	})
+	s.Run("returns the Machines", func() {
+		s.Require().Len(result.Machines, 2)
+		s.Equal("worker-a", result.Machines[0].MachineSet)
+		s.Equal("m6i.xlarge", result.Machines[0].InstanceType)
+		s.Equal("node-1", result.Machines[0].Node)
+	})
+	s.Run("returns error if the API is not installed", func() {
+		_, err := k.MachinesList(s.T().Context(), MachinesListOptions{APIGroup: machines.GroupClusterAPI})
+		s.EqualError(err, "no machine API is installed, the Machine CRDs of cluster.x-k8s.io/v1beta1 are not served")
+	})
+	s.Run("returns error for unsupported API groups", func() {
+		_, err := k.MachinesList(s.T().Context(), MachinesListOptions{APIGroup: "metal3.io"})
+		s.EqualError(err, "unsupported machine API group metal3.io, supported groups are [cluster.x-k8s.io machine.openshift.io]")
+	})
+}
+
+func (s *MachinesSuite) TestMachineSetsList() {
+	k := s.derived()
+	result, err := k.MachineSetsList(s.T().Context(), "", "")
+	s.Require().NoError(err)
+	s.Equal("1 MachineSets (1 of 2 replicas ready) in all namespaces, cluster.x-k8s.io/v1beta1 is not installed", result.Summary)
+	s.Require().Len(result.MachineSets, 1)
+	s.Equal(int64(4), *result.MachineSets[0].MaxSize)
+}
+
+func (s *MachinesSuite) TestMachinesForNode() {
+	k := s.derived()
+	s.Run("finds the Machine from the annotation of the Node", func() {
+		result, err := k.MachinesForNode(s.T().Context(), "node-1")
+		s.Require().NoError(err)
+		s.Equal("Node node-1 (Ready=True) is backed by Machine openshift-machine-api/worker-a-1 (Running) of MachineSet worker-a (1 of 2 replicas ready)", result.Summary)
+		s.Require().NotNil(result.MachineSet)
+		s.Equal("worker-a", result.MachineSet.Name)
+	})
+	s.Run("finds the Machine from the provider ID of the Node", func() {
+		result, err := k.MachinesForNode(s.T().Context(), "node-2")
+		s.Require().NoError(err)
+		s.Require().NotNil(result.Machine)
+		s.Equal("worker-a-2", result.Machine.Name)
+	})
+	s.Run("reports the Nodes not backed by a Machine", func() {
+		result, err := k.MachinesForNode(s.T().Context(), "node-3")
+		s.Require().NoError(err)
+		s.Nil(result.Machine)
+		s.Equal("Node node-3 (Ready=Unknown) isn't backed by a Machine of the installed machine APIs", result.Summary)
+	})
+}
+
+func (s *MachinesSuite) TestMachineSetsScale() {
+	k := s.derived()
+	s.Run("scales the MachineSet in the OpenShift Machine API namespace", func() {
+		result, err := k.MachineSetsScale(s.T().Context(), MachineSetsScaleOptions{Name: "worker-a", Replicas: 3})
+		s.Require().NoError(err)
+		s.Equal(&MachineSetsScaleResult{
+			APIGroup: machines.GroupOpenShift, Namespace: "openshift-machine-api", Name: "worker-a", PreviousReplicas: 2, Replicas: 3,
+			Warnings: []string{"MachineSet worker-a is a node group of the cluster autoscaler (min=1, max=4), the autoscaler may override the requested replicas"},
+		}, result)
+		s.Require().Len(s.patches, 1)
+		s.JSONEq(`{"spec":{"replicas":3}}`, s.patches[0])
+	})
+	s.Run("warns about the deleted Machines", func() {
+		result, err := k.MachineSetsScale(WithDryRun(s.T().Context()), MachineSetsScaleOptions{Name: "worker-a", Replicas: 1})
+		s.Require().NoError(err)
+		s.Contains(result.Warnings, "1 Machines are deleted, their Nodes are drained and their Pods evicted")
+		s.Equal("All", s.dryRun[len(s.dryRun)-1])
+	})
+	s.Run("refuses to scale outside of the bounds of the cluster autoscaler", func() {
+		_, err := k.MachineSetsScale(s.T().Context(), MachineSetsScaleOptions{Name: "worker-a", Replicas: 6})
+		s.EqualError(err, "refusing to scale: MachineSet worker-a is a node group of the cluster autoscaler (min=1, max=4), update the bounds of the autoscaler instead or set force=true to override")
+	})
+	s.Run("scales outside of the bounds of the cluster autoscaler with force", func() {
+		_, err := k.MachineSetsScale(s.T().Context(), MachineSetsScaleOptions{Name: "worker-a", Replicas: 6, Force: true})
+		s.NoError(err)
+	})
+}
+
+func TestMachines(t *testing.T) {
+	suite.Run(t, new(MachinesSuite))
+}
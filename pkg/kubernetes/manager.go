@@ -19,6 +19,7 @@ import (
 
 type Manager struct {
 	accessControlClientset *AccessControlClientset
+	eventStore             *EventStore
 
 	staticConfig *config.StaticConfig
 }
@@ -48,7 +49,7 @@ func NewKubeconfigManager(config *config.StaticConfig, kubeconfigContext string)
 
 	restConfig, err := clientCmdConfig.ClientConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes rest config from kubeconfig: %v", err)
+		return nil, fmt.Errorf("failed to create kubernetes rest config from kubeconfig: %w", err)
 	}
 
 	return NewManager(config, restConfig, clientCmdConfig)
@@ -65,7 +66,7 @@ func NewInClusterManager(config *config.StaticConfig) (*Manager, error) {
 
 	restConfig, err := InClusterConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create in-cluster kubernetes rest config: %v", err)
+		return nil, fmt.Errorf("failed to create in-cluster kubernetes rest config: %w", err)
 	}
 
 	// Create a dummy kubeconfig clientcmdapi.Config for in-cluster config to be used in places where clientcmd.ClientConfig is required
@@ -74,12 +75,15 @@ func NewInClusterManager(config *config.StaticConfig) (*Manager, error) {
 		Server:                restConfig.Host,
 		InsecureSkipTLSVerify: restConfig.Insecure,
 	}
+	// The token file is kept so that the rotated service account tokens are reloaded
 	clientCmdConfig.AuthInfos["user"] = &clientcmdapi.AuthInfo{
-		Token: restConfig.BearerToken,
+		Token:     restConfig.BearerToken,
+		TokenFile: restConfig.BearerTokenFile,
 	}
 	clientCmdConfig.Contexts[inClusterKubeConfigDefaultContext] = &clientcmdapi.Context{
-		Cluster:  "cluster",
-		AuthInfo: "user",
+		Cluster:   "cluster",
+		AuthInfo:  "user",
+		Namespace: inClusterNamespace(),
 	}
 	clientCmdConfig.CurrentContext = inClusterKubeConfigDefaultContext
 
@@ -97,7 +101,9 @@ func NewManager(config *config.StaticConfig, restConfig *rest.Config, clientCmdC
 		return nil, errors.New("clientCmdConfig cannot be nil")
 	}
 
-	// Apply QPS and Burst from environment variables if set (primarily for testing)
+	// Apply the client settings of the configuration, QPS and Burst from environment variables take precedence
+	// (primarily for testing)
+	applyClientSettings(config, restConfig)
 	applyRateLimitFromEnv(restConfig)
 
 	k8s := &Manager{
@@ -112,9 +118,33 @@ func NewManager(config *config.StaticConfig, restConfig *rest.Config, clientCmdC
 	if err != nil {
 		return nil, err
 	}
+	// the event store and the helper cleanup watch and list across all namespaces, which target_namespaces denies
+	namespaceScoped := len(config.TargetNamespaces) > 0
+	if config.EventStoreSize > 0 && !namespaceScoped && (&AccessControlRoundTripper{staticConfig: config}).isAllowed(eventGVK) {
+		k8s.eventStore = NewEventStore(config.EventStoreSize)
+		k8s.eventStore.Start(k8s.accessControlClientset)
+	}
+	if ttl, _ := config.HelperCleanupDuration(); ttl > 0 && !namespaceScoped {
+		(&Kubernetes{accessControlClientSet: k8s.accessControlClientset}).cleanupHelpersInBackground(ttl)
+	}
 	return k8s, nil
 }
 
+// Close releases the resources held by the manager (e.g. stops the event store)
+func (m *Manager) Close() {
+	if m.eventStore != nil {
+		m.eventStore.Stop()
+	}
+}
+
+// Ready returns an error if the API server can't be reached or isn't ready to serve requests (/readyz)
+func (m *Manager) Ready(ctx context.Context) error {
+	if err := m.accessControlClientset.CoreV1().RESTClient().Get().AbsPath("/readyz").Do(ctx).Error(); err != nil {
+		return fmt.Errorf("API server %s is not ready: %w", m.accessControlClientset.cfg.Host, err)
+	}
+	return nil
+}
+
 func (m *Manager) VerifyToken(ctx context.Context, token, audience string) (*authenticationv1api.UserInfo, []string, error) {
 	tokenReviewClient := m.accessControlClientset.AuthenticationV1().TokenReviews()
 	tokenReview := &authenticationv1api.TokenReview{
@@ -130,7 +160,7 @@ func (m *Manager) VerifyToken(ctx context.Context, token, audience string) (*aut
 
 	result, err := tokenReviewClient.Create(ctx, tokenReview, metav1.CreateOptions{})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create token review: %v", err)
+		return nil, nil, fmt.Errorf("failed to create token review: %w", err)
 	}
 
 	if !result.Status.Authenticated {
@@ -144,14 +174,21 @@ func (m *Manager) VerifyToken(ctx context.Context, token, audience string) (*aut
 }
 
 func (m *Manager) Derived(ctx context.Context) (*Kubernetes, error) {
+	if m.staticConfig.Impersonation {
+		return m.impersonated(ctx)
+	}
 	authorization, ok := ctx.Value(OAuthAuthorizationHeader).(string)
 	if !ok || !strings.HasPrefix(authorization, "Bearer ") {
 		if m.staticConfig.RequireOAuth {
 			return nil, errors.New("oauth token required")
 		}
-		return &Kubernetes{m.accessControlClientset}, nil
+		return &Kubernetes{accessControlClientSet: m.accessControlClientset, eventStore: m.eventStore}, nil
 	}
 	klog.V(5).Infof("%s header found (Bearer), using provided bearer token", OAuthAuthorizationHeader)
+	userAgent := CustomUserAgent
+	if m.staticConfig.UserAgent != "" {
+		userAgent = m.staticConfig.UserAgent
+	}
 	derivedCfg := &rest.Config{
 		Host:          m.accessControlClientset.cfg.Host,
 		APIPath:       m.accessControlClientset.cfg.APIPath,
@@ -164,8 +201,8 @@ func (m *Manager) Derived(ctx context.Context) (*Kubernetes, error) {
 			CAData:     m.accessControlClientset.cfg.CAData,
 		},
 		BearerToken: strings.TrimPrefix(authorization, "Bearer "),
-		// pass custom UserAgent to identify the client
-		UserAgent:   CustomUserAgent,
+		// pass custom UserAgent to identify the client (unless one is configured)
+		UserAgent:   userAgent,
 		QPS:         m.accessControlClientset.cfg.QPS,
 		Burst:       m.accessControlClientset.cfg.Burst,
 		Timeout:     m.accessControlClientset.cfg.Timeout,
@@ -177,7 +214,7 @@ func (m *Manager) Derived(ctx context.Context) (*Kubernetes, error) {
 			klog.Errorf("failed to get kubeconfig: %v", err)
 			return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
 		}
-		return &Kubernetes{m.accessControlClientset}, nil
+		return &Kubernetes{accessControlClientSet: m.accessControlClientset, eventStore: m.eventStore}, nil
 	}
 	clientCmdApiConfig.AuthInfos = make(map[string]*clientcmdapi.AuthInfo)
 	derived, err := NewAccessControlClientset(m.staticConfig, clientcmd.NewDefaultClientConfig(clientCmdApiConfig, nil), derivedCfg)
@@ -186,9 +223,9 @@ func (m *Manager) Derived(ctx context.Context) (*Kubernetes, error) {
 			klog.Errorf("failed to create derived clientset: %v", err)
 			return nil, fmt.Errorf("failed to create derived clientset: %w", err)
 		}
-		return &Kubernetes{m.accessControlClientset}, nil
+		return &Kubernetes{accessControlClientSet: m.accessControlClientset, eventStore: m.eventStore}, nil
 	}
-	return &Kubernetes{derived}, nil
+	return &Kubernetes{accessControlClientSet: derived, eventStore: m.eventStore}, nil
 }
 
 // Invalidate invalidates the cached discovery information.
@@ -196,6 +233,20 @@ func (m *Manager) Invalidate() {
 	m.accessControlClientset.DiscoveryClient().Invalidate()
 }
 
+// applyClientSettings applies the QPS, Burst and UserAgent of the configuration if set, the rest of the settings
+// keep the kubeconfig (or client-go default) values
+func applyClientSettings(config *config.StaticConfig, cfg *rest.Config) {
+	if config.QPS > 0 {
+		cfg.QPS = config.QPS
+	}
+	if config.Burst > 0 {
+		cfg.Burst = config.Burst
+	}
+	if config.UserAgent != "" {
+		cfg.UserAgent = config.UserAgent
+	}
+}
+
 // applyRateLimitFromEnv applies QPS and Burst rate limits from environment variables if set.
 // This is primarily useful for tests to avoid client-side rate limiting.
 // Environment variables:
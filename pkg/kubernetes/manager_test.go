@@ -53,6 +53,47 @@ func (s *ManagerTestSuite) TestNewInClusterManager() {
 				s.Contains(manager.accessControlClientset.cfg.UserAgent, "("+runtime.GOOS+"/"+runtime.GOARCH+")")
 			})
 		})
+		s.Run("with POD_NAMESPACE", func() {
+			InClusterConfig = func() (*rest.Config, error) {
+				return &rest.Config{Host: "https://kubernetes.default.svc"}, nil
+			}
+			s.T().Setenv("POD_NAMESPACE", "mcp-system")
+			manager, err := NewInClusterManager(&config.StaticConfig{})
+			s.Require().NoError(err)
+			s.Run("uses the pod namespace as default namespace", func() {
+				k := &Kubernetes{accessControlClientSet: manager.accessControlClientset}
+				s.Equal("mcp-system", k.NamespaceOrDefault(""))
+			})
+		})
+		s.Run("with service account namespace", func() {
+			InClusterConfig = func() (*rest.Config, error) {
+				return &rest.Config{Host: "https://kubernetes.default.svc"}, nil
+			}
+			originalNamespaceFile := inClusterNamespaceFile
+			defer func() { inClusterNamespaceFile = originalNamespaceFile }()
+			inClusterNamespaceFile = filepath.Join(s.T().TempDir(), "namespace")
+			s.Require().NoError(os.WriteFile(inClusterNamespaceFile, []byte("mcp-sa\n"), 0600))
+			manager, err := NewInClusterManager(&config.StaticConfig{})
+			s.Require().NoError(err)
+			s.Run("uses the service account namespace as default namespace", func() {
+				k := &Kubernetes{accessControlClientSet: manager.accessControlClientset}
+				s.Equal("mcp-sa", k.NamespaceOrDefault(""))
+			})
+		})
+		s.Run("with service account token file", func() {
+			tokenFile := filepath.Join(s.T().TempDir(), "token")
+			s.Require().NoError(os.WriteFile(tokenFile, []byte("rotated-token"), 0600))
+			InClusterConfig = func() (*rest.Config, error) {
+				return &rest.Config{Host: "https://kubernetes.default.svc", BearerToken: "initial-token", BearerTokenFile: tokenFile}, nil
+			}
+			manager, err := NewInClusterManager(&config.StaticConfig{})
+			s.Require().NoError(err)
+			s.Run("keeps the token file so that rotated tokens are reloaded", func() {
+				clientConfig, err := manager.accessControlClientset.ToRawKubeConfigLoader().ClientConfig()
+				s.Require().NoError(err)
+				s.Equal(tokenFile, clientConfig.BearerTokenFile)
+			})
+		})
 		s.Run("with explicit kubeconfig", func() {
 			manager, err := NewInClusterManager(&config.StaticConfig{
 				KubeConfig: s.mockServer.KubeconfigFile(s.T()),
@@ -226,6 +267,29 @@ func (s *ManagerTestSuite) TestNewManager() {
 		s.EqualError(err, "config cannot be nil", "expected 'config cannot be nil' error as first check")
 		s.Nil(manager, "expected nil manager when all parameters are nil")
 	})
+
+	s.Run("with client settings in config", func() {
+		clientCmdConfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{}, nil)
+		manager, err := NewManager(&config.StaticConfig{QPS: 50, Burst: 100, UserAgent: "kubernetes-mcp-server/fleet"}, s.mockServer.Config(), clientCmdConfig)
+		s.Require().NoError(err)
+		s.Run("applies qps", func() {
+			s.Equal(float32(50), manager.accessControlClientset.cfg.QPS)
+		})
+		s.Run("applies burst", func() {
+			s.Equal(100, manager.accessControlClientset.cfg.Burst)
+		})
+		s.Run("applies user-agent", func() {
+			s.Equal("kubernetes-mcp-server/fleet", manager.accessControlClientset.cfg.UserAgent)
+		})
+		s.Run("environment takes precedence", func() {
+			s.Require().NoError(os.Setenv("KUBE_CLIENT_QPS", "1000"))
+			s.Require().NoError(os.Setenv("KUBE_CLIENT_BURST", "2000"))
+			manager, err := NewManager(&config.StaticConfig{QPS: 50, Burst: 100}, s.mockServer.Config(), clientCmdConfig)
+			s.Require().NoError(err)
+			s.Equal(float32(1000), manager.accessControlClientset.cfg.QPS)
+			s.Equal(2000, manager.accessControlClientset.cfg.Burst)
+		})
+	})
 }
 
 func TestManager(t *testing.T) {
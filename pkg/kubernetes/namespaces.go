@@ -2,8 +2,15 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 )
 
 func (k *Kubernetes) NamespacesList(ctx context.Context, options ResourceListOptions) (runtime.Unstructured, error) {
@@ -17,3 +24,92 @@ func (k *Kubernetes) ProjectsList(ctx context.Context, options ResourceListOptio
 		Group: "project.openshift.io", Version: "v1", Kind: "Project",
 	}, "", options)
 }
+
+func (k *Kubernetes) NamespacesCreate(ctx context.Context, name string, labels map[string]string) (*v1.Namespace, error) {
+	return k.AccessControlClientset().CoreV1().Namespaces().Create(ctx, &v1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+	}, metav1.CreateOptions{DryRun: dryRun(ctx)})
+}
+
+func (k *Kubernetes) NamespacesDelete(ctx context.Context, name string) error {
+	return k.AccessControlClientset().CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+}
+
+// NamespaceTerminationDiagnostic describes what is preventing a namespace from being deleted
+type NamespaceTerminationDiagnostic struct {
+	Name  string            `json:"name"`
+	Phase v1.NamespacePhase `json:"phase"`
+	// Finalizers are the namespace spec finalizers, removed by the namespace controller once the namespace is empty
+	Finalizers []v1.FinalizerName `json:"finalizers,omitempty"`
+	// Conditions are the namespace deletion conditions that are currently reporting a problem
+	Conditions []v1.NamespaceCondition `json:"conditions,omitempty"`
+	// RemainingResources are the resources still present in the namespace
+	RemainingResources []NamespaceRemainingResource `json:"remainingResources,omitempty"`
+	// TargetErrors are the resource types (or API groups) that couldn't be checked
+	TargetErrors TargetErrors `json:"targetErrors,omitempty"`
+}
+
+type NamespaceRemainingResource struct {
+	APIVersion        string       `json:"apiVersion"`
+	Kind              string       `json:"kind"`
+	Name              string       `json:"name"`
+	Finalizers        []string     `json:"finalizers,omitempty"`
+	DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+}
+
+// NamespacesDiagnoseTerminating inspects a namespace to find the resources and finalizers that are blocking its deletion.
+// Every namespaced resource type that supports listing is checked, resource types that can't be listed are
+// reported as TargetErrors since they are a common cause of namespaces stuck in the Terminating phase.
+func (k *Kubernetes) NamespacesDiagnoseTerminating(ctx context.Context, name string) (*NamespaceTerminationDiagnostic, error) {
+	namespace, err := k.AccessControlClientset().CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	diagnostic := &NamespaceTerminationDiagnostic{
+		Name:       namespace.Name,
+		Phase:      namespace.Status.Phase,
+		Finalizers: namespace.Spec.Finalizers,
+	}
+	for _, condition := range namespace.Status.Conditions {
+		if condition.Status == v1.ConditionTrue {
+			diagnostic.Conditions = append(diagnostic.Conditions, condition)
+		}
+	}
+
+	resourceLists, err := discovery.ServerPreferredNamespacedResources(k.AccessControlClientset().DiscoveryClient())
+	if groupErr := (*discovery.ErrGroupDiscoveryFailed)(nil); errors.As(err, &groupErr) {
+		for gv, gvErr := range groupErr.Groups {
+			diagnostic.TargetErrors.Add("groupVersion/"+gv.String(), fmt.Errorf("failed to discover resources: %w", gvErr))
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to discover namespaced resources: %w", err)
+	}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			if !slices.Contains(apiResource.Verbs, "list") {
+				continue
+			}
+			gvr := gv.WithResource(apiResource.Name)
+			list, err := k.AccessControlClientset().DynamicClient().Resource(gvr).Namespace(name).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				diagnostic.TargetErrors.Add("resource/"+gvr.GroupResource().String(), err)
+				continue
+			}
+			for _, item := range list.Items {
+				diagnostic.RemainingResources = append(diagnostic.RemainingResources, NamespaceRemainingResource{
+					APIVersion:        gv.String(),
+					Kind:              apiResource.Kind,
+					Name:              item.GetName(),
+					Finalizers:        item.GetFinalizers(),
+					DeletionTimestamp: item.GetDeletionTimestamp(),
+				})
+			}
+		}
+	}
+	return diagnostic, nil
+}
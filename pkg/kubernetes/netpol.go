@@ -0,0 +1,181 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/netpol"
+)
+
+// NetpolEndpoint references the source or destination of a connection: a Pod, a Pod with the provided labels in a
+// namespace, or an IP address outside of the cluster
+type NetpolEndpoint struct {
+	// Namespace of the Pod (defaults to the configured namespace if no IP address is provided)
+	Namespace string
+	// Pod name (Optional, a Pod with the provided Labels if empty)
+	Pod string
+	// Labels of the Pod if no Pod name is provided (Optional)
+	Labels map[string]string
+	// IP address outside of the cluster, if no Namespace nor Pod is provided
+	IP string
+}
+
+// NetpolAnalysis is the decision of the NetworkPolicies for a connection between two endpoints
+type NetpolAnalysis struct {
+	// Summary is a one line description, e.g. "Pod shop/web to Pod shop/db on 5432/TCP is denied by the ingress policies of the destination"
+	Summary     string `json:"summary"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Port        string `json:"port"`
+	netpol.Verdict
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// NetpolIsolationReport lists the namespaces whose Pods are all isolated by NetworkPolicies (e.g. default deny)
+type NetpolIsolationReport struct {
+	// Summary is a one line description, e.g. "3 of 12 namespaces isolate all their Pods, 2 with a default deny"
+	Summary string `json:"summary"`
+	// Isolated are the namespaces with policies selecting all their Pods
+	Isolated []netpol.NamespaceIsolation `json:"isolated"`
+	// NotIsolated are the namespaces whose Pods accept all the connections unless a policy selects them
+	NotIsolated []string `json:"notIsolated"`
+}
+
+// NetpolAnalyze evaluates the NetworkPolicies of the namespaces of the source and the destination for a connection
+// between them. Endpoints without a Pod name are evaluated as a Pod with the provided labels.
+// Namespaces that can't be read are matched by their kubernetes.io/metadata.name label only, with a warning.
+func (k *Kubernetes) NetpolAnalyze(ctx context.Context, source, destination NetpolEndpoint, port int32, protocol string) (*NetpolAnalysis, error) {
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid port %d", port)
+	}
+	if protocol == "" {
+		protocol = string(v1.ProtocolTCP)
+	}
+	analysis := &NetpolAnalysis{Port: fmt.Sprintf("%d/%s", port, protocol)}
+	var endpoints [2]netpol.Endpoint
+	var namespaces []string
+	for i, ref := range []*NetpolEndpoint{&source, &destination} {
+		endpoint, err := k.netpolEndpoint(ctx, ref, &analysis.Warnings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the %s: %w", []string{"source", "destination"}[i], err)
+		}
+		endpoints[i] = endpoint
+		if endpoint.IsPod() {
+			namespaces = append(namespaces, endpoint.Namespace)
+		}
+	}
+	analysis.Source, analysis.Destination = source.String(), destination.String()
+	var policies []networkingv1.NetworkPolicy
+	for _, namespace := range slices.Compact(namespaces) {
+		list, err := k.AccessControlClientset().NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the network policies of namespace %s: %w", namespace, err)
+		}
+		policies = append(policies, list.Items...)
+	}
+	analysis.Verdict = netpol.NewEvaluator(policies).Evaluate(netpol.Connection{
+		Source:      endpoints[0],
+		Destination: endpoints[1],
+		Port:        port,
+		Protocol:    v1.Protocol(protocol),
+	})
+	analysis.Summary = analysis.summary()
+	return analysis, nil
+}
+
+// netpolEndpoint resolves the labels, IP address, and container ports of the Pod, and the labels of its namespace
+func (k *Kubernetes) netpolEndpoint(ctx context.Context, ref *NetpolEndpoint, warnings *[]string) (netpol.Endpoint, error) {
+	if ref.Namespace == "" && ref.Pod == "" && ref.IP != "" {
+		return netpol.Endpoint{IP: ref.IP}, nil
+	}
+	ref.Namespace = k.NamespaceOrDefault(ref.Namespace)
+	endpoint := netpol.Endpoint{Namespace: ref.Namespace, Labels: ref.Labels, IP: ref.IP}
+	if ref.Pod != "" {
+		pod, err := k.AccessControlClientset().CoreV1().Pods(ref.Namespace).Get(ctx, ref.Pod, metav1.GetOptions{})
+		if err != nil {
+			return endpoint, err
+		}
+		endpoint.Labels, endpoint.IP = pod.Labels, pod.Status.PodIP
+		for _, container := range pod.Spec.Containers {
+			endpoint.Ports = append(endpoint.Ports, container.Ports...)
+		}
+	}
+	namespace, err := k.AccessControlClientset().CoreV1().Namespaces().Get(ctx, ref.Namespace, metav1.GetOptions{})
+	if err != nil {
+		*warnings = append(*warnings, fmt.Sprintf("unable to get namespace %s, only its %s label is matched by the namespace selectors: %v",
+			ref.Namespace, v1.LabelMetadataName, err))
+		endpoint.NamespaceLabels = map[string]string{v1.LabelMetadataName: ref.Namespace}
+	} else {
+		endpoint.NamespaceLabels = namespace.Labels
+	}
+	return endpoint, nil
+}
+
+// NetpolIsolation reports the namespaces whose Pods are all selected by NetworkPolicies, for ingress or egress
+func (k *Kubernetes) NetpolIsolation(ctx context.Context) (*NetpolIsolationReport, error) {
+	namespaces, err := k.AccessControlClientset().CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	policies, err := k.AccessControlClientset().NetworkingV1().NetworkPolicies(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network policies: %w", err)
+	}
+	names := make([]string, 0, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		names = append(names, namespace.Name)
+	}
+	report := &NetpolIsolationReport{Isolated: []netpol.NamespaceIsolation{}, NotIsolated: []string{}}
+	defaultDeny := 0
+	for _, isolation := range netpol.NewEvaluator(policies.Items).Isolation(names) {
+		if !isolation.IngressIsolated && !isolation.EgressIsolated {
+			report.NotIsolated = append(report.NotIsolated, isolation.Namespace)
+			continue
+		}
+		if isolation.DefaultDenyIngress || isolation.DefaultDenyEgress {
+			defaultDeny++
+		}
+		report.Isolated = append(report.Isolated, isolation)
+	}
+	report.Summary = fmt.Sprintf("%d of %d namespaces isolate all their Pods, %d with a default deny", len(report.Isolated), len(names), defaultDeny)
+	return report, nil
+}
+
+func (a *NetpolAnalysis) summary() string {
+	summary := fmt.Sprintf("%s to %s on %s", a.Source, a.Destination, a.Port)
+	if a.Allowed {
+		return summary + " is allowed"
+	}
+	var denied []string
+	if a.Egress != nil && !a.Egress.Allowed {
+		denied = append(denied, "the egress policies of the source")
+	}
+	if a.Ingress != nil && !a.Ingress.Allowed {
+		denied = append(denied, "the ingress policies of the destination")
+	}
+	return summary + " is denied by " + strings.Join(denied, " and ")
+}
+
+// String returns the endpoint as reported by NetpolAnalysis, e.g. "Pod shop/web" or "Pod of namespace shop with labels app=web"
+func (e NetpolEndpoint) String() string {
+	switch {
+	case e.Pod != "":
+		return "Pod " + e.Namespace + "/" + e.Pod
+	case e.Namespace == "":
+		return "IP " + e.IP
+	case len(e.Labels) == 0:
+		return "Pod of namespace " + e.Namespace
+	}
+	labels := make([]string, 0, len(e.Labels))
+	for _, key := range slices.Sorted(maps.Keys(e.Labels)) {
+		labels = append(labels, key+"="+e.Labels[key])
+	}
+	return "Pod of namespace " + e.Namespace + " with labels " + strings.Join(labels, ",")
+}
@@ -0,0 +1,263 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	NetworkProbeDNS        = "dns"
+	NetworkProbeTCP        = "tcp"
+	NetworkProbeHTTP       = "http"
+	NetworkProbeTraceroute = "traceroute"
+	// NetworkProbesMax is the maximum number of probes run by a single helper pod
+	NetworkProbesMax = 20
+	// networkProbeTimeoutSeconds is the timeout of each of the DNS, TCP, and HTTP probes
+	networkProbeTimeoutSeconds = 5
+	// networkProbeMaxHops is the maximum number of hops of the traceroute probes
+	networkProbeMaxHops = 15
+)
+
+// NetworkProbeTypes are the supported network probe types
+var NetworkProbeTypes = []string{NetworkProbeDNS, NetworkProbeTCP, NetworkProbeHTTP, NetworkProbeTraceroute}
+
+// networkProbeScript runs the probes passed as positional parameters (type, target and port of each probe)
+// so that the targets are never interpreted by the shell, and prints one "ok|failed <output>" line per probe
+var networkProbeScript = fmt.Sprintf(`while [ $# -ge 3 ]; do type="$1"; target="$2"; port="$3"; shift 3
+case "$type" in
+dns) out=$(dig +search +short "$target" 2>&1) && [ -n "$out" ] ;;
+tcp) out=$(nc -z -v -w %[1]d "$target" "$port" 2>&1) ;;
+http) out=$(curl -sS -o /dev/null -w '%%{http_code}' --max-time %[1]d "$target" 2>/tmp/curl.err); rc=$?; out="$out $(cat /tmp/curl.err)"; [ $rc -eq 0 ] ;;
+traceroute) out=$(traceroute -n -w 2 -q 1 -m %[2]d "$target" 2>&1) ;;
+esac
+if [ $? -eq 0 ]; then status=ok; else status=failed; fi
+printf '%%s %%s\n' "$status" "$(printf '%%s' "$out" | tr '\n' ' ')"
+done`, networkProbeTimeoutSeconds, networkProbeMaxHops)
+
+// NetworkProbe is a network test run from the network debug helper pod
+type NetworkProbe struct {
+	// Type of the probe: dns, tcp, http, or traceroute
+	Type string `json:"type"`
+	// Target is a host name, IP address, or URL (http), or a service/<name> or pod/<name> reference resolved in Namespace
+	Target string `json:"target"`
+	// Namespace of the service/<name> and pod/<name> targets (defaults to the namespace of the helper pod)
+	Namespace string `json:"namespace,omitempty"`
+	// Port of the tcp and http probes (defaults to the first port of service/<name> targets)
+	Port int `json:"port,omitempty"`
+	// Path of the http probes (ignored for URL targets)
+	Path string `json:"path,omitempty"`
+}
+
+// NetworkProbeResult is the pass/fail result of a NetworkProbe
+type NetworkProbeResult struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	// Address is the resolved host (dns, traceroute), host:port (tcp), or URL (http) that was probed
+	Address string `json:"address,omitempty"`
+	Success bool   `json:"success"`
+	// StatusCode is the HTTP response status code of the http probes (0 if no response was received)
+	StatusCode int `json:"statusCode,omitempty"`
+	// Output of the probe command (resolved addresses, traceroute hops, or error message)
+	Output string `json:"output,omitempty"`
+}
+
+// NetworkDebug is the result of the network probes run from a short-lived helper pod
+type NetworkDebug struct {
+	// Summary is a one line description of the results, e.g. "2/3 probes succeeded from namespace ns-1"
+	Summary string `json:"summary"`
+	// Namespace where the helper pod ran, NetworkPolicies of this namespace apply to the probes
+	Namespace string `json:"namespace"`
+	Node      string `json:"node,omitempty"`
+	// Labels of the helper pod, used to match the podSelector of the NetworkPolicies
+	Labels  map[string]string    `json:"labels,omitempty"`
+	Results []NetworkProbeResult `json:"results"`
+}
+
+// NetworkDebugOptions selects where the network debug helper pod runs
+type NetworkDebugOptions struct {
+	// Namespace of the helper pod (Optional, defaults to the configured namespace)
+	Namespace string
+	// Node of the helper pod (Optional)
+	Node string
+	// Labels of the helper pod, e.g. the labels of the Pods whose connectivity is tested (Optional)
+	Labels map[string]string
+}
+
+// NetworkDebugProbes runs the provided DNS, TCP, HTTP, and traceroute probes from a short-lived helper pod
+// and returns the pass/fail result of each of them.
+// Targets that can't be resolved (e.g. missing Pods) are reported as failed without being probed.
+func (k *Kubernetes) NetworkDebugProbes(ctx context.Context, options NetworkDebugOptions, probes []NetworkProbe) (*NetworkDebug, error) {
+	if len(probes) == 0 {
+		return nil, fmt.Errorf("no probes provided")
+	}
+	if len(probes) > NetworkProbesMax {
+		return nil, fmt.Errorf("too many probes (%d), the maximum is %d", len(probes), NetworkProbesMax)
+	}
+	for i, probe := range probes {
+		if err := probe.validate(); err != nil {
+			return nil, fmt.Errorf("invalid probe %d: %w", i, err)
+		}
+	}
+	networkDebug := &NetworkDebug{
+		Namespace: k.NamespaceOrDefault(options.Namespace),
+		Node:      options.Node,
+		Labels:    options.Labels,
+		Results:   make([]NetworkProbeResult, len(probes)),
+	}
+	command := []string{"sh", "-c", networkProbeScript, "sh"}
+	var probed []int
+	for i, probe := range probes {
+		if probe.Namespace == "" {
+			probe.Namespace = networkDebug.Namespace
+		}
+		networkDebug.Results[i] = NetworkProbeResult{Type: probe.Type, Target: probe.Target}
+		host, port, err := k.resolveNetworkProbeTarget(ctx, probe)
+		if err != nil {
+			networkDebug.Results[i].Output = fmt.Sprintf("failed to resolve target: %v", err)
+			continue
+		}
+		target, portArg := networkProbeAddress(probe, host, port)
+		networkDebug.Results[i].Address = target
+		if probe.Type == NetworkProbeTCP {
+			networkDebug.Results[i].Address = net.JoinHostPort(host, portArg)
+		}
+		command = append(command, probe.Type, target, portArg)
+		probed = append(probed, i)
+	}
+	if len(probed) > 0 {
+		out, err := k.RunHelperPod(ctx, HelperPodOptions{
+			Role:      HelperImageNetworkTest,
+			Namespace: networkDebug.Namespace,
+			NodeName:  options.Node,
+			Labels:    options.Labels,
+			Command:   command,
+			// traceroute probes can take up to 2 seconds per hop
+			Timeout: helperTimeout(ctx, 0) + time.Duration(len(probed)*networkProbeMaxHops*2)*time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+		lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+		for n, i := range probed {
+			if n >= len(lines) {
+				networkDebug.Results[i].Output = "no result returned by the helper pod"
+				continue
+			}
+			parseNetworkProbeResult(&networkDebug.Results[i], lines[n])
+		}
+	}
+	succeeded := 0
+	for _, result := range networkDebug.Results {
+		if result.Success {
+			succeeded++
+		}
+	}
+	networkDebug.Summary = fmt.Sprintf("%d/%d probes succeeded from namespace %s", succeeded, len(probes), networkDebug.Namespace)
+	if options.Node != "" {
+		networkDebug.Summary += " on node " + options.Node
+	}
+	return networkDebug, nil
+}
+
+func (p NetworkProbe) validate() error {
+	if p.Target == "" {
+		return fmt.Errorf("missing target")
+	}
+	if p.Port < 0 || p.Port > 65535 {
+		return fmt.Errorf("invalid port %d", p.Port)
+	}
+	switch p.Type {
+	case NetworkProbeDNS, NetworkProbeTraceroute, NetworkProbeHTTP:
+	case NetworkProbeTCP:
+		if p.Port == 0 && !strings.HasPrefix(p.Target, "service/") {
+			return fmt.Errorf("tcp probe to %s requires a port", p.Target)
+		}
+	default:
+		return fmt.Errorf("unsupported type %q, supported types are %s", p.Type, strings.Join(NetworkProbeTypes, ", "))
+	}
+	return nil
+}
+
+// resolveNetworkProbeTarget resolves the service/<name> (Service DNS name and first port) and pod/<name> (Pod IP)
+// targets of the probe, other targets are returned as is
+func (k *Kubernetes) resolveNetworkProbeTarget(ctx context.Context, probe NetworkProbe) (string, int, error) {
+	if name, ok := strings.CutPrefix(probe.Target, "service/"); ok {
+		service, err := k.AccessControlClientset().CoreV1().Services(probe.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", 0, err
+		}
+		port := probe.Port
+		if port == 0 && len(service.Spec.Ports) > 0 {
+			port = int(service.Spec.Ports[0].Port)
+		}
+		if port == 0 && probe.Type == NetworkProbeTCP {
+			return "", 0, fmt.Errorf("service %s has no ports, provide the port to probe", name)
+		}
+		if service.Spec.Type == v1.ServiceTypeExternalName {
+			return service.Spec.ExternalName, port, nil
+		}
+		return service.Name + "." + service.Namespace + ".svc", port, nil
+	}
+	if name, ok := strings.CutPrefix(probe.Target, "pod/"); ok {
+		pod, err := k.AccessControlClientset().CoreV1().Pods(probe.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", 0, err
+		}
+		if pod.Status.PodIP == "" {
+			return "", 0, fmt.Errorf("pod %s has no IP address (phase %s)", name, pod.Status.Phase)
+		}
+		return pod.Status.PodIP, probe.Port, nil
+	}
+	return probe.Target, probe.Port, nil
+}
+
+// networkProbeAddress returns the target and port positional parameters of the probe script
+func networkProbeAddress(probe NetworkProbe, host string, port int) (string, string) {
+	switch probe.Type {
+	case NetworkProbeTCP:
+		return host, strconv.Itoa(port)
+	case NetworkProbeHTTP:
+		if strings.Contains(host, "://") {
+			return host, ""
+		}
+		if port > 0 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		} else if strings.Contains(host, ":") && net.ParseIP(host) != nil {
+			host = "[" + host + "]"
+		}
+		return "http://" + host + "/" + strings.TrimPrefix(probe.Path, "/"), ""
+	default:
+		return host, ""
+	}
+}
+
+// parseNetworkProbeResult parses an "ok|failed <output>" line printed by the probe script
+func parseNetworkProbeResult(result *NetworkProbeResult, line string) {
+	status, output, _ := strings.Cut(strings.TrimSpace(line), " ")
+	result.Success = status == "ok"
+	result.Output = strings.TrimSpace(output)
+	switch result.Type {
+	case NetworkProbeDNS:
+		if !result.Success && result.Output == "" {
+			result.Output = "no records found"
+		}
+	case NetworkProbeHTTP:
+		code, rest, _ := strings.Cut(result.Output, " ")
+		result.StatusCode, _ = strconv.Atoi(code)
+		result.Output = strings.TrimSpace(rest)
+		if result.StatusCode > 0 {
+			// The server responded, 4xx and 5xx status codes are reported as failures
+			result.Success = result.Success && result.StatusCode < 400
+			if result.Output == "" {
+				result.Output = "HTTP " + code
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NetworkDebugSuite struct {
+	suite.Suite
+}
+
+func (s *NetworkDebugSuite) TestNetworkProbeValidate() {
+	s.Run("valid probes", func() {
+		s.NoError(NetworkProbe{Type: NetworkProbeDNS, Target: "example.com"}.validate())
+		s.NoError(NetworkProbe{Type: NetworkProbeTCP, Target: "10.0.0.1", Port: 5432}.validate())
+		s.NoError(NetworkProbe{Type: NetworkProbeTCP, Target: "service/db"}.validate())
+	})
+	s.Run("unsupported type", func() {
+		s.EqualError(NetworkProbe{Type: "icmp", Target: "example.com"}.validate(),
+			`unsupported type "icmp", supported types are dns, tcp, http, traceroute`)
+	})
+	s.Run("missing target", func() {
+		s.EqualError(NetworkProbe{Type: NetworkProbeDNS}.validate(), "missing target")
+	})
+	s.Run("tcp probe without port", func() {
+		s.EqualError(NetworkProbe{Type: NetworkProbeTCP, Target: "pod/db-0"}.validate(), "tcp probe to pod/db-0 requires a port")
+	})
+}
+
+func (s *NetworkDebugSuite) TestNetworkProbeAddress() {
+	tests := []struct {
+		name         string
+		probe        NetworkProbe
+		host         string
+		port         int
+		expectedHost string
+		expectedPort string
+	}{
+		{"dns", NetworkProbe{Type: NetworkProbeDNS}, "web.ns-1.svc", 80, "web.ns-1.svc", ""},
+		{"tcp", NetworkProbe{Type: NetworkProbeTCP}, "10.244.0.5", 5432, "10.244.0.5", "5432"},
+		{"http with port and path", NetworkProbe{Type: NetworkProbeHTTP, Path: "healthz"}, "web.ns-1.svc", 8080, "http://web.ns-1.svc:8080/healthz", ""},
+		{"http without port", NetworkProbe{Type: NetworkProbeHTTP}, "example.com", 0, "http://example.com/", ""},
+		{"http IPv6", NetworkProbe{Type: NetworkProbeHTTP, Path: "/ready"}, "fd00::5", 0, "http://[fd00::5]/ready", ""},
+		{"http URL", NetworkProbe{Type: NetworkProbeHTTP, Path: "/ignored"}, "https://example.com/status", 443, "https://example.com/status", ""},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			host, port := networkProbeAddress(tt.probe, tt.host, tt.port)
+			s.Equal(tt.expectedHost, host)
+			s.Equal(tt.expectedPort, port)
+		})
+	}
+}
+
+func (s *NetworkDebugSuite) TestParseNetworkProbeResult() {
+	tests := []struct {
+		name     string
+		probe    string
+		line     string
+		expected NetworkProbeResult
+	}{
+		{"dns ok", NetworkProbeDNS, "ok 10.96.0.10 ", NetworkProbeResult{Success: true, Output: "10.96.0.10"}},
+		{"dns no records", NetworkProbeDNS, "failed ", NetworkProbeResult{Output: "no records found"}},
+		{"tcp refused", NetworkProbeTCP, "failed nc: connect to 10.244.0.5 port 5432 (tcp) failed: Connection refused ",
+			NetworkProbeResult{Output: "nc: connect to 10.244.0.5 port 5432 (tcp) failed: Connection refused"}},
+		{"http ok", NetworkProbeHTTP, "ok 200 ", NetworkProbeResult{Success: true, StatusCode: 200, Output: "HTTP 200"}},
+		{"http server error", NetworkProbeHTTP, "ok 503 ", NetworkProbeResult{StatusCode: 503, Output: "HTTP 503"}},
+		{"http connection failure", NetworkProbeHTTP, "failed 000 curl: (28) Connection timed out after 5001 milliseconds",
+			NetworkProbeResult{Output: "curl: (28) Connection timed out after 5001 milliseconds"}},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			result := NetworkProbeResult{Type: tt.probe}
+			parseNetworkProbeResult(&result, tt.line)
+			tt.expected.Type = tt.probe
+			s.Equal(tt.expected, result)
+		})
+	}
+}
+
+func TestNetworkDebug(t *testing.T) {
+	suite.Run(t, new(NetworkDebugSuite))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/metrics/pkg/apis/metrics"
 	metricsv1beta1api "k8s.io/metrics/pkg/apis/metrics/v1beta1"
@@ -53,6 +54,35 @@ func (k *Kubernetes) NodesStatsSummary(ctx context.Context, name string) (string
 		return "", fmt.Errorf("failed to get node %s: %w", name, err)
 	}
 
+	return k.nodeStatsSummary(ctx, name)
+}
+
+type NodeStatsSummary struct {
+	Node    string
+	Summary string
+}
+
+// NodesStatsSummaries retrieves the stats summary from the kubelet of every node matching the label selector.
+// The summaries are retrieved concurrently (see FanOutNodes) and returned in the order of the node list.
+// Nodes whose kubelet can't be reached don't fail the operation, their errors are returned as TargetErrors
+// along with the summaries of the remaining nodes.
+func (k *Kubernetes) NodesStatsSummaries(ctx context.Context, labelSelector string) ([]NodeStatsSummary, TargetErrors, error) {
+	nodes, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	results, targetErrors := FanOutNodes(ctx, nodes.Items, func(ctx context.Context, node v1.Node) (NodeStatsSummary, error) {
+		summary, err := k.nodeStatsSummary(ctx, node.Name)
+		return NodeStatsSummary{Node: node.Name, Summary: summary}, err
+	})
+	var summaries []NodeStatsSummary
+	for _, result := range results {
+		summaries = append(summaries, result.Value)
+	}
+	return summaries, targetErrors, nil
+}
+
+func (k *Kubernetes) nodeStatsSummary(ctx context.Context, name string) (string, error) {
 	result := k.AccessControlClientset().CoreV1().RESTClient().
 		Get().
 		AbsPath("api", "v1", "nodes", name, "proxy", "stats", "summary").
@@ -69,6 +99,24 @@ func (k *Kubernetes) NodesStatsSummary(ctx context.Context, name string) (string
 	return string(rawData), nil
 }
 
+// nodeConfigz retrieves the effective configuration of the kubelet of the node through the API server proxy
+func (k *Kubernetes) nodeConfigz(ctx context.Context, name string) (string, error) {
+	result := k.AccessControlClientset().CoreV1().RESTClient().
+		Get().
+		AbsPath("api", "v1", "nodes", name, "proxy", "configz").
+		Do(ctx)
+	if result.Error() != nil {
+		return "", fmt.Errorf("failed to get node configz: %w", result.Error())
+	}
+
+	rawData, err := result.Raw()
+	if err != nil {
+		return "", fmt.Errorf("failed to read node configz response: %w", err)
+	}
+
+	return string(rawData), nil
+}
+
 type NodesTopOptions struct {
 	metav1.ListOptions
 	Name string
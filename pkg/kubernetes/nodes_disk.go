@@ -0,0 +1,303 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeDiskUsageDefaultPaths are the node directories measured by NodesDiskUsage when no path is provided:
+// the runtime storage (containerd, CRI-O), the kubelet state (volumes, ephemeral storage), and the logs
+var NodeDiskUsageDefaultPaths = []string{"/var/lib/containerd", "/var/lib/containers", "/var/lib/kubelet", "/var/log"}
+
+// nodeDiskUsageScript measures the size of the paths passed as positional parameters after the mode, then lists the
+// runtime images and containers with crictl, and removes the unused images when the mode is prune.
+// Each line of the output is prefixed with its type so that a missing runtime doesn't prevent the du report.
+var nodeDiskUsageScript = `mode="$1"; shift
+for p in "$@"; do
+  if [ -e "$p" ]; then echo "du $(du -sxk "$p" 2>/dev/null | tail -n 1 | cut -f 1) $p"; else echo "du - $p"; fi
+done
+socket=""
+for s in ` + strings.Join(nodeRuntimeSockets, " ") + `; do if [ -S "$s" ]; then socket="$s"; break; fi; done
+if [ -z "$socket" ]; then echo "noruntime no container runtime socket found (` + strings.Join(nodeRuntimeSockets, ", ") + `)"; exit 0; fi
+if ! command -v crictl >/dev/null 2>&1; then echo "noruntime crictl is not installed on the node"; exit 0; fi
+crictl="crictl --runtime-endpoint unix://$socket --timeout 10s"
+echo "runtime unix://$socket"
+echo "images $($crictl images --output json | tr -d '\n')"
+echo "containers $($crictl ps --all --output json | tr -d '\n')"
+if [ "$mode" = "prune" ]; then
+  if out=$($crictl rmi --prune 2>&1); then echo "pruned $($crictl images --output json | tr -d '\n')"; else echo "pruneerror $(echo "$out" | tr '\n' ' ')"; fi
+fi`
+
+type NodeDiskUsageOptions struct {
+	NodeName string
+	// Paths are the absolute node paths whose size is measured (NodeDiskUsageDefaultPaths if empty)
+	Paths []string
+	// PruneImages removes the runtime images not used by any container (crictl rmi --prune)
+	PruneImages bool
+}
+
+// NodeDiskUsage combines the filesystem usage reported by the kubelet with the size of the node directories and
+// of the runtime images, to troubleshoot and remediate the DiskPressure of a node
+type NodeDiskUsage struct {
+	Node string `json:"node"`
+	// DiskPressure is the status of the DiskPressure condition of the node, with its message
+	DiskPressure string `json:"diskPressure,omitempty"`
+	// Filesystems are the node (nodefs), image (imagefs), and container (containerfs) filesystems reported by the kubelet
+	Filesystems []NodeFilesystem `json:"filesystems,omitempty"`
+	Paths       []NodeDiskPath   `json:"paths,omitempty"`
+	// RuntimeEndpoint is the detected CRI socket, e.g. unix:///run/containerd/containerd.sock
+	RuntimeEndpoint string `json:"runtimeEndpoint,omitempty"`
+	// Images are the runtime images, largest first
+	Images []NodeDiskImage `json:"images,omitempty"`
+	// UnusedImagesBytes is the size of the images not used by any container nor pinned, that an image garbage collection reclaims
+	UnusedImagesBytes int64 `json:"unusedImagesBytes"`
+	// PrunedImages are the images removed by the image garbage collection, and ReclaimedBytes their size
+	PrunedImages   []NodeDiskImage `json:"prunedImages,omitempty"`
+	ReclaimedBytes int64           `json:"reclaimedBytes,omitempty"`
+	Warnings       []string        `json:"warnings,omitempty"`
+}
+
+type NodeFilesystem struct {
+	// Name is nodefs (kubelet root directory), imagefs (runtime images), or containerfs (container writable layers)
+	Name           string  `json:"name"`
+	CapacityBytes  uint64  `json:"capacityBytes"`
+	UsedBytes      uint64  `json:"usedBytes"`
+	AvailableBytes uint64  `json:"availableBytes"`
+	UsedPercent    float64 `json:"usedPercent"`
+	Inodes         uint64  `json:"inodes,omitempty"`
+	InodesFree     uint64  `json:"inodesFree,omitempty"`
+}
+
+type NodeDiskPath struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"sizeBytes"`
+	// Missing is true when the path doesn't exist on the node
+	Missing bool `json:"missing,omitempty"`
+}
+
+type NodeDiskImage struct {
+	RuntimeImage
+	// InUse is true when the image is used by a container of the node, including the exited ones
+	InUse bool `json:"inUse"`
+}
+
+// nodeStatsSummaryFs is the subset of the kubelet Summary API response with the node filesystems
+type nodeStatsSummaryFs struct {
+	Node struct {
+		Fs      *nodeFsStats `json:"fs"`
+		Runtime *struct {
+			ImageFs     *nodeFsStats `json:"imageFs"`
+			ContainerFs *nodeFsStats `json:"containerFs"`
+		} `json:"runtime"`
+	} `json:"node"`
+}
+
+type nodeFsStats struct {
+	AvailableBytes *uint64 `json:"availableBytes"`
+	CapacityBytes  *uint64 `json:"capacityBytes"`
+	UsedBytes      *uint64 `json:"usedBytes"`
+	InodesFree     *uint64 `json:"inodesFree"`
+	Inodes         *uint64 `json:"inodes"`
+}
+
+// NodesDiskUsage reports the filesystem usage of the node from the kubelet stats summary, the size of the provided
+// node paths (du), and the size of the runtime images (crictl images), and optionally removes the unused images.
+// Failures retrieving the stats summary don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) NodesDiskUsage(ctx context.Context, options NodeDiskUsageOptions) (*NodeDiskUsage, error) {
+	paths := options.Paths
+	if len(paths) == 0 {
+		paths = NodeDiskUsageDefaultPaths
+	}
+	for _, p := range paths {
+		if !path.IsAbs(p) || path.Clean(p) != p {
+			return nil, fmt.Errorf("invalid path %q, paths must be absolute and clean", p)
+		}
+	}
+	node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, options.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", options.NodeName, err)
+	}
+	usage := &NodeDiskUsage{Node: options.NodeName}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeDiskPressure {
+			usage.DiskPressure = string(condition.Status)
+			if condition.Status == v1.ConditionTrue && condition.Message != "" {
+				usage.DiskPressure += ": " + condition.Message
+			}
+		}
+	}
+	if summary, err := k.NodesStatsSummary(ctx, options.NodeName); err != nil {
+		usage.Warnings = append(usage.Warnings, fmt.Sprintf("unable to retrieve the stats summary of the node: %v", err))
+	} else if usage.Filesystems, err = parseNodeFilesystems(summary); err != nil {
+		usage.Warnings = append(usage.Warnings, err.Error())
+	}
+	mode := "report"
+	if options.PruneImages {
+		mode = "prune"
+	}
+	out, err := k.RunNodeShell(ctx, NodeShellOptions{
+		NodeName: options.NodeName,
+		Script:   nodeDiskUsageScript,
+		Args:     append([]string{mode}, paths...),
+		ReadOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = usage.parse(out); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// Summary returns a one line description of the node disk usage
+func (u *NodeDiskUsage) Summary() string {
+	summary := fmt.Sprintf("Disk usage of node %s", u.Node)
+	var details []string
+	for _, fs := range u.Filesystems {
+		if fs.Name == "nodefs" {
+			details = append(details, fmt.Sprintf("nodefs %.0f%% used (%s available)", fs.UsedPercent, formatBytes(int64(fs.AvailableBytes))))
+		}
+	}
+	if strings.HasPrefix(u.DiskPressure, string(v1.ConditionTrue)) {
+		details = append(details, "DiskPressure")
+	}
+	if u.Images != nil {
+		details = append(details, fmt.Sprintf("%d images, %s reclaimable by image garbage collection", len(u.Images), formatBytes(u.UnusedImagesBytes)))
+	}
+	if u.PrunedImages != nil {
+		details = append(details, fmt.Sprintf("%d images removed, %s reclaimed", len(u.PrunedImages), formatBytes(u.ReclaimedBytes)))
+	}
+	if len(details) > 0 {
+		summary += ": " + strings.Join(details, ", ")
+	}
+	return summary
+}
+
+// parse reads the output of nodeDiskUsageScript
+func (u *NodeDiskUsage) parse(out string) error {
+	var containers []RuntimeContainer
+	for _, line := range strings.Split(out, "\n") {
+		kind, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		var err error
+		switch kind {
+		case "du":
+			size, p, _ := strings.Cut(value, " ")
+			kb, parseErr := strconv.ParseInt(size, 10, 64)
+			u.Paths = append(u.Paths, NodeDiskPath{Path: p, SizeBytes: kb * 1024, Missing: parseErr != nil})
+		case "noruntime":
+			u.Warnings = append(u.Warnings, "unable to list the runtime images: "+value)
+		case "runtime":
+			u.RuntimeEndpoint = value
+		case "images":
+			var images []RuntimeImage
+			if images, err = parseRuntimeImages(value); err == nil {
+				u.Images = nodeDiskImages(images)
+			}
+		case "containers":
+			containers, err = parseRuntimeContainers(value)
+		case "pruned":
+			var remaining []RuntimeImage
+			if remaining, err = parseRuntimeImages(value); err == nil {
+				u.PrunedImages = make([]NodeDiskImage, 0)
+				for _, image := range u.Images {
+					if !slices.ContainsFunc(remaining, func(r RuntimeImage) bool { return r.ID == image.ID }) {
+						u.PrunedImages = append(u.PrunedImages, image)
+						u.ReclaimedBytes += image.SizeBytes
+					}
+				}
+			}
+		case "pruneerror":
+			u.Warnings = append(u.Warnings, "failed to remove the unused images: "+strings.TrimSpace(value))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(u.Paths, func(i, j int) bool { return u.Paths[i].SizeBytes > u.Paths[j].SizeBytes })
+	MarkInUseNodeDiskImages(u.Images, containers)
+	for _, image := range u.Images {
+		if !image.InUse && !image.Pinned {
+			u.UnusedImagesBytes += image.SizeBytes
+		}
+	}
+	return nil
+}
+
+// MarkInUseNodeDiskImages flags the images used by the containers, matching the image ID, tags, and digests
+func MarkInUseNodeDiskImages(images []NodeDiskImage, containers []RuntimeContainer) {
+	for i := range images {
+		image := &images[i]
+		references := append(append([]string{image.ID}, image.RepoTags...), image.RepoDigests...)
+		image.InUse = slices.ContainsFunc(containers, func(c RuntimeContainer) bool {
+			return slices.Contains(references, c.ImageRef) || slices.Contains(references, c.Image)
+		})
+	}
+}
+
+func nodeDiskImages(images []RuntimeImage) []NodeDiskImage {
+	result := make([]NodeDiskImage, 0, len(images))
+	for _, image := range images {
+		result = append(result, NodeDiskImage{RuntimeImage: image})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].SizeBytes > result[j].SizeBytes })
+	return result
+}
+
+func parseNodeFilesystems(summary string) ([]NodeFilesystem, error) {
+	var stats nodeStatsSummaryFs
+	if err := json.Unmarshal([]byte(summary), &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse the stats summary of the node: %w", err)
+	}
+	filesystems := make([]NodeFilesystem, 0, 3)
+	add := func(name string, fs *nodeFsStats) {
+		if fs == nil || fs.CapacityBytes == nil {
+			return
+		}
+		filesystem := NodeFilesystem{Name: name, CapacityBytes: *fs.CapacityBytes}
+		for _, field := range []struct {
+			target *uint64
+			value  *uint64
+		}{{&filesystem.UsedBytes, fs.UsedBytes}, {&filesystem.AvailableBytes, fs.AvailableBytes}, {&filesystem.Inodes, fs.Inodes}, {&filesystem.InodesFree, fs.InodesFree}} {
+			if field.value != nil {
+				*field.target = *field.value
+			}
+		}
+		if filesystem.CapacityBytes > 0 {
+			// The used bytes reported by the kubelet only account for the kubelet directories, the capacity minus the
+			// available bytes is the usage of the whole filesystem as reported by df
+			used := filesystem.CapacityBytes - min(filesystem.AvailableBytes, filesystem.CapacityBytes)
+			filesystem.UsedPercent = float64(used*1000/filesystem.CapacityBytes) / 10
+		}
+		filesystems = append(filesystems, filesystem)
+	}
+	add("nodefs", stats.Node.Fs)
+	if stats.Node.Runtime != nil {
+		add("imagefs", stats.Node.Runtime.ImageFs)
+		add("containerfs", stats.Node.Runtime.ContainerFs)
+	}
+	return filesystems, nil
+}
+
+// formatBytes formats the size in bytes with binary units, e.g. 1.5GiB
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
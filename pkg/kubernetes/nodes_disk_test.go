@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NodesDiskSuite struct {
+	suite.Suite
+}
+
+const nodeDiskUsageOutput = `du 2048 /var/log
+du 10240 /var/lib/containerd
+du - /var/lib/containers
+runtime unix:///run/containerd/containerd.sock
+images {"images":[{"id":"sha256:small","repoTags":["example.com/app:1.0"],"size":"1024"},{"id":"sha256:large","repoTags":["example.com/old:1.0"],"size":"4096"},{"id":"sha256:pause","repoTags":["registry.k8s.io/pause:3.9"],"size":"512","pinned":true}]}
+containers {"containers":[{"id":"a1b2","metadata":{"name":"app"},"image":{"image":"example.com/app:1.0"},"imageRef":"sha256:small","state":"CONTAINER_EXITED"}]}
+`
+
+func (s *NodesDiskSuite) TestParse() {
+	usage := &NodeDiskUsage{Node: "node-1"}
+	s.Require().NoError(usage.parse(nodeDiskUsageOutput))
+	s.Run("sorts paths by size", func() {
+		s.Equal([]NodeDiskPath{
+			{Path: "/var/lib/containerd", SizeBytes: 10485760},
+			{Path: "/var/log", SizeBytes: 2097152},
+			{Path: "/var/lib/containers", Missing: true},
+		}, usage.Paths)
+	})
+	s.Run("sorts images by size", func() {
+		s.Require().Len(usage.Images, 3)
+		s.Equal("sha256:large", usage.Images[0].ID)
+		s.Equal("sha256:pause", usage.Images[2].ID)
+	})
+	s.Run("flags the images used by a container", func() {
+		s.False(usage.Images[0].InUse)
+		s.True(usage.Images[1].InUse)
+	})
+	s.Run("reclaimable bytes exclude the used and pinned images", func() {
+		s.Equal(int64(4096), usage.UnusedImagesBytes)
+	})
+	s.Run("no pruned images without prune", func() {
+		s.Nil(usage.PrunedImages)
+	})
+	s.Run("summary", func() {
+		s.Equal("Disk usage of node node-1: 3 images, 4.0KiB reclaimable by image garbage collection", usage.Summary())
+	})
+}
+
+func (s *NodesDiskSuite) TestParsePruned() {
+	usage := &NodeDiskUsage{Node: "node-1"}
+	s.Require().NoError(usage.parse(nodeDiskUsageOutput +
+		`pruned {"images":[{"id":"sha256:small","repoTags":["example.com/app:1.0"],"size":"1024"},{"id":"sha256:pause","size":"512","pinned":true}]}`))
+	s.Require().Len(usage.PrunedImages, 1)
+	s.Equal("sha256:large", usage.PrunedImages[0].ID)
+	s.Equal(int64(4096), usage.ReclaimedBytes)
+	s.Contains(usage.Summary(), "1 images removed, 4.0KiB reclaimed")
+}
+
+func (s *NodesDiskSuite) TestParseWithoutRuntime() {
+	usage := &NodeDiskUsage{Node: "node-1"}
+	s.Require().NoError(usage.parse("du 4 /var/log\nnoruntime crictl is not installed on the node\n"))
+	s.Nil(usage.Images)
+	s.Equal([]string{"unable to list the runtime images: crictl is not installed on the node"}, usage.Warnings)
+}
+
+func (s *NodesDiskSuite) TestParseNodeFilesystems() {
+	filesystems, err := parseNodeFilesystems(`{"node":{"fs":{"availableBytes":250,"capacityBytes":1000,"usedBytes":600,"inodes":100,"inodesFree":40},
+		"runtime":{"imageFs":{"availableBytes":500,"capacityBytes":1000,"usedBytes":300}}}}`)
+	s.Require().NoError(err)
+	s.Equal([]NodeFilesystem{
+		{Name: "nodefs", CapacityBytes: 1000, UsedBytes: 600, AvailableBytes: 250, UsedPercent: 75, Inodes: 100, InodesFree: 40},
+		{Name: "imagefs", CapacityBytes: 1000, UsedBytes: 300, AvailableBytes: 500, UsedPercent: 50},
+	}, filesystems)
+	s.Run("invalid summary", func() {
+		_, err := parseNodeFilesystems("not json")
+		s.ErrorContains(err, "failed to parse the stats summary of the node")
+	})
+}
+
+func (s *NodesDiskSuite) TestFormatBytes() {
+	s.Equal("512B", formatBytes(512))
+	s.Equal("1.5KiB", formatBytes(1536))
+	s.Equal("2.0GiB", formatBytes(2*1024*1024*1024))
+}
+
+func TestNodesDisk(t *testing.T) {
+	suite.Run(t, new(NodesDiskSuite))
+}
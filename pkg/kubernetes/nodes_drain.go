@@ -0,0 +1,430 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
+)
+
+// drainPreviewMaxCandidates is the maximum number of candidate nodes reported for each evicted pod
+const drainPreviewMaxCandidates = 3
+
+var (
+	drainPDBViolationRule = findings.Rule{
+		ID:          "DRAIN001",
+		Name:        "PodDisruptionBudgetViolation",
+		Description: "Evicting the pods would exceed the disruptions allowed by a PodDisruptionBudget, the drain blocks until enough replacements are ready",
+		Severity:    findings.SeverityError,
+	}
+	drainLocalStorageRule = findings.Rule{
+		ID:          "DRAIN002",
+		Name:        "LocalStorage",
+		Description: "Pod uses emptyDir volumes, their data is lost when the pod is evicted (kubectl drain requires --delete-emptydir-data)",
+		Severity:    findings.SeverityWarning,
+	}
+	drainUnmanagedPodRule = findings.Rule{
+		ID:          "DRAIN003",
+		Name:        "UnmanagedPod",
+		Description: "Pod is not managed by a controller, it won't be recreated after the eviction (kubectl drain requires --force)",
+		Severity:    findings.SeverityWarning,
+	}
+	drainNoCandidateNodeRule = findings.Rule{
+		ID:          "DRAIN004",
+		Name:        "NoCandidateNode",
+		Description: "No other schedulable node fits the pod, its replacement will stay Pending",
+		Severity:    findings.SeverityWarning,
+	}
+	drainWorkloadOutageRule = findings.Rule{
+		ID:          "DRAIN005",
+		Name:        "WorkloadOutage",
+		Description: "All the replicas of the workload run on the drained node, the workload is unavailable until they're rescheduled",
+		Severity:    findings.SeverityWarning,
+	}
+)
+
+// DrainPreview describes the effects of draining a node (kubectl drain --ignore-daemonsets) without performing it
+type DrainPreview struct {
+	Node string `json:"node"`
+	// Cordoned is true if the node is already marked as unschedulable
+	Cordoned bool `json:"cordoned"`
+	// Evicted are the pods that would be evicted from the node
+	Evicted []DrainPreviewPod `json:"evicted"`
+	// Skipped are the pods that the drain leaves on the node (DaemonSet and static pods)
+	Skipped []DrainPreviewPod `json:"skipped,omitempty"`
+	// Workloads describes how the replicas of the affected workloads are distributed across the cluster
+	Workloads []DrainPreviewWorkload `json:"workloads,omitempty"`
+}
+
+type DrainPreviewPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Owner is the controller of the pod (Kind/name)
+	Owner  string `json:"owner,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// LocalStorage are the emptyDir volumes whose data is lost on eviction
+	LocalStorage []string `json:"localStorage,omitempty"`
+	// PodDisruptionBudgets are the PodDisruptionBudgets that apply to the pod
+	PodDisruptionBudgets []string `json:"podDisruptionBudgets,omitempty"`
+	// CandidateNodes are the nodes where the replacement is likely to be scheduled (most available resources first)
+	CandidateNodes []string `json:"candidateNodes,omitempty"`
+}
+
+type DrainPreviewWorkload struct {
+	Owner      string `json:"owner"`
+	Namespace  string `json:"namespace"`
+	PodsOnNode int    `json:"podsOnNode"`
+	TotalPods  int    `json:"totalPods"`
+}
+
+// NodesDrainPreview previews the drain of the node: the pods that would be evicted or skipped, the PodDisruptionBudgets
+// that would block the eviction, the pods with local storage, and the nodes where the replacements are likely to schedule.
+func (k *Kubernetes) NodesDrainPreview(ctx context.Context, name string) (*DrainPreview, *findings.Report, error) {
+	node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	pdbs, err := k.AccessControlClientset().PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Resolve the Deployments owning the ReplicaSets to report the workloads users actually manage
+	replicaSetOwners := map[string]string{}
+	for _, pod := range pods.Items {
+		ref := metav1.GetControllerOf(&pod)
+		if pod.Spec.NodeName != name || ref == nil || ref.Kind != "ReplicaSet" {
+			continue
+		}
+		key := pod.Namespace + "/" + ref.Name
+		if _, ok := replicaSetOwners[key]; ok {
+			continue
+		}
+		replicaSetOwners[key] = "ReplicaSet/" + ref.Name
+		if rs, rsErr := k.AccessControlClientset().AppsV1().ReplicaSets(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{}); rsErr == nil {
+			if owner := metav1.GetControllerOf(rs); owner != nil {
+				replicaSetOwners[key] = owner.Kind + "/" + owner.Name
+			}
+		}
+	}
+	preview, report := AnalyzeNodeDrain(node, nodes.Items, pods.Items, pdbs.Items, func(pod *v1.Pod) string {
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil {
+			return ""
+		}
+		if owner, ok := replicaSetOwners[pod.Namespace+"/"+ref.Name]; ok && ref.Kind == "ReplicaSet" {
+			return owner
+		}
+		return ref.Kind + "/" + ref.Name
+	})
+	return preview, report, nil
+}
+
+// AnalyzeNodeDrain previews the drain of the node given the cluster nodes, pods, and PodDisruptionBudgets.
+// The pods are classified with the same rules as kubectl drain --ignore-daemonsets.
+// The owner function returns the workload (Kind/name) that manages the pod, or an empty string for unmanaged pods.
+func AnalyzeNodeDrain(node *v1.Node, nodes []v1.Node, pods []v1.Pod, pdbs []policyv1.PodDisruptionBudget, owner func(*v1.Pod) string) (*DrainPreview, *findings.Report) {
+	preview := &DrainPreview{Node: node.Name, Cordoned: node.Spec.Unschedulable, Evicted: []DrainPreviewPod{}}
+	report := &findings.Report{Tool: "nodes_drain_preview", Findings: []findings.Finding{}}
+	podResource := func(pod *v1.Pod, field string) findings.Resource {
+		return findings.Resource{APIVersion: "v1", Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Field: field}
+	}
+
+	requested := map[string]v1.ResourceList{}
+	workloads := map[string]*DrainPreviewWorkload{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || isTerminalPod(pod) {
+			continue
+		}
+		requested[pod.Spec.NodeName] = addResourceList(requested[pod.Spec.NodeName], podRequests(pod))
+		if o := owner(pod); o != "" && !isDaemonSetPod(pod) && !isMirrorPod(pod) {
+			key := pod.Namespace + "/" + o
+			if workloads[key] == nil {
+				workloads[key] = &DrainPreviewWorkload{Owner: o, Namespace: pod.Namespace}
+			}
+			workloads[key].TotalPods++
+			if pod.Spec.NodeName == node.Name {
+				workloads[key].PodsOnNode++
+			}
+		}
+	}
+
+	evictedByPDB := map[*policyv1.PodDisruptionBudget][]string{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != node.Name || pod.DeletionTimestamp != nil {
+			continue
+		}
+		p := DrainPreviewPod{Namespace: pod.Namespace, Name: pod.Name, Owner: owner(pod)}
+		switch {
+		case isDaemonSetPod(pod):
+			p.Reason = "managed by a DaemonSet, ignored by the drain"
+			preview.Skipped = append(preview.Skipped, p)
+			continue
+		case isMirrorPod(pod):
+			p.Reason = "static (mirror) pod, it can only be removed from the node manifests"
+			preview.Skipped = append(preview.Skipped, p)
+			continue
+		case isTerminalPod(pod):
+			p.Reason = "completed pod, deleted without replacement"
+			preview.Evicted = append(preview.Evicted, p)
+			continue
+		}
+		if p.Owner == "" {
+			p.Reason = "not managed by a controller, it won't be recreated"
+			report.Add(drainUnmanagedPodRule, podResource(pod, "metadata.ownerReferences"),
+				fmt.Sprintf("Pod %s is not managed by a controller, it's deleted and not recreated when node %s is drained", pod.Name, node.Name),
+				"Manage the pod with a controller (e.g. Deployment) or recreate it manually after the drain")
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				p.LocalStorage = append(p.LocalStorage, volume.Name)
+			}
+		}
+		if len(p.LocalStorage) > 0 {
+			report.Add(drainLocalStorageRule, podResource(pod, "spec.volumes"),
+				fmt.Sprintf("Pod %s uses emptyDir volumes %s, their data is lost when the pod is evicted", pod.Name, strings.Join(p.LocalStorage, ", ")),
+				"Make sure the data can be recreated or move it to a persistent volume before draining the node")
+		}
+		for j := range pdbs {
+			pdb := &pdbs[j]
+			if pdb.Namespace != pod.Namespace || !pdbMatches(pdb, pod) {
+				continue
+			}
+			p.PodDisruptionBudgets = append(p.PodDisruptionBudgets, pdb.Name)
+			evictedByPDB[pdb] = append(evictedByPDB[pdb], pod.Name)
+		}
+		if p.Owner != "" {
+			p.CandidateNodes = candidateNodes(pod, node.Name, nodes, requested)
+			if len(p.CandidateNodes) == 0 {
+				report.Add(drainNoCandidateNodeRule, podResource(pod, ""),
+					fmt.Sprintf("No other schedulable node fits pod %s (resource requests, node selector, affinity, and taints), its replacement will stay Pending", pod.Name),
+					"Add capacity to the cluster or review the pod scheduling constraints before draining the node")
+			}
+		}
+		preview.Evicted = append(preview.Evicted, p)
+	}
+
+	for j := range pdbs {
+		pdb := &pdbs[j]
+		evicted := evictedByPDB[pdb]
+		if len(evicted) == 0 || int32(len(evicted)) <= pdb.Status.DisruptionsAllowed {
+			continue
+		}
+		report.Add(drainPDBViolationRule,
+			findings.Resource{APIVersion: "policy/v1", Kind: "PodDisruptionBudget", Namespace: pdb.Namespace, Name: pdb.Name, Field: "status.disruptionsAllowed"},
+			fmt.Sprintf("Draining node %s evicts %d pods (%s) covered by PodDisruptionBudget %s, which allows %d disruptions, the drain blocks until replacements are ready",
+				node.Name, len(evicted), strings.Join(evicted, ", "), pdb.Name, pdb.Status.DisruptionsAllowed),
+			"Scale up the workload or make sure its replacements can become ready on other nodes before draining")
+	}
+
+	keys := make([]string, 0, len(workloads))
+	for key, w := range workloads {
+		if w.PodsOnNode > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		w := workloads[key]
+		preview.Workloads = append(preview.Workloads, *w)
+		if w.PodsOnNode == w.TotalPods {
+			kind, name, _ := strings.Cut(w.Owner, "/")
+			report.Add(drainWorkloadOutageRule, findings.Resource{Kind: kind, Namespace: w.Namespace, Name: name},
+				fmt.Sprintf("All %d replicas of %s run on node %s, the workload is unavailable until they're rescheduled", w.TotalPods, w.Owner, node.Name),
+				"Scale up the workload and spread its replicas (topologySpreadConstraints or pod anti-affinity) before draining the node")
+		}
+	}
+	return preview, report
+}
+
+func isDaemonSetPod(pod *v1.Pod) bool {
+	ref := metav1.GetControllerOf(pod)
+	return ref != nil && ref.Kind == "DaemonSet"
+}
+
+// isMirrorPod returns true for the API representation of the static pods managed by the kubelet
+func isMirrorPod(pod *v1.Pod) bool {
+	_, ok := pod.Annotations[v1.MirrorPodAnnotationKey]
+	return ok
+}
+
+func isTerminalPod(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+func pdbMatches(pdb *policyv1.PodDisruptionBudget, pod *v1.Pod) bool {
+	if pdb.Spec.Selector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+// candidateNodes returns the nodes, other than the drained one, that fit the pod sorted by available resources
+func candidateNodes(pod *v1.Pod, drained string, nodes []v1.Node, requested map[string]v1.ResourceList) []string {
+	type candidate struct {
+		name  string
+		score float64
+	}
+	var candidates []candidate
+	podRequested := podRequests(pod)
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Name == drained || node.Spec.Unschedulable || !isNodeReady(node) ||
+			!toleratesNodeTaints(pod, node) || !matchesNodeSelector(pod, node) {
+			continue
+		}
+		score, fits := 0.0, true
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			allocatable := node.Status.Allocatable[name]
+			free := allocatable.DeepCopy()
+			free.Sub(requested[node.Name][name])
+			if podRequest := podRequested[name]; free.Cmp(podRequest) < 0 {
+				fits = false
+				break
+			}
+			if allocatable.MilliValue() > 0 {
+				free.Sub(podRequested[name])
+				score += float64(free.MilliValue()) / float64(allocatable.MilliValue())
+			}
+		}
+		if fits {
+			candidates = append(candidates, candidate{name: node.Name, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	var names []string
+	for i := 0; i < len(candidates) && i < drainPreviewMaxCandidates; i++ {
+		names = append(names, candidates[i].name)
+	}
+	return names
+}
+
+func isNodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func toleratesNodeTaints(pod *v1.Pod, node *v1.Node) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != v1.TaintEffectNoSchedule && taint.Effect != v1.TaintEffectNoExecute {
+			continue
+		}
+		if !slices.ContainsFunc(pod.Spec.Tolerations, func(t v1.Toleration) bool { return t.ToleratesTaint(taint) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNodeSelector checks the pod nodeSelector and required node affinity against the node labels
+func matchesNodeSelector(pod *v1.Pod, node *v1.Node) bool {
+	nodeLabels := labels.Set(node.Labels)
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(nodeLabels) {
+		return false
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// Node selector terms are ORed, the requirements of each term are ANDed
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		selector := labels.NewSelector()
+		valid := len(term.MatchExpressions) > 0
+		for _, expression := range term.MatchExpressions {
+			requirement, err := labels.NewRequirement(expression.Key, nodeSelectorOperators[expression.Operator], expression.Values)
+			if err != nil {
+				valid = false
+				break
+			}
+			selector = selector.Add(*requirement)
+		}
+		if valid && selector.Matches(nodeLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+var nodeSelectorOperators = map[v1.NodeSelectorOperator]selection.Operator{
+	v1.NodeSelectorOpIn:           selection.In,
+	v1.NodeSelectorOpNotIn:        selection.NotIn,
+	v1.NodeSelectorOpExists:       selection.Exists,
+	v1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	v1.NodeSelectorOpGt:           selection.GreaterThan,
+	v1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// podRequests returns the effective resource requests of the pod used by the scheduler:
+// the sum of the containers and native sidecars, or the largest init container if greater, plus the pod overhead
+func podRequests(pod *v1.Pod) v1.ResourceList {
+	requests := v1.ResourceList{}
+	sidecars := v1.ResourceList{}
+	for _, c := range pod.Spec.InitContainers {
+		if isNativeSidecar(c) {
+			sidecars = addResourceList(sidecars, c.Resources.Requests)
+			continue
+		}
+		// Regular init containers run after the previous native sidecars have started
+		init := addResourceList(v1.ResourceList{}, sidecars)
+		init = addResourceList(init, c.Resources.Requests)
+		maxResourceList(requests, init)
+	}
+	containers := addResourceList(v1.ResourceList{}, sidecars)
+	for _, c := range pod.Spec.Containers {
+		containers = addResourceList(containers, c.Resources.Requests)
+	}
+	maxResourceList(requests, containers)
+	return addResourceList(requests, pod.Spec.Overhead)
+}
+
+func addResourceList(list, add v1.ResourceList) v1.ResourceList {
+	if list == nil {
+		list = v1.ResourceList{}
+	}
+	for name, quantity := range add {
+		value := list[name]
+		value.Add(quantity)
+		list[name] = value
+	}
+	return list
+}
+
+func maxResourceList(list, other v1.ResourceList) {
+	for name, quantity := range other {
+		if value, ok := list[name]; !ok || quantity.Cmp(value) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type NodesDrainSuite struct {
+	suite.Suite
+}
+
+func drainTestNode(name string, cpu, memory string, mutate ...func(*v1.Node)) v1.Node {
+	node := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/hostname": name}},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse(memory)},
+			Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+	for _, m := range mutate {
+		m(&node)
+	}
+	return node
+}
+
+func drainTestPod(name, node, ownerKind, ownerName string, mutate ...func(*v1.Pod)) v1.Pod {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: name, Labels: map[string]string{"app": ownerName}},
+		Spec: v1.PodSpec{NodeName: node, Containers: []v1.Container{{Name: "c", Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("512Mi")},
+		}}}},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+	if ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName, Controller: ptr.To(true)}}
+	}
+	for _, m := range mutate {
+		m(&pod)
+	}
+	return pod
+}
+
+func drainTestOwner(pod *v1.Pod) string {
+	if ref := metav1.GetControllerOf(pod); ref != nil {
+		return ref.Kind + "/" + ref.Name
+	}
+	return ""
+}
+
+func (s *NodesDrainSuite) TestAnalyzeNodeDrain() {
+	drained := drainTestNode("node-1", "4", "8Gi")
+	nodes := []v1.Node{
+		drained,
+		drainTestNode("node-2", "4", "8Gi"),
+		drainTestNode("node-3", "8", "16Gi"),
+		drainTestNode("node-cordoned", "16", "32Gi", func(n *v1.Node) { n.Spec.Unschedulable = true }),
+		drainTestNode("node-gpu", "16", "32Gi", func(n *v1.Node) {
+			n.Spec.Taints = []v1.Taint{{Key: "nvidia.com/gpu", Effect: v1.TaintEffectNoSchedule}}
+		}),
+		drainTestNode("node-not-ready", "16", "32Gi", func(n *v1.Node) { n.Status.Conditions[0].Status = v1.ConditionFalse }),
+	}
+	pods := []v1.Pod{
+		drainTestPod("web-1", "node-1", "ReplicaSet", "web"),
+		drainTestPod("web-2", "node-1", "ReplicaSet", "web"),
+		drainTestPod("web-3", "node-2", "ReplicaSet", "web"),
+		drainTestPod("db-0", "node-1", "StatefulSet", "db", func(p *v1.Pod) {
+			p.Spec.Volumes = []v1.Volume{{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}}
+			p.Spec.NodeSelector = map[string]string{"kubernetes.io/hostname": "node-3"}
+		}),
+		drainTestPod("debug", "node-1", "", "debug"),
+		drainTestPod("huge", "node-1", "ReplicaSet", "huge", func(p *v1.Pod) {
+			p.Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("10")
+		}),
+		drainTestPod("node-exporter-abcde", "node-1", "DaemonSet", "node-exporter"),
+		drainTestPod("kube-apiserver-node-1", "node-1", "Node", "node-1", func(p *v1.Pod) {
+			p.Annotations = map[string]string{v1.MirrorPodAnnotationKey: "hash"}
+		}),
+		drainTestPod("job-done", "node-1", "Job", "job", func(p *v1.Pod) { p.Status.Phase = v1.PodSucceeded }),
+	}
+	pdbs := []policyv1.PodDisruptionBudget{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "web-other-namespace"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+		},
+	}
+	preview, report := AnalyzeNodeDrain(&drained, nodes, pods, pdbs, drainTestOwner)
+	s.Run("lists evicted pods", func() {
+		names := make([]string, 0, len(preview.Evicted))
+		for _, p := range preview.Evicted {
+			names = append(names, p.Name)
+		}
+		s.Equal([]string{"web-1", "web-2", "db-0", "debug", "huge", "job-done"}, names)
+	})
+	s.Run("lists skipped DaemonSet and static pods", func() {
+		s.Require().Len(preview.Skipped, 2)
+		s.Equal("node-exporter-abcde", preview.Skipped[0].Name)
+		s.Equal("managed by a DaemonSet, ignored by the drain", preview.Skipped[0].Reason)
+		s.Equal("kube-apiserver-node-1", preview.Skipped[1].Name)
+	})
+	s.Run("reports candidate nodes with most available resources first", func() {
+		// node-2 already runs web-3, node-cordoned, node-gpu (taint) and node-not-ready are excluded
+		s.Equal([]string{"node-3", "node-2"}, preview.Evicted[0].CandidateNodes)
+	})
+	s.Run("honors node selector", func() {
+		s.Equal([]string{"node-3"}, preview.Evicted[2].CandidateNodes)
+	})
+	s.Run("reports local storage", func() {
+		s.Equal([]string{"cache"}, preview.Evicted[2].LocalStorage)
+	})
+	s.Run("reports PodDisruptionBudgets in the same namespace", func() {
+		s.Equal([]string{"web"}, preview.Evicted[0].PodDisruptionBudgets)
+	})
+	s.Run("reports workload distribution", func() {
+		s.Equal([]DrainPreviewWorkload{
+			{Owner: "ReplicaSet/huge", Namespace: "ns-1", PodsOnNode: 1, TotalPods: 1},
+			{Owner: "ReplicaSet/web", Namespace: "ns-1", PodsOnNode: 2, TotalPods: 3},
+			{Owner: "StatefulSet/db", Namespace: "ns-1", PodsOnNode: 1, TotalPods: 1},
+		}, preview.Workloads)
+	})
+	s.Run("reports findings", func() {
+		ruleIDs := make([]string, 0, len(report.Findings))
+		for _, f := range report.Findings {
+			ruleIDs = append(ruleIDs, f.RuleID+" "+f.Resource.Name)
+		}
+		s.Equal([]string{
+			"DRAIN002 db-0",
+			"DRAIN003 debug",
+			"DRAIN004 huge",
+			"DRAIN001 web",
+			"DRAIN005 huge",
+			"DRAIN005 db",
+		}, ruleIDs)
+	})
+	s.Run("PodDisruptionBudget violation message", func() {
+		s.Equal("Draining node node-1 evicts 2 pods (web-1, web-2) covered by PodDisruptionBudget web, which allows 1 disruptions, the drain blocks until replacements are ready",
+			report.Findings[3].Message)
+	})
+}
+
+func (s *NodesDrainSuite) TestPodRequests() {
+	s.Run("sums containers and native sidecars plus overhead", func() {
+		requests := podRequests(&v1.Pod{Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Name: "sidecar", RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways), Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")}}},
+			},
+			Containers: []v1.Container{
+				{Name: "a", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m")}}},
+				{Name: "b", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("300m")}}},
+			},
+			Overhead: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+		}})
+		s.Equal(int64(650), requests.Cpu().MilliValue())
+	})
+	s.Run("uses the largest init container if greater", func() {
+		requests := podRequests(&v1.Pod{Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Name: "init", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")}}},
+			},
+			Containers: []v1.Container{
+				{Name: "a", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}}},
+			},
+		}})
+		s.Equal(int64(2*1024*1024*1024), requests.Memory().Value())
+	})
+}
+
+func (s *NodesDrainSuite) TestMatchesNodeSelector() {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"zone": "a", "cores": "8"}}}
+	affinity := func(expressions ...v1.NodeSelectorRequirement) *v1.Pod {
+		return &v1.Pod{Spec: v1.PodSpec{Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{MatchExpressions: expressions},
+			}},
+		}}}}
+	}
+	s.Run("matches In expression", func() {
+		s.True(matchesNodeSelector(affinity(v1.NodeSelectorRequirement{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a", "b"}}), node))
+	})
+	s.Run("matches Gt expression", func() {
+		s.True(matchesNodeSelector(affinity(v1.NodeSelectorRequirement{Key: "cores", Operator: v1.NodeSelectorOpGt, Values: []string{"4"}}), node))
+	})
+	s.Run("doesn't match NotIn expression", func() {
+		s.False(matchesNodeSelector(affinity(v1.NodeSelectorRequirement{Key: "zone", Operator: v1.NodeSelectorOpNotIn, Values: []string{"a"}}), node))
+	})
+	s.Run("doesn't match nodeSelector", func() {
+		s.False(matchesNodeSelector(&v1.Pod{Spec: v1.PodSpec{NodeSelector: map[string]string{"zone": "b"}}}, node))
+	})
+}
+
+func TestNodesDrain(t *testing.T) {
+	suite.Run(t, new(NodesDrainSuite))
+}
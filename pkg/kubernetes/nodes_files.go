@@ -0,0 +1,383 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+const (
+	// NodeFilesList lists the contents of a node directory (ls -la)
+	NodeFilesList = "list"
+	// NodeFilesGet copies a node file to the artifact store, or to the local filesystem of the server
+	NodeFilesGet = "get"
+	// NodeFilesPut copies a file from the local filesystem of the server to the node, unless the node file already has
+	// the same sha256 checksum, and verifies the checksum of the written file
+	NodeFilesPut = "put"
+	// nodeFilesMaxPutSize is the maximum size of the files copied to a node, the content is passed to the helper pod
+	// in its command, which is limited by the kernel maximum argument length (128 KiB once base64 encoded)
+	nodeFilesMaxPutSize = 64 * 1024
+	// NodeFilesMaxInlineSize is the maximum size of the files returned inline by the get operation, larger files must
+	// be stored as artifacts
+	NodeFilesMaxInlineSize = 256 * 1024
+)
+
+// NodeFilesOptions describes a node_files operation
+type NodeFilesOptions struct {
+	// NodeName is the name of the node to access
+	NodeName string
+	// Operation is one of NodeFilesList, NodeFilesGet or NodeFilesPut
+	Operation string
+	// SourcePath is the node path to list or get, or the local path of the file to put
+	SourcePath string
+	// DestPath is the local path where the node file is written (get, stored as an artifact if empty), or the node path
+	// of the file to put
+	DestPath string
+	// Inline returns the content of the node file in the result of the get operation instead of storing it
+	Inline bool
+	// Base64 returns the inline content base64 encoded, required for binary files
+	Base64 bool
+	// ReadOnly mounts the node root filesystem read-only in the helper pod and only allows the list and get operations
+	ReadOnly bool
+	// Tolerations of the helper pod, required to access the nodes with NoExecute taints (Optional)
+	Tolerations []v1.Toleration
+	// ImagePullSecret is the name of an image pull secret of the helper pod, added to the configured ones (Optional)
+	ImagePullSecret string
+	// Resources are the compute resource requests and limits of the helper pod, e.g. to comply with the LimitRanges or
+	// ResourceQuotas of its namespace (Optional)
+	Resources v1.ResourceRequirements
+}
+
+// NodesFiles lists, gets, or puts files on the node by running a privileged helper pod with the node root filesystem
+// mounted at HelperPodHostRoot, or a HostProcess helper pod running PowerShell on Windows nodes.
+// The node_files_read_only configuration enforces ReadOnly for every operation.
+// The node_files_unprivileged configuration runs an unprivileged helper pod, compatible with the restricted Pod
+// Security Standard security context, that only mounts (and can only access) the node_files_allowed_host_paths.
+// The node_files_allowed_paths and node_files_denied_paths configurations restrict the accessible node paths.
+func (k *Kubernetes) NodesFiles(ctx context.Context, options NodeFilesOptions) (string, error) {
+	readOnly := options.ReadOnly || k.AccessControlClientset().staticConfig.NodeFilesReadOnly
+	if readOnly && options.Operation != NodeFilesList && options.Operation != NodeFilesGet {
+		return "", fmt.Errorf("operation %s is not allowed in read-only mode, only %s and %s are allowed",
+			options.Operation, NodeFilesList, NodeFilesGet)
+	}
+	if options.Operation == NodeFilesGet && options.Inline && options.DestPath != "" {
+		return "", errors.New("inline and dest_path can't be used together")
+	}
+	nodePath := options.SourcePath
+	if options.Operation == NodeFilesPut {
+		nodePath = options.DestPath
+	}
+	node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, options.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", options.NodeName, err)
+	}
+	windows := IsWindowsNode(node)
+	var helperPodOptions HelperPodOptions
+	if windows {
+		helperPodOptions, err = k.nodeFilesWindowsHelperPodOptions(options.NodeName, nodePath)
+	} else {
+		helperPodOptions, err = k.nodeFilesHelperPodOptions(options.NodeName, readOnly, nodePath)
+	}
+	if err != nil {
+		return "", err
+	}
+	helperPodOptions.Tolerations = options.Tolerations
+	helperPodOptions.Resources = options.Resources
+	if options.ImagePullSecret != "" {
+		helperPodOptions.ImagePullSecrets = []string{options.ImagePullSecret}
+	}
+	switch {
+	case options.Operation == NodeFilesList && windows:
+		return k.nodeFilesWindowsList(ctx, helperPodOptions, options)
+	case options.Operation == NodeFilesList:
+		return k.nodeFilesList(ctx, helperPodOptions, options)
+	case options.Operation == NodeFilesGet && windows:
+		return k.nodeFilesWindowsGet(ctx, helperPodOptions, options)
+	case options.Operation == NodeFilesGet:
+		return k.nodeFilesGet(ctx, helperPodOptions, options)
+	case options.Operation == NodeFilesPut && windows:
+		return k.nodeFilesWindowsPut(ctx, helperPodOptions, options)
+	case options.Operation == NodeFilesPut:
+		return k.nodeFilesPut(ctx, helperPodOptions, options)
+	}
+	return "", fmt.Errorf("unsupported operation %s, supported operations are %s, %s and %s",
+		options.Operation, NodeFilesList, NodeFilesGet, NodeFilesPut)
+}
+
+// nodeFilesHelperPodOptions returns the options of the helper pod that accesses the provided node paths, a privileged
+// helper pod with the node root filesystem mounted, or an unprivileged helper pod with the allowed host paths mounted
+// if node_files_unprivileged is enabled.
+// The node paths are checked against node_files_allowed_paths, node_files_denied_paths, and, in unprivileged mode,
+// node_files_allowed_host_paths so that no helper pod is created for paths that are not allowed.
+func (k *Kubernetes) nodeFilesHelperPodOptions(nodeName string, readOnly bool, nodePaths ...string) (HelperPodOptions, error) {
+	staticConfig := k.AccessControlClientset().staticConfig
+	for _, nodePath := range nodePaths {
+		if _, err := nodeFilesHostPath(nodePath); err != nil {
+			return HelperPodOptions{}, err
+		}
+		if denied := nodeFilesMatchPath(nodePath, staticConfig.NodeFilesDeniedPaths); denied != "" {
+			return HelperPodOptions{}, fmt.Errorf("path not allowed: %s (denied by node_files_denied_paths entry %s)", nodePath, denied)
+		}
+		if len(staticConfig.NodeFilesAllowedPaths) > 0 && nodeFilesMatchPath(nodePath, staticConfig.NodeFilesAllowedPaths) == "" {
+			return HelperPodOptions{}, fmt.Errorf("path not allowed: %s (allowed paths are %s)",
+				nodePath, strings.Join(staticConfig.NodeFilesAllowedPaths, ", "))
+		}
+		if staticConfig.NodeFilesUnprivileged && nodeFilesMatchPath(nodePath, staticConfig.NodeFilesAllowedHostPaths) == "" {
+			return HelperPodOptions{}, fmt.Errorf("node path %s is not allowed in unprivileged mode, allowed paths are %s",
+				nodePath, strings.Join(staticConfig.NodeFilesAllowedHostPaths, ", "))
+		}
+	}
+	if !staticConfig.NodeFilesUnprivileged {
+		return HelperPodOptions{
+			Role:             HelperImageBusybox,
+			NodeName:         nodeName,
+			HostRoot:         true,
+			ReadOnlyHostRoot: readOnly,
+			// Required to access the files protected by SELinux or owned by other users
+			Privileged: true,
+		}, nil
+	}
+	return HelperPodOptions{
+		Role:             HelperImageBusybox,
+		NodeName:         nodeName,
+		HostPaths:        staticConfig.NodeFilesAllowedHostPaths,
+		ReadOnlyHostRoot: readOnly,
+		Restricted:       true,
+	}, nil
+}
+
+func (k *Kubernetes) nodeFilesList(ctx context.Context, helperPodOptions HelperPodOptions, options NodeFilesOptions) (string, error) {
+	nodePath, err := nodeFilesHostPath(options.SourcePath)
+	if err != nil {
+		return "", err
+	}
+	// The path is passed as a positional parameter so that it's never interpreted by the shell
+	helperPodOptions.Command = []string{"sh", "-c",
+		k.nodeFilesResolveScript() + `path=$(resolve "$1" "$2") || exit 1; ls -la -- "$path"`, "sh", nodePath, options.SourcePath}
+	return k.RunHelperPod(ctx, helperPodOptions)
+}
+
+func (k *Kubernetes) nodeFilesGet(ctx context.Context, helperPodOptions HelperPodOptions, options NodeFilesOptions) (string, error) {
+	nodePath, err := nodeFilesHostPath(options.SourcePath)
+	if err != nil {
+		return "", err
+	}
+	// The content is base64 encoded so that binary files are not altered by the pod logs
+	helperPodOptions.Command = []string{"sh", "-c",
+		k.nodeFilesResolveScript() + `path=$(resolve "$1" "$2") || exit 1` + "\n" +
+			`[ -f "$path" ] || { echo "$2 is not a regular file" >&2; exit 1; }; base64 -- "$path"`, "sh", nodePath, options.SourcePath}
+	encoded, err := k.RunHelperPod(ctx, helperPodOptions)
+	if err != nil {
+		return "", err
+	}
+	return k.nodeFilesGetResult(ctx, options, encoded)
+}
+
+// nodeFilesGetResult decodes the base64 encoded content of the node file printed by the helper pod and returns it
+// inline, writes it to the local dest_path, or stores it as an artifact
+func (k *Kubernetes) nodeFilesGetResult(ctx context.Context, options NodeFilesOptions, encoded string) (string, error) {
+	content, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode file %s of node %s: %w", options.SourcePath, options.NodeName, err)
+	}
+	if options.Inline {
+		return nodeFilesInline(options, content)
+	}
+	if options.DestPath == "" {
+		return k.nodeFilesStoreArtifact(ctx, options, content)
+	}
+	if err = os.WriteFile(filepath.Clean(options.DestPath), content, 0600); err != nil {
+		return "", fmt.Errorf("failed to write local file %s: %w", options.DestPath, err)
+	}
+	return fmt.Sprintf("File %s of node %s (%d bytes) copied to %s", options.SourcePath, options.NodeName, len(content), options.DestPath), nil
+}
+
+// nodeFilesInline returns the content of the node file, base64 encoded if requested, text files are returned as is
+func nodeFilesInline(options NodeFilesOptions, content []byte) (string, error) {
+	if len(content) > NodeFilesMaxInlineSize {
+		return "", fmt.Errorf("file %s of node %s is too large to be returned inline (%d bytes), the maximum size is %d bytes, get it without inline to store it as an artifact",
+			options.SourcePath, options.NodeName, len(content), NodeFilesMaxInlineSize)
+	}
+	if options.Base64 {
+		return base64.StdEncoding.EncodeToString(content), nil
+	}
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0 {
+		return "", fmt.Errorf("file %s of node %s is a binary file, use base64 to return it inline", options.SourcePath, options.NodeName)
+	}
+	if len(content) == 0 {
+		return fmt.Sprintf("File %s of node %s is empty", options.SourcePath, options.NodeName), nil
+	}
+	return string(content), nil
+}
+
+// nodeFilesStoreArtifact stores the content of the node file in the artifact store
+func (k *Kubernetes) nodeFilesStoreArtifact(ctx context.Context, options NodeFilesOptions, content []byte) (string, error) {
+	store := k.NewArtifactStore()
+	base := path.Base(options.SourcePath)
+	name := artifacts.NewName(time.Now(), path.Ext(base), "node-file", options.NodeName, strings.TrimSuffix(base, path.Ext(base)))
+	artifact, err := store.Put(ctx, name, content)
+	if artifact == nil {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to upload artifact %s to %s: %w", artifact.Name, store.RemoteName(), err)
+	}
+	ret := fmt.Sprintf("File %s of node %s (%d bytes) stored as artifact %s", options.SourcePath, options.NodeName, len(content), artifact.URI)
+	if artifact.URL != "" {
+		ret += " (uploaded to " + artifact.URL + ")"
+	}
+	return ret, nil
+}
+
+func (k *Kubernetes) nodeFilesPut(ctx context.Context, helperPodOptions HelperPodOptions, options NodeFilesOptions) (string, error) {
+	nodePath, err := nodeFilesHostPath(options.DestPath)
+	if err != nil {
+		return "", err
+	}
+	content, checksum, err := nodeFilesReadLocal(options, nodeFilesMaxPutSize)
+	if err != nil {
+		return "", err
+	}
+	// The copy is skipped if the node file already has the same checksum, so that retries are safe, and the written
+	// file is verified against the local checksum.
+	// The base64 alphabet can't terminate the quoted heredoc.
+	helperPodOptions.Command = []string{"sh", "-c",
+		k.nodeFilesResolveScript() + `path=$(resolve "$1" "$3") || exit 1` + "\n" +
+			`before=$(sha256sum -- "$path" 2>/dev/null | cut -d' ' -f1)` + "\n" +
+			`if [ "$before" = "$2" ]; then echo "unchanged $2"; exit 0; fi` + "\n" +
+			"base64 -d > \"$path\" <<'EOF'\n" + base64.StdEncoding.EncodeToString(content) + "\nEOF\n" +
+			`after=$(sha256sum -- "$path" | cut -d' ' -f1)` + "\n" +
+			`echo "${before:--} ${after:--}"` + "\n" +
+			`[ "$after" = "$2" ] || { echo "checksum mismatch, expected sha256 $2" >&2; exit 1; }` + "\n",
+		"sh", nodePath, checksum, options.DestPath}
+	out, err := k.RunHelperPod(ctx, helperPodOptions)
+	if err != nil {
+		return "", err
+	}
+	return nodeFilesPutResult(options, len(content), checksum, out)
+}
+
+// nodeFilesReadLocal returns the content of the local file to put and its sha256 checksum
+func nodeFilesReadLocal(options NodeFilesOptions, maxSize int) ([]byte, string, error) {
+	if options.SourcePath == "" {
+		return nil, "", errors.New("missing local source path")
+	}
+	content, err := os.ReadFile(filepath.Clean(options.SourcePath))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read local file %s: %w", options.SourcePath, err)
+	}
+	if len(content) > maxSize {
+		return nil, "", fmt.Errorf("local file %s is too large (%d bytes), the maximum size is %d bytes", options.SourcePath, len(content), maxSize)
+	}
+	sum := sha256.Sum256(content)
+	return content, hex.EncodeToString(sum[:]), nil
+}
+
+// nodeFilesPutResult describes the result of the put operation from the output of the helper pod, either
+// "unchanged <checksum>" if the copy was skipped, or "<checksum before (- if new)> <checksum after>"
+func nodeFilesPutResult(options NodeFilesOptions, size int, checksum, out string) (string, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) != 2 || fields[1] != checksum {
+		return "", fmt.Errorf("failed to verify the sha256 checksum %s of %s on node %s, unexpected helper pod output: %q",
+			checksum, options.DestPath, options.NodeName, strings.TrimSpace(out))
+	}
+	if fields[0] == "unchanged" {
+		return fmt.Sprintf("File %s (%d bytes) not copied, %s on node %s already has the same content (sha256 %s)",
+			options.SourcePath, size, options.DestPath, options.NodeName, checksum), nil
+	}
+	before := fields[0]
+	if before == "-" {
+		before = "none (new file)"
+	}
+	return fmt.Sprintf("File %s (%d bytes) copied to %s on node %s, sha256 before: %s, after: %s (verified)",
+		options.SourcePath, size, options.DestPath, options.NodeName, before, checksum), nil
+}
+
+// nodeFilesMatchPath returns the first of the paths that is the absolute node path or contains it, or "" if none
+func nodeFilesMatchPath(nodePath string, paths []string) string {
+	nodePath = path.Clean(nodePath)
+	for _, p := range paths {
+		if cleaned := path.Clean(p); nodePath == cleaned || strings.HasPrefix(nodePath, strings.TrimSuffix(cleaned, "/")+"/") {
+			return p
+		}
+	}
+	return ""
+}
+
+// nodeFilesResolveScript returns the definition of the resolve shell function of the Linux node_files helper pods
+func (k *Kubernetes) nodeFilesResolveScript() string {
+	return nodeFilesResolveScript(HelperPodHostRoot, k.AccessControlClientset().staticConfig)
+}
+
+// nodeFilesResolveScript returns the definition of a resolve shell function that prints the real path of a file in the
+// helper pod ($1, reported as the node path $2 in the errors), with its symbolic links resolved, or fails if the real
+// path is not under root or its node path is not allowed by node_files_allowed_paths, node_files_denied_paths and, in
+// unprivileged mode, node_files_allowed_host_paths.
+// The node paths are checked before creating the helper pod, the real paths are checked again on the node so that a
+// symbolic link in an allowed path can't point to a denied one. The missing files are resolved from their directory.
+func nodeFilesResolveScript(root string, staticConfig *config.StaticConfig) string {
+	quotedRoot := nodeFilesShellQuote(root)
+	script := &strings.Builder{}
+	script.WriteString("resolve() {\n")
+	script.WriteString(`real=$(realpath "$1" 2>/dev/null) || { dir=$(realpath "$(dirname "$1")" 2>/dev/null) && real="$dir/$(basename "$1")"; } || { echo "$2: no such file or directory" >&2; return 1; }` + "\n")
+	_, _ = fmt.Fprintf(script, `case "$real" in %s|%s/*) ;; *) echo "path not allowed: $2 (resolves outside of the node filesystem)" >&2; return 1;; esac`+"\n", quotedRoot, quotedRoot)
+	_, _ = fmt.Fprintf(script, `node="${real#%s}"; node="${node:-/}"`+"\n", quotedRoot)
+	for _, denied := range staticConfig.NodeFilesDeniedPaths {
+		_, _ = fmt.Fprintf(script, `case "$node" in %s) echo "path not allowed: $2 (resolves to $node, denied by node_files_denied_paths entry "%s")" >&2; return 1;; esac`+"\n",
+			nodeFilesCasePattern(denied), nodeFilesShellQuote(denied))
+	}
+	if len(staticConfig.NodeFilesAllowedPaths) > 0 {
+		_, _ = fmt.Fprintf(script, `case "$node" in %s) ;; *) echo "path not allowed: $2 (resolves to $node, allowed paths are "%s")" >&2; return 1;; esac`+"\n",
+			nodeFilesCasePattern(staticConfig.NodeFilesAllowedPaths...), nodeFilesShellQuote(strings.Join(staticConfig.NodeFilesAllowedPaths, ", ")))
+	}
+	if staticConfig.NodeFilesUnprivileged {
+		_, _ = fmt.Fprintf(script, `case "$node" in %s) ;; *) echo "node path $2 resolves to $node, which is not allowed in unprivileged mode, allowed paths are "%s >&2; return 1;; esac`+"\n",
+			nodeFilesCasePattern(staticConfig.NodeFilesAllowedHostPaths...), nodeFilesShellQuote(strings.Join(staticConfig.NodeFilesAllowedHostPaths, ", ")))
+	}
+	script.WriteString("echo \"$real\"\n}\n")
+	return script.String()
+}
+
+// nodeFilesCasePattern returns the shell case pattern that matches the node paths and their contents
+func nodeFilesCasePattern(nodePaths ...string) string {
+	patterns := make([]string, 0, 2*len(nodePaths))
+	for _, nodePath := range nodePaths {
+		cleaned := path.Clean(nodePath)
+		if cleaned == "/" {
+			patterns = append(patterns, "/*")
+			continue
+		}
+		patterns = append(patterns, nodeFilesShellQuote(cleaned), nodeFilesShellQuote(cleaned)+"/*")
+	}
+	return strings.Join(patterns, "|")
+}
+
+// nodeFilesShellQuote quotes the string so that it's never interpreted by the shell
+func nodeFilesShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// nodeFilesHostPath returns the path in the helper pod of the provided absolute node path
+func nodeFilesHostPath(nodePath string) (string, error) {
+	if !path.IsAbs(nodePath) {
+		return "", fmt.Errorf("node path must be absolute, got %q", nodePath)
+	}
+	return path.Join(HelperPodHostRoot, path.Clean(nodePath)), nil
+}
@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type NodesFilesSuite struct {
+	suite.Suite
+}
+
+func (s *NodesFilesSuite) TestNodeFilesInline() {
+	options := NodeFilesOptions{NodeName: "node-1", SourcePath: "/etc/kubernetes/kubelet.conf", Inline: true}
+	s.Run("returns text files as is", func() {
+		ret, err := nodeFilesInline(options, []byte("apiVersion: v1\n"))
+		s.Require().NoError(err)
+		s.Equal("apiVersion: v1\n", ret)
+	})
+	s.Run("describes empty files", func() {
+		ret, err := nodeFilesInline(options, nil)
+		s.Require().NoError(err)
+		s.Equal("File /etc/kubernetes/kubelet.conf of node node-1 is empty", ret)
+	})
+	s.Run("rejects binary files", func() {
+		for _, content := range []string{"kubelet\x00binary", "\xff\xfe"} {
+			_, err := nodeFilesInline(options, []byte(content))
+			s.EqualError(err, "file /etc/kubernetes/kubelet.conf of node node-1 is a binary file, use base64 to return it inline")
+		}
+	})
+	s.Run("returns base64 encoded files", func() {
+		base64Options := options
+		base64Options.Base64 = true
+		ret, err := nodeFilesInline(base64Options, []byte("kubelet\x00binary"))
+		s.Require().NoError(err)
+		s.Equal("a3ViZWxldABiaW5hcnk=", ret)
+	})
+	s.Run("rejects files larger than the maximum size", func() {
+		_, err := nodeFilesInline(options, []byte(strings.Repeat("a", NodeFilesMaxInlineSize+1)))
+		s.EqualError(err, "file /etc/kubernetes/kubelet.conf of node node-1 is too large to be returned inline (262145 bytes), "+
+			"the maximum size is 262144 bytes, get it without inline to store it as an artifact")
+	})
+}
+
+func (s *NodesFilesSuite) TestNodeFilesPutResult() {
+	options := NodeFilesOptions{NodeName: "node-1", SourcePath: "99-custom.conf", DestPath: "/etc/sysctl.d/99-custom.conf"}
+	s.Run("describes new files", func() {
+		ret, err := nodeFilesPutResult(options, 24, "abc", "- abc\n")
+		s.Require().NoError(err)
+		s.Equal("File 99-custom.conf (24 bytes) copied to /etc/sysctl.d/99-custom.conf on node node-1, sha256 before: none (new file), after: abc (verified)", ret)
+	})
+	s.Run("describes overwritten files", func() {
+		ret, err := nodeFilesPutResult(options, 24, "abc", "def abc\n")
+		s.Require().NoError(err)
+		s.Equal("File 99-custom.conf (24 bytes) copied to /etc/sysctl.d/99-custom.conf on node node-1, sha256 before: def, after: abc (verified)", ret)
+	})
+	s.Run("describes skipped copies", func() {
+		ret, err := nodeFilesPutResult(options, 24, "abc", "unchanged abc\n")
+		s.Require().NoError(err)
+		s.Equal("File 99-custom.conf (24 bytes) not copied, /etc/sysctl.d/99-custom.conf on node node-1 already has the same content (sha256 abc)", ret)
+	})
+	s.Run("fails on checksum mismatch", func() {
+		_, err := nodeFilesPutResult(options, 24, "abc", "def 123\n")
+		s.EqualError(err, `failed to verify the sha256 checksum abc of /etc/sysctl.d/99-custom.conf on node node-1, unexpected helper pod output: "def 123"`)
+	})
+	s.Run("fails on unexpected output", func() {
+		_, err := nodeFilesPutResult(options, 24, "abc", "")
+		s.EqualError(err, `failed to verify the sha256 checksum abc of /etc/sysctl.d/99-custom.conf on node node-1, unexpected helper pod output: ""`)
+	})
+}
+
+func (s *NodesFilesSuite) TestNodeFilesResolveScript() {
+	if _, err := exec.LookPath("realpath"); err != nil {
+		s.T().Skip("realpath is required to run the resolve script")
+	}
+	// The temporary directory is the node root filesystem mounted in the helper pod
+	root, err := filepath.EvalSymlinks(s.T().TempDir())
+	s.Require().NoError(err)
+	for _, dir := range []string{"etc/kubernetes/pki", "var/log/pods"} {
+		s.Require().NoError(os.MkdirAll(filepath.Join(root, dir), 0700))
+	}
+	s.Require().NoError(os.WriteFile(filepath.Join(root, "etc/kubernetes/pki/ca.key"), []byte("key"), 0600))
+	s.Require().NoError(os.WriteFile(filepath.Join(root, "var/log/pods/kubelet.log"), []byte("log"), 0600))
+	s.Require().NoError(os.Symlink("../../etc/kubernetes/pki/ca.key", filepath.Join(root, "var/log/pki-link")))
+	s.Require().NoError(os.Symlink("../../../etc/kubernetes/pki", filepath.Join(root, "var/log/pods/pki-dir")))
+	s.Require().NoError(os.Symlink("/", filepath.Join(root, "var/log/outside")))
+	staticConfig := &config.StaticConfig{
+		NodeFilesAllowedPaths: []string{"/var/log", "/etc/kubernetes"},
+		NodeFilesDeniedPaths:  []string{"/etc/kubernetes/pki"},
+	}
+	resolve := func(staticConfig *config.StaticConfig, nodePath string) (string, error) {
+		out, err := exec.Command("sh", "-c", nodeFilesResolveScript(root, staticConfig)+`resolve "$1" "$2"`,
+			"sh", filepath.Join(root, nodePath), nodePath).CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+	s.Run("resolves the allowed paths", func() {
+		out, err := resolve(staticConfig, "/var/log/pods/kubelet.log")
+		s.Require().NoError(err, out)
+		s.Equal(filepath.Join(root, "var/log/pods/kubelet.log"), out)
+	})
+	s.Run("resolves the missing files from their directory", func() {
+		out, err := resolve(staticConfig, "/var/log/pods/new.log")
+		s.Require().NoError(err, out)
+		s.Equal(filepath.Join(root, "var/log/pods/new.log"), out)
+	})
+	s.Run("rejects the symbolic links to denied files", func() {
+		out, err := resolve(staticConfig, "/var/log/pki-link")
+		s.Require().Error(err)
+		s.Equal("path not allowed: /var/log/pki-link (resolves to /etc/kubernetes/pki/ca.key, denied by node_files_denied_paths entry /etc/kubernetes/pki)", out)
+	})
+	s.Run("rejects the files in symbolic links to denied directories", func() {
+		out, err := resolve(staticConfig, "/var/log/pods/pki-dir/ca.key")
+		s.Require().Error(err)
+		s.Equal("path not allowed: /var/log/pods/pki-dir/ca.key (resolves to /etc/kubernetes/pki/ca.key, denied by node_files_denied_paths entry /etc/kubernetes/pki)", out)
+	})
+	s.Run("rejects the symbolic links to paths that are not allowed", func() {
+		out, err := resolve(&config.StaticConfig{NodeFilesAllowedPaths: []string{"/var/log/pods"}}, "/var/log/pods/pki-dir/ca.key")
+		s.Require().Error(err)
+		s.Equal("path not allowed: /var/log/pods/pki-dir/ca.key (resolves to /etc/kubernetes/pki/ca.key, allowed paths are /var/log/pods)", out)
+	})
+	s.Run("rejects the symbolic links to paths that are not mounted in unprivileged mode", func() {
+		out, err := resolve(&config.StaticConfig{NodeFilesUnprivileged: true, NodeFilesAllowedHostPaths: []string{"/var/log"}}, "/var/log/pki-link")
+		s.Require().Error(err)
+		s.Equal("node path /var/log/pki-link resolves to /etc/kubernetes/pki/ca.key, which is not allowed in unprivileged mode, allowed paths are /var/log", out)
+	})
+	s.Run("rejects the symbolic links outside of the node filesystem", func() {
+		out, err := resolve(staticConfig, "/var/log/outside/etc/passwd")
+		s.Require().Error(err)
+		s.Equal("path not allowed: /var/log/outside/etc/passwd (resolves outside of the node filesystem)", out)
+	})
+}
+
+func TestNodesFiles(t *testing.T) {
+	suite.Run(t, new(NodesFilesSuite))
+}
@@ -0,0 +1,245 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Fields of the node OS inventory, named after the node system info
+const (
+	NodeInventoryOperatingSystem  = "operatingSystem"
+	NodeInventoryArchitecture     = "architecture"
+	NodeInventoryOSImage          = "osImage"
+	NodeInventoryKernelVersion    = "kernelVersion"
+	NodeInventoryContainerRuntime = "containerRuntimeVersion"
+	NodeInventoryKubeletVersion   = "kubeletVersion"
+)
+
+// nodeInventoryFields are the fields of the inventory, in the order they're reported
+var nodeInventoryFields = []string{
+	NodeInventoryOperatingSystem, NodeInventoryArchitecture, NodeInventoryOSImage,
+	NodeInventoryKernelVersion, NodeInventoryContainerRuntime, NodeInventoryKubeletVersion,
+}
+
+// kubeletMaxMinorSkew is the number of minor versions a kubelet may be older than the API server
+// (https://kubernetes.io/releases/version-skew-policy/#kubelet)
+const kubeletMaxMinorSkew = 3
+
+// NodeOSInventory aggregates the OS, kernel, container runtime and kubelet versions of the nodes, and flags the nodes
+// whose versions differ from most nodes (outliers) and the kubelet versions outside the version skew policy
+type NodeOSInventory struct {
+	// Summary is a one line description, e.g. "5 nodes, 1 with outlier versions, 1 version skew finding"
+	Summary       string `json:"summary"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// Fields are the distinct values of each field, the most common value first
+	Fields []NodeInventoryField `json:"fields"`
+	Nodes  []NodeOSInfo         `json:"nodes"`
+	// Skew are the kubelet versions outside the version skew policy and the mixed kubelet minor versions
+	Skew     []string `json:"skew,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// NodeInventoryField is a field of the node system info with its distinct values
+type NodeInventoryField struct {
+	Field  string               `json:"field"`
+	Values []NodeInventoryValue `json:"values"`
+}
+
+// NodeInventoryValue is a value of a NodeInventoryField and the number of nodes reporting it
+type NodeInventoryValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+	// Nodes are the nodes reporting the value, only for the outlier values
+	Nodes []string `json:"nodes,omitempty"`
+	// Outlier is true if the value differs from the most common value among the nodes with the same operating system
+	Outlier bool `json:"outlier,omitempty"`
+}
+
+// NodeOSInfo is the system info of a node
+type NodeOSInfo struct {
+	Node                    string `json:"node"`
+	OperatingSystem         string `json:"operatingSystem"`
+	Architecture            string `json:"architecture"`
+	OSImage                 string `json:"osImage"`
+	KernelVersion           string `json:"kernelVersion"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+	KubeletVersion          string `json:"kubeletVersion"`
+	// Outliers are the fields whose value differs from most nodes
+	Outliers []string `json:"outliers,omitempty"`
+}
+
+func (n *NodeOSInfo) field(field string) string {
+	switch field {
+	case NodeInventoryOperatingSystem:
+		return n.OperatingSystem
+	case NodeInventoryArchitecture:
+		return n.Architecture
+	case NodeInventoryOSImage:
+		return n.OSImage
+	case NodeInventoryKernelVersion:
+		return n.KernelVersion
+	case NodeInventoryContainerRuntime:
+		return n.ContainerRuntimeVersion
+	case NodeInventoryKubeletVersion:
+		return n.KubeletVersion
+	}
+	return ""
+}
+
+// NodesOSInventory lists the nodes (all nodes or the ones matching the label selector) and aggregates their system
+// info. The API server version is only needed for the version skew policy, failing to retrieve it is a warning.
+func (k *Kubernetes) NodesOSInventory(ctx context.Context, labelSelector string) (*NodeOSInventory, error) {
+	nodes, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	serverVersion := ""
+	var warnings []string
+	if serverInfo, err := k.AccessControlClientset().DiscoveryClient().ServerVersion(); err != nil {
+		warnings = append(warnings, fmt.Sprintf("failed to get the API server version, the kubelet versions are not checked against the version skew policy: %v", err))
+	} else {
+		serverVersion = serverInfo.GitVersion
+	}
+	inventory := AnalyzeNodesOSInventory(nodes.Items, serverVersion)
+	inventory.Warnings = append(warnings, inventory.Warnings...)
+	return inventory, nil
+}
+
+// AnalyzeNodesOSInventory aggregates the system info of the nodes. The outliers of the OS image, kernel and container
+// runtime are detected among the nodes with the same operating system, a Windows node isn't an outlier of a Linux
+// cluster, and the architecture has no outliers (mixed architecture clusters are deliberate).
+func AnalyzeNodesOSInventory(nodes []v1.Node, serverVersion string) *NodeOSInventory {
+	inventory := &NodeOSInventory{ServerVersion: serverVersion, Fields: []NodeInventoryField{}, Nodes: make([]NodeOSInfo, 0, len(nodes))}
+	for _, node := range nodes {
+		info := node.Status.NodeInfo
+		inventory.Nodes = append(inventory.Nodes, NodeOSInfo{
+			Node:                    node.Name,
+			OperatingSystem:         info.OperatingSystem,
+			Architecture:            info.Architecture,
+			OSImage:                 info.OSImage,
+			KernelVersion:           info.KernelVersion,
+			ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+			KubeletVersion:          info.KubeletVersion,
+		})
+	}
+	slices.SortFunc(inventory.Nodes, func(a, b NodeOSInfo) int { return cmp.Compare(a.Node, b.Node) })
+	for _, field := range nodeInventoryFields {
+		inventory.Fields = append(inventory.Fields, inventory.analyzeField(field))
+	}
+	inventory.analyzeKubeletSkew()
+	outliers := 0
+	for _, node := range inventory.Nodes {
+		if len(node.Outliers) > 0 {
+			outliers++
+		}
+	}
+	inventory.Summary = fmt.Sprintf("%d nodes, %d with outlier versions, %d version skew findings", len(inventory.Nodes), outliers, len(inventory.Skew))
+	return inventory
+}
+
+// analyzeField counts the values of the field and flags the values that differ from the most common value among the
+// nodes with the same operating system
+func (i *NodeOSInventory) analyzeField(field string) NodeInventoryField {
+	// value -> nodes, and operating system -> value -> count
+	nodesByValue := map[string][]string{}
+	counts := map[string]map[string]int{}
+	for _, node := range i.Nodes {
+		value := node.field(field)
+		nodesByValue[value] = append(nodesByValue[value], node.Node)
+		if counts[node.OperatingSystem] == nil {
+			counts[node.OperatingSystem] = map[string]int{}
+		}
+		counts[node.OperatingSystem][value]++
+	}
+	outliers := map[string]bool{}
+	if field != NodeInventoryOperatingSystem && field != NodeInventoryArchitecture {
+		for os := range counts {
+			common := mostCommon(counts[os])
+			for n := range i.Nodes {
+				node := &i.Nodes[n]
+				if node.OperatingSystem == os && node.field(field) != common {
+					node.Outliers = append(node.Outliers, field)
+					outliers[node.field(field)] = true
+				}
+			}
+		}
+	}
+	ret := NodeInventoryField{Field: field, Values: make([]NodeInventoryValue, 0, len(nodesByValue))}
+	for value, nodes := range nodesByValue {
+		v := NodeInventoryValue{Value: value, Count: len(nodes), Outlier: outliers[value]}
+		if v.Outlier {
+			v.Nodes = nodes
+		}
+		ret.Values = append(ret.Values, v)
+	}
+	slices.SortFunc(ret.Values, func(a, b NodeInventoryValue) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+	})
+	return ret
+}
+
+// mostCommon returns the value with the highest count, the greatest value for ties (usually the newest version)
+func mostCommon(counts map[string]int) string {
+	common := ""
+	for _, value := range slices.Sorted(maps.Keys(counts)) {
+		if counts[value] >= counts[common] {
+			common = value
+		}
+	}
+	return common
+}
+
+// analyzeKubeletSkew reports the kubelets newer than the API server or older than the version skew policy allows,
+// and the mixed kubelet minor versions (e.g. an upgrade in progress)
+func (i *NodeOSInventory) analyzeKubeletSkew() {
+	var server *version.Version
+	if i.ServerVersion != "" {
+		parsed, err := version.ParseGeneric(i.ServerVersion)
+		if err != nil {
+			i.Warnings = append(i.Warnings, fmt.Sprintf("failed to parse the API server version %s: %v", i.ServerVersion, err))
+		} else {
+			server = parsed
+		}
+	}
+	minors := map[string][]string{}
+	for _, node := range i.Nodes {
+		if node.KubeletVersion == "" {
+			continue
+		}
+		kubelet, err := version.ParseGeneric(node.KubeletVersion)
+		if err != nil {
+			i.Warnings = append(i.Warnings, fmt.Sprintf("failed to parse the kubelet version %s of node %s: %v", node.KubeletVersion, node.Node, err))
+			continue
+		}
+		minor := fmt.Sprintf("%d.%d", kubelet.Major(), kubelet.Minor())
+		minors[minor] = append(minors[minor], node.Node)
+		switch {
+		case server == nil:
+		case kubelet.Major() != server.Major() || kubelet.Minor() > server.Minor():
+			i.Skew = append(i.Skew, fmt.Sprintf("the kubelet %s of node %s is newer than the API server %s, which the version skew policy doesn't allow",
+				node.KubeletVersion, node.Node, i.ServerVersion))
+		case server.Minor()-kubelet.Minor() > kubeletMaxMinorSkew:
+			i.Skew = append(i.Skew, fmt.Sprintf("the kubelet %s of node %s is %d minor versions older than the API server %s, the version skew policy allows %d",
+				node.KubeletVersion, node.Node, server.Minor()-kubelet.Minor(), i.ServerVersion, kubeletMaxMinorSkew))
+		}
+	}
+	if len(minors) > 1 {
+		var versions []string
+		for _, minor := range slices.SortedFunc(maps.Keys(minors), func(a, b string) int {
+			compare, _ := version.MustParseGeneric(a).Compare(b)
+			return compare
+		}) {
+			versions = append(versions, fmt.Sprintf("%s (%d nodes)", minor, len(minors[minor])))
+		}
+		i.Skew = append(i.Skew, fmt.Sprintf("the kubelets run %d minor versions: %s, e.g. an upgrade in progress, upgrade the older nodes",
+			len(minors), strings.Join(versions, ", ")))
+	}
+}
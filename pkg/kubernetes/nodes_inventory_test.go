@@ -0,0 +1,121 @@
+package kubernetes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type NodesOSInventorySuite struct {
+	suite.Suite
+}
+
+func inventoryNode(name, os, kernel, runtime, kubelet string) v1.Node {
+	return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{
+		OperatingSystem:         os,
+		Architecture:            "amd64",
+		OSImage:                 os + " image",
+		KernelVersion:           kernel,
+		ContainerRuntimeVersion: runtime,
+		KubeletVersion:          kubelet,
+	}}}
+}
+
+func (s *NodesOSInventorySuite) TestAnalyzeNodesOSInventory() {
+	inventory := AnalyzeNodesOSInventory([]v1.Node{
+		inventoryNode("node-3", "linux", "5.15.0-100", "containerd://1.7.2", "v1.30.2"),
+		inventoryNode("node-1", "linux", "5.15.0-105", "containerd://1.7.2", "v1.30.2"),
+		inventoryNode("node-2", "linux", "5.15.0-105", "containerd://1.7.2", "v1.29.6"),
+		inventoryNode("node-4", "linux", "5.15.0-105", "containerd://1.7.2", "v1.30.2"),
+		inventoryNode("win-1", "windows", "10.0.20348.2340", "containerd://1.7.2", "v1.30.2"),
+	}, "v1.30.4")
+	s.Run("returns the summary", func() {
+		s.Equal("5 nodes, 2 with outlier versions, 1 version skew findings", inventory.Summary)
+	})
+	s.Run("sorts the nodes by name", func() {
+		s.Equal("node-1", inventory.Nodes[0].Node)
+		s.Equal("win-1", inventory.Nodes[4].Node)
+	})
+	s.Run("counts the values of each field, the most common value first", func() {
+		s.Require().Len(inventory.Fields, 6)
+		kernel := inventory.Fields[3]
+		s.Equal(NodeInventoryKernelVersion, kernel.Field)
+		s.Equal([]NodeInventoryValue{
+			{Value: "5.15.0-105", Count: 3},
+			{Value: "10.0.20348.2340", Count: 1},
+			{Value: "5.15.0-100", Count: 1, Nodes: []string{"node-3"}, Outlier: true},
+		}, kernel.Values)
+	})
+	s.Run("flags the outliers among the nodes with the same operating system", func() {
+		s.Equal([]string{NodeInventoryKubeletVersion}, inventory.Nodes[1].Outliers)
+		s.Equal([]string{NodeInventoryKernelVersion}, inventory.Nodes[2].Outliers)
+		s.Empty(inventory.Nodes[4].Outliers)
+	})
+	s.Run("doesn't flag the operating system and architecture", func() {
+		s.False(inventory.Fields[0].Values[1].Outlier)
+	})
+	s.Run("reports the mixed kubelet minor versions", func() {
+		s.Equal([]string{"the kubelets run 2 minor versions: 1.29 (1 nodes), 1.30 (4 nodes), e.g. an upgrade in progress, upgrade the older nodes"}, inventory.Skew)
+	})
+}
+
+func (s *NodesOSInventorySuite) TestAnalyzeNodesOSInventorySkew() {
+	s.Run("reports the kubelets newer than the API server", func() {
+		inventory := AnalyzeNodesOSInventory([]v1.Node{inventoryNode("node-1", "linux", "6.1", "cri-o://1.31.0", "v1.31.0")}, "v1.30.4")
+		s.Equal([]string{"the kubelet v1.31.0 of node node-1 is newer than the API server v1.30.4, which the version skew policy doesn't allow"}, inventory.Skew)
+	})
+	s.Run("reports the kubelets older than the version skew policy allows", func() {
+		inventory := AnalyzeNodesOSInventory([]v1.Node{inventoryNode("node-1", "linux", "6.1", "cri-o://1.26.0", "v1.26.0")}, "v1.30.4")
+		s.Equal([]string{"the kubelet v1.26.0 of node node-1 is 4 minor versions older than the API server v1.30.4, the version skew policy allows 3"}, inventory.Skew)
+	})
+	s.Run("accepts the kubelets within the version skew policy", func() {
+		inventory := AnalyzeNodesOSInventory([]v1.Node{inventoryNode("node-1", "linux", "6.1", "cri-o://1.27.0", "v1.27.0")}, "v1.30.4")
+		s.Empty(inventory.Skew)
+		s.Equal("1 nodes, 0 with outlier versions, 0 version skew findings", inventory.Summary)
+	})
+	s.Run("warns about unparseable versions", func() {
+		inventory := AnalyzeNodesOSInventory([]v1.Node{inventoryNode("node-1", "linux", "6.1", "cri-o://1.27.0", "unknown")}, "v1.30.4")
+		s.Len(inventory.Warnings, 1)
+		s.Contains(inventory.Warnings[0], "failed to parse the kubelet version unknown of node node-1")
+	})
+}
+
+func (s *NodesOSInventorySuite) TestNodesOSInventory() {
+	mockServer := test.NewMockServer()
+	s.T().Cleanup(mockServer.Close)
+	mockServer.Handle(&test.DiscoveryClientHandler{})
+	mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/nodes" {
+			s.Equal("node-role.kubernetes.io/worker=", req.URL.Query().Get("labelSelector"))
+			test.WriteObject(w, &v1.NodeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"},
+				Items:    []v1.Node{inventoryNode("node-1", "linux", "6.1", "containerd://1.7.2", "v1.30.2")},
+			})
+		}
+	}))
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	inventory, err := k.NodesOSInventory(s.T().Context(), "node-role.kubernetes.io/worker=")
+	s.Require().NoError(err)
+	s.Run("returns the nodes matching the label selector", func() {
+		s.Len(inventory.Nodes, 1)
+	})
+	s.Run("warns when the API server version can't be retrieved", func() {
+		s.Require().Len(inventory.Warnings, 1)
+		s.Contains(inventory.Warnings[0], "failed to get the API server version")
+	})
+}
+
+func TestNodesOSInventory(t *testing.T) {
+	suite.Run(t, new(NodesOSInventorySuite))
+}
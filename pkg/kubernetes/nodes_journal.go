@@ -0,0 +1,53 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// journalUnitPattern matches the valid systemd unit names (and prevents them from being parsed as options)
+var journalUnitPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9@._:\\-]*$`)
+
+// nodesJournalScript builds the journalctl arguments from the unit, lines, since, and until positional parameters
+const nodesJournalScript = `unit="$1"; lines="$2"; since="$3"; until="$4"
+set -- --no-pager --output=short-iso --unit="$unit" --lines="$lines"
+if [ -n "$since" ]; then set -- "$@" --since="$since"; fi
+if [ -n "$until" ]; then set -- "$@" --until="$until"; fi
+exec journalctl "$@"`
+
+// NodesJournalOptions describes the journal entries to retrieve from a node
+type NodesJournalOptions struct {
+	// NodeName is the name of the node
+	NodeName string
+	// Unit is the systemd unit whose entries are retrieved, e.g. kubelet, crio, or containerd
+	Unit string
+	// Since and Until filter the entries by time, in any of the journalctl formats (e.g. "2025-01-01 10:00:00", "-1h", "yesterday")
+	Since string
+	Until string
+	// Lines is the number of most recent entries to retrieve (0 means all)
+	Lines int64
+}
+
+// NodesJournal returns the journalctl output of a systemd unit of the node.
+// The kubelet log query API (nodes_log) is not enabled on every distribution, the journal is read from the node
+// root filesystem by a privileged helper pod instead.
+func (k *Kubernetes) NodesJournal(ctx context.Context, options NodesJournalOptions) (string, error) {
+	if !journalUnitPattern.MatchString(options.Unit) {
+		return "", fmt.Errorf("invalid unit name %q", options.Unit)
+	}
+	if options.Lines < 0 {
+		return "", fmt.Errorf("invalid number of lines %d", options.Lines)
+	}
+	lines := "all"
+	if options.Lines > 0 {
+		lines = strconv.FormatInt(options.Lines, 10)
+	}
+	return k.RunNodeShell(ctx, NodeShellOptions{
+		NodeName: options.NodeName,
+		Script:   nodesJournalScript,
+		Args:     []string{options.Unit, lines, options.Since, options.Until},
+		ReadOnly: true,
+	})
+}
@@ -0,0 +1,407 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	// DefaultNotReadyTailLines is the default number of kubelet log lines analyzed by NodesWhyNotReady
+	DefaultNotReadyTailLines = int64(200)
+	// nodeHeartbeatGracePeriod is the default node-monitor-grace-period of the node lifecycle controller, the node is
+	// marked Unknown when its kubelet doesn't renew its Lease for longer
+	nodeHeartbeatGracePeriod = 40 * time.Second
+	// nodePressureThreshold is the PSI (avg10 or avg60 percentage of stalled time) above which a resource is reported
+	nodePressureThreshold = 10.0
+	// nodeReadinessMaxLogLines is the maximum number of kubelet log lines reported as evidence
+	nodeReadinessMaxLogLines = 10
+)
+
+// Hypothesis likelihoods, ranked from the most to the least likely
+const (
+	LikelihoodHigh   = "high"
+	LikelihoodMedium = "medium"
+	LikelihoodLow    = "low"
+)
+
+// cniPodNames are the name prefixes of the Pods of the common network plugins (CNI) and of the components they depend on
+var cniPodNames = []string{
+	"aws-node", "calico-node", "canal", "cilium", "antrea-agent", "flannel", "kube-flannel", "kindnet", "kube-ovn",
+	"ovnkube-node", "sdn-", "multus", "weave-net", "kube-router", "kube-proxy", "azure-cni", "azure-ip-masq",
+}
+
+// kubeletLogPatterns are the kubelet log messages matched to the hypotheses, the line is reported as evidence
+var kubeletLogPatterns = []struct {
+	cause   string
+	pattern *regexp.Regexp
+}{
+	{nodeCauseCertificate, regexp.MustCompile(`(?i)x509|certificate has expired|certificate signed by unknown authority|Unauthorized`)},
+	{nodeCauseAPIServer, regexp.MustCompile(`(?i)(Error updating node status|failed to renew lease|Unable to register node).*(connection refused|i/o timeout|no route to host|TLS handshake timeout|context deadline exceeded)`)},
+	{nodeCauseCNI, regexp.MustCompile(`(?i)NetworkPluginNotReady|network plugin is not ready|cni plugin not initialized|no networks found in /etc/cni/net.d`)},
+	{nodeCauseRuntime, regexp.MustCompile(`(?i)PLEG is not healthy|container runtime is down|ContainerRuntimeNotReady|container runtime status check may not have completed|connect: no such file or directory.*(containerd|crio)\.sock`)},
+	{nodeCauseResources, regexp.MustCompile(`(?i)eviction manager: .*(attempting to reclaim|must evict)|out of memory|no space left on device|fork/exec.*resource temporarily unavailable`)},
+}
+
+// Causes of the hypotheses reported by NodesWhyNotReady
+const (
+	nodeCauseUnreachable = "The node is down or unreachable from the control plane (powered off, kubelet not running, or network partition)"
+	nodeCauseAPIServer   = "The kubelet is running but can't reach the API server to update its status"
+	nodeCauseCertificate = "The kubelet client or serving certificate is expired or not trusted"
+	nodeCauseCNI         = "The network plugin (CNI) is not ready on the node"
+	nodeCauseRuntime     = "The container runtime is not healthy (PLEG not healthy or runtime down)"
+	nodeCauseResources   = "The node is running out of resources (memory, disk or PIDs)"
+	nodeCauseKubelet     = "The kubelet reports the node as not ready"
+)
+
+// NodeReadiness explains why a node is NotReady: the node conditions, the heartbeat lag of its kubelet, the
+// resources under pressure, the network plugin (CNI) Pods running on it, and the kubelet log errors, combined into
+// a list of hypotheses ranked by likelihood.
+type NodeReadiness struct {
+	// Summary is a one line description, e.g. "Node worker-1 is NotReady (Unknown for 12m), most likely: ..."
+	Summary string `json:"summary"`
+	Node    string `json:"node"`
+	// Ready is the status of the Ready condition (True, False or Unknown)
+	Ready         string `json:"ready"`
+	Unschedulable bool   `json:"unschedulable,omitempty"`
+	// Hypotheses are the likely causes of the node not being ready, the most likely first
+	Hypotheses []NodeHypothesis    `json:"hypotheses"`
+	Conditions []WorkloadCondition `json:"conditions,omitempty"`
+	Heartbeat  *NodeHeartbeat      `json:"heartbeat,omitempty"`
+	Pressure   *NodePressure       `json:"pressure,omitempty"`
+	CNIPods    []NodeReadinessPod  `json:"cniPods,omitempty"`
+	KubeletLog []string            `json:"kubeletLog,omitempty"`
+	Warnings   []string            `json:"warnings,omitempty"`
+	hypotheses map[string]*NodeHypothesis
+}
+
+// NodeHypothesis is a likely cause of a node not being ready with the evidence that supports it
+type NodeHypothesis struct {
+	Likelihood string   `json:"likelihood"`
+	Cause      string   `json:"cause"`
+	Evidence   []string `json:"evidence"`
+	// Next is a suggestion of the next troubleshooting step
+	Next string `json:"next,omitempty"`
+}
+
+// NodeHeartbeat is the time since the kubelet last renewed its Lease and updated the Ready condition
+type NodeHeartbeat struct {
+	// LeaseRenewTime is the last time the kubelet renewed its Lease in the kube-node-lease namespace
+	LeaseRenewTime string `json:"leaseRenewTime,omitempty"`
+	LeaseLag       string `json:"leaseLag,omitempty"`
+	// ConditionHeartbeatTime is the last time the kubelet updated the Ready condition
+	ConditionHeartbeatTime string `json:"conditionHeartbeatTime,omitempty"`
+	ConditionLag           string `json:"conditionLag,omitempty"`
+	// Stale is true when the Lease (or the condition if there is no Lease) is older than the grace period (40s)
+	Stale bool `json:"stale"`
+}
+
+// NodeReadinessPod is the status of a network plugin Pod running on the node
+type NodeReadinessPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Ready     bool   `json:"ready"`
+	Restarts  int32  `json:"restarts,omitempty"`
+}
+
+// NodeReadinessInput are the data gathered from the cluster analyzed by AnalyzeNodeReadiness
+type NodeReadinessInput struct {
+	Node *v1.Node
+	// Lease is the Lease of the node kubelet, nil if not found
+	Lease *coordinationv1.Lease
+	// Pods are the Pods running on the node
+	Pods []v1.Pod
+	// Pressure is the PSI of the node, nil if not available
+	Pressure *NodePressure
+	// KubeletLog is the tail of the kubelet log, KubeletLogError the error retrieving it through the API proxy
+	KubeletLog      string
+	KubeletLogError error
+	// StatsSummaryError is the error retrieving the kubelet stats summary through the API proxy
+	StatsSummaryError error
+	Now               time.Time
+}
+
+// NodesWhyNotReady gathers the NodeReadiness of the node with the provided name.
+// The kubelet log and stats summary are retrieved through the API server proxy, their failures are evidence (the
+// kubelet is unreachable) rather than errors. Failures retrieving the Lease or the Pods are reported as warnings.
+func (k *Kubernetes) NodesWhyNotReady(ctx context.Context, name string, tailLines int64) (*NodeReadiness, error) {
+	core := k.AccessControlClientset().CoreV1()
+	node, err := core.Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", name, err)
+	}
+	if tailLines <= 0 {
+		tailLines = DefaultNotReadyTailLines
+	}
+	input := NodeReadinessInput{Node: node, Now: time.Now()}
+	var warnings []string
+	lease, err := k.AccessControlClientset().CoordinationV1().Leases(v1.NamespaceNodeLease).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		input.Lease = lease
+	case !apierrors.IsNotFound(err):
+		warnings = append(warnings, fmt.Sprintf("unable to get the lease of the node: %v", err))
+	}
+	pods, err := core.Pods("").List(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String()})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("unable to list the pods of the node: %v", err))
+	} else {
+		input.Pods = pods.Items
+	}
+	input.KubeletLog, input.KubeletLogError = k.NodesLog(ctx, name, "kubelet", tailLines)
+	summary, err := k.nodeStatsSummary(ctx, name)
+	if err != nil {
+		input.StatsSummaryError = err
+	} else if input.Pressure, err = parseNodePressure(name, summary); err != nil {
+		warnings = append(warnings, fmt.Sprintf("unable to get the pressure of the node: %v", err))
+	}
+	readiness := AnalyzeNodeReadiness(input)
+	readiness.Warnings = append(warnings, readiness.Warnings...)
+	return readiness, nil
+}
+
+// AnalyzeNodeReadiness ranks the likely causes of the node not being ready from the gathered data
+func AnalyzeNodeReadiness(input NodeReadinessInput) *NodeReadiness {
+	node := input.Node
+	readiness := &NodeReadiness{
+		Node:          node.Name,
+		Ready:         string(v1.ConditionUnknown),
+		Unschedulable: node.Spec.Unschedulable,
+		Hypotheses:    []NodeHypothesis{},
+		hypotheses:    map[string]*NodeHypothesis{},
+	}
+	var ready *v1.NodeCondition
+	for i := range node.Status.Conditions {
+		condition := &node.Status.Conditions[i]
+		readiness.Conditions = append(readiness.Conditions, WorkloadCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.UTC().Format(time.RFC3339),
+		})
+		if condition.Type == v1.NodeReady {
+			ready = condition
+			readiness.Ready = string(condition.Status)
+		}
+	}
+	readiness.Heartbeat = nodeHeartbeat(ready, input.Lease, input.Now)
+	readiness.analyzeConditions(ready, input)
+	readiness.analyzePressure(node, input.Pressure)
+	readiness.analyzeCNIPods(input.Pods)
+	readiness.analyzeKubeletLog(input.KubeletLog)
+	for _, hypothesis := range readiness.hypotheses {
+		readiness.Hypotheses = append(readiness.Hypotheses, *hypothesis)
+	}
+	rank := map[string]int{LikelihoodHigh: 0, LikelihoodMedium: 1, LikelihoodLow: 2}
+	sort.SliceStable(readiness.Hypotheses, func(i, j int) bool {
+		hi, hj := readiness.Hypotheses[i], readiness.Hypotheses[j]
+		if rank[hi.Likelihood] != rank[hj.Likelihood] {
+			return rank[hi.Likelihood] < rank[hj.Likelihood]
+		}
+		if len(hi.Evidence) != len(hj.Evidence) {
+			return len(hi.Evidence) > len(hj.Evidence)
+		}
+		return hi.Cause < hj.Cause
+	})
+	readiness.Summary = readiness.summary(ready, input.Now)
+	return readiness
+}
+
+func nodeHeartbeat(ready *v1.NodeCondition, lease *coordinationv1.Lease, now time.Time) *NodeHeartbeat {
+	heartbeat := &NodeHeartbeat{}
+	var last time.Time
+	if ready != nil && !ready.LastHeartbeatTime.IsZero() {
+		heartbeat.ConditionHeartbeatTime = ready.LastHeartbeatTime.UTC().Format(time.RFC3339)
+		heartbeat.ConditionLag = now.Sub(ready.LastHeartbeatTime.Time).Round(time.Second).String()
+		last = ready.LastHeartbeatTime.Time
+	}
+	if lease != nil && lease.Spec.RenewTime != nil {
+		heartbeat.LeaseRenewTime = lease.Spec.RenewTime.UTC().Format(time.RFC3339)
+		heartbeat.LeaseLag = now.Sub(lease.Spec.RenewTime.Time).Round(time.Second).String()
+		last = lease.Spec.RenewTime.Time
+	}
+	if last.IsZero() {
+		return nil
+	}
+	heartbeat.Stale = now.Sub(last) > nodeHeartbeatGracePeriod
+	return heartbeat
+}
+
+// add adds the evidence to the hypothesis of the cause, raising its likelihood if needed
+func (r *NodeReadiness) add(cause, likelihood string, evidence ...string) {
+	hypothesis, ok := r.hypotheses[cause]
+	if !ok {
+		hypothesis = &NodeHypothesis{Likelihood: likelihood, Cause: cause, Evidence: []string{}, Next: nodeCauseNext[cause]}
+		r.hypotheses[cause] = hypothesis
+	}
+	if likelihood == LikelihoodHigh || (likelihood == LikelihoodMedium && hypothesis.Likelihood == LikelihoodLow) {
+		hypothesis.Likelihood = likelihood
+	}
+	hypothesis.Evidence = append(hypothesis.Evidence, evidence...)
+}
+
+func (r *NodeReadiness) analyzeConditions(ready *v1.NodeCondition, input NodeReadinessInput) {
+	kubeletUnreachable := input.KubeletLogError != nil && input.StatsSummaryError != nil
+	stale := r.Heartbeat != nil && r.Heartbeat.Stale
+	heartbeat := "the kubelet heartbeat is stale"
+	if r.Heartbeat != nil && r.Heartbeat.LeaseLag != "" {
+		heartbeat = fmt.Sprintf("the kubelet Lease was last renewed %s ago (grace period %s)", r.Heartbeat.LeaseLag, nodeHeartbeatGracePeriod)
+	} else if r.Heartbeat != nil && r.Heartbeat.ConditionLag != "" {
+		heartbeat = fmt.Sprintf("the Ready condition was last updated %s ago (grace period %s)", r.Heartbeat.ConditionLag, nodeHeartbeatGracePeriod)
+	}
+	if ready == nil || ready.Status == v1.ConditionUnknown || stale {
+		var evidence []string
+		if ready == nil {
+			evidence = append(evidence, "the node has no Ready condition, the kubelet never posted its status")
+		} else if ready.Status == v1.ConditionUnknown {
+			evidence = append(evidence, fmt.Sprintf("Ready condition is Unknown: %s", conditionText(ready)))
+		}
+		if stale {
+			evidence = append(evidence, heartbeat)
+		}
+		if kubeletUnreachable {
+			evidence = append(evidence, fmt.Sprintf("the kubelet is unreachable through the API server proxy: %v", input.KubeletLogError))
+			r.add(nodeCauseUnreachable, LikelihoodHigh, evidence...)
+		} else {
+			likelihood := LikelihoodMedium
+			if input.KubeletLogError == nil {
+				evidence = append(evidence, "the kubelet answers through the API server proxy, so it's running")
+				likelihood = LikelihoodHigh
+			}
+			r.add(nodeCauseAPIServer, likelihood, evidence...)
+		}
+		return
+	}
+	if ready.Status != v1.ConditionFalse {
+		return
+	}
+	text := conditionText(ready)
+	message := strings.ToLower(ready.Message)
+	switch {
+	case strings.Contains(message, "network") || strings.Contains(message, "cni"):
+		r.add(nodeCauseCNI, LikelihoodHigh, "Ready condition is False: "+text)
+	case strings.Contains(message, "pleg") || strings.Contains(message, "runtime"):
+		r.add(nodeCauseRuntime, LikelihoodHigh, "Ready condition is False: "+text)
+	default:
+		r.add(nodeCauseKubelet, LikelihoodMedium, "Ready condition is False: "+text)
+	}
+}
+
+func (r *NodeReadiness) analyzePressure(node *v1.Node, pressure *NodePressure) {
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case v1.NodeMemoryPressure, v1.NodeDiskPressure, v1.NodePIDPressure:
+			if condition.Status == v1.ConditionTrue {
+				r.add(nodeCauseResources, LikelihoodMedium, fmt.Sprintf("%s condition is True: %s", condition.Type, conditionText(&condition)))
+			}
+		}
+	}
+	r.Pressure = pressure
+	if pressure != nil && pressure.Pressure >= nodePressureThreshold {
+		r.add(nodeCauseResources, LikelihoodLow, fmt.Sprintf("%s PSI is %.2f%% (tasks stalled waiting for %s)", pressure.Resource, pressure.Pressure, pressure.Resource))
+	}
+}
+
+func (r *NodeReadiness) analyzeCNIPods(pods []v1.Pod) {
+	for i := range pods {
+		pod := &pods[i]
+		if !isCNIPod(pod) {
+			continue
+		}
+		status := NodeReadinessPod{Namespace: pod.Namespace, Name: pod.Name, Status: PodStatusReason(pod)}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady {
+				status.Ready = condition.Status == v1.ConditionTrue
+			}
+		}
+		for _, container := range pod.Status.ContainerStatuses {
+			status.Restarts += container.RestartCount
+		}
+		r.CNIPods = append(r.CNIPods, status)
+		if !status.Ready {
+			r.add(nodeCauseCNI, LikelihoodMedium, fmt.Sprintf("network plugin Pod %s/%s is not ready (%s, %d restarts)", pod.Namespace, pod.Name, status.Status, status.Restarts))
+		}
+	}
+}
+
+func isCNIPod(pod *v1.Pod) bool {
+	for _, prefix := range cniPodNames {
+		if strings.HasPrefix(pod.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *NodeReadiness) analyzeKubeletLog(log string) {
+	matched := 0
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(line)
+		for _, logPattern := range kubeletLogPatterns {
+			if !logPattern.pattern.MatchString(line) {
+				continue
+			}
+			likelihood := LikelihoodMedium
+			if logPattern.cause == nodeCauseCertificate {
+				likelihood = LikelihoodHigh
+			}
+			if matched < nodeReadinessMaxLogLines {
+				r.KubeletLog = append(r.KubeletLog, line)
+				r.add(logPattern.cause, likelihood, "kubelet log: "+line)
+			}
+			matched++
+			break
+		}
+	}
+	if matched > nodeReadinessMaxLogLines {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("%d more kubelet log lines matched, only the first %d are reported", matched-nodeReadinessMaxLogLines, nodeReadinessMaxLogLines))
+	}
+}
+
+// nodeCauseNext are the next troubleshooting steps suggested for each cause
+var nodeCauseNext = map[string]string{
+	nodeCauseUnreachable: "Check the machine from the infrastructure provider console, and the kubelet service and the network connectivity of the node (e.g. nodes_journal if the node is reachable)",
+	nodeCauseKubelet:     "Check the kubelet log of the node for the reason of the Ready condition",
+	nodeCauseCertificate: "Check the certificate signing requests of the node (certificates_list) and the expiration of the kubelet certificates",
+	nodeCauseAPIServer:   "Check the connectivity from the node to the API server (load balancer, firewall, DNS)",
+	nodeCauseCNI:         "Check the network plugin Pods of the node and their logs, and the CNI configuration in /etc/cni/net.d",
+	nodeCauseRuntime:     "Check the container runtime service of the node (e.g. nodes_journal with unit crio or containerd) and nodes_runtime_info",
+	nodeCauseResources:   "Check the top consumers of the node (pods_top, nodes_disk_usage) and the Pods evicted from it",
+}
+
+func conditionText(condition *v1.NodeCondition) string {
+	text := condition.Reason
+	if condition.Message != "" {
+		if text != "" {
+			text += ", "
+		}
+		text += condition.Message
+	}
+	return text
+}
+
+func (r *NodeReadiness) summary(ready *v1.NodeCondition, now time.Time) string {
+	status := "NotReady"
+	if r.Ready == string(v1.ConditionTrue) {
+		status = "Ready"
+	}
+	summary := fmt.Sprintf("Node %s is %s", r.Node, status)
+	if ready != nil && !ready.LastTransitionTime.IsZero() {
+		summary += fmt.Sprintf(" (%s for %s)", ready.Status, now.Sub(ready.LastTransitionTime.Time).Round(time.Second))
+	}
+	if len(r.Hypotheses) == 0 {
+		return summary + ", no likely cause found"
+	}
+	return summary + ", most likely: " + r.Hypotheses[0].Cause
+}
@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NodesNotReadySuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (s *NodesNotReadySuite) SetupTest() {
+	s.now = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *NodesNotReadySuite) node(conditions ...v1.NodeCondition) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}, Status: v1.NodeStatus{Conditions: conditions}}
+}
+
+func (s *NodesNotReadySuite) condition(conditionType v1.NodeConditionType, status v1.ConditionStatus, reason, message string, age time.Duration) v1.NodeCondition {
+	return v1.NodeCondition{
+		Type: conditionType, Status: status, Reason: reason, Message: message,
+		LastHeartbeatTime:  metav1.NewTime(s.now.Add(-age)),
+		LastTransitionTime: metav1.NewTime(s.now.Add(-age)),
+	}
+}
+
+func (s *NodesNotReadySuite) lease(age time.Duration) *coordinationv1.Lease {
+	return &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{RenewTime: &metav1.MicroTime{Time: s.now.Add(-age)}}}
+}
+
+func causes(readiness *NodeReadiness) []string {
+	ret := make([]string, 0, len(readiness.Hypotheses))
+	for _, hypothesis := range readiness.Hypotheses {
+		ret = append(ret, hypothesis.Likelihood+": "+hypothesis.Cause)
+	}
+	return ret
+}
+
+func (s *NodesNotReadySuite) TestUnreachableNode() {
+	proxyErr := errors.New("dial tcp 10.0.0.1:10250: i/o timeout")
+	readiness := AnalyzeNodeReadiness(NodeReadinessInput{
+		Node:              s.node(s.condition(v1.NodeReady, v1.ConditionUnknown, "NodeStatusUnknown", "Kubelet stopped posting node status.", 12*time.Minute)),
+		Lease:             s.lease(12 * time.Minute),
+		KubeletLogError:   proxyErr,
+		StatsSummaryError: proxyErr,
+		Now:               s.now,
+	})
+	s.Run("ranks the unreachable node first", func() {
+		s.Equal([]string{"high: " + nodeCauseUnreachable}, causes(readiness))
+		s.Contains(readiness.Hypotheses[0].Evidence, "the kubelet Lease was last renewed 12m0s ago (grace period 40s)")
+		s.Contains(readiness.Hypotheses[0].Evidence, "the kubelet is unreachable through the API server proxy: dial tcp 10.0.0.1:10250: i/o timeout")
+	})
+	s.Run("reports the heartbeat lag", func() {
+		s.Require().NotNil(readiness.Heartbeat)
+		s.True(readiness.Heartbeat.Stale)
+		s.Equal("12m0s", readiness.Heartbeat.LeaseLag)
+	})
+	s.Run("summary", func() {
+		s.Equal("Node worker-1 is NotReady (Unknown for 12m0s), most likely: "+nodeCauseUnreachable, readiness.Summary)
+	})
+}
+
+func (s *NodesNotReadySuite) TestKubeletCantReachAPIServer() {
+	readiness := AnalyzeNodeReadiness(NodeReadinessInput{
+		Node:  s.node(s.condition(v1.NodeReady, v1.ConditionUnknown, "NodeStatusUnknown", "Kubelet stopped posting node status.", 5*time.Minute)),
+		Lease: s.lease(5 * time.Minute),
+		KubeletLog: "I0110 11:55:00.000000 1 kubelet.go:100] starting\n" +
+			`E0110 11:56:00.000000 1 kubelet_node_status.go:540] "Error updating node status, will retry" err="error getting node: dial tcp 10.0.0.10:6443: connect: connection refused"` + "\n" +
+			`E0110 11:57:00.000000 1 certificate_manager.go:562] "Unhandled Error" err="x509: certificate has expired or is not yet valid"`,
+		Now: s.now,
+	})
+	s.Equal([]string{"high: " + nodeCauseAPIServer, "high: " + nodeCauseCertificate}, causes(readiness))
+	s.Contains(readiness.Hypotheses[0].Evidence, "the kubelet answers through the API server proxy, so it's running")
+	s.Len(readiness.KubeletLog, 2)
+}
+
+func (s *NodesNotReadySuite) TestNetworkPluginNotReady() {
+	readiness := AnalyzeNodeReadiness(NodeReadinessInput{
+		Node: s.node(
+			s.condition(v1.NodeReady, v1.ConditionFalse, "KubeletNotReady", "container runtime network not ready: NetworkReady=false reason:NetworkPluginNotReady", 3*time.Minute),
+			s.condition(v1.NodeDiskPressure, v1.ConditionTrue, "KubeletHasDiskPressure", "kubelet has disk pressure", 3*time.Minute),
+		),
+		Lease: s.lease(5 * time.Second),
+		Pods: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "calico-node-x7k2p"},
+				Status: v1.PodStatus{
+					Phase:             v1.PodRunning,
+					Conditions:        []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionFalse}},
+					ContainerStatuses: []v1.ContainerStatus{{Name: "calico-node", RestartCount: 7}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web-1"},
+				Status:     v1.PodStatus{Phase: v1.PodRunning},
+			},
+		},
+		Pressure: &NodePressure{Node: "worker-1", Pressure: 2, Resource: "io"},
+		Now:      s.now,
+	})
+	s.Run("ranks the network plugin first", func() {
+		s.Equal([]string{"high: " + nodeCauseCNI, "medium: " + nodeCauseResources}, causes(readiness))
+		s.Equal([]string{
+			"Ready condition is False: KubeletNotReady, container runtime network not ready: NetworkReady=false reason:NetworkPluginNotReady",
+			"network plugin Pod kube-system/calico-node-x7k2p is not ready (Running, 7 restarts)",
+		}, readiness.Hypotheses[0].Evidence)
+	})
+	s.Run("reports the network plugin Pods only", func() {
+		s.Equal([]NodeReadinessPod{{Namespace: "kube-system", Name: "calico-node-x7k2p", Status: "Running", Restarts: 7}}, readiness.CNIPods)
+	})
+	s.Run("heartbeat is not stale", func() {
+		s.False(readiness.Heartbeat.Stale)
+	})
+}
+
+func (s *NodesNotReadySuite) TestReadyNode() {
+	readiness := AnalyzeNodeReadiness(NodeReadinessInput{
+		Node:  s.node(s.condition(v1.NodeReady, v1.ConditionTrue, "KubeletReady", "kubelet is posting ready status", time.Hour)),
+		Lease: s.lease(3 * time.Second),
+		Now:   s.now,
+	})
+	s.Empty(readiness.Hypotheses)
+	s.Equal("Node worker-1 is Ready (True for 1h0m0s), no likely cause found", readiness.Summary)
+}
+
+func TestNodesNotReady(t *testing.T) {
+	suite.Run(t, new(NodesNotReadySuite))
+}
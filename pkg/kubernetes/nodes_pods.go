@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Sort orders of NodesPods
+const (
+	NodePodsSortByName   = "name"
+	NodePodsSortByCPU    = "cpu"
+	NodePodsSortByMemory = "memory"
+)
+
+// NodePod is a pod scheduled on a node with its effective resource requests and limits (see podRequests)
+type NodePod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	// Restarts is the sum of the restarts of the containers and init containers
+	Restarts int32           `json:"restarts"`
+	Requests v1.ResourceList `json:"requests,omitempty"`
+	Limits   v1.ResourceList `json:"limits,omitempty"`
+	QOSClass string          `json:"qosClass,omitempty"`
+}
+
+// NodesPods lists the pods scheduled on the node with the provided name (fieldSelector spec.nodeName).
+// The pods are sorted by namespace and name, or by their cpu or memory request (the largest first).
+func (k *Kubernetes) NodesPods(ctx context.Context, name, sortBy string) ([]NodePod, error) {
+	core := k.AccessControlClientset().CoreV1()
+	// the node is retrieved so that a wrong name fails instead of returning no pods
+	if _, err := core.Nodes().Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+	pods, err := core.Pods("").List(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String()})
+	if err != nil {
+		return nil, err
+	}
+	return NodePodsOf(pods.Items, sortBy)
+}
+
+// NodePodsOf returns the NodePod of each of the pods in the provided sort order
+func NodePodsOf(pods []v1.Pod, sortBy string) ([]NodePod, error) {
+	var resourceName v1.ResourceName
+	switch sortBy {
+	case "", NodePodsSortByName:
+	case NodePodsSortByCPU:
+		resourceName = v1.ResourceCPU
+	case NodePodsSortByMemory:
+		resourceName = v1.ResourceMemory
+	default:
+		return nil, fmt.Errorf("invalid sort order %s, valid values are: %s, %s, %s", sortBy, NodePodsSortByName, NodePodsSortByCPU, NodePodsSortByMemory)
+	}
+	nodePods := make([]NodePod, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		nodePod := NodePod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Phase:     string(pod.Status.Phase),
+			Requests:  podRequests(pod),
+			Limits:    podLimits(pod),
+			QOSClass:  string(pod.Status.QOSClass),
+		}
+		for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, status := range statuses {
+				nodePod.Restarts += status.RestartCount
+			}
+		}
+		nodePods = append(nodePods, nodePod)
+	}
+	sort.SliceStable(nodePods, func(i, j int) bool {
+		if resourceName != "" {
+			a, b := nodePods[i].Requests[resourceName], nodePods[j].Requests[resourceName]
+			if c := a.Cmp(b); c != 0 {
+				return c > 0
+			}
+		}
+		if nodePods[i].Namespace != nodePods[j].Namespace {
+			return nodePods[i].Namespace < nodePods[j].Namespace
+		}
+		return nodePods[i].Name < nodePods[j].Name
+	})
+	return nodePods, nil
+}
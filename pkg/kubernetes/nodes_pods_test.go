@@ -0,0 +1,76 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NodesPodsSuite struct {
+	suite.Suite
+}
+
+func nodePodsTestPod(namespace, name, cpu, memory string) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "c", Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse(memory)},
+		}}}},
+		Status: v1.PodStatus{Phase: v1.PodRunning, QOSClass: v1.PodQOSBurstable},
+	}
+}
+
+func (s *NodesPodsSuite) TestNodePodsOf() {
+	pods := []v1.Pod{
+		nodePodsTestPod("ns-2", "api", "1", "256Mi"),
+		nodePodsTestPod("ns-1", "web", "100m", "1Gi"),
+		nodePodsTestPod("ns-1", "db", "500m", "512Mi"),
+	}
+	pods[2].Spec.Containers[0].Resources.Limits = v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}
+	pods[2].Status.InitContainerStatuses = []v1.ContainerStatus{{Name: "init", RestartCount: 1}}
+	pods[2].Status.ContainerStatuses = []v1.ContainerStatus{{Name: "c", RestartCount: 2}}
+	names := func(nodePods []NodePod) []string {
+		var ret []string
+		for _, p := range nodePods {
+			ret = append(ret, p.Namespace+"/"+p.Name)
+		}
+		return ret
+	}
+	s.Run("sorts by namespace and name by default", func() {
+		nodePods, err := NodePodsOf(pods, "")
+		s.Require().NoError(err)
+		s.Equal([]string{"ns-1/db", "ns-1/web", "ns-2/api"}, names(nodePods))
+	})
+	s.Run("sorts by memory request", func() {
+		nodePods, err := NodePodsOf(pods, NodePodsSortByMemory)
+		s.Require().NoError(err)
+		s.Equal([]string{"ns-1/web", "ns-1/db", "ns-2/api"}, names(nodePods))
+	})
+	s.Run("sorts by cpu request", func() {
+		nodePods, err := NodePodsOf(pods, NodePodsSortByCPU)
+		s.Require().NoError(err)
+		s.Equal([]string{"ns-2/api", "ns-1/db", "ns-1/web"}, names(nodePods))
+	})
+	s.Run("returns the pod status and resources", func() {
+		nodePods, err := NodePodsOf(pods, "")
+		s.Require().NoError(err)
+		db := nodePods[0]
+		s.Equal("Running", db.Phase)
+		s.Equal(int32(3), db.Restarts)
+		s.Equal("Burstable", db.QOSClass)
+		s.Equal("512Mi", db.Requests.Memory().String())
+		s.Equal("1Gi", db.Limits.Memory().String())
+		s.NotContains(db.Limits, v1.ResourceCPU)
+	})
+	s.Run("rejects invalid sort order", func() {
+		_, err := NodePodsOf(pods, "restarts")
+		s.EqualError(err, "invalid sort order restarts, valid values are: name, cpu, memory")
+	})
+}
+
+func TestNodesPods(t *testing.T) {
+	suite.Run(t, new(NodesPodsSuite))
+}
@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// PSIData is the pressure of a resource over the last 10, 60 and 300 seconds (percentage of time)
+type PSIData struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300,omitempty"`
+}
+
+// PSIStats is the PSI (Pressure Stall Information) of a resource as reported by the kubelet Summary API:
+// some is the share of time at least one task was stalled, full is the share of time all non-idle tasks were stalled
+// https://kubernetes.io/docs/reference/instrumentation/understand-psi-metrics/
+type PSIStats struct {
+	Some *PSIData `json:"some,omitempty"`
+	Full *PSIData `json:"full,omitempty"`
+}
+
+// max returns the highest avg10 or avg60 value of the some and full pressure
+func (p *PSIStats) max() float64 {
+	if p == nil {
+		return 0
+	}
+	ret := 0.0
+	for _, data := range []*PSIData{p.Some, p.Full} {
+		if data != nil {
+			ret = max(ret, data.Avg10, data.Avg60)
+		}
+	}
+	return ret
+}
+
+// NodePressure is the PSI of the CPU, memory and I/O of a node
+type NodePressure struct {
+	Node   string    `json:"node"`
+	CPU    *PSIStats `json:"cpu,omitempty"`
+	Memory *PSIStats `json:"memory,omitempty"`
+	IO     *PSIStats `json:"io,omitempty"`
+	// Pressure is the highest avg10 or avg60 value of all the resources, used to rank the nodes
+	Pressure float64 `json:"pressure"`
+	// Resource is the resource with the highest pressure (cpu, memory or io)
+	Resource string `json:"resource,omitempty"`
+}
+
+// nodeStatsSummaryPSI is the subset of the kubelet Summary API response with the node PSI
+type nodeStatsSummaryPSI struct {
+	Node struct {
+		CPU *struct {
+			PSI *PSIStats `json:"psi"`
+		} `json:"cpu"`
+		Memory *struct {
+			PSI *PSIStats `json:"psi"`
+		} `json:"memory"`
+		IO *struct {
+			PSI *PSIStats `json:"psi"`
+		} `json:"io"`
+	} `json:"node"`
+}
+
+// NodesPressure retrieves the PSI of every node matching the label selector from the kubelet stats summaries,
+// ranked by pressure (highest first).
+// Nodes whose kubelet can't be reached, or that don't report PSI (it requires cgroup v2, Linux 4.20+ and the
+// KubeletPSI feature gate), are returned as TargetErrors.
+func (k *Kubernetes) NodesPressure(ctx context.Context, labelSelector string) ([]NodePressure, TargetErrors, error) {
+	summaries, targetErrors, err := k.NodesStatsSummaries(ctx, labelSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	pressures := make([]NodePressure, 0, len(summaries))
+	for _, summary := range summaries {
+		pressure, err := parseNodePressure(summary.Node, summary.Summary)
+		if err != nil {
+			targetErrors.Add("node/"+summary.Node, err)
+			continue
+		}
+		pressures = append(pressures, *pressure)
+	}
+	sort.SliceStable(pressures, func(i, j int) bool {
+		return pressures[i].Pressure > pressures[j].Pressure
+	})
+	return pressures, targetErrors, nil
+}
+
+func parseNodePressure(node, summary string) (*NodePressure, error) {
+	stats := &nodeStatsSummaryPSI{}
+	if err := json.Unmarshal([]byte(summary), stats); err != nil {
+		return nil, fmt.Errorf("failed to parse node stats summary: %w", err)
+	}
+	pressure := &NodePressure{Node: node}
+	if stats.Node.CPU != nil {
+		pressure.CPU = stats.Node.CPU.PSI
+	}
+	if stats.Node.Memory != nil {
+		pressure.Memory = stats.Node.Memory.PSI
+	}
+	if stats.Node.IO != nil {
+		pressure.IO = stats.Node.IO.PSI
+	}
+	if pressure.CPU == nil && pressure.Memory == nil && pressure.IO == nil {
+		return nil, errors.New("PSI metrics not available, they require cgroup v2, Linux 4.20+ and the KubeletPSI feature gate")
+	}
+	for _, resource := range []struct {
+		name  string
+		stats *PSIStats
+	}{{"cpu", pressure.CPU}, {"memory", pressure.Memory}, {"io", pressure.IO}} {
+		if value := resource.stats.max(); value > pressure.Pressure || (pressure.Resource == "" && resource.stats != nil) {
+			pressure.Pressure, pressure.Resource = value, resource.name
+		}
+	}
+	return pressure, nil
+}
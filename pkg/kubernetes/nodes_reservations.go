@@ -0,0 +1,245 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// DefaultEvictionMargin is the default percentage of the capacity above an eviction threshold within which a node is
+// reported as close to the threshold
+const DefaultEvictionMargin = 5.0
+
+// Status of the eviction signals, from the least to the most severe
+const (
+	EvictionSignalUnknown  = "unknown"
+	EvictionSignalOK       = "ok"
+	EvictionSignalClose    = "close"
+	EvictionSignalExceeded = "exceeded"
+)
+
+var evictionSignalSeverity = map[string]int{
+	EvictionSignalUnknown:  0,
+	EvictionSignalOK:       1,
+	EvictionSignalClose:    2,
+	EvictionSignalExceeded: 3,
+}
+
+// NodeReservations are the resources a node reserves for the system and Kubernetes daemons, its eviction thresholds
+// (kubelet configz), and how close the available resources (kubelet stats summary) are to the thresholds.
+type NodeReservations struct {
+	Node string `json:"node"`
+	// Status is the most severe status of the eviction signals
+	Status         string            `json:"status"`
+	Capacity       v1.ResourceList   `json:"capacity,omitempty"`
+	Allocatable    v1.ResourceList   `json:"allocatable,omitempty"`
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+	KubeReserved   map[string]string `json:"kubeReserved,omitempty"`
+	// Signals are the eviction thresholds compared with the available resources, hard thresholds first
+	Signals []NodeEvictionSignal `json:"signals"`
+}
+
+// NodeEvictionSignal is an eviction threshold of the kubelet compared with the available resource it applies to
+type NodeEvictionSignal struct {
+	// Signal is the eviction signal, e.g. memory.available or nodefs.inodesFree
+	Signal string `json:"signal"`
+	// Eviction is hard (immediate eviction) or soft (eviction after the grace period)
+	Eviction string `json:"eviction"`
+	// Threshold is the configured threshold, a quantity (100Mi) or a percentage of the capacity (10%)
+	Threshold      string `json:"threshold"`
+	ThresholdValue int64  `json:"thresholdValue"`
+	Available      *int64 `json:"available,omitempty"`
+	Capacity       *int64 `json:"capacity,omitempty"`
+	// Headroom is the percentage of the capacity available above the threshold (negative when exceeded)
+	Headroom *float64 `json:"headroom,omitempty"`
+	Status   string   `json:"status"`
+}
+
+// nodeConfigz is the subset of the kubelet configz response with the reservations and the eviction thresholds
+type nodeConfigz struct {
+	KubeletConfig *struct {
+		SystemReserved map[string]string `json:"systemReserved"`
+		KubeReserved   map[string]string `json:"kubeReserved"`
+		EvictionHard   map[string]string `json:"evictionHard"`
+		EvictionSoft   map[string]string `json:"evictionSoft"`
+	} `json:"kubeletconfig"`
+}
+
+// nodeStatsSummaryAvailable is the subset of the kubelet Summary API response with the resources evaluated by the
+// eviction manager
+type nodeStatsSummaryAvailable struct {
+	Node struct {
+		Memory *struct {
+			AvailableBytes *uint64 `json:"availableBytes"`
+		} `json:"memory"`
+		Fs      *nodeFsStats `json:"fs"`
+		Runtime *struct {
+			ImageFs     *nodeFsStats `json:"imageFs"`
+			ContainerFs *nodeFsStats `json:"containerFs"`
+		} `json:"runtime"`
+		Rlimit *struct {
+			MaxPID  *int64 `json:"maxpid"`
+			CurProc *int64 `json:"curproc"`
+		} `json:"rlimit"`
+	} `json:"node"`
+}
+
+// NodesReservations reports the NodeReservations of the node with the provided name, or of every node matching the
+// label selector, ranked by the severity of their eviction signals.
+// The kubelet configz and stats summary are retrieved through the API server proxy, nodes whose kubelet can't be
+// reached don't fail the operation, their errors are returned as TargetErrors.
+func (k *Kubernetes) NodesReservations(ctx context.Context, name, labelSelector string, margin float64) ([]NodeReservations, TargetErrors, error) {
+	var nodes []v1.Node
+	if name != "" {
+		node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get node %s: %w", name, err)
+		}
+		nodes = append(nodes, *node)
+	} else {
+		nodeList, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		nodes = nodeList.Items
+	}
+	results, targetErrors := FanOutNodes(ctx, nodes, func(ctx context.Context, node v1.Node) (*NodeReservations, error) {
+		configz, err := k.nodeConfigz(ctx, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := k.nodeStatsSummary(ctx, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		return AnalyzeNodeReservations(&node, configz, summary, margin)
+	})
+	reservations := make([]NodeReservations, 0, len(results))
+	for _, result := range results {
+		reservations = append(reservations, *result.Value)
+	}
+	sort.SliceStable(reservations, func(i, j int) bool {
+		return evictionSignalSeverity[reservations[i].Status] > evictionSignalSeverity[reservations[j].Status]
+	})
+	return reservations, targetErrors, nil
+}
+
+// AnalyzeNodeReservations compares the eviction thresholds of the kubelet configz with the available resources of the
+// kubelet stats summary, a signal is close to its threshold when the available resource is less than margin percent
+// of the capacity above it
+func AnalyzeNodeReservations(node *v1.Node, configz, summary string, margin float64) (*NodeReservations, error) {
+	config := &nodeConfigz{}
+	if err := json.Unmarshal([]byte(configz), config); err != nil {
+		return nil, fmt.Errorf("failed to parse node configz: %w", err)
+	}
+	if config.KubeletConfig == nil {
+		return nil, errors.New("failed to parse node configz: missing kubeletconfig")
+	}
+	stats := &nodeStatsSummaryAvailable{}
+	if err := json.Unmarshal([]byte(summary), stats); err != nil {
+		return nil, fmt.Errorf("failed to parse node stats summary: %w", err)
+	}
+	reservations := &NodeReservations{
+		Node:           node.Name,
+		Status:         EvictionSignalOK,
+		Capacity:       node.Status.Capacity,
+		Allocatable:    node.Status.Allocatable,
+		SystemReserved: config.KubeletConfig.SystemReserved,
+		KubeReserved:   config.KubeletConfig.KubeReserved,
+		Signals:        []NodeEvictionSignal{},
+	}
+	available := stats.availableResources(node)
+	for _, eviction := range []struct {
+		name       string
+		thresholds map[string]string
+	}{{"hard", config.KubeletConfig.EvictionHard}, {"soft", config.KubeletConfig.EvictionSoft}} {
+		signals := make([]string, 0, len(eviction.thresholds))
+		for signal := range eviction.thresholds {
+			signals = append(signals, signal)
+		}
+		sort.Strings(signals)
+		for _, signal := range signals {
+			s := NodeEvictionSignal{Signal: signal, Eviction: eviction.name, Threshold: eviction.thresholds[signal], Status: EvictionSignalUnknown}
+			if r, ok := available[signal]; ok {
+				s.Available, s.Capacity = ptr.To(r.available), ptr.To(r.capacity)
+			}
+			s.evaluate(margin)
+			reservations.Signals = append(reservations.Signals, s)
+			if evictionSignalSeverity[s.Status] > evictionSignalSeverity[reservations.Status] {
+				reservations.Status = s.Status
+			}
+		}
+	}
+	return reservations, nil
+}
+
+// evictionResource is the available amount and the capacity of the resource of an eviction signal
+type evictionResource struct {
+	available int64
+	capacity  int64
+}
+
+// availableResources returns the evictionResource of each eviction signal reported by the stats summary
+func (s *nodeStatsSummaryAvailable) availableResources(node *v1.Node) map[string]evictionResource {
+	available := map[string]evictionResource{}
+	if memory := s.Node.Memory; memory != nil && memory.AvailableBytes != nil {
+		available["memory.available"] = evictionResource{int64(*memory.AvailableBytes), node.Status.Capacity.Memory().Value()}
+	}
+	fs := func(prefix string, stats *nodeFsStats) {
+		if stats == nil {
+			return
+		}
+		if stats.AvailableBytes != nil && stats.CapacityBytes != nil {
+			available[prefix+".available"] = evictionResource{int64(*stats.AvailableBytes), int64(*stats.CapacityBytes)}
+		}
+		if stats.InodesFree != nil && stats.Inodes != nil {
+			available[prefix+".inodesFree"] = evictionResource{int64(*stats.InodesFree), int64(*stats.Inodes)}
+		}
+	}
+	fs("nodefs", s.Node.Fs)
+	if runtime := s.Node.Runtime; runtime != nil {
+		fs("imagefs", runtime.ImageFs)
+		fs("containerfs", runtime.ContainerFs)
+	}
+	if rlimit := s.Node.Rlimit; rlimit != nil && rlimit.MaxPID != nil && rlimit.CurProc != nil {
+		available["pid.available"] = evictionResource{*rlimit.MaxPID - *rlimit.CurProc, *rlimit.MaxPID}
+	}
+	return available
+}
+
+// evaluate resolves the threshold value and compares it with the available resource
+func (s *NodeEvictionSignal) evaluate(margin float64) {
+	if percentage, ok := strings.CutSuffix(s.Threshold, "%"); ok {
+		value, err := strconv.ParseFloat(percentage, 64)
+		if err != nil || s.Capacity == nil {
+			return
+		}
+		s.ThresholdValue = int64(value / 100 * float64(*s.Capacity))
+	} else if quantity, err := resource.ParseQuantity(s.Threshold); err == nil {
+		s.ThresholdValue = quantity.Value()
+	} else {
+		return
+	}
+	if s.Available == nil || s.Capacity == nil || *s.Capacity <= 0 {
+		return
+	}
+	headroom := float64(*s.Available-s.ThresholdValue) / float64(*s.Capacity) * 100
+	s.Headroom = &headroom
+	switch {
+	case *s.Available < s.ThresholdValue:
+		s.Status = EvictionSignalExceeded
+	case headroom < margin:
+		s.Status = EvictionSignalClose
+	default:
+		s.Status = EvictionSignalOK
+	}
+}
@@ -0,0 +1,107 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NodesReservationsSuite struct {
+	suite.Suite
+}
+
+const reservationsTestConfigz = `{"kubeletconfig":{
+	"systemReserved":{"cpu":"500m","memory":"1Gi"},
+	"kubeReserved":{"memory":"512Mi"},
+	"evictionHard":{"memory.available":"100Mi","nodefs.available":"10%","nodefs.inodesFree":"5%","imagefs.available":"15%"},
+	"evictionSoft":{"memory.available":"1Gi"}
+}}`
+
+func reservationsTestNode() *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{
+			Capacity:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")},
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3500m"), v1.ResourceMemory: resource.MustParse("6Gi")},
+		},
+	}
+}
+
+func (s *NodesReservationsSuite) TestAnalyzeNodeReservations() {
+	// 900Mi of memory, 12% of nodefs and 50% of the inodes available, no imagefs stats
+	summary := `{"node":{"memory":{"availableBytes":943718400},"fs":{"availableBytes":12,"capacityBytes":100,"inodesFree":500,"inodes":1000}}}`
+	reservations, err := AnalyzeNodeReservations(reservationsTestNode(), reservationsTestConfigz, summary, DefaultEvictionMargin)
+	s.Require().NoError(err)
+	signals := map[string]NodeEvictionSignal{}
+	for _, signal := range reservations.Signals {
+		signals[signal.Eviction+"/"+signal.Signal] = signal
+	}
+	s.Run("returns the reserved resources", func() {
+		s.Equal(map[string]string{"cpu": "500m", "memory": "1Gi"}, reservations.SystemReserved)
+		s.Equal(map[string]string{"memory": "512Mi"}, reservations.KubeReserved)
+		s.Equal("6Gi", reservations.Allocatable.Memory().String())
+	})
+	s.Run("returns the hard thresholds first", func() {
+		s.Len(reservations.Signals, 5)
+		s.Equal("hard", reservations.Signals[0].Eviction)
+		s.Equal("soft", reservations.Signals[4].Eviction)
+	})
+	s.Run("resolves the percentage thresholds", func() {
+		s.Equal(int64(10), signals["hard/nodefs.available"].ThresholdValue)
+		s.Equal(int64(50), signals["hard/nodefs.inodesFree"].ThresholdValue)
+	})
+	s.Run("resolves the quantity thresholds", func() {
+		s.Equal(int64(100*1024*1024), signals["hard/memory.available"].ThresholdValue)
+	})
+	s.Run("flags the signals close to the threshold", func() {
+		s.Equal(EvictionSignalClose, signals["hard/nodefs.available"].Status)
+		s.InDelta(2.0, *signals["hard/nodefs.available"].Headroom, 0.001)
+	})
+	s.Run("flags the exceeded thresholds", func() {
+		s.Equal(EvictionSignalExceeded, signals["soft/memory.available"].Status)
+		s.Less(*signals["soft/memory.available"].Headroom, 0.0)
+	})
+	s.Run("reports the signals within the thresholds", func() {
+		s.Equal(EvictionSignalOK, signals["hard/memory.available"].Status)
+		s.Equal(EvictionSignalOK, signals["hard/nodefs.inodesFree"].Status)
+	})
+	s.Run("reports the signals without stats as unknown", func() {
+		s.Equal(EvictionSignalUnknown, signals["hard/imagefs.available"].Status)
+		s.Nil(signals["hard/imagefs.available"].Available)
+	})
+	s.Run("reports the most severe status", func() {
+		s.Equal(EvictionSignalExceeded, reservations.Status)
+	})
+}
+
+func (s *NodesReservationsSuite) TestAnalyzeNodeReservationsPIDs() {
+	configz := `{"kubeletconfig":{"evictionHard":{"pid.available":"10%"}}}`
+	summary := `{"node":{"rlimit":{"maxpid":1000,"curproc":850}}}`
+	reservations, err := AnalyzeNodeReservations(reservationsTestNode(), configz, summary, DefaultEvictionMargin)
+	s.Require().NoError(err)
+	s.Require().Len(reservations.Signals, 1)
+	s.Equal(int64(150), *reservations.Signals[0].Available)
+	s.Equal(EvictionSignalOK, reservations.Status)
+}
+
+func (s *NodesReservationsSuite) TestAnalyzeNodeReservationsInvalid() {
+	s.Run("invalid configz", func() {
+		_, err := AnalyzeNodeReservations(reservationsTestNode(), "not json", "{}", DefaultEvictionMargin)
+		s.ErrorContains(err, "failed to parse node configz")
+	})
+	s.Run("configz without kubeletconfig", func() {
+		_, err := AnalyzeNodeReservations(reservationsTestNode(), "{}", "{}", DefaultEvictionMargin)
+		s.EqualError(err, "failed to parse node configz: missing kubeletconfig")
+	})
+	s.Run("invalid stats summary", func() {
+		_, err := AnalyzeNodeReservations(reservationsTestNode(), reservationsTestConfigz, "not json", DefaultEvictionMargin)
+		s.ErrorContains(err, "failed to parse node stats summary")
+	})
+}
+
+func TestNodesReservations(t *testing.T) {
+	suite.Run(t, new(NodesReservationsSuite))
+}
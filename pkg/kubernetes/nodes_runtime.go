@@ -0,0 +1,249 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NodeRuntimeContainers lists the containers of the node runtime, including the exited ones (crictl ps -a)
+	NodeRuntimeContainers = "containers"
+	// NodeRuntimeImages lists the images of the node runtime (crictl images)
+	NodeRuntimeImages = "images"
+	// NodeRuntimeInspect returns the runtime status of a container (crictl inspect)
+	NodeRuntimeInspect = "inspect"
+)
+
+// nodeRuntimeSockets are the CRI sockets probed on the node, in order (containerd, CRI-O, k3s, cri-dockerd)
+var nodeRuntimeSockets = []string{
+	"/run/containerd/containerd.sock",
+	"/run/crio/crio.sock",
+	"/run/k3s/containerd/containerd.sock",
+	"/run/cri-dockerd.sock",
+}
+
+// nodeRuntimeScript detects the CRI socket, prints its endpoint and runs crictl with the positional parameters
+var nodeRuntimeScript = `socket=""
+for s in ` + strings.Join(nodeRuntimeSockets, " ") + `; do if [ -S "$s" ]; then socket="$s"; break; fi; done
+if [ -z "$socket" ]; then echo "no container runtime socket found (` + strings.Join(nodeRuntimeSockets, ", ") + `)" >&2; exit 1; fi
+if ! command -v crictl >/dev/null 2>&1; then echo "crictl is not installed on the node" >&2; exit 1; fi
+echo "unix://$socket"
+exec crictl --runtime-endpoint "unix://$socket" --timeout 10s "$@"`
+
+// runtimeContainerIDPattern matches the container IDs (or ID prefixes) accepted by crictl inspect
+var runtimeContainerIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// NodeRuntime is the state of the container runtime of a node as reported by crictl
+type NodeRuntime struct {
+	Node string `json:"node"`
+	// RuntimeEndpoint is the detected CRI socket, e.g. unix:///run/containerd/containerd.sock
+	RuntimeEndpoint string             `json:"runtimeEndpoint"`
+	Containers      []RuntimeContainer `json:"containers,omitempty"`
+	Images          []RuntimeImage     `json:"images,omitempty"`
+	// Inspect is the crictl inspect output of the requested container
+	Inspect  any      `json:"inspect,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// RuntimeContainer is a container known by the container runtime of a node
+type RuntimeContainer struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Attempt   uint32 `json:"attempt"`
+	State     string `json:"state"`
+	Image     string `json:"image"`
+	ImageRef  string `json:"imageRef,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	// PodNamespace, PodName, and PodUID are read from the io.kubernetes.pod.* labels set by the kubelet
+	PodNamespace string `json:"podNamespace,omitempty"`
+	PodName      string `json:"podName,omitempty"`
+	PodUID       string `json:"podUID,omitempty"`
+	// Orphaned is true when the Pod of the container is not known by the API server (e.g. deleted Pods not cleaned up)
+	Orphaned bool `json:"orphaned,omitempty"`
+}
+
+// RuntimeImage is an image stored by the container runtime of a node
+type RuntimeImage struct {
+	ID          string   `json:"id"`
+	RepoTags    []string `json:"repoTags,omitempty"`
+	RepoDigests []string `json:"repoDigests,omitempty"`
+	SizeBytes   int64    `json:"sizeBytes"`
+	Pinned      bool     `json:"pinned,omitempty"`
+}
+
+// crictlContainers is the crictl ps -o json output
+type crictlContainers struct {
+	Containers []struct {
+		ID       string `json:"id"`
+		Metadata struct {
+			Name    string `json:"name"`
+			Attempt uint32 `json:"attempt"`
+		} `json:"metadata"`
+		Image struct {
+			Image string `json:"image"`
+		} `json:"image"`
+		ImageRef  string            `json:"imageRef"`
+		State     string            `json:"state"`
+		CreatedAt string            `json:"createdAt"`
+		Labels    map[string]string `json:"labels"`
+	} `json:"containers"`
+}
+
+// crictlImages is the crictl images -o json output
+type crictlImages struct {
+	Images []struct {
+		ID          string   `json:"id"`
+		RepoTags    []string `json:"repoTags"`
+		RepoDigests []string `json:"repoDigests"`
+		Size        string   `json:"size"`
+		Pinned      bool     `json:"pinned"`
+	} `json:"images"`
+}
+
+// NodesRuntime runs crictl on the node to list its containers (including the exited ones and the ones whose Pod
+// was deleted), its images, or to inspect a container.
+// The CRI socket is detected on the node and the crictl binary of the node is used.
+func (k *Kubernetes) NodesRuntime(ctx context.Context, nodeName, operation, containerID string) (*NodeRuntime, error) {
+	var args []string
+	switch operation {
+	case NodeRuntimeContainers:
+		args = []string{"ps", "--all", "--output", "json"}
+	case NodeRuntimeImages:
+		args = []string{"images", "--output", "json"}
+	case NodeRuntimeInspect:
+		if !runtimeContainerIDPattern.MatchString(containerID) {
+			return nil, fmt.Errorf("invalid container id %q", containerID)
+		}
+		args = []string{"inspect", "--output", "json", containerID}
+	default:
+		return nil, fmt.Errorf("unsupported operation %s, supported operations are %s, %s and %s",
+			operation, NodeRuntimeContainers, NodeRuntimeImages, NodeRuntimeInspect)
+	}
+	out, err := k.RunNodeShell(ctx, NodeShellOptions{NodeName: nodeName, Script: nodeRuntimeScript, Args: args, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	endpoint, output, _ := strings.Cut(out, "\n")
+	nodeRuntime := &NodeRuntime{Node: nodeName, RuntimeEndpoint: strings.TrimSpace(endpoint)}
+	switch operation {
+	case NodeRuntimeContainers:
+		if nodeRuntime.Containers, err = parseRuntimeContainers(output); err != nil {
+			return nil, err
+		}
+		pods, err := k.AccessControlClientset().CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+		if err != nil {
+			nodeRuntime.Warnings = append(nodeRuntime.Warnings, fmt.Sprintf("failed to list the pods of the node, orphaned containers are not detected: %v", err))
+		} else {
+			MarkOrphanedRuntimeContainers(nodeRuntime.Containers, pods.Items)
+		}
+	case NodeRuntimeImages:
+		if nodeRuntime.Images, err = parseRuntimeImages(output); err != nil {
+			return nil, err
+		}
+	case NodeRuntimeInspect:
+		if err = json.Unmarshal([]byte(output), &nodeRuntime.Inspect); err != nil {
+			return nil, fmt.Errorf("failed to parse crictl inspect output: %w", err)
+		}
+	}
+	return nodeRuntime, nil
+}
+
+// Summary returns a one line description of the node runtime state
+func (r *NodeRuntime) Summary() string {
+	switch {
+	case r.Inspect != nil:
+		return fmt.Sprintf("Container runtime status on node %s (%s)", r.Node, r.RuntimeEndpoint)
+	case r.Images != nil:
+		return fmt.Sprintf("%d images on node %s (%s)", len(r.Images), r.Node, r.RuntimeEndpoint)
+	}
+	states := map[string]int{}
+	orphaned := 0
+	for _, container := range r.Containers {
+		states[strings.ToLower(strings.TrimPrefix(container.State, "CONTAINER_"))]++
+		if container.Orphaned {
+			orphaned++
+		}
+	}
+	var counts []string
+	for _, state := range []string{"running", "exited", "created", "unknown"} {
+		if states[state] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", states[state], state))
+		}
+	}
+	if orphaned > 0 {
+		counts = append(counts, fmt.Sprintf("%d orphaned", orphaned))
+	}
+	summary := fmt.Sprintf("%d containers on node %s (%s)", len(r.Containers), r.Node, r.RuntimeEndpoint)
+	if len(counts) > 0 {
+		summary += ": " + strings.Join(counts, ", ")
+	}
+	return summary
+}
+
+func parseRuntimeContainers(output string) ([]RuntimeContainer, error) {
+	var ps crictlContainers
+	if err := json.Unmarshal([]byte(output), &ps); err != nil {
+		return nil, fmt.Errorf("failed to parse crictl ps output: %w", err)
+	}
+	containers := make([]RuntimeContainer, 0, len(ps.Containers))
+	for _, c := range ps.Containers {
+		container := RuntimeContainer{
+			ID:           c.ID,
+			Name:         c.Metadata.Name,
+			Attempt:      c.Metadata.Attempt,
+			State:        c.State,
+			Image:        c.Image.Image,
+			ImageRef:     c.ImageRef,
+			PodNamespace: c.Labels["io.kubernetes.pod.namespace"],
+			PodName:      c.Labels["io.kubernetes.pod.name"],
+			PodUID:       c.Labels["io.kubernetes.pod.uid"],
+		}
+		if createdAt, err := strconv.ParseInt(c.CreatedAt, 10, 64); err == nil && createdAt > 0 {
+			container.CreatedAt = time.Unix(0, createdAt).UTC().Format(time.RFC3339)
+		}
+		containers = append(containers, container)
+	}
+	sort.SliceStable(containers, func(i, j int) bool {
+		if containers[i].PodNamespace != containers[j].PodNamespace {
+			return containers[i].PodNamespace < containers[j].PodNamespace
+		}
+		if containers[i].PodName != containers[j].PodName {
+			return containers[i].PodName < containers[j].PodName
+		}
+		return containers[i].Name < containers[j].Name
+	})
+	return containers, nil
+}
+
+func parseRuntimeImages(output string) ([]RuntimeImage, error) {
+	var list crictlImages
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse crictl images output: %w", err)
+	}
+	images := make([]RuntimeImage, 0, len(list.Images))
+	for _, i := range list.Images {
+		size, _ := strconv.ParseInt(i.Size, 10, 64)
+		images = append(images, RuntimeImage{ID: i.ID, RepoTags: i.RepoTags, RepoDigests: i.RepoDigests, SizeBytes: size, Pinned: i.Pinned})
+	}
+	return images, nil
+}
+
+// MarkOrphanedRuntimeContainers flags the containers whose Pod is not one of the provided Pods scheduled on the node
+func MarkOrphanedRuntimeContainers(containers []RuntimeContainer, pods []v1.Pod) {
+	uids := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		uids[string(pod.UID)] = true
+	}
+	for i := range containers {
+		containers[i].Orphaned = containers[i].PodUID != "" && !uids[containers[i].PodUID]
+	}
+}
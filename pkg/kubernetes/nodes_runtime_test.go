@@ -0,0 +1,80 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NodesRuntimeSuite struct {
+	suite.Suite
+}
+
+const crictlPsOutput = `{"containers":[
+  {"id":"b2c3","podSandboxId":"s2","metadata":{"name":"app","attempt":3},"image":{"image":"example.com/app:1.0"},"imageRef":"example.com/app@sha256:abc",
+   "state":"CONTAINER_EXITED","createdAt":"1735725600000000000",
+   "labels":{"io.kubernetes.container.name":"app","io.kubernetes.pod.name":"web-1","io.kubernetes.pod.namespace":"shop","io.kubernetes.pod.uid":"uid-deleted"}},
+  {"id":"a1b2","podSandboxId":"s1","metadata":{"name":"dns","attempt":0},"image":{"image":"registry.k8s.io/coredns:v1.11.1"},"imageRef":"sha256:def",
+   "state":"CONTAINER_RUNNING","createdAt":"1735722000000000000",
+   "labels":{"io.kubernetes.pod.name":"coredns-1","io.kubernetes.pod.namespace":"kube-system","io.kubernetes.pod.uid":"uid-coredns"}}
+]}`
+
+func (s *NodesRuntimeSuite) TestParseRuntimeContainers() {
+	containers, err := parseRuntimeContainers(crictlPsOutput)
+	s.Require().NoError(err)
+	s.Run("sorts containers by pod namespace and name", func() {
+		s.Require().Len(containers, 2)
+		s.Equal("coredns-1", containers[0].PodName)
+		s.Equal("web-1", containers[1].PodName)
+	})
+	s.Run("maps crictl fields", func() {
+		s.Equal(RuntimeContainer{
+			ID:           "b2c3",
+			Name:         "app",
+			Attempt:      3,
+			State:        "CONTAINER_EXITED",
+			Image:        "example.com/app:1.0",
+			ImageRef:     "example.com/app@sha256:abc",
+			CreatedAt:    "2025-01-01T10:00:00Z",
+			PodNamespace: "shop",
+			PodName:      "web-1",
+			PodUID:       "uid-deleted",
+		}, containers[1])
+	})
+	s.Run("invalid output", func() {
+		_, err := parseRuntimeContainers("crictl: command not found")
+		s.ErrorContains(err, "failed to parse crictl ps output")
+	})
+}
+
+func (s *NodesRuntimeSuite) TestParseRuntimeImages() {
+	images, err := parseRuntimeImages(`{"images":[{"id":"sha256:abc","repoTags":["example.com/app:1.0"],"repoDigests":[],"size":"52428800","uid":null,"username":"","pinned":true}]}`)
+	s.Require().NoError(err)
+	s.Equal([]RuntimeImage{{ID: "sha256:abc", RepoTags: []string{"example.com/app:1.0"}, RepoDigests: []string{}, SizeBytes: 52428800, Pinned: true}}, images)
+}
+
+func (s *NodesRuntimeSuite) TestMarkOrphanedRuntimeContainers() {
+	containers, err := parseRuntimeContainers(crictlPsOutput)
+	s.Require().NoError(err)
+	containers = append(containers, RuntimeContainer{ID: "c3d4", Name: "static"})
+	MarkOrphanedRuntimeContainers(containers, []v1.Pod{{ObjectMeta: metav1.ObjectMeta{UID: "uid-coredns"}}})
+	s.Run("containers of known pods are not orphaned", func() {
+		s.False(containers[0].Orphaned)
+	})
+	s.Run("containers of unknown pods are orphaned", func() {
+		s.True(containers[1].Orphaned)
+	})
+	s.Run("containers without pod labels are not orphaned", func() {
+		s.False(containers[2].Orphaned)
+	})
+	s.Run("summary counts states and orphans", func() {
+		nodeRuntime := &NodeRuntime{Node: "node-1", RuntimeEndpoint: "unix:///run/crio/crio.sock", Containers: containers}
+		s.Equal("3 containers on node node-1 (unix:///run/crio/crio.sock): 1 running, 1 exited, 1 orphaned", nodeRuntime.Summary())
+	})
+}
+
+func TestNodesRuntime(t *testing.T) {
+	suite.Run(t, new(NodesRuntimeSuite))
+}
@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeShellOptions describes a script run on a node by a privileged helper pod, with the node root filesystem
+// as root directory (chroot), so that the node binaries (journalctl, crictl, ...) and their configuration are used
+type NodeShellOptions struct {
+	// NodeName is the name of the node where the script runs
+	NodeName string
+	// Script is run by the node sh, Args are passed as positional parameters so that they're never interpreted by the shell
+	Script string
+	Args   []string
+	// ReadOnly mounts the node root filesystem read-only (not enforced on Windows nodes)
+	ReadOnly bool
+	// WindowsScript is the PowerShell script run on Windows nodes by a HostProcess helper pod, Args are passed as the
+	// ARG1, ARG2, ... environment variables (Optional, Windows nodes are not supported if empty)
+	WindowsScript string
+}
+
+// RunNodeShell runs the script on the node in a privileged helper pod chrooted into the node root filesystem, or the
+// WindowsScript in a HostProcess helper pod on Windows nodes, and returns its output
+func (k *Kubernetes) RunNodeShell(ctx context.Context, options NodeShellOptions) (string, error) {
+	node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, options.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", options.NodeName, err)
+	}
+	if IsWindowsNode(node) {
+		if options.WindowsScript == "" {
+			return "", fmt.Errorf("node %s is a Windows node, only Linux nodes are supported", options.NodeName)
+		}
+		env := make([]v1.EnvVar, 0, len(options.Args))
+		for i, arg := range options.Args {
+			env = append(env, v1.EnvVar{Name: fmt.Sprintf("ARG%d", i+1), Value: arg})
+		}
+		return k.RunHelperPod(ctx, HelperPodOptions{
+			Role:               HelperImageWindowsHostProcess,
+			NodeName:           options.NodeName,
+			WindowsHostProcess: true,
+			Command:            powerShellCommand(options.WindowsScript),
+			Env:                env,
+		})
+	}
+	return k.RunHelperPod(ctx, HelperPodOptions{
+		Role:             HelperImageBusybox,
+		NodeName:         options.NodeName,
+		HostRoot:         true,
+		ReadOnlyHostRoot: options.ReadOnly,
+		// Required to access the files protected by SELinux or owned by other users
+		Privileged: true,
+		Command:    append([]string{"chroot", HelperPodHostRoot, "sh", "-c", options.Script, "sh"}, options.Args...),
+	})
+}
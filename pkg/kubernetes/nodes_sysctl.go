@@ -0,0 +1,175 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
+)
+
+// SysctlThreshold is the recommended minimum value of a kernel parameter
+type SysctlThreshold struct {
+	Name string
+	Min  int64
+	// Reason explains which workloads are affected when the value is lower than Min
+	Reason string
+}
+
+// DefaultSysctlThresholds are the kernel parameters audited by default with their recommended minimum values
+var DefaultSysctlThresholds = []SysctlThreshold{
+	{Name: "net.netfilter.nf_conntrack_max", Min: 131072,
+		Reason: "nodes with many connections (ingress controllers, service meshes) drop packets when the conntrack table is full"},
+	{Name: "fs.inotify.max_user_watches", Min: 524288,
+		Reason: "file watchers (log collectors, development servers, kubelet) fail with 'no space left on device' when exhausted"},
+	{Name: "fs.inotify.max_user_instances", Min: 512,
+		Reason: "nodes running many pods with file watchers fail with 'too many open files' when exhausted"},
+	{Name: "vm.max_map_count", Min: 262144,
+		Reason: "Elasticsearch, OpenSearch and other mmap-heavy workloads refuse to start or crash"},
+}
+
+var (
+	sysctlBelowThresholdRule = findings.Rule{
+		ID:          "SYSCTL001",
+		Name:        "SysctlBelowRecommended",
+		Description: "Kernel parameter is lower than the recommended value for common workloads",
+		Severity:    findings.SeverityWarning,
+	}
+	sysctlUnavailableRule = findings.Rule{
+		ID:          "SYSCTL002",
+		Name:        "SysctlUnavailable",
+		Description: "Kernel parameter couldn't be read (kernel module not loaded or parameter not supported)",
+		Severity:    findings.SeverityInfo,
+	}
+)
+
+var sysctlNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$`)
+
+// NodeSysctls are the kernel parameter values read from a node, empty if the parameter couldn't be read
+type NodeSysctls struct {
+	Node   string            `json:"node"`
+	Values map[string]string `json:"values"`
+}
+
+// NodesSysctl reads the provided kernel parameters from the node with the provided name, or from every node matching
+// the label selector, by running a helper pod (or a helper DaemonSet for multiple nodes) in the host network namespace
+// of each node.
+// Nodes where the helper pod fails don't fail the operation, their errors are returned as TargetErrors.
+func (k *Kubernetes) NodesSysctl(ctx context.Context, name, labelSelector string, sysctls []string) ([]NodeSysctls, TargetErrors, error) {
+	for _, sysctl := range sysctls {
+		if !sysctlNameRegexp.MatchString(sysctl) {
+			return nil, nil, fmt.Errorf("invalid sysctl name %q", sysctl)
+		}
+	}
+	var nodes []v1.Node
+	if name != "" {
+		node, err := k.AccessControlClientset().CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get node %s: %w", name, err)
+		}
+		nodes = append(nodes, *node)
+	} else {
+		nodeList, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		nodes = nodeList.Items
+	}
+	script := strings.Builder{}
+	for _, sysctl := range sysctls {
+		script.WriteString(fmt.Sprintf("printf '%%s=%%s\\n' %s \"$(cat /proc/sys/%s 2>/dev/null)\"\n",
+			sysctl, strings.ReplaceAll(sysctl, ".", "/")))
+	}
+	options := HelperPodOptions{
+		Role: HelperImageBusybox,
+		// net.* parameters are per network namespace, the node values are only visible from the host network
+		HostNetwork: true,
+		Command:     []string{"sh", "-c", script.String()},
+	}
+	var ret []NodeSysctls
+	var targetErrors TargetErrors
+	if len(nodes) > 1 {
+		// A single helper DaemonSet is faster than a helper pod per node on large clusters
+		outputs, daemonSetErrors, err := k.RunHelperDaemonSet(ctx, options, nodes)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, node := range nodes {
+			if out, ok := outputs[node.Name]; ok {
+				ret = append(ret, NodeSysctls{Node: node.Name, Values: parseSysctlOutput(out)})
+			}
+		}
+		return ret, daemonSetErrors, nil
+	}
+	for _, node := range nodes {
+		options.NodeName = node.Name
+		out, err := k.RunHelperPod(ctx, options)
+		if err != nil {
+			targetErrors.Add("node/"+node.Name, err)
+			continue
+		}
+		ret = append(ret, NodeSysctls{Node: node.Name, Values: parseSysctlOutput(out)})
+	}
+	return ret, targetErrors, nil
+}
+
+func parseSysctlOutput(out string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if key, value, found := strings.Cut(line, "="); found {
+			values[key] = strings.Join(strings.Fields(value), " ")
+		}
+	}
+	return values
+}
+
+// SysctlReport evaluates the kernel parameter values of the nodes against the thresholds.
+// Parameters without a threshold or with non-numeric values are only checked for availability.
+func SysctlReport(nodes []NodeSysctls, thresholds []SysctlThreshold) *findings.Report {
+	report := &findings.Report{Tool: "nodes_sysctl", Findings: []findings.Finding{}}
+	for _, node := range nodes {
+		names := make([]string, 0, len(node.Values))
+		for sysctl := range node.Values {
+			names = append(names, sysctl)
+		}
+		sort.Strings(names)
+		for _, sysctl := range names {
+			resource := findings.Resource{APIVersion: "v1", Kind: "Node", Name: node.Node, Field: sysctl}
+			value := node.Values[sysctl]
+			if value == "" {
+				report.Add(sysctlUnavailableRule, resource, fmt.Sprintf("%s couldn't be read on node %s", sysctl, node.Node), "")
+				continue
+			}
+			threshold := findThreshold(thresholds, sysctl)
+			if threshold == nil {
+				continue
+			}
+			current, err := strconv.ParseInt(strings.Fields(value)[0], 10, 64)
+			if err != nil || current >= threshold.Min {
+				continue
+			}
+			message := fmt.Sprintf("%s is %d on node %s, lower than the recommended %d", sysctl, current, node.Node, threshold.Min)
+			if threshold.Reason != "" {
+				message += ": " + threshold.Reason
+			}
+			report.Add(sysctlBelowThresholdRule, resource, message,
+				fmt.Sprintf("Set %s=%d in the node configuration (e.g. /etc/sysctl.d, MachineConfig or node bootstrap)", sysctl, threshold.Min))
+		}
+	}
+	return report
+}
+
+func findThreshold(thresholds []SysctlThreshold, name string) *SysctlThreshold {
+	for i := range thresholds {
+		if thresholds[i].Name == name {
+			return &thresholds[i]
+		}
+	}
+	return nil
+}
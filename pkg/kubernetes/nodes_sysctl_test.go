@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
+)
+
+type NodesSysctlSuite struct {
+	suite.Suite
+}
+
+func (s *NodesSysctlSuite) TestParseSysctlOutput() {
+	values := parseSysctlOutput("vm.max_map_count=65530\nnet.ipv4.ip_local_port_range=32768\t60999\nnet.netfilter.nf_conntrack_max=\n")
+	s.Equal(map[string]string{
+		"vm.max_map_count":               "65530",
+		"net.ipv4.ip_local_port_range":   "32768 60999",
+		"net.netfilter.nf_conntrack_max": "",
+	}, values)
+}
+
+func (s *NodesSysctlSuite) TestSysctlReport() {
+	report := SysctlReport([]NodeSysctls{
+		{Node: "node-1", Values: map[string]string{
+			"vm.max_map_count":               "65530",
+			"fs.inotify.max_user_watches":    "1048576",
+			"net.netfilter.nf_conntrack_max": "",
+			"net.ipv4.ip_local_port_range":   "32768 60999",
+		}},
+		{Node: "node-2", Values: map[string]string{
+			"vm.max_map_count": "262144",
+		}},
+	}, DefaultSysctlThresholds)
+	s.Run("flags values below threshold", func() {
+		s.Require().Len(report.Findings, 2)
+		s.Equal("SYSCTL001", report.Findings[1].RuleID)
+		s.Equal(findings.SeverityWarning, report.Findings[1].Severity)
+		s.Equal(findings.Resource{APIVersion: "v1", Kind: "Node", Name: "node-1", Field: "vm.max_map_count"}, report.Findings[1].Resource)
+		s.Contains(report.Findings[1].Message, "vm.max_map_count is 65530 on node node-1, lower than the recommended 262144")
+		s.Equal("Set vm.max_map_count=262144 in the node configuration (e.g. /etc/sysctl.d, MachineConfig or node bootstrap)", report.Findings[1].Remediation)
+	})
+	s.Run("reports unavailable parameters", func() {
+		s.Equal("SYSCTL002", report.Findings[0].RuleID)
+		s.Equal("net.netfilter.nf_conntrack_max couldn't be read on node node-1", report.Findings[0].Message)
+	})
+	s.Run("values at or above threshold are not flagged", func() {
+		for _, finding := range report.Findings {
+			s.NotEqual("node-2", finding.Resource.Name)
+			s.NotEqual("fs.inotify.max_user_watches", finding.Resource.Field)
+		}
+	})
+	s.Run("custom thresholds", func() {
+		report := SysctlReport([]NodeSysctls{{Node: "node-1", Values: map[string]string{"net.ipv4.ip_local_port_range": "32768 60999"}}},
+			[]SysctlThreshold{{Name: "net.ipv4.ip_local_port_range", Min: 40000}})
+		s.Require().Len(report.Findings, 1)
+		s.Equal("net.ipv4.ip_local_port_range is 32768 on node node-1, lower than the recommended 40000", report.Findings[0].Message)
+	})
+	s.Run("empty report has no findings", func() {
+		s.NotNil(SysctlReport(nil, DefaultSysctlThresholds).Findings)
+	})
+}
+
+func TestNodesSysctl(t *testing.T) {
+	suite.Run(t, new(NodesSysctlSuite))
+}
@@ -12,6 +12,10 @@ type Openshift interface {
 
 func (m *Manager) IsOpenShift(ctx context.Context) bool {
 	// This method should be fast and not block (it's called at startup)
+	if m.staticConfig.Impersonation {
+		// There's no caller to impersonate at startup, the discovery uses the server credentials
+		return openshift.IsOpenshift(m.accessControlClientset.DiscoveryClient())
+	}
 	k, err := m.Derived(ctx)
 	if err != nil {
 		return false
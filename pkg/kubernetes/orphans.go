@@ -0,0 +1,423 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// OrphanReplicaSets are the ReplicaSets scaled to zero that are not owned by an existing Deployment
+	OrphanReplicaSets = "replicasets"
+	// OrphanClaims are the PersistentVolumeClaims not mounted by any running or pending Pod
+	OrphanClaims = "pvcs"
+	// OrphanCompletedPods are the Succeeded and Failed Pods that finished before the age threshold
+	OrphanCompletedPods = "completed-pods"
+	// OrphanHelpers are the helper pods and DaemonSets left behind by a server that stopped before deleting them
+	OrphanHelpers = "helpers"
+	// OrphanServices are the Services with a selector and no ready endpoint
+	OrphanServices = "services"
+)
+
+// OrphanCategories are the kinds of orphaned resources OrphansReport detects
+var OrphanCategories = []string{OrphanReplicaSets, OrphanClaims, OrphanCompletedPods, OrphanHelpers, OrphanServices}
+
+// OrphansOlderThanDefault is the default age of the completed Pods and helpers reported as orphans
+const OrphansOlderThanDefault = 24 * time.Hour
+
+// orphanHelpersMinAge protects the helpers of the running tool calls (same minimum as helper_cleanup_ttl)
+const orphanHelpersMinAge = 5 * time.Minute
+
+type OrphansOptions struct {
+	// Namespace of the resources, all namespaces if empty
+	Namespace string
+	// Categories of orphans to detect, all the OrphanCategories if empty
+	Categories []string
+	// OlderThan is the minimum age of the completed Pods (since they finished) and helpers (OrphansOlderThanDefault if zero)
+	OlderThan time.Duration
+}
+
+// OrphansReport lists the resources that are likely left behind and can be cleaned up
+type OrphansReport struct {
+	// Summary is a one line description, e.g. "5 orphaned resources found in all namespaces: 2 pvcs, 3 completed-pods"
+	Summary string `json:"summary"`
+	// DryRun is true if the deletions were only validated with server-side dry-run
+	DryRun  bool     `json:"dryRun,omitempty"`
+	Orphans []Orphan `json:"orphans"`
+	// Warnings are the categories that couldn't be checked (e.g. list not allowed)
+	Warnings     []string     `json:"warnings,omitempty"`
+	TargetErrors TargetErrors `json:"-"`
+}
+
+type Orphan struct {
+	Category  string `json:"category"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Reason explains why the resource is considered orphaned
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	// Deleted is true if the resource was deleted by OrphansCleanup
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// OrphansReport finds the orphaned resources of the provided categories.
+// Failures listing the resources of a category don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) OrphansReport(ctx context.Context, options OrphansOptions) (*OrphansReport, error) {
+	categories, err := orphanCategories(options.Categories)
+	if err != nil {
+		return nil, err
+	}
+	olderThan := options.OlderThan
+	if olderThan <= 0 {
+		olderThan = OrphansOlderThanDefault
+	}
+	report := &OrphansReport{Orphans: []Orphan{}}
+	now := time.Now()
+	for _, category := range categories {
+		var orphans []Orphan
+		switch category {
+		case OrphanReplicaSets:
+			orphans, err = k.orphanReplicaSets(ctx, options.Namespace)
+		case OrphanClaims:
+			orphans, err = k.orphanClaims(ctx, options.Namespace)
+		case OrphanCompletedPods:
+			orphans, err = k.orphanCompletedPods(ctx, options.Namespace, now.Add(-olderThan))
+		case OrphanHelpers:
+			orphans, err = k.orphanHelpers(ctx, options.Namespace, now.Add(-max(olderThan, orphanHelpersMinAge)))
+		case OrphanServices:
+			orphans, err = k.orphanServices(ctx, options.Namespace)
+		}
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("unable to check the %s: %v", category, err))
+			continue
+		}
+		report.Orphans = append(report.Orphans, orphans...)
+	}
+	sortOrphans(report.Orphans)
+	report.Summary = report.summary(options.Namespace, "found")
+	return report, nil
+}
+
+// OrphansCleanup deletes the orphaned resources of the provided categories found by OrphansReport.
+// The failures of the deletions are reported as TargetErrors, e.g. "3 of 5 orphaned resources deleted in all namespaces: 2 pvcs, 3 completed-pods".
+func (k *Kubernetes) OrphansCleanup(ctx context.Context, options OrphansOptions) (*OrphansReport, error) {
+	if len(options.Categories) == 0 {
+		return nil, fmt.Errorf("the categories to clean up are required, valid categories are: %s", strings.Join(OrphanCategories, ", "))
+	}
+	report, err := k.OrphansReport(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = IsDryRun(ctx)
+	for i := range report.Orphans {
+		orphan := &report.Orphans[i]
+		if err = k.preflight(ctx, orphan.Namespace, orphan.Name, orphanDeletePermission(orphan)); err == nil {
+			err = k.deleteOrphan(ctx, orphan)
+		}
+		if err != nil {
+			report.TargetErrors.Add(strings.ToLower(orphan.Kind)+"/"+orphan.Namespace+"/"+orphan.Name, err)
+			continue
+		}
+		orphan.Deleted = true
+	}
+	verb := "deleted"
+	if report.DryRun {
+		verb = "would be deleted (server-side dry-run)"
+	}
+	report.Summary = fmt.Sprintf("%d of %s", len(report.Orphans)-len(report.TargetErrors), report.summary(options.Namespace, verb))
+	return report, nil
+}
+
+func (k *Kubernetes) orphanReplicaSets(ctx context.Context, namespace string) ([]Orphan, error) {
+	replicaSets, err := k.AccessControlClientset().AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := k.AccessControlClientset().AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return OrphanedReplicaSets(replicaSets.Items, deployments.Items), nil
+}
+
+func (k *Kubernetes) orphanClaims(ctx context.Context, namespace string) ([]Orphan, error) {
+	claims, err := k.AccessControlClientset().CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return OrphanedClaims(claims.Items, pods.Items), nil
+}
+
+func (k *Kubernetes) orphanCompletedPods(ctx context.Context, namespace string, cutoff time.Time) ([]Orphan, error) {
+	pods, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return OrphanedCompletedPods(pods.Items, cutoff), nil
+}
+
+func (k *Kubernetes) orphanHelpers(ctx context.Context, namespace string, cutoff time.Time) ([]Orphan, error) {
+	daemonSets, err := k.AccessControlClientset().AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: helperLabelSelector})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: helperLabelSelector})
+	if err != nil {
+		return nil, err
+	}
+	return OrphanedHelpers(pods.Items, daemonSets.Items, cutoff), nil
+}
+
+func (k *Kubernetes) orphanServices(ctx context.Context, namespace string) ([]Orphan, error) {
+	services, err := k.AccessControlClientset().CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	endpointSlices, err := k.AccessControlClientset().DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return OrphanedServices(services.Items, endpointSlices.Items), nil
+}
+
+// OrphanedReplicaSets returns the ReplicaSets scaled to zero whose controller is not an existing Deployment.
+// The ReplicaSets of the revision history of a Deployment are scaled to zero too, they're not orphans.
+func OrphanedReplicaSets(replicaSets []appsv1.ReplicaSet, deployments []appsv1.Deployment) []Orphan {
+	deploymentUIDs := map[types.UID]bool{}
+	for _, deployment := range deployments {
+		deploymentUIDs[deployment.UID] = true
+	}
+	var orphans []Orphan
+	for _, rs := range replicaSets {
+		if ptr.Deref(rs.Spec.Replicas, 1) != 0 || rs.Status.Replicas != 0 {
+			continue
+		}
+		reason := "scaled to zero and not owned by a Deployment"
+		if owner := metav1.GetControllerOf(&rs); owner != nil {
+			if owner.Kind != "Deployment" || deploymentUIDs[owner.UID] {
+				continue
+			}
+			reason = fmt.Sprintf("scaled to zero and its Deployment %s no longer exists", owner.Name)
+		}
+		orphans = append(orphans, newOrphan(OrphanReplicaSets, "ReplicaSet", &rs.ObjectMeta, reason))
+	}
+	return orphans
+}
+
+// OrphanedClaims returns the PersistentVolumeClaims not mounted by any Pod that is not completed
+func OrphanedClaims(claims []v1.PersistentVolumeClaim, pods []v1.Pod) []Orphan {
+	var active []v1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+			active = append(active, pod)
+		}
+	}
+	var orphans []Orphan
+	for i := range claims {
+		claim := &claims[i]
+		if claim.DeletionTimestamp != nil || len(storageClaimPods(claim, active)) > 0 {
+			continue
+		}
+		reason := "not mounted by any Pod"
+		if claim.Status.Phase != v1.ClaimBound {
+			reason += fmt.Sprintf(" (phase %s)", claim.Status.Phase)
+		} else if capacity, ok := claim.Status.Capacity[v1.ResourceStorage]; ok {
+			reason += fmt.Sprintf(" (%s bound to %s)", capacity.String(), claim.Spec.VolumeName)
+		}
+		orphans = append(orphans, newOrphan(OrphanClaims, "PersistentVolumeClaim", &claim.ObjectMeta, reason))
+	}
+	return orphans
+}
+
+// OrphanedCompletedPods returns the Succeeded and Failed Pods that finished before the cutoff, except the helpers
+func OrphanedCompletedPods(pods []v1.Pod, cutoff time.Time) []Orphan {
+	helpers, _ := labels.Parse(helperLabelSelector)
+	var orphans []Orphan
+	for i := range pods {
+		pod := &pods[i]
+		if (pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed) || helpers.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		finishedAt := podFinishedAt(pod)
+		if finishedAt.After(cutoff) {
+			continue
+		}
+		reason := fmt.Sprintf("%s, finished at %s", pod.Status.Phase, finishedAt.UTC().Format(time.RFC3339))
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			reason += fmt.Sprintf(", owned by %s %s", owner.Kind, owner.Name)
+		}
+		orphans = append(orphans, newOrphan(OrphanCompletedPods, "Pod", &pod.ObjectMeta, reason))
+	}
+	return orphans
+}
+
+// OrphanedHelpers returns the helper pods and DaemonSets created before the cutoff, the pods of the helper DaemonSets
+// are deleted with their DaemonSet (see CleanupHelpers)
+func OrphanedHelpers(pods []v1.Pod, daemonSets []appsv1.DaemonSet, cutoff time.Time) []Orphan {
+	var orphans []Orphan
+	for i := range daemonSets {
+		if daemonSets[i].CreationTimestamp.Time.Before(cutoff) {
+			orphans = append(orphans, newOrphan(OrphanHelpers, "DaemonSet", &daemonSets[i].ObjectMeta, "helper DaemonSet left behind by a previous tool call"))
+		}
+	}
+	for i := range pods {
+		if pods[i].CreationTimestamp.Time.Before(cutoff) && metav1.GetControllerOf(&pods[i]) == nil {
+			orphans = append(orphans, newOrphan(OrphanHelpers, "Pod", &pods[i].ObjectMeta, "helper pod left behind by a previous tool call"))
+		}
+	}
+	return orphans
+}
+
+// OrphanedServices returns the Services with a selector that have no ready endpoint in their EndpointSlices.
+// The Services without selector (endpoints managed manually or by another controller) and ExternalName Services are ignored.
+func OrphanedServices(services []v1.Service, endpointSlices []discoveryv1.EndpointSlice) []Orphan {
+	ready := map[string]bool{}
+	for _, slice := range endpointSlices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready[slice.Namespace+"/"+slice.Labels[discoveryv1.LabelServiceName]] = true
+			}
+		}
+	}
+	var orphans []Orphan
+	for i := range services {
+		service := &services[i]
+		if service.Spec.Type == v1.ServiceTypeExternalName || len(service.Spec.Selector) == 0 || ready[service.Namespace+"/"+service.Name] {
+			continue
+		}
+		reason := fmt.Sprintf("no ready endpoint, the selector %s matches no ready Pod", labels.SelectorFromSet(service.Spec.Selector))
+		orphans = append(orphans, newOrphan(OrphanServices, "Service", &service.ObjectMeta, reason))
+	}
+	return orphans
+}
+
+// podFinishedAt returns the time the last container of the Pod terminated, the start or creation time if unknown
+func podFinishedAt(pod *v1.Pod) time.Time {
+	var finishedAt time.Time
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if status.State.Terminated != nil && status.State.Terminated.FinishedAt.After(finishedAt) {
+			finishedAt = status.State.Terminated.FinishedAt.Time
+		}
+	}
+	if finishedAt.IsZero() && pod.Status.StartTime != nil {
+		finishedAt = pod.Status.StartTime.Time
+	}
+	if finishedAt.IsZero() {
+		finishedAt = pod.CreationTimestamp.Time
+	}
+	return finishedAt
+}
+
+func (k *Kubernetes) deleteOrphan(ctx context.Context, orphan *Orphan) error {
+	deleteOptions := metav1.DeleteOptions{DryRun: dryRun(ctx), PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)}
+	if orphan.Category == OrphanHelpers {
+		deleteOptions.GracePeriodSeconds = ptr.To(int64(0))
+	}
+	clientset := k.AccessControlClientset()
+	switch orphan.Kind {
+	case "ReplicaSet":
+		return clientset.AppsV1().ReplicaSets(orphan.Namespace).Delete(ctx, orphan.Name, deleteOptions)
+	case "DaemonSet":
+		return clientset.AppsV1().DaemonSets(orphan.Namespace).Delete(ctx, orphan.Name, deleteOptions)
+	case "PersistentVolumeClaim":
+		return clientset.CoreV1().PersistentVolumeClaims(orphan.Namespace).Delete(ctx, orphan.Name, deleteOptions)
+	case "Pod":
+		return clientset.CoreV1().Pods(orphan.Namespace).Delete(ctx, orphan.Name, deleteOptions)
+	case "Service":
+		return clientset.CoreV1().Services(orphan.Namespace).Delete(ctx, orphan.Name, deleteOptions)
+	}
+	return fmt.Errorf("unsupported kind %s", orphan.Kind)
+}
+
+func orphanDeletePermission(orphan *Orphan) ResourcePermission {
+	switch orphan.Kind {
+	case "ReplicaSet":
+		return ResourcePermission{Verb: "delete", Group: "apps", Resource: "replicasets"}
+	case "DaemonSet":
+		return ResourcePermission{Verb: "delete", Group: "apps", Resource: "daemonsets"}
+	case "PersistentVolumeClaim":
+		return ResourcePermission{Verb: "delete", Resource: "persistentvolumeclaims"}
+	case "Service":
+		return ResourcePermission{Verb: "delete", Resource: "services"}
+	}
+	return ResourcePermission{Verb: "delete", Resource: "pods"}
+}
+
+// orphanCategories validates the categories, all the OrphanCategories if empty
+func orphanCategories(categories []string) ([]string, error) {
+	if len(categories) == 0 {
+		return OrphanCategories, nil
+	}
+	for _, category := range categories {
+		if !slices.Contains(OrphanCategories, category) {
+			return nil, fmt.Errorf("invalid category %q, valid categories are: %s", category, strings.Join(OrphanCategories, ", "))
+		}
+	}
+	return categories, nil
+}
+
+func newOrphan(category, kind string, meta *metav1.ObjectMeta, reason string) Orphan {
+	return Orphan{
+		Category:  category,
+		Kind:      kind,
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		Reason:    reason,
+		CreatedAt: meta.CreationTimestamp.UTC().Format(time.RFC3339),
+	}
+}
+
+// sortOrphans sorts the orphans by category (OrphanCategories order), namespace, kind, and name
+func sortOrphans(orphans []Orphan) {
+	sort.SliceStable(orphans, func(i, j int) bool {
+		a, b := orphans[i], orphans[j]
+		if a.Category != b.Category {
+			return slices.Index(OrphanCategories, a.Category) < slices.Index(OrphanCategories, b.Category)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+func (r *OrphansReport) summary(namespace, verb string) string {
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = "namespace " + namespace
+	}
+	counts := map[string]int{}
+	for _, orphan := range r.Orphans {
+		counts[orphan.Category]++
+	}
+	var details []string
+	for _, category := range OrphanCategories {
+		if counts[category] > 0 {
+			details = append(details, fmt.Sprintf("%d %s", counts[category], category))
+		}
+	}
+	summary := fmt.Sprintf("%d orphaned resources %s in %s", len(r.Orphans), verb, scope)
+	if len(details) > 0 {
+		summary += ": " + strings.Join(details, ", ")
+	}
+	return summary
+}
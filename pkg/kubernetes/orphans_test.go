@@ -0,0 +1,170 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+type OrphansSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (s *OrphansSuite) SetupTest() {
+	s.now = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *OrphansSuite) objectMeta(name string, age time.Duration) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: "shop", Name: name, CreationTimestamp: metav1.NewTime(s.now.Add(-age))}
+}
+
+func controller(kind, name string, uid types.UID) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{Kind: kind, Name: name, UID: uid, Controller: ptr.To(true)}}
+}
+
+func orphanNames(orphans []Orphan) []string {
+	names := make([]string, 0, len(orphans))
+	for _, orphan := range orphans {
+		names = append(names, orphan.Kind+"/"+orphan.Name)
+	}
+	return names
+}
+
+func (s *OrphansSuite) TestOrphanedReplicaSets() {
+	replicaSet := func(name string, replicas int32, owner []metav1.OwnerReference) appsv1.ReplicaSet {
+		meta := s.objectMeta(name, time.Hour)
+		meta.OwnerReferences = owner
+		return appsv1.ReplicaSet{ObjectMeta: meta, Spec: appsv1.ReplicaSetSpec{Replicas: ptr.To(replicas)}}
+	}
+	deployments := []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web", UID: "web-uid"}}}
+	orphans := OrphanedReplicaSets([]appsv1.ReplicaSet{
+		replicaSet("web-old", 0, controller("Deployment", "web", "web-uid")),
+		replicaSet("api-old", 0, controller("Deployment", "api", "api-uid")),
+		replicaSet("standalone", 0, nil),
+		replicaSet("standalone-running", 2, nil),
+		replicaSet("rollout", 0, controller("Rollout", "canary", "rollout-uid")),
+	}, deployments)
+	s.Equal([]string{"ReplicaSet/api-old", "ReplicaSet/standalone"}, orphanNames(orphans))
+	s.Equal("scaled to zero and its Deployment api no longer exists", orphans[0].Reason)
+	s.Equal("scaled to zero and not owned by a Deployment", orphans[1].Reason)
+	s.Equal(OrphanReplicaSets, orphans[0].Category)
+	s.Equal("2026-01-10T11:00:00Z", orphans[0].CreatedAt)
+}
+
+func (s *OrphansSuite) TestOrphanedClaims() {
+	claim := func(name string, phase v1.PersistentVolumeClaimPhase) v1.PersistentVolumeClaim {
+		c := v1.PersistentVolumeClaim{ObjectMeta: s.objectMeta(name, time.Hour), Status: v1.PersistentVolumeClaimStatus{Phase: phase}}
+		if phase == v1.ClaimBound {
+			c.Spec.VolumeName = "pv-" + name
+			c.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}
+		}
+		return c
+	}
+	pod := func(name, claimName string, phase v1.PodPhase) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: s.objectMeta(name, time.Hour),
+			Spec: v1.PodSpec{Volumes: []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+			}}}},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+	orphans := OrphanedClaims(
+		[]v1.PersistentVolumeClaim{claim("used", v1.ClaimBound), claim("unused", v1.ClaimBound), claim("completed", v1.ClaimBound), claim("pending", v1.ClaimPending)},
+		[]v1.Pod{pod("db-0", "used", v1.PodRunning), pod("backup", "completed", v1.PodSucceeded)},
+	)
+	s.Equal([]string{"PersistentVolumeClaim/unused", "PersistentVolumeClaim/completed", "PersistentVolumeClaim/pending"}, orphanNames(orphans))
+	s.Equal("not mounted by any Pod (10Gi bound to pv-unused)", orphans[0].Reason)
+	s.Equal("not mounted by any Pod (phase Pending)", orphans[2].Reason)
+}
+
+func (s *OrphansSuite) TestOrphanedCompletedPods() {
+	pod := func(name string, phase v1.PodPhase, finished time.Duration) v1.Pod {
+		p := v1.Pod{ObjectMeta: s.objectMeta(name, 48*time.Hour), Status: v1.PodStatus{Phase: phase}}
+		p.Status.ContainerStatuses = []v1.ContainerStatus{{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+			FinishedAt: metav1.NewTime(s.now.Add(-finished)),
+		}}}}
+		return p
+	}
+	job := pod("job-old", v1.PodSucceeded, 30*time.Hour)
+	job.OwnerReferences = controller("Job", "backup", "job-uid")
+	helper := pod("node-files-abc", v1.PodSucceeded, 30*time.Hour)
+	helper.Labels = map[string]string{AppKubernetesManagedBy: version.BinaryName, AppKubernetesPartOf: version.BinaryName + "-helper"}
+	running := pod("running", v1.PodRunning, 30*time.Hour)
+	running.Status.ContainerStatuses = nil
+	orphans := OrphanedCompletedPods([]v1.Pod{
+		job,
+		pod("failed-old", v1.PodFailed, 25*time.Hour),
+		pod("succeeded-recent", v1.PodSucceeded, time.Hour),
+		helper,
+		running,
+	}, s.now.Add(-24*time.Hour))
+	s.Equal([]string{"Pod/job-old", "Pod/failed-old"}, orphanNames(orphans))
+	s.Equal("Succeeded, finished at 2026-01-09T06:00:00Z, owned by Job backup", orphans[0].Reason)
+	s.Equal("Failed, finished at 2026-01-09T11:00:00Z", orphans[1].Reason)
+}
+
+func (s *OrphansSuite) TestOrphanedHelpers() {
+	daemonSetPod := v1.Pod{ObjectMeta: s.objectMeta("node-sysctl-ds-x", 2*time.Hour)}
+	daemonSetPod.OwnerReferences = controller("DaemonSet", "node-sysctl-ds", "ds-uid")
+	orphans := OrphanedHelpers(
+		[]v1.Pod{{ObjectMeta: s.objectMeta("node-files-old", 2*time.Hour)}, {ObjectMeta: s.objectMeta("node-files-running", time.Minute)}, daemonSetPod},
+		[]appsv1.DaemonSet{{ObjectMeta: s.objectMeta("node-sysctl-ds", 2*time.Hour)}},
+		s.now.Add(-time.Hour),
+	)
+	s.Equal([]string{"DaemonSet/node-sysctl-ds", "Pod/node-files-old"}, orphanNames(orphans))
+}
+
+func (s *OrphansSuite) TestOrphanedServices() {
+	service := func(name string, selector map[string]string, serviceType v1.ServiceType) v1.Service {
+		return v1.Service{ObjectMeta: s.objectMeta(name, time.Hour), Spec: v1.ServiceSpec{Selector: selector, Type: serviceType}}
+	}
+	slice := func(service string, ready bool) discoveryv1.EndpointSlice {
+		return discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: service + "-abc", Labels: map[string]string{discoveryv1.LabelServiceName: service}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)}}},
+		}
+	}
+	orphans := OrphanedServices([]v1.Service{
+		service("web", map[string]string{"app": "web"}, v1.ServiceTypeClusterIP),
+		service("api", map[string]string{"app": "api"}, v1.ServiceTypeClusterIP),
+		service("legacy", map[string]string{"app": "legacy"}, v1.ServiceTypeClusterIP),
+		service("manual", nil, v1.ServiceTypeClusterIP),
+		service("external", nil, v1.ServiceTypeExternalName),
+	}, []discoveryv1.EndpointSlice{slice("web", true), slice("api", false)})
+	s.Equal([]string{"Service/api", "Service/legacy"}, orphanNames(orphans))
+	s.Equal("no ready endpoint, the selector app=api matches no ready Pod", orphans[0].Reason)
+}
+
+func (s *OrphansSuite) TestOrphanCategories() {
+	s.Run("defaults to all categories", func() {
+		categories, err := orphanCategories(nil)
+		s.NoError(err)
+		s.Equal(OrphanCategories, categories)
+	})
+	s.Run("rejects unknown categories", func() {
+		_, err := orphanCategories([]string{"pvcs", "configmaps"})
+		s.EqualError(err, `invalid category "configmaps", valid categories are: replicasets, pvcs, completed-pods, helpers, services`)
+	})
+}
+
+func (s *OrphansSuite) TestSummary() {
+	report := &OrphansReport{Orphans: []Orphan{{Category: OrphanCompletedPods}, {Category: OrphanClaims}, {Category: OrphanCompletedPods}}}
+	s.Equal("3 orphaned resources found in namespace shop: 1 pvcs, 2 completed-pods", report.summary("shop", "found"))
+	s.Equal("0 orphaned resources found in all namespaces", (&OrphansReport{}).summary("", "found"))
+}
+
+func TestOrphans(t *testing.T) {
+	suite.Run(t, new(OrphansSuite))
+}
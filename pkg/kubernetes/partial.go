@@ -0,0 +1,34 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TargetError describes the failure of an operation that spans multiple targets (nodes, namespaces, clusters)
+// for one of those targets.
+// Operations that fan out should collect these errors and return the data retrieved from the remaining
+// targets instead of failing as a whole when a single target is unavailable.
+type TargetError struct {
+	// Target identifies the failed target, e.g. "node/worker-1", "namespace/default", or "cluster/prod"
+	Target string `json:"target"`
+	// Error is the reason why the operation failed for the target
+	Error string `json:"error"`
+}
+
+// TargetErrors is the list of per-target errors of an operation that spans multiple targets.
+type TargetErrors []TargetError
+
+// Add records the failure of the operation for the provided target.
+func (e *TargetErrors) Add(target string, err error) {
+	*e = append(*e, TargetError{Target: target, Error: err.Error()})
+}
+
+// Error implements the error interface so that TargetErrors can be returned when all targets failed.
+func (e TargetErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, te := range e {
+		messages = append(messages, fmt.Sprintf("%s: %s", te.Target, te.Error))
+	}
+	return strings.Join(messages, "; ")
+}
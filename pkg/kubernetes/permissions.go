@@ -0,0 +1,104 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// ResourcePermission is a permission on a Kubernetes API resource, as checked by a SelfSubjectAccessReview
+type ResourcePermission struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	// ClusterWide is set for the cluster-scoped resources (e.g. nodes) and the requests across all namespaces,
+	// they are checked regardless of the namespace
+	ClusterWide bool `json:"clusterWide,omitempty"`
+}
+
+// String returns the permission in the kubectl auth can-i form, e.g. "create pods/exec" or "list nodes.metrics.k8s.io"
+func (p ResourcePermission) String() string {
+	resource := p.Resource
+	if p.Group != "" {
+		resource += "." + p.Group
+	}
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	return p.Verb + " " + resource
+}
+
+// CanI reviews the permission for the current user with a SelfSubjectAccessReview in the provided namespace
+// (all namespaces if empty) on the object with the provided name (all objects if empty, the RBAC rules restricted
+// to resourceNames only allow the named objects), the reason of the authorizer is returned along with the decision
+func (k *Kubernetes) CanI(ctx context.Context, permission ResourcePermission, namespace, name string) (bool, string, error) {
+	if permission.ClusterWide {
+		namespace = ""
+	}
+	response, err := k.AccessControlClientset().AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &authv1.ResourceAttributes{
+			Namespace:   namespace,
+			Verb:        permission.Verb,
+			Group:       permission.Group,
+			Resource:    permission.Resource,
+			Subresource: permission.Subresource,
+			Name:        name,
+		}},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", err
+	}
+	return response.Status.Allowed, response.Status.Reason, nil
+}
+
+// PermissionDeniedError is returned when a SelfSubjectAccessReview denies an operation before it's performed
+type PermissionDeniedError struct {
+	Permission ResourcePermission
+	Namespace  string
+	// Reason is the reason of the authorizer, if any
+	Reason string
+}
+
+func (e *PermissionDeniedError) Error() string {
+	scope := "cluster-wide"
+	if e.Namespace != "" {
+		scope = "in namespace " + e.Namespace
+	}
+	message := fmt.Sprintf("you lack permission to %s %s", e.Permission, scope)
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+// preflight reviews the permissions of a mutating operation before it's performed so that a denied operation fails
+// with a PermissionDeniedError instead of a late rejection (e.g. buried in the failure of a helper pod).
+// Every permission is reviewed on the object with the provided name (empty for the collection), a permission is only
+// skipped if its review itself fails (e.g. SelfSubjectAccessReviews not allowed), the API server remains the authority.
+func (k *Kubernetes) preflight(ctx context.Context, namespace, name string, permissions ...ResourcePermission) error {
+	for _, permission := range permissions {
+		allowed, reason, err := k.CanI(ctx, permission, namespace, name)
+		if err != nil {
+			klog.V(2).Infof("skipping the %s pre-flight permission check: %v", permission, err)
+			continue
+		}
+		if !allowed {
+			deniedError := &PermissionDeniedError{Permission: permission, Namespace: namespace, Reason: reason}
+			if permission.ClusterWide {
+				deniedError.Namespace = ""
+			}
+			return deniedError
+		}
+	}
+	return nil
+}
+
+// resourcePermission returns the permission to perform the verb on the resource
+func resourcePermission(verb string, gvr *schema.GroupVersionResource, namespaced bool) ResourcePermission {
+	return ResourcePermission{Verb: verb, Group: gvr.Group, Resource: gvr.Resource, ClusterWide: !namespaced}
+}
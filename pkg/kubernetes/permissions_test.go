@@ -0,0 +1,115 @@
+package kubernetes
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type PermissionsSuite struct {
+	suite.Suite
+	mockServer    *test.MockServer
+	accessReviews *test.AccessReviewHandler
+}
+
+func (s *PermissionsSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+}
+
+func (s *PermissionsSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+// derived serves the SelfSubjectAccessReviews denied by the provided function, the reviews of the failing verb
+// fail with an internal error
+func (s *PermissionsSuite) derived(denied func(attributes *authorizationv1.ResourceAttributes) bool, failingVerb string) *Kubernetes {
+	s.accessReviews = test.NewAccessReviewHandler(denied)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.EscapedPath() == "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews" {
+			body, _ := io.ReadAll(req.Body)
+			if failingVerb != "" && strings.Contains(string(body), `"verb":"`+failingVerb+`"`) {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		s.accessReviews.ServeHTTP(w, req)
+	}))
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *PermissionsSuite) TestCanI() {
+	// RBAC rules restricted with resourceNames only allow the named objects
+	k := s.derived(func(attributes *authorizationv1.ResourceAttributes) bool {
+		return attributes.Name != "pod-1"
+	}, "")
+	s.Run("reviews the permission on the named object", func() {
+		allowed, _, err := k.CanI(s.T().Context(), ResourcePermission{Verb: "delete", Resource: "pods"}, "default", "pod-1")
+		s.Require().NoError(err)
+		s.True(allowed)
+		reviews := s.accessReviews.Reviews()
+		s.Require().Len(reviews, 1)
+		s.Equal("pod-1", reviews[0].Name)
+		s.Equal("default", reviews[0].Namespace)
+	})
+	s.Run("reviews the permission on all the objects without name", func() {
+		allowed, _, err := k.CanI(s.T().Context(), ResourcePermission{Verb: "delete", Resource: "pods"}, "default", "")
+		s.Require().NoError(err)
+		s.False(allowed)
+	})
+}
+
+func (s *PermissionsSuite) TestPreflight() {
+	k := s.derived(func(attributes *authorizationv1.ResourceAttributes) bool {
+		return attributes.Verb == "delete"
+	}, "")
+	err := k.preflight(s.T().Context(), "default", "pod-1",
+		ResourcePermission{Verb: "get", Resource: "pods"},
+		ResourcePermission{Verb: "delete", Resource: "pods"})
+	s.Run("returns a permission denied error", func() {
+		var deniedError *PermissionDeniedError
+		s.Require().ErrorAs(err, &deniedError)
+		s.EqualError(err, "you lack permission to delete pods in namespace default")
+	})
+	s.Run("reviews every permission on the named object", func() {
+		reviews := s.accessReviews.Reviews()
+		s.Require().Len(reviews, 2)
+		s.Equal("pod-1", reviews[0].Name)
+		s.Equal("pod-1", reviews[1].Name)
+	})
+}
+
+func (s *PermissionsSuite) TestPreflightFailingReview() {
+	k := s.derived(func(attributes *authorizationv1.ResourceAttributes) bool {
+		return attributes.Verb == "delete"
+	}, "get")
+	s.Run("skips the permission whose review fails and reviews the remaining ones", func() {
+		err := k.preflight(s.T().Context(), "default", "pod-1",
+			ResourcePermission{Verb: "get", Resource: "pods"},
+			ResourcePermission{Verb: "delete", Resource: "pods"})
+		s.EqualError(err, "you lack permission to delete pods in namespace default")
+	})
+	s.Run("allows the operation when the reviews fail", func() {
+		s.NoError(k.preflight(s.T().Context(), "default", "pod-1", ResourcePermission{Verb: "get", Resource: "pods"}))
+	})
+}
+
+func TestPermissions(t *testing.T) {
+	suite.Run(t, new(PermissionsSuite))
+}
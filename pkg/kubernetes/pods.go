@@ -5,8 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	labelutil "k8s.io/apimachinery/pkg/labels"
@@ -51,13 +54,51 @@ func (k *Kubernetes) PodsGet(ctx context.Context, namespace, name string) (*unst
 	}, k.NamespaceOrDefault(namespace), name)
 }
 
-func (k *Kubernetes) PodsDelete(ctx context.Context, namespace, name string) (string, error) {
+// PodsDeleteOptions are the options of the PodsDelete function
+type PodsDeleteOptions struct {
+	// Evict uses the Eviction API instead of deleting the Pod, the eviction is rejected by the API server if it
+	// would violate a PodDisruptionBudget
+	Evict bool
+	// GracePeriodSeconds overrides the terminationGracePeriodSeconds of the Pod (0 deletes the Pod immediately)
+	GracePeriodSeconds *int64
+}
+
+// PodsDelete deletes (or evicts) the Pod with the provided name, along with the Service and Route created for it by
+// the pods_run tool.
+// Evictions blocked by a PodDisruptionBudget return a PodDisruptionBudgetError describing the blocking budgets.
+func (k *Kubernetes) PodsDelete(ctx context.Context, namespace, name string, options PodsDeleteOptions) (string, error) {
 	namespace = k.NamespaceOrDefault(namespace)
 	pod, err := k.ResourcesGet(ctx, &schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}, namespace, name)
 	if err != nil {
 		return "", err
 	}
 
+	permission := ResourcePermission{Verb: "delete", Resource: "pods"}
+	if options.Evict {
+		permission = ResourcePermission{Verb: "create", Resource: "pods", Subresource: "eviction"}
+	}
+	if err = k.preflight(ctx, namespace, name, permission); err != nil {
+		return "", err
+	}
+
+	deleteOptions := metav1.DeleteOptions{DryRun: dryRun(ctx), GracePeriodSeconds: options.GracePeriodSeconds}
+	ret := "Pod deleted successfully"
+	if options.Evict {
+		ret = "Pod evicted successfully"
+		err = k.AccessControlClientset().CoreV1().Pods(namespace).EvictV1(ctx, &policyv1.Eviction{
+			ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: namespace},
+			DeleteOptions: &deleteOptions,
+		})
+		if err != nil && (apierrors.IsTooManyRequests(err) || apierrors.IsInternalError(err)) {
+			err = k.podDisruptionBudgetError(ctx, pod, err)
+		}
+	} else {
+		err = k.AccessControlClientset().CoreV1().Pods(namespace).Delete(ctx, name, deleteOptions)
+	}
+	if err != nil {
+		return "", err
+	}
+
 	isManaged := pod.GetLabels()[AppKubernetesManagedBy] == version.BinaryName
 	managedLabelSelector := labelutil.Set{
 		AppKubernetesManagedBy: version.BinaryName,
@@ -71,7 +112,7 @@ func (k *Kubernetes) PodsDelete(ctx context.Context, namespace, name string) (st
 			LabelSelector: managedLabelSelector.String(),
 		}); sl != nil {
 			for _, svc := range sl.Items {
-				_ = services.Delete(ctx, svc.Name, metav1.DeleteOptions{})
+				_ = services.Delete(ctx, svc.Name, metav1.DeleteOptions{DryRun: dryRun(ctx)})
 			}
 		}
 	}
@@ -85,13 +126,52 @@ func (k *Kubernetes) PodsDelete(ctx context.Context, namespace, name string) (st
 			LabelSelector: managedLabelSelector.String(),
 		}); rl != nil {
 			for _, route := range rl.Items {
-				_ = routeResources.Delete(ctx, route.GetName(), metav1.DeleteOptions{})
+				_ = routeResources.Delete(ctx, route.GetName(), metav1.DeleteOptions{DryRun: dryRun(ctx)})
 			}
 		}
 
 	}
-	return "Pod deleted successfully",
-		k.ResourcesDelete(ctx, &schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}, namespace, name)
+	return ret, nil
+}
+
+// PodDisruptionBudgetError is returned when the eviction of a Pod is rejected because of the PodDisruptionBudgets
+// that apply to it
+type PodDisruptionBudgetError struct {
+	// PodDisruptionBudgets describes the budgets that apply to the Pod
+	PodDisruptionBudgets []string
+	// Err is the error returned by the Eviction API
+	Err error
+}
+
+func (e *PodDisruptionBudgetError) Error() string {
+	if len(e.PodDisruptionBudgets) == 0 {
+		return fmt.Sprintf("eviction rejected: %v", e.Err)
+	}
+	return fmt.Sprintf("eviction blocked by PodDisruptionBudget %s: %v", strings.Join(e.PodDisruptionBudgets, ", "), e.Err)
+}
+
+func (e *PodDisruptionBudgetError) Unwrap() error {
+	return e.Err
+}
+
+// podDisruptionBudgetError describes the PodDisruptionBudgets that apply to the Pod whose eviction was rejected,
+// the API server rejects the eviction when a budget allows no more disruptions (429) or when more than one budget
+// applies to the Pod (500)
+func (k *Kubernetes) podDisruptionBudgetError(ctx context.Context, pod *unstructured.Unstructured, err error) error {
+	pdbs, listErr := k.AccessControlClientset().PolicyV1().PodDisruptionBudgets(pod.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if listErr != nil {
+		return err
+	}
+	ret := &PodDisruptionBudgetError{Err: err}
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		if !pdbMatches(pdb, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: pod.GetLabels()}}) {
+			continue
+		}
+		ret.PodDisruptionBudgets = append(ret.PodDisruptionBudgets, fmt.Sprintf("%s (disruptionsAllowed: %d, currentHealthy: %d, desiredHealthy: %d, expectedPods: %d)",
+			pdb.Name, pdb.Status.DisruptionsAllowed, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy, pdb.Status.ExpectedPods))
+	}
+	return ret
 }
 
 func (k *Kubernetes) PodsLog(ctx context.Context, namespace, name, container string, previous bool, tail int64) (string, error) {
@@ -238,6 +318,9 @@ func (k *Kubernetes) PodsExec(ctx context.Context, namespace, name, container st
 	if err != nil {
 		return "", err
 	}
+	if err = k.preflight(ctx, namespace, name, ResourcePermission{Verb: "create", Resource: "pods", Subresource: "exec"}); err != nil {
+		return "", err
+	}
 	// https://github.com/kubernetes/kubectl/blob/5366de04e168bcbc11f5e340d131a9ca8b7d0df4/pkg/cmd/exec/exec.go#L350-L352
 	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
 		return "", fmt.Errorf("cannot exec into a container in a completed pod; current phase is %s", pod.Status.Phase)
@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// PodsDebugOptions describes the ephemeral debug container attached to a Pod (kubectl debug)
+type PodsDebugOptions struct {
+	// Command to run in the ephemeral container, its output is returned
+	Command []string
+	// Image of the ephemeral container (Optional, defaults to the debug helper image)
+	Image string
+	// TargetContainer is the container whose process namespace is shared with the ephemeral container (Optional)
+	TargetContainer string
+	// Timeout is the maximum time to wait for the command to complete (defaults to 2 minutes)
+	Timeout time.Duration
+}
+
+// PodDebug is the result of a command run in an ephemeral debug container
+type PodDebug struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// Container is the name of the ephemeral container, it remains in the Pod spec until the Pod is deleted
+	Container       string `json:"container"`
+	Image           string `json:"image"`
+	TargetContainer string `json:"targetContainer,omitempty"`
+	// ExitCode of the command, nil if it didn't complete before the timeout
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Output   string `json:"output"`
+}
+
+// PodsDebug attaches an ephemeral container running the provided command to a running Pod, waits for the command
+// to complete and returns its output.
+// Unlike the helper pods, the ephemeral container runs in the Pod network (and, with a target container, process)
+// namespace, so no privileged node access is needed. Ephemeral containers can't be removed once added.
+func (k *Kubernetes) PodsDebug(ctx context.Context, namespace, name string, options PodsDebugOptions) (*PodDebug, error) {
+	if len(options.Command) == 0 {
+		return nil, fmt.Errorf("no command provided")
+	}
+	namespace = k.NamespaceOrDefault(namespace)
+	pods := k.AccessControlClientset().CoreV1().Pods(namespace)
+	pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		return nil, fmt.Errorf("cannot debug a pod that is not running; current phase is %s", pod.Status.Phase)
+	}
+	if options.TargetContainer != "" && !slices.ContainsFunc(pod.Spec.Containers, func(c v1.Container) bool { return c.Name == options.TargetContainer }) {
+		return nil, fmt.Errorf("target container %s not found in pod %s", options.TargetContainer, name)
+	}
+	image := options.Image
+	if image == "" {
+		// The node architecture is only used when readable, most debug images are multi-arch
+		if image, err = k.HelperImage(ctx, HelperImageDebug, pod.Spec.NodeName); err != nil {
+			if image, err = k.HelperImage(ctx, HelperImageDebug, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	podDebug := &PodDebug{
+		Namespace:       namespace,
+		Pod:             name,
+		Container:       k.helperPodName(HelperImageDebug),
+		Image:           image,
+		TargetContainer: options.TargetContainer,
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:                     podDebug.Container,
+			Image:                    image,
+			Command:                  options.Command,
+			TerminationMessagePolicy: v1.TerminationMessageReadFile,
+		},
+		TargetContainerName: options.TargetContainer,
+	})
+	if _, err = pods.UpdateEphemeralContainers(ctx, name, pod, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("ephemeral containers are not supported by the cluster: %w", err)
+		}
+		return nil, fmt.Errorf("failed to add ephemeral container: %w", err)
+	}
+	timeout := helperTimeout(ctx, options.Timeout)
+	var state v1.ContainerState
+	err = wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := pods.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, status := range current.Status.EphemeralContainerStatuses {
+			if status.Name == podDebug.Container {
+				state = status.State
+			}
+		}
+		return state.Terminated != nil, nil
+	})
+	switch {
+	case state.Terminated != nil:
+		podDebug.ExitCode = &state.Terminated.ExitCode
+		podDebug.Reason = state.Terminated.Reason
+	case state.Waiting != nil:
+		// The image couldn't be pulled or the container couldn't be created, there are no logs
+		return nil, fmt.Errorf("ephemeral container %s did not start: %s %s", podDebug.Container, state.Waiting.Reason, state.Waiting.Message)
+	case state.Running == nil:
+		return nil, fmt.Errorf("ephemeral container %s did not start: %w", podDebug.Container, err)
+	default:
+		podDebug.Reason = fmt.Sprintf("still running after %s, output collected so far", timeout)
+	}
+	logs, err := pods.GetLogs(name, &v1.PodLogOptions{Container: podDebug.Container}).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ephemeral container %s logs: %w", podDebug.Container, err)
+	}
+	podDebug.Output = string(logs)
+	return podDebug, nil
+}
@@ -0,0 +1,280 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	// DefaultDiagnoseTailLines is the default number of log lines of each container reported by PodsDiagnose
+	DefaultDiagnoseTailLines = int64(50)
+	// podDiagnosticMaxEvents is the maximum number of recent Warning events reported by PodsDiagnose
+	podDiagnosticMaxEvents = 10
+)
+
+// PodDiagnostic gathers in a single result what is usually needed to troubleshoot a failing (e.g. crash-looping) Pod:
+// the describe-style status, the state and last termination of each container with the explanation of their exit
+// codes, the resources, the recent Warning events, and the tail of the logs of the terminated containers.
+type PodDiagnostic struct {
+	// Summary is a one line description of the Pod health
+	Summary    string                   `json:"summary"`
+	Namespace  string                   `json:"namespace"`
+	Name       string                   `json:"name"`
+	Node       string                   `json:"node,omitempty"`
+	Owner      string                   `json:"owner,omitempty"`
+	Status     string                   `json:"status"`
+	Phase      v1.PodPhase              `json:"phase"`
+	Reason     string                   `json:"reason,omitempty"`
+	Message    string                   `json:"message,omitempty"`
+	QOSClass   v1.PodQOSClass           `json:"qosClass,omitempty"`
+	StartTime  string                   `json:"startTime,omitempty"`
+	Conditions []WorkloadCondition      `json:"conditions,omitempty"`
+	Containers []PodDiagnosticContainer `json:"containers"`
+	// Terminations are the abnormal container terminations, with the explanation of their exit codes
+	Terminations []ContainerTermination `json:"terminations,omitempty"`
+	// Events are the most recent Warning events of the Pod, newest first
+	Events   []WorkloadEvent `json:"events,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+type PodDiagnosticContainer struct {
+	Name string `json:"name"`
+	// Type is init, sidecar (native sidecar), ephemeral or container
+	Type         string `json:"type"`
+	Image        string `json:"image"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+	// State is the describe-style current state of the container, e.g. "Waiting: CrashLoopBackOff"
+	State     string `json:"state"`
+	LastState string `json:"lastState,omitempty"`
+	// Requests and Limits are the resources of the container, e.g. memory: 128Mi
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
+	// Logs is the tail of the logs of the previous instance of the restarted containers (or of the current instance
+	// of the terminated containers)
+	Logs string `json:"logs,omitempty"`
+	// LogsPrevious is true when Logs are the logs of the previous instance of the container
+	LogsPrevious bool `json:"logsPrevious,omitempty"`
+}
+
+// PodsDiagnose returns the PodDiagnostic of the Pod with the provided name.
+// Failures retrieving the events or the logs don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) PodsDiagnose(ctx context.Context, namespace, name string, tail int64) (*PodDiagnostic, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	pods := k.AccessControlClientset().CoreV1().Pods(namespace)
+	pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	diagnostic := AnalyzePod(pod)
+	if tail <= 0 {
+		tail = DefaultDiagnoseTailLines
+	}
+	for i := range diagnostic.Containers {
+		container := &diagnostic.Containers[i]
+		status := podContainerStatus(pod, container.Name)
+		if status == nil {
+			continue
+		}
+		switch {
+		case status.LastTerminationState.Terminated != nil:
+			container.LogsPrevious = true
+		case status.State.Terminated == nil:
+			continue
+		}
+		logs, err := k.PodsLog(ctx, namespace, name, container.Name, container.LogsPrevious, tail)
+		if err != nil {
+			diagnostic.Warnings = append(diagnostic.Warnings, fmt.Sprintf("unable to retrieve the logs of container %s: %v", container.Name, err))
+			container.LogsPrevious = false
+			continue
+		}
+		container.Logs = logs
+	}
+	events, err := k.podWarningEvents(ctx, namespace, name)
+	if err != nil {
+		diagnostic.Warnings = append(diagnostic.Warnings, fmt.Sprintf("unable to list the events of the pod: %v", err))
+	}
+	diagnostic.Events = events
+	return diagnostic, nil
+}
+
+// podWarningEvents returns the most recent Warning events of the Pod, newest first
+func (k *Kubernetes) podWarningEvents(ctx context.Context, namespace, name string) ([]WorkloadEvent, error) {
+	events, err := k.AccessControlClientset().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": name}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	warnings := make([]v1.Event, 0, len(events.Items))
+	for _, event := range events.Items {
+		if event.Type == v1.EventTypeWarning {
+			warnings = append(warnings, event)
+		}
+	}
+	return workloadEvents(warnings, map[string]bool{"Pod/" + name: true}, podDiagnosticMaxEvents), nil
+}
+
+// AnalyzePod returns the PodDiagnostic of the Pod without the events and logs, which require further API requests
+func AnalyzePod(pod *v1.Pod) *PodDiagnostic {
+	diagnostic := &PodDiagnostic{
+		Namespace:    pod.Namespace,
+		Name:         pod.Name,
+		Node:         pod.Spec.NodeName,
+		Status:       PodStatusReason(pod),
+		Phase:        pod.Status.Phase,
+		Reason:       pod.Status.Reason,
+		Message:      pod.Status.Message,
+		QOSClass:     pod.Status.QOSClass,
+		Containers:   []PodDiagnosticContainer{},
+		Terminations: ContainerTerminations(pod),
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		diagnostic.Owner = owner.Kind + "/" + owner.Name
+	}
+	if pod.Status.StartTime != nil {
+		diagnostic.StartTime = pod.Status.StartTime.UTC().Format(time.RFC3339)
+	}
+	for _, condition := range pod.Status.Conditions {
+		c := WorkloadCondition{Type: string(condition.Type), Status: string(condition.Status), Reason: condition.Reason, Message: condition.Message}
+		if !condition.LastTransitionTime.IsZero() {
+			c.LastTransitionTime = condition.LastTransitionTime.UTC().Format(time.RFC3339)
+		}
+		diagnostic.Conditions = append(diagnostic.Conditions, c)
+	}
+	addContainer := func(containerType string, container v1.Container) {
+		c := PodDiagnosticContainer{
+			Name:     container.Name,
+			Type:     containerType,
+			Image:    container.Image,
+			State:    "Waiting",
+			Requests: resourceListStrings(container.Resources.Requests),
+			Limits:   resourceListStrings(container.Resources.Limits),
+		}
+		if status := podContainerStatus(pod, container.Name); status != nil {
+			c.Ready = status.Ready
+			c.RestartCount = status.RestartCount
+			c.State = containerStateString(status.State)
+			if status.LastTerminationState != (v1.ContainerState{}) {
+				c.LastState = containerStateString(status.LastTerminationState)
+			}
+		}
+		diagnostic.Containers = append(diagnostic.Containers, c)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if isNativeSidecar(container) {
+			addContainer("sidecar", container)
+		} else {
+			addContainer("init", container)
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		addContainer("container", container)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		addContainer("ephemeral", v1.Container(container.EphemeralContainerCommon))
+	}
+
+	ready, total, restarts := 0, len(pod.Spec.Containers), int32(0)
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+	}
+	for _, c := range diagnostic.Containers {
+		restarts += c.RestartCount
+	}
+	diagnostic.Summary = fmt.Sprintf("Pod %s is %s (%d/%d containers ready, %d restarts)", pod.Name, diagnostic.Status, ready, total, restarts)
+	if len(diagnostic.Terminations) > 0 {
+		termination := diagnostic.Terminations[0]
+		diagnostic.Summary += fmt.Sprintf(", container %s terminated with exit code %d", termination.Container, termination.ExitCode)
+		if termination.Reason != "" {
+			diagnostic.Summary += " (" + termination.Reason + ")"
+		}
+	}
+	return diagnostic
+}
+
+// PodStatusReason returns the status of the Pod as displayed by the STATUS column of kubectl get pods,
+// e.g. CrashLoopBackOff, Init:ImagePullBackOff, Terminating
+func PodStatusReason(pod *v1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for i, status := range pod.Status.InitContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode == 0 {
+			continue
+		}
+		if i < len(pod.Spec.InitContainers) && isNativeSidecar(pod.Spec.InitContainers[i]) && status.Started != nil && *status.Started {
+			continue
+		}
+		switch {
+		case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+			return "Init:" + status.State.Terminated.Reason
+		case status.State.Terminated != nil:
+			return fmt.Sprintf("Init:ExitCode:%d", status.State.Terminated.ExitCode)
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing":
+			return "Init:" + status.State.Waiting.Reason
+		default:
+			return fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+	}
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			return status.State.Waiting.Reason
+		case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+			reason = status.State.Terminated.Reason
+		}
+	}
+	return reason
+}
+
+func podContainerStatus(pod *v1.Pod, name string) *v1.ContainerStatus {
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].Name == name {
+				return &statuses[i]
+			}
+		}
+	}
+	return nil
+}
+
+// containerStateString describes the container state the same way kubectl describe does
+func containerStateString(state v1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running since " + state.Running.StartedAt.UTC().Format(time.RFC3339)
+	case state.Waiting != nil:
+		return strings.TrimSuffix(fmt.Sprintf("Waiting: %s %s", state.Waiting.Reason, state.Waiting.Message), " ")
+	case state.Terminated != nil:
+		ret := fmt.Sprintf("Terminated: %s (exit code %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+		if !state.Terminated.FinishedAt.IsZero() {
+			ret += " at " + state.Terminated.FinishedAt.UTC().Format(time.RFC3339)
+		}
+		return ret
+	}
+	return "Waiting"
+}
+
+func resourceListStrings(resources v1.ResourceList) map[string]string {
+	if len(resources) == 0 {
+		return nil
+	}
+	ret := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		ret[string(name)] = quantity.String()
+	}
+	return ret
+}
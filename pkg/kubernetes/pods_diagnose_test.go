@@ -0,0 +1,86 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type PodsDiagnoseSuite struct {
+	suite.Suite
+}
+
+func (s *PodsDiagnoseSuite) TestPodStatusReason() {
+	s.Run("terminating pod", func() {
+		now := metav1.Now()
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}, Status: v1.PodStatus{Phase: v1.PodRunning}}
+		s.Equal("Terminating", PodStatusReason(pod))
+	})
+	s.Run("waiting container reason", func() {
+		pod := &v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+			{Name: "ok", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			{Name: "crashing", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		}}}
+		s.Equal("CrashLoopBackOff", PodStatusReason(pod))
+	})
+	s.Run("terminated container reason", func() {
+		pod := &v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: []v1.ContainerStatus{
+			{Name: "app", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+		}}}
+		s.Equal("OOMKilled", PodStatusReason(pod))
+	})
+	s.Run("failed init container", func() {
+		pod := &v1.Pod{
+			Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "migrate"}}},
+			Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "migrate", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 2}}},
+			}},
+		}
+		s.Equal("Init:ExitCode:2", PodStatusReason(pod))
+	})
+	s.Run("running init containers", func() {
+		pod := &v1.Pod{
+			Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "done"}, {Name: "running"}}},
+			Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "done", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}},
+				{Name: "running", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			}},
+		}
+		s.Equal("Init:1/2", PodStatusReason(pod))
+	})
+	s.Run("started native sidecar is skipped", func() {
+		pod := &v1.Pod{
+			Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "proxy", RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways)}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "proxy", Started: ptr.To(true), State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			}},
+		}
+		s.Equal("Running", PodStatusReason(pod))
+	})
+	s.Run("pod reason takes precedence over phase", func() {
+		pod := &v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"}}
+		s.Equal("Evicted", PodStatusReason(pod))
+	})
+}
+
+func (s *PodsDiagnoseSuite) TestAnalyzePod() {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+		Status: v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: []v1.ContainerStatus{
+			{Name: "main", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+		}},
+	}
+	diagnostic := AnalyzePod(pod)
+	s.Equal("Pod job-1 is Error (0/1 containers ready, 0 restarts), container main terminated with exit code 1 (Error)", diagnostic.Summary)
+	s.Require().Len(diagnostic.Containers, 1)
+	s.Equal("Terminated: Error (exit code 1)", diagnostic.Containers[0].State)
+	s.Empty(diagnostic.Containers[0].LastState)
+}
+
+func TestPodsDiagnose(t *testing.T) {
+	suite.Run(t, new(PodsDiagnoseSuite))
+}
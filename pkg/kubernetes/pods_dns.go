@@ -0,0 +1,180 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultClusterNdots is the ndots value the kubelet sets for ClusterFirst pods
+// https://github.com/kubernetes/kubernetes/blob/master/pkg/kubelet/network/dns/dns.go
+const defaultClusterNdots = 5
+
+// maxNameservers is the number of nameservers honored by the libc resolvers
+const maxNameservers = 3
+
+// maxSearchDomains is the number of search domains honored by older libc resolvers (glibc < 2.26, musl)
+const maxSearchDomains = 6
+
+// ResolvConf is the parsed content of a resolv.conf file
+type ResolvConf struct {
+	Nameservers []string `json:"nameservers,omitempty"`
+	Searches    []string `json:"searches,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// Ndots returns the value of the ndots option, or -1 if not set
+func (r *ResolvConf) Ndots() int {
+	ndots := -1
+	for _, option := range r.Options {
+		if value, found := strings.CutPrefix(option, "ndots:"); found {
+			if n, err := strconv.Atoi(value); err == nil {
+				ndots = n
+			}
+		}
+	}
+	return ndots
+}
+
+// ParseResolvConf parses the nameserver, search and options directives of a resolv.conf file
+func ParseResolvConf(content string) *ResolvConf {
+	resolvConf := &ResolvConf{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			resolvConf.Nameservers = append(resolvConf.Nameservers, fields[1:]...)
+		case "search", "domain":
+			// the last search (or domain) directive overrides the previous ones
+			resolvConf.Searches = fields[1:]
+		case "options":
+			resolvConf.Options = append(resolvConf.Options, fields[1:]...)
+		}
+	}
+	return resolvConf
+}
+
+// PodDNS is the effective DNS configuration of a Pod
+type PodDNS struct {
+	Namespace   string           `json:"namespace"`
+	Name        string           `json:"name"`
+	DNSPolicy   v1.DNSPolicy     `json:"dnsPolicy"`
+	HostNetwork bool             `json:"hostNetwork,omitempty"`
+	DNSConfig   *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
+	// ResolvConf is the /etc/resolv.conf file of the Pod container
+	ResolvConf *ResolvConf `json:"resolvConf,omitempty"`
+	// ResolvConfError is the reason why the /etc/resolv.conf file couldn't be read
+	ResolvConfError string `json:"resolvConfError,omitempty"`
+	// Ndots is the effective ndots value (-1 if unknown)
+	Ndots       int      `json:"ndots"`
+	Diagnostics []string `json:"diagnostics,omitempty"`
+}
+
+// PodsDNS returns the DNS configuration of the Pod (spec and /etc/resolv.conf of the container) with the diagnosed issues
+func (k *Kubernetes) PodsDNS(ctx context.Context, namespace, name, container string) (*PodDNS, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	pod, err := k.AccessControlClientset().CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podDNS := &PodDNS{
+		Namespace:   namespace,
+		Name:        name,
+		DNSPolicy:   pod.Spec.DNSPolicy,
+		HostNetwork: pod.Spec.HostNetwork,
+		DNSConfig:   pod.Spec.DNSConfig,
+	}
+	if podDNS.DNSPolicy == "" {
+		podDNS.DNSPolicy = v1.DNSClusterFirst
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		podDNS.ResolvConfError = fmt.Sprintf("pod is not running (phase %s)", pod.Status.Phase)
+	} else if content, err := k.PodsExec(ctx, namespace, name, container, []string{"cat", "/etc/resolv.conf"}); err != nil {
+		podDNS.ResolvConfError = err.Error()
+	} else {
+		podDNS.ResolvConf = ParseResolvConf(content)
+	}
+	podDNS.Ndots = podDNS.effectiveNdots()
+	podDNS.Diagnostics = podDNS.diagnose()
+	return podDNS, nil
+}
+
+// effectiveNdots returns the ndots from resolv.conf or, if it couldn't be read, the one expected from the Pod spec
+func (p *PodDNS) effectiveNdots() int {
+	if p.ResolvConf != nil {
+		if ndots := p.ResolvConf.Ndots(); ndots >= 0 {
+			return ndots
+		}
+		return 1 // resolver default
+	}
+	if p.DNSConfig != nil {
+		for _, option := range p.DNSConfig.Options {
+			if option.Name == "ndots" && option.Value != nil {
+				if n, err := strconv.Atoi(*option.Value); err == nil {
+					return n
+				}
+			}
+		}
+	}
+	if p.usesClusterDNS() {
+		return defaultClusterNdots
+	}
+	return -1
+}
+
+// usesClusterDNS returns true if the kubelet configures the cluster DNS service for the Pod
+func (p *PodDNS) usesClusterDNS() bool {
+	switch p.DNSPolicy {
+	case v1.DNSClusterFirst:
+		return !p.HostNetwork
+	case v1.DNSClusterFirstWithHostNet:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *PodDNS) diagnose() []string {
+	diagnostics := make([]string, 0)
+	if p.DNSPolicy == v1.DNSClusterFirst && p.HostNetwork {
+		diagnostics = append(diagnostics, "The Pod uses hostNetwork with dnsPolicy ClusterFirst, the kubelet falls back to the node DNS configuration (Default) "+
+			"and cluster Service names won't resolve. Use dnsPolicy ClusterFirstWithHostNet to resolve them.")
+	}
+	if p.DNSPolicy == v1.DNSDefault {
+		diagnostics = append(diagnostics, "The Pod uses dnsPolicy Default (node DNS configuration), cluster Service names won't resolve.")
+	}
+	var searches, nameservers []string
+	if p.ResolvConf != nil {
+		searches, nameservers = p.ResolvConf.Searches, p.ResolvConf.Nameservers
+	} else if p.DNSConfig != nil {
+		searches, nameservers = p.DNSConfig.Searches, p.DNSConfig.Nameservers
+	}
+	if p.ResolvConf != nil && len(nameservers) == 0 {
+		diagnostics = append(diagnostics, "/etc/resolv.conf has no nameserver, name resolution will fail.")
+	}
+	if len(nameservers) > maxNameservers {
+		diagnostics = append(diagnostics, fmt.Sprintf("%d nameservers are configured but resolvers only use the first %d: %s.",
+			len(nameservers), maxNameservers, strings.Join(nameservers[:maxNameservers], ", ")))
+	}
+	if len(searches) > maxSearchDomains {
+		diagnostics = append(diagnostics, fmt.Sprintf("%d search domains are configured, older glibc (< 2.26) and musl based images only use the first %d.",
+			len(searches), maxSearchDomains))
+	}
+	if p.Ndots >= 2 && len(searches) > 0 {
+		diagnostics = append(diagnostics, fmt.Sprintf("ndots is %d: names with fewer than %d dots (e.g. external names like api.example.com) are first tried with each of the %d search domains, "+
+			"causing up to %d extra DNS queries (x2 for A and AAAA) and added latency before the absolute name is resolved. "+
+			"Use fully qualified names with a trailing dot (api.example.com.) or lower ndots through dnsConfig.options (e.g. ndots: \"2\").",
+			p.Ndots, p.Ndots, len(searches), len(searches)))
+	}
+	if p.Ndots == 0 && len(searches) > 0 {
+		diagnostics = append(diagnostics, "ndots is 0: names are always queried as absolute names first, short Service names (e.g. my-service) cause an extra failed lookup before the search domains are used.")
+	}
+	return diagnostics
+}
@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+type PodsDNSSuite struct {
+	suite.Suite
+}
+
+func (s *PodsDNSSuite) TestParseResolvConf() {
+	resolvConf := ParseResolvConf(`# Generated by the kubelet
+search default.svc.cluster.local svc.cluster.local cluster.local
+nameserver 10.96.0.10
+; ignored comment
+options ndots:5 timeout:2
+`)
+	s.Run("parses nameservers", func() {
+		s.Equal([]string{"10.96.0.10"}, resolvConf.Nameservers)
+	})
+	s.Run("parses search domains", func() {
+		s.Equal([]string{"default.svc.cluster.local", "svc.cluster.local", "cluster.local"}, resolvConf.Searches)
+	})
+	s.Run("parses options", func() {
+		s.Equal([]string{"ndots:5", "timeout:2"}, resolvConf.Options)
+		s.Equal(5, resolvConf.Ndots())
+	})
+	s.Run("last search directive wins", func() {
+		s.Equal([]string{"example.com"}, ParseResolvConf("search a.local b.local\nsearch example.com\n").Searches)
+	})
+	s.Run("ndots not set", func() {
+		s.Equal(-1, ParseResolvConf("nameserver 1.1.1.1").Ndots())
+	})
+}
+
+func (s *PodsDNSSuite) TestDiagnose() {
+	s.Run("ClusterFirst with default ndots:5 reports search domain latency", func() {
+		podDNS := &PodDNS{DNSPolicy: v1.DNSClusterFirst, ResolvConf: ParseResolvConf(
+			"search ns.svc.cluster.local svc.cluster.local cluster.local\nnameserver 10.96.0.10\noptions ndots:5")}
+		podDNS.Ndots = podDNS.effectiveNdots()
+		s.Equal(5, podDNS.Ndots)
+		s.Require().Len(podDNS.diagnose(), 1)
+		s.Contains(podDNS.diagnose()[0], "ndots is 5: names with fewer than 5 dots")
+	})
+	s.Run("resolv.conf with ndots:1 has no diagnostics", func() {
+		podDNS := &PodDNS{DNSPolicy: v1.DNSClusterFirst, ResolvConf: ParseResolvConf(
+			"search ns.svc.cluster.local svc.cluster.local cluster.local\nnameserver 10.96.0.10\noptions ndots:1")}
+		podDNS.Ndots = podDNS.effectiveNdots()
+		s.Empty(podDNS.diagnose())
+	})
+	s.Run("resolv.conf can't be read, ndots is derived from the spec", func() {
+		s.Run("ClusterFirst defaults to 5", func() {
+			podDNS := &PodDNS{DNSPolicy: v1.DNSClusterFirst}
+			s.Equal(5, podDNS.effectiveNdots())
+		})
+		s.Run("dnsConfig options override the default", func() {
+			podDNS := &PodDNS{DNSPolicy: v1.DNSClusterFirst, DNSConfig: &v1.PodDNSConfig{
+				Options: []v1.PodDNSConfigOption{{Name: "ndots", Value: ptr.To("2")}},
+			}}
+			s.Equal(2, podDNS.effectiveNdots())
+		})
+		s.Run("Default policy is unknown", func() {
+			podDNS := &PodDNS{DNSPolicy: v1.DNSDefault}
+			s.Equal(-1, podDNS.effectiveNdots())
+		})
+	})
+	s.Run("hostNetwork with ClusterFirst", func() {
+		podDNS := &PodDNS{DNSPolicy: v1.DNSClusterFirst, HostNetwork: true}
+		podDNS.Ndots = podDNS.effectiveNdots()
+		s.Equal(-1, podDNS.Ndots)
+		s.Require().Len(podDNS.diagnose(), 1)
+		s.Contains(podDNS.diagnose()[0], "Use dnsPolicy ClusterFirstWithHostNet")
+	})
+	s.Run("Default policy", func() {
+		podDNS := &PodDNS{DNSPolicy: v1.DNSDefault}
+		s.Contains(podDNS.diagnose(), "The Pod uses dnsPolicy Default (node DNS configuration), cluster Service names won't resolve.")
+	})
+	s.Run("too many nameservers and search domains", func() {
+		podDNS := &PodDNS{DNSPolicy: v1.DNSNone, DNSConfig: &v1.PodDNSConfig{
+			Nameservers: []string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "8.8.4.4"},
+			Searches:    []string{"a", "b", "c", "d", "e", "f", "g"},
+		}}
+		podDNS.Ndots = podDNS.effectiveNdots()
+		s.Equal([]string{
+			"4 nameservers are configured but resolvers only use the first 3: 1.1.1.1, 8.8.8.8, 9.9.9.9.",
+			"7 search domains are configured, older glibc (< 2.26) and musl based images only use the first 6.",
+		}, podDNS.diagnose())
+	})
+	s.Run("resolv.conf without nameservers", func() {
+		podDNS := &PodDNS{DNSPolicy: v1.DNSClusterFirst, ResolvConf: ParseResolvConf("options ndots:1")}
+		s.Contains(podDNS.diagnose(), "/etc/resolv.conf has no nameserver, name resolution will fail.")
+	})
+}
+
+func TestPodsDNS(t *testing.T) {
+	suite.Run(t, new(PodsDNSSuite))
+}
@@ -0,0 +1,864 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultImagePullTailLines is the number of kubelet log lines searched for the image pull errors
+	DefaultImagePullTailLines = int64(1000)
+	// imagePullMaxLogLines is the maximum number of kubelet log lines reported as evidence
+	imagePullMaxLogLines = 10
+	// dockerHubRegistry is the registry of the image references without registry, e.g. nginx:1.27
+	dockerHubRegistry = "docker.io"
+)
+
+// imagePullReasons are the waiting reasons of the containers whose image can't be pulled
+var imagePullReasons = []string{
+	"ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull", "RegistryUnavailable",
+	"ErrImageInspect", "SignatureValidationFailed",
+}
+
+// imagePullEventReasons are the reasons of the kubelet Warning events reporting image pull failures
+var imagePullEventReasons = []string{"Failed", "ErrImagePull", "InspectFailed", "ErrImageNeverPull", "FailedToRetrieveImagePullSecret"}
+
+// Node files with the registry configuration of the container runtime, read by PodsImagePullDebug
+const (
+	nodeRegistriesConf        = "/etc/containers/registries.conf"
+	nodeContainerdConfig      = "/etc/containerd/config.toml"
+	nodeContainerdCertsDir    = "/etc/containerd/certs.d"
+	nodeContainerdHostsConfig = "hosts.toml"
+)
+
+// nodeCredentialFiles are the node files with registry credentials read by the kubelet and the container runtime
+var nodeCredentialFiles = []string{"/var/lib/kubelet/config.json", "/root/.docker/config.json"}
+
+// nodeRegistryConfigScript prints the node files like diagnosticsNodeFilesScript, the credentials (docker config
+// auth, password, and tokens, containerd auth, password, and token settings) are redacted on the node
+const nodeRegistryConfigScript = `for f in "$@"; do
+p=$(resolve "$f" "$f" 2>/dev/null) || { echo "==> denied $f"; continue; }
+if [ -f "$p" ]; then echo "==> ok $f"; sed -E -e 's/("(auth|password|identitytoken|registrytoken)"[[:space:]]*:[[:space:]]*)"[^"]*"/\1"redacted"/g' -e 's/^([[:space:]]*(auth|password|identitytoken|token)[[:space:]]*=[[:space:]]*).*/\1"redacted"/' "$p" | base64; else echo "==> missing $f"; fi
+done`
+
+// registryHostPattern matches the registry hosts (with optional port) used as node directory names
+var registryHostPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.-]*(:[0-9]+)?$`)
+
+// imageInMessagePattern matches the image reference of the kubelet image pull messages, e.g. Failed to pull image "nginx:1.27": ...
+var imageInMessagePattern = regexp.MustCompile(`image "([^"]+)"`)
+
+// Causes of the hypotheses reported by PodsImagePullDebug
+const (
+	imagePullCauseAuth       = "The registry rejected the pull credentials, or the Pod has no credentials for the registry (authentication or authorization failure)"
+	imagePullCauseSecret     = "An image pull secret referenced by the Pod is missing or invalid"
+	imagePullCauseRepository = "The image repository doesn't exist in the registry (or it's private and the credentials don't grant access to it)"
+	imagePullCauseTag        = "The image tag doesn't exist in the repository"
+	imagePullCauseDigest     = "The image digest doesn't exist in the repository (deleted manifest or digest of another repository)"
+	imagePullCauseRateLimit  = "The registry is rate limiting the pulls (e.g. Docker Hub anonymous pull limit)"
+	imagePullCauseNetwork    = "The node can't reach the registry (DNS, firewall, proxy, or registry down)"
+	imagePullCauseTLS        = "The node doesn't trust the registry certificate, or the registry doesn't serve HTTPS"
+	imagePullCausePlatform   = "The image has no variant for the platform (OS and architecture) of the node"
+	imagePullCauseReference  = "The image reference is invalid"
+	imagePullCauseNeverPull  = "The image is not present on the node and the pull policy is Never"
+	imagePullCauseBlocked    = "The registry is blocked by the registry configuration of the node"
+)
+
+// imagePullErrorPatterns are the image pull error messages matched to the hypotheses, the message is reported as
+// evidence of every cause it matches. The not found errors are matched to the repository, tag, or digest cause
+// depending on the image reference.
+var imagePullErrorPatterns = []struct {
+	cause      string
+	likelihood string
+	pattern    *regexp.Regexp
+}{
+	{imagePullCauseRateLimit, LikelihoodHigh, regexp.MustCompile(`(?i)toomanyrequests|429 Too Many Requests|rate limit`)},
+	{imagePullCausePlatform, LikelihoodHigh, regexp.MustCompile(`(?i)no match for platform|no matching manifest for|does not match the specified platform`)},
+	{imagePullCauseTLS, LikelihoodHigh, regexp.MustCompile(`(?i)x509: |certificate signed by unknown authority|server gave HTTP response to HTTPS client`)},
+	{imagePullCauseNetwork, LikelihoodHigh, regexp.MustCompile(`(?i)no such host|i/o timeout|connection refused|no route to host|network is unreachable|TLS handshake timeout|connection reset by peer`)},
+	{imagePullCauseReference, LikelihoodHigh, regexp.MustCompile(`(?i)invalid reference format|couldn't parse image reference|InvalidImageName`)},
+	// Docker Hub answers the pulls of private and missing repositories with the same error
+	{imagePullCauseAuth, LikelihoodMedium, regexp.MustCompile(`(?i)repository does not exist or may require|may require 'docker login'`)},
+	{imagePullCauseAuth, LikelihoodHigh, regexp.MustCompile(`(?i)unauthorized|authentication required|no basic auth credentials|insufficient_scope|403 Forbidden|requested access to the resource is denied|denied: `)},
+}
+
+// imagePullNotFoundPattern matches the errors of the missing repositories, tags, or digests
+var imagePullNotFoundPattern = regexp.MustCompile(`(?i)not found|manifest unknown|name unknown|repository does not exist|\b404\b`)
+
+// imagePullRepositoryNotFoundPattern matches the errors that identify a missing repository rather than a missing tag or digest
+var imagePullRepositoryNotFoundPattern = regexp.MustCompile(`(?i)name unknown|NAME_UNKNOWN|repository does not exist|repository name not known`)
+
+// PodImagePull explains why the containers of a Pod can't pull their image: the parsed image references, the image
+// pull secrets of the Pod and its ServiceAccount with the registries they have credentials for, the image pull errors
+// of the containers, events, and kubelet log, and optionally the registry configuration of the node, combined into a
+// list of hypotheses ranked by likelihood.
+type PodImagePull struct {
+	// Summary is a one line description, e.g. "Pod web has 1 container failing to pull its image (ImagePullBackOff), most likely: ..."
+	Summary   string `json:"summary"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Node      string `json:"node,omitempty"`
+	Status    string `json:"status"`
+	// ServiceAccount is the ServiceAccount of the Pod, its image pull secrets are added to the Pod when it's created
+	ServiceAccount string `json:"serviceAccount"`
+	// Hypotheses are the likely causes of the image pull failures, the most likely first
+	Hypotheses []ImagePullHypothesis `json:"hypotheses"`
+	// Containers are the containers failing to pull their image
+	Containers []ImagePullContainer `json:"containers"`
+	// PullSecrets are the image pull secrets referenced by the Pod or its ServiceAccount, and the image pull secrets
+	// of the namespace with credentials for the registries of the failing images that are not referenced
+	PullSecrets []ImagePullSecret `json:"pullSecrets,omitempty"`
+	// NodeRegistries is the registry configuration of the node for the registries of the failing images (only if inspected)
+	NodeRegistries []NodeRegistryConfig `json:"nodeRegistries,omitempty"`
+	// KubeletLog are the image pull errors of the kubelet log of the node
+	KubeletLog []string `json:"kubeletLog,omitempty"`
+	// Events are the most recent Warning events of the Pod, newest first
+	Events     []WorkloadEvent `json:"events,omitempty"`
+	Warnings   []string        `json:"warnings,omitempty"`
+	hypotheses map[string]*ImagePullHypothesis
+}
+
+// ImagePullHypothesis is a likely cause of the image pull failures with the evidence that supports it
+type ImagePullHypothesis struct {
+	Likelihood string   `json:"likelihood"`
+	Cause      string   `json:"cause"`
+	Evidence   []string `json:"evidence"`
+	// Next is a suggestion of the next troubleshooting step
+	Next string `json:"next,omitempty"`
+}
+
+// ImagePullContainer is a container failing to pull its image
+type ImagePullContainer struct {
+	Name       string `json:"name"`
+	Image      string `json:"image"`
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	PullPolicy string `json:"pullPolicy,omitempty"`
+	// Reason and Message are the waiting reason and message of the container, e.g. ImagePullBackOff
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+	// PullSecrets are the image pull secrets of the Pod with credentials for the registry of the image
+	PullSecrets []string `json:"pullSecrets,omitempty"`
+	// Issues are the pitfalls of the image reference, e.g. the mutable latest tag
+	Issues []string `json:"issues,omitempty"`
+}
+
+// ImagePullSecret is an image pull secret, the credentials are never reported
+type ImagePullSecret struct {
+	Name string `json:"name"`
+	// ReferencedBy are the objects referencing the secret (Pod, ServiceAccount/name), empty if it isn't referenced
+	ReferencedBy []string `json:"referencedBy,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	// Registries are the registries (and repository prefixes) the secret has credentials for
+	Registries []string `json:"registries,omitempty"`
+	// Issue is the reason why the secret can't be used by the kubelet, e.g. not found
+	Issue string `json:"issue,omitempty"`
+}
+
+// NodeRegistryConfig is the configuration of the container runtime of the node for a registry
+type NodeRegistryConfig struct {
+	Registry string `json:"registry"`
+	// Mirrors are the mirrors the node pulls the images of the registry from
+	Mirrors []string `json:"mirrors,omitempty"`
+	Blocked bool     `json:"blocked,omitempty"`
+	// Insecure is true when the node pulls from the registry over HTTP or without verifying its certificate
+	Insecure bool `json:"insecure,omitempty"`
+	// CredentialFiles are the node files with credentials for the registry (the credentials aren't reported)
+	CredentialFiles []string `json:"credentialFiles,omitempty"`
+	// Sources are the node files configuring the registry
+	Sources []string `json:"sources,omitempty"`
+}
+
+// ImageReference is a container image reference split into its parts, the references without registry are
+// qualified with docker.io (and library/ for the official images) like the container runtimes do
+type ImageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// PodImagePullInput are the data gathered from the cluster analyzed by AnalyzePodImagePull
+type PodImagePullInput struct {
+	Pod *v1.Pod
+	// ServiceAccount is the ServiceAccount of the Pod, nil if not found
+	ServiceAccount *v1.ServiceAccount
+	// Secrets are the secrets of the namespace of the Pod, SecretsError the error listing them
+	Secrets      []v1.Secret
+	SecretsError error
+	Events       []WorkloadEvent
+	// KubeletLog is the tail of the kubelet log of the node of the Pod
+	KubeletLog string
+	// NodeFiles are the registry configuration files of the node by path (credentials redacted), nil if not inspected
+	NodeFiles map[string][]byte
+}
+
+// PodsImagePullDebug gathers the PodImagePull of the Pod with the provided name.
+// The kubelet log is retrieved through the API server proxy. With nodeConfig, the registry configuration of the
+// node (registries.conf, containerd configuration, and kubelet credential files) is read through a node_files
+// helper pod, the credentials are redacted before they leave the node.
+// Failures retrieving the ServiceAccount, the secrets, the events, the kubelet log, or the node files are reported
+// as warnings.
+func (k *Kubernetes) PodsImagePullDebug(ctx context.Context, namespace, name string, nodeConfig bool) (*PodImagePull, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	core := k.AccessControlClientset().CoreV1()
+	pod, err := core.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	input := PodImagePullInput{Pod: pod}
+	var warnings []string
+	serviceAccount, err := core.ServiceAccounts(namespace).Get(ctx, podServiceAccount(pod), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		input.ServiceAccount = serviceAccount
+	case !apierrors.IsNotFound(err):
+		warnings = append(warnings, fmt.Sprintf("unable to get the service account of the pod: %v", err))
+	}
+	if secrets, err := core.Secrets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		input.SecretsError = err
+	} else {
+		input.Secrets = secrets.Items
+	}
+	if input.Events, err = k.podWarningEvents(ctx, namespace, name); err != nil {
+		warnings = append(warnings, fmt.Sprintf("unable to list the events of the pod: %v", err))
+	}
+	if nodeName := pod.Spec.NodeName; nodeName != "" {
+		if input.KubeletLog, err = k.NodesLog(ctx, nodeName, "kubelet", DefaultImagePullTailLines); err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to get the kubelet log of node %s: %v", nodeName, err))
+		}
+		if nodeConfig {
+			var registries []string
+			for _, container := range imagePullContainers(pod) {
+				registries = append(registries, container.Registry)
+			}
+			ReportProgress(ctx, "Reading the registry configuration of node %s", nodeName)
+			files, err := k.readNodeFiles(ctx, nodeName, nodeRegistryConfigScript, nodeRegistryConfigFiles(registries))
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("unable to read the registry configuration of node %s: %v", nodeName, err))
+			} else {
+				input.NodeFiles = map[string][]byte{}
+				for _, file := range files {
+					input.NodeFiles[file.Name] = file.Content
+				}
+			}
+		}
+	} else if nodeConfig {
+		warnings = append(warnings, "the pod is not scheduled on a node, the registry configuration of the node is not inspected")
+	}
+	imagePull := AnalyzePodImagePull(input)
+	imagePull.Warnings = append(warnings, imagePull.Warnings...)
+	return imagePull, nil
+}
+
+// AnalyzePodImagePull ranks the likely causes of the image pull failures of the Pod from the gathered data
+func AnalyzePodImagePull(input PodImagePullInput) *PodImagePull {
+	pod := input.Pod
+	imagePull := &PodImagePull{
+		Namespace:      pod.Namespace,
+		Name:           pod.Name,
+		Node:           pod.Spec.NodeName,
+		Status:         PodStatusReason(pod),
+		ServiceAccount: podServiceAccount(pod),
+		Hypotheses:     []ImagePullHypothesis{},
+		Containers:     imagePullContainers(pod),
+		Events:         input.Events,
+		hypotheses:     map[string]*ImagePullHypothesis{},
+	}
+	secrets := imagePull.analyzePullSecrets(input)
+	for i := range imagePull.Containers {
+		container := &imagePull.Containers[i]
+		switch container.Reason {
+		case "InvalidImageName":
+			imagePull.add(imagePullCauseReference, LikelihoodHigh, fmt.Sprintf("container %s: %s", container.Name, container.Message))
+		case "ErrImageNeverPull":
+			imagePull.add(imagePullCauseNeverPull, LikelihoodHigh, fmt.Sprintf("container %s: %s", container.Name, container.Message))
+		default:
+			imagePull.analyzeError("container "+container.Name, container.Message, container.reference())
+		}
+	}
+	imagePull.analyzeEvents(input.Events)
+	imagePull.analyzeKubeletLog(input.KubeletLog)
+	imagePull.analyzeCredentials(input, secrets)
+	imagePull.analyzeNodeFiles(input.NodeFiles)
+	for _, hypothesis := range imagePull.hypotheses {
+		imagePull.Hypotheses = append(imagePull.Hypotheses, *hypothesis)
+	}
+	rank := map[string]int{LikelihoodHigh: 0, LikelihoodMedium: 1, LikelihoodLow: 2}
+	sort.SliceStable(imagePull.Hypotheses, func(i, j int) bool {
+		hi, hj := imagePull.Hypotheses[i], imagePull.Hypotheses[j]
+		if rank[hi.Likelihood] != rank[hj.Likelihood] {
+			return rank[hi.Likelihood] < rank[hj.Likelihood]
+		}
+		if len(hi.Evidence) != len(hj.Evidence) {
+			return len(hi.Evidence) > len(hj.Evidence)
+		}
+		return hi.Cause < hj.Cause
+	})
+	imagePull.Summary = imagePull.summary()
+	return imagePull
+}
+
+// ParseImageReference splits the image reference into its registry, repository, tag, and digest
+func ParseImageReference(image string) ImageReference {
+	ref := ImageReference{}
+	name, digest, _ := strings.Cut(image, "@")
+	ref.Digest = digest
+	if slash, colon := strings.LastIndex(name, "/"), strings.LastIndex(name, ":"); colon > slash {
+		name, ref.Tag = name[:colon], name[colon+1:]
+	}
+	registry, repository, found := strings.Cut(name, "/")
+	if !found || (!strings.ContainsAny(registry, ".:") && registry != "localhost") {
+		registry, repository = dockerHubRegistry, name
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	ref.Registry, ref.Repository = registry, repository
+	return ref
+}
+
+// imagePullContainers returns the containers of the Pod failing to pull their image, with their parsed image reference
+func imagePullContainers(pod *v1.Pod) []ImagePullContainer {
+	specs := map[string]v1.Container{}
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		specs[container.Name] = container
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		specs[container.Name] = v1.Container(container.EphemeralContainerCommon)
+	}
+	containers := []ImagePullContainer{}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses) {
+		if status.State.Waiting == nil || !slices.Contains(imagePullReasons, status.State.Waiting.Reason) {
+			continue
+		}
+		spec, ok := specs[status.Name]
+		if !ok {
+			spec = v1.Container{Name: status.Name, Image: status.Image}
+		}
+		ref := ParseImageReference(spec.Image)
+		container := ImagePullContainer{
+			Name:       status.Name,
+			Image:      spec.Image,
+			Registry:   ref.Registry,
+			Repository: ref.Repository,
+			Tag:        ref.Tag,
+			Digest:     ref.Digest,
+			PullPolicy: string(spec.ImagePullPolicy),
+			Reason:     status.State.Waiting.Reason,
+			Message:    status.State.Waiting.Message,
+		}
+		switch {
+		case ref.Tag == "" && ref.Digest == "":
+			container.Issues = append(container.Issues, "the image has neither a tag nor a digest, the latest tag is pulled")
+		case ref.Tag == "latest":
+			container.Issues = append(container.Issues, "the image uses the mutable latest tag, the nodes may run different versions of the image")
+		case ref.Tag != "" && ref.Digest != "":
+			container.Issues = append(container.Issues, fmt.Sprintf("the image has both a tag and a digest, the digest is pulled and the tag %s is ignored", ref.Tag))
+		}
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+func (c *ImagePullContainer) reference() ImageReference {
+	return ImageReference{Registry: c.Registry, Repository: c.Repository, Tag: c.Tag, Digest: c.Digest}
+}
+
+// podServiceAccount returns the ServiceAccount of the Pod, default if not set
+func podServiceAccount(pod *v1.Pod) string {
+	if pod.Spec.ServiceAccountName != "" {
+		return pod.Spec.ServiceAccountName
+	}
+	return "default"
+}
+
+// add adds the evidence to the hypothesis of the cause, raising its likelihood if needed
+func (p *PodImagePull) add(cause, likelihood string, evidence ...string) {
+	hypothesis, ok := p.hypotheses[cause]
+	if !ok {
+		hypothesis = &ImagePullHypothesis{Likelihood: likelihood, Cause: cause, Evidence: []string{}, Next: imagePullCauseNext[cause]}
+		p.hypotheses[cause] = hypothesis
+	}
+	if likelihood == LikelihoodHigh || (likelihood == LikelihoodMedium && hypothesis.Likelihood == LikelihoodLow) {
+		hypothesis.Likelihood = likelihood
+	}
+	for _, e := range evidence {
+		if !slices.Contains(hypothesis.Evidence, e) {
+			hypothesis.Evidence = append(hypothesis.Evidence, e)
+		}
+	}
+}
+
+// support adds the evidence to the hypotheses of the causes already reported, the evidence alone doesn't explain the failures
+func (p *PodImagePull) support(evidence string, causes ...string) {
+	for _, cause := range causes {
+		if _, ok := p.hypotheses[cause]; ok {
+			p.add(cause, LikelihoodLow, evidence)
+		}
+	}
+}
+
+// analyzeError matches the image pull error message to the hypotheses, returns whether it matched any
+func (p *PodImagePull) analyzeError(source, message string, ref ImageReference) bool {
+	if message == "" {
+		return false
+	}
+	evidence := source + ": " + message
+	matched := false
+	for _, errorPattern := range imagePullErrorPatterns {
+		if errorPattern.pattern.MatchString(message) {
+			p.add(errorPattern.cause, errorPattern.likelihood, evidence)
+			matched = true
+		}
+	}
+	if !imagePullNotFoundPattern.MatchString(message) {
+		return matched
+	}
+	switch {
+	case imagePullRepositoryNotFoundPattern.MatchString(message):
+		likelihood := LikelihoodHigh
+		if _, ok := p.hypotheses[imagePullCauseAuth]; ok {
+			likelihood = LikelihoodMedium
+		}
+		p.add(imagePullCauseRepository, likelihood, evidence)
+	case ref.Digest != "":
+		p.add(imagePullCauseDigest, LikelihoodHigh, evidence)
+	default:
+		p.add(imagePullCauseTag, LikelihoodHigh, evidence)
+		if ref.Tag == "" {
+			p.add(imagePullCauseTag, LikelihoodHigh, fmt.Sprintf("the image %s/%s has no tag, the latest tag is pulled and the repository may not have one", ref.Registry, ref.Repository))
+		}
+	}
+	return true
+}
+
+func (p *PodImagePull) analyzeEvents(events []WorkloadEvent) {
+	for _, event := range events {
+		if !slices.Contains(imagePullEventReasons, event.Reason) {
+			continue
+		}
+		if event.Reason == "FailedToRetrieveImagePullSecret" {
+			p.add(imagePullCauseSecret, LikelihoodHigh, "event "+event.Reason+": "+event.Message)
+			continue
+		}
+		if event.Reason == "InspectFailed" {
+			p.add(imagePullCauseReference, LikelihoodMedium, "event "+event.Reason+": "+event.Message)
+		}
+		p.analyzeError("event "+event.Reason, event.Message, p.messageReference(event.Message))
+	}
+}
+
+func (p *PodImagePull) analyzeKubeletLog(log string) {
+	if log == "" || len(p.Containers) == 0 {
+		return
+	}
+	matched := 0
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		if !strings.Contains(lower, "pull") || (!strings.Contains(lower, "err") && !strings.Contains(lower, "fail")) {
+			continue
+		}
+		for _, container := range p.Containers {
+			if !strings.Contains(line, container.Image) {
+				continue
+			}
+			if matched < imagePullMaxLogLines {
+				p.KubeletLog = append(p.KubeletLog, line)
+				p.analyzeError("kubelet log", line, container.reference())
+			}
+			matched++
+			break
+		}
+	}
+	if matched > imagePullMaxLogLines {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("%d more kubelet log lines matched, only the first %d are reported", matched-imagePullMaxLogLines, imagePullMaxLogLines))
+	}
+}
+
+// messageReference returns the reference of the image of a kubelet message, or of the first failing container
+func (p *PodImagePull) messageReference(message string) ImageReference {
+	if match := imageInMessagePattern.FindStringSubmatch(message); match != nil {
+		return ParseImageReference(match[1])
+	}
+	if len(p.Containers) > 0 {
+		return p.Containers[0].reference()
+	}
+	return ImageReference{}
+}
+
+// analyzePullSecrets reports the image pull secrets referenced by the Pod and its ServiceAccount, and returns the
+// image pull secrets of the namespace by name
+func (p *PodImagePull) analyzePullSecrets(input PodImagePullInput) map[string]*ImagePullSecret {
+	if input.SecretsError != nil {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("unable to list the secrets of the namespace, the image pull secrets are not checked: %v", input.SecretsError))
+		return nil
+	}
+	secrets := map[string]*ImagePullSecret{}
+	for i := range input.Secrets {
+		secret := &input.Secrets[i]
+		pullSecret := &ImagePullSecret{Name: secret.Name, Type: string(secret.Type)}
+		if registries, err := pullSecretRegistries(secret); err != nil {
+			pullSecret.Issue = err.Error()
+		} else {
+			pullSecret.Registries = registries
+		}
+		secrets[secret.Name] = pullSecret
+	}
+	reference := func(name, by string) {
+		secret, ok := secrets[name]
+		if !ok {
+			secret = &ImagePullSecret{Name: name, Issue: "not found in namespace " + p.Namespace}
+			secrets[name] = secret
+		}
+		if !slices.Contains(secret.ReferencedBy, by) {
+			secret.ReferencedBy = append(secret.ReferencedBy, by)
+		}
+	}
+	for _, ref := range input.Pod.Spec.ImagePullSecrets {
+		reference(ref.Name, "Pod")
+	}
+	if input.ServiceAccount != nil {
+		for _, ref := range input.ServiceAccount.ImagePullSecrets {
+			reference(ref.Name, "ServiceAccount/"+input.ServiceAccount.Name)
+		}
+	}
+	for i := range p.Containers {
+		container := &p.Containers[i]
+		for _, ref := range input.Pod.Spec.ImagePullSecrets {
+			if secret := secrets[ref.Name]; secret.Issue == "" && secret.covers(container.reference()) {
+				container.PullSecrets = append(container.PullSecrets, ref.Name)
+			}
+		}
+	}
+	for _, secret := range secrets {
+		// Only the unreferenced secrets that could fix the failing pulls are reported
+		unreferenced := len(secret.ReferencedBy) == 0
+		if unreferenced && (secret.Issue != "" || !slices.ContainsFunc(p.Containers, func(c ImagePullContainer) bool { return secret.covers(c.reference()) })) {
+			continue
+		}
+		p.PullSecrets = append(p.PullSecrets, *secret)
+	}
+	sort.Slice(p.PullSecrets, func(i, j int) bool { return p.PullSecrets[i].Name < p.PullSecrets[j].Name })
+	return secrets
+}
+
+// analyzeCredentials relates the image pull secrets to the failing pulls
+func (p *PodImagePull) analyzeCredentials(input PodImagePullInput, secrets map[string]*ImagePullSecret) {
+	_, authFailure := p.hypotheses[imagePullCauseAuth]
+	_, repositoryNotFound := p.hypotheses[imagePullCauseRepository]
+	for _, secret := range p.PullSecrets {
+		if secret.Issue == "" || len(secret.ReferencedBy) == 0 {
+			continue
+		}
+		likelihood := LikelihoodLow
+		if authFailure || repositoryNotFound {
+			likelihood = LikelihoodHigh
+		}
+		p.add(imagePullCauseSecret, likelihood, fmt.Sprintf("image pull secret %s referenced by %s: %s", secret.Name, strings.Join(secret.ReferencedBy, ", "), secret.Issue))
+	}
+	if secrets != nil && input.ServiceAccount != nil {
+		for _, ref := range input.ServiceAccount.ImagePullSecrets {
+			if !slices.ContainsFunc(input.Pod.Spec.ImagePullSecrets, func(r v1.LocalObjectReference) bool { return r.Name == ref.Name }) {
+				p.support(fmt.Sprintf("the image pull secret %s of ServiceAccount %s is not in the Pod, it was added after the Pod was created (recreate the Pod)", ref.Name, input.ServiceAccount.Name),
+					imagePullCauseAuth, imagePullCauseRepository)
+			}
+		}
+	}
+	for _, container := range p.Containers {
+		if secrets == nil || len(container.PullSecrets) > 0 {
+			continue
+		}
+		p.support(fmt.Sprintf("no image pull secret of the Pod has credentials for %s/%s", container.Registry, container.Repository),
+			imagePullCauseAuth, imagePullCauseRepository, imagePullCauseRateLimit)
+		for _, secret := range p.PullSecrets {
+			if len(secret.ReferencedBy) == 0 && secret.covers(container.reference()) {
+				p.support(fmt.Sprintf("the image pull secret %s of the namespace has credentials for %s but it's not referenced by the Pod nor its ServiceAccount", secret.Name, container.Registry),
+					imagePullCauseAuth, imagePullCauseRepository, imagePullCauseRateLimit)
+			}
+		}
+	}
+}
+
+// analyzeNodeFiles reports the registry configuration of the node for the registries of the failing images
+func (p *PodImagePull) analyzeNodeFiles(files map[string][]byte) {
+	if files == nil {
+		return
+	}
+	for _, container := range p.Containers {
+		if slices.ContainsFunc(p.NodeRegistries, func(r NodeRegistryConfig) bool { return r.Registry == container.Registry }) {
+			continue
+		}
+		config, err := nodeRegistryConfig(files, container.reference())
+		if err != nil {
+			p.Warnings = append(p.Warnings, err.Error())
+		}
+		p.NodeRegistries = append(p.NodeRegistries, *config)
+		if config.Blocked {
+			p.add(imagePullCauseBlocked, LikelihoodHigh, fmt.Sprintf("registry %s is blocked in %s", container.Registry, strings.Join(config.Sources, ", ")))
+		}
+		if len(config.Mirrors) > 0 {
+			p.support(fmt.Sprintf("the node pulls the images of %s from the mirrors %s, the error may come from a mirror", container.Registry, strings.Join(config.Mirrors, ", ")),
+				imagePullCauseNetwork, imagePullCauseTLS, imagePullCauseAuth, imagePullCauseRepository, imagePullCauseTag, imagePullCauseDigest)
+		}
+		if config.Insecure {
+			p.support(fmt.Sprintf("registry %s is configured as insecure on the node", container.Registry), imagePullCauseTLS)
+		}
+		if len(config.CredentialFiles) > 0 {
+			p.support(fmt.Sprintf("the node has credentials for %s in %s, they're used when the Pod has no image pull secret for the registry", container.Registry, strings.Join(config.CredentialFiles, ", ")),
+				imagePullCauseAuth, imagePullCauseRepository)
+		}
+	}
+}
+
+// covers returns whether the secret has credentials for the image
+func (s *ImagePullSecret) covers(ref ImageReference) bool {
+	return slices.ContainsFunc(s.Registries, func(registry string) bool { return dockerConfigMatches(registry, ref) })
+}
+
+// pullSecretRegistries returns the registries the image pull secret has credentials for, the credentials aren't read
+func pullSecretRegistries(secret *v1.Secret) ([]string, error) {
+	var auths map[string]json.RawMessage
+	switch secret.Type {
+	case v1.SecretTypeDockerConfigJson:
+		config := struct {
+			Auths map[string]json.RawMessage `json:"auths"`
+		}{}
+		if err := json.Unmarshal(secret.Data[v1.DockerConfigJsonKey], &config); err != nil {
+			return nil, fmt.Errorf("key %s is not a valid docker config", v1.DockerConfigJsonKey)
+		}
+		auths = config.Auths
+	case v1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[v1.DockerConfigKey], &auths); err != nil {
+			return nil, fmt.Errorf("key %s is not a valid docker config", v1.DockerConfigKey)
+		}
+	default:
+		return nil, fmt.Errorf("type %s is not an image pull secret type (%s or %s)", secret.Type, v1.SecretTypeDockerConfigJson, v1.SecretTypeDockercfg)
+	}
+	if len(auths) == 0 {
+		return nil, errors.New("no registry credentials")
+	}
+	registries := make([]string, 0, len(auths))
+	for key := range auths {
+		registries = append(registries, dockerConfigRegistry(key))
+	}
+	sort.Strings(registries)
+	return registries, nil
+}
+
+// dockerConfigRegistry returns the registry (and repository prefix) of a docker config key,
+// e.g. https://index.docker.io/v1/ is docker.io and https://quay.io/org is quay.io/org
+func dockerConfigRegistry(key string) string {
+	key = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://"), "/")
+	host, prefix, _ := strings.Cut(key, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		host = dockerHubRegistry
+	}
+	if prefix == "" || prefix == "v1" || prefix == "v2" {
+		return host
+	}
+	return host + "/" + prefix
+}
+
+// dockerConfigMatches returns whether the credentials of the registry apply to the image like the kubelet matches
+// them: the host can be a glob (e.g. *.azurecr.io) and the repository prefix must match whole path segments
+func dockerConfigMatches(registry string, ref ImageReference) bool {
+	host, prefix, _ := strings.Cut(registry, "/")
+	if matched, _ := path.Match(host, ref.Registry); !matched {
+		return false
+	}
+	return prefix == "" || ref.Repository == prefix || strings.HasPrefix(ref.Repository, prefix+"/")
+}
+
+// nodeRegistryConfigFiles returns the node files with the registry configuration of the registries
+func nodeRegistryConfigFiles(registries []string) []string {
+	files := []string{nodeRegistriesConf, nodeContainerdConfig}
+	for _, registry := range registries {
+		if !registryHostPattern.MatchString(registry) {
+			continue
+		}
+		hostsConfig := path.Join(nodeContainerdCertsDir, registry, nodeContainerdHostsConfig)
+		if !slices.Contains(files, hostsConfig) {
+			files = append(files, hostsConfig)
+		}
+	}
+	return append(files, nodeCredentialFiles...)
+}
+
+// registriesConf is the subset of the containers-registries.conf(5) version 2 format used by the analysis
+type registriesConf struct {
+	Registries []struct {
+		Prefix   string `toml:"prefix"`
+		Location string `toml:"location"`
+		Insecure bool   `toml:"insecure"`
+		Blocked  bool   `toml:"blocked"`
+		Mirrors  []struct {
+			Location string `toml:"location"`
+			Insecure bool   `toml:"insecure"`
+		} `toml:"mirror"`
+	} `toml:"registry"`
+}
+
+// containerdHosts is the subset of the containerd hosts.toml format used by the analysis
+type containerdHosts struct {
+	SkipVerify bool `toml:"skip_verify"`
+	Hosts      map[string]struct {
+		SkipVerify bool `toml:"skip_verify"`
+	} `toml:"host"`
+}
+
+// nodeRegistryConfig returns the NodeRegistryConfig of the image registry from the node files, the files that can't
+// be parsed are reported in the returned error
+func nodeRegistryConfig(files map[string][]byte, ref ImageReference) (*NodeRegistryConfig, error) {
+	config := &NodeRegistryConfig{Registry: ref.Registry}
+	var errs []error
+	if content, ok := files[nodeRegistriesConf]; ok {
+		conf := &registriesConf{}
+		if _, err := toml.Decode(string(content), conf); err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse node file %s: %w", nodeRegistriesConf, err))
+		}
+		// The registry with the longest matching prefix applies
+		longest, length := -1, -1
+		for i, registry := range conf.Registries {
+			prefix := registry.Prefix
+			if prefix == "" {
+				prefix = registry.Location
+			}
+			if dockerConfigMatches(prefix, ref) && len(prefix) > length {
+				longest, length = i, len(prefix)
+			}
+		}
+		if longest >= 0 {
+			registry := conf.Registries[longest]
+			config.Blocked, config.Insecure = registry.Blocked, registry.Insecure
+			for _, mirror := range registry.Mirrors {
+				config.Mirrors = append(config.Mirrors, mirror.Location)
+				config.Insecure = config.Insecure || mirror.Insecure
+			}
+			config.Sources = append(config.Sources, nodeRegistriesConf)
+		}
+	}
+	if content, ok := files[nodeContainerdConfig]; ok {
+		if err := containerdRegistryConfig(content, config); err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse node file %s: %w", nodeContainerdConfig, err))
+		}
+	}
+	hostsConfig := path.Join(nodeContainerdCertsDir, ref.Registry, nodeContainerdHostsConfig)
+	if content, ok := files[hostsConfig]; ok {
+		hosts := &containerdHosts{}
+		if _, err := toml.Decode(string(content), hosts); err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse node file %s: %w", hostsConfig, err))
+		}
+		mirrors := make([]string, 0, len(hosts.Hosts))
+		for host, settings := range hosts.Hosts {
+			mirrors = append(mirrors, host)
+			config.Insecure = config.Insecure || settings.SkipVerify || strings.HasPrefix(host, "http://")
+		}
+		sort.Strings(mirrors)
+		config.Mirrors = append(config.Mirrors, mirrors...)
+		config.Insecure = config.Insecure || hosts.SkipVerify
+		config.Sources = append(config.Sources, hostsConfig)
+	}
+	for _, credentialFile := range nodeCredentialFiles {
+		content, ok := files[credentialFile]
+		if !ok {
+			continue
+		}
+		dockerConfig := struct {
+			Auths map[string]json.RawMessage `json:"auths"`
+		}{}
+		if err := json.Unmarshal(content, &dockerConfig); err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse node file %s: not a valid docker config", credentialFile))
+			continue
+		}
+		for key := range dockerConfig.Auths {
+			if dockerConfigMatches(dockerConfigRegistry(key), ref) {
+				config.CredentialFiles = append(config.CredentialFiles, credentialFile)
+				break
+			}
+		}
+	}
+	return config, errors.Join(errs...)
+}
+
+// containerdRegistryConfig adds the mirrors and the TLS configuration of the registry in the CRI plugin of the
+// containerd configuration (the deprecated registry.mirrors and registry.configs settings) to the NodeRegistryConfig
+func containerdRegistryConfig(content []byte, config *NodeRegistryConfig) error {
+	var containerd struct {
+		Plugins map[string]struct {
+			Registry struct {
+				Mirrors map[string]struct {
+					Endpoint []string `toml:"endpoint"`
+				} `toml:"mirrors"`
+				Configs map[string]struct {
+					TLS struct {
+						InsecureSkipVerify bool `toml:"insecure_skip_verify"`
+					} `toml:"tls"`
+				} `toml:"configs"`
+			} `toml:"registry"`
+		} `toml:"plugins"`
+	}
+	if _, err := toml.Decode(string(content), &containerd); err != nil {
+		return err
+	}
+	configured := false
+	for _, plugin := range containerd.Plugins {
+		if mirror, ok := plugin.Registry.Mirrors[config.Registry]; ok {
+			config.Mirrors = append(config.Mirrors, mirror.Endpoint...)
+			configured = true
+		}
+		if registryConfig, ok := plugin.Registry.Configs[config.Registry]; ok {
+			config.Insecure = config.Insecure || registryConfig.TLS.InsecureSkipVerify
+			configured = true
+		}
+	}
+	if configured {
+		config.Sources = append(config.Sources, nodeContainerdConfig)
+	}
+	return nil
+}
+
+// imagePullCauseNext are the next troubleshooting steps suggested for each cause
+var imagePullCauseNext = map[string]string{
+	imagePullCauseAuth:       "Check that an image pull secret of the Pod (or of its ServiceAccount, before the Pod is created) has valid credentials with pull access to the repository",
+	imagePullCauseSecret:     "Create the missing image pull secret in the namespace of the Pod with type kubernetes.io/dockerconfigjson, or fix its reference",
+	imagePullCauseRepository: "Check the registry and repository of the image reference, and that the credentials grant access to it if it's private",
+	imagePullCauseTag:        "Check the tags of the repository in the registry and fix the image tag, prefer immutable tags or digests over latest",
+	imagePullCauseDigest:     "Check that the digest belongs to the repository and the manifest wasn't deleted, e.g. by the registry garbage collection",
+	imagePullCauseRateLimit:  "Add an image pull secret with authenticated credentials for the registry, or pull the image through a mirror or pull-through cache",
+	imagePullCauseNetwork:    "Check the DNS resolution and the connectivity from the node to the registry (firewall, proxy settings of the container runtime)",
+	imagePullCauseTLS:        "Add the registry CA to the trusted certificates of the container runtime of the node (e.g. /etc/containerd/certs.d or /etc/containers/certs.d)",
+	imagePullCausePlatform:   "Build or pull a multi-architecture image that includes the platform of the node, or schedule the Pod on nodes of the image platform",
+	imagePullCauseReference:  "Fix the image reference of the container, e.g. lowercase repository and valid tag or digest",
+	imagePullCauseNeverPull:  "Pre-pull the image on the node or change the imagePullPolicy of the container to IfNotPresent",
+	imagePullCauseBlocked:    "Pull the image from an allowed registry or remove the registry from the blocked registries of the node configuration",
+}
+
+func (p *PodImagePull) summary() string {
+	if len(p.Containers) == 0 {
+		return fmt.Sprintf("Pod %s has no container failing to pull its image (status %s)", p.Name, p.Status)
+	}
+	summary := fmt.Sprintf("Pod %s has 1 container failing to pull its image (%s)", p.Name, p.Containers[0].Reason)
+	if len(p.Containers) > 1 {
+		summary = fmt.Sprintf("Pod %s has %d containers failing to pull their image (%s)", p.Name, len(p.Containers), p.Containers[0].Reason)
+	}
+	if len(p.Hypotheses) == 0 {
+		return summary + ", no likely cause found"
+	}
+	return summary + ", most likely: " + p.Hypotheses[0].Cause
+}
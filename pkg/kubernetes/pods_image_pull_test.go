@@ -0,0 +1,216 @@
+package kubernetes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PodsImagePullSuite struct {
+	suite.Suite
+}
+
+func (s *PodsImagePullSuite) pod(image, reason, message string, pullSecrets ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: v1.PodSpec{
+			NodeName:   "worker-1",
+			Containers: []v1.Container{{Name: "app", Image: image, ImagePullPolicy: v1.PullIfNotPresent}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name: "app", Image: image,
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason, Message: message}},
+			}},
+		},
+	}
+	for _, name := range pullSecrets {
+		pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, v1.LocalObjectReference{Name: name})
+	}
+	return pod
+}
+
+func (s *PodsImagePullSuite) pullSecret(name string, registries ...string) v1.Secret {
+	auths := `{"auths":{`
+	for i, registry := range registries {
+		if i > 0 {
+			auths += ","
+		}
+		auths += `"` + registry + `":{"auth":"dXNlcjpwYXNz"}`
+	}
+	return v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Type:       v1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{v1.DockerConfigJsonKey: []byte(auths + "}}")},
+	}
+}
+
+func imagePullCauses(imagePull *PodImagePull) []string {
+	ret := make([]string, 0, len(imagePull.Hypotheses))
+	for _, hypothesis := range imagePull.Hypotheses {
+		ret = append(ret, hypothesis.Likelihood+": "+hypothesis.Cause)
+	}
+	return ret
+}
+
+func (s *PodsImagePullSuite) TestParseImageReference() {
+	s.Equal(ImageReference{Registry: "docker.io", Repository: "library/nginx"}, ParseImageReference("nginx"))
+	s.Equal(ImageReference{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.2"}, ParseImageReference("bitnami/redis:7.2"))
+	s.Equal(ImageReference{Registry: "registry:5000", Repository: "team/app", Tag: "1.0"}, ParseImageReference("registry:5000/team/app:1.0"))
+	s.Equal(ImageReference{Registry: "localhost", Repository: "app", Tag: "dev"}, ParseImageReference("localhost/app:dev"))
+	s.Equal(ImageReference{Registry: "quay.io", Repository: "org/app", Tag: "1.0", Digest: "sha256:abc"}, ParseImageReference("quay.io/org/app:1.0@sha256:abc"))
+}
+
+func (s *PodsImagePullSuite) TestTagNotFound() {
+	message := `failed to pull and unpack image "docker.io/library/nginx:1.99": failed to resolve reference "docker.io/library/nginx:1.99": docker.io/library/nginx:1.99: not found`
+	imagePull := AnalyzePodImagePull(PodImagePullInput{
+		Pod:     s.pod("nginx:1.99", "ImagePullBackOff", `Back-off pulling image "nginx:1.99"`),
+		Secrets: []v1.Secret{},
+		Events: []WorkloadEvent{
+			{Type: "Warning", Reason: "Failed", Object: "Pod/web", Message: `Failed to pull image "nginx:1.99": ` + message},
+			{Type: "Warning", Reason: "BackOff", Object: "Pod/web", Message: `Back-off pulling image "nginx:1.99"`},
+		},
+		KubeletLog: "E0110 12:00:00.000000 1 log.go:32] \"PullImage from image service failed\" err=\"rpc error: code = NotFound desc = " + message + "\" image=\"nginx:1.99\"\nI0110 12:00:01.000000 1 kubelet.go:1] \"SyncLoop\"",
+	})
+	s.Run("parses the image reference", func() {
+		s.Require().Len(imagePull.Containers, 1)
+		s.Equal("docker.io", imagePull.Containers[0].Registry)
+		s.Equal("library/nginx", imagePull.Containers[0].Repository)
+		s.Equal("1.99", imagePull.Containers[0].Tag)
+	})
+	s.Run("ranks the missing tag first", func() {
+		s.Equal([]string{"high: " + imagePullCauseTag}, imagePullCauses(imagePull))
+		s.Equal("Pod web has 1 container failing to pull its image (ImagePullBackOff), most likely: "+imagePullCauseTag, imagePull.Summary)
+	})
+	s.Run("reports the kubelet log errors of the image", func() {
+		s.Len(imagePull.KubeletLog, 1)
+		s.Len(imagePull.Hypotheses[0].Evidence, 2)
+	})
+}
+
+func (s *PodsImagePullSuite) TestDigestNotFound() {
+	image := "quay.io/org/app@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+	imagePull := AnalyzePodImagePull(PodImagePullInput{
+		Pod:     s.pod(image, "ErrImagePull", "rpc error: code = Unknown desc = reading manifest sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac in quay.io/org/app: manifest unknown"),
+		Secrets: []v1.Secret{},
+	})
+	s.Equal([]string{"high: " + imagePullCauseDigest}, imagePullCauses(imagePull))
+}
+
+func (s *PodsImagePullSuite) TestAuthenticationFailure() {
+	pod := s.pod("quay.io/org/private:1.0", "ErrImagePull",
+		`failed to pull and unpack image "quay.io/org/private:1.0": failed to authorize: failed to fetch anonymous token: unexpected status: 401 UNAUTHORIZED`, "quay-pull")
+	pod.Spec.ServiceAccountName = "builder"
+	imagePull := AnalyzePodImagePull(PodImagePullInput{
+		Pod: pod,
+		ServiceAccount: &v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder"},
+			ImagePullSecrets: []v1.LocalObjectReference{{Name: "quay-pull"}, {Name: "quay-robot"}}},
+		Secrets: []v1.Secret{s.pullSecret("quay-robot", "quay.io/org"), s.pullSecret("dockerhub", "https://index.docker.io/v1/")},
+		Events:  []WorkloadEvent{{Type: "Warning", Reason: "FailedToRetrieveImagePullSecret", Object: "Pod/web", Message: `Unable to retrieve some image pull secrets (quay-pull); attempting to pull the image may not succeed.`}},
+	})
+	s.Run("ranks the authentication failure and the missing secret", func() {
+		s.Equal([]string{"high: " + imagePullCauseAuth, "high: " + imagePullCauseSecret}, imagePullCauses(imagePull))
+	})
+	s.Run("reports the referenced and the relevant image pull secrets", func() {
+		s.Equal([]ImagePullSecret{
+			{Name: "quay-pull", ReferencedBy: []string{"Pod", "ServiceAccount/builder"}, Issue: "not found in namespace default"},
+			{Name: "quay-robot", ReferencedBy: []string{"ServiceAccount/builder"}, Type: string(v1.SecretTypeDockerConfigJson), Registries: []string{"quay.io/org"}},
+		}, imagePull.PullSecrets)
+		s.Empty(imagePull.Containers[0].PullSecrets)
+	})
+	s.Run("explains that the service account secret was added after the pod creation", func() {
+		s.Contains(imagePull.Hypotheses[0].Evidence, "the image pull secret quay-robot of ServiceAccount builder is not in the Pod, it was added after the Pod was created (recreate the Pod)")
+		s.Contains(imagePull.Hypotheses[0].Evidence, "no image pull secret of the Pod has credentials for quay.io/org/private")
+	})
+	s.Run("doesn't reveal the credentials", func() {
+		s.NotContains(imagePull.Summary+imagePull.Hypotheses[0].Evidence[0], "dXNlcjpwYXNz")
+	})
+}
+
+func (s *PodsImagePullSuite) TestDockerHubRateLimit() {
+	imagePull := AnalyzePodImagePull(PodImagePullInput{
+		Pod: s.pod("redis:7.2", "ErrImagePull",
+			`failed to pull and unpack image "docker.io/library/redis:7.2": failed to copy: httpReadSeeker: failed open: unexpected status code https://registry-1.docker.io/v2/library/redis/manifests/sha256:abc: 429 Too Many Requests - Server message: toomanyrequests: You have reached your pull rate limit.`, "dockerhub"),
+		Secrets: []v1.Secret{s.pullSecret("dockerhub", "https://index.docker.io/v1/"), {ObjectMeta: metav1.ObjectMeta{Name: "tls"}, Type: v1.SecretTypeTLS}},
+	})
+	s.Equal([]string{"high: " + imagePullCauseRateLimit}, imagePullCauses(imagePull))
+	s.Run("matches the docker hub credentials", func() {
+		s.Equal([]string{"dockerhub"}, imagePull.Containers[0].PullSecrets)
+	})
+}
+
+func (s *PodsImagePullSuite) TestInvalidSecretType() {
+	imagePull := AnalyzePodImagePull(PodImagePullInput{
+		Pod:     s.pod("registry.example.com/app:1.0", "ErrImagePull", `failed to pull image "registry.example.com/app:1.0": pull access denied, repository does not exist or may require authorization: server message: insufficient_scope: authorization failed`, "creds"),
+		Secrets: []v1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "creds"}, Type: v1.SecretTypeOpaque}},
+	})
+	s.Equal([]string{"high: " + imagePullCauseAuth, "high: " + imagePullCauseSecret, "medium: " + imagePullCauseRepository}, imagePullCauses(imagePull))
+	s.Contains(imagePull.hypotheses[imagePullCauseSecret].Evidence, "image pull secret creds referenced by Pod: type Opaque is not an image pull secret type (kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg)")
+}
+
+func (s *PodsImagePullSuite) TestNodeRegistryConfig() {
+	imagePull := AnalyzePodImagePull(PodImagePullInput{
+		Pod:     s.pod("registry.example.com:5000/team/app:1.0", "ErrImagePull", `failed to pull image: Get "https://registry.example.com:5000/v2/": x509: certificate signed by unknown authority`),
+		Secrets: []v1.Secret{},
+		NodeFiles: map[string][]byte{
+			nodeRegistriesConf: []byte(`unqualified-search-registries = ["docker.io"]
+[[registry]]
+prefix = "registry.example.com:5000"
+location = "registry.example.com:5000"
+[[registry.mirror]]
+location = "mirror.example.com/team"
+[[registry]]
+location = "registry.example.com:5000/team"
+insecure = true
+`),
+			"/etc/containerd/certs.d/registry.example.com:5000/hosts.toml": []byte(`server = "https://registry.example.com:5000"
+[host."https://cache.example.com"]
+  capabilities = ["pull", "resolve"]
+`),
+			"/var/lib/kubelet/config.json": []byte(`{"auths":{"registry.example.com:5000":{"auth":"redacted"}}}`),
+		},
+	})
+	s.Run("reports the most specific registry configuration", func() {
+		s.Equal([]NodeRegistryConfig{{
+			Registry:        "registry.example.com:5000",
+			Mirrors:         []string{"https://cache.example.com"},
+			Insecure:        true,
+			CredentialFiles: []string{"/var/lib/kubelet/config.json"},
+			Sources:         []string{nodeRegistriesConf, "/etc/containerd/certs.d/registry.example.com:5000/hosts.toml"},
+		}}, imagePull.NodeRegistries)
+	})
+	s.Run("supports the TLS hypothesis", func() {
+		s.Equal([]string{"high: " + imagePullCauseTLS}, imagePullCauses(imagePull))
+		s.Contains(imagePull.Hypotheses[0].Evidence, "registry registry.example.com:5000 is configured as insecure on the node")
+	})
+	s.Run("blocked registry", func() {
+		blocked := AnalyzePodImagePull(PodImagePullInput{
+			Pod:       s.pod("quay.io/org/app:1.0", "ErrImagePull", `pull access to quay.io/org/app:1.0 is denied by the registries configuration`),
+			Secrets:   []v1.Secret{},
+			NodeFiles: map[string][]byte{nodeRegistriesConf: []byte("[[registry]]\nlocation = \"quay.io\"\nblocked = true\n")},
+		})
+		s.Equal("high: "+imagePullCauseBlocked, imagePullCauses(blocked)[0])
+	})
+	s.Run("only lists the registry files of the failing images", func() {
+		s.Equal([]string{nodeRegistriesConf, nodeContainerdConfig, "/etc/containerd/certs.d/quay.io/hosts.toml", "/var/lib/kubelet/config.json", "/root/.docker/config.json"},
+			nodeRegistryConfigFiles([]string{"quay.io", "quay.io", "../etc"}))
+	})
+}
+
+func (s *PodsImagePullSuite) TestNoImagePullFailure() {
+	pod := s.pod("nginx:1.27", "", "")
+	pod.Status.ContainerStatuses[0].State = v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+	pod.Status.Phase = v1.PodRunning
+	imagePull := AnalyzePodImagePull(PodImagePullInput{Pod: pod, SecretsError: errors.New("forbidden")})
+	s.Equal("Pod web has no container failing to pull its image (status Running)", imagePull.Summary)
+	s.Empty(imagePull.Hypotheses)
+	s.Equal([]string{"unable to list the secrets of the namespace, the image pull secrets are not checked: forbidden"}, imagePull.Warnings)
+}
+
+func TestPodsImagePull(t *testing.T) {
+	suite.Run(t, new(PodsImagePullSuite))
+}
@@ -0,0 +1,205 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
+)
+
+// defaultTerminationGracePeriodSeconds is the grace period applied when the Pod spec doesn't set one
+const defaultTerminationGracePeriodSeconds = int64(30)
+
+// knownSidecarRegexp matches the container names or images of well-known sidecars (service mesh and auth proxies,
+// secret injectors) that other containers usually depend on.
+var knownSidecarRegexp = regexp.MustCompile(`istio-proxy|proxyv2|linkerd-proxy|envoy|cloud-sql-proxy|cloudsql-proxy|oauth2-proxy|vault-agent|kuma-dp|consul-dataplane`)
+
+var (
+	initBeforeSidecarRule = findings.Rule{
+		ID:          "LIFECYCLE001",
+		Name:        "InitContainerBeforeSidecar",
+		Description: "Init container runs before a sidecar it may depend on has started",
+		Severity:    findings.SeverityWarning,
+	}
+	legacySidecarRule = findings.Rule{
+		ID:          "LIFECYCLE002",
+		Name:        "LegacySidecar",
+		Description: "Sidecar declared as a regular container instead of a native sidecar (init container with restartPolicy: Always)",
+		Severity:    findings.SeverityWarning,
+	}
+	sidecarWithoutStartupProbeRule = findings.Rule{
+		ID:          "LIFECYCLE003",
+		Name:        "SidecarWithoutStartupProbe",
+		Description: "Native sidecar without startupProbe, the next containers start before it's ready",
+		Severity:    findings.SeverityInfo,
+	}
+	preStopExceedsGracePeriodRule = findings.Rule{
+		ID:          "LIFECYCLE004",
+		Name:        "PreStopExceedsGracePeriod",
+		Description: "preStop hook sleeps longer than the termination grace period, the container is killed before it stops gracefully",
+		Severity:    findings.SeverityWarning,
+	}
+	postStartBlocksContainersRule = findings.Rule{
+		ID:          "LIFECYCLE005",
+		Name:        "PostStartBlocksContainers",
+		Description: "postStart hook delays the start of the containers declared after it",
+		Severity:    findings.SeverityInfo,
+	}
+)
+
+// PodLifecycle explains the startup and shutdown ordering of the containers of a Pod
+type PodLifecycle struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Startup   []string `json:"startup"`
+	Shutdown  []string `json:"shutdown"`
+}
+
+// PodsLifecycle returns the startup and shutdown ordering of the containers of the Pod and the detected anti-patterns
+func (k *Kubernetes) PodsLifecycle(ctx context.Context, namespace, name string) (*PodLifecycle, *findings.Report, error) {
+	pod, err := k.AccessControlClientset().CoreV1().Pods(k.NamespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	lifecycle, report := AnalyzePodLifecycle(pod)
+	return lifecycle, report, nil
+}
+
+// AnalyzePodLifecycle explains the startup and shutdown ordering of the init containers, native sidecars
+// (init containers with restartPolicy: Always) and containers of the Pod, including their lifecycle hooks,
+// and reports the known ordering anti-patterns.
+func AnalyzePodLifecycle(pod *v1.Pod) (*PodLifecycle, *findings.Report) {
+	lifecycle := &PodLifecycle{Namespace: pod.Namespace, Name: pod.Name, Startup: []string{}, Shutdown: []string{}}
+	report := &findings.Report{Tool: "pods_lifecycle", Findings: []findings.Finding{}}
+	resource := func(field string) findings.Resource {
+		return findings.Resource{APIVersion: "v1", Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Field: field}
+	}
+	gracePeriod := ptr.Deref(pod.Spec.TerminationGracePeriodSeconds, defaultTerminationGracePeriodSeconds)
+
+	// Startup
+	var sidecars []v1.Container
+	var regularInits []string
+	step := func(format string, args ...any) {
+		lifecycle.Startup = append(lifecycle.Startup, fmt.Sprintf("%d. ", len(lifecycle.Startup)+1)+fmt.Sprintf(format, args...))
+	}
+	for i, c := range pod.Spec.InitContainers {
+		field := fmt.Sprintf("spec.initContainers[%d]", i)
+		if !isNativeSidecar(c) {
+			step("Init container %s runs to completion (must exit successfully before the next step starts)", c.Name)
+			regularInits = append(regularInits, c.Name)
+			continue
+		}
+		sidecars = append(sidecars, c)
+		switch {
+		case c.StartupProbe != nil:
+			step("Native sidecar %s starts and keeps running, the next step waits for its startupProbe to succeed", c.Name)
+		case hasPostStart(c):
+			step("Native sidecar %s starts and keeps running, the next step waits for its postStart hook to complete", c.Name)
+		default:
+			step("Native sidecar %s starts and keeps running, the next step starts as soon as it's running (no startupProbe)", c.Name)
+			if i < len(pod.Spec.InitContainers)-1 || len(pod.Spec.Containers) > 0 {
+				report.Add(sidecarWithoutStartupProbeRule, resource(field),
+					fmt.Sprintf("Native sidecar %s has no startupProbe, the containers declared after it may start before it's ready", c.Name),
+					"Add a startupProbe to the sidecar so that the next containers only start once it's ready")
+			}
+		}
+		if len(regularInits) > 0 && isKnownSidecar(c) {
+			report.Add(initBeforeSidecarRule, resource(field),
+				fmt.Sprintf("Init containers %s run before sidecar %s starts, they will hang or fail if they need it (e.g. network access through a proxy)",
+					strings.Join(regularInits, ", "), c.Name),
+				fmt.Sprintf("Declare the sidecar %s before the init containers that depend on it", c.Name))
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		names := make([]string, 0, len(pod.Spec.Containers))
+		for _, c := range pod.Spec.Containers {
+			names = append(names, c.Name)
+		}
+		if len(names) == 1 {
+			step("Container %s starts", names[0])
+		} else {
+			step("Containers %s start in the declared order without waiting for each other to be ready", strings.Join(names, ", "))
+		}
+	}
+	for i, c := range pod.Spec.Containers {
+		field := fmt.Sprintf("spec.containers[%d]", i)
+		if hasPostStart(c) && i < len(pod.Spec.Containers)-1 {
+			step("The postStart hook of container %s blocks the start of the containers declared after it until it completes", c.Name)
+			report.Add(postStartBlocksContainersRule, resource(field+".lifecycle.postStart"),
+				fmt.Sprintf("The postStart hook of container %s delays the start of the containers declared after it", c.Name), "")
+		}
+		if isKnownSidecar(c) && (len(regularInits) > 0 || pod.Spec.RestartPolicy != v1.RestartPolicyAlways && pod.Spec.RestartPolicy != "") {
+			message := fmt.Sprintf("Container %s looks like a sidecar but is declared as a regular container", c.Name)
+			if len(regularInits) > 0 {
+				message += ", it's not available to the init containers"
+			}
+			if pod.Spec.RestartPolicy == v1.RestartPolicyNever || pod.Spec.RestartPolicy == v1.RestartPolicyOnFailure {
+				message += ", and it keeps the Pod running after the main container completes (restartPolicy " + string(pod.Spec.RestartPolicy) + ")"
+			}
+			report.Add(legacySidecarRule, resource(field), message,
+				"Declare it as a native sidecar: an init container with restartPolicy: Always (Kubernetes 1.29+)")
+		}
+	}
+
+	// Shutdown
+	shutdownStep := func(format string, args ...any) {
+		lifecycle.Shutdown = append(lifecycle.Shutdown, fmt.Sprintf("%d. ", len(lifecycle.Shutdown)+1)+fmt.Sprintf(format, args...))
+	}
+	shutdownStep("The Pod gets a deletion timestamp, terminationGracePeriodSeconds is %d for all the containers", gracePeriod)
+	if preStops := containersWithPreStop(pod.Spec.Containers); len(preStops) > 0 {
+		shutdownStep("The preStop hooks of containers %s run in parallel, then the containers receive SIGTERM", strings.Join(preStops, ", "))
+	} else {
+		shutdownStep("All the containers receive SIGTERM in parallel")
+	}
+	if len(sidecars) > 0 {
+		names := make([]string, 0, len(sidecars))
+		for i := len(sidecars) - 1; i >= 0; i-- {
+			names = append(names, sidecars[i].Name)
+		}
+		shutdownStep("Once all the containers have exited, native sidecars %s are stopped in reverse order (preStop hook, then SIGTERM)", strings.Join(names, ", "))
+	}
+	shutdownStep("Containers still running when the grace period expires receive SIGKILL")
+	for i, c := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		if c.Lifecycle == nil || c.Lifecycle.PreStop == nil || c.Lifecycle.PreStop.Sleep == nil {
+			continue
+		}
+		field := fmt.Sprintf("spec.containers[%d].lifecycle.preStop.sleep", i-len(pod.Spec.InitContainers))
+		if i < len(pod.Spec.InitContainers) {
+			field = fmt.Sprintf("spec.initContainers[%d].lifecycle.preStop.sleep", i)
+		}
+		if c.Lifecycle.PreStop.Sleep.Seconds >= gracePeriod {
+			report.Add(preStopExceedsGracePeriodRule, resource(field),
+				fmt.Sprintf("The preStop hook of container %s sleeps %ds, not less than the termination grace period (%ds)", c.Name, c.Lifecycle.PreStop.Sleep.Seconds, gracePeriod),
+				"Increase terminationGracePeriodSeconds or reduce the preStop sleep")
+		}
+	}
+	return lifecycle, report
+}
+
+func isNativeSidecar(c v1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
+func isKnownSidecar(c v1.Container) bool {
+	return knownSidecarRegexp.MatchString(c.Name) || knownSidecarRegexp.MatchString(c.Image)
+}
+
+func hasPostStart(c v1.Container) bool {
+	return c.Lifecycle != nil && c.Lifecycle.PostStart != nil
+}
+
+func containersWithPreStop(containers []v1.Container) []string {
+	var names []string
+	for _, c := range containers {
+		if c.Lifecycle != nil && c.Lifecycle.PreStop != nil {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type PodsLifecycleSuite struct {
+	suite.Suite
+}
+
+func (s *PodsLifecycleSuite) TestAnalyzePodLifecycleSimple() {
+	lifecycle, report := AnalyzePodLifecycle(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "simple"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app"}}},
+	})
+	s.Run("explains startup", func() {
+		s.Equal([]string{"1. Container app starts"}, lifecycle.Startup)
+	})
+	s.Run("explains shutdown with default grace period", func() {
+		s.Equal([]string{
+			"1. The Pod gets a deletion timestamp, terminationGracePeriodSeconds is 30 for all the containers",
+			"2. All the containers receive SIGTERM in parallel",
+			"3. Containers still running when the grace period expires receive SIGKILL",
+		}, lifecycle.Shutdown)
+	})
+	s.Run("has no findings", func() {
+		s.Empty(report.Findings)
+	})
+}
+
+func (s *PodsLifecycleSuite) TestAnalyzePodLifecycleSidecars() {
+	lifecycle, report := AnalyzePodLifecycle(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mesh"},
+		Spec: v1.PodSpec{
+			TerminationGracePeriodSeconds: ptr.To(int64(10)),
+			InitContainers: []v1.Container{
+				{Name: "migrate-db"},
+				{Name: "istio-proxy", RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways), StartupProbe: &v1.Probe{}},
+				{Name: "log-shipper", RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways)},
+			},
+			Containers: []v1.Container{
+				{Name: "app", Lifecycle: &v1.Lifecycle{
+					PostStart: &v1.LifecycleHandler{Exec: &v1.ExecAction{Command: []string{"warmup"}}},
+					PreStop:   &v1.LifecycleHandler{Sleep: &v1.SleepAction{Seconds: 15}},
+				}},
+				{Name: "metrics"},
+			},
+		},
+	})
+	s.Run("explains startup order", func() {
+		s.Equal([]string{
+			"1. Init container migrate-db runs to completion (must exit successfully before the next step starts)",
+			"2. Native sidecar istio-proxy starts and keeps running, the next step waits for its startupProbe to succeed",
+			"3. Native sidecar log-shipper starts and keeps running, the next step starts as soon as it's running (no startupProbe)",
+			"4. Containers app, metrics start in the declared order without waiting for each other to be ready",
+			"5. The postStart hook of container app blocks the start of the containers declared after it until it completes",
+		}, lifecycle.Startup)
+	})
+	s.Run("explains shutdown order", func() {
+		s.Equal([]string{
+			"1. The Pod gets a deletion timestamp, terminationGracePeriodSeconds is 10 for all the containers",
+			"2. The preStop hooks of containers app run in parallel, then the containers receive SIGTERM",
+			"3. Once all the containers have exited, native sidecars log-shipper, istio-proxy are stopped in reverse order (preStop hook, then SIGTERM)",
+			"4. Containers still running when the grace period expires receive SIGKILL",
+		}, lifecycle.Shutdown)
+	})
+	s.Run("reports anti-patterns", func() {
+		ruleIDs := make([]string, 0, len(report.Findings))
+		for _, f := range report.Findings {
+			ruleIDs = append(ruleIDs, f.RuleID)
+		}
+		s.Equal([]string{"LIFECYCLE001", "LIFECYCLE003", "LIFECYCLE005", "LIFECYCLE004"}, ruleIDs)
+	})
+	s.Run("init container before known sidecar", func() {
+		s.Equal("Init containers migrate-db run before sidecar istio-proxy starts, they will hang or fail if they need it (e.g. network access through a proxy)",
+			report.Findings[0].Message)
+		s.Equal("spec.initContainers[1]", report.Findings[0].Resource.Field)
+	})
+	s.Run("preStop sleep exceeds grace period", func() {
+		s.Equal("The preStop hook of container app sleeps 15s, not less than the termination grace period (10s)", report.Findings[3].Message)
+		s.Equal("spec.containers[0].lifecycle.preStop.sleep", report.Findings[3].Resource.Field)
+	})
+}
+
+func (s *PodsLifecycleSuite) TestAnalyzePodLifecycleLegacySidecar() {
+	s.Run("in Job pod", func() {
+		_, report := AnalyzePodLifecycle(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "job"},
+			Spec: v1.PodSpec{
+				RestartPolicy: v1.RestartPolicyNever,
+				Containers:    []v1.Container{{Name: "job"}, {Name: "proxy", Image: "gcr.io/cloud-sql-connectors/cloud-sql-proxy:2.14"}},
+			},
+		})
+		s.Require().Len(report.Findings, 1)
+		s.Equal("LIFECYCLE002", report.Findings[0].RuleID)
+		s.Equal("Container proxy looks like a sidecar but is declared as a regular container, and it keeps the Pod running after the main container completes (restartPolicy Never)",
+			report.Findings[0].Message)
+	})
+	s.Run("in long running pod without init containers", func() {
+		_, report := AnalyzePodLifecycle(&v1.Pod{
+			Spec: v1.PodSpec{
+				RestartPolicy: v1.RestartPolicyAlways,
+				Containers:    []v1.Container{{Name: "app"}, {Name: "istio-proxy"}},
+			},
+		})
+		s.Empty(report.Findings)
+	})
+}
+
+func TestPodsLifecycle(t *testing.T) {
+	suite.Run(t, new(PodsLifecycleSuite))
+}
@@ -0,0 +1,314 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// DefaultOOMReportRestartThreshold is the default number of restarts from which a container is reported as restarting
+	DefaultOOMReportRestartThreshold = 5
+	// oomReportMemoryPSIThreshold is the memory PSI (avg10 or avg60 percentage) from which a node is under memory pressure
+	oomReportMemoryPSIThreshold = 10
+	// oomReportLimitRatio is the ratio of the memory limit from which the working set of a container is close to it
+	oomReportLimitRatio = 0.9
+	// oomReportLimitIncrease is the recommended increase of the memory limit of the OOMKilled containers
+	oomReportLimitIncrease = 1.5
+)
+
+// OOMReportOptions describes the Pods scanned by PodsOOMReport
+type OOMReportOptions struct {
+	// Namespace of the Pods, all namespaces if empty
+	Namespace     string
+	LabelSelector string
+	// RestartThreshold is the number of restarts from which a container is reported even if it was not OOMKilled
+	RestartThreshold int32
+	// NodeStats correlates the containers with the memory of their nodes from the kubelet stats summaries
+	NodeStats bool
+}
+
+// OOMReport lists the containers that were OOMKilled, restart often, or are close to their memory limit, and the
+// memory pressure of their nodes
+type OOMReport struct {
+	// Summary is a one line description, e.g. "2 containers OOMKilled, 1 restarting (5+ restarts) in namespace shop"
+	Summary    string         `json:"summary"`
+	Containers []OOMContainer `json:"containers"`
+	Nodes      []OOMNode      `json:"nodes,omitempty"`
+	// TargetErrors are the nodes whose stats summary couldn't be retrieved
+	TargetErrors TargetErrors `json:"targetErrors,omitempty"`
+}
+
+// OOMContainer is a container of the OOMReport, with the recommended action
+type OOMContainer struct {
+	Namespace    string `json:"namespace"`
+	Pod          string `json:"pod"`
+	Container    string `json:"container"`
+	Node         string `json:"node,omitempty"`
+	OOMKilled    bool   `json:"oomKilled"`
+	RestartCount int32  `json:"restartCount"`
+	// LastTermination is the most recent abnormal termination of the container, with its explanation
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+	MemoryRequest   string                `json:"memoryRequest,omitempty"`
+	MemoryLimit     string                `json:"memoryLimit,omitempty"`
+	// WorkingSet is the current memory working set of the container (kubelet stats summary), and LimitUsage its
+	// percentage of the memory limit
+	WorkingSet         string   `json:"workingSet,omitempty"`
+	LimitUsage         *float64 `json:"limitUsagePercent,omitempty"`
+	NodeMemoryPressure bool     `json:"nodeMemoryPressure,omitempty"`
+	Recommendation     string   `json:"recommendation"`
+}
+
+// OOMNode is the memory state of a node hosting containers of the OOMReport
+type OOMNode struct {
+	Node string `json:"node"`
+	// MemoryPressure is true if the node reports the MemoryPressure condition or its memory PSI is high
+	MemoryPressure  bool      `json:"memoryPressure"`
+	MemoryAvailable string    `json:"memoryAvailable,omitempty"`
+	MemoryPSI       *PSIStats `json:"memoryPsi,omitempty"`
+	OOMKilled       int       `json:"oomKilledContainers"`
+}
+
+// nodeStatsSummaryMemory is the subset of the kubelet Summary API response with the memory of the node and the
+// memory working set of its containers
+type nodeStatsSummaryMemory struct {
+	nodeStatsSummaryPods
+	Node struct {
+		Memory *struct {
+			AvailableBytes *uint64   `json:"availableBytes"`
+			PSI            *PSIStats `json:"psi"`
+		} `json:"memory"`
+	} `json:"node"`
+}
+
+// PodsOOMReport scans the Pods for OOMKilled containers (current state or last termination), containers restarting
+// at least options.RestartThreshold times, and, with the node stats, containers close to their memory limit.
+// The node stats are retrieved through the API server proxy, nodes whose kubelet can't be reached don't fail the
+// operation, their errors are returned in the report.
+func (k *Kubernetes) PodsOOMReport(ctx context.Context, options OOMReportOptions) (*OOMReport, error) {
+	pods, err := k.AccessControlClientset().CoreV1().Pods(options.Namespace).List(ctx, metav1.ListOptions{LabelSelector: options.LabelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	report := &OOMReport{Containers: make([]OOMContainer, 0)}
+	nodes := map[string]*OOMNode{}
+	var stats map[string]*nodeStatsSummaryMemory
+	if options.NodeStats {
+		nodeList, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		nodeNames := map[string]bool{}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != "" && pod.Status.Phase == v1.PodRunning {
+				nodeNames[pod.Spec.NodeName] = true
+			}
+		}
+		for _, node := range nodeList.Items {
+			if !nodeNames[node.Name] {
+				continue
+			}
+			nodes[node.Name] = &OOMNode{Node: node.Name}
+			for _, condition := range node.Status.Conditions {
+				if condition.Type == v1.NodeMemoryPressure {
+					nodes[node.Name].MemoryPressure = condition.Status == v1.ConditionTrue
+				}
+			}
+		}
+		stats, report.TargetErrors = k.nodesStatsSummaryMemory(ctx, slices.Sorted(maps.Keys(nodes)))
+	}
+	for _, node := range nodes {
+		if summary := stats[node.Node]; summary != nil && summary.Node.Memory != nil {
+			if summary.Node.Memory.AvailableBytes != nil {
+				available := mebibytes(int64(*summary.Node.Memory.AvailableBytes))
+				node.MemoryAvailable = available.String()
+			}
+			node.MemoryPSI = summary.Node.Memory.PSI
+			node.MemoryPressure = node.MemoryPressure || node.MemoryPSI.max() >= oomReportMemoryPSIThreshold
+		}
+	}
+	for i := range pods.Items {
+		report.Containers = append(report.Containers, oomContainers(&pods.Items[i], options.RestartThreshold, nodes, stats)...)
+	}
+	sort.SliceStable(report.Containers, func(i, j int) bool {
+		a, b := report.Containers[i], report.Containers[j]
+		if a.OOMKilled != b.OOMKilled {
+			return a.OOMKilled
+		}
+		if a.RestartCount != b.RestartCount {
+			return a.RestartCount > b.RestartCount
+		}
+		return a.Namespace+"/"+a.Pod+"/"+a.Container < b.Namespace+"/"+b.Pod+"/"+b.Container
+	})
+	for _, container := range report.Containers {
+		if node := nodes[container.Node]; node != nil && container.OOMKilled {
+			node.OOMKilled++
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(nodes)) {
+		// Only the nodes of the reported containers are relevant
+		if nodes[name].OOMKilled > 0 || nodes[name].MemoryPressure || slices.ContainsFunc(report.Containers, func(c OOMContainer) bool { return c.Node == name }) {
+			report.Nodes = append(report.Nodes, *nodes[name])
+		}
+	}
+	report.Summary = report.summary(options)
+	return report, nil
+}
+
+// oomContainers returns the containers of the Pod that were OOMKilled, restart at least restartThreshold times, or
+// are close to their memory limit, with their recommended action
+func oomContainers(pod *v1.Pod, restartThreshold int32, nodes map[string]*OOMNode, stats map[string]*nodeStatsSummaryMemory) []OOMContainer {
+	terminations := ContainerTerminations(pod)
+	specs := map[string]v1.Container{}
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		specs[container.Name] = container
+	}
+	workingSets := map[string]uint64{}
+	if summary := stats[pod.Spec.NodeName]; summary != nil {
+		for _, statsPod := range summary.Pods {
+			if statsPod.PodRef.Namespace != pod.Namespace || statsPod.PodRef.Name != pod.Name {
+				continue
+			}
+			for _, container := range statsPod.Containers {
+				if container.Memory != nil && container.Memory.WorkingSetBytes != nil {
+					workingSets[container.Name] = *container.Memory.WorkingSetBytes
+				}
+			}
+		}
+	}
+	var ret []OOMContainer
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		container := OOMContainer{
+			Namespace:    pod.Namespace,
+			Pod:          pod.Name,
+			Container:    status.Name,
+			Node:         pod.Spec.NodeName,
+			RestartCount: status.RestartCount,
+		}
+		for _, termination := range terminations {
+			if termination.Container != status.Name {
+				continue
+			}
+			container.OOMKilled = container.OOMKilled || termination.Reason == "OOMKilled"
+			// The current state is more recent than the last termination
+			if container.LastTermination == nil {
+				container.LastTermination = &termination
+			}
+		}
+		spec := specs[status.Name]
+		request, hasRequest := spec.Resources.Requests[v1.ResourceMemory]
+		limit, hasLimit := spec.Resources.Limits[v1.ResourceMemory]
+		if hasRequest {
+			container.MemoryRequest = request.String()
+		}
+		if hasLimit {
+			container.MemoryLimit = limit.String()
+		}
+		closeToLimit := false
+		if workingSet, ok := workingSets[status.Name]; ok {
+			quantity := mebibytes(int64(workingSet))
+			container.WorkingSet = quantity.String()
+			if hasLimit && !limit.IsZero() {
+				usage := float64(workingSet) / limit.AsApproximateFloat64()
+				container.LimitUsage = ptr.To(round(usage*100, 1))
+				closeToLimit = usage >= oomReportLimitRatio
+			}
+		}
+		if node := nodes[pod.Spec.NodeName]; node != nil {
+			container.NodeMemoryPressure = node.MemoryPressure
+		}
+		if !container.OOMKilled && container.RestartCount < restartThreshold && !closeToLimit {
+			continue
+		}
+		container.Recommendation = container.recommendation(limit, hasLimit, closeToLimit)
+		ret = append(ret, container)
+	}
+	return ret
+}
+
+// recommendation returns the recommended action for the container
+func (c *OOMContainer) recommendation(limit resource.Quantity, hasLimit, closeToLimit bool) string {
+	switch {
+	case c.OOMKilled && !hasLimit:
+		return fmt.Sprintf("the container has no memory limit, it was killed because node %s ran out of memory: "+
+			"set a memory request matching its usage (see resources_recommend) so that it's scheduled on a node with enough memory, and a memory limit", c.Node)
+	case c.OOMKilled && c.NodeMemoryPressure:
+		return fmt.Sprintf("node %s is under memory pressure, the container may have been killed because of the memory used by other workloads: "+
+			"increase the memory request of the container to reserve its memory, and check the node usage (nodes_top, nodes_pressure_report)", c.Node)
+	case c.OOMKilled:
+		increased := resource.NewQuantity(roundUp(int64(math.Ceil(limit.AsApproximateFloat64()*oomReportLimitIncrease)), 1<<20), resource.BinarySI)
+		return fmt.Sprintf("increase the memory limit of the container above %s (e.g. %s), or check for a memory leak and the heap size of its runtime, "+
+			"resources_recommend sizes it from the usage", limit.String(), increased.String())
+	case closeToLimit:
+		return fmt.Sprintf("the working set of the container is %.1f%% of its memory limit %s, it will be OOMKilled on the next peak: increase the memory limit",
+			*c.LimitUsage, limit.String())
+	case c.LastTermination != nil:
+		return fmt.Sprintf("the restarts are not caused by the memory limit, the last termination is %s (exit code %d): %s",
+			c.LastTermination.Reason, c.LastTermination.ExitCode, c.LastTermination.Explanation)
+	}
+	return "the restarts are not caused by the memory limit, check the container logs (pods_log with previous=true) and its probes (pods_diagnose)"
+}
+
+// nodesStatsSummaryMemory retrieves the stats summaries of the provided nodes concurrently (see FanOut)
+func (k *Kubernetes) nodesStatsSummaryMemory(ctx context.Context, nodeNames []string) (map[string]*nodeStatsSummaryMemory, TargetErrors) {
+	results, targetErrors := FanOut(ctx, 0, nodeNames, func(name string) string { return "node/" + name },
+		func(ctx context.Context, name string) (*nodeStatsSummaryMemory, error) {
+			summary, err := k.nodeStatsSummary(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			stats := &nodeStatsSummaryMemory{}
+			if err = json.Unmarshal([]byte(summary), stats); err != nil {
+				return nil, fmt.Errorf("failed to parse node stats summary: %w", err)
+			}
+			return stats, nil
+		})
+	stats := make(map[string]*nodeStatsSummaryMemory, len(results))
+	for _, result := range results {
+		stats[strings.TrimPrefix(result.Target, "node/")] = result.Value
+	}
+	return stats, targetErrors
+}
+
+func (r *OOMReport) summary(options OOMReportOptions) string {
+	oomKilled, restarting, closeToLimit := 0, 0, 0
+	for _, container := range r.Containers {
+		switch {
+		case container.OOMKilled:
+			oomKilled++
+		case container.RestartCount >= options.RestartThreshold:
+			restarting++
+		default:
+			closeToLimit++
+		}
+	}
+	scope := "in all namespaces"
+	if options.Namespace != "" {
+		scope = "in namespace " + options.Namespace
+	}
+	summary := fmt.Sprintf("%d containers OOMKilled, %d restarting (%d+ restarts)", oomKilled, restarting, options.RestartThreshold)
+	if options.NodeStats {
+		summary += fmt.Sprintf(", %d close to their memory limit", closeToLimit)
+	}
+	summary += " " + scope
+	var pressure []string
+	for _, node := range r.Nodes {
+		if node.MemoryPressure {
+			pressure = append(pressure, node.Node)
+		}
+	}
+	if len(pressure) > 0 {
+		summary += ", nodes under memory pressure: " + strings.Join(pressure, ", ")
+	}
+	return summary
+}
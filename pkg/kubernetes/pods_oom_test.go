@@ -0,0 +1,152 @@
+package kubernetes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type PodsOOMSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+}
+
+func (s *PodsOOMSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	memory := func(request, limit string) v1.ResourceRequirements {
+		resources := v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse(request)}}
+		if limit != "" {
+			resources.Limits = v1.ResourceList{v1.ResourceMemory: resource.MustParse(limit)}
+		}
+		return resources
+	}
+	oomKilled := v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+	pods := []v1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"},
+		Spec: v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{
+			{Name: "api", Resources: memory("128Mi", "256Mi")},
+			{Name: "cache", Resources: memory("64Mi", "100Mi")},
+			{Name: "proxy", Resources: memory("32Mi", "")},
+		}},
+		Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+			{Name: "api", RestartCount: 3, LastTerminationState: oomKilled},
+			{Name: "cache"},
+			{Name: "proxy"},
+		}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "worker"},
+		Spec:       v1.PodSpec{NodeName: "node-2", Containers: []v1.Container{{Name: "worker", Resources: memory("64Mi", "")}}},
+		Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+			{Name: "worker", RestartCount: 1, LastTerminationState: oomKilled},
+		}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "crashing"},
+		Spec:       v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "app"}}},
+		Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+			{Name: "app", RestartCount: 12, LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+		}},
+	}}
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}, Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-3"}},
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/shop/pods", "/api/v1/pods":
+			test.WriteObject(w, &v1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}, Items: pods})
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"}, Items: nodes})
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"memory":{"availableBytes":2147483648,"psi":{"some":{"avg10":1.5,"avg60":0.5}}}},` +
+				`"pods":[{"podRef":{"name":"api","namespace":"shop"},"containers":[` +
+				`{"name":"api","memory":{"workingSetBytes":104857600}},` +
+				`{"name":"cache","memory":{"workingSetBytes":99614720}},` +
+				`{"name":"proxy","memory":{"workingSetBytes":10485760}}]}]}`))
+		case "/api/v1/nodes/node-2/proxy/stats/summary":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+}
+
+func (s *PodsOOMSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsOOMSuite) derived() *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *PodsOOMSuite) TestPodsOOMReport() {
+	k := s.derived()
+	report, err := k.PodsOOMReport(s.T().Context(), OOMReportOptions{Namespace: "shop", RestartThreshold: DefaultOOMReportRestartThreshold, NodeStats: true})
+	s.Require().NoError(err)
+	s.Run("returns the summary", func() {
+		s.Equal("2 containers OOMKilled, 1 restarting (5+ restarts), 1 close to their memory limit in namespace shop, nodes under memory pressure: node-2", report.Summary)
+	})
+	s.Run("sorts the OOMKilled containers first", func() {
+		s.Require().Len(report.Containers, 4)
+		s.Equal("api", report.Containers[0].Container)
+		s.Equal("worker", report.Containers[1].Container)
+		s.Equal("app", report.Containers[2].Container)
+		s.Equal("cache", report.Containers[3].Container)
+	})
+	s.Run("recommends increasing the memory limit of OOMKilled containers", func() {
+		api := report.Containers[0]
+		s.True(api.OOMKilled)
+		s.Equal("256Mi", api.MemoryLimit)
+		s.Equal("100Mi", api.WorkingSet)
+		s.Equal(39.1, *api.LimitUsage)
+		s.Equal("OOMKilled", api.LastTermination.Reason)
+		s.Contains(api.Recommendation, "increase the memory limit of the container above 256Mi (e.g. 384Mi)")
+	})
+	s.Run("recommends a memory request for OOMKilled containers on nodes under memory pressure", func() {
+		worker := report.Containers[1]
+		s.True(worker.NodeMemoryPressure)
+		s.Contains(worker.Recommendation, "the container has no memory limit, it was killed because node node-2 ran out of memory")
+	})
+	s.Run("explains the restarts that are not memory related", func() {
+		s.False(report.Containers[2].OOMKilled)
+		s.Contains(report.Containers[2].Recommendation, "the restarts are not caused by the memory limit, the last termination is Error (exit code 1)")
+	})
+	s.Run("reports the containers close to their memory limit", func() {
+		s.Equal(95.0, *report.Containers[3].LimitUsage)
+		s.Contains(report.Containers[3].Recommendation, "the working set of the container is 95.0% of its memory limit 100Mi")
+	})
+	s.Run("returns the memory of the nodes", func() {
+		s.Require().Len(report.Nodes, 2)
+		s.Equal(OOMNode{Node: "node-1", MemoryAvailable: "2Gi", MemoryPSI: report.Nodes[0].MemoryPSI, OOMKilled: 1}, report.Nodes[0])
+		s.Equal(OOMNode{Node: "node-2", MemoryPressure: true, OOMKilled: 1}, report.Nodes[1])
+	})
+	s.Run("returns the nodes whose stats summary can't be retrieved", func() {
+		s.Require().Len(report.TargetErrors, 1)
+		s.Equal("node/node-2", report.TargetErrors[0].Target)
+	})
+	s.Run("without node stats only reports the OOMKilled and restarting containers", func() {
+		report, err := k.PodsOOMReport(s.T().Context(), OOMReportOptions{RestartThreshold: DefaultOOMReportRestartThreshold})
+		s.Require().NoError(err)
+		s.Len(report.Containers, 3)
+		s.Empty(report.Nodes)
+		s.Equal("2 containers OOMKilled, 1 restarting (5+ restarts) in all namespaces", report.Summary)
+	})
+}
+
+func TestPodsOOM(t *testing.T) {
+	suite.Run(t, new(PodsOOMSuite))
+}
@@ -0,0 +1,289 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodScheduling explains why a Pending Pod is not scheduled: the nodes ruled out with the reasons of each
+// (unschedulable, node selector and affinity, taints, resources, host ports), the Pod level issues
+// (scheduling gates, unbound PersistentVolumeClaims, custom scheduler), and the FailedScheduling events.
+type PodScheduling struct {
+	// Summary is a one line explanation in the format of the scheduler messages,
+	// e.g. "Pod web is Pending, 0/3 nodes are available: 3 Insufficient cpu."
+	Summary   string      `json:"summary"`
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Phase     v1.PodPhase `json:"phase"`
+	// Node is the node the Pod is scheduled on, if any
+	Node          string `json:"node,omitempty"`
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// SchedulerMessage is the message of the PodScheduled condition reported by the scheduler
+	SchedulerMessage string `json:"schedulerMessage,omitempty"`
+	// NominatedNode is the node where the scheduler is preempting lower priority Pods to make room for the Pod
+	NominatedNode string `json:"nominatedNode,omitempty"`
+	// Requests are the effective resource requests of the Pod considered by the scheduler
+	Requests map[string]string `json:"requests,omitempty"`
+	// Issues are the reasons that prevent the Pod from being scheduled regardless of the nodes
+	Issues []string `json:"issues,omitempty"`
+	// AvailableNodes are the nodes that pass all the evaluated checks
+	AvailableNodes []string `json:"availableNodes,omitempty"`
+	// RuledOutNodes are the nodes that can't run the Pod with the reasons why
+	RuledOutNodes []NodeScheduling `json:"ruledOutNodes,omitempty"`
+	// Events are the most recent Warning (e.g. FailedScheduling) events of the Pod, newest first
+	Events   []WorkloadEvent `json:"events,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+type NodeScheduling struct {
+	Node    string   `json:"node"`
+	Reasons []string `json:"reasons"`
+}
+
+// PodsWhyPending returns the PodScheduling of the Pod with the provided name.
+// Failures retrieving the events or the PersistentVolumeClaims don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) PodsWhyPending(ctx context.Context, namespace, name string) (*PodScheduling, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	core := k.AccessControlClientset().CoreV1()
+	pod, err := core.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if pod.Spec.NodeName != "" {
+		return AnalyzePodScheduling(pod, nil, nil), nil
+	}
+	nodes, err := core.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := core.Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	scheduling := AnalyzePodScheduling(pod, nodes.Items, pods.Items)
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claim := volume.PersistentVolumeClaim.ClaimName
+		pvc, pvcErr := core.PersistentVolumeClaims(namespace).Get(ctx, claim, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(pvcErr):
+			scheduling.Issues = append(scheduling.Issues, fmt.Sprintf("PersistentVolumeClaim %s not found", claim))
+		case pvcErr != nil:
+			scheduling.Warnings = append(scheduling.Warnings, fmt.Sprintf("unable to retrieve PersistentVolumeClaim %s: %v", claim, pvcErr))
+		case pvc.Status.Phase != v1.ClaimBound:
+			issue := fmt.Sprintf("PersistentVolumeClaim %s is %s", claim, pvc.Status.Phase)
+			if pvc.Spec.StorageClassName != nil {
+				issue += fmt.Sprintf(" (storage class %s)", *pvc.Spec.StorageClassName)
+			}
+			scheduling.Issues = append(scheduling.Issues, issue)
+		}
+	}
+	events, err := k.podWarningEvents(ctx, namespace, name)
+	if err != nil {
+		scheduling.Warnings = append(scheduling.Warnings, fmt.Sprintf("unable to list the events of the pod: %v", err))
+	}
+	scheduling.Events = events
+	return scheduling, nil
+}
+
+// AnalyzePodScheduling evaluates the scheduling of the Pod against each of the nodes given the Pods already running in the cluster.
+// The checks mirror the scheduler filters for the node status, node selector and affinity, taints, resources, and host ports.
+// Inter-pod affinity and topology spread constraints depend on the placement of other Pods and are not evaluated.
+func AnalyzePodScheduling(pod *v1.Pod, nodes []v1.Node, pods []v1.Pod) *PodScheduling {
+	scheduling := &PodScheduling{
+		Namespace:     pod.Namespace,
+		Name:          pod.Name,
+		Phase:         pod.Status.Phase,
+		Node:          pod.Spec.NodeName,
+		SchedulerName: pod.Spec.SchedulerName,
+		NominatedNode: pod.Status.NominatedNodeName,
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status != v1.ConditionTrue {
+			scheduling.SchedulerMessage = condition.Message
+		}
+	}
+	if pod.Spec.NodeName != "" {
+		scheduling.Summary = fmt.Sprintf("Pod %s is not pending, it's scheduled on node %s (phase %s)", pod.Name, pod.Spec.NodeName, pod.Status.Phase)
+		return scheduling
+	}
+	requests := podRequests(pod)
+	scheduling.Requests = resourceListStrings(requests)
+
+	if len(pod.Spec.SchedulingGates) > 0 {
+		gates := make([]string, 0, len(pod.Spec.SchedulingGates))
+		for _, gate := range pod.Spec.SchedulingGates {
+			gates = append(gates, gate.Name)
+		}
+		scheduling.Issues = append(scheduling.Issues, fmt.Sprintf(
+			"scheduling is blocked by the scheduling gates %s, the scheduler ignores the Pod until the controllers that added them remove them", strings.Join(gates, ", ")))
+	}
+	if pod.Spec.SchedulerName != "" && pod.Spec.SchedulerName != v1.DefaultSchedulerName {
+		scheduling.Issues = append(scheduling.Issues, fmt.Sprintf("Pod is scheduled by the custom scheduler %s, make sure it's running", pod.Spec.SchedulerName))
+	}
+	if affinity := pod.Spec.Affinity; (affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil)) || len(pod.Spec.TopologySpreadConstraints) > 0 {
+		scheduling.Issues = append(scheduling.Issues,
+			"Pod has inter-pod affinity, anti-affinity, or topology spread constraints, which are not evaluated by this analysis (check the scheduler message)")
+	}
+
+	// Resources, Pod count, and host ports already in use on each node
+	requested := map[string]v1.ResourceList{}
+	podCount := map[string]int64{}
+	hostPorts := map[string]map[string]bool{}
+	for i := range pods {
+		p := &pods[i]
+		if p.Spec.NodeName == "" || isTerminalPod(p) || (p.Namespace == pod.Namespace && p.Name == pod.Name) {
+			continue
+		}
+		requested[p.Spec.NodeName] = addResourceList(requested[p.Spec.NodeName], podRequests(p))
+		podCount[p.Spec.NodeName]++
+		for _, port := range podHostPorts(p) {
+			if hostPorts[p.Spec.NodeName] == nil {
+				hostPorts[p.Spec.NodeName] = map[string]bool{}
+			}
+			hostPorts[p.Spec.NodeName][port] = true
+		}
+	}
+
+	// Reasons aggregated in the scheduler message format, e.g. "2 Insufficient cpu"
+	reasonCount := map[string]int{}
+	for i := range nodes {
+		node := &nodes[i]
+		var reasons, summaries []string
+		addReason := func(summary, reason string) {
+			reasons = append(reasons, reason)
+			if summary != "" && !slices.Contains(summaries, summary) {
+				summaries = append(summaries, summary)
+			}
+		}
+		unschedulableTaint := &v1.Taint{Key: v1.TaintNodeUnschedulable, Effect: v1.TaintEffectNoSchedule}
+		if node.Spec.Unschedulable && !tolerates(pod.Spec.Tolerations, unschedulableTaint) {
+			addReason("node(s) were unschedulable", "node is unschedulable (cordoned)")
+		}
+		if mismatches := labelMismatches(pod.Spec.NodeSelector, node.Labels, "node"); len(mismatches) > 0 {
+			addReason("node(s) didn't match Pod's node affinity/selector", "node selector doesn't match the node labels: "+strings.Join(mismatches, ", "))
+		} else if !matchesNodeSelector(pod, node) {
+			addReason("node(s) didn't match Pod's node affinity/selector", "required node affinity doesn't match the node labels")
+		}
+		for j := range node.Spec.Taints {
+			taint := &node.Spec.Taints[j]
+			if taint.Effect != v1.TaintEffectNoSchedule && taint.Effect != v1.TaintEffectNoExecute {
+				continue
+			}
+			// The cordon taint is already reported as unschedulable
+			if node.Spec.Unschedulable && taint.Key == v1.TaintNodeUnschedulable {
+				continue
+			}
+			if !tolerates(pod.Spec.Tolerations, taint) {
+				addReason(fmt.Sprintf("node(s) had untolerated taint {%s: %s}", taint.Key, taint.Value),
+					fmt.Sprintf("untolerated taint %s", taint.ToString()))
+			}
+		}
+		if allocatable, ok := node.Status.Allocatable[v1.ResourcePods]; ok && podCount[node.Name]+1 > allocatable.Value() {
+			addReason("Too many pods", fmt.Sprintf("too many pods: %d of %d allocatable already running", podCount[node.Name], allocatable.Value()))
+		}
+		for _, name := range sortedResourceNames(requests) {
+			podRequest := requests[name]
+			if podRequest.IsZero() {
+				continue
+			}
+			allocatable := node.Status.Allocatable[name]
+			free := allocatable.DeepCopy()
+			free.Sub(requested[node.Name][name])
+			if free.Cmp(podRequest) < 0 {
+				if free.Sign() < 0 {
+					free = resource.Quantity{}
+				}
+				addReason("Insufficient "+string(name), fmt.Sprintf("insufficient %s: requested %s, available %s of %s allocatable",
+					name, podRequest.String(), free.String(), allocatable.String()))
+			}
+		}
+		for _, port := range podHostPorts(pod) {
+			if hostPorts[node.Name][port] {
+				addReason("node(s) didn't have free ports for the requested pod ports", fmt.Sprintf("host port %s is already in use", port))
+			}
+		}
+		if len(reasons) == 0 {
+			scheduling.AvailableNodes = append(scheduling.AvailableNodes, node.Name)
+			continue
+		}
+		scheduling.RuledOutNodes = append(scheduling.RuledOutNodes, NodeScheduling{Node: node.Name, Reasons: reasons})
+		for _, summary := range summaries {
+			reasonCount[summary]++
+		}
+	}
+	sort.Strings(scheduling.AvailableNodes)
+	sort.Slice(scheduling.RuledOutNodes, func(i, j int) bool { return scheduling.RuledOutNodes[i].Node < scheduling.RuledOutNodes[j].Node })
+
+	aggregated := make([]string, 0, len(reasonCount))
+	for summary, count := range reasonCount {
+		aggregated = append(aggregated, fmt.Sprintf("%d %s", count, summary))
+	}
+	sort.Strings(aggregated)
+	scheduling.Summary = fmt.Sprintf("Pod %s is Pending, %d/%d nodes are available", pod.Name, len(scheduling.AvailableNodes), len(nodes))
+	if len(aggregated) > 0 {
+		scheduling.Summary += ": " + strings.Join(aggregated, ", ")
+	}
+	scheduling.Summary += "."
+	return scheduling
+}
+
+func tolerates(tolerations []v1.Toleration, taint *v1.Taint) bool {
+	return slices.ContainsFunc(tolerations, func(t v1.Toleration) bool { return t.ToleratesTaint(taint) })
+}
+
+// labelMismatches returns the entries of the selector that don't match the labels of the object of the provided kind,
+// e.g. "disktype=ssd (node: hdd)"
+func labelMismatches(selector, objectLabels map[string]string, kind string) []string {
+	var mismatches []string
+	for key, value := range selector {
+		actual, ok := objectLabels[key]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s=%s (%s: not set)", key, value, kind))
+		case actual != value:
+			mismatches = append(mismatches, fmt.Sprintf("%s=%s (%s: %s)", key, value, kind, actual))
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches
+}
+
+// podHostPorts returns the host ports requested by the containers of the Pod (protocol/port)
+func podHostPorts(pod *v1.Pod) []string {
+	var ports []string
+	for _, containers := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			for _, port := range container.Ports {
+				if port.HostPort <= 0 {
+					continue
+				}
+				protocol := port.Protocol
+				if protocol == "" {
+					protocol = v1.ProtocolTCP
+				}
+				ports = append(ports, fmt.Sprintf("%s/%d", protocol, port.HostPort))
+			}
+		}
+	}
+	return ports
+}
+
+func sortedResourceNames(resources v1.ResourceList) []v1.ResourceName {
+	names := make([]v1.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
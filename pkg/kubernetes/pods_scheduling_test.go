@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type PodsSchedulingSuite struct {
+	suite.Suite
+}
+
+func (s *PodsSchedulingSuite) TestAnalyzePodScheduling() {
+	pending := drainTestPod("pending", "", "ReplicaSet", "web", func(pod *v1.Pod) {
+		pod.Status.Phase = v1.PodPending
+		pod.Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("2")
+	})
+	s.Run("reports the reasons of each ruled out node", func() {
+		nodes := []v1.Node{
+			drainTestNode("available", "4", "8Gi"),
+			drainTestNode("cordoned", "4", "8Gi", func(node *v1.Node) {
+				node.Spec.Unschedulable = true
+				node.Spec.Taints = []v1.Taint{{Key: v1.TaintNodeUnschedulable, Effect: v1.TaintEffectNoSchedule}}
+			}),
+			drainTestNode("control-plane", "4", "8Gi", func(node *v1.Node) {
+				node.Spec.Taints = []v1.Taint{{Key: "node-role.kubernetes.io/control-plane", Effect: v1.TaintEffectNoSchedule}}
+			}),
+			drainTestNode("full", "4", "8Gi"),
+			drainTestNode("small", "1", "8Gi"),
+		}
+		pods := []v1.Pod{
+			drainTestPod("running-1", "full", "ReplicaSet", "db", func(pod *v1.Pod) {
+				pod.Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("3")
+			}),
+			drainTestPod("completed", "available", "Job", "batch", func(pod *v1.Pod) {
+				pod.Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("4")
+				pod.Status.Phase = v1.PodSucceeded
+			}),
+		}
+		scheduling := AnalyzePodScheduling(&pending, nodes, pods)
+		s.Equal("Pod pending is Pending, 1/5 nodes are available: 1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }, "+
+			"1 node(s) were unschedulable, 2 Insufficient cpu.", scheduling.Summary)
+		s.Equal([]string{"available"}, scheduling.AvailableNodes)
+		s.Equal([]NodeScheduling{
+			{Node: "control-plane", Reasons: []string{"untolerated taint node-role.kubernetes.io/control-plane:NoSchedule"}},
+			{Node: "cordoned", Reasons: []string{"node is unschedulable (cordoned)"}},
+			{Node: "full", Reasons: []string{"insufficient cpu: requested 2, available 1 of 4 allocatable"}},
+			{Node: "small", Reasons: []string{"insufficient cpu: requested 2, available 1 of 1 allocatable"}},
+		}, scheduling.RuledOutNodes)
+		s.Equal(map[string]string{"cpu": "2", "memory": "512Mi"}, scheduling.Requests)
+	})
+	s.Run("tolerated taints don't rule out nodes", func() {
+		tolerating := pending.DeepCopy()
+		tolerating.Spec.Tolerations = []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+		nodes := []v1.Node{drainTestNode("gpu", "4", "8Gi", func(node *v1.Node) {
+			node.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+		})}
+		scheduling := AnalyzePodScheduling(tolerating, nodes, nil)
+		s.Equal("Pod pending is Pending, 1/1 nodes are available.", scheduling.Summary)
+		s.Empty(scheduling.RuledOutNodes)
+	})
+	s.Run("node selector and affinity mismatches", func() {
+		selecting := pending.DeepCopy()
+		selecting.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+		nodes := []v1.Node{
+			drainTestNode("hdd", "4", "8Gi", func(node *v1.Node) { node.Labels["disktype"] = "hdd" }),
+			drainTestNode("unlabeled", "4", "8Gi"),
+		}
+		scheduling := AnalyzePodScheduling(selecting, nodes, nil)
+		s.Equal("Pod pending is Pending, 0/2 nodes are available: 2 node(s) didn't match Pod's node affinity/selector.", scheduling.Summary)
+		s.Equal([]string{"node selector doesn't match the node labels: disktype=ssd (node: hdd)"}, scheduling.RuledOutNodes[0].Reasons)
+		s.Equal([]string{"node selector doesn't match the node labels: disktype=ssd (node: not set)"}, scheduling.RuledOutNodes[1].Reasons)
+		selecting.Spec.NodeSelector = nil
+		selecting.Spec.Affinity = &v1.Affinity{NodeAffinity: &v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{
+				{Key: "disktype", Operator: v1.NodeSelectorOpIn, Values: []string{"ssd", "nvme"}},
+			}}},
+		}}}
+		scheduling = AnalyzePodScheduling(selecting, nodes, nil)
+		s.Equal([]string{"required node affinity doesn't match the node labels"}, scheduling.RuledOutNodes[0].Reasons)
+	})
+	s.Run("pod count and host ports", func() {
+		withPort := pending.DeepCopy()
+		withPort.Spec.Containers[0].Ports = []v1.ContainerPort{{ContainerPort: 8080, HostPort: 80}}
+		nodes := []v1.Node{drainTestNode("node-1", "8", "8Gi", func(node *v1.Node) {
+			node.Status.Allocatable[v1.ResourcePods] = resource.MustParse("1")
+		})}
+		pods := []v1.Pod{drainTestPod("ingress", "node-1", "DaemonSet", "ingress", func(pod *v1.Pod) {
+			pod.Spec.Containers[0].Ports = []v1.ContainerPort{{ContainerPort: 80, HostPort: 80, Protocol: v1.ProtocolTCP}}
+		})}
+		scheduling := AnalyzePodScheduling(withPort, nodes, pods)
+		s.Equal("Pod pending is Pending, 0/1 nodes are available: 1 Too many pods, 1 node(s) didn't have free ports for the requested pod ports.", scheduling.Summary)
+		s.Equal([]string{"too many pods: 1 of 1 allocatable already running", "host port TCP/80 is already in use"}, scheduling.RuledOutNodes[0].Reasons)
+	})
+	s.Run("pod level issues", func() {
+		gated := pending.DeepCopy()
+		gated.Spec.SchedulingGates = []v1.PodSchedulingGate{{Name: "example.com/quota"}}
+		gated.Spec.SchedulerName = "custom-scheduler"
+		gated.Spec.TopologySpreadConstraints = []v1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}}
+		scheduling := AnalyzePodScheduling(gated, nil, nil)
+		s.Require().Len(scheduling.Issues, 3)
+		s.Contains(scheduling.Issues[0], "scheduling gates example.com/quota")
+		s.Contains(scheduling.Issues[1], "custom scheduler custom-scheduler")
+		s.Contains(scheduling.Issues[2], "topology spread constraints")
+	})
+	s.Run("scheduled pod", func() {
+		scheduled := drainTestPod("running", "node-1", "ReplicaSet", "web")
+		scheduling := AnalyzePodScheduling(&scheduled, nil, nil)
+		s.Equal("Pod running is not pending, it's scheduled on node node-1 (phase Running)", scheduling.Summary)
+		s.Empty(scheduling.RuledOutNodes)
+	})
+}
+
+func TestPodsScheduling(t *testing.T) {
+	suite.Run(t, new(PodsSchedulingSuite))
+}
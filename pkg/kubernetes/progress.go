@@ -0,0 +1,26 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProgressReporter receives the stages of long-running operations (e.g. "waiting for helper pod X to complete"),
+// implementations must be safe for concurrent use since fan-out operations report from several goroutines.
+type ProgressReporter interface {
+	Report(message string)
+}
+
+type progressReporterKey struct{}
+
+// WithProgressReporter returns a context that makes the operations performed with it report their stages to reporter
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+// ReportProgress reports a stage of the current operation, it's a no-op if the context has no ProgressReporter
+func ReportProgress(ctx context.Context, format string, args ...any) {
+	if reporter, ok := ctx.Value(progressReporterKey{}).(ProgressReporter); ok {
+		reporter.Report(fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,41 @@
+package kubernetes
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	projectGVK        = schema.GroupVersionKind{Group: "project.openshift.io", Version: "v1", Kind: "Project"}
+	projectRequestGVK = schema.GroupVersionKind{Group: "project.openshift.io", Version: "v1", Kind: "ProjectRequest"}
+)
+
+// ProjectsCreate requests a new OpenShift project, the project is created using the cluster project template
+// so that the requester gets the admin role in the new project (regular users can't create namespaces)
+func (k *Kubernetes) ProjectsCreate(ctx context.Context, name, displayName, description string) (*unstructured.Unstructured, error) {
+	gvr, err := k.resourceFor(&projectRequestGVK)
+	if err != nil {
+		return nil, err
+	}
+	projectRequest := &unstructured.Unstructured{Object: map[string]any{}}
+	projectRequest.SetGroupVersionKind(projectRequestGVK)
+	projectRequest.SetName(name)
+	if displayName != "" {
+		projectRequest.Object["displayName"] = displayName
+	}
+	if description != "" {
+		projectRequest.Object["description"] = description
+	}
+	return k.AccessControlClientset().DynamicClient().Resource(*gvr).Create(ctx, projectRequest, metav1.CreateOptions{DryRun: dryRun(ctx)})
+}
+
+func (k *Kubernetes) ProjectsDelete(ctx context.Context, name string) error {
+	gvr, err := k.resourceFor(&projectGVK)
+	if err != nil {
+		return err
+	}
+	return k.AccessControlClientset().DynamicClient().Resource(*gvr).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+}
@@ -21,6 +21,8 @@ type Provider interface {
 	GetDerivedKubernetes(ctx context.Context, target string) (*Kubernetes, error)
 	GetDefaultTarget() string
 	GetTargetParameterName() string
+	// Ready returns an error if the API server of the default target is not ready (readiness probe of the server)
+	Ready(ctx context.Context) error
 	// WatchTargets sets up a watcher for changes in the cluster targets and calls the provided McpReload function when changes are detected
 	WatchTargets(reload McpReload)
 	Close()
@@ -48,7 +48,7 @@ func (p *kubeConfigClusterProvider) reset() error {
 	m, err := NewKubeconfigManager(p.staticConfig, "")
 	if err != nil {
 		if errors.Is(err, ErrorKubeconfigInClusterNotAllowed) {
-			return fmt.Errorf("kubeconfig ClusterProviderStrategy is invalid for in-cluster deployments: %v", err)
+			return fmt.Errorf("kubeconfig ClusterProviderStrategy is invalid for in-cluster deployments: %w", err)
 		}
 		return err
 	}
@@ -58,6 +58,7 @@ func (p *kubeConfigClusterProvider) reset() error {
 		return err
 	}
 
+	p.Close()
 	p.managers = map[string]*Manager{
 		rawConfig.CurrentContext: m, // we already initialized a manager for the default context, let's use it
 	}
@@ -69,7 +70,6 @@ func (p *kubeConfigClusterProvider) reset() error {
 		p.managers[name] = nil
 	}
 
-	p.Close()
 	p.kubeconfigWatcher = watcher.NewKubeconfig(m.accessControlClientset.clientCmdConfig)
 	p.clusterStateWatcher = watcher.NewClusterState(m.accessControlClientset.DiscoveryClient())
 	p.defaultContext = rawConfig.CurrentContext
@@ -132,6 +132,10 @@ func (p *kubeConfigClusterProvider) GetDefaultTarget() string {
 	return p.defaultContext
 }
 
+func (p *kubeConfigClusterProvider) Ready(ctx context.Context) error {
+	return p.managers[p.defaultContext].Ready(ctx)
+}
+
 func (p *kubeConfigClusterProvider) WatchTargets(reload McpReload) {
 	reloadWithReset := func() error {
 		if err := p.reset(); err != nil {
@@ -150,4 +154,9 @@ func (p *kubeConfigClusterProvider) Close() {
 			w.Close()
 		}
 	}
+	for _, m := range p.managers {
+		if m != nil {
+			m.Close()
+		}
+	}
 }
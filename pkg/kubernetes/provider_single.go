@@ -51,21 +51,23 @@ func (p *singleClusterProvider) reset() error {
 			p.staticConfig.KubeConfig)
 	}
 
+	var m *Manager
 	var err error
 	if p.strategy == config.ClusterProviderInCluster || IsInCluster(p.staticConfig) {
-		p.manager, err = NewInClusterManager(p.staticConfig)
+		m, err = NewInClusterManager(p.staticConfig)
 	} else {
-		p.manager, err = NewKubeconfigManager(p.staticConfig, "")
+		m, err = NewKubeconfigManager(p.staticConfig, "")
 	}
 	if err != nil {
 		if errors.Is(err, ErrorInClusterNotInCluster) {
-			return fmt.Errorf("server must be deployed in cluster for the %s ClusterProviderStrategy: %v",
+			return fmt.Errorf("server must be deployed in cluster for the %s ClusterProviderStrategy: %w",
 				p.strategy, err)
 		}
 		return err
 	}
 
 	p.Close()
+	p.manager = m
 	p.kubeconfigWatcher = watcher.NewKubeconfig(p.manager.accessControlClientset.clientCmdConfig)
 	p.clusterStateWatcher = watcher.NewClusterState(p.manager.accessControlClientset.DiscoveryClient())
 	return nil
@@ -98,6 +100,10 @@ func (p *singleClusterProvider) GetDefaultTarget() string {
 	return ""
 }
 
+func (p *singleClusterProvider) Ready(ctx context.Context) error {
+	return p.manager.Ready(ctx)
+}
+
 func (p *singleClusterProvider) GetTargetParameterName() string {
 	return ""
 }
@@ -120,4 +126,7 @@ func (p *singleClusterProvider) Close() {
 			w.Close()
 		}
 	}
+	if p.manager != nil {
+		p.manager.Close()
+	}
 }
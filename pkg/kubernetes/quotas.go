@@ -0,0 +1,607 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// quotaMaxDenials is the maximum number of recent quota and LimitRange denials reported by QuotasStatus
+const quotaMaxDenials = 20
+
+var (
+	// quotaExceededRegexp matches the admission errors of the requests exceeding a ResourceQuota, e.g.
+	// pods "web-1" is forbidden: exceeded quota: compute, requested: requests.cpu=500m, used: requests.cpu=2, limited: requests.cpu=2
+	quotaExceededRegexp = regexp.MustCompile(`exceeded quota: ([^,\s]+)`)
+	// quotaFailedRegexp matches the admission errors of the Pods not specifying a resource tracked by a ResourceQuota, e.g.
+	// pods "web-1" is forbidden: failed quota: compute: must specify limits.cpu for: app
+	quotaFailedRegexp = regexp.MustCompile(`failed quota: ([^:\s]+):`)
+	// limitRangeRegexp matches the admission errors of the Pods violating a LimitRange, e.g.
+	// pods "web-1" is forbidden: maximum cpu usage per Container is 1, but limit is 2
+	limitRangeRegexp = regexp.MustCompile(`(maximum|minimum) \S+ usage per (Container|Pod|PersistentVolumeClaim)|limit to request ratio per`)
+)
+
+// QuotasStatus reports the ResourceQuotas and LimitRanges of a namespace (or all namespaces), the quota and LimitRange
+// denials of the recent create requests, and optionally whether a Pod like the provided one is admitted
+type QuotasStatus struct {
+	// Summary is a one line description, e.g. "2 ResourceQuotas and 1 LimitRanges in namespace shop, 1 exhausted: compute (requests.cpu)"
+	Summary     string             `json:"summary"`
+	Quotas      []QuotaStatus      `json:"quotas"`
+	LimitRanges []LimitRangeStatus `json:"limitRanges,omitempty"`
+	// Pod is the admission check of a new Pod with the resources of the provided Pod
+	Pod *QuotaPodCheck `json:"pod,omitempty"`
+	// Denials are the most recent events of the create requests rejected by a ResourceQuota or a LimitRange, newest first
+	Denials  []QuotaDenial `json:"denials,omitempty"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+type QuotaStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Scopes are the scopes and scope selectors restricting the objects tracked by the quota, e.g. NotTerminating, PriorityClass In (high)
+	Scopes    []string        `json:"scopes,omitempty"`
+	Resources []QuotaResource `json:"resources"`
+}
+
+type QuotaResource struct {
+	Name        string  `json:"name"`
+	Used        string  `json:"used"`
+	Hard        string  `json:"hard"`
+	UsedPercent float64 `json:"usedPercent"`
+	// Exhausted is true when the used value reached the hard limit, any further request of the resource is rejected
+	Exhausted bool `json:"exhausted,omitempty"`
+}
+
+type LimitRangeStatus struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Limits    []LimitRangeLimit `json:"limits"`
+}
+
+type LimitRangeLimit struct {
+	// Type is Container, Pod, or PersistentVolumeClaim
+	Type                 string            `json:"type"`
+	Min                  map[string]string `json:"min,omitempty"`
+	Max                  map[string]string `json:"max,omitempty"`
+	Default              map[string]string `json:"default,omitempty"`
+	DefaultRequest       map[string]string `json:"defaultRequest,omitempty"`
+	MaxLimitRequestRatio map[string]string `json:"maxLimitRequestRatio,omitempty"`
+}
+
+// QuotaPodCheck evaluates the admission of a new Pod with the resources of an existing Pod (e.g. another replica)
+// against the ResourceQuotas and LimitRanges of its namespace
+type QuotaPodCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Usage is the quota usage of the Pod, once the LimitRange defaults applied
+	Usage map[string]string `json:"usage,omitempty"`
+	// Admitted is false when a ResourceQuota or a LimitRange rejects the Pod
+	Admitted bool `json:"admitted"`
+	// BlockedBy are the ResourceQuota resources whose remaining amount is lower than the Pod usage
+	BlockedBy []QuotaBlock `json:"blockedBy,omitempty"`
+	// Issues are the resources tracked by a quota the Pod doesn't specify and the LimitRange violations
+	Issues []string `json:"issues,omitempty"`
+}
+
+type QuotaBlock struct {
+	Quota     string `json:"quota"`
+	Resource  string `json:"resource"`
+	Requested string `json:"requested"`
+	Used      string `json:"used"`
+	Hard      string `json:"hard"`
+}
+
+type QuotaDenial struct {
+	WorkloadEvent
+	// Kind is ResourceQuota or LimitRange
+	Kind string `json:"kind"`
+	// Quota is the name of the ResourceQuota that rejected the request
+	Quota string `json:"quota,omitempty"`
+}
+
+type QuotasStatusOptions struct {
+	// Namespace of the quotas, all namespaces if empty and AllNamespaces is set
+	Namespace     string
+	AllNamespaces bool
+	// Pod is the name of the Pod whose resources are checked against the remaining quota (Optional)
+	Pod string
+	// Object is the Kind/name of the object whose create requests failed, e.g. ReplicaSet/web-5d9c (Optional, all the denials if empty)
+	Object string
+}
+
+// QuotasStatus returns the ResourceQuotas and LimitRanges of the namespace with the recent denials, and checks the
+// admission of a Pod like the provided one.
+// Failures retrieving anything but the quotas don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) QuotasStatus(ctx context.Context, options QuotasStatusOptions) (*QuotasStatus, error) {
+	namespace := ""
+	if !options.AllNamespaces || options.Pod != "" {
+		namespace = k.NamespaceOrDefault(options.Namespace)
+	}
+	core := k.AccessControlClientset().CoreV1()
+	quotas, err := core.ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	status := &QuotasStatus{Quotas: QuotaStatuses(quotas.Items)}
+	var limitRanges []v1.LimitRange
+	if list, err := core.LimitRanges(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("unable to list the LimitRanges: %v", err))
+	} else {
+		limitRanges = list.Items
+		status.LimitRanges = LimitRangeStatuses(limitRanges)
+	}
+	if options.Pod != "" {
+		pod, err := core.Pods(namespace).Get(ctx, options.Pod, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s: %w", options.Pod, err)
+		}
+		status.Pod = CheckPodQuotas(pod, quotas.Items, limitRanges)
+	}
+	if events, err := core.Events(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("unable to list the events: %v", err))
+	} else {
+		status.Denials = QuotaDenials(events.Items, options.Object, quotaMaxDenials)
+	}
+	status.Summary = status.summary(namespace)
+	return status, nil
+}
+
+func (s *QuotasStatus) summary(namespace string) string {
+	scope := "in all namespaces"
+	if namespace != "" {
+		scope = "in namespace " + namespace
+	}
+	summary := fmt.Sprintf("%d ResourceQuotas and %d LimitRanges %s", len(s.Quotas), len(s.LimitRanges), scope)
+	var exhausted []string
+	for _, quota := range s.Quotas {
+		var resources []string
+		for _, r := range quota.Resources {
+			if r.Exhausted {
+				resources = append(resources, r.Name)
+			}
+		}
+		if len(resources) > 0 {
+			exhausted = append(exhausted, fmt.Sprintf("%s (%s)", quota.Name, strings.Join(resources, ", ")))
+		}
+	}
+	if len(exhausted) > 0 {
+		summary += fmt.Sprintf(", %d exhausted: %s", len(exhausted), strings.Join(exhausted, ", "))
+	}
+	if s.Pod != nil {
+		if s.Pod.Admitted {
+			summary += fmt.Sprintf(", a Pod like %s is admitted", s.Pod.Name)
+		} else {
+			var quotas []string
+			for _, block := range s.Pod.BlockedBy {
+				quotas = append(quotas, fmt.Sprintf("%s (%s)", block.Quota, block.Resource))
+			}
+			if len(quotas) > 0 {
+				summary += fmt.Sprintf(", a Pod like %s is blocked by %s", s.Pod.Name, strings.Join(quotas, ", "))
+			} else {
+				summary += fmt.Sprintf(", a Pod like %s is rejected: %s", s.Pod.Name, s.Pod.Issues[0])
+			}
+		}
+	}
+	if len(s.Denials) > 0 {
+		summary += fmt.Sprintf(", %d recent denials", len(s.Denials))
+	}
+	return summary
+}
+
+// QuotaStatuses returns the used and hard values of the ResourceQuotas, sorted by namespace and name
+func QuotaStatuses(quotas []v1.ResourceQuota) []QuotaStatus {
+	result := make([]QuotaStatus, 0, len(quotas))
+	for _, quota := range quotas {
+		status := QuotaStatus{Namespace: quota.Namespace, Name: quota.Name, Scopes: quotaScopes(&quota), Resources: make([]QuotaResource, 0, len(quota.Status.Hard))}
+		hard := quota.Status.Hard
+		if len(hard) == 0 {
+			// The quota controller didn't process the quota yet
+			hard = quota.Spec.Hard
+		}
+		for name, limit := range hard {
+			used := quota.Status.Used[name]
+			r := QuotaResource{Name: string(name), Used: used.String(), Hard: limit.String(), Exhausted: used.Cmp(limit) >= 0}
+			if limit.MilliValue() > 0 {
+				r.UsedPercent = float64(used.MilliValue()*1000/limit.MilliValue()) / 10
+			} else {
+				r.UsedPercent = 100
+			}
+			status.Resources = append(status.Resources, r)
+		}
+		sort.Slice(status.Resources, func(i, j int) bool { return status.Resources[i].Name < status.Resources[j].Name })
+		result = append(result, status)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func quotaScopes(quota *v1.ResourceQuota) []string {
+	scopes := make([]string, 0)
+	for _, scope := range quota.Spec.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	if quota.Spec.ScopeSelector != nil {
+		for _, expression := range quota.Spec.ScopeSelector.MatchExpressions {
+			scope := fmt.Sprintf("%s %s", expression.ScopeName, expression.Operator)
+			if len(expression.Values) > 0 {
+				scope += " (" + strings.Join(expression.Values, ", ") + ")"
+			}
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil
+	}
+	return scopes
+}
+
+// LimitRangeStatuses returns the limits of the LimitRanges, sorted by namespace and name
+func LimitRangeStatuses(limitRanges []v1.LimitRange) []LimitRangeStatus {
+	result := make([]LimitRangeStatus, 0, len(limitRanges))
+	for _, limitRange := range limitRanges {
+		status := LimitRangeStatus{Namespace: limitRange.Namespace, Name: limitRange.Name, Limits: make([]LimitRangeLimit, 0, len(limitRange.Spec.Limits))}
+		for _, item := range limitRange.Spec.Limits {
+			status.Limits = append(status.Limits, LimitRangeLimit{
+				Type:                 string(item.Type),
+				Min:                  resourceListStrings(item.Min),
+				Max:                  resourceListStrings(item.Max),
+				Default:              resourceListStrings(item.Default),
+				DefaultRequest:       resourceListStrings(item.DefaultRequest),
+				MaxLimitRequestRatio: resourceListStrings(item.MaxLimitRequestRatio),
+			})
+		}
+		result = append(result, status)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// CheckPodQuotas evaluates the admission of a new Pod with the resources of the provided Pod: the LimitRange defaults
+// are applied to its containers as the LimitRanger admission plugin does, then its usage is compared with the remaining
+// amount of each ResourceQuota of its scope, and the LimitRange minimums and maximums are checked
+func CheckPodQuotas(pod *v1.Pod, quotas []v1.ResourceQuota, limitRanges []v1.LimitRange) *QuotaPodCheck {
+	check := &QuotaPodCheck{Name: pod.Name, Status: string(pod.Status.Phase), Admitted: true}
+	pod = podWithLimitRangeDefaults(pod, limitRanges)
+	usage := podQuotaUsage(pod)
+	check.Usage = resourceListStrings(usage)
+	for _, quota := range quotas {
+		if !quotaMatchesPod(&quota, pod) {
+			continue
+		}
+		hard := quota.Status.Hard
+		if len(hard) == 0 {
+			hard = quota.Spec.Hard
+		}
+		names := make([]string, 0, len(hard))
+		for name := range hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			limit := hard[v1.ResourceName(name)]
+			requested, tracked := usage[v1.ResourceName(name)]
+			if !tracked {
+				if missing := podContainersMissing(pod, v1.ResourceName(name)); len(missing) > 0 {
+					check.Issues = append(check.Issues, fmt.Sprintf("ResourceQuota %s requires %s to be specified for the containers: %s",
+						quota.Name, name, strings.Join(missing, ", ")))
+				}
+				continue
+			}
+			used := quota.Status.Used[v1.ResourceName(name)]
+			total := used.DeepCopy()
+			total.Add(requested)
+			if total.Cmp(limit) > 0 {
+				check.BlockedBy = append(check.BlockedBy, QuotaBlock{
+					Quota: quota.Name, Resource: name, Requested: requested.String(), Used: used.String(), Hard: limit.String(),
+				})
+			}
+		}
+	}
+	check.Issues = append(check.Issues, limitRangeViolations(pod, limitRanges)...)
+	check.Admitted = len(check.BlockedBy) == 0 && len(check.Issues) == 0
+	return check
+}
+
+// podWithLimitRangeDefaults returns a copy of the Pod with the default requests and limits of the Container
+// LimitRanges applied to the containers that don't specify them, as the LimitRanger admission plugin does
+func podWithLimitRangeDefaults(pod *v1.Pod, limitRanges []v1.LimitRange) *v1.Pod {
+	pod = pod.DeepCopy()
+	apply := func(containers []v1.Container) {
+		for i := range containers {
+			resources := &containers[i].Resources
+			for _, limitRange := range limitRanges {
+				for _, item := range limitRange.Spec.Limits {
+					if item.Type != v1.LimitTypeContainer {
+						continue
+					}
+					for name, value := range item.Default {
+						if _, ok := resources.Limits[name]; !ok {
+							if resources.Limits == nil {
+								resources.Limits = v1.ResourceList{}
+							}
+							resources.Limits[name] = value.DeepCopy()
+						}
+					}
+					for name, value := range item.DefaultRequest {
+						if _, ok := resources.Requests[name]; !ok {
+							if resources.Requests == nil {
+								resources.Requests = v1.ResourceList{}
+							}
+							resources.Requests[name] = value.DeepCopy()
+						}
+					}
+				}
+			}
+			// Requests default to the limits when not specified
+			for name, value := range resources.Limits {
+				if _, ok := resources.Requests[name]; !ok {
+					if resources.Requests == nil {
+						resources.Requests = v1.ResourceList{}
+					}
+					resources.Requests[name] = value.DeepCopy()
+				}
+			}
+		}
+	}
+	apply(pod.Spec.InitContainers)
+	apply(pod.Spec.Containers)
+	return pod
+}
+
+// podQuotaUsage returns the usage of the Pod as computed by the ResourceQuota admission plugin
+func podQuotaUsage(pod *v1.Pod) v1.ResourceList {
+	one := resource.MustParse("1")
+	usage := v1.ResourceList{v1.ResourcePods: one.DeepCopy(), "count/pods": one.DeepCopy()}
+	requests := podRequests(pod)
+	limits := podLimits(pod)
+	for name, value := range requests {
+		usage[v1.ResourceName("requests."+string(name))] = value.DeepCopy()
+		if name == v1.ResourceCPU || name == v1.ResourceMemory || name == v1.ResourceEphemeralStorage || strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix) {
+			usage[name] = value.DeepCopy()
+		}
+	}
+	for name, value := range limits {
+		usage[v1.ResourceName("limits."+string(name))] = value.DeepCopy()
+	}
+	return usage
+}
+
+// podLimits returns the effective resource limits of the pod, computed as podRequests does
+func podLimits(pod *v1.Pod) v1.ResourceList {
+	limits := v1.ResourceList{}
+	sidecars := v1.ResourceList{}
+	for _, c := range pod.Spec.InitContainers {
+		if isNativeSidecar(c) {
+			sidecars = addResourceList(sidecars, c.Resources.Limits)
+			continue
+		}
+		init := addResourceList(v1.ResourceList{}, sidecars)
+		init = addResourceList(init, c.Resources.Limits)
+		maxResourceList(limits, init)
+	}
+	containers := addResourceList(v1.ResourceList{}, sidecars)
+	for _, c := range pod.Spec.Containers {
+		containers = addResourceList(containers, c.Resources.Limits)
+	}
+	maxResourceList(limits, containers)
+	return addResourceList(limits, pod.Spec.Overhead)
+}
+
+// podContainersMissing returns the containers that don't specify the compute resource tracked by a quota,
+// the ResourceQuota admission plugin rejects such Pods
+func podContainersMissing(pod *v1.Pod, name v1.ResourceName) []string {
+	var resources func(c v1.Container) v1.ResourceList
+	var resourceName v1.ResourceName
+	switch {
+	case strings.HasPrefix(string(name), "limits."):
+		resources, resourceName = func(c v1.Container) v1.ResourceList { return c.Resources.Limits }, v1.ResourceName(strings.TrimPrefix(string(name), "limits."))
+	case strings.HasPrefix(string(name), "requests."):
+		resources, resourceName = func(c v1.Container) v1.ResourceList { return c.Resources.Requests }, v1.ResourceName(strings.TrimPrefix(string(name), "requests."))
+	case name == v1.ResourceCPU || name == v1.ResourceMemory:
+		resources, resourceName = func(c v1.Container) v1.ResourceList { return c.Resources.Requests }, name
+	default:
+		return nil
+	}
+	// Only cpu and memory are required to be specified, the other resources are optional
+	if resourceName != v1.ResourceCPU && resourceName != v1.ResourceMemory {
+		return nil
+	}
+	var missing []string
+	for _, c := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		if _, ok := resources(c)[resourceName]; !ok {
+			missing = append(missing, c.Name)
+		}
+	}
+	return missing
+}
+
+// quotaMatchesPod returns true if the Pod is tracked by the quota according to its scopes and scope selector
+func quotaMatchesPod(quota *v1.ResourceQuota, pod *v1.Pod) bool {
+	for _, scope := range quota.Spec.Scopes {
+		if !quotaScopeMatchesPod(v1.ScopedResourceSelectorRequirement{ScopeName: scope, Operator: v1.ScopeSelectorOpExists}, pod) {
+			return false
+		}
+	}
+	if quota.Spec.ScopeSelector != nil {
+		for _, expression := range quota.Spec.ScopeSelector.MatchExpressions {
+			if !quotaScopeMatchesPod(expression, pod) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func quotaScopeMatchesPod(expression v1.ScopedResourceSelectorRequirement, pod *v1.Pod) bool {
+	switch expression.ScopeName {
+	case v1.ResourceQuotaScopeTerminating:
+		return pod.Spec.ActiveDeadlineSeconds != nil
+	case v1.ResourceQuotaScopeNotTerminating:
+		return pod.Spec.ActiveDeadlineSeconds == nil
+	case v1.ResourceQuotaScopeBestEffort:
+		return podIsBestEffort(pod)
+	case v1.ResourceQuotaScopeNotBestEffort:
+		return !podIsBestEffort(pod)
+	case v1.ResourceQuotaScopePriorityClass:
+		operator := map[v1.ScopeSelectorOperator]selection.Operator{
+			v1.ScopeSelectorOpIn:           selection.In,
+			v1.ScopeSelectorOpNotIn:        selection.NotIn,
+			v1.ScopeSelectorOpExists:       selection.Exists,
+			v1.ScopeSelectorOpDoesNotExist: selection.DoesNotExist,
+		}[expression.Operator]
+		requirement, err := labels.NewRequirement(string(v1.ResourceQuotaScopePriorityClass), operator, expression.Values)
+		if err != nil {
+			return false
+		}
+		return requirement.Matches(labels.Set{string(v1.ResourceQuotaScopePriorityClass): pod.Spec.PriorityClassName})
+	case v1.ResourceQuotaScopeCrossNamespacePodAffinity:
+		return podHasCrossNamespaceAffinity(pod)
+	}
+	return false
+}
+
+func podIsBestEffort(pod *v1.Pod) bool {
+	for _, c := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		for _, list := range []v1.ResourceList{c.Resources.Requests, c.Resources.Limits} {
+			if _, ok := list[v1.ResourceCPU]; ok {
+				return false
+			}
+			if _, ok := list[v1.ResourceMemory]; ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func podHasCrossNamespaceAffinity(pod *v1.Pod) bool {
+	if pod.Spec.Affinity == nil {
+		return false
+	}
+	var terms []v1.PodAffinityTerm
+	if a := pod.Spec.Affinity.PodAffinity; a != nil {
+		terms = append(terms, a.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, weighted := range a.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weighted.PodAffinityTerm)
+		}
+	}
+	if a := pod.Spec.Affinity.PodAntiAffinity; a != nil {
+		terms = append(terms, a.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, weighted := range a.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weighted.PodAffinityTerm)
+		}
+	}
+	for _, term := range terms {
+		if len(term.Namespaces) > 0 || term.NamespaceSelector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// limitRangeViolations returns the minimums and maximums of the Container and Pod LimitRanges the Pod violates
+func limitRangeViolations(pod *v1.Pod, limitRanges []v1.LimitRange) []string {
+	var violations []string
+	check := func(limitRange, subject string, limitType v1.LimitType, requests, limits v1.ResourceList, item v1.LimitRangeItem) {
+		for _, name := range sortedResourceNames(item.Min) {
+			minimum := item.Min[name]
+			if request, ok := requests[name]; ok && request.Cmp(minimum) < 0 {
+				violations = append(violations, fmt.Sprintf("LimitRange %s: minimum %s usage per %s is %s, but %s requests %s",
+					limitRange, name, limitType, minimum.String(), subject, request.String()))
+			}
+		}
+		for _, name := range sortedResourceNames(item.Max) {
+			maximum := item.Max[name]
+			if limit, ok := limits[name]; !ok {
+				violations = append(violations, fmt.Sprintf("LimitRange %s: maximum %s usage per %s is %s, but %s has no limit",
+					limitRange, name, limitType, maximum.String(), subject))
+			} else if limit.Cmp(maximum) > 0 {
+				violations = append(violations, fmt.Sprintf("LimitRange %s: maximum %s usage per %s is %s, but %s limit is %s",
+					limitRange, name, limitType, maximum.String(), subject, limit.String()))
+			}
+		}
+	}
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			switch item.Type {
+			case v1.LimitTypeContainer:
+				for _, c := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+					check(limitRange.Name, "container "+c.Name, item.Type, c.Resources.Requests, c.Resources.Limits, item)
+				}
+			case v1.LimitTypePod:
+				check(limitRange.Name, "the Pod", item.Type, podRequests(pod), podLimits(pod), item)
+			}
+		}
+	}
+	return violations
+}
+
+// QuotaDenials returns the most recent events of the create requests rejected by a ResourceQuota or a LimitRange,
+// newest first. When object (Kind/name) is provided, only its events are returned, the events of the ReplicaSets
+// of a Deployment are matched by their name prefix.
+func QuotaDenials(events []v1.Event, object string, limit int) []QuotaDenial {
+	kind, name, _ := strings.Cut(object, "/")
+	matching := make([]*v1.Event, 0)
+	for i := range events {
+		event := &events[i]
+		if object != "" {
+			involved := event.InvolvedObject
+			matches := strings.EqualFold(involved.Kind, kind) && involved.Name == name
+			if strings.EqualFold(kind, WorkloadKindDeployment) && involved.Kind == WorkloadKindReplicaSet && strings.HasPrefix(involved.Name, name+"-") {
+				matches = true
+			}
+			if !matches {
+				continue
+			}
+		}
+		if quotaExceededRegexp.MatchString(event.Message) || quotaFailedRegexp.MatchString(event.Message) || limitRangeRegexp.MatchString(event.Message) {
+			matching = append(matching, event)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return eventTimestamp(matching[i]).After(eventTimestamp(matching[j]))
+	})
+	if len(matching) > limit {
+		matching = matching[:limit]
+	}
+	result := make([]QuotaDenial, 0, len(matching))
+	for _, event := range matching {
+		denial := QuotaDenial{
+			WorkloadEvent: WorkloadEvent{
+				Timestamp: eventTimestamp(event).UTC().Format(time.RFC3339),
+				Type:      event.Type,
+				Reason:    event.Reason,
+				Object:    event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+				Message:   strings.TrimSpace(event.Message),
+				Count:     event.Count,
+			},
+			Kind: "LimitRange",
+		}
+		if match := quotaExceededRegexp.FindStringSubmatch(event.Message); match != nil {
+			denial.Kind, denial.Quota = "ResourceQuota", match[1]
+		} else if match = quotaFailedRegexp.FindStringSubmatch(event.Message); match != nil {
+			denial.Kind, denial.Quota = "ResourceQuota", match[1]
+		}
+		result = append(result, denial)
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type QuotasSuite struct {
+	suite.Suite
+}
+
+func quotasTestQuota(name string, hard, used v1.ResourceList, mutate func(q *v1.ResourceQuota)) v1.ResourceQuota {
+	q := v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Spec:       v1.ResourceQuotaSpec{Hard: hard},
+		Status:     v1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+	if mutate != nil {
+		mutate(&q)
+	}
+	return q
+}
+
+func quotasTestPod(requests, limits v1.ResourceList) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web-1"},
+		Spec: v1.PodSpec{Containers: []v1.Container{
+			{Name: "app", Resources: v1.ResourceRequirements{Requests: requests, Limits: limits}},
+		}},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func (s *QuotasSuite) TestQuotaStatuses() {
+	statuses := QuotaStatuses([]v1.ResourceQuota{
+		quotasTestQuota("objects", v1.ResourceList{"pods": resource.MustParse("10")}, v1.ResourceList{"pods": resource.MustParse("4")}, nil),
+		quotasTestQuota("compute",
+			v1.ResourceList{"requests.cpu": resource.MustParse("2"), "limits.memory": resource.MustParse("4Gi")},
+			v1.ResourceList{"requests.cpu": resource.MustParse("2"), "limits.memory": resource.MustParse("1Gi")},
+			func(q *v1.ResourceQuota) {
+				q.Spec.Scopes = []v1.ResourceQuotaScope{v1.ResourceQuotaScopeNotTerminating}
+				q.Spec.ScopeSelector = &v1.ScopeSelector{MatchExpressions: []v1.ScopedResourceSelectorRequirement{
+					{ScopeName: v1.ResourceQuotaScopePriorityClass, Operator: v1.ScopeSelectorOpIn, Values: []string{"high"}},
+				}}
+			}),
+	})
+	s.Require().Len(statuses, 2)
+	s.Run("sorts quotas by name", func() {
+		s.Equal("compute", statuses[0].Name)
+		s.Equal("objects", statuses[1].Name)
+	})
+	s.Run("reports scopes and scope selectors", func() {
+		s.Equal([]string{"NotTerminating", "PriorityClass In (high)"}, statuses[0].Scopes)
+		s.Nil(statuses[1].Scopes)
+	})
+	s.Run("reports used and hard values", func() {
+		s.Equal([]QuotaResource{
+			{Name: "limits.memory", Used: "1Gi", Hard: "4Gi", UsedPercent: 25},
+			{Name: "requests.cpu", Used: "2", Hard: "2", UsedPercent: 100, Exhausted: true},
+		}, statuses[0].Resources)
+		s.Equal([]QuotaResource{{Name: "pods", Used: "4", Hard: "10", UsedPercent: 40}}, statuses[1].Resources)
+	})
+	s.Run("summary reports the exhausted quotas", func() {
+		status := &QuotasStatus{Quotas: statuses}
+		s.Equal("2 ResourceQuotas and 0 LimitRanges in namespace shop, 1 exhausted: compute (requests.cpu)", status.summary("shop"))
+	})
+}
+
+func (s *QuotasSuite) TestCheckPodQuotas() {
+	compute := quotasTestQuota("compute",
+		v1.ResourceList{"requests.cpu": resource.MustParse("2"), "limits.cpu": resource.MustParse("4"), "pods": resource.MustParse("10")},
+		v1.ResourceList{"requests.cpu": resource.MustParse("1800m"), "limits.cpu": resource.MustParse("2"), "pods": resource.MustParse("3")},
+		nil)
+	s.Run("blocked by the quotas whose remaining amount is lower than the pod usage", func() {
+		check := CheckPodQuotas(quotasTestPod(
+			v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+			v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}), []v1.ResourceQuota{compute}, nil)
+		s.False(check.Admitted)
+		s.Equal([]QuotaBlock{{Quota: "compute", Resource: "requests.cpu", Requested: "500m", Used: "1800m", Hard: "2"}}, check.BlockedBy)
+		s.Equal("500m", check.Usage["requests.cpu"])
+		s.Equal("1", check.Usage["pods"])
+	})
+	s.Run("admitted within the remaining quota", func() {
+		check := CheckPodQuotas(quotasTestPod(
+			v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+			v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}), []v1.ResourceQuota{compute}, nil)
+		s.True(check.Admitted)
+		s.Empty(check.BlockedBy)
+		s.Empty(check.Issues)
+	})
+	s.Run("rejected when a tracked resource is not specified", func() {
+		check := CheckPodQuotas(quotasTestPod(v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")}, nil), []v1.ResourceQuota{compute}, nil)
+		s.False(check.Admitted)
+		s.Equal([]string{"ResourceQuota compute requires limits.cpu to be specified for the containers: app"}, check.Issues)
+	})
+	s.Run("applies the LimitRange defaults", func() {
+		limitRange := v1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "defaults"},
+			Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{{
+				Type:    v1.LimitTypeContainer,
+				Default: v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m")},
+			}}},
+		}
+		check := CheckPodQuotas(quotasTestPod(nil, nil), []v1.ResourceQuota{compute}, []v1.LimitRange{limitRange})
+		s.True(check.Admitted)
+		s.Equal("200m", check.Usage["limits.cpu"])
+		s.Equal("200m", check.Usage["requests.cpu"])
+	})
+	s.Run("ignores the quotas of other scopes", func() {
+		terminating := compute.DeepCopy()
+		terminating.Spec.Scopes = []v1.ResourceQuotaScope{v1.ResourceQuotaScopeTerminating}
+		check := CheckPodQuotas(quotasTestPod(v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}, nil), []v1.ResourceQuota{*terminating}, nil)
+		s.True(check.Admitted)
+		pod := quotasTestPod(v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}, nil)
+		pod.Spec.ActiveDeadlineSeconds = ptr.To(int64(60))
+		s.False(CheckPodQuotas(pod, []v1.ResourceQuota{*terminating}, nil).Admitted)
+	})
+	s.Run("reports the LimitRange violations", func() {
+		limitRange := v1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "bounds"},
+			Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{{
+				Type: v1.LimitTypeContainer,
+				Min:  v1.ResourceList{v1.ResourceMemory: resource.MustParse("64Mi")},
+				Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			}}},
+		}
+		check := CheckPodQuotas(quotasTestPod(
+			v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Mi")},
+			v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")}), nil, []v1.LimitRange{limitRange})
+		s.False(check.Admitted)
+		s.Equal([]string{
+			"LimitRange bounds: minimum memory usage per Container is 64Mi, but container app requests 32Mi",
+			"LimitRange bounds: maximum cpu usage per Container is 1, but container app limit is 2",
+		}, check.Issues)
+	})
+}
+
+func (s *QuotasSuite) TestQuotaDenials() {
+	events := []v1.Event{
+		{
+			InvolvedObject: v1.ObjectReference{Kind: "ReplicaSet", Name: "web-5d9c"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedCreate",
+			Message:        `Error creating: pods "web-5d9c-x" is forbidden: exceeded quota: compute, requested: requests.cpu=500m, used: requests.cpu=2, limited: requests.cpu=2`,
+			FirstTimestamp: metav1.Unix(1735725600, 0),
+		},
+		{
+			InvolvedObject: v1.ObjectReference{Kind: "Job", Name: "backup"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedCreate",
+			Message:        `Error creating: pods "backup-x" is forbidden: failed quota: compute: must specify limits.cpu for: backup`,
+			FirstTimestamp: metav1.Unix(1735729200, 0),
+		},
+		{
+			InvolvedObject: v1.ObjectReference{Kind: "ReplicaSet", Name: "api-7f8b"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedCreate",
+			Message:        `Error creating: pods "api-7f8b-x" is forbidden: maximum cpu usage per Container is 1, but limit is 2`,
+			FirstTimestamp: metav1.Unix(1735722000, 0),
+		},
+		{
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedScheduling",
+			Message:        "0/3 nodes are available: 3 Insufficient cpu",
+			FirstTimestamp: metav1.Unix(1735729200, 0),
+		},
+	}
+	s.Run("returns the quota and LimitRange denials newest first", func() {
+		denials := QuotaDenials(events, "", 10)
+		s.Require().Len(denials, 3)
+		s.Equal("Job/backup", denials[0].Object)
+		s.Equal("ResourceQuota", denials[0].Kind)
+		s.Equal("compute", denials[0].Quota)
+		s.Equal("ReplicaSet/web-5d9c", denials[1].Object)
+		s.Equal("compute", denials[1].Quota)
+		s.Equal("LimitRange", denials[2].Kind)
+		s.Empty(denials[2].Quota)
+	})
+	s.Run("filters by object", func() {
+		denials := QuotaDenials(events, "Job/backup", 10)
+		s.Require().Len(denials, 1)
+		s.Equal("Job/backup", denials[0].Object)
+	})
+	s.Run("deployments match their replicasets", func() {
+		denials := QuotaDenials(events, "Deployment/web", 10)
+		s.Require().Len(denials, 1)
+		s.Equal("ReplicaSet/web-5d9c", denials[0].Object)
+	})
+	s.Run("limits the number of denials", func() {
+		s.Len(QuotaDenials(events, "", 1), 1)
+	})
+}
+
+func TestQuotas(t *testing.T) {
+	suite.Run(t, new(QuotasSuite))
+}
@@ -0,0 +1,93 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	authv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/rbac"
+)
+
+// RbacDecision is the decision of the authorizers for a subject and a resource request
+type RbacDecision struct {
+	// Subject is the reviewed subject, empty for the current user
+	Subject string `json:"subject,omitempty"`
+	Request string `json:"request"`
+	Allowed bool   `json:"allowed"`
+	Denied  bool   `json:"denied,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// RbacCanI reviews the resource request for the subject with a SubjectAccessReview, or for the current user with a
+// SelfSubjectAccessReview if the subject is nil.
+// The review is performed by the authorizers of the API server and not limited to RBAC.
+func (k *Kubernetes) RbacCanI(ctx context.Context, subject *rbacv1.Subject, request rbac.ResourceRequest) (*RbacDecision, error) {
+	attributes := &authv1.ResourceAttributes{
+		Namespace:   request.Namespace,
+		Verb:        request.Verb,
+		Group:       request.Group,
+		Resource:    request.Resource,
+		Subresource: request.Subresource,
+		Name:        request.Name,
+	}
+	decision := &RbacDecision{Request: request.String()}
+	var status authv1.SubjectAccessReviewStatus
+	if subject == nil {
+		review, err := k.AccessControlClientset().AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authv1.SelfSubjectAccessReview{
+			Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		status = review.Status
+	} else {
+		spec := authv1.SubjectAccessReviewSpec{ResourceAttributes: attributes}
+		switch subject.Kind {
+		case rbac.KindUser:
+			spec.User = subject.Name
+		case rbac.KindGroup:
+			spec.Groups = []string{subject.Name}
+		case rbac.KindServiceAccount:
+			spec.User = fmt.Sprintf("system:serviceaccount:%s:%s", subject.Namespace, subject.Name)
+			spec.Groups = []string{"system:serviceaccounts", "system:serviceaccounts:" + subject.Namespace, "system:authenticated"}
+		}
+		review, err := k.AccessControlClientset().AuthorizationV1().SubjectAccessReviews().Create(ctx, &authv1.SubjectAccessReview{
+			Spec: spec,
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		status = review.Status
+		decision.Subject = rbac.FormatSubject(*subject)
+	}
+	decision.Allowed, decision.Denied, decision.Reason = status.Allowed, status.Denied, status.Reason
+	if status.EvaluationError != "" && decision.Reason == "" {
+		decision.Reason = status.EvaluationError
+	}
+	return decision, nil
+}
+
+// RbacGraph lists the Roles, ClusterRoles, RoleBindings, and ClusterRoleBindings of the cluster into an RBAC graph
+func (k *Kubernetes) RbacGraph(ctx context.Context) (*rbac.Graph, error) {
+	rbacClient := k.AccessControlClientset().RbacV1()
+	roles, err := rbacClient.Roles(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	clusterRoles, err := rbacClient.ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster roles: %w", err)
+	}
+	roleBindings, err := rbacClient.RoleBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	clusterRoleBindings, err := rbacClient.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster role bindings: %w", err)
+	}
+	return rbac.NewGraph(roles.Items, clusterRoles.Items, roleBindings.Items, clusterRoleBindings.Items), nil
+}
@@ -10,12 +10,12 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/version"
-	authv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -38,7 +38,8 @@ func (k *Kubernetes) ResourcesList(ctx context.Context, gvk *schema.GroupVersion
 
 	// Check if operation is allowed for all namespaces (applicable for namespaced resources)
 	isNamespaced, _ := k.isNamespaced(gvk)
-	if isNamespaced && !k.canIUse(ctx, gvr, namespace, "list") && namespace == "" {
+	if isNamespaced && namespace == "" &&
+		(k.AccessControlClientset().IsNamespaceAllowed(namespace) != nil || !k.canIUse(ctx, gvr, namespace, "list")) {
 		namespace = k.configuredNamespace()
 	}
 	if options.AsTable {
@@ -81,54 +82,95 @@ func (k *Kubernetes) ResourcesDelete(ctx context.Context, gvk *schema.GroupVersi
 	}
 
 	// If it's a namespaced resource and namespace wasn't provided, try to use the default configured one
-	if namespaced, nsErr := k.isNamespaced(gvk); nsErr == nil && namespaced {
+	namespaced, nsErr := k.isNamespaced(gvk)
+	if nsErr == nil && namespaced {
 		namespace = k.NamespaceOrDefault(namespace)
 	}
-	return k.AccessControlClientset().DynamicClient().Resource(*gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err = k.preflight(ctx, namespace, name, resourcePermission("delete", gvr, namespaced)); err != nil {
+		return err
+	}
+	return k.AccessControlClientset().DynamicClient().Resource(*gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRun(ctx)})
 }
 
+// ResourcesScale gets the scale of the resource, and updates it to desiredScale if shouldScale is set.
+// The resources managed by a HorizontalPodAutoscaler are only scaled if force is set, the returned warnings describe
+// the autoscaler that may override the requested replicas.
 func (k *Kubernetes) ResourcesScale(
 	ctx context.Context,
 	gvk *schema.GroupVersionKind,
 	namespace, name string,
 	desiredScale int64,
-	shouldScale bool,
-) (*unstructured.Unstructured, error) {
+	shouldScale, force bool,
+) (*unstructured.Unstructured, []string, error) {
+	_, scale, warnings, err := k.resourcesScale(ctx, gvk, namespace, name, desiredScale, shouldScale, force)
+	return scale, warnings, err
+}
+
+// resourcesScale implements ResourcesScale and WorkloadsScale, it returns the scale of the resource before and after
+// the update
+func (k *Kubernetes) resourcesScale(
+	ctx context.Context,
+	gvk *schema.GroupVersionKind,
+	namespace, name string,
+	desiredScale int64,
+	shouldScale, force bool,
+) (previous, scale *unstructured.Unstructured, warnings []string, err error) {
 	gvr, err := k.resourceFor(gvk)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	var resourceClient dynamic.ResourceInterface
 
-	if namespaced, nsErr := k.isNamespaced(gvk); nsErr == nil && namespaced {
+	namespaced, nsErr := k.isNamespaced(gvk)
+	if nsErr == nil && namespaced {
+		namespace = k.NamespaceOrDefault(namespace)
 		resourceClient = k.
 			AccessControlClientset().
 			DynamicClient().
 			Resource(*gvr).
-			Namespace(k.NamespaceOrDefault(namespace))
+			Namespace(namespace)
 	} else {
 		resourceClient = k.
 			AccessControlClientset().DynamicClient().Resource(*gvr)
 	}
 
-	scale, err := resourceClient.Get(ctx, name, metav1.GetOptions{}, "scale")
+	previous, err = resourceClient.Get(ctx, name, metav1.GetOptions{}, "scale")
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
+	}
+	if !shouldScale {
+		return previous, previous, nil, nil
 	}
 
-	if shouldScale {
-		if err := unstructured.SetNestedField(scale.Object, desiredScale, "spec", "replicas"); err != nil {
-			return scale, fmt.Errorf("failed to set .spec.replicas on scale object %v: %w", scale, err)
-		}
-
-		scale, err = resourceClient.Update(ctx, scale, metav1.UpdateOptions{}, "scale")
+	if nsErr == nil && namespaced {
+		hpa, err := k.workloadHorizontalPodAutoscaler(ctx, gvk.Kind, namespace, name)
 		if err != nil {
-			return scale, fmt.Errorf("failed to update scale: %w", err)
+			warnings = append(warnings, fmt.Sprintf("unable to check for HorizontalPodAutoscalers targeting the resource: %v", err))
+		} else if hpa != nil {
+			hpaDescription := fmt.Sprintf("%s %s is managed by HorizontalPodAutoscaler %s (minReplicas=%d, maxReplicas=%d)",
+				gvk.Kind, name, hpa.Name, ptr.Deref(hpa.Spec.MinReplicas, 1), hpa.Spec.MaxReplicas)
+			if !force {
+				return nil, nil, nil, fmt.Errorf("refusing to scale: %s, update the HorizontalPodAutoscaler instead or set force=true to override", hpaDescription)
+			}
+			warnings = append(warnings, hpaDescription+", the autoscaler may override the requested replicas")
 		}
 	}
 
-	return scale, nil
+	if replicas, found, _ := unstructured.NestedInt64(previous.Object, "spec", "replicas"); found && replicas == desiredScale {
+		return previous, previous, warnings, nil
+	}
+	scale = previous.DeepCopy()
+	if err := unstructured.SetNestedField(scale.Object, desiredScale, "spec", "replicas"); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to set .spec.replicas on scale object %v: %w", scale, err)
+	}
+
+	scale, err = resourceClient.Update(ctx, scale, metav1.UpdateOptions{DryRun: dryRun(ctx)}, "scale")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to update scale: %w", err)
+	}
+
+	return previous, scale, warnings, nil
 }
 
 // resourcesListAsTable retrieves a list of resources in a table format.
@@ -188,17 +230,23 @@ func (k *Kubernetes) resourcesCreateOrUpdate(ctx context.Context, resources []*u
 
 		namespace := obj.GetNamespace()
 		// If it's a namespaced resource and namespace wasn't provided, try to use the default configured one
-		if namespaced, nsErr := k.isNamespaced(&gvk); nsErr == nil && namespaced {
+		namespaced, nsErr := k.isNamespaced(&gvk)
+		if nsErr == nil && namespaced {
 			namespace = k.NamespaceOrDefault(namespace)
 		}
+		// server-side apply requires the patch permission (and create for new resources)
+		if rErr = k.preflight(ctx, namespace, obj.GetName(), resourcePermission("patch", gvr, namespaced)); rErr != nil {
+			return nil, rErr
+		}
 		resources[i], rErr = k.AccessControlClientset().DynamicClient().Resource(*gvr).Namespace(namespace).Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
 			FieldManager: version.BinaryName,
+			DryRun:       dryRun(ctx),
 		})
 		if rErr != nil {
 			return nil, rErr
 		}
 		// Clear the cache to ensure the next operation is performed on the latest exposed APIs (will change after the CRD creation)
-		if gvk.Kind == "CustomResourceDefinition" {
+		if gvk.Kind == "CustomResourceDefinition" && !IsDryRun(ctx) {
 			k.AccessControlClientset().RESTMapper().Reset()
 		}
 	}
@@ -234,19 +282,7 @@ func (k *Kubernetes) supportsGroupVersion(groupVersion string) bool {
 }
 
 func (k *Kubernetes) canIUse(ctx context.Context, gvr *schema.GroupVersionResource, namespace, verb string) bool {
-	accessReviews := k.AccessControlClientset().AuthorizationV1().SelfSubjectAccessReviews()
-	response, err := accessReviews.Create(ctx, &authv1.SelfSubjectAccessReview{
-		Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &authv1.ResourceAttributes{
-			Namespace: namespace,
-			Verb:      verb,
-			Group:     gvr.Group,
-			Version:   gvr.Version,
-			Resource:  gvr.Resource,
-		}},
-	}, metav1.CreateOptions{})
-	if err != nil {
-		// TODO: maybe return the error too
-		return false
-	}
-	return response.Status.Allowed
+	// TODO: maybe return the error too
+	allowed, _, _ := k.CanI(ctx, ResourcePermission{Verb: verb, Group: gvr.Group, Resource: gvr.Resource}, namespace, "")
+	return allowed
 }
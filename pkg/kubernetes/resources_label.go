@@ -0,0 +1,257 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ResourcesLabelMaxObjectsDefault is the default maximum number of objects a bulk label edit can change
+const ResourcesLabelMaxObjectsDefault = 50
+
+type ResourcesLabelOptions struct {
+	// Namespace of the objects, the configured namespace if empty (ignored for cluster-scoped kinds)
+	Namespace string
+	// AllNamespaces selects the objects of all namespaces
+	AllNamespaces bool
+	// LabelSelector selects the objects to edit, required so that a call doesn't edit every object of a kind by mistake
+	LabelSelector     string
+	AddLabels         map[string]string
+	RemoveLabels      []string
+	AddAnnotations    map[string]string
+	RemoveAnnotations []string
+	// MaxObjects is the safety cap of the edit, no object is changed if more objects are selected
+	// (ResourcesLabelMaxObjectsDefault if zero)
+	MaxObjects int
+}
+
+// ResourcesLabelReport is the result of a bulk label and annotation edit
+type ResourcesLabelReport struct {
+	// Summary is a one line description, e.g. "2 Deployment objects changed in namespace shop (3 selected by app=web, 1 unchanged)"
+	Summary string `json:"summary"`
+	// DryRun is true if the changes were only validated with server-side dry-run
+	DryRun bool `json:"dryRun,omitempty"`
+	// Selected is the number of objects matching the label selector
+	Selected int `json:"selected"`
+	// Objects are the changed objects (or the objects that would change with dry-run)
+	Objects []LabeledObject `json:"objects"`
+	// Unchanged is the number of selected objects that already had the requested labels and annotations
+	Unchanged    int          `json:"unchanged,omitempty"`
+	TargetErrors TargetErrors `json:"-"`
+}
+
+// LabeledObject describes the labels and annotations changed on an object
+type LabeledObject struct {
+	Namespace   string           `json:"namespace,omitempty"`
+	Name        string           `json:"name"`
+	Labels      *MetadataChanges `json:"labels,omitempty"`
+	Annotations *MetadataChanges `json:"annotations,omitempty"`
+}
+
+// MetadataChanges are the keys added or updated (with their new value) and removed from the labels or annotations of an object
+type MetadataChanges struct {
+	Set     map[string]string `json:"set,omitempty"`
+	Removed []string          `json:"removed,omitempty"`
+}
+
+// ResourcesLabel adds and removes the labels and annotations of the objects of the provided kind that match the label
+// selector with a JSON merge patch per object.
+// The edit fails without changing any object if more objects than MaxObjects are selected. The objects that already
+// have the requested labels and annotations are not patched, the failures of the remaining objects are reported as
+// TargetErrors.
+func (k *Kubernetes) ResourcesLabel(ctx context.Context, gvk *schema.GroupVersionKind, options ResourcesLabelOptions) (*ResourcesLabelReport, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	gvr, err := k.resourceFor(gvk)
+	if err != nil {
+		return nil, err
+	}
+	namespace := ""
+	namespaced, nsErr := k.isNamespaced(gvk)
+	if nsErr == nil && namespaced && !options.AllNamespaces {
+		namespace = k.NamespaceOrDefault(options.Namespace)
+	}
+	maxObjects := options.MaxObjects
+	if maxObjects <= 0 {
+		maxObjects = ResourcesLabelMaxObjectsDefault
+	}
+	resource := k.AccessControlClientset().DynamicClient().Resource(*gvr).Namespace(namespace)
+	list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: options.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) > maxObjects {
+		return nil, fmt.Errorf("%d %s match %s, more than the maximum of %d objects (max_objects), no object was changed: narrow the selector or increase the maximum",
+			len(list.Items), gvr.Resource, options.LabelSelector, maxObjects)
+	}
+	report := &ResourcesLabelReport{DryRun: IsDryRun(ctx), Selected: len(list.Items), Objects: []LabeledObject{}}
+	var toPatch []*unstructured.Unstructured
+	for i := range list.Items {
+		if labelChanges(&list.Items[i], options) != nil {
+			toPatch = append(toPatch, &list.Items[i])
+		} else {
+			report.Unchanged++
+		}
+	}
+	for _, obj := range toPatch {
+		if err = k.preflight(ctx, obj.GetNamespace(), obj.GetName(), resourcePermission("patch", gvr, namespaced)); err != nil {
+			return nil, err
+		}
+	}
+	for _, obj := range toPatch {
+		changed := labelChanges(obj, options)
+		patch, err := json.Marshal(changed.patch())
+		if err != nil {
+			return nil, err
+		}
+		_, err = k.AccessControlClientset().DynamicClient().Resource(*gvr).Namespace(obj.GetNamespace()).
+			Patch(ctx, obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun(ctx)})
+		if err != nil {
+			report.TargetErrors.Add(labeledObjectTarget(gvk.Kind, obj), err)
+			continue
+		}
+		report.Objects = append(report.Objects, *changed)
+	}
+	scope := "namespace " + namespace
+	if !namespaced {
+		scope = "the cluster"
+	} else if namespace == "" {
+		scope = "all namespaces"
+	}
+	report.Summary = report.summary(gvk.Kind, scope, options.LabelSelector)
+	if len(toPatch) > 0 && len(report.Objects) == 0 {
+		return nil, fmt.Errorf("failed to change the %d selected objects: %w", len(toPatch), report.TargetErrors)
+	}
+	return report, nil
+}
+
+func (o *ResourcesLabelOptions) validate() error {
+	if strings.TrimSpace(o.LabelSelector) == "" {
+		return errors.New("a label selector is required")
+	}
+	if _, err := labels.Parse(o.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label selector %q: %w", o.LabelSelector, err)
+	}
+	if len(o.AddLabels)+len(o.RemoveLabels)+len(o.AddAnnotations)+len(o.RemoveAnnotations) == 0 {
+		return errors.New("no label or annotation to add or remove")
+	}
+	var errs []string
+	for _, key := range slices.Sorted(maps.Keys(o.AddLabels)) {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Sprintf("label %q: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(o.AddLabels[key]) {
+			errs = append(errs, fmt.Sprintf("label %s value %q: %s", key, o.AddLabels[key], msg))
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(o.AddAnnotations)) {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Sprintf("annotation %q: %s", key, msg))
+		}
+	}
+	for _, key := range o.RemoveLabels {
+		if _, ok := o.AddLabels[key]; ok {
+			errs = append(errs, fmt.Sprintf("label %s is both added and removed", key))
+		}
+	}
+	for _, key := range o.RemoveAnnotations {
+		if _, ok := o.AddAnnotations[key]; ok {
+			errs = append(errs, fmt.Sprintf("annotation %s is both added and removed", key))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid labels or annotations: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// labelChanges returns the changes the options make to the labels and annotations of the object, nil if none
+func labelChanges(obj *unstructured.Unstructured, options ResourcesLabelOptions) *LabeledObject {
+	changed := &LabeledObject{
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		Labels:      metadataChanges(obj.GetLabels(), options.AddLabels, options.RemoveLabels),
+		Annotations: metadataChanges(obj.GetAnnotations(), options.AddAnnotations, options.RemoveAnnotations),
+	}
+	if changed.Labels == nil && changed.Annotations == nil {
+		return nil
+	}
+	return changed
+}
+
+func metadataChanges(current, add map[string]string, remove []string) *MetadataChanges {
+	changes := &MetadataChanges{}
+	for key, value := range add {
+		if existing, ok := current[key]; !ok || existing != value {
+			if changes.Set == nil {
+				changes.Set = map[string]string{}
+			}
+			changes.Set[key] = value
+		}
+	}
+	for _, key := range remove {
+		if _, ok := current[key]; ok && !slices.Contains(changes.Removed, key) {
+			changes.Removed = append(changes.Removed, key)
+		}
+	}
+	if len(changes.Set) == 0 && len(changes.Removed) == 0 {
+		return nil
+	}
+	sort.Strings(changes.Removed)
+	return changes
+}
+
+// patch returns the JSON merge patch of the changes, the removed keys are set to null
+func (o *LabeledObject) patch() map[string]any {
+	metadata := map[string]any{}
+	for field, changes := range map[string]*MetadataChanges{"labels": o.Labels, "annotations": o.Annotations} {
+		if changes == nil {
+			continue
+		}
+		values := map[string]any{}
+		for key, value := range changes.Set {
+			values[key] = value
+		}
+		for _, key := range changes.Removed {
+			values[key] = nil
+		}
+		metadata[field] = values
+	}
+	return map[string]any{"metadata": metadata}
+}
+
+func labeledObjectTarget(kind string, obj *unstructured.Unstructured) string {
+	target := strings.ToLower(kind) + "/"
+	if obj.GetNamespace() != "" {
+		target += obj.GetNamespace() + "/"
+	}
+	return target + obj.GetName()
+}
+
+func (r *ResourcesLabelReport) summary(kind, scope, labelSelector string) string {
+	verb := "changed"
+	if r.DryRun {
+		verb = "would change (server-side dry-run)"
+	}
+	summary := fmt.Sprintf("%d %s objects %s in %s (%d selected by %s", len(r.Objects), kind, verb, scope, r.Selected, labelSelector)
+	if r.Unchanged > 0 {
+		summary += fmt.Sprintf(", %d unchanged", r.Unchanged)
+	}
+	if len(r.TargetErrors) > 0 {
+		summary += fmt.Sprintf(", %d failed", len(r.TargetErrors))
+	}
+	return summary + ")"
+}
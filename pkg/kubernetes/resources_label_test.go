@@ -0,0 +1,95 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type ResourcesLabelSuite struct {
+	suite.Suite
+}
+
+func (s *ResourcesLabelSuite) TestValidate() {
+	s.Run("requires a label selector", func() {
+		options := ResourcesLabelOptions{AddLabels: map[string]string{"team": "payments"}}
+		s.EqualError(options.validate(), "a label selector is required")
+	})
+	s.Run("rejects invalid label selectors", func() {
+		options := ResourcesLabelOptions{LabelSelector: "app in (", AddLabels: map[string]string{"team": "payments"}}
+		s.ErrorContains(options.validate(), `invalid label selector "app in ("`)
+	})
+	s.Run("requires a change", func() {
+		options := ResourcesLabelOptions{LabelSelector: "app=web"}
+		s.EqualError(options.validate(), "no label or annotation to add or remove")
+	})
+	s.Run("rejects invalid keys and values", func() {
+		options := ResourcesLabelOptions{
+			LabelSelector:  "app=web",
+			AddLabels:      map[string]string{"team": "pay ments", "-invalid": "x"},
+			AddAnnotations: map[string]string{"example.com/": "x"},
+		}
+		err := options.validate()
+		s.ErrorContains(err, `label "-invalid": name part must consist of alphanumeric characters`)
+		s.ErrorContains(err, `label team value "pay ments": a valid label must be`)
+		s.ErrorContains(err, `annotation "example.com/": name part must be non-empty`)
+	})
+	s.Run("rejects keys both added and removed", func() {
+		options := ResourcesLabelOptions{
+			LabelSelector: "app=web",
+			AddLabels:     map[string]string{"team": "payments"},
+			RemoveLabels:  []string{"team"},
+		}
+		s.EqualError(options.validate(), "invalid labels or annotations: label team is both added and removed")
+	})
+	s.Run("accepts valid changes", func() {
+		options := ResourcesLabelOptions{
+			LabelSelector:     "app in (web, api)",
+			AddLabels:         map[string]string{"example.com/team": "payments"},
+			RemoveAnnotations: []string{"deprecated"},
+		}
+		s.NoError(options.validate())
+	})
+}
+
+func (s *ResourcesLabelSuite) TestLabelChanges() {
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace("shop")
+	obj.SetName("web")
+	obj.SetLabels(map[string]string{"app": "web", "team": "payments", "tier": "frontend"})
+	obj.SetAnnotations(map[string]string{"owner": "alice"})
+	s.Run("reports the keys that change", func() {
+		changes := labelChanges(obj, ResourcesLabelOptions{
+			AddLabels:         map[string]string{"team": "checkout", "app": "web", "env": "prod"},
+			RemoveLabels:      []string{"tier", "missing"},
+			RemoveAnnotations: []string{"missing"},
+		})
+		s.Require().NotNil(changes)
+		s.Equal("shop", changes.Namespace)
+		s.Equal("web", changes.Name)
+		s.Equal(&MetadataChanges{Set: map[string]string{"team": "checkout", "env": "prod"}, Removed: []string{"tier"}}, changes.Labels)
+		s.Nil(changes.Annotations)
+	})
+	s.Run("returns nil if the object already has the requested labels", func() {
+		s.Nil(labelChanges(obj, ResourcesLabelOptions{
+			AddLabels:    map[string]string{"team": "payments"},
+			RemoveLabels: []string{"missing"},
+		}))
+	})
+	s.Run("patch sets the removed keys to null", func() {
+		changes := labelChanges(obj, ResourcesLabelOptions{
+			AddAnnotations: map[string]string{"owner": "bob"},
+			RemoveLabels:   []string{"tier"},
+		})
+		s.Require().NotNil(changes)
+		s.Equal(map[string]any{"metadata": map[string]any{
+			"labels":      map[string]any{"tier": nil},
+			"annotations": map[string]any{"owner": "bob"},
+		}}, changes.patch())
+	})
+}
+
+func TestResourcesLabel(t *testing.T) {
+	suite.Run(t, new(ResourcesLabelSuite))
+}
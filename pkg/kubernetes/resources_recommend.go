@@ -0,0 +1,313 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	WorkloadKindPod = "Pod"
+	// DefaultResourcesRecommendHeadroom is the default percentage added to the usage of the containers
+	DefaultResourcesRecommendHeadroom = 20
+	// resourcesRecommendMinCPU and resourcesRecommendMinMemory are the minimum recommended requests, containers idle
+	// during the sampling still need some resources to start
+	resourcesRecommendMinCPU    = 10
+	resourcesRecommendMinMemory = 16 << 20
+	// resourcesRecommendTolerance is the relative difference between the current and the recommended values below
+	// which the current values are kept, to avoid rolling out the workload for insignificant changes
+	resourcesRecommendTolerance = 0.1
+)
+
+// ResourcesRecommendKinds is the list of kinds supported by ResourcesRecommend
+var ResourcesRecommendKinds = []string{WorkloadKindDeployment, WorkloadKindStatefulSet, WorkloadKindDaemonSet, WorkloadKindPod}
+
+type ResourcesRecommendOptions struct {
+	UsageSampleOptions
+	Kind      string
+	Namespace string
+	Name      string
+	// Headroom is the percentage added to the usage for the recommended requests
+	Headroom int
+	// Patch generates the strategic merge patch of the workload with the recommended resources
+	Patch bool
+}
+
+// ResourcesRecommendation compares the usage of the containers of a workload with their requests and limits, and
+// proposes right-sized values
+type ResourcesRecommendation struct {
+	// Summary is a one line description, e.g. "Deployment shop/web: 1 of 2 containers to right-size, ..."
+	Summary   string `json:"summary"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Source    string `json:"source"`
+	// Samples is the number of samples that returned the usage of the Pods, Pods the number of running Pods sampled
+	Samples    int                                `json:"samples"`
+	Pods       int                                `json:"pods"`
+	Headroom   int                                `json:"headroomPercent"`
+	Containers []ContainerResourcesRecommendation `json:"containers"`
+	// Patch is the strategic merge patch (YAML) of the workload with the recommended resources of the containers to
+	// right-size, e.g. for kubectl patch --patch-file
+	Patch    string   `json:"patch,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ContainerResourcesRecommendation is the recommendation for a container of the workload
+type ContainerResourcesRecommendation struct {
+	Name        string             `json:"name"`
+	Usage       *ContainerUsage    `json:"usage,omitempty"`
+	Current     ContainerResources `json:"current"`
+	Recommended ContainerResources `json:"recommended"`
+	// Changes describe the recommended changes, e.g. "decrease the cpu request from 1 to 150m (p95 usage 110m)"
+	Changes []string `json:"changes,omitempty"`
+}
+
+// ContainerResources are the CPU and memory requests and limits of a container, empty if not set
+type ContainerResources struct {
+	CPURequest    string `json:"cpuRequest,omitempty"`
+	CPULimit      string `json:"cpuLimit,omitempty"`
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	MemoryLimit   string `json:"memoryLimit,omitempty"`
+}
+
+// ResourcesRecommend samples the usage of the running Pods of the workload (see SampleUsage) and recommends the
+// requests and limits of its containers (see RecommendContainerResources)
+func (k *Kubernetes) ResourcesRecommend(ctx context.Context, options ResourcesRecommendOptions) (*ResourcesRecommendation, error) {
+	namespace := k.NamespaceOrDefault(options.Namespace)
+	apps := k.AccessControlClientset().AppsV1()
+	var selector *metav1.LabelSelector
+	var containers []v1.Container
+	var pods []v1.Pod
+	switch options.Kind {
+	case WorkloadKindDeployment:
+		d, err := apps.Deployments(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, containers = d.Spec.Selector, d.Spec.Template.Spec.Containers
+	case WorkloadKindStatefulSet:
+		s, err := apps.StatefulSets(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, containers = s.Spec.Selector, s.Spec.Template.Spec.Containers
+	case WorkloadKindDaemonSet:
+		ds, err := apps.DaemonSets(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, containers = ds.Spec.Selector, ds.Spec.Template.Spec.Containers
+	case WorkloadKindPod:
+		pod, err := k.AccessControlClientset().CoreV1().Pods(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		containers, pods = pod.Spec.Containers, []v1.Pod{*pod}
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", options.Kind, ResourcesRecommendKinds)
+	}
+	if selector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector of %s %s: %w", options.Kind, options.Name, err)
+		}
+		podList, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the pods of %s %s: %w", options.Kind, options.Name, err)
+		}
+		pods = podList.Items
+	}
+	running := make([]v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning && pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	if len(running) == 0 {
+		return nil, fmt.Errorf("%s %s has no running pods to sample the usage of", options.Kind, options.Name)
+	}
+	samples, err := k.SampleUsage(ctx, namespace, running, options.UsageSampleOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample the usage of the pods: %w", err)
+	}
+	recommendation := &ResourcesRecommendation{
+		Kind:       options.Kind,
+		Namespace:  namespace,
+		Name:       options.Name,
+		Source:     samples.Source,
+		Samples:    samples.Samples,
+		Pods:       len(running),
+		Headroom:   options.Headroom,
+		Containers: make([]ContainerResourcesRecommendation, 0, len(containers)),
+		Warnings:   samples.Warnings,
+	}
+	changed := 0
+	for _, container := range containers {
+		var usage *ContainerUsage
+		if containerUsage, ok := samples.Containers[container.Name]; ok {
+			usage = &containerUsage
+		} else {
+			recommendation.Warnings = append(recommendation.Warnings, fmt.Sprintf("no usage sample of container %s, its resources are kept", container.Name))
+		}
+		containerRecommendation := RecommendContainerResources(container, usage, options.Headroom)
+		if len(containerRecommendation.Changes) > 0 {
+			changed++
+		}
+		recommendation.Containers = append(recommendation.Containers, containerRecommendation)
+	}
+	if options.Patch && options.Kind == WorkloadKindPod {
+		recommendation.Warnings = append(recommendation.Warnings, "no patch is generated for a Pod, patch the resources of the workload that manages it")
+	} else if options.Patch && changed > 0 {
+		if recommendation.Patch, err = recommendation.patch(); err != nil {
+			return nil, err
+		}
+	}
+	recommendation.Summary = fmt.Sprintf("%s %s/%s: %d of %d containers to right-size, from %d usage samples of %d pods (%s)",
+		options.Kind, namespace, options.Name, changed, len(containers), recommendation.Samples, len(running), samples.Source)
+	return recommendation, nil
+}
+
+// RecommendContainerResources recommends the requests and limits of the container from its usage:
+// the CPU request is the 95th percentile of the CPU usage, and the memory request the maximum of the memory usage
+// (memory is not compressible, the container is OOM killed above its limit), plus the headroom percentage.
+// The limits keep their ratio to the requests, as the Vertical Pod Autoscaler does, and are not added if not set.
+// The current values within 10% of the recommended ones are kept, the container is not changed without usage.
+func RecommendContainerResources(container v1.Container, usage *ContainerUsage, headroom int) ContainerResourcesRecommendation {
+	current := containerResources(container.Resources)
+	recommendation := ContainerResourcesRecommendation{Name: container.Name, Usage: usage, Current: current, Recommended: current}
+	if usage == nil {
+		return recommendation
+	}
+	factor := 1 + float64(headroom)/100
+	cpuRequest := roundUp(int64(math.Ceil(float64(usage.CPU.P95.MilliValue())*factor)), 5)
+	cpuRequest = max(cpuRequest, resourcesRecommendMinCPU)
+	memoryRequest := roundUp(int64(math.Ceil(float64(usage.Memory.Max.Value())*factor)), 1<<20)
+	memoryRequest = max(memoryRequest, resourcesRecommendMinMemory)
+	requests, limits := container.Resources.Requests, container.Resources.Limits
+	for _, r := range []struct {
+		name        v1.ResourceName
+		recommended resource.Quantity
+		usage       string
+		request     *string
+		limit       *string
+	}{
+		{v1.ResourceCPU, *resource.NewMilliQuantity(cpuRequest, resource.DecimalSI), "p95 usage " + usage.CPU.P95.String(),
+			&recommendation.Recommended.CPURequest, &recommendation.Recommended.CPULimit},
+		{v1.ResourceMemory, *resource.NewQuantity(memoryRequest, resource.BinarySI), "max usage " + usage.Memory.Max.String(),
+			&recommendation.Recommended.MemoryRequest, &recommendation.Recommended.MemoryLimit},
+	} {
+		request, hasRequest := requests[r.name]
+		limit, hasLimit := limits[r.name]
+		// The request defaults to the limit when only the limit is set
+		if !hasRequest && hasLimit {
+			request, hasRequest = limit, true
+		}
+		switch {
+		case !hasRequest:
+			*r.request = r.recommended.String()
+			recommendation.Changes = append(recommendation.Changes, fmt.Sprintf("set the %s request to %s (%s)", r.name, r.recommended.String(), r.usage))
+		case withinTolerance(request, r.recommended):
+			continue
+		default:
+			*r.request = r.recommended.String()
+			recommendation.Changes = append(recommendation.Changes, fmt.Sprintf("%s the %s request from %s to %s (%s)",
+				increaseOrDecrease(request, r.recommended), r.name, request.String(), r.recommended.String(), r.usage))
+		}
+		if !hasLimit || request.IsZero() {
+			continue
+		}
+		ratio := limit.AsApproximateFloat64() / request.AsApproximateFloat64()
+		var recommendedLimit resource.Quantity
+		if r.name == v1.ResourceCPU {
+			recommendedLimit = *resource.NewMilliQuantity(roundUp(int64(math.Ceil(float64(r.recommended.MilliValue())*ratio)), 5), resource.DecimalSI)
+		} else {
+			recommendedLimit = *resource.NewQuantity(roundUp(int64(math.Ceil(float64(r.recommended.Value())*ratio)), 1<<20), resource.BinarySI)
+		}
+		if recommendedLimit.Cmp(limit) != 0 {
+			*r.limit = recommendedLimit.String()
+			recommendation.Changes = append(recommendation.Changes, fmt.Sprintf("%s the %s limit from %s to %s (keeps the limit to request ratio)",
+				increaseOrDecrease(limit, recommendedLimit), r.name, limit.String(), recommendedLimit.String()))
+		}
+	}
+	return recommendation
+}
+
+// patch returns the strategic merge patch of the workload with the recommended resources of the containers to
+// right-size, the containers are merged by name
+func (r *ResourcesRecommendation) patch() (string, error) {
+	containers := make([]map[string]any, 0, len(r.Containers))
+	for _, container := range r.Containers {
+		if len(container.Changes) == 0 {
+			continue
+		}
+		resources := map[string]map[string]string{}
+		for _, value := range []struct {
+			field, name, value string
+		}{
+			{"requests", "cpu", container.Recommended.CPURequest},
+			{"requests", "memory", container.Recommended.MemoryRequest},
+			{"limits", "cpu", container.Recommended.CPULimit},
+			{"limits", "memory", container.Recommended.MemoryLimit},
+		} {
+			if value.value == "" {
+				continue
+			}
+			if resources[value.field] == nil {
+				resources[value.field] = map[string]string{}
+			}
+			resources[value.field][value.name] = value.value
+		}
+		containers = append(containers, map[string]any{"name": container.Name, "resources": resources})
+	}
+	patch, err := output.MarshalYaml(map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       r.Kind,
+		"metadata":   map[string]string{"name": r.Name, "namespace": r.Namespace},
+		"spec":       map[string]any{"template": map[string]any{"spec": map[string]any{"containers": containers}}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate the patch: %w", err)
+	}
+	return patch, nil
+}
+
+func containerResources(requirements v1.ResourceRequirements) ContainerResources {
+	resources := ContainerResources{}
+	if cpu, ok := requirements.Requests[v1.ResourceCPU]; ok {
+		resources.CPURequest = cpu.String()
+	}
+	if cpu, ok := requirements.Limits[v1.ResourceCPU]; ok {
+		resources.CPULimit = cpu.String()
+	}
+	if memory, ok := requirements.Requests[v1.ResourceMemory]; ok {
+		resources.MemoryRequest = memory.String()
+	}
+	if memory, ok := requirements.Limits[v1.ResourceMemory]; ok {
+		resources.MemoryLimit = memory.String()
+	}
+	return resources
+}
+
+// withinTolerance returns true if the current value is within resourcesRecommendTolerance of the recommended one
+func withinTolerance(current, recommended resource.Quantity) bool {
+	return math.Abs(current.AsApproximateFloat64()-recommended.AsApproximateFloat64()) <= resourcesRecommendTolerance*recommended.AsApproximateFloat64()
+}
+
+func increaseOrDecrease(current, recommended resource.Quantity) string {
+	if recommended.Cmp(current) > 0 {
+		return "increase"
+	}
+	return "decrease"
+}
+
+// roundUp rounds the value up to a multiple of the provided step
+func roundUp(value, step int64) int64 {
+	return (value + step - 1) / step * step
+}
@@ -0,0 +1,198 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type ResourcesRecommendSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+}
+
+func (s *ResourcesRecommendSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	web := v1.Container{Name: "web", Resources: v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1Gi")},
+	}}
+	sidecar := v1.Container{Name: "sidecar"}
+	pod := func(name, node string, phase v1.PodPhase) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, Labels: map[string]string{"app": "web"}},
+			Spec:       v1.PodSpec{NodeName: node, Containers: []v1.Container{web, sidecar}},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+	}
+	summary := func(pods ...string) string {
+		ret := `{"pods":[`
+		for i, pod := range pods {
+			if i > 0 {
+				ret += ","
+			}
+			ret += fmt.Sprintf(`{"podRef":{"name":%q,"namespace":"shop"},"containers":[`+
+				`{"name":"web","cpu":{"usageNanoCores":%d},"memory":{"workingSetBytes":%d}},`+
+				`{"name":"sidecar","cpu":{"usageNanoCores":5000000},"memory":{"workingSetBytes":10485760}}]}`, pod, (i+1)*100_000_000, (i+1)*100<<20)
+		}
+		return ret + `]}`
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/shop/deployments/web":
+			test.WriteObject(w, &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{web, sidecar}}},
+				},
+			})
+		case "/api/v1/namespaces/shop/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items:    []v1.Pod{pod("web-1", "node-1", v1.PodRunning), pod("web-2", "node-2", v1.PodRunning), pod("web-3", "", v1.PodPending)},
+			})
+		case "/api/v1/namespaces/shop/pods/pending":
+			test.WriteObject(w, &v1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}, ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "pending"}})
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(summary("web-1", "other")))
+		case "/api/v1/nodes/node-2/proxy/stats/summary":
+			_, _ = w.Write([]byte(summary("unrelated", "web-2")))
+		}
+	}))
+}
+
+func (s *ResourcesRecommendSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ResourcesRecommendSuite) derived() *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *ResourcesRecommendSuite) TestUsageAggregator() {
+	aggregator := NewUsageAggregator()
+	for i := int64(1); i <= 20; i++ {
+		aggregator.Add("web", i*10, i<<20)
+	}
+	usage := aggregator.Usage()["web"]
+	s.Run("counts the samples", func() {
+		s.Equal(20, usage.Samples)
+	})
+	s.Run("returns the CPU stats", func() {
+		s.Equal("105m", usage.CPU.Average.String())
+		s.Equal("190m", usage.CPU.P95.String())
+		s.Equal("200m", usage.CPU.Max.String())
+	})
+	s.Run("returns the memory stats rounded up to the mebibyte", func() {
+		s.Equal("11Mi", usage.Memory.Average.String())
+		s.Equal("19Mi", usage.Memory.P95.String())
+		s.Equal("20Mi", usage.Memory.Max.String())
+	})
+}
+
+func (s *ResourcesRecommendSuite) TestRecommendContainerResources() {
+	usage := &ContainerUsage{Samples: 10, CPU: UsageStats{P95: resource.MustParse("200m")}, Memory: UsageStats{Max: resource.MustParse("200Mi")}}
+	s.Run("right-sizes the requests and keeps the limit to request ratio", func() {
+		recommendation := RecommendContainerResources(v1.Container{Name: "web", Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("128Mi")},
+			Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("256Mi")},
+		}}, usage, 20)
+		s.Equal(ContainerResources{CPURequest: "240m", CPULimit: "480m", MemoryRequest: "240Mi", MemoryLimit: "480Mi"}, recommendation.Recommended)
+		s.Equal([]string{
+			"decrease the cpu request from 1 to 240m (p95 usage 200m)",
+			"decrease the cpu limit from 2 to 480m (keeps the limit to request ratio)",
+			"increase the memory request from 128Mi to 240Mi (max usage 200Mi)",
+			"increase the memory limit from 256Mi to 480Mi (keeps the limit to request ratio)",
+		}, recommendation.Changes)
+	})
+	s.Run("sets the missing requests without adding limits", func() {
+		recommendation := RecommendContainerResources(v1.Container{Name: "web"}, usage, 0)
+		s.Equal(ContainerResources{CPURequest: "200m", MemoryRequest: "200Mi"}, recommendation.Recommended)
+		s.Len(recommendation.Changes, 2)
+	})
+	s.Run("keeps the values within the tolerance", func() {
+		recommendation := RecommendContainerResources(v1.Container{Name: "web", Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("256Mi")},
+		}}, usage, 20)
+		s.Equal(recommendation.Current, recommendation.Recommended)
+		s.Empty(recommendation.Changes)
+	})
+	s.Run("recommends the minimum requests for idle containers", func() {
+		recommendation := RecommendContainerResources(v1.Container{Name: "idle"}, &ContainerUsage{Samples: 1}, 20)
+		s.Equal(ContainerResources{CPURequest: "10m", MemoryRequest: "16Mi"}, recommendation.Recommended)
+	})
+	s.Run("keeps the resources without usage", func() {
+		recommendation := RecommendContainerResources(v1.Container{Name: "web", Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		}}, nil, 20)
+		s.Equal(ContainerResources{CPURequest: "1"}, recommendation.Recommended)
+		s.Empty(recommendation.Changes)
+	})
+}
+
+func (s *ResourcesRecommendSuite) TestResourcesRecommend() {
+	k := s.derived()
+	recommendation, err := k.ResourcesRecommend(s.T().Context(), ResourcesRecommendOptions{
+		UsageSampleOptions: UsageSampleOptions{Samples: 2},
+		Kind:               WorkloadKindDeployment,
+		Namespace:          "shop",
+		Name:               "web",
+		Headroom:           DefaultResourcesRecommendHeadroom,
+		Patch:              true,
+	})
+	s.Require().NoError(err)
+	s.Run("samples the running pods from the kubelet without metrics API", func() {
+		s.Equal(UsageSourceKubelet, recommendation.Source)
+		s.Equal(2, recommendation.Samples)
+		s.Equal(2, recommendation.Pods)
+		s.Equal(4, recommendation.Containers[0].Usage.Samples)
+	})
+	s.Run("returns the summary", func() {
+		s.Equal("Deployment shop/web: 2 of 2 containers to right-size, from 2 usage samples of 2 pods (kubelet)", recommendation.Summary)
+	})
+	s.Run("recommends the resources of the containers", func() {
+		s.Equal(ContainerResources{CPURequest: "240m", CPULimit: "480m", MemoryRequest: "240Mi", MemoryLimit: "240Mi"}, recommendation.Containers[0].Recommended)
+		s.Equal(ContainerResources{CPURequest: "10m", MemoryRequest: "16Mi"}, recommendation.Containers[1].Recommended)
+	})
+	s.Run("returns the patch of the deployment", func() {
+		s.Contains(recommendation.Patch, "kind: Deployment\n")
+		s.Contains(recommendation.Patch, "      - name: web\n        resources:\n          limits:\n            cpu: 480m\n            memory: 240Mi\n")
+		s.Contains(recommendation.Patch, "      - name: sidecar\n")
+	})
+	s.Run("returns error for pods that are not running", func() {
+		_, err := k.ResourcesRecommend(s.T().Context(), ResourcesRecommendOptions{Kind: WorkloadKindPod, Namespace: "shop", Name: "pending"})
+		s.EqualError(err, "Pod pending has no running pods to sample the usage of")
+	})
+	s.Run("returns error for unsupported kinds", func() {
+		_, err := k.ResourcesRecommend(s.T().Context(), ResourcesRecommendOptions{Kind: "ReplicaSet", Name: "web"})
+		s.EqualError(err, "unsupported workload kind ReplicaSet, supported kinds are [Deployment StatefulSet DaemonSet Pod]")
+	})
+	s.Run("returns error for the metrics source without metrics API", func() {
+		_, err := k.SampleUsage(s.T().Context(), "shop", nil, UsageSampleOptions{Source: UsageSourceMetrics})
+		s.EqualError(err, "metrics API is not available, sample the usage from the kubelet instead")
+	})
+}
+
+func TestResourcesRecommend(t *testing.T) {
+	suite.Run(t, new(ResourcesRecommendSuite))
+}
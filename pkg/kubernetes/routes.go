@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"context"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var routeGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// RouteOptions are the options to expose a Service with an OpenShift Route
+type RouteOptions struct {
+	// Name of the Route (Optional, defaults to the Service name)
+	Name string
+	// Service to route the traffic to
+	Service string
+	// TargetPort is the name or number of the Service target port (Optional, all ports if empty)
+	TargetPort string
+	// Host is the hostname for the Route (Optional, generated by the router if empty)
+	Host string
+	// Path the router watches to route the traffic to the Service (Optional)
+	Path string
+	// TLSTermination is the TLS termination type: edge, passthrough, or reencrypt (Optional, no TLS if empty)
+	TLSTermination string
+}
+
+func (k *Kubernetes) RoutesList(ctx context.Context, namespace string, options ResourceListOptions) (runtime.Unstructured, error) {
+	return k.ResourcesList(ctx, &routeGVK, namespace, options)
+}
+
+func (k *Kubernetes) RoutesCreate(ctx context.Context, namespace string, options RouteOptions) ([]*unstructured.Unstructured, error) {
+	name := options.Name
+	if name == "" {
+		name = options.Service
+	}
+	spec := map[string]any{
+		"to": map[string]any{"kind": "Service", "name": options.Service},
+	}
+	if options.TargetPort != "" {
+		var targetPort any = options.TargetPort
+		if port, err := strconv.Atoi(options.TargetPort); err == nil {
+			targetPort = int64(port)
+		}
+		spec["port"] = map[string]any{"targetPort": targetPort}
+	}
+	if options.Host != "" {
+		spec["host"] = options.Host
+	}
+	if options.Path != "" {
+		spec["path"] = options.Path
+	}
+	if options.TLSTermination != "" {
+		spec["tls"] = map[string]any{"termination": options.TLSTermination}
+	}
+	route := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	route.SetGroupVersionKind(routeGVK)
+	route.SetName(name)
+	route.SetNamespace(namespace)
+	return k.resourcesCreateOrUpdate(ctx, []*unstructured.Unstructured{route})
+}
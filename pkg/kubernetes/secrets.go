@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// Secret is the view of a Kubernetes Secret returned by the secrets tool, the values are masked unless revealed
+type Secret struct {
+	Namespace         string            `json:"namespace"`
+	Name              string            `json:"name"`
+	Type              string            `json:"type"`
+	Immutable         bool              `json:"immutable,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Keys              []string          `json:"keys"`
+	// Data are the values of the Secret keys, masked unless revealed.
+	// Revealed binary values that are not valid UTF-8 are base64 encoded and prefixed with "base64:".
+	Data     map[string]string `json:"data,omitempty"`
+	Revealed bool              `json:"revealed,omitempty"`
+}
+
+// SecretsList returns the Secrets in the provided namespace (all namespaces if empty), only the keys are listed
+func (k *Kubernetes) SecretsList(ctx context.Context, namespace, labelSelector string) ([]Secret, error) {
+	secrets, err := k.AccessControlClientset().CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Secret, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		ret = append(ret, *newSecret(&secret))
+	}
+	return ret, nil
+}
+
+// SecretsGet returns the Secret with its values masked, or decoded when reveal is requested and allowed by the
+// server configuration (allow_secret_reveal)
+func (k *Kubernetes) SecretsGet(ctx context.Context, namespace, name string, reveal bool) (*Secret, error) {
+	if reveal && !k.AccessControlClientset().staticConfig.AllowSecretReveal {
+		return nil, errors.New("revealing Secret values is disabled, set allow_secret_reveal = true in the server configuration to enable it")
+	}
+	secret, err := k.AccessControlClientset().CoreV1().Secrets(k.NamespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := newSecret(secret)
+	ret.Data = make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		switch {
+		case !reveal:
+			ret.Data[key] = output.RedactedValue(len(value))
+		case utf8.Valid(value):
+			ret.Data[key] = string(value)
+		default:
+			ret.Data[key] = "base64:" + base64.StdEncoding.EncodeToString(value)
+		}
+	}
+	ret.Revealed = reveal
+	return ret, nil
+}
+
+func newSecret(secret *v1.Secret) *Secret {
+	ret := &Secret{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+		Type:      string(secret.Type),
+		Immutable: secret.Immutable != nil && *secret.Immutable,
+		Labels:    secret.Labels,
+		Keys:      make([]string, 0, len(secret.Data)),
+	}
+	if !secret.CreationTimestamp.IsZero() {
+		ret.CreationTimestamp = secret.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	for key := range secret.Data {
+		ret.Keys = append(ret.Keys, key)
+	}
+	slices.Sort(ret.Keys)
+	return ret
+}
@@ -0,0 +1,157 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ToolAllowed is the capability of the tools whose permissions are all granted
+	ToolAllowed = "allowed"
+	// ToolDenied is the capability of the tools missing some of their permissions
+	ToolDenied = "denied"
+	// ToolUnchecked is the capability of the tools that don't declare their permissions (e.g. they depend on the
+	// resource kind provided in the arguments) or whose permissions couldn't be reviewed
+	ToolUnchecked = "unchecked"
+)
+
+// SelfCheck is the result of the verification of the connectivity, optional APIs and permissions of the cluster
+type SelfCheck struct {
+	Reachable     bool   `json:"reachable"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// Error is the reason why the API server is not reachable
+	Error string `json:"error,omitempty"`
+	// Namespace is the namespace where the permissions of the namespaced resources are checked (all namespaces if empty)
+	Namespace string            `json:"namespace,omitempty"`
+	APIs      []APIAvailability `json:"apis,omitempty"`
+	Tools     []ToolCapability  `json:"tools,omitempty"`
+}
+
+// APIAvailability reports whether an optional API used by some tools is served by the cluster
+type APIAvailability struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	// Detail describes the API or why it's not available
+	Detail string `json:"detail,omitempty"`
+}
+
+// ToolCapability reports whether the current user is granted the permissions needed by a tool
+type ToolCapability struct {
+	Tool string `json:"tool"`
+	// Status is allowed, denied, or unchecked
+	Status string `json:"status"`
+	// Denied are the permissions the current user lacks
+	Denied []string `json:"denied,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// SelfCheck verifies the API server is reachable, detects the optional APIs (metrics, node log query, OpenShift
+// routes) and reviews the permissions of the provided tools with SelfSubjectAccessReviews.
+// Tools with no permissions (nil) are reported as unchecked.
+func (k *Kubernetes) SelfCheck(ctx context.Context, namespace string, tools map[string][]ResourcePermission) *SelfCheck {
+	selfCheck := &SelfCheck{Namespace: namespace}
+	serverVersion, err := k.AccessControlClientset().DiscoveryClient().ServerVersion()
+	if err != nil {
+		selfCheck.Error = err.Error()
+		return selfCheck
+	}
+	selfCheck.Reachable = true
+	selfCheck.ServerVersion = serverVersion.GitVersion
+	selfCheck.APIs = []APIAvailability{
+		k.groupVersionAvailability("metrics", "metrics.k8s.io/v1beta1", "required by pods_top and nodes_top"),
+		k.nodeLogQueryAvailability(ctx),
+		k.groupVersionAvailability("routes", "route.openshift.io/v1", "OpenShift routes, used to expose pods_run"),
+	}
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// the same permission is usually needed by several tools, it's reviewed once
+	reviewed := map[ResourcePermission]error{}
+	allowed := map[ResourcePermission]bool{}
+	for _, name := range names {
+		capability := ToolCapability{Tool: name, Status: ToolAllowed}
+		if tools[name] == nil {
+			capability.Status = ToolUnchecked
+		}
+		for _, permission := range tools[name] {
+			if _, ok := reviewed[permission]; !ok {
+				allowed[permission], _, reviewed[permission] = k.CanI(ctx, permission, namespace, "")
+			}
+			if err := reviewed[permission]; err != nil {
+				capability.Status = ToolUnchecked
+				capability.Error = fmt.Sprintf("failed to review %s: %v", permission, err)
+				break
+			}
+			if !allowed[permission] {
+				capability.Status = ToolDenied
+				capability.Denied = append(capability.Denied, permission.String())
+			}
+		}
+		selfCheck.Tools = append(selfCheck.Tools, capability)
+	}
+	return selfCheck
+}
+
+// Summary returns a one line description of the self check
+func (c *SelfCheck) Summary() string {
+	if !c.Reachable {
+		return "API server is not reachable: " + c.Error
+	}
+	counts := map[string]int{}
+	for _, tool := range c.Tools {
+		counts[tool.Status]++
+	}
+	var apis []string
+	for _, availability := range c.APIs {
+		if availability.Available {
+			apis = append(apis, availability.Name)
+		}
+	}
+	if len(apis) == 0 {
+		apis = []string{"none"}
+	}
+	return fmt.Sprintf("API server reachable (%s), optional APIs available: %s, tools: %d allowed, %d denied, %d unchecked",
+		c.ServerVersion, strings.Join(apis, ", "), counts[ToolAllowed], counts[ToolDenied], counts[ToolUnchecked])
+}
+
+func (k *Kubernetes) groupVersionAvailability(name, groupVersion, detail string) APIAvailability {
+	availability := APIAvailability{Name: name, Available: k.supportsGroupVersion(groupVersion), Detail: groupVersion + ", " + detail}
+	if !availability.Available {
+		availability.Detail = groupVersion + " is not served by the cluster"
+	}
+	return availability
+}
+
+// nodeLogQueryAvailability queries the kubelet log of a node, the node log query requires the NodeLogQuery feature
+// gate and the enableSystemLogQuery kubelet setting
+func (k *Kubernetes) nodeLogQueryAvailability(ctx context.Context) APIAvailability {
+	availability := APIAvailability{Name: "node log query"}
+	nodes, err := k.AccessControlClientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+	switch {
+	case err != nil:
+		availability.Detail = fmt.Sprintf("failed to list nodes: %v", err)
+		return availability
+	case len(nodes.Items) == 0:
+		availability.Detail = "the cluster has no nodes"
+		return availability
+	}
+	node := nodes.Items[0].Name
+	err = k.AccessControlClientset().CoreV1().RESTClient().Get().
+		AbsPath("api", "v1", "nodes", node, "proxy", "logs").
+		Param("query", "kubelet").
+		Param("tailLines", "1").
+		Do(ctx).Error()
+	if err != nil {
+		availability.Detail = fmt.Sprintf("failed to query the logs of node %s (NodeLogQuery feature gate and enableSystemLogQuery kubelet setting required): %v", node, err)
+		return availability
+	}
+	availability.Available = true
+	availability.Detail = "/api/v1/nodes/{node}/proxy/logs, required by nodes_log"
+	return availability
+}
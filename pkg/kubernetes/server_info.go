@@ -0,0 +1,125 @@
+package kubernetes
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+// ServerInfo describes the running MCP server, its configured clusters and the limits that apply to the tools
+type ServerInfo struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	CommitHash string `json:"commitHash,omitempty"`
+	BuildTime  string `json:"buildTime,omitempty"`
+	// Toolsets are the names of the enabled toolsets
+	Toolsets      []string `json:"toolsets"`
+	EnabledTools  []string `json:"enabledTools,omitempty"`
+	DisabledTools []string `json:"disabledTools,omitempty"`
+	// ClusterProviderStrategy is the resolved strategy used to find the clusters
+	ClusterProviderStrategy string `json:"clusterProviderStrategy"`
+	// Clusters are the names of the configured clusters (kubeconfig contexts), no server URLs or credentials
+	Clusters       []string           `json:"clusters,omitempty"`
+	DefaultCluster string             `json:"defaultCluster,omitempty"`
+	Limits         ServerInfoLimits   `json:"limits"`
+	Features       ServerInfoFeatures `json:"features"`
+}
+
+type ServerInfoLimits struct {
+	// ToolProfile is the configured tool profile (read-only, operator, admin)
+	ToolProfile        string `json:"toolProfile,omitempty"`
+	ReadOnly           bool   `json:"readOnly"`
+	DisableDestructive bool   `json:"disableDestructive"`
+	DryRun             bool   `json:"dryRun"`
+	// RequireConfirmation is true if destructive tools need to be confirmed with confirm_action
+	RequireConfirmation bool `json:"requireConfirmation"`
+	// BreakGlassMaxDuration is set if the destructive tools must be enabled for a bounded time window with elevate
+	BreakGlassMaxDuration string `json:"breakGlassMaxDuration,omitempty"`
+	// AllowSecretReveal is true if the secrets tool can return the decoded Secret values
+	AllowSecretReveal bool `json:"allowSecretReveal"`
+	// OutputMaxBytes is the size above which the tool outputs are truncated (paginated with continue_result)
+	OutputMaxBytes int `json:"outputMaxBytes,omitempty"`
+	// ToolCallsPerMinute and MaxConcurrentDestructiveToolCalls are the per-session tool call limits (0 if unlimited)
+	ToolCallsPerMinute                int `json:"toolCallsPerMinute,omitempty"`
+	MaxConcurrentDestructiveToolCalls int `json:"maxConcurrentDestructiveToolCalls,omitempty"`
+	// DeniedResources summarizes the denied_resources configuration, e.g. "rbac.authorization.k8s.io/v1 Role" or "v1 *"
+	DeniedResources []string `json:"deniedResources,omitempty"`
+}
+
+type ServerInfoFeatures struct {
+	RequireOAuth        bool   `json:"requireOAuth"`
+	ValidateToken       bool   `json:"validateToken"`
+	StsTokenExchange    bool   `json:"stsTokenExchange"`
+	EventStore          bool   `json:"eventStore"`
+	EventStoreSize      int    `json:"eventStoreSize,omitempty"`
+	HelperImageRegistry string `json:"helperImageRegistry,omitempty"`
+	ListOutput          string `json:"listOutput,omitempty"`
+}
+
+// ServerInfo reports the server version, enabled toolsets, configured clusters, active limits and feature flags.
+// Only names and flags are reported, sensitive settings (server URLs, credentials, client secrets) are never exposed.
+func (k *Kubernetes) ServerInfo() (*ServerInfo, error) {
+	cfg := config.Default()
+	if k.AccessControlClientset().staticConfig != nil {
+		cfg = k.AccessControlClientset().staticConfig.Snapshot()
+	}
+	info := &ServerInfo{
+		Name:                    version.BinaryName,
+		Version:                 version.Version,
+		CommitHash:              version.CommitHash,
+		BuildTime:               version.BuildTime,
+		Toolsets:                cfg.Toolsets,
+		EnabledTools:            cfg.EnabledTools,
+		DisabledTools:           cfg.DisabledTools,
+		ClusterProviderStrategy: resolveStrategy(cfg),
+		Limits: ServerInfoLimits{
+			ToolProfile:                       cfg.ToolProfile,
+			ReadOnly:                          cfg.IsReadOnly(),
+			DisableDestructive:                cfg.IsDestructiveDisabled(),
+			DryRun:                            cfg.DryRun,
+			RequireConfirmation:               cfg.RequireConfirmation,
+			AllowSecretReveal:                 cfg.AllowSecretReveal,
+			BreakGlassMaxDuration:             cfg.BreakGlassMaxDuration,
+			OutputMaxBytes:                    cfg.OutputMaxBytes,
+			ToolCallsPerMinute:                cfg.ToolCallsPerMinute,
+			MaxConcurrentDestructiveToolCalls: cfg.MaxConcurrentDestructiveToolCalls,
+		},
+		Features: ServerInfoFeatures{
+			RequireOAuth:        cfg.RequireOAuth,
+			ValidateToken:       cfg.ValidateToken,
+			StsTokenExchange:    cfg.StsClientId != "",
+			EventStore:          cfg.EventStoreSize > 0,
+			EventStoreSize:      cfg.EventStoreSize,
+			HelperImageRegistry: cfg.HelperImageRegistry,
+			ListOutput:          cfg.ListOutput,
+		},
+	}
+	for _, gvk := range cfg.DeniedResources {
+		groupVersion := gvk.Version
+		if gvk.Group != "" {
+			groupVersion = gvk.Group + "/" + gvk.Version
+		}
+		kind := gvk.Kind
+		if kind == "" {
+			kind = "*"
+		}
+		info.Limits.DeniedResources = append(info.Limits.DeniedResources, groupVersion+" "+kind)
+	}
+	defaultContext, err := k.ConfigurationContextsDefault()
+	if err != nil {
+		return nil, err
+	}
+	info.DefaultCluster = defaultContext
+	if info.ClusterProviderStrategy == config.ClusterProviderKubeConfig {
+		contexts, err := k.ConfigurationContextsList()
+		if err != nil {
+			return nil, err
+		}
+		info.Clusters = slices.Sorted(maps.Keys(contexts))
+	} else if defaultContext != "" {
+		info.Clusters = []string{defaultContext}
+	}
+	return info, nil
+}
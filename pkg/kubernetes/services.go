@@ -0,0 +1,364 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// serviceInspectionMaxNearMisses is the maximum number of Pods reported as partially matching the Service selector
+	serviceInspectionMaxNearMisses = 5
+	// serviceProbeTimeoutSeconds is the connection timeout of each of the Service port probes
+	serviceProbeTimeoutSeconds = 5
+)
+
+// ServiceInspection describes the connectivity of a Service: the Pods matching its selector, the endpoints
+// published in its EndpointSlices, the detected issues (selector mismatches, not ready endpoints, unresolved
+// target ports), and optionally the result of probing the Service ports from a short-lived helper pod.
+type ServiceInspection struct {
+	// Summary is a one line description of the Service endpoints, e.g. "Service web has 2/3 ready endpoints"
+	Summary      string                  `json:"summary"`
+	Namespace    string                  `json:"namespace"`
+	Name         string                  `json:"name"`
+	Type         v1.ServiceType          `json:"type"`
+	ClusterIP    string                  `json:"clusterIP,omitempty"`
+	ExternalName string                  `json:"externalName,omitempty"`
+	Selector     map[string]string       `json:"selector,omitempty"`
+	Ports        []ServiceInspectionPort `json:"ports,omitempty"`
+	// Pods are the Pods matching the Service selector
+	Pods []ServiceInspectionPod `json:"pods,omitempty"`
+	// Endpoints are the endpoints published in the EndpointSlices of the Service
+	Endpoints []ServiceEndpoint `json:"endpoints,omitempty"`
+	Issues    []string          `json:"issues,omitempty"`
+	// Probes are the results of the TCP connection tests to the Service ports (only when requested)
+	Probes   []ServiceProbe `json:"probes,omitempty"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+type ServiceInspectionPort struct {
+	Name       string      `json:"name,omitempty"`
+	Port       int32       `json:"port"`
+	TargetPort string      `json:"targetPort"`
+	Protocol   v1.Protocol `json:"protocol"`
+	NodePort   int32       `json:"nodePort,omitempty"`
+}
+
+type ServiceInspectionPod struct {
+	Name  string      `json:"name"`
+	Phase v1.PodPhase `json:"phase"`
+	Ready bool        `json:"ready"`
+	IP    string      `json:"ip,omitempty"`
+	Node  string      `json:"node,omitempty"`
+}
+
+type ServiceEndpoint struct {
+	// EndpointSlice is the name of the EndpointSlice publishing the endpoint
+	EndpointSlice string   `json:"endpointSlice"`
+	Addresses     []string `json:"addresses"`
+	Ready         bool     `json:"ready"`
+	Serving       bool     `json:"serving"`
+	Terminating   bool     `json:"terminating,omitempty"`
+	// TargetRef is the object backing the endpoint (Kind/name), usually a Pod
+	TargetRef string `json:"targetRef,omitempty"`
+	Node      string `json:"node,omitempty"`
+	Zone      string `json:"zone,omitempty"`
+}
+
+type ServiceProbe struct {
+	// Target is the address and port probed, e.g. web.ns-1.svc:80
+	Target  string `json:"target"`
+	Success bool   `json:"success"`
+	Output  string `json:"output,omitempty"`
+}
+
+// ServicesInspect returns the ServiceInspection of the Service with the provided name.
+// When probe is true, the TCP ports of the Service are probed from a short-lived helper pod,
+// failures running the helper pod don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) ServicesInspect(ctx context.Context, namespace, name string, probe bool) (*ServiceInspection, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	service, err := k.AccessControlClientset().CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var pods []v1.Pod
+	if len(service.Spec.Selector) > 0 {
+		podList, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		pods = podList.Items
+	}
+	var endpointSlices []discoveryv1.EndpointSlice
+	if service.Spec.Type != v1.ServiceTypeExternalName {
+		sliceList, err := k.AccessControlClientset().DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.Set{discoveryv1.LabelServiceName: name}.String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list endpoint slices: %w", err)
+		}
+		endpointSlices = sliceList.Items
+	}
+	inspection := AnalyzeService(service, pods, endpointSlices)
+	if probe {
+		probes, err := k.probeService(ctx, service)
+		if err != nil {
+			inspection.Warnings = append(inspection.Warnings, fmt.Sprintf("unable to probe the service: %v", err))
+		}
+		inspection.Probes = probes
+	}
+	return inspection, nil
+}
+
+// probeService tests the TCP connection to each of the Service ports through the Service DNS name from a helper pod
+func (k *Kubernetes) probeService(ctx context.Context, service *v1.Service) ([]ServiceProbe, error) {
+	host := service.Name + "." + service.Namespace + ".svc"
+	if service.Spec.Type == v1.ServiceTypeExternalName {
+		host = service.Spec.ExternalName
+	}
+	// The host and ports are passed as positional parameters so that they're never interpreted by the shell
+	script := fmt.Sprintf(`host="$1"; shift; for port in "$@"; do `+
+		`if out=$(nc -z -v -w %d "$host" "$port" 2>&1); then echo "ok $host:$port"; `+
+		`else echo "failed $host:$port $(echo $out | tr '\n' ' ')"; fi; done`, serviceProbeTimeoutSeconds)
+	command := []string{"sh", "-c", script, "sh", host}
+	for _, port := range service.Spec.Ports {
+		if port.Protocol == "" || port.Protocol == v1.ProtocolTCP {
+			command = append(command, strconv.Itoa(int(port.Port)))
+		}
+	}
+	if len(command) == 5 {
+		return nil, nil
+	}
+	out, err := k.RunHelperPod(ctx, HelperPodOptions{Role: HelperImageNetworkTest, Command: command})
+	if err != nil {
+		return nil, err
+	}
+	return parseServiceProbes(out), nil
+}
+
+func parseServiceProbes(out string) []ServiceProbe {
+	var probes []ServiceProbe
+	for _, line := range strings.Split(out, "\n") {
+		status, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if status != "ok" && status != "failed" {
+			continue
+		}
+		target, output, _ := strings.Cut(rest, " ")
+		probes = append(probes, ServiceProbe{Target: target, Success: status == "ok", Output: strings.TrimSpace(output)})
+	}
+	return probes
+}
+
+// AnalyzeService inspects the Service given the Pods of its namespace and its EndpointSlices
+func AnalyzeService(service *v1.Service, pods []v1.Pod, endpointSlices []discoveryv1.EndpointSlice) *ServiceInspection {
+	inspection := &ServiceInspection{
+		Namespace:    service.Namespace,
+		Name:         service.Name,
+		Type:         service.Spec.Type,
+		ClusterIP:    service.Spec.ClusterIP,
+		ExternalName: service.Spec.ExternalName,
+		Selector:     service.Spec.Selector,
+	}
+	if inspection.Type == "" {
+		inspection.Type = v1.ServiceTypeClusterIP
+	}
+	for _, port := range service.Spec.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		targetPort := serviceTargetPort(port)
+		inspection.Ports = append(inspection.Ports, ServiceInspectionPort{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: targetPort.String(),
+			Protocol:   protocol,
+			NodePort:   port.NodePort,
+		})
+	}
+	if service.Spec.Type == v1.ServiceTypeExternalName {
+		inspection.Summary = fmt.Sprintf("Service %s is an ExternalName Service resolved by DNS to %s, it has no endpoints", service.Name, service.Spec.ExternalName)
+		return inspection
+	}
+
+	// Pods matching the selector and partially matching ones (likely label typos or stale labels)
+	var matching []*v1.Pod
+	var nearMisses []string
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for i := range pods {
+		pod := &pods[i]
+		if len(service.Spec.Selector) == 0 || isTerminalPod(pod) {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matching = append(matching, pod)
+			inspection.Pods = append(inspection.Pods, ServiceInspectionPod{
+				Name: pod.Name, Phase: pod.Status.Phase, Ready: isPodReady(pod), IP: pod.Status.PodIP, Node: pod.Spec.NodeName,
+			})
+			continue
+		}
+		if mismatches := labelMismatches(service.Spec.Selector, pod.Labels, "Pod"); len(mismatches) < len(service.Spec.Selector) && len(nearMisses) < serviceInspectionMaxNearMisses {
+			nearMisses = append(nearMisses, fmt.Sprintf("%s (%s)", pod.Name, strings.Join(mismatches, ", ")))
+		}
+	}
+	selectorString := selector.String()
+	switch {
+	case len(service.Spec.Selector) == 0:
+		inspection.Issues = append(inspection.Issues,
+			"Service has no selector, its EndpointSlices are not managed by Kubernetes and must be created manually or by another controller")
+	case len(matching) == 0:
+		issue := fmt.Sprintf("selector %s matches no running Pods in namespace %s", selectorString, service.Namespace)
+		if len(nearMisses) > 0 {
+			issue += ", Pods partially matching the selector: " + strings.Join(nearMisses, "; ")
+		}
+		inspection.Issues = append(inspection.Issues, issue)
+	default:
+		var notReady []string
+		for _, pod := range inspection.Pods {
+			if !pod.Ready {
+				notReady = append(notReady, pod.Name)
+			}
+		}
+		if len(notReady) > 0 {
+			inspection.Issues = append(inspection.Issues, fmt.Sprintf("%d of %d Pods matching the selector are not ready (failing readiness probes or not started): %s",
+				len(notReady), len(matching), strings.Join(notReady, ", ")))
+		}
+	}
+
+	// Target ports not exposed by the matching Pods
+	for _, port := range service.Spec.Ports {
+		targetPort := serviceTargetPort(port)
+		var unresolved []string
+		for _, pod := range matching {
+			if !podExposesPort(pod, targetPort, port.Protocol) {
+				unresolved = append(unresolved, pod.Name)
+			}
+		}
+		if len(unresolved) == 0 {
+			continue
+		}
+		portName := strconv.Itoa(int(port.Port))
+		if port.Name != "" {
+			portName = port.Name
+		}
+		if targetPort.Type == intstr.String {
+			inspection.Issues = append(inspection.Issues, fmt.Sprintf(
+				"targetPort %s of port %s is not a named container port of Pods %s, they're not published as endpoints of this port",
+				targetPort.StrVal, portName, strings.Join(unresolved, ", ")))
+		} else {
+			inspection.Issues = append(inspection.Issues, fmt.Sprintf(
+				"targetPort %d of port %s is not declared in the container ports of Pods %s, make sure the application listens on it",
+				targetPort.IntVal, portName, strings.Join(unresolved, ", ")))
+		}
+	}
+
+	// Endpoints published in the EndpointSlices
+	ready := 0
+	for _, slice := range endpointSlices {
+		for _, endpoint := range slice.Endpoints {
+			e := ServiceEndpoint{
+				EndpointSlice: slice.Name,
+				Addresses:     endpoint.Addresses,
+				Ready:         endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready,
+				Serving:       endpoint.Conditions.Serving == nil || *endpoint.Conditions.Serving,
+				Terminating:   endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating,
+			}
+			if endpoint.TargetRef != nil {
+				e.TargetRef = endpoint.TargetRef.Kind + "/" + endpoint.TargetRef.Name
+			}
+			if endpoint.NodeName != nil {
+				e.Node = *endpoint.NodeName
+			}
+			if endpoint.Zone != nil {
+				e.Zone = *endpoint.Zone
+			}
+			if e.Ready {
+				ready++
+			}
+			inspection.Endpoints = append(inspection.Endpoints, e)
+		}
+	}
+	sort.SliceStable(inspection.Endpoints, func(i, j int) bool {
+		return strings.Join(inspection.Endpoints[i].Addresses, ",") < strings.Join(inspection.Endpoints[j].Addresses, ",")
+	})
+	switch {
+	case len(inspection.Endpoints) == 0:
+		inspection.Issues = append(inspection.Issues, "Service has no endpoints, connections to the Service are refused or time out")
+	case ready == 0:
+		inspection.Issues = append(inspection.Issues, fmt.Sprintf("none of the %d endpoints are ready, connections to the Service are refused or time out", len(inspection.Endpoints)))
+	case ready < len(inspection.Endpoints):
+		var notReady []string
+		for _, e := range inspection.Endpoints {
+			if !e.Ready {
+				notReady = append(notReady, strings.Join(e.Addresses, ","))
+			}
+		}
+		inspection.Issues = append(inspection.Issues, fmt.Sprintf("%d of %d endpoints are not ready: %s",
+			len(notReady), len(inspection.Endpoints), strings.Join(notReady, ", ")))
+	}
+	if service.Spec.PublishNotReadyAddresses {
+		inspection.Issues = append(inspection.Issues, "publishNotReadyAddresses is enabled, Pods receive traffic before they're ready")
+	}
+
+	inspection.Summary = fmt.Sprintf("Service %s has %d/%d ready endpoints", service.Name, ready, len(inspection.Endpoints))
+	if len(service.Spec.Selector) > 0 {
+		inspection.Summary += fmt.Sprintf(", %d Pods match the selector %s", len(matching), selectorString)
+	}
+	return inspection
+}
+
+// serviceTargetPort returns the targetPort of the Service port, which defaults to the port
+func serviceTargetPort(port v1.ServicePort) intstr.IntOrString {
+	if port.TargetPort.Type == intstr.String && port.TargetPort.StrVal != "" {
+		return port.TargetPort
+	}
+	if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal != 0 {
+		return port.TargetPort
+	}
+	return intstr.FromInt32(port.Port)
+}
+
+// podExposesPort returns true if the named target port is a container port of the Pod, or if the numeric target port
+// is declared in the container ports (or no container ports are declared, since declaring them is optional)
+func podExposesPort(pod *v1.Pod, targetPort intstr.IntOrString, protocol v1.Protocol) bool {
+	if protocol == "" {
+		protocol = v1.ProtocolTCP
+	}
+	declared := false
+	for _, containers := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			for _, port := range container.Ports {
+				declared = true
+				portProtocol := port.Protocol
+				if portProtocol == "" {
+					portProtocol = v1.ProtocolTCP
+				}
+				if portProtocol != protocol {
+					continue
+				}
+				if (targetPort.Type == intstr.String && port.Name == targetPort.StrVal) ||
+					(targetPort.Type == intstr.Int && port.ContainerPort == targetPort.IntVal) {
+					return true
+				}
+			}
+		}
+	}
+	return targetPort.Type == intstr.Int && !declared
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+type ServicesSuite struct {
+	suite.Suite
+}
+
+func servicesTestPod(name string, podLabels map[string]string, ready bool, ports ...v1.ContainerPort) v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: name, Labels: podLabels},
+		Spec:       v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "app", Ports: ports}}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning, Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+	}
+}
+
+func servicesTestService(selector map[string]string, ports ...v1.ServicePort) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: "10.96.0.10", Selector: selector, Ports: ports},
+	}
+}
+
+func servicesTestEndpointSlice(endpoints ...discoveryv1.Endpoint) []discoveryv1.EndpointSlice {
+	return []discoveryv1.EndpointSlice{{ObjectMeta: metav1.ObjectMeta{Name: "web-abcde"}, Endpoints: endpoints}}
+}
+
+func (s *ServicesSuite) TestAnalyzeService() {
+	s.Run("healthy service", func() {
+		service := servicesTestService(map[string]string{"app": "web"}, v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("http")})
+		pods := []v1.Pod{servicesTestPod("web-1", map[string]string{"app": "web"}, true, v1.ContainerPort{Name: "http", ContainerPort: 8080})}
+		slices := servicesTestEndpointSlice(discoveryv1.Endpoint{
+			Addresses:  []string{"10.244.0.5"},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+			TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+			NodeName:   ptr.To("node-1"),
+		})
+		inspection := AnalyzeService(service, pods, slices)
+		s.Equal("Service web has 1/1 ready endpoints, 1 Pods match the selector app=web", inspection.Summary)
+		s.Empty(inspection.Issues)
+		s.Equal([]ServiceEndpoint{{EndpointSlice: "web-abcde", Addresses: []string{"10.244.0.5"}, Ready: true, Serving: true, TargetRef: "Pod/web-1", Node: "node-1"}},
+			inspection.Endpoints)
+		s.Equal("http", inspection.Ports[0].TargetPort)
+	})
+	s.Run("selector matching no pods reports the partially matching ones", func() {
+		service := servicesTestService(map[string]string{"app": "web", "tier": "frontend"}, v1.ServicePort{Port: 80})
+		pods := []v1.Pod{
+			servicesTestPod("web-1", map[string]string{"app": "web", "tier": "front"}, true),
+			servicesTestPod("db-1", map[string]string{"app": "db"}, true),
+		}
+		inspection := AnalyzeService(service, pods, nil)
+		s.Equal("Service web has 0/0 ready endpoints, 0 Pods match the selector app=web,tier=frontend", inspection.Summary)
+		s.Equal([]string{
+			"selector app=web,tier=frontend matches no running Pods in namespace ns-1, Pods partially matching the selector: web-1 (tier=frontend (Pod: front))",
+			"Service has no endpoints, connections to the Service are refused or time out",
+		}, inspection.Issues)
+	})
+	s.Run("not ready pods and endpoints", func() {
+		service := servicesTestService(map[string]string{"app": "web"}, v1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(8080)})
+		pods := []v1.Pod{
+			servicesTestPod("web-1", map[string]string{"app": "web"}, true),
+			servicesTestPod("web-2", map[string]string{"app": "web"}, false),
+		}
+		slices := servicesTestEndpointSlice(
+			discoveryv1.Endpoint{Addresses: []string{"10.244.0.5"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)}},
+			discoveryv1.Endpoint{Addresses: []string{"10.244.0.6"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false), Serving: ptr.To(false)}},
+		)
+		inspection := AnalyzeService(service, pods, slices)
+		s.Equal("Service web has 1/2 ready endpoints, 2 Pods match the selector app=web", inspection.Summary)
+		s.Equal([]string{
+			"1 of 2 Pods matching the selector are not ready (failing readiness probes or not started): web-2",
+			"1 of 2 endpoints are not ready: 10.244.0.6",
+		}, inspection.Issues)
+	})
+	s.Run("target ports not exposed by the pods", func() {
+		service := servicesTestService(map[string]string{"app": "web"},
+			v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+			v1.ServicePort{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt32(9090)},
+		)
+		pods := []v1.Pod{servicesTestPod("web-1", map[string]string{"app": "web"}, true, v1.ContainerPort{Name: "web", ContainerPort: 8080})}
+		slices := servicesTestEndpointSlice(discoveryv1.Endpoint{Addresses: []string{"10.244.0.5"}})
+		inspection := AnalyzeService(service, pods, slices)
+		s.Equal([]string{
+			"targetPort http of port http is not a named container port of Pods web-1, they're not published as endpoints of this port",
+			"targetPort 9090 of port metrics is not declared in the container ports of Pods web-1, make sure the application listens on it",
+		}, inspection.Issues)
+	})
+	s.Run("service without selector", func() {
+		inspection := AnalyzeService(servicesTestService(nil, v1.ServicePort{Port: 5432}), nil, nil)
+		s.Equal("Service web has 0/0 ready endpoints", inspection.Summary)
+		s.Len(inspection.Issues, 2)
+		s.Contains(inspection.Issues[0], "Service has no selector")
+	})
+	s.Run("ExternalName service", func() {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "db"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "db.example.com"},
+		}
+		inspection := AnalyzeService(service, nil, nil)
+		s.Equal("Service db is an ExternalName Service resolved by DNS to db.example.com, it has no endpoints", inspection.Summary)
+		s.Empty(inspection.Issues)
+	})
+}
+
+func (s *ServicesSuite) TestParseServiceProbes() {
+	probes := parseServiceProbes("ok web.ns-1.svc:80\nfailed web.ns-1.svc:443 nc: connect to web.ns-1.svc port 443 (tcp) timed out\n\n")
+	s.Equal([]ServiceProbe{
+		{Target: "web.ns-1.svc:80", Success: true},
+		{Target: "web.ns-1.svc:443", Output: "nc: connect to web.ns-1.svc port 443 (tcp) timed out"},
+	}, probes)
+}
+
+func TestServices(t *testing.T) {
+	suite.Run(t, new(ServicesSuite))
+}
@@ -0,0 +1,397 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	// SnapshotChangeAdded is the change of the objects created after the snapshot
+	SnapshotChangeAdded = "added"
+	// SnapshotChangeRemoved is the change of the objects deleted after the snapshot
+	SnapshotChangeRemoved = "removed"
+	// SnapshotChangeModified is the change of the objects updated after the snapshot
+	SnapshotChangeModified = "modified"
+	// SnapshotMaxObjects is the maximum number of objects of a snapshot, to keep the artifacts and the diffs small
+	SnapshotMaxObjects = 5000
+	// SnapshotMaxFieldDiffs is the maximum number of field diffs reported per modified object
+	SnapshotMaxFieldDiffs = 50
+	// snapshotArtifactPrefix is the first part of the names of the snapshot artifacts
+	snapshotArtifactPrefix = "snapshot"
+)
+
+// snapshotIgnoredFields change on every update of the objects and are not reported as field diffs
+var snapshotIgnoredFields = []string{"metadata.resourceVersion", "metadata.generation", "metadata.managedFields"}
+
+// SnapshotOptions selects the objects captured by a snapshot
+type SnapshotOptions struct {
+	// Resources are the resource types of the objects, e.g. deployments, Deployment.apps, configmaps
+	Resources []string
+	// Namespaces of the namespaced objects, the current namespace if empty
+	Namespaces []string
+	// AllNamespaces captures the namespaced objects of all namespaces, Namespaces are ignored
+	AllNamespaces bool
+	LabelSelector string
+	// IncludeStatus captures the status of the objects, which is otherwise left out of the snapshot and the diffs
+	IncludeStatus bool
+}
+
+// Snapshot is the content of a snapshot artifact
+type Snapshot struct {
+	Created time.Time `json:"created"`
+	// Kinds are the resolved resource types of the snapshot, listed again by the diff
+	Kinds         []SnapshotKind   `json:"kinds"`
+	Namespaces    []string         `json:"namespaces,omitempty"`
+	AllNamespaces bool             `json:"allNamespaces,omitempty"`
+	LabelSelector string           `json:"labelSelector,omitempty"`
+	IncludeStatus bool             `json:"includeStatus,omitempty"`
+	Objects       []SnapshotObject `json:"objects"`
+}
+
+type SnapshotKind struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+	// resource is the resolved GroupVersionResource, not persisted
+	resource schema.GroupVersionResource
+}
+
+type SnapshotObject struct {
+	APIVersion      string         `json:"apiVersion"`
+	Kind            string         `json:"kind"`
+	Namespace       string         `json:"namespace,omitempty"`
+	Name            string         `json:"name"`
+	ResourceVersion string         `json:"resourceVersion"`
+	Object          map[string]any `json:"object"`
+}
+
+// SnapshotResult describes a stored snapshot
+type SnapshotResult struct {
+	Summary  string              `json:"summary"`
+	Artifact *artifacts.Artifact `json:"artifact"`
+	// Objects is the number of captured objects per kind
+	Objects map[string]int `json:"objects"`
+}
+
+// SnapshotDiff lists the changes of the live objects since the snapshot
+type SnapshotDiff struct {
+	Summary  string    `json:"summary"`
+	Snapshot string    `json:"snapshot"`
+	Created  time.Time `json:"created"`
+	// Changes of the objects, ordered by kind, namespace and name
+	Changes   []SnapshotChange `json:"changes"`
+	Unchanged int              `json:"unchanged"`
+}
+
+type SnapshotChange struct {
+	Change     string `json:"change"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Fields are the field-level diffs of the modified objects
+	Fields []SnapshotFieldDiff `json:"fields,omitempty"`
+	// FieldsTruncated is set when more than SnapshotMaxFieldDiffs fields changed
+	FieldsTruncated bool `json:"fieldsTruncated,omitempty"`
+}
+
+// SnapshotFieldDiff is a changed field, Old is missing for added fields and New for removed fields
+type SnapshotFieldDiff struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// SnapshotCreate captures the selected objects and stores them as a JSON artifact, to be compared with the live
+// objects by SnapshotDiff.
+// Secret values are redacted, their changes are only detected through the resourceVersion of the Secrets.
+func (k *Kubernetes) SnapshotCreate(ctx context.Context, options SnapshotOptions) (*SnapshotResult, error) {
+	if len(options.Resources) == 0 {
+		return nil, errors.New("at least one resource type must be provided")
+	}
+	snapshot := &Snapshot{
+		Created:       time.Now().UTC(),
+		AllNamespaces: options.AllNamespaces,
+		LabelSelector: options.LabelSelector,
+		IncludeStatus: options.IncludeStatus,
+	}
+	if !options.AllNamespaces {
+		for _, namespace := range options.Namespaces {
+			if namespace != "" && !slices.Contains(snapshot.Namespaces, namespace) {
+				snapshot.Namespaces = append(snapshot.Namespaces, namespace)
+			}
+		}
+		if len(snapshot.Namespaces) == 0 {
+			snapshot.Namespaces = []string{k.NamespaceOrDefault("")}
+		}
+	}
+	for _, resource := range options.Resources {
+		kind, err := k.snapshotKindFor(resource)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(snapshot.Kinds, func(existing SnapshotKind) bool { return existing.resource == kind.resource }) {
+			snapshot.Kinds = append(snapshot.Kinds, *kind)
+		}
+	}
+	objects, err := k.snapshotList(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Objects = objects
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	store := k.NewArtifactStore()
+	artifact, err := store.Put(ctx, artifacts.NewName(snapshot.Created, ".json", snapshotArtifactPrefix), content)
+	if artifact == nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload artifact %s to %s: %w", artifact.Name, store.RemoteName(), err)
+	}
+	result := &SnapshotResult{Artifact: artifact, Objects: map[string]int{}}
+	for _, kind := range snapshot.Kinds {
+		result.Objects[kind.Kind] = 0
+	}
+	for _, object := range objects {
+		result.Objects[object.Kind]++
+	}
+	result.Summary = fmt.Sprintf("Snapshot of %d objects %s stored as artifact %s, compare it with the live objects with snapshot_diff",
+		len(objects), snapshot.scope(), artifact.URI)
+	return result, nil
+}
+
+// SnapshotDiff lists the objects added, removed and modified since the snapshot stored in the named artifact,
+// with the field-level diffs of the modified objects
+func (k *Kubernetes) SnapshotDiff(ctx context.Context, name string) (*SnapshotDiff, error) {
+	_, content, err := k.NewArtifactStore().Get(name)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{}
+	if err = json.Unmarshal(content, snapshot); err != nil || snapshot.Created.IsZero() {
+		return nil, fmt.Errorf("artifact %s is not a snapshot", name)
+	}
+	for i := range snapshot.Kinds {
+		gvk := schema.FromAPIVersionAndKind(snapshot.Kinds[i].APIVersion, snapshot.Kinds[i].Kind)
+		mapping, err := k.AccessControlClientset().RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve resource type %s %s: %w", snapshot.Kinds[i].APIVersion, snapshot.Kinds[i].Kind, err)
+		}
+		snapshot.Kinds[i].resource = mapping.Resource
+	}
+	live, err := k.snapshotList(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	diff := &SnapshotDiff{Snapshot: name, Created: snapshot.Created}
+	previous := make(map[string]SnapshotObject, len(snapshot.Objects))
+	for _, object := range snapshot.Objects {
+		previous[object.key()] = object
+	}
+	for _, object := range live {
+		old, found := previous[object.key()]
+		delete(previous, object.key())
+		switch {
+		case !found:
+			diff.Changes = append(diff.Changes, object.change(SnapshotChangeAdded))
+		case old.ResourceVersion == object.ResourceVersion:
+			diff.Unchanged++
+		default:
+			change := object.change(SnapshotChangeModified)
+			snapshotFieldDiffs(&change, "", old.Object, object.Object)
+			// The redacted Secret values don't reveal the changes of the data
+			if len(change.Fields) > 0 || object.Kind == "Secret" {
+				diff.Changes = append(diff.Changes, change)
+			} else {
+				diff.Unchanged++
+			}
+		}
+	}
+	for _, object := range previous {
+		diff.Changes = append(diff.Changes, object.change(SnapshotChangeRemoved))
+	}
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		return snapshotChangeKey(diff.Changes[i]) < snapshotChangeKey(diff.Changes[j])
+	})
+	counts := map[string]int{}
+	for _, change := range diff.Changes {
+		counts[change.Change]++
+	}
+	diff.Summary = fmt.Sprintf("%d added, %d removed, %d modified, %d unchanged objects %s since snapshot %s of %s",
+		counts[SnapshotChangeAdded], counts[SnapshotChangeRemoved], counts[SnapshotChangeModified], diff.Unchanged,
+		snapshot.scope(), name, snapshot.Created.Format(time.RFC3339))
+	return diff, nil
+}
+
+// snapshotKindFor resolves a resource type, e.g. deployments, deployment, Deployment.apps, deployments.v1.apps
+func (k *Kubernetes) snapshotKindFor(resource string) (*SnapshotKind, error) {
+	restMapper := k.AccessControlClientset().RESTMapper()
+	fullySpecified, groupResource := schema.ParseResourceArg(strings.ToLower(strings.TrimSpace(resource)))
+	var gvk schema.GroupVersionKind
+	var err error
+	if fullySpecified != nil {
+		gvk, err = restMapper.KindFor(*fullySpecified)
+	}
+	if fullySpecified == nil || err != nil {
+		gvk, err = restMapper.KindFor(groupResource.WithVersion(""))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource type %s: %w", resource, err)
+	}
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource type %s: %w", resource, err)
+	}
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return &SnapshotKind{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+		resource:   mapping.Resource,
+	}, nil
+}
+
+// snapshotList lists the objects selected by the snapshot, normalized and sorted by kind, namespace and name
+func (k *Kubernetes) snapshotList(ctx context.Context, snapshot *Snapshot) ([]SnapshotObject, error) {
+	var objects []SnapshotObject
+	for _, kind := range snapshot.Kinds {
+		namespaces := []string{metav1.NamespaceAll}
+		if kind.Namespaced && !snapshot.AllNamespaces {
+			namespaces = snapshot.Namespaces
+		}
+		for _, namespace := range namespaces {
+			list, err := k.AccessControlClientset().DynamicClient().Resource(kind.resource).Namespace(namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: snapshot.LabelSelector,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", kind.resource.GroupResource().String(), err)
+			}
+			for i := range list.Items {
+				objects = append(objects, snapshotObject(kind, &list.Items[i], snapshot.IncludeStatus))
+			}
+			if len(objects) > SnapshotMaxObjects {
+				return nil, fmt.Errorf("more than %d objects are selected, narrow the snapshot with fewer resource types, namespaces, or a label selector",
+					SnapshotMaxObjects)
+			}
+		}
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].key() < objects[j].key()
+	})
+	return objects, nil
+}
+
+func snapshotObject(kind SnapshotKind, obj *unstructured.Unstructured, includeStatus bool) SnapshotObject {
+	// The lists don't return the apiVersion and kind of their items
+	obj.SetAPIVersion(kind.APIVersion)
+	obj.SetKind(kind.Kind)
+	obj.SetManagedFields(nil)
+	if !includeStatus {
+		unstructured.RemoveNestedField(obj.Object, "status")
+	}
+	output.RedactSecrets(obj)
+	ret := SnapshotObject{
+		APIVersion:      kind.APIVersion,
+		Kind:            kind.Kind,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Object:          obj.Object,
+	}
+	// The live objects are compared with the ones decoded from the artifact, whose numbers are float64
+	if content, err := json.Marshal(obj.Object); err == nil {
+		_ = json.Unmarshal(content, &ret.Object)
+	}
+	return ret
+}
+
+func (o *SnapshotObject) key() string {
+	return o.APIVersion + "/" + o.Kind + "/" + o.Namespace + "/" + o.Name
+}
+
+func (o *SnapshotObject) change(change string) SnapshotChange {
+	return SnapshotChange{Change: change, APIVersion: o.APIVersion, Kind: o.Kind, Namespace: o.Namespace, Name: o.Name}
+}
+
+func snapshotChangeKey(c SnapshotChange) string {
+	return c.APIVersion + "/" + c.Kind + "/" + c.Namespace + "/" + c.Name
+}
+
+// snapshotFieldDiffs appends the paths of the fields that differ between the old and new values, the lists of the
+// same length are compared item by item
+func snapshotFieldDiffs(change *SnapshotChange, path string, oldValue, newValue any) {
+	if slices.Contains(snapshotIgnoredFields, path) || reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+	oldMap, oldIsMap := oldValue.(map[string]any)
+	newMap, newIsMap := newValue.(map[string]any)
+	if oldIsMap && newIsMap {
+		keys := make([]string, 0, len(oldMap)+len(newMap))
+		for key := range oldMap {
+			keys = append(keys, key)
+		}
+		for key := range newMap {
+			if _, found := oldMap[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			snapshotFieldDiffs(change, snapshotFieldPath(path, key), oldMap[key], newMap[key])
+		}
+		return
+	}
+	oldList, oldIsList := oldValue.([]any)
+	newList, newIsList := newValue.([]any)
+	if oldIsList && newIsList && len(oldList) == len(newList) {
+		for i := range oldList {
+			snapshotFieldDiffs(change, fmt.Sprintf("%s[%d]", path, i), oldList[i], newList[i])
+		}
+		return
+	}
+	if len(change.Fields) >= SnapshotMaxFieldDiffs {
+		change.FieldsTruncated = true
+		return
+	}
+	change.Fields = append(change.Fields, SnapshotFieldDiff{Path: path, Old: oldValue, New: newValue})
+}
+
+// snapshotFieldPath quotes the keys that aren't identifiers, e.g. metadata.labels["app.kubernetes.io/name"]
+func snapshotFieldPath(path, key string) string {
+	if strings.ContainsAny(key, "./[]\" ") || key == "" {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func (s *Snapshot) scope() string {
+	switch {
+	case s.AllNamespaces:
+		return "in all namespaces"
+	case len(s.Namespaces) == 1:
+		return "in namespace " + s.Namespaces[0]
+	default:
+		return "in namespaces " + strings.Join(s.Namespaces, ", ")
+	}
+}
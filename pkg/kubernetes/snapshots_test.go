@@ -0,0 +1,174 @@
+package kubernetes
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type SnapshotsSuite struct {
+	suite.Suite
+	mockServer  *test.MockServer
+	deployments []appsv1.Deployment
+	secrets     []v1.Secret
+}
+
+func (s *SnapshotsSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: []string{
+		`{"name":"secrets","singularName":"secret","namespaced":true,"kind":"Secret","verbs":["get","list","watch"]}`,
+	}})
+	s.deployments = []appsv1.Deployment{
+		snapshotDeployment("web", "1", 1, "web:1"),
+		snapshotDeployment("api", "1", 2, "api:1"),
+	}
+	s.secrets = []v1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db", ResourceVersion: "1"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/shop/deployments":
+			test.WriteObject(w, &appsv1.DeploymentList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DeploymentList"}, Items: s.deployments})
+		case "/api/v1/namespaces/shop/secrets":
+			test.WriteObject(w, &v1.SecretList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "SecretList"}, Items: s.secrets})
+		}
+	}))
+}
+
+func (s *SnapshotsSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *SnapshotsSuite) derived() *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[artifacts]
+		dir = "` + filepath.ToSlash(s.T().TempDir()) + `"
+	`)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func snapshotDeployment(name, resourceVersion string, replicas int32, image string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, ResourceVersion: resourceVersion, Labels: map[string]string{"app.kubernetes.io/name": name}},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: name, Image: image}}}},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: replicas},
+	}
+}
+
+func (s *SnapshotsSuite) TestSnapshotCreate() {
+	k := s.derived()
+	result, err := k.SnapshotCreate(s.T().Context(), SnapshotOptions{Resources: []string{"deployments.apps", "Secret"}, Namespaces: []string{"shop"}})
+	s.Require().NoError(err)
+	s.Run("returns the summary", func() {
+		s.Regexp(`^Snapshot of 3 objects in namespace shop stored as artifact artifact://snapshot-\d{8}-\d{6}-[0-9a-f]{8}\.json, `, result.Summary)
+	})
+	s.Run("counts the objects per kind", func() {
+		s.Equal(map[string]int{"Deployment": 2, "Secret": 1}, result.Objects)
+	})
+	s.Run("stores the objects without their status and Secret values", func() {
+		_, content, err := k.NewArtifactStore().Get(result.Artifact.Name)
+		s.Require().NoError(err)
+		s.Contains(string(content), `"kind":"Deployment"`)
+		s.Contains(string(content), `"image":"web:1"`)
+		s.NotContains(string(content), `"readyReplicas"`)
+		s.NotContains(string(content), "czNjcjN0")
+	})
+}
+
+func (s *SnapshotsSuite) TestSnapshotDiff() {
+	k := s.derived()
+	result, err := k.SnapshotCreate(s.T().Context(), SnapshotOptions{Resources: []string{"deployment", "secrets"}, Namespaces: []string{"shop"}})
+	s.Require().NoError(err)
+	s.deployments = []appsv1.Deployment{
+		snapshotDeployment("web", "2", 3, "web:2"),
+		snapshotDeployment("worker", "5", 1, "worker:1"),
+	}
+	s.secrets[0].ResourceVersion = "2"
+	s.secrets = append(s.secrets, v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "tls", ResourceVersion: "3"}})
+	diff, err := k.SnapshotDiff(s.T().Context(), result.Artifact.Name)
+	s.Require().NoError(err)
+	s.Run("returns the summary", func() {
+		s.Regexp(`^2 added, 1 removed, 2 modified, 0 unchanged objects in namespace shop since snapshot snapshot-.+\.json of `, diff.Summary)
+	})
+	s.Run("orders the changes by kind, namespace and name", func() {
+		var changes []string
+		for _, change := range diff.Changes {
+			changes = append(changes, change.Change+" "+change.Kind+"/"+change.Name)
+		}
+		s.Equal([]string{
+			"removed Deployment/api",
+			"modified Deployment/web",
+			"added Deployment/worker",
+			"modified Secret/db",
+			"added Secret/tls",
+		}, changes)
+	})
+	s.Run("returns the field-level diffs of the modified objects", func() {
+		s.Equal([]SnapshotFieldDiff{
+			{Path: "spec.replicas", Old: float64(1), New: float64(3)},
+			{Path: "spec.template.spec.containers[0].image", Old: "web:1", New: "web:2"},
+		}, diff.Changes[1].Fields)
+	})
+	s.Run("reports the Secrets with a new resourceVersion as modified", func() {
+		s.Empty(diff.Changes[3].Fields)
+	})
+}
+
+func (s *SnapshotsSuite) TestSnapshotDiffUnchanged() {
+	k := s.derived()
+	result, err := k.SnapshotCreate(s.T().Context(), SnapshotOptions{Resources: []string{"deployments"}, Namespaces: []string{"shop"}})
+	s.Require().NoError(err)
+	s.deployments[0].ResourceVersion = "2"
+	s.deployments[0].Status.ReadyReplicas = 0
+	diff, err := k.SnapshotDiff(s.T().Context(), result.Artifact.Name)
+	s.Require().NoError(err)
+	s.Run("ignores the status changes", func() {
+		s.Empty(diff.Changes)
+		s.Equal(2, diff.Unchanged)
+	})
+}
+
+func (s *SnapshotsSuite) TestSnapshotErrors() {
+	k := s.derived()
+	s.Run("returns error without resource types", func() {
+		_, err := k.SnapshotCreate(s.T().Context(), SnapshotOptions{})
+		s.EqualError(err, "at least one resource type must be provided")
+	})
+	s.Run("returns error for unknown resource types", func() {
+		_, err := k.SnapshotCreate(s.T().Context(), SnapshotOptions{Resources: []string{"widgets"}})
+		s.ErrorContains(err, "failed to resolve resource type widgets: ")
+	})
+	s.Run("returns error for missing snapshots", func() {
+		_, err := k.SnapshotDiff(s.T().Context(), "snapshot-20250101-000000-00000000.json")
+		s.EqualError(err, "artifact not found: snapshot-20250101-000000-00000000.json")
+	})
+}
+
+func (s *SnapshotsSuite) TestSnapshotFieldPath() {
+	s.Equal("metadata.labels[\"app.kubernetes.io/name\"]", snapshotFieldPath("metadata.labels", "app.kubernetes.io/name"))
+	s.Equal("spec", snapshotFieldPath("", "spec"))
+}
+
+func TestSnapshots(t *testing.T) {
+	suite.Run(t, new(SnapshotsSuite))
+}
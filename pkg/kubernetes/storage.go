@@ -0,0 +1,445 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// storageDefaultClassAnnotation marks the default StorageClass of the cluster
+	storageDefaultClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	// storageSelectedNodeAnnotation is set on the claims of WaitForFirstConsumer StorageClasses once a consumer is scheduled
+	storageSelectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+	// storageNoProvisioner is the provisioner of the StorageClasses of statically provisioned volumes (e.g. local volumes)
+	storageNoProvisioner = "kubernetes.io/no-provisioner"
+	// storageClaimMaxEvents is the maximum number of recent events reported by StorageWhyPending
+	storageClaimMaxEvents = 10
+)
+
+// StorageClaim maps a PersistentVolumeClaim to its PersistentVolume, its StorageClass, the nodes the volume is
+// attached to, and the Pods that use it
+type StorageClaim struct {
+	Namespace   string                          `json:"namespace"`
+	Name        string                          `json:"name"`
+	Phase       v1.PersistentVolumeClaimPhase   `json:"phase"`
+	Requested   string                          `json:"requested,omitempty"`
+	Capacity    string                          `json:"capacity,omitempty"`
+	AccessModes []v1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	VolumeMode  string                          `json:"volumeMode,omitempty"`
+	// StorageClass is the StorageClass of the claim, the default StorageClass if the claim doesn't set one
+	StorageClass      string `json:"storageClass,omitempty"`
+	Provisioner       string `json:"provisioner,omitempty"`
+	VolumeBindingMode string `json:"volumeBindingMode,omitempty"`
+	// Volume is the PersistentVolume bound to the claim
+	Volume        string `json:"volume,omitempty"`
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+	// Driver is the CSI driver of the volume, or its in-tree volume type
+	Driver string `json:"driver,omitempty"`
+	// Nodes are the nodes the volume is attached to (VolumeAttachments), with the attach errors if any
+	Nodes []string `json:"nodes,omitempty"`
+	// Pods are the Pods of the namespace that use the claim
+	Pods    []string `json:"pods,omitempty"`
+	Created string   `json:"created,omitempty"`
+}
+
+// StorageClaims are the mapped PersistentVolumeClaims with the warnings of the lookups that failed
+type StorageClaims struct {
+	Claims   []StorageClaim `json:"claims"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+// StorageBinding explains why a PersistentVolumeClaim is not bound: the StorageClass issues (missing StorageClass,
+// no default StorageClass, WaitForFirstConsumer without a scheduled consumer), the PersistentVolumes ruled out with
+// the reasons of each (capacity, access modes, volume mode, selector, already bound), and the events of the claim.
+type StorageBinding struct {
+	// Summary is a one line explanation, e.g. "PersistentVolumeClaim data is Pending: StorageClass fast not found"
+	Summary string `json:"summary"`
+	StorageClaim
+	// Issues are the reasons that prevent the claim from being bound
+	Issues []string `json:"issues,omitempty"`
+	// CandidateVolumes are the PersistentVolumes the claim can be bound to
+	CandidateVolumes []string `json:"candidateVolumes,omitempty"`
+	// RuledOutVolumes are the PersistentVolumes of the StorageClass of the claim that can't be bound with the reasons why
+	RuledOutVolumes []VolumeBinding `json:"ruledOutVolumes,omitempty"`
+	// Events are the most recent events of the claim (e.g. ProvisioningFailed, WaitForFirstConsumer), newest first
+	Events   []WorkloadEvent `json:"events,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+type VolumeBinding struct {
+	Volume  string   `json:"volume"`
+	Reasons []string `json:"reasons"`
+}
+
+// StorageListClaims returns the PersistentVolumeClaims of the namespace (all namespaces if empty) mapped to their
+// PersistentVolumes, StorageClasses, node attachments, and Pods.
+// Failures retrieving anything but the claims don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) StorageListClaims(ctx context.Context, namespace string) (*StorageClaims, error) {
+	core := k.AccessControlClientset().CoreV1()
+	claims, err := core.PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	lookups := k.storageLookups(ctx, namespace)
+	result := &StorageClaims{Claims: MapStorageClaims(claims.Items, lookups.volumes, lookups.classes, lookups.attachments, lookups.pods), Warnings: lookups.warnings}
+	return result, nil
+}
+
+// StorageWhyPending returns the StorageBinding of the PersistentVolumeClaim with the provided name.
+// Failures retrieving anything but the claim don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) StorageWhyPending(ctx context.Context, namespace, name string) (*StorageBinding, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	core := k.AccessControlClientset().CoreV1()
+	claim, err := core.PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	lookups := k.storageLookups(ctx, namespace)
+	binding := AnalyzeClaimBinding(claim, lookups.volumes, lookups.classes, lookups.attachments, lookups.pods)
+	binding.Warnings = lookups.warnings
+	events, err := core.Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "PersistentVolumeClaim", "involvedObject.name": name}.String(),
+	})
+	if err != nil {
+		binding.Warnings = append(binding.Warnings, fmt.Sprintf("unable to list the events of the claim: %v", err))
+	} else {
+		binding.Events = workloadEvents(events.Items, map[string]bool{"PersistentVolumeClaim/" + name: true}, storageClaimMaxEvents)
+	}
+	return binding, nil
+}
+
+type storageLookups struct {
+	volumes     []v1.PersistentVolume
+	classes     []storagev1.StorageClass
+	attachments []storagev1.VolumeAttachment
+	pods        []v1.Pod
+	warnings    []string
+}
+
+// storageLookups lists the cluster objects the PersistentVolumeClaims of the namespace are mapped to
+func (k *Kubernetes) storageLookups(ctx context.Context, namespace string) *storageLookups {
+	lookups := &storageLookups{}
+	core := k.AccessControlClientset().CoreV1()
+	storage := k.AccessControlClientset().StorageV1()
+	if volumes, err := core.PersistentVolumes().List(ctx, metav1.ListOptions{}); err != nil {
+		lookups.warnings = append(lookups.warnings, fmt.Sprintf("unable to list the PersistentVolumes: %v", err))
+	} else {
+		lookups.volumes = volumes.Items
+	}
+	if classes, err := storage.StorageClasses().List(ctx, metav1.ListOptions{}); err != nil {
+		lookups.warnings = append(lookups.warnings, fmt.Sprintf("unable to list the StorageClasses: %v", err))
+	} else {
+		lookups.classes = classes.Items
+	}
+	if attachments, err := storage.VolumeAttachments().List(ctx, metav1.ListOptions{}); err != nil {
+		lookups.warnings = append(lookups.warnings, fmt.Sprintf("unable to list the VolumeAttachments: %v", err))
+	} else {
+		lookups.attachments = attachments.Items
+	}
+	if pods, err := core.Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		lookups.warnings = append(lookups.warnings, fmt.Sprintf("unable to list the Pods: %v", err))
+	} else {
+		lookups.pods = pods.Items
+	}
+	return lookups
+}
+
+// MapStorageClaims maps the PersistentVolumeClaims to their PersistentVolumes, StorageClasses, node attachments, and Pods
+func MapStorageClaims(claims []v1.PersistentVolumeClaim, volumes []v1.PersistentVolume, classes []storagev1.StorageClass,
+	attachments []storagev1.VolumeAttachment, pods []v1.Pod) []StorageClaim {
+	result := make([]StorageClaim, 0, len(claims))
+	for i := range claims {
+		result = append(result, storageClaim(&claims[i], volumes, classes, attachments, pods))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// AnalyzeClaimBinding evaluates the binding of the PersistentVolumeClaim against its StorageClass and each of the
+// PersistentVolumes, the checks mirror the ones of the PersistentVolume controller
+func AnalyzeClaimBinding(claim *v1.PersistentVolumeClaim, volumes []v1.PersistentVolume, classes []storagev1.StorageClass,
+	attachments []storagev1.VolumeAttachment, pods []v1.Pod) *StorageBinding {
+	binding := &StorageBinding{StorageClaim: storageClaim(claim, volumes, classes, attachments, pods)}
+	switch claim.Status.Phase {
+	case v1.ClaimBound:
+		binding.Summary = fmt.Sprintf("PersistentVolumeClaim %s is not pending, it's Bound to PersistentVolume %s", claim.Name, claim.Spec.VolumeName)
+		return binding
+	case v1.ClaimLost:
+		binding.Issues = append(binding.Issues, fmt.Sprintf(
+			"the claim lost its PersistentVolume %s, the volume was deleted or its claimRef removed, the data may be lost", claim.Spec.VolumeName))
+		binding.Summary = fmt.Sprintf("PersistentVolumeClaim %s is Lost: %s", claim.Name, binding.Issues[0])
+		return binding
+	}
+
+	className := binding.StorageClass
+	var class *storagev1.StorageClass
+	for i := range classes {
+		if classes[i].Name == className {
+			class = &classes[i]
+		}
+	}
+	switch {
+	case claim.Spec.StorageClassName == nil && className == "":
+		binding.Issues = append(binding.Issues,
+			"the claim has no StorageClass and the cluster has no default StorageClass, only PersistentVolumes without a StorageClass can be bound")
+	case className != "" && class == nil:
+		binding.Issues = append(binding.Issues, fmt.Sprintf(
+			"StorageClass %s not found, volumes can't be provisioned and only PersistentVolumes of StorageClass %s can be bound", className, className))
+	}
+	if class != nil && class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		binding.Issues = append(binding.Issues, storageConsumerIssues(claim, className, pods)...)
+	}
+
+	// Volume requested by name
+	if claim.Spec.VolumeName != "" {
+		found := false
+		for _, volume := range volumes {
+			found = found || volume.Name == claim.Spec.VolumeName
+		}
+		if !found {
+			binding.Issues = append(binding.Issues, fmt.Sprintf("the claim requests PersistentVolume %s, which doesn't exist", claim.Spec.VolumeName))
+		}
+	}
+
+	dynamic := class != nil && class.Provisioner != storageNoProvisioner
+	for i := range volumes {
+		volume := &volumes[i]
+		if volume.Spec.StorageClassName != className || (claim.Spec.VolumeName != "" && volume.Name != claim.Spec.VolumeName) {
+			continue
+		}
+		if reasons := storageVolumeMismatches(claim, volume); len(reasons) > 0 {
+			binding.RuledOutVolumes = append(binding.RuledOutVolumes, VolumeBinding{Volume: volume.Name, Reasons: reasons})
+		} else {
+			binding.CandidateVolumes = append(binding.CandidateVolumes, volume.Name)
+		}
+	}
+	switch {
+	case len(binding.CandidateVolumes) > 0 || dynamic:
+	case className == "":
+		binding.Issues = append(binding.Issues, fmt.Sprintf(
+			"no PersistentVolume without a StorageClass can be bound to the claim, %d ruled out", len(binding.RuledOutVolumes)))
+	default:
+		binding.Issues = append(binding.Issues, fmt.Sprintf(
+			"no PersistentVolume of StorageClass %s can be bound to the claim, %d ruled out, and the StorageClass doesn't provision volumes dynamically",
+			className, len(binding.RuledOutVolumes)))
+	}
+	if dynamic && len(binding.Issues) == 0 && len(binding.CandidateVolumes) == 0 {
+		binding.Issues = append(binding.Issues, fmt.Sprintf(
+			"waiting for the provisioner %s to create a volume, check the ProvisioningFailed events and that the provisioner is running", class.Provisioner))
+	}
+
+	binding.Summary = fmt.Sprintf("PersistentVolumeClaim %s is %s", claim.Name, claim.Status.Phase)
+	if len(binding.Issues) > 0 {
+		binding.Summary += ": " + binding.Issues[0]
+	}
+	return binding
+}
+
+// storageConsumerIssues explains the delayed binding of the claims of WaitForFirstConsumer StorageClasses
+func storageConsumerIssues(claim *v1.PersistentVolumeClaim, className string, pods []v1.Pod) []string {
+	if node := claim.Annotations[storageSelectedNodeAnnotation]; node != "" {
+		return []string{fmt.Sprintf("a consumer Pod was scheduled on node %s, waiting for the volume to be provisioned for the node", node)}
+	}
+	consumers := storageClaimPods(claim, pods)
+	if len(consumers) == 0 {
+		return []string{fmt.Sprintf(
+			"StorageClass %s has volumeBindingMode WaitForFirstConsumer, the claim is bound once a Pod using it is scheduled and no Pod uses it", className)}
+	}
+	issues := make([]string, 0, len(consumers))
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != "" || !slices.Contains(consumers, pod.Name) {
+			continue
+		}
+		issue := fmt.Sprintf("StorageClass %s has volumeBindingMode WaitForFirstConsumer and Pod %s using the claim is not scheduled", className, pod.Name)
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Message != "" {
+				issue += ": " + condition.Message
+			}
+		}
+		issues = append(issues, issue+" (see pods_why_pending)")
+	}
+	return issues
+}
+
+// storageVolumeMismatches returns the reasons why the PersistentVolume can't be bound to the claim
+func storageVolumeMismatches(claim *v1.PersistentVolumeClaim, volume *v1.PersistentVolume) []string {
+	reasons := make([]string, 0)
+	if ref := volume.Spec.ClaimRef; ref != nil && (ref.Namespace != claim.Namespace || ref.Name != claim.Name || (ref.UID != "" && ref.UID != claim.UID)) {
+		if volume.Status.Phase == v1.VolumeReleased {
+			reasons = append(reasons, fmt.Sprintf("Released by %s/%s, its claimRef must be removed to reuse it (reclaim policy %s)",
+				ref.Namespace, ref.Name, volume.Spec.PersistentVolumeReclaimPolicy))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("bound to %s/%s", ref.Namespace, ref.Name))
+		}
+	}
+	if volume.Status.Phase == v1.VolumeFailed || volume.Status.Phase == v1.VolumePending {
+		reasons = append(reasons, fmt.Sprintf("phase %s", volume.Status.Phase))
+	}
+	requested := claim.Spec.Resources.Requests[v1.ResourceStorage]
+	if capacity := volume.Spec.Capacity[v1.ResourceStorage]; capacity.Cmp(requested) < 0 {
+		reasons = append(reasons, fmt.Sprintf("capacity %s is less than the requested %s", capacity.String(), requested.String()))
+	}
+	for _, mode := range claim.Spec.AccessModes {
+		if !slices.Contains(volume.Spec.AccessModes, mode) {
+			reasons = append(reasons, fmt.Sprintf("access mode %s not supported (%s)", mode, storageAccessModes(volume.Spec.AccessModes)))
+		}
+	}
+	if claimMode, volumeMode := storageVolumeMode(claim.Spec.VolumeMode), storageVolumeMode(volume.Spec.VolumeMode); claimMode != volumeMode {
+		reasons = append(reasons, fmt.Sprintf("volume mode %s doesn't match the requested %s", volumeMode, claimMode))
+	}
+	if claim.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(claim.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(volume.Labels)) {
+			reasons = append(reasons, fmt.Sprintf("labels don't match the claim selector %s", metav1.FormatLabelSelector(claim.Spec.Selector)))
+		}
+	}
+	return reasons
+}
+
+// storageClaim returns the StorageClaim of the claim
+func storageClaim(claim *v1.PersistentVolumeClaim, volumes []v1.PersistentVolume, classes []storagev1.StorageClass,
+	attachments []storagev1.VolumeAttachment, pods []v1.Pod) StorageClaim {
+	result := StorageClaim{
+		Namespace:    claim.Namespace,
+		Name:         claim.Name,
+		Phase:        claim.Status.Phase,
+		AccessModes:  claim.Spec.AccessModes,
+		VolumeMode:   storageVolumeMode(claim.Spec.VolumeMode),
+		StorageClass: storageClassName(claim, classes),
+		Volume:       claim.Spec.VolumeName,
+		Pods:         storageClaimPods(claim, pods),
+	}
+	if requested, ok := claim.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+		result.Requested = requested.String()
+	}
+	if capacity, ok := claim.Status.Capacity[v1.ResourceStorage]; ok {
+		result.Capacity = capacity.String()
+	}
+	if !claim.CreationTimestamp.IsZero() {
+		result.Created = claim.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	for _, class := range classes {
+		if class.Name == result.StorageClass {
+			result.Provisioner = class.Provisioner
+			if class.VolumeBindingMode != nil {
+				result.VolumeBindingMode = string(*class.VolumeBindingMode)
+			}
+		}
+	}
+	if claim.Status.Phase != v1.ClaimBound || claim.Spec.VolumeName == "" {
+		return result
+	}
+	for _, volume := range volumes {
+		if volume.Name != claim.Spec.VolumeName {
+			continue
+		}
+		result.ReclaimPolicy = string(volume.Spec.PersistentVolumeReclaimPolicy)
+		result.Driver = storageVolumeDriver(&volume)
+	}
+	for _, attachment := range attachments {
+		if attachment.Spec.Source.PersistentVolumeName == nil || *attachment.Spec.Source.PersistentVolumeName != claim.Spec.VolumeName {
+			continue
+		}
+		node := attachment.Spec.NodeName
+		switch {
+		case attachment.Status.AttachError != nil:
+			node += " (attach error: " + attachment.Status.AttachError.Message + ")"
+		case attachment.Status.DetachError != nil:
+			node += " (detach error: " + attachment.Status.DetachError.Message + ")"
+		case !attachment.Status.Attached:
+			node += " (attaching)"
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+	sort.Strings(result.Nodes)
+	return result
+}
+
+// storageClassName returns the StorageClass of the claim, or the default StorageClass if the claim doesn't set one
+func storageClassName(claim *v1.PersistentVolumeClaim, classes []storagev1.StorageClass) string {
+	if claim.Spec.StorageClassName != nil {
+		return *claim.Spec.StorageClassName
+	}
+	if claim.Status.Phase == v1.ClaimBound {
+		return ""
+	}
+	for _, class := range classes {
+		if class.Annotations[storageDefaultClassAnnotation] == "true" {
+			return class.Name
+		}
+	}
+	return ""
+}
+
+// storageClaimPods returns the names of the Pods that use the claim, directly or through a generic ephemeral volume
+func storageClaimPods(claim *v1.PersistentVolumeClaim, pods []v1.Pod) []string {
+	names := make([]string, 0)
+	for _, pod := range pods {
+		if pod.Namespace != claim.Namespace {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if (volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim.Name) ||
+				(volume.Ephemeral != nil && pod.Name+"-"+volume.Name == claim.Name) {
+				names = append(names, pod.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// storageVolumeDriver returns the CSI driver of the volume, or its in-tree volume type
+func storageVolumeDriver(volume *v1.PersistentVolume) string {
+	source := volume.Spec.PersistentVolumeSource
+	switch {
+	case source.CSI != nil:
+		return source.CSI.Driver
+	case source.Local != nil:
+		return "local"
+	case source.HostPath != nil:
+		return "hostPath"
+	case source.NFS != nil:
+		return "nfs"
+	case source.ISCSI != nil:
+		return "iscsi"
+	case source.FC != nil:
+		return "fc"
+	}
+	return ""
+}
+
+func storageVolumeMode(mode *v1.PersistentVolumeMode) string {
+	if mode == nil {
+		return string(v1.PersistentVolumeFilesystem)
+	}
+	return string(*mode)
+}
+
+func storageAccessModes(modes []v1.PersistentVolumeAccessMode) string {
+	names := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		names = append(names, string(mode))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,228 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type StorageSuite struct {
+	suite.Suite
+}
+
+func storageTestClaim(mutate func(c *v1.PersistentVolumeClaim)) *v1.PersistentVolumeClaim {
+	c := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "data"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("local"),
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources:        v1.VolumeResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+	}
+	if mutate != nil {
+		mutate(c)
+	}
+	return c
+}
+
+func storageTestVolume(name, class, capacity string, mutate func(pv *v1.PersistentVolume)) v1.PersistentVolume {
+	pv := v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PersistentVolumeSpec{
+			StorageClassName: class,
+			Capacity:         v1.ResourceList{v1.ResourceStorage: resource.MustParse(capacity)},
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+		},
+		Status: v1.PersistentVolumeStatus{Phase: v1.VolumeAvailable},
+	}
+	if mutate != nil {
+		mutate(&pv)
+	}
+	return pv
+}
+
+var storageTestClasses = []storagev1.StorageClass{
+	{
+		ObjectMeta:        metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}},
+		Provisioner:       "ebs.csi.aws.com",
+		VolumeBindingMode: ptr.To(storagev1.VolumeBindingImmediate),
+	},
+	{
+		ObjectMeta:        metav1.ObjectMeta{Name: "local"},
+		Provisioner:       "kubernetes.io/no-provisioner",
+		VolumeBindingMode: ptr.To(storagev1.VolumeBindingWaitForFirstConsumer),
+	},
+}
+
+func (s *StorageSuite) TestMapStorageClaims() {
+	claims := []v1.PersistentVolumeClaim{
+		*storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Name = "pending"
+			c.Spec.StorageClassName = nil
+		}),
+		*storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Spec.StorageClassName = ptr.To("standard")
+			c.Spec.VolumeName = "pvc-1234"
+			c.Status = v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound, Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}}
+		}),
+	}
+	volumes := []v1.PersistentVolume{storageTestVolume("pvc-1234", "standard", "10Gi", func(pv *v1.PersistentVolume) {
+		pv.Spec.PersistentVolumeReclaimPolicy = v1.PersistentVolumeReclaimDelete
+		pv.Spec.CSI = &v1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-1"}
+	})}
+	attachments := []storagev1.VolumeAttachment{
+		{Spec: storagev1.VolumeAttachmentSpec{NodeName: "node-1", Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To("pvc-1234")}},
+			Status: storagev1.VolumeAttachmentStatus{Attached: true}},
+		{Spec: storagev1.VolumeAttachmentSpec{NodeName: "node-2", Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To("pvc-1234")}},
+			Status: storagev1.VolumeAttachmentStatus{AttachError: &storagev1.VolumeError{Message: "volume is in use"}}},
+		{Spec: storagev1.VolumeAttachmentSpec{NodeName: "node-3", Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To("other")}},
+			Status: storagev1.VolumeAttachmentStatus{Attached: true}},
+	}
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "db-0"}, Spec: v1.PodSpec{Volumes: []v1.Volume{
+			{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "db-0"}, Spec: v1.PodSpec{Volumes: []v1.Volume{
+			{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+		}}},
+	}
+	mapped := MapStorageClaims(claims, volumes, storageTestClasses, attachments, pods)
+	s.Require().Len(mapped, 2)
+	s.Run("sorts the claims by name", func() {
+		s.Equal("data", mapped[0].Name)
+		s.Equal("pending", mapped[1].Name)
+	})
+	s.Run("maps bound claims to their volume, class, and attachments", func() {
+		s.Equal(v1.ClaimBound, mapped[0].Phase)
+		s.Equal("10Gi", mapped[0].Requested)
+		s.Equal("10Gi", mapped[0].Capacity)
+		s.Equal("standard", mapped[0].StorageClass)
+		s.Equal("ebs.csi.aws.com", mapped[0].Provisioner)
+		s.Equal("Immediate", mapped[0].VolumeBindingMode)
+		s.Equal("pvc-1234", mapped[0].Volume)
+		s.Equal("Delete", mapped[0].ReclaimPolicy)
+		s.Equal("ebs.csi.aws.com", mapped[0].Driver)
+		s.Equal([]string{"node-1", "node-2 (attach error: volume is in use)"}, mapped[0].Nodes)
+	})
+	s.Run("reports the pods of the namespace using the claim", func() {
+		s.Equal([]string{"db-0"}, mapped[0].Pods)
+	})
+	s.Run("reports the default class for claims without a class", func() {
+		s.Equal("standard", mapped[1].StorageClass)
+		s.Empty(mapped[1].Capacity)
+		s.Empty(mapped[1].Nodes)
+	})
+}
+
+func (s *StorageSuite) TestAnalyzeClaimBinding() {
+	s.Run("bound claim", func() {
+		binding := AnalyzeClaimBinding(storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Spec.VolumeName = "pv-1"
+			c.Status.Phase = v1.ClaimBound
+		}), nil, storageTestClasses, nil, nil)
+		s.Equal("PersistentVolumeClaim data is not pending, it's Bound to PersistentVolume pv-1", binding.Summary)
+		s.Empty(binding.Issues)
+	})
+	s.Run("missing StorageClass", func() {
+		binding := AnalyzeClaimBinding(storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Spec.StorageClassName = ptr.To("fast")
+		}), nil, storageTestClasses, nil, nil)
+		s.Equal("PersistentVolumeClaim data is Pending: StorageClass fast not found, volumes can't be provisioned and only PersistentVolumes of StorageClass fast can be bound",
+			binding.Summary)
+		s.Contains(binding.Issues, "no PersistentVolume of StorageClass fast can be bound to the claim, 0 ruled out, and the StorageClass doesn't provision volumes dynamically")
+	})
+	s.Run("no default StorageClass", func() {
+		binding := AnalyzeClaimBinding(storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Spec.StorageClassName = nil
+		}), nil, storageTestClasses[1:], nil, nil)
+		s.Equal([]string{
+			"the claim has no StorageClass and the cluster has no default StorageClass, only PersistentVolumes without a StorageClass can be bound",
+			"no PersistentVolume without a StorageClass can be bound to the claim, 0 ruled out",
+		}, binding.Issues)
+	})
+	s.Run("WaitForFirstConsumer without consumer", func() {
+		binding := AnalyzeClaimBinding(storageTestClaim(nil), []v1.PersistentVolume{storageTestVolume("local-1", "local", "100Gi", nil)}, storageTestClasses, nil, nil)
+		s.Equal([]string{
+			"StorageClass local has volumeBindingMode WaitForFirstConsumer, the claim is bound once a Pod using it is scheduled and no Pod uses it",
+		}, binding.Issues)
+		s.Equal([]string{"local-1"}, binding.CandidateVolumes)
+	})
+	s.Run("WaitForFirstConsumer with unscheduled consumer", func() {
+		pods := []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "db-0"},
+			Spec: v1.PodSpec{Volumes: []v1.Volume{
+				{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+			}},
+			Status: v1.PodStatus{Conditions: []v1.PodCondition{{
+				Type: v1.PodScheduled, Status: v1.ConditionFalse, Message: "0/3 nodes are available: 3 Insufficient memory.",
+			}}},
+		}}
+		binding := AnalyzeClaimBinding(storageTestClaim(nil), []v1.PersistentVolume{storageTestVolume("local-1", "local", "100Gi", nil)}, storageTestClasses, nil, pods)
+		s.Equal([]string{
+			"StorageClass local has volumeBindingMode WaitForFirstConsumer and Pod db-0 using the claim is not scheduled: " +
+				"0/3 nodes are available: 3 Insufficient memory. (see pods_why_pending)",
+		}, binding.Issues)
+		s.Equal([]string{"db-0"}, binding.Pods)
+	})
+	s.Run("no matching PersistentVolume", func() {
+		volumes := []v1.PersistentVolume{
+			storageTestVolume("small", "local", "5Gi", nil),
+			storageTestVolume("rwx", "local", "10Gi", func(pv *v1.PersistentVolume) {
+				pv.Spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadOnlyMany}
+				pv.Spec.VolumeMode = ptr.To(v1.PersistentVolumeBlock)
+			}),
+			storageTestVolume("released", "local", "20Gi", func(pv *v1.PersistentVolume) {
+				pv.Spec.ClaimRef = &v1.ObjectReference{Namespace: "ns-1", Name: "old", UID: "uid-old"}
+				pv.Spec.PersistentVolumeReclaimPolicy = v1.PersistentVolumeReclaimRetain
+				pv.Status.Phase = v1.VolumeReleased
+			}),
+			storageTestVolume("other-class", "standard", "10Gi", nil),
+		}
+		binding := AnalyzeClaimBinding(storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Annotations = map[string]string{"volume.kubernetes.io/selected-node": "node-1"}
+		}), volumes, storageTestClasses, nil, nil)
+		s.Equal([]string{
+			"a consumer Pod was scheduled on node node-1, waiting for the volume to be provisioned for the node",
+			"no PersistentVolume of StorageClass local can be bound to the claim, 3 ruled out, and the StorageClass doesn't provision volumes dynamically",
+		}, binding.Issues)
+		s.Empty(binding.CandidateVolumes)
+		s.Equal([]VolumeBinding{
+			{Volume: "small", Reasons: []string{"capacity 5Gi is less than the requested 10Gi"}},
+			{Volume: "rwx", Reasons: []string{"access mode ReadWriteOnce not supported (ReadOnlyMany)", "volume mode Block doesn't match the requested Filesystem"}},
+			{Volume: "released", Reasons: []string{"Released by ns-1/old, its claimRef must be removed to reuse it (reclaim policy Retain)"}},
+		}, binding.RuledOutVolumes)
+	})
+	s.Run("selector", func() {
+		volumes := []v1.PersistentVolume{storageTestVolume("fast", "local", "10Gi", func(pv *v1.PersistentVolume) {
+			pv.Labels = map[string]string{"tier": "slow"}
+		})}
+		binding := AnalyzeClaimBinding(storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "fast"}}
+		}), volumes, storageTestClasses, nil, nil)
+		s.Equal([]VolumeBinding{{Volume: "fast", Reasons: []string{"labels don't match the claim selector tier=fast"}}}, binding.RuledOutVolumes)
+	})
+	s.Run("dynamic provisioning", func() {
+		binding := AnalyzeClaimBinding(storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Spec.StorageClassName = ptr.To("standard")
+		}), nil, storageTestClasses, nil, nil)
+		s.Equal("PersistentVolumeClaim data is Pending: waiting for the provisioner ebs.csi.aws.com to create a volume, "+
+			"check the ProvisioningFailed events and that the provisioner is running", binding.Summary)
+	})
+	s.Run("requested volume not found", func() {
+		binding := AnalyzeClaimBinding(storageTestClaim(func(c *v1.PersistentVolumeClaim) {
+			c.Spec.StorageClassName = ptr.To("standard")
+			c.Spec.VolumeName = "pv-missing"
+		}), nil, storageTestClasses, nil, nil)
+		s.Equal([]string{"the claim requests PersistentVolume pv-missing, which doesn't exist"}, binding.Issues)
+	})
+}
+
+func TestStorage(t *testing.T) {
+	suite.Run(t, new(StorageSuite))
+}
@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const throttlingMessage = "throttled by the API server (API Priority and Fairness)"
+
+// throttlingRoundTripper explains the 429 Too Many Requests responses of the requests rejected by API Priority and
+// Fairness, returned once client-go exhausts its retries. The API server identifies them with the
+// X-Kubernetes-PF-* headers of the flow schema and priority level that rejected the request.
+// The rest of the 429 responses (e.g. evictions blocked by a PodDisruptionBudget) are returned unchanged.
+type throttlingRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *throttlingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests || !isPriorityAndFairnessRejection(resp.Header) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	status := &metav1.Status{}
+	// the transports of the derived clients are wrapped again, the response is only explained once
+	if json.Unmarshal(body, status) == nil && status.Kind == "Status" && !strings.HasPrefix(status.Message, throttlingMessage) {
+		status.Message = throttlingExplanation(resp.Header.Get("Retry-After")) + status.Message
+		if explained, marshalErr := json.Marshal(status); marshalErr == nil {
+			body = explained
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// isPriorityAndFairnessRejection returns true if the response was classified by API Priority and Fairness
+func isPriorityAndFairnessRejection(header http.Header) bool {
+	return header.Get(flowcontrolv1.ResponseHeaderMatchedFlowSchemaUID) != "" ||
+		header.Get(flowcontrolv1.ResponseHeaderMatchedPriorityLevelConfigurationUID) != ""
+}
+
+// throttlingExplanation returns the explanation prefixed to the errors of the requests rejected by API Priority and
+// Fairness, with the delay suggested by the Retry-After header
+func throttlingExplanation(retryAfter string) string {
+	retry := "retry later"
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		retry = fmt.Sprintf("retry after %ds", seconds)
+	}
+	return fmt.Sprintf("%s, %s or lower the qps and burst settings: ", throttlingMessage, retry)
+}
@@ -0,0 +1,57 @@
+package kubernetes
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ThrottlingSuite struct {
+	suite.Suite
+}
+
+type throttlingResponse struct {
+	header http.Header
+	body   string
+}
+
+func (r throttlingResponse) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusTooManyRequests, Header: r.header, Body: io.NopCloser(strings.NewReader(r.body))}, nil
+}
+
+func (s *ThrottlingSuite) roundTrip(header http.Header, body string) string {
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/api/v1/pods", nil)
+	s.Require().NoError(err)
+	// the transports of the derived clients are wrapped twice
+	rt := &throttlingRoundTripper{delegate: &throttlingRoundTripper{delegate: throttlingResponse{header: header, body: body}}}
+	resp, err := rt.RoundTrip(req)
+	s.Require().NoError(err)
+	explained, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return string(explained)
+}
+
+func (s *ThrottlingSuite) TestThrottlingRoundTripper() {
+	const tooManyRequests = `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"Too many requests, please try again later.","reason":"TooManyRequests","code":429}`
+	s.Run("explains the rejections of API Priority and Fairness with retry after", func() {
+		header := http.Header{"X-Kubernetes-Pf-Flowschema-Uid": {"uid-1"}, "Retry-After": {"3"}}
+		s.JSONEq(`{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","reason":"TooManyRequests","code":429,
+			"message":"throttled by the API server (API Priority and Fairness), retry after 3s or lower the qps and burst settings: Too many requests, please try again later."}`,
+			s.roundTrip(header, tooManyRequests))
+	})
+	s.Run("explains the rejections of API Priority and Fairness without retry after", func() {
+		header := http.Header{"X-Kubernetes-Pf-Prioritylevel-Uid": {"uid-2"}}
+		s.Contains(s.roundTrip(header, tooManyRequests), `"message":"throttled by the API server (API Priority and Fairness), retry later or lower the qps and burst settings: Too many`)
+	})
+	s.Run("evictions blocked by a PodDisruptionBudget are unchanged", func() {
+		const blocked = `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"Cannot evict pod as it would violate the pod's disruption budget.","reason":"TooManyRequests","details":{"causes":[{"reason":"DisruptionBudget","message":"The disruption budget web-pdb needs 2 healthy pods and has 2 currently"}]},"code":429}`
+		s.Equal(blocked, s.roundTrip(http.Header{}, blocked))
+	})
+}
+
+func TestThrottling(t *testing.T) {
+	suite.Run(t, new(ThrottlingSuite))
+}
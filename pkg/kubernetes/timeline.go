@@ -0,0 +1,414 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Sources of the timeline entries
+const (
+	// TimelineSourceEvent entries are the Kubernetes events, including the ones retained by the event store
+	TimelineSourceEvent = "event"
+	// TimelineSourcePod entries are the Pod phase transitions, from the timestamps of the Pod status
+	TimelineSourcePod = "pod"
+	// TimelineSourceRollout entries are the rollout revisions (ReplicaSets, ControllerRevisions) and conditions of the workloads
+	TimelineSourceRollout = "rollout"
+	// TimelineSourceChange entries are the changes of the workloads by their field managers (managedFields)
+	TimelineSourceChange = "change"
+)
+
+const (
+	// DefaultTimelineWindow is the default duration of the timeline window, ending now
+	DefaultTimelineWindow = time.Hour
+	// DefaultTimelineLimit is the default maximum number of entries of the timeline, the most recent are kept
+	DefaultTimelineLimit = 200
+)
+
+// TimelineKinds is the list of workload kinds supported by Timeline
+var TimelineKinds = []string{WorkloadKindDeployment, WorkloadKindStatefulSet, WorkloadKindDaemonSet, WorkloadKindPod}
+
+type TimelineOptions struct {
+	// Namespace of the timeline, the current namespace if empty
+	Namespace string
+	// Kind and Name of the workload (Optional, the whole namespace if empty)
+	Kind string
+	Name string
+	// Since and Until are the window of the timeline (Optional, the last hour if zero)
+	Since time.Time
+	Until time.Time
+	// Limit is the maximum number of entries, the most recent are kept
+	Limit int
+}
+
+// Timeline merges the events, the Pod phase transitions, the rollout revisions and the changes of the workloads of a
+// namespace or a workload into a chronologically ordered incident timeline
+type Timeline struct {
+	// Summary is a one line description, e.g. "12 entries for Deployment shop/web from ... to ...: 8 events (3 warnings), ..."
+	Summary   string          `json:"summary"`
+	Namespace string          `json:"namespace"`
+	Workload  string          `json:"workload,omitempty"`
+	Since     string          `json:"since"`
+	Until     string          `json:"until"`
+	Entries   []TimelineEntry `json:"entries"`
+	// Truncated is true if older entries were dropped to honor the limit
+	Truncated bool     `json:"truncated,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// TimelineEntry is something that happened to an object of the timeline
+type TimelineEntry struct {
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source"`
+	// Type is Warning for the entries reporting a failure, Normal otherwise
+	Type    string `json:"type"`
+	Object  string `json:"object"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+	Count   int32  `json:"count,omitempty"`
+	time    time.Time
+}
+
+// timelineBuilder collects the entries within the window of the timeline
+type timelineBuilder struct {
+	since, until time.Time
+	entries      []TimelineEntry
+}
+
+func (b *timelineBuilder) add(timestamp time.Time, source, eventType, object, reason, message string) *TimelineEntry {
+	if timestamp.IsZero() || timestamp.Before(b.since) || timestamp.After(b.until) {
+		return nil
+	}
+	b.entries = append(b.entries, TimelineEntry{
+		Timestamp: timestamp.UTC().Format(time.RFC3339),
+		Source:    source,
+		Type:      eventType,
+		Object:    object,
+		Reason:    reason,
+		Message:   strings.TrimSpace(message),
+		time:      timestamp,
+	})
+	return &b.entries[len(b.entries)-1]
+}
+
+// Timeline builds the timeline of the namespace, or of the workload with its Pods and the objects managing them
+// (ReplicaSets, ControllerRevisions). Only the workload is required, failing to list the other objects are warnings.
+func (k *Kubernetes) Timeline(ctx context.Context, options TimelineOptions) (*Timeline, error) {
+	if (options.Kind == "") != (options.Name == "") {
+		return nil, errors.New("kind and name must be provided together, or none of them for the timeline of the namespace")
+	}
+	if options.Kind != "" && !slices.Contains(TimelineKinds, options.Kind) {
+		return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", options.Kind, TimelineKinds)
+	}
+	namespace := k.NamespaceOrDefault(options.Namespace)
+	until := options.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	since := options.Since
+	if since.IsZero() {
+		since = until.Add(-DefaultTimelineWindow)
+	}
+	if !since.Before(until) {
+		return nil, fmt.Errorf("the start of the window %s is not before its end %s", since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339))
+	}
+	timeline := &Timeline{Namespace: namespace, Since: since.UTC().Format(time.RFC3339), Until: until.UTC().Format(time.RFC3339)}
+	if options.Kind != "" {
+		timeline.Workload = options.Kind + "/" + options.Name
+	}
+	builder := &timelineBuilder{since: since, until: until}
+	// objects are the Kind/Name of the objects of the workload whose events are part of the timeline, nil for all the
+	// objects of the namespace
+	var objects map[string]bool
+	var selector *metav1.LabelSelector
+	apps := k.AccessControlClientset().AppsV1()
+	owned := func(object metav1.Object) bool {
+		if options.Kind == "" {
+			return true
+		}
+		owner := metav1.GetControllerOf(object)
+		return owner != nil && owner.Kind == options.Kind && owner.Name == options.Name
+	}
+	switch options.Kind {
+	case WorkloadKindDeployment:
+		d, err := apps.Deployments(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		timelineDeployment(builder, d)
+		selector = d.Spec.Selector
+	case WorkloadKindStatefulSet:
+		s, err := apps.StatefulSets(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		timelineChanges(builder, WorkloadKindStatefulSet, &s.ObjectMeta)
+		selector = s.Spec.Selector
+	case WorkloadKindDaemonSet:
+		ds, err := apps.DaemonSets(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		timelineChanges(builder, WorkloadKindDaemonSet, &ds.ObjectMeta)
+		selector = ds.Spec.Selector
+	case WorkloadKindPod:
+		pod, err := k.AccessControlClientset().CoreV1().Pods(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		timelinePod(builder, pod)
+		objects = map[string]bool{timeline.Workload: true}
+	default:
+		if deployments, err := apps.Deployments(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+			timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("unable to list the Deployments: %v", err))
+		} else {
+			for i := range deployments.Items {
+				timelineDeployment(builder, &deployments.Items[i])
+			}
+		}
+		if statefulSets, err := apps.StatefulSets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+			timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("unable to list the StatefulSets: %v", err))
+		} else {
+			for i := range statefulSets.Items {
+				timelineChanges(builder, WorkloadKindStatefulSet, &statefulSets.Items[i].ObjectMeta)
+			}
+		}
+		if daemonSets, err := apps.DaemonSets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+			timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("unable to list the DaemonSets: %v", err))
+		} else {
+			for i := range daemonSets.Items {
+				timelineChanges(builder, WorkloadKindDaemonSet, &daemonSets.Items[i].ObjectMeta)
+			}
+		}
+	}
+	if options.Kind != WorkloadKindPod {
+		if options.Kind != "" {
+			objects = map[string]bool{timeline.Workload: true}
+		}
+		labelSelector := ""
+		if selector != nil {
+			parsed, err := metav1.LabelSelectorAsSelector(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector of %s %s: %w", options.Kind, options.Name, err)
+			}
+			labelSelector = parsed.String()
+		}
+		if options.Kind == "" || options.Kind == WorkloadKindDeployment {
+			if replicaSets, err := apps.ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector}); err != nil {
+				timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("unable to list the ReplicaSets: %v", err))
+			} else {
+				for i := range replicaSets.Items {
+					if rs := &replicaSets.Items[i]; owned(rs) {
+						timelineReplicaSet(builder, rs)
+						if objects != nil {
+							objects[WorkloadKindReplicaSet+"/"+rs.Name] = true
+						}
+					}
+				}
+			}
+		}
+		if options.Kind != WorkloadKindDeployment {
+			if revisions, err := apps.ControllerRevisions(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector}); err != nil {
+				timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("unable to list the ControllerRevisions: %v", err))
+			} else {
+				for i := range revisions.Items {
+					if revision := &revisions.Items[i]; owned(revision) {
+						timelineControllerRevision(builder, revision)
+					}
+				}
+			}
+		}
+		if pods, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector}); err != nil {
+			timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("unable to list the Pods: %v", err))
+		} else {
+			for i := range pods.Items {
+				timelinePod(builder, &pods.Items[i])
+				if objects != nil {
+					objects["Pod/"+pods.Items[i].Name] = true
+				}
+			}
+		}
+	}
+	k.timelineEvents(ctx, builder, timeline, namespace, objects)
+	slices.SortStableFunc(builder.entries, func(a, b TimelineEntry) int { return a.time.Compare(b.time) })
+	timeline.Entries = builder.entries
+	if timeline.Entries == nil {
+		timeline.Entries = []TimelineEntry{}
+	}
+	limit := cmp.Or(options.Limit, DefaultTimelineLimit)
+	if len(timeline.Entries) > limit {
+		timeline.Entries, timeline.Truncated = timeline.Entries[len(timeline.Entries)-limit:], true
+	}
+	timeline.Summary = timeline.summary(limit)
+	return timeline, nil
+}
+
+// timelineEvents adds the events of the objects (all the events of the namespace if nil) from the API server and,
+// if enabled, from the event store, which retains the events the API server already removed
+func (k *Kubernetes) timelineEvents(ctx context.Context, builder *timelineBuilder, timeline *Timeline, namespace string, objects map[string]bool) {
+	events, err := k.AccessControlClientset().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("unable to list the events: %v", err))
+		return
+	}
+	seen := map[types.UID]bool{}
+	for i := range events.Items {
+		event := &events.Items[i]
+		object := event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name
+		if objects != nil && !objects[object] {
+			continue
+		}
+		seen[event.UID] = true
+		if entry := builder.add(eventTimestamp(event), TimelineSourceEvent, event.Type, object, event.Reason, event.Message); entry != nil && event.Count > 1 {
+			entry.Count = event.Count
+		}
+	}
+	// The events were listed with the caller's credentials, the caller is allowed to query them from the event store
+	if k.eventStore == nil {
+		return
+	}
+	for _, record := range k.eventStore.Query(EventStoreQuery{Namespace: namespace, Since: builder.since, Until: builder.until}) {
+		object := record.InvolvedObject["kind"] + "/" + record.InvolvedObject["name"]
+		if seen[record.UID] || (objects != nil && !objects[object]) {
+			continue
+		}
+		if entry := builder.add(record.Timestamp, TimelineSourceEvent, record.Type, object, record.Reason, record.Message); entry != nil && record.Count > 1 {
+			entry.Count = record.Count
+		}
+	}
+}
+
+// timelineDeployment adds the changes and the rollout conditions of the Deployment
+func timelineDeployment(builder *timelineBuilder, d *appsv1.Deployment) {
+	timelineChanges(builder, WorkloadKindDeployment, &d.ObjectMeta)
+	for _, condition := range d.Status.Conditions {
+		eventType := v1.EventTypeNormal
+		if condition.Status != v1.ConditionTrue {
+			eventType = v1.EventTypeWarning
+		}
+		builder.add(condition.LastTransitionTime.Time, TimelineSourceRollout, eventType, WorkloadKindDeployment+"/"+d.Name,
+			fmt.Sprintf("%s=%s", condition.Type, condition.Status), strings.TrimSpace(condition.Reason+": "+condition.Message))
+	}
+}
+
+// timelineChanges adds the creation of the workload and the last change of each of its field managers, the status
+// updates are left out as they're reported by the rollout and Pod entries
+func timelineChanges(builder *timelineBuilder, kind string, meta *metav1.ObjectMeta) {
+	object := kind + "/" + meta.Name
+	builder.add(meta.CreationTimestamp.Time, TimelineSourceChange, v1.EventTypeNormal, object, "Created", "")
+	for _, field := range meta.ManagedFields {
+		if field.Subresource != "" || field.Time == nil || field.Time.Equal(&meta.CreationTimestamp) {
+			continue
+		}
+		builder.add(field.Time.Time, TimelineSourceChange, v1.EventTypeNormal, object, string(field.Operation),
+			fmt.Sprintf("last change by %s", field.Manager))
+	}
+}
+
+// timelineReplicaSet adds the rollout revision of the ReplicaSet of a Deployment, the ReplicaSets without revision
+// aren't managed by a Deployment
+func timelineReplicaSet(builder *timelineBuilder, rs *appsv1.ReplicaSet) {
+	revision, ok := rs.Annotations[deploymentRevisionAnnotation]
+	owner := metav1.GetControllerOf(rs)
+	if !ok || owner == nil {
+		return
+	}
+	images := make([]string, 0, len(rs.Spec.Template.Spec.Containers))
+	for _, container := range rs.Spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	builder.add(rs.CreationTimestamp.Time, TimelineSourceRollout, v1.EventTypeNormal, owner.Kind+"/"+owner.Name, "Revision "+revision,
+		fmt.Sprintf("ReplicaSet %s created with images %s", rs.Name, strings.Join(images, ", ")))
+}
+
+// timelineControllerRevision adds the rollout revision of a StatefulSet or a DaemonSet
+func timelineControllerRevision(builder *timelineBuilder, revision *appsv1.ControllerRevision) {
+	object := "ControllerRevision/" + revision.Name
+	if owner := metav1.GetControllerOf(revision); owner != nil {
+		object = owner.Kind + "/" + owner.Name
+	}
+	builder.add(revision.CreationTimestamp.Time, TimelineSourceRollout, v1.EventTypeNormal, object,
+		fmt.Sprintf("Revision %d", revision.Revision), fmt.Sprintf("ControllerRevision %s created", revision.Name))
+}
+
+// timelinePod adds the phase transitions of the Pod: its creation, the transitions of its conditions, the start and
+// termination of its containers, and its deletion
+func timelinePod(builder *timelineBuilder, pod *v1.Pod) {
+	object := "Pod/" + pod.Name
+	builder.add(pod.CreationTimestamp.Time, TimelineSourcePod, v1.EventTypeNormal, object, "Created", "")
+	for _, condition := range pod.Status.Conditions {
+		eventType := v1.EventTypeNormal
+		if condition.Status != v1.ConditionTrue {
+			eventType = v1.EventTypeWarning
+		}
+		message := condition.Reason
+		if condition.Message != "" {
+			message = strings.TrimSpace(condition.Reason + ": " + condition.Message)
+		}
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionTrue && pod.Spec.NodeName != "" {
+			message = "scheduled on node " + pod.Spec.NodeName
+		}
+		builder.add(condition.LastTransitionTime.Time, TimelineSourcePod, eventType, object, fmt.Sprintf("%s=%s", condition.Type, condition.Status), message)
+	}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		for _, state := range []v1.ContainerState{status.LastTerminationState, status.State} {
+			if state.Running != nil {
+				builder.add(state.Running.StartedAt.Time, TimelineSourcePod, v1.EventTypeNormal, object, "Started",
+					fmt.Sprintf("container %s started", status.Name))
+			}
+			if terminated := state.Terminated; terminated != nil {
+				eventType := v1.EventTypeNormal
+				message := fmt.Sprintf("container %s terminated with exit code %d", status.Name, terminated.ExitCode)
+				if _, known := terminationReasons[terminated.Reason]; terminated.ExitCode != 0 || known {
+					eventType = v1.EventTypeWarning
+					if explanation, _, _ := ExplainExitCode(terminated.Reason, terminated.ExitCode); explanation != "" {
+						message += ": " + explanation
+					}
+				}
+				builder.add(terminated.StartedAt.Time, TimelineSourcePod, v1.EventTypeNormal, object, "Started",
+					fmt.Sprintf("container %s started", status.Name))
+				builder.add(terminated.FinishedAt.Time, TimelineSourcePod, eventType, object, cmp.Or(terminated.Reason, "Terminated"), message)
+			}
+		}
+	}
+	if pod.DeletionTimestamp != nil {
+		// The deletion timestamp is the deadline of the graceful termination
+		requested := pod.DeletionTimestamp.Time
+		if pod.DeletionGracePeriodSeconds != nil {
+			requested = requested.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+		}
+		builder.add(requested, TimelineSourcePod, v1.EventTypeNormal, object, "Terminating", "deletion requested")
+	}
+}
+
+func (t *Timeline) summary(limit int) string {
+	scope := "namespace " + t.Namespace
+	if t.Workload != "" {
+		kind, name, _ := strings.Cut(t.Workload, "/")
+		scope = kind + " " + t.Namespace + "/" + name
+	}
+	counts := map[string]int{}
+	warnings := 0
+	for _, entry := range t.Entries {
+		counts[entry.Source]++
+		if entry.Type == v1.EventTypeWarning {
+			warnings++
+		}
+	}
+	summary := fmt.Sprintf("%d entries for %s from %s to %s (%d warnings): %d events, %d pod transitions, %d rollout entries, %d changes",
+		len(t.Entries), scope, t.Since, t.Until, warnings,
+		counts[TimelineSourceEvent], counts[TimelineSourcePod], counts[TimelineSourceRollout], counts[TimelineSourceChange])
+	if t.Truncated {
+		summary += fmt.Sprintf(", truncated to the %d most recent entries", limit)
+	}
+	return summary
+}
@@ -0,0 +1,207 @@
+package kubernetes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type TimelineSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+	since      time.Time
+}
+
+func (s *TimelineSuite) SetupTest() {
+	s.since = time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int) metav1.Time {
+		return metav1.NewTime(s.since.Add(time.Duration(minutes) * time.Minute))
+	}
+	owner := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: ptr.To(true)}}
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: []string{
+		`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+	}})
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	deployment := appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web", CreationTimestamp: at(-600), ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "kubectl-client-side-apply", Operation: metav1.ManagedFieldsOperationUpdate, Time: ptr.To(at(32))},
+			{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: ptr.To(at(40))},
+		}},
+		Spec: appsv1.DeploymentSpec{Selector: selector},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: v1.ConditionFalse, Reason: "MinimumReplicasUnavailable", Message: "Deployment does not have minimum availability.", LastTransitionTime: at(35)},
+		}},
+	}
+	replicaSets := []appsv1.ReplicaSet{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web-2", CreationTimestamp: at(32), OwnerReferences: owner("Deployment", "web"),
+			Annotations: map[string]string{deploymentRevisionAnnotation: "2"}},
+		Spec: appsv1.ReplicaSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "web:2"}}}}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web-1", CreationTimestamp: at(-600), OwnerReferences: owner("Deployment", "web"),
+			Annotations: map[string]string{deploymentRevisionAnnotation: "1"}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "other-1", CreationTimestamp: at(10), OwnerReferences: owner("Deployment", "other"),
+			Annotations: map[string]string{deploymentRevisionAnnotation: "1"}},
+	}}
+	pods := []v1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web-2-abc", CreationTimestamp: at(33), Labels: map[string]string{"app": "web"}},
+		Spec:       v1.PodSpec{NodeName: "node-1"},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: at(33)},
+				{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: "ContainersNotReady", Message: "containers with unready status: [web]", LastTransitionTime: at(36)},
+			},
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:                 "web",
+				State:                v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: at(37)}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137, StartedAt: at(34), FinishedAt: at(36)}},
+			}},
+		},
+	}}
+	events := []v1.Event{{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: "web-2-abc.1", UID: "1"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-2-abc"},
+		Type:           v1.EventTypeWarning, Reason: "BackOff", Message: "Back-off restarting failed container", Count: 3,
+		FirstTimestamp: at(36), LastTimestamp: at(38),
+	}, {
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: "web.1", UID: "2"},
+		InvolvedObject: v1.ObjectReference{Kind: "Deployment", Name: "web"},
+		Type:           v1.EventTypeNormal, Reason: "ScalingReplicaSet", Message: "Scaled up replica set web-2 to 1",
+		FirstTimestamp: at(32),
+	}, {
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: "other.1", UID: "3"},
+		InvolvedObject: v1.ObjectReference{Kind: "Deployment", Name: "other"},
+		Type:           v1.EventTypeNormal, Reason: "ScalingReplicaSet", Message: "Scaled up replica set other-1 to 1",
+		FirstTimestamp: at(10),
+	}, {
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: "web.0", UID: "4"},
+		InvolvedObject: v1.ObjectReference{Kind: "Deployment", Name: "web"},
+		Type:           v1.EventTypeNormal, Reason: "ScalingReplicaSet", Message: "Scaled up replica set web-1 to 1",
+		FirstTimestamp: at(-600),
+	}}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/shop/deployments/web":
+			test.WriteObject(w, &deployment)
+		case "/apis/apps/v1/namespaces/shop/deployments":
+			test.WriteObject(w, &appsv1.DeploymentList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DeploymentList"}, Items: []appsv1.Deployment{deployment}})
+		case "/apis/apps/v1/namespaces/shop/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSetList"}, Items: replicaSets})
+		case "/api/v1/namespaces/shop/pods":
+			test.WriteObject(w, &v1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}, Items: pods})
+		case "/api/v1/namespaces/shop/events":
+			test.WriteObject(w, &v1.EventList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"}, Items: events})
+		}
+	}))
+}
+
+func (s *TimelineSuite) TearDownTest() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *TimelineSuite) derived() *Kubernetes {
+	cfg := test.Must(config.ReadToml([]byte(``)))
+	cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	m, err := NewKubeconfigManager(cfg, "")
+	s.Require().NoError(err, "Expected no error creating manager")
+	k, err := m.Derived(s.T().Context())
+	s.Require().NoError(err, "Expected no error deriving kubernetes")
+	return k
+}
+
+func (s *TimelineSuite) TestTimelineWorkload() {
+	timeline, err := s.derived().Timeline(s.T().Context(), TimelineOptions{
+		Namespace: "shop", Kind: WorkloadKindDeployment, Name: "web", Since: s.since, Until: s.since.Add(time.Hour),
+	})
+	s.Require().NoError(err)
+	s.Run("returns the summary", func() {
+		s.Equal("11 entries for Deployment shop/web from 2025-01-01T14:00:00Z to 2025-01-01T15:00:00Z (4 warnings): "+
+			"2 events, 6 pod transitions, 2 rollout entries, 1 changes", timeline.Summary)
+	})
+	s.Run("orders the entries of the window chronologically", func() {
+		var entries []string
+		for _, entry := range timeline.Entries {
+			entries = append(entries, entry.Timestamp[11:16]+" "+entry.Source+" "+entry.Object+" "+entry.Reason)
+		}
+		s.Equal([]string{
+			"14:32 change Deployment/web Update",
+			"14:32 rollout Deployment/web Revision 2",
+			"14:32 event Deployment/web ScalingReplicaSet",
+			"14:33 pod Pod/web-2-abc Created",
+			"14:33 pod Pod/web-2-abc PodScheduled=True",
+			"14:34 pod Pod/web-2-abc Started",
+			"14:35 rollout Deployment/web Available=False",
+			"14:36 pod Pod/web-2-abc Ready=False",
+			"14:36 pod Pod/web-2-abc OOMKilled",
+			"14:37 pod Pod/web-2-abc Started",
+			"14:38 event Pod/web-2-abc BackOff",
+		}, entries)
+	})
+	s.Run("describes the entries", func() {
+		s.Equal("last change by kubectl-client-side-apply", timeline.Entries[0].Message)
+		s.Equal("ReplicaSet web-2 created with images web:2", timeline.Entries[1].Message)
+		s.Equal("scheduled on node node-1", timeline.Entries[4].Message)
+		s.Equal("MinimumReplicasUnavailable: Deployment does not have minimum availability.", timeline.Entries[6].Message)
+		s.Equal(v1.EventTypeWarning, timeline.Entries[8].Type)
+		s.Contains(timeline.Entries[8].Message, "container web terminated with exit code 137: ")
+		s.Equal(int32(3), timeline.Entries[10].Count)
+	})
+	s.Run("leaves out the status updates and the objects of other workloads", func() {
+		for _, entry := range timeline.Entries {
+			s.NotContains(entry.Object, "other")
+			s.NotEqual("14:40", entry.Timestamp[11:16])
+		}
+	})
+}
+
+func (s *TimelineSuite) TestTimelineNamespace() {
+	timeline, err := s.derived().Timeline(s.T().Context(), TimelineOptions{Namespace: "shop", Since: s.since, Until: s.since.Add(time.Hour), Limit: 5})
+	s.Require().NoError(err)
+	s.Run("returns the summary of the namespace", func() {
+		s.Contains(timeline.Summary, " for namespace shop ")
+	})
+	s.Run("keeps the most recent entries", func() {
+		s.True(timeline.Truncated)
+		s.Len(timeline.Entries, 5)
+		s.Equal("2025-01-01T14:38:00Z", timeline.Entries[4].Timestamp)
+		s.Contains(timeline.Summary, ", truncated to the 5 most recent entries")
+	})
+	s.Run("warns about the objects that can't be listed", func() {
+		s.Contains(timeline.Warnings[len(timeline.Warnings)-1], "unable to list the ControllerRevisions")
+	})
+}
+
+func (s *TimelineSuite) TestTimelineErrors() {
+	k := s.derived()
+	s.Run("returns error for kind without name", func() {
+		_, err := k.Timeline(s.T().Context(), TimelineOptions{Kind: WorkloadKindDeployment})
+		s.EqualError(err, "kind and name must be provided together, or none of them for the timeline of the namespace")
+	})
+	s.Run("returns error for unsupported kinds", func() {
+		_, err := k.Timeline(s.T().Context(), TimelineOptions{Kind: "ReplicaSet", Name: "web"})
+		s.EqualError(err, "unsupported workload kind ReplicaSet, supported kinds are [Deployment StatefulSet DaemonSet Pod]")
+	})
+	s.Run("returns error for an empty window", func() {
+		_, err := k.Timeline(s.T().Context(), TimelineOptions{Since: s.since, Until: s.since})
+		s.EqualError(err, "the start of the window 2025-01-01T14:00:00Z is not before its end 2025-01-01T14:00:00Z")
+	})
+}
+
+func TestTimeline(t *testing.T) {
+	suite.Run(t, new(TimelineSuite))
+}
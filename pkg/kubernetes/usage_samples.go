@@ -0,0 +1,278 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/metrics/pkg/apis/metrics"
+	metricsv1beta1api "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// Sources of the container usage samples
+const (
+	// UsageSourceMetrics samples the usage from the metrics API (metrics-server)
+	UsageSourceMetrics = "metrics"
+	// UsageSourceKubelet samples the usage from the kubelet stats summaries of the nodes of the Pods, through the API
+	// server proxy, when the metrics API is not available
+	UsageSourceKubelet = "kubelet"
+)
+
+// UsageSources is the list of sources supported by SampleUsage
+var UsageSources = []string{UsageSourceMetrics, UsageSourceKubelet}
+
+// UsageSampleOptions describes how the usage of the containers of a set of Pods is sampled
+type UsageSampleOptions struct {
+	// Source of the samples, UsageSourceMetrics or UsageSourceKubelet (Optional, the metrics API if available, the
+	// kubelet otherwise)
+	Source string
+	// Samples is the number of times the usage is sampled (at least 1)
+	Samples int
+	// Interval is the time between two samples
+	Interval time.Duration
+}
+
+// UsageStats are the average, 95th percentile, and maximum of the samples of a resource
+type UsageStats struct {
+	Average resource.Quantity `json:"average"`
+	P95     resource.Quantity `json:"p95"`
+	Max     resource.Quantity `json:"max"`
+}
+
+// ContainerUsage is the aggregated usage of the containers with the same name in the sampled Pods
+type ContainerUsage struct {
+	// Samples is the number of values the stats are computed from, one per sampled Pod and sample
+	Samples int        `json:"samples"`
+	CPU     UsageStats `json:"cpu"`
+	Memory  UsageStats `json:"memory"`
+}
+
+// UsageSamples is the result of SampleUsage
+type UsageSamples struct {
+	// Source the samples were taken from
+	Source string
+	// Samples is the number of samples that returned the usage of at least one container
+	Samples int
+	// Containers is the aggregated usage by container name
+	Containers map[string]ContainerUsage
+	Warnings   []string
+}
+
+// UsageAggregator aggregates the CPU (millicores) and memory (bytes) usage samples of containers by name
+type UsageAggregator struct {
+	cpu    map[string][]int64
+	memory map[string][]int64
+}
+
+// NewUsageAggregator returns an empty UsageAggregator
+func NewUsageAggregator() *UsageAggregator {
+	return &UsageAggregator{cpu: map[string][]int64{}, memory: map[string][]int64{}}
+}
+
+// Add adds a usage sample of the container with the provided name
+func (a *UsageAggregator) Add(container string, cpuMillicores, memoryBytes int64) {
+	a.cpu[container] = append(a.cpu[container], cpuMillicores)
+	a.memory[container] = append(a.memory[container], memoryBytes)
+}
+
+// Usage returns the aggregated usage by container name, the memory stats are rounded up to the mebibyte
+func (a *UsageAggregator) Usage() map[string]ContainerUsage {
+	usage := make(map[string]ContainerUsage, len(a.cpu))
+	for container, cpu := range a.cpu {
+		cpuAverage, cpuP95, cpuMax := usageStats(cpu)
+		memoryAverage, memoryP95, memoryMax := usageStats(a.memory[container])
+		usage[container] = ContainerUsage{
+			Samples: len(cpu),
+			CPU: UsageStats{
+				Average: *resource.NewMilliQuantity(cpuAverage, resource.DecimalSI),
+				P95:     *resource.NewMilliQuantity(cpuP95, resource.DecimalSI),
+				Max:     *resource.NewMilliQuantity(cpuMax, resource.DecimalSI),
+			},
+			Memory: UsageStats{
+				Average: mebibytes(memoryAverage),
+				P95:     mebibytes(memoryP95),
+				Max:     mebibytes(memoryMax),
+			},
+		}
+	}
+	return usage
+}
+
+// usageStats returns the average (rounded up), the nearest-rank 95th percentile, and the maximum of the values
+func usageStats(values []int64) (average, p95, maximum int64) {
+	if len(values) == 0 {
+		return 0, 0, 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	sum := int64(0)
+	for _, value := range sorted {
+		sum += value
+	}
+	average = (sum + int64(len(sorted)) - 1) / int64(len(sorted))
+	p95 = sorted[int(math.Ceil(0.95*float64(len(sorted))))-1]
+	return average, p95, sorted[len(sorted)-1]
+}
+
+// mebibytes returns the quantity of the provided bytes rounded up to the mebibyte
+func mebibytes(bytes int64) resource.Quantity {
+	return *resource.NewQuantity((bytes+(1<<20)-1)/(1<<20)*(1<<20), resource.BinarySI)
+}
+
+// nodeStatsSummaryPods is the subset of the kubelet Summary API response with the usage of the containers
+type nodeStatsSummaryPods struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			Name string `json:"name"`
+			CPU  *struct {
+				UsageNanoCores *uint64 `json:"usageNanoCores"`
+			} `json:"cpu"`
+			Memory *struct {
+				WorkingSetBytes *uint64 `json:"workingSetBytes"`
+			} `json:"memory"`
+		} `json:"containers"`
+	} `json:"pods"`
+}
+
+// SampleUsage samples the usage of the containers of the provided Pods (of the same namespace) options.Samples
+// times, every options.Interval, and aggregates it by container name.
+// The memory usage is the working set, the one the kubelet evicts and the OOM killer acts on.
+// Samples that fail after the first one don't fail the operation, they're reported as warnings.
+func (k *Kubernetes) SampleUsage(ctx context.Context, namespace string, pods []v1.Pod, options UsageSampleOptions) (*UsageSamples, error) {
+	source := options.Source
+	metricsAvailable := k.supportsGroupVersion(metrics.GroupName + "/" + metricsv1beta1api.SchemeGroupVersion.Version)
+	switch source {
+	case "":
+		source = UsageSourceMetrics
+		if !metricsAvailable {
+			source = UsageSourceKubelet
+		}
+	case UsageSourceMetrics:
+		if !metricsAvailable {
+			return nil, errors.New("metrics API is not available, sample the usage from the kubelet instead")
+		}
+	case UsageSourceKubelet:
+	default:
+		return nil, fmt.Errorf("unsupported usage source %s, supported sources are %v", source, UsageSources)
+	}
+	names := make(map[string]bool, len(pods))
+	nodeNames := make(map[string]bool)
+	for _, pod := range pods {
+		names[pod.Name] = true
+		if pod.Spec.NodeName != "" {
+			nodeNames[pod.Spec.NodeName] = true
+		}
+	}
+	samples := &UsageSamples{Source: source}
+	aggregator := NewUsageAggregator()
+	warnings := map[string]bool{}
+	for i := 0; i < max(options.Samples, 1); i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(options.Interval):
+			}
+		}
+		var sampled int
+		var sampleWarnings []string
+		var err error
+		if source == UsageSourceMetrics {
+			sampled, err = k.sampleUsageMetrics(ctx, namespace, names, aggregator)
+		} else {
+			sampled, sampleWarnings, err = k.sampleUsageKubelet(ctx, namespace, names, slices.Sorted(maps.Keys(nodeNames)), aggregator)
+		}
+		if err != nil && i == 0 {
+			return nil, err
+		}
+		if err != nil {
+			sampleWarnings = append(sampleWarnings, fmt.Sprintf("sample %d failed: %v", i+1, err))
+		}
+		for _, warning := range sampleWarnings {
+			if !warnings[warning] {
+				warnings[warning] = true
+				samples.Warnings = append(samples.Warnings, warning)
+			}
+		}
+		if sampled > 0 {
+			samples.Samples++
+		}
+		ReportProgress(ctx, "Sampled the usage of %d pods (%d/%d)", sampled, i+1, max(options.Samples, 1))
+	}
+	samples.Containers = aggregator.Usage()
+	return samples, nil
+}
+
+// sampleUsageMetrics adds the usage of the containers of the named Pods reported by the metrics API to the
+// aggregator, returns the number of Pods sampled
+func (k *Kubernetes) sampleUsageMetrics(ctx context.Context, namespace string, names map[string]bool, aggregator *UsageAggregator) (int, error) {
+	podMetrics, err := k.AccessControlClientset().MetricsV1beta1Client().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pod metrics in namespace %s: %w", namespace, err)
+	}
+	sampled := 0
+	for _, podMetric := range podMetrics.Items {
+		if !names[podMetric.Name] {
+			continue
+		}
+		sampled++
+		for _, container := range podMetric.Containers {
+			aggregator.Add(container.Name, container.Usage.Cpu().MilliValue(), container.Usage.Memory().Value())
+		}
+	}
+	return sampled, nil
+}
+
+// sampleUsageKubelet adds the usage of the containers of the named Pods reported by the kubelet stats summaries of
+// the provided nodes to the aggregator, returns the number of Pods sampled and the nodes that failed as warnings
+func (k *Kubernetes) sampleUsageKubelet(ctx context.Context, namespace string, names map[string]bool, nodeNames []string, aggregator *UsageAggregator) (int, []string, error) {
+	results, targetErrors := FanOut(ctx, 0, nodeNames, func(name string) string { return "node/" + name },
+		func(ctx context.Context, name string) (*nodeStatsSummaryPods, error) {
+			summary, err := k.nodeStatsSummary(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			stats := &nodeStatsSummaryPods{}
+			if err = json.Unmarshal([]byte(summary), stats); err != nil {
+				return nil, fmt.Errorf("failed to parse node stats summary: %w", err)
+			}
+			return stats, nil
+		})
+	if len(results) == 0 && len(targetErrors) > 0 {
+		return 0, nil, targetErrors
+	}
+	var warnings []string
+	for _, targetError := range targetErrors {
+		warnings = append(warnings, fmt.Sprintf("unable to sample the usage of the pods of %s: %v", targetError.Target, targetError.Error))
+	}
+	sampled := 0
+	for _, result := range results {
+		for _, pod := range result.Value.Pods {
+			if pod.PodRef.Namespace != namespace || !names[pod.PodRef.Name] {
+				continue
+			}
+			sampled++
+			for _, container := range pod.Containers {
+				if container.CPU == nil || container.CPU.UsageNanoCores == nil || container.Memory == nil || container.Memory.WorkingSetBytes == nil {
+					continue
+				}
+				aggregator.Add(container.Name, int64(*container.CPU.UsageNanoCores/1_000_000), int64(*container.Memory.WorkingSetBytes))
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return sampled, warnings, nil
+}
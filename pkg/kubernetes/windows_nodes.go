@@ -0,0 +1,154 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// nodeFilesMaxWindowsPutSize is the maximum size of the files copied to a Windows node, the content is passed to
+	// the helper pod in an environment variable, which is limited to 32767 characters once base64 encoded
+	nodeFilesMaxWindowsPutSize = 20 * 1024
+	// windowsNodeFilesListScript lists the node directory (or file) at NODE_PATH
+	windowsNodeFilesListScript = `Get-ChildItem -Force -LiteralPath $env:NODE_PATH | Format-Table -AutoSize Mode, LastWriteTime, Length, Name | Out-String -Width 4096`
+	// windowsNodeFilesGetScript prints the base64 encoded content of the node file at NODE_PATH
+	windowsNodeFilesGetScript = `if (-not (Test-Path -LiteralPath $env:NODE_PATH -PathType Leaf)) { [Console]::Error.WriteLine("$env:SOURCE_PATH is not a regular file"); exit 1 }
+[Convert]::ToBase64String([IO.File]::ReadAllBytes($env:NODE_PATH))`
+	// windowsNodeFilesPutScript writes the base64 encoded CONTENT into the node file at NODE_PATH unless it already has
+	// the sha256 CHECKSUM, with the same output as the Linux script
+	windowsNodeFilesPutScript = `$before = '-'
+if (Test-Path -LiteralPath $env:NODE_PATH -PathType Leaf) { $before = (Get-FileHash -Algorithm SHA256 -LiteralPath $env:NODE_PATH).Hash.ToLower() }
+if ($before -eq $env:CHECKSUM) { "unchanged $before"; exit 0 }
+[IO.File]::WriteAllBytes($env:NODE_PATH, [Convert]::FromBase64String($env:CONTENT))
+$after = (Get-FileHash -Algorithm SHA256 -LiteralPath $env:NODE_PATH).Hash.ToLower()
+"$before $after"
+if ($after -ne $env:CHECKSUM) { [Console]::Error.WriteLine("checksum mismatch, expected sha256 $env:CHECKSUM"); exit 1 }`
+)
+
+var windowsAbsolutePath = regexp.MustCompile(`^[A-Za-z]:/`)
+
+// NodeOS returns the operating system of the provided node, as reported by the kubelet in the node status, falling
+// back to the well-known kubernetes.io/os label
+func NodeOS(node *v1.Node) string {
+	if node == nil {
+		return ""
+	}
+	if os := node.Status.NodeInfo.OperatingSystem; os != "" {
+		return os
+	}
+	return node.Labels[v1.LabelOSStable]
+}
+
+// IsWindowsNode returns true if the provided node runs Windows
+func IsWindowsNode(node *v1.Node) bool {
+	return NodeOS(node) == string(v1.Windows)
+}
+
+// powerShellCommand returns the command running the PowerShell script of the node in a HostProcess helper pod, the
+// script arguments are passed as environment variables so that they're never interpreted by PowerShell
+func powerShellCommand(script string) []string {
+	return []string{"powershell.exe", "-NoLogo", "-NoProfile", "-NonInteractive", "-Command", "$ErrorActionPreference = 'Stop'\n" + script}
+}
+
+// nodeFilesWindowsHelperPodOptions returns the options of the HostProcess helper pod that accesses the provided
+// Windows node path, checked against node_files_allowed_paths and node_files_denied_paths.
+// HostProcess containers can access every node file, node_files_unprivileged is not supported.
+func (k *Kubernetes) nodeFilesWindowsHelperPodOptions(nodeName, nodePath string) (HelperPodOptions, error) {
+	staticConfig := k.AccessControlClientset().staticConfig
+	if staticConfig.NodeFilesUnprivileged {
+		return HelperPodOptions{}, fmt.Errorf("node %s is a Windows node, node_files_unprivileged is only supported on Linux nodes", nodeName)
+	}
+	windowsPath, err := windowsNodePath(nodePath)
+	if err != nil {
+		return HelperPodOptions{}, err
+	}
+	if denied := windowsNodeFilesMatchPath(windowsPath, staticConfig.NodeFilesDeniedPaths); denied != "" {
+		return HelperPodOptions{}, fmt.Errorf("path not allowed: %s (denied by node_files_denied_paths entry %s)", nodePath, denied)
+	}
+	if len(staticConfig.NodeFilesAllowedPaths) > 0 && windowsNodeFilesMatchPath(windowsPath, staticConfig.NodeFilesAllowedPaths) == "" {
+		return HelperPodOptions{}, fmt.Errorf("path not allowed: %s (allowed paths are %s)",
+			nodePath, strings.Join(staticConfig.NodeFilesAllowedPaths, ", "))
+	}
+	return HelperPodOptions{
+		Role:               HelperImageWindowsHostProcess,
+		NodeName:           nodeName,
+		WindowsHostProcess: true,
+	}, nil
+}
+
+func (k *Kubernetes) nodeFilesWindowsList(ctx context.Context, helperPodOptions HelperPodOptions, options NodeFilesOptions) (string, error) {
+	nodePath, err := windowsNodePath(options.SourcePath)
+	if err != nil {
+		return "", err
+	}
+	helperPodOptions.Command = powerShellCommand(windowsNodeFilesListScript)
+	helperPodOptions.Env = []v1.EnvVar{{Name: "NODE_PATH", Value: nodePath}}
+	return k.RunHelperPod(ctx, helperPodOptions)
+}
+
+func (k *Kubernetes) nodeFilesWindowsGet(ctx context.Context, helperPodOptions HelperPodOptions, options NodeFilesOptions) (string, error) {
+	nodePath, err := windowsNodePath(options.SourcePath)
+	if err != nil {
+		return "", err
+	}
+	helperPodOptions.Command = powerShellCommand(windowsNodeFilesGetScript)
+	helperPodOptions.Env = []v1.EnvVar{{Name: "NODE_PATH", Value: nodePath}, {Name: "SOURCE_PATH", Value: options.SourcePath}}
+	encoded, err := k.RunHelperPod(ctx, helperPodOptions)
+	if err != nil {
+		return "", err
+	}
+	return k.nodeFilesGetResult(ctx, options, encoded)
+}
+
+func (k *Kubernetes) nodeFilesWindowsPut(ctx context.Context, helperPodOptions HelperPodOptions, options NodeFilesOptions) (string, error) {
+	nodePath, err := windowsNodePath(options.DestPath)
+	if err != nil {
+		return "", err
+	}
+	content, checksum, err := nodeFilesReadLocal(options, nodeFilesMaxWindowsPutSize)
+	if err != nil {
+		return "", err
+	}
+	helperPodOptions.Command = powerShellCommand(windowsNodeFilesPutScript)
+	helperPodOptions.Env = []v1.EnvVar{
+		{Name: "NODE_PATH", Value: nodePath},
+		{Name: "CONTENT", Value: base64.StdEncoding.EncodeToString(content)},
+		{Name: "CHECKSUM", Value: checksum},
+	}
+	out, err := k.RunHelperPod(ctx, helperPodOptions)
+	if err != nil {
+		return "", err
+	}
+	return nodeFilesPutResult(options, len(content), checksum, out)
+}
+
+// windowsNodePath returns the cleaned absolute Windows node path with forward slashes, e.g. C:/var/log for C:\var\log
+func windowsNodePath(nodePath string) (string, error) {
+	normalized := strings.ReplaceAll(nodePath, `\`, "/")
+	if !windowsAbsolutePath.MatchString(normalized) {
+		return "", fmt.Errorf("node path must be an absolute Windows path (e.g. C:\\var\\log), got %q", nodePath)
+	}
+	return strings.ToUpper(normalized[:1]) + ":" + path.Clean(normalized[2:]), nil
+}
+
+// windowsNodeFilesMatchPath returns the first of the paths that is the absolute Windows node path or contains it, or
+// "" if none, the paths are compared case-insensitively and the paths that are not Windows paths never match
+func windowsNodeFilesMatchPath(nodePath string, paths []string) string {
+	nodePath = strings.ToLower(nodePath)
+	for _, p := range paths {
+		cleaned, err := windowsNodePath(p)
+		if err != nil {
+			continue
+		}
+		if cleaned = strings.ToLower(cleaned); nodePath == cleaned || strings.HasPrefix(nodePath, strings.TrimSuffix(cleaned, "/")+"/") {
+			return p
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type WindowsNodesSuite struct {
+	suite.Suite
+}
+
+func (s *WindowsNodesSuite) TestNodeOS() {
+	s.Run("returns the operating system of the node status", func() {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.LabelOSStable: "linux"}},
+			Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{OperatingSystem: "windows"}},
+		}
+		s.Equal("windows", NodeOS(node))
+		s.True(IsWindowsNode(node))
+	})
+	s.Run("falls back to the kubernetes.io/os label", func() {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.LabelOSStable: "windows"}}}
+		s.True(IsWindowsNode(node))
+	})
+	s.Run("returns empty for unknown nodes", func() {
+		s.Empty(NodeOS(nil))
+		s.False(IsWindowsNode(&v1.Node{}))
+	})
+}
+
+func (s *WindowsNodesSuite) TestWindowsNodePath() {
+	for nodePath, expected := range map[string]string{
+		`C:\var\log\kubelet.log`:   "C:/var/log/kubelet.log",
+		`c:/var/log/../lib/`:       "C:/var/lib",
+		`C:\`:                      "C:/",
+		`D:\k\..\..\..\kubeconfig`: "D:/kubeconfig",
+	} {
+		s.Run(nodePath, func() {
+			windowsPath, err := windowsNodePath(nodePath)
+			s.Require().NoError(err)
+			s.Equal(expected, windowsPath)
+		})
+	}
+	for _, nodePath := range []string{"/var/log", `var\log`, "C:", `\\server\share`} {
+		s.Run(nodePath+" is not allowed", func() {
+			_, err := windowsNodePath(nodePath)
+			s.ErrorContains(err, "node path must be an absolute Windows path")
+		})
+	}
+}
+
+func (s *WindowsNodesSuite) TestWindowsNodeFilesMatchPath() {
+	paths := []string{"/var/log", `C:\var\log`, "c:/etc/kubernetes/pki/"}
+	s.Equal(`C:\var\log`, windowsNodeFilesMatchPath("C:/var/log/kubelet.log", paths))
+	s.Equal("c:/etc/kubernetes/pki/", windowsNodeFilesMatchPath("C:/ETC/Kubernetes/PKI/ca.key", paths), "matches case-insensitively")
+	s.Empty(windowsNodeFilesMatchPath("C:/var/logs", paths))
+	s.Empty(windowsNodeFilesMatchPath("D:/var/log", paths))
+}
+
+func TestWindowsNodes(t *testing.T) {
+	suite.Run(t, new(WindowsNodesSuite))
+}
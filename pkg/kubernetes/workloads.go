@@ -0,0 +1,142 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Workload kinds that can be scaled through the scale subresource
+const (
+	WorkloadKindDeployment  = "Deployment"
+	WorkloadKindStatefulSet = "StatefulSet"
+	WorkloadKindReplicaSet  = "ReplicaSet"
+)
+
+// WorkloadKinds is the list of workload kinds supported by WorkloadsScale
+var WorkloadKinds = []string{WorkloadKindDeployment, WorkloadKindStatefulSet, WorkloadKindReplicaSet}
+
+type WorkloadsScaleOptions struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Replicas  int32
+	// Force scales the workload even if it's managed by a HorizontalPodAutoscaler
+	Force bool
+	// WaitReady blocks until the workload reports the desired number of available replicas
+	WaitReady bool
+	// Timeout for the WaitReady condition
+	Timeout time.Duration
+}
+
+type WorkloadsScaleResult struct {
+	Kind             string   `json:"kind"`
+	Namespace        string   `json:"namespace"`
+	Name             string   `json:"name"`
+	PreviousReplicas int32    `json:"previousReplicas"`
+	Replicas         int32    `json:"replicas"`
+	ReadyReplicas    *int32   `json:"readyReplicas,omitempty"`
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// workloadStatus is a normalized view of the status of a scalable workload
+type workloadStatus struct {
+	generation         int64
+	observedGeneration int64
+	replicas           int32
+	updatedReplicas    int32
+	availableReplicas  int32
+}
+
+// WorkloadsScale scales the workload with the scale subresource like ResourcesScale (including the
+// HorizontalPodAutoscaler guard) and optionally waits until it reports the desired number of available replicas
+func (k *Kubernetes) WorkloadsScale(ctx context.Context, options WorkloadsScaleOptions) (*WorkloadsScaleResult, error) {
+	namespace := k.NamespaceOrDefault(options.Namespace)
+	result := &WorkloadsScaleResult{Kind: options.Kind, Namespace: namespace, Name: options.Name, Replicas: options.Replicas}
+
+	if !slices.Contains(WorkloadKinds, options.Kind) {
+		return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", options.Kind, WorkloadKinds)
+	}
+	gvk := &schema.GroupVersionKind{Group: appsv1.GroupName, Version: "v1", Kind: options.Kind}
+	previous, _, warnings, err := k.resourcesScale(ctx, gvk, namespace, options.Name, int64(options.Replicas), true, options.Force)
+	if err != nil {
+		return nil, err
+	}
+	previousReplicas, _, _ := unstructured.NestedInt64(previous.Object, "spec", "replicas")
+	result.PreviousReplicas = int32(previousReplicas)
+	result.Warnings = warnings
+
+	// Nothing to wait for when the scale was only validated with server-side dry-run
+	if !options.WaitReady || IsDryRun(ctx) {
+		return result, nil
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	var status *workloadStatus
+	err = wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		status, err = k.workloadStatus(ctx, options.Kind, namespace, options.Name)
+		if err != nil {
+			return false, err
+		}
+		return status.observedGeneration >= status.generation &&
+			status.replicas == options.Replicas &&
+			status.updatedReplicas == options.Replicas &&
+			status.availableReplicas == options.Replicas, nil
+	})
+	if status != nil {
+		result.ReadyReplicas = &status.availableReplicas
+	}
+	if err != nil {
+		return result, fmt.Errorf("%s %s did not reach %d available replicas: %w", options.Kind, options.Name, options.Replicas, err)
+	}
+	return result, nil
+}
+
+func (k *Kubernetes) workloadStatus(ctx context.Context, kind, namespace, name string) (*workloadStatus, error) {
+	switch kind {
+	case WorkloadKindDeployment:
+		d, err := k.AccessControlClientset().AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workloadStatus{d.Generation, d.Status.ObservedGeneration, d.Status.Replicas, d.Status.UpdatedReplicas, d.Status.AvailableReplicas}, nil
+	case WorkloadKindStatefulSet:
+		s, err := k.AccessControlClientset().AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workloadStatus{s.Generation, s.Status.ObservedGeneration, s.Status.Replicas, s.Status.UpdatedReplicas, s.Status.AvailableReplicas}, nil
+	case WorkloadKindReplicaSet:
+		r, err := k.AccessControlClientset().AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		// ReplicaSets don't perform rolling updates, all of their replicas are up to date
+		return &workloadStatus{r.Generation, r.Status.ObservedGeneration, r.Status.Replicas, r.Status.Replicas, r.Status.AvailableReplicas}, nil
+	}
+	return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", kind, WorkloadKinds)
+}
+
+// workloadHorizontalPodAutoscaler returns the HorizontalPodAutoscaler targeting the provided workload (if any)
+func (k *Kubernetes) workloadHorizontalPodAutoscaler(ctx context.Context, kind, namespace, name string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpas, err := k.AccessControlClientset().AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, hpa := range hpas.Items {
+		if hpa.Spec.ScaleTargetRef.Kind == kind && hpa.Spec.ScaleTargetRef.Name == name {
+			return &hpa, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,520 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	WorkloadKindDaemonSet = "DaemonSet"
+	// workloadStatusMaxEvents is the maximum number of recent events reported by WorkloadsStatus
+	workloadStatusMaxEvents = 10
+	// workloadStatusMaxReplicaSets is the maximum number of recent ReplicaSets of a Deployment reported by WorkloadsStatus
+	workloadStatusMaxReplicaSets = 3
+	// deploymentRevisionAnnotation is the revision of the Deployment a ReplicaSet was created for
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// WorkloadStatusKinds is the list of workload kinds supported by WorkloadsStatus
+var WorkloadStatusKinds = []string{WorkloadKindDeployment, WorkloadKindStatefulSet, WorkloadKindDaemonSet}
+
+// Rollout states reported by WorkloadsStatus
+const (
+	WorkloadRolloutComplete    = "Complete"
+	WorkloadRolloutProgressing = "Progressing"
+	WorkloadRolloutPaused      = "Paused"
+	WorkloadRolloutStalled     = "Stalled"
+	// WorkloadRolloutManual is reported for workloads with an OnDelete update strategy, pods are only updated when deleted
+	WorkloadRolloutManual = "Manual"
+)
+
+// Health verdicts reported by WorkloadsStatus
+const (
+	WorkloadHealthy = "Healthy"
+	// WorkloadUpdating is reported for workloads with a rollout in progress and no failing Pods
+	WorkloadUpdating = "Updating"
+	// WorkloadDegraded is reported for workloads with unavailable replicas, failing Pods, or a stalled rollout
+	WorkloadDegraded = "Degraded"
+	// WorkloadUnavailable is reported for workloads without any available replica
+	WorkloadUnavailable = "Unavailable"
+)
+
+// workloadPodRunningReasons are the kubectl STATUS values of the Pods that are not failing
+var workloadPodRunningReasons = map[string]bool{
+	"Running": true, "Succeeded": true, "Completed": true, "Pending": true, "ContainerCreating": true, "PodInitializing": true, "Terminating": true,
+}
+
+// WorkloadStatus is a compact view of the desired state (spec) and the observed state (status) of a workload,
+// the equivalent of kubectl get, describe, and events for the workload
+type WorkloadStatus struct {
+	// Summary is a one line description of the workload health
+	Summary string `json:"summary"`
+	// Health is the verdict correlating the replicas, the rollout, and the failures of the Pods
+	Health             string              `json:"health"`
+	HealthReasons      []string            `json:"healthReasons,omitempty"`
+	Kind               string              `json:"kind"`
+	Namespace          string              `json:"namespace"`
+	Name               string              `json:"name"`
+	Generation         int64               `json:"generation"`
+	ObservedGeneration int64               `json:"observedGeneration"`
+	Selector           string              `json:"selector,omitempty"`
+	Strategy           string              `json:"strategy,omitempty"`
+	Rollout            string              `json:"rollout"`
+	RolloutMessage     string              `json:"rolloutMessage,omitempty"`
+	Replicas           WorkloadReplicas    `json:"replicas"`
+	Images             []string            `json:"images,omitempty"`
+	Conditions         []WorkloadCondition `json:"conditions,omitempty"`
+	// HorizontalPodAutoscaler targeting the workload (if any)
+	HorizontalPodAutoscaler *WorkloadHorizontalPodAutoscaler `json:"horizontalPodAutoscaler,omitempty"`
+	// ReplicaSets are the most recent ReplicaSets of a Deployment, newest revision first
+	ReplicaSets []WorkloadReplicaSet `json:"replicaSets,omitempty"`
+	// PodFailures counts the failing Pods of the workload by reason, e.g. CrashLoopBackOff, ImagePullBackOff, OOMKilled
+	PodFailures map[string]int32 `json:"podFailures,omitempty"`
+	// Terminations are the abnormal container terminations of the workload Pods, with the explanation of their exit codes
+	Terminations []ContainerTermination `json:"terminations,omitempty"`
+	// Events are the most recent events of the workload and the objects it manages (ReplicaSets and Pods), newest first
+	Events   []WorkloadEvent `json:"events,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+// WorkloadReplicas are the replica counters of a workload, for DaemonSets they count the scheduled nodes
+type WorkloadReplicas struct {
+	Desired      int32 `json:"desired"`
+	Current      int32 `json:"current"`
+	Ready        int32 `json:"ready"`
+	Updated      int32 `json:"updated"`
+	Available    int32 `json:"available"`
+	Unavailable  int32 `json:"unavailable"`
+	Misscheduled int32 `json:"misscheduled,omitempty"`
+}
+
+// WorkloadReplicaSet is a compact view of a ReplicaSet managed by a Deployment
+type WorkloadReplicaSet struct {
+	Name      string   `json:"name"`
+	Revision  string   `json:"revision,omitempty"`
+	Replicas  int32    `json:"replicas"`
+	Ready     int32    `json:"ready"`
+	Available int32    `json:"available"`
+	Images    []string `json:"images,omitempty"`
+	Created   string   `json:"created,omitempty"`
+}
+
+type WorkloadCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+type WorkloadHorizontalPodAutoscaler struct {
+	Name            string              `json:"name"`
+	MinReplicas     int32               `json:"minReplicas"`
+	MaxReplicas     int32               `json:"maxReplicas"`
+	CurrentReplicas int32               `json:"currentReplicas"`
+	DesiredReplicas int32               `json:"desiredReplicas"`
+	LastScaleTime   string              `json:"lastScaleTime,omitempty"`
+	Conditions      []WorkloadCondition `json:"conditions,omitempty"`
+}
+
+type WorkloadEvent struct {
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Object    string `json:"object"`
+	Message   string `json:"message"`
+	Count     int32  `json:"count,omitempty"`
+}
+
+func (k *Kubernetes) WorkloadsStatus(ctx context.Context, kind, namespace, name string) (*WorkloadStatus, error) {
+	namespace = k.NamespaceOrDefault(namespace)
+	apps := k.AccessControlClientset().AppsV1()
+	var status *WorkloadStatus
+	var selector *metav1.LabelSelector
+	switch kind {
+	case WorkloadKindDeployment:
+		d, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		status, selector = DeploymentStatus(d), d.Spec.Selector
+	case WorkloadKindStatefulSet:
+		s, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		status, selector = StatefulSetStatus(s), s.Spec.Selector
+	case WorkloadKindDaemonSet:
+		ds, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		status, selector = DaemonSetStatus(ds), ds.Spec.Selector
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", kind, WorkloadStatusKinds)
+	}
+
+	// DaemonSets can't be the target of a HorizontalPodAutoscaler
+	if kind != WorkloadKindDaemonSet {
+		hpa, err := k.workloadHorizontalPodAutoscaler(ctx, kind, namespace, name)
+		if err != nil {
+			status.Warnings = append(status.Warnings, fmt.Sprintf("unable to check for HorizontalPodAutoscalers targeting the workload: %v", err))
+		} else if hpa != nil {
+			status.HorizontalPodAutoscaler = workloadHorizontalPodAutoscalerStatus(hpa)
+		}
+	}
+
+	// Events of the workload and of the objects it manages
+	objects := map[string]bool{kind + "/" + name: true}
+	if kind == WorkloadKindDeployment {
+		replicaSets, err := apps.ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: status.Selector})
+		if err != nil {
+			status.Warnings = append(status.Warnings, fmt.Sprintf("unable to list the ReplicaSets of the workload: %v", err))
+		} else {
+			owned := make([]appsv1.ReplicaSet, 0, len(replicaSets.Items))
+			for _, rs := range replicaSets.Items {
+				if owner := metav1.GetControllerOf(&rs); owner != nil && owner.Kind == kind && owner.Name == name {
+					objects[WorkloadKindReplicaSet+"/"+rs.Name] = true
+					owned = append(owned, rs)
+				}
+			}
+			status.ReplicaSets = workloadReplicaSets(owned, workloadStatusMaxReplicaSets)
+		}
+	}
+	if selector != nil {
+		pods, err := k.AccessControlClientset().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: status.Selector})
+		if err != nil {
+			status.Warnings = append(status.Warnings, fmt.Sprintf("unable to list the Pods of the workload: %v", err))
+		} else {
+			status.PodFailures = WorkloadPodFailures(pods.Items)
+			for _, pod := range pods.Items {
+				objects["Pod/"+pod.Name] = true
+				for _, termination := range ContainerTerminations(&pod) {
+					termination.Pod = pod.Name
+					status.Terminations = append(status.Terminations, termination)
+				}
+			}
+		}
+	}
+	events, err := k.AccessControlClientset().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("unable to list the events of the workload: %v", err))
+	} else {
+		status.Events = workloadEvents(events.Items, objects, workloadStatusMaxEvents)
+	}
+	status.Health, status.HealthReasons = WorkloadHealth(status)
+	status.Summary = status.Health + ": " + status.Summary
+	return status, nil
+}
+
+// WorkloadHealth returns the health verdict of the workload, correlating its replicas, its rollout, and the failures
+// of its Pods, and the reasons of the verdict
+func WorkloadHealth(status *WorkloadStatus) (string, []string) {
+	reasons := make([]string, 0)
+	if unavailable := status.Replicas.Desired - status.Replicas.Available; unavailable > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d of %d replicas are unavailable", unavailable, status.Replicas.Desired))
+	}
+	if status.Rollout == WorkloadRolloutStalled {
+		reasons = append(reasons, "the rollout is stalled: "+status.RolloutMessage)
+	}
+	for _, reason := range slices.Sorted(maps.Keys(status.PodFailures)) {
+		pods := "pods"
+		if status.PodFailures[reason] == 1 {
+			pods = "pod"
+		}
+		reasons = append(reasons, fmt.Sprintf("%d %s %s", status.PodFailures[reason], pods, reason))
+	}
+	switch {
+	case status.Replicas.Desired > 0 && status.Replicas.Available == 0:
+		return WorkloadUnavailable, reasons
+	case len(reasons) > 0:
+		return WorkloadDegraded, reasons
+	case status.Rollout == WorkloadRolloutProgressing && status.RolloutMessage != "":
+		return WorkloadUpdating, []string{status.RolloutMessage}
+	case status.Rollout == WorkloadRolloutProgressing:
+		return WorkloadUpdating, nil
+	}
+	return WorkloadHealthy, nil
+}
+
+// WorkloadPodFailures counts the failing Pods by reason: the kubectl STATUS of the Pods that are not running normally
+// (e.g. CrashLoopBackOff, ImagePullBackOff, Init:Error), Unschedulable for the Pods that can't be scheduled, and
+// OOMKilled for the Pods with containers killed for exceeding their memory limit, even if they restarted since
+func WorkloadPodFailures(pods []v1.Pod) map[string]int32 {
+	failures := make(map[string]int32)
+	for i := range pods {
+		pod := &pods[i]
+		reason := PodStatusReason(pod)
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason == v1.PodReasonUnschedulable {
+				reason = v1.PodReasonUnschedulable
+			}
+		}
+		if reason != "" && !workloadPodRunningReasons[reason] && !workloadPodInitializing(reason) {
+			failures[reason]++
+		}
+		if reason != "OOMKilled" && slices.ContainsFunc(ContainerTerminations(pod), func(t ContainerTermination) bool { return t.Reason == "OOMKilled" }) {
+			failures["OOMKilled"]++
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return failures
+}
+
+// workloadPodInitializing returns true for the kubectl STATUS of Pods running their init containers, e.g. Init:0/2
+func workloadPodInitializing(reason string) bool {
+	progress, found := strings.CutPrefix(reason, "Init:")
+	if !found {
+		return false
+	}
+	done, total, found := strings.Cut(progress, "/")
+	_, doneErr := strconv.Atoi(done)
+	_, totalErr := strconv.Atoi(total)
+	return found && doneErr == nil && totalErr == nil
+}
+
+// workloadReplicaSets returns the most recent ReplicaSets of a Deployment, newest revision first
+func workloadReplicaSets(replicaSets []appsv1.ReplicaSet, limit int) []WorkloadReplicaSet {
+	revision := func(rs *appsv1.ReplicaSet) int64 {
+		r, _ := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+		return r
+	}
+	sort.SliceStable(replicaSets, func(i, j int) bool {
+		return revision(&replicaSets[i]) > revision(&replicaSets[j])
+	})
+	if len(replicaSets) > limit {
+		replicaSets = replicaSets[:limit]
+	}
+	result := make([]WorkloadReplicaSet, 0, len(replicaSets))
+	for _, rs := range replicaSets {
+		replicaSet := WorkloadReplicaSet{
+			Name:      rs.Name,
+			Revision:  rs.Annotations[deploymentRevisionAnnotation],
+			Replicas:  rs.Status.Replicas,
+			Ready:     rs.Status.ReadyReplicas,
+			Available: rs.Status.AvailableReplicas,
+		}
+		for _, c := range rs.Spec.Template.Spec.Containers {
+			replicaSet.Images = append(replicaSet.Images, c.Image)
+		}
+		if !rs.CreationTimestamp.IsZero() {
+			replicaSet.Created = rs.CreationTimestamp.UTC().Format(time.RFC3339)
+		}
+		result = append(result, replicaSet)
+	}
+	return result
+}
+
+// DeploymentStatus returns the status of the Deployment, the rollout follows the logic of kubectl rollout status
+func DeploymentStatus(d *appsv1.Deployment) *WorkloadStatus {
+	desired := ptr.Deref(d.Spec.Replicas, 1)
+	status := newWorkloadStatus(WorkloadKindDeployment, &d.ObjectMeta, d.Status.ObservedGeneration, d.Spec.Selector, &d.Spec.Template)
+	status.Strategy = string(d.Spec.Strategy.Type)
+	status.Replicas = WorkloadReplicas{
+		Desired:     desired,
+		Current:     d.Status.Replicas,
+		Ready:       d.Status.ReadyReplicas,
+		Updated:     d.Status.UpdatedReplicas,
+		Available:   d.Status.AvailableReplicas,
+		Unavailable: d.Status.UnavailableReplicas,
+	}
+	for _, c := range d.Status.Conditions {
+		status.Conditions = append(status.Conditions, workloadCondition(string(c.Type), string(c.Status), c.Reason, c.Message, c.LastTransitionTime))
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			status.Rollout, status.RolloutMessage = WorkloadRolloutStalled, c.Message
+		}
+	}
+	switch {
+	case status.Rollout != "":
+	case d.Spec.Paused:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutPaused, "the rollout is paused, resume it to apply the changes of the pod template"
+	case d.Status.ObservedGeneration < d.Generation:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing, "waiting for the latest changes to be observed by the controller"
+	case d.Status.UpdatedReplicas < desired:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d out of %d new replicas have been updated", d.Status.UpdatedReplicas, desired)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d old replicas are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	default:
+		status.Rollout = WorkloadRolloutComplete
+	}
+	status.Summary = workloadSummary(status)
+	return status
+}
+
+// StatefulSetStatus returns the status of the StatefulSet, the rollout follows the logic of kubectl rollout status
+func StatefulSetStatus(s *appsv1.StatefulSet) *WorkloadStatus {
+	desired := ptr.Deref(s.Spec.Replicas, 1)
+	status := newWorkloadStatus(WorkloadKindStatefulSet, &s.ObjectMeta, s.Status.ObservedGeneration, s.Spec.Selector, &s.Spec.Template)
+	status.Strategy = string(s.Spec.UpdateStrategy.Type)
+	status.Replicas = WorkloadReplicas{
+		Desired:     desired,
+		Current:     s.Status.Replicas,
+		Ready:       s.Status.ReadyReplicas,
+		Updated:     s.Status.UpdatedReplicas,
+		Available:   s.Status.AvailableReplicas,
+		Unavailable: max(desired-s.Status.AvailableReplicas, 0),
+	}
+	for _, c := range s.Status.Conditions {
+		status.Conditions = append(status.Conditions, workloadCondition(string(c.Type), string(c.Status), c.Reason, c.Message, c.LastTransitionTime))
+	}
+	partition := int32(0)
+	if s.Spec.UpdateStrategy.RollingUpdate != nil {
+		partition = ptr.Deref(s.Spec.UpdateStrategy.RollingUpdate.Partition, 0)
+	}
+	switch {
+	case s.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutManual, "pods are only updated when they're deleted (OnDelete update strategy)"
+	case s.Status.ObservedGeneration < s.Generation:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing, "waiting for the latest changes to be observed by the controller"
+	case s.Status.ReadyReplicas < desired:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d of %d replicas are ready", s.Status.ReadyReplicas, desired)
+	case partition > 0 && s.Status.UpdatedReplicas < desired-partition:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d of %d replicas above partition %d have been updated", s.Status.UpdatedReplicas, desired-partition, partition)
+	case partition == 0 && s.Status.UpdateRevision != s.Status.CurrentRevision:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d of %d replicas have been updated to revision %s", s.Status.UpdatedReplicas, desired, s.Status.UpdateRevision)
+	default:
+		status.Rollout = WorkloadRolloutComplete
+	}
+	status.Summary = workloadSummary(status)
+	return status
+}
+
+// DaemonSetStatus returns the status of the DaemonSet, the rollout follows the logic of kubectl rollout status
+func DaemonSetStatus(ds *appsv1.DaemonSet) *WorkloadStatus {
+	status := newWorkloadStatus(WorkloadKindDaemonSet, &ds.ObjectMeta, ds.Status.ObservedGeneration, ds.Spec.Selector, &ds.Spec.Template)
+	status.Strategy = string(ds.Spec.UpdateStrategy.Type)
+	status.Replicas = WorkloadReplicas{
+		Desired:      ds.Status.DesiredNumberScheduled,
+		Current:      ds.Status.CurrentNumberScheduled,
+		Ready:        ds.Status.NumberReady,
+		Updated:      ds.Status.UpdatedNumberScheduled,
+		Available:    ds.Status.NumberAvailable,
+		Unavailable:  ds.Status.NumberUnavailable,
+		Misscheduled: ds.Status.NumberMisscheduled,
+	}
+	for _, c := range ds.Status.Conditions {
+		status.Conditions = append(status.Conditions, workloadCondition(string(c.Type), string(c.Status), c.Reason, c.Message, c.LastTransitionTime))
+	}
+	switch {
+	case ds.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutManual, "pods are only updated when they're deleted (OnDelete update strategy)"
+	case ds.Status.ObservedGeneration < ds.Generation:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing, "waiting for the latest changes to be observed by the controller"
+	case ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d out of %d new pods have been updated", ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled)
+	case ds.Status.NumberAvailable < ds.Status.DesiredNumberScheduled:
+		status.Rollout, status.RolloutMessage = WorkloadRolloutProgressing,
+			fmt.Sprintf("%d of %d updated pods are available", ds.Status.NumberAvailable, ds.Status.DesiredNumberScheduled)
+	default:
+		status.Rollout = WorkloadRolloutComplete
+	}
+	status.Summary = workloadSummary(status)
+	return status
+}
+
+func newWorkloadStatus(kind string, meta *metav1.ObjectMeta, observedGeneration int64, selector *metav1.LabelSelector, template *v1.PodTemplateSpec) *WorkloadStatus {
+	status := &WorkloadStatus{
+		Kind:               kind,
+		Namespace:          meta.Namespace,
+		Name:               meta.Name,
+		Generation:         meta.Generation,
+		ObservedGeneration: observedGeneration,
+	}
+	if s, err := metav1.LabelSelectorAsSelector(selector); err == nil && selector != nil {
+		status.Selector = s.String()
+	}
+	for _, c := range template.Spec.Containers {
+		status.Images = append(status.Images, c.Image)
+	}
+	return status
+}
+
+func workloadCondition(conditionType, status, reason, message string, lastTransitionTime metav1.Time) WorkloadCondition {
+	condition := WorkloadCondition{Type: conditionType, Status: status, Reason: reason, Message: message}
+	if !lastTransitionTime.IsZero() {
+		condition.LastTransitionTime = lastTransitionTime.UTC().Format(time.RFC3339)
+	}
+	return condition
+}
+
+func workloadSummary(status *WorkloadStatus) string {
+	unit := "replicas"
+	if status.Kind == WorkloadKindDaemonSet {
+		unit = "pods"
+	}
+	summary := fmt.Sprintf("%s %s: %d/%d %s ready, %d updated, %d available, rollout %s",
+		status.Kind, status.Name, status.Replicas.Ready, status.Replicas.Desired, unit,
+		status.Replicas.Updated, status.Replicas.Available, strings.ToLower(status.Rollout))
+	if status.RolloutMessage != "" {
+		summary += " (" + status.RolloutMessage + ")"
+	}
+	return summary
+}
+
+func workloadHorizontalPodAutoscalerStatus(hpa *autoscalingv2.HorizontalPodAutoscaler) *WorkloadHorizontalPodAutoscaler {
+	status := &WorkloadHorizontalPodAutoscaler{
+		Name:            hpa.Name,
+		MinReplicas:     ptr.Deref(hpa.Spec.MinReplicas, 1),
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+	}
+	if hpa.Status.LastScaleTime != nil {
+		status.LastScaleTime = hpa.Status.LastScaleTime.UTC().Format(time.RFC3339)
+	}
+	for _, c := range hpa.Status.Conditions {
+		status.Conditions = append(status.Conditions, workloadCondition(string(c.Type), string(c.Status), c.Reason, c.Message, c.LastTransitionTime))
+	}
+	return status
+}
+
+// workloadEvents returns the most recent events (newest first) involving the provided objects (Kind/Name)
+func workloadEvents(events []v1.Event, objects map[string]bool, limit int) []WorkloadEvent {
+	matching := make([]*v1.Event, 0)
+	for i := range events {
+		if objects[events[i].InvolvedObject.Kind+"/"+events[i].InvolvedObject.Name] {
+			matching = append(matching, &events[i])
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return eventTimestamp(matching[i]).After(eventTimestamp(matching[j]))
+	})
+	if len(matching) > limit {
+		matching = matching[:limit]
+	}
+	result := make([]WorkloadEvent, 0, len(matching))
+	for _, event := range matching {
+		result = append(result, WorkloadEvent{
+			Timestamp: eventTimestamp(event).UTC().Format(time.RFC3339),
+			Type:      event.Type,
+			Reason:    event.Reason,
+			Object:    event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			Message:   strings.TrimSpace(event.Message),
+			Count:     event.Count,
+		})
+	}
+	return result
+}
@@ -0,0 +1,266 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type WorkloadsStatusSuite struct {
+	suite.Suite
+}
+
+func (s *WorkloadsStatusSuite) TestDeploymentStatus() {
+	deployment := func(mutate func(d *appsv1.Deployment)) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web", Generation: 2},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(3)),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Strategy: appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType},
+				Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "nginx:1.27"}}}},
+			},
+			Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+		}
+		if mutate != nil {
+			mutate(d)
+		}
+		return d
+	}
+	s.Run("complete rollout", func() {
+		status := DeploymentStatus(deployment(nil))
+		s.Equal(WorkloadRolloutComplete, status.Rollout)
+		s.Equal(WorkloadReplicas{Desired: 3, Current: 3, Ready: 3, Updated: 3, Available: 3}, status.Replicas)
+		s.Equal("app=web", status.Selector)
+		s.Equal("RollingUpdate", status.Strategy)
+		s.Equal([]string{"nginx:1.27"}, status.Images)
+		s.Equal("Deployment web: 3/3 replicas ready, 3 updated, 3 available, rollout complete", status.Summary)
+	})
+	s.Run("generation not observed", func() {
+		status := DeploymentStatus(deployment(func(d *appsv1.Deployment) { d.Generation = 3 }))
+		s.Equal(WorkloadRolloutProgressing, status.Rollout)
+		s.Equal("waiting for the latest changes to be observed by the controller", status.RolloutMessage)
+	})
+	s.Run("replicas not updated", func() {
+		status := DeploymentStatus(deployment(func(d *appsv1.Deployment) { d.Status.UpdatedReplicas = 1 }))
+		s.Equal(WorkloadRolloutProgressing, status.Rollout)
+		s.Equal("1 out of 3 new replicas have been updated", status.RolloutMessage)
+	})
+	s.Run("old replicas pending termination", func() {
+		status := DeploymentStatus(deployment(func(d *appsv1.Deployment) { d.Status.Replicas = 4 }))
+		s.Equal("1 old replicas are pending termination", status.RolloutMessage)
+	})
+	s.Run("updated replicas not available", func() {
+		status := DeploymentStatus(deployment(func(d *appsv1.Deployment) {
+			d.Status.AvailableReplicas = 2
+			d.Status.UnavailableReplicas = 1
+		}))
+		s.Equal("2 of 3 updated replicas are available", status.RolloutMessage)
+		s.Equal(int32(1), status.Replicas.Unavailable)
+	})
+	s.Run("progress deadline exceeded", func() {
+		status := DeploymentStatus(deployment(func(d *appsv1.Deployment) {
+			d.Status.UpdatedReplicas = 1
+			d.Status.Conditions = []appsv1.DeploymentCondition{{
+				Type:    appsv1.DeploymentProgressing,
+				Status:  v1.ConditionFalse,
+				Reason:  "ProgressDeadlineExceeded",
+				Message: `ReplicaSet "web-5d9f" has timed out progressing.`,
+			}}
+		}))
+		s.Equal(WorkloadRolloutStalled, status.Rollout)
+		s.Equal(`ReplicaSet "web-5d9f" has timed out progressing.`, status.RolloutMessage)
+		s.Require().Len(status.Conditions, 1)
+		s.Equal("ProgressDeadlineExceeded", status.Conditions[0].Reason)
+	})
+	s.Run("paused", func() {
+		status := DeploymentStatus(deployment(func(d *appsv1.Deployment) { d.Spec.Paused = true }))
+		s.Equal(WorkloadRolloutPaused, status.Rollout)
+	})
+}
+
+func (s *WorkloadsStatusSuite) TestStatefulSetStatus() {
+	statefulSet := func(mutate func(ss *appsv1.StatefulSet)) *appsv1.StatefulSet {
+		ss := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "db", Generation: 1},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:       ptr.To(int32(3)),
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+			},
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: 1, Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3,
+				CurrentRevision: "db-1", UpdateRevision: "db-1",
+			},
+		}
+		if mutate != nil {
+			mutate(ss)
+		}
+		return ss
+	}
+	s.Run("complete rollout", func() {
+		s.Equal(WorkloadRolloutComplete, StatefulSetStatus(statefulSet(nil)).Rollout)
+	})
+	s.Run("replicas not ready", func() {
+		status := StatefulSetStatus(statefulSet(func(ss *appsv1.StatefulSet) {
+			ss.Status.ReadyReplicas = 2
+			ss.Status.AvailableReplicas = 2
+		}))
+		s.Equal("2 of 3 replicas are ready", status.RolloutMessage)
+		s.Equal(int32(1), status.Replicas.Unavailable)
+	})
+	s.Run("revision not rolled out", func() {
+		status := StatefulSetStatus(statefulSet(func(ss *appsv1.StatefulSet) {
+			ss.Status.UpdateRevision = "db-2"
+			ss.Status.UpdatedReplicas = 1
+		}))
+		s.Equal(WorkloadRolloutProgressing, status.Rollout)
+		s.Equal("1 of 3 replicas have been updated to revision db-2", status.RolloutMessage)
+	})
+	s.Run("partitioned rollout", func() {
+		status := StatefulSetStatus(statefulSet(func(ss *appsv1.StatefulSet) {
+			ss.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To(int32(2))}
+			ss.Status.UpdateRevision = "db-2"
+			ss.Status.UpdatedReplicas = 1
+		}))
+		s.Equal(WorkloadRolloutComplete, status.Rollout)
+	})
+	s.Run("OnDelete update strategy", func() {
+		status := StatefulSetStatus(statefulSet(func(ss *appsv1.StatefulSet) {
+			ss.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+		}))
+		s.Equal(WorkloadRolloutManual, status.Rollout)
+	})
+}
+
+func (s *WorkloadsStatusSuite) TestDaemonSetStatus() {
+	status := DaemonSetStatus(&appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "fluentd", Generation: 1},
+		Spec:       appsv1.DaemonSetSpec{UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType}},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration: 1, DesiredNumberScheduled: 5, CurrentNumberScheduled: 5, NumberReady: 4,
+			UpdatedNumberScheduled: 5, NumberAvailable: 4, NumberUnavailable: 1, NumberMisscheduled: 1,
+		},
+	})
+	s.Equal(WorkloadReplicas{Desired: 5, Current: 5, Ready: 4, Updated: 5, Available: 4, Unavailable: 1, Misscheduled: 1}, status.Replicas)
+	s.Equal(WorkloadRolloutProgressing, status.Rollout)
+	s.Equal("DaemonSet fluentd: 4/5 pods ready, 5 updated, 4 available, rollout progressing (4 of 5 updated pods are available)", status.Summary)
+}
+
+func (s *WorkloadsStatusSuite) TestWorkloadEvents() {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	event := func(kind, name, reason string, age time.Duration) v1.Event {
+		return v1.Event{
+			InvolvedObject: v1.ObjectReference{Kind: kind, Name: name},
+			Reason:         reason,
+			Type:           v1.EventTypeNormal,
+			FirstTimestamp: metav1.NewTime(now.Add(-age)),
+		}
+	}
+	events := workloadEvents([]v1.Event{
+		event("Deployment", "web", "ScalingReplicaSet", 10*time.Minute),
+		event("Pod", "web-1", "BackOff", time.Minute),
+		event("Pod", "other-1", "BackOff", 0),
+		event("ReplicaSet", "web-5d9f", "SuccessfulCreate", 5*time.Minute),
+		event("Deployment", "other", "ScalingReplicaSet", 0),
+	}, map[string]bool{"Deployment/web": true, "ReplicaSet/web-5d9f": true, "Pod/web-1": true}, 2)
+	s.Run("filters events of the workload objects and sorts them newest first", func() {
+		s.Require().Len(events, 2)
+		s.Equal("Pod/web-1", events[0].Object)
+		s.Equal("2025-01-01T09:59:00Z", events[0].Timestamp)
+		s.Equal("ReplicaSet/web-5d9f", events[1].Object)
+	})
+}
+
+func (s *WorkloadsStatusSuite) TestWorkloadPodFailures() {
+	waiting := func(name, reason string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.PodStatus{Phase: v1.PodPending, ContainerStatuses: []v1.ContainerStatus{{
+				Name: "web", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}},
+			}}},
+		}
+	}
+	s.Run("ignores running and starting Pods", func() {
+		s.Nil(WorkloadPodFailures([]v1.Pod{
+			{Status: v1.PodStatus{Phase: v1.PodRunning}},
+			waiting("creating", "ContainerCreating"),
+			{Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "init"}}}, Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{{
+				Name: "init", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			}}}},
+		}))
+	})
+	s.Run("counts failing Pods by reason", func() {
+		s.Equal(map[string]int32{"CrashLoopBackOff": 2, "ImagePullBackOff": 1}, WorkloadPodFailures([]v1.Pod{
+			waiting("crash-1", "CrashLoopBackOff"),
+			waiting("crash-2", "CrashLoopBackOff"),
+			waiting("pull", "ImagePullBackOff"),
+		}))
+	})
+	s.Run("counts unschedulable Pods", func() {
+		s.Equal(map[string]int32{"Unschedulable": 1}, WorkloadPodFailures([]v1.Pod{{Status: v1.PodStatus{
+			Phase:      v1.PodPending,
+			Conditions: []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable}},
+		}}}))
+	})
+	s.Run("counts Pods with OOMKilled containers that restarted", func() {
+		s.Equal(map[string]int32{"OOMKilled": 1}, WorkloadPodFailures([]v1.Pod{{Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:                 "web",
+				State:                v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}},
+		}}}))
+	})
+}
+
+func (s *WorkloadsStatusSuite) TestWorkloadHealth() {
+	s.Run("healthy", func() {
+		health, reasons := WorkloadHealth(&WorkloadStatus{Rollout: WorkloadRolloutComplete, Replicas: WorkloadReplicas{Desired: 3, Available: 3}})
+		s.Equal(WorkloadHealthy, health)
+		s.Empty(reasons)
+	})
+	s.Run("scaled to zero is healthy", func() {
+		health, _ := WorkloadHealth(&WorkloadStatus{Rollout: WorkloadRolloutComplete})
+		s.Equal(WorkloadHealthy, health)
+	})
+	s.Run("updating", func() {
+		health, reasons := WorkloadHealth(&WorkloadStatus{
+			Rollout: WorkloadRolloutProgressing, RolloutMessage: "1 of 3 updated replicas are available", Replicas: WorkloadReplicas{Desired: 3, Available: 3},
+		})
+		s.Equal(WorkloadUpdating, health)
+		s.Equal([]string{"1 of 3 updated replicas are available"}, reasons)
+	})
+	s.Run("degraded by unavailable replicas and failing Pods", func() {
+		health, reasons := WorkloadHealth(&WorkloadStatus{
+			Rollout:     WorkloadRolloutProgressing,
+			Replicas:    WorkloadReplicas{Desired: 3, Available: 2},
+			PodFailures: map[string]int32{"ImagePullBackOff": 1, "CrashLoopBackOff": 2},
+		})
+		s.Equal(WorkloadDegraded, health)
+		s.Equal([]string{"1 of 3 replicas are unavailable", "2 pods CrashLoopBackOff", "1 pod ImagePullBackOff"}, reasons)
+	})
+	s.Run("degraded by stalled rollout", func() {
+		health, reasons := WorkloadHealth(&WorkloadStatus{
+			Rollout: WorkloadRolloutStalled, RolloutMessage: "progress deadline exceeded", Replicas: WorkloadReplicas{Desired: 3, Available: 3},
+		})
+		s.Equal(WorkloadDegraded, health)
+		s.Equal([]string{"the rollout is stalled: progress deadline exceeded"}, reasons)
+	})
+	s.Run("unavailable", func() {
+		health, reasons := WorkloadHealth(&WorkloadStatus{
+			Replicas: WorkloadReplicas{Desired: 2}, PodFailures: map[string]int32{"OOMKilled": 2},
+		})
+		s.Equal(WorkloadUnavailable, health)
+		s.Equal([]string{"2 of 2 replicas are unavailable", "2 pods OOMKilled"}, reasons)
+	})
+}
+
+func TestWorkloadsStatus(t *testing.T) {
+	suite.Run(t, new(WorkloadsStatusSuite))
+}
@@ -0,0 +1,94 @@
+package kustomize
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+type BuildOptions struct {
+	// Kustomization is the inline content of the kustomization.yaml file
+	Kustomization string
+	// Files are additional files (resources, patches, configMapGenerator sources...) referenced by the
+	// inline kustomization, keyed by their path relative to the kustomization root
+	Files map[string]string
+	// URL is a remote kustomization (e.g. https://github.com/org/repo//path?ref=v1.0.0) to build
+	// Ignored if Kustomization is provided
+	URL string
+}
+
+// Build renders the provided kustomization and returns the resulting multi-document YAML manifest.
+func Build(options BuildOptions) (string, error) {
+	kustomization := options.Kustomization
+	if kustomization == "" {
+		if options.URL == "" {
+			return "", errors.New("either an inline kustomization or a URL must be provided")
+		}
+		generated, err := yaml.Marshal(map[string]any{
+			"apiVersion": types.KustomizationVersion,
+			"kind":       types.KustomizationKind,
+			"resources":  []string{options.URL},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to generate kustomization for %s: %w", options.URL, err)
+		}
+		kustomization = string(generated)
+	}
+
+	// Remote resources are cloned to disk by kustomize, use a temporary on-disk root for consistency
+	root, err := os.MkdirTemp("", "kustomize-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create kustomization root: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(root) }()
+
+	files := map[string]string{konfig.DefaultKustomizationFileName(): kustomization}
+	for name, content := range options.Files {
+		if _, ok := files[name]; ok {
+			return "", fmt.Errorf("file %s conflicts with the inline kustomization", name)
+		}
+		files[name] = content
+	}
+	for name, content := range files {
+		path, err := fileInRoot(root, name)
+		if err != nil {
+			return "", err
+		}
+		if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return "", fmt.Errorf("failed to create directory for %s: %w", name, err)
+		}
+		if err = os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), root)
+	if err != nil {
+		return "", fmt.Errorf("failed to build kustomization: %w", err)
+	}
+	rendered, err := resMap.AsYaml()
+	if err != nil {
+		return "", fmt.Errorf("failed to render kustomization: %w", err)
+	}
+	return string(rendered), nil
+}
+
+// fileInRoot returns the absolute path for name ensuring it doesn't escape the kustomization root
+func fileInRoot(root, name string) (string, error) {
+	if name == "" || filepath.IsAbs(name) {
+		return "", fmt.Errorf("invalid file name %q, must be a path relative to the kustomization root", name)
+	}
+	path := filepath.Join(root, filepath.Clean(name))
+	if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file name %q, must be a path relative to the kustomization root", name)
+	}
+	return path, nil
+}
@@ -0,0 +1,81 @@
+package kustomize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KustomizeSuite struct {
+	suite.Suite
+}
+
+func (s *KustomizeSuite) TestBuildInline() {
+	s.Run("renders resources with transformers", func() {
+		rendered, err := Build(BuildOptions{
+			Kustomization: "namePrefix: dev-\nnamespace: dev\nresources:\n- configmap.yaml\n",
+			Files: map[string]string{
+				"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  key: value\n",
+			},
+		})
+		s.Require().NoError(err)
+		s.Contains(rendered, "name: dev-app")
+		s.Contains(rendered, "namespace: dev")
+	})
+	s.Run("renders resources in nested directories", func() {
+		rendered, err := Build(BuildOptions{
+			Kustomization: "resources:\n- base\n",
+			Files: map[string]string{
+				"base/kustomization.yaml": "resources:\n- configmap.yaml\n",
+				"base/configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: base\n",
+			},
+		})
+		s.Require().NoError(err)
+		s.Contains(rendered, "name: base")
+	})
+	s.Run("renders generators", func() {
+		rendered, err := Build(BuildOptions{
+			Kustomization: "generatorOptions:\n  disableNameSuffixHash: true\nconfigMapGenerator:\n- name: generated\n  files:\n  - app.properties\n",
+			Files:         map[string]string{"app.properties": "foo=bar\n"},
+		})
+		s.Require().NoError(err)
+		s.Contains(rendered, "name: generated")
+		s.Contains(rendered, "foo=bar")
+	})
+}
+
+func (s *KustomizeSuite) TestBuildErrors() {
+	s.Run("returns error with no kustomization or url", func() {
+		_, err := Build(BuildOptions{})
+		s.EqualError(err, "either an inline kustomization or a URL must be provided")
+	})
+	s.Run("returns error for files escaping the root", func() {
+		_, err := Build(BuildOptions{
+			Kustomization: "resources:\n- ../configmap.yaml\n",
+			Files:         map[string]string{"../configmap.yaml": "apiVersion: v1\nkind: ConfigMap\n"},
+		})
+		s.ErrorContains(err, "must be a path relative to the kustomization root")
+	})
+	s.Run("returns error for absolute files", func() {
+		_, err := Build(BuildOptions{
+			Kustomization: "resources: []\n",
+			Files:         map[string]string{"/etc/passwd": ""},
+		})
+		s.ErrorContains(err, "must be a path relative to the kustomization root")
+	})
+	s.Run("returns error for files overriding the kustomization", func() {
+		_, err := Build(BuildOptions{
+			Kustomization: "resources: []\n",
+			Files:         map[string]string{"kustomization.yaml": ""},
+		})
+		s.EqualError(err, "file kustomization.yaml conflicts with the inline kustomization")
+	})
+	s.Run("returns error for missing resources", func() {
+		_, err := Build(BuildOptions{Kustomization: "resources:\n- missing.yaml\n"})
+		s.ErrorContains(err, "failed to build kustomization")
+	})
+}
+
+func TestKustomize(t *testing.T) {
+	suite.Run(t, new(KustomizeSuite))
+}
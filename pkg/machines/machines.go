@@ -0,0 +1,254 @@
+// Package machines reads the Machines and MachineSets of the machine management APIs of a cluster, Cluster API
+// (cluster.x-k8s.io/v1beta1) and the OpenShift Machine API (machine.openshift.io/v1beta1), and correlates the Nodes
+// with the Machines that back them.
+//
+// The CRDs are read with the dynamic client, the APIs whose CRDs are missing are reported as not installed.
+package machines
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupClusterAPI is the API group of the Cluster API Machines and MachineSets
+	GroupClusterAPI = "cluster.x-k8s.io"
+	// GroupOpenShift is the API group of the OpenShift Machine API Machines and MachineSets
+	GroupOpenShift = "machine.openshift.io"
+
+	// OpenShiftNamespace is the namespace of the Machines and MachineSets of the OpenShift Machine API
+	OpenShiftNamespace = "openshift-machine-api"
+)
+
+// API describes the resources, labels, and annotations of a machine management API
+type API struct {
+	Group   string
+	Version string
+	// MachineSetLabel is the label of the Machines with the name of their MachineSet
+	MachineSetLabel string
+	// NodeAnnotation is the annotation of the Nodes with the name (Cluster API) or namespace/name (OpenShift) of their Machine
+	NodeAnnotation string
+	// NodeNamespaceAnnotation is the annotation of the Nodes with the namespace of their Machine (Cluster API only)
+	NodeNamespaceAnnotation string
+	// MinSizeAnnotation and MaxSizeAnnotation are the node group bounds of the cluster autoscaler
+	MinSizeAnnotation string
+	MaxSizeAnnotation string
+}
+
+var (
+	ClusterAPI = API{
+		Group:                   GroupClusterAPI,
+		Version:                 "v1beta1",
+		MachineSetLabel:         "cluster.x-k8s.io/set-name",
+		NodeAnnotation:          "cluster.x-k8s.io/machine",
+		NodeNamespaceAnnotation: "cluster.x-k8s.io/cluster-namespace",
+		MinSizeAnnotation:       "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size",
+		MaxSizeAnnotation:       "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size",
+	}
+	OpenShift = API{
+		Group:             GroupOpenShift,
+		Version:           "v1beta1",
+		MachineSetLabel:   "machine.openshift.io/cluster-api-machineset",
+		NodeAnnotation:    "machine.openshift.io/machine",
+		MinSizeAnnotation: "machine.openshift.io/cluster-api-autoscaler-node-group-min-size",
+		MaxSizeAnnotation: "machine.openshift.io/cluster-api-autoscaler-node-group-max-size",
+	}
+	// APIs are the supported machine management APIs
+	APIs = []API{ClusterAPI, OpenShift}
+	// Groups are the API groups of the supported machine management APIs
+	Groups = []string{GroupClusterAPI, GroupOpenShift}
+)
+
+// APIFor returns the machine management API of the group
+func APIFor(group string) (API, error) {
+	for _, api := range APIs {
+		if api.Group == group {
+			return api, nil
+		}
+	}
+	return API{}, fmt.Errorf("unsupported machine API group %s, supported groups are %v", group, Groups)
+}
+
+// MachineGVR returns the GroupVersionResource of the Machines of the API
+func (a API) MachineGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: a.Group, Version: a.Version, Resource: "machines"}
+}
+
+// MachineSetGVR returns the GroupVersionResource of the MachineSets of the API
+func (a API) MachineSetGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: a.Group, Version: a.Version, Resource: "machinesets"}
+}
+
+// GroupVersion returns the group version of the API, e.g. cluster.x-k8s.io/v1beta1
+func (a API) GroupVersion() string {
+	return a.Group + "/" + a.Version
+}
+
+// Machine is a Cluster API or OpenShift Machine
+type Machine struct {
+	APIGroup   string `json:"apiGroup"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	MachineSet string `json:"machineSet,omitempty"`
+	// Cluster is the name of the Cluster API cluster of the Machine
+	Cluster    string `json:"cluster,omitempty"`
+	Phase      string `json:"phase,omitempty"`
+	Node       string `json:"node,omitempty"`
+	ProviderID string `json:"providerID,omitempty"`
+	// Version is the Kubernetes version of the Cluster API Machine
+	Version      string `json:"version,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	Zone         string `json:"zone,omitempty"`
+	// Infrastructure is the infrastructure machine of the Cluster API Machine, e.g. AWSMachine/workers-abcde
+	Infrastructure string `json:"infrastructure,omitempty"`
+	// Message is the failure message of the Machine, or the messages of its false conditions
+	Message           string `json:"message,omitempty"`
+	CreationTimestamp string `json:"creationTimestamp,omitempty"`
+}
+
+// MachineSet is a Cluster API or OpenShift MachineSet
+type MachineSet struct {
+	APIGroup  string `json:"apiGroup"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Cluster   string `json:"cluster,omitempty"`
+	// MachineDeployment is the Cluster API MachineDeployment owning the MachineSet, which reverts its scaling
+	MachineDeployment string `json:"machineDeployment,omitempty"`
+	Replicas          int64  `json:"replicas"`
+	CurrentReplicas   int64  `json:"currentReplicas"`
+	ReadyReplicas     int64  `json:"readyReplicas"`
+	AvailableReplicas int64  `json:"availableReplicas"`
+	InstanceType      string `json:"instanceType,omitempty"`
+	Zone              string `json:"zone,omitempty"`
+	// Infrastructure is the infrastructure machine template of the Cluster API MachineSet, e.g. AWSMachineTemplate/workers
+	Infrastructure string `json:"infrastructure,omitempty"`
+	// MinSize and MaxSize are the bounds of the cluster autoscaler, if the MachineSet is a node group of the autoscaler
+	MinSize *int64 `json:"minSize,omitempty"`
+	MaxSize *int64 `json:"maxSize,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewMachine reads the Machine of the API
+func NewMachine(api API, obj *unstructured.Unstructured) *Machine {
+	machine := &Machine{
+		APIGroup:   api.Group,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		MachineSet: obj.GetLabels()[api.MachineSetLabel],
+	}
+	if created := obj.GetCreationTimestamp(); !created.IsZero() {
+		machine.CreationTimestamp = created.UTC().Format(time.RFC3339)
+	}
+	machine.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	machine.Node, _, _ = unstructured.NestedString(obj.Object, "status", "nodeRef", "name")
+	machine.ProviderID, _, _ = unstructured.NestedString(obj.Object, "spec", "providerID")
+	switch api.Group {
+	case GroupClusterAPI:
+		machine.Cluster, _, _ = unstructured.NestedString(obj.Object, "spec", "clusterName")
+		machine.Version, _, _ = unstructured.NestedString(obj.Object, "spec", "version")
+		machine.Zone, _, _ = unstructured.NestedString(obj.Object, "spec", "failureDomain")
+		machine.Infrastructure = reference(obj.Object, "spec", "infrastructureRef")
+		machine.Message, _, _ = unstructured.NestedString(obj.Object, "status", "failureMessage")
+	case GroupOpenShift:
+		machine.InstanceType = obj.GetLabels()["machine.openshift.io/instance-type"]
+		machine.Zone = obj.GetLabels()["machine.openshift.io/zone"]
+		machine.Message, _, _ = unstructured.NestedString(obj.Object, "status", "errorMessage")
+	}
+	if machine.Message == "" {
+		machine.Message = falseConditions(obj)
+	}
+	return machine
+}
+
+// NewMachineSet reads the MachineSet of the API
+func NewMachineSet(api API, obj *unstructured.Unstructured) *MachineSet {
+	machineSet := &MachineSet{APIGroup: api.Group, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	machineSet.Replicas, _, _ = unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	machineSet.CurrentReplicas, _, _ = unstructured.NestedInt64(obj.Object, "status", "replicas")
+	machineSet.ReadyReplicas, _, _ = unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	machineSet.AvailableReplicas, _, _ = unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+	machineSet.MinSize = sizeAnnotation(obj, api.MinSizeAnnotation)
+	machineSet.MaxSize = sizeAnnotation(obj, api.MaxSizeAnnotation)
+	switch api.Group {
+	case GroupClusterAPI:
+		machineSet.Cluster, _, _ = unstructured.NestedString(obj.Object, "spec", "clusterName")
+		machineSet.Zone, _, _ = unstructured.NestedString(obj.Object, "spec", "template", "spec", "failureDomain")
+		machineSet.Infrastructure = reference(obj.Object, "spec", "template", "spec", "infrastructureRef")
+		machineSet.Message, _, _ = unstructured.NestedString(obj.Object, "status", "failureMessage")
+		for _, owner := range obj.GetOwnerReferences() {
+			if owner.Kind == "MachineDeployment" {
+				machineSet.MachineDeployment = owner.Name
+			}
+		}
+	case GroupOpenShift:
+		labels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+		machineSet.InstanceType = labels["machine.openshift.io/instance-type"]
+		machineSet.Zone = labels["machine.openshift.io/zone"]
+		machineSet.Message, _, _ = unstructured.NestedString(obj.Object, "status", "errorMessage")
+	}
+	if machineSet.Message == "" {
+		machineSet.Message = falseConditions(obj)
+	}
+	return machineSet
+}
+
+// MachineRef returns the API, namespace, and name of the Machine of the Node from its annotations, ok is false if the
+// Node isn't annotated by any of the machine management APIs
+func MachineRef(node *v1.Node) (api API, namespace, name string, ok bool) {
+	if value := node.Annotations[OpenShift.NodeAnnotation]; value != "" {
+		namespace, name, found := strings.Cut(value, "/")
+		if !found {
+			return OpenShift, OpenShiftNamespace, value, true
+		}
+		return OpenShift, namespace, name, true
+	}
+	name, namespace = node.Annotations[ClusterAPI.NodeAnnotation], node.Annotations[ClusterAPI.NodeNamespaceAnnotation]
+	if name != "" && namespace != "" {
+		return ClusterAPI, namespace, name, true
+	}
+	return API{}, "", "", false
+}
+
+// reference renders the object reference at the path, e.g. AWSMachine/workers-abcde
+func reference(object map[string]any, fields ...string) string {
+	kind, _, _ := unstructured.NestedString(object, append(fields, "kind")...)
+	name, _, _ := unstructured.NestedString(object, append(fields, "name")...)
+	if kind == "" || name == "" {
+		return ""
+	}
+	return kind + "/" + name
+}
+
+// sizeAnnotation parses the autoscaler size annotation, nil if missing or invalid
+func sizeAnnotation(obj *unstructured.Unstructured, annotation string) *int64 {
+	size, err := strconv.ParseInt(obj.GetAnnotations()[annotation], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &size
+}
+
+// falseConditions joins the messages of the false conditions, e.g. InfrastructureReady: 1 of 2 completed
+func falseConditions(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var messages []string
+	for _, c := range conditions {
+		condition, _ := c.(map[string]any)
+		if status, _, _ := unstructured.NestedString(condition, "status"); status != string(v1.ConditionFalse) {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		if message == "" {
+			message, _, _ = unstructured.NestedString(condition, "reason")
+		}
+		messages = append(messages, strings.TrimSuffix(conditionType+": "+message, ": "))
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,120 @@
+package machines
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewMachine(t *testing.T) {
+	t.Run("reads a Cluster API Machine", func(t *testing.T) {
+		machine := NewMachine(ClusterAPI, &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"namespace": "default", "name": "workers-abcde", "labels": map[string]any{"cluster.x-k8s.io/set-name": "workers"}},
+			"spec": map[string]any{
+				"clusterName":       "prod",
+				"version":           "v1.31.0",
+				"failureDomain":     "us-east-1a",
+				"providerID":        "aws:///us-east-1a/i-0123",
+				"infrastructureRef": map[string]any{"kind": "AWSMachine", "name": "workers-abcde"},
+			},
+			"status": map[string]any{
+				"phase":   "Provisioning",
+				"nodeRef": map[string]any{"name": "ip-10-0-0-1"},
+				"conditions": []any{
+					map[string]any{"type": "Ready", "status": "True"},
+					map[string]any{"type": "InfrastructureReady", "status": "False", "reason": "WaitingForInstance"},
+				},
+			},
+		}})
+		expected := &Machine{
+			APIGroup: GroupClusterAPI, Namespace: "default", Name: "workers-abcde", MachineSet: "workers", Cluster: "prod",
+			Phase: "Provisioning", Node: "ip-10-0-0-1", ProviderID: "aws:///us-east-1a/i-0123", Version: "v1.31.0", Zone: "us-east-1a",
+			Infrastructure: "AWSMachine/workers-abcde", Message: "InfrastructureReady: WaitingForInstance",
+		}
+		if !reflect.DeepEqual(machine, expected) {
+			t.Errorf("expected %+v, got %+v", expected, machine)
+		}
+	})
+	t.Run("reads an OpenShift Machine", func(t *testing.T) {
+		machine := NewMachine(OpenShift, &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"namespace": "openshift-machine-api", "name": "worker-a-xyz", "labels": map[string]any{
+				"machine.openshift.io/cluster-api-machineset": "worker-a",
+				"machine.openshift.io/instance-type":          "m6i.xlarge",
+				"machine.openshift.io/zone":                   "us-east-1a",
+			}},
+			"status": map[string]any{"phase": "Failed", "errorMessage": "InsufficientInstanceCapacity"},
+		}})
+		expected := &Machine{
+			APIGroup: GroupOpenShift, Namespace: "openshift-machine-api", Name: "worker-a-xyz", MachineSet: "worker-a",
+			Phase: "Failed", InstanceType: "m6i.xlarge", Zone: "us-east-1a", Message: "InsufficientInstanceCapacity",
+		}
+		if !reflect.DeepEqual(machine, expected) {
+			t.Errorf("expected %+v, got %+v", expected, machine)
+		}
+	})
+}
+
+func TestNewMachineSet(t *testing.T) {
+	t.Run("reads a Cluster API MachineSet owned by a MachineDeployment", func(t *testing.T) {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"namespace": "default", "name": "workers-5d8f"},
+			"spec": map[string]any{"clusterName": "prod", "replicas": int64(3), "template": map[string]any{"spec": map[string]any{
+				"infrastructureRef": map[string]any{"kind": "AWSMachineTemplate", "name": "workers"},
+			}}},
+			"status": map[string]any{"replicas": int64(3), "readyReplicas": int64(2), "availableReplicas": int64(2)},
+		}}
+		obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: "MachineDeployment", Name: "workers"}})
+		expected := &MachineSet{
+			APIGroup: GroupClusterAPI, Namespace: "default", Name: "workers-5d8f", Cluster: "prod", MachineDeployment: "workers",
+			Replicas: 3, CurrentReplicas: 3, ReadyReplicas: 2, AvailableReplicas: 2, Infrastructure: "AWSMachineTemplate/workers",
+		}
+		if machineSet := NewMachineSet(ClusterAPI, obj); !reflect.DeepEqual(machineSet, expected) {
+			t.Errorf("expected %+v, got %+v", expected, machineSet)
+		}
+	})
+	t.Run("reads the bounds of the cluster autoscaler of an OpenShift MachineSet", func(t *testing.T) {
+		machineSet := NewMachineSet(OpenShift, &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"namespace": "openshift-machine-api", "name": "worker-a", "annotations": map[string]any{
+				"machine.openshift.io/cluster-api-autoscaler-node-group-min-size": "1",
+				"machine.openshift.io/cluster-api-autoscaler-node-group-max-size": "6",
+			}},
+			"spec": map[string]any{"replicas": int64(2), "template": map[string]any{"metadata": map[string]any{"labels": map[string]any{
+				"machine.openshift.io/instance-type": "m6i.xlarge",
+			}}}},
+		}})
+		if !reflect.DeepEqual(machineSet.MinSize, ptr.To(int64(1))) || !reflect.DeepEqual(machineSet.MaxSize, ptr.To(int64(6))) {
+			t.Errorf("expected autoscaler bounds 1-6, got %v-%v", machineSet.MinSize, machineSet.MaxSize)
+		}
+		if machineSet.InstanceType != "m6i.xlarge" {
+			t.Errorf("expected instance type m6i.xlarge, got %s", machineSet.InstanceType)
+		}
+	})
+}
+
+func TestMachineRef(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		group       string
+		namespace   string
+		machine     string
+		ok          bool
+	}{
+		{"OpenShift", map[string]string{"machine.openshift.io/machine": "openshift-machine-api/worker-a-xyz"}, GroupOpenShift, "openshift-machine-api", "worker-a-xyz", true},
+		{"Cluster API", map[string]string{"cluster.x-k8s.io/machine": "workers-abcde", "cluster.x-k8s.io/cluster-namespace": "default"}, GroupClusterAPI, "default", "workers-abcde", true},
+		{"Cluster API without namespace", map[string]string{"cluster.x-k8s.io/machine": "workers-abcde"}, "", "", "", false},
+		{"not annotated", nil, "", "", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			api, namespace, name, ok := MachineRef(&v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: c.annotations}})
+			if api.Group != c.group || namespace != c.namespace || name != c.machine || ok != c.ok {
+				t.Errorf("expected %s %s/%s %v, got %s %s/%s %v", c.group, c.namespace, c.machine, c.ok, api.Group, namespace, name, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type APIDeprecationsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *APIDeprecationsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{Groups: []string{
+		`{"name":"batch","versions":[{"groupVersion":"batch/v1","version":"v1"}],"preferredVersion":{"groupVersion":"batch/v1","version":"v1"}}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/version":
+			_, _ = w.Write([]byte(`{"major":"1","minor":"24","gitVersion":"v1.24.3"}`))
+		case "/apis/batch/v1":
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"batch/v1","resources":[
+				{"name":"cronjobs","singularName":"","namespaced":true,"kind":"CronJob","verbs":["get","list","watch","create","update","patch","delete"]}
+			]}`))
+		case "/apis/batch/v1/cronjobs", "/apis/batch/v1/namespaces/shop/cronjobs":
+			_, _ = w.Write([]byte(`{"apiVersion":"batch/v1","kind":"CronJobList","items":[
+				{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"backup","namespace":"shop","managedFields":[
+					{"manager":"helm","operation":"Update","apiVersion":"batch/v1beta1"}
+				]}}
+			]}`))
+		case "/apis/apps/v1/deployments":
+			_, _ = w.Write([]byte(`{"apiVersion":"apps/v1","kind":"DeploymentList","items":[
+				{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"default","annotations":{
+					"kubectl.kubernetes.io/last-applied-configuration":"{\"apiVersion\":\"apps/v1beta2\",\"kind\":\"Deployment\"}"
+				}}},
+				{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api","namespace":"default","managedFields":[
+					{"manager":"kubectl","operation":"Apply","apiVersion":"apps/v1"}
+				]}}
+			]}`))
+		case "/apis/apps/v1/daemonsets", "/apis/apps/v1/replicasets", "/apis/apps/v1/statefulsets",
+			"/apis/apps/v1/namespaces/shop/deployments", "/apis/apps/v1/namespaces/shop/daemonsets",
+			"/apis/apps/v1/namespaces/shop/replicasets", "/apis/apps/v1/namespaces/shop/statefulsets":
+			_, _ = w.Write([]byte(`{"apiVersion":"apps/v1","kind":"List","items":[]}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *APIDeprecationsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *APIDeprecationsSuite) TestAPIDeprecations() {
+	s.InitMcpClient()
+	s.Run("api_deprecations()", func() {
+		toolResult, err := s.CallTool("api_deprecations", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns summary", func() {
+			s.Truef(strings.HasPrefix(text, "# 2 objects use deprecated API versions in all namespaces (cluster v1.24.3, target 1.25), "+
+				"1 use API versions removed in the cluster version, 1 use API versions removed in 1.25\n"), "unexpected result %v", text)
+		})
+		var report kubernetes.APIDeprecationsReport
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &report))
+		s.Run("is not upgrade ready", func() {
+			s.False(report.UpgradeReady)
+			s.Empty(report.Warnings)
+		})
+		s.Run("returns the findings", func() {
+			s.Require().Len(report.Findings, 2)
+			s.Equal(kubernetes.APIDeprecationFinding{
+				Kind: "Deployment", Namespace: "default", Name: "web", APIVersion: "apps/v1beta2", Sources: []string{"last-applied"},
+				DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Status: kubernetes.APIRemoved,
+			}, report.Findings[0])
+			s.Equal(kubernetes.APIDeprecationFinding{
+				Kind: "CronJob", Namespace: "shop", Name: "backup", APIVersion: "batch/v1beta1", Sources: []string{"managedFields (helm)"},
+				DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "batch/v1", Status: kubernetes.APIRemovedInTarget,
+			}, report.Findings[1])
+		})
+	})
+	s.Run("api_deprecations(namespace=shop, target_version=1.24)", func() {
+		toolResult, err := s.CallTool("api_deprecations", map[string]interface{}{"namespace": "shop", "target_version": "1.24"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# 1 objects use deprecated API versions in namespace shop (cluster v1.24.3, target 1.24)\n"), "unexpected result %v", text)
+		s.Contains(text, "upgradeReady: true")
+	})
+	s.Run("api_deprecations(target_version=1.20)", func() {
+		toolResult, err := s.CallTool("api_deprecations", map[string]interface{}{"target_version": "1.20"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to scan deprecated APIs: target version 1.20 is older than the cluster version 1.24", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestAPIDeprecations(t *testing.T) {
+	suite.Run(t, new(APIDeprecationsSuite))
+}
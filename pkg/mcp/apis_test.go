@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type ApisSuite struct {
+	BaseMcpSuite
+}
+
+func (s *ApisSuite) TestExplain() {
+	s.InitMcpClient()
+	s.Run("explain with missing kind returns error", func() {
+		toolResult, _ := s.CallTool("explain", map[string]interface{}{"apiVersion": "v1"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to explain resource, missing argument kind", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("explain(apiVersion=v1, kind=Pod)", func() {
+		toolResult, err := s.CallTool("explain", map[string]interface{}{"apiVersion": "v1", "kind": "Pod"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "KIND:       Pod\nVERSION:    v1\n")
+		s.Contains(text, "FIELDS:\n")
+		s.Regexp(`\n\s+spec\s+<PodSpec>\n`, text)
+	})
+	s.Run("explain(apiVersion=apps/v1, kind=Deployment, field=spec.template.spec.containers.image)", func() {
+		toolResult, err := s.CallTool("explain", map[string]interface{}{
+			"apiVersion": "apps/v1", "kind": "Deployment", "field": "spec.template.spec.containers.image",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "GROUP:      apps\nKIND:       Deployment\n")
+		s.Contains(text, "FIELD: image <string>")
+	})
+	s.Run("explain(apiVersion=v1, kind=Pod, field=spec.securityContext, recursive=true)", func() {
+		toolResult, err := s.CallTool("explain", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Pod", "field": "spec.securityContext", "recursive": true,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Regexp(`seLinuxOptions\s+<SELinuxOptions>\n\s+level\s+<string>`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("explain(apiVersion=v1, kind=Pod, field=spec.missing)", func() {
+		toolResult, _ := s.CallTool("explain", map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "field": "spec.missing"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, `field "missing" does not exist`)
+	})
+	s.Run("explain(apiVersion=custom.non.existent.example.com/v1, kind=Custom)", func() {
+		toolResult, _ := s.CallTool("explain", map[string]interface{}{"apiVersion": "custom.non.existent.example.com/v1", "kind": "Custom"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, `failed to explain Custom: no matches for kind "Custom"`)
+	})
+}
+
+func (s *ApisSuite) TestExplainDenied() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		denied_resources = [ { version = "v1", kind = "Secret" } ]
+	`), s.Cfg), "Expected to parse denied resources config")
+	s.InitMcpClient()
+	toolResult, _ := s.CallTool("explain", map[string]interface{}{"apiVersion": "v1", "kind": "Secret"})
+	s.Truef(toolResult.IsError, "call tool should fail")
+	s.Equal("failed to explain Secret: resource not allowed: /v1, Kind=Secret", toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func (s *ApisSuite) TestCrdsList() {
+	s.InitMcpClient()
+	s.Run("crds_list(group=kubevirt.io)", func() {
+		toolResult, err := s.CallTool("crds_list", map[string]interface{}{"group": "kubevirt.io"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# 6 CustomResourceDefinitions found"), "unexpected result %v", text)
+		var crds []kubernetes.CustomResourceDefinition
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &crds))
+		s.Require().Len(crds, 6)
+		s.Run("includes the subgroups", func() {
+			s.Equal("datasources.cdi.kubevirt.io", crds[0].Name)
+		})
+		s.Run("returns the versions and scope", func() {
+			vm := crds[len(crds)-1]
+			s.Equal("virtualmachines.kubevirt.io", vm.Name)
+			s.Equal("VirtualMachine", vm.Kind)
+			s.Equal("Namespaced", vm.Scope)
+			s.Require().Len(vm.Versions, 1)
+			s.Equal("v1", vm.Versions[0].Name)
+			s.True(vm.Versions[0].Storage)
+		})
+	})
+	s.Run("crds_list(group=non.existent.example.com)", func() {
+		toolResult, err := s.CallTool("crds_list", map[string]interface{}{"group": "non.existent.example.com"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# 0 CustomResourceDefinitions found, 0 not established\n[]\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestApis(t *testing.T) {
+	suite.Run(t, new(ApisSuite))
+}
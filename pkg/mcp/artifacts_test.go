@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+type ArtifactsSuite struct {
+	BaseMcpSuite
+}
+
+func (s *ArtifactsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	for name, content := range map[string]string{
+		"pod-log-ns-1-pod-1-20250101-100000-0a1b2c3d.log": "line 1\nline 2\nline 3\n",
+		"diagnostics-20250101-090000.tar.gz":              "bundle",
+		"empty.txt":                                       "",
+	} {
+		p := filepath.Join(s.Cfg.Artifacts.Dir, name)
+		s.Require().NoError(os.WriteFile(p, []byte(content), 0600))
+	}
+	older := time.Now().Add(-time.Hour)
+	s.Require().NoError(os.Chtimes(filepath.Join(s.Cfg.Artifacts.Dir, "diagnostics-20250101-090000.tar.gz"), older, older))
+}
+
+func (s *ArtifactsSuite) TestArtifactsList() {
+	s.InitMcpClient()
+	s.Run("artifacts_list()", func() {
+		toolResult, err := s.CallTool("artifacts_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the number of artifacts", func() {
+			s.Regexp(`^# 3 artifacts, read them with artifacts_get or as artifact://<name> MCP resources\n`, text)
+		})
+		s.Run("returns the artifacts the most recent first", func() {
+			s.Regexp(`(?s)NAME\s+TYPE\s+SIZE\s+CREATED\n`+
+				`.*pod-log-ns-1-pod-1-20250101-100000-0a1b2c3d\.log\s+text/plain\s+21\s+`+
+				`.*diagnostics-20250101-090000\.tar\.gz\s+application/gzip\s+6\s+`, text)
+		})
+	})
+	s.Run("artifacts_list(output_format=json)", func() {
+		toolResult, err := s.CallTool("artifacts_list", map[string]interface{}{"output_format": "json"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("returns the artifacts envelope", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, `"kind":"Artifact"`)
+			s.Contains(text, `"uri":"artifact://diagnostics-20250101-090000.tar.gz"`)
+			s.Contains(text, `"summary":"3 artifacts"`)
+		})
+	})
+	s.Run("artifacts_list() with no artifacts", func() {
+		s.Require().NoError(os.RemoveAll(s.Cfg.Artifacts.Dir))
+		toolResult, err := s.CallTool("artifacts_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("No artifacts found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *ArtifactsSuite) TestArtifactsGet() {
+	s.InitMcpClient()
+	s.Run("artifacts_get(name=pod-log-ns-1-pod-1-20250101-100000-0a1b2c3d.log)", func() {
+		toolResult, err := s.CallTool("artifacts_get", map[string]interface{}{"name": "pod-log-ns-1-pod-1-20250101-100000-0a1b2c3d.log"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("line 1\nline 2\nline 3\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("artifacts_get(name=artifact://pod-log-ns-1-pod-1-20250101-100000-0a1b2c3d.log, tail=2)", func() {
+		toolResult, err := s.CallTool("artifacts_get", map[string]interface{}{
+			"name": "artifact://pod-log-ns-1-pod-1-20250101-100000-0a1b2c3d.log",
+			"tail": 2,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("line 2\nline 3\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("artifacts_get(name=diagnostics-20250101-090000.tar.gz) describes binary artifacts", func() {
+		toolResult, err := s.CallTool("artifacts_get", map[string]interface{}{"name": "diagnostics-20250101-090000.tar.gz"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("Artifact diagnostics-20250101-090000.tar.gz is a binary file (application/gzip, 6 bytes) that can't be returned as text, it's stored in "+
+			filepath.Join(s.Cfg.Artifacts.Dir, "diagnostics-20250101-090000.tar.gz"), toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("artifacts_get(name=empty.txt)", func() {
+		toolResult, err := s.CallTool("artifacts_get", map[string]interface{}{"name": "empty.txt"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("Artifact empty.txt is empty", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("artifacts_get(name=missing.log)", func() {
+		toolResult, _ := s.CallTool("artifacts_get", map[string]interface{}{"name": "missing.log"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get artifact missing.log: artifact not found: missing.log, list the available ones with artifacts_list",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("artifacts_get(name=artifact://../etc/passwd)", func() {
+		toolResult, _ := s.CallTool("artifacts_get", map[string]interface{}{"name": "artifact://../etc/passwd"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get artifact, invalid artifact URI artifact://../etc/passwd", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestArtifacts(t *testing.T) {
+	suite.Run(t, new(ArtifactsSuite))
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type AutoscalerSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *AutoscalerSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: []string{
+		`{"name":"configmaps","singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["get","list","watch"]}`,
+		`{"name":"events","singularName":"event","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/kube-system/configmaps/cluster-autoscaler-status":
+			test.WriteObject(w, &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "cluster-autoscaler-status"},
+				Data: map[string]string{"status": "autoscalerStatus: Running\nclusterWide:\n  health:\n    status: Healthy\n  scaleUp:\n    status: NoActivity\n  scaleDown:\n    status: NoCandidates\n" +
+					"nodeGroups:\n- name: workers\n  health:\n    status: Healthy\n    maxSize: 3\n"},
+			})
+		case "/api/v1/events", "/api/v1/namespaces/default/events":
+			test.WriteObject(w, &v1.EventList{})
+		case "/api/v1/namespaces/default/pods/web-1":
+			test.WriteObject(w, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-1"},
+				Status:     v1.PodStatus{Phase: v1.PodPending},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"machines"}
+}
+
+func (s *AutoscalerSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *AutoscalerSuite) TestAutoscalerStatus() {
+	s.InitMcpClient()
+	s.Run("autoscaler_status() returns the status of the cluster-autoscaler", func() {
+		toolResult, err := s.CallTool("autoscaler_status", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# cluster-autoscaler Running (Healthy) in namespace kube-system, scale-up NoActivity, scale-down NoCandidates, 1 node groups; 0 recent autoscaler events\n"), text)
+		s.Contains(text, "name: workers")
+	})
+	s.Run("autoscaler_status(pod=web-1) explains the missing autoscaler decision", func() {
+		toolResult, err := s.CallTool("autoscaler_status", map[string]interface{}{"pod": "web-1", "namespace": "default"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "; Pod default/web-1: no autoscaler decision was recorded for the Pod\n")
+	})
+}
+
+func TestAutoscaler(t *testing.T) {
+	suite.Run(t, new(AutoscalerSuite))
+}
@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// BreakGlass keeps the state of the time-limited elevations (break_glass_max_duration).
+// While the server is not elevated the tools annotated with destructiveHint=true are not registered.
+// Elevations apply to the whole server since the registered tools are shared by every MCP session.
+type BreakGlass struct {
+	maxDuration time.Duration
+	token       string
+	now         func() time.Time
+	mu          sync.Mutex
+	until       time.Time
+	timer       *time.Timer
+}
+
+func NewBreakGlass(maxDuration time.Duration, token string) *BreakGlass {
+	return &BreakGlass{maxDuration: maxDuration, token: token, now: time.Now}
+}
+
+// Enabled returns true if break-glass is configured (the destructive tools require an elevation)
+func (b *BreakGlass) Enabled() bool {
+	return b.maxDuration > 0
+}
+
+// Elevated returns true if an elevation is active
+func (b *BreakGlass) Elevated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.now().Before(b.until)
+}
+
+// allows returns true if the tool can be registered or called with the current elevation state
+func (b *BreakGlass) allows(tool api.ServerTool) bool {
+	return !b.requiresElevation(tool) || b.Elevated()
+}
+
+// requiresElevation returns true if the tool is only available while the server is elevated
+func (b *BreakGlass) requiresElevation(tool api.ServerTool) bool {
+	return b.Enabled() && ptr.Deref(tool.Tool.Annotations.DestructiveHint, false) &&
+		tool.Tool.Name != api.ConfirmActionToolName
+}
+
+// Elevate starts (or replaces) the elevation window, revert is called once it expires.
+// The caller must provide the break-glass token, or be authenticated (require_oauth) when no token is configured.
+func (b *BreakGlass) Elevate(token string, authenticated bool, duration time.Duration, revert func()) (time.Time, error) {
+	if b.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(b.token)) != 1 {
+		return time.Time{}, errors.New("invalid break-glass token")
+	}
+	if b.token == "" && !authenticated {
+		return time.Time{}, errors.New("break-glass requires an authenticated caller when no break_glass_token is configured")
+	}
+	if duration <= 0 || duration > b.maxDuration {
+		return time.Time{}, fmt.Errorf("duration must be positive and can't exceed %s", b.maxDuration)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.until = b.now().Add(duration)
+	b.timer = time.AfterFunc(duration, revert)
+	return b.until, nil
+}
+
+// elevateArgs are the arguments of the elevate tool
+type elevateArgs struct {
+	Reason   string `json:"reason"`
+	Duration string `json:"duration"`
+	Token    string `json:"token"`
+}
+
+// breakGlassTools returns the tool to enable the destructive tools for a bounded time window
+func (s *Server) breakGlassTools() []api.ServerTool {
+	properties := map[string]*jsonschema.Schema{
+		"reason": {
+			Type:        "string",
+			Description: "Why the destructive tools are needed (e.g. incident or ticket reference), recorded in the audit log",
+		},
+		"duration": {
+			Type: "string",
+			Description: fmt.Sprintf("How long the destructive tools stay enabled, e.g. 10m (Optional, defaults to and can't exceed %s)",
+				s.breakGlass.maxDuration),
+		},
+	}
+	required := []string{"reason"}
+	if s.breakGlass.token != "" {
+		properties["token"] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "Break-glass token provided by the user, never guess or reuse it without the user's approval",
+		}
+		required = append(required, "token")
+	}
+	return []api.ServerTool{{
+		Tool: api.Tool{
+			Name: api.ElevateToolName,
+			Description: "Temporarily enable the destructive tools, which are disabled by default on this server. " +
+				"Only call this tool when the user explicitly asks for elevated access and provides the reason. " +
+				"The elevation applies to every session of the server and is reverted automatically once the duration expires",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties,
+				Required:   required,
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Break-glass: Elevate",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(false),
+			},
+		},
+		ClusterAware: ptr.To(false),
+		Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+			args, err := api.ParseArguments[elevateArgs](params)
+			if err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to elevate, %w", err)), nil
+			}
+			duration := s.breakGlass.maxDuration
+			if args.Duration != "" {
+				if duration, err = time.ParseDuration(args.Duration); err != nil {
+					return api.NewToolCallResult("", fmt.Errorf("failed to elevate, invalid duration %s", args.Duration)), nil
+				}
+			}
+			authorization, _ := params.Context.Value(internalk8s.OAuthAuthorizationHeader).(string)
+			authenticated := s.configuration.RequireOAuth && strings.HasPrefix(authorization, "Bearer ")
+			until, err := s.breakGlass.Elevate(args.Token, authenticated, duration, s.revertElevation)
+			if err != nil {
+				klog.FromContext(params.Context).Info("break-glass: elevation denied", "reason", args.Reason, "error", err.Error())
+				return api.NewToolCallResult("", fmt.Errorf("failed to elevate: %w", err)), nil
+			}
+			klog.FromContext(params.Context).Info("break-glass: elevation granted", "until", until.UTC().Format(time.RFC3339), "reason", args.Reason)
+			s.reloadMu.Lock()
+			err = s.reloadToolsets()
+			s.reloadMu.Unlock()
+			if err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to elevate, unable to enable the destructive tools: %w", err)), nil
+			}
+			return api.NewToolCallResult(fmt.Sprintf(
+				"Destructive tools are enabled until %s, the server reverts to the restricted tool set afterwards: %s",
+				until.UTC().Format(time.RFC3339), strings.Join(s.elevatedTools(), ", ")), nil), nil
+		},
+	}}
+}
+
+// revertElevation disables the destructive tools once the elevation window expires
+func (s *Server) revertElevation() {
+	if s.breakGlass.Elevated() {
+		// the elevation was renewed before this timer fired
+		return
+	}
+	klog.Info("break-glass: elevation expired, destructive tools disabled")
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	if err := s.reloadToolsets(); err != nil {
+		klog.Errorf("break-glass: failed to disable the destructive tools: %v", err)
+	}
+}
+
+// elevatedTools returns the names of the enabled tools that are only available while the server is elevated
+func (s *Server) elevatedTools() []string {
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	names := make([]string, 0)
+	for name, tool := range s.tools {
+		if s.breakGlass.requiresElevation(tool) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+type BreakGlassSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *BreakGlassSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		break_glass_max_duration = "30m"
+		break_glass_token = "s3cr3t"
+	`), s.Cfg), "Expected to parse break-glass config")
+}
+
+func (s *BreakGlassSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *BreakGlassSuite) toolNames() []string {
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	names := make([]string, 0, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func (s *BreakGlassSuite) TestRestrictedByDefault() {
+	s.InitMcpClient()
+	names := s.toolNames()
+	s.Run("hides destructive tools", func() {
+		s.NotContains(names, "pods_delete")
+		s.NotContains(names, "resources_delete")
+	})
+	s.Run("exposes non-destructive tools", func() {
+		s.Contains(names, "pods_list")
+		s.Contains(names, "pods_run")
+	})
+	s.Run("exposes elevate tool", func() {
+		s.Contains(names, "elevate")
+	})
+}
+
+func (s *BreakGlassSuite) TestElevate() {
+	s.InitMcpClient()
+	s.Run("elevate(token=invalid)", func() {
+		toolResult, err := s.CallTool("elevate", map[string]interface{}{"reason": "INC-1", "token": "wrong"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to elevate: invalid break-glass token", toolResult.Content[0].(mcp.TextContent).Text)
+		s.NotContains(s.toolNames(), "pods_delete")
+	})
+	s.Run("elevate(duration=1h)", func() {
+		toolResult, err := s.CallTool("elevate", map[string]interface{}{"reason": "INC-1", "token": "s3cr3t", "duration": "1h"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to elevate: duration must be positive and can't exceed 30m0s", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("elevate(reason=missing)", func() {
+		toolResult, err := s.CallTool("elevate", map[string]interface{}{"token": "s3cr3t"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to elevate, missing argument reason", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("elevate(token=valid)", func() {
+		toolResult, err := s.CallTool("elevate", map[string]interface{}{"reason": "INC-1", "token": "s3cr3t", "duration": "10m"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("describes the enabled tools", func() {
+			s.Regexp(`^Destructive tools are enabled until \S+, the server reverts to the restricted tool set afterwards: `, text)
+			s.Contains(text, "pods_delete")
+		})
+		s.Run("exposes destructive tools", func() {
+			names := s.toolNames()
+			s.Contains(names, "pods_delete")
+			s.Contains(names, "resources_delete")
+		})
+	})
+}
+
+func (s *BreakGlassSuite) TestElevationExpires() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("elevate", map[string]interface{}{"reason": "INC-1", "token": "s3cr3t", "duration": "1s"})
+	s.Require().NoError(err)
+	s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	s.Require().Contains(s.toolNames(), "pods_delete")
+	s.Run("reverts to the restricted tool set", func() {
+		s.Eventually(func() bool {
+			for _, name := range s.toolNames() {
+				if name == "pods_delete" {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+}
+
+func (s *BreakGlassSuite) TestUnauthenticatedElevationRefused() {
+	s.Cfg.BreakGlassToken = ""
+	_, err := NewServer(Configuration{StaticConfig: s.Cfg})
+	s.EqualError(err, "break_glass_token is required when break_glass_max_duration is set and require_oauth is disabled")
+}
+
+func (s *BreakGlassSuite) TestBreakGlassStore() {
+	destructive := api.ServerTool{Tool: api.Tool{Name: "pods_delete", Annotations: api.ToolAnnotations{DestructiveHint: ptr.To(true)}}}
+	readOnly := api.ServerTool{Tool: api.Tool{Name: "pods_list", Annotations: api.ToolAnnotations{ReadOnlyHint: ptr.To(true)}}}
+	s.Run("disabled allows every tool", func() {
+		breakGlass := NewBreakGlass(0, "")
+		s.True(breakGlass.allows(destructive))
+	})
+	s.Run("enabled only allows non-destructive tools", func() {
+		breakGlass := NewBreakGlass(time.Hour, "")
+		s.False(breakGlass.allows(destructive))
+		s.True(breakGlass.allows(readOnly))
+	})
+	s.Run("elevation without token and unauthenticated caller", func() {
+		breakGlass := NewBreakGlass(time.Hour, "")
+		_, err := breakGlass.Elevate("", false, time.Minute, func() {})
+		s.EqualError(err, "break-glass requires an authenticated caller when no break_glass_token is configured")
+		s.False(breakGlass.allows(destructive))
+	})
+	s.Run("elevation without token and authenticated caller", func() {
+		breakGlass := NewBreakGlass(time.Hour, "")
+		_, err := breakGlass.Elevate("", true, time.Minute, func() {})
+		s.Require().NoError(err)
+		s.True(breakGlass.allows(destructive))
+		s.Run("expires", func() {
+			breakGlass.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+			s.False(breakGlass.Elevated())
+			s.False(breakGlass.allows(destructive))
+		})
+	})
+}
+
+func TestBreakGlass(t *testing.T) {
+	suite.Run(t, new(BreakGlassSuite))
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// cacheParameterName is the name of the parameter added to the cached tools to bypass the cache
+const cacheParameterName = "cache"
+
+// cacheTTL returns the duration the results of the tool are cached for, only the read-only tools are cached
+func (c *Configuration) cacheTTL(tool api.ServerTool) time.Duration {
+	if !ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
+		return 0
+	}
+	return c.CacheTTL(tool.Tool.Name)
+}
+
+// isCacheBypassed returns true if the caller requested a fresh result through the cache parameter
+func isCacheBypassed(toolCallRequest api.ToolCallRequest) bool {
+	useCache, ok := toolCallRequest.GetArguments()[cacheParameterName].(bool)
+	return ok && !useCache
+}
+
+type cachedResult struct {
+	result    *api.ToolCallResult
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// ResultCache keeps the successful results of the tool calls until their TTL expires.
+// The results are cached by session, target cluster, tool and arguments so that a session never gets the results
+// retrieved with the credentials or the cluster of another one.
+type ResultCache struct {
+	now     func() time.Time
+	mu      sync.Mutex
+	results map[string]cachedResult
+}
+
+func NewResultCache() *ResultCache {
+	return &ResultCache{now: time.Now, results: make(map[string]cachedResult)}
+}
+
+// Get returns the cached result of the key and its age, nil if there's none or it expired
+func (c *ResultCache) Get(key string) (*api.ToolCallResult, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.results[key]
+	if !ok {
+		return nil, 0
+	}
+	now := c.now()
+	if !now.Before(cached.expiresAt) {
+		delete(c.results, key)
+		return nil, 0
+	}
+	return cached.result, now.Sub(cached.createdAt)
+}
+
+// Set caches the result for the TTL, the failed results and the ones revealing sensitive values are not cached
+func (c *ResultCache) Set(key string, result *api.ToolCallResult, ttl time.Duration) {
+	if result == nil || result.Error != nil || result.Revealed || ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	// drop the expired results so that the cache doesn't grow with the keys that are never requested again
+	for expired, cached := range c.results {
+		if !now.Before(cached.expiresAt) {
+			delete(c.results, expired)
+		}
+	}
+	// the result is copied since the server redacts and paginates the content of the returned one
+	copied := *result
+	c.results[key] = cachedResult{result: &copied, createdAt: now, expiresAt: now.Add(ttl)}
+}
+
+// resultCacheKey returns the cache key of the tool call, the cache parameter is not part of the key
+func resultCacheKey(session, cluster string, tool api.ServerTool, toolCallRequest api.ToolCallRequest) string {
+	arguments := maps.Clone(toolCallRequest.GetArguments())
+	delete(arguments, cacheParameterName)
+	// the keys of the maps are marshalled in sorted order
+	marshalled, _ := json.Marshal(arguments)
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s", session, cluster, tool.Tool.Name, marshalled)
+}
+
+// cachedToolCallResult returns a copy of the cached result prefixed with the cache metadata,
+// the structured outputs are returned as is so that they can still be parsed
+func cachedToolCallResult(result *api.ToolCallResult, age, ttl time.Duration, structured bool) *api.ToolCallResult {
+	if structured {
+		return api.NewToolCallResult(result.Content, nil)
+	}
+	return api.NewToolCallResult(
+		fmt.Sprintf("# Cached result (age %s, ttl %s), call again with %s=false to query the cluster\n",
+			age.Truncate(time.Second), ttl, cacheParameterName)+result.Content,
+		nil)
+}
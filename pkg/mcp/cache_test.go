@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+type ResultCacheSuite struct {
+	suite.Suite
+	now   time.Time
+	cache *ResultCache
+}
+
+func (s *ResultCacheSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.cache = NewResultCache()
+	s.cache.now = func() time.Time { return s.now }
+}
+
+func (s *ResultCacheSuite) TestGet() {
+	s.cache.Set("key", api.NewToolCallResult("cached", nil), time.Minute)
+	s.Run("returns the result and its age", func() {
+		s.now = s.now.Add(20 * time.Second)
+		result, age := s.cache.Get("key")
+		s.Require().NotNil(result)
+		s.Equal("cached", result.Content)
+		s.Equal(20*time.Second, age)
+	})
+	s.Run("returns nil for other keys", func() {
+		result, _ := s.cache.Get("other")
+		s.Nil(result)
+	})
+	s.Run("returns nil once expired", func() {
+		s.now = s.now.Add(time.Minute)
+		result, _ := s.cache.Get("key")
+		s.Nil(result)
+		s.Empty(s.cache.results)
+	})
+}
+
+func (s *ResultCacheSuite) TestSet() {
+	s.Run("copies the result", func() {
+		result := api.NewToolCallResult("cached", nil)
+		s.cache.Set("key", result, time.Minute)
+		result.Content = "modified"
+		cached, _ := s.cache.Get("key")
+		s.Equal("cached", cached.Content)
+	})
+	s.Run("ignores failed results", func() {
+		s.cache.Set("failed", api.NewToolCallResult("", errors.New("not found")), time.Minute)
+		result, _ := s.cache.Get("failed")
+		s.Nil(result)
+	})
+	s.Run("ignores revealed results", func() {
+		s.cache.Set("revealed", &api.ToolCallResult{Content: "password: secret", Revealed: true}, time.Minute)
+		result, _ := s.cache.Get("revealed")
+		s.Nil(result)
+	})
+	s.Run("drops the expired results", func() {
+		s.now = s.now.Add(2 * time.Minute)
+		s.cache.Set("other", api.NewToolCallResult("cached", nil), time.Minute)
+		s.Len(s.cache.results, 1)
+	})
+}
+
+func TestResultCache(t *testing.T) {
+	suite.Run(t, new(ResultCacheSuite))
+}
+
+type CacheSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	nodeGets   atomic.Int32
+}
+
+func (s *CacheSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.nodeGets.Store(0)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/nodes/node-1" {
+			s.nodeGets.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"Node","metadata":{"name":"node-1"}}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.CacheTTLs = map[string]string{"resources_get": "1m", "resources_delete": "1m"}
+}
+
+func (s *CacheSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *CacheSuite) getNode(arguments map[string]interface{}) *mcp.CallToolResult {
+	arguments["apiVersion"] = "v1"
+	arguments["kind"] = "Node"
+	arguments["name"] = "node-1"
+	toolResult, err := s.CallTool("resources_get", arguments)
+	s.Require().NoError(err)
+	s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	return toolResult
+}
+
+func (s *CacheSuite) TestCachedResult() {
+	s.InitMcpClient()
+	first := s.getNode(map[string]interface{}{})
+	s.Run("first call queries the cluster", func() {
+		s.Equal(int32(1), s.nodeGets.Load())
+		s.NotContains(first.Content[0].(mcp.TextContent).Text, "# Cached result")
+	})
+	s.Run("repeated call returns the cached result", func() {
+		toolResult := s.getNode(map[string]interface{}{})
+		s.Equal(int32(1), s.nodeGets.Load())
+		s.Regexp(`^# Cached result \(age \d+s, ttl 1m0s\), call again with cache=false to query the cluster\n`, toolResult.Content[0].(mcp.TextContent).Text)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, first.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("cache=false queries the cluster", func() {
+		toolResult := s.getNode(map[string]interface{}{"cache": false})
+		s.Equal(int32(2), s.nodeGets.Load())
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "# Cached result")
+	})
+	s.Run("other sessions don't share the cache", func() {
+		other := test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP())
+		defer other.Close()
+		toolResult, err := other.CallTool("resources_get", map[string]interface{}{"apiVersion": "v1", "kind": "Node", "name": "node-1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal(int32(3), s.nodeGets.Load())
+	})
+}
+
+func (s *CacheSuite) TestCacheParameter() {
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	for _, tool := range tools.Tools {
+		switch tool.Name {
+		case "resources_get":
+			s.Run("is added to the cached read-only tools", func() {
+				s.Require().Contains(tool.InputSchema.Properties, "cache")
+				s.Contains(tool.InputSchema.Properties["cache"].(map[string]any)["description"], "results are cached for 1m0s")
+			})
+		case "resources_delete":
+			s.Run("is not added to the mutating tools", func() {
+				s.NotContains(tool.InputSchema.Properties, "cache")
+			})
+		case "resources_list":
+			s.Run("is not added to the tools without ttl", func() {
+				s.NotContains(tool.InputSchema.Properties, "cache")
+			})
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type CertificatesSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	csrs       map[string]*certificatesv1.CertificateSigningRequest
+	dryRun     []string
+}
+
+func (s *CertificatesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "system:node:worker-1", Organization: []string{"system:nodes"}},
+	}, key)
+	s.Require().NoError(err)
+	csr := func(name string, conditions ...certificatesv1.CertificateSigningRequestCondition) *certificatesv1.CertificateSigningRequest {
+		return &certificatesv1.CertificateSigningRequest{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "certificates.k8s.io/v1", Kind: "CertificateSigningRequest"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: certificatesv1.CertificateSigningRequestSpec{
+				Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request}),
+				SignerName: certificatesv1.KubeAPIServerClientKubeletSignerName,
+				Usages:     []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth},
+				Username:   "system:bootstrap:abcdef",
+				Groups:     []string{"system:bootstrappers", "system:authenticated"},
+			},
+			Status: certificatesv1.CertificateSigningRequestStatus{Conditions: conditions},
+		}
+	}
+	s.csrs = map[string]*certificatesv1.CertificateSigningRequest{
+		"csr-worker-1": csr("csr-worker-1"),
+		"csr-worker-2": csr("csr-worker-2"),
+		"csr-issued": csr("csr-issued", certificatesv1.CertificateSigningRequestCondition{
+			Type: certificatesv1.CertificateApproved, Status: v1.ConditionTrue, Reason: "AutoApproved", Message: "Auto approving kubelet client certificate",
+		}),
+	}
+	s.csrs["csr-issued"].Status.Certificate = []byte("certificate")
+	s.dryRun = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		Groups: []string{
+			`{"name":"certificates.k8s.io","versions":[{"groupVersion":"certificates.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"certificates.k8s.io/v1","version":"v1"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		const csrsPath = "/apis/certificates.k8s.io/v1/certificatesigningrequests"
+		switch {
+		case req.URL.Path == "/apis/certificates.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"certificates.k8s.io/v1","resources":[
+				{"name":"certificatesigningrequests","singularName":"","namespaced":false,"kind":"CertificateSigningRequest","verbs":["get","list","create","delete"]},
+				{"name":"certificatesigningrequests/approval","singularName":"","namespaced":false,"kind":"CertificateSigningRequest","verbs":["get","update"]}
+			]}`))
+		case req.URL.Path == csrsPath:
+			list := &certificatesv1.CertificateSigningRequestList{TypeMeta: metav1.TypeMeta{APIVersion: "certificates.k8s.io/v1", Kind: "CertificateSigningRequestList"}}
+			for _, name := range []string{"csr-issued", "csr-worker-1", "csr-worker-2"} {
+				list.Items = append(list.Items, *s.csrs[name])
+			}
+			test.WriteObject(w, list)
+		case strings.HasSuffix(req.URL.Path, "/approval") && req.Method == http.MethodPut:
+			body, _ := io.ReadAll(req.Body)
+			obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			s.dryRun = append(s.dryRun, req.URL.Query().Get("dryRun"))
+			updated := obj.(*certificatesv1.CertificateSigningRequest)
+			if req.URL.Query().Get("dryRun") == "" {
+				s.csrs[updated.Name] = updated
+			}
+			test.WriteObject(w, updated)
+		case strings.HasPrefix(req.URL.Path, csrsPath+"/"):
+			if csr, ok := s.csrs[strings.TrimPrefix(req.URL.Path, csrsPath+"/")]; ok {
+				test.WriteObject(w, csr)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *CertificatesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *CertificatesSuite) TestCertificatesList() {
+	s.InitMcpClient()
+	s.Run("certificates_list()", func() {
+		toolResult, err := s.CallTool("certificates_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("lists the pending CSRs", func() {
+			s.Contains(text, "# 2 pending CertificateSigningRequests found\n")
+			s.Contains(text, "  name: csr-worker-1\n")
+			s.Contains(text, "  status: Pending\n")
+			s.NotContains(text, "csr-issued")
+		})
+		s.Run("returns the signer, requester, usages and requested subject", func() {
+			s.Contains(text, "  signerName: kubernetes.io/kube-apiserver-client-kubelet\n")
+			s.Contains(text, "  requester: system:bootstrap:abcdef\n")
+			s.Contains(text, "  - client auth\n")
+			s.Contains(text, "  subject: CN=system:node:worker-1,O=system:nodes\n")
+		})
+	})
+	s.Run("certificates_list(all=true)", func() {
+		toolResult, err := s.CallTool("certificates_list", map[string]interface{}{"all": true})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "# 3 CertificateSigningRequests found\n")
+		s.Contains(text, "  name: csr-issued\n")
+		s.Contains(text, "  status: Approved,Issued\n")
+		s.Contains(text, "  - 'Approved: AutoApproved Auto approving kubelet client certificate'\n")
+	})
+}
+
+func (s *CertificatesSuite) TestCertificates() {
+	s.InitMcpClient()
+	s.Run("certificates(action=approve)", func() {
+		toolResult, err := s.CallTool("certificates", map[string]interface{}{"action": "approve", "name": "csr-worker-1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "# CertificateSigningRequest csr-worker-1 approved successfully\n")
+		s.Contains(text, "status: Approved\n")
+		s.Regexp(`- 'Approved: KubernetesMCPServerApprove This CSR was approved by the Kubernetes MCP\s+server'\n`, text)
+		s.Run("is no longer listed as pending", func() {
+			listResult, err := s.CallTool("certificates_list", map[string]interface{}{})
+			s.Require().NoError(err)
+			s.NotContains(listResult.Content[0].(mcp.TextContent).Text, "csr-worker-1")
+		})
+	})
+	s.Run("certificates(action=deny, message)", func() {
+		toolResult, err := s.CallTool("certificates", map[string]interface{}{
+			"action":  "deny",
+			"name":    "csr-worker-2",
+			"message": "unknown node",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "# CertificateSigningRequest csr-worker-2 denied successfully\n")
+		s.Contains(text, "- 'Denied: KubernetesMCPServerDeny unknown node'\n")
+	})
+	s.Run("certificates(action=approve) of a denied CSR", func() {
+		toolResult, err := s.CallTool("certificates", map[string]interface{}{"action": "approve", "name": "csr-worker-2"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to approve certificate signing request csr-worker-2: certificate signing request is not pending (status: Denied)",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("certificates(action=renew)", func() {
+		toolResult, err := s.CallTool("certificates", map[string]interface{}{"action": "renew", "name": "csr-worker-1"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to manage certificate signing request, unsupported action "renew", supported actions: approve, deny`,
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *CertificatesSuite) TestCertificatesDryRun() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("certificates", map[string]interface{}{"action": "approve", "name": "csr-worker-1", "dry_run": true})
+	s.Require().NoError(err)
+	s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	s.Run("uses server-side dry-run", func() {
+		s.Equal([]string{"All"}, s.dryRun)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# Dry run:")
+	})
+	s.Run("doesn't approve the CSR", func() {
+		s.Empty(s.csrs["csr-worker-1"].Status.Conditions)
+	})
+}
+
+func (s *CertificatesSuite) TestCertificatesOperatorProfile() {
+	s.Cfg.ToolProfile = "operator"
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	s.Contains(names, "certificates_list")
+	s.NotContains(names, "certificates")
+}
+
+func TestCertificates(t *testing.T) {
+	suite.Run(t, new(CertificatesSuite))
+}
@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ConfigUsageSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *ConfigUsageSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	updated := time.Now().Add(-time.Hour)
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"configmaps","singularName":"","namespaced":true,"kind":"ConfigMap","verbs":["get","list"]}`,
+			`{"name":"secrets","singularName":"","namespaced":true,"kind":"Secret","verbs":["get","list"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/configmaps/app-config":
+			test.WriteObject(w, &v1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "app-config", ResourceVersion: "4242",
+					CreationTimestamp: metav1.NewTime(updated.Add(-24 * time.Hour)),
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: ptr.To(metav1.NewTime(updated))},
+					}},
+				Data: map[string]string{"log-level": "debug-value"},
+			})
+		case "/api/v1/namespaces/ns-1/secrets/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v1/namespaces/ns-1/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-5d9c-x2k4p", OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d9c", UID: "rs-uid", Controller: ptr.To(true)},
+					}},
+					Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "example.com/app:1.0", EnvFrom: []v1.EnvFromSource{{
+						ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app-config"}},
+					}}}}},
+					Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{
+						Name: "app", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(updated.Add(-time.Hour))}},
+					}}},
+				}},
+			})
+		case "/apis/apps/v1/namespaces/ns-1/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSetList"},
+				Items: []appsv1.ReplicaSet{{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-5d9c", OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "web-uid", Controller: ptr.To(true)},
+				}}}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *ConfigUsageSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ConfigUsageSuite) TestConfigUsage() {
+	s.InitMcpClient()
+	s.Run("config_usage(namespace=ns-1, name=app-config)", func() {
+		toolResult, err := s.CallTool("config_usage", map[string]interface{}{"namespace": "ns-1", "name": "app-config"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# ConfigMap app-config, used by 1 pods of 1 workloads, 1 pods are stale (web-5d9c-x2k4p)\n"), text)
+		s.Contains(text, "resourceVersion: \"4242\"")
+		s.Contains(text, "workload: Deployment/web\n")
+		s.Contains(text, "type: envFrom\n")
+		s.Contains(text, "stale: true")
+		s.NotContains(text, "debug-value", "values are never returned")
+	})
+	s.Run("config_usage(kind=Secret, name=missing) reports the missing secret", func() {
+		toolResult, err := s.CallTool("config_usage", map[string]interface{}{"kind": "Secret", "namespace": "ns-1", "name": "missing"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# Secret missing not found, not used by any running pod\n"))
+	})
+	s.Run("config_usage(kind=Deployment) returns error", func() {
+		toolResult, err := s.CallTool("config_usage", map[string]interface{}{"kind": "Deployment", "name": "web"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to map config usage, invalid argument kind: ")
+	})
+	s.Run("config_usage() returns error", func() {
+		toolResult, err := s.CallTool("config_usage", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to map config usage, ")
+	})
+}
+
+func TestConfigUsage(t *testing.T) {
+	suite.Run(t, new(ConfigUsageSuite))
+}
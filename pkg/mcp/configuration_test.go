@@ -7,11 +7,13 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/suite"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	v1 "k8s.io/client-go/tools/clientcmd/api/v1"
 	"sigs.k8s.io/yaml"
 
 	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 )
 
@@ -57,6 +59,39 @@ func (s *ConfigurationSuite) TestContextsList() {
 	})
 }
 
+func (s *ConfigurationSuite) TestContextsSetDefault() {
+	s.InitMcpClient()
+	s.Run("configuration_contexts_set_default(name=nil)", func() {
+		toolResult, err := s.CallTool("configuration_contexts_set_default", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to set default context, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("configuration_contexts_set_default(name=inexistent)", func() {
+		toolResult, err := s.CallTool("configuration_contexts_set_default", map[string]interface{}{"name": "inexistent"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to set default context: context inexistent not found in kubeconfig", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("configuration_contexts_set_default(name=cluster-3)", func() {
+		toolResult, err := s.CallTool("configuration_contexts_set_default", map[string]interface{}{"name": "cluster-3"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("updates kubeconfig current-context", func() {
+			kubeconfig, err := clientcmd.LoadFromFile(s.Cfg.KubeConfig)
+			s.Require().NoError(err)
+			s.Equal("cluster-3", kubeconfig.CurrentContext)
+		})
+		s.Run("preserves kubeconfig contexts", func() {
+			kubeconfig, err := clientcmd.LoadFromFile(s.Cfg.KubeConfig)
+			s.Require().NoError(err)
+			s.Len(kubeconfig.Contexts, 11)
+		})
+	})
+}
+
 func (s *ConfigurationSuite) TestConfigurationView() {
 	s.InitMcpClient()
 	s.Run("configuration_view", func() {
@@ -165,6 +200,54 @@ func (s *ConfigurationSuite) TestConfigurationViewInCluster() {
 	})
 }
 
+func (s *ConfigurationSuite) TestServerInfo() {
+	s.Cfg.ReadOnly = true
+	s.Cfg.EventStoreSize = 100
+	s.Cfg.DeniedResources = []config.GroupVersionKind{
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+		{Group: "", Version: "v1"},
+	}
+	s.InitMcpClient()
+	s.Run("server_info", func() {
+		toolResult, err := s.CallTool("server_info", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		var info kubernetes.ServerInfo
+		err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &info)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+		})
+		s.Run("returns server name and version", func() {
+			s.Equal("kubernetes-mcp-server", info.Name)
+			s.NotEmpty(info.Version)
+		})
+		s.Run("returns enabled toolsets", func() {
+			s.Equal([]string{"core", "config", "helm"}, info.Toolsets)
+		})
+		s.Run("returns cluster names", func() {
+			s.Equal("kubeconfig", info.ClusterProviderStrategy)
+			s.Len(info.Clusters, 11)
+			s.Contains(info.Clusters, "cluster-1")
+			s.Equal("fake-context", info.DefaultCluster)
+		})
+		s.Run("does not return server URLs", func() {
+			s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "127.0.0.1")
+		})
+		s.Run("returns limits", func() {
+			s.True(info.Limits.ReadOnly)
+			s.False(info.Limits.DisableDestructive)
+			s.Equal([]string{"rbac.authorization.k8s.io/v1 Role", "v1 *"}, info.Limits.DeniedResources)
+		})
+		s.Run("returns feature flags", func() {
+			s.True(info.Features.EventStore)
+			s.Equal(100, info.Features.EventStoreSize)
+			s.False(info.Features.RequireOAuth)
+		})
+	})
+}
+
 func TestConfiguration(t *testing.T) {
 	suite.Run(t, new(ConfigurationSuite))
 }
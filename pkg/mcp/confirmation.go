@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	// confirmationTokenTTL is the time a pending action can be confirmed for
+	confirmationTokenTTL = 5 * time.Minute
+)
+
+// PendingAction is a destructive tool call waiting for confirmation
+type PendingAction struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	// Session is the ID of the MCP session the action was requested in, only the same session can confirm it
+	Session   string `json:"session,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+	Nonce     string `json:"nonce"`
+}
+
+// ConfirmationStore signs and verifies the confirmation tokens of the pending actions.
+// Tokens are signed with a random key generated on startup (tokens don't survive server restarts)
+// and can only be used once.
+type ConfirmationStore struct {
+	key  []byte
+	now  func() time.Time
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+func NewConfirmationStore() *ConfirmationStore {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return &ConfirmationStore{key: key, now: time.Now, used: make(map[string]time.Time)}
+}
+
+// Sign returns the confirmation token of the pending action, the expiration and nonce are set by the store
+func (c *ConfirmationStore) Sign(action *PendingAction) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	action.Nonce = hex.EncodeToString(nonce)
+	action.ExpiresAt = c.now().Add(confirmationTokenTTL).Unix()
+	payload, err := json.Marshal(action)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + c.signature(encoded), nil
+}
+
+// Verify checks the token signature and expiration, and returns the pending action.
+// The token is consumed, subsequent verifications of the same token fail.
+func (c *ConfirmationStore) Verify(token, session string) (*PendingAction, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(c.signature(encoded))) {
+		return nil, errors.New("invalid confirmation token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid confirmation token")
+	}
+	action := &PendingAction{}
+	if err = json.Unmarshal(payload, action); err != nil {
+		return nil, errors.New("invalid confirmation token")
+	}
+	now := c.now()
+	if now.Unix() > action.ExpiresAt {
+		return nil, errors.New("confirmation token expired, call the tool again to get a new one")
+	}
+	if action.Session != session {
+		return nil, errors.New("confirmation token was issued for a different MCP session")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for nonce, expiresAt := range c.used {
+		if now.After(expiresAt) {
+			delete(c.used, nonce)
+		}
+	}
+	if _, used := c.used[action.Nonce]; used {
+		return nil, errors.New("confirmation token was already used")
+	}
+	c.used[action.Nonce] = time.Unix(action.ExpiresAt, 0)
+	return action, nil
+}
+
+func (c *ConfirmationStore) signature(encoded string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// requiresConfirmation returns true if the tool call must be confirmed with confirm_action before it's executed
+func (c *Configuration) requiresConfirmation(tool api.ServerTool) bool {
+	return c.RequireConfirmation && ptr.Deref(tool.Tool.Annotations.DestructiveHint, false) &&
+		tool.Tool.Name != api.ConfirmActionToolName
+}
+
+// pendingActionResult describes the pending action and returns the token to confirm it
+func (s *Server) pendingActionResult(tool api.ServerTool, toolCallRequest *ToolCallRequest, session *mcp.ServerSession) *api.ToolCallResult {
+	action := &PendingAction{Tool: tool.Tool.Name, Arguments: toolCallRequest.arguments, Session: sessionID(session)}
+	token, err := s.confirmations.Sign(action)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to request confirmation for %s: %w", tool.Tool.Name, err))
+	}
+	pending, err := output.MarshalYaml(map[string]any{
+		"tool":      action.Tool,
+		"arguments": action.Arguments,
+		"expiresAt": time.Unix(action.ExpiresAt, 0).UTC().Format(time.RFC3339),
+		"token":     token,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to request confirmation for %s: %w", tool.Tool.Name, err))
+	}
+	return api.NewToolCallResult(
+		fmt.Sprintf("# Confirmation required: %s is a destructive tool, the action was not performed\n", tool.Tool.Name)+
+			"# Ask the user to review the following action, then call "+api.ConfirmActionToolName+" with the token to perform it\n"+pending,
+		nil)
+}
+
+// confirmationTools returns the tools to confirm the pending destructive actions
+func (s *Server) confirmationTools() []api.ServerTool {
+	return []api.ServerTool{{
+		Tool: api.Tool{
+			Name: api.ConfirmActionToolName,
+			Description: "Perform a destructive action that was put on hold for confirmation. " +
+				"Destructive tools don't perform their action right away, they return a description of the pending action and a confirmation token. " +
+				"Only call this tool once the user has reviewed and approved the pending action. " +
+				fmt.Sprintf("Tokens can only be used once, in the same session, and expire after %s", confirmationTokenTTL),
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"token": {
+						Type:        "string",
+						Description: "Confirmation token returned by the destructive tool",
+					},
+				},
+				Required: []string{"token"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Confirm Action",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		},
+		ClusterAware: ptr.To(false),
+		Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+			token, ok := params.GetArguments()["token"].(string)
+			if !ok || token == "" {
+				return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to confirm action, missing argument token"))), nil
+			}
+			session, _ := params.Value(sessionContextKey).(*mcp.ServerSession)
+			action, err := s.confirmations.Verify(token, sessionID(session))
+			if err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to confirm action: %w", err)), nil
+			}
+			tool, ok := s.tool(action.Tool)
+			if !ok {
+				return api.NewToolCallResult("", fmt.Errorf("failed to confirm action, tool %s is no longer available", action.Tool)), nil
+			}
+			return s.callTool(params.Context, session, tool, &ToolCallRequest{Name: action.Tool, arguments: action.Arguments}, true)
+		},
+	}}
+}
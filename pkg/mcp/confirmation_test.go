@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ConfirmationStoreSuite struct {
+	suite.Suite
+}
+
+func (s *ConfirmationStoreSuite) TestSignAndVerify() {
+	store := NewConfirmationStore()
+	token, err := store.Sign(&PendingAction{Tool: "pods_delete", Arguments: map[string]any{"name": "pod-1"}, Session: "session-1"})
+	s.Require().NoError(err)
+	s.Run("verifies valid token", func() {
+		action, err := store.Verify(token, "session-1")
+		s.Require().NoError(err)
+		s.Equal("pods_delete", action.Tool)
+		s.Equal(map[string]any{"name": "pod-1"}, action.Arguments)
+	})
+	s.Run("rejects used token", func() {
+		_, err := store.Verify(token, "session-1")
+		s.EqualError(err, "confirmation token was already used")
+	})
+}
+
+func (s *ConfirmationStoreSuite) TestVerifyInvalid() {
+	store := NewConfirmationStore()
+	token, err := store.Sign(&PendingAction{Tool: "pods_delete", Arguments: map[string]any{"name": "pod-1"}})
+	s.Require().NoError(err)
+	s.Run("rejects tampered token", func() {
+		tampered, _ := store.Sign(&PendingAction{Tool: "namespaces", Arguments: map[string]any{"name": "kube-system"}})
+		payload, _, _ := strings.Cut(tampered, ".")
+		_, signature, _ := strings.Cut(token, ".")
+		_, err := store.Verify(payload+"."+signature, "")
+		s.EqualError(err, "invalid confirmation token")
+	})
+	s.Run("rejects token signed by another server", func() {
+		_, err := NewConfirmationStore().Verify(token, "")
+		s.EqualError(err, "invalid confirmation token")
+	})
+	s.Run("rejects token from another session", func() {
+		_, err := store.Verify(token, "session-2")
+		s.EqualError(err, "confirmation token was issued for a different MCP session")
+	})
+	s.Run("rejects expired token", func() {
+		store.now = func() time.Time { return time.Now().Add(confirmationTokenTTL + time.Minute) }
+		_, err := store.Verify(token, "")
+		s.EqualError(err, "confirmation token expired, call the tool again to get a new one")
+	})
+}
+
+func TestConfirmationStore(t *testing.T) {
+	suite.Run(t, new(ConfirmationStoreSuite))
+}
+
+type ConfirmationSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	deleted    []string
+}
+
+func (s *ConfirmationSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.deleted = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"namespaces","singularName":"","namespaced":false,"kind":"Namespace","verbs":["create","delete","get","list"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/namespaces/ns-1" && req.Method == http.MethodDelete {
+			// dry-run deletions don't count as performed
+			options := &metav1.DeleteOptions{}
+			if body, _ := io.ReadAll(req.Body); len(body) > 0 {
+				_, _, _ = scheme.Codecs.UniversalDeserializer().Decode(body, nil, options)
+			}
+			if len(options.DryRun) > 0 {
+				test.WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+				return
+			}
+			s.mu.Lock()
+			s.deleted = append(s.deleted, "ns-1")
+			s.mu.Unlock()
+			test.WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *ConfirmationSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ConfirmationSuite) deletedNamespaces() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.deleted...)
+}
+
+func (s *ConfirmationSuite) TestRequireConfirmation() {
+	s.Cfg.RequireConfirmation = true
+	s.InitMcpClient()
+	var token string
+	s.Run("namespaces(action=delete)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{
+			"action": "delete",
+			"name":   "ns-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("does not perform the action", func() {
+			s.Empty(s.deletedNamespaces())
+		})
+		s.Run("describes the pending action", func() {
+			s.True(strings.HasPrefix(text, "# Confirmation required: namespaces is a destructive tool, the action was not performed\n"),
+				"unexpected result %v", text)
+			s.Contains(text, "tool: namespaces\n")
+			s.Contains(text, "  name: ns-1\n")
+		})
+		s.Run("returns confirmation token", func() {
+			matches := regexp.MustCompile(`(?m)^token: (\S+)$`).FindStringSubmatch(text)
+			s.Require().Len(matches, 2, "token not found in %s", text)
+			token = matches[1]
+		})
+	})
+	s.Run("namespaces(action=delete, dry_run=true) doesn't require confirmation", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{
+			"action":  "delete",
+			"name":    "ns-1",
+			"dry_run": true,
+		})
+		s.Require().NoError(err)
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "# Confirmation required")
+	})
+	s.Run("confirm_action(token=valid)", func() {
+		toolResult, err := s.CallTool("confirm_action", map[string]interface{}{
+			"token": token,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("performs the action", func() {
+			s.Equal([]string{"ns-1"}, s.deletedNamespaces())
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "ns-1")
+		})
+	})
+	s.Run("confirm_action(token=used)", func() {
+		toolResult, err := s.CallTool("confirm_action", map[string]interface{}{
+			"token": token,
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to confirm action: confirmation token was already used", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Len(s.deletedNamespaces(), 1)
+	})
+	s.Run("confirm_action(token=nil)", func() {
+		toolResult, err := s.CallTool("confirm_action", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to confirm action, missing argument token", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *ConfirmationSuite) TestConfirmationNotRequired() {
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	s.Run("confirm_action is not registered", func() {
+		for _, tool := range tools.Tools {
+			s.NotEqual("confirm_action", tool.Name)
+		}
+	})
+	s.Run("namespaces(action=delete) performs the action", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{
+			"action": "delete",
+			"name":   "ns-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal([]string{"ns-1"}, s.deletedNamespaces())
+	})
+}
+
+func TestConfirmation(t *testing.T) {
+	suite.Run(t, new(ConfirmationSuite))
+}
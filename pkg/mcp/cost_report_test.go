@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type CostReportSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *CostReportSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	pod := func(name, cpu, memory string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: name},
+			Spec: v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "main", Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse(memory)},
+			}}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/namespaces/ns-1/pods" {
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items:    []v1.Pod{pod("web-1", "500m", "1Gi"), pod("web-2", "1500m", "3Gi")},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *CostReportSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *CostReportSuite) TestCostReport() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		[cost]
+		currency = "EUR"
+		cpu_core_hour = 0.03
+		memory_gib_hour = 0.004
+	`), s.Cfg), "Expected to parse cost config")
+	s.InitMcpClient()
+	s.Run("cost_report(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("cost_report", map[string]interface{}{"namespace": "ns-1"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# Estimated cost of namespace ns-1: 55.48 EUR per month (requests)\n"), text)
+		s.Contains(text, "cpuRequestCores: 2\n")
+		s.Contains(text, "memoryRequestGiB: 4\n")
+		s.Contains(text, "hourlyCost: 0.076\n")
+	})
+	s.Run("cost_report(usage=true) without metrics API reports a warning", func() {
+		toolResult, err := s.CallTool("cost_report", map[string]interface{}{"namespace": "ns-1", "usage": true})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# Estimated cost of namespace ns-1: 55.48 EUR per month (requests)\n"), text)
+		s.Contains(text, "unable to get the usage of the pods: metrics API is not available")
+	})
+}
+
+func (s *CostReportSuite) TestCostReportWithoutPrices() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("cost_report", map[string]interface{}{})
+	s.Require().NoError(err)
+	s.True(toolResult.IsError)
+	s.Equal("failed to estimate cost: no unit price configured, set cost.cpu_core_hour and cost.memory_gib_hour in the configuration",
+		toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func TestCostReport(t *testing.T) {
+	suite.Run(t, new(CostReportSuite))
+}
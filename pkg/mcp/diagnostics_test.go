@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type DiagnosticsSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+	outputDir        string
+}
+
+func (s *DiagnosticsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.outputDir = s.T().TempDir()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"},
+				Items: []v1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+				},
+			})
+		case "/api/v1/nodes/node-1", "/api/v1/nodes/node-2":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: strings.TrimPrefix(req.URL.Path, "/api/v1/nodes/")},
+			})
+		case "/api/v1/nodes/node-1/proxy/logs":
+			_, _ = w.Write([]byte("I0101 10:00:00.000000 1234 kubelet.go:2000] \"SyncLoop ADD\" tailLines=" + req.URL.Query().Get("tailLines") + "\n"))
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"nodeName":"node-1"}}`))
+		case "/api/v1/nodes/node-2/proxy/logs":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/api/v1/namespaces/shop/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items:    []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"}}},
+			})
+		case "/api/v1/namespaces/shop/events":
+			test.WriteObject(w, &v1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items:    []v1.Event{{ObjectMeta: metav1.ObjectMeta{Name: "web-1.1", Namespace: "shop"}, Reason: "BackOff"}},
+			})
+		case "/api/v1/namespaces/forbidden/pods":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		return "==> ok /host/etc/hosts\n" + base64.StdEncoding.EncodeToString([]byte("127.0.0.1 localhost\n")) + "\n" +
+			"==> missing /host/etc/missing\n"
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		toolsets = ["diagnostics"]
+		[toolset_configs.diagnostics]
+		output_dir = "` + filepath.ToSlash(s.outputDir) + `"
+	`)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *DiagnosticsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *DiagnosticsSuite) TestDiagnosticsCollect() {
+	s.InitMcpClient()
+	s.Run("diagnostics_collect(namespaces=[shop], nodes=[node-1], node_files=[...], log_lines=50)", func() {
+		toolResult, err := s.CallTool("diagnostics_collect", map[string]interface{}{
+			"namespaces": []interface{}{"shop"},
+			"nodes":      []interface{}{"node-1"},
+			"node_files": []interface{}{"/etc/hosts", "/etc/missing"},
+			"log_lines":  50,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		bundles, err := filepath.Glob(filepath.Join(s.outputDir, "diagnostics-*.tar.gz"))
+		s.Require().NoError(err)
+		s.Require().Len(bundles, 1)
+		s.Run("writes the bundle to the output directory", func() {
+			s.True(strings.HasPrefix(text, "# Diagnostic bundle with 6 files written to "+bundles[0]+"\n"), text)
+			s.Contains(text, "- name: nodes/node-1/files/etc/hosts\n  size: 20\n")
+		})
+		files := s.untar(bundles[0])
+		s.Run("collects the selected nodes", func() {
+			s.Contains(files["nodes.yaml"], "name: node-1")
+			s.NotContains(files["nodes.yaml"], "name: node-2")
+		})
+		s.Run("collects the pods and events of the selected namespaces", func() {
+			s.Contains(files["namespaces/shop/pods.yaml"], "name: web-1")
+			s.Contains(files["namespaces/shop/events.yaml"], "reason: BackOff")
+		})
+		s.Run("collects the kubelet logs and stats summary", func() {
+			s.Equal("I0101 10:00:00.000000 1234 kubelet.go:2000] \"SyncLoop ADD\" tailLines=50\n", files["nodes/node-1/kubelet.log"])
+			s.Equal(`{"node":{"nodeName":"node-1"}}`, files["nodes/node-1/stats-summary.json"])
+		})
+		s.Run("collects the existing node files", func() {
+			s.Equal("127.0.0.1 localhost\n", files["nodes/node-1/files/etc/hosts"])
+			s.NotContains(files, "nodes/node-1/files/etc/missing")
+		})
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("reads the node files from a read-only helper pod", func() {
+			s.Equal("node-1", pod.Spec.NodeName)
+			s.True(pod.Spec.Containers[0].VolumeMounts[0].ReadOnly)
+			s.Equal([]string{"sh", "/host/etc/hosts", "/host/etc/missing"}, pod.Spec.Containers[0].Command[3:])
+			s.Equal([]string{pod.Name}, s.helperPodHandler.Deleted())
+		})
+	})
+	s.Run("diagnostics_collect(namespaces=[shop, forbidden]) with unavailable node", func() {
+		toolResult, err := s.CallTool("diagnostics_collect", map[string]interface{}{
+			"namespaces": []interface{}{"shop", "forbidden"},
+			"nodes":      []interface{}{"node-1", "node-2", "node-3"},
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("reports the failed targets", func() {
+			s.Contains(text, "# Partial result: 3 of ")
+			s.Contains(text, "- error: node node-3 not found\n  target: node/node-3\n")
+			s.Contains(text, "target: namespace/forbidden\n")
+			s.Contains(text, "target: node/node-2\n")
+		})
+		s.Run("lists the failed targets in the bundle", func() {
+			s.Contains(text, "- name: errors.yaml\n")
+		})
+	})
+	s.Run("diagnostics_collect(node_files=[relative])", func() {
+		toolResult, err := s.CallTool("diagnostics_collect", map[string]interface{}{"node_files": []interface{}{"etc/hosts"}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to collect diagnostics: invalid node file "etc/hosts", node paths must be absolute`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *DiagnosticsSuite) untar(bundle string) map[string]string {
+	f, err := os.Open(bundle)
+	s.Require().NoError(err)
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	s.Require().NoError(err)
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		s.Require().NoError(err)
+		content, err := io.ReadAll(tr)
+		s.Require().NoError(err)
+		_, name, _ := strings.Cut(header.Name, "/")
+		files[name] = string(content)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	suite.Run(t, new(DiagnosticsSuite))
+}
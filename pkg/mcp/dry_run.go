@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"fmt"
+	"maps"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// dryRunParameterName is the name of the parameter added to the mutating tools to request a dry-run
+const dryRunParameterName = "dry_run"
+
+// isDryRun returns true if the tool call must not persist any change, either because the server runs in
+// dry-run mode or because the caller requested it through the dry_run parameter.
+// Read-only tools are never affected.
+func (c *Configuration) isDryRun(tool api.ServerTool, toolCallRequest api.ToolCallRequest) bool {
+	if ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
+		return false
+	}
+	if c.DryRun {
+		return true
+	}
+	dryRun, _ := toolCallRequest.GetArguments()[dryRunParameterName].(bool)
+	return dryRun
+}
+
+// dryRunResult prefixes the result of a tool that ran with Kubernetes server-side dry-run
+func dryRunResult(result *api.ToolCallResult) *api.ToolCallResult {
+	if result.Error != nil {
+		return result
+	}
+	return api.NewToolCallResult(
+		"# Dry run: the changes were validated by the Kubernetes API server (server-side dry-run), nothing was persisted\n"+result.Content,
+		nil)
+}
+
+// dryRunSimulation describes the operation a mutating tool that doesn't support server-side dry-run would perform,
+// the tool is not invoked
+func dryRunSimulation(tool api.ServerTool, toolCallRequest api.ToolCallRequest) *api.ToolCallResult {
+	arguments := maps.Clone(toolCallRequest.GetArguments())
+	delete(arguments, dryRunParameterName)
+	operation, err := output.MarshalYaml(map[string]any{"tool": tool.Tool.Name, "arguments": arguments})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to describe the dry run of %s: %w", tool.Tool.Name, err))
+	}
+	return api.NewToolCallResult(
+		fmt.Sprintf("# Dry run: %s doesn't support server-side dry-run, the tool was not invoked and nothing was changed\n", tool.Tool.Name)+
+			"# The following operation would be performed\n"+operation,
+		nil)
+}
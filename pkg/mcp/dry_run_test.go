@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type DryRunSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	requests   []string
+}
+
+func (s *DryRunSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.requests = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"namespaces","singularName":"","namespaced":false,"kind":"Namespace","verbs":["create","delete","get","list"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/ns-1") {
+			return
+		}
+		dryRun := req.URL.Query().Get("dryRun")
+		// Typed clients send the delete options protobuf encoded in the request body
+		if body, _ := io.ReadAll(req.Body); len(body) > 0 {
+			options := &metav1.DeleteOptions{}
+			if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, options); err == nil && len(options.DryRun) > 0 {
+				dryRun = options.DryRun[0]
+			}
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, req.Method+" "+req.URL.Path+" dryRun="+dryRun)
+		s.mu.Unlock()
+		switch {
+		case req.URL.Path == "/api/v1/namespaces/ns-1" && req.Method == http.MethodDelete:
+			test.WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+		case req.URL.Path == "/api/v1/namespaces/ns-1/pods/pod-1":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "pod-1"},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *DryRunSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *DryRunSuite) recordedRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *DryRunSuite) TestDryRunParameter() {
+	s.InitMcpClient()
+	s.Run("namespaces(action=delete, dry_run=true)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{
+			"action":  "delete",
+			"name":    "ns-1",
+			"dry_run": true,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("sends server-side dry-run request", func() {
+			s.Equal([]string{"DELETE /api/v1/namespaces/ns-1 dryRun=All"}, s.recordedRequests())
+		})
+		s.Run("returns dry-run result", func() {
+			s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text,
+				"# Dry run: the changes were validated by the Kubernetes API server (server-side dry-run), nothing was persisted\n"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("pods_exec(dry_run=true)", func() {
+		s.requests = nil
+		toolResult, err := s.CallTool("pods_exec", map[string]interface{}{
+			"namespace": "ns-1",
+			"name":      "pod-1",
+			"command":   []interface{}{"rm", "-rf", "/data"},
+			"dry_run":   true,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("does not invoke the tool", func() {
+			s.Empty(s.recordedRequests())
+		})
+		s.Run("describes the operation", func() {
+			s.True(strings.HasPrefix(text, "# Dry run: pods_exec doesn't support server-side dry-run, the tool was not invoked and nothing was changed\n"),
+				"unexpected result %v", text)
+			s.Contains(text, "tool: pods_exec\n")
+			s.Contains(text, "  - rm\n")
+			s.NotContains(text, "dry_run")
+		})
+	})
+	s.Run("namespaces(action=delete)", func() {
+		s.requests = nil
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{
+			"action": "delete",
+			"name":   "ns-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("sends regular request", func() {
+			s.Equal([]string{"DELETE /api/v1/namespaces/ns-1 dryRun="}, s.recordedRequests())
+		})
+		s.Run("returns regular result", func() {
+			s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "# Dry run")
+		})
+	})
+}
+
+func (s *DryRunSuite) TestDryRunConfiguration() {
+	s.Cfg.DryRun = true
+	s.InitMcpClient()
+	s.Run("namespaces(action=delete, dry_run=false)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{
+			"action":  "delete",
+			"name":    "ns-1",
+			"dry_run": false,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("sends server-side dry-run request regardless of the parameter", func() {
+			s.Equal([]string{"DELETE /api/v1/namespaces/ns-1 dryRun=All"}, s.recordedRequests())
+		})
+	})
+	s.Run("pods_get(name=pod-1)", func() {
+		s.requests = nil
+		toolResult, err := s.CallTool("pods_get", map[string]interface{}{
+			"namespace": "ns-1",
+			"name":      "pod-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("read-only tools are not affected", func() {
+			s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "# Dry run")
+			s.Equal([]string{"GET /api/v1/namespaces/ns-1/pods/pod-1 dryRun="}, s.recordedRequests())
+		})
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	suite.Run(t, new(DryRunSuite))
+}
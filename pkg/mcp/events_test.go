@@ -1,15 +1,23 @@
 package mcp
 
 import (
+	"io"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/containers/kubernetes-mcp-server/internal/test"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/suite"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/yaml"
 )
 
@@ -144,3 +152,127 @@ func (s *EventsSuite) TestEventsListDenied() {
 func TestEvents(t *testing.T) {
 	suite.Run(t, new(EventsSuite))
 }
+
+type EventsHistorySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *EventsHistorySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	now := time.Now()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+		},
+		Groups: []string{
+			`{"name":"authorization.k8s.io","versions":[{"groupVersion":"authorization.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"authorization.k8s.io/v1","version":"v1"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/authorization.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"authorization.k8s.io/v1","resources":[
+				{"name":"selfsubjectaccessreviews","singularName":"","namespaced":false,"kind":"SelfSubjectAccessReview","verbs":["create"]}
+			]}`))
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			body, _ := io.ReadAll(req.Body)
+			if obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body); err == nil {
+				review := obj.(*authorizationv1.SelfSubjectAccessReview)
+				review.Status.Allowed = review.Spec.ResourceAttributes.Namespace != "forbidden"
+				test.WriteObject(w, review)
+			}
+		case "/api/v1/events":
+			if req.URL.Query().Get("watch") == "true" {
+				<-req.Context().Done()
+				return
+			}
+			event := func(name, namespace, eventType string, ago time.Duration) v1.Event {
+				return v1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: namespace, UID: "uid-" + types.UID(name)},
+					Type:           eventType,
+					Reason:         "Reason",
+					Message:        "Message of " + name,
+					InvolvedObject: v1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "a-pod"},
+					FirstTimestamp: metav1.NewTime(now.Add(-ago)),
+				}
+			}
+			test.WriteObject(w, &v1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+				Items: []v1.Event{
+					event("old-event", "default", "Warning", 3*time.Hour),
+					event("recent-event", "default", "Normal", 10*time.Minute),
+					event("other-namespace-event", "ns-1", "Warning", 5*time.Minute),
+				},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *EventsHistorySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *EventsHistorySuite) TestEventsHistoryDisabled() {
+	s.InitMcpClient()
+	s.Run("events_history (event store disabled)", func() {
+		toolResult, err := s.CallTool("events_history", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to query events history: the event store is not enabled, set event_store_size in the server configuration to enable it",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *EventsHistorySuite) TestEventsHistory() {
+	s.Cfg.EventStoreSize = 100
+	s.InitMcpClient()
+	s.Require().Eventually(func() bool {
+		toolResult, err := s.CallTool("events_history", map[string]interface{}{})
+		return err == nil && !toolResult.IsError && strings.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# The following 3 events")
+	}, 10*time.Second, 50*time.Millisecond, "expected event store to ingest the cluster events")
+	s.Run("events_history(since=1h)", func() {
+		toolResult, err := s.CallTool("events_history", map[string]interface{}{"since": "1h"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		var decoded []map[string]interface{}
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+		s.Run("returns events in time window sorted by timestamp", func() {
+			s.Require().Len(decoded, 2)
+			s.Equal("Message of recent-event", decoded[0]["message"])
+			s.Equal("Message of other-namespace-event", decoded[1]["message"])
+		})
+	})
+	s.Run("events_history(namespace=default, type=Warning)", func() {
+		toolResult, err := s.CallTool("events_history", map[string]interface{}{"namespace": "default", "type": "Warning"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# The following 1 events")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "Message of old-event")
+	})
+	s.Run("events_history(since=invalid)", func() {
+		toolResult, err := s.CallTool("events_history", map[string]interface{}{"since": "yesterday"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to parse since parameter: ")
+	})
+	s.Run("events_history(namespace=forbidden)", func() {
+		toolResult, err := s.CallTool("events_history", map[string]interface{}{"namespace": "forbidden"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to query events history: not allowed to list events in namespace forbidden", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestEventsHistory(t *testing.T) {
+	suite.Run(t, new(EventsHistorySuite))
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type GitOpsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	patched    bool
+}
+
+func (s *GitOpsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.patched = false
+	application := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1", "kind": "Application",
+		"metadata": map[string]any{"namespace": "argocd", "name": "shop"},
+		"spec": map[string]any{
+			"source":      map[string]any{"repoURL": "https://github.com/org/repo", "path": "apps/shop", "targetRevision": "main"},
+			"destination": map[string]any{"namespace": "shop"},
+		},
+		"status": map[string]any{
+			"sync":   map[string]any{"status": "OutOfSync", "revision": "abc123"},
+			"health": map[string]any{"status": "Missing"},
+			"resources": []any{
+				map[string]any{"group": "apps", "version": "v1", "kind": "Deployment", "namespace": "shop", "name": "web", "status": "OutOfSync"},
+			},
+		},
+	}}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{Groups: []string{
+		`{"name":"argoproj.io","versions":[{"groupVersion":"argoproj.io/v1alpha1","version":"v1alpha1"}],"preferredVersion":{"groupVersion":"argoproj.io/v1alpha1","version":"v1alpha1"}}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/argoproj.io/v1alpha1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"argoproj.io/v1alpha1","resources":[
+				{"name":"applications","singularName":"application","namespaced":true,"kind":"Application","verbs":["get","list","patch"]}
+			]}`))
+		case "/apis/argoproj.io/v1alpha1/applications":
+			list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "argoproj.io/v1alpha1", "kind": "ApplicationList"}}
+			list.Items = []unstructured.Unstructured{*application}
+			test.WriteObject(w, list)
+		case "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications/shop":
+			if req.Method == http.MethodPatch {
+				s.patched = true
+			}
+			test.WriteObject(w, application)
+		case "/apis/apps/v1/namespaces/shop/deployments/web":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"gitops"}
+}
+
+func (s *GitOpsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *GitOpsSuite) TestGitOpsDrift() {
+	s.InitMcpClient()
+	s.Run("gitops_drift() lists the GitOps objects", func() {
+		toolResult, err := s.CallTool("gitops_drift", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# 1 Applications (1 not synced) in all namespaces, Kustomizations are not installed\n"), text)
+		s.Contains(text, "https://github.com/org/repo path apps/shop at main")
+	})
+	s.Run("gitops_drift(kind=Application, namespace=argocd, name=shop) reports the missing objects", func() {
+		toolResult, err := s.CallTool("gitops_drift", map[string]interface{}{"kind": "Application", "namespace": "argocd", "name": "shop"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# Application argocd/shop (OutOfSync, Missing, revision abc123): 1 of 1 checked objects drifted: 1 missing, 0 modified, 0 out of sync"), text)
+		s.Contains(text, "# Source: https://github.com/org/repo path apps/shop at main\n")
+		s.Regexp(`Missing\s+Deployment\.apps\s+shop\s+web\s+the object doesn't exist`, text)
+	})
+	s.Run("gitops_drift(name=shop) without kind", func() {
+		toolResult, err := s.CallTool("gitops_drift", map[string]interface{}{"name": "shop"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to detect GitOps drift, kind is required with name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *GitOpsSuite) TestGitOpsSync() {
+	s.InitMcpClient()
+	s.Run("gitops_sync(kind=Application, namespace=argocd, name=shop)", func() {
+		toolResult, err := s.CallTool("gitops_sync", map[string]interface{}{"kind": "Application", "namespace": "argocd", "name": "shop"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("Sync of Application argocd/shop requested, the controller applies the revision asynchronously, follow the progress with gitops_drift",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.True(s.patched)
+	})
+	s.Run("gitops_sync(kind=Kustomization, name=shop) when Flux is not installed", func() {
+		toolResult, err := s.CallTool("gitops_sync", map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system", "name": "shop"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "failed to sync Kustomization shop: "), toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestGitOps(t *testing.T) {
+	suite.Run(t, new(GitOpsSuite))
+}
@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 )
 
@@ -27,7 +31,7 @@ func ServerToolToGoSdkTool(s *Server, tool api.ServerTool) (*mcp.Tool, mcp.ToolH
 	if tool.Tool.InputSchema != nil {
 		schema, err := json.Marshal(tool.Tool.InputSchema)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to marshal tool input schema for tool %s: %v", tool.Tool.Name, err)
+			return nil, nil, fmt.Errorf("failed to marshal tool input schema for tool %s: %w", tool.Tool.Name, err)
 		}
 		// TODO: temporary fix to append an empty properties object (some client have trouble parsing a schema without properties)
 		// As opposed, Gemini had trouble for a while when properties was present but empty.
@@ -38,7 +42,7 @@ func ServerToolToGoSdkTool(s *Server, tool api.ServerTool) (*mcp.Tool, mcp.ToolH
 
 		var fixedSchema map[string]interface{}
 		if err := json.Unmarshal(schema, &fixedSchema); err != nil {
-			return nil, nil, fmt.Errorf("failed to unmarshal tool input schema for tool %s: %v", tool.Tool.Name, err)
+			return nil, nil, fmt.Errorf("failed to unmarshal tool input schema for tool %s: %w", tool.Tool.Name, err)
 		}
 
 		goSdkTool.InputSchema = fixedSchema
@@ -48,19 +52,7 @@ func ServerToolToGoSdkTool(s *Server, tool api.ServerTool) (*mcp.Tool, mcp.ToolH
 		if err != nil {
 			return nil, fmt.Errorf("%v for tool %s", err, tool.Tool.Name)
 		}
-		// get the correct derived Kubernetes client for the target specified in the request
-		cluster := toolCallRequest.GetString(s.p.GetTargetParameterName(), s.p.GetDefaultTarget())
-		k, err := s.p.GetDerivedKubernetes(ctx, cluster)
-		if err != nil {
-			return nil, err
-		}
-
-		result, err := tool.Handler(api.ToolHandlerParams{
-			Context:         ctx,
-			Kubernetes:      k,
-			ToolCallRequest: toolCallRequest,
-			ListOutput:      s.configuration.ListOutput(),
-		})
+		result, err := s.callTool(withProgress(ctx, request), request.Session, tool, toolCallRequest, false)
 		if err != nil {
 			return nil, err
 		}
@@ -87,7 +79,7 @@ func GoSdkToolCallRequestToToolCallRequest(request *mcp.CallToolRequest) (*ToolC
 func GoSdkToolCallParamsToToolCallRequest(toolCallParams *mcp.CallToolParamsRaw) (*ToolCallRequest, error) {
 	var arguments map[string]any
 	if err := json.Unmarshal(toolCallParams.Arguments, &arguments); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal tool call arguments: %v", err)
+		return nil, fmt.Errorf("failed to unmarshal tool call arguments: %w", err)
 	}
 	return &ToolCallRequest{
 		Name:      toolCallParams.Name,
@@ -107,3 +99,118 @@ func (ToolCallRequest *ToolCallRequest) GetString(key, defaultValue string) stri
 	}
 	return defaultValue
 }
+
+// callTool invokes the tool handler for the tool call request of the provided session.
+// Destructive tool calls that are not confirmed are put on hold when the server requires confirmation.
+func (s *Server) callTool(ctx context.Context, session *mcp.ServerSession, tool api.ServerTool, toolCallRequest *ToolCallRequest, confirmed bool) (*api.ToolCallResult, error) {
+	// the elevation may expire while the tool is still registered (or pending confirmation)
+	if !s.breakGlass.allows(tool) {
+		return api.NewToolCallResult("", api.NewCategorizedError(api.ErrorCategoryDeniedByConfig,
+			fmt.Errorf("tool %s requires a break-glass elevation, call %s first", tool.Tool.Name, api.ElevateToolName))), nil
+	}
+	// apply the defaults configured for the session (session_configure tool)
+	state := s.sessions.Get(session)
+	applySessionDefaults(tool, toolCallRequest, state)
+	defaultTarget := s.sessionTarget(ctx, state)
+	// get the correct derived Kubernetes client for the target specified in the request
+	cluster := defaultTarget
+	if tool.IsClusterAware() {
+		cluster = toolCallRequest.GetString(s.p.GetTargetParameterName(), defaultTarget)
+	}
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "cluster", cluster)
+	ctx = klog.NewContext(ctx, logger)
+
+	// policies: the mutating tool calls are checked before they're planned with dry-run, put on hold, or performed
+	if denied := s.evaluatePolicies(ctx, session, cluster, tool, toolCallRequest); denied != nil {
+		return denied, nil
+	}
+
+	// dry-run: tools supporting it run with Kubernetes server-side dry-run, the rest of the mutating tools are only described
+	dryRun := s.configuration.isDryRun(tool, toolCallRequest)
+	if dryRun && !tool.IsDryRunSupported() {
+		return dryRunSimulation(tool, toolCallRequest), nil
+	}
+	if dryRun {
+		ctx = internalk8s.WithDryRun(ctx)
+	} else if !confirmed && s.configuration.requiresConfirmation(tool) {
+		// the pending action is bound to the resolved target so that it doesn't change if the session defaults do
+		if tool.IsClusterAware() && s.p.GetTargetParameterName() != "" && cluster != "" {
+			if toolCallRequest.arguments == nil {
+				toolCallRequest.arguments = make(map[string]any)
+			}
+			toolCallRequest.arguments[s.p.GetTargetParameterName()] = cluster
+		}
+		return s.pendingActionResult(tool, toolCallRequest, session), nil
+	}
+
+	// cache: the results of the read-only tools with a TTL are reused by the calls with the same arguments
+	var result *api.ToolCallResult
+	cacheTTL := s.configuration.cacheTTL(tool)
+	cacheKey := resultCacheKey(sessionID(session), cluster, tool, toolCallRequest)
+	if cacheTTL > 0 && !isCacheBypassed(toolCallRequest) {
+		if cached, age := s.resultCache.Get(cacheKey); cached != nil {
+			logger.V(3).Info("mcp tool call served from cache", "age", age)
+			result = cachedToolCallResult(cached, age, cacheTTL, api.IsStructuredOutput(toolCallRequest))
+		}
+	}
+	if result == nil {
+		var err error
+		if result, err = s.invokeTool(ctx, session, cluster, tool, toolCallRequest); err != nil {
+			return nil, err
+		}
+		if cacheTTL > 0 {
+			s.resultCache.Set(cacheKey, result, cacheTTL)
+		}
+	}
+	// prevent credentials from reaching the model unless they were explicitly revealed
+	if !result.Revealed {
+		result.Content = output.Redact(result.Content)
+	}
+	// truncate large outputs, the remaining chunks are retrieved with continue_result
+	// (structured outputs are returned whole since a truncated JSON document can't be parsed)
+	if tool.Tool.Name != api.ContinueResultToolName && !api.IsStructuredOutput(toolCallRequest) {
+		result.Content = s.pager.Paginate(result.Content, sessionID(session))
+	}
+	if dryRun {
+		result = dryRunResult(result)
+	}
+	return result, nil
+}
+
+// invokeTool invokes the tool handler bounded by the tool timeout
+func (s *Server) invokeTool(ctx context.Context, session *mcp.ServerSession, cluster string, tool api.ServerTool, toolCallRequest *ToolCallRequest) (*api.ToolCallResult, error) {
+	logger := klog.FromContext(ctx)
+	k, err := s.derivedKubernetes(ctx, session, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// bound the tool call with the configured timeout, the handlers stop as soon as the context is done
+	timeout := s.configuration.ToolTimeout(tool.Tool.Name)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	result, err := tool.Handler(api.ToolHandlerParams{
+		Context:         withSession(ctx, session),
+		Kubernetes:      k,
+		ToolCallRequest: toolCallRequest,
+		ListOutput:      s.configuration.ListOutput(),
+		InputSchema:     tool.Tool.InputSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.Error = api.NewCategorizedError(api.ErrorCategoryTimeout,
+			fmt.Errorf("tool %s timed out after %s (timeouts.%s): %w", tool.Tool.Name, timeout, tool.Tool.Name, result.Error))
+	}
+	if result.Error != nil {
+		logger.V(1).Info("mcp tool call failed", "duration", time.Since(start), "error", api.ErrorCategoryOf(result.Error), "message", result.Error.Error())
+	} else {
+		logger.V(3).Info("mcp tool call completed", "duration", time.Since(start))
+	}
+	return result, nil
+}
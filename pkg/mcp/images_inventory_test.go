@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type ImagesInventorySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	scanner    *httptest.Server
+	scanned    []string
+}
+
+func (s *ImagesInventorySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.scanned = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-5d9c-x2k4p", OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d9c", UID: "rs-uid", Controller: ptr.To(true)},
+					}},
+					Spec: v1.PodSpec{Containers: []v1.Container{{Name: "nginx", Image: "docker.io/library/nginx:1.27", ImagePullPolicy: v1.PullIfNotPresent}}},
+					Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+						{Name: "nginx", ImageID: "docker.io/library/nginx@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"},
+					}},
+				}},
+			})
+		case "/apis/apps/v1/namespaces/ns-1/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSetList"},
+				Items: []appsv1.ReplicaSet{{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-5d9c", OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "web-uid", Controller: ptr.To(true)},
+				}}}},
+			})
+		}
+	}))
+	s.scanner = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.scanned = append(s.scanned, req.URL.Query().Get("image"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Results":[{"Target":"nginx","Vulnerabilities":[` +
+			`{"VulnerabilityID":"CVE-2024-0011","PkgName":"curl","InstalledVersion":"7.88.1","FixedVersion":"7.88.2","Severity":"CRITICAL"}]}]}`))
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *ImagesInventorySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+	s.scanner.Close()
+}
+
+func (s *ImagesInventorySuite) TestImagesInventory() {
+	s.InitMcpClient()
+	s.Run("images_inventory(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("images_inventory", map[string]interface{}{"namespace": "ns-1"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# 1 unique images used by 1 containers in namespace ns-1\n"), text)
+		s.Contains(text, "image: docker.io/library/nginx:1.27")
+		s.Contains(text, "- sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac")
+		s.Contains(text, "- IfNotPresent")
+		s.Contains(text, "- ns-1/Deployment/web")
+		s.Empty(s.scanned)
+	})
+	s.Run("images_inventory(vulnerabilities=true) without scanner returns error", func() {
+		toolResult, err := s.CallTool("images_inventory", map[string]interface{}{"namespace": "ns-1", "vulnerabilities": true})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to list images: no image scanner configured, set image_scanner.type and image_scanner.url in the configuration",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *ImagesInventorySuite) TestImagesInventoryVulnerabilities() {
+	s.Cfg.ImageScanner = config.ImageScanner{Type: config.ImageScannerTrivy, Url: s.scanner.URL}
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("images_inventory", map[string]interface{}{"namespace": "ns-1", "vulnerabilities": true})
+	s.Require().NoError(err)
+	s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+	text := toolResult.Content[0].(mcp.TextContent).Text
+	s.True(strings.HasPrefix(text, "# 1 unique images used by 1 containers in namespace ns-1, 1 known vulnerabilities (1 critical, 0 high) reported by trivy\n"), text)
+	s.Contains(text, "id: CVE-2024-0011")
+	s.Equal([]string{"docker.io/library/nginx@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"}, s.scanned)
+}
+
+func TestImagesInventory(t *testing.T) {
+	suite.Run(t, new(ImagesInventorySuite))
+}
@@ -0,0 +1,285 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type JobsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	jobs       map[string]*batchv1.Job
+	dryRun     []string
+}
+
+func (s *JobsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	failedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+	s.jobs = map[string]*batchv1.Job{
+		"backup-28000000": {
+			TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-28000000", Namespace: "default", OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "batch/v1", Kind: "CronJob", Name: "backup", Controller: ptr.To(true)},
+			}},
+			Spec: batchv1.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "backup-28000000"}}},
+			Status: batchv1.JobStatus{Failed: 1, Conditions: []batchv1.JobCondition{{
+				Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit", LastTransitionTime: failedAt,
+			}}},
+		},
+		"report-old": {
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+			ObjectMeta: metav1.ObjectMeta{Name: "report-old", Namespace: "default"},
+			Status: batchv1.JobStatus{Failed: 1, Conditions: []batchv1.JobCondition{{
+				Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded", LastTransitionTime: metav1.NewTime(time.Now().Add(-72 * time.Hour)),
+			}}},
+		},
+	}
+	s.dryRun = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		Groups: []string{
+			`{"name":"batch","versions":[{"groupVersion":"batch/v1","version":"v1"}],"preferredVersion":{"groupVersion":"batch/v1","version":"v1"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		const jobsPath = "/apis/batch/v1/namespaces/default/jobs"
+		switch {
+		case req.URL.Path == "/apis/batch/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"batch/v1","resources":[
+				{"name":"jobs","singularName":"","namespaced":true,"kind":"Job","verbs":["get","list","create"]},
+				{"name":"cronjobs","singularName":"","namespaced":true,"kind":"CronJob","verbs":["get","list"]}
+			]}`))
+		case req.URL.Path == jobsPath && req.Method == http.MethodPost:
+			body, _ := io.ReadAll(req.Body)
+			obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			job := obj.(*batchv1.Job)
+			job.TypeMeta = metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"}
+			job.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": job.Name}}
+			s.dryRun = append(s.dryRun, req.URL.Query().Get("dryRun"))
+			if req.URL.Query().Get("dryRun") == "" {
+				s.jobs[job.Name] = job
+			}
+			test.WriteObject(w, job)
+		case req.URL.Path == jobsPath || req.URL.Path == "/apis/batch/v1/jobs":
+			list := &batchv1.JobList{TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "JobList"}}
+			for _, name := range []string{"backup-28000000", "report-old"} {
+				list.Items = append(list.Items, *s.jobs[name])
+			}
+			test.WriteObject(w, list)
+		case strings.HasPrefix(req.URL.Path, jobsPath+"/"):
+			job, ok := s.jobs[strings.TrimPrefix(req.URL.Path, jobsPath+"/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			// the created Jobs complete immediately
+			if len(job.Status.Conditions) == 0 {
+				job.Status.Succeeded = 1
+				job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			}
+			test.WriteObject(w, job)
+		case req.URL.Path == "/apis/batch/v1/namespaces/default/cronjobs/backup":
+			test.WriteObject(w, &batchv1.CronJob{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+				ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", UID: "cronjob-uid"},
+				Spec: batchv1.CronJobSpec{
+					Schedule: "0 2 * * *",
+					JobTemplate: batchv1.JobTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "backup"}},
+						Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyOnFailure,
+							Containers:    []corev1.Container{{Name: "backup", Image: "backup:latest"}},
+						}}},
+					},
+				},
+			})
+		case req.URL.Path == "/api/v1/namespaces/default/pods":
+			name := strings.TrimPrefix(req.URL.Query().Get("labelSelector"), "job-name=")
+			container := "job"
+			if strings.HasPrefix(name, "backup") {
+				container = "backup"
+			}
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name + "-abcde", Namespace: "default"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: container}}},
+			}
+			if name == "backup-28000000" {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "backup", State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+				}}}
+			}
+			test.WriteObject(w, &corev1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}, Items: []corev1.Pod{pod}})
+		case strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/default/pods/") && strings.HasSuffix(req.URL.Path, "/log"):
+			_, _ = w.Write([]byte("output of " + req.URL.Query().Get("container") + "\n"))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *JobsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *JobsSuite) TestJobsRun() {
+	s.InitMcpClient()
+	s.Run("jobs(action=run, name=hello, image=busybox, command=[echo, hello])", func() {
+		toolResult, err := s.CallTool("jobs", map[string]interface{}{
+			"action": "run", "name": "hello", "image": "busybox", "command": []interface{}{"echo", "hello"},
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# Job default/hello Succeeded\n"), text)
+		s.Run("creates the job", func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.Require().Contains(s.jobs, "hello")
+			job := s.jobs["hello"]
+			s.Equal("kubernetes-mcp-server", job.Labels["app.kubernetes.io/managed-by"])
+			s.Equal(int32(0), *job.Spec.BackoffLimit)
+			s.Equal(corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+			s.Equal("busybox", job.Spec.Template.Spec.Containers[0].Image)
+			s.Equal([]string{"echo", "hello"}, job.Spec.Template.Spec.Containers[0].Command)
+		})
+		s.Run("returns the logs of the job pod", func() {
+			var status kubernetes.JobStatus
+			s.Require().NoError(yaml.Unmarshal([]byte(text), &status))
+			s.Equal(kubernetes.JobStatusSucceeded, status.Status)
+			s.Equal("hello-abcde", status.Pod)
+			s.Equal("output of job\n", status.Logs)
+		})
+	})
+	s.Run("jobs(action=run, wait=false)", func() {
+		toolResult, err := s.CallTool("jobs", map[string]interface{}{"action": "run", "name": "no-wait", "image": "busybox", "wait": false})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# Job default/no-wait created\n"), text)
+		s.NotContains(text, "logs:")
+	})
+	s.Run("jobs(action=run, dry_run=true) validates the job with server-side dry-run", func() {
+		toolResult, err := s.CallTool("jobs", map[string]interface{}{"action": "run", "name": "dry", "image": "busybox", "dry_run": true})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.NotContains(s.jobs, "dry")
+		s.Equal("All", s.dryRun[len(s.dryRun)-1])
+	})
+	s.Run("jobs(action=run) without image", func() {
+		toolResult, err := s.CallTool("jobs", map[string]interface{}{"action": "run"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to run job, missing argument image", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("jobs(action=invalid)", func() {
+		toolResult, err := s.CallTool("jobs", map[string]interface{}{"action": "invalid"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to run job, invalid argument action: enum: invalid does not equal any of: [run trigger]", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *JobsSuite) TestJobsTrigger() {
+	s.InitMcpClient()
+	s.Run("jobs(action=trigger, cronjob=backup)", func() {
+		toolResult, err := s.CallTool("jobs", map[string]interface{}{"action": "trigger", "cronjob": "backup"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var status kubernetes.JobStatus
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &status))
+		s.Regexp("^backup-manual-[a-z0-9]{5}$", status.Name)
+		s.Equal("backup", status.CronJob)
+		s.Equal("output of backup\n", status.Logs)
+		s.Run("creates the job from the cronjob template", func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.Require().Contains(s.jobs, status.Name)
+			job := s.jobs[status.Name]
+			s.Equal("manual", job.Annotations["cronjob.kubernetes.io/instantiate"])
+			s.Equal("backup", job.Labels["app"])
+			s.Equal("backup:latest", job.Spec.Template.Spec.Containers[0].Image)
+			s.Require().Len(job.OwnerReferences, 1)
+			s.Equal("CronJob", job.OwnerReferences[0].Kind)
+			s.True(*job.OwnerReferences[0].Controller)
+		})
+	})
+	s.Run("jobs(action=trigger) without cronjob", func() {
+		toolResult, err := s.CallTool("jobs", map[string]interface{}{"action": "trigger"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to run job, missing argument cronjob", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *JobsSuite) TestJobsFailures() {
+	s.InitMcpClient()
+	s.Run("jobs_failures(namespace=default)", func() {
+		toolResult, err := s.CallTool("jobs_failures", map[string]interface{}{"namespace": "default"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# 1 failed Jobs found in the last 24h0m0s\n"), text)
+		var failures []kubernetes.JobStatus
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &failures))
+		s.Require().Len(failures, 1)
+		s.Equal("backup-28000000", failures[0].Name)
+		s.Equal("backup", failures[0].CronJob)
+		s.Equal(kubernetes.JobStatusFailed, failures[0].Status)
+		s.Equal("BackoffLimitExceeded", failures[0].Reason)
+		s.Run("reports the container terminations of the job pods", func() {
+			s.Require().Len(failures[0].Terminations, 1)
+			s.Equal("backup-28000000-abcde", failures[0].Terminations[0].Pod)
+			s.Equal("OOMKilled", failures[0].Terminations[0].Reason)
+		})
+	})
+	s.Run("jobs_failures(since=0) lists all the failed jobs, most recent first", func() {
+		toolResult, err := s.CallTool("jobs_failures", map[string]interface{}{"since": "0"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var failures []kubernetes.JobStatus
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &failures))
+		s.Require().Len(failures, 2)
+		s.Equal("backup-28000000", failures[0].Name)
+		s.Equal("report-old", failures[1].Name)
+	})
+	s.Run("jobs_failures(since=invalid)", func() {
+		toolResult, err := s.CallTool("jobs_failures", map[string]interface{}{"since": "yesterday"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to list job failures, invalid since duration")
+	})
+}
+
+func TestJobs(t *testing.T) {
+	suite.Run(t, new(JobsSuite))
+}
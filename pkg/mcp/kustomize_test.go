@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"sigs.k8s.io/yaml"
+)
+
+type KustomizeSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	applied    map[string]string
+}
+
+const kustomizeDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+func (s *KustomizeSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.applied = map[string]string{}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPatch {
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		s.applied[req.URL.Path] = req.Header.Get("Content-Type")
+		jsonBody, err := yaml.YAMLToJSON(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jsonBody)
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *KustomizeSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *KustomizeSuite) TestKustomizeBuild() {
+	s.InitMcpClient()
+	s.Run("kustomize_build(kustomization=nil, url=nil)", func() {
+		toolResult, err := s.CallTool("kustomize_build", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to build kustomization, missing argument kustomization or url", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("kustomize_build(kustomization=invalid)", func() {
+		toolResult, err := s.CallTool("kustomize_build", map[string]interface{}{
+			"kustomization": "resources:\n- missing.yaml\n",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to build kustomization: ")
+	})
+	s.Run("kustomize_build(kustomization=valid, files=valid)", func() {
+		toolResult, err := s.CallTool("kustomize_build", map[string]interface{}{
+			"kustomization": "namespace: default\nnamePrefix: dev-\nresources:\n- deployment.yaml\n",
+			"files":         map[string]interface{}{"deployment.yaml": kustomizeDeployment},
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("returns rendered manifests", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, "name: dev-web")
+			s.Contains(text, "namespace: default")
+		})
+		s.Run("does not apply rendered manifests", func() {
+			s.Empty(s.applied)
+		})
+	})
+}
+
+func (s *KustomizeSuite) TestKustomizeReadOnly() {
+	s.Cfg.ReadOnly = true
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	s.Contains(names, "kustomize_build")
+	s.NotContains(names, "kustomize_apply")
+}
+
+func (s *KustomizeSuite) TestKustomizeApply() {
+	s.InitMcpClient()
+	s.Run("kustomize_apply(kustomization=nil, url=nil)", func() {
+		toolResult, err := s.CallTool("kustomize_apply", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to build kustomization, missing argument kustomization or url", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("kustomize_apply(kustomization=valid, files=valid)", func() {
+		toolResult, err := s.CallTool("kustomize_apply", map[string]interface{}{
+			"kustomization": "namespace: default\nnamePrefix: dev-\nresources:\n- deployment.yaml\n",
+			"files":         map[string]interface{}{"deployment.yaml": kustomizeDeployment},
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("applies rendered manifests with server-side apply", func() {
+			s.Equal("application/apply-patch+yaml", s.applied["/apis/apps/v1/namespaces/default/deployments/dev-web"])
+		})
+		s.Run("returns applied resources", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, "# The following resources (YAML) have been created or updated successfully")
+			s.Contains(text, "name: dev-web")
+		})
+	})
+}
+
+func TestKustomize(t *testing.T) {
+	suite.Run(t, new(KustomizeSuite))
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type LogsSearchSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *LogsSearchSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/pods":
+			pods := []v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web"},
+					Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "api"},
+					Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+			}
+			if req.URL.Query().Get("labelSelector") == "app=none" {
+				pods = nil
+			}
+			test.WriteObject(w, &v1.PodList{Items: pods})
+		case "/api/v1/namespaces/ns-1/pods/web/log":
+			_, _ = w.Write([]byte("GET / 200\nERROR connection refused\n"))
+		case "/api/v1/namespaces/ns-2/pods/api/log":
+			_, _ = w.Write([]byte("listening on :8080\n"))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *LogsSearchSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *LogsSearchSuite) TestLogsSearch() {
+	s.InitMcpClient()
+	s.Run("logs_search(pattern=nil)", func() {
+		toolResult, err := s.CallTool("logs_search", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to search logs, missing argument pattern", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("logs_search(pattern=connection refused)", func() {
+		toolResult, err := s.CallTool("logs_search", map[string]interface{}{"pattern": "connection refused"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# 1 matches in the last 100 lines of 2 containers of 2 pods\n"+
+			"[ns-1/web/app] ERROR connection refused\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("logs_search(pattern=connection refused, max_pods=1)", func() {
+		toolResult, err := s.CallTool("logs_search", map[string]interface{}{"pattern": "connection refused", "max_pods": 1})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+			"# 1 matches in the last 100 lines of 1 containers of 1 pods (1 pods not searched, narrow the namespace or label_selector or increase max_pods)\n")
+	})
+	s.Run("logs_search(pattern=(?i)error, output_format=json)", func() {
+		toolResult, err := s.CallTool("logs_search", map[string]interface{}{"pattern": "error", "ignore_case": true, "output_format": "json"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var envelope struct {
+			Kind  string `json:"kind"`
+			Items []struct {
+				Namespace string `json:"namespace"`
+				Pod       string `json:"pod"`
+				Container string `json:"container"`
+				Line      string `json:"line"`
+			} `json:"items"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("LogMatch", envelope.Kind)
+		s.Require().Len(envelope.Items, 1)
+		s.Equal("web", envelope.Items[0].Pod)
+		s.Equal("ERROR connection refused", envelope.Items[0].Line)
+	})
+	s.Run("logs_search(label_selector=app=none)", func() {
+		toolResult, err := s.CallTool("logs_search", map[string]interface{}{"pattern": "error", "label_selector": "app=none"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("No pods found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("logs_search(pattern=invalid)", func() {
+		toolResult, err := s.CallTool("logs_search", map[string]interface{}{"pattern": "error("})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, `failed to search logs: invalid pattern "error("`)
+	})
+}
+
+func TestLogsSearch(t *testing.T) {
+	suite.Run(t, new(LogsSearchSuite))
+}
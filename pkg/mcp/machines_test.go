@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type MachinesSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	scaled     bool
+}
+
+func (s *MachinesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.scaled = false
+	machine := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "Machine",
+		"metadata": map[string]any{"namespace": "default", "name": "workers-abcde", "labels": map[string]any{"cluster.x-k8s.io/set-name": "workers"}},
+		"spec": map[string]any{
+			"clusterName":       "prod",
+			"infrastructureRef": map[string]any{"kind": "AWSMachine", "name": "workers-abcde"},
+		},
+		"status": map[string]any{"phase": "Running", "nodeRef": map[string]any{"name": "node-1"}},
+	}}
+	machineSet := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "MachineSet",
+		"metadata": map[string]any{"namespace": "default", "name": "workers"},
+		"spec":     map[string]any{"clusterName": "prod", "replicas": int64(1)},
+		"status":   map[string]any{"replicas": int64(1), "readyReplicas": int64(1), "availableReplicas": int64(1)},
+	}}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{Groups: []string{
+		`{"name":"cluster.x-k8s.io","versions":[{"groupVersion":"cluster.x-k8s.io/v1beta1","version":"v1beta1"}],"preferredVersion":{"groupVersion":"cluster.x-k8s.io/v1beta1","version":"v1beta1"}}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/cluster.x-k8s.io/v1beta1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"cluster.x-k8s.io/v1beta1","resources":[
+				{"name":"machines","singularName":"machine","namespaced":true,"kind":"Machine","verbs":["get","list"]},
+				{"name":"machinesets","singularName":"machineset","namespaced":true,"kind":"MachineSet","verbs":["get","list","patch"]}
+			]}`))
+		case "/apis/cluster.x-k8s.io/v1beta1/machines":
+			list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "MachineList"}}
+			list.Items = []unstructured.Unstructured{*machine}
+			test.WriteObject(w, list)
+		case "/apis/cluster.x-k8s.io/v1beta1/namespaces/default/machines/workers-abcde":
+			test.WriteObject(w, machine)
+		case "/apis/cluster.x-k8s.io/v1beta1/namespaces/default/machinesets/workers":
+			test.WriteObject(w, machineSet)
+		case "/apis/cluster.x-k8s.io/v1beta1/namespaces/default/machinesets/workers/scale":
+			s.scaled = true
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"Scale","apiVersion":"autoscaling/v1","metadata":{"name":"workers","namespace":"default"},"spec":{"replicas":2}}`))
+		case "/api/v1/nodes/node-1":
+			test.WriteObject(w, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: map[string]string{
+				"cluster.x-k8s.io/machine":           "workers-abcde",
+				"cluster.x-k8s.io/cluster-namespace": "default",
+			}}})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"machines"}
+}
+
+func (s *MachinesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *MachinesSuite) TestMachinesList() {
+	s.InitMcpClient()
+	s.Run("machines_list() lists the Machines of the installed APIs", func() {
+		toolResult, err := s.CallTool("machines_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# 1 Machines (0 not running) in all namespaces, machine.openshift.io/v1beta1 is not installed\n"), text)
+		s.Regexp(`cluster\.x-k8s\.io\s+default\s+workers-abcde\s+workers\s+Running\s+node-1\s+AWSMachine/workers-abcde`, text)
+	})
+	s.Run("machines_for_node(node=node-1) returns the Machine and its MachineSet", func() {
+		toolResult, err := s.CallTool("machines_for_node", map[string]interface{}{"node": "node-1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# Node node-1 (Ready=Unknown) is backed by Machine default/workers-abcde (Running) of MachineSet workers (1 of 1 replicas ready)\n"), text)
+	})
+	s.Run("machines_for_node() without node", func() {
+		toolResult, err := s.CallTool("machines_for_node", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to get the machine of the node, missing argument node", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *MachinesSuite) TestMachineSetsScale() {
+	s.InitMcpClient()
+	s.Run("machinesets_scale(namespace=default, name=workers, replicas=2)", func() {
+		toolResult, err := s.CallTool("machinesets_scale", map[string]interface{}{"namespace": "default", "name": "workers", "replicas": 2})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# The MachineSet has been scaled successfully"), toolResult.Content[0].(mcp.TextContent).Text)
+		s.True(s.scaled)
+	})
+	s.Run("machinesets_scale(replicas=-1) is invalid", func() {
+		toolResult, err := s.CallTool("machinesets_scale", map[string]interface{}{"name": "workers", "replicas": -1})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid argument replicas")
+	})
+}
+
+func TestMachines(t *testing.T) {
+	suite.Run(t, new(MachinesSuite))
+}
@@ -6,15 +6,19 @@ import (
 	"net/http"
 	"os"
 	"slices"
+	"strings"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	authenticationapiv1 "k8s.io/api/authentication/v1"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
 	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/policy"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
 	"github.com/containers/kubernetes-mcp-server/pkg/version"
 )
@@ -27,17 +31,25 @@ type Configuration struct {
 	*config.StaticConfig
 	listOutput output.Output
 	toolsets   []api.Toolset
+	registry   *toolsets.ToolsetRegistry
 }
 
 func (c *Configuration) Toolsets() []api.Toolset {
 	if c.toolsets == nil {
 		for _, toolset := range c.StaticConfig.Toolsets {
-			c.toolsets = append(c.toolsets, toolsets.ToolsetFromString(toolset))
+			c.toolsets = append(c.toolsets, c.toolsetRegistry().ToolsetFromString(toolset))
 		}
 	}
 	return c.toolsets
 }
 
+func (c *Configuration) toolsetRegistry() *toolsets.ToolsetRegistry {
+	if c.registry == nil {
+		return toolsets.DefaultRegistry
+	}
+	return c.registry
+}
+
 func (c *Configuration) ListOutput() output.Output {
 	if c.listOutput == nil {
 		c.listOutput = output.FromString(c.StaticConfig.ListOutput)
@@ -46,37 +58,70 @@ func (c *Configuration) ListOutput() output.Output {
 }
 
 func (c *Configuration) isToolApplicable(tool api.ServerTool) bool {
-	if c.ReadOnly && !ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
-		return false
-	}
-	if c.DisableDestructive && ptr.Deref(tool.Tool.Annotations.DestructiveHint, false) {
+	if c.IsReadOnly() && !ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
 		return false
 	}
-	if c.EnabledTools != nil && !slices.Contains(c.EnabledTools, tool.Tool.Name) {
+	if c.IsDestructiveDisabled() && ptr.Deref(tool.Tool.Annotations.DestructiveHint, false) {
 		return false
 	}
-	if c.DisabledTools != nil && slices.Contains(c.DisabledTools, tool.Tool.Name) {
+	// node-level and cluster-wide tools (e.g. nodes_top, namespaces_delete) are hidden in namespace-scoped mode
+	if len(c.TargetNamespaces) > 0 && slices.ContainsFunc(tool.Permissions, func(p api.ResourcePermission) bool { return p.ClusterWide }) {
 		return false
 	}
-	return true
+	return c.IsToolEnabled(tool.Tool.Name)
 }
 
 type Server struct {
 	configuration *Configuration
 	server        *mcp.Server
 	enabledTools  []string
+	sessions      *SessionStore
+	confirmations *ConfirmationStore
+	rateLimiter   *RateLimiter
+	breakGlass    *BreakGlass
+	policies      []api.MutationPolicy
+	pager         *api.ResultPager
+	resultCache   *ResultCache
+	resourceURIs  []string
 	p             internalk8s.Provider
+	toolsMu       sync.RWMutex
+	tools         map[string]api.ServerTool
+	reloadMu      sync.Mutex
+}
+
+// ServerOption configures the optional dependencies of a Server.
+// Programs embedding the server can use them to provide their own toolsets or cluster provider.
+type ServerOption func(s *Server)
+
+// WithToolsetRegistry sets the registry used to resolve the configured toolset names.
+// Defaults to toolsets.DefaultRegistry (the built-in toolsets).
+func WithToolsetRegistry(registry *toolsets.ToolsetRegistry) ServerOption {
+	return func(s *Server) {
+		s.configuration.registry = registry
+	}
 }
 
-func NewServer(configuration Configuration) (*Server, error) {
+// WithProvider sets the provider used to access the Kubernetes clusters.
+// Defaults to the provider resolved from the configuration (ClusterProviderStrategy).
+func WithProvider(p internalk8s.Provider) ServerOption {
+	return func(s *Server) {
+		s.p = p
+	}
+}
+
+func NewServer(configuration Configuration, opts ...ServerOption) (*Server, error) {
 	s := &Server{
 		configuration: &configuration,
+		sessions:      NewSessionStore(),
+		confirmations: NewConfirmationStore(),
+		pager:         api.NewResultPager(configuration.OutputMaxBytes),
+		resultCache:   NewResultCache(),
 		server: mcp.NewServer(
 			&mcp.Implementation{
 				Name: version.BinaryName, Title: version.BinaryName, Version: version.Version,
 			},
 			&mcp.ServerOptions{
-				HasResources: false,
+				HasResources: true,
 				HasPrompts:   false,
 				HasTools:     true,
 			}),
@@ -84,26 +129,67 @@ func NewServer(configuration Configuration) (*Server, error) {
 
 	s.server.AddReceivingMiddleware(authHeaderPropagationMiddleware)
 	s.server.AddReceivingMiddleware(toolCallLoggingMiddleware)
+	s.rateLimiter = NewRateLimiter(configuration.ToolCallsPerMinute, configuration.ToolCallsBurst, configuration.MaxConcurrentDestructiveToolCalls)
+	if s.rateLimiter.enabled() {
+		s.server.AddReceivingMiddleware(s.rateLimitMiddleware)
+	}
+	if len(configuration.Policies) > 0 {
+		policies, err := policy.New(configuration.Policies)
+		if err != nil {
+			return nil, err
+		}
+		s.policies = append(s.policies, policies)
+	}
 	if configuration.RequireOAuth && false { // TODO: Disabled scope auth validation for now
 		s.server.AddReceivingMiddleware(toolScopedAuthorizationMiddleware)
 	}
 
-	var err error
-	s.p, err = internalk8s.NewProvider(s.configuration.StaticConfig)
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.configuration.toolsetRegistry().Validate(s.configuration.StaticConfig.Toolsets); err != nil {
+		return nil, err
+	}
+
+	// the elevations must be authenticated, by the break-glass token or by the OAuth token of the caller
+	if err := s.configuration.ValidateBreakGlass(); err != nil {
+		return nil, err
+	}
+	breakGlassDuration, err := s.configuration.BreakGlassDuration()
 	if err != nil {
 		return nil, err
 	}
+	s.breakGlass = NewBreakGlass(breakGlassDuration, s.configuration.BreakGlassToken)
+	if s.p == nil {
+		s.p, err = internalk8s.NewProvider(s.configuration.StaticConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, template := range resourceTemplates() {
+		s.server.AddResourceTemplate(template, s.readResource)
+	}
 	err = s.reloadToolsets()
 	if err != nil {
 		return nil, err
 	}
-	s.p.WatchTargets(s.reloadToolsets)
+	s.p.WatchTargets(s.reloadTargets)
 
 	return s, nil
 }
 
+// reloadTargets registers the tools of the changed targets, serialized with the configuration reloads
+func (s *Server) reloadTargets() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	return s.reloadToolsets()
+}
+
+// reloadToolsets registers the applicable tools, the callers (except NewServer) must hold reloadMu
 func (s *Server) reloadToolsets() error {
 	ctx := context.Background()
+	// the clients of the sessions may point to targets that changed or no longer exist
+	s.sessions.ResetKubernetes()
 
 	targets, err := s.p.GetTargets(ctx)
 	if err != nil {
@@ -115,10 +201,15 @@ func (s *Server) reloadToolsets() error {
 		ShouldIncludeTargetListTool(s.p.GetTargetParameterName(), targets),
 	)
 
-	mutator := WithTargetParameter(
-		s.p.GetDefaultTarget(),
-		s.p.GetTargetParameterName(),
-		targets,
+	mutator := CompositeMutator(
+		WithTargetParameter(
+			s.p.GetDefaultTarget(),
+			s.p.GetTargetParameterName(),
+			targets,
+		),
+		WithDryRunParameter(dryRunParameterName, s.configuration.DryRun),
+		WithCacheParameter(cacheParameterName, s.configuration.cacheTTL),
+		WithTargetNamespaces(s.configuration.TargetNamespaces),
 	)
 
 	// TODO: No option to perform a full replacement of tools.
@@ -130,17 +221,74 @@ func (s *Server) reloadToolsets() error {
 	// Build new list of applicable tools
 	applicableTools := make([]api.ServerTool, 0)
 	s.enabledTools = make([]string, 0)
+	elevatable := false
 	for _, toolset := range s.configuration.Toolsets() {
 		for _, tool := range toolset.GetTools(s.p) {
 			tool := mutator(tool)
 			if !filter(tool) {
 				continue
 			}
+			elevatable = elevatable || s.breakGlass.requiresElevation(tool)
+			if !s.breakGlass.allows(tool) {
+				continue
+			}
 
 			applicableTools = append(applicableTools, tool)
 			s.enabledTools = append(s.enabledTools, tool.Tool.Name)
 		}
 	}
+	// session tools only make sense when there are other tools to configure
+	for _, tool := range s.sessionTools(s.p.GetTargetParameterName(), targets) {
+		if len(applicableTools) == 0 || !s.configuration.isToolApplicable(tool) {
+			continue
+		}
+		applicableTools = append(applicableTools, tool)
+		s.enabledTools = append(s.enabledTools, tool.Tool.Name)
+	}
+	// the self check reports the capabilities of the other tools
+	for _, tool := range s.selfCheckTools() {
+		tool := mutator(tool)
+		if len(applicableTools) == 0 || !filter(tool) {
+			continue
+		}
+		applicableTools = append(applicableTools, tool)
+		s.enabledTools = append(s.enabledTools, tool.Tool.Name)
+	}
+
+	// break-glass tools only make sense when there are destructive tools to enable
+	if elevatable {
+		for _, tool := range s.breakGlassTools() {
+			if !s.configuration.isToolApplicable(tool) {
+				continue
+			}
+			applicableTools = append(applicableTools, tool)
+			s.enabledTools = append(s.enabledTools, tool.Tool.Name)
+		}
+	}
+	// confirmation tools only make sense when there are destructive tools to confirm
+	if slices.ContainsFunc(applicableTools, s.configuration.requiresConfirmation) {
+		for _, tool := range s.confirmationTools() {
+			applicableTools = append(applicableTools, tool)
+			s.enabledTools = append(s.enabledTools, tool.Tool.Name)
+		}
+	}
+	// pagination tools only make sense when the outputs of the other tools can be truncated
+	if s.pager.Enabled() && len(applicableTools) > 0 {
+		for _, tool := range s.paginationTools() {
+			if !s.configuration.isToolApplicable(tool) {
+				continue
+			}
+			applicableTools = append(applicableTools, tool)
+			s.enabledTools = append(s.enabledTools, tool.Tool.Name)
+		}
+	}
+	tools := make(map[string]api.ServerTool, len(applicableTools))
+	for _, tool := range applicableTools {
+		tools[tool.Tool.Name] = tool
+	}
+	s.toolsMu.Lock()
+	s.tools = tools
+	s.toolsMu.Unlock()
 
 	// TODO: No option to perform a full replacement of tools.
 	// Remove tools that are no longer applicable
@@ -155,13 +303,40 @@ func (s *Server) reloadToolsets() error {
 	for _, tool := range applicableTools {
 		goSdkTool, goSdkToolHandler, err := ServerToolToGoSdkTool(s, tool)
 		if err != nil {
-			return fmt.Errorf("failed to convert tool %s: %v", tool.Tool.Name, err)
+			return fmt.Errorf("failed to convert tool %s: %w", tool.Tool.Name, err)
 		}
 		s.server.AddTool(goSdkTool, goSdkToolHandler)
 	}
+	s.reloadResources(targets)
 	return nil
 }
 
+// ReloadConfiguration applies the config.ReloadableSettings of the reloaded configuration without restarting the
+// server: the tools are registered and unregistered, and the connected clients are notified of the change
+// (notifications/tools/list_changed). The denied resources apply to the next Kubernetes requests.
+// The reloaded configuration is validated first, the current configuration is kept if it's invalid.
+func (s *Server) ReloadConfiguration(reloaded *config.StaticConfig) error {
+	if err := s.configuration.toolsetRegistry().Validate(reloaded.Toolsets); err != nil {
+		return err
+	}
+	if reloaded.ToolProfile != "" && !slices.Contains(config.ToolProfiles, reloaded.ToolProfile) {
+		return fmt.Errorf("invalid tool profile: %s, valid profiles are: %s", reloaded.ToolProfile, strings.Join(config.ToolProfiles, ", "))
+	}
+	if err := reloaded.ValidateToolPatterns(); err != nil {
+		return err
+	}
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	changed := s.configuration.Reload(reloaded)
+	if len(changed) == 0 {
+		klog.V(1).Info("Configuration reloaded, no changes to apply")
+		return nil
+	}
+	klog.V(0).Infof("Configuration reloaded, applying the changed settings: %s", strings.Join(changed, ", "))
+	s.configuration.toolsets = nil
+	return s.reloadToolsets()
+}
+
 func (s *Server) ServeStdio(ctx context.Context) error {
 	return s.server.Run(ctx, &mcp.LoggingTransport{Transport: &mcp.StdioTransport{}, Writer: os.Stderr})
 }
@@ -199,8 +374,23 @@ func (s *Server) GetTargetParameterName() string {
 	return s.p.GetTargetParameterName()
 }
 
+// tool returns the registered tool with the provided name
+func (s *Server) tool(name string) (api.ServerTool, bool) {
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	tool, ok := s.tools[name]
+	return tool, ok
+}
+
 func (s *Server) GetEnabledTools() []string {
-	return s.enabledTools
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	return slices.Clone(s.enabledTools)
+}
+
+// Ready returns an error if the server can't serve tool calls (e.g. the API server of the default target is not ready)
+func (s *Server) Ready(ctx context.Context) error {
+	return s.p.Ready(ctx)
 }
 
 func (s *Server) Close() {
@@ -209,6 +399,8 @@ func (s *Server) Close() {
 	}
 }
 
+// NewTextResult returns the content as text, or the error message as text along with its category
+// (api.ErrorCategoryOf) in the error field of the structured content
 func NewTextResult(content string, err error) *mcp.CallToolResult {
 	if err != nil {
 		return &mcp.CallToolResult{
@@ -218,6 +410,9 @@ func NewTextResult(content string, err error) *mcp.CallToolResult {
 					Text: err.Error(),
 				},
 			},
+			StructuredContent: map[string]any{
+				"error": api.ErrorCategoryOf(err),
+			},
 		}
 	}
 	return &mcp.CallToolResult{
@@ -43,13 +43,33 @@ func (s *McpLoggingSuite) TestLogsToolCall() {
 	s.Require().NoError(err, "call to tool configuration_view failed")
 
 	s.Run("Logs tool name", func() {
-		s.Contains(s.logBuffer.String(), "mcp tool call: configuration_view(")
+		s.Regexp(`"mcp tool call" .*tool="configuration_view"`, s.logBuffer.String())
 	})
 	s.Run("Logs tool call arguments", func() {
-		expected := `"mcp tool call: configuration_view\((.+)\)"`
+		expected := `"mcp tool call" .* arguments=(.+)`
 		m := regexp.MustCompile(expected).FindStringSubmatch(s.logBuffer.String())
 		s.Len(m, 2, "Expected log entry to contain arguments")
-		s.Equal("map[minified:false]", m[1], "Expected log arguments to be 'map[minified:false]'")
+		s.Equal(`{"minified":false}`, m[1], `Expected log arguments to be '{"minified":false}'`)
+	})
+	s.Run("Logs generated request ID", func() {
+		s.Regexp(`"mcp tool call" requestId="[0-9a-f-]{36}" sessionId="[^"]+"`, s.logBuffer.String())
+	})
+	s.Run("Logs tool call completion with request ID and cluster", func() {
+		s.Regexp(`"mcp tool call completed" requestId="[0-9a-f-]{36}" sessionId="[^"]+" tool="configuration_view" cluster="[^"]*" duration="`, s.logBuffer.String())
+	})
+}
+
+func (s *McpLoggingSuite) TestLogsToolCallRequestID() {
+	s.SetLogLevel(5)
+	s.InitMcpClient(transport.WithHTTPHeaders(map[string]string{"X-Request-Id": "req-1337"}))
+	_, err := s.CallTool("pods_get", map[string]interface{}{"name": "not-found"})
+	s.Require().NoError(err, "call to tool pods_get failed")
+
+	s.Run("Logs provided request ID", func() {
+		s.Contains(s.logBuffer.String(), `"mcp tool call" requestId="req-1337"`)
+	})
+	s.Run("Logs tool call failure with error category", func() {
+		s.Regexp(`"mcp tool call failed" requestId="req-1337" .* error="not_found"`, s.logBuffer.String())
 	})
 }
 
@@ -65,7 +85,7 @@ func (s *McpLoggingSuite) TestLogsToolCallHeaders() {
 	s.Require().NoError(err, "call to tool configuration_view failed")
 
 	s.Run("Logs tool call headers", func() {
-		expectedLog := "mcp tool call headers: A-Loggable-Header: should-be-logged"
+		expectedLog := "\tA-Loggable-Header: should-be-logged"
 		s.Contains(s.logBuffer.String(), expectedLog, "Expected log to contain loggable header")
 	})
 	sensitiveHeaders := []string{
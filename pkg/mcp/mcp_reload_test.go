@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type ReloadConfigurationSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *ReloadConfigurationSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"core"}
+}
+
+func (s *ReloadConfigurationSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ReloadConfigurationSuite) reloaded(cfg string) *config.StaticConfig {
+	reloaded := config.Default()
+	reloaded.KubeConfig = s.Cfg.KubeConfig
+	s.Require().NoError(toml.Unmarshal([]byte(cfg), reloaded), "Expected to parse reloaded config")
+	return reloaded
+}
+
+func (s *ReloadConfigurationSuite) toolNames() []string {
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err, "call ListTools failed")
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func (s *ReloadConfigurationSuite) TestReloadsToolsets() {
+	s.InitMcpClient()
+	s.Require().NotContains(s.toolNames(), "configuration_view")
+	s.Run("registers the enabled toolsets and notifies the tools change", func() {
+		s.Require().NoError(s.mcpServer.ReloadConfiguration(s.reloaded(`toolsets = ["core", "config"]`)))
+		notification := s.WaitForNotification(5 * time.Second)
+		s.Equal("notifications/tools/list_changed", notification.Method, "ReloadConfiguration did not notify tools change")
+		s.Contains(s.toolNames(), "configuration_view")
+	})
+	s.Run("unregisters the disabled toolsets", func() {
+		s.Require().NoError(s.mcpServer.ReloadConfiguration(s.reloaded(`toolsets = ["config"]`)))
+		s.WaitForNotification(5 * time.Second)
+		names := s.toolNames()
+		s.Contains(names, "configuration_view")
+		s.NotContains(names, "pods_list")
+	})
+	s.Run("applies the tool filters", func() {
+		s.Require().NoError(s.mcpServer.ReloadConfiguration(s.reloaded(`
+			toolsets = ["core"]
+			disabled_tools = ["pods_list"]
+		`)))
+		s.WaitForNotification(5 * time.Second)
+		names := s.toolNames()
+		s.Contains(names, "pods_get")
+		s.NotContains(names, "pods_list")
+	})
+}
+
+func (s *ReloadConfigurationSuite) TestReloadsDeniedResources() {
+	s.InitMcpClient()
+	s.Require().NoError(s.mcpServer.ReloadConfiguration(s.reloaded(`
+		toolsets = ["core"]
+		denied_resources = [ { version = "v1", kind = "Pod" } ]
+	`)))
+	s.Run("pods_list (denied)", func() {
+		toolResult, err := s.CallTool("pods_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "resource not allowed: /v1, Kind=Pod")
+	})
+}
+
+func (s *ReloadConfigurationSuite) TestKeepsConfigurationIfInvalid() {
+	s.InitMcpClient()
+	err := s.mcpServer.ReloadConfiguration(s.reloaded(`toolsets = ["core", "invalid"]`))
+	s.Require().Error(err)
+	s.Contains(err.Error(), "invalid toolset name: invalid")
+	s.Equal([]string{"core"}, s.Cfg.Toolsets)
+	s.Contains(s.toolNames(), "pods_list")
+}
+
+func TestReloadConfiguration(t *testing.T) {
+	suite.Run(t, new(ReloadConfigurationSuite))
+}
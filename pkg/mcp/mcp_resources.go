@@ -0,0 +1,266 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	// resourceURIScheme is the scheme of the URIs of the Kubernetes objects exposed as MCP resources:
+	// k8s://<context>/namespaces[/<name>], k8s://<context>/nodes[/<name>], k8s://<context>/<namespace>/pods[/<name>]
+	// and k8s://<context>/<namespace>/events
+	resourceURIScheme = "k8s://"
+	// resourceDefaultContext is the context of the resource URIs when the provider doesn't support multiple targets
+	resourceDefaultContext = "default"
+	resourceMIMEType       = "application/yaml"
+)
+
+// resourceGVKs are the kinds of the objects that can be read by URI, by plural resource name
+var resourceGVKs = map[string]*schema.GroupVersionKind{
+	"namespaces": {Group: "", Version: "v1", Kind: "Namespace"},
+	"nodes":      {Group: "", Version: "v1", Kind: "Node"},
+	"pods":       {Group: "", Version: "v1", Kind: "Pod"},
+}
+
+// resourceTemplates returns the MCP resource templates of the Kubernetes objects that can be read by URI
+func resourceTemplates() []*mcp.ResourceTemplate {
+	return []*mcp.ResourceTemplate{
+		{
+			Name:        "namespace",
+			Title:       "Namespace",
+			URITemplate: resourceURIScheme + "{context}/namespaces/{name}",
+			Description: "Kubernetes Namespace in the provided context (kubeconfig context or cluster)",
+			MIMEType:    resourceMIMEType,
+		},
+		{
+			Name:        "node",
+			Title:       "Node",
+			URITemplate: resourceURIScheme + "{context}/nodes/{name}",
+			Description: "Kubernetes Node in the provided context (kubeconfig context or cluster)",
+			MIMEType:    resourceMIMEType,
+		},
+		{
+			Name:        "pods",
+			Title:       "Pods",
+			URITemplate: resourceURIScheme + "{context}/{namespace}/pods",
+			Description: "Kubernetes Pods in the provided namespace and context (kubeconfig context or cluster)",
+			MIMEType:    resourceMIMEType,
+		},
+		{
+			Name:        "pod",
+			Title:       "Pod",
+			URITemplate: resourceURIScheme + "{context}/{namespace}/pods/{name}",
+			Description: "Kubernetes Pod in the provided namespace and context (kubeconfig context or cluster)",
+			MIMEType:    resourceMIMEType,
+		},
+		{
+			Name:        "events",
+			Title:       "Events",
+			URITemplate: resourceURIScheme + "{context}/{namespace}/events",
+			Description: "Kubernetes Events in the provided namespace and context (kubeconfig context or cluster)",
+			MIMEType:    resourceMIMEType,
+		},
+		{
+			Name:        "artifact",
+			Title:       "Artifact",
+			URITemplate: artifacts.URIScheme + "{name}",
+			Description: "File generated by a tool in the server-side artifact store (diagnostic bundle, node file, or log), listed by artifacts_list",
+		},
+	}
+}
+
+// targetResources returns the MCP resources listing the cluster-scoped objects of the provided target
+func targetResources(target string) []*mcp.Resource {
+	contextName := resourceContext(target)
+	return []*mcp.Resource{
+		{
+			Name:        "namespaces/" + contextName,
+			Title:       "Namespaces (" + contextName + ")",
+			URI:         resourceURIScheme + escapeResourceURISegment(contextName) + "/namespaces",
+			Description: "Kubernetes Namespaces in the " + contextName + " context",
+			MIMEType:    resourceMIMEType,
+		},
+		{
+			Name:        "nodes/" + contextName,
+			Title:       "Nodes (" + contextName + ")",
+			URI:         resourceURIScheme + escapeResourceURISegment(contextName) + "/nodes",
+			Description: "Kubernetes Nodes in the " + contextName + " context",
+			MIMEType:    resourceMIMEType,
+		},
+	}
+}
+
+// reloadResources registers the MCP resources of the provided targets and removes the ones of the stale targets.
+// Resources are only (re-)added when they change since every change is notified to the clients.
+func (s *Server) reloadResources(targets []string) {
+	previousResources := s.resourceURIs
+	s.resourceURIs = make([]string, 0, 2*len(targets))
+	for _, target := range targets {
+		for _, resource := range targetResources(target) {
+			s.resourceURIs = append(s.resourceURIs, resource.URI)
+			if !slices.Contains(previousResources, resource.URI) {
+				s.server.AddResource(resource, s.readResource)
+			}
+		}
+	}
+	resourcesToRemove := make([]string, 0)
+	for _, uri := range previousResources {
+		if !slices.Contains(s.resourceURIs, uri) {
+			resourcesToRemove = append(resourcesToRemove, uri)
+		}
+	}
+	if len(resourcesToRemove) > 0 {
+		s.server.RemoveResources(resourcesToRemove...)
+	}
+}
+
+// readResource returns the YAML representation of the Kubernetes object (or objects) referenced by the resource URI
+func (s *Server) readResource(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := request.Params.URI
+	if strings.HasPrefix(uri, artifacts.URIScheme) {
+		return s.readArtifact(uri)
+	}
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	target := ref.context
+	if s.p.GetTargetParameterName() == "" {
+		if ref.context != resourceDefaultContext {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		target = ""
+	}
+	k, err := s.derivedKubernetes(ctx, request.Session, target)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := ref.read(ctx, k)
+	if apierrors.IsNotFound(err) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource %s: %w", uri, err)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+		URI:      uri,
+		MIMEType: resourceMIMEType,
+		Text:     output.Redact(ret),
+	}}}, nil
+}
+
+// readArtifact returns the content of the artifact referenced by the artifact:// URI, the text artifacts are redacted
+func (s *Server) readArtifact(uri string) (*mcp.ReadResourceResult, error) {
+	name, ok := artifacts.NameFromURI(uri)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	artifact, content, err := artifacts.NewStore(s.configuration.StaticConfig).Get(name)
+	if errors.Is(err, artifacts.ErrNotFound) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource %s: %w", uri, err)
+	}
+	contents := &mcp.ResourceContents{URI: uri, MIMEType: artifact.MIMEType}
+	if artifacts.IsText(artifact.MIMEType) {
+		contents.Text = output.Redact(string(content))
+	} else {
+		contents.Blob = content
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+}
+
+// resourceURI is a parsed k8s:// resource URI
+type resourceURI struct {
+	context   string
+	namespace string
+	// resource is the plural name of the referenced resource type (namespaces, nodes, pods or events)
+	resource string
+	name     string
+}
+
+func parseResourceURI(uri string) (*resourceURI, error) {
+	path, ok := strings.CutPrefix(uri, resourceURIScheme)
+	if !ok {
+		return nil, fmt.Errorf("unsupported resource URI %s", uri)
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil || unescaped == "" {
+			return nil, fmt.Errorf("invalid resource URI %s", uri)
+		}
+		segments[i] = unescaped
+	}
+	ref := &resourceURI{context: segments[0]}
+	switch {
+	// cluster-scoped resources take precedence over the namespaces named after them
+	case len(segments) == 2 && (segments[1] == "namespaces" || segments[1] == "nodes"):
+		ref.resource = segments[1]
+	case len(segments) == 3 && (segments[1] == "namespaces" || segments[1] == "nodes"):
+		ref.resource, ref.name = segments[1], segments[2]
+	case len(segments) == 3 && (segments[2] == "pods" || segments[2] == "events"):
+		ref.namespace, ref.resource = segments[1], segments[2]
+	case len(segments) == 4 && segments[2] == "pods":
+		ref.namespace, ref.resource, ref.name = segments[1], segments[2], segments[3]
+	default:
+		return nil, fmt.Errorf("unsupported resource URI %s", uri)
+	}
+	return ref, nil
+}
+
+func (r *resourceURI) read(ctx context.Context, k *internalk8s.Kubernetes) (string, error) {
+	if r.resource == "events" {
+		events, err := k.EventsList(ctx, r.namespace)
+		if err != nil {
+			return "", err
+		}
+		if events == nil {
+			events = []map[string]any{}
+		}
+		return output.MarshalYaml(events)
+	}
+	gvk, ok := resourceGVKs[r.resource]
+	if !ok {
+		return "", errors.New("unsupported resource " + r.resource)
+	}
+	if r.name == "" {
+		list, err := k.ResourcesList(ctx, gvk, r.namespace, internalk8s.ResourceListOptions{})
+		if err != nil {
+			return "", err
+		}
+		return output.MarshalYaml(list)
+	}
+	obj, err := k.ResourcesGet(ctx, gvk, r.namespace, r.name)
+	if err != nil {
+		return "", err
+	}
+	return output.MarshalYaml(obj)
+}
+
+// resourceContext returns the context of the resource URIs of the provided target
+func resourceContext(target string) string {
+	if target == "" {
+		return resourceDefaultContext
+	}
+	return target
+}
+
+// escapeResourceURISegment escapes the characters of context names (e.g. ARNs or OpenShift contexts) that are not
+// allowed in the URI template variables
+func escapeResourceURISegment(segment string) string {
+	return strings.ReplaceAll(url.QueryEscape(segment), "+", "%20")
+}
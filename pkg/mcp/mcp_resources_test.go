@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type McpResourcesSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *McpResourcesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: []string{
+		`{"name":"namespaces","singularName":"","namespaced":false,"kind":"Namespace","verbs":["get","list","watch"]}`,
+		`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"},
+				Items: []v1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+				},
+			})
+		case "/api/v1/namespaces/ns-1":
+			test.WriteObject(w, &v1.Namespace{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+				ObjectMeta: metav1.ObjectMeta{Name: "ns-1"},
+			})
+		case "/api/v1/namespaces/ns-1/pods/pod-1":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "ns-1"},
+				Spec: v1.PodSpec{Containers: []v1.Container{{
+					Name: "app",
+					Env:  []v1.EnvVar{{Name: "DB_PASSWORD", Value: "s3cr3t"}},
+				}}},
+			})
+		case "/api/v1/namespaces/ns-1/events":
+			test.WriteObject(w, &v1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []v1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "event-1", Namespace: "ns-1"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod-1"},
+					Type:           "Warning",
+					Reason:         "BackOff",
+					Message:        "Back-off restarting failed container",
+				}},
+			})
+		case "/api/v1/namespaces/ns-1/pods/pod-1/log":
+			_, _ = w.Write([]byte("starting\nDB_PASSWORD=s3cr3t\nlistening on :8080\n"))
+		case "/api/v1/namespaces/ns-1/pods/missing-pod":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *McpResourcesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *McpResourcesSuite) readResource(uri string) (*mcp.ReadResourceResult, error) {
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	return s.ReadResource(s.T().Context(), request)
+}
+
+func (s *McpResourcesSuite) TestListResources() {
+	s.InitMcpClient()
+	result, err := s.ListResources(s.T().Context(), mcp.ListResourcesRequest{})
+	s.Require().NoError(err)
+	s.Run("lists the cluster-scoped collections of each context", func() {
+		uris := make([]string, 0, len(result.Resources))
+		for _, resource := range result.Resources {
+			uris = append(uris, resource.URI)
+		}
+		s.ElementsMatch([]string{"k8s://fake-context/namespaces", "k8s://fake-context/nodes"}, uris)
+	})
+}
+
+func (s *McpResourcesSuite) TestListResourceTemplates() {
+	s.InitMcpClient()
+	result, err := s.ListResourceTemplates(s.T().Context(), mcp.ListResourceTemplatesRequest{})
+	s.Require().NoError(err)
+	s.Run("lists the namespace, node, pod, event and artifact templates", func() {
+		templates := make([]string, 0, len(result.ResourceTemplates))
+		for _, template := range result.ResourceTemplates {
+			templates = append(templates, template.URITemplate.Raw())
+		}
+		s.ElementsMatch([]string{
+			"k8s://{context}/namespaces/{name}",
+			"k8s://{context}/nodes/{name}",
+			"k8s://{context}/{namespace}/pods",
+			"k8s://{context}/{namespace}/pods/{name}",
+			"k8s://{context}/{namespace}/events",
+			"artifact://{name}",
+		}, templates)
+	})
+}
+
+func (s *McpResourcesSuite) TestReadResource() {
+	s.InitMcpClient()
+	s.Run("k8s://fake-context/nodes returns the nodes", func() {
+		result, err := s.readResource("k8s://fake-context/nodes")
+		s.Require().NoError(err)
+		s.Require().Len(result.Contents, 1)
+		contents := result.Contents[0].(mcp.TextResourceContents)
+		s.Equal("application/yaml", contents.MIMEType)
+		s.Contains(contents.Text, "name: node-1")
+		s.Contains(contents.Text, "name: node-2")
+	})
+	s.Run("k8s://fake-context/namespaces/ns-1 returns the namespace", func() {
+		result, err := s.readResource("k8s://fake-context/namespaces/ns-1")
+		s.Require().NoError(err)
+		s.Contains(result.Contents[0].(mcp.TextResourceContents).Text, "kind: Namespace")
+	})
+	s.Run("k8s://fake-context/ns-1/pods/pod-1 returns the pod", func() {
+		result, err := s.readResource("k8s://fake-context/ns-1/pods/pod-1")
+		s.Require().NoError(err)
+		text := result.Contents[0].(mcp.TextResourceContents).Text
+		s.Contains(text, "kind: Pod")
+		s.Contains(text, "name: pod-1")
+		s.Run("redacts credentials", func() {
+			s.NotContains(text, "s3cr3t")
+			s.Contains(text, "value: <redacted>")
+		})
+	})
+	s.Run("k8s://fake-context/ns-1/events returns the events", func() {
+		result, err := s.readResource("k8s://fake-context/ns-1/events")
+		s.Require().NoError(err)
+		text := result.Contents[0].(mcp.TextResourceContents).Text
+		s.Contains(text, "Reason: BackOff")
+		s.Contains(text, "Message: Back-off restarting failed container")
+	})
+	s.Run("k8s://fake-context/ns-1/pods/missing-pod returns not found", func() {
+		_, err := s.readResource("k8s://fake-context/ns-1/pods/missing-pod")
+		s.ErrorContains(err, "Resource not found")
+	})
+	s.Run("k8s://other-context/ns-1/pods/pod-1 returns error", func() {
+		_, err := s.readResource("k8s://other-context/ns-1/pods/pod-1")
+		s.Error(err)
+	})
+	s.Run("k8s://fake-context/ns-1/secrets/secret-1 returns not found", func() {
+		_, err := s.readResource("k8s://fake-context/ns-1/secrets/secret-1")
+		s.ErrorContains(err, "Resource not found")
+	})
+}
+
+func (s *McpResourcesSuite) TestReadResourceDeniedResources() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		denied_resources = [ { version = "v1", kind = "Pod" } ]
+	`), s.Cfg), "Expected to parse denied resources config")
+	s.InitMcpClient()
+	s.Run("k8s://fake-context/ns-1/pods/pod-1 returns error", func() {
+		_, err := s.readResource("k8s://fake-context/ns-1/pods/pod-1")
+		s.ErrorContains(err, "resource not allowed: /v1, Kind=Pod")
+	})
+}
+
+func (s *McpResourcesSuite) TestReadArtifact() {
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		[artifacts]
+		dir = "` + filepath.ToSlash(s.T().TempDir()) + `"
+	`)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("pods_log", map[string]interface{}{"namespace": "ns-1", "name": "pod-1", "artifact": true})
+	s.Require().NoError(err)
+	s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	text := toolResult.Content[0].(mcp.TextContent).Text
+	uri := regexp.MustCompile(`artifact://[a-z0-9.-]+\.log`).FindString(text)
+	s.Run("pods_log(artifact=true) returns the artifact URI and a preview", func() {
+		s.Regexp(`^# Full log \(3 lines, \d+ bytes\) written to artifact://pod-log-ns-1-pod-1-\d{8}-\d{6}-[0-9a-f]{8}\.log, `+
+			`read the MCP resource to get the whole log\n# Last 3 lines:\nstarting\n`, text)
+	})
+	s.Run("artifact:// returns the redacted log", func() {
+		result, err := s.readResource(uri)
+		s.Require().NoError(err)
+		s.Require().Len(result.Contents, 1)
+		contents := result.Contents[0].(mcp.TextResourceContents)
+		s.Equal("text/plain", contents.MIMEType)
+		s.Equal("starting\nDB_PASSWORD=<redacted>\nlistening on :8080\n", contents.Text)
+	})
+	s.Run("artifact:// with a missing artifact returns error", func() {
+		_, err := s.readResource("artifact://missing.log")
+		s.ErrorContains(err, "Resource not found")
+	})
+}
+
+func TestParseResourceURI(t *testing.T) {
+	cases := map[string]*resourceURI{
+		"k8s://ctx/namespaces":             {context: "ctx", resource: "namespaces"},
+		"k8s://ctx/nodes/node-1":           {context: "ctx", resource: "nodes", name: "node-1"},
+		"k8s://ctx/namespaces/events":      {context: "ctx", resource: "namespaces", name: "events"},
+		"k8s://ctx/ns-1/events":            {context: "ctx", namespace: "ns-1", resource: "events"},
+		"k8s://ctx/ns-1/pods/pod-1":        {context: "ctx", namespace: "ns-1", resource: "pods", name: "pod-1"},
+		"k8s://a%3Ab%2Fc%20d/ns-1/pods":    {context: "a:b/c d", namespace: "ns-1", resource: "pods"},
+		"k8s://ctx/ns-1/secrets/secret-1":  nil,
+		"k8s://ctx//pods/pod-1":            nil,
+		"file:///ctx/ns-1/pods/pod-1":      nil,
+		"k8s://ctx/ns-1/pods/pod-1/status": nil,
+	}
+	for uri, expected := range cases {
+		t.Run(uri, func(t *testing.T) {
+			ref, err := parseResourceURI(uri)
+			if expected == nil {
+				if err == nil {
+					t.Fatalf("expected error parsing %s, got %v", uri, ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error parsing %s: %v", uri, err)
+			}
+			if *ref != *expected {
+				t.Errorf("expected %v, got %v", expected, ref)
+			}
+		})
+	}
+	t.Run("escaped contexts round trip", func(t *testing.T) {
+		context := "arn:aws:eks:us-east-1:123456789012:cluster/my cluster"
+		ref, err := parseResourceURI("k8s://" + escapeResourceURISegment(context) + "/nodes")
+		if err != nil || ref.context != context {
+			t.Errorf("expected context %s, got %v (%v)", context, ref, err)
+		}
+	})
+}
+
+func TestMcpResources(t *testing.T) {
+	suite.Run(t, new(McpResourcesSuite))
+}
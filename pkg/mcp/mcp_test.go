@@ -94,6 +94,23 @@ func (s *McpHeadersSuite) TestAuthorizationHeaderPropagation() {
 	}
 }
 
+func (s *McpHeadersSuite) TestImpersonationHeadersRejected() {
+	s.Cfg.Impersonation = true
+	s.InitMcpClient(transport.WithHTTPHeaders(map[string]string{
+		"kubernetes-impersonate-user":  "alice",
+		"kubernetes-impersonate-group": "system:masters",
+	}))
+	_, err := s.CallTool("pods_list", map[string]interface{}{})
+	s.Run("rejects the call", func() {
+		s.ErrorContains(err, "impersonation requires a verified oauth token, require_oauth must be enabled")
+	})
+	s.Run("doesn't impersonate the identity of the headers", func() {
+		s.pathHeadersMux.Lock()
+		defer s.pathHeadersMux.Unlock()
+		s.Nil(s.pathHeaders["/api/v1/namespaces/default/pods"], "No requests should be made to /api/v1/namespaces/default/pods")
+	})
+}
+
 func TestMcpHeaders(t *testing.T) {
 	suite.Run(t, new(McpHeadersSuite))
 }
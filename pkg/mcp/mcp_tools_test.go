@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/BurntSushi/toml"
@@ -80,6 +81,56 @@ func (s *McpToolProcessingSuite) TestDisableDestructive() {
 	})
 }
 
+func (s *McpToolProcessingSuite) TestToolProfileReadOnly() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		tool_profile = "read-only"
+	`), s.Cfg), "Expected to parse tool profile server config")
+	s.InitMcpClient()
+
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NotNil(tools)
+
+	s.Run("ListTools returns tools", func() {
+		s.NoError(err, "call ListTools failed")
+		s.NotNilf(tools, "list tools failed")
+	})
+
+	s.Run("ListTools returns only read-only tools", func() {
+		for _, tool := range tools.Tools {
+			s.Truef(ptr.Deref(tool.Annotations.ReadOnlyHint, false),
+				"Tool %s is not read-only but is exposed in the read-only profile", tool.Name)
+		}
+	})
+}
+
+func (s *McpToolProcessingSuite) TestToolProfileOperator() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		tool_profile = "operator"
+	`), s.Cfg), "Expected to parse tool profile server config")
+	s.InitMcpClient()
+
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NotNil(tools)
+
+	s.Run("ListTools returns tools", func() {
+		s.NoError(err, "call ListTools failed")
+		s.NotNilf(tools, "list tools failed")
+	})
+
+	s.Run("ListTools returns non-destructive write tools", func() {
+		s.Truef(slices.ContainsFunc(tools.Tools, func(tool mcp.Tool) bool {
+			return !ptr.Deref(tool.Annotations.ReadOnlyHint, false)
+		}), "Expected at least one write tool in operator profile")
+	})
+
+	s.Run("ListTools does not return destructive tools", func() {
+		for _, tool := range tools.Tools {
+			s.Falsef(ptr.Deref(tool.Annotations.DestructiveHint, false),
+				"Tool %s is destructive but should not be in operator profile", tool.Name)
+		}
+	})
+}
+
 func (s *McpToolProcessingSuite) TestEnabledTools() {
 	s.Require().NoError(toml.Unmarshal([]byte(`
 		enabled_tools = [ "namespaces_list", "events_list" ]
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type MetricsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	prometheus *httptest.Server
+	mu         sync.Mutex
+	paths      []string
+	forms      []url.Values
+}
+
+func (s *MetricsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.paths, s.forms = nil, nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.prometheus = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		s.mu.Lock()
+		s.paths = append(s.paths, req.URL.Path)
+		s.forms = append(s.forms, form)
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if form.Get("query") == "up{" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"1:4: parse error: unexpected end of input"}`))
+			return
+		}
+		if req.URL.Path == "/api/v1/query_range" {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{"node":"node-1"},"values":[[1735722000,"0.25"],[1735725600,"0.5"]]}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+			`{"metric":{"node":"node-1"},"value":[1735725600,"0.5"]}]}}`))
+	}))
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		toolsets = ["metrics"]
+		list_output = "yaml"
+		[toolset_configs.metrics]
+		url = "` + s.prometheus.URL + `"
+	`)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *MetricsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	s.prometheus.Close()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *MetricsSuite) lastRequest() (string, url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Require().NotEmpty(s.paths)
+	return s.paths[len(s.paths)-1], s.forms[len(s.forms)-1]
+}
+
+func (s *MetricsSuite) TestMetricsQuery() {
+	s.InitMcpClient()
+	s.Run("metrics_query(query=node_load1)", func() {
+		toolResult, err := s.CallTool("metrics_query", map[string]interface{}{
+			"query": "node_load1",
+			"time":  "2025-01-01T10:00:00Z",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("returns the samples", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, "resultType: vector")
+			s.Contains(text, "node: node-1")
+		})
+		s.Run("queries the instant query endpoint at the provided time", func() {
+			path, form := s.lastRequest()
+			s.Equal("/api/v1/query", path)
+			s.Equal("node_load1", form.Get("query"))
+			s.Equal("1735725600", form.Get("time"))
+		})
+	})
+	s.Run("metrics_query(query=invalid)", func() {
+		toolResult, err := s.CallTool("metrics_query", map[string]interface{}{"query": "up{"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to query metrics: prometheus API error (bad_data): 1:4: parse error: unexpected end of input",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("metrics_query(time=invalid)", func() {
+		toolResult, err := s.CallTool("metrics_query", map[string]interface{}{"query": "up", "time": "yesterday"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, `failed to query metrics, invalid argument time: "yesterday" is not a valid time`)
+	})
+}
+
+func (s *MetricsSuite) TestMetricsRangeQuery() {
+	s.InitMcpClient()
+	s.Run("metrics_range_query(start, end, step)", func() {
+		toolResult, err := s.CallTool("metrics_range_query", map[string]interface{}{
+			"query": "node_load1",
+			"start": "1735722000",
+			"end":   "2025-01-01T10:00:00Z",
+			"step":  "5m",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("returns the series", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, "resultType: matrix")
+			s.Contains(text, "\"0.25\"")
+		})
+		s.Run("queries the range query endpoint", func() {
+			path, form := s.lastRequest()
+			s.Equal("/api/v1/query_range", path)
+			s.Equal("1735722000", form.Get("start"))
+			s.Equal("1735725600", form.Get("end"))
+			s.Equal("5m", form.Get("step"))
+		})
+	})
+	s.Run("metrics_range_query(defaults)", func() {
+		toolResult, err := s.CallTool("metrics_range_query", map[string]interface{}{"query": "node_load1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		_, form := s.lastRequest()
+		start, _ := strconv.ParseFloat(form.Get("start"), 64)
+		end, _ := strconv.ParseFloat(form.Get("end"), 64)
+		s.Run("queries the last hour", func() {
+			s.InDelta(float64(time.Now().Unix()), end, 60)
+			s.InDelta(3600, end-start, 0.001)
+		})
+		s.Run("divides the range in 120 points", func() {
+			s.Equal("30", form.Get("step"))
+		})
+	})
+	s.Run("metrics_range_query(start=-2h, end=-3h)", func() {
+		toolResult, err := s.CallTool("metrics_range_query", map[string]interface{}{"query": "up", "start": "-2h", "end": "-3h"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Regexp(`^failed to query metrics range, start \S+ must be before end \S+$`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, new(MetricsSuite))
+}
@@ -8,12 +8,18 @@ import (
 
 	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog/v2"
 )
 
 func authHeaderPropagationMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
 		if req.GetExtra() != nil && req.GetExtra().Header != nil {
+			// Default kubeconfig context of the connection
+			if target := req.GetExtra().Header.Get(string(internalk8s.ContextHeader)); target != "" {
+				ctx = context.WithValue(ctx, internalk8s.ContextHeader, target)
+			}
+
 			// Get the standard Authorization header (OAuth compliant)
 			authHeader := req.GetExtra().Header.Get(string(internalk8s.OAuthAuthorizationHeader))
 			if authHeader != "" {
@@ -30,16 +36,31 @@ func authHeaderPropagationMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	}
 }
 
+// RequestIDHeader is the HTTP header providing the ID the tool calls are logged with (e.g. set by a proxy),
+// a random ID is generated when the header isn't set
+const RequestIDHeader = "X-Request-Id"
+
+// toolCallLoggingMiddleware tags the logger of the tool calls (klog.FromContext) with the requestId, sessionId and tool
 func toolCallLoggingMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
 		switch params := req.GetParams().(type) {
 		case *mcp.CallToolParamsRaw:
+			requestID := ""
+			if req.GetExtra() != nil && req.GetExtra().Header != nil {
+				requestID = req.GetExtra().Header.Get(RequestIDHeader)
+			}
+			if requestID == "" {
+				requestID = string(uuid.NewUUID())
+			}
+			session, _ := req.GetSession().(*mcp.ServerSession)
+			logger := klog.LoggerWithValues(klog.FromContext(ctx), "requestId", requestID, "sessionId", sessionID(session), "tool", params.Name)
+			ctx = klog.NewContext(ctx, logger)
 			toolCallRequest, _ := GoSdkToolCallParamsToToolCallRequest(params)
-			klog.V(5).Infof("mcp tool call: %s(%v)", toolCallRequest.Name, toolCallRequest.GetArguments())
+			logger.V(5).Info("mcp tool call", "arguments", toolCallRequest.GetArguments())
 			if req.GetExtra() != nil && req.GetExtra().Header != nil {
 				buffer := bytes.NewBuffer(make([]byte, 0))
 				if err := req.GetExtra().Header.WriteSubset(buffer, map[string]bool{"Authorization": true, "authorization": true}); err == nil {
-					klog.V(7).Infof("mcp tool call headers: %s", buffer)
+					logger.V(7).Info("mcp tool call headers", "headers", buffer.String())
 				}
 			}
 		}
@@ -1,17 +1,23 @@
 package mcp
 
 import (
+	"io"
+	"net/http"
 	"regexp"
 	"slices"
 	"testing"
 
 	"github.com/BurntSushi/toml"
+	"github.com/containers/kubernetes-mcp-server/internal/test"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/yaml"
 )
 
@@ -173,3 +179,164 @@ func (s *NamespacesSuite) TestProjectsListInOpenShiftDenied() {
 func TestNamespaces(t *testing.T) {
 	suite.Run(t, new(NamespacesSuite))
 }
+
+type NamespacesLifecycleSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	created    *v1.Namespace
+	deleted    []string
+}
+
+func (s *NamespacesLifecycleSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.created = nil
+	s.deleted = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"namespaces","singularName":"","namespaced":false,"kind":"Namespace","verbs":["create","delete","get","list"]}`,
+		},
+		Groups: []string{
+			`{"name":"metrics.k8s.io","versions":[{"groupVersion":"metrics.k8s.io/v1beta1","version":"v1beta1"}],"preferredVersion":{"groupVersion":"metrics.k8s.io/v1beta1","version":"v1beta1"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/metrics.k8s.io/v1beta1":
+			// Unavailable aggregated API, usual cause of namespaces stuck in the Terminating phase
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/api/v1/namespaces":
+			if req.Method == http.MethodPost {
+				body, _ := io.ReadAll(req.Body)
+				if obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body); err == nil {
+					s.created = obj.(*v1.Namespace)
+					s.created.Status.Phase = v1.NamespaceActive
+					test.WriteObject(w, s.created)
+				}
+			}
+		case "/api/v1/namespaces/to-delete":
+			if req.Method == http.MethodDelete {
+				s.deleted = append(s.deleted, "to-delete")
+				test.WriteObject(w, &metav1.Status{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}, Status: metav1.StatusSuccess})
+			}
+		case "/api/v1/namespaces/active":
+			test.WriteObject(w, &v1.Namespace{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+				ObjectMeta: metav1.ObjectMeta{Name: "active"},
+				Status:     v1.NamespaceStatus{Phase: v1.NamespaceActive},
+			})
+		case "/api/v1/namespaces/stuck":
+			test.WriteObject(w, &v1.Namespace{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+				ObjectMeta: metav1.ObjectMeta{Name: "stuck", DeletionTimestamp: &metav1.Time{}},
+				Spec:       v1.NamespaceSpec{Finalizers: []v1.FinalizerName{v1.FinalizerKubernetes}},
+				Status: v1.NamespaceStatus{Phase: v1.NamespaceTerminating, Conditions: []v1.NamespaceCondition{
+					{Type: v1.NamespaceDeletionDiscoveryFailure, Status: v1.ConditionTrue, Reason: "DiscoveryFailed"},
+					{Type: v1.NamespaceContentRemaining, Status: v1.ConditionTrue, Reason: "SomeResourcesRemain"},
+					{Type: v1.NamespaceDeletionContentFailure, Status: v1.ConditionFalse, Reason: "ContentDeleted"},
+				}},
+			})
+		case "/api/v1/namespaces/stuck/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+					ObjectMeta: metav1.ObjectMeta{Name: "stuck-pod", Namespace: "stuck", Finalizers: []string{"example.com/cleanup"}},
+				}},
+			})
+		case "/apis/apps/v1/namespaces/stuck/deployments":
+			test.WriteObject(w, &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "apps/v1", "kind": "DeploymentList"}})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NamespacesLifecycleSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NamespacesLifecycleSuite) TestNamespaces() {
+	s.InitMcpClient()
+	s.Run("namespaces(name=nil)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{"action": "create"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to manage namespace, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("namespaces(action=unsupported)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{"action": "unsupported", "name": "ns"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal(`failed to manage namespace, unsupported action "unsupported", supported actions: create, delete, diagnose`,
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("namespaces(action=create, name=new-namespace, labels={team: a-team})", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{
+			"action": "create", "name": "new-namespace", "labels": map[string]interface{}{"team": "a-team"},
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("creates namespace with labels", func() {
+			s.Require().NotNil(s.created)
+			s.Equal("new-namespace", s.created.Name)
+			s.Equal(map[string]string{"team": "a-team"}, s.created.Labels)
+		})
+		s.Run("returns created namespace", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, "# The following namespace (YAML) has been created successfully")
+			s.Contains(text, "name: new-namespace")
+		})
+	})
+	s.Run("namespaces(action=delete, name=to-delete)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{"action": "delete", "name": "to-delete"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("deletes namespace", func() {
+			s.Equal([]string{"to-delete"}, s.deleted)
+		})
+	})
+	s.Run("namespaces(action=diagnose, name=active)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{"action": "diagnose", "name": "active"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# Namespace active is not terminating (phase: Active)")
+	})
+	s.Run("namespaces(action=diagnose, name=stuck)", func() {
+		toolResult, err := s.CallTool("namespaces", map[string]interface{}{"action": "diagnose", "name": "stuck"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns summary", func() {
+			s.Contains(text, "# Namespace stuck is terminating, 1 remaining resources")
+		})
+		var diagnostic map[string]interface{}
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &diagnostic))
+		s.Run("returns namespace finalizers", func() {
+			s.Equal([]interface{}{"kubernetes"}, diagnostic["finalizers"])
+		})
+		s.Run("returns only failing conditions", func() {
+			s.Len(diagnostic["conditions"], 2)
+		})
+		s.Run("returns remaining resources with their finalizers", func() {
+			s.Equal([]interface{}{map[string]interface{}{
+				"apiVersion": "v1", "kind": "Pod", "name": "stuck-pod", "finalizers": []interface{}{"example.com/cleanup"},
+			}}, diagnostic["remainingResources"])
+		})
+		s.Run("returns unavailable API groups as target errors", func() {
+			s.Contains(text, "target: groupVersion/metrics.k8s.io/v1beta1")
+		})
+	})
+}
+
+func TestNamespacesLifecycle(t *testing.T) {
+	suite.Run(t, new(NamespacesLifecycleSuite))
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type NetpolSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NetpolSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"namespaces","singularName":"","namespaced":false,"kind":"Namespace","verbs":["get","list"]}`,
+		},
+		Groups: []string{
+			`{"name":"networking.k8s.io","versions":[{"groupVersion":"networking.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"networking.k8s.io/v1","version":"v1"}}`,
+		},
+	})
+	defaultDeny := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "default-deny"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+	}
+	allowWeb := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db-from-web"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}},
+				Ports: []networkingv1.NetworkPolicyPort{{Port: ptr.To(intstr.FromInt32(5432))}},
+			}},
+		},
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/networking.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"networking.k8s.io/v1","resources":[
+				{"name":"networkpolicies","singularName":"","namespaced":true,"kind":"NetworkPolicy","verbs":["get","list"]}
+			]}`))
+		case "/api/v1/namespaces":
+			test.WriteObject(w, &v1.NamespaceList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NamespaceList"},
+				Items:    []v1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, {ObjectMeta: metav1.ObjectMeta{Name: "shop"}}},
+			})
+		case "/api/v1/namespaces/shop":
+			test.WriteObject(w, &v1.Namespace{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{v1.LabelMetadataName: "shop"}},
+			})
+		case "/api/v1/namespaces/shop/pods/web":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web", Labels: map[string]string{"app": "web"}},
+				Status:     v1.PodStatus{PodIP: "10.244.0.10"},
+			})
+		case "/api/v1/namespaces/shop/pods/db-0":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db-0", Labels: map[string]string{"app": "db"}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "db", Ports: []v1.ContainerPort{{Name: "postgres", ContainerPort: 5432}}}}},
+				Status:     v1.PodStatus{PodIP: "10.244.0.11"},
+			})
+		case "/apis/networking.k8s.io/v1/namespaces/shop/networkpolicies", "/apis/networking.k8s.io/v1/networkpolicies":
+			test.WriteObject(w, &networkingv1.NetworkPolicyList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicyList"},
+				Items:    []networkingv1.NetworkPolicy{defaultDeny, allowWeb},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"network_debug"}
+}
+
+func (s *NetpolSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NetpolSuite) TestNetpolAnalyze() {
+	s.InitMcpClient()
+	s.Run("netpol_analyze(source_pod=web, destination_pod=db-0, port=5432)", func() {
+		toolResult, err := s.CallTool("netpol_analyze", map[string]interface{}{
+			"source_namespace": "shop", "source_pod": "web", "destination_namespace": "shop", "destination_pod": "db-0", "port": 5432,
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# Pod shop/web to Pod shop/db-0 on 5432/TCP is allowed\n"), text)
+		var analysis kubernetes.NetpolAnalysis
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &analysis))
+		s.True(analysis.Allowed)
+		s.Require().NotNil(analysis.Ingress)
+		s.Equal("allowed by rule 0 of shop/db-from-web", analysis.Ingress.Reason)
+	})
+	s.Run("netpol_analyze(source_labels, destination_pod=db-0, port=5432) is denied", func() {
+		toolResult, err := s.CallTool("netpol_analyze", map[string]interface{}{
+			"source_namespace": "shop", "source_labels": map[string]interface{}{"app": "batch"}, "destination_namespace": "shop", "destination_pod": "db-0", "port": 5432,
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text,
+			"# Pod of namespace shop with labels app=batch to Pod shop/db-0 on 5432/TCP is denied by the ingress policies of the destination\n"))
+	})
+	s.Run("netpol_analyze(source_ip, destination_pod=web, port=8080) is denied", func() {
+		toolResult, err := s.CallTool("netpol_analyze", map[string]interface{}{
+			"source_ip": "192.168.0.1", "destination_namespace": "shop", "destination_pod": "web", "port": 8080,
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# IP 192.168.0.1 to Pod shop/web on 8080/TCP is denied by the ingress policies of the destination\n"), text)
+		var analysis kubernetes.NetpolAnalysis
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &analysis))
+		s.False(analysis.Allowed)
+		s.Require().NotNil(analysis.Ingress)
+		s.Equal("the Pod is isolated for ingress by shop/default-deny, none of their rules allow the peer", analysis.Ingress.Reason)
+	})
+	s.Run("netpol_analyze() returns error", func() {
+		toolResult, err := s.CallTool("netpol_analyze", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze network policies, ")
+	})
+}
+
+func (s *NetpolSuite) TestNetpolListIsolating() {
+	s.InitMcpClient()
+	s.Run("netpol_list_isolating()", func() {
+		toolResult, err := s.CallTool("netpol_list_isolating", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# 1 of 2 namespaces isolate all their Pods, 1 with a default deny\n"), text)
+		var report kubernetes.NetpolIsolationReport
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &report))
+		s.Require().Len(report.Isolated, 1)
+		s.True(report.Isolated[0].DefaultDenyIngress)
+		s.Equal([]string{"default"}, report.NotIsolated)
+	})
+}
+
+func TestNetpol(t *testing.T) {
+	suite.Run(t, new(NetpolSuite))
+}
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type NetworkDebugSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+}
+
+func (s *NetworkDebugSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.Cfg.Toolsets = []string{"network_debug"}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"services","singularName":"","namespaced":true,"kind":"Service","verbs":["get","list","watch"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/shop/services/web":
+			test.WriteObject(w, &v1.Service{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, Ports: []v1.ServicePort{{Name: "http", Port: 8080}}},
+			})
+		case "/api/v1/namespaces/shop/pods/db-0":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "shop"},
+				Status:     v1.PodStatus{Phase: v1.PodPending},
+			})
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		return "ok 10.96.0.10 \n" +
+			"ok 503 \n" +
+			"failed nc: connect to 10.0.0.5 port 5432 (tcp) timed out: Operation in progress \n"
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NetworkDebugSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NetworkDebugSuite) TestNetworkDebugProbes() {
+	s.InitMcpClient()
+	s.Run("network_debug_probes(namespace=frontend, probes=[...])", func() {
+		toolResult, err := s.CallTool("network_debug_probes", map[string]interface{}{
+			"namespace": "frontend",
+			"labels":    map[string]interface{}{"app": "frontend"},
+			"probes": []interface{}{
+				map[string]interface{}{"type": "dns", "target": "service/web", "namespace": "shop"},
+				map[string]interface{}{"type": "http", "target": "service/web", "namespace": "shop", "path": "/healthz"},
+				map[string]interface{}{"type": "tcp", "target": "pod/db-0", "namespace": "shop", "port": 5432},
+				map[string]interface{}{"type": "tcp", "target": "10.0.0.5", "port": 5432},
+			},
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the results", func() {
+			s.True(strings.HasPrefix(text, "# 1/4 probes succeeded from namespace frontend\n"), text)
+		})
+		var networkDebug kubernetes.NetworkDebug
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &networkDebug))
+		s.Run("returns the result of each probe", func() {
+			s.Equal([]kubernetes.NetworkProbeResult{
+				{Type: "dns", Target: "service/web", Address: "web.shop.svc", Success: true, Output: "10.96.0.10"},
+				{Type: "http", Target: "service/web", Address: "http://web.shop.svc:8080/healthz", StatusCode: 503, Output: "HTTP 503"},
+				{Type: "tcp", Target: "pod/db-0", Output: "failed to resolve target: pod db-0 has no IP address (phase Pending)"},
+				{Type: "tcp", Target: "10.0.0.5", Address: "10.0.0.5:5432", Output: "nc: connect to 10.0.0.5 port 5432 (tcp) timed out: Operation in progress"},
+			}, networkDebug.Results)
+		})
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		helperPod := s.helperPodHandler.Created()[0]
+		s.Run("runs the resolved probes from a helper pod in the source namespace", func() {
+			s.Equal("frontend", helperPod.Namespace)
+			s.Equal("frontend", helperPod.Labels["app"])
+			s.Equal([]string{"sh",
+				"dns", "web.shop.svc", "",
+				"http", "http://web.shop.svc:8080/healthz", "",
+				"tcp", "10.0.0.5", "5432",
+			}, helperPod.Spec.Containers[0].Command[3:])
+			s.Equal([]string{helperPod.Name}, s.helperPodHandler.Deleted())
+		})
+	})
+	s.Run("network_debug_probes(probes=[{type=icmp}])", func() {
+		toolResult, err := s.CallTool("network_debug_probes", map[string]interface{}{
+			"probes": []interface{}{map[string]interface{}{"type": "icmp", "target": "example.com"}},
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to run network probes, invalid argument probes:")
+	})
+	s.Run("network_debug_probes(probes=[{type=tcp, target=pod/db-0}])", func() {
+		toolResult, err := s.CallTool("network_debug_probes", map[string]interface{}{
+			"probes": []interface{}{map[string]interface{}{"type": "tcp", "target": "pod/db-0"}},
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to run network probes: invalid probe 0: tcp probe to pod/db-0 requires a port",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestNetworkDebug(t *testing.T) {
+	suite.Run(t, new(NetworkDebugSuite))
+}
@@ -0,0 +1,559 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NodeFilesSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+}
+
+func (s *NodeFilesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/node-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			})
+		case "/api/v1/nodes/win-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "win-1"},
+				Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{OperatingSystem: "windows"}},
+			})
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		if command := pod.Spec.Containers[0].Command; command[0] == "powershell.exe" {
+			switch script := command[len(command)-1]; {
+			case strings.Contains(script, "Get-ChildItem"):
+				return "Mode   LastWriteTime        Length Name\r\n-a---  1/1/2025 12:00:00 AM 42     kubelet.log\r\n"
+			case strings.Contains(script, "ReadAllBytes"):
+				return base64.StdEncoding.EncodeToString([]byte("windows kubelet log\r\n")) + "\r\n"
+			case strings.Contains(script, "WriteAllBytes"):
+				return "- " + pod.Spec.Containers[0].Env[2].Value + "\r\n"
+			}
+			return ""
+		}
+		script := pod.Spec.Containers[0].Command[2]
+		switch {
+		case strings.Contains(script, "ls -la"):
+			return "-rw-r--r-- 1 root root 42 Jan  1 00:00 kubelet.log\n"
+		case strings.Contains(script, "base64 -- "):
+			return base64.StdEncoding.EncodeToString([]byte("kubelet log\x00binary")) + "\n"
+		case strings.Contains(script, "base64 -d"):
+			// The node file doesn't exist, the written file has the expected checksum
+			return "- " + pod.Spec.Containers[0].Command[5] + "\n"
+		}
+		return ""
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodeFilesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodeFilesSuite) TestNodeFiles() {
+	s.InitMcpClient()
+	s.Run("node_files(operation=list)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "/var/log/../log",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("returns directory listing", func() {
+			s.Equal("-rw-r--r-- 1 root root 42 Jan  1 00:00 kubelet.log\n", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("creates privileged helper pod on the node", func() {
+			s.Equal("node-1", pod.Spec.NodeName)
+			s.True(*pod.Spec.Containers[0].SecurityContext.Privileged)
+		})
+		s.Run("mounts the node root filesystem read-write", func() {
+			s.Require().Len(pod.Spec.Volumes, 1)
+			s.Equal("/", pod.Spec.Volumes[0].HostPath.Path)
+			s.Equal([]v1.VolumeMount{{Name: "host-root", MountPath: "/host"}}, pod.Spec.Containers[0].VolumeMounts)
+		})
+		s.Run("passes the cleaned node path as an argument", func() {
+			s.Equal([]string{"sh", "/host/var/log", "/var/log/../log"}, pod.Spec.Containers[0].Command[3:])
+		})
+		s.Run("lists the real path of the node path", func() {
+			s.Contains(pod.Spec.Containers[0].Command[2], `path=$(resolve "$1" "$2") || exit 1; ls -la -- "$path"`)
+		})
+	})
+	s.Run("node_files(operation=get)", func() {
+		dest := filepath.Join(s.T().TempDir(), "kubelet.log")
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/var/log/kubelet.log",
+			"dest_path":   dest,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("describes the copied file", func() {
+			s.Equal("File /var/log/kubelet.log of node node-1 (18 bytes) copied to "+dest, toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Run("writes the decoded file locally", func() {
+			content, err := os.ReadFile(dest)
+			s.Require().NoError(err)
+			s.Equal("kubelet log\x00binary", string(content))
+		})
+	})
+	s.Run("node_files(operation=get) without dest_path", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/var/log/kubelet.log",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("describes the stored artifact", func() {
+			s.Regexp(`^File /var/log/kubelet.log of node node-1 \(18 bytes\) stored as artifact `+
+				`artifact://node-file-node-1-kubelet-\d{8}-\d{6}-[0-9a-f]{8}\.log$`, text)
+		})
+		s.Run("writes the decoded file in the artifacts directory", func() {
+			name := strings.TrimPrefix(text[strings.LastIndex(text, " ")+1:], "artifact://")
+			content, err := os.ReadFile(filepath.Join(s.Cfg.Artifacts.Dir, name))
+			s.Require().NoError(err)
+			s.Equal("kubelet log\x00binary", string(content))
+		})
+	})
+	s.Run("node_files(operation=get, inline=true, base64=true)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/var/log/kubelet.log",
+			"inline":      true,
+			"base64":      true,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("returns the base64 encoded content", func() {
+			s.Equal(base64.StdEncoding.EncodeToString([]byte("kubelet log\x00binary")), toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("node_files(operation=get, inline=true) with a binary file", func() {
+		toolResult, _ := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/var/log/kubelet.log",
+			"inline":      true,
+		})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get files of node node-1: file /var/log/kubelet.log of node node-1 is a binary file, use base64 to return it inline",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("node_files(operation=get, inline=true, dest_path=/tmp/kubelet.log)", func() {
+		created := len(s.helperPodHandler.Created())
+		toolResult, _ := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/var/log/kubelet.log",
+			"dest_path":   "/tmp/kubelet.log",
+			"inline":      true,
+		})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get files of node node-1: inline and dest_path can't be used together", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Len(s.helperPodHandler.Created(), created, "doesn't create helper pod")
+	})
+	s.Run("node_files(operation=put)", func() {
+		source := filepath.Join(s.T().TempDir(), "99-custom.conf")
+		s.Require().NoError(os.WriteFile(source, []byte("vm.max_map_count=262144\n"), 0600))
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "put",
+			"source_path": source,
+			"dest_path":   "/etc/sysctl.d/99-custom.conf",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		sum := sha256.Sum256([]byte("vm.max_map_count=262144\n"))
+		checksum := hex.EncodeToString(sum[:])
+		s.Run("describes the copied file with its checksums", func() {
+			s.Equal("File "+source+" (24 bytes) copied to /etc/sysctl.d/99-custom.conf on node node-1, sha256 before: none (new file), after: "+checksum+" (verified)",
+				toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		command := s.helperPodHandler.Created()[len(s.helperPodHandler.Created())-1].Spec.Containers[0].Command
+		s.Run("writes the encoded content in the node path", func() {
+			s.Equal("/host/etc/sysctl.d/99-custom.conf", command[4])
+			s.Contains(command[2], base64.StdEncoding.EncodeToString([]byte("vm.max_map_count=262144\n")))
+		})
+		s.Run("passes the local checksum to verify the node file", func() {
+			s.Equal(checksum, command[5])
+			s.Contains(command[2], `if [ "$before" = "$2" ]; then echo "unchanged $2"; exit 0; fi`)
+		})
+	})
+	s.Run("node_files(operation=list, source_path=relative)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "var/log",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to list files of node node-1: node path must be absolute, got "var/log"`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("node_files(operation=delete)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "delete",
+			"source_path": "/var/log",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to access node files, invalid argument operation: enum: delete does not equal any of: [list get put]",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *NodeFilesSuite) TestNodeFilesWindows() {
+	s.InitMcpClient()
+	s.Run("node_files(name=win-1, operation=list)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "win-1",
+			"operation":   "list",
+			"source_path": `C:\var\log\..\log`,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("returns directory listing", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "kubelet.log")
+		})
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("creates HostProcess helper pod on the Windows node", func() {
+			s.Equal("win-1", pod.Spec.NodeName)
+			s.Equal(&v1.PodOS{Name: v1.Windows}, pod.Spec.OS)
+			s.True(*pod.Spec.Containers[0].SecurityContext.WindowsOptions.HostProcess)
+			s.Empty(pod.Spec.Volumes)
+		})
+		s.Run("runs PowerShell with the cleaned node path as an environment variable", func() {
+			s.Equal("powershell.exe", pod.Spec.Containers[0].Command[0])
+			s.Equal([]v1.EnvVar{{Name: "NODE_PATH", Value: "C:/var/log"}}, pod.Spec.Containers[0].Env)
+		})
+	})
+	s.Run("node_files(name=win-1, operation=get, inline=true)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "win-1",
+			"operation":   "get",
+			"source_path": `C:\var\log\kubelet\kubelet.log`,
+			"inline":      true,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("windows kubelet log\r\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("node_files(name=win-1, operation=put)", func() {
+		source := filepath.Join(s.T().TempDir(), "config.yaml")
+		s.Require().NoError(os.WriteFile(source, []byte("kind: KubeletConfiguration\n"), 0600))
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "win-1",
+			"operation":   "put",
+			"source_path": source,
+			"dest_path":   `C:\var\lib\kubelet\config.yaml`,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		sum := sha256.Sum256([]byte("kind: KubeletConfiguration\n"))
+		s.Run("describes the copied file with its checksums", func() {
+			s.Equal("File "+source+` (27 bytes) copied to C:\var\lib\kubelet\config.yaml on node win-1, sha256 before: none (new file), after: `+
+				hex.EncodeToString(sum[:])+" (verified)", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Run("passes the encoded content as an environment variable", func() {
+			pod := s.helperPodHandler.Created()[len(s.helperPodHandler.Created())-1]
+			s.Equal([]v1.EnvVar{
+				{Name: "NODE_PATH", Value: "C:/var/lib/kubelet/config.yaml"},
+				{Name: "CONTENT", Value: base64.StdEncoding.EncodeToString([]byte("kind: KubeletConfiguration\n"))},
+				{Name: "CHECKSUM", Value: hex.EncodeToString(sum[:])},
+			}, pod.Spec.Containers[0].Env)
+		})
+	})
+	s.Run("node_files(name=win-1, operation=list, source_path=/var/log)", func() {
+		toolResult, _ := s.CallTool("node_files", map[string]interface{}{
+			"name":        "win-1",
+			"operation":   "list",
+			"source_path": "/var/log",
+		})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal(`failed to list files of node win-1: node path must be an absolute Windows path (e.g. C:\var\log), got "/var/log"`,
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *NodeFilesSuite) TestNodeFilesHelperPodSpec() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		helper_image_pull_secrets = ["mirror-pull-secret"]
+	`), s.Cfg), "Expected to parse helper_image_pull_secrets config")
+	s.InitMcpClient()
+	s.Run("node_files(tolerations, image_pull_secret, resources)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":              "node-1",
+			"operation":         "list",
+			"source_path":       "/var/log",
+			"tolerations":       []interface{}{map[string]interface{}{"key": "node-role.kubernetes.io/control-plane", "operator": "Exists", "effect": "NoExecute"}},
+			"image_pull_secret": "private-registry",
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "10m", "memory": "16Mi"},
+				"limits":   map[string]interface{}{"memory": "64Mi"},
+			},
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("sets the tolerations", func() {
+			s.Equal([]v1.Toleration{{Key: "node-role.kubernetes.io/control-plane", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute}},
+				pod.Spec.Tolerations)
+		})
+		s.Run("adds the image pull secret to the configured ones", func() {
+			s.Equal([]v1.LocalObjectReference{{Name: "mirror-pull-secret"}, {Name: "private-registry"}}, pod.Spec.ImagePullSecrets)
+		})
+		s.Run("sets the resource requests and limits", func() {
+			resources := pod.Spec.Containers[0].Resources
+			s.Equal("10m", resources.Requests.Cpu().String())
+			s.Equal("16Mi", resources.Requests.Memory().String())
+			s.Equal("64Mi", resources.Limits.Memory().String())
+		})
+	})
+	s.Run("node_files(resources={requests: {cpu: invalid}})", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "/var/log",
+			"resources":   map[string]interface{}{"requests": map[string]interface{}{"cpu": "lots"}},
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to access node files, invalid argument resources.requests: cpu: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *NodeFilesSuite) TestNodeFilesReadOnly() {
+	s.InitMcpClient()
+	s.Run("node_files(operation=list, read_only=true)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "/var/log",
+			"read_only":   true,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("mounts the node root filesystem read-only", func() {
+			s.Require().Len(s.helperPodHandler.Created(), 1)
+			s.Equal([]v1.VolumeMount{{Name: "host-root", MountPath: "/host", ReadOnly: true}},
+				s.helperPodHandler.Created()[0].Spec.Containers[0].VolumeMounts)
+		})
+	})
+	s.Run("node_files(operation=put, read_only=true)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "put",
+			"source_path": "/tmp/file",
+			"dest_path":   "/etc/file",
+			"read_only":   true,
+		})
+		s.Require().NoError(err)
+		s.Run("is not allowed", func() {
+			s.True(toolResult.IsError)
+			s.Equal("failed to put files of node node-1: operation put is not allowed in read-only mode, only list and get are allowed",
+				toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Run("doesn't create helper pod", func() {
+			s.Len(s.helperPodHandler.Created(), 1)
+		})
+	})
+}
+
+func (s *NodeFilesSuite) TestNodeFilesReadOnlyConfig() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		node_files_read_only = true
+	`), s.Cfg), "Expected to parse node_files_read_only config")
+	s.InitMcpClient()
+	s.Run("node_files(operation=get) mounts the node root filesystem read-only", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/var/log/kubelet.log",
+			"dest_path":   filepath.Join(s.T().TempDir(), "kubelet.log"),
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		s.True(s.helperPodHandler.Created()[0].Spec.Containers[0].VolumeMounts[0].ReadOnly)
+	})
+	s.Run("node_files(operation=put, read_only=false) is not allowed", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "put",
+			"source_path": "/tmp/file",
+			"dest_path":   "/etc/file",
+			"read_only":   false,
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "operation put is not allowed in read-only mode")
+	})
+}
+
+func (s *NodeFilesSuite) TestNodeFilesUnprivilegedConfig() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		node_files_unprivileged = true
+		node_files_allowed_host_paths = ["/var/log", "/etc/kubernetes/manifests"]
+	`), s.Cfg), "Expected to parse node_files_unprivileged config")
+	s.InitMcpClient()
+	s.Run("node_files(operation=list, source_path=/var/log/pods)", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "/var/log/pods",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("creates unprivileged helper pod with restricted security context", func() {
+			securityContext := pod.Spec.Containers[0].SecurityContext
+			s.Nil(securityContext.Privileged)
+			s.True(*securityContext.RunAsNonRoot)
+			s.False(*securityContext.AllowPrivilegeEscalation)
+			s.Equal(v1.SeccompProfileTypeRuntimeDefault, securityContext.SeccompProfile.Type)
+		})
+		s.Run("mounts only the allowed host paths", func() {
+			s.Require().Len(pod.Spec.Volumes, 2)
+			s.Equal("/var/log", pod.Spec.Volumes[0].HostPath.Path)
+			s.Equal("/etc/kubernetes/manifests", pod.Spec.Volumes[1].HostPath.Path)
+			s.Equal("/host/var/log", pod.Spec.Containers[0].VolumeMounts[0].MountPath)
+		})
+		s.Run("passes the node path under the host root", func() {
+			s.Equal("/host/var/log/pods", pod.Spec.Containers[0].Command[4])
+		})
+	})
+	s.Run("node_files(operation=get, source_path=/etc/kubernetes/pki/ca.key) is not allowed", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/etc/kubernetes/pki/ca.key",
+			"dest_path":   filepath.Join(s.T().TempDir(), "ca.key"),
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to get files of node node-1: node path /etc/kubernetes/pki/ca.key is not allowed in unprivileged mode, "+
+			"allowed paths are /var/log, /etc/kubernetes/manifests", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Len(s.helperPodHandler.Created(), 1, "doesn't create helper pod")
+	})
+	s.Run("node_files(operation=list, source_path=/var/log/../../etc) is not allowed", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "/var/log/../../etc",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "node path /var/log/../../etc is not allowed in unprivileged mode")
+	})
+	s.Run("node_files(name=win-1) is not supported in unprivileged mode", func() {
+		created := len(s.helperPodHandler.Created())
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "win-1",
+			"operation":   "list",
+			"source_path": `C:\var\log`,
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to list files of node win-1: node win-1 is a Windows node, node_files_unprivileged is only supported on Linux nodes",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Len(s.helperPodHandler.Created(), created, "doesn't create helper pod")
+	})
+}
+
+func (s *NodeFilesSuite) TestNodeFilesAllowedAndDeniedPathsConfig() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		node_files_allowed_paths = ["/var/log", "/etc/kubernetes"]
+		node_files_denied_paths = ["/etc/kubernetes/pki"]
+	`), s.Cfg), "Expected to parse node_files_allowed_paths and node_files_denied_paths config")
+	s.InitMcpClient()
+	s.Run("node_files(operation=list, source_path=/var/log) is allowed", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "/var/log",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Len(s.helperPodHandler.Created(), 1)
+	})
+	s.Run("node_files(operation=get, source_path=/etc/kubernetes/pki/ca.key) is denied", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "get",
+			"source_path": "/etc/kubernetes/pki/ca.key",
+			"dest_path":   filepath.Join(s.T().TempDir(), "ca.key"),
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to get files of node node-1: path not allowed: /etc/kubernetes/pki/ca.key (denied by node_files_denied_paths entry /etc/kubernetes/pki)",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("node_files(operation=put, dest_path=/etc/sysctl.d/99-custom.conf) is not in the allowed paths", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "put",
+			"source_path": "/tmp/99-custom.conf",
+			"dest_path":   "/etc/sysctl.d/99-custom.conf",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to put files of node node-1: path not allowed: /etc/sysctl.d/99-custom.conf (allowed paths are /var/log, /etc/kubernetes)",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("node_files(operation=list, source_path=/var/log/../../etc/kubernetes/pki) is denied", func() {
+		toolResult, err := s.CallTool("node_files", map[string]interface{}{
+			"name":        "node-1",
+			"operation":   "list",
+			"source_path": "/var/log/../../etc/kubernetes/pki",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "path not allowed: /var/log/../../etc/kubernetes/pki (denied by")
+	})
+	s.Run("doesn't create helper pods for paths not allowed", func() {
+		s.Len(s.helperPodHandler.Created(), 1)
+	})
+}
+
+func TestNodeFiles(t *testing.T) {
+	suite.Run(t, new(NodeFilesSuite))
+}
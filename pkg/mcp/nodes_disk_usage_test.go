@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type NodesDiskUsageSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+}
+
+func (s *NodesDiskUsageSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/node-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+					{Type: v1.NodeDiskPressure, Status: v1.ConditionTrue, Message: "kubelet has disk pressure"},
+				}},
+			})
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"nodeName":"node-1","fs":{"availableBytes":1073741824,"capacityBytes":10737418240,"usedBytes":8589934592}}}`))
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		logs := "du 1048576 /var/lib/containerd\n" +
+			"runtime unix:///run/containerd/containerd.sock\n" +
+			`images {"images":[{"id":"sha256:app","repoTags":["example.com/app:1.0"],"size":"1024"},{"id":"sha256:old","repoTags":["example.com/old:1.0"],"size":"2048"}]}` + "\n" +
+			`containers {"containers":[{"id":"a1b2","metadata":{"name":"app"},"image":{"image":"example.com/app:1.0"},"imageRef":"sha256:app","state":"CONTAINER_RUNNING"}]}` + "\n"
+		if pod.Spec.Containers[0].Command[6] == "prune" {
+			logs += `pruned {"images":[{"id":"sha256:app","repoTags":["example.com/app:1.0"],"size":"1024"}]}` + "\n"
+		}
+		return logs
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesDiskUsageSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesDiskUsageSuite) TestNodesDiskUsage() {
+	s.InitMcpClient()
+	s.Run("nodes_disk_usage(name=node-1)", func() {
+		toolResult, err := s.CallTool("nodes_disk_usage", map[string]interface{}{"name": "node-1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the disk usage", func() {
+			s.Contains(text, "# Disk usage of node node-1: nodefs 90% used (1.0GiB available), DiskPressure, 2 images, 2.0KiB reclaimable by image garbage collection\n")
+		})
+		var usage kubernetes.NodeDiskUsage
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &usage))
+		s.Run("reports the DiskPressure condition", func() {
+			s.Equal("True: kubelet has disk pressure", usage.DiskPressure)
+		})
+		s.Run("reports the node filesystem", func() {
+			s.Require().Len(usage.Filesystems, 1)
+			s.Equal("nodefs", usage.Filesystems[0].Name)
+		})
+		s.Run("flags the images used by a container", func() {
+			s.Require().Len(usage.Images, 2)
+			s.Equal("sha256:old", usage.Images[0].ID)
+			s.False(usage.Images[0].InUse)
+			s.True(usage.Images[1].InUse)
+		})
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("measures the default paths in the read-only node root filesystem", func() {
+			command := pod.Spec.Containers[0].Command
+			s.Equal([]string{"chroot", "/host", "sh", "-c"}, command[:4])
+			s.Equal(append([]string{"sh", "report"}, kubernetes.NodeDiskUsageDefaultPaths...), command[5:])
+			s.True(*pod.Spec.Containers[0].SecurityContext.Privileged)
+		})
+	})
+	s.Run("nodes_disk_usage(name=node-1, paths=[/var/log], prune_images=true, output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_disk_usage", map[string]interface{}{
+			"name": "node-1", "paths": []interface{}{"/var/log"}, "prune_images": true, "output_format": "json",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var envelope struct {
+			Kind  string                      `json:"kind"`
+			Items []*kubernetes.NodeDiskUsage `json:"items"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("NodeDiskUsage", envelope.Kind)
+		s.Require().Len(envelope.Items, 1)
+		s.Run("removes the unused images", func() {
+			s.Require().Len(envelope.Items[0].PrunedImages, 1)
+			s.Equal("sha256:old", envelope.Items[0].PrunedImages[0].ID)
+			s.Equal(int64(2048), envelope.Items[0].ReclaimedBytes)
+		})
+		command := s.helperPodHandler.Created()[len(s.helperPodHandler.Created())-1].Spec.Containers[0].Command
+		s.Equal([]string{"sh", "prune", "/var/log"}, command[5:])
+	})
+	s.Run("nodes_disk_usage(name=node-1, paths=[relative])", func() {
+		toolResult, err := s.CallTool("nodes_disk_usage", map[string]interface{}{"name": "node-1", "paths": []interface{}{"var/log"}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to get disk usage of node node-1: invalid path "var/log", paths must be absolute and clean`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestNodesDiskUsage(t *testing.T) {
+	suite.Run(t, new(NodesDiskUsageSuite))
+}
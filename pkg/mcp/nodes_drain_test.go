@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
+)
+
+type NodesDrainPreviewSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NodesDrainPreviewSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		Groups: []string{
+			`{"name":"policy","versions":[{"groupVersion":"policy/v1","version":"v1"}],"preferredVersion":{"groupVersion":"policy/v1","version":"v1"}}`,
+		},
+	})
+	node := func(name string) v1.Node {
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")},
+				Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+			},
+		}
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/policy/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"policy/v1","resources":[
+				{"name":"poddisruptionbudgets","singularName":"","namespaced":true,"kind":"PodDisruptionBudget","verbs":["get","list"]}
+			]}`))
+		case "/api/v1/nodes/node-1":
+			n := node("node-1")
+			test.WriteObject(w, &n)
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{Items: []v1.Node{node("node-1"), node("node-2")}})
+		case "/api/v1/pods":
+			test.WriteObject(w, &v1.PodList{Items: []v1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "db-0", Labels: map[string]string{"app": "db"},
+						OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: ptr.To(true)}}},
+					Spec: v1.PodSpec{NodeName: "node-1", Volumes: []v1.Volume{
+						{Name: "scratch", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+					}},
+					Status: v1.PodStatus{Phase: v1.PodRunning},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "fluentd-xyz",
+						OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "fluentd", Controller: ptr.To(true)}}},
+					Spec:   v1.PodSpec{NodeName: "node-1"},
+					Status: v1.PodStatus{Phase: v1.PodRunning},
+				},
+			}})
+		case "/apis/policy/v1/poddisruptionbudgets":
+			test.WriteObject(w, &policyv1.PodDisruptionBudgetList{Items: []policyv1.PodDisruptionBudget{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "db"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+				Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+			}}})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesDrainPreviewSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesDrainPreviewSuite) TestNodesDrainPreview() {
+	s.InitMcpClient()
+	s.Run("nodes_drain_preview(name=nil)", func() {
+		toolResult, err := s.CallTool("nodes_drain_preview", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to preview node drain, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_drain_preview(name=node-1)", func() {
+		toolResult, err := s.CallTool("nodes_drain_preview", map[string]interface{}{
+			"name": "node-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns evicted pods", func() {
+			s.True(strings.HasPrefix(text, "# Node drain preview\n"), "unexpected result %s", text)
+			s.Contains(text, "  name: db-0\n")
+			s.Contains(text, "- candidateNodes:\n  - node-2\n")
+		})
+		s.Run("returns skipped pods", func() {
+			s.Contains(text, "- name: fluentd-xyz\n")
+		})
+		s.Run("returns findings", func() {
+			s.Contains(text, "# Findings\n")
+			s.Contains(text, "ruleId: DRAIN001")
+			s.Contains(text, "ruleId: DRAIN002")
+			s.Contains(text, "ruleId: DRAIN005")
+		})
+	})
+	s.Run("nodes_drain_preview(name=node-1, format=json)", func() {
+		toolResult, err := s.CallTool("nodes_drain_preview", map[string]interface{}{
+			"name":   "node-1",
+			"format": "json",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var report findings.Report
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &report))
+		s.Run("returns findings report", func() {
+			s.Equal("nodes_drain_preview", report.Tool)
+			s.Len(report.Findings, 3)
+		})
+	})
+	s.Run("nodes_drain_preview(name=missing-node)", func() {
+		toolResult, err := s.CallTool("nodes_drain_preview", map[string]interface{}{
+			"name": "missing-node",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to preview drain of node missing-node")
+	})
+}
+
+func TestNodesDrainPreview(t *testing.T) {
+	suite.Run(t, new(NodesDrainPreviewSuite))
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NodesJournalSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+}
+
+func (s *NodesJournalSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/node-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			})
+		case "/api/v1/nodes/win-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "win-1", Labels: map[string]string{"kubernetes.io/os": "windows"}},
+			})
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		if pod.Spec.Containers[0].Command[6] == "crio" {
+			return ""
+		}
+		return "2025-01-01T10:00:00+0000 node-1 kubelet[1234]: I0101 10:00:00.000000 1234 kubelet.go:2000] \"SyncLoop ADD\"\n" +
+			"2025-01-01T10:00:01+0000 node-1 kubelet[1234]: E0101 10:00:01.000000 1234 pod_workers.go:1300] \"Error syncing pod\"\n"
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesJournalSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesJournalSuite) TestNodesJournal() {
+	s.InitMcpClient()
+	s.Run("nodes_journal(name=node-1, unit=kubelet, since=-1h)", func() {
+		toolResult, err := s.CallTool("nodes_journal", map[string]interface{}{
+			"name":  "node-1",
+			"unit":  "kubelet",
+			"since": "-1h",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("returns the journal entries", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "\"Error syncing pod\"\n")
+		})
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("creates privileged helper pod on the node", func() {
+			s.Equal("node-1", pod.Spec.NodeName)
+			s.True(*pod.Spec.Containers[0].SecurityContext.Privileged)
+		})
+		s.Run("mounts the node root filesystem read-only", func() {
+			s.Equal([]v1.VolumeMount{{Name: "host-root", MountPath: "/host", ReadOnly: true}}, pod.Spec.Containers[0].VolumeMounts)
+		})
+		s.Run("runs journalctl in the node root filesystem with the filters as arguments", func() {
+			command := pod.Spec.Containers[0].Command
+			s.Equal([]string{"chroot", "/host", "sh", "-c"}, command[:4])
+			s.Contains(command[4], `exec journalctl "$@"`)
+			s.Equal([]string{"sh", "kubelet", "100", "-1h", ""}, command[5:])
+		})
+		s.Run("deletes the helper pod", func() {
+			s.Equal([]string{pod.Name}, s.helperPodHandler.Deleted())
+		})
+	})
+	s.Run("nodes_journal(unit=crio, lines=0)", func() {
+		toolResult, err := s.CallTool("nodes_journal", map[string]interface{}{"name": "node-1", "unit": "crio", "lines": 0})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("retrieves all the entries", func() {
+			command := s.helperPodHandler.Created()[len(s.helperPodHandler.Created())-1].Spec.Containers[0].Command
+			s.Equal("all", command[7])
+		})
+		s.Run("describes the empty journal", func() {
+			s.Equal("The unit crio has no journal entries on node node-1", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("nodes_journal(unit=--flag)", func() {
+		toolResult, err := s.CallTool("nodes_journal", map[string]interface{}{"name": "node-1", "unit": "--merge"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to get journal of unit --merge on node node-1: invalid unit name "--merge"`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_journal(name=missing)", func() {
+		toolResult, err := s.CallTool("nodes_journal", map[string]interface{}{"name": "missing", "unit": "kubelet"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get journal of unit kubelet on node missing: failed to get node missing:")
+	})
+	s.Run("nodes_journal(name=win-1)", func() {
+		created := len(s.helperPodHandler.Created())
+		toolResult, err := s.CallTool("nodes_journal", map[string]interface{}{"name": "win-1", "unit": "kubelet"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to get journal of unit kubelet on node win-1: node win-1 is a Windows node, only Linux nodes are supported",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Len(s.helperPodHandler.Created(), created, "doesn't create helper pod")
+	})
+}
+
+func (s *NodesJournalSuite) TestNodesJournalTimeout() {
+	s.Cfg.Timeouts = map[string]string{"nodes_journal": "1500ms"}
+	s.helperPodHandler.Phase = v1.PodPending
+	s.InitMcpClient()
+	s.Run("nodes_journal(name=node-1) with a pending helper pod", func() {
+		toolResult, err := s.CallTool("nodes_journal", map[string]interface{}{"name": "node-1", "unit": "kubelet"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("cancels the tool call after the configured timeout", func() {
+			s.Equal("tool nodes_journal timed out after 1.5s (timeouts.nodes_journal): "+
+				"failed to get journal of unit kubelet on node node-1: "+
+				"helper pod "+pod.Name+" did not complete (phase Pending): context deadline exceeded",
+				toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Run("deletes the helper pod", func() {
+			s.Equal([]string{pod.Name}, s.helperPodHandler.Deleted())
+		})
+	})
+}
+
+func TestNodesJournal(t *testing.T) {
+	suite.Run(t, new(NodesJournalSuite))
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NodesOSInventorySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NodesOSInventorySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	node := func(name, kernel, kubelet string) v1.Node {
+		return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{
+			OperatingSystem: "linux", Architecture: "amd64", OSImage: "Ubuntu 22.04.4 LTS", KernelVersion: kernel,
+			ContainerRuntimeVersion: "containerd://1.7.2", KubeletVersion: kubelet,
+		}}}
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/version":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"major":"1","minor":"30","gitVersion":"v1.30.4"}`))
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"},
+				Items:    []v1.Node{node("node-1", "5.15.0-105", "v1.30.2"), node("node-2", "5.15.0-105", "v1.30.2"), node("node-3", "5.15.0-100", "v1.29.6")},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesOSInventorySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesOSInventorySuite) TestNodesOSInventory() {
+	s.InitMcpClient()
+	s.Run("nodes_os_inventory()", func() {
+		toolResult, err := s.CallTool("nodes_os_inventory", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the summary", func() {
+			s.True(strings.HasPrefix(text, "# 3 nodes, 1 with outlier versions, 1 version skew findings\n"), text)
+		})
+		s.Run("returns the outlier nodes", func() {
+			s.Regexp(`kernelVersion\s+5\.15\.0-100\s+1\s+node-3`, text)
+			s.Regexp(`node-3\s+linux\s+amd64\s+Ubuntu 22\.04\.4 LTS\s+5\.15\.0-100\s+containerd://1\.7\.2\s+v1\.29\.6\s+kernelVersion,kubeletVersion`, text)
+		})
+		s.Run("returns the version skew", func() {
+			s.Contains(text, "# API server version: v1.30.4\n")
+			s.Contains(text, "# Version skew: the kubelets run 2 minor versions: 1.29 (1 nodes), 1.30 (2 nodes)")
+		})
+	})
+	s.Run("nodes_os_inventory(output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_os_inventory", map[string]interface{}{"output_format": "json"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		var envelope map[string]any
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("NodeOSInventory", envelope["kind"])
+		s.Len(envelope["items"], 3)
+	})
+}
+
+func TestNodesOSInventory(t *testing.T) {
+	suite.Run(t, new(NodesOSInventorySuite))
+}
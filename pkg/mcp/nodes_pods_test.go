@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NodesPodsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NodesPodsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	pod := func(namespace, name, memory string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "c", Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse(memory)},
+				Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse(memory)},
+			}}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, QOSClass: v1.PodQOSBurstable,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "c", RestartCount: 4}}},
+		}
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/node-1", "/api/v1/nodes/node-2":
+			test.WriteObject(w, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: req.URL.Path[len("/api/v1/nodes/"):]}})
+		case "/api/v1/pods":
+			if req.URL.Query().Get("fieldSelector") != "spec.nodeName=node-1" {
+				test.WriteObject(w, &v1.PodList{})
+				return
+			}
+			test.WriteObject(w, &v1.PodList{Items: []v1.Pod{pod("ns-1", "web", "256Mi"), pod("ns-1", "db", "2Gi")}})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesPodsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesPodsSuite) TestNodesPods() {
+	s.InitMcpClient()
+	s.Run("nodes_pods(name=nil)", func() {
+		toolResult, err := s.CallTool("nodes_pods", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to list node pods, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_pods(name=node-1)", func() {
+		toolResult, err := s.CallTool("nodes_pods", map[string]interface{}{
+			"name": "node-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Regexp(`(?m)^NAMESPACE\s+NAME\s+PHASE\s+RESTARTS\s+CPU REQUEST\s+CPU LIMIT\s+MEMORY REQUEST\s+MEMORY LIMIT\s+QOS\n`+
+			`ns-1\s+db\s+Running\s+4\s+100m\s+-\s+2Gi\s+2Gi\s+Burstable\n`+
+			`ns-1\s+web\s+Running\s+4\s+100m\s+-\s+256Mi\s+256Mi\s+Burstable\n`,
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_pods(name=node-1, sortBy=memory, output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_pods", map[string]interface{}{
+			"name":          "node-1",
+			"sortBy":        "memory",
+			"output_format": "json",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var envelope struct {
+			Kind    string `json:"kind"`
+			Summary string `json:"summary"`
+			Items   []struct {
+				Name     string            `json:"name"`
+				Requests map[string]string `json:"requests"`
+			} `json:"items"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("Pod", envelope.Kind)
+		s.Equal("2 pods scheduled on node node-1", envelope.Summary)
+		s.Require().Len(envelope.Items, 2)
+		s.Equal("db", envelope.Items[0].Name)
+		s.Equal("2Gi", envelope.Items[0].Requests["memory"])
+	})
+	s.Run("nodes_pods(name=node-2)", func() {
+		toolResult, err := s.CallTool("nodes_pods", map[string]interface{}{
+			"name": "node-2",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("No pods scheduled on node node-2", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_pods(name=missing-node)", func() {
+		toolResult, err := s.CallTool("nodes_pods", map[string]interface{}{
+			"name": "missing-node",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to list pods of node missing-node")
+	})
+}
+
+func TestNodesPods(t *testing.T) {
+	suite.Run(t, new(NodesPodsSuite))
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NodesPressureSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NodesPressureSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			if req.URL.Query().Get("labelSelector") == "psi=false" {
+				_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"NodeList","items":[{"metadata":{"name":"cgroup-v1-node"}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"NodeList","items":[` +
+				`{"metadata":{"name":"idle-node"}},{"metadata":{"name":"busy-node"}},` +
+				`{"metadata":{"name":"cgroup-v1-node"}},{"metadata":{"name":"unreachable-node"}}]}`))
+		case "/api/v1/nodes/idle-node/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"nodeName":"idle-node",` +
+				`"cpu":{"usageNanoCores":1000,"psi":{"some":{"avg10":0.5,"avg60":0.25,"avg300":0.1},"full":{"avg10":0,"avg60":0}}},` +
+				`"memory":{"psi":{"some":{"avg10":0,"avg60":0},"full":{"avg10":0,"avg60":0}}},` +
+				`"io":{"psi":{"some":{"avg10":1.5,"avg60":1},"full":{"avg10":0.5,"avg60":0.5}}}}}`))
+		case "/api/v1/nodes/busy-node/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"nodeName":"busy-node",` +
+				`"cpu":{"psi":{"some":{"avg10":12,"avg60":8},"full":{"avg10":0,"avg60":0}}},` +
+				`"memory":{"psi":{"some":{"avg10":35.5,"avg60":20},"full":{"avg10":15,"avg60":9.75}}},` +
+				`"io":{"psi":{"some":{"avg10":2,"avg60":1},"full":{"avg10":1,"avg60":0.5}}}}}`))
+		case "/api/v1/nodes/cgroup-v1-node/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"nodeName":"cgroup-v1-node","cpu":{"usageNanoCores":1000},"memory":{"usageBytes":1000}}}`))
+		case "/api/v1/nodes/unreachable-node/proxy/stats/summary":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesPressureSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesPressureSuite) TestNodesPressureReport() {
+	s.InitMcpClient()
+	s.Run("nodes_pressure_report()", func() {
+		toolResult, err := s.CallTool("nodes_pressure_report", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the nodes under pressure", func() {
+			s.Contains(text, "# 1 of 2 nodes under pressure (avg10 or avg60 >= 10%)\n")
+		})
+		s.Run("ranks the nodes by pressure", func() {
+			s.Regexp(`(?s)NODE +STATUS +PRESSURE +RESOURCE +CPU SOME +CPU FULL +MEMORY SOME +MEMORY FULL +IO SOME +IO FULL\n`+
+				`busy-node +UNDER PRESSURE +35\.50 +memory +12\.00/8\.00 +0\.00/0\.00 +35\.50/20\.00 +15\.00/9\.75 +2\.00/1\.00 +1\.00/0\.50 *\n`+
+				`idle-node +OK +1\.50 +io +0\.50/0\.25 .*\n# PSI values are avg10/avg60`, text)
+		})
+		s.Run("reports nodes without PSI and unreachable nodes as target errors", func() {
+			s.Contains(text, "# Partial result: 2 of 4 targets failed")
+			s.Regexp(`error: PSI metrics not available, they require cgroup v2, Linux 4\.20\+ and the KubeletPSI\s+feature gate\n  target: node/cgroup-v1-node`, text)
+			s.Contains(text, "target: node/unreachable-node")
+		})
+	})
+	s.Run("nodes_pressure_report(threshold=1)", func() {
+		toolResult, err := s.CallTool("nodes_pressure_report", map[string]interface{}{"threshold": 1})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 2 of 2 nodes under pressure (avg10 or avg60 >= 1%)\n")
+	})
+	s.Run("nodes_pressure_report(output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_pressure_report", map[string]interface{}{"output_format": "json"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var envelope struct {
+			Kind  string `json:"kind"`
+			Items []struct {
+				Node     string  `json:"node"`
+				Pressure float64 `json:"pressure"`
+				Resource string  `json:"resource"`
+			} `json:"items"`
+			Errors []map[string]string `json:"errors"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("NodePressure", envelope.Kind)
+		s.Require().Len(envelope.Items, 2)
+		s.Equal("busy-node", envelope.Items[0].Node)
+		s.Equal(35.5, envelope.Items[0].Pressure)
+		s.Equal("memory", envelope.Items[0].Resource)
+		s.Len(envelope.Errors, 2)
+	})
+	s.Run("nodes_pressure_report(label_selector=psi=false)", func() {
+		toolResult, err := s.CallTool("nodes_pressure_report", map[string]interface{}{"label_selector": "psi=false"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed for all targets: node/cgroup-v1-node: PSI metrics not available, they require cgroup v2, Linux 4.20+ and the KubeletPSI feature gate",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestNodesPressure(t *testing.T) {
+	suite.Run(t, new(NodesPressureSuite))
+}
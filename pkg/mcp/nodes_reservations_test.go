@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NodesReservationsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NodesReservationsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"NodeList","items":[` +
+				`{"metadata":{"name":"healthy-node"},"status":{"capacity":{"cpu":"4","memory":"8Gi"},"allocatable":{"cpu":"3500m","memory":"6Gi"}}},` +
+				`{"metadata":{"name":"full-node"},"status":{"capacity":{"cpu":"4","memory":"8Gi"},"allocatable":{"cpu":"3500m","memory":"6Gi"}}},` +
+				`{"metadata":{"name":"unreachable-node"}}]}`))
+		case "/api/v1/nodes/full-node":
+			_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"Node","metadata":{"name":"full-node"},"status":{"capacity":{"cpu":"4","memory":"8Gi"}}}`))
+		case "/api/v1/nodes/healthy-node/proxy/configz", "/api/v1/nodes/full-node/proxy/configz":
+			_, _ = w.Write([]byte(`{"kubeletconfig":{"systemReserved":{"cpu":"500m","memory":"1Gi"},"kubeReserved":{"memory":"512Mi"},` +
+				`"evictionHard":{"memory.available":"100Mi","nodefs.available":"10%"}}}`))
+		case "/api/v1/nodes/healthy-node/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"memory":{"availableBytes":4294967296},"fs":{"availableBytes":53687091200,"capacityBytes":107374182400}}}`))
+		case "/api/v1/nodes/full-node/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"memory":{"availableBytes":4294967296},"fs":{"availableBytes":5368709120,"capacityBytes":107374182400}}}`))
+		case "/api/v1/nodes/unreachable-node/proxy/configz":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesReservationsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesReservationsSuite) TestNodesReservations() {
+	s.InitMcpClient()
+	s.Run("nodes_reservations()", func() {
+		toolResult, err := s.CallTool("nodes_reservations", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the flagged nodes", func() {
+			s.Contains(text, "# 1 of 2 nodes exceed or are within 5% of an eviction threshold\n")
+		})
+		s.Run("ranks the nodes by the severity of their signals", func() {
+			s.Regexp(`(?s)NODE +STATUS +SIGNAL +EVICTION +THRESHOLD +AVAILABLE +CAPACITY +HEADROOM\n`+
+				`full-node +OK +memory\.available +hard +100Mi +4096Mi +8192Mi +48\.8% *\n`+
+				`full-node +EXCEEDED +nodefs\.available +hard +10% +5120Mi +102400Mi +-5\.0% *\n`+
+				`healthy-node +OK +memory\.available +hard +100Mi .*\n`+
+				`healthy-node +OK +nodefs\.available +hard +10% +51200Mi +102400Mi +40\.0% *\n`, text)
+		})
+		s.Run("reports the reserved resources", func() {
+			s.Regexp(`NODE +CPU CAPACITY +CPU ALLOCATABLE +MEMORY CAPACITY +MEMORY ALLOCATABLE +SYSTEM RESERVED +KUBE RESERVED\n`+
+				`full-node +4 +3500m +8Gi +6Gi +cpu=500m,memory=1Gi +memory=512Mi *\n`, text)
+		})
+		s.Run("reports unreachable nodes as target errors", func() {
+			s.Contains(text, "# Partial result: 1 of 3 targets failed")
+			s.Contains(text, "target: node/unreachable-node")
+		})
+	})
+	s.Run("nodes_reservations(name=full-node, output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_reservations", map[string]interface{}{"name": "full-node", "output_format": "json"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var envelope struct {
+			Kind  string `json:"kind"`
+			Items []struct {
+				Node    string `json:"node"`
+				Status  string `json:"status"`
+				Signals []struct {
+					Signal         string `json:"signal"`
+					ThresholdValue int64  `json:"thresholdValue"`
+				} `json:"signals"`
+			} `json:"items"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("NodeReservations", envelope.Kind)
+		s.Require().Len(envelope.Items, 1)
+		s.Equal("exceeded", envelope.Items[0].Status)
+		s.Require().Len(envelope.Items[0].Signals, 2)
+		s.Equal(int64(10737418240), envelope.Items[0].Signals[1].ThresholdValue)
+	})
+	s.Run("nodes_reservations(name=missing-node)", func() {
+		toolResult, err := s.CallTool("nodes_reservations", map[string]interface{}{"name": "missing-node"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get nodes reservations: failed to get node missing-node")
+	})
+}
+
+func TestNodesReservations(t *testing.T) {
+	suite.Run(t, new(NodesReservationsSuite))
+}
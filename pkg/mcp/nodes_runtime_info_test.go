@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type NodesRuntimeInfoSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+	podSelectors     []string
+}
+
+func (s *NodesRuntimeInfoSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.podSelectors = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/node-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			})
+		case "/api/v1/pods":
+			s.podSelectors = append(s.podSelectors, req.URL.Query().Get("fieldSelector"))
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items:    []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "coredns-1", Namespace: "kube-system", UID: types.UID("uid-coredns")}}},
+			})
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		command := pod.Spec.Containers[0].Command
+		switch command[6] {
+		case "ps":
+			return "unix:///run/containerd/containerd.sock\n" + `{"containers":[
+				{"id":"a1b2","metadata":{"name":"coredns","attempt":0},"image":{"image":"registry.k8s.io/coredns:v1.11.1"},"state":"CONTAINER_RUNNING",
+				 "labels":{"io.kubernetes.pod.name":"coredns-1","io.kubernetes.pod.namespace":"kube-system","io.kubernetes.pod.uid":"uid-coredns"}},
+				{"id":"b2c3","metadata":{"name":"app","attempt":2},"image":{"image":"example.com/app:1.0"},"state":"CONTAINER_EXITED",
+				 "labels":{"io.kubernetes.pod.name":"web-1","io.kubernetes.pod.namespace":"shop","io.kubernetes.pod.uid":"uid-deleted"}}
+			]}`
+		case "inspect":
+			return "unix:///run/containerd/containerd.sock\n" + `{"status":{"id":"b2c3","state":"CONTAINER_EXITED","exitCode":137,"reason":"OOMKilled"}}`
+		}
+		return "unix:///run/containerd/containerd.sock\n" + `{"images":[{"id":"sha256:abc","repoTags":["example.com/app:1.0"],"size":"1024"}]}`
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesRuntimeInfoSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesRuntimeInfoSuite) TestNodesRuntimeInfo() {
+	s.InitMcpClient()
+	s.Run("nodes_runtime_info(name=node-1)", func() {
+		toolResult, err := s.CallTool("nodes_runtime_info", map[string]interface{}{"name": "node-1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the containers", func() {
+			s.Contains(text, "# 2 containers on node node-1 (unix:///run/containerd/containerd.sock): 1 running, 1 exited, 1 orphaned\n")
+		})
+		var nodeRuntime kubernetes.NodeRuntime
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &nodeRuntime))
+		s.Run("flags the containers of the pods unknown by the API server", func() {
+			s.Require().Len(nodeRuntime.Containers, 2)
+			s.False(nodeRuntime.Containers[0].Orphaned)
+			s.True(nodeRuntime.Containers[1].Orphaned)
+			s.Equal([]string{"spec.nodeName=node-1"}, s.podSelectors)
+		})
+		s.Require().Len(s.helperPodHandler.Created(), 1)
+		pod := s.helperPodHandler.Created()[0]
+		s.Run("runs crictl in the node root filesystem", func() {
+			command := pod.Spec.Containers[0].Command
+			s.Equal([]string{"chroot", "/host", "sh", "-c"}, command[:4])
+			s.Contains(command[4], `exec crictl --runtime-endpoint "unix://$socket"`)
+			s.Equal([]string{"sh", "ps", "--all", "--output", "json"}, command[5:])
+			s.True(*pod.Spec.Containers[0].SecurityContext.Privileged)
+		})
+	})
+	s.Run("nodes_runtime_info(namespace=shop, output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_runtime_info", map[string]interface{}{"name": "node-1", "namespace": "shop", "output_format": "json"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var envelope struct {
+			Kind    string                        `json:"kind"`
+			Items   []kubernetes.RuntimeContainer `json:"items"`
+			Summary string                        `json:"summary"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("RuntimeContainer", envelope.Kind)
+		s.Require().Len(envelope.Items, 1)
+		s.Equal("web-1", envelope.Items[0].PodName)
+		s.Equal("1 containers on node node-1 (unix:///run/containerd/containerd.sock): 1 exited, 1 orphaned", envelope.Summary)
+	})
+	s.Run("nodes_runtime_info(operation=images)", func() {
+		toolResult, err := s.CallTool("nodes_runtime_info", map[string]interface{}{"name": "node-1", "operation": "images"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 1 images on node node-1 (unix:///run/containerd/containerd.sock)\n")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "sizeBytes: 1024")
+	})
+	s.Run("nodes_runtime_info(operation=inspect, container_id=b2c3)", func() {
+		toolResult, err := s.CallTool("nodes_runtime_info", map[string]interface{}{"name": "node-1", "operation": "inspect", "container_id": "b2c3"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "reason: OOMKilled")
+		command := s.helperPodHandler.Created()[len(s.helperPodHandler.Created())-1].Spec.Containers[0].Command
+		s.Equal([]string{"sh", "inspect", "--output", "json", "b2c3"}, command[5:])
+	})
+	s.Run("nodes_runtime_info(operation=inspect, container_id=--invalid)", func() {
+		toolResult, err := s.CallTool("nodes_runtime_info", map[string]interface{}{"name": "node-1", "operation": "inspect", "container_id": "--all"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to get runtime inspect of node node-1: invalid container id "--all"`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestNodesRuntimeInfo(t *testing.T) {
+	suite.Run(t, new(NodesRuntimeInfoSuite))
+}
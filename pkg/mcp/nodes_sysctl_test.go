@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
+)
+
+type NodesSysctlSuite struct {
+	BaseMcpSuite
+	mockServer             *test.MockServer
+	helperPodHandler       *test.HelperPodHandler
+	helperDaemonSetHandler *test.HelperDaemonSetHandler
+}
+
+func (s *NodesSysctlSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			nodes := &v1.NodeList{Items: []v1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+			}}
+			if req.URL.Query().Get("labelSelector") == "" {
+				nodes.Items = append(nodes.Items, v1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "unreachable-node"},
+					Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionUnknown}}},
+				})
+			}
+			test.WriteObject(w, nodes)
+		case "/api/v1/nodes/node-1", "/api/v1/nodes/node-2":
+			test.WriteObject(w, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: strings.TrimPrefix(req.URL.Path, "/api/v1/nodes/")}})
+		}
+	}))
+	logs := func(nodeName, script string) string {
+		values := map[string]string{
+			"net.netfilter.nf_conntrack_max": "262144",
+			"fs.inotify.max_user_watches":    "1048576",
+			"fs.inotify.max_user_instances":  "8192",
+			"vm.max_map_count":               "262144",
+			"net.core.somaxconn":             "4096",
+		}
+		if nodeName == "node-1" {
+			values["fs.inotify.max_user_watches"] = "8192"
+			values["fs.inotify.max_user_instances"] = "128"
+			values["vm.max_map_count"] = "65530"
+		}
+		// Only return the values of the sysctls read by the helper pod script
+		out := ""
+		for sysctl, value := range values {
+			if strings.Contains(script, "/proc/sys/"+strings.ReplaceAll(sysctl, ".", "/")) {
+				out += sysctl + "=" + value + "\n"
+			}
+		}
+		return out
+	}
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		return logs(pod.Spec.NodeName, pod.Spec.Containers[0].Command[2])
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.helperDaemonSetHandler = &test.HelperDaemonSetHandler{Logs: func(pod *v1.Pod) string {
+		return logs(pod.Spec.NodeName, pod.Spec.InitContainers[0].Command[2])
+	}}
+	s.mockServer.Handle(s.helperDaemonSetHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesSysctlSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesSysctlSuite) TestNodesSysctl() {
+	s.InitMcpClient()
+	s.Run("nodes_sysctl(name=node-1)", func() {
+		toolResult, err := s.CallTool("nodes_sysctl", map[string]interface{}{
+			"name": "node-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns sysctl values", func() {
+			s.True(strings.HasPrefix(text, "# Node sysctls\n- node: node-1\n"), "unexpected result %s", text)
+			s.Contains(text, "    vm.max_map_count: \"65530\"\n")
+		})
+		s.Run("returns findings for values below threshold", func() {
+			s.Contains(text, "# Findings\nfindings:\n")
+			s.Contains(text, "fs.inotify.max_user_instances is 128 on node node-1")
+			s.Contains(text, "fs.inotify.max_user_watches is 8192 on node node-1")
+			s.Contains(text, "vm.max_map_count is 65530 on node node-1")
+			s.NotContains(text, "nf_conntrack_max is")
+			s.True(strings.HasSuffix(text, "tool: nodes_sysctl\n"), "unexpected result %s", text)
+		})
+		s.Run("creates helper pod on the node host network", func() {
+			s.Require().Len(s.helperPodHandler.Created(), 1)
+			pod := s.helperPodHandler.Created()[0]
+			s.Equal("node-1", pod.Spec.NodeName)
+			s.True(pod.Spec.HostNetwork)
+			s.Contains(pod.Spec.Containers[0].Command[2], "cat /proc/sys/vm/max_map_count")
+			s.Equal([]string{pod.Name}, s.helperPodHandler.Deleted())
+		})
+	})
+	s.Run("nodes_sysctl(label_selector=node-role.kubernetes.io/worker=, format=sarif)", func() {
+		toolResult, err := s.CallTool("nodes_sysctl", map[string]interface{}{
+			"label_selector": "node-role.kubernetes.io/worker=",
+			"format":         "sarif",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var log findings.SarifLog
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &log))
+		s.Run("returns findings of all matching nodes", func() {
+			s.Require().Len(log.Runs, 1)
+			s.Len(log.Runs[0].Results, 3)
+		})
+		s.Run("creates a helper daemonset on the matching nodes host network", func() {
+			s.Require().Len(s.helperDaemonSetHandler.Created(), 1)
+			daemonSet := s.helperDaemonSetHandler.Created()[0]
+			s.True(daemonSet.Spec.Template.Spec.HostNetwork)
+			s.Equal([]string{"node-1", "node-2"},
+				daemonSet.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchFields[0].Values)
+			s.Equal([]string{daemonSet.Name}, s.helperDaemonSetHandler.Deleted())
+		})
+	})
+	s.Run("nodes_sysctl(sysctls=[net.core.somaxconn], thresholds={fs.inotify.max_user_instances: 16384})", func() {
+		toolResult, err := s.CallTool("nodes_sysctl", map[string]interface{}{
+			"label_selector": "node-role.kubernetes.io/worker=",
+			"sysctls":        []interface{}{"net.core.somaxconn"},
+			"thresholds":     map[string]interface{}{"fs.inotify.max_user_instances": 16384},
+			"format":         "json",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var report findings.Report
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &report))
+		s.Run("reads provided sysctls and sysctls with thresholds", func() {
+			command := s.helperDaemonSetHandler.Created()[len(s.helperDaemonSetHandler.Created())-1].Spec.Template.Spec.InitContainers[0].Command[2]
+			s.Contains(command, "cat /proc/sys/net/core/somaxconn")
+			s.Contains(command, "cat /proc/sys/fs/inotify/max_user_instances")
+			s.NotContains(command, "cat /proc/sys/vm/max_map_count")
+		})
+		s.Run("applies provided thresholds", func() {
+			s.Require().Len(report.Findings, 2)
+			s.Contains(report.Findings[0].Message, "lower than the recommended 16384")
+			s.Contains(report.Findings[1].Message, "lower than the recommended 16384")
+		})
+	})
+	s.Run("nodes_sysctl() with unreachable node", func() {
+		toolResult, err := s.CallTool("nodes_sysctl", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns partial result", func() {
+			s.Contains(text, "# Partial result: 1 of 3 targets failed\n")
+			s.Contains(text, "error: node unreachable-node is not ready\n  target: node/unreachable-node\n")
+		})
+	})
+	s.Run("nodes_sysctl(sysctls=[invalid;name])", func() {
+		toolResult, err := s.CallTool("nodes_sysctl", map[string]interface{}{
+			"sysctls": []interface{}{"vm.max_map_count; rm -rf /"},
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to audit node sysctls: invalid sysctl name "vm.max_map_count; rm -rf /"`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_sysctl(thresholds={vm.max_map_count: high})", func() {
+		toolResult, err := s.CallTool("nodes_sysctl", map[string]interface{}{
+			"thresholds": map[string]interface{}{"vm.max_map_count": "high"},
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to audit node sysctls, invalid argument thresholds: validating /additionalProperties: type: high has type "string", want "integer"`,
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_sysctl(format=xml)", func() {
+		toolResult, err := s.CallTool("nodes_sysctl", map[string]interface{}{"format": "xml"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to audit node sysctls, invalid argument format: enum: xml does not equal any of: [yaml json sarif]",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestNodesSysctl(t *testing.T) {
+	suite.Run(t, new(NodesSysctlSuite))
+}
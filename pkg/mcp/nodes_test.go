@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 	"testing"
@@ -82,6 +83,9 @@ func (s *NodesSuite) TestNodesLog() {
 			s.Equalf(expectedMessage, toolResult.Content[0].(mcp.TextContent).Text,
 				"expected descriptive error '%s', got %v", expectedMessage, toolResult.Content[0].(mcp.TextContent).Text)
 		})
+		s.Run("has invalid argument error category", func() {
+			s.Equal(map[string]any{"error": "invalid_argument"}, toolResult.StructuredContent)
+		})
 	})
 	s.Run("nodes_log(name=existing-node, query=nil)", func() {
 		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
@@ -97,6 +101,9 @@ func (s *NodesSuite) TestNodesLog() {
 			s.Regexpf(expectedMessage, toolResult.Content[0].(mcp.TextContent).Text,
 				"expected descriptive error '%s', got %v", expectedMessage, toolResult.Content[0].(mcp.TextContent).Text)
 		})
+		s.Run("has invalid argument error category", func() {
+			s.Equal(map[string]any{"error": "invalid_argument"}, toolResult.StructuredContent)
+		})
 	})
 	s.Run("nodes_log(name=inexistent-node, query=/kubelet.log)", func() {
 		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
@@ -113,6 +120,9 @@ func (s *NodesSuite) TestNodesLog() {
 			s.Equalf(expectedMessage, toolResult.Content[0].(mcp.TextContent).Text,
 				"expected descriptive error '%s', got %v", expectedMessage, toolResult.Content[0].(mcp.TextContent).Text)
 		})
+		s.Run("has not found error category", func() {
+			s.Equal(map[string]any{"error": "not_found"}, toolResult.StructuredContent)
+		})
 	})
 	s.Run("nodes_log(name=existing-node, query=/missing.log)", func() {
 		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
@@ -162,6 +172,48 @@ func (s *NodesSuite) TestNodesLog() {
 				"expected log content '%s', got %v", expectedMessage, toolResult.Content[0].(mcp.TextContent).Text)
 		})
 	})
+	s.Run("nodes_log(name=existing-node, query=/kubelet.log, output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
+			"name":          "existing-node",
+			"query":         "/kubelet.log",
+			"tailLines":     2,
+			"output_format": "json",
+		})
+		s.Require().NotNil(toolResult, "toolResult should not be nil")
+		s.Run("no error", func() {
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+			s.Nilf(err, "call tool should not return error object")
+		})
+		s.Run("returns structured envelope", func() {
+			s.JSONEq(`{"kind":"NodeLog","items":["Line 4","Line 5"],"summary":"2 lines of /kubelet.log logs from node existing-node","truncated":true}`,
+				toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("nodes_log(name=existing-node, query=/kubelet.log, tailLines=0, artifact=true, output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
+			"name":          "existing-node",
+			"query":         "/kubelet.log",
+			"tailLines":     0,
+			"artifact":      true,
+			"output_format": "json",
+		})
+		s.Require().NotNil(toolResult, "toolResult should not be nil")
+		s.Run("no error", func() {
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+			s.Nilf(err, "call tool should not return error object")
+		})
+		s.Run("returns the artifact URI and the preview", func() {
+			var envelope struct {
+				Items     []string `json:"items"`
+				Summary   string   `json:"summary"`
+				Truncated bool     `json:"truncated"`
+			}
+			s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+			s.Equal([]string{"Line 1", "Line 2", "Line 3", "Line 4", "Line 5"}, envelope.Items)
+			s.Regexp(`^Full log \(5 lines, 35 bytes\) written to artifact://node-log-existing-node-kubelet.log-\d{8}-\d{6}-[0-9a-f]{8}\.log, `, envelope.Summary)
+			s.True(envelope.Truncated)
+		})
+	})
 	for _, tailCase := range []interface{}{2, int64(2), float64(2)} {
 		s.Run("nodes_log(name=existing-node, query=/kubelet.log, tailLines=2)", func() {
 			toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
@@ -222,6 +274,9 @@ func (s *NodesSuite) TestNodesLogDenied() {
 			s.Regexpf(expectedMessage, msg,
 				"expected descriptive error '%s', got %v", expectedMessage, msg)
 		})
+		s.Run("has denied by config error category", func() {
+			s.Equal(map[string]any{"error": "denied_by_config"}, toolResult.StructuredContent)
+		})
 	})
 }
 
@@ -240,6 +295,26 @@ func (s *NodesSuite) TestNodesStatsSummary() {
 			}`))
 			return
 		}
+		// List Nodes response
+		if req.URL.Path == "/api/v1/nodes" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			if req.URL.Query().Get("labelSelector") == "role=unreachable" {
+				_, _ = w.Write([]byte(`{"apiVersion": "v1", "kind": "NodeList", "items": [{"metadata": {"name": "unreachable-node"}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{
+				"apiVersion": "v1",
+				"kind": "NodeList",
+				"items": [{"metadata": {"name": "existing-node"}}, {"metadata": {"name": "unreachable-node"}}]
+			}`))
+			return
+		}
+		// Unreachable kubelet response
+		if req.URL.Path == "/api/v1/nodes/unreachable-node/proxy/stats/summary" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
 		// Get Stats Summary response
 		if req.URL.Path == "/api/v1/nodes/existing-node/proxy/stats/summary" {
 			w.Header().Set("Content-Type", "application/json")
@@ -269,14 +344,34 @@ func (s *NodesSuite) TestNodesStatsSummary() {
 	s.Run("nodes_stats_summary(name=nil)", func() {
 		toolResult, err := s.CallTool("nodes_stats_summary", map[string]interface{}{})
 		s.Require().NotNil(toolResult, "toolResult should not be nil")
+		s.Run("no error", func() {
+			s.Falsef(toolResult.IsError, "call tool should succeed with partial results")
+			s.Nilf(err, "call tool should not return error object")
+		})
+		s.Run("returns stats summary for reachable nodes", func() {
+			content := toolResult.Content[0].(mcp.TextContent).Text
+			s.Containsf(content, "# Node: existing-node", "expected stats to contain node header, got %v", content)
+			s.Containsf(content, "usageNanoCores", "expected stats to contain CPU metrics, got %v", content)
+		})
+		s.Run("returns target errors for unreachable nodes", func() {
+			content := toolResult.Content[0].(mcp.TextContent).Text
+			s.Containsf(content, "# Partial result: 1 of 2 targets failed", "expected partial result header, got %v", content)
+			s.Regexpf(`(?s)targetErrors:\n- error: .*failed to get node stats summary: .+\n  target: node/unreachable-node`, content,
+				"expected structured target errors, got %v", content)
+		})
+	})
+	s.Run("nodes_stats_summary(label_selector=role=unreachable)", func() {
+		toolResult, err := s.CallTool("nodes_stats_summary", map[string]interface{}{
+			"label_selector": "role=unreachable",
+		})
+		s.Require().NotNil(toolResult, "toolResult should not be nil")
 		s.Run("has error", func() {
 			s.Truef(toolResult.IsError, "call tool should fail")
 			s.Nilf(err, "call tool should not return error object")
 		})
-		s.Run("describes missing name", func() {
-			expectedMessage := "failed to get node stats summary, missing argument name"
-			s.Equalf(expectedMessage, toolResult.Content[0].(mcp.TextContent).Text,
-				"expected descriptive error '%s', got %v", expectedMessage, toolResult.Content[0].(mcp.TextContent).Text)
+		s.Run("describes all failed targets", func() {
+			s.Regexpf("^failed for all targets: node/unreachable-node: failed to get node stats summary: ", toolResult.Content[0].(mcp.TextContent).Text,
+				"expected descriptive error, got %v", toolResult.Content[0].(mcp.TextContent).Text)
 		})
 	})
 	s.Run("nodes_stats_summary(name=inexistent-node)", func() {
@@ -181,6 +181,23 @@ func (s *NodesTopSuite) TestNodesTop() {
 		})
 	})
 
+	s.Run("nodes_top(output_format=json)", func() {
+		toolResult, err := s.CallTool("nodes_top", map[string]interface{}{
+			"output_format": "json",
+		})
+		s.Require().NotNil(toolResult, "toolResult should not be nil")
+		s.Run("no error", func() {
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+			s.Nilf(err, "call tool should not return error object")
+		})
+		s.Run("returns structured envelope", func() {
+			s.JSONEq(`{"kind":"NodeMetrics","summary":"Resource consumption of 2 nodes","truncated":false,"items":[
+				{"name":"node-1","cpuUsageMillicores":500,"cpuPercentage":12,"memoryUsageBytes":2147483648,"memoryPercentage":12},
+				{"name":"node-2","cpuUsageMillicores":1000,"cpuPercentage":25,"memoryUsageBytes":4294967296,"memoryPercentage":25}
+			]}`, toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+
 	s.Run("nodes_top(label_selector=node-role.kubernetes.io/worker=)", func() {
 		toolResult, err := s.CallTool("nodes_top", map[string]interface{}{
 			"label_selector": "node-role.kubernetes.io/worker=",
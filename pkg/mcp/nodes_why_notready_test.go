@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type NodesWhyNotReadySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NodesWhyNotReadySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		Groups: []string{
+			`{"name":"coordination.k8s.io","versions":[{"groupVersion":"coordination.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"coordination.k8s.io/v1","version":"v1"}}`,
+		},
+	})
+	stale := time.Now().Add(-10 * time.Minute)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/coordination.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"coordination.k8s.io/v1","resources":[
+				{"name":"leases","singularName":"","namespaced":true,"kind":"Lease","verbs":["get","list","watch"]}
+			]}`))
+		case "/api/v1/nodes/worker-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+				Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{
+					Type: v1.NodeReady, Status: v1.ConditionUnknown, Reason: "NodeStatusUnknown", Message: "Kubelet stopped posting node status.",
+					LastHeartbeatTime: metav1.NewTime(stale), LastTransitionTime: metav1.NewTime(stale),
+				}}},
+			})
+		case "/apis/coordination.k8s.io/v1/namespaces/kube-node-lease/leases/worker-1":
+			test.WriteObject(w, &coordinationv1.Lease{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "coordination.k8s.io/v1", Kind: "Lease"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-node-lease", Name: "worker-1"},
+				Spec:       coordinationv1.LeaseSpec{RenewTime: &metav1.MicroTime{Time: stale}},
+			})
+		case "/api/v1/pods":
+			test.WriteObject(w, &v1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}})
+		case "/api/v1/nodes/worker-1/proxy/logs", "/api/v1/nodes/worker-1/proxy/stats/summary":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesWhyNotReadySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesWhyNotReadySuite) TestNodesWhyNotReady() {
+	s.InitMcpClient()
+	s.Run("nodes_why_notready(name=worker-1)", func() {
+		toolResult, err := s.CallTool("nodes_why_notready", map[string]interface{}{"name": "worker-1"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# Node worker-1 is NotReady (Unknown for 10m"), text)
+		var readiness kubernetes.NodeReadiness
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &readiness))
+		s.Contains(readiness.Summary, "most likely: The node is down or unreachable from the control plane")
+		s.Require().NotEmpty(readiness.Hypotheses)
+		s.Equal("high", readiness.Hypotheses[0].Likelihood)
+		s.True(strings.HasPrefix(readiness.Hypotheses[0].Cause, "The node is down or unreachable from the control plane"), readiness.Hypotheses[0].Cause)
+		s.Contains(strings.Join(readiness.Hypotheses[0].Evidence, "\n"), "the kubelet is unreachable through the API server proxy")
+		s.Require().NotNil(readiness.Heartbeat)
+		s.True(readiness.Heartbeat.Stale)
+	})
+	s.Run("nodes_why_notready(name=missing) returns error", func() {
+		toolResult, err := s.CallTool("nodes_why_notready", map[string]interface{}{"name": "missing"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze readiness of node missing: failed to get node missing")
+	})
+	s.Run("nodes_why_notready() returns error", func() {
+		toolResult, err := s.CallTool("nodes_why_notready", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze node readiness, ")
+	})
+}
+
+func TestNodesWhyNotReady(t *testing.T) {
+	suite.Run(t, new(NodesWhyNotReadySuite))
+}
@@ -0,0 +1,226 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type OpenShiftSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	requests   map[string]map[string]any
+}
+
+func (s *OpenShiftSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.Cfg.Toolsets = []string{"openshift"}
+	s.requests = map[string]map[string]any{}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{Groups: []string{
+		`{"name":"project.openshift.io","versions":[{"groupVersion":"project.openshift.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"project.openshift.io/v1","version":"v1"}}`,
+		`{"name":"route.openshift.io","versions":[{"groupVersion":"route.openshift.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"route.openshift.io/v1","version":"v1"}}`,
+		`{"name":"build.openshift.io","versions":[{"groupVersion":"build.openshift.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"build.openshift.io/v1","version":"v1"}}`,
+	}})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if body, _ := io.ReadAll(req.Body); len(body) > 0 {
+			request := map[string]any{}
+			_ = json.Unmarshal(body, &request)
+			s.requests[req.Method+" "+req.URL.Path] = request
+		}
+		writeObject := func(obj map[string]any) {
+			test.WriteObject(w, &unstructured.Unstructured{Object: obj})
+		}
+		switch req.URL.Path {
+		case "/apis/project.openshift.io/v1":
+			writeAPIResourceList(w, "project.openshift.io/v1",
+				`{"name":"projects","singularName":"","namespaced":false,"kind":"Project","verbs":["create","delete","get","list"]}`,
+				`{"name":"projectrequests","singularName":"","namespaced":false,"kind":"ProjectRequest","verbs":["create","list"]}`)
+		case "/apis/route.openshift.io/v1":
+			writeAPIResourceList(w, "route.openshift.io/v1",
+				`{"name":"routes","singularName":"","namespaced":true,"kind":"Route","verbs":["create","delete","get","list","patch"]}`)
+		case "/apis/build.openshift.io/v1":
+			writeAPIResourceList(w, "build.openshift.io/v1",
+				`{"name":"builds","singularName":"","namespaced":true,"kind":"Build","verbs":["get","list"]}`,
+				`{"name":"builds/log","singularName":"","namespaced":true,"kind":"BuildLog","verbs":["get"]}`,
+				`{"name":"buildconfigs","singularName":"","namespaced":true,"kind":"BuildConfig","verbs":["get","list"]}`,
+				`{"name":"buildconfigs/instantiate","singularName":"","namespaced":true,"kind":"BuildRequest","verbs":["create"]}`)
+		case "/apis/route.openshift.io/v1/routes", "/apis/route.openshift.io/v1/namespaces/default/routes":
+			writeObject(map[string]any{"apiVersion": "route.openshift.io/v1", "kind": "RouteList", "items": []any{
+				map[string]any{"apiVersion": "route.openshift.io/v1", "kind": "Route", "metadata": map[string]any{"name": "a-route", "namespace": "default"}},
+			}})
+		case "/apis/route.openshift.io/v1/namespaces/default/routes/web":
+			writeObject(map[string]any{"apiVersion": "route.openshift.io/v1", "kind": "Route",
+				"metadata": map[string]any{"name": "web", "namespace": "default"}, "spec": s.requests["PATCH "+req.URL.Path]["spec"]})
+		case "/apis/project.openshift.io/v1/projectrequests":
+			writeObject(map[string]any{"apiVersion": "project.openshift.io/v1", "kind": "Project",
+				"metadata": map[string]any{"name": "a-project"}, "status": map[string]any{"phase": "Active"}})
+		case "/apis/project.openshift.io/v1/projects/a-project":
+			writeObject(map[string]any{"apiVersion": "v1", "kind": "Status", "status": "Success"})
+		case "/apis/build.openshift.io/v1/namespaces/default/buildconfigs/app/instantiate":
+			writeObject(map[string]any{"apiVersion": "build.openshift.io/v1", "kind": "Build",
+				"metadata": map[string]any{"name": "app-3", "namespace": "default"}, "status": map[string]any{"phase": "New"}})
+		case "/apis/build.openshift.io/v1/namespaces/default/buildconfigs/app":
+			writeObject(map[string]any{"apiVersion": "build.openshift.io/v1", "kind": "BuildConfig",
+				"metadata": map[string]any{"name": "app", "namespace": "default"}, "status": map[string]any{"lastVersion": 2}})
+		case "/apis/build.openshift.io/v1/namespaces/default/buildconfigs/never-built":
+			writeObject(map[string]any{"apiVersion": "build.openshift.io/v1", "kind": "BuildConfig",
+				"metadata": map[string]any{"name": "never-built", "namespace": "default"}})
+		case "/apis/build.openshift.io/v1/namespaces/default/builds/app-2/log":
+			_, _ = w.Write([]byte("STEP 1/2: FROM registry.example.com/base\nPush successful (tailLines=" + req.URL.Query().Get("tailLines") + ")\n"))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func writeAPIResourceList(w http.ResponseWriter, groupVersion string, resources ...string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"` + groupVersion + `","resources":[`))
+	for i, r := range resources {
+		if i > 0 {
+			_, _ = w.Write([]byte(","))
+		}
+		_, _ = w.Write([]byte(r))
+	}
+	_, _ = w.Write([]byte(`]}`))
+}
+
+func (s *OpenShiftSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *OpenShiftSuite) TestRoutes() {
+	s.InitMcpClient()
+	s.Run("routes_list", func() {
+		toolResult, err := s.CallTool("routes_list", map[string]interface{}{})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "name: a-route")
+	})
+	s.Run("routes_create(service=nil)", func() {
+		toolResult, err := s.CallTool("routes_create", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to create route, missing argument service", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("routes_create(service=web, port=8080, tls_termination=edge)", func() {
+		toolResult, err := s.CallTool("routes_create", map[string]interface{}{
+			"namespace": "default", "service": "web", "port": "8080", "tls_termination": "edge",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("applies route", func() {
+			s.Equal(map[string]any{
+				"to":   map[string]any{"kind": "Service", "name": "web"},
+				"port": map[string]any{"targetPort": float64(8080)},
+				"tls":  map[string]any{"termination": "edge"},
+			}, s.requests["PATCH /apis/route.openshift.io/v1/namespaces/default/routes/web"]["spec"])
+		})
+		s.Run("returns route", func() {
+			s.Regexp(`^# The following route \(YAML\) has been created or updated successfully`, toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *OpenShiftSuite) TestRoutesDenied() {
+	s.Cfg.DeniedResources = []config.GroupVersionKind{{Group: "route.openshift.io", Version: "v1", Kind: "Route"}}
+	s.InitMcpClient()
+	s.Run("routes_list (denied)", func() {
+		toolResult, err := s.CallTool("routes_list", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "resource not allowed: route.openshift.io/v1, Kind=Route")
+	})
+}
+
+func (s *OpenShiftSuite) TestProjects() {
+	s.InitMcpClient()
+	s.Run("projects(action=create)", func() {
+		toolResult, err := s.CallTool("projects", map[string]interface{}{
+			"action": "create", "name": "a-project", "display_name": "A Project",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("requests project", func() {
+			request := s.requests["POST /apis/project.openshift.io/v1/projectrequests"]
+			s.Equal("ProjectRequest", request["kind"])
+			s.Equal("A Project", request["displayName"])
+		})
+		s.Run("returns project", func() {
+			s.Regexp(`^# The following project \(YAML\) has been created successfully`, toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("projects(action=delete)", func() {
+		toolResult, err := s.CallTool("projects", map[string]interface{}{"action": "delete", "name": "a-project"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("Project a-project deletion requested successfully", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("projects(action=invalid)", func() {
+		toolResult, err := s.CallTool("projects", map[string]interface{}{"action": "invalid", "name": "a-project"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal(`failed to manage project, unsupported action "invalid", supported actions: create, delete`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *OpenShiftSuite) TestBuilds() {
+	s.InitMcpClient()
+	s.Run("builds_start(build_config=app)", func() {
+		toolResult, err := s.CallTool("builds_start", map[string]interface{}{"namespace": "default", "build_config": "app"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("instantiates build config", func() {
+			request := s.requests["POST /apis/build.openshift.io/v1/namespaces/default/buildconfigs/app/instantiate"]
+			s.Equal("BuildRequest", request["kind"])
+		})
+		s.Run("returns build", func() {
+			s.Regexp(`^# Build app-3 started successfully`, toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("builds_log(name=nil, build_config=nil)", func() {
+		toolResult, err := s.CallTool("builds_log", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get build log, missing argument name or build_config", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("builds_log(name=app-2, tail=5)", func() {
+		toolResult, err := s.CallTool("builds_log", map[string]interface{}{"namespace": "default", "name": "app-2", "tail": 5})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "Push successful (tailLines=5)")
+	})
+	s.Run("builds_log(build_config=app) returns latest build logs", func() {
+		toolResult, err := s.CallTool("builds_log", map[string]interface{}{"namespace": "default", "build_config": "app"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "Push successful (tailLines=100)")
+	})
+	s.Run("builds_log(build_config=never-built)", func() {
+		toolResult, err := s.CallTool("builds_log", map[string]interface{}{"namespace": "default", "build_config": "never-built"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get build log: no builds have been started for build config never-built", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestOpenShift(t *testing.T) {
+	suite.Run(t, new(OpenShiftSuite))
+}
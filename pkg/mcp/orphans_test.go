@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type OrphansSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	deletes    []string
+}
+
+func (s *OrphansSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.deletes = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"persistentvolumeclaims","singularName":"","namespaced":true,"kind":"PersistentVolumeClaim","verbs":["get","list","delete"]}`,
+		},
+	})
+	finished := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodDelete:
+			s.mu.Lock()
+			s.deletes = append(s.deletes, req.URL.Path)
+			s.mu.Unlock()
+			test.WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+		case req.URL.Path == "/api/v1/namespaces/ns-1/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "backup-1", CreationTimestamp: finished},
+						Status: v1.PodStatus{Phase: v1.PodSucceeded, ContainerStatuses: []v1.ContainerStatus{{
+							State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: finished}},
+						}}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-1", CreationTimestamp: finished},
+						Spec: v1.PodSpec{Volumes: []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{
+							PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "web-data"},
+						}}}},
+						Status: v1.PodStatus{Phase: v1.PodRunning},
+					},
+				},
+			})
+		case req.URL.Path == "/api/v1/namespaces/ns-1/persistentvolumeclaims":
+			test.WriteObject(w, &v1.PersistentVolumeClaimList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaimList"},
+				Items: []v1.PersistentVolumeClaim{
+					{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-data"}, Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound}},
+					{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "old-data"}, Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending}},
+				},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+// SetupSubTest discards the deletions of the previous subtests
+func (s *OrphansSuite) SetupSubTest() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletes = nil
+}
+
+func (s *OrphansSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *OrphansSuite) recordedDeletes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.deletes...)
+}
+
+func (s *OrphansSuite) TestOrphansReport() {
+	s.InitMcpClient()
+	s.Run("orphans_report(categories=[pvcs, completed-pods])", func() {
+		toolResult, err := s.CallTool("orphans_report", map[string]interface{}{
+			"namespace":  "ns-1",
+			"categories": []interface{}{"pvcs", "completed-pods"},
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# 2 orphaned resources found in namespace ns-1: 1 pvcs, 1 completed-pods\n"), text)
+		var report kubernetes.OrphansReport
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &report))
+		s.Require().Len(report.Orphans, 2)
+		s.Equal("old-data", report.Orphans[0].Name)
+		s.Equal("not mounted by any Pod (phase Pending)", report.Orphans[0].Reason)
+		s.Equal("backup-1", report.Orphans[1].Name)
+		s.True(strings.HasPrefix(report.Orphans[1].Reason, "Succeeded, finished at"), report.Orphans[1].Reason)
+		s.Empty(s.recordedDeletes())
+	})
+	s.Run("orphans_report(older_than=72h) ignores the recently completed Pods", func() {
+		toolResult, err := s.CallTool("orphans_report", map[string]interface{}{
+			"namespace":  "ns-1",
+			"categories": []interface{}{"completed-pods"},
+			"older_than": "72h",
+		})
+		s.Require().NoError(err)
+		s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 0 orphaned resources found in namespace ns-1\n"))
+	})
+	s.Run("orphans_report(older_than=invalid) returns error", func() {
+		toolResult, err := s.CallTool("orphans_report", map[string]interface{}{"older_than": "yesterday"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to report orphans, invalid older_than "yesterday", expected a positive duration (e.g. 6h)`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("orphans_report(categories=[configmaps]) returns error", func() {
+		toolResult, err := s.CallTool("orphans_report", map[string]interface{}{"categories": []interface{}{"configmaps"}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to report orphans, invalid argument categories")
+	})
+}
+
+func (s *OrphansSuite) TestOrphansCleanup() {
+	s.InitMcpClient()
+	s.Run("orphans_cleanup(categories=[completed-pods]) deletes the completed Pods", func() {
+		toolResult, err := s.CallTool("orphans_cleanup", map[string]interface{}{
+			"namespace":  "ns-1",
+			"categories": []interface{}{"completed-pods"},
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# 1 of 1 orphaned resources deleted in namespace ns-1: 1 completed-pods\n"), text)
+		var report kubernetes.OrphansReport
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &report))
+		s.Require().Len(report.Orphans, 1)
+		s.True(report.Orphans[0].Deleted)
+		s.Equal([]string{"/api/v1/namespaces/ns-1/pods/backup-1"}, s.recordedDeletes())
+	})
+	s.Run("orphans_cleanup(dry_run=true) previews the deletions", func() {
+		toolResult, err := s.CallTool("orphans_cleanup", map[string]interface{}{
+			"namespace":  "ns-1",
+			"categories": []interface{}{"pvcs"},
+			"dry_run":    true,
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "1 of 1 orphaned resources would be deleted (server-side dry-run) in namespace ns-1: 1 pvcs")
+	})
+	s.Run("orphans_cleanup(categories=[]) returns error", func() {
+		toolResult, err := s.CallTool("orphans_cleanup", map[string]interface{}{"categories": []interface{}{}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to clean up orphans: the categories to clean up are required, valid categories are: replicasets, pvcs, completed-pods, helpers, services",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Empty(s.recordedDeletes())
+	})
+}
+
+func (s *OrphansSuite) TestOrphansCleanupProfile() {
+	s.Cfg.ToolProfile = "operator"
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	s.Contains(names, "orphans_report")
+	s.NotContains(names, "orphans_cleanup", "orphans_cleanup is only available to the profiles with destructive tools")
+}
+
+func TestOrphans(t *testing.T) {
+	suite.Run(t, new(OrphansSuite))
+}
@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// paginationTools returns the tools to retrieve the remaining chunks of the truncated tool outputs
+func (s *Server) paginationTools() []api.ServerTool {
+	return []api.ServerTool{{
+		Tool: api.Tool{
+			Name: api.ContinueResultToolName,
+			Description: "Get the next chunk of a tool output that was truncated because it exceeded the output size limit. " +
+				"Truncated outputs end with a cursor, only call this tool if the remaining output is needed to answer the user. " +
+				"Cursors can only be used once, in the same session, and expire after a few minutes",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"cursor": {
+						Type:        "string",
+						Description: "Cursor returned at the end of the truncated tool output",
+					},
+				},
+				Required: []string{"cursor"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Continue Result",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(false),
+			},
+		},
+		ClusterAware: ptr.To(false),
+		Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+			cursor, ok := params.GetArguments()["cursor"].(string)
+			if !ok || cursor == "" {
+				return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to continue result, missing argument cursor"))), nil
+			}
+			session, _ := params.Value(sessionContextKey).(*mcp.ServerSession)
+			ret, err := s.pager.Next(cursor, sessionID(session))
+			if err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to continue result: %w", err)), nil
+			}
+			return api.NewToolCallResult(ret, nil), nil
+		},
+	}}
+}
+
+// sessionID returns the ID of the provided MCP session, or an empty string if there's no session
+func sessionID(session *mcp.ServerSession) string {
+	if session == nil {
+		return ""
+	}
+	return session.ID()
+}
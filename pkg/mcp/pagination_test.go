@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type PaginationSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	log        string
+}
+
+func (s *PaginationSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.log = ""
+	for i := 1; i <= 20; i++ {
+		s.log += fmt.Sprintf("Line %02d\n", i)
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/node-1":
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			})
+		case "/api/v1/nodes/node-1/proxy/logs":
+			_, _ = w.Write([]byte(s.log))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.OutputMaxBytes = 64
+}
+
+func (s *PaginationSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+var cursorRegexp = regexp.MustCompile(`call continue_result with cursor "([0-9a-f]+)" to get the next chunk`)
+
+func (s *PaginationSuite) TestPagination() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("nodes_log", map[string]interface{}{"name": "node-1", "query": "kubelet"})
+	s.Require().NoError(err)
+	s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	text := toolResult.Content[0].(mcp.TextContent).Text
+	s.Run("truncates the output at a line boundary", func() {
+		s.True(strings.HasPrefix(text, "Line 01\nLine 02\n"), "unexpected result %s", text)
+		s.Contains(text, "Line 08\n# Output truncated: returned bytes 0-64 of 160, call continue_result with cursor ")
+		s.NotContains(text, "Line 09")
+	})
+	s.Require().Regexp(cursorRegexp, text)
+	cursor := cursorRegexp.FindStringSubmatch(text)[1]
+	s.Run("continue_result(cursor) returns the next chunk", func() {
+		toolResult, err := s.CallTool("continue_result", map[string]interface{}{"cursor": cursor})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# Output continued: bytes 64-128 of 160\nLine 09\n"), "unexpected result %s", text)
+		s.Require().Regexp(cursorRegexp, text)
+		s.Run("continue_result(cursor) returns the last chunk", func() {
+			toolResult, err := s.CallTool("continue_result", map[string]interface{}{"cursor": cursorRegexp.FindStringSubmatch(text)[1]})
+			s.Require().NoError(err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+			s.Equal("# Output continued: bytes 128-160 of 160 (end of output)\nLine 17\nLine 18\nLine 19\nLine 20\n",
+				toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("continue_result(cursor) with used cursor", func() {
+		toolResult, err := s.CallTool("continue_result", map[string]interface{}{"cursor": cursor})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to continue result: invalid or expired cursor, call the original tool again", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("continue_result(cursor=nil)", func() {
+		toolResult, err := s.CallTool("continue_result", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to continue result, missing argument cursor", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("structured output is not truncated", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{"name": "node-1", "query": "kubelet", "output_format": "json"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, `"Line 20"`)
+	})
+}
+
+func (s *PaginationSuite) TestPaginationDisabled() {
+	s.Cfg.OutputMaxBytes = 0
+	s.InitMcpClient()
+	s.Run("returns the whole output", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{"name": "node-1", "query": "kubelet"})
+		s.Require().NoError(err)
+		s.Equal(s.log, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("doesn't register continue_result", func() {
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Require().NoError(err)
+		for _, tool := range tools.Tools {
+			s.NotEqual("continue_result", tool.Name)
+		}
+	})
+}
+
+func TestPagination(t *testing.T) {
+	suite.Run(t, new(PaginationSuite))
+}
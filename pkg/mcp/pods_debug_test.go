@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type PodsDebugSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	debugged   *v1.Pod
+}
+
+func (s *PodsDebugSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.debugged = nil
+	running := &v1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns-1"},
+		Spec:       v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "app", Image: "example.com/app:distroless"}}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/pods/web":
+			if s.debugged == nil {
+				test.WriteObject(w, running)
+				return
+			}
+			// The ephemeral container completes immediately
+			pod := s.debugged.DeepCopy()
+			pod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{{
+				Name:  pod.Spec.EphemeralContainers[0].Name,
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}},
+			}}
+			test.WriteObject(w, pod)
+		case "/api/v1/namespaces/ns-1/pods/web/ephemeralcontainers":
+			pod := &v1.Pod{}
+			body, _ := io.ReadAll(req.Body)
+			if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, pod); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.debugged = pod
+			test.WriteObject(w, pod)
+		case "/api/v1/namespaces/ns-1/pods/web/log":
+			if s.debugged != nil && req.URL.Query().Get("container") == s.debugged.Spec.EphemeralContainers[0].Name {
+				_, _ = w.Write([]byte("LISTEN 0 4096 *:8080 *:* users:((\"app\",pid=1,fd=3))\n"))
+			}
+		case "/api/v1/namespaces/ns-1/pods/pending":
+			pending := running.DeepCopy()
+			pending.Name = "pending"
+			pending.Status.Phase = v1.PodPending
+			test.WriteObject(w, pending)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsDebugSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsDebugSuite) TestPodsDebug() {
+	s.InitMcpClient()
+	s.Run("pods_debug(name=web, namespace=ns-1, target_container=app)", func() {
+		toolResult, err := s.CallTool("pods_debug", map[string]interface{}{
+			"namespace":        "ns-1",
+			"name":             "web",
+			"command":          []interface{}{"ss", "-tlnp"},
+			"target_container": "app",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Require().NotNil(s.debugged)
+		s.Require().Len(s.debugged.Spec.EphemeralContainers, 1)
+		ephemeralContainer := s.debugged.Spec.EphemeralContainers[0]
+		s.Run("adds an ephemeral container targeting the container", func() {
+			s.Regexp("^kubernetes-mcp-server-debug-[a-z0-9]{5}$", ephemeralContainer.Name)
+			s.Equal([]string{"ss", "-tlnp"}, ephemeralContainer.Command)
+			s.Equal("app", ephemeralContainer.TargetContainerName)
+		})
+		s.Run("uses the debug helper image", func() {
+			s.Equal("docker.io/nicolaka/netshoot:v0.14", ephemeralContainer.Image)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the exit code", func() {
+			s.Contains(text, "# Ephemeral container "+ephemeralContainer.Name+" in pod web exited with code 0\n")
+		})
+		var podDebug kubernetes.PodDebug
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &podDebug))
+		s.Run("returns the command output", func() {
+			s.Equal("LISTEN 0 4096 *:8080 *:* users:((\"app\",pid=1,fd=3))\n", podDebug.Output)
+			s.Equal("Completed", podDebug.Reason)
+		})
+	})
+	s.Run("pods_debug(image=custom)", func() {
+		s.debugged = nil
+		_, err := s.CallTool("pods_debug", map[string]interface{}{
+			"namespace": "ns-1", "name": "web", "command": []interface{}{"id"}, "image": "example.com/tools:1.0",
+		})
+		s.Require().NoError(err)
+		s.Require().NotNil(s.debugged)
+		s.Equal("example.com/tools:1.0", s.debugged.Spec.EphemeralContainers[0].Image)
+		s.Empty(s.debugged.Spec.EphemeralContainers[0].TargetContainerName)
+	})
+	s.Run("pods_debug(target_container=missing)", func() {
+		toolResult, err := s.CallTool("pods_debug", map[string]interface{}{
+			"namespace": "ns-1", "name": "web", "command": []interface{}{"ps"}, "target_container": "missing",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to debug pod web in namespace ns-1: target container missing not found in pod web", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("pods_debug(name=pending)", func() {
+		toolResult, err := s.CallTool("pods_debug", map[string]interface{}{"namespace": "ns-1", "name": "pending", "command": []interface{}{"ps"}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to debug pod pending in namespace ns-1: cannot debug a pod that is not running; current phase is Pending",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("pods_debug(command=[])", func() {
+		toolResult, err := s.CallTool("pods_debug", map[string]interface{}{"namespace": "ns-1", "name": "web", "command": []interface{}{}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to debug pod, invalid argument command:")
+	})
+}
+
+func TestPodsDebug(t *testing.T) {
+	suite.Run(t, new(PodsDebugSuite))
+}
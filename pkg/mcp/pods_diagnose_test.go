@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type PodsDiagnoseSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	logQueries []string
+}
+
+func (s *PodsDiagnoseSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.logQueries = nil
+	now := time.Now()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/pods/crashing":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "crashing", Namespace: "ns-1", OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d4f8", Controller: ptr.To(true)},
+				}},
+				Spec: v1.PodSpec{
+					NodeName: "node-1",
+					Containers: []v1.Container{
+						{Name: "app", Image: "example.com/app:1.0", Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("64Mi")},
+							Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")},
+						}},
+						{Name: "proxy", Image: "example.com/proxy:1.0"},
+					},
+				},
+				Status: v1.PodStatus{
+					Phase:    v1.PodRunning,
+					QOSClass: v1.PodQOSBurstable,
+					Conditions: []v1.PodCondition{
+						{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: "ContainersNotReady", Message: "containers with unready status: [app]"},
+					},
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name:         "app",
+							RestartCount: 7,
+							State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+								Reason: "CrashLoopBackOff", Message: "back-off 5m0s restarting failed container=app",
+							}},
+							LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+						},
+						{
+							Name:  "proxy",
+							Ready: true,
+							State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-time.Hour))}},
+						},
+					},
+				},
+			})
+		case "/api/v1/namespaces/ns-1/pods/crashing/log":
+			s.logQueries = append(s.logQueries, req.URL.RawQuery)
+			_, _ = w.Write([]byte("starting\njava.lang.OutOfMemoryError: Java heap space\n"))
+		case "/api/v1/namespaces/ns-1/events":
+			test.WriteObject(w, &v1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []v1.Event{
+					{
+						ObjectMeta:     metav1.ObjectMeta{Name: "backoff", Namespace: "ns-1"},
+						InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "crashing"},
+						Type:           v1.EventTypeWarning,
+						Reason:         "BackOff",
+						Message:        "Back-off restarting failed container app in pod crashing",
+						LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+						Count:          42,
+					},
+					{
+						ObjectMeta:     metav1.ObjectMeta{Name: "pulled", Namespace: "ns-1"},
+						InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "crashing"},
+						Type:           v1.EventTypeNormal,
+						Reason:         "Pulled",
+						Message:        "Container image already present on machine",
+						LastTimestamp:  metav1.NewTime(now.Add(-2 * time.Minute)),
+					},
+				},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsDiagnoseSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsDiagnoseSuite) TestPodsDiagnose() {
+	s.InitMcpClient()
+	s.Run("pods_diagnose(name=crashing, namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("pods_diagnose", map[string]interface{}{"namespace": "ns-1", "name": "crashing"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the Pod health", func() {
+			s.Contains(text, "# Pod crashing is CrashLoopBackOff (1/2 containers ready, 7 restarts), container app terminated with exit code 137 (OOMKilled)\n")
+		})
+		var diagnostic kubernetes.PodDiagnostic
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &diagnostic))
+		s.Run("returns the describe-style status", func() {
+			s.Equal("ReplicaSet/web-5d4f8", diagnostic.Owner)
+			s.Equal("node-1", diagnostic.Node)
+			s.Equal(v1.PodQOSBurstable, diagnostic.QOSClass)
+			s.Require().Len(diagnostic.Conditions, 1)
+			s.Equal("ContainersNotReady", diagnostic.Conditions[0].Reason)
+		})
+		s.Run("returns the container states and resources", func() {
+			s.Require().Len(diagnostic.Containers, 2)
+			app := diagnostic.Containers[0]
+			s.Equal("Waiting: CrashLoopBackOff back-off 5m0s restarting failed container=app", app.State)
+			s.Equal("Terminated: OOMKilled (exit code 137)", app.LastState)
+			s.Equal(int32(7), app.RestartCount)
+			s.Equal(map[string]string{"memory": "128Mi"}, app.Limits)
+			s.Equal(map[string]string{"memory": "64Mi"}, app.Requests)
+			s.Regexp(`^Running since `, diagnostic.Containers[1].State)
+		})
+		s.Run("explains the terminations", func() {
+			s.Require().Len(diagnostic.Terminations, 1)
+			s.Contains(diagnostic.Terminations[0].Explanation, "OOM killer")
+		})
+		s.Run("returns the previous logs of the restarted containers", func() {
+			s.Equal("starting\njava.lang.OutOfMemoryError: Java heap space\n", diagnostic.Containers[0].Logs)
+			s.True(diagnostic.Containers[0].LogsPrevious)
+			s.Empty(diagnostic.Containers[1].Logs)
+			s.Equal([]string{"container=app&previous=true&tailLines=50"}, s.logQueries)
+		})
+		s.Run("returns only the Warning events", func() {
+			s.Require().Len(diagnostic.Events, 1)
+			s.Equal("BackOff", diagnostic.Events[0].Reason)
+			s.Equal(int32(42), diagnostic.Events[0].Count)
+		})
+	})
+	s.Run("pods_diagnose(tail=5)", func() {
+		s.logQueries = nil
+		toolResult, err := s.CallTool("pods_diagnose", map[string]interface{}{"namespace": "ns-1", "name": "crashing", "tail": 5})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal([]string{"container=app&previous=true&tailLines=5"}, s.logQueries)
+	})
+	s.Run("pods_diagnose(name=missing)", func() {
+		toolResult, err := s.CallTool("pods_diagnose", map[string]interface{}{"namespace": "ns-1", "name": "missing"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to diagnose pod missing in namespace ns-1:")
+	})
+}
+
+func TestPodsDiagnose(t *testing.T) {
+	suite.Run(t, new(PodsDiagnoseSuite))
+}
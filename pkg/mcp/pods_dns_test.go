@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type PodsDNSSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *PodsDNSSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/pods/running-pod":
+			test.WriteObject(w, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "running-pod"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app"}}, DNSPolicy: v1.DNSClusterFirst},
+				Status:     v1.PodStatus{Phase: v1.PodRunning},
+			})
+		case "/api/v1/namespaces/default/pods/pending-pod":
+			test.WriteObject(w, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pending-pod"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app"}}, DNSPolicy: v1.DNSClusterFirst, HostNetwork: true},
+				Status:     v1.PodStatus{Phase: v1.PodPending},
+			})
+		case "/api/v1/namespaces/default/pods/running-pod/exec":
+			var stdin, stdout bytes.Buffer
+			ctx, err := test.CreateHTTPStreams(w, req, &test.StreamOptions{Stdin: &stdin, Stdout: &stdout})
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(err.Error()))
+				return
+			}
+			defer func(conn io.Closer) { _ = conn.Close() }(ctx.Closer)
+			if strings.Join(req.URL.Query()["command"], " ") == "cat /etc/resolv.conf" {
+				_, _ = io.WriteString(ctx.StdoutStream, "search default.svc.cluster.local svc.cluster.local cluster.local\n"+
+					"nameserver 10.96.0.10\noptions ndots:5\n")
+			}
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsDNSSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsDNSSuite) TestPodsDNS() {
+	s.InitMcpClient()
+	s.Run("pods_dns(name=nil)", func() {
+		toolResult, err := s.CallTool("pods_dns", map[string]interface{}{})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to inspect pod DNS, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("pods_dns(name=running-pod)", func() {
+		toolResult, err := s.CallTool("pods_dns", map[string]interface{}{
+			"name": "running-pod",
+		})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# Pod DNS configuration\n"), "unexpected result %s", text)
+		var podDNS kubernetes.PodDNS
+		s.Require().NoError(yaml.Unmarshal([]byte(strings.TrimPrefix(text, "# Pod DNS configuration\n")), &podDNS))
+		s.Run("reads resolv.conf from the container", func() {
+			s.Require().NotNil(podDNS.ResolvConf)
+			s.Equal([]string{"10.96.0.10"}, podDNS.ResolvConf.Nameservers)
+			s.Len(podDNS.ResolvConf.Searches, 3)
+		})
+		s.Run("returns effective ndots", func() {
+			s.Equal(5, podDNS.Ndots)
+		})
+		s.Run("diagnoses ndots latency", func() {
+			s.Require().Len(podDNS.Diagnostics, 1)
+			s.Contains(podDNS.Diagnostics[0], "ndots is 5")
+		})
+	})
+	s.Run("pods_dns(name=pending-pod)", func() {
+		toolResult, err := s.CallTool("pods_dns", map[string]interface{}{
+			"name": "pending-pod",
+		})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var podDNS kubernetes.PodDNS
+		s.Require().NoError(yaml.Unmarshal([]byte(strings.TrimPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# Pod DNS configuration\n")), &podDNS))
+		s.Run("reports why resolv.conf couldn't be read", func() {
+			s.Nil(podDNS.ResolvConf)
+			s.Equal("pod is not running (phase Pending)", podDNS.ResolvConfError)
+		})
+		s.Run("diagnoses hostNetwork with ClusterFirst", func() {
+			s.Require().Len(podDNS.Diagnostics, 1)
+			s.Contains(podDNS.Diagnostics[0], "ClusterFirstWithHostNet")
+		})
+	})
+	s.Run("pods_dns(name=missing-pod)", func() {
+		toolResult, err := s.CallTool("pods_dns", map[string]interface{}{
+			"name": "missing-pod",
+		})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to inspect pod missing-pod DNS in namespace")
+	})
+}
+
+func TestPodsDNS(t *testing.T) {
+	suite.Run(t, new(PodsDNSSuite))
+}
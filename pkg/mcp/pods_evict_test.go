@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type PodsEvictSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	requests   []string
+}
+
+func (s *PodsEvictSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.requests = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		Groups: []string{
+			`{"name":"policy","versions":[{"groupVersion":"policy/v1","version":"v1"}],"preferredVersion":{"groupVersion":"policy/v1","version":"v1"}}`,
+		},
+	})
+	pod := func(name string) *v1.Pod {
+		return &v1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns-1", Labels: map[string]string{"app": "web"}},
+		}
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Records the method, path, and the deletion options of the mutating requests
+		if req.Method != http.MethodGet {
+			// Typed clients send the delete options protobuf encoded in the request body, and the evictions JSON encoded
+			body, _ := io.ReadAll(req.Body)
+			deleteOptions := &metav1.DeleteOptions{}
+			if strings.HasSuffix(req.URL.Path, "/eviction") {
+				eviction := &policyv1.Eviction{DeleteOptions: deleteOptions}
+				if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, eviction); err == nil && eviction.DeleteOptions != nil {
+					deleteOptions = eviction.DeleteOptions
+				}
+			} else {
+				_, _, _ = scheme.Codecs.UniversalDeserializer().Decode(body, nil, deleteOptions)
+			}
+			record := req.Method + " " + req.URL.Path
+			if deleteOptions.GracePeriodSeconds != nil {
+				record += " gracePeriodSeconds=" + strconv.FormatInt(*deleteOptions.GracePeriodSeconds, 10)
+			}
+			if len(deleteOptions.DryRun) > 0 {
+				record += " dryRun=" + deleteOptions.DryRun[0]
+			}
+			s.mu.Lock()
+			s.requests = append(s.requests, record)
+			s.mu.Unlock()
+		}
+		switch req.URL.Path {
+		case "/apis/policy/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"policy/v1","resources":[
+				{"name":"poddisruptionbudgets","singularName":"","namespaced":true,"kind":"PodDisruptionBudget","verbs":["get","list"]}
+			]}`))
+		case "/apis/policy/v1/namespaces/ns-1/poddisruptionbudgets":
+			test.WriteObject(w, &policyv1.PodDisruptionBudgetList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "policy/v1", Kind: "PodDisruptionBudgetList"},
+				Items: []policyv1.PodDisruptionBudget{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "db-pdb", Namespace: "ns-1"},
+						Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "web-pdb", Namespace: "ns-1"},
+						Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+						Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0, CurrentHealthy: 2, DesiredHealthy: 2, ExpectedPods: 2},
+					},
+				},
+			})
+		case "/api/v1/namespaces/ns-1/pods/web-1", "/api/v1/namespaces/ns-1/pods/web-2":
+			test.WriteObject(w, pod(req.URL.Path[len("/api/v1/namespaces/ns-1/pods/"):]))
+		case "/api/v1/namespaces/ns-1/pods/web-1/eviction":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+		case "/api/v1/namespaces/ns-1/pods/web-2/eviction":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure",` +
+				`"message":"Cannot evict pod as it would violate the pod's disruption budget.","reason":"TooManyRequests","code":429,` +
+				`"details":{"causes":[{"reason":"DisruptionBudget","message":"The disruption budget web-pdb needs 2 healthy pods and has 2 currently"}]}}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsEvictSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsEvictSuite) recordedRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *PodsEvictSuite) TestPodsDeleteEvict() {
+	s.InitMcpClient()
+	s.Run("pods_delete(evict=true)", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{"namespace": "ns-1", "name": "web-1", "evict": true})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("Pod evicted successfully", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Run("uses the Eviction API", func() {
+			s.Equal([]string{"POST /api/v1/namespaces/ns-1/pods/web-1/eviction"}, s.recordedRequests())
+		})
+	})
+	s.Run("pods_delete(evict=true) blocked by PodDisruptionBudget", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{"namespace": "ns-1", "name": "web-2", "evict": true})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to evict pod web-2 in namespace ns-1: eviction blocked by PodDisruptionBudget "+
+			"web-pdb (disruptionsAllowed: 0, currentHealthy: 2, desiredHealthy: 2, expectedPods: 2): "+
+			"Cannot evict pod as it would violate the pod's disruption budget.",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *PodsEvictSuite) TestPodsDeleteGracePeriod() {
+	s.InitMcpClient()
+	s.Run("pods_delete(grace_period_seconds=0)", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{"namespace": "ns-1", "name": "web-1", "grace_period_seconds": 0})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("Pod deleted successfully", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Equal([]string{"DELETE /api/v1/namespaces/ns-1/pods/web-1 gracePeriodSeconds=0"}, s.recordedRequests())
+	})
+	s.Run("pods_delete(evict=true, grace_period_seconds=5, dry_run=true)", func() {
+		s.mu.Lock()
+		s.requests = nil
+		s.mu.Unlock()
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{
+			"namespace":            "ns-1",
+			"name":                 "web-1",
+			"evict":                true,
+			"grace_period_seconds": 5,
+			"dry_run":              true,
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal([]string{"POST /api/v1/namespaces/ns-1/pods/web-1/eviction gracePeriodSeconds=5 dryRun=All"}, s.recordedRequests())
+	})
+}
+
+func TestPodsEvict(t *testing.T) {
+	suite.Run(t, new(PodsEvictSuite))
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type PodsImagePullDebugSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *PodsImagePullDebugSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+			`{"name":"secrets","singularName":"","namespaced":true,"kind":"Secret","verbs":["get","list"]}`,
+			`{"name":"serviceaccounts","singularName":"","namespaced":true,"kind":"ServiceAccount","verbs":["get","list"]}`,
+		},
+	})
+	pullError := `failed to pull and unpack image "quay.io/org/private:1.0": failed to authorize: failed to fetch anonymous token: unexpected status: 401 UNAUTHORIZED`
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/pods/private":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "private", Namespace: "ns-1"},
+				Spec: v1.PodSpec{
+					NodeName:         "node-1",
+					Containers:       []v1.Container{{Name: "app", Image: "quay.io/org/private:1.0"}},
+					ImagePullSecrets: []v1.LocalObjectReference{{Name: "quay-pull"}},
+				},
+				Status: v1.PodStatus{
+					Phase: v1.PodPending,
+					ContainerStatuses: []v1.ContainerStatus{{
+						Name:  "app",
+						Image: "quay.io/org/private:1.0",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: `Back-off pulling image "quay.io/org/private:1.0"`}},
+					}},
+				},
+			})
+		case "/api/v1/namespaces/ns-1/serviceaccounts/default":
+			test.WriteObject(w, &v1.ServiceAccount{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns-1"},
+			})
+		case "/api/v1/namespaces/ns-1/secrets":
+			test.WriteObject(w, &v1.SecretList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "SecretList"},
+				Items: []v1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "quay-robot", Namespace: "ns-1"},
+					Type:       v1.SecretTypeDockerConfigJson,
+					Data:       map[string][]byte{v1.DockerConfigJsonKey: []byte(`{"auths":{"quay.io":{"auth":"c2VjcmV0LXZhbHVl"}}}`)},
+				}},
+			})
+		case "/api/v1/namespaces/ns-1/events":
+			test.WriteObject(w, &v1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []v1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "failed", Namespace: "ns-1"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "private"},
+					Type:           v1.EventTypeWarning,
+					Reason:         "Failed",
+					Message:        `Failed to pull image "quay.io/org/private:1.0": ` + pullError,
+					LastTimestamp:  metav1.NewTime(time.Now().Add(-time.Minute)),
+				}},
+			})
+		case "/api/v1/nodes/node-1/proxy/logs":
+			_, _ = w.Write([]byte(`E0110 12:00:00.000000 1 log.go:32] "PullImage from image service failed" err="` + pullError + `" image="quay.io/org/private:1.0"` + "\n"))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsImagePullDebugSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsImagePullDebugSuite) TestPodsImagePullDebug() {
+	s.InitMcpClient()
+	s.Run("pods_image_pull_debug(namespace=ns-1, name=private)", func() {
+		toolResult, err := s.CallTool("pods_image_pull_debug", map[string]interface{}{"namespace": "ns-1", "name": "private"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# Pod private has 1 container failing to pull its image (ImagePullBackOff), most likely: The registry rejected the pull credentials"), text)
+		var pull kubernetes.PodImagePull
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &pull))
+		s.Require().Len(pull.Containers, 1)
+		s.Equal("quay.io", pull.Containers[0].Registry)
+		s.Equal("org/private", pull.Containers[0].Repository)
+		pullSecrets := map[string]kubernetes.ImagePullSecret{}
+		for _, secret := range pull.PullSecrets {
+			pullSecrets[secret.Name] = secret
+		}
+		s.Equal("not found in namespace ns-1", pullSecrets["quay-pull"].Issue)
+		s.Require().NotEmpty(pull.Hypotheses)
+		s.Contains(pull.Hypotheses[0].Evidence,
+			"the image pull secret quay-robot of the namespace has credentials for quay.io but it's not referenced by the Pod nor its ServiceAccount")
+		s.Run("doesn't reveal the credentials", func() {
+			s.NotContains(text, "c2VjcmV0LXZhbHVl")
+		})
+	})
+	s.Run("pods_image_pull_debug(name=missing) returns error", func() {
+		toolResult, err := s.CallTool("pods_image_pull_debug", map[string]interface{}{"namespace": "ns-1", "name": "missing"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze pod missing image pull in namespace ns-1: ")
+	})
+	s.Run("pods_image_pull_debug() returns error", func() {
+		toolResult, err := s.CallTool("pods_image_pull_debug", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze pod image pull, ")
+	})
+}
+
+func TestPodsImagePullDebug(t *testing.T) {
+	suite.Run(t, new(PodsImagePullDebugSuite))
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
+)
+
+type PodsLifecycleSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *PodsLifecycleSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/namespaces/default/pods/mesh-pod" {
+			return
+		}
+		test.WriteObject(w, &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mesh-pod"},
+			Spec: v1.PodSpec{
+				InitContainers: []v1.Container{
+					{Name: "migrate-db"},
+					{Name: "istio-proxy", RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways), StartupProbe: &v1.Probe{}},
+				},
+				Containers: []v1.Container{{Name: "app"}},
+			},
+		})
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsLifecycleSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsLifecycleSuite) TestPodsLifecycle() {
+	s.InitMcpClient()
+	s.Run("pods_lifecycle(name=nil)", func() {
+		toolResult, err := s.CallTool("pods_lifecycle", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to analyze pod lifecycle, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("pods_lifecycle(name=mesh-pod)", func() {
+		toolResult, err := s.CallTool("pods_lifecycle", map[string]interface{}{
+			"name": "mesh-pod",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns startup order", func() {
+			s.True(strings.HasPrefix(text, "# Pod startup and shutdown order\n"), "unexpected result %s", text)
+			s.Contains(text, "- 1. Init container migrate-db runs to completion")
+			s.Contains(text, "- 2. Native sidecar istio-proxy starts and keeps running")
+		})
+		s.Run("returns shutdown order", func() {
+			s.Contains(text, "native sidecars istio-proxy are stopped")
+		})
+		s.Run("returns findings", func() {
+			s.Contains(text, "# Findings\n")
+			s.Contains(text, "ruleId: LIFECYCLE001")
+		})
+	})
+	s.Run("pods_lifecycle(name=mesh-pod, format=json)", func() {
+		toolResult, err := s.CallTool("pods_lifecycle", map[string]interface{}{
+			"name":   "mesh-pod",
+			"format": "json",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var report findings.Report
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &report))
+		s.Run("returns findings report", func() {
+			s.Equal("pods_lifecycle", report.Tool)
+			s.Require().Len(report.Findings, 1)
+			s.Equal("LIFECYCLE001", report.Findings[0].RuleID)
+			s.Equal("mesh-pod", report.Findings[0].Resource.Name)
+		})
+	})
+	s.Run("pods_lifecycle(name=missing-pod)", func() {
+		toolResult, err := s.CallTool("pods_lifecycle", map[string]interface{}{
+			"name": "missing-pod",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze pod missing-pod lifecycle in namespace")
+	})
+}
+
+func TestPodsLifecycle(t *testing.T) {
+	suite.Run(t, new(PodsLifecycleSuite))
+}
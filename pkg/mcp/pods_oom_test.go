@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type PodsOOMReportSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *PodsOOMReportSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-1"},
+					Spec: v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "web", Resources: v1.ResourceRequirements{
+						Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+					}}}},
+					Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{
+						Name: "web", RestartCount: 2,
+						LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+					}}},
+				}},
+			})
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"},
+				Items:    []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}},
+			})
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"memory":{"availableBytes":1073741824}},"pods":[{"podRef":{"name":"web-1","namespace":"ns-1"},` +
+				`"containers":[{"name":"web","memory":{"workingSetBytes":104857600}}]}]}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsOOMReportSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsOOMReportSuite) TestPodsOOMReport() {
+	s.InitMcpClient()
+	s.Run("pods_oom_report(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("pods_oom_report", map[string]interface{}{"namespace": "ns-1"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the summary", func() {
+			s.True(strings.HasPrefix(text, "# 1 containers OOMKilled, 0 restarting (5+ restarts), 0 close to their memory limit in namespace ns-1\n"), text)
+		})
+		s.Run("returns the recommendation", func() {
+			s.Contains(text, "increase the memory limit of the container above 512Mi (e.g. 768Mi)")
+		})
+		s.Run("returns the memory of the node", func() {
+			s.Contains(text, "memoryAvailable: 1Gi")
+		})
+	})
+	s.Run("pods_oom_report(restart_threshold=0)", func() {
+		toolResult, _ := s.CallTool("pods_oom_report", map[string]interface{}{"restart_threshold": 0})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "failed to report OOMKilled pods, "), toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestPodsOOMReport(t *testing.T) {
+	suite.Run(t, new(PodsOOMReportSuite))
+}
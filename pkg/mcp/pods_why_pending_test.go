@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type PodsWhyPendingSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *PodsWhyPendingSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	pending := &v1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "ns-1"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "app", Image: "example.com/app:1.0", Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+			}}},
+			Volumes: []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+			}}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			Conditions: []v1.PodCondition{{
+				Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable",
+				Message: "0/2 nodes are available: pod has unbound immediate PersistentVolumeClaims.",
+			}},
+		},
+	}
+	node := func(name, cpu string, taints ...v1.Taint) v1.Node {
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{Taints: taints},
+			Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse("8Gi"), v1.ResourcePods: resource.MustParse("110"),
+			}},
+		}
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+			`{"name":"persistentvolumeclaims","singularName":"","namespaced":true,"kind":"PersistentVolumeClaim","verbs":["get","list","watch"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/pods/pending":
+			test.WriteObject(w, pending)
+		case "/api/v1/namespaces/ns-1/pods/running":
+			running := pending.DeepCopy()
+			running.Name = "running"
+			running.Spec.NodeName = "worker-1"
+			running.Status.Phase = v1.PodRunning
+			test.WriteObject(w, running)
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"},
+				Items: []v1.Node{
+					node("control-plane", "4", v1.Taint{Key: "node-role.kubernetes.io/control-plane", Effect: v1.TaintEffectNoSchedule}),
+					node("worker-1", "1"),
+				},
+			})
+		case "/api/v1/pods":
+			test.WriteObject(w, &v1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}, Items: []v1.Pod{*pending}})
+		case "/api/v1/namespaces/ns-1/persistentvolumeclaims/data":
+			test.WriteObject(w, &v1.PersistentVolumeClaim{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "ns-1"},
+				Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: ptr.To("fast")},
+				Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+			})
+		case "/api/v1/namespaces/ns-1/events":
+			test.WriteObject(w, &v1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []v1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "failed-scheduling", Namespace: "ns-1"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pending"},
+					Type:           v1.EventTypeWarning,
+					Reason:         "FailedScheduling",
+					Message:        "0/2 nodes are available: pod has unbound immediate PersistentVolumeClaims.",
+					LastTimestamp:  metav1.NewTime(time.Now().Add(-time.Minute)),
+				}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *PodsWhyPendingSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsWhyPendingSuite) TestPodsWhyPending() {
+	s.InitMcpClient()
+	s.Run("pods_why_pending(name=pending, namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("pods_why_pending", map[string]interface{}{"namespace": "ns-1", "name": "pending"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the nodes ruled out", func() {
+			s.Contains(text, "# Pod pending is Pending, 0/2 nodes are available: "+
+				"1 Insufficient cpu, 1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }.\n")
+		})
+		var scheduling kubernetes.PodScheduling
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &scheduling))
+		s.Run("returns the reasons of each node", func() {
+			s.Equal([]kubernetes.NodeScheduling{
+				{Node: "control-plane", Reasons: []string{"untolerated taint node-role.kubernetes.io/control-plane:NoSchedule"}},
+				{Node: "worker-1", Reasons: []string{"insufficient cpu: requested 2, available 1 of 1 allocatable"}},
+			}, scheduling.RuledOutNodes)
+		})
+		s.Run("returns the unbound PersistentVolumeClaims", func() {
+			s.Equal([]string{"PersistentVolumeClaim data is Pending (storage class fast)"}, scheduling.Issues)
+		})
+		s.Run("returns the scheduler message and events", func() {
+			s.Equal("0/2 nodes are available: pod has unbound immediate PersistentVolumeClaims.", scheduling.SchedulerMessage)
+			s.Require().Len(scheduling.Events, 1)
+			s.Equal("FailedScheduling", scheduling.Events[0].Reason)
+		})
+	})
+	s.Run("pods_why_pending(name=running)", func() {
+		toolResult, err := s.CallTool("pods_why_pending", map[string]interface{}{"namespace": "ns-1", "name": "running"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# Pod running is not pending, it's scheduled on node worker-1 (phase Running)\n")
+	})
+	s.Run("pods_why_pending(name=missing)", func() {
+		toolResult, err := s.CallTool("pods_why_pending", map[string]interface{}{"namespace": "ns-1", "name": "missing"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze pod missing scheduling in namespace ns-1:")
+	})
+}
+
+func TestPodsWhyPending(t *testing.T) {
+	suite.Run(t, new(PodsWhyPendingSuite))
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// WithMutationPolicy adds a policy evaluated before the mutating tools are invoked, in addition to the policies of the
+// configuration. Programs embedding the server can use it to provide their own policy engines (e.g. Rego).
+func WithMutationPolicy(policy api.MutationPolicy) ServerOption {
+	return func(s *Server) {
+		s.policies = append(s.policies, policy)
+	}
+}
+
+// evaluatePolicies checks the call of a mutating tool against the mutation policies, it returns the result of the
+// denied calls or nil if the call is allowed.
+// The empty namespaces of the namespaced targets are resolved to the default namespace of the cluster.
+func (s *Server) evaluatePolicies(ctx context.Context, session *mcp.ServerSession, cluster string, tool api.ServerTool, toolCallRequest *ToolCallRequest) *api.ToolCallResult {
+	if len(s.policies) == 0 || ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
+		return nil
+	}
+	requests, err := api.NewMutationRequests(tool, toolCallRequest)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to evaluate the policies of %s: %w", tool.Tool.Name, err))
+	}
+	k, err := s.derivedKubernetes(ctx, session, cluster)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to evaluate the policies of %s: %w", tool.Tool.Name, err))
+	}
+	takesNamespace := tool.Tool.InputSchema != nil && tool.Tool.InputSchema.Properties["namespace"] != nil
+	var reasons, failures []string
+	for _, request := range requests {
+		if request.Namespace == "" {
+			if request.Object != nil {
+				gv, _ := schema.ParseGroupVersion(request.APIVersion)
+				mapping, err := k.AccessControlClientset().RESTMapper().RESTMapping(gv.WithKind(request.Kind).GroupKind(), gv.Version)
+				if err == nil && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+					request.Namespace = k.NamespaceOrDefault("")
+				}
+			} else if takesNamespace {
+				request.Namespace = k.NamespaceOrDefault("")
+			}
+		}
+		for _, policy := range s.policies {
+			denied, err := policy.Evaluate(ctx, request)
+			if err != nil {
+				failures = append(failures, strings.ReplaceAll(err.Error(), "\n", "; "))
+			}
+			reasons = append(reasons, denied...)
+		}
+	}
+	if len(reasons) == 0 && len(failures) == 0 {
+		return nil
+	}
+	klog.FromContext(ctx).Info("policy: tool call denied", "reasons", reasons, "failures", failures)
+	// the policies fail closed, the ones that can't be evaluated deny the call but are reported apart from the violations
+	var message []string
+	if len(reasons) > 0 {
+		message = append(message, "denied by policy: "+strings.Join(reasons, "; "))
+	}
+	if len(failures) > 0 {
+		message = append(message, "denied by policies that failed to evaluate: "+strings.Join(failures, "; "))
+	}
+	return api.NewToolCallResult("", api.NewCategorizedError(api.ErrorCategoryDeniedByConfig,
+		fmt.Errorf("%s %s", tool.Tool.Name, strings.Join(message, ", "))))
+}
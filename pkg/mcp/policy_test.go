@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type PolicySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	requests   []string
+}
+
+func (s *PolicySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.requests = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/") {
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, req.Method+" "+req.URL.Path)
+		s.mu.Unlock()
+		if req.URL.Path == "/api/v1/namespaces/ns-1/pods/pod-1" && req.Method == http.MethodGet {
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "pod-1"},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		[[policies]]
+		name = "no-default-namespace"
+		expression = 'request.namespace != "default"'
+		message = "the default namespace can't be modified"
+
+		[[policies]]
+		name = "no-host-network"
+		expression = 'object == null || !has(object.spec) || !has(object.spec.hostNetwork) || !object.spec.hostNetwork'
+		message = "Pods can't use the host network"
+		tools = ["resources_*"]
+	`), s.Cfg), "Expected to parse policies config")
+}
+
+func (s *PolicySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PolicySuite) recordedRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *PolicySuite) TestPolicies() {
+	s.InitMcpClient()
+	s.Run("pods_delete(namespace=default) is denied", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{"namespace": "default", "name": "pod-1"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("pods_delete denied by policy: no-default-namespace: the default namespace can't be modified",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Empty(s.recordedRequests(), "denied calls must not reach the API server")
+	})
+	s.Run("pods_delete() in the default namespace is denied", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{"name": "pod-1"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "no-default-namespace: the default namespace can't be modified")
+	})
+	s.Run("pods_delete(namespace=default, dry_run=true) is denied", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{"namespace": "default", "name": "pod-1", "dry_run": true})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "pods_delete denied by policy")
+	})
+	s.Run("resources_create_or_update with a host network Pod is denied", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{
+			"resource": `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"ns-1"},"spec":{"hostNetwork":true}}`,
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("resources_create_or_update denied by policy: no-host-network: Pods can't use the host network",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("resources_create_or_update with a Pod without namespace is denied", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{
+			"resource": `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web"}}`,
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("resources_create_or_update denied by policy: no-default-namespace: the default namespace can't be modified",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Empty(s.recordedRequests(), "denied calls must not reach the API server")
+	})
+	s.Run("resources_create_or_update with a policy failing to evaluate is denied", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{
+			"resource": `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"ns-1"},"spec":{"hostNetwork":"yes"}}`,
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("resources_create_or_update denied by policies that failed to evaluate: no-host-network: no such overload",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Empty(s.recordedRequests(), "denied calls must not reach the API server")
+	})
+	s.Run("kustomize_apply with a rendered object in the default namespace is denied", func() {
+		toolResult, err := s.CallTool("kustomize_apply", map[string]interface{}{
+			"kustomization": "namespace: default\nresources:\n- configmap.yaml\n",
+			"files":         map[string]interface{}{"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"},
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("kustomize_apply denied by policy: no-default-namespace: the default namespace can't be modified",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Empty(s.recordedRequests(), "denied calls must not reach the API server")
+	})
+	s.Run("read-only tools are not evaluated", func() {
+		toolResult, err := s.CallTool("pods_get", map[string]interface{}{"namespace": "default", "name": "pod-1"})
+		s.Require().NoError(err)
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "denied by policy")
+	})
+	s.Run("pods_delete(namespace=ns-1) is allowed", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]interface{}{"namespace": "ns-1", "name": "pod-1"})
+		s.Require().NoError(err)
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "denied by policy")
+		s.Contains(s.recordedRequests(), "GET /api/v1/namespaces/ns-1/pods/pod-1")
+	})
+}
+
+func (s *PolicySuite) TestInvalidPolicy() {
+	s.Cfg.Policies[0].Expression = "request.namespace !="
+	_, err := NewServer(Configuration{StaticConfig: s.Cfg})
+	s.ErrorContains(err, "invalid policy no-default-namespace: ERROR: <input>:1:21: Syntax error")
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(PolicySuite))
+}
@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/klog/v2"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// progressReporter sends the stages reported during a tool call as MCP progress notifications.
+// The stages don't have a known total, the progress is the number of stages reported so far (it must increase with
+// every notification).
+type progressReporter struct {
+	ctx      context.Context
+	session  *mcp.ServerSession
+	token    any
+	mu       sync.Mutex
+	progress float64
+}
+
+var _ api.ProgressReporter = (*progressReporter)(nil)
+
+func (r *progressReporter) Report(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress++
+	err := r.session.NotifyProgress(r.ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: r.token,
+		Message:       message,
+		Progress:      r.progress,
+	})
+	if err != nil {
+		klog.V(3).Infof("failed to send progress notification: %v", err)
+	}
+}
+
+// withProgress returns a context that reports the progress of the tool call if the client provided a progress token
+func withProgress(ctx context.Context, request *mcp.CallToolRequest) context.Context {
+	if request.Session == nil || request.Params == nil || request.Params.GetProgressToken() == nil {
+		return ctx
+	}
+	return internalk8s.WithProgressReporter(ctx, &progressReporter{ctx: ctx, session: request.Session, token: request.Params.GetProgressToken()})
+}
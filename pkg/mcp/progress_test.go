@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ProgressSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+	mu               sync.Mutex
+	notifications    []map[string]any
+}
+
+func (s *ProgressSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.notifications = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/nodes/node-1" {
+			test.WriteObject(w, &v1.Node{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			})
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string { return "entry\n" }}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *ProgressSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ProgressSuite) callTool(meta *mcp.Meta) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "nodes_journal"
+	request.Params.Arguments = map[string]any{"name": "node-1", "unit": "kubelet"}
+	request.Params.Meta = meta
+	toolResult, err := s.Client.CallTool(s.T().Context(), request)
+	s.Require().NoError(err)
+	s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+}
+
+func (s *ProgressSuite) progressNotifications() []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]any{}, s.notifications...)
+}
+
+func (s *ProgressSuite) TestProgressNotifications() {
+	s.InitMcpClient()
+	s.OnNotification(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == "notifications/progress" {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.notifications = append(s.notifications, notification.Params.AdditionalFields)
+		}
+	})
+	s.Run("without progress token", func() {
+		s.callTool(nil)
+		s.Never(func() bool { return len(s.progressNotifications()) > 0 }, 300*time.Millisecond, 50*time.Millisecond)
+	})
+	s.Run("with progress token", func() {
+		s.callTool(&mcp.Meta{ProgressToken: "journal-1"})
+		s.Eventually(func() bool { return len(s.progressNotifications()) == 3 }, 2*time.Second, 50*time.Millisecond)
+		notifications := s.progressNotifications()
+		pod := s.helperPodHandler.Created()[len(s.helperPodHandler.Created())-1]
+		s.Run("reports the stages of the helper pod", func() {
+			s.Equal("Creating helper pod "+pod.Namespace+"/"+pod.Name+" on node node-1", notifications[0]["message"])
+			s.Equal("Waiting for helper pod "+pod.Name+" to complete: Succeeded", notifications[1]["message"])
+			s.Equal("Reading helper pod "+pod.Name+" output", notifications[2]["message"])
+		})
+		s.Run("associates the notifications with the tool call", func() {
+			for i, notification := range notifications {
+				s.Equal("journal-1", notification["progressToken"])
+				s.Equal(float64(i+1), notification["progress"])
+			}
+		})
+	})
+}
+
+func TestProgress(t *testing.T) {
+	suite.Run(t, new(ProgressSuite))
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type QuotasSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *QuotasSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+			`{"name":"limitranges","singularName":"","namespaced":true,"kind":"LimitRange","verbs":["get","list"]}`,
+			`{"name":"resourcequotas","singularName":"","namespaced":true,"kind":"ResourceQuota","verbs":["get","list"]}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/resourcequotas", "/api/v1/resourcequotas":
+			hard := corev1.ResourceList{"requests.cpu": resource.MustParse("2"), "pods": resource.MustParse("10")}
+			test.WriteObject(w, &corev1.ResourceQuotaList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuotaList"},
+				Items: []corev1.ResourceQuota{{
+					ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "default"},
+					Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+					Status: corev1.ResourceQuotaStatus{Hard: hard, Used: corev1.ResourceList{
+						"requests.cpu": resource.MustParse("2"), "pods": resource.MustParse("4"),
+					}},
+				}},
+			})
+		case "/api/v1/namespaces/default/limitranges", "/api/v1/limitranges":
+			test.WriteObject(w, &corev1.LimitRangeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "LimitRangeList"},
+				Items: []corev1.LimitRange{{
+					ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "default"},
+					Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+						Type:           corev1.LimitTypeContainer,
+						DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+					}}},
+				}},
+			})
+		case "/api/v1/namespaces/default/pods/web-1":
+			test.WriteObject(w, &corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			})
+		case "/api/v1/namespaces/default/pods/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v1/namespaces/default/events", "/api/v1/events":
+			test.WriteObject(w, &corev1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []corev1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "web-5d9c.1", Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "ReplicaSet", Name: "web-5d9c"},
+					Type:           corev1.EventTypeWarning,
+					Reason:         "FailedCreate",
+					Message:        `Error creating: pods "web-5d9c-x" is forbidden: exceeded quota: compute, requested: requests.cpu=100m, used: requests.cpu=2, limited: requests.cpu=2`,
+				}, {
+					ObjectMeta:     metav1.ObjectMeta{Name: "db-0.1", Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "db-0"},
+					Type:           corev1.EventTypeNormal,
+					Reason:         "Scheduled",
+					Message:        "Successfully assigned default/db-0 to node-1",
+				}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *QuotasSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *QuotasSuite) TestQuotasStatus() {
+	s.InitMcpClient()
+	s.Run("quotas_status()", func() {
+		toolResult, err := s.CallTool("quotas_status", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns summary", func() {
+			s.True(strings.HasPrefix(text,
+				"# 1 ResourceQuotas and 1 LimitRanges in namespace default, 1 exhausted: compute (requests.cpu), 1 recent denials\n"), "unexpected result %v", text)
+		})
+		var status kubernetes.QuotasStatus
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &status))
+		s.Run("returns used and hard values", func() {
+			s.Require().Len(status.Quotas, 1)
+			s.Equal([]kubernetes.QuotaResource{
+				{Name: "pods", Used: "4", Hard: "10", UsedPercent: 40},
+				{Name: "requests.cpu", Used: "2", Hard: "2", UsedPercent: 100, Exhausted: true},
+			}, status.Quotas[0].Resources)
+		})
+		s.Run("returns the quota denials", func() {
+			s.Require().Len(status.Denials, 1)
+			s.Equal("ReplicaSet/web-5d9c", status.Denials[0].Object)
+			s.Equal("compute", status.Denials[0].Quota)
+		})
+	})
+	s.Run("quotas_status(all_namespaces=true)", func() {
+		toolResult, err := s.CallTool("quotas_status", map[string]interface{}{"all_namespaces": true})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 1 ResourceQuotas and 1 LimitRanges in all namespaces")
+	})
+	s.Run("quotas_status(pod=web-1)", func() {
+		toolResult, err := s.CallTool("quotas_status", map[string]interface{}{"pod": "web-1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, ", a Pod like web-1 is blocked by compute (requests.cpu)")
+		var status kubernetes.QuotasStatus
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &status))
+		s.Require().NotNil(status.Pod)
+		s.Equal([]kubernetes.QuotaBlock{{Quota: "compute", Resource: "requests.cpu", Requested: "100m", Used: "2", Hard: "2"}}, status.Pod.BlockedBy)
+	})
+	s.Run("quotas_status(object=Deployment/api)", func() {
+		toolResult, err := s.CallTool("quotas_status", map[string]interface{}{"object": "Deployment/api"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "denials")
+	})
+	s.Run("quotas_status(pod=missing)", func() {
+		toolResult, err := s.CallTool("quotas_status", map[string]interface{}{"pod": "missing"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get quotas status: failed to get pod missing")
+	})
+}
+
+func TestQuotas(t *testing.T) {
+	suite.Run(t, new(QuotasSuite))
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// destructiveRetryAfter is the delay suggested to retry a destructive tool call rejected by the concurrency limit
+const destructiveRetryAfter = time.Second
+
+// RateLimitedError is returned for the tool calls rejected by the RateLimiter
+type RateLimitedError struct {
+	RetryAfter time.Duration
+	Reason     string
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s: %s", e.RetryAfter, e.Reason)
+}
+
+// RateLimiter limits the rate of the tool calls and the concurrent destructive tool calls of each MCP session so that
+// a runaway agent loop can't flood the API server.
+// The limits of a session are removed once the session is closed.
+type RateLimiter struct {
+	callsPerMinute           int
+	limit                    rate.Limit
+	burst                    int
+	maxConcurrentDestructive int
+	mu                       sync.Mutex
+	sessions                 map[*mcp.ServerSession]*sessionLimits
+}
+
+type sessionLimits struct {
+	limiter     *rate.Limiter
+	destructive int
+}
+
+// NewRateLimiter returns a RateLimiter allowing callsPerMinute tool calls (burst at once) and maxConcurrentDestructive
+// in-flight destructive tool calls per session, a limit of 0 disables it
+func NewRateLimiter(callsPerMinute, burst, maxConcurrentDestructive int) *RateLimiter {
+	r := &RateLimiter{
+		callsPerMinute:           callsPerMinute,
+		limit:                    rate.Inf,
+		maxConcurrentDestructive: maxConcurrentDestructive,
+		sessions:                 make(map[*mcp.ServerSession]*sessionLimits),
+	}
+	if callsPerMinute > 0 {
+		r.limit = rate.Limit(float64(callsPerMinute) / time.Minute.Seconds())
+		r.burst = burst
+		if r.burst <= 0 {
+			r.burst = callsPerMinute
+		}
+	}
+	return r
+}
+
+func (r *RateLimiter) enabled() bool {
+	return r.limit != rate.Inf || r.maxConcurrentDestructive > 0
+}
+
+// acquire reserves a tool call of the session, the returned function must be called once the tool call completes
+func (r *RateLimiter) acquire(session *mcp.ServerSession, destructive bool) (func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limits, ok := r.sessions[session]
+	if !ok {
+		limits = &sessionLimits{limiter: rate.NewLimiter(r.limit, r.burst)}
+		r.sessions[session] = limits
+		go func() {
+			_ = session.Wait()
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			delete(r.sessions, session)
+		}()
+	}
+	if destructive && r.maxConcurrentDestructive > 0 && limits.destructive >= r.maxConcurrentDestructive {
+		return nil, &RateLimitedError{
+			RetryAfter: destructiveRetryAfter,
+			Reason:     fmt.Sprintf("%d destructive tool calls are already running in this session", limits.destructive),
+		}
+	}
+	reservation := limits.limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		return nil, &RateLimitedError{
+			RetryAfter: time.Duration(math.Ceil(delay.Seconds())) * time.Second,
+			Reason:     fmt.Sprintf("the session exceeded %d tool calls per minute", r.callsPerMinute),
+		}
+	}
+	if !destructive {
+		return func() {}, nil
+	}
+	limits.destructive++
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		limits.destructive--
+	}, nil
+}
+
+// rateLimitMiddleware rejects the tool calls exceeding the limits of the RateLimiter with a RateLimitedError result
+func (s *Server) rateLimitMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+		session, isServerSession := req.GetSession().(*mcp.ServerSession)
+		if !ok || !isServerSession || session == nil {
+			return next(ctx, method, req)
+		}
+		tool, _ := s.tool(params.Name)
+		release, err := s.rateLimiter.acquire(session, ptr.Deref(tool.Tool.Annotations.DestructiveHint, false))
+		if err != nil {
+			klog.FromContext(ctx).V(1).Info("mcp tool call rejected", "sessionId", session.ID(), "tool", params.Name, "reason", err.Error())
+			result := NewTextResult("", err)
+			var rateLimited *RateLimitedError
+			if errors.As(err, &rateLimited) {
+				result.StructuredContent = map[string]any{
+					"error":             api.ErrorCategoryRateLimited,
+					"retryAfterSeconds": rateLimited.RetryAfter.Seconds(),
+					"reason":            rateLimited.Reason,
+				}
+			}
+			return result, nil
+		}
+		defer release()
+		return next(ctx, method, req)
+	}
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	gosdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type RateLimitSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *RateLimitSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *RateLimitSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *RateLimitSuite) session() *gosdk.ServerSession {
+	var session *gosdk.ServerSession
+	for ss := range s.mcpServer.server.Sessions() {
+		session = ss
+	}
+	s.Require().NotNil(session)
+	return session
+}
+
+func (s *RateLimitSuite) TestToolCallsPerMinute() {
+	s.Cfg.ToolCallsPerMinute = 2
+	s.InitMcpClient()
+	for i := 0; i < 2; i++ {
+		toolResult, err := s.CallTool("server_info", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	}
+	s.Run("tool calls above the limit are rejected", func() {
+		toolResult, err := s.CallTool("server_info", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("rate limited, retry after 30s: the session exceeded 2 tool calls per minute", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Run("with a structured error", func() {
+			s.Equal(map[string]any{
+				"error":             "rate_limited",
+				"retryAfterSeconds": float64(30),
+				"reason":            "the session exceeded 2 tool calls per minute",
+			}, toolResult.StructuredContent)
+		})
+	})
+	s.Run("other sessions are not affected", func() {
+		other := test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP())
+		defer other.Close()
+		toolResult, err := other.CallTool("server_info", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+}
+
+func (s *RateLimitSuite) TestMaxConcurrentDestructiveToolCalls() {
+	s.Cfg.MaxConcurrentDestructiveToolCalls = 1
+	s.InitMcpClient()
+	session := s.session()
+	release, err := s.mcpServer.rateLimiter.acquire(session, true)
+	s.Require().NoError(err)
+	s.Run("destructive tool calls above the limit are rejected", func() {
+		_, err := s.mcpServer.rateLimiter.acquire(session, true)
+		s.EqualError(err, "rate limited, retry after 1s: 1 destructive tool calls are already running in this session")
+	})
+	s.Run("non-destructive tool calls are not limited", func() {
+		toolResult, err := s.CallTool("server_info", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+	s.Run("destructive tool calls are allowed once the running ones complete", func() {
+		release()
+		release, err := s.mcpServer.rateLimiter.acquire(session, true)
+		s.Require().NoError(err)
+		release()
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	suite.Run(t, new(RateLimitSuite))
+}
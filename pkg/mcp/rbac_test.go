@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/rbac"
+)
+
+type RbacSuite struct {
+	BaseMcpSuite
+	mockServer    *test.MockServer
+	accessReviews *test.AccessReviewHandler
+}
+
+func (s *RbacSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.accessReviews = test.NewAccessReviewHandler(func(attributes *authorizationv1.ResourceAttributes) bool {
+		return attributes.Resource == "secrets"
+	})
+	s.accessReviews.Groups = append(s.accessReviews.Groups,
+		`{"name":"rbac.authorization.k8s.io","versions":[{"groupVersion":"rbac.authorization.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"rbac.authorization.k8s.io/v1","version":"v1"}}`,
+	)
+	s.mockServer.Handle(s.accessReviews)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/rbac.authorization.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"rbac.authorization.k8s.io/v1","resources":[
+				{"name":"roles","singularName":"","namespaced":true,"kind":"Role","verbs":["list"]},
+				{"name":"clusterroles","singularName":"","namespaced":false,"kind":"ClusterRole","verbs":["list"]},
+				{"name":"rolebindings","singularName":"","namespaced":true,"kind":"RoleBinding","verbs":["list"]},
+				{"name":"clusterrolebindings","singularName":"","namespaced":false,"kind":"ClusterRoleBinding","verbs":["list"]}
+			]}`))
+		case "/apis/rbac.authorization.k8s.io/v1/roles":
+			test.WriteObject(w, &rbacv1.RoleList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleList"},
+				Items: []rbacv1.Role{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "pod-exec"},
+					Rules:      []rbacv1.PolicyRule{{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}},
+				}},
+			})
+		case "/apis/rbac.authorization.k8s.io/v1/clusterroles":
+			test.WriteObject(w, &rbacv1.ClusterRoleList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleList"},
+				Items: []rbacv1.ClusterRole{{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+					Rules:      []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+				}},
+			})
+		case "/apis/rbac.authorization.k8s.io/v1/rolebindings":
+			test.WriteObject(w, &rbacv1.RoleBindingList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBindingList"},
+				Items: []rbacv1.RoleBinding{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "builder-exec"},
+					Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "builder"}, {Kind: rbacv1.GroupKind, Name: "developers"}},
+					RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-exec"},
+				}},
+			})
+		case "/apis/rbac.authorization.k8s.io/v1/clusterrolebindings":
+			test.WriteObject(w, &rbacv1.ClusterRoleBindingList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBindingList"},
+				Items: []rbacv1.ClusterRoleBinding{{
+					ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+					Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "admin"}},
+					RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+				}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"rbac"}
+}
+
+func (s *RbacSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *RbacSuite) TestRbacCanI() {
+	s.InitMcpClient()
+	s.Run("rbac_can_i(verb=create, resource=pods, subresource=exec, namespace=ns-1) for the current user", func() {
+		toolResult, err := s.CallTool("rbac_can_i", map[string]interface{}{
+			"verb": "create", "resource": "pods", "subresource": "exec", "namespace": "ns-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# The current user can create pods/exec in namespace ns-1\n"), text)
+		var decision kubernetes.RbacDecision
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &decision))
+		s.True(decision.Allowed)
+		s.Run("reviews the access with a SelfSubjectAccessReview", func() {
+			reviews := s.accessReviews.Reviews()
+			s.Require().NotEmpty(reviews)
+			last := reviews[len(reviews)-1]
+			s.Equal(authorizationv1.ResourceAttributes{Namespace: "ns-1", Verb: "create", Resource: "pods", Subresource: "exec"}, last)
+		})
+	})
+	s.Run("rbac_can_i(verb=get, resource=secrets, name=token, subject=serviceaccount:ns-1:builder)", func() {
+		toolResult, err := s.CallTool("rbac_can_i", map[string]interface{}{
+			"verb": "get", "resource": "secrets", "name": "token", "namespace": "ns-1", "subject": "serviceaccount:ns-1:builder",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# ServiceAccount:ns-1:builder cannot get secrets/token in namespace ns-1\n"), text)
+		s.Run("reviews the access with a SubjectAccessReview for the service account", func() {
+			reviews := s.accessReviews.SubjectReviews()
+			s.Require().Len(reviews, 1)
+			s.Equal("system:serviceaccount:ns-1:builder", reviews[0].User)
+			s.Equal([]string{"system:serviceaccounts", "system:serviceaccounts:ns-1", "system:authenticated"}, reviews[0].Groups)
+			s.Equal("token", reviews[0].ResourceAttributes.Name)
+		})
+	})
+	s.Run("rbac_can_i with an invalid subject", func() {
+		toolResult, err := s.CallTool("rbac_can_i", map[string]interface{}{"verb": "get", "resource": "pods", "subject": "alice"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal(`failed to check access, invalid subject "alice", expected user:<name>, group:<name> or serviceaccount:<namespace>:<name>`,
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("rbac_can_i without verb", func() {
+		toolResult, err := s.CallTool("rbac_can_i", map[string]interface{}{"resource": "pods"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to check access, missing argument verb", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *RbacSuite) TestRbacWhoCan() {
+	s.InitMcpClient()
+	s.Run("rbac_who_can(verb=create, resource=pods, subresource=exec, namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("rbac_who_can", map[string]interface{}{
+			"verb": "create", "resource": "pods", "subresource": "exec", "namespace": "ns-1",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# 3 subjects can create pods/exec in namespace ns-1\n"), text)
+		var grants []rbac.Grant
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &grants))
+		s.Require().Len(grants, 3)
+		s.Equal(rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "developers"}, grants[0].Subject)
+		s.Equal(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "ns-1", Name: "builder"}, grants[1].Subject)
+		s.Equal("RoleBinding/ns-1/builder-exec", grants[1].Binding)
+		s.Equal("Role/ns-1/pod-exec", grants[1].Role)
+		s.Equal(rbacv1.Subject{Kind: rbacv1.UserKind, Name: "admin"}, grants[2].Subject)
+		s.Equal("ClusterRoleBinding/admins", grants[2].Binding)
+	})
+	s.Run("rbac_who_can(verb=create, resource=pods, subresource=exec) cluster-wide", func() {
+		toolResult, err := s.CallTool("rbac_who_can", map[string]interface{}{"verb": "create", "resource": "pods", "subresource": "exec"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 1 subjects can create pods/exec cluster-wide\n"),
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *RbacSuite) TestRbacListBindingsForSubject() {
+	s.InitMcpClient()
+	s.Run("rbac_list_bindings_for_subject(subject=user:bob, groups=[developers])", func() {
+		toolResult, err := s.CallTool("rbac_list_bindings_for_subject", map[string]interface{}{
+			"subject": "user:bob", "groups": []interface{}{"developers"},
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# 1 bindings grant roles to User:bob\n"), text)
+		var grants []rbac.Grant
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &grants))
+		s.Require().Len(grants, 1)
+		s.Equal("RoleBinding/ns-1/builder-exec", grants[0].Binding)
+		s.Run("reports the rules of the bound roles", func() {
+			s.Require().Len(grants[0].Rules, 1)
+			s.Equal([]string{"pods/exec"}, grants[0].Rules[0].Resources)
+		})
+	})
+	s.Run("rbac_list_bindings_for_subject(subject=serviceaccount:ns-1:builder, namespace=ns-2)", func() {
+		toolResult, err := s.CallTool("rbac_list_bindings_for_subject", map[string]interface{}{
+			"subject": "serviceaccount:ns-1:builder", "namespace": "ns-2",
+		})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 0 bindings grant roles to ServiceAccount:ns-1:builder\n"),
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestRbac(t *testing.T) {
+	suite.Run(t, new(RbacSuite))
+}
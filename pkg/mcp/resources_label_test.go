@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type ResourcesLabelSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	requests   []string
+}
+
+func (s *ResourcesLabelSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.requests = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/ns-1/pods") {
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, req.Method+" "+req.URL.Path+" dryRun="+req.URL.Query().Get("dryRun")+" "+string(body))
+		s.mu.Unlock()
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/api/v1/namespaces/ns-1/pods" && req.URL.Query().Get("labelSelector") == "app=web":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-1", Labels: map[string]string{"app": "web", "tier": "frontend"}}},
+					{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-2", Labels: map[string]string{"app": "web", "team": "payments"}}},
+				},
+			})
+		case req.Method == http.MethodPatch:
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *ResourcesLabelSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ResourcesLabelSuite) recordedRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := append([]string{}, s.requests...)
+	s.requests = nil
+	return requests
+}
+
+func (s *ResourcesLabelSuite) TestResourcesLabel() {
+	s.InitMcpClient()
+	s.Run("resources_label(add_labels) patches the objects missing the labels", func() {
+		toolResult, err := s.CallTool("resources_label", map[string]interface{}{
+			"apiVersion":    "v1",
+			"kind":          "Pod",
+			"namespace":     "ns-1",
+			"labelSelector": "app=web",
+			"add_labels":    map[string]interface{}{"team": "payments"},
+			"remove_labels": []interface{}{"tier"},
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# 1 Pod objects changed in namespace ns-1 (2 selected by app=web, 1 unchanged)\n"), text)
+		var report kubernetes.ResourcesLabelReport
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &report))
+		s.Equal(2, report.Selected)
+		s.Equal(1, report.Unchanged)
+		s.Equal([]kubernetes.LabeledObject{{
+			Namespace: "ns-1",
+			Name:      "web-1",
+			Labels:    &kubernetes.MetadataChanges{Set: map[string]string{"team": "payments"}, Removed: []string{"tier"}},
+		}}, report.Objects)
+		s.Equal([]string{
+			"GET /api/v1/namespaces/ns-1/pods dryRun= ",
+			`PATCH /api/v1/namespaces/ns-1/pods/web-1 dryRun= {"metadata":{"labels":{"team":"payments","tier":null}}}`,
+		}, s.recordedRequests())
+	})
+	s.Run("resources_label leaves the objects with the requested labels unchanged", func() {
+		toolResult, err := s.CallTool("resources_label", map[string]interface{}{
+			"apiVersion":    "v1",
+			"kind":          "Pod",
+			"namespace":     "ns-1",
+			"labelSelector": "app=web",
+			"add_labels":    map[string]interface{}{"app": "web"},
+		})
+		s.Require().NoError(err)
+		s.False(toolResult.IsError)
+		s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 0 Pod objects changed in namespace ns-1 (2 selected by app=web, 2 unchanged)\n"))
+		s.Equal([]string{"GET /api/v1/namespaces/ns-1/pods dryRun= "}, s.recordedRequests())
+	})
+	s.Run("resources_label(dry_run=true) patches with server-side dry-run", func() {
+		toolResult, err := s.CallTool("resources_label", map[string]interface{}{
+			"apiVersion":      "v1",
+			"kind":            "Pod",
+			"namespace":       "ns-1",
+			"labelSelector":   "app=web",
+			"add_annotations": map[string]interface{}{"owner": "payments"},
+			"dry_run":         true,
+		})
+		s.Require().NoError(err)
+		s.False(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "2 Pod objects would change (server-side dry-run) in namespace ns-1")
+		s.Contains(s.recordedRequests(), `PATCH /api/v1/namespaces/ns-1/pods/web-2 dryRun=All {"metadata":{"annotations":{"owner":"payments"}}}`)
+	})
+	s.Run("resources_label(max_objects=1) fails without changing any object", func() {
+		toolResult, err := s.CallTool("resources_label", map[string]interface{}{
+			"apiVersion":    "v1",
+			"kind":          "Pod",
+			"namespace":     "ns-1",
+			"labelSelector": "app=web",
+			"add_labels":    map[string]interface{}{"team": "checkout"},
+			"max_objects":   1,
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to label resources: 2 pods match app=web, more than the maximum of 1 objects (max_objects), no object was changed: narrow the selector or increase the maximum",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Equal([]string{"GET /api/v1/namespaces/ns-1/pods dryRun= "}, s.recordedRequests())
+	})
+	s.Run("resources_label with missing labelSelector returns error", func() {
+		toolResult, err := s.CallTool("resources_label", map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"add_labels": map[string]interface{}{"team": "checkout"},
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to label resources, missing argument labelSelector", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("resources_label without changes returns error", func() {
+		toolResult, err := s.CallTool("resources_label", map[string]interface{}{
+			"apiVersion":    "v1",
+			"kind":          "Pod",
+			"labelSelector": "app=web",
+		})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to label resources: no label or annotation to add or remove", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestResourcesLabel(t *testing.T) {
+	suite.Run(t, new(ResourcesLabelSuite))
+}
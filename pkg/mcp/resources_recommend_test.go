@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ResourcesRecommendSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *ResourcesRecommendSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	containers := []v1.Container{{Name: "web", Resources: v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+	}}}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/ns-1/deployments/web":
+			test.WriteObject(w, &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web"},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: containers}},
+				},
+			})
+		case "/api/v1/namespaces/ns-1/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []v1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-1", Labels: map[string]string{"app": "web"}},
+					Spec:       v1.PodSpec{NodeName: "node-1", Containers: containers},
+					Status:     v1.PodStatus{Phase: v1.PodRunning},
+				}},
+			})
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"pods":[{"podRef":{"name":"web-1","namespace":"ns-1"},"containers":[` +
+				`{"name":"web","cpu":{"usageNanoCores":100000000},"memory":{"workingSetBytes":104857600}}]}]}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *ResourcesRecommendSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ResourcesRecommendSuite) TestResourcesRecommend() {
+	s.InitMcpClient()
+	s.Run("resources_recommend(kind=Deployment, name=web, samples=1, patch=true)", func() {
+		toolResult, err := s.CallTool("resources_recommend", map[string]interface{}{
+			"kind":      "Deployment",
+			"namespace": "ns-1",
+			"name":      "web",
+			"samples":   1,
+			"patch":     true,
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the summary", func() {
+			s.True(strings.HasPrefix(text, "# Deployment ns-1/web: 1 of 1 containers to right-size, from 1 usage samples of 1 pods (kubelet)\n"), text)
+		})
+		s.Run("returns the recommended changes", func() {
+			s.Contains(text, "decrease the cpu request from 1 to 120m (p95 usage 100m)")
+			s.Contains(text, "decrease the memory request from 1Gi to 120Mi (max usage 100Mi)")
+		})
+		s.Run("returns the patch", func() {
+			s.Contains(text, "patch: |")
+		})
+	})
+	s.Run("resources_recommend(kind=ReplicaSet)", func() {
+		toolResult, _ := s.CallTool("resources_recommend", map[string]interface{}{"kind": "ReplicaSet", "name": "web"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to recommend resources, invalid argument kind: enum: ReplicaSet does not equal any of: [Deployment StatefulSet DaemonSet Pod]",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("resources_recommend(name=web) missing kind", func() {
+		toolResult, _ := s.CallTool("resources_recommend", map[string]interface{}{"name": "web"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to recommend resources, missing argument kind", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestResourcesRecommend(t *testing.T) {
+	suite.Run(t, new(ResourcesRecommendSuite))
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// selfCheckTools returns the tools to verify which of the registered tools can be used in the cluster
+func (s *Server) selfCheckTools() []api.ServerTool {
+	return []api.ServerTool{{
+		Tool: api.Tool{
+			Name: api.SelfCheckToolName,
+			Description: "Check the connection to the cluster and which of the available tools can be used before calling them. " +
+				"Reports whether the API server is reachable and its version, whether the optional APIs are available " +
+				"(metrics, node log query, OpenShift routes), and a capability matrix of the tools with the Kubernetes permissions " +
+				"the current user lacks (reviewed with SelfSubjectAccessReviews). " +
+				"Call it to understand why tools fail with authorization or not found errors",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace where the permissions of the namespaced resources are checked (Optional, all namespaces if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Self Check",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		},
+		Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+			namespace, _ := params.GetArguments()["namespace"].(string)
+			// only the tools operating on the cluster need permissions
+			permissions := make(map[string][]api.ResourcePermission)
+			s.toolsMu.RLock()
+			for name, tool := range s.tools {
+				if tool.IsClusterAware() && name != api.SelfCheckToolName {
+					permissions[name] = tool.Permissions
+				}
+			}
+			s.toolsMu.RUnlock()
+			selfCheck := params.SelfCheck(params, namespace, permissions)
+			marshalledYaml, err := output.MarshalYaml(selfCheck)
+			if err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to run self check: %w", err)), nil
+			}
+			return api.NewToolCallResult("# "+selfCheck.Summary()+"\n"+marshalledYaml, nil), nil
+		},
+	}}
+}
@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type SelfCheckSuite struct {
+	BaseMcpSuite
+	mockServer  *test.MockServer
+	unreachable atomic.Bool
+	mu          sync.Mutex
+	reviews     []authorizationv1.ResourceAttributes
+}
+
+func (s *SelfCheckSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.unreachable.Store(false)
+	s.reviews = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if s.unreachable.Load() && req.URL.Path == "/version" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch req.URL.Path {
+		case "/apis/authorization.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"authorization.k8s.io/v1","resources":[
+				{"name":"selfsubjectaccessreviews","singularName":"","namespaced":false,"kind":"SelfSubjectAccessReview","verbs":["create"]}
+			]}`))
+		case "/version":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"major":"1","minor":"30","gitVersion":"v1.30.0"}`))
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NodeList"},
+				Items:    []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}},
+			})
+		case "/api/v1/nodes/node-1/proxy/logs":
+			_, _ = w.Write([]byte("kubelet log line\n"))
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			body, _ := io.ReadAll(req.Body)
+			if obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body); err == nil {
+				review := obj.(*authorizationv1.SelfSubjectAccessReview)
+				attributes := review.Spec.ResourceAttributes
+				s.mu.Lock()
+				s.reviews = append(s.reviews, *attributes)
+				s.mu.Unlock()
+				review.Status.Allowed = attributes.Subresource != "exec" && attributes.Verb != "delete"
+				test.WriteObject(w, review)
+			}
+		}
+	}))
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		Groups: []string{
+			`{"name":"authorization.k8s.io","versions":[{"groupVersion":"authorization.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"authorization.k8s.io/v1","version":"v1"}}`,
+		},
+	})
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *SelfCheckSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *SelfCheckSuite) capability(selfCheck *kubernetes.SelfCheck, tool string) *kubernetes.ToolCapability {
+	for i := range selfCheck.Tools {
+		if selfCheck.Tools[i].Tool == tool {
+			return &selfCheck.Tools[i]
+		}
+	}
+	return nil
+}
+
+func (s *SelfCheckSuite) TestSelfCheck() {
+	s.InitMcpClient()
+	s.Run("self_check(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("self_check", map[string]interface{}{"namespace": "ns-1"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the check", func() {
+			s.Truef(strings.HasPrefix(text, "# API server reachable (v1.30.0), optional APIs available: node log query, tools: "), text)
+		})
+		var selfCheck kubernetes.SelfCheck
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &selfCheck))
+		s.Run("detects the optional APIs", func() {
+			s.Require().Len(selfCheck.APIs, 3)
+			s.Equal("metrics", selfCheck.APIs[0].Name)
+			s.False(selfCheck.APIs[0].Available)
+			s.Equal("node log query", selfCheck.APIs[1].Name)
+			s.True(selfCheck.APIs[1].Available)
+			s.Equal("routes", selfCheck.APIs[2].Name)
+			s.False(selfCheck.APIs[2].Available)
+		})
+		s.Run("reports the tools with all their permissions as allowed", func() {
+			s.Equal(&kubernetes.ToolCapability{Tool: "pods_get", Status: kubernetes.ToolAllowed}, s.capability(&selfCheck, "pods_get"))
+		})
+		s.Run("reports the permissions the tools lack", func() {
+			s.Equal(&kubernetes.ToolCapability{Tool: "pods_exec", Status: kubernetes.ToolDenied, Denied: []string{"create pods/exec"}},
+				s.capability(&selfCheck, "pods_exec"))
+			s.Equal(&kubernetes.ToolCapability{Tool: "namespaces", Status: kubernetes.ToolDenied, Denied: []string{"delete namespaces"}},
+				s.capability(&selfCheck, "namespaces"))
+		})
+		s.Run("reports the tools without declared permissions as unchecked", func() {
+			s.Equal(&kubernetes.ToolCapability{Tool: "resources_get", Status: kubernetes.ToolUnchecked}, s.capability(&selfCheck, "resources_get"))
+		})
+		s.Run("excludes the tools not operating on the cluster", func() {
+			s.Nil(s.capability(&selfCheck, "configuration_view"))
+			s.Nil(s.capability(&selfCheck, "session_configure"))
+			s.Nil(s.capability(&selfCheck, "self_check"))
+		})
+		s.Run("reviews each permission once", func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			getPods := 0
+			for _, review := range s.reviews {
+				if review.Verb == "get" && review.Resource == "pods" && review.Subresource == "" {
+					getPods++
+				}
+			}
+			s.Equal(1, getPods)
+		})
+		s.Run("reviews the namespaced permissions in the namespace and the cluster-wide ones in all namespaces", func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for _, review := range s.reviews {
+				switch {
+				case review.Resource == "pods" && review.Verb == "get":
+					s.Equal("ns-1", review.Namespace)
+				case review.Resource == "nodes":
+					s.Equal("", review.Namespace)
+				}
+			}
+		})
+	})
+	s.Run("self_check with an unreachable API server", func() {
+		s.unreachable.Store(true)
+		toolResult, err := s.CallTool("self_check", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Truef(strings.HasPrefix(text, "# API server is not reachable: "), text)
+		s.Contains(text, "reachable: false")
+	})
+}
+
+func TestSelfCheck(t *testing.T) {
+	suite.Run(t, new(SelfCheckSuite))
+}
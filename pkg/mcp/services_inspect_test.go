@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type ServicesInspectSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	helperPodHandler *test.HelperPodHandler
+	sliceSelectors   []string
+}
+
+func (s *ServicesInspectSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.sliceSelectors = nil
+	pod := func(name, app string, ready v1.ConditionStatus) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns-1", Labels: map[string]string{"app": app}},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}}}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: "10.244.0.5", Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}}},
+		}
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"services","singularName":"","namespaced":true,"kind":"Service","verbs":["get","list","watch"]}`,
+		},
+		Groups: []string{
+			`{"name":"discovery.k8s.io","versions":[{"groupVersion":"discovery.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"discovery.k8s.io/v1","version":"v1"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/discovery.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"discovery.k8s.io/v1","resources":[
+				{"name":"endpointslices","singularName":"","namespaced":true,"kind":"EndpointSlice","verbs":["get","list"]}
+			]}`))
+		case "/api/v1/namespaces/ns-1/services/web":
+			test.WriteObject(w, &v1.Service{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns-1"},
+				Spec: v1.ServiceSpec{
+					Type:      v1.ServiceTypeClusterIP,
+					ClusterIP: "10.96.0.10",
+					Selector:  map[string]string{"app": "web"},
+					Ports:     []v1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromString("http"), Protocol: v1.ProtocolTCP}},
+				},
+			})
+		case "/api/v1/namespaces/ns-1/pods":
+			if req.Method != http.MethodGet {
+				return
+			}
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items:    []v1.Pod{pod("web-1", "web", v1.ConditionFalse), pod("api-1", "api", v1.ConditionTrue)},
+			})
+		case "/apis/discovery.k8s.io/v1/namespaces/ns-1/endpointslices":
+			s.sliceSelectors = append(s.sliceSelectors, req.URL.Query().Get("labelSelector"))
+			test.WriteObject(w, &discoveryv1.EndpointSliceList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "discovery.k8s.io/v1", Kind: "EndpointSliceList"},
+				Items: []discoveryv1.EndpointSlice{{
+					ObjectMeta:  metav1.ObjectMeta{Name: "web-abcde", Namespace: "ns-1"},
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{{
+						Addresses:  []string{"10.244.0.5"},
+						Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+						TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+					}},
+				}},
+			})
+		}
+	}))
+	s.helperPodHandler = &test.HelperPodHandler{Logs: func(pod *v1.Pod) string {
+		return "failed web.ns-1.svc:80 nc: connect to web.ns-1.svc (10.96.0.10) port 80 (tcp) failed: Connection refused\n"
+	}}
+	s.mockServer.Handle(s.helperPodHandler)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *ServicesInspectSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ServicesInspectSuite) TestServicesInspect() {
+	s.InitMcpClient()
+	s.Run("services_inspect(name=web, namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("services_inspect", map[string]interface{}{"namespace": "ns-1", "name": "web"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("summarizes the Service endpoints", func() {
+			s.Contains(text, "# Service web has 0/1 ready endpoints, 1 Pods match the selector app=web\n")
+		})
+		var inspection kubernetes.ServiceInspection
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &inspection))
+		s.Run("lists the EndpointSlices of the Service", func() {
+			s.Equal([]string{"kubernetes.io/service-name=web"}, s.sliceSelectors)
+			s.Require().Len(inspection.Endpoints, 1)
+			s.Equal("Pod/web-1", inspection.Endpoints[0].TargetRef)
+		})
+		s.Run("flags the not ready Pods and endpoints", func() {
+			s.Equal([]string{
+				"1 of 1 Pods matching the selector are not ready (failing readiness probes or not started): web-1",
+				"none of the 1 endpoints are ready, connections to the Service are refused or time out",
+			}, inspection.Issues)
+		})
+		s.Run("doesn't probe the Service", func() {
+			s.Empty(inspection.Probes)
+			s.Empty(s.helperPodHandler.Created())
+		})
+	})
+	s.Run("services_inspect(probe=true)", func() {
+		toolResult, err := s.CallTool("services_inspect", map[string]interface{}{"namespace": "ns-1", "name": "web", "probe": true})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		var inspection kubernetes.ServiceInspection
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &inspection))
+		s.Run("returns the probe results", func() {
+			s.Equal([]kubernetes.ServiceProbe{{
+				Target: "web.ns-1.svc:80",
+				Output: "nc: connect to web.ns-1.svc (10.96.0.10) port 80 (tcp) failed: Connection refused",
+			}}, inspection.Probes)
+		})
+		s.Run("probes the Service ports from a helper pod", func() {
+			s.Require().Len(s.helperPodHandler.Created(), 1)
+			helperPod := s.helperPodHandler.Created()[0]
+			s.Equal([]string{"sh", "web.ns-1.svc", "80"}, helperPod.Spec.Containers[0].Command[3:])
+			s.False(helperPod.Spec.HostNetwork)
+			s.Equal([]string{helperPod.Name}, s.helperPodHandler.Deleted())
+		})
+	})
+	s.Run("services_inspect(name=missing)", func() {
+		toolResult, err := s.CallTool("services_inspect", map[string]interface{}{"namespace": "ns-1", "name": "missing"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to inspect service missing in namespace ns-1:")
+	})
+}
+
+func TestServicesInspect(t *testing.T) {
+	suite.Run(t, new(ServicesInspectSuite))
+}
@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const sessionContextKey = ContextKey("SessionContextKey")
+
+// SessionState holds the defaults that apply to the tool calls of a single MCP session
+type SessionState struct {
+	// Namespace is used for the tool calls that accept a namespace parameter when it's not provided
+	Namespace string `json:"namespace,omitempty"`
+	// Target is the cluster (e.g. kubeconfig context) used for the tool calls when it's not provided
+	Target string `json:"target,omitempty"`
+}
+
+// SessionStore keeps the SessionState and the Kubernetes clients of the active MCP sessions.
+// The state of a session is removed once the session is closed.
+type SessionStore struct {
+	mu         sync.RWMutex
+	sessions   map[*mcp.ServerSession]SessionState
+	kubernetes map[*mcp.ServerSession]map[string]sessionKubernetes
+}
+
+// sessionKubernetes is the Kubernetes client derived for the requests of a session to a target, it's reused
+// (with its discovery cache and access-control state) while the credentials of the requests don't change
+type sessionKubernetes struct {
+	credentials string
+	kubernetes  *internalk8s.Kubernetes
+}
+
+func NewSessionStore() *SessionStore {
+	return &SessionStore{
+		sessions:   make(map[*mcp.ServerSession]SessionState),
+		kubernetes: make(map[*mcp.ServerSession]map[string]sessionKubernetes),
+	}
+}
+
+// Get returns the state of the provided session, or an empty state if the session has none
+func (s *SessionStore) Get(session *mcp.ServerSession) SessionState {
+	if session == nil {
+		return SessionState{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessions[session]
+}
+
+// Set replaces the state of the provided session
+func (s *SessionStore) Set(session *mcp.ServerSession, state SessionState) {
+	if session == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.track(session)
+	s.sessions[session] = state
+}
+
+// Kubernetes returns the Kubernetes client of the session for the target.
+// The client is derived with the provided function on the first call, and again when the credentials of the session
+// requests (e.g. a refreshed bearer token) change.
+func (s *SessionStore) Kubernetes(session *mcp.ServerSession, target, credentials string, derive func() (*internalk8s.Kubernetes, error)) (*internalk8s.Kubernetes, error) {
+	if session == nil {
+		return derive()
+	}
+	s.mu.RLock()
+	cached, ok := s.kubernetes[session][target]
+	s.mu.RUnlock()
+	if ok && cached.credentials == credentials {
+		return cached.kubernetes, nil
+	}
+	k, err := derive()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.track(session)
+	if s.kubernetes[session] == nil {
+		s.kubernetes[session] = make(map[string]sessionKubernetes)
+	}
+	s.kubernetes[session][target] = sessionKubernetes{credentials: credentials, kubernetes: k}
+	return k, nil
+}
+
+// ResetKubernetes removes the Kubernetes clients of all the sessions (e.g. when the cluster targets are reloaded)
+func (s *SessionStore) ResetKubernetes() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.kubernetes)
+}
+
+// Delete removes the state of the provided session
+func (s *SessionStore) Delete(session *mcp.ServerSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, session)
+	delete(s.kubernetes, session)
+}
+
+// track removes the state of the session once it's closed, must be called with the lock held
+func (s *SessionStore) track(session *mcp.ServerSession) {
+	_, hasState := s.sessions[session]
+	_, hasKubernetes := s.kubernetes[session]
+	if hasState || hasKubernetes {
+		return
+	}
+	go func() {
+		_ = session.Wait()
+		s.Delete(session)
+	}()
+}
+
+// applySessionDefaults sets the session namespace for the tools that accept a namespace parameter when the
+// tool call doesn't provide one
+func applySessionDefaults(tool api.ServerTool, toolCallRequest *ToolCallRequest, state SessionState) {
+	if state.Namespace == "" || tool.Tool.InputSchema == nil || tool.Tool.InputSchema.Properties["namespace"] == nil {
+		return
+	}
+	if _, ok := toolCallRequest.arguments["namespace"]; ok {
+		return
+	}
+	if toolCallRequest.arguments == nil {
+		toolCallRequest.arguments = make(map[string]any)
+	}
+	toolCallRequest.arguments["namespace"] = state.Namespace
+}
+
+// sessionTools returns the tools to manage the state of the MCP session
+func (s *Server) sessionTools(targetParameterName string, targets []string) []api.ServerTool {
+	properties := map[string]*jsonschema.Schema{
+		"namespace": {
+			Type: "string",
+			Description: "Default namespace for the subsequent tool calls of this session that accept a namespace parameter " +
+				"and don't provide one (Optional, an empty string removes the session default)",
+		},
+	}
+	if targetParameterName != "" && len(targets) > 1 {
+		properties[targetParameterName] = &jsonschema.Schema{
+			Type: "string",
+			Description: fmt.Sprintf("Default %s for the subsequent tool calls of this session that don't provide one "+
+				"(Optional, an empty string removes the session default)", targetParameterName),
+		}
+	}
+	return []api.ServerTool{{
+		Tool: api.Tool{
+			Name: api.SessionConfigureToolName,
+			Description: "Configure the defaults for the current MCP session so that subsequent tool calls don't need to repeat them. " +
+				"Only the provided parameters are updated, call without parameters to get the current session defaults",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties,
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Session: Configure",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(false),
+			},
+		},
+		ClusterAware: ptr.To(false),
+		Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+			session, ok := params.Value(sessionContextKey).(*mcp.ServerSession)
+			if !ok || session == nil {
+				return api.NewToolCallResult("", errors.New("failed to configure session, no active MCP session")), nil
+			}
+			state := s.sessions.Get(session)
+			args := params.GetArguments()
+			if namespace, ok := args["namespace"].(string); ok {
+				state.Namespace = namespace
+			}
+			if target, ok := args[targetParameterName].(string); ok && targetParameterName != "" {
+				if target != "" && !slices.Contains(targets, target) {
+					return api.NewToolCallResult("", fmt.Errorf("failed to configure session, %s %s not found", targetParameterName, target)), nil
+				}
+				state.Target = target
+			}
+			s.sessions.Set(session, state)
+			stateYaml, err := output.MarshalYaml(state)
+			if err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to configure session: %w", err)), nil
+			}
+			return api.NewToolCallResult("# Session defaults\n"+stateYaml, nil), nil
+		},
+	}}
+}
+
+// derivedKubernetes returns the Kubernetes client for the target of the session requests, each session gets its own
+// client derived from the credentials of its requests
+func (s *Server) derivedKubernetes(ctx context.Context, session *mcp.ServerSession, target string) (*internalk8s.Kubernetes, error) {
+	credentials, _ := ctx.Value(internalk8s.OAuthAuthorizationHeader).(string)
+	return s.sessions.Kubernetes(session, target, credentials, func() (*internalk8s.Kubernetes, error) {
+		return s.p.GetDerivedKubernetes(ctx, target)
+	})
+}
+
+// sessionTarget returns the target used by the tool calls of the session that don't provide one: the target
+// configured with session_configure, the one provided by the connection header, or the provider default
+func (s *Server) sessionTarget(ctx context.Context, state SessionState) string {
+	if state.Target != "" {
+		return state.Target
+	}
+	if target, ok := ctx.Value(internalk8s.ContextHeader).(string); ok && target != "" && s.p.GetTargetParameterName() != "" {
+		return target
+	}
+	return s.p.GetDefaultTarget()
+}
+
+func withSession(ctx context.Context, session *mcp.ServerSession) context.Context {
+	return context.WithValue(ctx, sessionContextKey, session)
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	gosdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type SessionSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	paths      []string
+}
+
+func (s *SessionSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.paths = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		s.paths = append(s.paths, req.URL.Path)
+		s.mu.Unlock()
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/pods", "/api/v1/namespaces/ns-1/pods":
+			test.WriteObject(w, &v1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+			})
+		}
+	}))
+	kubeconfig := s.mockServer.Kubeconfig()
+	kubeconfig.Contexts["other-context"] = clientcmdapi.NewContext()
+	kubeconfig.Contexts["other-context"].Cluster = kubeconfig.Contexts["fake-context"].Cluster
+	kubeconfig.Contexts["other-context"].AuthInfo = kubeconfig.Contexts["fake-context"].AuthInfo
+	s.Cfg.KubeConfig = test.KubeconfigFile(s.T(), kubeconfig)
+}
+
+func (s *SessionSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *SessionSuite) requested(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SessionSuite) sessionCount() int {
+	s.mcpServer.sessions.mu.RLock()
+	defer s.mcpServer.sessions.mu.RUnlock()
+	return len(s.mcpServer.sessions.sessions)
+}
+
+func (s *SessionSuite) TestSessionConfigure() {
+	s.InitMcpClient()
+	s.Run("session_configure(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("session_configure", map[string]interface{}{"namespace": "ns-1"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("returns session defaults", func() {
+			s.Equal("# Session defaults\nnamespace: ns-1\n", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("session_configure(context=other-context)", func() {
+		toolResult, err := s.CallTool("session_configure", map[string]interface{}{"context": "other-context"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# Session defaults\nnamespace: ns-1\ntarget: other-context\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("session_configure(context=inexistent)", func() {
+		toolResult, err := s.CallTool("session_configure", map[string]interface{}{"context": "inexistent"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to configure session, context inexistent not found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("session_configure(namespace='') removes the session default", func() {
+		toolResult, err := s.CallTool("session_configure", map[string]interface{}{"namespace": "", "context": ""})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# Session defaults\n{}\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *SessionSuite) TestSessionDefaults() {
+	s.InitMcpClient()
+	_, err := s.CallTool("session_configure", map[string]interface{}{"namespace": "ns-1"})
+	s.Require().NoError(err)
+	s.Run("pods_list_in_namespace without namespace uses session namespace", func() {
+		toolResult, err := s.CallTool("pods_list_in_namespace", map[string]interface{}{})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.True(s.requested("/api/v1/namespaces/ns-1/pods"), "expected request to session namespace")
+	})
+	s.Run("other sessions are not affected", func() {
+		other := test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP())
+		defer other.Close()
+		toolResult, err := other.CallTool("pods_list_in_namespace", map[string]interface{}{"namespace": "default"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		toolResult, err = other.CallTool("session_configure", map[string]interface{}{})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Equal("# Session defaults\n{}\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("closed sessions are removed", func() {
+		s.Eventually(func() bool { return s.sessionCount() == 1 }, 5*time.Second, 50*time.Millisecond)
+	})
+}
+
+func (s *SessionSuite) TestConnectionContextHeader() {
+	kubeconfig := s.mockServer.Kubeconfig()
+	kubeconfig.Contexts["other-context"] = clientcmdapi.NewContext()
+	kubeconfig.Contexts["other-context"].Cluster = kubeconfig.Contexts["fake-context"].Cluster
+	kubeconfig.Contexts["other-context"].AuthInfo = kubeconfig.Contexts["fake-context"].AuthInfo
+	kubeconfig.Contexts["other-context"].Namespace = "ns-1"
+	s.Cfg.KubeConfig = test.KubeconfigFile(s.T(), kubeconfig)
+	s.InitMcpClient(transport.WithHTTPHeaders(map[string]string{"kubernetes-context": "other-context"}))
+	s.Run("tool calls without context use the context of the connection", func() {
+		_, err := s.CallTool("pods_get", map[string]interface{}{"name": "a-pod"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.True(s.requested("/api/v1/namespaces/ns-1/pods/a-pod"), "expected request to the namespace of other-context")
+	})
+	s.Run("tool calls with context use the provided context", func() {
+		_, err := s.CallTool("pods_get", map[string]interface{}{"name": "a-pod", "context": "fake-context"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.True(s.requested("/api/v1/namespaces/default/pods/a-pod"), "expected request to the namespace of fake-context")
+	})
+	s.Run("the session keeps a client per context", func() {
+		s.mcpServer.sessions.mu.RLock()
+		defer s.mcpServer.sessions.mu.RUnlock()
+		s.Require().Len(s.mcpServer.sessions.kubernetes, 1)
+		for _, clients := range s.mcpServer.sessions.kubernetes {
+			s.Len(clients, 2)
+		}
+	})
+	s.Run("sessions reuse their clients", func() {
+		_, err := s.CallTool("pods_get", map[string]interface{}{"name": "a-pod"})
+		s.Require().NoError(err)
+		s.mcpServer.sessions.mu.RLock()
+		defer s.mcpServer.sessions.mu.RUnlock()
+		for _, clients := range s.mcpServer.sessions.kubernetes {
+			s.Len(clients, 2)
+		}
+	})
+}
+
+func (s *SessionSuite) TestSessionStoreKubernetes() {
+	s.InitMcpClient()
+	var session *gosdk.ServerSession
+	for ss := range s.mcpServer.server.Sessions() {
+		session = ss
+	}
+	s.Require().NotNil(session)
+	store := NewSessionStore()
+	derivations := 0
+	derive := func() (*internalk8s.Kubernetes, error) {
+		derivations++
+		return &internalk8s.Kubernetes{}, nil
+	}
+	first, err := store.Kubernetes(session, "ctx-1", "Bearer token-1", derive)
+	s.Require().NoError(err)
+	s.Run("reuses the client of the session for the same credentials", func() {
+		k, err := store.Kubernetes(session, "ctx-1", "Bearer token-1", derive)
+		s.Require().NoError(err)
+		s.Same(first, k)
+		s.Equal(1, derivations)
+	})
+	s.Run("derives a new client when the credentials change", func() {
+		k, err := store.Kubernetes(session, "ctx-1", "Bearer token-2", derive)
+		s.Require().NoError(err)
+		s.NotSame(first, k)
+		s.Equal(2, derivations)
+	})
+	s.Run("derives a new client after a reset", func() {
+		store.ResetKubernetes()
+		_, err := store.Kubernetes(session, "ctx-1", "Bearer token-2", derive)
+		s.Require().NoError(err)
+		s.Equal(3, derivations)
+	})
+}
+
+func TestSession(t *testing.T) {
+	suite.Run(t, new(SessionSuite))
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type SnapshotsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	configMaps []v1.ConfigMap
+}
+
+func (s *SnapshotsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: []string{
+		`{"name":"configmaps","singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["get","list","watch"]}`,
+	}})
+	s.configMaps = []v1.ConfigMap{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "settings", ResourceVersion: "1"},
+		Data:       map[string]string{"mode": "blue"},
+	}}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/namespaces/ns-1/configmaps" {
+			test.WriteObject(w, &v1.ConfigMapList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"}, Items: s.configMaps})
+		}
+	}))
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		[artifacts]
+		dir = "` + filepath.ToSlash(s.T().TempDir()) + `"
+	`)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *SnapshotsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *SnapshotsSuite) TestSnapshots() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("snapshot_create", map[string]interface{}{"resources": []any{"configmaps"}, "namespaces": []any{"ns-1"}})
+	s.Require().NoError(err)
+	s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+	text := toolResult.Content[0].(mcp.TextContent).Text
+	uri := regexp.MustCompile(`artifact://snapshot-[a-z0-9.-]+\.json`).FindString(text)
+	s.Run("snapshot_create returns the artifact and the objects per kind", func() {
+		s.Regexp(`^Snapshot of 1 objects in namespace ns-1 stored as artifact artifact://snapshot-\d{8}-\d{6}-[0-9a-f]{8}\.json, `, text)
+		s.Regexp(`ConfigMap\s+1`, text)
+	})
+	s.configMaps[0].ResourceVersion = "2"
+	s.configMaps[0].Data["mode"] = "green"
+	s.Run("snapshot_diff returns the changes and their field-level diffs", func() {
+		toolResult, err := s.CallTool("snapshot_diff", map[string]interface{}{"name": uri})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Regexp(`^# 0 added, 0 removed, 1 modified, 0 unchanged objects in namespace ns-1 since snapshot snapshot-`, text)
+		s.Regexp(`modified\s+v1\s+ConfigMap\s+ns-1\s+settings\s+1`, text)
+		s.Contains(text, "# ConfigMap ns-1/settings\n  data.mode: \"blue\" -> \"green\"\n")
+	})
+	s.Run("snapshot_diff(output_format=json) returns the changes envelope", func() {
+		toolResult, err := s.CallTool("snapshot_diff", map[string]interface{}{"name": uri, "output_format": "json"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		var envelope map[string]any
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("SnapshotChange", envelope["kind"])
+		s.Len(envelope["items"], 1)
+	})
+	s.Run("snapshot_diff(name=missing) returns error", func() {
+		toolResult, _ := s.CallTool("snapshot_diff", map[string]interface{}{"name": "snapshot-20250101-000000-00000000.json"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to diff snapshot snapshot-20250101-000000-00000000.json: artifact not found: snapshot-20250101-000000-00000000.json, "+
+			"list the available ones with artifacts_list", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *SnapshotsSuite) TestSnapshotsReadOnly() {
+	s.Cfg.ReadOnly = true
+	s.InitMcpClient()
+	s.Run("snapshot_create is available in read-only mode", func() {
+		toolResult, err := s.CallTool("snapshot_create", map[string]interface{}{"resources": []any{"configmaps"}, "namespaces": []any{"ns-1"}})
+		s.Require().NoError(err)
+		s.False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestSnapshots(t *testing.T) {
+	suite.Run(t, new(SnapshotsSuite))
+}
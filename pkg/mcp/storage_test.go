@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type StorageSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *StorageSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	claims := []corev1.PersistentVolumeClaim{{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("standard"),
+			VolumeName:       "pvc-1234",
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources:        corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound, Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("fast"),
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources:        corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+			`{"name":"persistentvolumeclaims","singularName":"","namespaced":true,"kind":"PersistentVolumeClaim","verbs":["get","list","watch"]}`,
+			`{"name":"persistentvolumes","singularName":"","namespaced":false,"kind":"PersistentVolume","verbs":["get","list","watch"]}`,
+		},
+		Groups: []string{
+			`{"name":"storage.k8s.io","versions":[{"groupVersion":"storage.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"storage.k8s.io/v1","version":"v1"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		const claimsPath = "/api/v1/namespaces/default/persistentvolumeclaims"
+		switch {
+		case req.URL.Path == "/apis/storage.k8s.io/v1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"storage.k8s.io/v1","resources":[
+				{"name":"storageclasses","singularName":"","namespaced":false,"kind":"StorageClass","verbs":["get","list","watch"]},
+				{"name":"volumeattachments","singularName":"","namespaced":false,"kind":"VolumeAttachment","verbs":["get","list","watch"]}
+			]}`))
+		case req.URL.Path == claimsPath || req.URL.Path == "/api/v1/persistentvolumeclaims":
+			test.WriteObject(w, &corev1.PersistentVolumeClaimList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaimList"}, Items: claims})
+		case strings.HasPrefix(req.URL.Path, claimsPath+"/"):
+			for _, claim := range claims {
+				if claim.Name == strings.TrimPrefix(req.URL.Path, claimsPath+"/") {
+					claim.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"}
+					test.WriteObject(w, &claim)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case req.URL.Path == "/api/v1/persistentvolumes":
+			test.WriteObject(w, &corev1.PersistentVolumeList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeList"},
+				Items: []corev1.PersistentVolume{{
+					ObjectMeta: metav1.ObjectMeta{Name: "pvc-1234"},
+					Spec: corev1.PersistentVolumeSpec{
+						StorageClassName:              "standard",
+						PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+						Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+						PersistentVolumeSource:        corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-1"}},
+						ClaimRef:                      &corev1.ObjectReference{Namespace: "default", Name: "data"},
+					},
+					Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+				}},
+			})
+		case req.URL.Path == "/apis/storage.k8s.io/v1/storageclasses":
+			test.WriteObject(w, &storagev1.StorageClassList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "storage.k8s.io/v1", Kind: "StorageClassList"},
+				Items: []storagev1.StorageClass{{
+					ObjectMeta:        metav1.ObjectMeta{Name: "standard"},
+					Provisioner:       "ebs.csi.aws.com",
+					VolumeBindingMode: ptr.To(storagev1.VolumeBindingWaitForFirstConsumer),
+				}},
+			})
+		case req.URL.Path == "/apis/storage.k8s.io/v1/volumeattachments":
+			test.WriteObject(w, &storagev1.VolumeAttachmentList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "storage.k8s.io/v1", Kind: "VolumeAttachmentList"},
+				Items: []storagev1.VolumeAttachment{{
+					ObjectMeta: metav1.ObjectMeta{Name: "csi-1234"},
+					Spec:       storagev1.VolumeAttachmentSpec{NodeName: "node-1", Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To("pvc-1234")}},
+					Status:     storagev1.VolumeAttachmentStatus{Attached: true},
+				}},
+			})
+		case req.URL.Path == "/api/v1/namespaces/default/pods" || req.URL.Path == "/api/v1/pods":
+			test.WriteObject(w, &corev1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []corev1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default"},
+					Spec: corev1.PodSpec{NodeName: "node-1", Volumes: []corev1.Volume{
+						{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+					}},
+				}},
+			})
+		case req.URL.Path == "/api/v1/namespaces/default/events":
+			test.WriteObject(w, &corev1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []corev1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "cache.1", Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "cache"},
+					Type:           corev1.EventTypeWarning,
+					Reason:         "ProvisioningFailed",
+					Message:        `storageclass.storage.k8s.io "fast" not found`,
+				}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"storage"}
+}
+
+func (s *StorageSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *StorageSuite) TestStoragePvcsList() {
+	s.InitMcpClient()
+	s.Run("storage_pvcs_list()", func() {
+		toolResult, err := s.CallTool("storage_pvcs_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns summary", func() {
+			s.True(strings.HasPrefix(text, "# 2 PersistentVolumeClaims found, 1 not bound\n"), "unexpected result %v", text)
+		})
+		var claims kubernetes.StorageClaims
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &claims))
+		s.Require().Len(claims.Claims, 2)
+		s.Run("maps claims to their volume, class, attachments, and pods", func() {
+			data := claims.Claims[1]
+			s.Equal("data", data.Name)
+			s.Equal("10Gi", data.Capacity)
+			s.Equal("pvc-1234", data.Volume)
+			s.Equal("ebs.csi.aws.com", data.Driver)
+			s.Equal("Delete", data.ReclaimPolicy)
+			s.Equal("WaitForFirstConsumer", data.VolumeBindingMode)
+			s.Equal([]string{"node-1"}, data.Nodes)
+			s.Equal([]string{"db-0"}, data.Pods)
+		})
+		s.Run("returns pending claims", func() {
+			s.Equal("cache", claims.Claims[0].Name)
+			s.Equal(corev1.ClaimPending, claims.Claims[0].Phase)
+		})
+	})
+	s.Run("storage_pvcs_list(all_namespaces=true)", func() {
+		toolResult, err := s.CallTool("storage_pvcs_list", map[string]interface{}{"all_namespaces": true})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 2 PersistentVolumeClaims found")
+	})
+}
+
+func (s *StorageSuite) TestStorageWhyPending() {
+	s.InitMcpClient()
+	s.Run("storage_why_pending(name=nil)", func() {
+		toolResult, err := s.CallTool("storage_why_pending", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze persistent volume claim binding")
+	})
+	s.Run("storage_why_pending(name=missing)", func() {
+		toolResult, err := s.CallTool("storage_why_pending", map[string]interface{}{"name": "missing"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to analyze persistent volume claim missing binding")
+	})
+	s.Run("storage_why_pending(name=cache)", func() {
+		toolResult, err := s.CallTool("storage_why_pending", map[string]interface{}{"namespace": "default", "name": "cache"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns summary", func() {
+			s.True(strings.HasPrefix(text, "# PersistentVolumeClaim cache is Pending: StorageClass fast not found"), "unexpected result %v", text)
+		})
+		s.Run("returns events of the claim", func() {
+			s.Contains(text, "reason: ProvisioningFailed\n")
+			s.Contains(text, "object: PersistentVolumeClaim/cache\n")
+		})
+	})
+	s.Run("storage_why_pending(name=data)", func() {
+		toolResult, err := s.CallTool("storage_why_pending", map[string]interface{}{"namespace": "default", "name": "data"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text,
+			"# PersistentVolumeClaim data is not pending, it's Bound to PersistentVolume pvc-1234\n"))
+	})
+}
+
+func TestStorage(t *testing.T) {
+	suite.Run(t, new(StorageSuite))
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type TargetNamespacesSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *TargetNamespacesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/namespaces/team-a/pods" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"PodList","items":[{"metadata":{"name":"web","namespace":"team-a"}}]}`))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.TargetNamespaces = []string{"team-a", "team-b"}
+}
+
+func (s *TargetNamespacesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *TargetNamespacesSuite) TestTools() {
+	s.Cfg.Toolsets = []string{"core", "diagnostics"}
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	names := make([]string, 0, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	s.Run("hides node-level tools", func() {
+		s.NotContains(names, "nodes_top")
+		s.NotContains(names, "node_files")
+		s.NotContains(names, "diagnostics_collect")
+	})
+	s.Run("hides cluster-scoped destructive tools", func() {
+		s.NotContains(names, "namespaces")
+	})
+	s.Run("hides tools across all namespaces", func() {
+		s.NotContains(names, "pods_list")
+	})
+	s.Run("exposes namespaced tools", func() {
+		s.Contains(names, "pods_list_in_namespace")
+		s.Contains(names, "pods_get")
+	})
+	s.Run("makes the namespace of pods_list_in_namespace optional", func() {
+		for _, tool := range tools.Tools {
+			if tool.Name == "pods_list_in_namespace" {
+				s.NotContains(tool.InputSchema.Required, "namespace")
+			}
+		}
+	})
+}
+
+func (s *TargetNamespacesSuite) TestNamespacedToolCalls() {
+	s.InitMcpClient()
+	s.Run("defaults to the first target namespace", func() {
+		toolResult, err := s.CallTool("pods_list_in_namespace", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "web")
+	})
+	s.Run("rejects other namespaces", func() {
+		toolResult, err := s.CallTool("pods_list_in_namespace", map[string]interface{}{"namespace": "kube-system"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+			"resource not allowed: namespace kube-system, the server is restricted to the target_namespaces (team-a, team-b)")
+		s.Equal(map[string]any{"error": "denied_by_config"}, toolResult.StructuredContent)
+	})
+	s.Run("rejects cluster-scoped changes of the generic tools", func() {
+		toolResult, err := s.CallTool("resources_delete", map[string]interface{}{"apiVersion": "v1", "kind": "Node", "name": "node-1"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "resource not allowed: /v1, Kind=Node")
+	})
+}
+
+func TestTargetNamespaces(t *testing.T) {
+	suite.Run(t, new(TargetNamespacesSuite))
+}
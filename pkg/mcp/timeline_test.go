@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type TimelineSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *TimelineSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{V1Resources: []string{
+		`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+	}})
+	created := metav1.NewTime(time.Date(2025, 1, 1, 14, 32, 0, 0, time.UTC))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns-1/pods/web-1":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "web-1", CreationTimestamp: created},
+			})
+		case "/api/v1/namespaces/ns-1/events":
+			test.WriteObject(w, &v1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []v1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Namespace: "ns-1", Name: "web-1.1"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+					Type:           v1.EventTypeWarning, Reason: "BackOff", Message: "Back-off pulling image", Count: 4,
+					FirstTimestamp: created, LastTimestamp: metav1.NewTime(created.Add(3 * time.Minute)),
+				}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *TimelineSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *TimelineSuite) TestTimeline() {
+	s.InitMcpClient()
+	arguments := map[string]interface{}{
+		"namespace": "ns-1",
+		"kind":      "Pod",
+		"name":      "web-1",
+		"since":     "2025-01-01T14:00:00Z",
+		"until":     "2025-01-01T15:00:00Z",
+	}
+	s.Run("timeline(kind=Pod, name=web-1)", func() {
+		toolResult, err := s.CallTool("timeline", arguments)
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the summary", func() {
+			s.True(strings.HasPrefix(text, "# 2 entries for Pod ns-1/web-1 from 2025-01-01T14:00:00Z to 2025-01-01T15:00:00Z (1 warnings): "+
+				"1 events, 1 pod transitions, 0 rollout entries, 0 changes\n"), text)
+		})
+		s.Run("returns the entries", func() {
+			s.Regexp(`2025-01-01T14:32:00Z\s+pod\s+Normal\s+Pod/web-1\s+Created`, text)
+			s.Regexp(`2025-01-01T14:35:00Z\s+event\s+Warning\s+Pod/web-1\s+BackOff\s+Back-off pulling image \(x4\)`, text)
+		})
+	})
+	s.Run("timeline(kind=Pod, name=web-1, output_format=json)", func() {
+		arguments["output_format"] = "json"
+		toolResult, err := s.CallTool("timeline", arguments)
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, toolResult.Content[0].(mcp.TextContent).Text)
+		var envelope map[string]any
+		s.Require().NoError(json.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &envelope))
+		s.Equal("TimelineEntry", envelope["kind"])
+		s.Len(envelope["items"], 2)
+	})
+	s.Run("timeline(since=invalid)", func() {
+		toolResult, _ := s.CallTool("timeline", map[string]interface{}{"since": "yesterday"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.True(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "failed to parse since parameter: "), toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("timeline(kind=Pod) missing name", func() {
+		toolResult, _ := s.CallTool("timeline", map[string]interface{}{"kind": "Pod"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to build timeline: kind and name must be provided together, or none of them for the timeline of the namespace",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestTimeline(t *testing.T) {
+	suite.Run(t, new(TimelineSuite))
+}
@@ -1,6 +1,8 @@
 package mcp
 
 import (
+	"strings"
+
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 )
@@ -31,8 +33,8 @@ func ShouldIncludeTargetListTool(targetName string, targets []string) ToolFilter
 		}
 
 		// TODO: this check should be removed or make more generic when we have other
-		if tool.Tool.Name == "configuration_contexts_list" && targetName != kubernetes.KubeConfigTargetParameterName {
-			// let's not include configuration_contexts_list if we aren't targeting contexts in our Provider
+		if strings.HasPrefix(tool.Tool.Name, "configuration_contexts_") && targetName != kubernetes.KubeConfigTargetParameterName {
+			// let's not include configuration_contexts_* tools if we aren't targeting contexts in our Provider
 			return false
 		}
 
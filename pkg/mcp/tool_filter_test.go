@@ -76,6 +76,18 @@ func (s *ToolFilterSuite) TestShouldIncludeTargetListTool() {
 				s.False(filter(tool))
 			})
 		})
+		s.Run("with targets > 1", func() {
+			s.Run("and tool is configuration_contexts_set_default and targetName is not context: returns false", func() {
+				filter := ShouldIncludeTargetListTool("not_context", []string{"1", "2"})
+				tool := api.ServerTool{Tool: api.Tool{Name: "configuration_contexts_set_default"}, TargetListProvider: ptr.To(true)}
+				s.False(filter(tool))
+			})
+			s.Run("and tool is configuration_contexts_set_default and targetName is context: returns true", func() {
+				filter := ShouldIncludeTargetListTool("context", []string{"1", "2"})
+				tool := api.ServerTool{Tool: api.Tool{Name: "configuration_contexts_set_default"}, TargetListProvider: ptr.To(true)}
+				s.True(filter(tool))
+			})
+		})
 	})
 }
 
@@ -2,14 +2,29 @@ package mcp
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
 )
 
 type ToolMutator func(tool api.ServerTool) api.ServerTool
 
+// CompositeMutator applies the provided mutators in order
+func CompositeMutator(mutators ...ToolMutator) ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		for _, m := range mutators {
+			tool = m(tool)
+		}
+		return tool
+	}
+}
+
 const maxTargetsInEnum = 5 // TODO: test and validate that this is a reasonable cutoff
 
 // WithTargetParameter adds a target selection parameter to the tool's input schema if the tool is cluster-aware
@@ -62,3 +77,87 @@ func createTargetProperty(defaultCluster, targetName string, targets []string) *
 
 	return baseSchema
 }
+
+// WithDryRunParameter adds a dry_run parameter to the tool's input schema if the tool is not read-only
+func WithDryRunParameter(dryRunParameterName string, globalDryRun bool) ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		if ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
+			return tool
+		}
+
+		if tool.Tool.InputSchema == nil {
+			tool.Tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+
+		if tool.Tool.InputSchema.Properties == nil {
+			tool.Tool.InputSchema.Properties = make(map[string]*jsonschema.Schema)
+		}
+
+		description := "Optional parameter to preview the changes without persisting them. "
+		if tool.IsDryRunSupported() {
+			description += "The changes are validated by the Kubernetes API server (server-side dry-run). "
+		} else {
+			description += "The tool is not invoked, only the operation it would perform is described. "
+		}
+		if globalDryRun {
+			description += "The server runs in dry-run mode, changes are never persisted regardless of this parameter"
+		} else {
+			description += "Defaults to false"
+		}
+		tool.Tool.InputSchema.Properties[dryRunParameterName] = &jsonschema.Schema{
+			Type:        "boolean",
+			Description: description,
+		}
+
+		return tool
+	}
+}
+
+// WithCacheParameter adds a cache parameter to the tool's input schema if the results of the tool are cached
+func WithCacheParameter(cacheParameterName string, cacheTTL func(tool api.ServerTool) time.Duration) ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		ttl := cacheTTL(tool)
+		if ttl <= 0 {
+			return tool
+		}
+
+		if tool.Tool.InputSchema == nil {
+			tool.Tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+
+		if tool.Tool.InputSchema.Properties == nil {
+			tool.Tool.InputSchema.Properties = make(map[string]*jsonschema.Schema)
+		}
+
+		tool.Tool.InputSchema.Properties[cacheParameterName] = &jsonschema.Schema{
+			Type: "boolean",
+			Description: fmt.Sprintf("Optional parameter to use the cached result of a previous call with the same arguments "+
+				"(results are cached for %s). Set to false to query the cluster again. Defaults to true", ttl),
+		}
+
+		return tool
+	}
+}
+
+// WithTargetNamespaces makes the namespace parameter of the Kubernetes tools optional when the server is restricted to
+// target namespaces, the tool calls that don't provide one default to the configured namespace
+func WithTargetNamespaces(targetNamespaces []string) ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		if len(targetNamespaces) == 0 || len(tool.Permissions) == 0 || tool.Tool.InputSchema == nil ||
+			tool.Tool.InputSchema.Properties["namespace"] == nil || !slices.Contains(tool.Tool.InputSchema.Required, "namespace") {
+			return tool
+		}
+
+		// the schema is shared by the toolset definitions, it's copied before it's changed
+		inputSchema := *tool.Tool.InputSchema
+		inputSchema.Required = slices.DeleteFunc(slices.Clone(inputSchema.Required), func(name string) bool { return name == "namespace" })
+		inputSchema.Properties = maps.Clone(inputSchema.Properties)
+		namespace := *inputSchema.Properties["namespace"]
+		namespace.Description = fmt.Sprintf("%s (Optional, one of %s, defaults to the namespace of the current context "+
+			"if it's one of them or to %s otherwise)", namespace.Description, strings.Join(targetNamespaces, ", "), targetNamespaces[0])
+		inputSchema.Properties["namespace"] = &namespace
+		tool.Tool.InputSchema = &inputSchema
+
+		return tool
+	}
+}
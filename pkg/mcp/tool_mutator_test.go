@@ -345,3 +345,101 @@ func (s *TargetParameterToolMutatorSuite) TestNonClusterAwareTool() {
 func TestTargetParameterToolMutator(t *testing.T) {
 	suite.Run(t, new(TargetParameterToolMutatorSuite))
 }
+
+type DryRunParameterToolMutatorSuite struct {
+	suite.Suite
+}
+
+func (s *DryRunParameterToolMutatorSuite) TestMutatingTool() {
+	tool := WithDryRunParameter("dry_run", false)(createTestToolWithNilSchema("mutating-tool"))
+	s.Require().NotNil(tool.Tool.InputSchema)
+	s.Require().NotNil(tool.Tool.InputSchema.Properties["dry_run"], "Expected dry_run property to be added")
+	s.Run("adds boolean parameter", func() {
+		s.Equal("boolean", tool.Tool.InputSchema.Properties["dry_run"].Type)
+	})
+	s.Run("describes the simulated dry-run", func() {
+		desc := tool.Tool.InputSchema.Properties["dry_run"].Description
+		s.Contains(desc, "The tool is not invoked, only the operation it would perform is described")
+		s.Contains(desc, "Defaults to false")
+	})
+}
+
+func (s *DryRunParameterToolMutatorSuite) TestMutatingToolWithDryRunSupport() {
+	tool := createTestTool("dry-run-tool")
+	tool.DryRunSupported = ptr.To(true)
+	tool = WithDryRunParameter("dry_run", true)(tool)
+	s.Require().NotNil(tool.Tool.InputSchema.Properties["dry_run"], "Expected dry_run property to be added")
+	s.Run("describes the server-side dry-run", func() {
+		s.Contains(tool.Tool.InputSchema.Properties["dry_run"].Description, "validated by the Kubernetes API server (server-side dry-run)")
+	})
+	s.Run("describes the server dry-run mode", func() {
+		s.Contains(tool.Tool.InputSchema.Properties["dry_run"].Description, "changes are never persisted regardless of this parameter")
+	})
+}
+
+func (s *DryRunParameterToolMutatorSuite) TestReadOnlyTool() {
+	tool := createTestTool("read-only-tool")
+	tool.Tool.Annotations.ReadOnlyHint = ptr.To(true)
+	tool = WithDryRunParameter("dry_run", true)(tool)
+	s.Run("does not add dry_run parameter", func() {
+		s.Nilf(tool.Tool.InputSchema.Properties["dry_run"], "Expected dry_run property to not be added")
+	})
+}
+
+func TestDryRunParameterToolMutator(t *testing.T) {
+	suite.Run(t, new(DryRunParameterToolMutatorSuite))
+}
+
+type TargetNamespacesToolMutatorSuite struct {
+	suite.Suite
+}
+
+func createTestToolWithRequiredNamespace(name string) api.ServerTool {
+	return api.ServerTool{
+		Tool: api.Tool{
+			Name: name,
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"namespace": {Type: "string", Description: "Namespace to list pods from"}},
+				Required:   []string{"namespace"},
+			},
+		},
+		Permissions: []api.ResourcePermission{{Verb: "list", Resource: "pods"}},
+	}
+}
+
+func (s *TargetNamespacesToolMutatorSuite) TestNamespacedTool() {
+	original := createTestToolWithRequiredNamespace("namespaced-tool")
+	tool := WithTargetNamespaces([]string{"team-a", "team-b"})(original)
+	s.Run("makes namespace optional", func() {
+		s.NotContains(tool.Tool.InputSchema.Required, "namespace")
+	})
+	s.Run("describes the default namespace", func() {
+		s.Equal("Namespace to list pods from (Optional, one of team-a, team-b, defaults to the namespace of the current context "+
+			"if it's one of them or to team-a otherwise)", tool.Tool.InputSchema.Properties["namespace"].Description)
+	})
+	s.Run("does not change the original tool", func() {
+		s.Equal([]string{"namespace"}, original.Tool.InputSchema.Required)
+		s.Equal("Namespace to list pods from", original.Tool.InputSchema.Properties["namespace"].Description)
+	})
+}
+
+func (s *TargetNamespacesToolMutatorSuite) TestWithoutTargetNamespaces() {
+	tool := WithTargetNamespaces(nil)(createTestToolWithRequiredNamespace("namespaced-tool"))
+	s.Run("keeps namespace required", func() {
+		s.Equal([]string{"namespace"}, tool.Tool.InputSchema.Required)
+	})
+}
+
+func (s *TargetNamespacesToolMutatorSuite) TestNonKubernetesTool() {
+	tool := createTestToolWithRequiredNamespace("kiali-tool")
+	tool.Permissions = nil
+	tool = WithTargetNamespaces([]string{"team-a"})(tool)
+	s.Run("keeps namespace required", func() {
+		s.Equal([]string{"namespace"}, tool.Tool.InputSchema.Required)
+	})
+}
+
+func TestTargetNamespacesToolMutator(t *testing.T) {
+	suite.Run(t, new(TargetNamespacesToolMutatorSuite))
+}
@@ -11,15 +11,26 @@ import (
 	"github.com/containers/kubernetes-mcp-server/internal/test"
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	configuration "github.com/containers/kubernetes-mcp-server/pkg/config"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/config"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/core"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/diagnostics"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/gitops"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/helm"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/kiali"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/kubevirt"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/machines"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/metrics"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/networkdebug"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/openshift"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/rbac"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/storage"
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/suite"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/ptr"
 )
 
 const updateJsonEnvVar = "UPDATE_TOOLSETS_JSON"
@@ -156,9 +167,16 @@ func (s *ToolsetsSuite) TestGranularToolsetsTools() {
 	testCases := []api.Toolset{
 		&core.Toolset{},
 		&config.Toolset{},
+		&diagnostics.Toolset{},
+		&gitops.Toolset{},
 		&helm.Toolset{},
 		&kiali.Toolset{},
 		&kubevirt.Toolset{},
+		&machines.Toolset{},
+		&metrics.Toolset{},
+		&networkdebug.Toolset{},
+		&rbac.Toolset{},
+		&storage.Toolset{},
 	}
 	for _, testCase := range testCases {
 		s.Run("Toolset "+testCase.GetName(), func() {
@@ -178,6 +196,35 @@ func (s *ToolsetsSuite) TestGranularToolsetsTools() {
 	}
 }
 
+func (s *ToolsetsSuite) TestOpenShiftToolsetTools() {
+	s.Run("Toolset openshift in Kubernetes", func() {
+		toolsets.Clear()
+		toolsets.Register(&openshift.Toolset{})
+		s.Cfg.Toolsets = []string{"openshift"}
+		s.InitMcpClient()
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Run("ListTools returns no tools", func() {
+			s.NoError(err, "Expected no error from ListTools")
+			s.Empty(tools.Tools, "Expected no tools from ListTools")
+		})
+	})
+	s.Run("Toolset openshift in OpenShift", func() {
+		toolsets.Clear()
+		toolsets.Register(&openshift.Toolset{})
+		s.Handle(&test.InOpenShiftHandler{})
+		s.Cfg.Toolsets = []string{"openshift"}
+		s.InitMcpClient()
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Run("ListTools returns tools", func() {
+			s.NotNil(tools, "Expected tools from ListTools")
+			s.NoError(err, "Expected no error from ListTools")
+		})
+		s.Run("ListTools returns correct Tool metadata", func() {
+			s.assertJsonSnapshot("toolsets-openshift-tools.json", tools.Tools)
+		})
+	})
+}
+
 func (s *ToolsetsSuite) TestInputSchemaEdgeCases() {
 	//https://github.com/containers/kubernetes-mcp-server/issues/340
 	s.Run("InputSchema for no-arg tool is object with empty properties", func() {
@@ -200,6 +247,57 @@ func (s *ToolsetsSuite) TestInputSchemaEdgeCases() {
 	})
 }
 
+type echoHandler struct{ prefix string }
+
+func (h *echoHandler) Handle(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	return api.NewToolCallResult(h.prefix+params.GetArguments()["message"].(string), nil), nil
+}
+
+type embeddedToolset struct{}
+
+func (t *embeddedToolset) GetName() string { return "embedded" }
+
+func (t *embeddedToolset) GetDescription() string {
+	return "Toolset provided by a program embedding the server"
+}
+
+func (t *embeddedToolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return []api.ServerTool{{
+		Tool: api.Tool{
+			Name:        "echo",
+			InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"message": {Type: "string"}}},
+			Annotations: api.ToolAnnotations{ReadOnlyHint: ptr.To(true)},
+		},
+		Handler:      (&echoHandler{prefix: "echo: "}).Handle,
+		ClusterAware: ptr.To(false),
+	}}
+}
+
+func (s *ToolsetsSuite) TestToolsetRegistry() {
+	s.Run("Custom toolset registry", func() {
+		s.Cfg.Toolsets = []string{"embedded"}
+		var err error
+		s.mcpServer, err = NewServer(Configuration{StaticConfig: s.Cfg}, WithToolsetRegistry(toolsets.NewToolsetRegistry(&embeddedToolset{})))
+		s.Require().NoError(err, "Expected no error creating MCP server")
+		s.McpClient = test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP())
+		// exposes the tools of the registry toolsets
+		s.Equal([]string{"echo", "session_configure", "self_check", "continue_result"}, s.mcpServer.GetEnabledTools())
+		// calls the tool handler
+		toolResult, err := s.CallTool("echo", map[string]interface{}{"message": "hello"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("echo: hello", toolResult.Content[0].(mcp.TextContent).Text)
+		// does not register the toolsets in the default registry
+		s.Nil(toolsets.ToolsetFromString("embedded"))
+	})
+	s.Run("Default registry rejects unknown toolsets", func() {
+		s.Cfg.Toolsets = []string{"embedded"}
+		_, err := NewServer(Configuration{StaticConfig: s.Cfg})
+		s.Require().Error(err, "Expected error creating MCP server")
+		s.Contains(err.Error(), "invalid toolset name: embedded")
+	})
+}
+
 func (s *ToolsetsSuite) InitMcpClient() {
 	var err error
 	s.mcpServer, err = NewServer(Configuration{StaticConfig: s.Cfg})
@@ -0,0 +1,351 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+)
+
+type WorkloadsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	replicas   int32
+	hpas       []autoscalingv2.HorizontalPodAutoscaler
+}
+
+func (s *WorkloadsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.replicas = 1
+	s.hpas = nil
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(&test.DiscoveryClientHandler{
+		V1Resources: []string{
+			`{"name":"events","singularName":"","namespaced":true,"kind":"Event","verbs":["get","list","watch"]}`,
+		},
+		Groups: []string{
+			`{"name":"autoscaling","versions":[{"groupVersion":"autoscaling/v2","version":"v2"}],"preferredVersion":{"groupVersion":"autoscaling/v2","version":"v2"}}`,
+		},
+	})
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/autoscaling/v2":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"autoscaling/v2","resources":[
+				{"name":"horizontalpodautoscalers","singularName":"","namespaced":true,"kind":"HorizontalPodAutoscaler","verbs":["get","list"]}
+			]}`))
+		case "/apis/autoscaling/v2/namespaces/default/horizontalpodautoscalers":
+			test.WriteObject(w, &autoscalingv2.HorizontalPodAutoscalerList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscalerList"},
+				Items:    s.hpas,
+			})
+		case "/apis/apps/v1/namespaces/default/deployments/web/scale":
+			if req.Method == http.MethodPut {
+				body, _ := io.ReadAll(req.Body)
+				if scale, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body); err == nil {
+					s.replicas = scale.(*autoscalingv1.Scale).Spec.Replicas
+				}
+			}
+			test.WriteObject(w, &autoscalingv1.Scale{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v1", Kind: "Scale"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       autoscalingv1.ScaleSpec{Replicas: s.replicas},
+			})
+		case "/apis/apps/v1/namespaces/default/deployments/web":
+			test.WriteObject(w, &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To(s.replicas),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+				Status: appsv1.DeploymentStatus{
+					ObservedGeneration: 2,
+					Replicas:           s.replicas,
+					UpdatedReplicas:    s.replicas,
+					AvailableReplicas:  s.replicas,
+				},
+			})
+		case "/apis/apps/v1/namespaces/default/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSetList"},
+				Items: []appsv1.ReplicaSet{{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-5d9f", Namespace: "default", Annotations: map[string]string{"deployment.kubernetes.io/revision": "2"},
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: ptr.To(true)}}},
+					Status: appsv1.ReplicaSetStatus{Replicas: 1, AvailableReplicas: 1},
+				}, {
+					ObjectMeta: metav1.ObjectMeta{Name: "web-7c4b", Namespace: "default", Annotations: map[string]string{"deployment.kubernetes.io/revision": "1"},
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: ptr.To(true)}}},
+				}, {
+					ObjectMeta: metav1.ObjectMeta{Name: "other-1a2b", Namespace: "default", OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "apps/v1", Kind: "Deployment", Name: "other", Controller: ptr.To(true)}}},
+				}},
+			})
+		case "/api/v1/namespaces/default/pods":
+			test.WriteObject(w, &corev1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				Items: []corev1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-5d9f-abcde", Namespace: "default"},
+					Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+						Name:                 "web",
+						RestartCount:         3,
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+					}}},
+				}},
+			})
+		case "/api/v1/namespaces/default/events":
+			test.WriteObject(w, &corev1.EventList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+				Items: []corev1.Event{
+					{
+						ObjectMeta:     metav1.ObjectMeta{Name: "web-5d9f-abcde.1", Namespace: "default"},
+						InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-5d9f-abcde"},
+						Type:           corev1.EventTypeWarning,
+						Reason:         "BackOff",
+						Message:        "Back-off restarting failed container",
+					},
+					{
+						ObjectMeta:     metav1.ObjectMeta{Name: "other.1", Namespace: "default"},
+						InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "other"},
+						Reason:         "Pulled",
+					},
+				},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *WorkloadsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *WorkloadsSuite) TestWorkloadsScale() {
+	s.InitMcpClient()
+	s.Run("workloads_scale(kind=nil)", func() {
+		toolResult, err := s.CallTool("workloads_scale", map[string]interface{}{"name": "web", "replicas": 2})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to scale workload, missing argument kind", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("workloads_scale(kind=DaemonSet)", func() {
+		toolResult, err := s.CallTool("workloads_scale", map[string]interface{}{"kind": "DaemonSet", "name": "web", "replicas": 2})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "unsupported workload kind DaemonSet")
+	})
+	s.Run("workloads_scale(kind=Deployment, name=web, replicas=3)", func() {
+		toolResult, err := s.CallTool("workloads_scale", map[string]interface{}{
+			"kind": "Deployment", "namespace": "default", "name": "web", "replicas": 3,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("updates scale subresource", func() {
+			s.Equal(int32(3), s.replicas)
+		})
+		s.Run("returns previous and current replicas", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, "previousReplicas: 1")
+			s.Contains(text, "replicas: 3")
+		})
+	})
+	s.Run("workloads_scale(kind=Deployment, name=web, replicas=2, wait_ready=true)", func() {
+		toolResult, err := s.CallTool("workloads_scale", map[string]interface{}{
+			"kind": "Deployment", "namespace": "default", "name": "web", "replicas": 2, "wait_ready": true, "timeout": 5,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("returns ready replicas", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "readyReplicas: 2")
+		})
+	})
+}
+
+func (s *WorkloadsSuite) TestWorkloadsScaleWithHorizontalPodAutoscaler() {
+	s.hpas = []autoscalingv2.HorizontalPodAutoscaler{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			MinReplicas:    ptr.To(int32(1)),
+			MaxReplicas:    10,
+		},
+	}}
+	s.InitMcpClient()
+	s.Run("workloads_scale(kind=Deployment, name=web, replicas=5)", func() {
+		toolResult, err := s.CallTool("workloads_scale", map[string]interface{}{
+			"kind": "Deployment", "namespace": "default", "name": "web", "replicas": 5,
+		})
+		s.Run("refuses to scale", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Regexp("refusing to scale: Deployment web is managed by HorizontalPodAutoscaler web-hpa", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Run("does not update scale subresource", func() {
+			s.Equal(int32(1), s.replicas)
+		})
+	})
+	s.Run("workloads_scale(kind=Deployment, name=web, replicas=5, force=true)", func() {
+		toolResult, err := s.CallTool("workloads_scale", map[string]interface{}{
+			"kind": "Deployment", "namespace": "default", "name": "web", "replicas": 5, "force": true,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("updates scale subresource", func() {
+			s.Equal(int32(5), s.replicas)
+		})
+		s.Run("warns about the HorizontalPodAutoscaler", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "the autoscaler may override the requested replicas")
+		})
+	})
+}
+
+func (s *WorkloadsSuite) TestResourcesScaleWithHorizontalPodAutoscaler() {
+	s.hpas = []autoscalingv2.HorizontalPodAutoscaler{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			MinReplicas:    ptr.To(int32(1)),
+			MaxReplicas:    10,
+		},
+	}}
+	s.InitMcpClient()
+	s.Run("resources_scale(kind=Deployment, name=web)", func() {
+		toolResult, err := s.CallTool("resources_scale", map[string]interface{}{
+			"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "default", "name": "web",
+		})
+		s.Run("returns the current scale", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "replicas: 1")
+		})
+	})
+	s.Run("resources_scale(kind=Deployment, name=web, scale=5)", func() {
+		toolResult, err := s.CallTool("resources_scale", map[string]interface{}{
+			"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "default", "name": "web", "scale": 5,
+		})
+		s.Run("refuses to scale", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "refusing to scale: Deployment web is managed by HorizontalPodAutoscaler web-hpa")
+		})
+		s.Run("does not update scale subresource", func() {
+			s.Equal(int32(1), s.replicas)
+		})
+	})
+	s.Run("resources_scale(kind=Deployment, name=web, scale=5, force=true)", func() {
+		toolResult, err := s.CallTool("resources_scale", map[string]interface{}{
+			"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "default", "name": "web", "scale": 5, "force": true,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		s.Run("updates scale subresource", func() {
+			s.Equal(int32(5), s.replicas)
+		})
+		s.Run("warns about the HorizontalPodAutoscaler", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+				"# Warning: Deployment web is managed by HorizontalPodAutoscaler web-hpa (minReplicas=1, maxReplicas=10), the autoscaler may override the requested replicas\n")
+		})
+	})
+}
+
+func (s *WorkloadsSuite) TestWorkloadsStatus() {
+	s.hpas = []autoscalingv2.HorizontalPodAutoscaler{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			MinReplicas:    ptr.To(int32(1)),
+			MaxReplicas:    10,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 1, DesiredReplicas: 2},
+	}}
+	s.InitMcpClient()
+	s.Run("workloads_status(kind=nil)", func() {
+		toolResult, err := s.CallTool("workloads_status", map[string]interface{}{"name": "web"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get workload status, missing argument kind", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("workloads_status(name=nil)", func() {
+		toolResult, err := s.CallTool("workloads_status", map[string]interface{}{"kind": "Deployment"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get workload status, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("workloads_status(kind=ReplicaSet)", func() {
+		toolResult, err := s.CallTool("workloads_status", map[string]interface{}{"kind": "ReplicaSet", "name": "web"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "unsupported workload kind ReplicaSet")
+	})
+	s.Run("workloads_status(kind=Deployment, name=web)", func() {
+		toolResult, err := s.CallTool("workloads_status", map[string]interface{}{
+			"kind": "Deployment", "namespace": "default", "name": "web",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns summary", func() {
+			s.True(strings.HasPrefix(text, "# Degraded: Deployment web: 0/1 replicas ready, 1 updated, 1 available, rollout complete\n"),
+				"unexpected result %v", text)
+		})
+		s.Run("returns health verdict", func() {
+			s.Contains(text, "health: Degraded\n")
+			s.Contains(text, "- 1 pod OOMKilled\n")
+		})
+		s.Run("returns recent ReplicaSets of the Deployment, newest first", func() {
+			s.Regexp(`(?s)replicaSets:\n.*  name: web-5d9f\n.*  revision: "2"\n.*  name: web-7c4b\n.*  revision: "1"\n`, text)
+			s.NotContains(text, "other-1a2b")
+		})
+		s.Run("returns pod failures", func() {
+			s.Contains(text, "podFailures:\n  OOMKilled: 1\n")
+		})
+		s.Run("returns replicas", func() {
+			s.Contains(text, "  desired: 1\n")
+			s.Contains(text, "  available: 1\n")
+		})
+		s.Run("returns HorizontalPodAutoscaler", func() {
+			s.Contains(text, "  name: web-hpa\n")
+			s.Contains(text, "  desiredReplicas: 2\n")
+		})
+		s.Run("returns explained container terminations", func() {
+			s.Contains(text, "- container: web\n")
+			s.Contains(text, "  signal: SIGKILL\n")
+			s.Contains(text, "  reason: OOMKilled\n")
+			s.Contains(text, "OOM killer")
+		})
+		s.Run("returns events of the workload Pods", func() {
+			s.Contains(text, "object: Pod/web-5d9f-abcde\n")
+			s.Contains(text, "reason: BackOff\n")
+			s.NotContains(text, "Pod/other")
+		})
+	})
+}
+
+func TestWorkloads(t *testing.T) {
+	suite.Run(t, new(WorkloadsSuite))
+}
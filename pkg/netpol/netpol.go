@@ -0,0 +1,303 @@
+// Package netpol evaluates the NetworkPolicies (networking.k8s.io/v1) of a cluster to decide whether a connection
+// between two endpoints is allowed, which policies isolate them, and which rules allow the connection.
+//
+// The policies are evaluated with the semantics of the NetworkPolicy API, enforcing them is up to the network plugin:
+// the decisions don't apply if it doesn't support NetworkPolicies, and the policies of other APIs (e.g.
+// AdminNetworkPolicy, Calico or Cilium policies) are not evaluated.
+package netpol
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Endpoint is the source or destination of a connection: a Pod, or an IP address outside of the cluster
+type Endpoint struct {
+	// Namespace of the Pod, empty for the IP addresses outside of the cluster
+	Namespace string
+	// NamespaceLabels are matched by the namespaceSelector of the peers
+	NamespaceLabels map[string]string
+	// Labels of the Pod, matched by the podSelector of the policies and peers
+	Labels map[string]string
+	// IP address of the endpoint, matched by the ipBlock of the peers (Optional for Pods)
+	IP string
+	// Ports of the containers of the Pod, resolve the named ports of the rules (destination only)
+	Ports []v1.ContainerPort
+}
+
+// IsPod returns true if the endpoint is a Pod of the cluster
+func (e Endpoint) IsPod() bool {
+	return e.Namespace != ""
+}
+
+// Connection is a connection from a source to a destination port
+type Connection struct {
+	Source      Endpoint
+	Destination Endpoint
+	Port        int32
+	// Protocol of the connection (Optional, defaults to TCP)
+	Protocol v1.Protocol
+}
+
+// Verdict is the decision of the NetworkPolicies for a connection, allowed if both the egress of the source
+// and the ingress of the destination allow it
+type Verdict struct {
+	Allowed bool `json:"allowed"`
+	// Egress is the decision for the source, nil if the source is outside of the cluster
+	Egress *DirectionVerdict `json:"egress,omitempty"`
+	// Ingress is the decision for the destination, nil if the destination is outside of the cluster
+	Ingress *DirectionVerdict `json:"ingress,omitempty"`
+}
+
+// DirectionVerdict is the decision of the policies selecting a Pod for ingress or egress
+type DirectionVerdict struct {
+	Allowed bool `json:"allowed"`
+	// Isolated is true if policies select the Pod for the direction, only the connections allowed by their rules are
+	Isolated bool `json:"isolated"`
+	// Policies selecting the Pod for the direction (namespace/name)
+	Policies []string `json:"policies,omitempty"`
+	// Matches are the rules allowing the connection
+	Matches []RuleMatch `json:"matches,omitempty"`
+	// PortMismatches are the rules allowing the peer on other ports or protocols
+	PortMismatches []RuleMatch `json:"portMismatches,omitempty"`
+	Reason         string      `json:"reason"`
+}
+
+// RuleMatch is an ingress or egress rule of a policy
+type RuleMatch struct {
+	// Policy is the namespace/name of the NetworkPolicy
+	Policy string `json:"policy"`
+	// Rule is the index of the rule in the ingress or egress rules of the policy
+	Rule int `json:"rule"`
+}
+
+// NamespaceIsolation reports the policies of a namespace selecting all of its Pods (empty podSelector)
+type NamespaceIsolation struct {
+	Namespace string `json:"namespace"`
+	// IngressIsolated is true if all the Pods only accept the ingress connections allowed by the policies
+	IngressIsolated bool `json:"ingressIsolated"`
+	// EgressIsolated is true if all the Pods only open the egress connections allowed by the policies
+	EgressIsolated bool `json:"egressIsolated"`
+	// DefaultDenyIngress is true if a policy selecting all the Pods has no ingress rules (default deny)
+	DefaultDenyIngress bool `json:"defaultDenyIngress"`
+	// DefaultDenyEgress is true if a policy selecting all the Pods has no egress rules (default deny)
+	DefaultDenyEgress bool `json:"defaultDenyEgress"`
+	// IsolatingPolicies are the names of the policies selecting all the Pods
+	IsolatingPolicies []string `json:"isolatingPolicies,omitempty"`
+	// Policies is the number of NetworkPolicies of the namespace
+	Policies int `json:"policies"`
+}
+
+// Evaluator evaluates the connections against the NetworkPolicies of a cluster
+type Evaluator struct {
+	policies map[string][]networkingv1.NetworkPolicy
+}
+
+func NewEvaluator(policies []networkingv1.NetworkPolicy) *Evaluator {
+	e := &Evaluator{policies: make(map[string][]networkingv1.NetworkPolicy)}
+	for _, policy := range policies {
+		e.policies[policy.Namespace] = append(e.policies[policy.Namespace], policy)
+	}
+	for _, namespacePolicies := range e.policies {
+		slices.SortFunc(namespacePolicies, func(a, b networkingv1.NetworkPolicy) int { return strings.Compare(a.Name, b.Name) })
+	}
+	return e
+}
+
+// Evaluate returns the decision of the egress policies of the source and the ingress policies of the destination
+func (e *Evaluator) Evaluate(connection Connection) Verdict {
+	if connection.Protocol == "" {
+		connection.Protocol = v1.ProtocolTCP
+	}
+	verdict := Verdict{Allowed: true}
+	if connection.Source.IsPod() {
+		verdict.Egress = e.evaluate(networkingv1.PolicyTypeEgress, connection.Source, connection.Destination, connection)
+		verdict.Allowed = verdict.Egress.Allowed
+	}
+	if connection.Destination.IsPod() {
+		verdict.Ingress = e.evaluate(networkingv1.PolicyTypeIngress, connection.Destination, connection.Source, connection)
+		verdict.Allowed = verdict.Allowed && verdict.Ingress.Allowed
+	}
+	return verdict
+}
+
+// evaluate returns the decision of the policies selecting the pod for the direction, for a connection with the peer
+func (e *Evaluator) evaluate(direction networkingv1.PolicyType, pod, peer Endpoint, connection Connection) *DirectionVerdict {
+	verdict := &DirectionVerdict{}
+	for _, policy := range e.policies[pod.Namespace] {
+		if !slices.Contains(PolicyTypes(policy), direction) || !selects(&policy.Spec.PodSelector, pod.Labels) {
+			continue
+		}
+		name := policy.Namespace + "/" + policy.Name
+		verdict.Policies = append(verdict.Policies, name)
+		for i, rule := range rules(policy, direction) {
+			// rules without peers match all the peers, and rules without ports all the ports
+			if len(rule.peers) > 0 &&
+				!slices.ContainsFunc(rule.peers, func(p networkingv1.NetworkPolicyPeer) bool { return peerMatches(p, policy.Namespace, peer) }) {
+				continue
+			}
+			if len(rule.ports) == 0 || slices.ContainsFunc(rule.ports, func(p networkingv1.NetworkPolicyPort) bool { return portMatches(p, connection) }) {
+				verdict.Matches = append(verdict.Matches, RuleMatch{Policy: name, Rule: i})
+			} else {
+				verdict.PortMismatches = append(verdict.PortMismatches, RuleMatch{Policy: name, Rule: i})
+			}
+		}
+	}
+	verdict.Isolated = len(verdict.Policies) > 0
+	verdict.Allowed = !verdict.Isolated || len(verdict.Matches) > 0
+	switch {
+	case !verdict.Isolated:
+		verdict.Reason = fmt.Sprintf("no NetworkPolicy selects the Pod for %s, all %s connections are allowed", directionName(direction), directionName(direction))
+	case verdict.Allowed:
+		verdict.Reason = fmt.Sprintf("allowed by %s", formatMatches(verdict.Matches))
+	case len(verdict.PortMismatches) > 0:
+		verdict.Reason = fmt.Sprintf("the Pod is isolated for %s by %s, %s allow the peer but not port %d/%s",
+			directionName(direction), strings.Join(verdict.Policies, ", "), formatMatches(verdict.PortMismatches), connection.Port, connection.Protocol)
+	default:
+		verdict.Reason = fmt.Sprintf("the Pod is isolated for %s by %s, none of their rules allow the peer",
+			directionName(direction), strings.Join(verdict.Policies, ", "))
+	}
+	return verdict
+}
+
+// Isolation returns the isolation of the provided namespaces by the policies selecting all of their Pods,
+// sorted by namespace
+func (e *Evaluator) Isolation(namespaces []string) []NamespaceIsolation {
+	namespaces = slices.Sorted(slices.Values(namespaces))
+	isolations := make([]NamespaceIsolation, 0, len(namespaces))
+	for _, namespace := range slices.Compact(namespaces) {
+		isolation := NamespaceIsolation{Namespace: namespace, Policies: len(e.policies[namespace])}
+		for _, policy := range e.policies[namespace] {
+			if len(policy.Spec.PodSelector.MatchLabels) > 0 || len(policy.Spec.PodSelector.MatchExpressions) > 0 {
+				continue
+			}
+			isolation.IsolatingPolicies = append(isolation.IsolatingPolicies, policy.Name)
+			for _, policyType := range PolicyTypes(policy) {
+				switch policyType {
+				case networkingv1.PolicyTypeIngress:
+					isolation.IngressIsolated = true
+					isolation.DefaultDenyIngress = isolation.DefaultDenyIngress || len(policy.Spec.Ingress) == 0
+				case networkingv1.PolicyTypeEgress:
+					isolation.EgressIsolated = true
+					isolation.DefaultDenyEgress = isolation.DefaultDenyEgress || len(policy.Spec.Egress) == 0
+				}
+			}
+		}
+		isolations = append(isolations, isolation)
+	}
+	return isolations
+}
+
+// PolicyTypes returns the policy types of the policy, defaulting to Ingress, and Egress if the policy has egress rules
+func PolicyTypes(policy networkingv1.NetworkPolicy) []networkingv1.PolicyType {
+	if len(policy.Spec.PolicyTypes) > 0 {
+		return policy.Spec.PolicyTypes
+	}
+	if len(policy.Spec.Egress) > 0 {
+		return []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+	}
+	return []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+}
+
+// rule is an ingress (from) or egress (to) rule of a policy
+type rule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+func rules(policy networkingv1.NetworkPolicy, direction networkingv1.PolicyType) []rule {
+	var ret []rule
+	if direction == networkingv1.PolicyTypeIngress {
+		for _, ingress := range policy.Spec.Ingress {
+			ret = append(ret, rule{peers: ingress.From, ports: ingress.Ports})
+		}
+	} else {
+		for _, egress := range policy.Spec.Egress {
+			ret = append(ret, rule{peers: egress.To, ports: egress.Ports})
+		}
+	}
+	return ret
+}
+
+// peerMatches returns true if the peer of a policy of the namespace matches the endpoint:
+// the podSelector alone selects the Pods of the namespace of the policy, the namespaceSelector alone all the Pods of
+// the selected namespaces, and both the selected Pods of the selected namespaces
+func peerMatches(peer networkingv1.NetworkPolicyPeer, namespace string, endpoint Endpoint) bool {
+	if peer.IPBlock != nil {
+		return ipBlockMatches(peer.IPBlock, endpoint.IP)
+	}
+	if !endpoint.IsPod() {
+		return false
+	}
+	if peer.NamespaceSelector == nil {
+		return endpoint.Namespace == namespace && selects(peer.PodSelector, endpoint.Labels)
+	}
+	return selects(peer.NamespaceSelector, endpoint.NamespaceLabels) && (peer.PodSelector == nil || selects(peer.PodSelector, endpoint.Labels))
+}
+
+func ipBlockMatches(ipBlock *networkingv1.IPBlock, address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	if _, cidr, err := net.ParseCIDR(ipBlock.CIDR); err != nil || !cidr.Contains(ip) {
+		return false
+	}
+	return !slices.ContainsFunc(ipBlock.Except, func(except string) bool {
+		_, cidr, err := net.ParseCIDR(except)
+		return err == nil && cidr.Contains(ip)
+	})
+}
+
+// portMatches returns true if the port of a rule matches the connection, named ports are resolved with the
+// container ports of the destination
+func portMatches(port networkingv1.NetworkPolicyPort, connection Connection) bool {
+	protocol := v1.ProtocolTCP
+	if port.Protocol != nil {
+		protocol = *port.Protocol
+	}
+	if protocol != connection.Protocol {
+		return false
+	}
+	if port.Port == nil {
+		return true
+	}
+	if port.Port.IntVal == 0 && port.Port.StrVal != "" {
+		return slices.ContainsFunc(connection.Destination.Ports, func(containerPort v1.ContainerPort) bool {
+			containerProtocol := containerPort.Protocol
+			if containerProtocol == "" {
+				containerProtocol = v1.ProtocolTCP
+			}
+			return containerPort.Name == port.Port.StrVal && containerProtocol == protocol && containerPort.ContainerPort == connection.Port
+		})
+	}
+	if port.EndPort != nil {
+		return port.Port.IntVal <= connection.Port && connection.Port <= *port.EndPort
+	}
+	return port.Port.IntVal == connection.Port
+}
+
+// selects returns true if the label selector selects the labels, invalid selectors select nothing
+func selects(selector *metav1.LabelSelector, set map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	return err == nil && s.Matches(labels.Set(set))
+}
+
+func directionName(direction networkingv1.PolicyType) string {
+	return strings.ToLower(string(direction))
+}
+
+func formatMatches(matches []RuleMatch) string {
+	formatted := make([]string, 0, len(matches))
+	for _, match := range matches {
+		formatted = append(formatted, fmt.Sprintf("rule %d of %s", match.Rule, match.Policy))
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,135 @@
+package netpol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+type NetpolSuite struct {
+	suite.Suite
+	evaluator *Evaluator
+	frontend  Endpoint
+	backend   Endpoint
+	database  Endpoint
+	monitor   Endpoint
+}
+
+func (s *NetpolSuite) SetupTest() {
+	s.evaluator = NewEvaluator([]networkingv1.NetworkPolicy{{
+		// default deny ingress of the shop namespace
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "default-deny"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "backend-from-frontend"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "backend"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}}},
+				Ports: []networkingv1.NetworkPolicyPort{{Port: ptr.To(intstr.FromString("http"))}},
+			}, {
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{{Port: ptr.To(intstr.FromInt32(9000)), EndPort: ptr.To(int32(9100))}},
+			}},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "database-egress"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "database"}},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}}},
+			}},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "allow-all-egress"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      []networkingv1.NetworkPolicyEgressRule{{}},
+		},
+	}})
+	shop := map[string]string{"kubernetes.io/metadata.name": "shop"}
+	s.frontend = Endpoint{Namespace: "shop", NamespaceLabels: shop, Labels: map[string]string{"app": "frontend"}, IP: "10.244.0.10"}
+	s.backend = Endpoint{Namespace: "shop", NamespaceLabels: shop, Labels: map[string]string{"app": "backend"}, IP: "10.244.0.11",
+		Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}, {Name: "metrics", ContainerPort: 9090}}}
+	s.database = Endpoint{Namespace: "shop", NamespaceLabels: shop, Labels: map[string]string{"app": "database"}, IP: "10.244.0.12"}
+	s.monitor = Endpoint{Namespace: "monitoring", NamespaceLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"},
+		Labels: map[string]string{"app": "prometheus"}, IP: "10.244.1.10"}
+}
+
+func (s *NetpolSuite) TestEvaluate() {
+	s.Run("allowed by a pod selector peer with a named port", func() {
+		verdict := s.evaluator.Evaluate(Connection{Source: s.frontend, Destination: s.backend, Port: 8080})
+		s.True(verdict.Allowed)
+		s.False(verdict.Egress.Isolated)
+		s.Equal([]string{"shop/backend-from-frontend", "shop/default-deny"}, verdict.Ingress.Policies)
+		s.Equal([]RuleMatch{{Policy: "shop/backend-from-frontend", Rule: 0}}, verdict.Ingress.Matches)
+		s.Equal("allowed by rule 0 of shop/backend-from-frontend", verdict.Ingress.Reason)
+	})
+	s.Run("denied on other ports", func() {
+		verdict := s.evaluator.Evaluate(Connection{Source: s.frontend, Destination: s.backend, Port: 9090})
+		s.False(verdict.Allowed)
+		s.Equal([]RuleMatch{{Policy: "shop/backend-from-frontend", Rule: 0}}, verdict.Ingress.PortMismatches)
+		s.Equal("the Pod is isolated for ingress by shop/backend-from-frontend, shop/default-deny, rule 0 of shop/backend-from-frontend allow the peer but not port 9090/TCP",
+			verdict.Ingress.Reason)
+	})
+	s.Run("denied on other protocols", func() {
+		s.False(s.evaluator.Evaluate(Connection{Source: s.frontend, Destination: s.backend, Port: 8080, Protocol: v1.ProtocolUDP}).Allowed)
+	})
+	s.Run("allowed by a namespace and pod selector peer within a port range", func() {
+		verdict := s.evaluator.Evaluate(Connection{Source: s.monitor, Destination: s.backend, Port: 9090})
+		s.True(verdict.Allowed)
+		s.Equal([]RuleMatch{{Policy: "monitoring/allow-all-egress", Rule: 0}}, verdict.Egress.Matches)
+		s.Equal([]RuleMatch{{Policy: "shop/backend-from-frontend", Rule: 1}}, verdict.Ingress.Matches)
+	})
+	s.Run("namespace selector doesn't match the pods of other namespaces", func() {
+		monitor := s.frontend
+		monitor.Labels = map[string]string{"app": "prometheus"}
+		s.False(s.evaluator.Evaluate(Connection{Source: monitor, Destination: s.backend, Port: 9090}).Allowed)
+	})
+	s.Run("default deny", func() {
+		verdict := s.evaluator.Evaluate(Connection{Source: s.backend, Destination: s.frontend, Port: 8080})
+		s.False(verdict.Allowed)
+		s.Equal([]string{"shop/default-deny"}, verdict.Ingress.Policies)
+		s.Equal("the Pod is isolated for ingress by shop/default-deny, none of their rules allow the peer", verdict.Ingress.Reason)
+	})
+	s.Run("egress to ip blocks", func() {
+		external := Endpoint{IP: "10.2.3.4"}
+		verdict := s.evaluator.Evaluate(Connection{Source: s.database, Destination: external, Port: 443})
+		s.True(verdict.Allowed)
+		s.Nil(verdict.Ingress, "the destination is outside of the cluster")
+		s.True(verdict.Egress.Isolated, "policy types default to Ingress and Egress with egress rules")
+		s.False(s.evaluator.Evaluate(Connection{Source: s.database, Destination: Endpoint{IP: "10.1.0.1"}, Port: 443}).Allowed, "excepted")
+		s.False(s.evaluator.Evaluate(Connection{Source: s.database, Destination: Endpoint{IP: "192.168.0.1"}, Port: 443}).Allowed)
+	})
+	s.Run("external source", func() {
+		verdict := s.evaluator.Evaluate(Connection{Source: Endpoint{IP: "192.168.0.1"}, Destination: s.monitor, Port: 9090})
+		s.True(verdict.Allowed)
+		s.Nil(verdict.Egress)
+		s.Equal("no NetworkPolicy selects the Pod for ingress, all ingress connections are allowed", verdict.Ingress.Reason)
+	})
+}
+
+func (s *NetpolSuite) TestIsolation() {
+	isolations := s.evaluator.Isolation([]string{"shop", "monitoring", "default"})
+	s.Equal([]NamespaceIsolation{
+		{Namespace: "default"},
+		{Namespace: "monitoring", EgressIsolated: true, IsolatingPolicies: []string{"allow-all-egress"}, Policies: 1},
+		{Namespace: "shop", IngressIsolated: true, DefaultDenyIngress: true, IsolatingPolicies: []string{"default-deny"}, Policies: 3},
+	}, isolations)
+}
+
+func (s *NetpolSuite) TestPolicyTypes() {
+	s.Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, PolicyTypes(networkingv1.NetworkPolicy{}))
+}
+
+func TestNetpol(t *testing.T) {
+	suite.Run(t, new(NetpolSuite))
+}
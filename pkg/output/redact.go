@@ -0,0 +1,86 @@
+package output
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Redacted replaces the sensitive values removed from the tool outputs
+const Redacted = "<redacted>"
+
+// lastAppliedConfigurationAnnotation contains the full object applied with kubectl (including the Secret data)
+const lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// sensitiveName matches the names of environment variables and keys that usually hold credentials
+const sensitiveName = `[A-Za-z0-9_.-]*(?i:password|passwd|secret|token|api_?key|access_?key|private_?key|credentials?)[A-Za-z0-9_.-]*`
+
+// redactPatterns match the common credential formats, the first capture group (if any) is preserved
+var redactPatterns = []*regexp.Regexp{
+	// PEM encoded private keys
+	regexp.MustCompile(`(-----BEGIN [A-Z ]*PRIVATE KEY-----)[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	// JSON Web Tokens (e.g. ServiceAccount tokens)
+	regexp.MustCompile(`()\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`),
+	// Bearer tokens in headers or command lines
+	regexp.MustCompile(`((?i:bearer)\s+)[A-Za-z0-9._~+/=-]{16,}`),
+	// AWS access key IDs
+	regexp.MustCompile(`()\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	// GitHub tokens
+	regexp.MustCompile(`()\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	// Slack tokens
+	regexp.MustCompile(`()\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+	// Environment variables with sensitive names rendered as YAML (e.g. Pod env)
+	regexp.MustCompile(`(?m)(^\s*-?\s*name:\s*['"]?` + sensitiveName + `['"]?\s*\n\s*value:\s*).+$`),
+	// Environment variables with sensitive names rendered as NAME=value (e.g. env command output)
+	regexp.MustCompile(`(?m)(^\s*(?:export\s+)?` + sensitiveName + `=).+$`),
+}
+
+// Redact removes the credentials matching the common token patterns and the values of the environment variables
+// with sensitive names from the provided text
+func Redact(text string) string {
+	for _, pattern := range redactPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+Redacted)
+	}
+	return text
+}
+
+// RedactSecrets masks the values of the Secrets in the provided object or list, the keys are preserved
+func RedactSecrets(obj runtime.Unstructured) {
+	if list, ok := obj.(*unstructured.UnstructuredList); ok {
+		for i := range list.Items {
+			RedactSecrets(&list.Items[i])
+		}
+		return
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || u.GetKind() != "Secret" {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, found, _ := unstructured.NestedMap(u.Object, field)
+		if !found {
+			continue
+		}
+		for key, value := range values {
+			s, _ := value.(string)
+			size := len(s)
+			if decoded, err := base64.StdEncoding.DecodeString(s); err == nil && field == "data" {
+				size = len(decoded)
+			}
+			values[key] = RedactedValue(size)
+		}
+		_ = unstructured.SetNestedMap(u.Object, values, field)
+	}
+	if annotations := u.GetAnnotations(); annotations[lastAppliedConfigurationAnnotation] != "" {
+		annotations[lastAppliedConfigurationAnnotation] = Redacted
+		u.SetAnnotations(annotations)
+	}
+}
+
+// RedactedValue returns the placeholder of a masked value, including its size to help troubleshooting (e.g. empty values)
+func RedactedValue(size int) string {
+	return fmt.Sprintf("<redacted, %d bytes>", size)
+}
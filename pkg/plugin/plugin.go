@@ -0,0 +1,256 @@
+// Package plugin loads the out-of-tree toolsets shipped as executables (plugins) in the configured plugins_dir.
+//
+// A plugin is an executable implementing two commands:
+//   - "<plugin> describe" writes the Manifest of the toolset (JSON) to the standard output.
+//   - "<plugin> call" reads a Request (JSON) from the standard input and writes a Response (JSON) to the standard output.
+//
+// The plugins run with the environment of the server (KUBECONFIG is set to the configured kubeconfig), they use the
+// credentials of the server and are not subject to the denied_resources nor to the per-connection credentials.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+const (
+	CommandDescribe = "describe"
+	CommandCall     = "call"
+)
+
+// DescribeTimeout is the maximum duration of the describe command of a plugin
+var DescribeTimeout = 10 * time.Second
+
+// Manifest describes the toolset provided by a plugin
+type Manifest struct {
+	// Name of the toolset, used to enable it in the toolsets configuration
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Tools       []Tool `json:"tools"`
+}
+
+// Tool describes a tool of a plugin toolset
+type Tool struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Annotations api.ToolAnnotations `json:"annotations"`
+	// InputSchema is the JSON schema of the arguments, an object without properties if not provided
+	InputSchema *jsonschema.Schema `json:"inputSchema,omitempty"`
+}
+
+// Request is the tool call sent to the call command of a plugin
+type Request struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// Response is the result of the call command of a plugin
+type Response struct {
+	// Content is the output returned to the agent
+	Content string `json:"content"`
+	// Error, when set, is returned to the agent as a tool error
+	Error string `json:"error,omitempty"`
+}
+
+// Toolset is a toolset provided by a plugin
+type Toolset struct {
+	path         string
+	manifest     *Manifest
+	staticConfig *config.StaticConfig
+}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return t.manifest.Name
+}
+
+func (t *Toolset) GetDescription() string {
+	return t.manifest.Description
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	tools := make([]api.ServerTool, 0, len(t.manifest.Tools))
+	for _, tool := range t.manifest.Tools {
+		inputSchema := tool.InputSchema
+		if inputSchema == nil {
+			inputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		tools = append(tools, api.ServerTool{Tool: api.Tool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Annotations: tool.Annotations,
+			InputSchema: inputSchema,
+		}, Handler: t.handler(tool.Name), ClusterAware: ptr.To(false)})
+	}
+	return tools
+}
+
+func (t *Toolset) handler(tool string) api.ToolHandlerFunc {
+	return func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+		response, err := t.call(params, &Request{Tool: tool, Arguments: params.GetArguments()})
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to call plugin %s: %v", t.manifest.Name, err)), nil
+		}
+		if response.Error != "" {
+			return api.NewToolCallResult("", errors.New(response.Error)), nil
+		}
+		return api.NewToolCallResult(response.Content, nil), nil
+	}
+}
+
+func (t *Toolset) call(ctx context.Context, request *Request) (*Response, error) {
+	if request.Arguments == nil {
+		request.Arguments = map[string]any{}
+	}
+	stdin, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := t.run(ctx, CommandCall, stdin)
+	if err != nil {
+		return nil, err
+	}
+	response := &Response{}
+	if err = json.Unmarshal(stdout, response); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return response, nil
+}
+
+func (t *Toolset) describe(ctx context.Context) (*Manifest, error) {
+	ctx, cancel := context.WithTimeout(ctx, DescribeTimeout)
+	defer cancel()
+	stdout, err := t.run(ctx, CommandDescribe, nil)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err = json.Unmarshal(stdout, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if strings.TrimSpace(manifest.Name) == "" {
+		return nil, errors.New("invalid manifest: name is required")
+	}
+	for i, tool := range manifest.Tools {
+		if strings.TrimSpace(tool.Name) == "" {
+			return nil, fmt.Errorf("invalid manifest: name of tool %d is required", i)
+		}
+	}
+	return manifest, nil
+}
+
+func (t *Toolset) run(ctx context.Context, command string, stdin []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, t.path, command)
+	cmd.Env = os.Environ()
+	if t.staticConfig != nil && t.staticConfig.KubeConfig != "" {
+		cmd.Env = append(cmd.Env, "KUBECONFIG="+t.staticConfig.KubeConfig)
+	}
+	cmd.Stdin = bytes.NewReader(stdin)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// Load describes the plugins of the directory, in lexical order.
+// The hidden files, directories and files that are not executable are ignored.
+func Load(ctx context.Context, dir string, staticConfig *config.StaticConfig) ([]*Toolset, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory %s: %w", dir, err)
+	}
+	var loaded []*Toolset
+	names := map[string]string{}
+	tools := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0111 == 0 {
+			continue
+		}
+		toolset := &Toolset{path: filepath.Join(dir, entry.Name()), staticConfig: staticConfig}
+		if toolset.manifest, err = toolset.describe(ctx); err != nil {
+			return nil, fmt.Errorf("failed to load plugin %s: %w", toolset.path, err)
+		}
+		if other, ok := names[toolset.manifest.Name]; ok {
+			return nil, fmt.Errorf("failed to load plugin %s: toolset %s is already provided by %s", toolset.path, toolset.manifest.Name, other)
+		}
+		names[toolset.manifest.Name] = toolset.path
+		for _, tool := range toolset.manifest.Tools {
+			if other, ok := tools[tool.Name]; ok {
+				return nil, fmt.Errorf("failed to load plugin %s: tool %s is already provided by %s", toolset.path, tool.Name, other)
+			}
+			tools[tool.Name] = toolset.path
+		}
+		loaded = append(loaded, toolset)
+	}
+	return loaded, nil
+}
+
+// Register loads the plugins of the directory and registers their toolsets in the registry.
+// The toolsets can't replace the toolsets already registered, nor their tools (the tools are identified by name), nor
+// the tools added by the server itself (api.ServerToolNames).
+func Register(ctx context.Context, registry *toolsets.ToolsetRegistry, dir string, staticConfig *config.StaticConfig) error {
+	loaded, err := Load(ctx, dir, staticConfig)
+	if err != nil {
+		return err
+	}
+	registered := map[string]string{}
+	for _, toolset := range registry.Toolsets() {
+		for _, tool := range toolset.GetTools(anyCluster{}) {
+			registered[tool.Tool.Name] = toolset.GetName()
+		}
+	}
+	for _, toolset := range loaded {
+		if registry.ToolsetFromString(toolset.GetName()) != nil {
+			return fmt.Errorf("failed to register plugin %s: toolset %s is already registered", toolset.path, toolset.GetName())
+		}
+		for _, tool := range toolset.manifest.Tools {
+			if slices.Contains(api.ServerToolNames, tool.Name) {
+				return fmt.Errorf("failed to register plugin %s: tool %s is reserved by the server", toolset.path, tool.Name)
+			}
+			if other, ok := registered[tool.Name]; ok {
+				return fmt.Errorf("failed to register plugin %s: tool %s is already registered by toolset %s", toolset.path, tool.Name, other)
+			}
+		}
+	}
+	for _, toolset := range loaded {
+		klog.V(1).Infof("Registering toolset %s (%d tools) of plugin %s", toolset.GetName(), len(toolset.manifest.Tools), toolset.path)
+		registry.Register(toolset)
+	}
+	return nil
+}
+
+// anyCluster lists the tools of the registered toolsets as if the cluster was OpenShift, so that the names of the
+// tools only available in OpenShift can't be taken by the plugins either
+type anyCluster struct{}
+
+func (anyCluster) IsOpenShift(context.Context) bool {
+	return true
+}
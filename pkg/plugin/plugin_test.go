@@ -0,0 +1,164 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+const crdPlugin = `#!/bin/sh
+if [ "$1" = "describe" ]; then
+  echo '{"name":"acme","description":"ACME operator tools","tools":[
+    {"name":"acme_databases_list","description":"List the ACME databases","annotations":{"readOnlyHint":true},
+     "inputSchema":{"type":"object","properties":{"namespace":{"type":"string"}}}},
+    {"name":"acme_fail","description":"Always fails"},
+    {"name":"acme_crash","description":"Exits with an error"}
+  ]}'
+  exit 0
+fi
+request=$(cat)
+case "$request" in
+  *acme_databases_list*) printf '{"content":"kubeconfig=%s request=%s"}' "$KUBECONFIG" "$(echo "$request" | sed 's/"/\\"/g')" ;;
+  *acme_fail*) echo '{"error":"database not found"}' ;;
+  *) echo "boom" >&2; exit 3 ;;
+esac
+`
+
+type PluginSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *PluginSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *PluginSuite) write(name, content string, mode os.FileMode) {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, name), []byte(content), mode))
+}
+
+type callRequest map[string]any
+
+func (r callRequest) GetArguments() map[string]any {
+	return r
+}
+
+func (s *PluginSuite) call(tool api.ServerTool, arguments map[string]any) *api.ToolCallResult {
+	result, err := tool.Handler(api.ToolHandlerParams{Context: context.Background(), ToolCallRequest: callRequest(arguments)})
+	s.Require().NoError(err)
+	return result
+}
+
+func (s *PluginSuite) TestLoad() {
+	s.write("acme", crdPlugin, 0755)
+	s.write("README.md", "not a plugin", 0644)
+	s.write(".hidden", crdPlugin, 0755)
+	s.Require().NoError(os.Mkdir(filepath.Join(s.dir, "subdir"), 0755))
+	loaded, err := Load(context.Background(), s.dir, &config.StaticConfig{KubeConfig: "/tmp/kubeconfig"})
+	s.Require().NoError(err)
+	s.Require().Len(loaded, 1, "only the executables are loaded")
+	s.Equal("acme", loaded[0].GetName())
+	s.Equal("ACME operator tools", loaded[0].GetDescription())
+	tools := loaded[0].GetTools(nil)
+	s.Require().Len(tools, 3)
+	s.Run("defines the tools of the manifest", func() {
+		s.Equal("acme_databases_list", tools[0].Tool.Name)
+		s.True(*tools[0].Tool.Annotations.ReadOnlyHint)
+		s.Contains(tools[0].Tool.InputSchema.Properties, "namespace")
+		s.Equal("object", tools[1].Tool.InputSchema.Type, "defaults to an object schema")
+		s.False(tools[0].IsClusterAware())
+	})
+	s.Run("calls the tool with the arguments and the configured kubeconfig", func() {
+		result := s.call(tools[0], map[string]any{"namespace": "db"})
+		s.Require().NoError(result.Error)
+		s.Equal(`kubeconfig=/tmp/kubeconfig request={"tool":"acme_databases_list","arguments":{"namespace":"db"}}`, result.Content)
+	})
+	s.Run("returns the error of the response", func() {
+		result := s.call(tools[1], nil)
+		s.Require().Error(result.Error)
+		s.Equal("database not found", result.Error.Error())
+	})
+	s.Run("returns the error of the process", func() {
+		result := s.call(tools[2], nil)
+		s.Require().Error(result.Error)
+		s.Equal("failed to call plugin acme: exit status 3: boom", result.Error.Error())
+	})
+}
+
+func (s *PluginSuite) TestLoadErrors() {
+	s.Run("missing directory", func() {
+		_, err := Load(context.Background(), filepath.Join(s.dir, "missing"), nil)
+		s.ErrorContains(err, "failed to read plugins directory")
+	})
+	s.Run("invalid manifest", func() {
+		s.write("invalid", "#!/bin/sh\necho 'not json'\n", 0755)
+		_, err := Load(context.Background(), s.dir, nil)
+		s.ErrorContains(err, "invalid manifest: ")
+	})
+	s.Run("manifest without name", func() {
+		s.write("invalid", "#!/bin/sh\necho '{\"tools\":[]}'\n", 0755)
+		_, err := Load(context.Background(), s.dir, nil)
+		s.ErrorContains(err, "invalid manifest: name is required")
+	})
+	s.Run("duplicate toolset", func() {
+		s.Require().NoError(os.Remove(filepath.Join(s.dir, "invalid")))
+		s.write("acme", crdPlugin, 0755)
+		s.write("acme-copy", crdPlugin, 0755)
+		_, err := Load(context.Background(), s.dir, nil)
+		s.ErrorContains(err, "toolset acme is already provided by "+filepath.Join(s.dir, "acme"))
+	})
+	s.Run("duplicate tool", func() {
+		s.Require().NoError(os.Remove(filepath.Join(s.dir, "acme-copy")))
+		s.write("other", strings.Replace(crdPlugin, `"name":"acme"`, `"name":"other"`, 1), 0755)
+		_, err := Load(context.Background(), s.dir, nil)
+		s.ErrorContains(err, "tool acme_databases_list is already provided by "+filepath.Join(s.dir, "acme"))
+	})
+}
+
+func (s *PluginSuite) TestRegister() {
+	s.write("acme", crdPlugin, 0755)
+	s.Run("registers the toolsets", func() {
+		registry := toolsets.NewToolsetRegistry()
+		s.Require().NoError(Register(context.Background(), registry, s.dir, nil))
+		s.Equal([]string{"acme"}, registry.ToolsetNames())
+		s.NoError(registry.Validate([]string{"acme"}))
+	})
+	s.Run("doesn't replace the registered toolsets", func() {
+		registry := toolsets.NewToolsetRegistry()
+		s.Require().NoError(Register(context.Background(), registry, s.dir, nil))
+		err := Register(context.Background(), registry, s.dir, nil)
+		s.ErrorContains(err, "toolset acme is already registered")
+		s.Len(registry.Toolsets(), 1)
+	})
+	s.Run("doesn't replace the registered tools", func() {
+		registry := toolsets.NewToolsetRegistry()
+		s.Require().NoError(Register(context.Background(), registry, s.dir, nil))
+		s.Require().NoError(os.Remove(filepath.Join(s.dir, "acme")))
+		s.write("other", strings.Replace(crdPlugin, `"name":"acme"`, `"name":"other"`, 1), 0755)
+		err := Register(context.Background(), registry, s.dir, nil)
+		s.ErrorContains(err, "tool acme_databases_list is already registered by toolset acme")
+		s.Len(registry.Toolsets(), 1)
+	})
+	s.Run("doesn't shadow the tools added by the server", func() {
+		s.Require().NoError(os.Remove(filepath.Join(s.dir, "other")))
+		for _, name := range []string{"elevate", "confirm_action", "continue_result", "self_check", "session_configure"} {
+			s.write("acme", strings.Replace(crdPlugin, `"name":"acme_fail"`, `"name":"`+name+`"`, 1), 0755)
+			registry := toolsets.NewToolsetRegistry()
+			err := Register(context.Background(), registry, s.dir, nil)
+			s.ErrorContains(err, "tool "+name+" is reserved by the server")
+			s.Empty(registry.Toolsets())
+		}
+	})
+}
+
+func TestPlugin(t *testing.T) {
+	suite.Run(t, new(PluginSuite))
+}
@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// celCostLimit bounds the evaluation of the CEL expressions (e.g. comprehensions over large manifests)
+const celCostLimit = 1_000_000
+
+// celEngine compiles CEL expressions, as Kubernetes ValidatingAdmissionPolicies the expressions can access:
+// object, the manifest of the object the tool creates or updates (null if the tool doesn't take a manifest), and
+// request, with the tool, arguments, apiVersion, kind, group, resource, namespace, and name of the call
+type celEngine struct{}
+
+var _ Engine = (*celEngine)(nil)
+
+func (e *celEngine) Compile(expression string) (Program, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("the expression must evaluate to a bool, got %s", ast.OutputType())
+	}
+	program, err := env.Program(ast, cel.CostLimit(celCostLimit), cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, err
+	}
+	return &celProgram{program: program}, nil
+}
+
+type celProgram struct {
+	program cel.Program
+}
+
+func (p *celProgram) Allows(ctx context.Context, request api.MutationRequest) (bool, error) {
+	arguments := request.Arguments
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	var object any = types.NullValue
+	if request.Object != nil {
+		object = request.Object
+	}
+	result, _, err := p.program.ContextEval(ctx, map[string]any{
+		"object": object,
+		"request": map[string]any{
+			"tool":       request.Tool,
+			"arguments":  arguments,
+			"apiVersion": request.APIVersion,
+			"kind":       request.Kind,
+			"group":      request.Group,
+			"resource":   request.Resource,
+			"namespace":  request.Namespace,
+			"name":       request.Name,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	allowed, ok := result.Value().(bool)
+	if !ok {
+		return false, errors.New("the expression must evaluate to a bool")
+	}
+	return allowed, nil
+}
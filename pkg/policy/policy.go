@@ -0,0 +1,89 @@
+// Package policy evaluates the policies configured by the operators (policies TOML option) against the mutating tool
+// calls before the tools are invoked.
+//
+// Policies are expressions that must evaluate to true for a call to be allowed, they're written in one of the
+// supported engines (CEL). Programs embedding the server can provide other engines (e.g. Rego) by implementing
+// api.MutationPolicy.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// Engine compiles the expressions of a policy language
+type Engine interface {
+	Compile(expression string) (Program, error)
+}
+
+// Program is a compiled policy expression
+type Program interface {
+	// Allows returns true if the request passes the policy
+	Allows(ctx context.Context, request api.MutationRequest) (bool, error)
+}
+
+// engines are the supported policy engines by name
+var engines = map[string]Engine{
+	config.PolicyEngineCEL: &celEngine{},
+}
+
+// Policies are the compiled policies of the configuration, every applicable policy must allow a request
+type Policies struct {
+	policies []compiledPolicy
+}
+
+var _ api.MutationPolicy = (*Policies)(nil)
+
+type compiledPolicy struct {
+	config.Policy
+	program Program
+}
+
+// New compiles the configured policies, it fails if an expression is not valid
+func New(policies []config.Policy) (*Policies, error) {
+	compiled := make([]compiledPolicy, 0, len(policies))
+	for _, policy := range policies {
+		name := policy.Engine
+		if name == "" {
+			name = config.PolicyEngineCEL
+		}
+		engine, ok := engines[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid policy %s, unsupported engine %q", policy.Name, policy.Engine)
+		}
+		program, err := engine.Compile(policy.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %w", policy.Name, err)
+		}
+		compiled = append(compiled, compiledPolicy{Policy: policy, program: program})
+	}
+	return &Policies{policies: compiled}, nil
+}
+
+// Evaluate returns the reasons of the policies denying the request, e.g. "no-kube-system: kube-system can't be modified".
+// The policies that fail to evaluate (e.g. a field missing from the manifest) are returned as an error, which denies
+// the request too.
+func (p *Policies) Evaluate(ctx context.Context, request api.MutationRequest) ([]string, error) {
+	var reasons []string
+	var errs []error
+	for _, policy := range p.policies {
+		if !policy.AppliesTo(request.Tool) {
+			continue
+		}
+		allowed, err := policy.program.Allows(ctx, request)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", policy.Name, err))
+		} else if !allowed {
+			message := policy.Message
+			if message == "" {
+				message = "the call doesn't satisfy " + policy.Expression
+			}
+			reasons = append(reasons, fmt.Sprintf("%s: %s", policy.Name, message))
+		}
+	}
+	return reasons, errors.Join(errs...)
+}
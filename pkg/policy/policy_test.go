@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type PolicySuite struct {
+	suite.Suite
+}
+
+var hostNetworkPod = api.MutationRequest{
+	Tool:       "resources_create_or_update",
+	Arguments:  map[string]any{"resource": "..."},
+	APIVersion: "v1",
+	Kind:       "Pod",
+	Namespace:  "shop",
+	Name:       "web",
+	Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "web", "namespace": "shop"},
+		"spec":       map[string]any{"hostNetwork": true, "containers": []any{map[string]any{"name": "app", "image": "nginx:latest"}}},
+	},
+}
+
+func (s *PolicySuite) evaluate(policies []config.Policy, request api.MutationRequest) []string {
+	p, err := New(policies)
+	s.Require().NoError(err)
+	reasons, err := p.Evaluate(context.Background(), request)
+	s.Require().NoError(err)
+	return reasons
+}
+
+func (s *PolicySuite) TestNew() {
+	s.Run("compiles the CEL expressions", func() {
+		_, err := New([]config.Policy{{Name: "p", Expression: `request.namespace != "kube-system"`}})
+		s.NoError(err)
+	})
+	s.Run("invalid expression", func() {
+		_, err := New([]config.Policy{{Name: "p", Expression: `request.namespace !=`}})
+		s.ErrorContains(err, "invalid policy p: ERROR: <input>:1:21: Syntax error")
+	})
+	s.Run("undeclared variable", func() {
+		_, err := New([]config.Policy{{Name: "p", Expression: `verb == "delete"`}})
+		s.ErrorContains(err, "undeclared reference to 'verb'")
+	})
+	s.Run("expression not evaluating to a bool", func() {
+		_, err := New([]config.Policy{{Name: "p", Expression: `request.name.size()`}})
+		s.EqualError(err, "invalid policy p: the expression must evaluate to a bool, got int")
+	})
+	s.Run("unsupported engine", func() {
+		_, err := New([]config.Policy{{Name: "p", Engine: "rego", Expression: "allow"}})
+		s.EqualError(err, `invalid policy p, unsupported engine "rego"`)
+	})
+}
+
+func (s *PolicySuite) TestEvaluate() {
+	s.Run("allows the requests satisfying the policies", func() {
+		s.Empty(s.evaluate([]config.Policy{{Name: "no-kube-system", Expression: `request.namespace != "kube-system"`}}, hostNetworkPod))
+	})
+	s.Run("denies the requests violating a policy with its message", func() {
+		s.Equal([]string{"no-host-network: Pods can't use the host network"}, s.evaluate([]config.Policy{{
+			Name:       "no-host-network",
+			Expression: `object == null || !has(object.spec.hostNetwork) || !object.spec.hostNetwork`,
+			Message:    "Pods can't use the host network",
+		}}, hostNetworkPod))
+	})
+	s.Run("denies with the expression when there's no message", func() {
+		s.Equal([]string{`no-latest: the call doesn't satisfy object.spec.containers.all(c, !c.image.endsWith(":latest"))`},
+			s.evaluate([]config.Policy{{Name: "no-latest", Expression: `object.spec.containers.all(c, !c.image.endsWith(":latest"))`}}, hostNetworkPod))
+	})
+	s.Run("reports every violated policy", func() {
+		s.Len(s.evaluate([]config.Policy{
+			{Name: "shop-read-only", Expression: `request.namespace != "shop"`},
+			{Name: "allowed", Expression: `true`},
+			{Name: "no-pods", Expression: `request.kind != "Pod"`},
+		}, hostNetworkPod), 2)
+	})
+	s.Run("skips the policies of other tools", func() {
+		s.Empty(s.evaluate([]config.Policy{{Name: "no-deletes", Expression: `false`, Tools: []string{"*_delete"}}}, hostNetworkPod))
+	})
+	s.Run("exposes the arguments and the permission resource", func() {
+		policies := []config.Policy{{Name: "small-scale", Expression: `request.resource != "deployments" || int(request.arguments.scale) <= 10`}}
+		request := api.MutationRequest{Tool: "resources_scale", Resource: "deployments", Arguments: map[string]any{"scale": float64(20)}}
+		s.Equal([]string{"small-scale: the call doesn't satisfy " + policies[0].Expression}, s.evaluate(policies, request))
+		request.Arguments["scale"] = float64(3)
+		s.Empty(s.evaluate(policies, request))
+	})
+	s.Run("object is null for the tools without a manifest", func() {
+		s.Empty(s.evaluate([]config.Policy{{Name: "p", Expression: `object == null`}}, api.MutationRequest{Tool: "pods_delete"}))
+	})
+	s.Run("returns the policies that fail to evaluate as an error", func() {
+		p, err := New([]config.Policy{
+			{Name: "p", Expression: `object.spec.replicas < 5`},
+			{Name: "no-kube-system", Expression: `request.namespace != "kube-system"`},
+		})
+		s.Require().NoError(err)
+		reasons, err := p.Evaluate(context.Background(), hostNetworkPod)
+		s.Empty(reasons)
+		s.EqualError(err, "p: no such key: replicas")
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(PolicySuite))
+}
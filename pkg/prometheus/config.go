@@ -0,0 +1,82 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// ToolsetName is the name of the toolset (and of its configuration section) that queries Prometheus
+const ToolsetName = "metrics"
+
+// Config holds the metrics toolset configuration
+type Config struct {
+	// Url is the Prometheus (or Thanos Querier) endpoint, e.g. https://thanos-querier.openshift-monitoring.svc:9091
+	Url string `toml:"url"`
+	// BearerToken is sent in the Authorization header of the Prometheus requests
+	BearerToken string `toml:"bearer_token,omitempty"`
+	// BearerTokenFile is the path of a file containing the bearer token, read on every request so that rotated
+	// tokens (e.g. projected service account tokens) are picked up
+	BearerTokenFile      string `toml:"bearer_token_file,omitempty"`
+	Insecure             bool   `toml:"insecure,omitempty"`
+	CertificateAuthority string `toml:"certificate_authority,omitempty"`
+}
+
+var _ config.Extended = (*Config)(nil)
+
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("metrics config is nil")
+	}
+	if c.Url == "" {
+		return errors.New("url is required")
+	}
+	u, err := url.Parse(c.Url)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("url must be a valid URL")
+	}
+	if c.BearerToken != "" && c.BearerTokenFile != "" {
+		return errors.New("bearer_token and bearer_token_file are mutually exclusive")
+	}
+	if strings.EqualFold(u.Scheme, "https") && !c.Insecure && strings.TrimSpace(c.CertificateAuthority) == "" {
+		return errors.New("certificate_authority is required for https when insecure is false")
+	}
+	if caValue := strings.TrimSpace(c.CertificateAuthority); caValue != "" {
+		if _, err = os.Stat(caValue); err != nil {
+			return fmt.Errorf("certificate_authority must be a valid file path: %w", err)
+		}
+	}
+	if tokenFile := strings.TrimSpace(c.BearerTokenFile); tokenFile != "" {
+		if _, err = os.Stat(tokenFile); err != nil {
+			return fmt.Errorf("bearer_token_file must be a valid file path: %w", err)
+		}
+	}
+	return nil
+}
+
+func metricsToolsetParser(ctx context.Context, primitive toml.Primitive, md toml.MetaData) (config.Extended, error) {
+	var cfg Config
+	if err := md.PrimitiveDecode(primitive, &cfg); err != nil {
+		return nil, err
+	}
+	// Relative file paths are resolved relative to the config directory
+	configDir := config.ConfigDirPathFromContext(ctx)
+	for _, path := range []*string{&cfg.CertificateAuthority, &cfg.BearerTokenFile} {
+		if *path != "" && configDir != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(configDir, *path)
+		}
+	}
+	return &cfg, nil
+}
+
+func init() {
+	config.RegisterToolsetConfig(ToolsetName, metricsToolsetParser)
+}
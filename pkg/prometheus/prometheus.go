@@ -0,0 +1,179 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// Prometheus is a client of the Prometheus HTTP API (https://prometheus.io/docs/prometheus/latest/querying/api/),
+// also served by Thanos Querier
+type Prometheus struct {
+	url                  string
+	bearerToken          string
+	bearerTokenFile      string
+	insecure             bool
+	certificateAuthority string
+}
+
+// QueryResult is the data of a successful instant or range query
+type QueryResult struct {
+	// ResultType is one of matrix, vector, scalar or string
+	ResultType string `json:"resultType"`
+	Result     []any  `json:"result"`
+	// Warnings are returned by Prometheus for queries that succeeded with caveats (e.g. partial responses)
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type apiResponse struct {
+	Status    string       `json:"status"`
+	Data      *QueryResult `json:"data,omitempty"`
+	ErrorType string       `json:"errorType,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Warnings  []string     `json:"warnings,omitempty"`
+}
+
+// NewPrometheus creates a new Prometheus client from the metrics toolset configuration
+func NewPrometheus(config *config.StaticConfig) *Prometheus {
+	p := &Prometheus{}
+	if cfg, ok := config.GetToolsetConfig(ToolsetName); ok {
+		if pc, ok := cfg.(*Config); ok && pc != nil {
+			p.url = pc.Url
+			p.bearerToken = pc.BearerToken
+			p.bearerTokenFile = pc.BearerTokenFile
+			p.insecure = pc.Insecure
+			p.certificateAuthority = pc.CertificateAuthority
+		}
+	}
+	return p
+}
+
+// Query evaluates an instant query at the provided time (RFC3339 or Unix timestamp, evaluation time of the server if empty)
+func (p *Prometheus) Query(ctx context.Context, query, time string) (*QueryResult, error) {
+	params := url.Values{"query": {query}}
+	if time != "" {
+		params.Set("time", time)
+	}
+	return p.executeQuery(ctx, "/api/v1/query", params)
+}
+
+// QueryRange evaluates a query over a range of time (RFC3339 or Unix timestamps) with the provided resolution step
+// (duration, e.g. 30s, or float number of seconds)
+func (p *Prometheus) QueryRange(ctx context.Context, query, start, end, step string) (*QueryResult, error) {
+	params := url.Values{"query": {query}, "start": {start}, "end": {end}, "step": {step}}
+	return p.executeQuery(ctx, "/api/v1/query_range", params)
+}
+
+func (p *Prometheus) executeQuery(ctx context.Context, endpoint string, params url.Values) (*QueryResult, error) {
+	if p == nil || strings.TrimSpace(p.url) == "" {
+		return nil, errors.New("prometheus url not configured, set [toolset_configs.metrics].url")
+	}
+	apiURL, err := url.JoinPath(strings.TrimSpace(p.url), endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prometheus url: %w", err)
+	}
+	klog.V(3).Infof("prometheus API call: POST %s", apiURL)
+	// POST allows queries larger than the URL length limits
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	authHeader, err := p.authorizationHeader()
+	if err != nil {
+		return nil, err
+	}
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	resp, err := p.createHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	response := &apiResponse{}
+	if err = json.Unmarshal(body, response); err != nil {
+		// Proxies and authentication failures don't return the Prometheus API format
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("prometheus API error: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return nil, fmt.Errorf("failed to parse prometheus response: %w", err)
+	}
+	if response.Status != "success" {
+		if response.Error == "" {
+			return nil, fmt.Errorf("prometheus API error: status %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("prometheus API error (%s): %s", response.ErrorType, response.Error)
+	}
+	if response.Data == nil {
+		return nil, errors.New("prometheus response has no data")
+	}
+	response.Data.Warnings = response.Warnings
+	return response.Data, nil
+}
+
+// authorizationHeader returns the Authorization header value (Bearer <token>), or empty if no token is configured
+func (p *Prometheus) authorizationHeader() (string, error) {
+	token := p.bearerToken
+	if p.bearerTokenFile != "" {
+		content, err := os.ReadFile(p.bearerTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read bearer token file %s: %w", p.bearerTokenFile, err)
+		}
+		token = string(content)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(token, "Bearer ") {
+		return token, nil
+	}
+	return "Bearer " + token, nil
+}
+
+func (p *Prometheus) createHTTPClient() *http.Client {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: p.insecure,
+	}
+	if caValue := strings.TrimSpace(p.certificateAuthority); caValue != "" {
+		caPEM, err := os.ReadFile(caValue)
+		if err != nil {
+			klog.Errorf("failed to read CA certificate from file %s: %v; proceeding without custom CA", caValue, err)
+		} else {
+			// Start with the host system pool when possible so we don't drop system roots
+			certPool, err := x509.SystemCertPool()
+			if err != nil || certPool == nil {
+				certPool = x509.NewCertPool()
+			}
+			if certPool.AppendCertsFromPEM(caPEM) {
+				tlsConfig.RootCAs = certPool
+			} else {
+				klog.Errorf("failed to append certificate authority %s; proceeding without custom CA", caValue)
+			}
+		}
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+}
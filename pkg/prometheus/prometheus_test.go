@@ -0,0 +1,157 @@
+package prometheus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type PrometheusSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	requests []*http.Request
+	forms    []url.Values
+	response string
+	status   int
+}
+
+func (s *PrometheusSuite) SetupTest() {
+	s.requests, s.forms = nil, nil
+	s.response = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"node":"node-1"},"value":[1735725600,"0.5"]}]}}`
+	s.status = http.StatusOK
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		s.requests = append(s.requests, req)
+		s.forms = append(s.forms, form)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(s.response))
+	}))
+}
+
+func (s *PrometheusSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *PrometheusSuite) prometheus(extra string) *Prometheus {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.metrics]
+		url = "` + s.server.URL + `/prometheus"
+	` + extra)))
+	return NewPrometheus(cfg)
+}
+
+func (s *PrometheusSuite) TestQuery() {
+	result, err := s.prometheus(`bearer_token = "t0k3n"`).Query(s.T().Context(), `up{job="kubelet"}`, "1735725600")
+	s.Require().NoError(err)
+	s.Run("returns the result", func() {
+		s.Equal("vector", result.ResultType)
+		s.Len(result.Result, 1)
+	})
+	s.Run("posts the query to the instant query endpoint", func() {
+		s.Require().Len(s.requests, 1)
+		s.Equal(http.MethodPost, s.requests[0].Method)
+		s.Equal("/prometheus/api/v1/query", s.requests[0].URL.Path)
+		s.Equal(`up{job="kubelet"}`, s.forms[0].Get("query"))
+		s.Equal("1735725600", s.forms[0].Get("time"))
+	})
+	s.Run("sets the bearer token", func() {
+		s.Equal("Bearer t0k3n", s.requests[0].Header.Get("Authorization"))
+	})
+}
+
+func (s *PrometheusSuite) TestQueryRange() {
+	s.response = `{"status":"success","warnings":["partial response"],"data":{"resultType":"matrix","result":[]}}`
+	tokenFile := filepath.Join(s.T().TempDir(), "token")
+	s.Require().NoError(os.WriteFile(tokenFile, []byte("f1l3-t0k3n\n"), 0600))
+	result, err := s.prometheus(`bearer_token_file = "`+filepath.ToSlash(tokenFile)+`"`).
+		QueryRange(s.T().Context(), "up", "1735722000", "1735725600", "30")
+	s.Require().NoError(err)
+	s.Run("returns the result and warnings", func() {
+		s.Equal("matrix", result.ResultType)
+		s.Equal([]string{"partial response"}, result.Warnings)
+	})
+	s.Run("posts the query to the range query endpoint", func() {
+		s.Equal("/prometheus/api/v1/query_range", s.requests[0].URL.Path)
+		s.Equal("1735722000", s.forms[0].Get("start"))
+		s.Equal("1735725600", s.forms[0].Get("end"))
+		s.Equal("30", s.forms[0].Get("step"))
+	})
+	s.Run("sets the bearer token read from file", func() {
+		s.Equal("Bearer f1l3-t0k3n", s.requests[0].Header.Get("Authorization"))
+	})
+}
+
+func (s *PrometheusSuite) TestQueryErrors() {
+	s.Run("returns the Prometheus API error", func() {
+		s.status = http.StatusBadRequest
+		s.response = `{"status":"error","errorType":"bad_data","error":"invalid parameter \"query\": 1:4: parse error"}`
+		_, err := s.prometheus("").Query(s.T().Context(), "up{", "")
+		s.EqualError(err, `prometheus API error (bad_data): invalid parameter "query": 1:4: parse error`)
+	})
+	s.Run("returns the HTTP error for non-API responses", func() {
+		s.status = http.StatusForbidden
+		s.response = "Forbidden"
+		_, err := s.prometheus("").Query(s.T().Context(), "up", "")
+		s.EqualError(err, "prometheus API error: status 403: Forbidden")
+	})
+	s.Run("returns error when not configured", func() {
+		_, err := NewPrometheus(config.Default()).Query(s.T().Context(), "up", "")
+		s.EqualError(err, "prometheus url not configured, set [toolset_configs.metrics].url")
+	})
+}
+
+func (s *PrometheusSuite) TestConfig() {
+	s.Run("requires url", func() {
+		_, err := config.ReadToml([]byte(`
+			[toolset_configs.metrics]
+			insecure = true
+		`))
+		s.ErrorContains(err, "url is required")
+	})
+	s.Run("requires certificate_authority for https when insecure is false", func() {
+		_, err := config.ReadToml([]byte(`
+			[toolset_configs.metrics]
+			url = "https://thanos-querier.example:9091"
+		`))
+		s.ErrorContains(err, "certificate_authority is required for https when insecure is false")
+	})
+	s.Run("rejects bearer_token with bearer_token_file", func() {
+		_, err := config.ReadToml([]byte(`
+			[toolset_configs.metrics]
+			url = "http://prometheus.example:9090"
+			bearer_token = "t0k3n"
+			bearer_token_file = "/var/run/secrets/token"
+		`))
+		s.ErrorContains(err, "bearer_token and bearer_token_file are mutually exclusive")
+	})
+	s.Run("resolves relative paths", func() {
+		dir := s.T().TempDir()
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, "ca.crt"), []byte("ca"), 0600))
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, "token"), []byte("t0k3n"), 0600))
+		cfg := test.Must(config.ReadToml([]byte(`
+			[toolset_configs.metrics]
+			url = "https://thanos-querier.example:9091"
+			certificate_authority = "ca.crt"
+			bearer_token_file = "token"
+		`), config.WithDirPath(dir)))
+		metricsCfg, ok := cfg.GetToolsetConfig(ToolsetName)
+		s.Require().True(ok)
+		s.Equal(filepath.Join(dir, "ca.crt"), metricsCfg.(*Config).CertificateAuthority)
+		s.Equal(filepath.Join(dir, "token"), metricsCfg.(*Config).BearerTokenFile)
+	})
+}
+
+func TestPrometheus(t *testing.T) {
+	suite.Run(t, new(PrometheusSuite))
+}
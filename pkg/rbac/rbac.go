@@ -0,0 +1,233 @@
+// Package rbac provides a graph of the RBAC objects of a cluster (Roles, ClusterRoles, and their bindings) to answer
+// the reverse lookups the authorization API doesn't provide: which subjects can perform an action, and which
+// permissions are bound to a subject.
+//
+// The graph is evaluated with the rules of the Kubernetes RBAC authorizer, permissions granted by other authorizers
+// (e.g. Node, webhooks) are not reported.
+package rbac
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+const (
+	KindUser           = rbacv1.UserKind
+	KindGroup          = rbacv1.GroupKind
+	KindServiceAccount = rbacv1.ServiceAccountKind
+)
+
+// ResourceRequest is the action to look up, with the attributes of a SelfSubjectAccessReview
+type ResourceRequest struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	// Name of the resource (Optional, any resource name if empty)
+	Name string
+	// Namespace of the resource (Optional, cluster-wide if empty: only the ClusterRoleBindings grant it)
+	Namespace string
+}
+
+// Grant is a binding granting a role to a subject
+type Grant struct {
+	Subject rbacv1.Subject `json:"subject"`
+	// Binding is the granting binding, e.g. ClusterRoleBinding/cluster-admin or RoleBinding/ns-1/edit
+	Binding string `json:"binding"`
+	// Role is the bound role, e.g. ClusterRole/admin or Role/ns-1/pod-reader
+	Role string `json:"role"`
+	// Namespace where the role is granted, empty for the ClusterRoleBindings (all namespaces)
+	Namespace string `json:"namespace,omitempty"`
+	// Rules are the rules of the role, only reported by BindingsFor
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+	// RoleNotFound is set if the bound role doesn't exist, the binding grants nothing
+	RoleNotFound bool `json:"roleNotFound,omitempty"`
+}
+
+// Graph links the subjects to the roles granted by the bindings
+type Graph struct {
+	roles               map[string]*rbacv1.Role
+	clusterRoles        map[string]*rbacv1.ClusterRole
+	roleBindings        []rbacv1.RoleBinding
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+}
+
+func NewGraph(roles []rbacv1.Role, clusterRoles []rbacv1.ClusterRole, roleBindings []rbacv1.RoleBinding, clusterRoleBindings []rbacv1.ClusterRoleBinding) *Graph {
+	g := &Graph{
+		roles:               make(map[string]*rbacv1.Role, len(roles)),
+		clusterRoles:        make(map[string]*rbacv1.ClusterRole, len(clusterRoles)),
+		roleBindings:        roleBindings,
+		clusterRoleBindings: clusterRoleBindings,
+	}
+	for i := range roles {
+		g.roles[roles[i].Namespace+"/"+roles[i].Name] = &roles[i]
+	}
+	for i := range clusterRoles {
+		g.clusterRoles[clusterRoles[i].Name] = &clusterRoles[i]
+	}
+	return g
+}
+
+// WhoCan returns the grants of the subjects allowed to perform the request, sorted by subject
+func (g *Graph) WhoCan(request ResourceRequest) []Grant {
+	grants := make([]Grant, 0)
+	for _, grant := range g.grants(request.Namespace) {
+		if !grant.RoleNotFound && slices.ContainsFunc(grant.Rules, func(rule rbacv1.PolicyRule) bool { return RuleAllows(rule, request) }) {
+			grant.Rules = nil
+			grants = append(grants, grant)
+		}
+	}
+	sortGrants(grants)
+	return grants
+}
+
+// BindingsFor returns the grants of the subject, including the ones of its groups.
+// The groups of a ServiceAccount (system:serviceaccounts and system:serviceaccounts:<namespace>) and
+// system:authenticated are implied.
+func (g *Graph) BindingsFor(subject rbacv1.Subject, groups []string) []Grant {
+	groups = slices.Clone(groups)
+	if subject.Kind == KindServiceAccount {
+		groups = append(groups, "system:serviceaccounts", "system:serviceaccounts:"+subject.Namespace)
+	}
+	if subject.Kind != KindGroup {
+		groups = append(groups, "system:authenticated")
+	}
+	grants := make([]Grant, 0)
+	for _, grant := range g.grants("*") {
+		s := grant.Subject
+		if subjectMatches(s, subject) || (s.Kind == KindGroup && slices.Contains(groups, s.Name)) {
+			grants = append(grants, grant)
+		}
+	}
+	sortGrants(grants)
+	return grants
+}
+
+// grants returns one grant per subject of the bindings applicable to the namespace:
+// the ClusterRoleBindings, and the RoleBindings of the namespace ("*" for every namespace, "" for none)
+func (g *Graph) grants(namespace string) []Grant {
+	var grants []Grant
+	for _, binding := range g.clusterRoleBindings {
+		role := "ClusterRole/" + binding.RoleRef.Name
+		clusterRole, found := g.clusterRoles[binding.RoleRef.Name]
+		for _, subject := range binding.Subjects {
+			grant := Grant{Subject: subject, Binding: "ClusterRoleBinding/" + binding.Name, Role: role, RoleNotFound: !found}
+			if found {
+				grant.Rules = clusterRole.Rules
+			}
+			grants = append(grants, grant)
+		}
+	}
+	for _, binding := range g.roleBindings {
+		if namespace != "*" && binding.Namespace != namespace {
+			continue
+		}
+		var rules []rbacv1.PolicyRule
+		var role string
+		found := false
+		if binding.RoleRef.Kind == "ClusterRole" {
+			role = "ClusterRole/" + binding.RoleRef.Name
+			if clusterRole, ok := g.clusterRoles[binding.RoleRef.Name]; ok {
+				rules, found = clusterRole.Rules, true
+			}
+		} else {
+			role = "Role/" + binding.Namespace + "/" + binding.RoleRef.Name
+			if r, ok := g.roles[binding.Namespace+"/"+binding.RoleRef.Name]; ok {
+				rules, found = r.Rules, true
+			}
+		}
+		for _, subject := range binding.Subjects {
+			// subjects of kind ServiceAccount default to the namespace of the RoleBinding
+			if subject.Kind == KindServiceAccount && subject.Namespace == "" {
+				subject.Namespace = binding.Namespace
+			}
+			grants = append(grants, Grant{
+				Subject:      subject,
+				Binding:      "RoleBinding/" + binding.Namespace + "/" + binding.Name,
+				Role:         role,
+				Namespace:    binding.Namespace,
+				Rules:        rules,
+				RoleNotFound: !found,
+			})
+		}
+	}
+	return grants
+}
+
+// RuleAllows returns true if the policy rule allows the resource request, following the matching of the RBAC
+// authorizer (wildcards, resource/subresource and */subresource forms, resource names)
+func RuleAllows(rule rbacv1.PolicyRule, request ResourceRequest) bool {
+	if !contains(rule.Verbs, request.Verb) || !contains(rule.APIGroups, request.Group) {
+		return false
+	}
+	resource := request.Resource
+	if request.Subresource != "" {
+		resource += "/" + request.Subresource
+	}
+	if !slices.ContainsFunc(rule.Resources, func(r string) bool {
+		return r == rbacv1.ResourceAll || r == resource || (request.Subresource != "" && r == "*/"+request.Subresource)
+	}) {
+		return false
+	}
+	return len(rule.ResourceNames) == 0 || slices.Contains(rule.ResourceNames, request.Name)
+}
+
+// String returns the request in the kubectl auth can-i form, e.g. "create pods/exec in namespace ns-1"
+func (r ResourceRequest) String() string {
+	resource := r.Resource
+	if r.Group != "" {
+		resource += "." + r.Group
+	}
+	if r.Subresource != "" {
+		resource += "/" + r.Subresource
+	}
+	if r.Name != "" {
+		resource += "/" + r.Name
+	}
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s cluster-wide", r.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", r.Verb, resource, r.Namespace)
+}
+
+// ParseSubject parses a subject in the kind:name or kind:namespace:name form, e.g. user:alice, group:dev,
+// or serviceaccount:ns-1:builder
+func ParseSubject(subject string) (rbacv1.Subject, error) {
+	parts := strings.Split(subject, ":")
+	switch {
+	case len(parts) >= 2 && strings.EqualFold(parts[0], KindUser):
+		return rbacv1.Subject{Kind: KindUser, APIGroup: rbacv1.GroupName, Name: strings.Join(parts[1:], ":")}, nil
+	case len(parts) >= 2 && strings.EqualFold(parts[0], KindGroup):
+		return rbacv1.Subject{Kind: KindGroup, APIGroup: rbacv1.GroupName, Name: strings.Join(parts[1:], ":")}, nil
+	case len(parts) == 3 && strings.EqualFold(parts[0], KindServiceAccount) && parts[1] != "" && parts[2] != "":
+		return rbacv1.Subject{Kind: KindServiceAccount, Namespace: parts[1], Name: parts[2]}, nil
+	}
+	return rbacv1.Subject{}, fmt.Errorf("invalid subject %q, expected user:<name>, group:<name> or serviceaccount:<namespace>:<name>", subject)
+}
+
+// FormatSubject returns the subject in the form parsed by ParseSubject, e.g. ServiceAccount:ns-1:builder
+func FormatSubject(subject rbacv1.Subject) string {
+	if subject.Kind == KindServiceAccount {
+		return subject.Kind + ":" + subject.Namespace + ":" + subject.Name
+	}
+	return subject.Kind + ":" + subject.Name
+}
+
+func subjectMatches(a, b rbacv1.Subject) bool {
+	return a.Kind == b.Kind && a.Name == b.Name && (a.Kind != KindServiceAccount || a.Namespace == b.Namespace)
+}
+
+func contains(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
+
+func sortGrants(grants []Grant) {
+	slices.SortStableFunc(grants, func(a, b Grant) int {
+		return strings.Compare(
+			a.Subject.Kind+"/"+a.Subject.Namespace+"/"+a.Subject.Name+"/"+a.Binding,
+			b.Subject.Kind+"/"+b.Subject.Namespace+"/"+b.Subject.Name+"/"+b.Binding)
+	})
+}
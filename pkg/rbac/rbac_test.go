@@ -0,0 +1,164 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RbacSuite struct {
+	suite.Suite
+	graph *Graph
+}
+
+func (s *RbacSuite) SetupTest() {
+	s.graph = NewGraph(
+		[]rbacv1.Role{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "pod-exec"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}},
+		}},
+		[]rbacv1.ClusterRole{{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Name: "view"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{"", "apps"}, Resources: []string{"pods", "deployments"}}},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Name: "config-reader"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"app-config"}}},
+		}},
+		[]rbacv1.RoleBinding{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "builder-exec"},
+			Subjects:   []rbacv1.Subject{{Kind: KindServiceAccount, Name: "builder"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-exec"},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "developers-view"},
+			Subjects:   []rbacv1.Subject{{Kind: KindGroup, Name: "developers"}, {Kind: KindUser, Name: "alice"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "config"},
+			Subjects:   []rbacv1.Subject{{Kind: KindGroup, Name: "system:serviceaccounts:ns-2"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "config-reader"},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "dangling"},
+			Subjects:   []rbacv1.Subject{{Kind: KindUser, Name: "alice"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "missing"},
+		}},
+		[]rbacv1.ClusterRoleBinding{{
+			ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+			Subjects:   []rbacv1.Subject{{Kind: KindUser, Name: "admin"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		}},
+	)
+}
+
+func (s *RbacSuite) subjects(grants []Grant) []string {
+	subjects := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		subjects = append(subjects, FormatSubject(grant.Subject))
+	}
+	return subjects
+}
+
+func (s *RbacSuite) TestWhoCan() {
+	s.Run("namespaced request includes the RoleBindings of the namespace and the ClusterRoleBindings", func() {
+		grants := s.graph.WhoCan(ResourceRequest{Verb: "list", Resource: "pods", Namespace: "ns-1"})
+		s.Equal([]string{"Group:developers", "User:admin", "User:alice"}, s.subjects(grants))
+		s.Equal("RoleBinding/ns-1/developers-view", grants[0].Binding)
+		s.Equal("ClusterRole/view", grants[0].Role)
+		s.Equal("ns-1", grants[0].Namespace)
+		s.Nil(grants[0].Rules)
+	})
+	s.Run("namespaced request excludes the RoleBindings of other namespaces", func() {
+		s.Equal([]string{"User:admin"}, s.subjects(s.graph.WhoCan(ResourceRequest{Verb: "list", Resource: "pods", Namespace: "ns-2"})))
+	})
+	s.Run("cluster-wide request only includes the ClusterRoleBindings", func() {
+		s.Equal([]string{"User:admin"}, s.subjects(s.graph.WhoCan(ResourceRequest{Verb: "list", Resource: "pods"})))
+	})
+	s.Run("matches the subresources", func() {
+		grants := s.graph.WhoCan(ResourceRequest{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "ns-1"})
+		s.Equal([]string{"ServiceAccount:ns-1:builder", "User:admin"}, s.subjects(grants))
+		s.Equal("Role/ns-1/pod-exec", grants[0].Role)
+	})
+	s.Run("matches the API groups", func() {
+		s.Equal([]string{"Group:developers", "User:admin", "User:alice"},
+			s.subjects(s.graph.WhoCan(ResourceRequest{Verb: "get", Group: "apps", Resource: "deployments", Namespace: "ns-1"})))
+		s.Equal([]string{"User:admin"},
+			s.subjects(s.graph.WhoCan(ResourceRequest{Verb: "get", Group: "batch", Resource: "jobs", Namespace: "ns-1"})))
+	})
+	s.Run("matches the resource names", func() {
+		s.Equal([]string{"Group:system:serviceaccounts:ns-2", "User:admin"},
+			s.subjects(s.graph.WhoCan(ResourceRequest{Verb: "get", Resource: "configmaps", Name: "app-config", Namespace: "ns-2"})))
+		s.Equal([]string{"User:admin"},
+			s.subjects(s.graph.WhoCan(ResourceRequest{Verb: "get", Resource: "configmaps", Name: "other", Namespace: "ns-2"})))
+		s.Equal([]string{"User:admin"},
+			s.subjects(s.graph.WhoCan(ResourceRequest{Verb: "get", Resource: "configmaps", Namespace: "ns-2"})))
+	})
+}
+
+func (s *RbacSuite) TestBindingsFor() {
+	s.Run("user", func() {
+		grants := s.graph.BindingsFor(rbacv1.Subject{Kind: KindUser, Name: "alice"}, nil)
+		s.Require().Len(grants, 2)
+		s.Equal("RoleBinding/ns-1/developers-view", grants[0].Binding)
+		s.Equal([]string{"get", "list", "watch"}, grants[0].Rules[0].Verbs)
+		s.Run("reports the bindings of missing roles", func() {
+			s.Equal("RoleBinding/ns-2/dangling", grants[1].Binding)
+			s.Equal("Role/ns-2/missing", grants[1].Role)
+			s.True(grants[1].RoleNotFound)
+		})
+	})
+	s.Run("user with groups", func() {
+		grants := s.graph.BindingsFor(rbacv1.Subject{Kind: KindUser, Name: "bob"}, []string{"developers"})
+		s.Equal([]string{"Group:developers"}, s.subjects(grants))
+	})
+	s.Run("service account with the namespace defaulted from the RoleBinding", func() {
+		grants := s.graph.BindingsFor(rbacv1.Subject{Kind: KindServiceAccount, Namespace: "ns-1", Name: "builder"}, nil)
+		s.Equal([]string{"ServiceAccount:ns-1:builder"}, s.subjects(grants))
+	})
+	s.Run("service account with the implicit service account groups", func() {
+		grants := s.graph.BindingsFor(rbacv1.Subject{Kind: KindServiceAccount, Namespace: "ns-2", Name: "default"}, nil)
+		s.Equal([]string{"Group:system:serviceaccounts:ns-2"}, s.subjects(grants))
+	})
+	s.Run("service account of another namespace", func() {
+		s.Equal([]string{"Group:system:serviceaccounts:ns-2"},
+			s.subjects(s.graph.BindingsFor(rbacv1.Subject{Kind: KindServiceAccount, Namespace: "ns-2", Name: "builder"}, nil)))
+	})
+}
+
+func (s *RbacSuite) TestParseSubject() {
+	s.Run("user", func() {
+		subject, err := ParseSubject("user:system:admin")
+		s.Require().NoError(err)
+		s.Equal(rbacv1.Subject{Kind: KindUser, APIGroup: rbacv1.GroupName, Name: "system:admin"}, subject)
+	})
+	s.Run("group", func() {
+		subject, err := ParseSubject("Group:developers")
+		s.Require().NoError(err)
+		s.Equal(rbacv1.Subject{Kind: KindGroup, APIGroup: rbacv1.GroupName, Name: "developers"}, subject)
+	})
+	s.Run("service account", func() {
+		subject, err := ParseSubject("serviceaccount:ns-1:builder")
+		s.Require().NoError(err)
+		s.Equal(rbacv1.Subject{Kind: KindServiceAccount, Namespace: "ns-1", Name: "builder"}, subject)
+	})
+	s.Run("service account without namespace", func() {
+		_, err := ParseSubject("serviceaccount:builder")
+		s.EqualError(err, `invalid subject "serviceaccount:builder", expected user:<name>, group:<name> or serviceaccount:<namespace>:<name>`)
+	})
+	s.Run("without kind", func() {
+		_, err := ParseSubject("alice")
+		s.Error(err)
+	})
+}
+
+func (s *RbacSuite) TestResourceRequestString() {
+	s.Equal("create pods/exec in namespace ns-1", ResourceRequest{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "ns-1"}.String())
+	s.Equal("get deployments.apps/web cluster-wide", ResourceRequest{Verb: "get", Group: "apps", Resource: "deployments", Name: "web"}.String())
+}
+
+func TestRbac(t *testing.T) {
+	suite.Run(t, new(RbacSuite))
+}
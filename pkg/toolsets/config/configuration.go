@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/jsonschema-go/jsonschema"
@@ -31,6 +32,33 @@ func initConfiguration() []api.ServerTool {
 			TargetListProvider: ptr.To(true),
 			Handler:            contextsList,
 		},
+		{
+			Tool: api.Tool{
+				Name: "configuration_contexts_set_default",
+				Description: "Set the default context used by the tools when the context parameter is not provided. " +
+					"Updates the current-context of the kubeconfig file (equivalent to `kubectl config use-context`)",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"name": {
+							Type:        "string",
+							Description: "Name of the context to use as default (as returned by configuration_contexts_list)",
+						},
+					},
+					Required: []string{"name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Configuration: Contexts Set Default",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware:       ptr.To(false),
+			TargetListProvider: ptr.To(true),
+			Handler:            contextsSetDefault,
+		},
 		{
 			Tool: api.Tool{
 				Name:        "configuration_view",
@@ -57,6 +85,26 @@ func initConfiguration() []api.ServerTool {
 			ClusterAware: ptr.To(false),
 			Handler:      configurationView,
 		},
+		{
+			Tool: api.Tool{
+				Name: "server_info",
+				Description: "Get information about this MCP server: version, enabled toolsets, configured clusters (names only), " +
+					"active limits (read-only mode, destructive tools, denied resources) and feature flags. " +
+					"Useful to understand which capabilities are available before calling other tools",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Server: Info",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      serverInfo,
+		},
 	}
 	return tools
 }
@@ -64,7 +112,7 @@ func initConfiguration() []api.ServerTool {
 func contextsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	contexts, err := params.ConfigurationContextsList()
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list contexts: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list contexts: %w", err)), nil
 	}
 
 	if len(contexts) == 0 {
@@ -73,7 +121,7 @@ func contextsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 
 	defaultContext, err := params.ConfigurationContextsDefault()
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get default context: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get default context: %w", err)), nil
 	}
 
 	result := fmt.Sprintf("Available Kubernetes contexts (%d total, default: %s):\n\n", len(contexts), defaultContext)
@@ -96,6 +144,18 @@ func contextsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	return api.NewToolCallResult(result, nil), nil
 }
 
+func contextsSetDefault(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to set default context, missing argument name"))), nil
+	}
+	if err := params.ConfigurationContextsSetDefault(name); err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to set default context: %w", err)), nil
+	}
+	return api.NewToolCallResult(fmt.Sprintf("Default context set to %s, "+
+		"tools will target this context when the context parameter is not set", name), nil), nil
+}
+
 func configurationView(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	minify := true
 	minified := params.GetArguments()["minified"]
@@ -104,11 +164,23 @@ func configurationView(params api.ToolHandlerParams) (*api.ToolCallResult, error
 	}
 	ret, err := params.ConfigurationView(minify)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get configuration: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get configuration: %w", err)), nil
 	}
 	configurationYaml, err := output.MarshalYaml(ret)
 	if err != nil {
-		err = fmt.Errorf("failed to get configuration: %v", err)
+		err = fmt.Errorf("failed to get configuration: %w", err)
 	}
 	return api.NewToolCallResult(configurationYaml, err), nil
 }
+
+func serverInfo(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ret, err := params.ServerInfo()
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get server info: %w", err)), nil
+	}
+	serverInfoYaml, err := output.MarshalYaml(ret)
+	if err != nil {
+		err = fmt.Errorf("failed to get server info: %w", err)
+	}
+	return api.NewToolCallResult(serverInfoYaml, err), nil
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initApis() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "explain",
+			Description: "Get the documentation of the fields of a Kubernetes resource (built-in or custom) by providing its apiVersion, kind, and optionally the path of a field, like kubectl explain. " +
+				"The documentation is built from the OpenAPI v3 schema published by the cluster, including the field types, descriptions, required fields, and enum values. " +
+				"Use recursive to list all the nested fields with their types, e.g. before authoring a manifest",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"apiVersion": {
+						Type:        "string",
+						Description: "apiVersion of the resource (examples of valid apiVersion are: v1, apps/v1, cert-manager.io/v1)",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "kind of the resource (examples of valid kind are: Pod, Deployment, Certificate)",
+					},
+					"field": {
+						Type:        "string",
+						Description: "Dotted path of the field to explain, e.g. spec.template.spec.containers (Optional, the whole resource if not provided)",
+					},
+					"recursive": {
+						Type:        "boolean",
+						Description: "List all the nested fields with their types instead of the documentation of the direct fields (Optional, default false)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"apiVersion", "kind"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "APIs: Explain",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: explain},
+		{Tool: api.Tool{
+			Name: "crds_list",
+			Description: "List the Kubernetes CustomResourceDefinitions (CRDs) of the cluster with their group, kind, scope, short names, " +
+				"versions (served, storage, and deprecated ones), and the conditions that are not healthy (e.g. not Established, NamesAccepted False, NonStructuralSchema)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"group": {
+						Type:        "string",
+						Description: "Only list the CRDs of the provided API group or of its subgroups, e.g. cert-manager.io (Optional)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "APIs: List CRDs",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: crdsList, Permissions: []api.ResourcePermission{listCustomResourceDefinitions}},
+		{Tool: api.Tool{
+			Name: "api_deprecations",
+			Description: "Report the readiness of the cluster for a Kubernetes upgrade by scanning the live objects for the deprecated and removed API versions " +
+				"they were last applied with (kubectl.kubernetes.io/last-applied-configuration annotation) or managed with (server-side apply and update managed fields), " +
+				"relative to the cluster version and the target version of the upgrade. " +
+				"Each finding reports the object, the deprecated apiVersion, the tools that use it, the release that deprecated it and the one that removes it, and the replacement apiVersion. " +
+				"Objects using an API version removed in the target version must have their manifests (or Helm charts, operators) migrated before the upgrade",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the objects to scan (Optional, all namespaces and the cluster-scoped objects if not provided)",
+					},
+					"target_version": {
+						Type:        "string",
+						Description: "Kubernetes minor version of the upgrade, e.g. 1.32 (Optional, the next minor version of the cluster if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "APIs: Deprecations",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: apiDeprecations},
+	}
+}
+
+type explainArgs struct {
+	Field     string `json:"field"`
+	Recursive bool   `json:"recursive"`
+}
+
+func explain(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	gvk, err := parseGroupVersionKind(params.GetArguments())
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to explain resource, %w", err)), nil
+	}
+	args, err := api.ParseArguments[explainArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to explain resource, %w", err)), nil
+	}
+	ret, err := params.Explain(gvk, args.Field, args.Recursive)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to explain %s: %w", gvk.Kind, err)), nil
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
+
+type crdsListArgs struct {
+	Group string `json:"group"`
+}
+
+func crdsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[crdsListArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list custom resource definitions, %w", err)), nil
+	}
+	crds, err := params.CRDsList(params, args.Group)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list custom resource definitions: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(crds)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list custom resource definitions: %w", err)), nil
+	}
+	notEstablished := 0
+	for _, crd := range crds {
+		if !crd.Established {
+			notEstablished++
+		}
+	}
+	summary := fmt.Sprintf("# %d CustomResourceDefinitions found, %d not established", len(crds), notEstablished)
+	return api.NewToolCallResult(summary+"\n"+marshalled, nil), nil
+}
+
+type apiDeprecationsArgs struct {
+	Namespace     string `json:"namespace"`
+	TargetVersion string `json:"target_version"`
+}
+
+func apiDeprecations(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[apiDeprecationsArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scan deprecated APIs, %w", err)), nil
+	}
+	report, err := params.APIDeprecations(params, kubernetes.APIDeprecationsOptions{
+		Namespace:     args.Namespace,
+		TargetVersion: args.TargetVersion,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scan deprecated APIs: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scan deprecated APIs: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+report.Summary+"\n"+marshalled, nil), nil
+}
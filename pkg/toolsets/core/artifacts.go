@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+)
+
+func initArtifacts() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "artifacts_list",
+			Description: "List the files generated by the tools in the server-side artifact store, the most recent first: " +
+				"the diagnostic bundles of diagnostics_collect, the node files of node_files get, the logs of pods_log and nodes_log with artifact=true, and the snapshots of snapshot_create. " +
+				"The artifacts are read with artifacts_get or as artifact:// MCP resources, they expire according to the artifacts retention configuration",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Artifacts: List",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(false),
+			},
+		}, Handler: artifactsList, ClusterAware: ptr.To(false)},
+		{Tool: api.Tool{
+			Name: "artifacts_get",
+			Description: "Get the content of a text artifact of the server-side artifact store (e.g. a log or a node file) by name or artifact:// URI, as listed by artifacts_list. " +
+				"Use tail to only return the last lines of large artifacts, binary artifacts (e.g. diagnostic bundles) are only described",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name or artifact:// URI of the artifact",
+					},
+					"tail": {
+						Type:        "integer",
+						Description: "Number of lines to return from the end of the artifact (Optional, 0 means the whole artifact)",
+						Default:     api.ToRawMessage(0),
+						Minimum:     ptr.To(float64(0)),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Artifacts: Get",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(false),
+			},
+		}, Handler: artifactsGet, ClusterAware: ptr.To(false)},
+	}
+}
+
+func artifactsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	list, err := params.NewArtifactStore().List()
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list artifacts: %w", err)), nil
+	}
+	if api.IsStructuredOutput(params) {
+		return api.NewStructuredToolCallResult(params, &api.Envelope{
+			Kind:    "Artifact",
+			Items:   list,
+			Summary: fmt.Sprintf("%d artifacts", len(list)),
+		}, ""), nil
+	}
+	if len(list) == 0 {
+		return api.NewToolCallResult("No artifacts found", nil), nil
+	}
+	table := api.NewTable("NAME", "TYPE", "SIZE", "CREATED")
+	for _, artifact := range list {
+		table.AddRow(artifact.Name, artifact.MIMEType, artifact.Size, artifact.Created.Format(time.RFC3339))
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to print artifacts: %w", err)), nil
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# %d artifacts, read them with artifacts_get or as artifact://<name> MCP resources\n", len(list))+rendered, nil), nil
+}
+
+type artifactsGetArgs struct {
+	Name string `json:"name"`
+	Tail int    `json:"tail"`
+}
+
+func artifactsGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[artifactsGetArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get artifact, %w", err)), nil
+	}
+	name := args.Name
+	if strings.HasPrefix(name, artifacts.URIScheme) {
+		var ok bool
+		if name, ok = artifacts.NameFromURI(args.Name); !ok {
+			return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to get artifact, invalid artifact URI %s", args.Name))), nil
+		}
+	}
+	artifact, content, err := params.NewArtifactStore().Get(name)
+	if errors.Is(err, artifacts.ErrNotFound) {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get artifact %s: %w, list the available ones with artifacts_list", name, err)), nil
+	}
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get artifact %s: %w", name, err)), nil
+	}
+	if !artifacts.IsText(artifact.MIMEType) {
+		return api.NewToolCallResult(fmt.Sprintf("Artifact %s is a binary file (%s, %d bytes) that can't be returned as text, it's stored in %s",
+			artifact.Name, artifact.MIMEType, artifact.Size, artifact.Path), nil), nil
+	}
+	ret := string(content)
+	if ret == "" {
+		return api.NewToolCallResult(fmt.Sprintf("Artifact %s is empty", artifact.Name), nil), nil
+	}
+	if args.Tail > 0 {
+		lines := strings.Split(strings.TrimSuffix(ret, "\n"), "\n")
+		if len(lines) > args.Tail {
+			ret = strings.Join(lines[len(lines)-args.Tail:], "\n") + "\n"
+		}
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
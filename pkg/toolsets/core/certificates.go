@@ -0,0 +1,122 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initCertificates() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "certificates_list",
+			Description: "List the Kubernetes CertificateSigningRequests (CSRs) in the current cluster with their status, signer, requester, groups, usages, and requested subject. " +
+				"Pending kubelet client (kubernetes.io/kube-apiserver-client-kubelet) or serving (kubernetes.io/kubelet-serving) CSRs " +
+				"are a frequent cause of nodes failing to join the cluster or of kubelet certificate rotation problems (e.g. logs and exec failing with TLS errors)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"all": {
+						Type:        "boolean",
+						Description: "List all the CSRs, including the approved, denied, and failed ones (Optional, only the pending CSRs are listed by default)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Certificates: List",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: certificatesList, Permissions: []api.ResourcePermission{listCertificateRequests}},
+		{Tool: api.Tool{
+			Name: "certificates",
+			Description: "Approve or deny a pending Kubernetes CertificateSigningRequest (CSR) in the current cluster. " +
+				"Supported actions: 'approve' approves the CSR so that its signer issues the certificate, 'deny' denies the CSR. " +
+				"Verify the requester, usages, and subject of the CSR with certificates_list before approving it, " +
+				"an approved kubelet CSR grants the requester the identity of the node",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"action": {
+						Type:        "string",
+						Description: "Action to perform on the CSR",
+						Enum:        []any{certificatesActionApprove, certificatesActionDeny},
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the CertificateSigningRequest",
+					},
+					"message": {
+						Type:        "string",
+						Description: "Human readable message recorded in the Approved or Denied condition of the CSR (Optional)",
+					},
+				},
+				Required: []string{"action", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Certificates: Approve or Deny",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: certificates, Permissions: []api.ResourcePermission{getCertificateRequests, approveCertificateRequests}, DryRunSupported: ptr.To(true)},
+	}
+}
+
+func certificatesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	all, _ := params.GetArguments()["all"].(bool)
+	ret, err := params.CertificatesList(params, all)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list certificate signing requests: %w", err)), nil
+	}
+	marshalledYaml, err := output.MarshalYaml(ret)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list certificate signing requests: %w", err)), nil
+	}
+	if all {
+		return api.NewToolCallResult(fmt.Sprintf("# %d CertificateSigningRequests found\n", len(ret))+marshalledYaml, nil), nil
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# %d pending CertificateSigningRequests found\n", len(ret))+marshalledYaml, nil), nil
+}
+
+const (
+	certificatesActionApprove = "approve"
+	certificatesActionDeny    = "deny"
+)
+
+func certificates(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	action, _ := params.GetArguments()["action"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to manage certificate signing request, missing argument name"))), nil
+	}
+	message, _ := params.GetArguments()["message"].(string)
+	var done string
+	switch action {
+	case certificatesActionApprove:
+		done = "approved"
+	case certificatesActionDeny:
+		done = "denied"
+	default:
+		return api.NewToolCallResult("", fmt.Errorf("failed to manage certificate signing request, unsupported action %q, supported actions: %s, %s",
+			action, certificatesActionApprove, certificatesActionDeny)), nil
+	}
+	csr, err := params.CertificatesApprove(params, name, action == certificatesActionApprove, message)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to %s certificate signing request %s: %w", action, name, err)), nil
+	}
+	marshalledYaml, err := output.MarshalYaml(csr)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to %s certificate signing request %s: %w", action, name, err)), nil
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# CertificateSigningRequest %s %s successfully\n", name, done)+marshalledYaml, nil), nil
+}
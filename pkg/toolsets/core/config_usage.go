@@ -0,0 +1,77 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initConfigUsage() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "config_usage",
+			Description: "Map the Pods in the current or provided namespace that use a ConfigMap or Secret and how they use it " +
+				"(volumes, subPath mounts, env, envFrom, image pull secrets), with their workloads, the referenced keys missing from the object, " +
+				"and whether the running Pods are stale: the containers reading it at startup (env, envFrom, subPath) that started before its last update. " +
+				"Explains the impact of a change, use it to answer whether it's safe to change a ConfigMap or Secret and which workloads need a restart. " +
+				"The values of the object are never returned",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the object (Optional, default ConfigMap)",
+						Enum:        []any{kubernetes.ConfigUsageKindConfigMap, kubernetes.ConfigUsageKindSecret},
+						Default:     api.ToRawMessage(kubernetes.ConfigUsageKindConfigMap),
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the ConfigMap or Secret",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the ConfigMap or Secret",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Config: Usage",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: configUsage, Permissions: []api.ResourcePermission{getConfigMaps, getSecrets, listPods, listReplicaSets}},
+	}
+}
+
+type configUsageArgs struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func configUsage(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[configUsageArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to map config usage, %w", err)), nil
+	}
+	if args.Kind == "" {
+		args.Kind = kubernetes.ConfigUsageKindConfigMap
+	}
+	usage, err := params.ConfigUsage(params, args.Kind, args.Namespace, args.Name)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to map usage of %s %s: %w", args.Kind, args.Name, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(usage)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to map usage of %s %s: %w", args.Kind, args.Name, err)), nil
+	}
+	return api.NewToolCallResult("# "+usage.Summary+"\n"+marshalled, nil), nil
+}
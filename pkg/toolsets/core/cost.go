@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initCost() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "cost_report",
+			Description: "Estimate the cost of the namespaces of the current cluster, or of the provided namespace, by multiplying the CPU and memory " +
+				"requested by their running Pods by the unit prices of the configuration (cost.cpu_core_hour and cost.memory_gib_hour). " +
+				"Optionally adds the cost of the current usage of the Pods (metrics API) and the idle cost of the requests above the usage. " +
+				"The costs are hourly and monthly estimates (730 hours) for cost attribution, not billing",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to estimate the cost of (Optional, all namespaces if not provided)",
+					},
+					"usage": {
+						Type:        "boolean",
+						Description: "Add the cost of the current usage of the Pods and the idle cost, requires the metrics server (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Cost: Report",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: costReport, Permissions: []api.ResourcePermission{listPods, listPodMetrics}},
+	}
+}
+
+type costReportArgs struct {
+	Namespace string `json:"namespace"`
+	Usage     bool   `json:"usage"`
+}
+
+func costReport(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[costReportArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to estimate cost, %w", err)), nil
+	}
+	report, err := params.CostReport(params, kubernetes.CostReportOptions{Namespace: args.Namespace, Usage: args.Usage})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to estimate cost: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to estimate cost: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+report.Summary+"\n"+marshalled, nil), nil
+}
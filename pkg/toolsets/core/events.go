@@ -2,11 +2,13 @@ package core
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
@@ -30,7 +32,50 @@ func initEvents() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: eventsList},
+		}, Handler: eventsList, Permissions: []api.ResourcePermission{listEvents}},
+		{Tool: api.Tool{
+			Name: "events_history",
+			Description: "Query the history of Kubernetes events retained by the server's embedded event store within a time window. " +
+				"Unlike events_list, includes events that have already been removed by the API server (1 hour retention by default), " +
+				"useful for post-incident analysis. Requires the event store to be enabled in the server configuration (event_store_size)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to retrieve the events from (Optional, all namespaces if not provided)",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Start of the time window, either an RFC3339 timestamp (e.g. 2025-01-01T10:00:00Z) or a duration relative to now (e.g. 30m, 6h) (Optional, oldest retained event if not provided)",
+					},
+					"until": {
+						Type:        "string",
+						Description: "End of the time window, either an RFC3339 timestamp (e.g. 2025-01-01T11:00:00Z) or a duration relative to now (e.g. 5m) (Optional, now if not provided)",
+					},
+					"type": {
+						Type:        "string",
+						Description: "Type of the events to retrieve (Optional, all types if not provided)",
+						Enum:        []any{"Normal", "Warning"},
+					},
+					"involved_object_kind": {
+						Type:        "string",
+						Description: "Kind of the object involved in the events, e.g. Pod (Optional)",
+					},
+					"involved_object_name": {
+						Type:        "string",
+						Description: "Name of the object involved in the events (Optional)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Events: History",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: eventsHistory},
 	}
 }
 
@@ -41,14 +86,53 @@ func eventsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	eventMap, err := params.EventsList(params, namespace.(string))
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list events in all namespaces: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list events in all namespaces: %w", err)), nil
 	}
 	if len(eventMap) == 0 {
 		return api.NewToolCallResult("# No events found", nil), nil
 	}
 	yamlEvents, err := output.MarshalYaml(eventMap)
 	if err != nil {
-		err = fmt.Errorf("failed to list events in all namespaces: %v", err)
+		err = fmt.Errorf("failed to list events in all namespaces: %w", err)
 	}
 	return api.NewToolCallResult(fmt.Sprintf("# The following events (YAML format) were found:\n%s", yamlEvents), err), nil
 }
+
+func eventsHistory(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	query := kubernetes.EventStoreQuery{}
+	query.Namespace, _ = params.GetArguments()["namespace"].(string)
+	query.Type, _ = params.GetArguments()["type"].(string)
+	query.InvolvedObjectKind, _ = params.GetArguments()["involved_object_kind"].(string)
+	query.InvolvedObjectName, _ = params.GetArguments()["involved_object_name"].(string)
+	var err error
+	if query.Since, err = parseEventsTime(params.GetArguments()["since"]); err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to parse since parameter: %w", err)), nil
+	}
+	if query.Until, err = parseEventsTime(params.GetArguments()["until"]); err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to parse until parameter: %w", err)), nil
+	}
+	events, err := params.EventsHistory(params, query)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query events history: %w", err)), nil
+	}
+	if len(events) == 0 {
+		return api.NewToolCallResult("# No events found", nil), nil
+	}
+	yamlEvents, err := output.MarshalYaml(events)
+	if err != nil {
+		err = fmt.Errorf("failed to query events history: %w", err)
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# The following %d events (YAML format) were found:\n%s", len(events), yamlEvents), err), nil
+}
+
+// parseEventsTime parses an RFC3339 timestamp or a duration relative to now (e.g. 2h)
+func parseEventsTime(value any) (time.Time, error) {
+	s, _ := value.(string)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d.Abs()), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initImages() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "images_inventory",
+			Description: "List the unique container images running in the current cluster, in the provided namespace or in all namespaces, " +
+				"with their pull policies, the digests of the running images, and the workloads using them. " +
+				"Optionally queries the vulnerability scanner of the configuration (image_scanner, Clair or Trivy) for the known CVEs of each image, " +
+				"the images are then ranked by the severity of their vulnerabilities",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pods (Optional, all namespaces if not provided)",
+					},
+					"vulnerabilities": {
+						Type:        "boolean",
+						Description: "Query the configured image scanner for the known vulnerabilities of each image (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+					"max_vulnerabilities": {
+						Type:        "integer",
+						Description: "Maximum number of vulnerabilities listed for each image, the most severe first, all of them are counted (Optional)",
+						Default:     api.ToRawMessage(kubernetes.ImagesInventoryMaxVulnerabilitiesDefault),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Images: Inventory",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: imagesInventory, Permissions: []api.ResourcePermission{listPods, listReplicaSets}},
+	}
+}
+
+type imagesInventoryArgs struct {
+	Namespace          string `json:"namespace"`
+	Vulnerabilities    bool   `json:"vulnerabilities"`
+	MaxVulnerabilities int    `json:"max_vulnerabilities"`
+}
+
+func imagesInventory(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[imagesInventoryArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list images, %w", err)), nil
+	}
+	inventory, err := params.ImagesInventory(params, kubernetes.ImagesInventoryOptions{
+		Namespace:          args.Namespace,
+		Vulnerabilities:    args.Vulnerabilities,
+		MaxVulnerabilities: args.MaxVulnerabilities,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list images: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(inventory)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list images: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+inventory.Summary+"\n"+marshalled, nil), nil
+}
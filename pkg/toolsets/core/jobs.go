@@ -0,0 +1,221 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	jobsActionRun     = "run"
+	jobsActionTrigger = "trigger"
+)
+
+func initJobs() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "jobs",
+			Description: "Run a Kubernetes Job in the current or provided namespace. " +
+				"Supported actions: 'run' creates a Job running the provided command in the provided image, " +
+				"'trigger' creates a Job from the template of a CronJob to run it now (like kubectl create job --from=cronjob/<name>). " +
+				"By default the tool waits for the Job to complete and returns its status, the explained container terminations, and the logs of its last Pod",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"action": {
+						Type:        "string",
+						Description: "Action to perform",
+						Enum:        []any{jobsActionRun, jobsActionTrigger},
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Job (Optional, current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Job to create with the 'run' action (Optional, a random name is generated if not provided)",
+					},
+					"image": {
+						Type:        "string",
+						Description: "Container image of the Job, required by the 'run' action",
+					},
+					"command": {
+						Type:        "array",
+						Description: "Command and arguments run by the Job container with the 'run' action, e.g. [\"sh\", \"-c\", \"echo hello\"] (Optional, the image entrypoint if not provided)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"backoff_limit": {
+						Type:        "integer",
+						Description: "Number of retries before the Job created with the 'run' action is considered failed (Optional)",
+						Default:     api.ToRawMessage(0),
+						Minimum:     ptr.To(float64(0)),
+					},
+					"ttl_seconds_after_finished": {
+						Type:        "integer",
+						Description: "Delete the Job created with the 'run' action and its Pods the provided number of seconds after it finishes (Optional, the Job is kept if not provided)",
+						Minimum:     ptr.To(float64(0)),
+					},
+					"cronjob": {
+						Type:        "string",
+						Description: "Name of the CronJob to run now, required by the 'trigger' action",
+					},
+					"wait": {
+						Type:        "boolean",
+						Description: "Wait for the Job to complete and return the logs of its last Pod (Optional)",
+						Default:     api.ToRawMessage(true),
+					},
+					"timeout": {
+						Type:        "integer",
+						Description: "Maximum number of seconds to wait for the Job to complete when wait is set (Optional)",
+						Default:     api.ToRawMessage(300),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+				Required: []string{"action"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Jobs: Run",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: jobs, Permissions: []api.ResourcePermission{createJobs, getJobs, listPods, getPodsLog}, DryRunSupported: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "jobs_failures",
+			Description: "List the Kubernetes Jobs that failed recently in the current or provided namespace, most recent first, " +
+				"with the reason of the failure (e.g. BackoffLimitExceeded, DeadlineExceeded), the CronJob that created them, " +
+				"and the explained container terminations (exit codes, OOMKilled) of their Pods",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to list the failed Jobs from (Optional, all namespaces if not provided)",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Only list the Jobs that failed within the provided duration, e.g. 30m, 6h, 168h (Optional, 0 lists all the failed Jobs)",
+						Default:     api.ToRawMessage("24h"),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Jobs: List Failures",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: jobsFailures, Permissions: []api.ResourcePermission{listJobs, listPods}},
+	}
+}
+
+type jobsArgs struct {
+	Action                  string   `json:"action"`
+	Namespace               string   `json:"namespace"`
+	Name                    string   `json:"name"`
+	Image                   string   `json:"image"`
+	Command                 []string `json:"command"`
+	BackoffLimit            int32    `json:"backoff_limit"`
+	TTLSecondsAfterFinished *int32   `json:"ttl_seconds_after_finished"`
+	CronJob                 string   `json:"cronjob"`
+	Wait                    bool     `json:"wait"`
+	Timeout                 int64    `json:"timeout"`
+}
+
+func jobs(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[jobsArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to run job, %w", err)), nil
+	}
+	timeout := time.Duration(args.Timeout) * time.Second
+	var status *kubernetes.JobStatus
+	switch args.Action {
+	case jobsActionRun:
+		if args.Image == "" {
+			return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to run job, missing argument image"))), nil
+		}
+		status, err = params.JobsRun(params, kubernetes.JobsRunOptions{
+			Namespace:               args.Namespace,
+			Name:                    args.Name,
+			Image:                   args.Image,
+			Command:                 args.Command,
+			BackoffLimit:            args.BackoffLimit,
+			TTLSecondsAfterFinished: args.TTLSecondsAfterFinished,
+			Wait:                    args.Wait,
+			Timeout:                 timeout,
+		})
+	case jobsActionTrigger:
+		if args.CronJob == "" {
+			return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to run job, missing argument cronjob"))), nil
+		}
+		status, err = params.JobsTrigger(params, kubernetes.JobsTriggerOptions{
+			Namespace: args.Namespace,
+			CronJob:   args.CronJob,
+			Wait:      args.Wait,
+			Timeout:   timeout,
+		})
+	default:
+		return api.NewToolCallResult("", fmt.Errorf("failed to run job, invalid action %q, supported actions are %s and %s", args.Action, jobsActionRun, jobsActionTrigger)), nil
+	}
+	if status == nil && err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to run job: %w", err)), nil
+	}
+	marshalled, marshalErr := output.MarshalYaml(status)
+	if marshalErr != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to run job: %v", marshalErr)), nil
+	}
+	if err != nil {
+		// The Job was created, but it didn't complete in time
+		return api.NewToolCallResult("", fmt.Errorf("%v\n%s", err, marshalled)), nil
+	}
+	var summary string
+	switch {
+	case kubernetes.IsDryRun(params):
+		summary = fmt.Sprintf("# Job %s/%s validated with server-side dry-run", status.Namespace, status.Name)
+	case !args.Wait:
+		summary = fmt.Sprintf("# Job %s/%s created", status.Namespace, status.Name)
+	default:
+		summary = fmt.Sprintf("# Job %s/%s %s", status.Namespace, status.Name, status.Status)
+	}
+	return api.NewToolCallResult(summary+"\n"+marshalled, nil), nil
+}
+
+type jobsFailuresArgs struct {
+	Namespace string `json:"namespace"`
+	Since     string `json:"since"`
+}
+
+func jobsFailures(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[jobsFailuresArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list job failures, %w", err)), nil
+	}
+	var since time.Duration
+	if args.Since != "" {
+		if since, err = time.ParseDuration(args.Since); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list job failures, invalid since duration: %w", err)), nil
+		}
+	}
+	failures, err := params.JobsFailures(params, args.Namespace, since.Abs())
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list job failures: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(failures)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list job failures: %w", err)), nil
+	}
+	summary := fmt.Sprintf("# %d failed Jobs found", len(failures))
+	if since > 0 {
+		summary += " in the last " + since.Abs().String()
+	}
+	return api.NewToolCallResult(summary+"\n"+marshalled, nil), nil
+}
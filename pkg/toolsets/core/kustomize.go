@@ -0,0 +1,118 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kustomize"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initKustomize() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "kustomize_build",
+			Description: "Render Kubernetes manifests from a kustomization (equivalent to `kustomize build` or `kubectl kustomize`). " +
+				"The kustomization can be provided inline (with any additional referenced files) or as a remote URL. " +
+				"The rendered manifests are applied to the cluster with kustomize_apply",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: kustomizationProperties(),
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Kustomize: Build",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: kustomizeBuild},
+		{Tool: api.Tool{
+			Name: "kustomize_apply",
+			Description: "Render Kubernetes manifests from a kustomization (equivalent to `kubectl apply -k`) and apply them to the current cluster using server-side apply. " +
+				"The kustomization can be provided inline (with any additional referenced files) or as a remote URL",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: kustomizationProperties(),
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Kustomize: Apply",
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: kustomizeApply, DryRunSupported: ptr.To(true), Manifest: kustomizeRender},
+	}
+}
+
+func kustomizationProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"kustomization": {
+			Type:        "string",
+			Description: "Inline content of the kustomization.yaml file (Optional, required if url is not provided)",
+		},
+		"files": {
+			Type:        "object",
+			Description: "Additional files referenced by the inline kustomization (resources, patches, generator sources...) keyed by their path relative to the kustomization root, e.g. {\"deployment.yaml\": \"apiVersion: apps/v1\\nkind: Deployment...\"} (Optional)",
+			AdditionalProperties: &jsonschema.Schema{
+				Type: "string",
+			},
+		},
+		"url": {
+			Type:        "string",
+			Description: "Remote kustomization to build, e.g. https://github.com/kubernetes-sigs/kustomize//examples/helloWorld?ref=v5.0.0 (Optional, ignored if kustomization is provided)",
+		},
+	}
+}
+
+// kustomizeRender builds the kustomization of the arguments, returning the rendered manifests
+func kustomizeRender(arguments map[string]any) (string, error) {
+	options := kustomize.BuildOptions{}
+	options.Kustomization, _ = arguments["kustomization"].(string)
+	options.URL, _ = arguments["url"].(string)
+	if options.Kustomization == "" && options.URL == "" {
+		return "", api.InvalidArgument(errors.New("failed to build kustomization, missing argument kustomization or url"))
+	}
+	if files, ok := arguments["files"].(map[string]any); ok {
+		options.Files = make(map[string]string, len(files))
+		for name, content := range files {
+			c, ok := content.(string)
+			if !ok {
+				return "", fmt.Errorf("failed to build kustomization, file %s content is not a string", name)
+			}
+			options.Files[name] = c
+		}
+	}
+	rendered, err := kustomize.Build(options)
+	if err != nil {
+		return "", fmt.Errorf("failed to build kustomization: %w", err)
+	}
+	return rendered, nil
+}
+
+func kustomizeBuild(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	rendered, err := kustomizeRender(params.GetArguments())
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	return api.NewToolCallResult(rendered, nil), nil
+}
+
+func kustomizeApply(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	rendered, err := kustomizeRender(params.GetArguments())
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	resources, err := params.ResourcesCreateOrUpdate(params, rendered)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to apply kustomization: %w", err)), nil
+	}
+	marshalledYaml, err := output.MarshalYaml(resources)
+	if err != nil {
+		err = fmt.Errorf("failed to apply kustomization: %w", err)
+	}
+	return api.NewToolCallResult("# The following resources (YAML) have been created or updated successfully\n"+marshalledYaml, err), nil
+}
@@ -2,13 +2,16 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/jsonschema-go/jsonschema"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
 func initNamespaces(o internalk8s.Openshift) []api.ServerTool {
@@ -26,7 +29,44 @@ func initNamespaces(o internalk8s.Openshift) []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: namespacesList,
+		}, Handler: namespacesList, Permissions: []api.ResourcePermission{listNamespaces},
+	})
+	ret = append(ret, api.ServerTool{
+		Tool: api.Tool{
+			Name: "namespaces",
+			Description: "Manage the lifecycle of a Kubernetes namespace in the current cluster. " +
+				"Supported actions: 'create' creates a new namespace, 'delete' deletes an existing namespace (and all of its resources), " +
+				"'diagnose' lists the remaining resources, finalizers, and conditions that are blocking the deletion of a namespace stuck in the Terminating phase",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"action": {
+						Type:        "string",
+						Description: "Action to perform on the namespace",
+						Enum:        []any{namespaceActionCreate, namespaceActionDelete, namespaceActionDiagnose},
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the namespace",
+					},
+					"labels": {
+						Type:        "object",
+						Description: "Labels to set on the namespace (Optional, only applicable to the create action)",
+						AdditionalProperties: &jsonschema.Schema{
+							Type: "string",
+						},
+					},
+				},
+				Required: []string{"action", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Namespaces: Manage",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: namespaces, Permissions: []api.ResourcePermission{createNamespaces, deleteNamespaces}, DryRunSupported: ptr.To(true),
 	})
 	if o.IsOpenShift(context.Background()) {
 		ret = append(ret, api.ServerTool{
@@ -42,7 +82,7 @@ func initNamespaces(o internalk8s.Openshift) []api.ServerTool {
 					DestructiveHint: ptr.To(false),
 					OpenWorldHint:   ptr.To(true),
 				},
-			}, Handler: projectsList,
+			}, Handler: projectsList, Permissions: []api.ResourcePermission{listProjects},
 		})
 	}
 	return ret
@@ -51,15 +91,71 @@ func initNamespaces(o internalk8s.Openshift) []api.ServerTool {
 func namespacesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	ret, err := params.NamespacesList(params, internalk8s.ResourceListOptions{AsTable: params.ListOutput.AsTable()})
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list namespaces: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list namespaces: %w", err)), nil
 	}
 	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
 }
 
+const (
+	namespaceActionCreate   = "create"
+	namespaceActionDelete   = "delete"
+	namespaceActionDiagnose = "diagnose"
+)
+
+func namespaces(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	action, _ := params.GetArguments()["action"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to manage namespace, missing argument name"))), nil
+	}
+	switch action {
+	case namespaceActionCreate:
+		labels := map[string]string{}
+		if l, ok := params.GetArguments()["labels"].(map[string]any); ok {
+			for key, value := range l {
+				labels[key] = fmt.Sprintf("%v", value)
+			}
+		}
+		ns, err := params.NamespacesCreate(params, name, labels)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to create namespace %s: %w", name, err)), nil
+		}
+		ns.ManagedFields = nil
+		marshalledYaml, err := output.MarshalYaml(ns)
+		if err != nil {
+			err = fmt.Errorf("failed to create namespace %s: %w", name, err)
+		}
+		return api.NewToolCallResult("# The following namespace (YAML) has been created successfully\n"+marshalledYaml, err), nil
+	case namespaceActionDelete:
+		if err := params.NamespacesDelete(params, name); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to delete namespace %s: %w", name, err)), nil
+		}
+		return api.NewToolCallResult(fmt.Sprintf("Namespace %s deletion requested successfully, "+
+			"use the diagnose action if the namespace remains in the Terminating phase", name), nil), nil
+	case namespaceActionDiagnose:
+		diagnostic, err := params.NamespacesDiagnoseTerminating(params, name)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to diagnose namespace %s: %w", name, err)), nil
+		}
+		marshalledYaml, err := output.MarshalYaml(diagnostic)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to diagnose namespace %s: %w", name, err)), nil
+		}
+		header := fmt.Sprintf("# Namespace %s is not terminating (phase: %s)\n", name, diagnostic.Phase)
+		if diagnostic.Phase == v1.NamespaceTerminating {
+			header = fmt.Sprintf("# Namespace %s is terminating, %d remaining resources\n", name, len(diagnostic.RemainingResources))
+		}
+		return api.NewToolCallResult(header+marshalledYaml, nil), nil
+	default:
+		return api.NewToolCallResult("", fmt.Errorf("failed to manage namespace, unsupported action %q, supported actions: %s, %s, %s",
+			action, namespaceActionCreate, namespaceActionDelete, namespaceActionDiagnose)), nil
+	}
+}
+
 func projectsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	ret, err := params.ProjectsList(params, internalk8s.ResourceListOptions{AsTable: params.ListOutput.AsTable()})
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list projects: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list projects: %w", err)), nil
 	}
 	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
 }
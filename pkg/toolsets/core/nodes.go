@@ -1,19 +1,24 @@
 package core
 
 import (
-	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/kubectl/pkg/metricsutil"
+	metricsapi "k8s.io/metrics/pkg/apis/metrics"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
 func initNodes() []api.ServerTool {
@@ -38,6 +43,12 @@ func initNodes() []api.ServerTool {
 						Default:     api.ToRawMessage(100),
 						Minimum:     ptr.To(float64(0)),
 					},
+					"artifact": {
+						Type: "boolean",
+						Description: "Write the full log to a server-side artifact exposed as an MCP resource and only return its URI and the last lines of the log (Optional). " +
+							"Use it for very large logs (e.g. tailLines=0) instead of returning the whole log",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
 				},
 				Required: []string{"name", "query"},
 			},
@@ -47,27 +58,244 @@ func initNodes() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: nodesLog},
+		}, Handler: nodesLog, Permissions: []api.ResourcePermission{getNodes, getNodesProxy}},
 		{Tool: api.Tool{
-			Name:        "nodes_stats_summary",
-			Description: "Get detailed resource usage statistics from a Kubernetes node via the kubelet's Summary API. Provides comprehensive metrics including CPU, memory, filesystem, and network usage at the node, pod, and container levels. On systems with cgroup v2 and kernel 4.20+, also includes PSI (Pressure Stall Information) metrics that show resource pressure for CPU, memory, and I/O. See https://kubernetes.io/docs/reference/instrumentation/understand-psi-metrics/ for details on PSI metrics",
+			Name: "nodes_journal",
+			Description: "Get the journal (journalctl) entries of a systemd unit of a Kubernetes node, e.g. kubelet, crio, or containerd. " +
+				"Reads the journal of the node through a short-lived privileged helper pod, " +
+				"use it when nodes_log fails because the kubelet log query API is not enabled",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to get the journal from",
+					},
+					"unit": {
+						Type:        "string",
+						Description: "Systemd unit to get the journal entries of",
+						Examples:    []any{"kubelet", "crio", "containerd"},
+					},
+					"since": {
+						Type:        "string",
+						Description: "Only return the entries since the provided time, in any of the journalctl formats, e.g. \"2025-01-01 10:00:00\", \"-1h\", \"today\" (Optional)",
+					},
+					"until": {
+						Type:        "string",
+						Description: "Only return the entries until the provided time, in any of the journalctl formats (Optional)",
+					},
+					"lines": {
+						Type:        "integer",
+						Description: "Number of most recent entries to retrieve (Optional, 0 means all entries)",
+						Default:     api.ToRawMessage(kubernetes.DefaultTailLines),
+						Minimum:     ptr.To(float64(0)),
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+				Required: []string{"name", "unit"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Node: Journal",
+				ReadOnlyHint:    ptr.To(false), // Creates a helper pod on the node
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesJournal, Permissions: nodeHelperPodPermissions()},
+		{Tool: api.Tool{
+			Name: "nodes_runtime_info",
+			Description: "Get the state of the container runtime (containerd, CRI-O) of a Kubernetes node with crictl. " +
+				"Lists the runtime containers, including the exited ones and the ones whose Pod is no longer known by the API server (orphaned), " +
+				"lists the runtime images, or inspects a container. " +
+				"Runs the crictl of the node through a short-lived privileged helper pod, the runtime socket is detected automatically",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to get the container runtime state from",
+					},
+					"operation": {
+						Type:        "string",
+						Description: "Operation to perform: list the runtime 'containers', list the runtime 'images', or 'inspect' the container with the provided container_id (Optional, default containers)",
+						Enum:        []any{kubernetes.NodeRuntimeContainers, kubernetes.NodeRuntimeImages, kubernetes.NodeRuntimeInspect},
+						Default:     api.ToRawMessage(kubernetes.NodeRuntimeContainers),
+					},
+					"container_id": {
+						Type:        "string",
+						Description: "ID (or unique ID prefix) of the runtime container to inspect (only applicable to the inspect operation)",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Only list the containers of the Pods in the provided namespace (Optional, only applicable to the containers operation)",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Node: Runtime Info",
+				ReadOnlyHint:    ptr.To(false), // Creates a helper pod on the node
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesRuntimeInfo, Permissions: nodeHelperPodPermissions()},
+		{Tool: api.Tool{
+			Name: "nodes_disk_usage",
+			Description: "Troubleshoot the disk usage (DiskPressure) of a Kubernetes node. " +
+				"Combines the node, image, and container filesystem usage reported by the kubelet Summary API with the size of the provided node directories (du) " +
+				"and the size of the container runtime images (crictl images), flagging the images used by a container and the space an image garbage collection reclaims. " +
+				"Optionally removes the images not used by any container (crictl rmi --prune). " +
+				"Runs through a short-lived privileged helper pod with the node root filesystem mounted read-only",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
 					"name": {
 						Type:        "string",
-						Description: "Name of the node to get stats from",
+						Description: "Name of the node to report the disk usage of",
 					},
+					"paths": {
+						Type: "array",
+						Description: "Absolute node paths whose size is measured (Optional, defaults to " +
+							strings.Join(kubernetes.NodeDiskUsageDefaultPaths, ", ") + ")",
+						Items: &jsonschema.Schema{Type: "string"},
+					},
+					"prune_images": {
+						Type:        "boolean",
+						Description: "Remove the container runtime images not used by any container, including the exited ones, to reclaim disk space (Optional, default false)",
+						Default:     api.ToRawMessage(false),
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
 				},
 				Required: []string{"name"},
 			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Node: Disk Usage",
+				ReadOnlyHint:    ptr.To(false), // Creates a helper pod on the node, and removes the unused images with prune_images
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesDiskUsage, Permissions: slices.Concat(nodeHelperPodPermissions(), []api.ResourcePermission{getNodesProxy})},
+		{Tool: api.Tool{
+			Name:        "nodes_stats_summary",
+			Description: "Get detailed resource usage statistics from a Kubernetes node (or all nodes) via the kubelet's Summary API. Provides comprehensive metrics including CPU, memory, filesystem, and network usage at the node, pod, and container levels. On systems with cgroup v2 and kernel 4.20+, also includes PSI (Pressure Stall Information) metrics that show resource pressure for CPU, memory, and I/O. See https://kubernetes.io/docs/reference/instrumentation/understand-psi-metrics/ for details on PSI metrics. When querying multiple nodes, nodes whose kubelet is unreachable are reported separately without failing the whole request",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to get stats from (Optional, all Nodes if not provided)",
+					},
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, only applicable when name is not provided)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
 			Annotations: api.ToolAnnotations{
 				Title:           "Node: Stats Summary",
 				ReadOnlyHint:    ptr.To(true),
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: nodesStatsSummary},
+		}, Handler: nodesStatsSummary, Permissions: []api.ResourcePermission{listNodes, getNodesProxy}},
+		{Tool: api.Tool{
+			Name: "nodes_pressure_report",
+			Description: "Rank the Kubernetes nodes (all nodes or the ones matching a label selector) by resource pressure, using the PSI (Pressure Stall Information) " +
+				"of CPU, memory and I/O (some and full, avg10 and avg60) reported by the kubelet Summary API, and flag the nodes under pressure. " +
+				"PSI requires cgroup v2, Linux 4.20+ and the KubeletPSI feature gate, nodes without PSI or whose kubelet is unreachable are reported separately. " +
+				"See https://kubernetes.io/docs/reference/instrumentation/understand-psi-metrics/ for details on PSI metrics",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, all Nodes if not provided)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"threshold": {
+						Type:        "number",
+						Description: "Percentage of stalled time (avg10 or avg60 of any resource) above which a node is considered under pressure",
+						Default:     api.ToRawMessage(10),
+						Minimum:     ptr.To(float64(0)),
+						Maximum:     ptr.To(float64(100)),
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Pressure Report",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesPressureReport, Permissions: []api.ResourcePermission{listNodes, getNodesProxy}},
+		{Tool: api.Tool{
+			Name: "nodes_reservations",
+			Description: "Report the resources reserved by the Kubernetes nodes (all nodes or the ones matching a label selector) for the system and Kubernetes daemons " +
+				"(systemReserved, kubeReserved) and their eviction thresholds (evictionHard, evictionSoft), read from the kubelet configuration (configz), " +
+				"and compare the thresholds with the available memory, disk, inodes and PIDs reported by the kubelet Summary API, " +
+				"flagging the nodes that exceed or are close to an eviction threshold",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to report (Optional, all Nodes if not provided)",
+					},
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, only applicable when name is not provided)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"margin": {
+						Type:        "number",
+						Description: "Percentage of the capacity above an eviction threshold within which a node is flagged as close to the threshold",
+						Default:     api.ToRawMessage(kubernetes.DefaultEvictionMargin),
+						Minimum:     ptr.To(float64(0)),
+						Maximum:     ptr.To(float64(100)),
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Reservations",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesReservations, Permissions: []api.ResourcePermission{listNodes, getNodes, getNodesProxy}},
+		{Tool: api.Tool{
+			Name: "nodes_os_inventory",
+			Description: "Summarize the operating systems, OS images, kernel versions, container runtime versions and kubelet versions " +
+				"of the Kubernetes nodes (all nodes or the ones matching a label selector), with the number of nodes running each version. " +
+				"Flags the outliers, the nodes whose versions differ from most nodes with the same operating system (e.g. a node left behind by an upgrade), " +
+				"and the kubelet version skew (kubelets newer than the API server or older than the version skew policy allows, mixed kubelet minor versions)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, all Nodes if not provided)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: OS Inventory",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesOSInventory, Permissions: []api.ResourcePermission{listNodes}},
 		{Tool: api.Tool{
 			Name:        "nodes_top",
 			Description: "List the resource consumption (CPU and memory) as recorded by the Kubernetes Metrics Server for the specified Kubernetes Nodes or all nodes in the cluster",
@@ -83,6 +311,7 @@ func initNodes() []api.ServerTool {
 						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, only applicable when name is not provided)",
 						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
 					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
 				},
 			},
 			Annotations: api.ToolAnnotations{
@@ -92,18 +321,226 @@ func initNodes() []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: nodesTop},
+		}, Handler: nodesTop, Permissions: []api.ResourcePermission{listNodeMetrics}},
+		{Tool: api.Tool{
+			Name: "nodes_sysctl",
+			Description: "Audit kernel parameters (sysctls) of a Kubernetes node (or all nodes) and flag the values lower than the recommended thresholds for common workloads " +
+				"(" + strings.Join(sysctlThresholdsDescription(), ", ") + "). " +
+				"The values are read by a short-lived helper pod (or DaemonSet when auditing multiple nodes) running in the node host network namespace",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to audit (Optional, all Nodes if not provided)",
+					},
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, only applicable when name is not provided)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"sysctls": {
+						Type:        "array",
+						Description: "Names of the kernel parameters to read, e.g. [\"net.core.somaxconn\"] (Optional, defaults to the parameters with a recommended threshold)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"thresholds": {
+						Type:                 "object",
+						Description:          "Minimum value for kernel parameters, overrides the recommended thresholds, e.g. {\"vm.max_map_count\": 1048576} (Optional)",
+						AdditionalProperties: &jsonschema.Schema{Type: "integer"},
+					},
+					"format": findings.FormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Sysctl",
+				ReadOnlyHint:    ptr.To(false), // Creates a helper pod (or DaemonSet) on the nodes
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesSysctl, Permissions: nodeHelperPodPermissions()},
+		{Tool: api.Tool{
+			Name: "nodes_drain_preview",
+			Description: "Preview the drain of a Kubernetes node (kubectl drain --ignore-daemonsets) without performing it. " +
+				"Lists the pods that would be evicted or left on the node, the evictions blocked by PodDisruptionBudgets, the pods with local storage (emptyDir) or without controller, " +
+				"how the affected workloads are distributed across the cluster, and the nodes where the replacements are likely to be scheduled",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to preview the drain for",
+					},
+					"format": findings.FormatProperty(),
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Drain Preview",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesDrainPreview, Permissions: []api.ResourcePermission{getNodes, listAllPods, listAllPodDisruptionBudgets}},
+		{Tool: api.Tool{
+			Name: "nodes_pods",
+			Description: "List the pods scheduled on a Kubernetes node (in all namespaces) with their phase, restarts, " +
+				"effective CPU and memory requests and limits, and QoS class. Useful together with nodes_top and nodes_stats_summary " +
+				"to find the pods responsible for the resource consumption or pressure of a node",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to list the pods of",
+					},
+					"sortBy": {
+						Type:        "string",
+						Description: "Sort order of the pods, by namespace and name, or by CPU or memory request with the largest first (Optional, defaults to name)",
+						Enum:        []any{kubernetes.NodePodsSortByName, kubernetes.NodePodsSortByCPU, kubernetes.NodePodsSortByMemory},
+						Default:     api.ToRawMessage(kubernetes.NodePodsSortByName),
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Pods",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesPods, Permissions: []api.ResourcePermission{getNodes, listAllPods}},
+		{Tool: api.Tool{
+			Name: "nodes_why_notready",
+			Description: "Explain why a Kubernetes node is NotReady. Gathers the node conditions, the kubelet heartbeat lag (node Lease), " +
+				"the tail of the kubelet log (through the API server proxy), the memory, disk and PID pressure (conditions and PSI from the kubelet Summary API), " +
+				"and the status of the network plugin (CNI) pods on the node, and combines them into a list of hypotheses ranked by likelihood, " +
+				"each with its evidence and the suggested next troubleshooting step",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to troubleshoot",
+					},
+					"tailLines": {
+						Type:        "integer",
+						Description: "Number of lines of the kubelet log to analyze",
+						Default:     api.ToRawMessage(kubernetes.DefaultNotReadyTailLines),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Why NotReady",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesWhyNotReady, Permissions: []api.ResourcePermission{getNodes, getNodesProxy, getLeases, listAllPods}},
+		{Tool: api.Tool{
+			Name: "node_files",
+			Description: "List, get, or put files on a Kubernetes node through a short-lived privileged helper pod with the node root filesystem mounted. " +
+				"'get' returns the content of a node file (inline=true) or copies it to the artifact store (artifact:// resource) or the local filesystem of the MCP server, 'put' copies a local file of the MCP server to the node, skipped if the node file already has the same sha256 checksum and verified after the copy. " +
+				"Use read_only=true to mount the node root filesystem read-only when only inspecting the node (list and get). " +
+				"Windows nodes are accessed through a HostProcess helper pod running PowerShell, with Windows node paths (e.g. C:\\var\\log)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to access",
+					},
+					"operation": {
+						Type:        "string",
+						Description: "Operation to perform: 'list' the node directory (or file) at source_path, 'get' the node file at source_path inline, into the artifact store, or into the local dest_path, or 'put' the local file at source_path into the node dest_path",
+						Enum:        []any{kubernetes.NodeFilesList, kubernetes.NodeFilesGet, kubernetes.NodeFilesPut},
+					},
+					"source_path": {
+						Type:        "string",
+						Description: "Absolute node path to list or get (e.g. /var/log, or C:\\var\\log on Windows nodes), or local path of the file to put",
+					},
+					"dest_path": {
+						Type:        "string",
+						Description: "Local path where the node file is written (get, Optional, the file is stored as an artifact listed by artifacts_list if empty), or absolute node path of the file to put (put)",
+					},
+					"inline": {
+						Type: "boolean",
+						Description: fmt.Sprintf("Return the content of the node file in the result instead of storing it (get, Optional, default false), "+
+							"only for files up to %d KiB, binary files require base64=true", kubernetes.NodeFilesMaxInlineSize/1024),
+						Default: api.ToRawMessage(false),
+					},
+					"base64": {
+						Type:        "boolean",
+						Description: "Return the inline content base64 encoded (get with inline=true, Optional, default false)",
+						Default:     api.ToRawMessage(false),
+					},
+					"read_only": {
+						Type:        "boolean",
+						Description: "Mount the node root filesystem read-only in the helper pod, only the list and get operations are allowed (Optional, default false)",
+						Default:     api.ToRawMessage(false),
+					},
+					"tolerations": {
+						Type: "array",
+						Description: "Tolerations of the helper pod, required for nodes with NoExecute taints, " +
+							"e.g. [{\"operator\": \"Exists\"}] to tolerate every taint (Optional)",
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"key":      {Type: "string", Description: "Taint key to tolerate (Optional, all the keys with the Exists operator if empty)"},
+								"operator": {Type: "string", Description: "Toleration operator (Optional, defaults to Equal)", Enum: []any{"Exists", "Equal"}},
+								"value":    {Type: "string", Description: "Taint value matched by the Equal operator (Optional)"},
+								"effect":   {Type: "string", Description: "Taint effect to tolerate (Optional, all the effects if empty)", Enum: []any{"NoSchedule", "PreferNoSchedule", "NoExecute"}},
+							},
+						},
+					},
+					"image_pull_secret": {
+						Type:        "string",
+						Description: "Name of an image pull secret of the helper pod namespace to pull the helper image from a private registry (Optional, added to the configured helper_image_pull_secrets)",
+					},
+					"resources": {
+						Type:        "object",
+						Description: "Compute resource requests and limits of the helper pod, e.g. {\"requests\": {\"cpu\": \"10m\", \"memory\": \"16Mi\"}, \"limits\": {\"memory\": \"64Mi\"}} (Optional)",
+						Properties: map[string]*jsonschema.Schema{
+							"requests": {Type: "object", Description: "Resource requests (cpu, memory)", AdditionalProperties: &jsonschema.Schema{Type: "string"}},
+							"limits":   {Type: "object", Description: "Resource limits (cpu, memory)", AdditionalProperties: &jsonschema.Schema{Type: "string"}},
+						},
+					},
+				},
+				Required: []string{"name", "operation", "source_path"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Files",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true), // put overwrites node files
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodeFiles, Permissions: nodeHelperPodPermissions()},
+	}
+}
+
+func sysctlThresholdsDescription() []string {
+	var ret []string
+	for _, threshold := range kubernetes.DefaultSysctlThresholds {
+		ret = append(ret, fmt.Sprintf("%s >= %d", threshold.Name, threshold.Min))
 	}
+	return ret
 }
 
 func nodesLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	name, ok := params.GetArguments()["name"].(string)
 	if !ok || name == "" {
-		return api.NewToolCallResult("", errors.New("failed to get node log, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get node log, missing argument name"))), nil
 	}
 	query, ok := params.GetArguments()["query"].(string)
 	if !ok || query == "" {
-		return api.NewToolCallResult("", errors.New("failed to get node log, missing argument query")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get node log, missing argument query"))), nil
 	}
 	tailLines := params.GetArguments()["tailLines"]
 	var tailInt int64
@@ -111,55 +548,631 @@ func nodesLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		var err error
 		tailInt, err = api.ParseInt64(tailLines)
 		if err != nil {
-			return api.NewToolCallResult("", fmt.Errorf("failed to parse tailLines parameter: %w", err)), nil
+			return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to parse tailLines parameter: %w", err))), nil
 		}
 	}
 	ret, err := params.NodesLog(params, name, query, tailInt)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get node log for %s: %v", name, err)), nil
-	} else if ret == "" {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get node log for %s: %w", name, err)), nil
+	}
+	lines := make([]string, 0)
+	if ret != "" {
+		lines = strings.Split(strings.TrimSuffix(ret, "\n"), "\n")
+	}
+	envelope := &api.Envelope{
+		Kind:      "NodeLog",
+		Items:     lines,
+		Summary:   fmt.Sprintf("%d lines of %s logs from node %s", len(lines), query, name),
+		Truncated: tailInt > 0 && int64(len(lines)) >= tailInt,
+	}
+	if ret == "" {
 		ret = fmt.Sprintf("The node %s has not logged any message yet or the log file is empty", name)
+	} else if artifact, _ := params.GetArguments()["artifact"].(bool); artifact {
+		summary, preview, err := storeLogArtifact(params, ret, "node-log", name, query)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to store node log for %s: %w", name, err)), nil
+		}
+		envelope.Items, envelope.Summary, envelope.Truncated = preview, summary, true
+		ret = logArtifactText(summary, preview)
 	}
-	return api.NewToolCallResult(ret, nil), nil
+	return api.NewStructuredToolCallResult(params, envelope, ret), nil
+}
+
+type nodesJournalArgs struct {
+	Name  string `json:"name"`
+	Unit  string `json:"unit"`
+	Since string `json:"since"`
+	Until string `json:"until"`
+	Lines int64  `json:"lines"`
+}
+
+func nodesJournal(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesJournalArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get node journal, %w", err)), nil
+	}
+	ret, err := params.NodesJournal(params, kubernetes.NodesJournalOptions{
+		NodeName: args.Name,
+		Unit:     args.Unit,
+		Since:    args.Since,
+		Until:    args.Until,
+		Lines:    args.Lines,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get journal of unit %s on node %s: %w", args.Unit, args.Name, err)), nil
+	}
+	lines := make([]string, 0)
+	if ret != "" {
+		lines = strings.Split(strings.TrimSuffix(ret, "\n"), "\n")
+	}
+	envelope := &api.Envelope{
+		Kind:      "NodeJournal",
+		Items:     lines,
+		Summary:   fmt.Sprintf("%d lines of %s journal from node %s", len(lines), args.Unit, args.Name),
+		Truncated: args.Lines > 0 && int64(len(lines)) >= args.Lines,
+	}
+	if ret == "" {
+		ret = fmt.Sprintf("The unit %s has no journal entries on node %s", args.Unit, args.Name)
+	}
+	return api.NewStructuredToolCallResult(params, envelope, ret), nil
+}
+
+type nodesRuntimeInfoArgs struct {
+	Name        string `json:"name"`
+	Operation   string `json:"operation"`
+	ContainerID string `json:"container_id"`
+	Namespace   string `json:"namespace"`
+}
+
+func nodesRuntimeInfo(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesRuntimeInfoArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get node runtime info, %w", err)), nil
+	}
+	nodeRuntime, err := params.NodesRuntime(params, args.Name, args.Operation, args.ContainerID)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get runtime %s of node %s: %w", args.Operation, args.Name, err)), nil
+	}
+	if args.Namespace != "" {
+		nodeRuntime.Containers = slices.DeleteFunc(nodeRuntime.Containers, func(c kubernetes.RuntimeContainer) bool {
+			return c.PodNamespace != args.Namespace
+		})
+	}
+	envelope := &api.Envelope{Summary: nodeRuntime.Summary()}
+	switch args.Operation {
+	case kubernetes.NodeRuntimeImages:
+		envelope.Kind, envelope.Items = "RuntimeImage", nodeRuntime.Images
+	case kubernetes.NodeRuntimeInspect:
+		envelope.Kind, envelope.Items = "RuntimeContainerStatus", []any{nodeRuntime.Inspect}
+	default:
+		envelope.Kind, envelope.Items = "RuntimeContainer", nodeRuntime.Containers
+	}
+	marshalled, err := output.MarshalYaml(nodeRuntime)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get runtime %s of node %s: %w", args.Operation, args.Name, err)), nil
+	}
+	return api.NewStructuredToolCallResult(params, envelope, "# "+envelope.Summary+"\n"+marshalled), nil
+}
+
+type nodesDiskUsageArgs struct {
+	Name        string   `json:"name"`
+	Paths       []string `json:"paths"`
+	PruneImages bool     `json:"prune_images"`
+}
+
+func nodesDiskUsage(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesDiskUsageArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get node disk usage, %w", err)), nil
+	}
+	usage, err := params.NodesDiskUsage(params, kubernetes.NodeDiskUsageOptions{NodeName: args.Name, Paths: args.Paths, PruneImages: args.PruneImages})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get disk usage of node %s: %w", args.Name, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(usage)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get disk usage of node %s: %w", args.Name, err)), nil
+	}
+	envelope := &api.Envelope{Kind: "NodeDiskUsage", Items: []*kubernetes.NodeDiskUsage{usage}, Summary: usage.Summary()}
+	return api.NewStructuredToolCallResult(params, envelope, "# "+envelope.Summary+"\n"+marshalled), nil
+}
+
+// nodesSelectorArgs are the arguments of the node tools that target a node by name or the nodes matching a selector
+type nodesSelectorArgs struct {
+	Name          string `json:"name"`
+	LabelSelector string `json:"label_selector"`
 }
 
 func nodesStatsSummary(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
-	name, ok := params.GetArguments()["name"].(string)
-	if !ok || name == "" {
-		return api.NewToolCallResult("", errors.New("failed to get node stats summary, missing argument name")), nil
+	args, err := api.ParseArguments[nodesSelectorArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get node stats summary, %w", err)), nil
+	}
+	if args.Name == "" {
+		return nodesStatsSummaries(params, args.LabelSelector)
+	}
+	ret, err := params.NodesStatsSummary(params, args.Name)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get node stats summary for %s: %w", args.Name, err)), nil
+	}
+	envelope := &api.Envelope{
+		Kind:    "NodeStatsSummary",
+		Items:   []nodeStatsSummary{newNodeStatsSummary(args.Name, ret)},
+		Summary: fmt.Sprintf("Stats summary of node %s", args.Name),
+	}
+	return api.NewStructuredToolCallResult(params, envelope, ret), nil
+}
+
+func nodesStatsSummaries(params api.ToolHandlerParams, labelSelector string) (*api.ToolCallResult, error) {
+	summaries, targetErrors, err := params.NodesStatsSummaries(params, labelSelector)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes stats summary: %w", err)), nil
+	}
+	envelope := &api.Envelope{
+		Kind:    "NodeStatsSummary",
+		Items:   make([]nodeStatsSummary, 0, len(summaries)),
+		Summary: fmt.Sprintf("Stats summary of %d nodes", len(summaries)),
+	}
+	if len(summaries) == 0 && len(targetErrors) == 0 {
+		return api.NewStructuredToolCallResult(params, envelope, "No nodes found"), nil
+	}
+	ret := strings.Builder{}
+	for _, summary := range summaries {
+		ret.WriteString(fmt.Sprintf("# Node: %s\n%s\n", summary.Node, strings.TrimSuffix(summary.Summary, "\n")))
+		envelope.Items = append(envelope.Items.([]nodeStatsSummary), newNodeStatsSummary(summary.Node, summary.Summary))
+	}
+	return api.NewStructuredPartialToolCallResult(params, envelope, ret.String(), len(summaries), targetErrors), nil
+}
+
+type nodesPressureReportArgs struct {
+	LabelSelector string  `json:"label_selector"`
+	Threshold     float64 `json:"threshold"`
+}
+
+func nodesPressureReport(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesPressureReportArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes pressure report, %w", err)), nil
+	}
+	pressures, targetErrors, err := params.NodesPressure(params, args.LabelSelector)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes pressure report: %w", err)), nil
+	}
+	underPressure := 0
+	for _, pressure := range pressures {
+		if pressure.Pressure >= args.Threshold {
+			underPressure++
+		}
+	}
+	envelope := &api.Envelope{
+		Kind:    "NodePressure",
+		Items:   pressures,
+		Summary: fmt.Sprintf("%d of %d nodes under pressure (avg10 or avg60 >= %g%%)", underPressure, len(pressures), args.Threshold),
+	}
+	if len(pressures) == 0 && len(targetErrors) == 0 {
+		return api.NewStructuredToolCallResult(params, envelope, "No nodes found"), nil
+	}
+	table := api.NewTable("NODE", "STATUS", "PRESSURE", "RESOURCE", "CPU SOME", "CPU FULL", "MEMORY SOME", "MEMORY FULL", "IO SOME", "IO FULL")
+	for _, pressure := range pressures {
+		status := "OK"
+		if pressure.Pressure >= args.Threshold {
+			status = "UNDER PRESSURE"
+		}
+		row := []any{pressure.Node, status, fmt.Sprintf("%.2f", pressure.Pressure), pressure.Resource}
+		for _, stats := range []*kubernetes.PSIStats{pressure.CPU, pressure.Memory, pressure.IO} {
+			if stats == nil {
+				stats = &kubernetes.PSIStats{}
+			}
+			row = append(row, formatPSIData(stats.Some), formatPSIData(stats.Full))
+		}
+		table.AddRow(row...)
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes pressure report: %w", err)), nil
+	}
+	ret := "# " + envelope.Summary + "\n" + rendered +
+		"# PSI values are avg10/avg60, the percentage of time tasks were stalled waiting for the resource\n"
+	return api.NewStructuredPartialToolCallResult(params, envelope, ret, len(pressures), targetErrors), nil
+}
+
+type nodesReservationsArgs struct {
+	Name          string  `json:"name"`
+	LabelSelector string  `json:"label_selector"`
+	Margin        float64 `json:"margin"`
+}
+
+func nodesReservations(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesReservationsArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes reservations, %w", err)), nil
+	}
+	reservations, targetErrors, err := params.NodesReservations(params, args.Name, args.LabelSelector, args.Margin)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes reservations: %w", err)), nil
+	}
+	flagged := 0
+	for _, r := range reservations {
+		if r.Status == kubernetes.EvictionSignalExceeded || r.Status == kubernetes.EvictionSignalClose {
+			flagged++
+		}
+	}
+	envelope := &api.Envelope{
+		Kind:    "NodeReservations",
+		Items:   reservations,
+		Summary: fmt.Sprintf("%d of %d nodes exceed or are within %g%% of an eviction threshold", flagged, len(reservations), args.Margin),
+	}
+	if len(reservations) == 0 && len(targetErrors) == 0 {
+		return api.NewStructuredToolCallResult(params, envelope, "No nodes found"), nil
+	}
+	signals := api.NewTable("NODE", "STATUS", "SIGNAL", "EVICTION", "THRESHOLD", "AVAILABLE", "CAPACITY", "HEADROOM")
+	reserved := api.NewTable("NODE", "CPU CAPACITY", "CPU ALLOCATABLE", "MEMORY CAPACITY", "MEMORY ALLOCATABLE", "SYSTEM RESERVED", "KUBE RESERVED")
+	for _, r := range reservations {
+		for _, signal := range r.Signals {
+			headroom := "-"
+			if signal.Headroom != nil {
+				headroom = fmt.Sprintf("%.1f%%", *signal.Headroom)
+			}
+			signals.AddRow(r.Node, strings.ToUpper(signal.Status), signal.Signal, signal.Eviction, signal.Threshold,
+				formatEvictionQuantity(signal.Signal, signal.Available), formatEvictionQuantity(signal.Signal, signal.Capacity), headroom)
+		}
+		reserved.AddRow(r.Node, r.Capacity.Cpu(), r.Allocatable.Cpu(), r.Capacity.Memory(), r.Allocatable.Memory(),
+			formatReserved(r.SystemReserved), formatReserved(r.KubeReserved))
+	}
+	renderedSignals, err := api.Render(signals, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes reservations: %w", err)), nil
+	}
+	renderedReserved, err := api.Render(reserved, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes reservations: %w", err)), nil
+	}
+	ret := "# " + envelope.Summary + "\n" + renderedSignals +
+		"# Reserved resources, allocatable is the capacity minus the reserved resources and the hard memory eviction threshold\n" + renderedReserved
+	return api.NewStructuredPartialToolCallResult(params, envelope, ret, len(reservations), targetErrors), nil
+}
+
+type nodesOSInventoryArgs struct {
+	LabelSelector string `json:"label_selector"`
+}
+
+func nodesOSInventory(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesOSInventoryArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes OS inventory, %w", err)), nil
+	}
+	inventory, err := params.NodesOSInventory(params, args.LabelSelector)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes OS inventory: %w", err)), nil
+	}
+	envelope := &api.Envelope{
+		Kind:    "NodeOSInventory",
+		Items:   inventory.Nodes,
+		Summary: inventory.Summary,
+	}
+	if len(inventory.Nodes) == 0 {
+		return api.NewStructuredToolCallResult(params, envelope, "No nodes found"), nil
+	}
+	fields := api.NewTable("FIELD", "VALUE", "NODES", "OUTLIER NODES")
+	for _, field := range inventory.Fields {
+		for _, value := range field.Values {
+			outliers := "-"
+			if value.Outlier {
+				outliers = strings.Join(value.Nodes, ",")
+			}
+			fields.AddRow(field.Field, value.Value, value.Count, outliers)
+		}
+	}
+	nodes := api.NewTable("NODE", "OS", "ARCH", "OS IMAGE", "KERNEL", "RUNTIME", "KUBELET", "OUTLIERS")
+	for _, node := range inventory.Nodes {
+		outliers := "-"
+		if len(node.Outliers) > 0 {
+			outliers = strings.Join(node.Outliers, ",")
+		}
+		nodes.AddRow(node.Node, node.OperatingSystem, node.Architecture, node.OSImage, node.KernelVersion,
+			node.ContainerRuntimeVersion, node.KubeletVersion, outliers)
+	}
+	renderedFields, err := api.Render(fields, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes OS inventory: %w", err)), nil
+	}
+	renderedNodes, err := api.Render(nodes, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes OS inventory: %w", err)), nil
+	}
+	ret := "# " + inventory.Summary + "\n" + renderedFields + "# Nodes\n" + renderedNodes
+	if inventory.ServerVersion != "" {
+		ret += "# API server version: " + inventory.ServerVersion + "\n"
+	}
+	for _, skew := range inventory.Skew {
+		ret += "# Version skew: " + skew + "\n"
 	}
-	ret, err := params.NodesStatsSummary(params, name)
+	for _, warning := range inventory.Warnings {
+		ret += "# Warning: " + warning + "\n"
+	}
+	return api.NewStructuredToolCallResult(params, envelope, ret), nil
+}
+
+// formatEvictionQuantity formats the amount of the resource of the eviction signal, bytes in Mi, inodes and PIDs as is
+func formatEvictionQuantity(signal string, value *int64) string {
+	switch {
+	case value == nil:
+		return "-"
+	case strings.HasSuffix(signal, ".available") && signal != "pid.available":
+		return fmt.Sprintf("%dMi", *value/(1024*1024))
+	default:
+		return fmt.Sprintf("%d", *value)
+	}
+}
+
+// formatReserved formats the reserved resources, e.g. cpu=500m,memory=1Gi
+func formatReserved(reserved map[string]string) string {
+	if len(reserved) == 0 {
+		return "-"
+	}
+	values := make([]string, 0, len(reserved))
+	for _, name := range slices.Sorted(maps.Keys(reserved)) {
+		values = append(values, name+"="+reserved[name])
+	}
+	return strings.Join(values, ",")
+}
+
+func formatPSIData(data *kubernetes.PSIData) string {
+	if data == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f/%.2f", data.Avg10, data.Avg60)
+}
+
+// nodeStatsSummary is the structured output item of nodes_stats_summary
+type nodeStatsSummary struct {
+	Node string `json:"node"`
+	// Summary is the kubelet Summary API response, embedded as JSON when valid
+	Summary any `json:"summary"`
+}
+
+func newNodeStatsSummary(node, summary string) nodeStatsSummary {
+	if json.Valid([]byte(summary)) {
+		return nodeStatsSummary{Node: node, Summary: json.RawMessage(summary)}
+	}
+	return nodeStatsSummary{Node: node, Summary: summary}
+}
+
+type nodesSysctlArgs struct {
+	nodesSelectorArgs
+	Sysctls    []string         `json:"sysctls"`
+	Thresholds map[string]int64 `json:"thresholds"`
+	Format     string           `json:"format"`
+}
+
+func nodesSysctl(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesSysctlArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit node sysctls, %w", err)), nil
+	}
+	format := args.Format
+	thresholds := slices.Clone(kubernetes.DefaultSysctlThresholds)
+	sysctls := args.Sysctls
+	if len(sysctls) == 0 {
+		for _, threshold := range thresholds {
+			sysctls = append(sysctls, threshold.Name)
+		}
+	}
+	for sysctl, minValue := range args.Thresholds {
+		if i := slices.IndexFunc(thresholds, func(t kubernetes.SysctlThreshold) bool { return t.Name == sysctl }); i >= 0 {
+			thresholds[i].Min = minValue
+		} else {
+			thresholds = append(thresholds, kubernetes.SysctlThreshold{Name: sysctl, Min: minValue})
+		}
+		if !slices.Contains(sysctls, sysctl) {
+			sysctls = append(sysctls, sysctl)
+		}
+	}
+	nodes, targetErrors, err := params.NodesSysctl(params, args.Name, args.LabelSelector, sysctls)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit node sysctls: %w", err)), nil
+	}
+	if len(nodes) == 0 && len(targetErrors) == 0 {
+		return api.NewToolCallResult("No nodes found", nil), nil
+	}
+	report, err := kubernetes.SysctlReport(nodes, thresholds).Render(format)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit node sysctls: %w", err)), nil
+	}
+	if format != "" && format != findings.FormatYaml {
+		return api.NewPartialToolCallResult(report, len(nodes), targetErrors), nil
+	}
+	values, err := output.MarshalYaml(nodes)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit node sysctls: %w", err)), nil
+	}
+	return api.NewPartialToolCallResult("# Node sysctls\n"+values+"# Findings\n"+report, len(nodes), targetErrors), nil
+}
+
+type nodesDrainPreviewArgs struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+}
+
+func nodesDrainPreview(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesDrainPreviewArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to preview node drain, %w", err)), nil
+	}
+	name, format := args.Name, args.Format
+	preview, report, err := params.NodesDrainPreview(params, name)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to preview drain of node %s: %w", name, err)), nil
+	}
+	rendered, err := report.Render(format)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to preview drain of node %s: %w", name, err)), nil
+	}
+	if format != "" && format != findings.FormatYaml {
+		return api.NewToolCallResult(rendered, nil), nil
+	}
+	marshalledYaml, err := output.MarshalYaml(preview)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to preview drain of node %s: %w", name, err)), nil
+	}
+	return api.NewToolCallResult("# Node drain preview\n"+marshalledYaml+"# Findings\n"+rendered, nil), nil
+}
+
+type nodesPodsArgs struct {
+	Name   string `json:"name"`
+	SortBy string `json:"sortBy"`
+}
+
+func nodesPods(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesPodsArgs](params)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get node stats summary for %s: %v", name, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list node pods, %w", err)), nil
+	}
+	pods, err := params.NodesPods(params, args.Name, args.SortBy)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list pods of node %s: %w", args.Name, err)), nil
+	}
+	if api.IsStructuredOutput(params) {
+		return api.NewStructuredToolCallResult(params, &api.Envelope{
+			Kind:    "Pod",
+			Items:   pods,
+			Summary: fmt.Sprintf("%d pods scheduled on node %s", len(pods), args.Name),
+		}, ""), nil
+	}
+	if len(pods) == 0 {
+		return api.NewToolCallResult(fmt.Sprintf("No pods scheduled on node %s", args.Name), nil), nil
+	}
+	table := api.NewTable("NAMESPACE", "NAME", "PHASE", "RESTARTS", "CPU REQUEST", "CPU LIMIT", "MEMORY REQUEST", "MEMORY LIMIT", "QOS")
+	for _, pod := range pods {
+		table.AddRow(pod.Namespace, pod.Name, pod.Phase, pod.Restarts,
+			nodePodQuantity(pod.Requests, v1.ResourceCPU), nodePodQuantity(pod.Limits, v1.ResourceCPU),
+			nodePodQuantity(pod.Requests, v1.ResourceMemory), nodePodQuantity(pod.Limits, v1.ResourceMemory),
+			pod.QOSClass)
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to print pods of node %s: %w", args.Name, err)), nil
+	}
+	return api.NewToolCallResult(rendered, nil), nil
+}
+
+// nodePodQuantity returns the quantity of the resource, or "-" if it's not requested or limited
+func nodePodQuantity(resources v1.ResourceList, name v1.ResourceName) string {
+	quantity, ok := resources[name]
+	if !ok {
+		return "-"
+	}
+	return quantity.String()
+}
+
+type nodesWhyNotReadyArgs struct {
+	Name      string `json:"name"`
+	TailLines int64  `json:"tailLines"`
+}
+
+func nodesWhyNotReady(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesWhyNotReadyArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze node readiness, %w", err)), nil
+	}
+	readiness, err := params.NodesWhyNotReady(params, args.Name, args.TailLines)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze readiness of node %s: %w", args.Name, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(readiness)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze readiness of node %s: %w", args.Name, err)), nil
+	}
+	return api.NewToolCallResult("# "+readiness.Summary+"\n"+marshalled, nil), nil
+}
+
+type nodeFilesArgs struct {
+	Name            string          `json:"name"`
+	Operation       string          `json:"operation"`
+	SourcePath      string          `json:"source_path"`
+	DestPath        string          `json:"dest_path"`
+	Inline          bool            `json:"inline"`
+	Base64          bool            `json:"base64"`
+	ReadOnly        bool            `json:"read_only"`
+	Tolerations     []v1.Toleration `json:"tolerations"`
+	ImagePullSecret string          `json:"image_pull_secret"`
+	Resources       struct {
+		Requests map[string]string `json:"requests"`
+		Limits   map[string]string `json:"limits"`
+	} `json:"resources"`
+}
+
+func nodeFiles(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodeFilesArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to access node files, %w", err)), nil
+	}
+	requests, err := parseResourceList(args.Resources.Requests)
+	if err != nil {
+		return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to access node files, invalid argument resources.requests: %w", err))), nil
+	}
+	limits, err := parseResourceList(args.Resources.Limits)
+	if err != nil {
+		return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to access node files, invalid argument resources.limits: %w", err))), nil
+	}
+	ret, err := params.NodesFiles(params, kubernetes.NodeFilesOptions{
+		NodeName:        args.Name,
+		Operation:       args.Operation,
+		SourcePath:      args.SourcePath,
+		DestPath:        args.DestPath,
+		Inline:          args.Inline,
+		Base64:          args.Base64,
+		ReadOnly:        args.ReadOnly,
+		Tolerations:     args.Tolerations,
+		ImagePullSecret: args.ImagePullSecret,
+		Resources:       v1.ResourceRequirements{Requests: requests, Limits: limits},
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to %s files of node %s: %w", args.Operation, args.Name, err)), nil
 	}
 	return api.NewToolCallResult(ret, nil), nil
 }
 
-func nodesTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
-	nodesTopOptions := kubernetes.NodesTopOptions{}
-	if v, ok := params.GetArguments()["name"].(string); ok {
-		nodesTopOptions.Name = v
+// parseResourceList parses the quantities of the provided resources, e.g. {"cpu": "10m", "memory": "16Mi"}
+func parseResourceList(quantities map[string]string) (v1.ResourceList, error) {
+	if len(quantities) == 0 {
+		return nil, nil
 	}
-	if v, ok := params.GetArguments()["label_selector"].(string); ok {
-		nodesTopOptions.LabelSelector = v
+	resources := make(v1.ResourceList, len(quantities))
+	for name, quantity := range quantities {
+		parsed, err := resource.ParseQuantity(quantity)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		resources[v1.ResourceName(name)] = parsed
+	}
+	return resources, nil
+}
+
+func nodesTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[nodesSelectorArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes top, %w", err)), nil
 	}
+	nodesTopOptions := kubernetes.NodesTopOptions{Name: args.Name}
+	nodesTopOptions.LabelSelector = args.LabelSelector
 
 	nodeMetrics, err := params.NodesTop(params, nodesTopOptions)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes top: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes top: %w", err)), nil
 	}
 
 	// Get the list of nodes to extract their allocatable resources
 	nodes, err := params.AccessControlClientset().Nodes()
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes client: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes client: %w", err)), nil
 	}
 
 	nodeList, err := nodes.List(params, metav1.ListOptions{
 		LabelSelector: nodesTopOptions.LabelSelector,
 	})
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list nodes: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list nodes: %w", err)), nil
 	}
 
 	// Build availableResources map
@@ -174,13 +1187,79 @@ func nodesTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		}
 	}
 
-	// Print the metrics
-	buf := new(bytes.Buffer)
-	printer := metricsutil.NewTopCmdPrinter(buf, true)
-	err = printer.PrintNodeMetrics(nodeMetrics.Items, availableResources, false, "")
+	if api.IsStructuredOutput(params) {
+		items := make([]nodeTop, 0, len(nodeMetrics.Items))
+		for _, m := range nodeMetrics.Items {
+			items = append(items, newNodeTop(m.Name, m.Usage, availableResources[m.Name]))
+		}
+		return api.NewStructuredToolCallResult(params, &api.Envelope{
+			Kind:    "NodeMetrics",
+			Items:   items,
+			Summary: fmt.Sprintf("Resource consumption of %d nodes", len(items)),
+		}, ""), nil
+	}
+
+	if len(nodeMetrics.Items) == 0 {
+		return api.NewToolCallResult("", nil), nil
+	}
+	table := api.NewTable("NAME", "CPU(cores)", "CPU(%)", "MEMORY(bytes)", "MEMORY(%)", "SWAP(bytes)", "SWAP(%)")
+	metrics := slices.SortedFunc(slices.Values(nodeMetrics.Items), func(a, b metricsapi.NodeMetrics) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, m := range metrics {
+		available := availableResources[m.Name]
+		cpu, memory, swap := m.Usage[v1.ResourceCPU], m.Usage[v1.ResourceMemory], m.Usage[nodeTopSwap]
+		table.AddRow(m.Name,
+			fmt.Sprintf("%dm", cpu.MilliValue()), nodeTopPercentage(cpu, available, v1.ResourceCPU),
+			fmt.Sprintf("%dMi", memory.Value()/(1024*1024)), nodeTopPercentage(memory, available, v1.ResourceMemory),
+			fmt.Sprintf("%dMi", swap.Value()/(1024*1024)), nodeTopPercentage(swap, available, nodeTopSwap))
+		delete(availableResources, m.Name)
+	}
+	// nodes of which the metrics are unavailable
+	for _, name := range slices.Sorted(maps.Keys(availableResources)) {
+		if args.Name == "" || args.Name == name {
+			table.AddRow(name, "<unknown>", "<unknown>", "<unknown>", "<unknown>", "<unknown>", "<unknown>")
+		}
+	}
+	rendered, err := api.Render(table, api.RenderTable)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to print node metrics: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to print node metrics: %w", err)), nil
 	}
+	return api.NewToolCallResult(rendered, nil), nil
+}
 
-	return api.NewToolCallResult(buf.String(), nil), nil
+// nodeTopSwap is the swap usage reported by the metrics API (with the NodeSwap feature)
+const nodeTopSwap v1.ResourceName = "swap"
+
+// nodeTopPercentage returns the usage percentage of the allocatable resource (kubectl top nodes format)
+func nodeTopPercentage(usage resource.Quantity, available v1.ResourceList, name v1.ResourceName) string {
+	allocatable, found := available[name]
+	if !found {
+		return "<unknown>"
+	}
+	fraction := 0.0
+	if !allocatable.IsZero() {
+		fraction = float64(usage.MilliValue()) / float64(allocatable.MilliValue()) * 100
+	}
+	return fmt.Sprintf("%d%%", int64(fraction))
+}
+
+// nodeTop is the structured output item of nodes_top, usages are in millicores and bytes
+type nodeTop struct {
+	Name               string `json:"name"`
+	CpuUsageMillicores int64  `json:"cpuUsageMillicores"`
+	CpuPercentage      *int64 `json:"cpuPercentage,omitempty"`
+	MemoryUsageBytes   int64  `json:"memoryUsageBytes"`
+	MemoryPercentage   *int64 `json:"memoryPercentage,omitempty"`
+}
+
+func newNodeTop(name string, usage, allocatable v1.ResourceList) nodeTop {
+	top := nodeTop{Name: name, CpuUsageMillicores: usage.Cpu().MilliValue(), MemoryUsageBytes: usage.Memory().Value()}
+	if cpu := allocatable.Cpu().MilliValue(); cpu > 0 {
+		top.CpuPercentage = ptr.To(top.CpuUsageMillicores * 100 / cpu)
+	}
+	if memory := allocatable.Memory().Value(); memory > 0 {
+		top.MemoryPercentage = ptr.To(top.MemoryUsageBytes * 100 / memory)
+	}
+	return top
 }
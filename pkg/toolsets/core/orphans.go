@@ -0,0 +1,134 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initOrphans() []api.ServerTool {
+	categories := make([]any, 0, len(kubernetes.OrphanCategories))
+	for _, category := range kubernetes.OrphanCategories {
+		categories = append(categories, category)
+	}
+	properties := func(categoriesDescription string) map[string]*jsonschema.Schema {
+		return map[string]*jsonschema.Schema{
+			"namespace": {
+				Type:        "string",
+				Description: "Namespace of the resources (Optional, all namespaces if not provided)",
+			},
+			"categories": {
+				Type:        "array",
+				Description: categoriesDescription,
+				Items:       &jsonschema.Schema{Type: "string", Enum: categories},
+			},
+			"older_than": {
+				Type:        "string",
+				Description: "Minimum age of the completed Pods (since they finished) and of the helpers, e.g. 6h (Optional, defaults to " + kubernetes.OrphansOlderThanDefault.String() + ")",
+			},
+		}
+	}
+	categoriesDescription := "replicasets (scaled to zero and not owned by an existing Deployment), " +
+		"pvcs (PersistentVolumeClaims not mounted by any Pod), completed-pods (Succeeded and Failed Pods older than older_than), " +
+		"helpers (helper pods and DaemonSets left behind by previous tool calls), services (Services with a selector and no ready endpoint)"
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "orphans_report",
+			Description: "Find the orphaned resources of the current cluster in the provided namespace or in all namespaces, resources that are likely left behind and can be cleaned up: " +
+				categoriesDescription + ". Each resource is reported with the reason why it's considered orphaned",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties("Categories of orphaned resources to find (Optional, all categories if not provided)"),
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Orphans: Report",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: orphansReport, Permissions: orphansPermissions},
+		{Tool: api.Tool{
+			Name: "orphans_cleanup",
+			Description: "Delete the orphaned resources of the provided categories found by orphans_report in the provided namespace or in all namespaces. " +
+				"Review the orphans_report of the same categories first, use dry_run to preview the deletions",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties("Categories of orphaned resources to delete"),
+				Required:   []string{"categories"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Orphans: Cleanup",
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: orphansCleanup, Permissions: slices.Concat(orphansPermissions, []api.ResourcePermission{deleteReplicaSets, deletePersistentVolumeClaims, deletePods, deleteDaemonSets, deleteServices}),
+			DryRunSupported: ptr.To(true)},
+	}
+}
+
+type orphansArgs struct {
+	Namespace  string   `json:"namespace"`
+	Categories []string `json:"categories"`
+	OlderThan  string   `json:"older_than"`
+}
+
+func (a *orphansArgs) options() (kubernetes.OrphansOptions, error) {
+	options := kubernetes.OrphansOptions{Namespace: a.Namespace, Categories: a.Categories}
+	if a.OlderThan != "" {
+		olderThan, err := time.ParseDuration(a.OlderThan)
+		if err != nil || olderThan <= 0 {
+			return options, fmt.Errorf("invalid older_than %q, expected a positive duration (e.g. 6h)", a.OlderThan)
+		}
+		options.OlderThan = olderThan
+	}
+	return options, nil
+}
+
+func orphansReport(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[orphansArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to report orphans, %w", err)), nil
+	}
+	options, err := args.options()
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to report orphans, %w", err)), nil
+	}
+	report, err := params.OrphansReport(params, options)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to report orphans: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to report orphans: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+report.Summary+"\n"+marshalled, nil), nil
+}
+
+func orphansCleanup(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[orphansArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to clean up orphans, %w", err)), nil
+	}
+	options, err := args.options()
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to clean up orphans, %w", err)), nil
+	}
+	report, err := params.OrphansCleanup(params, options)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to clean up orphans: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to clean up orphans: %w", err)), nil
+	}
+	return api.NewPartialToolCallResult("# "+report.Summary+"\n"+marshalled, len(report.Orphans)-len(report.TargetErrors), report.TargetErrors), nil
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// Kubernetes API permissions needed by the core tools, reviewed by the self_check tool
+var (
+	getPods                       = api.ResourcePermission{Verb: "get", Resource: "pods"}
+	listPods                      = api.ResourcePermission{Verb: "list", Resource: "pods"}
+	listAllPods                   = api.ResourcePermission{Verb: "list", Resource: "pods", ClusterWide: true}
+	createPods                    = api.ResourcePermission{Verb: "create", Resource: "pods"}
+	deletePods                    = api.ResourcePermission{Verb: "delete", Resource: "pods"}
+	getPodsLog                    = api.ResourcePermission{Verb: "get", Resource: "pods", Subresource: "log"}
+	createPodsExec                = api.ResourcePermission{Verb: "create", Resource: "pods", Subresource: "exec"}
+	updatePodsEphemeralContainers = api.ResourcePermission{Verb: "update", Resource: "pods", Subresource: "ephemeralcontainers"}
+	listPodMetrics                = api.ResourcePermission{Verb: "list", Group: "metrics.k8s.io", Resource: "pods"}
+	listEvents                    = api.ResourcePermission{Verb: "list", Resource: "events"}
+	listResourceQuotas            = api.ResourcePermission{Verb: "list", Resource: "resourcequotas"}
+	listLimitRanges               = api.ResourcePermission{Verb: "list", Resource: "limitranges"}
+	getJobs                       = api.ResourcePermission{Verb: "get", Group: "batch", Resource: "jobs"}
+	listJobs                      = api.ResourcePermission{Verb: "list", Group: "batch", Resource: "jobs"}
+	createJobs                    = api.ResourcePermission{Verb: "create", Group: "batch", Resource: "jobs"}
+	listSecrets                   = api.ResourcePermission{Verb: "list", Resource: "secrets"}
+	getSecrets                    = api.ResourcePermission{Verb: "get", Resource: "secrets"}
+	getConfigMaps                 = api.ResourcePermission{Verb: "get", Resource: "configmaps"}
+	getServiceAccounts            = api.ResourcePermission{Verb: "get", Resource: "serviceaccounts"}
+	getServices                   = api.ResourcePermission{Verb: "get", Resource: "services"}
+	listEndpointSlices            = api.ResourcePermission{Verb: "list", Group: "discovery.k8s.io", Resource: "endpointslices"}
+	listAllPodDisruptionBudgets   = api.ResourcePermission{Verb: "list", Group: "policy", Resource: "poddisruptionbudgets", ClusterWide: true}
+	getNodes                      = api.ResourcePermission{Verb: "get", Resource: "nodes", ClusterWide: true}
+	listNodes                     = api.ResourcePermission{Verb: "list", Resource: "nodes", ClusterWide: true}
+	getNodesProxy                 = api.ResourcePermission{Verb: "get", Resource: "nodes", Subresource: "proxy", ClusterWide: true}
+	getLeases                     = api.ResourcePermission{Verb: "get", Group: "coordination.k8s.io", Resource: "leases"}
+	listNodeMetrics               = api.ResourcePermission{Verb: "list", Group: "metrics.k8s.io", Resource: "nodes", ClusterWide: true}
+	listNamespaces                = api.ResourcePermission{Verb: "list", Resource: "namespaces", ClusterWide: true}
+	createNamespaces              = api.ResourcePermission{Verb: "create", Resource: "namespaces", ClusterWide: true}
+	deleteNamespaces              = api.ResourcePermission{Verb: "delete", Resource: "namespaces", ClusterWide: true}
+	listProjects                  = api.ResourcePermission{Verb: "list", Group: "project.openshift.io", Resource: "projects", ClusterWide: true}
+	listCertificateRequests       = api.ResourcePermission{Verb: "list", Group: "certificates.k8s.io", Resource: "certificatesigningrequests", ClusterWide: true}
+	getCertificateRequests        = api.ResourcePermission{Verb: "get", Group: "certificates.k8s.io", Resource: "certificatesigningrequests", ClusterWide: true}
+	approveCertificateRequests    = api.ResourcePermission{Verb: "update", Group: "certificates.k8s.io", Resource: "certificatesigningrequests", Subresource: "approval", ClusterWide: true}
+	listCustomResourceDefinitions = api.ResourcePermission{Verb: "list", Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions", ClusterWide: true}
+	listReplicaSets               = api.ResourcePermission{Verb: "list", Group: "apps", Resource: "replicasets"}
+	deleteReplicaSets             = api.ResourcePermission{Verb: "delete", Group: "apps", Resource: "replicasets"}
+	listDeployments               = api.ResourcePermission{Verb: "list", Group: "apps", Resource: "deployments"}
+	listDaemonSets                = api.ResourcePermission{Verb: "list", Group: "apps", Resource: "daemonsets"}
+	listStatefulSets              = api.ResourcePermission{Verb: "list", Group: "apps", Resource: "statefulsets"}
+	listControllerRevisions       = api.ResourcePermission{Verb: "list", Group: "apps", Resource: "controllerrevisions"}
+	deleteDaemonSets              = api.ResourcePermission{Verb: "delete", Group: "apps", Resource: "daemonsets"}
+	listPersistentVolumeClaims    = api.ResourcePermission{Verb: "list", Resource: "persistentvolumeclaims"}
+	deletePersistentVolumeClaims  = api.ResourcePermission{Verb: "delete", Resource: "persistentvolumeclaims"}
+	listServices                  = api.ResourcePermission{Verb: "list", Resource: "services"}
+	deleteServices                = api.ResourcePermission{Verb: "delete", Resource: "services"}
+
+	// helperPodPermissions are the permissions needed to run the helper pods of the node tools
+	helperPodPermissions = []api.ResourcePermission{createPods, getPods, getPodsLog, deletePods}
+
+	// orphansPermissions are the permissions needed to find the orphaned resources
+	orphansPermissions = []api.ResourcePermission{listReplicaSets, listDeployments, listPersistentVolumeClaims, listPods, listDaemonSets, listServices, listEndpointSlices}
+)
+
+// nodeHelperPodPermissions returns the permissions of the tools running a helper pod on a node
+func nodeHelperPodPermissions() []api.ResourcePermission {
+	return slices.Concat([]api.ResourcePermission{getNodes}, helperPodPermissions)
+}
@@ -4,12 +4,19 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubectl/pkg/metricsutil"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+	"github.com/containers/kubernetes-mcp-server/pkg/findings"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
@@ -35,7 +42,7 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsListInAllNamespaces},
+		}, Handler: podsListInAllNamespaces, Permissions: []api.ResourcePermission{listAllPods}},
 		{Tool: api.Tool{
 			Name:        "pods_list_in_namespace",
 			Description: "List all the Kubernetes pods in the specified namespace in the current cluster",
@@ -60,10 +67,10 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsListInNamespace},
+		}, Handler: podsListInNamespace, Permissions: []api.ResourcePermission{listPods}},
 		{Tool: api.Tool{
 			Name:        "pods_get",
-			Description: "Get a Kubernetes Pod in the current or provided namespace with the provided name",
+			Description: "Get a Kubernetes Pod in the current or provided namespace with the provided name. Abnormal container terminations are reported with an explanation of their exit code or signal and the likely causes",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
@@ -84,10 +91,12 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsGet},
+		}, Handler: podsGet, Permissions: []api.ResourcePermission{getPods}},
 		{Tool: api.Tool{
-			Name:        "pods_delete",
-			Description: "Delete a Kubernetes Pod in the current or provided namespace with the provided name",
+			Name: "pods_delete",
+			Description: "Delete a Kubernetes Pod in the current or provided namespace with the provided name. " +
+				"Use evict=true to evict the Pod through the Eviction API instead (same as kubectl drain), " +
+				"the eviction is rejected when it would violate a PodDisruptionBudget and the blocking PodDisruptionBudgets are reported",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
@@ -99,6 +108,16 @@ func initPods() []api.ServerTool {
 						Type:        "string",
 						Description: "Name of the Pod to delete",
 					},
+					"evict": {
+						Type:        "boolean",
+						Description: "Evict the Pod with the Eviction API, honoring its PodDisruptionBudgets, instead of deleting it (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+					"grace_period_seconds": {
+						Type:        "integer",
+						Description: "Seconds given to the Pod to terminate gracefully (Optional, defaults to the terminationGracePeriodSeconds of the Pod, 0 deletes the Pod immediately)",
+						Minimum:     ptr.To(float64(0)),
+					},
 				},
 				Required: []string{"name"},
 			},
@@ -108,7 +127,7 @@ func initPods() []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsDelete},
+		}, Handler: podsDelete, Permissions: []api.ResourcePermission{deletePods}, DryRunSupported: ptr.To(true)},
 		{Tool: api.Tool{
 			Name:        "pods_top",
 			Description: "List the resource consumption (CPU and memory) as recorded by the Kubernetes Metrics Server for the specified Kubernetes Pods in the all namespaces, the provided namespace, or the current namespace",
@@ -142,7 +161,7 @@ func initPods() []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsTop},
+		}, Handler: podsTop, Permissions: []api.ResourcePermission{listPodMetrics}},
 		{Tool: api.Tool{
 			Name:        "pods_exec",
 			Description: "Execute a command in a Kubernetes Pod in the current or provided namespace with the provided name and command",
@@ -176,7 +195,52 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(true), // Depending on the Pod's entrypoint, executing certain commands may kill the Pod
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsExec},
+		}, Handler: podsExec, Permissions: []api.ResourcePermission{getPods, createPodsExec}},
+		{Tool: api.Tool{
+			Name: "pods_debug",
+			Description: "Debug a running Kubernetes Pod in the current or provided namespace with the provided name (kubectl debug). " +
+				"Attaches an ephemeral container running the provided command to the Pod and returns its output and exit code. " +
+				"The ephemeral container shares the Pod network namespace and, with target_container, the process namespace of that container, " +
+				"which allows debugging distroless or crashing containers without a shell and without privileged node access. " +
+				"Ephemeral containers can't be removed, they remain in the Pod spec until the Pod is deleted",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod to debug",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod to debug",
+					},
+					"command": {
+						Type:        "array",
+						Description: "Command to run in the ephemeral container. The first item is the command to be run, and the rest are the arguments to that command. Example: [\"ss\", \"-tlnp\"]",
+						Items: &jsonschema.Schema{
+							Type: "string",
+						},
+						MinItems: ptr.To(1),
+					},
+					"image": {
+						Type:        "string",
+						Description: "Image of the ephemeral container (Optional, defaults to the configured debug helper image)",
+					},
+					"target_container": {
+						Type:        "string",
+						Description: "Name of the Pod container whose process namespace is shared with the ephemeral container, e.g. to inspect its processes and /proc/1/root (Optional)",
+					},
+				},
+				Required: []string{"name", "command"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: Debug",
+				ReadOnlyHint:    ptr.To(false), // Adds an ephemeral container to the Pod
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsDebug, Permissions: []api.ResourcePermission{getPods, updatePodsEphemeralContainers, getPodsLog}},
 		{Tool: api.Tool{
 			Name:        "pods_log",
 			Description: "Get the logs of a Kubernetes Pod in the current or provided namespace with the provided name",
@@ -205,6 +269,11 @@ func initPods() []api.ServerTool {
 						Type:        "boolean",
 						Description: "Return previous terminated container logs (Optional)",
 					},
+					"artifact": {
+						Type: "boolean",
+						Description: "Write the full log to a server-side artifact exposed as an MCP resource and only return its URI and the last lines of the log (Optional). " +
+							"Use it for very large logs (e.g. tail=0) instead of returning the whole log",
+					},
 				},
 				Required: []string{"name"},
 			},
@@ -214,7 +283,258 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsLog},
+		}, Handler: podsLog, Permissions: []api.ResourcePermission{getPodsLog}},
+		{Tool: api.Tool{
+			Name: "logs_search",
+			Description: "Search a pattern (grep) in the logs of the Kubernetes Pods in all namespaces or the provided namespace, optionally filtered by a label selector, " +
+				"and return the matching lines with the namespace, Pod and container that logged them. " +
+				"Searches the last tail lines of every container of up to max_pods Pods concurrently, use it to find which Pod logged an error",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"pattern": {
+						Type:        "string",
+						Description: "Regular expression (RE2 syntax) to search in each log line, e.g. 'connection refused' or 'level=(error|fatal)'",
+					},
+					"ignore_case": {
+						Type:        "boolean",
+						Description: "Match the pattern case-insensitively (Optional, default false)",
+						Default:     api.ToRawMessage(false),
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pods to search the logs of (Optional, all namespaces if not provided)",
+					},
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)') to filter the Pods by label (Optional)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"tail": {
+						Type:        "integer",
+						Description: "Number of lines searched from the end of the logs of each container",
+						Default:     api.ToRawMessage(kubernetes.DefaultTailLines),
+						Minimum:     ptr.To(float64(1)),
+					},
+					"max_pods": {
+						Type:        "integer",
+						Description: "Maximum number of Pods whose logs are searched, in namespace and name order",
+						Default:     api.ToRawMessage(kubernetes.LogsSearchDefaultMaxPods),
+						Minimum:     ptr.To(float64(1)),
+					},
+					"max_matches": {
+						Type:        "integer",
+						Description: "Maximum number of matching lines returned",
+						Default:     api.ToRawMessage(kubernetes.LogsSearchDefaultMaxMatches),
+						Minimum:     ptr.To(float64(1)),
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+				Required: []string{"pattern"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Logs: Search",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: logsSearch, Permissions: []api.ResourcePermission{listAllPods, getPodsLog}},
+		{Tool: api.Tool{
+			Name: "pods_dns",
+			Description: "Inspect the effective DNS configuration of a Kubernetes Pod in the current or provided namespace with the provided name: " +
+				"dnsPolicy, dnsConfig, ndots and the container /etc/resolv.conf (read via exec). " +
+				"Diagnoses common resolution problems such as the ndots:5 latency for external names, hostNetwork Pods not resolving Services, or too many search domains and nameservers",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod to inspect",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod to inspect",
+					},
+					"container": {
+						Type:        "string",
+						Description: "Name of the Pod container to read /etc/resolv.conf from (Optional)",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: DNS",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsDNS, Permissions: []api.ResourcePermission{getPods, createPodsExec}},
+		{Tool: api.Tool{
+			Name: "pods_lifecycle",
+			Description: "Explain the startup and shutdown ordering of the containers of a Kubernetes Pod in the current or provided namespace with the provided name: " +
+				"init containers, native sidecars (init containers with restartPolicy: Always), containers and their lifecycle hooks (postStart, preStop). " +
+				"Flags known anti-patterns such as init containers that run before a sidecar they need, legacy sidecars declared as regular containers, or preStop hooks exceeding the grace period",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod to analyze",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod to analyze",
+					},
+					"format": findings.FormatProperty(),
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: Lifecycle",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsLifecycle, Permissions: []api.ResourcePermission{getPods}},
+		{Tool: api.Tool{
+			Name: "pods_diagnose",
+			Description: "Diagnose a failing (e.g. CrashLoopBackOff, Error, OOMKilled) Kubernetes Pod in the current or provided namespace with the provided name in a single call. " +
+				"Returns the describe-style status and conditions, the current and last state of each container with its restart count, " +
+				"the explanation of the exit codes, the resource requests and limits, the recent Warning events, " +
+				"and the tail of the logs of the previous instance of the restarted containers",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod to diagnose",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod to diagnose",
+					},
+					"tail": {
+						Type:        "integer",
+						Description: "Number of log lines to retrieve from the end of the logs of each terminated or restarted container (Optional, default: 50)",
+						Default:     api.ToRawMessage(kubernetes.DefaultDiagnoseTailLines),
+						Minimum:     ptr.To(float64(0)),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: Diagnose",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsDiagnose, Permissions: []api.ResourcePermission{getPods, getPodsLog, listEvents}},
+		{Tool: api.Tool{
+			Name: "pods_why_pending",
+			Description: "Explain why a Pending Kubernetes Pod in the current or provided namespace with the provided name is not scheduled. " +
+				"Evaluates the Pod against each node (unschedulable nodes, node selector and required node affinity, untolerated taints, " +
+				"allocatable resources vs requests of the Pods already running, Pod count, host ports) and reports the nodes ruled out with the reasons why, " +
+				"the Pod level issues (scheduling gates, unbound PersistentVolumeClaims, custom scheduler), and the FailedScheduling events",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pending Pod",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pending Pod",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: Why Pending",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsWhyPending, Permissions: []api.ResourcePermission{getPods, listNodes, listAllPods}},
+		{Tool: api.Tool{
+			Name: "pods_image_pull_debug",
+			Description: "Explain why a Kubernetes Pod in the current or provided namespace with the provided name can't pull its container images (ErrImagePull, ImagePullBackOff). " +
+				"Inspects the image references (registry, repository, tag vs digest), the image pull secrets of the Pod, its ServiceAccount, and the namespace " +
+				"with the registries they have credentials for (the credentials are never returned), and the image pull errors of the containers, events, and kubelet log, " +
+				"and ranks the likely causes (authentication, missing secret, repository, tag, or digest not found, rate limit, network, TLS, platform). " +
+				"Optionally reads the registry configuration of the node (registries.conf, containerd mirrors, node credentials) through a short-lived privileged helper pod",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod failing to pull its images",
+					},
+					"node_config": {
+						Type:        "boolean",
+						Description: "Read the registry configuration of the node of the Pod through a helper pod, the credentials are redacted on the node (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: Image Pull Debug",
+				ReadOnlyHint:    ptr.To(false), // Creates a helper pod on the node with node_config
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsImagePullDebug, Permissions: slices.Concat(
+			[]api.ResourcePermission{getPods, getServiceAccounts, listSecrets, listEvents, getNodesProxy}, nodeHelperPodPermissions())},
+		{Tool: api.Tool{
+			Name: "pods_oom_report",
+			Description: "Report the containers of the Kubernetes Pods in all namespaces or the provided namespace that were OOMKilled (current state or last termination) " +
+				"or restart repeatedly, with their memory requests and limits, the explanation of their last termination, and the recommended action " +
+				"(e.g. increase the memory limit, set a memory request, the restarts are not memory related). " +
+				"Correlates them with the memory of their nodes from the kubelet stats summaries (MemoryPressure condition, available memory, memory PSI) " +
+				"and the current working set of the containers, also reporting the containers close to their memory limit, unless node_stats is false",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pods to scan (Optional, all namespaces if not provided)",
+					},
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)'), use this option when you want to filter the pods by label (Optional)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"restart_threshold": {
+						Type:        "integer",
+						Description: "Number of restarts from which a container that was not OOMKilled is reported (Optional)",
+						Default:     api.ToRawMessage(kubernetes.DefaultOOMReportRestartThreshold),
+						Minimum:     ptr.To(float64(1)),
+					},
+					"node_stats": {
+						Type:        "boolean",
+						Description: "Correlate the containers with the memory of their nodes and their working set from the kubelet stats summaries (Optional)",
+						Default:     api.ToRawMessage(true),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: OOM Report",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsOOMReport, Permissions: []api.ResourcePermission{listAllPods, listNodes, getNodesProxy}},
 		{Tool: api.Tool{
 			Name:        "pods_run",
 			Description: "Run a Kubernetes Pod in the current or provided namespace with the provided container image and optional name",
@@ -245,7 +565,7 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsRun},
+		}, Handler: podsRun, Permissions: []api.ResourcePermission{createPods}, DryRunSupported: ptr.To(true)},
 	}
 }
 
@@ -259,16 +579,18 @@ func podsListInAllNamespaces(params api.ToolHandlerParams) (*api.ToolCallResult,
 	}
 	ret, err := params.PodsListInAllNamespaces(params, resourceListOptions)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list pods in all namespaces: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list pods in all namespaces: %w", err)), nil
 	}
 	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
 }
 
 func podsListInNamespace(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	ns := params.GetArguments()["namespace"]
-	if ns == nil {
-		return api.NewToolCallResult("", errors.New("failed to list pods in namespace, missing argument namespace")), nil
+	// the namespace is optional when the server is restricted to target namespaces, it defaults to the configured one
+	if ns == nil && params.AccessControlClientset().IsNamespaceAllowed("") == nil {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to list pods in namespace, missing argument namespace"))), nil
 	}
+	namespace, _ := ns.(string)
 	resourceListOptions := kubernetes.ResourceListOptions{
 		AsTable: params.ListOutput.AsTable(),
 	}
@@ -276,9 +598,9 @@ func podsListInNamespace(params api.ToolHandlerParams) (*api.ToolCallResult, err
 	if labelSelector != nil {
 		resourceListOptions.LabelSelector = labelSelector.(string)
 	}
-	ret, err := params.PodsListInNamespace(params, ns.(string), resourceListOptions)
+	ret, err := params.PodsListInNamespace(params, namespace, resourceListOptions)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list pods in namespace %s: %v", ns, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)), nil
 	}
 	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
 }
@@ -290,13 +612,26 @@ func podsGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	name := params.GetArguments()["name"]
 	if name == nil {
-		return api.NewToolCallResult("", errors.New("failed to get pod, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get pod, missing argument name"))), nil
 	}
 	ret, err := params.PodsGet(params, ns.(string), name.(string))
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get pod %s in namespace %s: %v", name, ns, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get pod %s in namespace %s: %w", name, ns, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(ret)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get pod %s in namespace %s: %w", name, ns, err)), nil
 	}
-	return api.NewToolCallResult(output.MarshalYaml(ret)), nil
+	// Explain the exit codes of the abnormal container terminations to avoid follow-up lookups
+	pod := &v1.Pod{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(ret.Object, pod); err == nil {
+		if terminations := kubernetes.ContainerTerminations(pod); len(terminations) > 0 {
+			if explained, err := output.MarshalYaml(terminations); err == nil {
+				marshalled += "# Container terminations\n" + explained
+			}
+		}
+	}
+	return api.NewToolCallResult(marshalled, nil), nil
 }
 
 func podsDelete(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
@@ -306,11 +641,23 @@ func podsDelete(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	name := params.GetArguments()["name"]
 	if name == nil {
-		return api.NewToolCallResult("", errors.New("failed to delete pod, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to delete pod, missing argument name"))), nil
 	}
-	ret, err := params.PodsDelete(params, ns.(string), name.(string))
+	options := kubernetes.PodsDeleteOptions{}
+	options.Evict, _ = params.GetArguments()["evict"].(bool)
+	if gracePeriod := params.GetArguments()["grace_period_seconds"]; gracePeriod != nil {
+		gracePeriodSeconds, err := api.ParseInt64(gracePeriod)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to parse grace_period_seconds parameter: %w", err)), nil
+		}
+		options.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	ret, err := params.PodsDelete(params, ns.(string), name.(string), options)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to delete pod %s in namespace %s: %v", name, ns, err)), nil
+		if options.Evict {
+			return api.NewToolCallResult("", fmt.Errorf("failed to evict pod %s in namespace %s: %w", name, ns, err)), nil
+		}
+		return api.NewToolCallResult("", fmt.Errorf("failed to delete pod %s in namespace %s: %w", name, ns, err)), nil
 	}
 	return api.NewToolCallResult(ret, err), nil
 }
@@ -331,13 +678,13 @@ func podsTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	ret, err := params.PodsTop(params, podsTopOptions)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get pods top: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get pods top: %w", err)), nil
 	}
 	buf := new(bytes.Buffer)
 	printer := metricsutil.NewTopCmdPrinter(buf, true)
 	err = printer.PrintPodMetrics(ret.Items, true, true, false, "", true)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get pods top: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get pods top: %w", err)), nil
 	}
 	return api.NewToolCallResult(buf.String(), nil), nil
 }
@@ -349,7 +696,7 @@ func podsExec(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	name := params.GetArguments()["name"]
 	if name == nil {
-		return api.NewToolCallResult("", errors.New("failed to exec in pod, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to exec in pod, missing argument name"))), nil
 	}
 	container := params.GetArguments()["container"]
 	if container == nil {
@@ -368,13 +715,47 @@ func podsExec(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	ret, err := params.PodsExec(params, ns.(string), name.(string), container.(string), command)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to exec in pod %s in namespace %s: %v", name, ns, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to exec in pod %s in namespace %s: %w", name, ns, err)), nil
 	} else if ret == "" {
 		ret = fmt.Sprintf("The executed command in pod %s in namespace %s has not produced any output", name, ns)
 	}
 	return api.NewToolCallResult(ret, err), nil
 }
 
+type podsDebugArgs struct {
+	Namespace       string   `json:"namespace"`
+	Name            string   `json:"name"`
+	Command         []string `json:"command"`
+	Image           string   `json:"image"`
+	TargetContainer string   `json:"target_container"`
+}
+
+func podsDebug(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[podsDebugArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to debug pod, %w", err)), nil
+	}
+	podDebug, err := params.PodsDebug(params, args.Namespace, args.Name, kubernetes.PodsDebugOptions{
+		Command:         args.Command,
+		Image:           args.Image,
+		TargetContainer: args.TargetContainer,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to debug pod %s in namespace %s: %w", args.Name, args.Namespace, err)), nil
+	}
+	summary := fmt.Sprintf("Ephemeral container %s in pod %s", podDebug.Container, podDebug.Pod)
+	if podDebug.ExitCode != nil {
+		summary += fmt.Sprintf(" exited with code %d", *podDebug.ExitCode)
+	} else {
+		summary += " is " + podDebug.Reason
+	}
+	marshalled, err := output.MarshalYaml(podDebug)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to debug pod %s in namespace %s: %w", args.Name, args.Namespace, err)), nil
+	}
+	return api.NewToolCallResult("# "+summary+"\n"+marshalled, nil), nil
+}
+
 func podsLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	ns := params.GetArguments()["namespace"]
 	if ns == nil {
@@ -382,7 +763,7 @@ func podsLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	name := params.GetArguments()["name"]
 	if name == nil {
-		return api.NewToolCallResult("", errors.New("failed to get pod log, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get pod log, missing argument name"))), nil
 	}
 	container := params.GetArguments()["container"]
 	if container == nil {
@@ -406,13 +787,232 @@ func podsLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 
 	ret, err := params.PodsLog(params.Context, ns.(string), name.(string), container.(string), previousBool, tailInt)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get pod %s log in namespace %s: %v", name, ns, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get pod %s log in namespace %s: %w", name, ns, err)), nil
 	} else if ret == "" {
 		ret = fmt.Sprintf("The pod %s in namespace %s has not logged any message yet", name, ns)
+	} else if artifact, _ := params.GetArguments()["artifact"].(bool); artifact {
+		summary, preview, err := storeLogArtifact(params, ret, "pod-log", ns.(string), name.(string), container.(string))
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to store pod %s log in namespace %s: %w", name, ns, err)), nil
+		}
+		ret = logArtifactText(summary, preview)
 	}
 	return api.NewToolCallResult(ret, err), nil
 }
 
+// logArtifactPreviewLines is the number of lines returned along with the URI of a log written to an artifact
+const logArtifactPreviewLines = 20
+
+// storeLogArtifact writes the (redacted) log to an artifact named after the provided parts and returns the summary
+// with the artifact URI and the last lines of the log as a preview
+func storeLogArtifact(params api.ToolHandlerParams, log string, nameParts ...string) (string, []string, error) {
+	store := params.NewArtifactStore()
+	lines := strings.Split(strings.TrimSuffix(log, "\n"), "\n")
+	artifact, err := store.Put(params, artifacts.NewName(time.Now(), ".log", nameParts...), []byte(output.Redact(log)))
+	if artifact == nil {
+		return "", nil, err
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to upload artifact %s to %s: %w", artifact.Name, store.RemoteName(), err)
+	}
+	summary := fmt.Sprintf("Full log (%d lines, %d bytes) written to %s, read the MCP resource to get the whole log", len(lines), len(log), artifact.URI)
+	if artifact.URL != "" {
+		summary += " (uploaded to " + artifact.URL + ")"
+	}
+	return summary, lines[max(0, len(lines)-logArtifactPreviewLines):], nil
+}
+
+// logArtifactText returns the text output of a log written to an artifact
+func logArtifactText(summary string, preview []string) string {
+	return fmt.Sprintf("# %s\n# Last %d lines:\n%s\n", summary, len(preview), strings.Join(preview, "\n"))
+}
+
+type logsSearchArgs struct {
+	Pattern       string `json:"pattern"`
+	IgnoreCase    bool   `json:"ignore_case"`
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"label_selector"`
+	Tail          int64  `json:"tail"`
+	MaxPods       int    `json:"max_pods"`
+	MaxMatches    int    `json:"max_matches"`
+}
+
+func logsSearch(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[logsSearchArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to search logs, %w", err)), nil
+	}
+	result, targetErrors, err := params.LogsSearch(params, kubernetes.LogsSearchOptions{
+		Pattern:       args.Pattern,
+		IgnoreCase:    args.IgnoreCase,
+		Namespace:     args.Namespace,
+		LabelSelector: args.LabelSelector,
+		TailLines:     args.Tail,
+		MaxPods:       args.MaxPods,
+		MaxMatches:    args.MaxMatches,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to search logs: %w", err)), nil
+	}
+	summary := fmt.Sprintf("%d matches in the last %d lines of %d containers of %d pods", len(result.Matches), args.Tail, result.Containers, result.Pods)
+	if result.Pods < result.TotalPods {
+		summary += fmt.Sprintf(" (%d pods not searched, narrow the namespace or label_selector or increase max_pods)", result.TotalPods-result.Pods)
+	}
+	envelope := &api.Envelope{Kind: "LogMatch", Items: result.Matches, Summary: summary, Truncated: result.Truncated}
+	if result.TotalPods == 0 {
+		return api.NewStructuredToolCallResult(params, envelope, "No pods found"), nil
+	}
+	ret := strings.Builder{}
+	ret.WriteString("# " + summary + "\n")
+	for _, match := range result.Matches {
+		ret.WriteString(fmt.Sprintf("[%s/%s/%s] %s\n", match.Namespace, match.Pod, match.Container, match.Line))
+	}
+	if result.DroppedMatches > 0 {
+		ret.WriteString(fmt.Sprintf("# %d more matches not returned, refine the pattern or increase max_matches\n", result.DroppedMatches))
+	}
+	return api.NewStructuredPartialToolCallResult(params, envelope, ret.String(), result.Containers, targetErrors), nil
+}
+
+func podsDNS(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ns := params.GetArguments()["namespace"]
+	if ns == nil {
+		ns = ""
+	}
+	name := params.GetArguments()["name"]
+	if name == nil {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to inspect pod DNS, missing argument name"))), nil
+	}
+	container := params.GetArguments()["container"]
+	if container == nil {
+		container = ""
+	}
+	ret, err := params.PodsDNS(params, ns.(string), name.(string), container.(string))
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to inspect pod %s DNS in namespace %s: %w", name, ns, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(ret)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to inspect pod %s DNS in namespace %s: %w", name, ns, err)), nil
+	}
+	return api.NewToolCallResult("# Pod DNS configuration\n"+marshalled, nil), nil
+}
+
+func podsLifecycle(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ns, _ := params.GetArguments()["namespace"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to analyze pod lifecycle, missing argument name"))), nil
+	}
+	format, _ := params.GetArguments()["format"].(string)
+	lifecycle, report, err := params.PodsLifecycle(params, ns, name)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod %s lifecycle in namespace %s: %w", name, ns, err)), nil
+	}
+	rendered, err := report.Render(format)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod %s lifecycle in namespace %s: %w", name, ns, err)), nil
+	}
+	if format != "" && format != findings.FormatYaml {
+		return api.NewToolCallResult(rendered, nil), nil
+	}
+	marshalled, err := output.MarshalYaml(lifecycle)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod %s lifecycle in namespace %s: %w", name, ns, err)), nil
+	}
+	return api.NewToolCallResult("# Pod startup and shutdown order\n"+marshalled+"# Findings\n"+rendered, nil), nil
+}
+
+func podsDiagnose(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ns, _ := params.GetArguments()["namespace"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to diagnose pod, missing argument name"))), nil
+	}
+	var tail int64
+	if v := params.GetArguments()["tail"]; v != nil {
+		var err error
+		if tail, err = api.ParseInt64(v); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to parse tail parameter: %w", err)), nil
+		}
+	}
+	diagnostic, err := params.PodsDiagnose(params, ns, name, tail)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose pod %s in namespace %s: %w", name, ns, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(diagnostic)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose pod %s in namespace %s: %w", name, ns, err)), nil
+	}
+	return api.NewToolCallResult("# "+diagnostic.Summary+"\n"+marshalled, nil), nil
+}
+
+func podsWhyPending(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ns, _ := params.GetArguments()["namespace"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to analyze pod scheduling, missing argument name"))), nil
+	}
+	scheduling, err := params.PodsWhyPending(params, ns, name)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod %s scheduling in namespace %s: %w", name, ns, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(scheduling)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod %s scheduling in namespace %s: %w", name, ns, err)), nil
+	}
+	return api.NewToolCallResult("# "+scheduling.Summary+"\n"+marshalled, nil), nil
+}
+
+type podsOOMReportArgs struct {
+	Namespace        string `json:"namespace"`
+	LabelSelector    string `json:"label_selector"`
+	RestartThreshold int32  `json:"restart_threshold"`
+	NodeStats        bool   `json:"node_stats"`
+}
+
+func podsOOMReport(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[podsOOMReportArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to report OOMKilled pods, %w", err)), nil
+	}
+	report, err := params.PodsOOMReport(params, kubernetes.OOMReportOptions{
+		Namespace:        args.Namespace,
+		LabelSelector:    args.LabelSelector,
+		RestartThreshold: args.RestartThreshold,
+		NodeStats:        args.NodeStats,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to report OOMKilled pods: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to report OOMKilled pods: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+report.Summary+"\n"+marshalled, nil), nil
+}
+
+type podsImagePullDebugArgs struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	NodeConfig bool   `json:"node_config"`
+}
+
+func podsImagePullDebug(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[podsImagePullDebugArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod image pull, %w", err)), nil
+	}
+	imagePull, err := params.PodsImagePullDebug(params, args.Namespace, args.Name, args.NodeConfig)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod %s image pull in namespace %s: %w", args.Name, args.Namespace, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(imagePull)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod %s image pull in namespace %s: %w", args.Name, args.Namespace, err)), nil
+	}
+	return api.NewToolCallResult("# "+imagePull.Summary+"\n"+marshalled, nil), nil
+}
+
 func podsRun(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	ns := params.GetArguments()["namespace"]
 	if ns == nil {
@@ -424,7 +1024,7 @@ func podsRun(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	image := params.GetArguments()["image"]
 	if image == nil {
-		return api.NewToolCallResult("", errors.New("failed to run pod, missing argument image")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to run pod, missing argument image"))), nil
 	}
 	port := params.GetArguments()["port"]
 	if port == nil {
@@ -432,11 +1032,11 @@ func podsRun(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	resources, err := params.PodsRun(params, ns.(string), name.(string), image.(string), int32(port.(float64)))
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to run pod %s in namespace %s: %v", name, ns, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to run pod %s in namespace %s: %w", name, ns, err)), nil
 	}
 	marshalledYaml, err := output.MarshalYaml(resources)
 	if err != nil {
-		err = fmt.Errorf("failed to run pod: %v", err)
+		err = fmt.Errorf("failed to run pod: %w", err)
 	}
 	return api.NewToolCallResult("# The following resources (YAML) have been created or updated successfully\n"+marshalledYaml, err), nil
 }
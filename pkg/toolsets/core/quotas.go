@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initQuotas() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "quotas_status",
+			Description: "List the Kubernetes ResourceQuotas and LimitRanges in the current or provided namespace, or in all namespaces, " +
+				"with the used and hard values of each quota resource, flagging the exhausted ones, and the recent create requests " +
+				"rejected by a ResourceQuota or a LimitRange (e.g. the FailedCreate events of a ReplicaSet), with the quota that rejected them. " +
+				"Optionally checks whether a new Pod with the resources of the provided Pod (e.g. another replica of a workload) is admitted, " +
+				"reporting the quotas that block it, the tracked resources its containers don't specify, and the LimitRange minimums and maximums it violates",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to get the quotas from (Optional, current namespace if not provided)",
+					},
+					"all_namespaces": {
+						Type:        "boolean",
+						Description: "Get the quotas of all namespaces (Optional, not applicable with pod)",
+						Default:     api.ToRawMessage(false),
+					},
+					"pod": {
+						Type:        "string",
+						Description: "Name of a Pod of the namespace whose resources are checked against the remaining quota and the LimitRanges (Optional)",
+					},
+					"object": {
+						Type: "string",
+						Description: "Kind/name of the object whose create requests failed, e.g. ReplicaSet/web-5d9c or Job/backup, " +
+							"a Deployment includes the denials of its ReplicaSets (Optional, the denials of all the objects if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Quotas: Status",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: quotasStatus, Permissions: []api.ResourcePermission{listResourceQuotas, listLimitRanges, getPods, listEvents}},
+	}
+}
+
+type quotasStatusArgs struct {
+	Namespace     string `json:"namespace"`
+	AllNamespaces bool   `json:"all_namespaces"`
+	Pod           string `json:"pod"`
+	Object        string `json:"object"`
+}
+
+func quotasStatus(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[quotasStatusArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get quotas status, %w", err)), nil
+	}
+	status, err := params.QuotasStatus(params, kubernetes.QuotasStatusOptions{
+		Namespace:     args.Namespace,
+		AllNamespaces: args.AllNamespaces,
+		Pod:           args.Pod,
+		Object:        args.Object,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get quotas status: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(status)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get quotas status: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+status.Summary+"\n"+marshalled, nil), nil
+}
@@ -105,7 +105,7 @@ func initResources(o internalk8s.Openshift) []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: resourcesCreateOrUpdate},
+		}, Handler: resourcesCreateOrUpdate, DryRunSupported: ptr.To(true), ManifestArgument: "resource"},
 		{Tool: api.Tool{
 			Name:        "resources_delete",
 			Description: "Delete a Kubernetes resource in the current cluster by providing its apiVersion, kind, optionally the namespace, and its name\n" + commonApiVersion,
@@ -137,10 +137,11 @@ func initResources(o internalk8s.Openshift) []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: resourcesDelete},
+		}, Handler: resourcesDelete, DryRunSupported: ptr.To(true)},
 		{Tool: api.Tool{
-			Name:        "resources_scale",
-			Description: "Get or update the scale of a Kubernetes resource in the current cluster by providing its apiVersion, kind, name, and optionally the namespace. If the scale is set in the tool call, the scale will be updated to that value. Always returns the current scale of the resource",
+			Name: "resources_scale",
+			Description: "Get or update the scale of a Kubernetes resource in the current cluster by providing its apiVersion, kind, name, and optionally the namespace. If the scale is set in the tool call, the scale will be updated to that value. " +
+				"Refuses to scale resources managed by a HorizontalPodAutoscaler unless force is set. Always returns the current scale of the resource",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
@@ -164,6 +165,11 @@ func initResources(o internalk8s.Openshift) []api.ServerTool {
 						Type:        "integer",
 						Description: "Optional scale to update the resources scale to. If not provided, will return the current scale of the resource, and not update it",
 					},
+					"force": {
+						Type:        "boolean",
+						Description: "Scale the resource even if it's managed by a HorizontalPodAutoscaler (Optional, the autoscaler may override the requested scale)",
+						Default:     api.ToRawMessage(false),
+					},
 				},
 				Required: []string{"apiVersion", "kind", "name"},
 			},
@@ -173,7 +179,73 @@ func initResources(o internalk8s.Openshift) []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: resourcesScale},
+		}, Handler: resourcesScale, DryRunSupported: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "resources_label",
+			Description: "Add or remove the labels and annotations of the Kubernetes objects of a kind selected by a label selector in the current cluster, " +
+				"in the current or provided namespace or in all namespaces. Objects that already have the requested labels and annotations are left unchanged. " +
+				"No object is changed if more objects than max_objects match the selector, use dry_run to preview the changes\n" + commonApiVersion,
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"apiVersion": {
+						Type:        "string",
+						Description: "apiVersion of the resources (examples of valid apiVersion are: v1, apps/v1, networking.k8s.io/v1)",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "kind of the resources (examples of valid kind are: Pod, Service, Deployment, Ingress)",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the objects (Optional, current namespace if not provided, ignored in case of cluster scoped resources)",
+					},
+					"all_namespaces": {
+						Type:        "boolean",
+						Description: "Select the objects of all namespaces (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+					"labelSelector": {
+						Type:        "string",
+						Description: "Kubernetes label selector of the objects to change (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)')",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"add_labels": {
+						Type:                 "object",
+						Description:          "Labels to add or update, e.g. {\"team\": \"payments\"} (Optional)",
+						AdditionalProperties: &jsonschema.Schema{Type: "string"},
+					},
+					"remove_labels": {
+						Type:        "array",
+						Description: "Keys of the labels to remove (Optional)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"add_annotations": {
+						Type:                 "object",
+						Description:          "Annotations to add or update (Optional)",
+						AdditionalProperties: &jsonschema.Schema{Type: "string"},
+					},
+					"remove_annotations": {
+						Type:        "array",
+						Description: "Keys of the annotations to remove (Optional)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"max_objects": {
+						Type:        "integer",
+						Description: "Maximum number of objects the selector can match, the call fails without changing any object if more match (Optional)",
+						Default:     api.ToRawMessage(internalk8s.ResourcesLabelMaxObjectsDefault),
+						Minimum:     ptr.To(1.0),
+					},
+				},
+				Required: []string{"apiVersion", "kind", "labelSelector"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Resources: Label",
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: resourcesLabel, DryRunSupported: ptr.To(true)},
 	}
 }
 
@@ -196,7 +268,7 @@ func resourcesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	gvk, err := parseGroupVersionKind(params.GetArguments())
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list resources, %s", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list resources, %w", err)), nil
 	}
 
 	ns, ok := namespace.(string)
@@ -206,8 +278,9 @@ func resourcesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 
 	ret, err := params.ResourcesList(params, gvk, ns, resourceListOptions)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list resources: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list resources: %w", err)), nil
 	}
+	output.RedactSecrets(ret)
 	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
 }
 
@@ -218,11 +291,11 @@ func resourcesGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	gvk, err := parseGroupVersionKind(params.GetArguments())
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get resource, %s", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get resource, %w", err)), nil
 	}
 	name := params.GetArguments()["name"]
 	if name == nil {
-		return api.NewToolCallResult("", errors.New("failed to get resource, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get resource, missing argument name"))), nil
 	}
 
 	ns, ok := namespace.(string)
@@ -237,15 +310,16 @@ func resourcesGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 
 	ret, err := params.ResourcesGet(params, gvk, ns, n)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get resource: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get resource: %w", err)), nil
 	}
+	output.RedactSecrets(ret)
 	return api.NewToolCallResult(output.MarshalYaml(ret)), nil
 }
 
 func resourcesCreateOrUpdate(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	resource := params.GetArguments()["resource"]
 	if resource == nil || resource == "" {
-		return api.NewToolCallResult("", errors.New("failed to create or update resources, missing argument resource")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to create or update resources, missing argument resource"))), nil
 	}
 
 	r, ok := resource.(string)
@@ -255,11 +329,14 @@ func resourcesCreateOrUpdate(params api.ToolHandlerParams) (*api.ToolCallResult,
 
 	resources, err := params.ResourcesCreateOrUpdate(params, r)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to create or update resources: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to create or update resources: %w", err)), nil
+	}
+	for _, resource := range resources {
+		output.RedactSecrets(resource)
 	}
 	marshalledYaml, err := output.MarshalYaml(resources)
 	if err != nil {
-		err = fmt.Errorf("failed to create or update resources:: %v", err)
+		err = fmt.Errorf("failed to create or update resources:: %w", err)
 	}
 	return api.NewToolCallResult("# The following resources (YAML) have been created or updated successfully\n"+marshalledYaml, err), nil
 }
@@ -271,11 +348,11 @@ func resourcesDelete(params api.ToolHandlerParams) (*api.ToolCallResult, error)
 	}
 	gvk, err := parseGroupVersionKind(params.GetArguments())
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to delete resource, %s", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to delete resource, %w", err)), nil
 	}
 	name := params.GetArguments()["name"]
 	if name == nil {
-		return api.NewToolCallResult("", errors.New("failed to delete resource, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to delete resource, missing argument name"))), nil
 	}
 
 	ns, ok := namespace.(string)
@@ -290,7 +367,7 @@ func resourcesDelete(params api.ToolHandlerParams) (*api.ToolCallResult, error)
 
 	err = params.ResourcesDelete(params, gvk, ns, n)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to delete resource: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to delete resource: %w", err)), nil
 	}
 	return api.NewToolCallResult("Resource deleted successfully", err), nil
 }
@@ -308,7 +385,7 @@ func resourcesScale(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 
 	name := params.GetArguments()["name"]
 	if name == nil {
-		return api.NewToolCallResult("", errors.New("failed to get/update resource scale, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get/update resource scale, missing argument name"))), nil
 	}
 
 	ns, ok := namespace.(string)
@@ -332,7 +409,8 @@ func resourcesScale(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		}
 	}
 
-	scale, err := params.ResourcesScale(params.Context, gvk, ns, n, desiredScale, shouldScale)
+	force, _ := params.GetArguments()["force"].(bool)
+	scale, warnings, err := params.ResourcesScale(params.Context, gvk, ns, n, desiredScale, shouldScale, force)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to get/update resource scale: %w", err)), nil
 	}
@@ -342,7 +420,51 @@ func resourcesScale(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		return api.NewToolCallResult("", fmt.Errorf("failed to marshall scale to yaml format: %v", scale)), nil
 	}
 
-	return api.NewToolCallResult("# Current resource scale (YAML) is below\n"+marshalled, err), nil
+	ret := ""
+	for _, warning := range warnings {
+		ret += "# Warning: " + warning + "\n"
+	}
+	return api.NewToolCallResult(ret+"# Current resource scale (YAML) is below\n"+marshalled, err), nil
+}
+
+type resourcesLabelArgs struct {
+	Namespace         string            `json:"namespace"`
+	AllNamespaces     bool              `json:"all_namespaces"`
+	LabelSelector     string            `json:"labelSelector"`
+	AddLabels         map[string]string `json:"add_labels"`
+	RemoveLabels      []string          `json:"remove_labels"`
+	AddAnnotations    map[string]string `json:"add_annotations"`
+	RemoveAnnotations []string          `json:"remove_annotations"`
+	MaxObjects        int               `json:"max_objects"`
+}
+
+func resourcesLabel(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	gvk, err := parseGroupVersionKind(params.GetArguments())
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to label resources, %w", err)), nil
+	}
+	args, err := api.ParseArguments[resourcesLabelArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to label resources, %w", err)), nil
+	}
+	report, err := params.ResourcesLabel(params, gvk, internalk8s.ResourcesLabelOptions{
+		Namespace:         args.Namespace,
+		AllNamespaces:     args.AllNamespaces,
+		LabelSelector:     args.LabelSelector,
+		AddLabels:         args.AddLabels,
+		RemoveLabels:      args.RemoveLabels,
+		AddAnnotations:    args.AddAnnotations,
+		RemoveAnnotations: args.RemoveAnnotations,
+		MaxObjects:        args.MaxObjects,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to label resources: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to label resources: %w", err)), nil
+	}
+	return api.NewPartialToolCallResult("# "+report.Summary+"\n"+marshalled, len(report.Objects), report.TargetErrors), nil
 }
 
 func parseScaleValue(desiredScale interface{}) (int64, error) {
@@ -356,11 +478,11 @@ func parseScaleValue(desiredScale interface{}) (int64, error) {
 func parseGroupVersionKind(arguments map[string]interface{}) (*schema.GroupVersionKind, error) {
 	apiVersion := arguments["apiVersion"]
 	if apiVersion == nil {
-		return nil, errors.New("missing argument apiVersion")
+		return nil, api.InvalidArgument(errors.New("missing argument apiVersion"))
 	}
 	kind := arguments["kind"]
 	if kind == nil {
-		return nil, errors.New("missing argument kind")
+		return nil, api.InvalidArgument(errors.New("missing argument kind"))
 	}
 
 	a, ok := apiVersion.(string)
@@ -370,7 +492,7 @@ func parseGroupVersionKind(arguments map[string]interface{}) (*schema.GroupVersi
 
 	gv, err := schema.ParseGroupVersion(a)
 	if err != nil {
-		return nil, errors.New("invalid argument apiVersion")
+		return nil, api.InvalidArgument(errors.New("invalid argument apiVersion"))
 	}
 	return &schema.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: kind.(string)}, nil
 }
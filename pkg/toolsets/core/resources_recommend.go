@@ -0,0 +1,130 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initResourcesRecommend() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.ResourcesRecommendKinds))
+	for _, kind := range kubernetes.ResourcesRecommendKinds {
+		kinds = append(kinds, kind)
+	}
+	sources := make([]any, 0, len(kubernetes.UsageSources))
+	for _, source := range kubernetes.UsageSources {
+		sources = append(sources, source)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "resources_recommend",
+			Description: "Recommend right-sized CPU and memory requests and limits for the containers of a Kubernetes workload (Deployment, StatefulSet, or DaemonSet) or Pod " +
+				"in the current or provided namespace. Samples the usage of its running Pods several times (metrics API, or the kubelet stats summaries if not available), " +
+				"and compares it with the requests and limits of each container: the recommended CPU request is the 95th percentile of the CPU usage and the memory request " +
+				"the maximum memory usage, plus the headroom, the limits keep their ratio to the requests. " +
+				"Optionally returns the strategic merge patch of the workload with the recommended resources, the workload is never modified",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the workload",
+						Enum:        kinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the workload (Optional, current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload",
+					},
+					"samples": {
+						Type:        "integer",
+						Description: "Number of times the usage of the Pods is sampled (Optional)",
+						Default:     api.ToRawMessage(3),
+						Minimum:     ptr.To(float64(1)),
+						Maximum:     ptr.To(float64(60)),
+					},
+					"interval": {
+						Type:        "integer",
+						Description: "Number of seconds between two samples (Optional)",
+						Default:     api.ToRawMessage(10),
+						Minimum:     ptr.To(float64(1)),
+						Maximum:     ptr.To(float64(300)),
+					},
+					"source": {
+						Type:        "string",
+						Description: "Source of the usage samples (Optional, the metrics API if available, the kubelet otherwise)",
+						Enum:        sources,
+					},
+					"headroom": {
+						Type:        "integer",
+						Description: "Percentage added to the usage for the recommended requests (Optional)",
+						Default:     api.ToRawMessage(kubernetes.DefaultResourcesRecommendHeadroom),
+						Minimum:     ptr.To(float64(0)),
+						Maximum:     ptr.To(float64(500)),
+					},
+					"patch": {
+						Type:        "boolean",
+						Description: "Return the strategic merge patch of the workload with the recommended resources, e.g. for kubectl patch --patch-file (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Resources: Recommend",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: resourcesRecommend, Permissions: []api.ResourcePermission{listPods, listPodMetrics, getNodesProxy}},
+	}
+}
+
+type resourcesRecommendArgs struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Samples   int    `json:"samples"`
+	Interval  int    `json:"interval"`
+	Source    string `json:"source"`
+	Headroom  int    `json:"headroom"`
+	Patch     bool   `json:"patch"`
+}
+
+func resourcesRecommend(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[resourcesRecommendArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to recommend resources, %w", err)), nil
+	}
+	options := kubernetes.ResourcesRecommendOptions{
+		UsageSampleOptions: kubernetes.UsageSampleOptions{
+			Source:   args.Source,
+			Samples:  args.Samples,
+			Interval: time.Duration(args.Interval) * time.Second,
+		},
+		Kind:      args.Kind,
+		Namespace: args.Namespace,
+		Name:      args.Name,
+		Headroom:  args.Headroom,
+		Patch:     args.Patch,
+	}
+	recommendation, err := params.ResourcesRecommend(params, options)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to recommend resources of %s %s: %w", args.Kind, args.Name, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(recommendation)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to recommend resources of %s %s: %w", args.Kind, args.Name, err)), nil
+	}
+	return api.NewToolCallResult("# "+recommendation.Summary+"\n"+marshalled, nil), nil
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initSecrets() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "secrets",
+			Description: "Inspect Kubernetes Secrets without exposing their values. " +
+				"Supported actions: 'list' lists the Secrets in the current or provided namespace (or all namespaces) with their type and keys, " +
+				"'get' returns a Secret with its values masked (only their size is reported). " +
+				"The decoded values are only returned with reveal=true when allowed by the server configuration, " +
+				"only request them when the user explicitly asks for them",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"action": {
+						Type:        "string",
+						Description: "Action to perform",
+						Enum:        []any{secretsActionList, secretsActionGet},
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Secrets (Optional, all namespaces for the list action and current namespace for the get action if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Secret (Required for the get action)",
+					},
+					"labelSelector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)'), use this option when you want to filter the listed Secrets by label (Optional, only applicable to the list action)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"reveal": {
+						Type:        "boolean",
+						Description: "Return the decoded values of the Secret (Optional, only applicable to the get action, requires allow_secret_reveal in the server configuration)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"action"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Secrets: Inspect",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: secrets, Permissions: []api.ResourcePermission{listSecrets}},
+	}
+}
+
+const (
+	secretsActionList = "list"
+	secretsActionGet  = "get"
+)
+
+func secrets(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	action, _ := params.GetArguments()["action"].(string)
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	switch action {
+	case secretsActionList:
+		labelSelector, _ := params.GetArguments()["labelSelector"].(string)
+		ret, err := params.SecretsList(params, namespace, labelSelector)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list secrets: %w", err)), nil
+		}
+		marshalledYaml, err := output.MarshalYaml(ret)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list secrets: %w", err)), nil
+		}
+		return api.NewToolCallResult(fmt.Sprintf("# %d Secrets found (values are not listed)\n", len(ret))+marshalledYaml, nil), nil
+	case secretsActionGet:
+		name, _ := params.GetArguments()["name"].(string)
+		if name == "" {
+			return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get secret, missing argument name"))), nil
+		}
+		reveal, _ := params.GetArguments()["reveal"].(bool)
+		ret, err := params.SecretsGet(params, namespace, name, reveal)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to get secret %s: %w", name, err)), nil
+		}
+		marshalledYaml, err := output.MarshalYaml(ret)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to get secret %s: %w", name, err)), nil
+		}
+		if !reveal {
+			return api.NewToolCallResult("# The values of the Secret are masked\n"+marshalledYaml, nil), nil
+		}
+		result := api.NewToolCallResult("# The values of the Secret are revealed as requested, don't repeat them unless the user asks for them\n"+marshalledYaml, nil)
+		result.Revealed = true
+		return result, nil
+	default:
+		return api.NewToolCallResult("", fmt.Errorf("failed to inspect secrets, unsupported action %q, supported actions: %s, %s",
+			action, secretsActionList, secretsActionGet)), nil
+	}
+}
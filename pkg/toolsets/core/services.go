@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initServices() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "services_inspect",
+			Description: "Inspect the connectivity of a Kubernetes Service in the current or provided namespace with the provided name. " +
+				"Lists the Pods matching the Service selector and the endpoints published in its EndpointSlices, " +
+				"and flags the usual causes of a Service without (ready) endpoints: selectors matching no Pods (with the Pods partially matching them), " +
+				"not ready Pods and endpoints, and target ports not exposed by the Pods. " +
+				"Optionally probes the TCP ports of the Service from a short-lived helper pod",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Service to inspect",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Service to inspect",
+					},
+					"probe": {
+						Type:        "boolean",
+						Description: "Test the TCP connection to each of the Service ports from a short-lived helper pod (Optional, default false)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Services: Inspect",
+				ReadOnlyHint:    ptr.To(false), // Creates a helper pod when probe is requested
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: servicesInspect, Permissions: []api.ResourcePermission{getServices, listPods, listEndpointSlices}},
+	}
+}
+
+func servicesInspect(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ns, _ := params.GetArguments()["namespace"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to inspect service, missing argument name"))), nil
+	}
+	probe, _ := params.GetArguments()["probe"].(bool)
+	inspection, err := params.ServicesInspect(params, ns, name, probe)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to inspect service %s in namespace %s: %w", name, ns, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(inspection)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to inspect service %s in namespace %s: %w", name, ns, err)), nil
+	}
+	return api.NewToolCallResult("# "+inspection.Summary+"\n"+marshalled, nil), nil
+}
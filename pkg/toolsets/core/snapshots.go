@@ -0,0 +1,205 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/artifacts"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+func initSnapshots() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "snapshot_create",
+			Description: "Capture the objects of the provided resource types, in the current or provided namespaces or in all namespaces, into a snapshot stored as an artifact, " +
+				"to later verify what changed (e.g. after applying changes) with snapshot_diff. " +
+				"The status of the objects is left out unless include_status is set, the Secret values are redacted. " +
+				fmt.Sprintf("A snapshot holds at most %d objects", kubernetes.SnapshotMaxObjects),
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"resources": {
+						Type:        "array",
+						Description: "Resource types of the objects, as resource or kind names optionally qualified by their group, e.g. [\"deployments\", \"configmaps\", \"ingresses.networking.k8s.io\"]",
+						Items:       &jsonschema.Schema{Type: "string"},
+						MinItems:    ptr.To(1),
+					},
+					"namespaces": {
+						Type:        "array",
+						Description: "Namespaces of the objects (Optional, current namespace if not provided, ignored in case of cluster scoped resources)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"all_namespaces": {
+						Type:        "boolean",
+						Description: "Capture the objects of all namespaces (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+					"labelSelector": {
+						Type:        "string",
+						Description: "Optional Kubernetes label selector of the objects (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)')",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"include_status": {
+						Type:        "boolean",
+						Description: "Capture and diff the status of the objects too (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"resources"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Snapshot: Create",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: snapshotCreate},
+		{Tool: api.Tool{
+			Name: "snapshot_diff",
+			Description: "Compare the live objects with a snapshot of snapshot_create, by name or artifact:// URI, and list the objects added, removed, and modified since the snapshot, " +
+				"with the field-level diffs (path, old and new values) of the modified objects. " +
+				"The objects are modified when their resourceVersion changed, the changes of the resourceVersion, generation, managedFields, " +
+				"and of the status (unless captured) are not reported",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name or artifact:// URI of the snapshot artifact",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Snapshot: Diff",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: snapshotDiff},
+	}
+}
+
+type snapshotCreateArgs struct {
+	Resources     []string `json:"resources"`
+	Namespaces    []string `json:"namespaces"`
+	AllNamespaces bool     `json:"all_namespaces"`
+	LabelSelector string   `json:"labelSelector"`
+	IncludeStatus bool     `json:"include_status"`
+}
+
+func snapshotCreate(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[snapshotCreateArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create snapshot, %w", err)), nil
+	}
+	result, err := params.SnapshotCreate(params, kubernetes.SnapshotOptions{
+		Resources:     args.Resources,
+		Namespaces:    args.Namespaces,
+		AllNamespaces: args.AllNamespaces,
+		LabelSelector: args.LabelSelector,
+		IncludeStatus: args.IncludeStatus,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create snapshot: %w", err)), nil
+	}
+	ret := result.Summary + "\n"
+	if result.Artifact.URL != "" {
+		ret += "Uploaded to " + result.Artifact.URL + "\n"
+	}
+	table := api.NewTable("KIND", "OBJECTS")
+	for _, kind := range slices.Sorted(maps.Keys(result.Objects)) {
+		table.AddRow(kind, result.Objects[kind])
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create snapshot: %w", err)), nil
+	}
+	return api.NewToolCallResult(ret+rendered, nil), nil
+}
+
+type snapshotDiffArgs struct {
+	Name string `json:"name"`
+}
+
+func snapshotDiff(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[snapshotDiffArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diff snapshot, %w", err)), nil
+	}
+	name := args.Name
+	if strings.HasPrefix(name, artifacts.URIScheme) {
+		var ok bool
+		if name, ok = artifacts.NameFromURI(args.Name); !ok {
+			return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to diff snapshot, invalid artifact URI %s", args.Name))), nil
+		}
+	}
+	diff, err := params.SnapshotDiff(params, name)
+	if errors.Is(err, artifacts.ErrNotFound) {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diff snapshot %s: %w, list the available ones with artifacts_list", name, err)), nil
+	}
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diff snapshot %s: %w", name, err)), nil
+	}
+	envelope := &api.Envelope{
+		Kind:    "SnapshotChange",
+		Items:   diff.Changes,
+		Summary: diff.Summary,
+	}
+	if len(diff.Changes) == 0 {
+		return api.NewStructuredToolCallResult(params, envelope, "# "+diff.Summary+"\nNo changes since the snapshot\n"), nil
+	}
+	table := api.NewTable("CHANGE", "APIVERSION", "KIND", "NAMESPACE", "NAME", "FIELDS")
+	for _, change := range diff.Changes {
+		fields := fmt.Sprint(len(change.Fields))
+		if change.FieldsTruncated {
+			fields += "+"
+		}
+		table.AddRow(change.Change, change.APIVersion, change.Kind, change.Namespace, change.Name, fields)
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diff snapshot %s: %w", name, err)), nil
+	}
+	ret := "# " + diff.Summary + "\n" + rendered
+	for _, change := range diff.Changes {
+		if change.Change != kubernetes.SnapshotChangeModified {
+			continue
+		}
+		ret += "# " + change.Kind + " " + strings.TrimPrefix(change.Namespace+"/"+change.Name, "/") + "\n"
+		if len(change.Fields) == 0 {
+			ret += "  (the changed values are redacted)\n"
+		}
+		for _, field := range change.Fields {
+			ret += fmt.Sprintf("  %s: %s -> %s\n", field.Path, snapshotValue(field.Old), snapshotValue(field.New))
+		}
+		if change.FieldsTruncated {
+			ret += fmt.Sprintf("  (more than %d fields changed)\n", kubernetes.SnapshotMaxFieldDiffs)
+		}
+	}
+	return api.NewStructuredToolCallResult(params, envelope, ret), nil
+}
+
+// snapshotValue renders the value of a field diff as compact JSON, <none> for missing values
+func snapshotValue(value any) string {
+	if value == nil {
+		return "<none>"
+	}
+	ret, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(ret)
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+func initTimeline() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.TimelineKinds))
+	for _, kind := range kubernetes.TimelineKinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "timeline",
+			Description: "Build the chronologically ordered incident timeline of the current or provided namespace, or of a workload (Deployment, StatefulSet, DaemonSet, or Pod) " +
+				"with its Pods, within a time window (the last hour by default), to answer what changed at a given time. Merges the events (including the ones retained by the event store), " +
+				"the Pod phase transitions (creation, scheduling, readiness, container starts and terminations, deletion) from the Pod status timestamps, " +
+				"the rollout revisions (ReplicaSets, ControllerRevisions) and conditions of the workloads, and the changes of the workloads by their field managers (managedFields)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the timeline (Optional, current namespace if not provided)",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the workload (Optional, the whole namespace if not provided, requires name)",
+						Enum:        kinds,
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload (Optional, requires kind)",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Start of the time window, either an RFC3339 timestamp (e.g. 2025-01-01T10:00:00Z) or a duration relative to now (e.g. 30m, 6h) (Optional, one hour before until if not provided)",
+					},
+					"until": {
+						Type:        "string",
+						Description: "End of the time window, either an RFC3339 timestamp (e.g. 2025-01-01T11:00:00Z) or a duration relative to now (e.g. 5m) (Optional, now if not provided)",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of entries of the timeline, the most recent entries are kept (Optional)",
+						Default:     api.ToRawMessage(kubernetes.DefaultTimelineLimit),
+						Minimum:     ptr.To(float64(1)),
+						Maximum:     ptr.To(float64(1000)),
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Timeline",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: timeline, Permissions: []api.ResourcePermission{
+			listEvents, listPods, listDeployments, listStatefulSets, listDaemonSets, listReplicaSets, listControllerRevisions}},
+	}
+}
+
+type timelineArgs struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Since     string `json:"since"`
+	Until     string `json:"until"`
+	Limit     int    `json:"limit"`
+}
+
+func timeline(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[timelineArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to build timeline, %w", err)), nil
+	}
+	options := kubernetes.TimelineOptions{Namespace: args.Namespace, Kind: args.Kind, Name: args.Name, Limit: args.Limit}
+	if options.Since, err = parseEventsTime(args.Since); err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to parse since parameter: %w", err)), nil
+	}
+	if options.Until, err = parseEventsTime(args.Until); err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to parse until parameter: %w", err)), nil
+	}
+	result, err := params.Timeline(params, options)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to build timeline: %w", err)), nil
+	}
+	envelope := &api.Envelope{
+		Kind:      "TimelineEntry",
+		Items:     result.Entries,
+		Summary:   result.Summary,
+		Truncated: result.Truncated,
+	}
+	table := api.NewTable("TIME", "SOURCE", "TYPE", "OBJECT", "REASON", "MESSAGE")
+	for _, entry := range result.Entries {
+		message := entry.Message
+		if entry.Count > 1 {
+			message = fmt.Sprintf("%s (x%d)", message, entry.Count)
+		}
+		table.AddRow(entry.Timestamp, entry.Source, entry.Type, entry.Object, entry.Reason, message)
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to build timeline: %w", err)), nil
+	}
+	ret := "# " + result.Summary + "\n" + rendered
+	for _, warning := range result.Warnings {
+		ret += "# Warning: " + warning + "\n"
+	}
+	return api.NewStructuredToolCallResult(params, envelope, ret), nil
+}
@@ -22,11 +22,27 @@ func (t *Toolset) GetDescription() string {
 
 func (t *Toolset) GetTools(o internalk8s.Openshift) []api.ServerTool {
 	return slices.Concat(
+		initApis(),
+		initArtifacts(),
+		initCertificates(),
+		initConfigUsage(),
+		initCost(),
 		initEvents(),
+		initImages(),
+		initJobs(),
 		initNamespaces(o),
 		initNodes(),
+		initOrphans(),
 		initPods(),
+		initQuotas(),
 		initResources(o),
+		initResourcesRecommend(),
+		initSecrets(),
+		initServices(),
+		initSnapshots(),
+		initTimeline(),
+		initWorkloads(),
+		initKustomize(),
 	)
 }
 
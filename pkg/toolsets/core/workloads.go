@@ -0,0 +1,184 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initWorkloads() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.WorkloadKinds))
+	for _, kind := range kubernetes.WorkloadKinds {
+		kinds = append(kinds, kind)
+	}
+	statusKinds := make([]any, 0, len(kubernetes.WorkloadStatusKinds))
+	for _, kind := range kubernetes.WorkloadStatusKinds {
+		statusKinds = append(statusKinds, kind)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "workloads_scale",
+			Description: "Scale a Kubernetes workload (Deployment, StatefulSet, or ReplicaSet) in the current or provided namespace to the provided number of replicas using the scale subresource. " +
+				"Refuses to scale workloads managed by a HorizontalPodAutoscaler unless force is set. " +
+				"Optionally waits until the workload reports the requested number of available replicas",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the workload to scale",
+						Enum:        kinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the workload (Optional, current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload to scale",
+					},
+					"replicas": {
+						Type:        "integer",
+						Description: "Desired number of replicas",
+						Minimum:     ptr.To(float64(0)),
+					},
+					"force": {
+						Type:        "boolean",
+						Description: "Scale the workload even if it's managed by a HorizontalPodAutoscaler (Optional, the autoscaler may override the requested replicas)",
+						Default:     api.ToRawMessage(false),
+					},
+					"wait_ready": {
+						Type:        "boolean",
+						Description: "Wait until the workload reports the requested number of available replicas (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+					"timeout": {
+						Type:        "integer",
+						Description: "Maximum number of seconds to wait for the workload to become ready when wait_ready is set (Optional)",
+						Default:     api.ToRawMessage(300),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+				Required: []string{"kind", "name", "replicas"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Workloads: Scale",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: workloadsScale, DryRunSupported: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "workloads_status",
+			Description: "Get a compact status summary of a Kubernetes workload (Deployment, StatefulSet, or DaemonSet) in the current or provided namespace. " +
+				"Merges the desired state (spec) with the observed state (status): desired, ready, updated, and available replicas, rollout progress, conditions, " +
+				"the HorizontalPodAutoscaler targeting the workload, the most recent ReplicaSets of a Deployment, the failing Pods counted by reason (e.g. CrashLoopBackOff, ImagePullBackOff, OOMKilled), " +
+				"the explained container terminations (exit codes, OOMKilled) and the most recent events of the workload and its Pods " +
+				"(the equivalent of kubectl get, describe, and events in a single call). " +
+				"Correlates them into a single health verdict (Healthy, Updating, Degraded, or Unavailable) with its reasons",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the workload",
+						Enum:        statusKinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the workload (Optional, current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload",
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Workloads: Status",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: workloadsStatus},
+	}
+}
+
+func workloadsScale(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	options := kubernetes.WorkloadsScaleOptions{}
+	options.Kind, _ = params.GetArguments()["kind"].(string)
+	if options.Kind == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to scale workload, missing argument kind"))), nil
+	}
+	options.Name, _ = params.GetArguments()["name"].(string)
+	if options.Name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to scale workload, missing argument name"))), nil
+	}
+	replicas, ok := params.GetArguments()["replicas"]
+	if !ok {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to scale workload, missing argument replicas"))), nil
+	}
+	replicasInt, err := api.ParseInt64(replicas)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to parse replicas parameter: %w", err)), nil
+	}
+	if replicasInt < 0 {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale workload, replicas must be greater than or equal to 0, got %d", replicasInt)), nil
+	}
+	options.Replicas = int32(replicasInt)
+	options.Namespace, _ = params.GetArguments()["namespace"].(string)
+	options.Force, _ = params.GetArguments()["force"].(bool)
+	options.WaitReady, _ = params.GetArguments()["wait_ready"].(bool)
+	if timeout, ok := params.GetArguments()["timeout"]; ok {
+		timeoutInt, err := api.ParseInt64(timeout)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to parse timeout parameter: %w", err)), nil
+		}
+		options.Timeout = time.Duration(timeoutInt) * time.Second
+	}
+
+	result, err := params.WorkloadsScale(params, options)
+	if result == nil && err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale %s %s: %w", options.Kind, options.Name, err)), nil
+	}
+	marshalled, marshalErr := output.MarshalYaml(result)
+	if marshalErr != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale %s %s: %v", options.Kind, options.Name, marshalErr)), nil
+	}
+	if err != nil {
+		// The workload was scaled, but it didn't become ready in time
+		return api.NewToolCallResult("", fmt.Errorf("%v\n%s", err, marshalled)), nil
+	}
+	return api.NewToolCallResult("# The workload has been scaled successfully\n"+marshalled, nil), nil
+}
+
+func workloadsStatus(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if kind == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get workload status, missing argument kind"))), nil
+	}
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get workload status, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	status, err := params.WorkloadsStatus(params, kind, namespace, name)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get status of %s %s: %w", kind, name, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(status)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get status of %s %s: %w", kind, name, err)), nil
+	}
+	return api.NewToolCallResult("# "+status.Summary+"\n"+marshalled, nil), nil
+}
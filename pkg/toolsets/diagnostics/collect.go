@@ -0,0 +1,128 @@
+package diagnostics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// collectPermissions are the Kubernetes API permissions needed by diagnostics_collect, reviewed by the self_check tool:
+// the node permissions of the node tools (kubelet logs and stats, node_files helper pods) and the Pods and events of
+// every namespace
+var collectPermissions = []api.ResourcePermission{
+	{Verb: "list", Resource: "nodes", ClusterWide: true},
+	{Verb: "get", Resource: "nodes", ClusterWide: true},
+	{Verb: "get", Resource: "nodes", Subresource: "proxy", ClusterWide: true},
+	{Verb: "list", Resource: "pods", ClusterWide: true},
+	{Verb: "list", Resource: "events", ClusterWide: true},
+	{Verb: "create", Resource: "pods"},
+	{Verb: "get", Resource: "pods"},
+	{Verb: "get", Resource: "pods", Subresource: "log"},
+	{Verb: "delete", Resource: "pods"},
+}
+
+func initCollect() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "diagnostics_collect",
+			Description: "Collect a diagnostic bundle (like sos report or must-gather) into a .tar.gz archive: " +
+				"the Nodes, the Pods and events of the selected namespaces, and the kubelet logs, kubelet stats summary, and selected files of the selected nodes. " +
+				"The bundle is stored in the output directory of the server and uploaded to the configured S3-compatible object storage, " +
+				"the tool returns its location and the list of collected files. " +
+				"Nodes and namespaces that can't be collected are reported without failing the collection",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespaces": {
+						Type:        "array",
+						Description: "Namespaces whose Pods and events are collected (Optional, defaults to all namespaces)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"nodes": {
+						Type:        "array",
+						Description: "Names of the nodes whose kubelet logs, stats, and files are collected (Optional, defaults to all nodes)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"node_files": {
+						Type: "array",
+						Description: "Absolute paths of the files collected from every selected node, e.g. [\"/etc/kubernetes/kubelet.conf\", \"/var/log/messages\"] " +
+							"(Optional, read from a privileged helper pod, missing files are skipped)",
+						Items: &jsonschema.Schema{Type: "string"},
+					},
+					"log_lines": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Number of kubelet log lines collected per node (Optional, defaults to %d)", kubernetes.DiagnosticsDefaultLogLines),
+						Default:     api.ToRawMessage(kubernetes.DiagnosticsDefaultLogLines),
+						Minimum:     ptr.To(1.0),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Diagnostics: Collect",
+				ReadOnlyHint:    ptr.To(false), // Writes the bundle and creates helper pods to read the node files
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: diagnosticsCollect, Permissions: collectPermissions},
+	}
+}
+
+type diagnosticsCollectArgs struct {
+	Namespaces []string `json:"namespaces"`
+	Nodes      []string `json:"nodes"`
+	NodeFiles  []string `json:"node_files"`
+	LogLines   int64    `json:"log_lines"`
+}
+
+type diagnosticBundle struct {
+	Bundle string                 `json:"bundle"`
+	URL    string                 `json:"url,omitempty"`
+	Files  []diagnosticBundleFile `json:"files"`
+}
+
+type diagnosticBundleFile struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+func diagnosticsCollect(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[diagnosticsCollectArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to collect diagnostics, %w", err)), nil
+	}
+	files, targetErrors, err := params.DiagnosticsCollect(params, kubernetes.DiagnosticsCollectOptions{
+		Namespaces: args.Namespaces,
+		Nodes:      args.Nodes,
+		NodeFiles:  args.NodeFiles,
+		LogLines:   args.LogLines,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to collect diagnostics: %w", err)), nil
+	}
+	name := "diagnostics-" + time.Now().UTC().Format("20060102-150405")
+	params.ReportProgress("Storing the diagnostic bundle %s with %d files", name, len(files))
+	localPath, objectURL, err := params.NewDiagnostics().Store(params, name, files)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to store diagnostic bundle: %w", err)), nil
+	}
+	bundle := diagnosticBundle{Bundle: localPath, URL: objectURL, Files: make([]diagnosticBundleFile, 0, len(files))}
+	for _, file := range files {
+		bundle.Files = append(bundle.Files, diagnosticBundleFile{Name: file.Name, Size: len(file.Content)})
+	}
+	marshalled, err := output.MarshalYaml(bundle)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to collect diagnostics: %w", err)), nil
+	}
+	summary := fmt.Sprintf("# Diagnostic bundle with %d files written to %s", len(files), localPath)
+	if objectURL != "" {
+		summary += " and uploaded to " + objectURL
+	}
+	return api.NewPartialToolCallResult(summary+"\n"+marshalled, len(files), targetErrors), nil
+}
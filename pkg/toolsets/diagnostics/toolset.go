@@ -0,0 +1,31 @@
+package diagnostics
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "diagnostics"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Collection of diagnostic bundles (nodes, pods, events, kubelet logs and stats, node files) stored locally or uploaded to an S3-compatible object storage, check the [diagnostics documentation](https://github.com/containers/kubernetes-mcp-server/blob/main/docs/DIAGNOSTICS.md) for more details."
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return slices.Concat(
+		initCollect(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
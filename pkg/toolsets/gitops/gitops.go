@@ -0,0 +1,235 @@
+package gitops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/gitops"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+var (
+	// readGitOps are the permissions needed to read the GitOps objects and the objects they manage
+	readGitOps = []api.ResourcePermission{
+		{Verb: "list", Group: gitops.ApplicationGVR.Group, Resource: gitops.ApplicationGVR.Resource},
+		{Verb: "list", Group: gitops.KustomizationGVR.Group, Resource: gitops.KustomizationGVR.Resource},
+	}
+	// syncGitOps are the permissions needed to request the sync of the GitOps objects
+	syncGitOps = []api.ResourcePermission{
+		{Verb: "patch", Group: gitops.ApplicationGVR.Group, Resource: gitops.ApplicationGVR.Resource},
+		{Verb: "patch", Group: gitops.KustomizationGVR.Group, Resource: gitops.KustomizationGVR.Resource},
+	}
+)
+
+func initGitOps() []api.ServerTool {
+	kinds := make([]any, 0, len(gitops.Kinds))
+	for _, kind := range gitops.Kinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "gitops_drift",
+			Description: "Detect the drift of the objects managed by GitOps controllers, Argo CD Applications (argoproj.io) and Flux Kustomizations (kustomize.toolkit.fluxcd.io). " +
+				"Without name, lists the Applications and Kustomizations in the provided namespace or in all namespaces with their sync status, health, revision, and source. " +
+				"With a kind and a name, reports the managed objects that drifted: the missing objects, the objects changed by other field managers after the last apply of the controller, " +
+				"and the objects reported out of sync by Argo CD. With a manifest (the desired rendered source, e.g. the output of kustomize_build or helm template), " +
+				"the fields of the desired objects are compared with the live objects instead, and the managed objects missing from the manifest are reported as extra. " +
+				"Trigger a sync with gitops_sync",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the GitOps object (Optional, lists both kinds if not provided, required with name)",
+						Enum:        kinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the GitOps objects (Optional, all namespaces if not provided when listing, current namespace if not provided with name)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Application or Kustomization whose drift is computed (Optional, lists the GitOps objects if not provided)",
+					},
+					"manifest": {
+						Type: "string",
+						Description: "Desired rendered source of the Application or Kustomization as a multi-document YAML manifest, " +
+							"the objects without namespace are in the destination namespace (Optional, the drift is computed from the state of the controller if not provided)",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "GitOps: Drift",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: gitopsDrift, Permissions: readGitOps},
+		{Tool: api.Tool{
+			Name: "gitops_sync",
+			Description: "Request the sync of an Argo CD Application (like argocd app sync) or the reconciliation of a Flux Kustomization (like flux reconcile kustomization). " +
+				"The controller applies the desired revision asynchronously, follow the progress with gitops_drift",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the GitOps object",
+						Enum:        kinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Application or Kustomization (Optional, current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Application or Kustomization",
+					},
+					"prune": {
+						Type:        "boolean",
+						Description: "Delete the objects of an Argo CD Application that are no longer in its source (Optional, not supported by Flux whose pruning is set by the Kustomization)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "GitOps: Sync",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: gitopsSync, Permissions: syncGitOps, DryRunSupported: ptr.To(true)},
+	}
+}
+
+type gitopsDriftArgs struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Manifest  string `json:"manifest"`
+}
+
+func gitopsDrift(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[gitopsDriftArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to detect GitOps drift, %w", err)), nil
+	}
+	if args.Name == "" {
+		if args.Manifest != "" {
+			return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to detect GitOps drift, manifest requires kind and name"))), nil
+		}
+		return gitopsList(params, args)
+	}
+	if args.Kind == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to detect GitOps drift, kind is required with name"))), nil
+	}
+	result, err := params.GitOpsDrift(params, kubernetes.GitOpsDriftOptions{Kind: args.Kind, Namespace: args.Namespace, Name: args.Name, Manifest: args.Manifest})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to detect GitOps drift: %w", err)), nil
+	}
+	envelope := &api.Envelope{Kind: "GitOpsDrift", Items: result.Drift, Summary: result.Summary, Truncated: result.Truncated}
+	ret := strings.Builder{}
+	ret.WriteString("# " + result.Summary + "\n")
+	ret.WriteString("# Source: " + result.App.Source + "\n")
+	if result.App.Message != "" {
+		ret.WriteString("# Message: " + result.App.Message + "\n")
+	}
+	if result.App.Suspended {
+		ret.WriteString("# The Kustomization is suspended, it's not reconciled\n")
+	}
+	if len(result.Drift) > 0 {
+		table := api.NewTable("DRIFT", "KIND", "NAMESPACE", "NAME", "REASON")
+		for _, drift := range result.Drift {
+			kind := drift.Kind
+			if drift.Group != "" {
+				kind += "." + drift.Group
+			}
+			table.AddRow(drift.Drift, kind, drift.Namespace, drift.Name, drift.Reason)
+		}
+		rendered, err := api.Render(table, api.RenderTable)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to detect GitOps drift: %w", err)), nil
+		}
+		ret.WriteString(rendered)
+	}
+	for _, drift := range result.Drift {
+		if len(drift.Fields) == 0 {
+			continue
+		}
+		ret.WriteString("# " + drift.Resource.String() + "\n")
+		for _, field := range drift.Fields {
+			ret.WriteString(fmt.Sprintf("  %s: desired %s, live %s\n", field.Path, gitopsValue(field.Desired), gitopsValue(field.Live)))
+		}
+		if drift.FieldsTruncated {
+			ret.WriteString(fmt.Sprintf("  (more than %d fields differ)\n", gitops.MaxFieldDiffs))
+		}
+	}
+	return api.NewStructuredPartialToolCallResult(params, envelope, ret.String(), result.Checked, result.TargetErrors), nil
+}
+
+func gitopsList(params api.ToolHandlerParams, args *gitopsDriftArgs) (*api.ToolCallResult, error) {
+	result, err := params.GitOpsList(params, args.Namespace, args.Kind)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list GitOps objects: %w", err)), nil
+	}
+	envelope := &api.Envelope{Kind: "GitOpsApp", Items: result.Apps, Summary: result.Summary}
+	// GitOpsList fails when none of the kinds can be listed
+	succeeded := len(gitops.Kinds) - len(result.NotInstalled) - len(result.TargetErrors)
+	if len(result.Apps) == 0 {
+		return api.NewStructuredPartialToolCallResult(params, envelope, "# "+result.Summary+"\nNo GitOps objects found\n", succeeded, result.TargetErrors), nil
+	}
+	table := api.NewTable("KIND", "NAMESPACE", "NAME", "SYNC", "HEALTH", "REVISION", "SOURCE", "MESSAGE")
+	for _, app := range result.Apps {
+		sync := app.SyncStatus
+		if app.Suspended {
+			sync += " (suspended)"
+		}
+		table.AddRow(app.Kind, app.Namespace, app.Name, sync, app.Health, app.Revision, app.Source, app.Message)
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list GitOps objects: %w", err)), nil
+	}
+	return api.NewStructuredPartialToolCallResult(params, envelope, "# "+result.Summary+"\n"+rendered, succeeded, result.TargetErrors), nil
+}
+
+type gitopsSyncArgs struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Prune     bool   `json:"prune"`
+}
+
+func gitopsSync(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[gitopsSyncArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to sync GitOps object, %w", err)), nil
+	}
+	ret, err := params.GitOpsSync(params, args.Kind, args.Namespace, args.Name, args.Prune)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to sync %s %s: %w", args.Kind, args.Name, err)), nil
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
+
+// gitopsValue renders the value of a field diff as compact JSON, <none> for missing values
+func gitopsValue(value any) string {
+	if value == nil {
+		return "<none>"
+	}
+	ret, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(ret)
+}
@@ -0,0 +1,31 @@
+package gitops
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "gitops"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "GitOps drift detection for Argo CD Applications and Flux Kustomizations: sync status, drift of the managed objects from the controller state or from a rendered manifest, and sync requests"
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return slices.Concat(
+		initGitOps(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
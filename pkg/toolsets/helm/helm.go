@@ -43,7 +43,7 @@ func initHelm() []api.ServerTool {
 				IdempotentHint:  nil, // TODO: consider replacing implementation with equivalent to: helm upgrade --install
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: helmInstall},
+		}, Handler: helmInstall, Permissions: []api.ResourcePermission{{Verb: "create", Resource: "secrets"}}},
 		{Tool: api.Tool{
 			Name:        "helm_list",
 			Description: "List all the Helm releases in the current or provided namespace (or in all namespaces if specified)",
@@ -66,7 +66,7 @@ func initHelm() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: helmList},
+		}, Handler: helmList, Permissions: []api.ResourcePermission{{Verb: "list", Resource: "secrets"}}},
 		{Tool: api.Tool{
 			Name:        "helm_uninstall",
 			Description: "Uninstall a Helm release in the current or provided namespace",
@@ -90,7 +90,7 @@ func initHelm() []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: helmUninstall},
+		}, Handler: helmUninstall, Permissions: []api.ResourcePermission{{Verb: "delete", Resource: "secrets"}}},
 	}
 }
 
@@ -98,7 +98,7 @@ func helmInstall(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	var chart string
 	ok := false
 	if chart, ok = params.GetArguments()["chart"].(string); !ok {
-		return api.NewToolCallResult("", fmt.Errorf("failed to install helm chart, missing argument chart")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to install helm chart, missing argument chart"))), nil
 	}
 	values := map[string]interface{}{}
 	if v, ok := params.GetArguments()["values"].(map[string]interface{}); ok {
@@ -139,7 +139,7 @@ func helmUninstall(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	var name string
 	ok := false
 	if name, ok = params.GetArguments()["name"].(string); !ok {
-		return api.NewToolCallResult("", fmt.Errorf("failed to uninstall helm chart, missing argument name")), nil
+		return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to uninstall helm chart, missing argument name"))), nil
 	}
 	namespace := ""
 	if v, ok := params.GetArguments()["namespace"].(string); ok {
@@ -100,7 +100,7 @@ func getMeshGraphHandler(params api.ToolHandlerParams) (*api.ToolCallResult, err
 	k := params.NewKiali()
 	content, err := k.GetMeshGraph(params.Context, namespaces, queryParams)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to retrieve mesh graph: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to retrieve mesh graph: %w", err)), nil
 	}
 	return api.NewToolCallResult(content, nil), nil
 }
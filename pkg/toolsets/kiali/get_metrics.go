@@ -163,7 +163,7 @@ func resourceMetricsHandler(params api.ToolHandlerParams) (*api.ToolCallResult,
 
 	content, err := ops.metricsFunc(params.Context, k, namespace, resourceName, queryParams)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get %s metrics: %v", ops.singularName, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get %s metrics: %w", ops.singularName, err)), nil
 	}
 	return api.NewToolCallResult(content, nil), nil
 }
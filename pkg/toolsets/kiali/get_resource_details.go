@@ -105,7 +105,7 @@ func resourceDetailsHandler(params api.ToolHandlerParams) (*api.ToolCallResult,
 		}
 		content, err := ops.detailsFunc(params.Context, k, namespaces, resourceName)
 		if err != nil {
-			return api.NewToolCallResult("", fmt.Errorf("failed to get %s details: %v", ops.singularName, err)), nil
+			return api.NewToolCallResult("", fmt.Errorf("failed to get %s details: %w", ops.singularName, err)), nil
 		}
 		return api.NewToolCallResult(content, nil), nil
 	}
@@ -113,7 +113,7 @@ func resourceDetailsHandler(params api.ToolHandlerParams) (*api.ToolCallResult,
 	// Otherwise, list resources (supports multiple namespaces)
 	content, err := ops.listFunc(params.Context, k, namespaces)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to list %ss: %v", ops.singularName, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to list %ss: %w", ops.singularName, err)), nil
 	}
 	return api.NewToolCallResult(content, nil), nil
 }
@@ -118,7 +118,7 @@ func TracesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		traceId := strings.TrimSpace(traceIdVal)
 		content, err := k.TraceDetails(params.Context, traceId)
 		if err != nil {
-			return api.NewToolCallResult("", fmt.Errorf("failed to get trace details: %v", err)), nil
+			return api.NewToolCallResult("", fmt.Errorf("failed to get trace details: %w", err)), nil
 		}
 		return api.NewToolCallResult(content, nil), nil
 	}
@@ -169,7 +169,7 @@ func TracesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		// Parse startMicros to calculate endMicros
 		startMicrosInt, err := strconv.ParseInt(startMicros, 10, 64)
 		if err != nil {
-			return api.NewToolCallResult("", fmt.Errorf("invalid startMicros value: %v", err)), nil
+			return api.NewToolCallResult("", fmt.Errorf("invalid startMicros value: %w", err)), nil
 		}
 		startTime := time.UnixMicro(startMicrosInt)
 		endTime := startTime.Add(10 * time.Minute)
@@ -194,7 +194,7 @@ func TracesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	content, err := ops.tracesFunc(params.Context, k, namespace, resourceName, queryParams)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get %s traces: %v", ops.singularName, err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get %s traces: %w", ops.singularName, err)), nil
 	}
 	return api.NewToolCallResult(content, nil), nil
 }
@@ -125,9 +125,9 @@ func setQueryParam(params api.ToolHandlerParams, queryParams map[string]string,
 	if err != nil {
 		switch key {
 		case "duration", "rateInterval":
-			return fmt.Errorf("invalid %s: %v, values must be in the format '10m', '5m', '1h', '2d' or seconds", key, err)
+			return fmt.Errorf("invalid %s: %w, values must be in the format '10m', '5m', '1h', '2d' or seconds", key, err)
 		default:
-			return fmt.Errorf("invalid %s: %v", key, err)
+			return fmt.Errorf("invalid %s: %w", key, err)
 		}
 	}
 	queryParams[key] = v
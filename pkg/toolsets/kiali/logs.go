@@ -97,7 +97,7 @@ func workloadLogsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, err
 	if container == "" {
 		workloadDetails, err := k.WorkloadDetails(params.Context, namespace, workload)
 		if err != nil {
-			return api.NewToolCallResult("", fmt.Errorf("failed to get workload details: %v", err)), nil
+			return api.NewToolCallResult("", fmt.Errorf("failed to get workload details: %w", err)), nil
 		}
 
 		// Parse the workload details JSON to extract container names
@@ -111,7 +111,7 @@ func workloadLogsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, err
 		}
 
 		if err := json.Unmarshal([]byte(workloadDetails), &workloadData); err != nil {
-			return api.NewToolCallResult("", fmt.Errorf("failed to parse workload details: %v", err)), nil
+			return api.NewToolCallResult("", fmt.Errorf("failed to parse workload details: %w", err)), nil
 		}
 
 		if len(workloadData.Pods) == 0 {
@@ -144,7 +144,7 @@ func workloadLogsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, err
 	// Use the WorkloadLogs method with the correct parameters
 	logs, err := k.WorkloadLogs(params.Context, namespace, workload, container, service, duration, logType, sinceTime, maxLines)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to get workload logs: %v", err)), nil
+		return api.NewToolCallResult("", fmt.Errorf("failed to get workload logs: %w", err)), nil
 	}
 
 	return api.NewToolCallResult(logs, nil), nil
@@ -76,7 +76,8 @@ func Tools() []api.ServerTool {
 					OpenWorldHint:   ptr.To(false),
 				},
 			},
-			Handler: create,
+			Handler:         create,
+			DryRunSupported: ptr.To(true),
 		},
 	}
 }
@@ -0,0 +1,72 @@
+package machines
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// readAutoscaler are the permissions needed to read the state and the events of the node autoscalers
+var readAutoscaler = []api.ResourcePermission{
+	{Verb: "get", Resource: "configmaps"},
+	{Verb: "list", Resource: "events"},
+	{Verb: "list", Group: "karpenter.sh", Resource: "nodepools"},
+	{Verb: "list", Group: "karpenter.sh", Resource: "nodeclaims"},
+}
+
+func initAutoscaler() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "autoscaler_status",
+			Description: "Get the status of the node autoscalers of the cluster: the cluster-autoscaler (from its cluster-autoscaler-status ConfigMap, with the health, scale-up and scale-down status, " +
+				"and the sizes and backoff of its node groups) and Karpenter (its NodePools with their resources, limits, and consolidation policy, and its NodeClaims with their lifecycle status), " +
+				"with the most recent events explaining their scale-up and scale-down decisions. " +
+				"With a pod, explains why the pending Pod did or didn't trigger a scale-up from its scheduling condition and the events of the autoscalers",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"pod": {
+						Type:        "string",
+						Description: "Name of a pending Pod whose scale-up decision is explained (Optional)",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod (Optional, current namespace if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Autoscaler: Status",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: autoscalerStatus, Permissions: readAutoscaler},
+	}
+}
+
+type autoscalerStatusArgs struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+}
+
+func autoscalerStatus(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[autoscalerStatusArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get autoscaler status, %w", err)), nil
+	}
+	status, err := params.AutoscalerStatus(params, args.Namespace, args.Pod)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get autoscaler status: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(status)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get autoscaler status: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+status.Summary+"\n"+marshalled, nil), nil
+}
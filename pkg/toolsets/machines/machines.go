@@ -0,0 +1,288 @@
+package machines
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/machines"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+var (
+	// readMachines are the permissions needed to read the Machines and MachineSets
+	readMachines = []api.ResourcePermission{
+		{Verb: "list", Group: machines.GroupClusterAPI, Resource: "machines"},
+		{Verb: "list", Group: machines.GroupClusterAPI, Resource: "machinesets"},
+		{Verb: "list", Group: machines.GroupOpenShift, Resource: "machines"},
+		{Verb: "list", Group: machines.GroupOpenShift, Resource: "machinesets"},
+	}
+	// scaleMachineSets are the permissions needed to scale the MachineSets
+	scaleMachineSets = []api.ResourcePermission{
+		{Verb: "patch", Group: machines.GroupClusterAPI, Resource: "machinesets", Subresource: "scale"},
+		{Verb: "patch", Group: machines.GroupOpenShift, Resource: "machinesets", Subresource: "scale"},
+	}
+)
+
+func initMachines() []api.ServerTool {
+	groups := make([]any, 0, len(machines.Groups))
+	for _, group := range machines.Groups {
+		groups = append(groups, group)
+	}
+	apiGroup := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{Type: "string", Description: description, Enum: groups}
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "machines_list",
+			Description: "List the Machines of Cluster API (cluster.x-k8s.io) and of the OpenShift Machine API (machine.openshift.io) in the provided namespace or in all namespaces, " +
+				"with their MachineSet, phase, Node, instance type or infrastructure machine, zone, and failure message",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"api_group": apiGroup("API group of the Machines (Optional, both APIs if not provided)"),
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Machines (Optional, all namespaces if not provided)",
+					},
+					"machineset": {
+						Type:        "string",
+						Description: "Name of the MachineSet whose Machines are listed (Optional)",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Machines: List",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: machinesList, Permissions: readMachines},
+		{Tool: api.Tool{
+			Name: "machinesets_list",
+			Description: "List the MachineSets (node pools) of Cluster API (cluster.x-k8s.io) and of the OpenShift Machine API (machine.openshift.io) in the provided namespace or in all namespaces, " +
+				"with their desired, current, ready, and available replicas, the bounds of the cluster autoscaler, the owning MachineDeployment, instance type or infrastructure template, and zone",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"api_group": apiGroup("API group of the MachineSets (Optional, both APIs if not provided)"),
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the MachineSets (Optional, all namespaces if not provided)",
+					},
+					api.OutputFormatParameterName: api.OutputFormatProperty(),
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "MachineSets: List",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: machineSetsList, Permissions: readMachines},
+		{Tool: api.Tool{
+			Name: "machines_for_node",
+			Description: "Get the Machine backing a Node and the MachineSet of the Machine, from the machine annotation of the Node or else from the node reference or provider ID of the Machines. " +
+				"Reports the Nodes not backed by a Machine (e.g. provisioned by hand)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"node": {
+						Type:        "string",
+						Description: "Name of the Node",
+					},
+				},
+				Required: []string{"node"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Machines: For Node",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: machinesForNode, Permissions: readMachines},
+		{Tool: api.Tool{
+			Name: "machinesets_scale",
+			Description: "Scale a Cluster API or OpenShift MachineSet (node pool) to the provided number of replicas using the scale subresource, " +
+				"scaling down deletes Machines and drains their Nodes. " +
+				"Refuses to scale the MachineSets owned by a Cluster API MachineDeployment, and outside of the bounds of the cluster autoscaler unless force is set. " +
+				"The Machines are provisioned asynchronously, follow the progress with machines_list",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"api_group": apiGroup("API group of the MachineSet (Optional, the installed API if not provided, required if both are installed)"),
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the MachineSet (Optional, openshift-machine-api for the OpenShift Machine API or the current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the MachineSet to scale",
+					},
+					"replicas": {
+						Type:        "integer",
+						Description: "Desired number of Machines",
+						Minimum:     ptr.To(float64(0)),
+					},
+					"force": {
+						Type:        "boolean",
+						Description: "Scale the MachineSet outside of the bounds of the cluster autoscaler (Optional, the autoscaler may override the requested replicas)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"name", "replicas"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "MachineSets: Scale",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: machineSetsScale, Permissions: scaleMachineSets, DryRunSupported: ptr.To(true)},
+	}
+}
+
+type machinesListArgs struct {
+	APIGroup   string `json:"api_group"`
+	Namespace  string `json:"namespace"`
+	MachineSet string `json:"machineset"`
+}
+
+func machinesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[machinesListArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list machines, %w", err)), nil
+	}
+	result, err := params.MachinesList(params, kubernetes.MachinesListOptions{APIGroup: args.APIGroup, Namespace: args.Namespace, MachineSet: args.MachineSet})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list machines: %w", err)), nil
+	}
+	envelope := &api.Envelope{Kind: "Machine", Items: result.Machines, Summary: result.Summary}
+	// MachinesList fails when none of the installed APIs can be listed
+	succeeded := len(machines.APIs) - len(result.NotInstalled) - len(result.TargetErrors)
+	if len(result.Machines) == 0 {
+		return api.NewStructuredPartialToolCallResult(params, envelope, "# "+result.Summary+"\nNo Machines found\n", succeeded, result.TargetErrors), nil
+	}
+	table := api.NewTable("APIGROUP", "NAMESPACE", "NAME", "MACHINESET", "PHASE", "NODE", "TYPE", "ZONE", "MESSAGE")
+	for _, machine := range result.Machines {
+		table.AddRow(machine.APIGroup, machine.Namespace, machine.Name, machine.MachineSet, machine.Phase, machine.Node,
+			machinesType(machine.InstanceType, machine.Infrastructure), machine.Zone, machine.Message)
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list machines: %w", err)), nil
+	}
+	return api.NewStructuredPartialToolCallResult(params, envelope, "# "+result.Summary+"\n"+rendered, succeeded, result.TargetErrors), nil
+}
+
+type machineSetsListArgs struct {
+	APIGroup  string `json:"api_group"`
+	Namespace string `json:"namespace"`
+}
+
+func machineSetsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[machineSetsListArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list machine sets, %w", err)), nil
+	}
+	result, err := params.MachineSetsList(params, args.APIGroup, args.Namespace)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list machine sets: %w", err)), nil
+	}
+	envelope := &api.Envelope{Kind: "MachineSet", Items: result.MachineSets, Summary: result.Summary}
+	succeeded := len(machines.APIs) - len(result.NotInstalled) - len(result.TargetErrors)
+	if len(result.MachineSets) == 0 {
+		return api.NewStructuredPartialToolCallResult(params, envelope, "# "+result.Summary+"\nNo MachineSets found\n", succeeded, result.TargetErrors), nil
+	}
+	table := api.NewTable("APIGROUP", "NAMESPACE", "NAME", "DESIRED", "CURRENT", "READY", "AVAILABLE", "AUTOSCALER", "OWNER", "TYPE", "ZONE", "MESSAGE")
+	for _, machineSet := range result.MachineSets {
+		autoscaler := ""
+		if machineSet.MinSize != nil || machineSet.MaxSize != nil {
+			autoscaler = fmt.Sprintf("%s-%s", machinesSize(machineSet.MinSize), machinesSize(machineSet.MaxSize))
+		}
+		owner := ""
+		if machineSet.MachineDeployment != "" {
+			owner = "MachineDeployment/" + machineSet.MachineDeployment
+		}
+		table.AddRow(machineSet.APIGroup, machineSet.Namespace, machineSet.Name, machineSet.Replicas, machineSet.CurrentReplicas, machineSet.ReadyReplicas,
+			machineSet.AvailableReplicas, autoscaler, owner, machinesType(machineSet.InstanceType, machineSet.Infrastructure), machineSet.Zone, machineSet.Message)
+	}
+	rendered, err := api.Render(table, api.RenderTable)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list machine sets: %w", err)), nil
+	}
+	return api.NewStructuredPartialToolCallResult(params, envelope, "# "+result.Summary+"\n"+rendered, succeeded, result.TargetErrors), nil
+}
+
+type machinesForNodeArgs struct {
+	Node string `json:"node"`
+}
+
+func machinesForNode(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[machinesForNodeArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get the machine of the node, %w", err)), nil
+	}
+	result, err := params.MachinesForNode(params, args.Node)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get the machine of node %s: %w", args.Node, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(result)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get the machine of node %s: %w", args.Node, err)), nil
+	}
+	return api.NewToolCallResult("# "+result.Summary+"\n"+marshalled, nil), nil
+}
+
+type machineSetsScaleArgs struct {
+	APIGroup  string `json:"api_group"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Replicas  int64  `json:"replicas"`
+	Force     bool   `json:"force"`
+}
+
+func machineSetsScale(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[machineSetsScaleArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale machine set, %w", err)), nil
+	}
+	result, err := params.MachineSetsScale(params, kubernetes.MachineSetsScaleOptions{
+		APIGroup:  args.APIGroup,
+		Namespace: args.Namespace,
+		Name:      args.Name,
+		Replicas:  args.Replicas,
+		Force:     args.Force,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale MachineSet %s: %w", args.Name, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(result)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale MachineSet %s: %w", args.Name, err)), nil
+	}
+	return api.NewToolCallResult("# The MachineSet has been scaled successfully, the Machines are reconciled asynchronously\n"+marshalled, nil), nil
+}
+
+// machinesType returns the instance type of the OpenShift Machines or the infrastructure machine of the Cluster API ones
+func machinesType(instanceType, infrastructure string) string {
+	if instanceType != "" {
+		return instanceType
+	}
+	return infrastructure
+}
+
+func machinesSize(size *int64) string {
+	if size == nil {
+		return "*"
+	}
+	return fmt.Sprint(*size)
+}
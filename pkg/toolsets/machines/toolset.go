@@ -0,0 +1,32 @@
+package machines
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "machines"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Machine management for Cluster API and OpenShift Machine API: Machines and MachineSets, the Machine backing a Node, the scaling of MachineSets (node pools), and the status and decisions of the cluster-autoscaler and Karpenter"
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return slices.Concat(
+		initMachines(),
+		initAutoscaler(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	// defaultRange is the range of the range queries that don't provide a start time
+	defaultRange = time.Hour
+	// defaultPoints is the number of points per series of the range queries that don't provide a step
+	defaultPoints = 120
+)
+
+// timeDescription describes the accepted time formats of the query parameters
+const timeDescription = "RFC3339 (e.g. 2025-01-01T10:00:00Z), Unix timestamp in seconds, \"now\", or a negative duration relative to now (e.g. -2h)"
+
+func initQuery() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "metrics_query",
+			Description: "Evaluate a PromQL instant query against the configured Prometheus (or Thanos Querier) and return the resulting samples. " +
+				"Useful to complement the current resource usage snapshots (nodes_top, pods_top, nodes_stats_summary) with metrics at a given point in time, " +
+				"e.g. max_over_time(container_memory_working_set_bytes{namespace=\"ns\"}[1d])",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"query": {
+						Type:        "string",
+						Description: "PromQL expression to evaluate",
+					},
+					"time": {
+						Type:        "string",
+						Description: "Evaluation time (Optional, defaults to now), " + timeDescription,
+					},
+				},
+				Required: []string{"query"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Metrics: Query",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: metricsQuery, ClusterAware: ptr.To(false)},
+		{Tool: api.Tool{
+			Name: "metrics_range_query",
+			Description: "Evaluate a PromQL query over a range of time against the configured Prometheus (or Thanos Querier) and return the resulting series. " +
+				"Useful to correlate the current state of the cluster with historical trends, " +
+				"e.g. sum by (node) (rate(node_cpu_seconds_total{mode!=\"idle\"}[5m])) over the last day",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"query": {
+						Type:        "string",
+						Description: "PromQL expression to evaluate",
+					},
+					"start": {
+						Type:        "string",
+						Description: "Start of the range (Optional, defaults to 1 hour before end), " + timeDescription,
+					},
+					"end": {
+						Type:        "string",
+						Description: "End of the range (Optional, defaults to now), " + timeDescription,
+					},
+					"step": {
+						Type: "string",
+						Description: "Resolution step, duration (e.g. 30s, 5m) or number of seconds " +
+							fmt.Sprintf("(Optional, defaults to the range divided in %d points)", defaultPoints),
+					},
+				},
+				Required: []string{"query"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Metrics: Range Query",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: metricsRangeQuery, ClusterAware: ptr.To(false)},
+	}
+}
+
+type metricsQueryArgs struct {
+	Query string `json:"query"`
+	Time  string `json:"time"`
+}
+
+func metricsQuery(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[metricsQueryArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query metrics, %w", err)), nil
+	}
+	evaluationTime := ""
+	if args.Time != "" {
+		t, err := parseTime(args.Time)
+		if err != nil {
+			return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to query metrics, invalid argument time: %w", err))), nil
+		}
+		evaluationTime = formatTime(t)
+	}
+	result, err := params.NewPrometheus().Query(params, args.Query, evaluationTime)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query metrics: %w", err)), nil
+	}
+	return api.NewToolCallResult(output.MarshalYaml(result)), nil
+}
+
+type metricsRangeQueryArgs struct {
+	Query string `json:"query"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Step  string `json:"step"`
+}
+
+func metricsRangeQuery(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[metricsRangeQueryArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query metrics range, %w", err)), nil
+	}
+	end := time.Now()
+	if args.End != "" {
+		if end, err = parseTime(args.End); err != nil {
+			return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to query metrics range, invalid argument end: %w", err))), nil
+		}
+	}
+	start := end.Add(-defaultRange)
+	if args.Start != "" {
+		if start, err = parseTime(args.Start); err != nil {
+			return api.NewToolCallResult("", api.InvalidArgument(fmt.Errorf("failed to query metrics range, invalid argument start: %w", err))), nil
+		}
+	}
+	if !start.Before(end) {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query metrics range, start %s must be before end %s",
+			start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))), nil
+	}
+	step := args.Step
+	if step == "" {
+		step = strconv.FormatInt(max(int64(end.Sub(start).Seconds())/defaultPoints, 1), 10)
+	}
+	result, err := params.NewPrometheus().QueryRange(params, args.Query, formatTime(start), formatTime(end), step)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query metrics range: %w", err)), nil
+	}
+	return api.NewToolCallResult(output.MarshalYaml(result)), nil
+}
+
+// parseTime parses the time formats accepted by the metrics tools
+func parseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "now" {
+		return time.Now(), nil
+	}
+	if strings.HasPrefix(value, "-") {
+		if d, err := time.ParseDuration(value); err == nil {
+			return time.Now().Add(d), nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(seconds * 1000)), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a valid time, expected %s", value, timeDescription)
+}
+
+// formatTime formats the time as expected by the Prometheus API (Unix timestamp in seconds)
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/prometheus"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return prometheus.ToolsetName
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Historical metrics queries (PromQL) against Prometheus or Thanos, check the [metrics documentation](https://github.com/containers/kubernetes-mcp-server/blob/main/docs/METRICS.md) for more details."
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return slices.Concat(
+		initQuery(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
@@ -0,0 +1,135 @@
+package networkdebug
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initNetpol() []api.ServerTool {
+	properties := map[string]*jsonschema.Schema{
+		"port": {
+			Type:        "integer",
+			Description: "Destination port of the connection",
+			Minimum:     ptr.To(1.0),
+			Maximum:     ptr.To(65535.0),
+		},
+		"protocol": {
+			Type:        "string",
+			Description: "Protocol of the connection (Optional, default TCP)",
+			Enum:        []any{"TCP", "UDP", "SCTP"},
+			Default:     api.ToRawMessage("TCP"),
+		},
+	}
+	for _, endpoint := range []string{"source", "destination"} {
+		properties[endpoint+"_namespace"] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "Namespace of the " + endpoint + " Pod (Optional, defaults to the configured namespace unless " + endpoint + "_ip is provided)",
+		}
+		properties[endpoint+"_pod"] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "Name of the " + endpoint + " Pod (Optional, a Pod of the namespace with the " + endpoint + "_labels if not provided)",
+		}
+		properties[endpoint+"_labels"] = &jsonschema.Schema{
+			Type:                 "object",
+			Description:          "Labels of the " + endpoint + " Pod if no " + endpoint + "_pod is provided, e.g. {\"app\": \"web\"} (Optional)",
+			AdditionalProperties: &jsonschema.Schema{Type: "string"},
+		}
+		properties[endpoint+"_ip"] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "IP address of the " + endpoint + " outside of the cluster, matched by the ipBlock rules (Optional, only if no " + endpoint + "_namespace nor " + endpoint + "_pod is provided)",
+		}
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "netpol_analyze",
+			Description: "Evaluate the NetworkPolicies for a connection from a source to a destination port and tell whether it's allowed or denied. " +
+				"The source and destination are Pods, Pods of a namespace with the provided labels, or IP addresses outside of the cluster. " +
+				"Reports the policies isolating the source (egress) and the destination (ingress), the rules allowing the connection, " +
+				"and the rules allowing the peer on other ports. " +
+				"The policies are evaluated without sending traffic, their enforcement depends on the network plugin, " +
+				"use network_debug_probes to test the actual connectivity",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties,
+				Required:   []string{"port"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Network Policies: Analyze",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: netpolAnalyze, Permissions: []api.ResourcePermission{
+			{Verb: "get", Resource: "pods"}, {Verb: "get", Resource: "namespaces", ClusterWide: true}, {Verb: "list", Group: networkingv1.GroupName, Resource: "networkpolicies"},
+		}},
+		{Tool: api.Tool{
+			Name: "netpol_list_isolating",
+			Description: "List the namespaces whose Pods are all isolated by NetworkPolicies (policies with an empty podSelector) for ingress or egress, " +
+				"whether they have a default deny policy (no rules), and the namespaces whose Pods accept all the connections unless a policy selects them",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Network Policies: List Isolating",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: netpolListIsolating, Permissions: []api.ResourcePermission{
+			{Verb: "list", Resource: "namespaces", ClusterWide: true}, {Verb: "list", Group: networkingv1.GroupName, Resource: "networkpolicies", ClusterWide: true},
+		}},
+	}
+}
+
+type netpolAnalyzeArgs struct {
+	SourceNamespace      string            `json:"source_namespace"`
+	SourcePod            string            `json:"source_pod"`
+	SourceLabels         map[string]string `json:"source_labels"`
+	SourceIP             string            `json:"source_ip"`
+	DestinationNamespace string            `json:"destination_namespace"`
+	DestinationPod       string            `json:"destination_pod"`
+	DestinationLabels    map[string]string `json:"destination_labels"`
+	DestinationIP        string            `json:"destination_ip"`
+	Port                 int32             `json:"port"`
+	Protocol             string            `json:"protocol"`
+}
+
+func netpolAnalyze(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[netpolAnalyzeArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze network policies, %w", err)), nil
+	}
+	analysis, err := params.NetpolAnalyze(params,
+		kubernetes.NetpolEndpoint{Namespace: args.SourceNamespace, Pod: args.SourcePod, Labels: args.SourceLabels, IP: args.SourceIP},
+		kubernetes.NetpolEndpoint{Namespace: args.DestinationNamespace, Pod: args.DestinationPod, Labels: args.DestinationLabels, IP: args.DestinationIP},
+		args.Port, args.Protocol)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze network policies: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(analysis)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze network policies: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+analysis.Summary+"\n"+marshalled, nil), nil
+}
+
+func netpolListIsolating(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	report, err := params.NetpolIsolation(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list isolating network policies: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list isolating network policies: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+report.Summary+"\n"+marshalled, nil), nil
+}
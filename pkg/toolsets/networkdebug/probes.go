@@ -0,0 +1,119 @@
+package networkdebug
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initProbes() []api.ServerTool {
+	probeTypes := make([]any, 0, len(kubernetes.NetworkProbeTypes))
+	for _, probeType := range kubernetes.NetworkProbeTypes {
+		probeTypes = append(probeTypes, probeType)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "network_debug_probes",
+			Description: "Test the network connectivity from a namespace (and optionally a node) to Services, Pods, and external hosts. " +
+				"Runs the provided DNS lookups (dig), TCP connection tests (nc), HTTP requests (curl), and traceroutes from a short-lived helper pod " +
+				"and returns the pass/fail result of each probe. " +
+				"The helper pod is subject to the NetworkPolicies of its namespace, provide the labels of a Pod to test the connectivity allowed for that Pod",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace where the helper pod runs, the source of the probes (Optional, defaults to the configured namespace)",
+					},
+					"node": {
+						Type:        "string",
+						Description: "Name of the node where the helper pod runs (Optional)",
+					},
+					"labels": {
+						Type:                 "object",
+						Description:          "Labels of the helper pod, e.g. the labels of the Pod whose connectivity is tested so that the same NetworkPolicies apply (Optional)",
+						AdditionalProperties: &jsonschema.Schema{Type: "string"},
+					},
+					"probes": {
+						Type:        "array",
+						Description: "Probes to run, e.g. [{\"type\": \"dns\", \"target\": \"service/web\"}, {\"type\": \"http\", \"target\": \"service/web\", \"namespace\": \"shop\", \"path\": \"/healthz\"}]",
+						MinItems:    ptr.To(1),
+						MaxItems:    ptr.To(kubernetes.NetworkProbesMax),
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"type": {
+									Type:        "string",
+									Description: "Type of probe: dns (lookup), tcp (connect), http (request, fails on 4xx and 5xx status codes), or traceroute",
+									Enum:        probeTypes,
+								},
+								"target": {
+									Type: "string",
+									Description: "Host name, IP address, or URL (http) to probe, " +
+										"or service/<name> (Service DNS name) and pod/<name> (Pod IP) references resolved in the probe namespace",
+								},
+								"namespace": {
+									Type:        "string",
+									Description: "Namespace of the service/<name> and pod/<name> targets (Optional, defaults to the namespace of the helper pod)",
+								},
+								"port": {
+									Type:        "integer",
+									Description: "Port of the tcp and http probes (Optional for service/<name> targets, defaults to the first Service port)",
+									Minimum:     ptr.To(1.0),
+									Maximum:     ptr.To(65535.0),
+								},
+								"path": {
+									Type:        "string",
+									Description: "Path of the http probes (Optional, ignored for URL targets)",
+								},
+							},
+							Required: []string{"type", "target"},
+						},
+					},
+				},
+				Required: []string{"probes"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Network Debug: Probes",
+				ReadOnlyHint:    ptr.To(false), // Creates a helper pod
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: networkDebugProbes, Permissions: []api.ResourcePermission{
+			{Verb: "create", Resource: "pods"}, {Verb: "get", Resource: "pods"}, {Verb: "get", Resource: "pods", Subresource: "log"}, {Verb: "delete", Resource: "pods"},
+		}},
+	}
+}
+
+type networkDebugProbesArgs struct {
+	Namespace string                    `json:"namespace"`
+	Node      string                    `json:"node"`
+	Labels    map[string]string         `json:"labels"`
+	Probes    []kubernetes.NetworkProbe `json:"probes"`
+}
+
+func networkDebugProbes(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[networkDebugProbesArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to run network probes, %w", err)), nil
+	}
+	networkDebug, err := params.NetworkDebugProbes(params, kubernetes.NetworkDebugOptions{
+		Namespace: args.Namespace,
+		Node:      args.Node,
+		Labels:    args.Labels,
+	}, args.Probes)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to run network probes: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(networkDebug)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to run network probes: %w", err)), nil
+	}
+	return api.NewToolCallResult("# "+networkDebug.Summary+"\n"+marshalled, nil), nil
+}
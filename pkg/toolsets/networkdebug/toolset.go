@@ -0,0 +1,32 @@
+package networkdebug
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "network_debug"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Network connectivity tests (DNS, TCP, HTTP, traceroute) run from a short-lived helper pod, and the evaluation of the NetworkPolicies between Pods, check the [network debug documentation](https://github.com/containers/kubernetes-mcp-server/blob/main/docs/NETWORK_DEBUG.md) for more details."
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return slices.Concat(
+		initProbes(),
+		initNetpol(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
@@ -0,0 +1,122 @@
+package openshift
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initBuilds() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name:        "builds_start",
+			Description: "Start a new OpenShift build from the provided BuildConfig (equivalent to `oc start-build`), use builds_log to follow its progress",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the BuildConfig (Optional, current namespace if not provided)",
+					},
+					"build_config": {
+						Type:        "string",
+						Description: "Name of the BuildConfig to start a build from",
+					},
+				},
+				Required: []string{"build_config"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Builds: Start",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: buildsStart, Permissions: []api.ResourcePermission{{Verb: "create", Group: "build.openshift.io", Resource: "buildconfigs", Subresource: "instantiate"}}},
+		{Tool: api.Tool{
+			Name:        "builds_log",
+			Description: "Get the last lines of the logs of an OpenShift build, either by build name or the latest build of a BuildConfig",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the build (Optional, current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the build (Optional, required if build_config is not provided)",
+					},
+					"build_config": {
+						Type:        "string",
+						Description: "Name of the BuildConfig to get the latest build logs from (Optional, ignored if name is provided)",
+					},
+					"tail": {
+						Type:        "integer",
+						Description: "Number of lines to retrieve from the end of the logs (Optional, default: 100)",
+						Default:     api.ToRawMessage(100),
+						Minimum:     ptr.To(float64(0)),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Builds: Log",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: buildsLog, Permissions: []api.ResourcePermission{{Verb: "get", Group: "build.openshift.io", Resource: "builds", Subresource: "log"}}},
+	}
+}
+
+func buildsStart(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	buildConfig, ok := params.GetArguments()["build_config"].(string)
+	if !ok || buildConfig == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to start build, missing argument build_config"))), nil
+	}
+	build, err := params.BuildsStart(params, namespace, buildConfig)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to start build for build config %s: %w", buildConfig, err)), nil
+	}
+	build.SetManagedFields(nil)
+	marshalledYaml, err := output.MarshalYaml(build)
+	if err != nil {
+		err = fmt.Errorf("failed to start build for build config %s: %w", buildConfig, err)
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# Build %s started successfully\n", build.GetName())+marshalledYaml, err), nil
+}
+
+func buildsLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	name, _ := params.GetArguments()["name"].(string)
+	buildConfig, _ := params.GetArguments()["build_config"].(string)
+	if name == "" && buildConfig == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to get build log, missing argument name or build_config"))), nil
+	}
+	var tail int64
+	if t := params.GetArguments()["tail"]; t != nil {
+		var err error
+		if tail, err = api.ParseInt64(t); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to parse tail parameter: %w", err)), nil
+		}
+	}
+	if name == "" {
+		var err error
+		if name, err = params.BuildsLatest(params, namespace, buildConfig); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to get build log: %w", err)), nil
+		}
+	}
+	ret, err := params.BuildsLog(params, namespace, name, tail)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get build %s log: %w", name, err)), nil
+	} else if ret == "" {
+		ret = fmt.Sprintf("The build %s has not logged any message yet", name)
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
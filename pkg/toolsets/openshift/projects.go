@@ -0,0 +1,92 @@
+package openshift
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initProjects() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "projects",
+			Description: "Manage OpenShift projects. " +
+				"create: requests a new project (equivalent to `oc new-project`), the requester becomes the project admin. " +
+				"delete: deletes the project and all of its resources",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"action": {
+						Type:        "string",
+						Description: "Action to perform on the project",
+						Enum:        []any{projectActionCreate, projectActionDelete},
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the project",
+					},
+					"display_name": {
+						Type:        "string",
+						Description: "Human readable name of the project (Optional, only applicable to the create action)",
+					},
+					"description": {
+						Type:        "string",
+						Description: "Description of the project (Optional, only applicable to the create action)",
+					},
+				},
+				Required: []string{"action", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Projects: Manage",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: projects, Permissions: []api.ResourcePermission{
+			{Verb: "create", Group: "project.openshift.io", Resource: "projectrequests", ClusterWide: true},
+			{Verb: "delete", Group: "project.openshift.io", Resource: "projects", ClusterWide: true},
+		}, DryRunSupported: ptr.To(true)},
+	}
+}
+
+const (
+	projectActionCreate = "create"
+	projectActionDelete = "delete"
+)
+
+func projects(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	action, _ := params.GetArguments()["action"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to manage project, missing argument name"))), nil
+	}
+	switch action {
+	case projectActionCreate:
+		displayName, _ := params.GetArguments()["display_name"].(string)
+		description, _ := params.GetArguments()["description"].(string)
+		project, err := params.ProjectsCreate(params, name, displayName, description)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to create project %s: %w", name, err)), nil
+		}
+		project.SetManagedFields(nil)
+		marshalledYaml, err := output.MarshalYaml(project)
+		if err != nil {
+			err = fmt.Errorf("failed to create project %s: %w", name, err)
+		}
+		return api.NewToolCallResult("# The following project (YAML) has been created successfully\n"+marshalledYaml, err), nil
+	case projectActionDelete:
+		if err := params.ProjectsDelete(params, name); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to delete project %s: %w", name, err)), nil
+		}
+		return api.NewToolCallResult(fmt.Sprintf("Project %s deletion requested successfully", name), nil), nil
+	default:
+		return api.NewToolCallResult("", fmt.Errorf("failed to manage project, unsupported action %q, supported actions: %s, %s",
+			action, projectActionCreate, projectActionDelete)), nil
+	}
+}
@@ -0,0 +1,121 @@
+package openshift
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initRoutes() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name:        "routes_list",
+			Description: "List the OpenShift Routes in the current cluster from all namespaces or from the provided namespace",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to list the Routes from (Optional, all namespaces if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Routes: List",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: routesList, Permissions: []api.ResourcePermission{{Verb: "list", Group: "route.openshift.io", Resource: "routes"}}},
+		{Tool: api.Tool{
+			Name:        "routes_create",
+			Description: "Expose a Service outside the cluster by creating (or updating) an OpenShift Route (equivalent to `oc expose service` / `oc create route`)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Service and the Route (Optional, current namespace if not provided)",
+					},
+					"service": {
+						Type:        "string",
+						Description: "Name of the Service to route the traffic to",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Route (Optional, defaults to the Service name)",
+					},
+					"port": {
+						Type:        "string",
+						Description: "Name or number of the Service target port (Optional, all ports if not provided)",
+					},
+					"hostname": {
+						Type:        "string",
+						Description: "Hostname for the Route (Optional, generated by the router if not provided)",
+					},
+					"path": {
+						Type:        "string",
+						Description: "Path that the router watches to route traffic to the Service (Optional)",
+					},
+					"tls_termination": {
+						Type:        "string",
+						Description: "TLS termination type (Optional, the Route is not secured if not provided)",
+						Enum:        []any{"edge", "passthrough", "reencrypt"},
+					},
+				},
+				Required: []string{"service"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Routes: Create",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: routesCreate, Permissions: []api.ResourcePermission{{Verb: "create", Group: "route.openshift.io", Resource: "routes"}}, DryRunSupported: ptr.To(true)},
+	}
+}
+
+func routesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	ret, err := params.RoutesList(params, namespace, internalk8s.ResourceListOptions{AsTable: params.ListOutput.AsTable()})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list routes: %w", err)), nil
+	}
+	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
+}
+
+func routesCreate(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args := params.GetArguments()
+	namespace, _ := args["namespace"].(string)
+	options := internalk8s.RouteOptions{}
+	options.Service, _ = args["service"].(string)
+	if options.Service == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to create route, missing argument service"))), nil
+	}
+	options.Name, _ = args["name"].(string)
+	options.Host, _ = args["hostname"].(string)
+	options.Path, _ = args["path"].(string)
+	options.TLSTermination, _ = args["tls_termination"].(string)
+	switch port := args["port"].(type) {
+	case string:
+		options.TargetPort = port
+	case float64:
+		options.TargetPort = fmt.Sprintf("%d", int64(port))
+	}
+	ret, err := params.RoutesCreate(params, namespace, options)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create route for service %s: %w", options.Service, err)), nil
+	}
+	marshalledYaml, err := output.MarshalYaml(ret)
+	if err != nil {
+		err = fmt.Errorf("failed to create route for service %s: %w", options.Service, err)
+	}
+	return api.NewToolCallResult("# The following route (YAML) has been created or updated successfully\n"+marshalledYaml, err), nil
+}
@@ -0,0 +1,37 @@
+package openshift
+
+import (
+	"context"
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "openshift"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "OpenShift specific tools (Routes, Projects, Builds), only available when the cluster is OpenShift"
+}
+
+func (t *Toolset) GetTools(o internalk8s.Openshift) []api.ServerTool {
+	if !o.IsOpenShift(context.Background()) {
+		return []api.ServerTool{}
+	}
+	return slices.Concat(
+		initRoutes(),
+		initProjects(),
+		initBuilds(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
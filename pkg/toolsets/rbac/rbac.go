@@ -0,0 +1,248 @@
+package rbac
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	rbacgraph "github.com/containers/kubernetes-mcp-server/pkg/rbac"
+)
+
+// listRbac are the permissions needed to build the RBAC graph
+var listRbac = []api.ResourcePermission{
+	{Verb: "list", Group: rbacv1.GroupName, Resource: "roles", ClusterWide: true},
+	{Verb: "list", Group: rbacv1.GroupName, Resource: "clusterroles", ClusterWide: true},
+	{Verb: "list", Group: rbacv1.GroupName, Resource: "rolebindings", ClusterWide: true},
+	{Verb: "list", Group: rbacv1.GroupName, Resource: "clusterrolebindings", ClusterWide: true},
+}
+
+const subjectDescription = "in the kind:name form for users and groups (e.g. user:alice, group:developers), " +
+	"and kind:namespace:name for service accounts (e.g. serviceaccount:ns-1:builder)"
+
+func initRbac() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "rbac_can_i",
+			Description: "Check whether the current user, or the provided subject, can perform an action on a Kubernetes resource (like kubectl auth can-i). " +
+				"The access review is evaluated by the authorizers of the API server, the reason of the decision is reported when available",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: with(resourceRequestProperties(), map[string]*jsonschema.Schema{
+					"subject": {
+						Type:        "string",
+						Description: "Subject to check, " + subjectDescription + " (Optional, defaults to the current user)",
+					},
+				}),
+				Required: []string{"verb", "resource"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "RBAC: Can I",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: rbacCanI},
+		{Tool: api.Tool{
+			Name: "rbac_who_can",
+			Description: "List the subjects (users, groups, and service accounts) that can perform an action on a Kubernetes resource, " +
+				"with the RoleBinding or ClusterRoleBinding and the role granting it. " +
+				"Only the permissions granted by RBAC are reported, permissions granted by other authorizers (e.g. Node, webhooks) are not",
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: resourceRequestProperties(),
+				Required:   []string{"verb", "resource"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "RBAC: Who Can",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: rbacWhoCan, Permissions: listRbac},
+		{Tool: api.Tool{
+			Name: "rbac_list_bindings_for_subject",
+			Description: "List the RoleBindings and ClusterRoleBindings granting roles to a subject, directly or through its groups, " +
+				"with the rules of the bound roles. " +
+				"The implicit groups of service accounts (system:serviceaccounts, system:serviceaccounts:<namespace>) and system:authenticated are included",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"subject": {
+						Type:        "string",
+						Description: "Subject whose bindings are listed, " + subjectDescription,
+					},
+					"groups": {
+						Type:        "array",
+						Description: "Groups the subject belongs to, whose bindings are listed too (Optional, group membership is not stored in the cluster)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace whose RoleBindings are listed along with the ClusterRoleBindings (Optional, all namespaces if not provided)",
+					},
+				},
+				Required: []string{"subject"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "RBAC: List Bindings for Subject",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: rbacListBindingsForSubject, Permissions: listRbac},
+	}
+}
+
+// resourceRequestProperties returns the properties of the action looked up by the tools
+func resourceRequestProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"verb": {
+			Type:        "string",
+			Description: "Verb of the action, e.g. get, list, watch, create, update, patch, delete, or a custom verb such as impersonate or escalate",
+		},
+		"resource": {
+			Type:        "string",
+			Description: "Resource of the action in its plural form, e.g. pods, deployments, secrets",
+		},
+		"api_group": {
+			Type:        "string",
+			Description: "API group of the resource, e.g. apps, rbac.authorization.k8s.io (Optional, the core group if not provided)",
+		},
+		"subresource": {
+			Type:        "string",
+			Description: "Subresource of the action, e.g. exec, log, status (Optional)",
+		},
+		"name": {
+			Type:        "string",
+			Description: "Name of the resource (Optional, any resource if not provided)",
+		},
+		"namespace": {
+			Type:        "string",
+			Description: "Namespace of the resource (Optional, cluster-wide if not provided: only the permissions in all namespaces are considered)",
+		},
+	}
+}
+
+func with(properties, additional map[string]*jsonschema.Schema) map[string]*jsonschema.Schema {
+	maps.Copy(properties, additional)
+	return properties
+}
+
+type resourceRequestArgs struct {
+	Verb        string `json:"verb"`
+	Resource    string `json:"resource"`
+	APIGroup    string `json:"api_group"`
+	Subresource string `json:"subresource"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+}
+
+func (a resourceRequestArgs) request() rbacgraph.ResourceRequest {
+	return rbacgraph.ResourceRequest{
+		Verb:        a.Verb,
+		Group:       a.APIGroup,
+		Resource:    a.Resource,
+		Subresource: a.Subresource,
+		Name:        a.Name,
+		Namespace:   a.Namespace,
+	}
+}
+
+type rbacCanIArgs struct {
+	resourceRequestArgs
+	Subject string `json:"subject"`
+}
+
+func rbacCanI(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[rbacCanIArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to check access, %w", err)), nil
+	}
+	var subject *rbacv1.Subject
+	if args.Subject != "" {
+		parsed, err := rbacgraph.ParseSubject(args.Subject)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to check access, %w", err)), nil
+		}
+		subject = &parsed
+	}
+	decision, err := params.RbacCanI(params, subject, args.request())
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to check access: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(decision)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to check access: %w", err)), nil
+	}
+	who := "The current user"
+	if decision.Subject != "" {
+		who = decision.Subject
+	}
+	verdict := "can"
+	if !decision.Allowed {
+		verdict = "cannot"
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# %s %s %s\n%s", who, verdict, decision.Request, marshalled), nil), nil
+}
+
+func rbacWhoCan(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[resourceRequestArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to look up subjects, %w", err)), nil
+	}
+	graph, err := params.RbacGraph(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to look up subjects: %w", err)), nil
+	}
+	request := args.request()
+	grants := graph.WhoCan(request)
+	marshalled, err := output.MarshalYaml(grants)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to look up subjects: %w", err)), nil
+	}
+	subjects := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		subjects = append(subjects, rbacgraph.FormatSubject(grant.Subject))
+	}
+	summary := fmt.Sprintf("# %d subjects can %s", len(slices.Compact(subjects)), request)
+	return api.NewToolCallResult(summary+"\n"+marshalled, nil), nil
+}
+
+type rbacListBindingsForSubjectArgs struct {
+	Subject   string   `json:"subject"`
+	Groups    []string `json:"groups"`
+	Namespace string   `json:"namespace"`
+}
+
+func rbacListBindingsForSubject(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[rbacListBindingsForSubjectArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list bindings, %w", err)), nil
+	}
+	subject, err := rbacgraph.ParseSubject(args.Subject)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list bindings, %w", err)), nil
+	}
+	graph, err := params.RbacGraph(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list bindings: %w", err)), nil
+	}
+	grants := slices.DeleteFunc(graph.BindingsFor(subject, args.Groups), func(grant rbacgraph.Grant) bool {
+		return args.Namespace != "" && grant.Namespace != "" && grant.Namespace != args.Namespace
+	})
+	marshalled, err := output.MarshalYaml(grants)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list bindings: %w", err)), nil
+	}
+	summary := fmt.Sprintf("# %d bindings grant roles to %s", len(grants), rbacgraph.FormatSubject(subject))
+	return api.NewToolCallResult(summary+"\n"+marshalled, nil), nil
+}
@@ -0,0 +1,31 @@
+package rbac
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "rbac"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Review of the RBAC permissions of the cluster: access checks, reverse lookups of the subjects allowed to perform an action, and the bindings of a subject"
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return slices.Concat(
+		initRbac(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
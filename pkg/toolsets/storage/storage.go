@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// listStorage are the permissions needed to map the PersistentVolumeClaims to their volumes, classes, and consumers
+var listStorage = []api.ResourcePermission{
+	{Verb: "list", Resource: "persistentvolumeclaims"},
+	{Verb: "list", Resource: "persistentvolumes", ClusterWide: true},
+	{Verb: "list", Group: storagev1.GroupName, Resource: "storageclasses", ClusterWide: true},
+	{Verb: "list", Group: storagev1.GroupName, Resource: "volumeattachments", ClusterWide: true},
+	{Verb: "list", Resource: "pods"},
+}
+
+func initStorage() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "storage_pvcs_list",
+			Description: "List the Kubernetes PersistentVolumeClaims in the current or provided namespace, or in all namespaces, with their phase, requested and actual capacity, " +
+				"and their mapping to the bound PersistentVolume (reclaim policy, CSI driver), the StorageClass (provisioner, volume binding mode), " +
+				"the nodes the volume is attached to (with attach errors), and the Pods that use them",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to list the PersistentVolumeClaims from (Optional, current namespace if not provided)",
+					},
+					"all_namespaces": {
+						Type:        "boolean",
+						Description: "List the PersistentVolumeClaims of all namespaces (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Storage: List PersistentVolumeClaims",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: storagePvcsList, Permissions: listStorage},
+		{Tool: api.Tool{
+			Name: "storage_why_pending",
+			Description: "Explain why a Kubernetes PersistentVolumeClaim in the current or provided namespace with the provided name is not bound. " +
+				"Checks the StorageClass of the claim (missing StorageClass, no default StorageClass, static provisioning only, " +
+				"WaitForFirstConsumer binding with no scheduled Pod using the claim), evaluates each PersistentVolume of the StorageClass " +
+				"(capacity, access modes, volume mode, selector, already bound or released) and reports the ones ruled out with the reasons why, " +
+				"and the events of the claim (e.g. ProvisioningFailed)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the PersistentVolumeClaim",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the PersistentVolumeClaim",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Storage: Why Pending",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: storageWhyPending, Permissions: append(slices.Clone(listStorage), api.ResourcePermission{Verb: "list", Resource: "events"})},
+	}
+}
+
+type storagePvcsListArgs struct {
+	Namespace     string `json:"namespace"`
+	AllNamespaces bool   `json:"all_namespaces"`
+}
+
+func storagePvcsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[storagePvcsListArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list persistent volume claims, %w", err)), nil
+	}
+	namespace := ""
+	if !args.AllNamespaces {
+		namespace = params.NamespaceOrDefault(args.Namespace)
+	}
+	claims, err := params.StorageListClaims(params, namespace)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list persistent volume claims: %w", err)), nil
+	}
+	marshalled, err := output.MarshalYaml(claims)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list persistent volume claims: %w", err)), nil
+	}
+	pending := 0
+	for _, claim := range claims.Claims {
+		if claim.Phase != v1.ClaimBound {
+			pending++
+		}
+	}
+	summary := fmt.Sprintf("# %d PersistentVolumeClaims found, %d not bound", len(claims.Claims), pending)
+	return api.NewToolCallResult(summary+"\n"+marshalled, nil), nil
+}
+
+type storageWhyPendingArgs struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func storageWhyPending(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	args, err := api.ParseArguments[storageWhyPendingArgs](params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze persistent volume claim binding, %w", err)), nil
+	}
+	if args.Name == "" {
+		return api.NewToolCallResult("", api.InvalidArgument(errors.New("failed to analyze persistent volume claim binding, missing argument name"))), nil
+	}
+	binding, err := params.StorageWhyPending(params, args.Namespace, args.Name)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze persistent volume claim %s binding: %w", args.Name, err)), nil
+	}
+	marshalled, err := output.MarshalYaml(binding)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze persistent volume claim %s binding: %w", args.Name, err)), nil
+	}
+	return api.NewToolCallResult("# "+binding.Summary+"\n"+marshalled, nil), nil
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "storage"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Diagnostics of the persistent storage: PersistentVolumeClaims mapped to their volumes, StorageClasses and node attachments, and the analysis of unbound claims"
+}
+
+func (t *Toolset) GetTools(_ internalk8s.Openshift) []api.ServerTool {
+	return slices.Concat(
+		initStorage(),
+	)
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
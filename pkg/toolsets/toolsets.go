@@ -4,36 +4,64 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 )
 
-var toolsets []api.Toolset
+// ToolsetRegistry holds a set of toolsets that can be looked up by name.
+//
+// The built-in toolsets register themselves in the DefaultRegistry when their package is imported.
+// Programs embedding the toolsets in their own MCP server can create their own registry with
+// NewToolsetRegistry to expose a custom selection of toolsets (built-in or their own implementations).
+type ToolsetRegistry struct {
+	mu       sync.RWMutex
+	toolsets []api.Toolset
+}
 
-// Clear removes all registered toolsets, TESTING PURPOSES ONLY.
-func Clear() {
-	toolsets = []api.Toolset{}
+// NewToolsetRegistry creates a registry with the provided toolsets
+func NewToolsetRegistry(toolsets ...api.Toolset) *ToolsetRegistry {
+	r := &ToolsetRegistry{}
+	for _, toolset := range toolsets {
+		r.Register(toolset)
+	}
+	return r
 }
 
-func Register(toolset api.Toolset) {
-	toolsets = append(toolsets, toolset)
+// Register adds the toolset to the registry
+func (r *ToolsetRegistry) Register(toolset api.Toolset) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.toolsets = append(r.toolsets, toolset)
 }
 
-func Toolsets() []api.Toolset {
-	return toolsets
+// Clear removes all registered toolsets
+func (r *ToolsetRegistry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.toolsets = []api.Toolset{}
 }
 
-func ToolsetNames() []string {
+// Toolsets returns the registered toolsets in registration order
+func (r *ToolsetRegistry) Toolsets() []api.Toolset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.toolsets)
+}
+
+// ToolsetNames returns the sorted names of the registered toolsets
+func (r *ToolsetRegistry) ToolsetNames() []string {
 	names := make([]string, 0)
-	for _, toolset := range Toolsets() {
+	for _, toolset := range r.Toolsets() {
 		names = append(names, toolset.GetName())
 	}
 	slices.Sort(names)
 	return names
 }
 
-func ToolsetFromString(name string) api.Toolset {
-	for _, toolset := range Toolsets() {
+// ToolsetFromString returns the registered toolset with the provided name or nil if there's none
+func (r *ToolsetRegistry) ToolsetFromString(name string) api.Toolset {
+	for _, toolset := range r.Toolsets() {
 		if toolset.GetName() == strings.TrimSpace(name) {
 			return toolset
 		}
@@ -41,11 +69,42 @@ func ToolsetFromString(name string) api.Toolset {
 	return nil
 }
 
-func Validate(toolsets []string) error {
+// Validate checks that all the provided toolset names are registered
+func (r *ToolsetRegistry) Validate(toolsets []string) error {
 	for _, toolset := range toolsets {
-		if ToolsetFromString(toolset) == nil {
-			return fmt.Errorf("invalid toolset name: %s, valid names are: %s", toolset, strings.Join(ToolsetNames(), ", "))
+		if r.ToolsetFromString(toolset) == nil {
+			return fmt.Errorf("invalid toolset name: %s, valid names are: %s", toolset, strings.Join(r.ToolsetNames(), ", "))
 		}
 	}
 	return nil
 }
+
+// DefaultRegistry is the registry where the built-in toolsets and the toolsets of the plugins_dir are registered
+var DefaultRegistry = NewToolsetRegistry()
+
+// Clear removes all registered toolsets, TESTING PURPOSES ONLY.
+func Clear() {
+	DefaultRegistry.Clear()
+}
+
+// Register adds the toolset to the DefaultRegistry, the toolsets built in other modules register themselves with it
+// in the init function of their package (imported by their own build of the server).
+func Register(toolset api.Toolset) {
+	DefaultRegistry.Register(toolset)
+}
+
+func Toolsets() []api.Toolset {
+	return DefaultRegistry.Toolsets()
+}
+
+func ToolsetNames() []string {
+	return DefaultRegistry.ToolsetNames()
+}
+
+func ToolsetFromString(name string) api.Toolset {
+	return DefaultRegistry.ToolsetFromString(name)
+}
+
+func Validate(toolsets []string) error {
+	return DefaultRegistry.Validate(toolsets)
+}
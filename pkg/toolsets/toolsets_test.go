@@ -92,6 +92,21 @@ func (s *ToolsetsSuite) TestValidate() {
 	})
 }
 
+func (s *ToolsetsSuite) TestToolsetRegistry() {
+	registry := NewToolsetRegistry(&TestToolset{name: "embedded-1"}, &TestToolset{name: "embedded-0"})
+	s.Run("Returns the toolsets provided in the constructor", func() {
+		s.Equal([]string{"embedded-0", "embedded-1"}, registry.ToolsetNames())
+		s.NotNil(registry.ToolsetFromString("embedded-1"))
+		s.Nil(registry.Validate([]string{"embedded-0", "embedded-1"}))
+	})
+	s.Run("Is isolated from the default registry", func() {
+		Register(&TestToolset{name: "default"})
+		s.Nil(registry.ToolsetFromString("default"), "Expected default toolset not to be in the registry")
+		s.Nil(ToolsetFromString("embedded-0"), "Expected registry toolset not to be in the default registry")
+		s.Error(registry.Validate([]string{"default"}))
+	})
+}
+
 func TestToolsets(t *testing.T) {
 	suite.Run(t, new(ToolsetsSuite))
 }